@@ -1089,6 +1089,11 @@ func (rc *Client) LoadRestoreStores(ctx context.Context) error {
 		}
 	}
 	log.Info("load restore stores", zap.Uint64s("store-ids", rc.restoreStores))
+	// Without the labeled stores, the restored regions can't be isolated from the online workload.
+	if len(rc.restoreStores) == 0 {
+		return errors.Annotatef(berrors.ErrInvalidArgument,
+			"online restore requires at least one up store labeled %s=%s", restoreLabelKey, restoreLabelValue)
+	}
 	return nil
 }
 
@@ -1242,7 +1247,7 @@ func (rc *Client) GenerateDDLJobsMap() {
 	rc.ddlJobsMap = make(map[UniqueTableName]bool)
 	for _, job := range rc.ddlJobs {
 		switch job.Type {
-		case model.ActionTruncateTable, model.ActionCreateTable, model.ActionRenameTable:
+		case model.ActionTruncateTable, model.ActionCreateTable, model.ActionCreateTableAsSelect, model.ActionRenameTable:
 			rc.ddlJobsMap[UniqueTableName{job.SchemaName, job.BinlogInfo.TableInfo.Name.String()}] = true
 		}
 	}
@@ -1293,7 +1298,7 @@ func (rc *Client) PreCheckTableClusterIndex(
 		}
 	}
 	for _, job := range ddlJobs {
-		if job.Type == model.ActionCreateTable {
+		if job.Type == model.ActionCreateTable || job.Type == model.ActionCreateTableAsSelect {
 			tableInfo := job.BinlogInfo.TableInfo
 			if tableInfo != nil {
 				oldTableInfo, err := rc.GetTableSchema(dom, model.NewCIStr(job.SchemaName), tableInfo.Name)
@@ -168,6 +168,43 @@ func (fpdc fakePDClient) GetAllStores(context.Context, ...pd.GetStoreOption) ([]
 	return append([]*metapb.Store{}, fpdc.stores...), nil
 }
 
+func TestLoadRestoreStores(t *testing.T) {
+	m := mc
+	mockStores := []*metapb.Store{
+		{
+			Id:     1,
+			Labels: []*metapb.StoreLabel{{Key: "exclusive", Value: "restore"}},
+		},
+		{
+			Id:     2,
+			State:  metapb.StoreState_Offline,
+			Labels: []*metapb.StoreLabel{{Key: "exclusive", Value: "restore"}},
+		},
+		{
+			Id: 3,
+		},
+	}
+
+	client, err := restore.NewRestoreClient(gluetidb.New(), fakePDClient{
+		stores: mockStores,
+	}, m.Storage, nil, defaultKeepaliveCfg)
+	require.NoError(t, err)
+	// The stores are only loaded for the online restore.
+	require.NoError(t, client.LoadRestoreStores(context.Background()))
+	client.EnableOnline()
+	require.NoError(t, client.LoadRestoreStores(context.Background()))
+
+	client, err = restore.NewRestoreClient(gluetidb.New(), fakePDClient{
+		stores: mockStores[1:],
+	}, m.Storage, nil, defaultKeepaliveCfg)
+	require.NoError(t, err)
+	require.NoError(t, client.LoadRestoreStores(context.Background()))
+	client.EnableOnline()
+	err = client.LoadRestoreStores(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "online restore requires at least one up store labeled exclusive=restore")
+}
+
 func TestPreCheckTableTiFlashReplicas(t *testing.T) {
 	m := mc
 	mockStores := []*metapb.Store{
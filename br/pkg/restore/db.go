@@ -60,7 +60,8 @@ func (db *DB) ExecDDL(ctx context.Context, ddlJob *model.Job) error {
 			log.Error("create database failed", zap.Stringer("db", dbInfo.Name), zap.Error(err))
 		}
 		return errors.Trace(err)
-	case model.ActionCreateTable:
+	case model.ActionCreateTable, model.ActionCreateTableAsSelect:
+		// The rows of CREATE TABLE ... SELECT are restored with the data, only the table is created here.
 		err = db.se.CreateTable(ctx, model.NewCIStr(ddlJob.SchemaName), tableInfo)
 		if err != nil {
 			log.Error("create table failed",
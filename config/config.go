@@ -131,6 +131,8 @@ type Config struct {
 	CompatibleKillQuery        bool               `toml:"compatible-kill-query" json:"compatible-kill-query"`
 	Plugin                     Plugin             `toml:"plugin" json:"plugin"`
 	PessimisticTxn             PessimisticTxn     `toml:"pessimistic-txn" json:"pessimistic-txn"`
+	TrxSummary                 TrxSummary         `toml:"transaction-summary" json:"transaction-summary"`
+	StmtSummary                StmtSummary        `toml:"stmt-summary" json:"stmt-summary"`
 	CheckMb4ValueInUTF8        AtomicBool         `toml:"check-mb4-value-in-utf8" json:"check-mb4-value-in-utf8"`
 	MaxIndexLength             int                `toml:"max-index-length" json:"max-index-length"`
 	IndexLimit                 int                `toml:"index-limit" json:"index-limit"`
@@ -154,6 +156,9 @@ type Config struct {
 	IsolationRead IsolationRead `toml:"isolation-read" json:"isolation-read"`
 	// MaxServerConnections is the maximum permitted number of simultaneous client connections.
 	MaxServerConnections uint32 `toml:"max-server-connections" json:"max-server-connections"`
+	// MaxResourceGroupConnections is the maximum permitted number of simultaneous client connections of each resource group.
+	// The resource groups which are not in it are unlimited.
+	MaxResourceGroupConnections map[string]uint32 `toml:"max-resource-group-connections" json:"max-resource-group-connections"`
 	// NewCollationsEnabledOnFirstBootstrap indicates if the new collations are enabled, it effects only when a TiDB cluster bootstrapped on the first time.
 	NewCollationsEnabledOnFirstBootstrap bool `toml:"new_collations_enabled_on_first_bootstrap" json:"new_collations_enabled_on_first_bootstrap"`
 	// Experimental contains parameters for experimental features.
@@ -198,6 +203,13 @@ type Config struct {
 	MaxBallastObjectSize int `toml:"max-ballast-object-size" json:"max-ballast-object-size"`
 	// BallastObjectSize set the initial size of the ballast object, the unit is byte.
 	BallastObjectSize int `toml:"ballast-object-size" json:"ballast-object-size"`
+	// InitializeSQLFile is the path of a SQL file executed when the TiDB cluster is bootstrapped on the first time.
+	// It's used to create the users, disable the root user or install the policies in the automated deployments.
+	// TiDB fails to start if any statement fails, and the file is executed again on the next start.
+	InitializeSQLFile string `toml:"initialize-sql-file" json:"initialize-sql-file"`
+	// UpgradeSQLFile is the path of a SQL file executed after the TiDB cluster is upgraded to a new bootstrap version,
+	// together with the migration hooks registered by session.RegisterUpgradeHook.
+	UpgradeSQLFile string `toml:"upgrade-sql-file" json:"upgrade-sql-file"`
 }
 
 // UpdateTempStoragePath is to update the `TempStoragePath` if port/statusPort was changed
@@ -419,6 +431,9 @@ type Security struct {
 	MinTLSVersion   string `toml:"tls-version" json:"tls-version"`
 	RSAKeySize      int    `toml:"rsa-key-size" json:"rsa-key-size"`
 	SecureBootstrap bool   `toml:"secure-bootstrap" json:"secure-bootstrap"`
+	// MapUserByCertSAN allows the clients which log in with an empty user name to be mapped to the user whose
+	// REQUIRE SAN matches the verified client certificate.
+	MapUserByCertSAN bool `toml:"map-user-by-cert-san" json:"map-user-by-cert-san"`
 }
 
 // The ErrConfigValidationFailed error is used so that external callers can do a type assertion
@@ -450,6 +465,13 @@ type Status struct {
 	MetricsInterval uint   `toml:"metrics-interval" json:"metrics-interval"`
 	ReportStatus    bool   `toml:"report-status" json:"report-status"`
 	RecordQPSbyDB   bool   `toml:"record-db-qps" json:"record-db-qps"`
+	// RecordQueryByDB adds the current database as a label of the tenant query metrics.
+	RecordQueryByDB bool `toml:"record-query-by-db" json:"record-query-by-db"`
+	// RecordQueryByResGroup adds tidb_metrics_resource_group as a label of the tenant query metrics.
+	RecordQueryByResGroup bool `toml:"record-query-by-resource-group" json:"record-query-by-resource-group"`
+	// MetricsResourceGroups are the values allowed for tidb_metrics_resource_group, they bound the
+	// cardinality of the resource group label.
+	MetricsResourceGroups []string `toml:"metrics-resource-groups" json:"metrics-resource-groups"`
 	// After a duration of this time in seconds if the server doesn't see any activity it pings
 	// the client to see if the transport is still alive.
 	GRPCKeepAliveTime uint `toml:"grpc-keepalive-time" json:"grpc-keepalive-time"`
@@ -462,6 +484,13 @@ type Status struct {
 	GRPCInitialWindowSize int `toml:"grpc-initial-window-size" json:"grpc-initial-window-size"`
 	// Set maximum message length in bytes that gRPC can send. `-1` means unlimited. The default value is 10MB.
 	GRPCMaxSendMsgSize int `toml:"grpc-max-send-msg-size" json:"grpc-max-send-msg-size"`
+	// RequireClientCert requires the clients of the status port to present the certificates signed by the
+	// cluster CA, it needs the cluster TLS to be configured.
+	RequireClientCert bool `toml:"require-client-cert" json:"require-client-cert"`
+	// RequireAuth requires the requests of the HTTP status API, except the health check `/status`, to be sent
+	// over the cluster TLS, and either present a certificate signed by the cluster CA or be authenticated by
+	// HTTP basic auth with a user which has the STATUS_API_CLIENT privilege.
+	RequireAuth bool `toml:"require-auth" json:"require-auth"`
 }
 
 // Performance is the performance section of the config.
@@ -517,6 +546,7 @@ type OpenTracing struct {
 	RPCMetrics bool                `toml:"rpc-metrics" json:"rpc-metrics"`
 	Sampler    OpenTracingSampler  `toml:"sampler" json:"sampler"`
 	Reporter   OpenTracingReporter `toml:"reporter" json:"reporter"`
+	OTLP       OpenTracingOTLP     `toml:"otlp" json:"otlp"`
 }
 
 // OpenTracingSampler is the config for opentracing sampler.
@@ -538,6 +568,19 @@ type OpenTracingReporter struct {
 	LocalAgentHostPort  string        `toml:"local-agent-host-port" json:"local-agent-host-port"`
 }
 
+// OpenTracingOTLP is the config for exporting the spans of statements to an
+// OpenTelemetry collector with the OTLP/HTTP protocol.
+type OpenTracingOTLP struct {
+	Enable bool `toml:"enable" json:"enable"`
+	// Endpoint is the URL of the OTLP/HTTP traces receiver.
+	Endpoint string `toml:"endpoint" json:"endpoint"`
+	// SampleRate is the ratio of the statements to be exported.
+	SampleRate float64 `toml:"sample-rate" json:"sample-rate"`
+	// SlowThreshold exports the statements slower than it regardless of the
+	// sample rate, 0 means disabled. The unit is millisecond.
+	SlowThreshold uint64 `toml:"slow-threshold" json:"slow-threshold"`
+}
+
 // ProxyProtocol is the PROXY protocol section of the config.
 type ProxyProtocol struct {
 	// PROXY protocol acceptable client networks.
@@ -580,6 +623,55 @@ func DefaultPessimisticTxn() PessimisticTxn {
 	}
 }
 
+// TrxSummary is the config for the transaction summary, which is shown in the information_schema.trx_summary table.
+type TrxSummary struct {
+	// The max count of the kinds of transactions that will be recorded on each TiDB server.
+	TransactionSummaryCapacity uint `toml:"transaction-summary-capacity" json:"transaction-summary-capacity"`
+	// Only the transactions that run for at least this many milliseconds are recorded.
+	TransactionSummaryMinDuration uint `toml:"transaction-summary-min-duration" json:"transaction-summary-min-duration"`
+}
+
+// Valid validates the transaction summary config.
+func (c *TrxSummary) Valid() error {
+	if c.TransactionSummaryCapacity > 5000 {
+		return fmt.Errorf("transaction-summary-capacity in [transaction-summary] should not be larger than 5000")
+	}
+	return nil
+}
+
+// DefaultTrxSummary returns the default configuration for TrxSummary
+func DefaultTrxSummary() TrxSummary {
+	return TrxSummary{
+		TransactionSummaryCapacity:    500,
+		TransactionSummaryMinDuration: 0,
+	}
+}
+
+// StmtSummary is the config for persisting the statements summary into files.
+type StmtSummary struct {
+	// Enable persisting the statements summary, so that it's still available after the server restarts.
+	EnablePersistent bool `toml:"enable-persistent" json:"enable-persistent"`
+	// The file where the statements summary is persisted.
+	Filename string `toml:"filename" json:"filename"`
+	// Max size in MB of a single file.
+	FileMaxSize int `toml:"file-max-size" json:"file-max-size"`
+	// Max days that the rotated files are kept.
+	FileMaxDays int `toml:"file-max-days" json:"file-max-days"`
+	// Max number of the rotated files that are kept.
+	FileMaxBackups int `toml:"file-max-backups" json:"file-max-backups"`
+}
+
+// DefaultStmtSummary returns the default configuration for StmtSummary
+func DefaultStmtSummary() StmtSummary {
+	return StmtSummary{
+		EnablePersistent: false,
+		Filename:         "tidb-statements.log",
+		FileMaxSize:      64,
+		FileMaxDays:      3,
+		FileMaxBackups:   0,
+	}
+}
+
 // Plugin is the config for plugin
 type Plugin struct {
 	Dir  string `toml:"dir" json:"dir"`
@@ -641,6 +733,7 @@ var defaultConf = Config{
 	RepairMode:                   false,
 	RepairTableList:              []string{},
 	MaxServerConnections:         0,
+	MaxResourceGroupConnections:  make(map[string]uint32),
 	TxnLocalLatches:              defTiKVCfg.TxnLocalLatches,
 	LowerCaseTableNames:          2,
 	GracefulWaitBeforeShutdown:   0,
@@ -666,6 +759,7 @@ var defaultConf = Config{
 		StatusPort:            DefStatusPort,
 		MetricsInterval:       15,
 		RecordQPSbyDB:         false,
+		MetricsResourceGroups: []string{},
 		GRPCKeepAliveTime:     10,
 		GRPCKeepAliveTimeout:  3,
 		GRPCConcurrentStreams: 1024,
@@ -728,6 +822,8 @@ var defaultConf = Config{
 		Load: "",
 	},
 	PessimisticTxn: DefaultPessimisticTxn(),
+	TrxSummary:     DefaultTrxSummary(),
+	StmtSummary:    DefaultStmtSummary(),
 	IsolationRead: IsolationRead{
 		Engines: []string{"tikv", "tiflash", "tidb"},
 	},
@@ -925,6 +1021,14 @@ func (c *Config) Valid() error {
 	if c.OOMAction != OOMActionLog && c.OOMAction != OOMActionCancel {
 		return fmt.Errorf("unsupported OOMAction %v, TiDB only supports [%v, %v]", c.OOMAction, OOMActionLog, OOMActionCancel)
 	}
+	for name, file := range map[string]string{"initialize-sql-file": c.InitializeSQLFile, "upgrade-sql-file": c.UpgradeSQLFile} {
+		if len(file) == 0 {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("can not access %s %s: %v", name, file, err)
+		}
+	}
 	if c.TableColumnCountLimit < DefTableColumnCountLimit || c.TableColumnCountLimit > DefMaxOfTableColumnCountLimit {
 		return fmt.Errorf("table-column-limit should be [%d, %d]", DefIndexLimit, DefMaxOfTableColumnCountLimit)
 	}
@@ -944,6 +1048,17 @@ func (c *Config) Valid() error {
 		return err
 	}
 
+	if err := c.TrxSummary.Valid(); err != nil {
+		return err
+	}
+
+	if c.Status.RequireClientCert && len(c.Security.ClusterSSLCA) == 0 {
+		return fmt.Errorf("require-client-cert in [status] needs cluster-ssl-ca in [security] to be set")
+	}
+	if c.Status.RequireAuth && (len(c.Security.ClusterSSLCA) == 0 || len(c.Security.ClusterSSLCert) == 0 || len(c.Security.ClusterSSLKey) == 0) {
+		return fmt.Errorf("require-auth in [status] needs cluster-ssl-ca, cluster-ssl-cert and cluster-ssl-key in [security] to be set")
+	}
+
 	if c.Performance.TxnTotalSizeLimit > 1<<40 {
 		return fmt.Errorf("txn-total-size-limit should be less than %d", 1<<40)
 	}
@@ -952,6 +1067,13 @@ func (c *Config) Valid() error {
 		return fmt.Errorf("memory-usage-alarm-ratio in [Performance] must be greater than or equal to 0 and less than or equal to 1")
 	}
 
+	if c.OpenTracing.OTLP.Enable && len(c.OpenTracing.OTLP.Endpoint) == 0 {
+		return fmt.Errorf("endpoint in [opentracing.otlp] should not be empty when it's enabled")
+	}
+	if c.OpenTracing.OTLP.SampleRate < 0 || c.OpenTracing.OTLP.SampleRate > 1 {
+		return fmt.Errorf("sample-rate in [opentracing.otlp] must be greater than or equal to 0 and less than or equal to 1")
+	}
+
 	if c.PreparedPlanCache.Capacity < 1 {
 		return fmt.Errorf("capacity in [prepared-plan-cache] should be at least 1")
 	}
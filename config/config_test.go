@@ -247,6 +247,8 @@ engines = ["tiflash"]
 foo= "bar"
 group= "abc"
 zone= "dc-1"
+[max-resource-group-connections]
+rg1 = 100
 [security]
 spilled-file-encryption-method = "plaintext"
 [pessimistic-txn]
@@ -301,6 +303,7 @@ grpc-max-send-msg-size = 40960
 	require.Equal(t, "bar", conf.Labels["foo"])
 	require.Equal(t, "abc", conf.Labels["group"])
 	require.Equal(t, "dc-1", conf.Labels["zone"])
+	require.Equal(t, map[string]uint32{"rg1": 100}, conf.MaxResourceGroupConnections)
 	require.Equal(t, SpilledFileEncryptionMethodPlaintext, conf.Security.SpilledFileEncryptionMethod)
 	require.True(t, conf.DeprecateIntegerDisplayWidth)
 	require.False(t, conf.EnableEnumLengthLimit)
@@ -641,6 +644,25 @@ func TestSecurityValid(t *testing.T) {
 	}
 }
 
+func TestStatusRequireClientCertValid(t *testing.T) {
+	conf := NewConfig()
+	conf.Status.RequireClientCert = true
+	require.Error(t, conf.Valid())
+	conf.Security.ClusterSSLCA = "ca.pem"
+	require.NoError(t, conf.Valid())
+}
+
+func TestStatusRequireAuthValid(t *testing.T) {
+	conf := NewConfig()
+	conf.Status.RequireAuth = true
+	require.Error(t, conf.Valid())
+	conf.Security.ClusterSSLCA = "ca.pem"
+	require.Error(t, conf.Valid())
+	conf.Security.ClusterSSLCert = "cert.pem"
+	conf.Security.ClusterSSLKey = "key.pem"
+	require.NoError(t, conf.Valid())
+}
+
 func TestTcpNoDelay(t *testing.T) {
 	c1 := NewConfig()
 	//check default value
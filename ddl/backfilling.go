@@ -46,15 +46,17 @@ import (
 type backfillWorkerType byte
 
 const (
-	typeAddIndexWorker     backfillWorkerType = 0
-	typeUpdateColumnWorker backfillWorkerType = 1
-	typeCleanUpIndexWorker backfillWorkerType = 2
+	typeAddIndexWorker             backfillWorkerType = 0
+	typeUpdateColumnWorker         backfillWorkerType = 1
+	typeCleanUpIndexWorker         backfillWorkerType = 2
+	typeChangeClusteredIndexWorker backfillWorkerType = 3
 )
 
 // By now the DDL jobs that need backfilling include:
 // 1: add-index
 // 2: modify-column-type
 // 3: clean-up global index
+// 4: change clustered index
 //
 // They all have a write reorganization state to back fill data into the rows existed.
 // Backfilling is time consuming, to accelerate this process, TiDB has built some sub
@@ -111,6 +113,8 @@ func (bWT backfillWorkerType) String() string {
 		return "update column"
 	case typeCleanUpIndexWorker:
 		return "clean up index"
+	case typeChangeClusteredIndexWorker:
+		return "change clustered index"
 	default:
 		return "unknown"
 	}
@@ -394,8 +398,12 @@ func (w *worker) handleReorgTasks(reorgInfo *reorgInfo, totalAddedCount *int64,
 	}
 
 	if err != nil {
-		// Update the reorg handle that has been processed.
-		err1 := reorgInfo.UpdateReorgMeta(nextKey)
+		var err1 error
+		// Update the reorg handle that has been processed. The index data written to the
+		// ingest engine isn't in the storage yet, so the reorg handle can't be updated.
+		if reorgInfo.ingestCtx == nil {
+			err1 = reorgInfo.UpdateReorgMeta(nextKey)
+		}
 		metrics.BatchAddIdxHistogram.WithLabelValues(metrics.LblError).Observe(elapsedTime.Seconds())
 		logutil.BgLogger().Warn("[ddl] backfill worker handle batch tasks failed",
 			zap.ByteString("elementType", reorgInfo.currElement.TypeKey),
@@ -411,7 +419,13 @@ func (w *worker) handleReorgTasks(reorgInfo *reorgInfo, totalAddedCount *int64,
 	}
 
 	// nextHandle will be updated periodically in runReorgJob, so no need to update it here.
-	w.reorgCtx.setNextKey(nextKey)
+	// The index data written to the ingest engine is imported at the checkpoints, the
+	// reorg handle is updated after that.
+	if reorgInfo.ingestCtx == nil {
+		w.reorgCtx.setNextKey(nextKey)
+	} else if err = w.checkpointIngest(reorgInfo, nextKey); err != nil {
+		return errors.Trace(err)
+	}
 	metrics.BatchAddIdxHistogram.WithLabelValues(metrics.LblOK).Observe(elapsedTime.Seconds())
 	logutil.BgLogger().Info("[ddl] backfill workers successfully processed batch",
 		zap.ByteString("elementType", reorgInfo.currElement.TypeKey),
@@ -544,10 +558,11 @@ func makeupDecodeColMap(sessCtx sessionctx.Context, t table.Table) (map[int64]de
 // The handle range is split from PD regions now. Each worker deal with a region table key range one time.
 // Each handle range by estimation, concurrent processing needs to perform after the handle range has been acquired.
 // The operation flow is as follows:
-//	1. Open numbers of defaultWorkers goroutines.
-//	2. Split table key range from PD regions.
-//	3. Send tasks to running workers by workers's task channel. Each task deals with a region key ranges.
-//	4. Wait all these running tasks finished, then continue to step 3, until all tasks is done.
+//  1. Open numbers of defaultWorkers goroutines.
+//  2. Split table key range from PD regions.
+//  3. Send tasks to running workers by workers's task channel. Each task deals with a region key ranges.
+//  4. Wait all these running tasks finished, then continue to step 3, until all tasks is done.
+//
 // The above operations are completed in a transaction.
 // Finally, update the concurrent processing of the total number of rows, and store the completed handle value.
 func (w *worker) writePhysicalTableRecord(t table.PhysicalTable, bfWorkerType backfillWorkerType, indexInfo *model.IndexInfo, oldColInfo, colInfo *model.ColumnInfo, reorgInfo *reorgInfo) error {
@@ -620,6 +635,10 @@ func (w *worker) writePhysicalTableRecord(t table.PhysicalTable, bfWorkerType ba
 			case typeAddIndexWorker:
 				idxWorker := newAddIndexWorker(sessCtx, w, i, t, indexInfo, decodeColMap, reorgInfo.ReorgMeta.SQLMode)
 				idxWorker.priority = job.Priority
+				if reorgInfo.ingestCtx != nil {
+					idxWorker.ingestEngine = reorgInfo.ingestCtx.engine
+					idxWorker.snapshotVer = reorgInfo.SnapshotVer
+				}
 				backfillWorkers = append(backfillWorkers, idxWorker.backfillWorker)
 				go idxWorker.backfillWorker.run(reorgInfo.d, idxWorker, job)
 			case typeUpdateColumnWorker:
@@ -634,6 +653,11 @@ func (w *worker) writePhysicalTableRecord(t table.PhysicalTable, bfWorkerType ba
 				idxWorker.priority = job.Priority
 				backfillWorkers = append(backfillWorkers, idxWorker.backfillWorker)
 				go idxWorker.backfillWorker.run(reorgInfo.d, idxWorker, job)
+			case typeChangeClusteredIndexWorker:
+				tblWorker := newChangeClusteredIndexWorker(sessCtx, w, i, t, decodeColMap)
+				tblWorker.priority = job.Priority
+				backfillWorkers = append(backfillWorkers, tblWorker.backfillWorker)
+				go tblWorker.backfillWorker.run(reorgInfo.d, tblWorker, job)
 			default:
 				return errors.New("unknow backfill type")
 			}
@@ -0,0 +1,485 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/ddl/placement"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
+	"github.com/pingcap/tidb/domain/infosync"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	decoder "github.com/pingcap/tidb/util/rowDecoder"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Changing the clustered index of a table rewrites all of its rows, because the
+// record keys of a clustered table are encoded by the primary key while the ones
+// of a non-clustered table are encoded by the row ID. The job works as follows:
+//   none -> delete only -> write only -> write reorganization -> public
+// A changing table with the target layout and a new physical table ID is attached
+// to the table, the DMLs write into it once it's write only, and the existing rows
+// are backfilled into it in the write reorganization state. At last the changing
+// table replaces the table, just like truncating a table, and the data of the old
+// physical table is cleaned up by the delete range.
+
+// checkAlterTableClusteredIndex checks whether the clustered index of the table can be changed.
+func checkAlterTableClusteredIndex(tblInfo *model.TableInfo, clustered bool) error {
+	if tblInfo.ChangingTable != nil {
+		return errUnsupportedAlterClusteredIndex.GenWithStackByArgs("table which is being changed")
+	}
+	if tblInfo.HasClusteredIndex() == clustered {
+		return errUnsupportedAlterClusteredIndex.GenWithStackByArgs("table which is already in the target layout")
+	}
+	if tblInfo.TempTableType != model.TempTableNone {
+		return ErrOptOnTemporaryTable.GenWithStackByArgs("alter clustered index")
+	}
+	if tblInfo.TableCacheStatusType != model.TableCacheStatusDisable {
+		return ErrOptOnCacheTable.GenWithStackByArgs("alter clustered index")
+	}
+	if tblInfo.Partition != nil {
+		return errUnsupportedAlterClusteredIndex.GenWithStackByArgs("partitioned table")
+	}
+	if tblInfo.TiFlashReplica != nil {
+		return errUnsupportedAlterClusteredIndex.GenWithStackByArgs("table with TiFlash replica")
+	}
+	if tblInfo.Lock != nil {
+		return errUnsupportedAlterClusteredIndex.GenWithStackByArgs("locked table")
+	}
+	if tblInfo.ContainsAutoRandomBits() {
+		return errUnsupportedAlterClusteredIndex.GenWithStackByArgs("table with auto_random column")
+	}
+	if !hasPrimaryKey(tblInfo) {
+		return errUnsupportedAlterClusteredIndex.GenWithStackByArgs("table without primary key")
+	}
+	if clustered {
+		if tblInfo.ShardRowIDBits > 0 {
+			return errUnsupportedShardRowIDBits
+		}
+		if pkIdx := tables.FindPrimaryIndex(tblInfo); pkIdx != nil && pkIdx.Invisible {
+			return ErrPKIndexCantBeInvisible
+		}
+	}
+	return nil
+}
+
+// buildClusteredIndexChangingTable builds the changing table which has the target layout of the primary key.
+func buildClusteredIndexChangingTable(tblInfo *model.TableInfo, clustered bool, newTableID int64) *model.TableInfo {
+	changing := tblInfo.Clone()
+	changing.ID = newTableID
+	changing.ChangingTable = nil
+	pkIdx := tables.FindPrimaryIndex(changing)
+	if clustered {
+		if len(pkIdx.Columns) == 1 && isIntHandleType(changing.Columns[pkIdx.Columns[0].Offset].Tp) {
+			// An integer primary key is used as the row handle directly.
+			changing.PKIsHandle = true
+			indices := make([]*model.IndexInfo, 0, len(changing.Indices)-1)
+			for _, idx := range changing.Indices {
+				if !idx.Primary {
+					indices = append(indices, idx)
+				}
+			}
+			changing.Indices = indices
+		} else {
+			changing.IsCommonHandle = true
+			changing.CommonHandleVersion = 1
+		}
+		return changing
+	}
+
+	if changing.PKIsHandle {
+		pkCol := changing.GetPkColInfo()
+		changing.Indices = append(changing.Indices, &model.IndexInfo{
+			ID:   allocateIndexID(changing),
+			Name: model.NewCIStr(mysql.PrimaryKeyName),
+			Columns: []*model.IndexColumn{{
+				Name:   pkCol.Name,
+				Offset: pkCol.Offset,
+				Length: types.UnspecifiedLength,
+			}},
+			State:   model.StatePublic,
+			Unique:  true,
+			Primary: true,
+			Tp:      model.IndexTypeBtree,
+		})
+		changing.PKIsHandle = false
+	} else {
+		changing.IsCommonHandle = false
+		changing.CommonHandleVersion = 0
+	}
+	return changing
+}
+
+// isIntHandleType checks whether the column of the type can be used as the row handle.
+func isIntHandleType(tp byte) bool {
+	switch tp {
+	case mysql.TypeLong, mysql.TypeLonglong,
+		mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24:
+		return true
+	}
+	return false
+}
+
+func (w *worker) onAlterTableClusteredIndex(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var clustered bool
+	var newTableID int64
+	if err := job.DecodeArgs(&clustered, &newTableID); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	schemaID := job.SchemaID
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if job.IsRollingback() {
+		return rollbackAlterTableClusteredIndex(t, job, tblInfo)
+	}
+
+	if tblInfo.ChangingTable == nil {
+		// none -> delete only
+		if err = checkAlterTableClusteredIndex(tblInfo, clustered); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		tblInfo.ChangingTable = buildClusteredIndexChangingTable(tblInfo, clustered, newTableID)
+		tblInfo.ChangingTable.State = model.StateDeleteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateDeleteOnly
+		return ver, nil
+	}
+
+	changing := tblInfo.ChangingTable
+	switch changing.State {
+	case model.StateDeleteOnly:
+		// delete only -> write only
+		changing.State = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> reorganization
+		changing.State = model.StateWriteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Initialize SnapshotVer to 0 for later reorganization check.
+		job.SnapshotVer = 0
+		job.SchemaState = model.StateWriteReorganization
+	case model.StateWriteReorganization:
+		tbl, err := getTable(d.store, schemaID, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		elements := []*meta.Element{{ID: changing.ID, TypeKey: meta.TableElementKey}}
+		reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
+		if err != nil || reorgInfo.first {
+			// If we run reorg firstly, we should update the job snapshot version
+			// and then run the reorg next time.
+			return ver, errors.Trace(err)
+		}
+
+		err = w.runReorgJob(t, reorgInfo, tblInfo, d.lease, func() (changeErr error) {
+			defer util.Recover(metrics.LabelDDL, "onAlterTableClusteredIndex",
+				func() {
+					changeErr = errCancelledDDLJob.GenWithStack("change clustered index of table `%v` panic", tblInfo.Name)
+				}, false)
+			return w.writePhysicalTableRecord(tbl.(table.PhysicalTable), typeChangeClusteredIndexWorker, nil, nil, nil, reorgInfo)
+		})
+		if err != nil {
+			if errWaitReorgTimeout.Equal(err) {
+				// If timeout, we should return, check for the owner and re-wait job done.
+				return ver, nil
+			}
+			if kv.IsTxnRetryableError(err) {
+				// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+				w.reorgCtx.cleanNotifyReorgCancel()
+				return ver, errors.Trace(err)
+			}
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run change clustered index job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
+					zap.String("job", job.String()), zap.Error(err1))
+			}
+			logutil.BgLogger().Warn("[ddl] run change clustered index job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			job.State = model.JobStateRollingback
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
+			return ver, errors.Trace(err)
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return replaceWithChangingTable(d, t, job, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("table", changing.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+// replaceWithChangingTable replaces the table with its changing table, the physical table ID
+// of the table is changed to the one of the changing table.
+func replaceWithChangingTable(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (ver int64, _ error) {
+	schemaID, oldTableID := job.SchemaID, tblInfo.ID
+	newTblInfo := tblInfo.ChangingTable
+	newTblInfo.State = model.StatePublic
+
+	// The auto IDs are allocated from the old table, move them to the new one.
+	autoIDs, err := t.GetAutoIDAccessors(schemaID, oldTableID).Get()
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	err = t.DropTableOrView(schemaID, oldTableID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	err = t.GetAutoIDAccessors(schemaID, oldTableID).Del()
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	tableRuleID, partRuleIDs, _, oldRules, err := getOldLabelRules(tblInfo, job.SchemaName, tblInfo.Name.L)
+	if err != nil {
+		return ver, errors.Wrapf(err, "failed to get old label rules from PD")
+	}
+	err = updateLabelRules(job, tblInfo, oldRules, tableRuleID, partRuleIDs, []string{}, newTblInfo.ID)
+	if err != nil {
+		return ver, errors.Wrapf(err, "failed to update the label rule to PD")
+	}
+	bundles, err := placement.NewFullTableBundles(t, newTblInfo)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	err = infosync.PutRuleBundlesWithDefaultRetry(context.TODO(), bundles)
+	if err != nil {
+		return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+	}
+
+	err = t.CreateTableOrView(schemaID, newTblInfo)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	err = t.GetAutoIDAccessors(schemaID, newTblInfo.ID).Put(autoIDs)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	job.SchemaState = model.StatePublic
+	ver, err = updateSchemaVersion(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, newTblInfo)
+	asyncNotifyEvent(d, &ddlutil.Event{Tp: model.ActionAlterTableClusteredIndex, TableInfo: newTblInfo})
+	// Refactor the job args to add the old physical table into delete range table.
+	job.Args = []interface{}{oldTableID}
+	return ver, nil
+}
+
+func rollbackAlterTableClusteredIndex(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (ver int64, err error) {
+	changing := tblInfo.ChangingTable
+	if changing == nil {
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	}
+	tblInfo.ChangingTable = nil
+	ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	// Refactor the job args to add the abandoned changing table into delete range table.
+	job.Args = []interface{}{changing.ID}
+	return ver, nil
+}
+
+type changeClusteredIndexWorker struct {
+	*backfillWorker
+	metricCounter prometheus.Counter
+
+	// The following attributes are used to reduce memory allocation.
+	rowRecords  []*changingRowRecord
+	rowDecoder  *decoder.RowDecoder
+	rowMap      map[int64]types.Datum
+	defaultVals []types.Datum
+}
+
+type changingRowRecord struct {
+	key  []byte        // It's used to lock a record. Record it to reduce the encoding time.
+	vals []types.Datum // It's the row to be written into the changing table.
+}
+
+func newChangeClusteredIndexWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, decodeColMap map[int64]decoder.Column) *changeClusteredIndexWorker {
+	rowDecoder := decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap)
+	return &changeClusteredIndexWorker{
+		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+		metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("change_clustered_index_rate"),
+		rowDecoder:     rowDecoder,
+		rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
+		defaultVals:    make([]types.Datum, len(t.WritableCols())),
+	}
+}
+
+func (w *changeClusteredIndexWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+// getNextKey gets next handle of entry that we are going to process.
+func (w *changeClusteredIndexWorker) getNextKey(taskRange reorgBackfillTask,
+	taskDone bool, lastAccessedHandle kv.Key) (nextHandle kv.Key) {
+	if !taskDone {
+		// The task is not done. So we need to pick the last processed entry's handle and add one.
+		return lastAccessedHandle.Next()
+	}
+
+	return taskRange.endKey.Next()
+}
+
+func (w *changeClusteredIndexWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBackfillTask) ([]*changingRowRecord, kv.Key, bool, error) {
+	w.rowRecords = w.rowRecords[:0]
+	startTime := time.Now()
+
+	// taskDone means that the added handle is out of taskRange.endHandle.
+	taskDone := false
+	var lastAccessedHandle kv.Key
+	oprStartTime := startTime
+	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), taskRange.startKey, taskRange.endKey,
+		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+			oprEndTime := time.Now()
+			logSlowOperations(oprEndTime.Sub(oprStartTime), "iterateSnapshotRows in changeClusteredIndexWorker fetchRowColVals", 0)
+			oprStartTime = oprEndTime
+
+			taskDone = recordKey.Cmp(taskRange.endKey) > 0
+
+			if taskDone || len(w.rowRecords) >= w.batchCnt {
+				return false, nil
+			}
+
+			if err1 := w.getRowRecord(handle, recordKey, rawRow); err1 != nil {
+				return false, errors.Trace(err1)
+			}
+			lastAccessedHandle = recordKey
+			if recordKey.Cmp(taskRange.endKey) == 0 {
+				// If taskRange.endIncluded == false, we will not reach here when handle == taskRange.endHandle.
+				taskDone = true
+				return false, nil
+			}
+			return true, nil
+		})
+
+	if len(w.rowRecords) == 0 {
+		taskDone = true
+	}
+
+	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", txn.StartTS()), zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
+	return w.rowRecords, w.getNextKey(taskRange, taskDone, lastAccessedHandle), taskDone, errors.Trace(err)
+}
+
+func (w *changeClusteredIndexWorker) getRowRecord(handle kv.Handle, recordKey []byte, rawRow []byte) error {
+	sysZone := timeutil.SystemLocation()
+	_, err := w.rowDecoder.DecodeAndEvalRowWithMap(w.sessCtx, handle, rawRow, time.UTC, sysZone, w.rowMap)
+	if err != nil {
+		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("row", err))
+	}
+
+	cols := w.table.WritableCols()
+	row := make([]types.Datum, len(cols))
+	for _, col := range cols {
+		val, ok := w.rowMap[col.ID]
+		if !ok {
+			val, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if val.Kind() == types.KindMysqlTime {
+				t := val.GetMysqlTime()
+				if t.Type() == mysql.TypeTimestamp && sysZone != time.UTC {
+					if err = t.ConvertTimeZone(sysZone, time.UTC); err != nil {
+						return errors.Trace(err)
+					}
+					val.SetMysqlTime(t)
+				}
+			}
+		}
+		row[col.Offset] = val
+	}
+
+	w.rowRecords = append(w.rowRecords, &changingRowRecord{key: recordKey, vals: row})
+	w.cleanRowMap()
+	return nil
+}
+
+func (w *changeClusteredIndexWorker) cleanRowMap() {
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+}
+
+// BackfillDataInTxn will backfill the rows into the changing table in a transaction.
+// The rows which are already written by the DMLs are skipped.
+func (w *changeClusteredIndexWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(kv.Priority, w.priority)
+
+		rowRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		for _, record := range rowRecords {
+			taskCtx.scanCount++
+
+			// Lock the row in the table, so the concurrent DMLs on the row conflict with the backfill.
+			err = txn.LockKeys(context.Background(), new(kv.LockCtx), record.key)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			added, err := tables.BackfillChangingRecord(w.sessCtx, txn, w.table, record.vals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if added {
+				taskCtx.addedCount++
+			}
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "BackfillDataInTxn", 3000)
+
+	return
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
@@ -699,6 +700,10 @@ func needChangeColumnData(oldCol, newCol *model.ColumnInfo) bool {
 		return (defaultNewColFlen > 0 && defaultNewColFlen < defaultOldColFlen) || (toUnsigned != originUnsigned)
 	}
 
+	if needReorgToChangeCharset(&oldCol.FieldType, &newCol.FieldType) {
+		return true
+	}
+
 	// Deal with the same type.
 	if oldCol.Tp == newCol.Tp {
 		switch oldCol.Tp {
@@ -751,6 +756,16 @@ func convertBetweenCharAndVarchar(oldCol, newCol byte) bool {
 		(oldCol == mysql.TypeString && types.IsTypeVarchar(newCol) && collate.NewCollationEnabled())
 }
 
+// needReorgToChangeCharset returns true if the charset of a string column is changed and the data
+// can't be kept as is. Only changing utf8 to utf8mb4 is lossless, the other changes need to convert
+// and validate every row against the new charset in the reorganization.
+func needReorgToChangeCharset(origin, to *types.FieldType) bool {
+	if !types.IsString(origin.Tp) || !types.IsString(to.Tp) || origin.Charset == to.Charset {
+		return false
+	}
+	return !(origin.Charset == charset.CharsetUTF8 && to.Charset == charset.CharsetUTF8MB4)
+}
+
 func isElemsChangedToModifyColumn(oldElems, newElems []string) bool {
 	if len(newElems) < len(oldElems) {
 		return true
@@ -772,11 +787,12 @@ type modifyColumnJobParameter struct {
 	changingCol           *model.ColumnInfo
 	changingIdxs          []*model.IndexInfo
 	pos                   *ast.ColumnPosition
+	renamedViews          []*renamedColumnView
 }
 
 func getModifyColumnInfo(t *meta.Meta, job *model.Job) (*model.DBInfo, *model.TableInfo, *model.ColumnInfo, *modifyColumnJobParameter, error) {
 	jobParam := &modifyColumnJobParameter{pos: &ast.ColumnPosition{}}
-	err := job.DecodeArgs(&jobParam.newCol, &jobParam.oldColName, jobParam.pos, &jobParam.modifyColumnTp, &jobParam.updatedAutoRandomBits, &jobParam.changingCol, &jobParam.changingIdxs)
+	err := job.DecodeArgs(&jobParam.newCol, &jobParam.oldColName, jobParam.pos, &jobParam.modifyColumnTp, &jobParam.updatedAutoRandomBits, &jobParam.changingCol, &jobParam.changingIdxs, &jobParam.renamedViews)
 	if err != nil {
 		job.State = model.JobStateCancelled
 		return nil, nil, nil, jobParam, errors.Trace(err)
@@ -873,7 +889,7 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 	}
 
 	if !needChangeColumnData(oldCol, jobParam.newCol) {
-		return w.doModifyColumn(d, t, job, dbInfo, tblInfo, jobParam.newCol, oldCol, jobParam.pos)
+		return w.doModifyColumn(d, t, job, dbInfo, tblInfo, jobParam.newCol, oldCol, jobParam.pos, jobParam.renamedViews)
 	}
 
 	if err = isGeneratedRelatedColumn(tblInfo, jobParam.newCol, oldCol); err != nil {
@@ -1350,7 +1366,7 @@ func (w *updateColumnWorker) getRowRecord(handle kv.Handle, recordKey []byte, ra
 			w.rowMap[w.oldColInfo.ID] = v
 		}
 	}
-	newColVal, err := table.CastValue(w.sessCtx, w.rowMap[w.oldColInfo.ID], w.newColInfo, false, false)
+	newColVal, err := table.CastChangingColumnValue(w.sessCtx, w.rowMap[w.oldColInfo.ID], w.oldColInfo, w.newColInfo)
 	if err != nil {
 		return w.reformatErrors(err)
 	}
@@ -1473,7 +1489,7 @@ func updateChangingInfo(changingCol *model.ColumnInfo, changingIdxs []*model.Ind
 // doModifyColumn updates the column information and reorders all columns. It does not support modifying column data.
 func (w *worker) doModifyColumn(
 	d *ddlCtx, t *meta.Meta, job *model.Job, dbInfo *model.DBInfo, tblInfo *model.TableInfo,
-	newCol, oldCol *model.ColumnInfo, pos *ast.ColumnPosition) (ver int64, _ error) {
+	newCol, oldCol *model.ColumnInfo, pos *ast.ColumnPosition, renamedViews []*renamedColumnView) (ver int64, _ error) {
 	if oldCol.ID != newCol.ID {
 		job.State = model.JobStateRollingback
 		return ver, errKeyColumnDoesNotExits.GenWithStack("column %s id %d does not exist, this column may have been updated by other DDL ran in parallel", oldCol.Name, newCol.ID)
@@ -1502,10 +1518,25 @@ func (w *worker) doModifyColumn(
 		}
 	}
 
+	oldColName := oldCol.Name
 	if err := adjustColumnInfoInModifyColumn(job, tblInfo, newCol, oldCol, pos, ""); err != nil {
 		return ver, errors.Trace(err)
 	}
 
+	// Rename the column in the expressions and the views which depend on it.
+	if newCol.Name.L != oldColName.L {
+		if err := renameColumnInDependentExprs(tblInfo, oldColName, newCol.Name); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		affects, err := applyRenamedColumnViews(t, job, renamedViews)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// affects are used to reload the rewritten views.
+		job.CtxVars = []interface{}{affects}
+	}
+
 	ver, err := updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
 	if err != nil {
 		// Modified the type definition of 'null' to 'not null' before this, so rollBack the job when an error occurs.
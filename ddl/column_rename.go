@@ -0,0 +1,374 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/util/generatedexpr"
+)
+
+// renamedColumnView records the definition of a view which is rewritten because
+// a column referenced by the view is renamed.
+type renamedColumnView struct {
+	SchemaID  int64  `json:"schema_id"`
+	ViewID    int64  `json:"view_id"`
+	OldSelect string `json:"old_select"`
+	NewSelect string `json:"new_select"`
+}
+
+// renameColumnInExpr parses the expression string, replaces the references of
+// oldName with newName and restores it. The restored string is returned with
+// a flag that indicates whether the expression is changed.
+func renameColumnInExpr(exprStr string, oldName, newName model.CIStr) (string, bool, error) {
+	expr, err := generatedexpr.ParseExpression(exprStr)
+	if err != nil {
+		return "", false, errors.Trace(err)
+	}
+	changed := false
+	for _, name := range findColumnNamesInExpr(expr) {
+		if name.Name.L == oldName.L {
+			name.Name = newName
+			changed = true
+		}
+	}
+	if !changed {
+		return exprStr, false, nil
+	}
+	var sb strings.Builder
+	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameBackQuotes |
+		format.RestoreSpacesAroundBinaryOperation
+	if err = expr.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return "", false, errors.Trace(err)
+	}
+	return sb.String(), true, nil
+}
+
+// renameColumnInDependentExprs rewrites the generated columns, the expression indexes
+// and the check constraints of the table which refer to the renamed column.
+func renameColumnInDependentExprs(tblInfo *model.TableInfo, oldName, newName model.CIStr) error {
+	for _, col := range tblInfo.Columns {
+		if !col.IsGenerated() {
+			continue
+		}
+		if _, ok := col.Dependences[oldName.L]; !ok {
+			continue
+		}
+		exprStr, changed, err := renameColumnInExpr(col.GeneratedExprString, oldName, newName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !changed {
+			continue
+		}
+		col.GeneratedExprString = exprStr
+		delete(col.Dependences, oldName.L)
+		col.Dependences[newName.L] = struct{}{}
+	}
+	for _, cons := range tblInfo.Constraints {
+		exprStr, changed, err := renameColumnInExpr(cons.ExprString, oldName, newName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !changed {
+			continue
+		}
+		cons.ExprString = exprStr
+		for i := range cons.ConstraintCols {
+			if cons.ConstraintCols[i].L == oldName.L {
+				cons.ConstraintCols[i] = newName
+			}
+		}
+	}
+	return nil
+}
+
+// buildRenamedColumnViews finds the views which refer to the column of the table and
+// rewrites their definitions with the new column name.
+func buildRenamedColumnViews(is infoschema.InfoSchema, schema, tbl, oldName, newName model.CIStr) ([]*renamedColumnView, error) {
+	var views []*renamedColumnView
+	for _, db := range is.AllSchemas() {
+		for _, t := range is.SchemaTables(db.Name) {
+			tblInfo := t.Meta()
+			if !tblInfo.IsView() {
+				continue
+			}
+			stmt, err := parser.New().ParseOneStmt(tblInfo.View.SelectStmt, "", "")
+			if err != nil {
+				// The view is broken already, leave it alone.
+				continue
+			}
+			renamer := &viewColumnRenamer{
+				is:         is,
+				viewSchema: db.Name,
+				schema:     schema,
+				table:      tbl,
+				oldName:    oldName,
+				newName:    newName,
+				renamed:    make(map[*ast.ColumnName]struct{}),
+			}
+			stmt.Accept(renamer)
+			if len(renamer.renamed) == 0 {
+				continue
+			}
+			// Keep consistent with `buildViewInfo`.
+			restoreFlag := format.RestoreStringSingleQuotes | format.RestoreKeyWordUppercase | format.RestoreNameBackQuotes
+			var sb strings.Builder
+			if err := stmt.Restore(format.NewRestoreCtx(restoreFlag, &sb)); err != nil {
+				return nil, errors.Trace(err)
+			}
+			views = append(views, &renamedColumnView{
+				SchemaID:  db.ID,
+				ViewID:    tblInfo.ID,
+				OldSelect: tblInfo.View.SelectStmt,
+				NewSelect: sb.String(),
+			})
+		}
+	}
+	return views, nil
+}
+
+// applyRenamedColumnViews updates the definitions of the views in the same transaction
+// as the renamed column. It returns the affected options used to reload the views.
+func applyRenamedColumnViews(t *meta.Meta, job *model.Job, views []*renamedColumnView) ([]*model.AffectedOption, error) {
+	affects := make([]*model.AffectedOption, 0, len(views))
+	for _, v := range views {
+		viewInfo, err := t.GetTable(v.SchemaID, v.ViewID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// The view has been dropped.
+		if viewInfo == nil || !viewInfo.IsView() {
+			continue
+		}
+		if viewInfo.View.SelectStmt != v.OldSelect {
+			job.State = model.JobStateCancelled
+			return nil, ErrInvalidDDLState.GenWithStack("view %s has been changed, please retry", viewInfo.Name)
+		}
+		viewInfo.View.SelectStmt = v.NewSelect
+		if err = t.UpdateTable(v.SchemaID, viewInfo); err != nil {
+			return nil, errors.Trace(err)
+		}
+		affects = append(affects, &model.AffectedOption{
+			SchemaID:    v.SchemaID,
+			OldSchemaID: v.SchemaID,
+			TableID:     v.ViewID,
+			OldTableID:  v.ViewID,
+		})
+	}
+	return affects, nil
+}
+
+// renameColumnSource is a data source in the FROM clause of a view.
+type renameColumnSource struct {
+	schema string
+	name   string
+	target bool
+	// cols is nil if the columns of the source are unknown.
+	cols map[string]struct{}
+}
+
+// renameColumnScope is the name resolution scope of a SELECT block.
+type renameColumnScope struct {
+	sources []*renameColumnSource
+	aliases map[string]struct{}
+	// inAliasClause is positive in the HAVING and ORDER BY clauses where the
+	// field aliases take precedence over the columns.
+	inAliasClause int
+	// barrier stops resolving the names in the outer scopes, it is used for derived tables.
+	barrier bool
+}
+
+// viewColumnRenamer renames the references of a table column in a view definition.
+type viewColumnRenamer struct {
+	is         infoschema.InfoSchema
+	viewSchema model.CIStr
+	schema     model.CIStr
+	table      model.CIStr
+	oldName    model.CIStr
+	newName    model.CIStr
+
+	scopes  []*renameColumnScope
+	renamed map[*ast.ColumnName]struct{}
+}
+
+// Enter implements ast.Visitor interface.
+func (r *viewColumnRenamer) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.SelectStmt:
+		r.scopes = append(r.scopes, r.buildScope(x))
+	case *ast.TableSource:
+		if _, ok := x.Source.(*ast.TableName); !ok {
+			r.scopes = append(r.scopes, &renameColumnScope{barrier: true})
+		}
+	case *ast.HavingClause, *ast.OrderByClause:
+		if len(r.scopes) > 0 {
+			r.scopes[len(r.scopes)-1].inAliasClause++
+		}
+	}
+	return in, false
+}
+
+// Leave implements ast.Visitor interface.
+func (r *viewColumnRenamer) Leave(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.SelectStmt:
+		r.scopes = r.scopes[:len(r.scopes)-1]
+	case *ast.TableSource:
+		if _, ok := x.Source.(*ast.TableName); !ok {
+			r.scopes = r.scopes[:len(r.scopes)-1]
+		}
+	case *ast.HavingClause, *ast.OrderByClause:
+		if len(r.scopes) > 0 {
+			r.scopes[len(r.scopes)-1].inAliasClause--
+		}
+	case *ast.ColumnNameExpr:
+		if r.refersToTarget(x.Name) {
+			x.Name.Name = r.newName
+			r.renamed[x.Name] = struct{}{}
+		}
+	case *ast.SelectField:
+		// Keep the output name of the field unchanged.
+		if colExpr, ok := x.Expr.(*ast.ColumnNameExpr); ok && x.AsName.L == "" {
+			if _, ok := r.renamed[colExpr.Name]; ok {
+				x.AsName = r.oldName
+			}
+		}
+	}
+	return in, true
+}
+
+func (r *viewColumnRenamer) buildScope(sel *ast.SelectStmt) *renameColumnScope {
+	scope := &renameColumnScope{aliases: make(map[string]struct{})}
+	if sel.Fields != nil {
+		for _, f := range sel.Fields.Fields {
+			if f.AsName.L != "" {
+				scope.aliases[f.AsName.L] = struct{}{}
+			}
+		}
+	}
+	if sel.From != nil {
+		r.collectSources(sel.From.TableRefs, scope)
+	}
+	return scope
+}
+
+func (r *viewColumnRenamer) collectSources(node ast.ResultSetNode, scope *renameColumnScope) {
+	switch x := node.(type) {
+	case *ast.Join:
+		r.collectSources(x.Left, scope)
+		if x.Right != nil {
+			r.collectSources(x.Right, scope)
+		}
+	case *ast.TableSource:
+		src := &renameColumnSource{name: x.AsName.L}
+		switch s := x.Source.(type) {
+		case *ast.TableName:
+			schema := s.Schema
+			if schema.L == "" {
+				schema = r.viewSchema
+			}
+			if src.name == "" {
+				src.schema, src.name = schema.L, s.Name.L
+			}
+			src.target = schema.L == r.schema.L && s.Name.L == r.table.L
+			if tbl, err := r.is.TableByName(schema, s.Name); err == nil {
+				src.cols = make(map[string]struct{}, len(tbl.Meta().Columns))
+				for _, col := range tbl.Meta().Columns {
+					src.cols[col.Name.L] = struct{}{}
+				}
+			}
+		case *ast.SelectStmt:
+			src.cols = derivedColumnNames(s)
+		}
+		scope.sources = append(scope.sources, src)
+	}
+}
+
+// derivedColumnNames returns the output column names of the derived table, nil is
+// returned if the names can't be inferred.
+func derivedColumnNames(sel *ast.SelectStmt) map[string]struct{} {
+	if sel.Fields == nil {
+		return nil
+	}
+	cols := make(map[string]struct{}, len(sel.Fields.Fields))
+	for _, f := range sel.Fields.Fields {
+		switch {
+		case f.AsName.L != "":
+			cols[f.AsName.L] = struct{}{}
+		case f.WildCard != nil:
+			return nil
+		default:
+			if colExpr, ok := f.Expr.(*ast.ColumnNameExpr); ok {
+				cols[colExpr.Name.Name.L] = struct{}{}
+			}
+		}
+	}
+	return cols
+}
+
+// refersToTarget checks whether the column name refers to the renamed column.
+func (r *viewColumnRenamer) refersToTarget(name *ast.ColumnName) bool {
+	if name.Name.L != r.oldName.L {
+		return false
+	}
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		scope := r.scopes[i]
+		if scope.barrier {
+			return false
+		}
+		if name.Table.L != "" {
+			for _, src := range scope.sources {
+				if src.name == name.Table.L && (name.Schema.L == "" || name.Schema.L == src.schema) {
+					return src.target
+				}
+			}
+			continue
+		}
+		if scope.inAliasClause > 0 {
+			if _, ok := scope.aliases[name.Name.L]; ok {
+				return false
+			}
+		}
+		foundTarget, foundOther := false, false
+		for _, src := range scope.sources {
+			if src.target {
+				foundTarget = true
+				continue
+			}
+			if src.cols == nil {
+				foundOther = true
+				continue
+			}
+			if _, ok := src.cols[name.Name.L]; ok {
+				foundOther = true
+			}
+		}
+		if foundOther {
+			return false
+		}
+		if foundTarget {
+			return true
+		}
+	}
+	return false
+}
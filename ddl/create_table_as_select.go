@@ -0,0 +1,527 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/ddl/placement"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
+	"github.com/pingcap/tidb/domain/infosync"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// Creating a table by CREATE TABLE ... SELECT works as follows:
+//   none -> write reorganization -> public
+// The table is created in the write reorganization state, in which it's only accessible
+// by the internal SQLs, then the select is executed on the DDL owner and its rows are
+// inserted into the table in batches by tidb_ddl_reorg_worker_cnt workers. At last the
+// table becomes public with all the rows. If the rows can't be inserted, the job is
+// cancelled, or the DDL owner changes while inserting the rows, the table is dropped and
+// the inserted data is cleaned up by the delete range.
+
+// createTableAsSelectSysVars are the system variables of the session which are used by the
+// DDL owner to execute the select of CREATE TABLE ... SELECT. The internal sessions of the DDL owner don't
+// load the global system variables, so the variables read by the functions are set too.
+var createTableAsSelectSysVars = []string{variable.TimeZone, variable.CharacterSetConnection, variable.CollationConnection,
+	variable.MaxAllowedPacket, variable.DefaultWeekFormat, variable.BlockEncryptionMode}
+
+// createTableAsSelectCheckInterval is the interval to check the progress and the cancellation
+// of inserting the rows of CREATE TABLE ... SELECT.
+const createTableAsSelectCheckInterval = 100 * time.Millisecond
+
+// createTableAsSelectArgs are the arguments of the CREATE TABLE ... SELECT job besides the table.
+type createTableAsSelectArgs struct {
+	selectSQL   string
+	selectCols  []model.CIStr
+	onDuplicate ast.OnDuplicateKeyHandlingType
+	currentDB   string
+	sysVars     map[string]string
+}
+
+func (w *worker) onCreateTableAsSelect(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	tbInfo := &model.TableInfo{}
+	args := &createTableAsSelectArgs{}
+	err := job.DecodeArgs(tbInfo, &args.selectSQL, &args.selectCols, &args.onDuplicate, &args.currentDB, &args.sysVars)
+	if err != nil {
+		// Invalid arguments, cancel this job.
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	if job.IsRollingback() {
+		return rollbackCreateTableAsSelect(t, job)
+	}
+
+	switch job.SchemaState {
+	case model.StateNone:
+		// none -> write reorganization
+		tbInfo, err = createTable(d, t, job)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		tbInfo.State = model.StateWriteReorganization
+		if err = t.UpdateTable(job.SchemaID, tbInfo); err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Default auto IDs base is 0, rebase them if the first ID is expected to be greater than 1.
+		if tbInfo.AutoIncID > 1 {
+			if err = t.GetAutoIDAccessors(job.SchemaID, tbInfo.ID).RowID().Put(tbInfo.AutoIncID - 1); err != nil {
+				return ver, errors.Trace(err)
+			}
+		}
+		if tbInfo.AutoRandID > 1 {
+			if err = t.GetAutoIDAccessors(job.SchemaID, tbInfo.ID).RandomID().Put(tbInfo.AutoRandID - 1); err != nil {
+				return ver, errors.Trace(err)
+			}
+		}
+		ver, err = updateSchemaVersion(t, job)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Initialize SnapshotVer to 0 for later reorganization check.
+		job.SnapshotVer = 0
+		job.SchemaState = model.StateWriteReorganization
+		asyncNotifyEvent(d, &ddlutil.Event{Tp: model.ActionCreateTable, TableInfo: tbInfo})
+		return ver, nil
+	case model.StateWriteReorganization:
+		tblInfo, err := getTableInfo(t, job.TableID, job.SchemaID)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		if job.SnapshotVer == 0 {
+			// Record that the rows are being inserted before inserting them, so the job is rolled back
+			// by the cancellation from now on, and insert them the next time.
+			job.SnapshotVer = t.StartTS
+			return ver, nil
+		}
+
+		err = w.runCreateTableAsSelectJob(d, job, tblInfo, args)
+		if err != nil {
+			if errWaitReorgTimeout.Equal(err) {
+				// If timeout, we should return, check for the owner and re-wait job done.
+				return ver, nil
+			}
+			logutil.BgLogger().Warn("[ddl] run create table as select job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			job.State = model.JobStateRollingback
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
+			return ver, errors.Trace(err)
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+
+		// write reorganization -> public
+		tblInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		return ver, nil
+	default:
+		return ver, ErrInvalidDDLState.GenWithStackByArgs("table", job.SchemaState)
+	}
+}
+
+// runCreateTableAsSelectJob inserts the select result into the table in the background, and waits
+// for it in the same way as runReorgJob. The count of the inserted rows is updated into the job.
+func (w *worker) runCreateTableAsSelectJob(d *ddlCtx, job *model.Job, tblInfo *model.TableInfo, args *createTableAsSelectArgs) error {
+	rc := w.reorgCtx
+	if rc.doneCh == nil {
+		sctx, err := w.sessPool.get()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		rc.setRowCount(0)
+		rc.resetWarnings()
+		rc.doneCh = make(chan error, 1)
+		dbName, sqlMode := job.SchemaName, job.ReorgMeta.SQLMode
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			defer w.sessPool.put(sctx)
+			rc.doneCh <- w.insertTableAsSelect(d, sctx, dbName, tblInfo, sqlMode, args)
+		}()
+	}
+
+	waitTimeout := defaultWaitReorgTimeout
+	// If lease > 0, we update the progress of the job and check again soon.
+	if d.lease > 0 {
+		waitTimeout = ReorgWaitTimeout
+	}
+	select {
+	case err := <-rc.doneCh:
+		rowCount, _, _ := rc.getRowCountAndKey()
+		logutil.BgLogger().Info("[ddl] run create table as select job done", zap.Int64("inserted rows", rowCount), zap.Error(err))
+		job.SetRowCount(rowCount)
+		w.mergeWarningsIntoJob(job)
+		rc.clean()
+		return errors.Trace(err)
+	case <-w.ctx.Done():
+		logutil.BgLogger().Info("[ddl] run create table as select job quit")
+		rc.setRowCount(0)
+		rc.resetWarnings()
+		// We return errWaitReorgTimeout here too, so that outer loop will break.
+		return errWaitReorgTimeout
+	case <-time.After(waitTimeout):
+		rowCount, _, _ := rc.getRowCountAndKey()
+		job.SetRowCount(rowCount)
+		logutil.BgLogger().Info("[ddl] run create table as select job wait timeout",
+			zap.Duration("waitTime", waitTimeout), zap.Int64("inserted rows", rowCount))
+		// If timeout, we will return, check the owner and retry to wait job done again.
+		return errWaitReorgTimeout
+	}
+}
+
+// insertTableAsSelect inserts the select result into the table. The select is executed by the internal session
+// sctx in the environment of the session creating the table, and its rows are inserted in batches by the
+// parallel workers, each batch is inserted in a transaction, so the size of the select result isn't limited by
+// the transaction size limit.
+func (w *worker) insertTableAsSelect(d *ddlCtx, sctx sessionctx.Context, dbName string, tblInfo *model.TableInfo,
+	sqlMode mysql.SQLMode, args *createTableAsSelectArgs) (err error) {
+	defer util.Recover(metrics.LabelDDL, "insertTableAsSelect",
+		func() {
+			err = errCancelledDDLJob.GenWithStack("create table `%v` as select panic", tblInfo.Name)
+		}, false)
+	rc := w.reorgCtx
+	ctx, cancel := context.WithCancel(w.ctx)
+	defer cancel()
+
+	// The batches inserted by the previous DDL owner can't be told apart from the rest of the select result.
+	rows, _, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, nil,
+		"SELECT COUNT(1) FROM %n.%n", dbName, tblInfo.Name.O)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if count := rows[0].GetInt64(0); count > 0 {
+		logutil.BgLogger().Info("[ddl] the rows of create table as select have been partially inserted", zap.Int64("rows", count))
+		return errCreateTableAsSelectInterrupted.GenWithStackByArgs(tblInfo.Name.O)
+	}
+
+	vars := sctx.GetSessionVars()
+	restore, err := setCreateTableAsSelectVars(vars, sqlMode, args, createTableAsSelectSysVars)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		restore()
+		atomic.StoreUint32(&vars.Killed, 0)
+	}()
+
+	workerCnt := int(variable.GetDDLReorgWorkerCounter())
+	batchSize := int(variable.GetDDLReorgBatchSize())
+	batchCh := make(chan [][]interface{}, workerCnt)
+	errCh := make(chan error, workerCnt)
+	var wg sync.WaitGroup
+	for i := 0; i < workerCnt; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.runCreateTableAsSelectWorker(ctx, d, dbName, tblInfo, sqlMode, args, batchCh); err != nil {
+				errCh <- err
+				cancel()
+			}
+		}()
+	}
+	// Kill the select if the job is cancelled or a worker fails.
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(createTableAsSelectCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+			case <-ticker.C:
+			}
+			if rc.isReorgCanceled() {
+				cancel()
+			}
+			if ctx.Err() != nil {
+				atomic.StoreUint32(&vars.Killed, 1)
+				return
+			}
+		}
+	}()
+	err = readCreateTableAsSelectBatches(ctx, sctx, args.selectSQL, batchSize, batchCh)
+	if err != nil {
+		cancel()
+	}
+	close(batchCh)
+	close(done)
+	wg.Wait()
+	if rc.isReorgCanceled() {
+		return errCancelledDDLJob
+	}
+	select {
+	case workerErr := <-errCh:
+		// The select is killed because of the error of the worker.
+		return errors.Trace(workerErr)
+	default:
+	}
+	return errors.Trace(err)
+}
+
+// readCreateTableAsSelectBatches executes the select of CREATE TABLE ... SELECT and sends its rows to batchCh
+// in batches, the values of the rows are converted to the parameters of the INSERT statements.
+func readCreateTableAsSelectBatches(ctx context.Context, sctx sessionctx.Context, selectSQL string, batchSize int, batchCh chan<- [][]interface{}) (err error) {
+	rs, err := sctx.(sqlexec.SQLExecutor).ExecuteInternal(ctx, selectSQL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer terror.Call(rs.Close)
+	fieldTypes := make([]*types.FieldType, 0, len(rs.Fields()))
+	for _, field := range rs.Fields() {
+		fieldTypes = append(fieldTypes, &field.Column.FieldType)
+	}
+	chk := rs.NewChunk(nil)
+	batch := make([][]interface{}, 0, batchSize)
+	for {
+		if err = rs.Next(ctx, chk); err != nil {
+			return errors.Trace(err)
+		}
+		if chk.NumRows() == 0 {
+			break
+		}
+		for i := 0; i < chk.NumRows(); i++ {
+			row := chk.GetRow(i)
+			params := make([]interface{}, 0, len(fieldTypes))
+			for j, ft := range fieldTypes {
+				params = append(params, createTableAsSelectParam(row.GetDatum(j, ft)))
+			}
+			batch = append(batch, params)
+			if len(batch) < batchSize {
+				continue
+			}
+			select {
+			case batchCh <- batch:
+			case <-ctx.Done():
+				return errors.Trace(ctx.Err())
+			}
+			batch = make([][]interface{}, 0, batchSize)
+		}
+	}
+	if len(batch) > 0 {
+		select {
+		case batchCh <- batch:
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		}
+	}
+	return nil
+}
+
+// createTableAsSelectParam converts the value of the select result to the parameter of the INSERT statement.
+func createTableAsSelectParam(d types.Datum) interface{} {
+	switch d.Kind() {
+	case types.KindNull:
+		return nil
+	case types.KindInt64:
+		return d.GetInt64()
+	case types.KindUint64:
+		return d.GetUint64()
+	case types.KindFloat32:
+		return d.GetFloat32()
+	case types.KindFloat64:
+		return d.GetFloat64()
+	case types.KindString, types.KindBytes:
+		if d.Kind() == types.KindString && d.Collation() != charset.CollationBin {
+			return d.GetString()
+		}
+		// The nil bytes are formatted as NULL.
+		return append([]byte{}, d.GetBytes()...)
+	case types.KindMysqlBit, types.KindBinaryLiteral:
+		if v, err := d.GetBinaryLiteral().ToInt(nil); err == nil {
+			return v
+		}
+	}
+	// The time, duration, decimal, enum, set and JSON values are inserted as the strings.
+	s, err := d.ToString()
+	terror.Log(err)
+	return s
+}
+
+// runCreateTableAsSelectWorker inserts the batches of the rows from batchCh, each batch is inserted by an
+// INSERT statement in a transaction.
+func (w *worker) runCreateTableAsSelectWorker(ctx context.Context, d *ddlCtx, dbName string, tblInfo *model.TableInfo,
+	sqlMode mysql.SQLMode, args *createTableAsSelectArgs, batchCh <-chan [][]interface{}) (err error) {
+	defer util.Recover(metrics.LabelDDL, "runCreateTableAsSelectWorker",
+		func() {
+			err = errCancelledDDLJob.GenWithStack("create table `%v` as select panic", tblInfo.Name)
+		}, false)
+	sctx, release, err := w.getCreateTableAsSelectSession(d)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer release()
+	// The values are formatted by the session's time zone in the select, and the strings are always in utf8mb4.
+	restore, err := setCreateTableAsSelectVars(sctx.GetSessionVars(), sqlMode, args, []string{variable.TimeZone})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer restore()
+
+	var sb strings.Builder
+	switch args.onDuplicate {
+	case ast.OnDuplicateKeyHandlingIgnore:
+		sb.WriteString("INSERT IGNORE")
+	case ast.OnDuplicateKeyHandlingReplace:
+		sb.WriteString("REPLACE")
+	default:
+		sb.WriteString("INSERT")
+	}
+	sqlexec.MustFormatSQL(&sb, " INTO %n.%n (", dbName, tblInfo.Name.O)
+	for i, col := range args.selectCols {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sqlexec.MustFormatSQL(&sb, "%n", col.O)
+	}
+	sb.WriteString(") VALUES ")
+	prefix := sb.String()
+	rowFormat := "(" + strings.Repeat("%?, ", len(args.selectCols)-1) + "%?)"
+
+	rc := w.reorgCtx
+	vars := sctx.GetSessionVars()
+	for batch := range batchCh {
+		if ctx.Err() != nil {
+			continue
+		}
+		sb.Reset()
+		sb.WriteString(prefix)
+		for i, row := range batch {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sqlexec.MustFormatSQL(&sb, rowFormat, row...)
+		}
+		if _, err = sctx.(sqlexec.SQLExecutor).ExecuteInternal(ctx, sb.String()); err != nil {
+			return errors.Trace(err)
+		}
+		rc.increaseRowCount(int64(len(batch)))
+		warnings := make(map[errors.ErrorID]*terror.Error)
+		warningsCount := make(map[errors.ErrorID]int64)
+		for _, warn := range vars.StmtCtx.GetWarnings() {
+			tErr := toTError(warn.Err)
+			if _, ok := warnings[tErr.ID()]; !ok {
+				warnings[tErr.ID()] = tErr
+			}
+			warningsCount[tErr.ID()]++
+		}
+		rc.mergeWarnings(warnings, warningsCount)
+	}
+	return nil
+}
+
+// getCreateTableAsSelectSession gets a session for the worker inserting the rows of CREATE TABLE ... SELECT, the
+// returned function releases it. The sessions are created by the session factory if it's set, because the
+// session pool of the DDL workers is too small for the parallel workers.
+func (w *worker) getCreateTableAsSelectSession(d *ddlCtx) (sessionctx.Context, func(), error) {
+	if d.sessFactory == nil {
+		sctx, err := w.sessPool.get()
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		return sctx, func() { w.sessPool.put(sctx) }, nil
+	}
+	res, err := d.sessFactory()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return res.(sessionctx.Context), res.Close, nil
+}
+
+// setCreateTableAsSelectVars sets the SQL mode, the current database and the system variables names of the
+// session creating the table to vars, the returned function restores them.
+func setCreateTableAsSelectVars(vars *variable.SessionVars, sqlMode mysql.SQLMode, args *createTableAsSelectArgs, names []string) (func(), error) {
+	originSQLMode, originDB := vars.SQLMode, vars.CurrentDB
+	origins := make(map[string]string, len(names))
+	restore := func() {
+		vars.SQLMode, vars.CurrentDB = originSQLMode, originDB
+		for name, origin := range origins {
+			terror.Log(vars.SetSystemVar(name, origin))
+		}
+	}
+	// The backslashes of the select and the parameters are always escaped.
+	vars.SQLMode = sqlMode &^ mysql.ModeNoBackslashEscapes
+	vars.CurrentDB = args.currentDB
+	for _, name := range names {
+		val, ok := args.sysVars[name]
+		if !ok {
+			continue
+		}
+		origin, _ := vars.GetSystemVar(name)
+		if err := vars.SetSystemVar(name, val); err != nil {
+			restore()
+			return nil, errors.Trace(err)
+		}
+		origins[name] = origin
+	}
+	return restore, nil
+}
+
+func rollbackCreateTableAsSelect(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, err := getTableInfo(t, job.TableID, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	physicalTableIDs := append(getPartitionIDs(tblInfo), tblInfo.ID)
+	bundles := make([]*placement.Bundle, 0, len(physicalTableIDs))
+	for _, id := range physicalTableIDs {
+		bundles = append(bundles, placement.NewBundle(id))
+	}
+	// The table has never been public, its placement rules are removed at once.
+	err = infosync.PutRuleBundlesWithDefaultRetry(context.TODO(), bundles)
+	if err != nil {
+		return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+	}
+
+	ver, err = updateSchemaVersion(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if err = t.DropTableOrView(job.SchemaID, job.TableID); err != nil {
+		return ver, errors.Trace(err)
+	}
+	if err = t.GetAutoIDAccessors(job.SchemaID, job.TableID).Del(); err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo.State = model.StateNone
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	// Refactor the job args to add the physical tables into delete range table.
+	job.Args = []interface{}{physicalTableIDs}
+	return ver, nil
+}
@@ -2091,8 +2091,8 @@ func (s *testStateChangeSuite) TestExpressionIndexDDLError(c *C) {
 	tk.MustExec("use test_db_state")
 	tk.MustExec("drop table if exists t")
 	tk.MustExec("create table t(a int, b int, index idx((a+b)))")
-	tk.MustGetErrCode("alter table t rename column b to b2", errno.ErrDependentByFunctionalIndex)
-	tk.MustGetErrCode("alter table t drop column b", errno.ErrDependentByFunctionalIndex)
+	tk.MustExec("alter table t rename column b to b2")
+	tk.MustGetErrCode("alter table t drop column b2", errno.ErrDependentByFunctionalIndex)
 	tk.MustExec("drop table t")
 }
 
@@ -0,0 +1,123 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlterTableClusteredIndex(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t1 (a int primary key nonclustered, b int, key idx_b(b))")
+	tk.MustExec("insert into t1 values (1, 10), (2, 20), (3, 30)")
+	tk.MustExec("alter table t1 alter primary key clustered")
+	require.True(t, testGetTableByNameT(t, tk.Session(), "test", "t1").Meta().PKIsHandle)
+	tk.MustExec("admin check table t1")
+	tk.MustQuery("select * from t1 use index(idx_b) where b > 10 order by a").Check(testkit.Rows("2 20", "3 30"))
+	tk.MustQuery("select tidb_pk_type from information_schema.tables where table_name = 't1'").Check(testkit.Rows("CLUSTERED"))
+	tk.MustExec("alter table t1 alter primary key nonclustered")
+	require.False(t, testGetTableByNameT(t, tk.Session(), "test", "t1").Meta().HasClusteredIndex())
+	tk.MustExec("admin check table t1")
+	tk.MustGetErrCode("insert into t1 values (1, 11)", errno.ErrDupEntry)
+	tk.MustExec("insert into t1 values (4, 40)")
+	tk.MustQuery("select * from t1 order by a").Check(testkit.Rows("1 10", "2 20", "3 30", "4 40"))
+
+	tk.MustExec("create table t2 (a varchar(10), b int, c int auto_increment, primary key (a, b) clustered, unique key uk_c(c))")
+	tk.MustExec("insert into t2 (a, b) values ('x', 1), ('y', 2)")
+	tk.MustExec("alter table t2 alter primary key nonclustered")
+	tk.MustExec("admin check table t2")
+	tk.MustExec("insert into t2 (a, b) values ('z', 3)")
+	// The auto IDs cached by the old table are skipped.
+	tk.MustQuery("select a, b, c > 2 from t2 order by c").Check(testkit.Rows("x 1 0", "y 2 0", "z 3 1"))
+	tk.MustExec("alter table t2 alter primary key clustered")
+	require.True(t, testGetTableByNameT(t, tk.Session(), "test", "t2").Meta().IsCommonHandle)
+	tk.MustExec("admin check table t2")
+	tk.MustQuery("select c from t2 where a = 'y' and b = 2").Check(testkit.Rows("2"))
+	// The table is already in the target layout.
+	tk.MustExec("alter table t2 alter primary key clustered")
+
+	tk.MustExec("create table t3 (a int, b int)")
+	tk.MustGetErrCode("alter table t3 alter primary key clustered", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("create table t4 (a int primary key nonclustered) partition by hash(a) partitions 2")
+	tk.MustGetErrCode("alter table t4 alter primary key clustered", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("create table t5 (a bigint auto_random primary key)")
+	tk.MustGetErrCode("alter table t5 alter primary key nonclustered", errno.ErrUnsupportedDDLOperation)
+}
+
+func TestAlterTableClusteredIndexWithDML(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+
+	for _, target := range []string{"clustered", "nonclustered"} {
+		source := "nonclustered"
+		if target == source {
+			source = "clustered"
+		}
+		tk.MustExec("drop table if exists t")
+		tk.MustExec(fmt.Sprintf("create table t (a int, b int, primary key (a) %s, key idx_b(b))", source))
+		tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+
+		originalHook := dom.DDL().GetHook()
+		hook := &ddl.TestDDLCallback{Do: dom}
+		var checkErr error
+		// The job may run several rounds in the same state, only run the DMLs once.
+		handled := make(map[model.SchemaState]bool)
+		hook.OnJobRunBeforeExported = func(job *model.Job) {
+			if checkErr != nil || job.Type != model.ActionAlterTableClusteredIndex || handled[job.SchemaState] {
+				return
+			}
+			handled[job.SchemaState] = true
+			var sqls []string
+			switch job.SchemaState {
+			case model.StateDeleteOnly:
+				sqls = []string{"delete from t where a = 1", "insert into t values (10, 10)"}
+			case model.StateWriteOnly:
+				sqls = []string{"update t set b = 20 where a = 2", "insert into t values (11, 11)", "delete from t where a = 10"}
+			case model.StateWriteReorganization:
+				sqls = []string{"update t set a = 30 where a = 3", "insert into t values (12, 12)"}
+			}
+			for _, sql := range sqls {
+				if _, checkErr = tk1.Exec(sql); checkErr != nil {
+					return
+				}
+			}
+		}
+		dom.DDL().(ddl.DDLForTest).SetHook(hook)
+		tk.MustExec(fmt.Sprintf("alter table t alter primary key %s", target))
+		dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+		require.NoError(t, checkErr)
+
+		tk.MustExec("admin check table t")
+		tk.MustQuery("select * from t order by a").Check(testkit.Rows("2 20", "11 11", "12 12", "30 3"))
+		tk.MustQuery("select b from t use index(idx_b) order by b").Check(testkit.Rows("3", "11", "12", "20"))
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTableAsSelectInvisible(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("insert into t1 values (1), (2), (3)")
+
+	originalHook := dom.DDL().GetHook()
+	hook := &ddl.TestDDLCallback{Do: dom}
+	var checked bool
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if checked || job.Type != model.ActionCreateTableAsSelect || job.SchemaState != model.StateWriteReorganization {
+			return
+		}
+		checked = true
+		// The table isn't accessible before all the rows are inserted.
+		tk1.MustGetErrCode("select * from t2", errno.ErrNoSuchTable)
+		tk1.MustGetErrCode("insert into t2 values (4)", errno.ErrNoSuchTable)
+		tk1.MustQuery("show tables like 't2'").Check(testkit.Rows())
+		tk1.MustQuery("select count(*) from information_schema.tables where table_schema = 'test' and table_name = 't2'").Check(testkit.Rows("0"))
+		tk1.MustQuery("select count(*) from information_schema.columns where table_schema = 'test' and table_name = 't2'").Check(testkit.Rows("0"))
+		tk1.MustGetErrCode("create table t2 (a int)", errno.ErrTableExists)
+	}
+	dom.DDL().(ddl.DDLForTest).SetHook(hook)
+	tk.MustExec("create table t2 select a from t1")
+	dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+	require.True(t, checked)
+	tk.MustQuery("select a from t2 order by a").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("show tables like 't2'").Check(testkit.Rows("t2"))
+}
+
+func TestCancelCreateTableAsSelect(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("insert into t1 values (1), (2), (3)")
+
+	for i, state := range []model.SchemaState{model.StateNone, model.StateWriteReorganization} {
+		originalHook := dom.DDL().GetHook()
+		hook := &ddl.TestDDLCallback{Do: dom}
+		var cancelled bool
+		var checkErr error
+		hook.OnJobRunBeforeExported = func(job *model.Job) {
+			if cancelled || job.Type != model.ActionCreateTableAsSelect || job.SchemaState != state {
+				return
+			}
+			cancelled = true
+			_, checkErr = tk1.Exec(fmt.Sprintf("admin cancel ddl jobs %d", job.ID))
+		}
+		dom.DDL().(ddl.DDLForTest).SetHook(hook)
+		tk.MustGetErrCode(fmt.Sprintf("create table t%d select a from t1", i+2), errno.ErrCancelledDDLJob)
+		dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+		require.True(t, cancelled)
+		require.NoError(t, checkErr)
+
+		// The table is dropped, and can be created again.
+		tk.MustGetErrCode(fmt.Sprintf("select * from t%d", i+2), errno.ErrNoSuchTable)
+		tk.MustExec(fmt.Sprintf("create table t%d select a from t1", i+2))
+		tk.MustQuery(fmt.Sprintf("select a from t%d order by a", i+2)).Check(testkit.Rows("1", "2", "3"))
+	}
+}
+
+func TestCreateTableAsSelectInBatches(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (id int primary key, a varchar(20), b varbinary(8), c decimal(10, 3), d datetime(3), " +
+		"e timestamp, f json, g enum('x', 'y'), h bit(8), i double, j float, k time)")
+	tk.MustExec("insert into t1 values (1, 'it''s', x'00ff27', -1.125, '2021-01-01 12:34:56.789', '2022-02-02 00:00:00', " +
+		"'{\"a\": [1, \"b\"]}', 'y', b'101', 0.1, 1.5, '-12:00:01'), (2, 'a\\\\b', null, null, null, null, null, null, null, null, null, null)")
+	for i := 0; i < 9; i++ {
+		tk.MustExec("insert into t1 select id + (select max(id) from t1), a, b, c, d, e, f, g, h, i, j, k from t1")
+	}
+
+	tk.MustExec("set @@global.tidb_ddl_reorg_batch_size = 32")
+	tk.MustExec("set @@global.tidb_ddl_reorg_worker_cnt = 4")
+	defer func() {
+		tk.MustExec("set @@global.tidb_ddl_reorg_batch_size = default")
+		tk.MustExec("set @@global.tidb_ddl_reorg_worker_cnt = default")
+	}()
+	tk.MustExec("set @@time_zone = '+08:00'")
+	tk.MustExec("create table t2 select * from t1")
+	tk.MustQuery("select row_count from information_schema.ddl_jobs where table_name = 't2' and job_type = 'create table as select'").
+		Check(testkit.Rows("1024"))
+	tk.MustQuery("select * from t2 order by id").Check(tk.MustQuery("select * from t1 order by id").Rows())
+	tk.MustQuery("select id, hex(b), bin(h) from t2 where id <= 2 order by id").Check(testkit.Rows("1 00FF27 101", "2 <nil> <nil>"))
+
+	// The rows inserted by the committed batches are cleaned up if a batch fails.
+	tk.MustGetErrCode("create table t3 (unique key (a)) select id, if(id in (1, 1000), 'x', concat('x', id)) as a from t1 order by id", errno.ErrDupEntry)
+	tk.MustGetErrCode("select * from t3", errno.ErrNoSuchTable)
+	tk.MustQuery("select state from information_schema.ddl_jobs where table_name = 't3' and job_type = 'create table as select'").
+		Check(testkit.Rows("rollback done"))
+}
@@ -440,10 +440,6 @@ create table log_message_1 (
 			"create table t (a date) partition by range (to_days(to_days(a))) (partition p0 values less than (1));",
 			ddl.ErrWrongExprInPartitionFunc,
 		},
-		{
-			"create table t (id timestamp) partition by range columns (id) (partition p0 values less than ('2019-01-09 11:23:34'));",
-			ddl.ErrNotAllowedTypeInPartition,
-		},
 		{
 			`create table t29 (
 				a decimal
@@ -1660,6 +1656,56 @@ func (s *testSerialDBSuite1) TestDropPartitionWithGlobalIndex(c *C) {
 	})
 }
 
+func (s *testSerialDBSuite1) TestTruncatePartitionWithGlobalIndex(c *C) {
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.EnableGlobalIndex = true
+	})
+	defer config.UpdateGlobal(func(conf *config.Config) {
+		conf.EnableGlobalIndex = false
+	})
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists test_global")
+	tk.MustExec(`create table test_global ( a int, b int, c int)
+	partition by range( a ) (
+		partition p1 values less than (10),
+		partition p2 values less than (20),
+		partition p3 values less than (30)
+	);`)
+	t := testGetTableByName(c, s.ctx, "test", "test_global")
+	pid2 := t.Meta().Partition.Definitions[1].ID
+	pid3 := t.Meta().Partition.Definitions[2].ID
+
+	tk.MustExec("Alter Table test_global Add Unique Index idx_b (b);")
+	tk.MustExec(`INSERT INTO test_global VALUES (1, 1, 1), (2, 2, 2), (11, 3, 3), (12, 4, 4), (21, 5, 5)`)
+
+	tk.MustExec("alter table test_global truncate partition p2, p3;")
+	tk.MustQuery("select * from test_global;").Sort().Check(testkit.Rows(`1 1 1`, `2 2 2`))
+	tk.MustQuery("select * from test_global use index(idx_b) where b > 0;").Sort().Check(testkit.Rows(`1 1 1`, `2 2 2`))
+
+	t = testGetTableByName(c, s.ctx, "test", "test_global")
+	c.Assert(t.Meta().Partition.DroppingDefinitions, HasLen, 0)
+	idxInfo := t.Meta().FindIndexByName("idx_b")
+	c.Assert(idxInfo, NotNil)
+	c.Assert(checkGlobalIndexCleanUpDone(c, s.ctx, t.Meta(), idxInfo, pid2), Equals, 2)
+	c.Assert(checkGlobalIndexCleanUpDone(c, s.ctx, t.Meta(), idxInfo, pid3), Equals, 2)
+
+	// The unique values of the truncated partitions can be inserted again.
+	tk.MustExec(`INSERT INTO test_global VALUES (13, 3, 3), (22, 4, 4)`)
+	tk.MustQuery("select * from test_global use index(idx_b) where b > 2;").Sort().Check(testkit.Rows(`13 3 3`, `22 4 4`))
+	tk.MustGetErrCode(`INSERT INTO test_global VALUES (25, 1, 1)`, errno.ErrDupEntry)
+
+	// Drop multiple partitions with global index.
+	tk.MustExec("alter table test_global drop partition p2, p3;")
+	tk.MustQuery("select * from test_global use index(idx_b) where b > 0;").Sort().Check(testkit.Rows(`1 1 1`, `2 2 2`))
+
+	// Exchanging partition of the table with global index is unsupported.
+	tk.MustExec("set @@tidb_enable_exchange_partition=1")
+	tk.MustExec("drop table if exists test_global_nt")
+	tk.MustExec("create table test_global_nt (a int, b int, c int, unique index idx_b (b))")
+	tk.MustGetErrCode("alter table test_global exchange partition p1 with table test_global_nt", errno.ErrUnsupportedDDLOperation)
+}
+
 func (s *testSerialDBSuite1) TestAlterTableExchangePartition(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -2653,8 +2653,6 @@ func (s *testDBSuite5) TestRenameColumn(c *C) {
 
 	tk.MustGetErrCode("alter table test_rename_column rename column col to col1", errno.ErrFKIncompatibleColumns)
 
-	tk.MustExec("drop table test_rename_column_base")
-
 	// Test renaming generated columns.
 	tk.MustExec("drop table test_rename_column")
 	tk.MustExec("create table test_rename_column (id int, col1 int generated always as (id + 1))")
@@ -2663,18 +2661,40 @@ func (s *testDBSuite5) TestRenameColumn(c *C) {
 	assertColNames("test_rename_column", "id", "col2")
 	s.mustExec(tk, c, "alter table test_rename_column rename column col2 to col1")
 	assertColNames("test_rename_column", "id", "col1")
-	tk.MustGetErrCode("alter table test_rename_column rename column id to id1", errno.ErrDependentByGeneratedColumn)
+	s.mustExec(tk, c, "alter table test_rename_column rename column id to id1")
+	assertColNames("test_rename_column", "id1", "col1")
+	tk.MustExec("insert into test_rename_column(id1) values (1)")
+	tk.MustQuery("select id1, col1 from test_rename_column").Check(testkit.Rows("1 2"))
+	tk.MustQuery("show create table test_rename_column").Check(testkit.Rows("test_rename_column CREATE TABLE `test_rename_column` (\n" +
+		"  `id1` int(11) DEFAULT NULL,\n" +
+		"  `col1` int(11) GENERATED ALWAYS AS (`id1` + 1) VIRTUAL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	// Test renaming columns referenced by expression indexes.
+	tk.MustExec("drop table test_rename_column")
+	tk.MustExec("create table test_rename_column (a int, b int, index idx((a + b)))")
+	tk.MustExec("insert into test_rename_column values (1, 2)")
+	s.mustExec(tk, c, "alter table test_rename_column rename column b to b2")
+	tk.MustExec("admin check table test_rename_column")
+	tk.MustQuery("select a from test_rename_column use index(idx) where a + b2 = 3").Check(testkit.Rows("1"))
 
 	// Test renaming view columns.
 	tk.MustExec("drop table test_rename_column")
 	s.mustExec(tk, c, "create table test_rename_column (id int, col1 int)")
-	s.mustExec(tk, c, "create view test_rename_column_view as select * from test_rename_column")
+	tk.MustExec("insert into test_rename_column values (1, 2)")
+	s.mustExec(tk, c, "create sql security invoker view test_rename_column_view as select * from test_rename_column")
+	s.mustExec(tk, c, "create sql security invoker view test_rename_column_view2 as select col1, v.id from test_rename_column v where col1 > 1 order by col1")
+	s.mustExec(tk, c, "create sql security invoker view test_rename_column_view3 as select t1.col1 from test_rename_column t1 join test_rename_column_base t2 on t1.id = t2.base")
 
 	s.mustExec(tk, c, "alter table test_rename_column rename column col1 to col2")
-	tk.MustGetErrCode("select * from test_rename_column_view", errno.ErrViewInvalid)
+	tk.MustQuery("select * from test_rename_column_view").Check(testkit.Rows("1 2"))
+	tk.MustQuery("select col1, id from test_rename_column_view2").Check(testkit.Rows("2 1"))
+	tk.MustQuery("select * from test_rename_column_view3").Check(testkit.Rows())
+	tk.MustQuery("select view_definition from information_schema.views where table_name = 'test_rename_column_view2'").Check(testkit.Rows(
+		"SELECT `col2` AS `col1`,`v`.`id` AS `id` FROM `" + s.schemaName + "`.`test_rename_column` AS `v` WHERE `col2`>1 ORDER BY `col1`"))
 
-	s.mustExec(tk, c, "drop view test_rename_column_view")
-	tk.MustExec("drop table test_rename_column")
+	s.mustExec(tk, c, "drop view test_rename_column_view, test_rename_column_view2, test_rename_column_view3")
+	tk.MustExec("drop table test_rename_column, test_rename_column_base")
 }
 
 func (s *testDBSuite7) TestSelectInViewFromAnotherDB(c *C) {
@@ -4174,10 +4194,13 @@ func (s *testDBSuite5) TestCheckConvertToCharacter(c *C) {
 	is := domain.GetDomain(ctx).InfoSchema()
 	t, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
 	c.Assert(err, IsNil)
-	tk.MustGetErrCode("alter table t modify column a varchar(10) charset utf8 collate utf8_bin", errno.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify column a varchar(10) charset utf8mb4 collate utf8mb4_bin", errno.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify column a varchar(10) charset latin1 collate latin1_bin", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("insert into t values (x'ff')")
+	// The data is converted by the reorganization, and the invalid characters can't be converted.
+	tk.MustGetErrCode("alter table t modify column a varchar(10) charset utf8 collate utf8_bin", errno.ErrTruncatedWrongValueForField)
+	tk.MustGetErrCode("alter table t modify column a varchar(10) charset utf8mb4 collate utf8mb4_bin", errno.ErrTruncatedWrongValueForField)
 	c.Assert(t.Cols()[0].Charset, Equals, "binary")
+	tk.MustExec("alter table t modify column a varchar(10) charset latin1 collate latin1_bin")
+	tk.MustQuery("select hex(a) from t").Check(testkit.Rows("FF"))
 }
 
 func (s *testDBSuite5) TestModifyColumnRollBack(c *C) {
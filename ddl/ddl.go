@@ -47,6 +47,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	goutil "github.com/pingcap/tidb/util"
 	tidbutil "github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/admin"
 	"github.com/pingcap/tidb/util/gcutil"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.etcd.io/etcd/clientv3"
@@ -99,6 +100,7 @@ type DDL interface {
 	AlterSchema(ctx sessionctx.Context, stmt *ast.AlterDatabaseStmt) error
 	DropSchema(ctx sessionctx.Context, schema model.CIStr) error
 	CreateTable(ctx sessionctx.Context, stmt *ast.CreateTableStmt) error
+	CreateTableAsSelect(ctx sessionctx.Context, stmt *ast.CreateTableStmt, selectCols []model.CIStr) error
 	CreateView(ctx sessionctx.Context, stmt *ast.CreateViewStmt) error
 	DropTable(ctx sessionctx.Context, tableIdent ast.Ident) (err error)
 	RecoverTable(ctx sessionctx.Context, recoverInfo *RecoverInfo) (err error)
@@ -210,6 +212,8 @@ type ddlCtx struct {
 	statsHandle  *handle.Handle
 	tableLockCkr util.DeadTableLockChecker
 	etcdCli      *clientv3.Client
+	mdlWaiter    MDLWaiter
+	sessFactory  pools.Factory
 
 	// hook may be modified.
 	mu struct {
@@ -325,6 +329,8 @@ func newDDL(ctx context.Context, options ...Option) *ddl {
 		infoCache:    opt.InfoCache,
 		tableLockCkr: deadLockCkr,
 		etcdCli:      opt.EtcdCli,
+		mdlWaiter:    opt.MDLWaiter,
+		sessFactory:  opt.SessionFactory,
 	}
 	ddlCtx.mu.hook = opt.Hook
 	ddlCtx.mu.interceptor = &BaseInterceptor{}
@@ -518,7 +524,7 @@ func getIntervalFromPolicy(policy []time.Duration, i int) (time.Duration, bool)
 
 func getJobCheckInterval(job *model.Job, i int) (time.Duration, bool) {
 	switch job.Type {
-	case model.ActionAddIndex, model.ActionAddPrimaryKey, model.ActionModifyColumn:
+	case model.ActionAddIndex, model.ActionAddPrimaryKey, model.ActionModifyColumn, model.ActionCreateTableAsSelect:
 		return getIntervalFromPolicy(slowDDLIntervalPolicy, i)
 	case model.ActionCreateTable, model.ActionCreateSchema:
 		return getIntervalFromPolicy(fastDDLIntervalPolicy, i)
@@ -530,7 +536,7 @@ func getJobCheckInterval(job *model.Job, i int) (time.Duration, bool) {
 // mayNeedReorg indicates that this job may need to reorganize the data.
 func mayNeedReorg(job *model.Job) bool {
 	switch job.Type {
-	case model.ActionAddIndex, model.ActionAddPrimaryKey:
+	case model.ActionAddIndex, model.ActionAddPrimaryKey, model.ActionCreateTableAsSelect:
 		return true
 	case model.ActionModifyColumn:
 		if len(job.CtxVars) > 0 {
@@ -607,6 +613,15 @@ func (d *ddl) doDDLJob(ctx sessionctx.Context, job *model.Job) error {
 		metrics.JobsGauge.WithLabelValues(job.Type.String()).Dec()
 		metrics.HandleJobHistogram.WithLabelValues(job.Type.String(), metrics.RetLabel(err)).Observe(time.Since(startTime).Seconds())
 	}()
+	// The job is cancelled if it does not finish within tidb_ddl_timeout, which includes waiting for all the
+	// TiDB servers to load the new schema.
+	var timeoutCh <-chan time.Time
+	if timeout := ctx.GetSessionVars().DDLTimeout; timeout > 0 {
+		timer := time.NewTimer(time.Duration(timeout) * time.Millisecond)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+	cancelledOnTimeout := false
 	i := 0
 	for {
 		failpoint.Inject("storeCloseInLoop", func(_ failpoint.Value) {
@@ -618,6 +633,8 @@ func (d *ddl) doDDLJob(ctx sessionctx.Context, job *model.Job) error {
 		case <-ticker.C:
 			i++
 			ticker = updateTickerInterval(ticker, 10*d.lease, job, i)
+		case <-timeoutCh:
+			cancelledOnTimeout = d.cancelJobOnTimeout(jobID)
 		case <-d.ctx.Done():
 			logutil.BgLogger().Info("[ddl] doDDLJob will quit because context done")
 			return context.Canceled
@@ -653,11 +670,19 @@ func (d *ddl) doDDLJob(ctx sessionctx.Context, job *model.Job) error {
 					ctx.GetSessionVars().StmtCtx.AppendWarning(warning)
 				}
 			}
+			if historyJob.Type == model.ActionCreateTableAsSelect {
+				// The rows inserted by CREATE TABLE ... SELECT are the affected rows of the statement.
+				ctx.GetSessionVars().StmtCtx.AddAffectedRows(uint64(historyJob.GetRowCount()))
+			}
 
 			logutil.BgLogger().Info("[ddl] DDL job is finished", zap.Int64("jobID", jobID))
 			return nil
 		}
 
+		if cancelledOnTimeout && historyJob.Error != nil && (historyJob.IsCancelled() || historyJob.IsRollbackDone()) {
+			logutil.BgLogger().Info("[ddl] DDL job is cancelled because of timeout", zap.Int64("jobID", jobID))
+			return errDDLTimeout.GenWithStackByArgs(jobID)
+		}
 		if historyJob.Error != nil {
 			logutil.BgLogger().Info("[ddl] DDL job is failed", zap.Int64("jobID", jobID))
 			return errors.Trace(historyJob.Error)
@@ -676,6 +701,29 @@ func (d *ddl) doDDLJob(ctx sessionctx.Context, job *model.Job) error {
 	}
 }
 
+// cancelJobOnTimeout cancels the job which does not finish within tidb_ddl_timeout.
+// It returns false if the job can't be cancelled, and then the caller keeps waiting for it.
+func (d *ddl) cancelJobOnTimeout(jobID int64) bool {
+	var cancelErr error
+	err := kv.RunInNewTxn(d.ctx, d.store, true, func(ctx context.Context, txn kv.Transaction) error {
+		errs, err := admin.CancelJobs(txn, []int64{jobID})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cancelErr = errs[0]
+		return nil
+	})
+	if err == nil {
+		err = cancelErr
+	}
+	if err != nil {
+		logutil.BgLogger().Warn("[ddl] cancel DDL job on timeout failed", zap.Int64("jobID", jobID), zap.Error(err))
+		return false
+	}
+	logutil.BgLogger().Info("[ddl] cancel DDL job on timeout", zap.Int64("jobID", jobID))
+	return true
+}
+
 func (d *ddl) callHookOnChanged(err error) error {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
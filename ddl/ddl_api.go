@@ -1759,6 +1759,25 @@ func checkPartitionDefinitionConstraints(ctx sessionctx.Context, tbInfo *model.T
 	return errors.Trace(err)
 }
 
+// hasPrimaryKey checks whether the table has an explicit primary key.
+func hasPrimaryKey(tblInfo *model.TableInfo) bool {
+	return tblInfo.PKIsHandle || tables.FindPrimaryIndex(tblInfo) != nil
+}
+
+// isPrimaryKeyRequired checks whether sql_require_primary_key is set. The internal DDLs are not restricted.
+func isPrimaryKeyRequired(ctx sessionctx.Context) bool {
+	vars := ctx.GetSessionVars()
+	return vars.PrimaryKeyRequired && !vars.InRestrictedSQL
+}
+
+// checkPrimaryKeyRequired checks the table has a primary key if sql_require_primary_key is set.
+func checkPrimaryKeyRequired(ctx sessionctx.Context, tblInfo *model.TableInfo) error {
+	if isPrimaryKeyRequired(ctx) && !hasPrimaryKey(tblInfo) {
+		return ErrTableWithoutPrimaryKey
+	}
+	return nil
+}
+
 // checkTableInfoValid uses to check table info valid. This is used to validate table info.
 func checkTableInfoValid(tblInfo *model.TableInfo) error {
 	_, err := tables.TableFromMeta(nil, tblInfo)
@@ -1855,7 +1874,13 @@ func BuildSessionTemporaryTableInfo(ctx sessionctx.Context, is infoschema.InfoSc
 	} else {
 		tbInfo, err = buildTableInfoWithCheck(ctx, s, dbCharset, dbCollate, placementPolicyRef)
 	}
-	return tbInfo, err
+	if err != nil {
+		return nil, err
+	}
+	if err = checkPrimaryKeyRequired(ctx, tbInfo); err != nil {
+		return nil, err
+	}
+	return tbInfo, nil
 }
 
 // buildTableInfoWithStmt builds model.TableInfo from a SQL statement without validity check
@@ -1969,6 +1994,9 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	if err = checkTableInfoValidWithStmt(ctx, tbInfo, s); err != nil {
 		return err
 	}
+	if err = checkPrimaryKeyRequired(ctx, tbInfo); err != nil {
+		return err
+	}
 
 	onExist := OnExistError
 	if s.IfNotExists {
@@ -1978,6 +2006,71 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	return d.CreateTableWithInfo(ctx, schema.Name, tbInfo, onExist)
 }
 
+// CreateTableAsSelect creates the table of CREATE TABLE ... SELECT. The table is created by a DDL job, which
+// inserts the select result into the table on the DDL owner before making the table public, so the table is
+// never accessed with a part of the rows. The job is rolled back, and the table is dropped, if the rows can't be
+// inserted. selectCols are the columns which the select result is inserted into.
+func (d *ddl) CreateTableAsSelect(ctx sessionctx.Context, s *ast.CreateTableStmt, selectCols []model.CIStr) (err error) {
+	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(ident.Schema)
+	}
+
+	tbInfo, err := buildTableInfoWithStmt(ctx, s, schema.Charset, schema.Collate, schema.PlacementPolicyRef)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkTableInfoValidWithStmt(ctx, tbInfo, s); err != nil {
+		return err
+	}
+	if err = checkPrimaryKeyRequired(ctx, tbInfo); err != nil {
+		return err
+	}
+
+	onExist := OnExistError
+	if s.IfNotExists {
+		onExist = OnExistIgnore
+	}
+	job, err := d.createTableWithInfoJob(ctx, schema.Name, tbInfo, onExist, false)
+	if err != nil || job == nil {
+		return err
+	}
+
+	// The select is executed by the DDL owner. The backslashes of the strings are always escaped by the restore,
+	// so the select is parsed without NO_BACKSLASH_ESCAPES by the owner.
+	restoreFlag := format.RestoreStringSingleQuotes | format.RestoreKeyWordUppercase | format.RestoreNameBackQuotes
+	var sb strings.Builder
+	if err = s.Select.Restore(format.NewRestoreCtx(restoreFlag, &sb)); err != nil {
+		return errors.Trace(err)
+	}
+	sessVars := ctx.GetSessionVars()
+	sysVars := make(map[string]string, len(createTableAsSelectSysVars))
+	for _, name := range createTableAsSelectSysVars {
+		sysVars[name], _ = sessVars.GetSystemVar(name)
+	}
+	job.Type = model.ActionCreateTableAsSelect
+	job.Args = append(job.Args, sb.String(), selectCols, s.OnDuplicate, sessVars.CurrentDB, sysVars)
+	job.ReorgMeta = &model.DDLReorgMeta{
+		SQLMode:       sessVars.SQLMode,
+		Warnings:      make(map[errors.ErrorID]*terror.Error),
+		WarningsCount: make(map[errors.ErrorID]int64),
+	}
+
+	err = d.doDDLJob(ctx, job)
+	if err != nil {
+		if onExist == OnExistIgnore && infoschema.ErrTableExists.Equal(err) {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			err = nil
+		}
+	} else {
+		d.preSplitAndScatter(ctx, tbInfo, tbInfo.GetPartitionInfo())
+	}
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func setTemporaryType(ctx sessionctx.Context, tbInfo *model.TableInfo, s *ast.CreateTableStmt) error {
 	switch s.TemporaryKeyword {
 	case ast.TemporaryGlobal:
@@ -2375,10 +2468,17 @@ func checkColumnsPartitionType(tbInfo *model.TableInfo) error {
 		// DATE and DATETIME
 		// CHAR, VARCHAR, BINARY, and VARBINARY
 		// See https://dev.mysql.com/doc/mysql-partitioning-excerpt/5.7/en/partitioning-columns.html
+		// As an extension, TiDB also permits TIMESTAMP for RANGE COLUMNS. The range
+		// boundaries are interpreted in UTC so that the partition a row belongs to does
+		// not depend on the time zone of the session which writes it.
 		switch colInfo.FieldType.Tp {
 		case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
 		case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeDuration:
 		case mysql.TypeVarchar, mysql.TypeString:
+		case mysql.TypeTimestamp:
+			if tbInfo.Partition.Type != model.PartitionTypeRange {
+				return ErrNotAllowedTypeInPartition.GenWithStackByArgs(col.O)
+			}
 		default:
 			return ErrNotAllowedTypeInPartition.GenWithStackByArgs(col.O)
 		}
@@ -2744,6 +2844,13 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		return err
 	}
 
+	if isPrimaryKeyRequired(sctx) {
+		if tb, err := is.TableByName(ident.Schema, ident.Name); err == nil &&
+			!hasPrimaryKey(tb.Meta()) && !isAddingPrimaryKey(validSpecs) {
+			return ErrTableWithoutPrimaryKey
+		}
+	}
+
 	if len(validSpecs) > 1 {
 		switch validSpecs[0].Tp {
 		case ast.AlterTableAddColumns:
@@ -2939,6 +3046,8 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 			err = d.AlterTableCache(sctx, ident)
 		case ast.AlterTableNoCache:
 			err = d.AlterTableNoCache(sctx, ident)
+		case ast.AlterTableClusteredIndex:
+			err = d.AlterTableClusteredIndex(sctx, ident, spec)
 		default:
 			// Nothing to do now.
 		}
@@ -3629,6 +3738,9 @@ func checkExchangePartition(pt *model.TableInfo, nt *model.TableInfo) error {
 	if nt.ForeignKeys != nil {
 		return errors.Trace(ErrPartitionExchangeForeignKey.GenWithStackByArgs(nt.Name))
 	}
+	if hasGlobalIndex(pt) {
+		return errors.Trace(errUnsupportedExchangeGlobalIndex.GenWithStackByArgs(pt.Name))
+	}
 
 	// NOTE: if nt is temporary table, it should be checked
 	return nil
@@ -3921,11 +4033,6 @@ func CheckModifyTypeCompatible(origin *types.FieldType, to *types.FieldType) (ca
 	}
 
 	// Deal with the different type.
-	if !checkTypeChangeSupported(origin, to) {
-		unsupportedMsg := fmt.Sprintf("change from original type %v to %v is currently unsupported yet", origin.CompactStr(), to.CompactStr())
-		return false, errUnsupportedModifyColumn.GenWithStackByArgs(unsupportedMsg)
-	}
-
 	// Check if different type can directly convert and no need to reorg.
 	stringToString := types.IsString(origin.Tp) && types.IsString(to.Tp)
 	integerToInteger := mysql.IsIntegerType(origin.Tp) && mysql.IsIntegerType(to.Tp)
@@ -3975,37 +4082,6 @@ func needReorgToChange(origin *types.FieldType, to *types.FieldType) (needReorg
 	return false, ""
 }
 
-func checkTypeChangeSupported(origin *types.FieldType, to *types.FieldType) bool {
-	if (types.IsTypeTime(origin.Tp) || origin.Tp == mysql.TypeDuration || origin.Tp == mysql.TypeYear ||
-		types.IsString(origin.Tp) || origin.Tp == mysql.TypeJSON) &&
-		to.Tp == mysql.TypeBit {
-		// TODO: Currently date/datetime/timestamp/time/year/string/json data type cast to bit are not compatible with mysql, should fix here after compatible.
-		return false
-	}
-
-	if (types.IsTypeTime(origin.Tp) || origin.Tp == mysql.TypeDuration || origin.Tp == mysql.TypeYear ||
-		origin.Tp == mysql.TypeNewDecimal || origin.Tp == mysql.TypeFloat || origin.Tp == mysql.TypeDouble || origin.Tp == mysql.TypeJSON || origin.Tp == mysql.TypeBit) &&
-		(to.Tp == mysql.TypeEnum || to.Tp == mysql.TypeSet) {
-		// TODO: Currently date/datetime/timestamp/time/year/decimal/float/double/json/bit cast to enum/set are not support yet, should fix here after supported.
-		return false
-	}
-
-	if (origin.Tp == mysql.TypeEnum || origin.Tp == mysql.TypeSet || origin.Tp == mysql.TypeBit ||
-		origin.Tp == mysql.TypeNewDecimal || origin.Tp == mysql.TypeFloat || origin.Tp == mysql.TypeDouble) &&
-		(types.IsTypeTime(to.Tp)) {
-		// TODO: Currently enum/set/bit/decimal/float/double cast to date/datetime/timestamp type are not support yet, should fix here after supported.
-		return false
-	}
-
-	if (origin.Tp == mysql.TypeEnum || origin.Tp == mysql.TypeSet || origin.Tp == mysql.TypeBit) &&
-		to.Tp == mysql.TypeDuration {
-		// TODO: Currently enum/set/bit cast to time are not support yet, should fix here after supported.
-		return false
-	}
-
-	return true
-}
-
 // checkModifyTypes checks if the 'origin' type can be modified to 'to' type no matter directly change
 // or change by reorg. It returns error if the two types are incompatible and correlated change are not
 // supported. However, even the two types can be change, if the "origin" type contains primary key, error will be returned.
@@ -4023,14 +4099,13 @@ func checkModifyTypes(ctx sessionctx.Context, origin *types.FieldType, to *types
 
 	err = checkModifyCharsetAndCollation(to.Charset, to.Collate, origin.Charset, origin.Collate, needRewriteCollationData)
 
-	if err != nil {
-		if to.Charset == charset.CharsetGBK || origin.Charset == charset.CharsetGBK {
-			return errors.Trace(err)
-		}
-		// column type change can handle the charset change between these two types in the process of the reorg.
-		if errUnsupportedModifyCharset.Equal(err) && canReorg {
-			return nil
+	// column type change can handle the charset change between these two types in the process of the reorg.
+	if err != nil && errUnsupportedModifyCharset.Equal(err) && (canReorg || needReorgToChangeCharset(origin, to)) {
+		if mysql.HasPriKeyFlag(origin.Flag) {
+			msg := "this column has primary key flag"
+			return errUnsupportedModifyColumn.GenWithStackByArgs(msg)
 		}
+		return nil
 	}
 	return errors.Trace(err)
 }
@@ -4562,20 +4637,10 @@ func (d *ddl) RenameColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Al
 		return errFKIncompatibleColumns.GenWithStackByArgs(oldColName, fkInfo.Name)
 	}
 
-	// Check generated expression.
-	for _, col := range allCols {
-		if col.GeneratedExpr == nil {
-			continue
-		}
-		dependedColNames := findColumnNamesInExpr(col.GeneratedExpr)
-		for _, name := range dependedColNames {
-			if name.Name.L == oldColName.L {
-				if col.Hidden {
-					return errDependentByFunctionalIndex.GenWithStackByArgs(oldColName.O)
-				}
-				return errDependentByGeneratedColumn.GenWithStackByArgs(oldColName.O)
-			}
-		}
+	// The views which refer to the column are rewritten with the new column name in the same job.
+	views, err := buildRenamedColumnViews(d.GetInfoSchemaWithInterceptor(ctx), schema.Name, tbl.Meta().Name, oldCol.Name, newColName)
+	if err != nil {
+		return errors.Trace(err)
 	}
 
 	newCol := oldCol.Clone()
@@ -4591,7 +4656,7 @@ func (d *ddl) RenameColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Al
 			Warnings:      make(map[errors.ErrorID]*terror.Error),
 			WarningsCount: make(map[errors.ErrorID]int64),
 		},
-		Args: []interface{}{&newCol, oldColName, spec.Position, 0},
+		Args: []interface{}{&newCol, oldColName, spec.Position, 0, 0, nil, nil, views},
 	}
 	err = d.doDDLJob(ctx, job)
 	err = d.callHookOnChanged(err)
@@ -4805,6 +4870,7 @@ func (d *ddl) AlterTableSetTiFlashReplica(ctx sessionctx.Context, ident ast.Iden
 
 	tbReplicaInfo := tb.Meta().TiFlashReplica
 	if tbReplicaInfo != nil && tbReplicaInfo.Count == replicaInfo.Count &&
+		tbReplicaInfo.HighPriority == replicaInfo.HighPriority &&
 		len(tbReplicaInfo.LocationLabels) == len(replicaInfo.Labels) {
 		changed := false
 		for i, label := range tbReplicaInfo.LocationLabels {
@@ -4853,10 +4919,6 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if !ctx.GetSessionVars().EnableExtendedStats {
 		return errors.New("Extended statistics feature is not generally available now, and tidb_enable_extended_stats is OFF")
 	}
-	// Not support Cardinality and Dependency statistics type for now.
-	if stats.StatsType == ast.StatsTypeCardinality || stats.StatsType == ast.StatsTypeDependency {
-		return errors.New("Cardinality and Dependency statistics types are not supported now")
-	}
 	_, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
 	if err != nil {
 		return err
@@ -4886,7 +4948,7 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if len(colIDs) != 2 && (stats.StatsType == ast.StatsTypeCorrelation || stats.StatsType == ast.StatsTypeDependency) {
 		return errors.New("Only support Correlation and Dependency statistics types on 2 columns")
 	}
-	if len(colIDs) < 1 && stats.StatsType == ast.StatsTypeCardinality {
+	if len(colIDs) < 2 && stats.StatsType == ast.StatsTypeCardinality {
 		return errors.New("Only support Cardinality statistics type on at least 2 columns")
 	}
 	// TODO: check whether covering index exists for cardinality / dependency types.
@@ -5506,6 +5568,9 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	if t.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 		return errors.Trace(ErrOptOnCacheTable.GenWithStackByArgs("Create Index"))
 	}
+	if err = checkPrimaryKeyRequired(ctx, t.Meta()); err != nil {
+		return err
+	}
 	// Deal with anonymous index.
 	if len(indexName.L) == 0 {
 		colName := model.NewCIStr("expression_index")
@@ -5764,6 +5829,9 @@ func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CI
 	if err != nil {
 		return err
 	}
+	if isPK && isPrimaryKeyRequired(ctx) {
+		return ErrTableWithoutPrimaryKey
+	}
 
 	if indexInfo == nil {
 		err = ErrCantDropFieldOrKey.GenWithStack("index %s doesn't exist", indexName)
@@ -5825,10 +5893,13 @@ func (d *ddl) DropIndexes(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 
 		indexInfo := t.Meta().FindIndexByName(indexName.L)
 		if indexInfo != nil {
-			_, err := checkIsDropPrimaryKey(indexName, indexInfo, t)
+			isPK, err := checkIsDropPrimaryKey(indexName, indexInfo, t)
 			if err != nil {
 				return err
 			}
+			if isPK && isPrimaryKeyRequired(ctx) {
+				return ErrTableWithoutPrimaryKey
+			}
 			if err := checkDropIndexOnAutoIncrementColumn(t.Meta(), indexInfo); err != nil {
 				return errors.Trace(err)
 			}
@@ -5852,6 +5923,16 @@ func (d *ddl) DropIndexes(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 	return errors.Trace(err)
 }
 
+// isAddingPrimaryKey checks whether the ALTER TABLE statement adds a primary key.
+func isAddingPrimaryKey(specs []*ast.AlterTableSpec) bool {
+	for _, spec := range specs {
+		if spec.Tp == ast.AlterTableAddConstraint && spec.Constraint.Tp == ast.ConstraintPrimaryKey {
+			return true
+		}
+	}
+	return false
+}
+
 func checkIsDropPrimaryKey(indexName model.CIStr, indexInfo *model.IndexInfo, t table.Table) (bool, error) {
 	var isPK bool
 	if indexName.L == strings.ToLower(mysql.PrimaryKeyName) &&
@@ -5965,7 +6046,7 @@ func checkColumnsTypeAndValuesMatch(ctx sessionctx.Context, meta *model.TableInf
 		// Check val.ConvertTo(colType) doesn't work, so we need this case by case check.
 		vkind := val.Kind()
 		switch colType.Tp {
-		case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeDuration:
+		case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeDuration, mysql.TypeTimestamp:
 			switch vkind {
 			case types.KindString, types.KindBytes:
 				break
@@ -6868,3 +6949,36 @@ func (d *ddl) AlterTableNoCache(ctx sessionctx.Context, ti ast.Ident) (err error
 	err = d.doDDLJob(ctx, job)
 	return d.callHookOnChanged(err)
 }
+
+// AlterTableClusteredIndex changes the primary key of a table to be clustered or non-clustered.
+// The rows of the table are copied into a new physical table with the target layout.
+func (d *ddl) AlterTableClusteredIndex(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	clustered := spec.PrimaryKeyTp == model.PrimaryKeyTypeClustered
+	// The table is already in the target layout, return directly.
+	if t.Meta().HasClusteredIndex() == clustered {
+		return nil
+	}
+	if err = checkAlterTableClusteredIndex(t.Meta(), clustered); err != nil {
+		return errors.Trace(err)
+	}
+	genIDs, err := d.genGlobalIDs(1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		SchemaName: schema.Name.L,
+		TableID:    t.Meta().ID,
+		Type:       model.ActionAlterTableClusteredIndex,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{clustered, genIDs[0]},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	return d.callHookOnChanged(err)
+}
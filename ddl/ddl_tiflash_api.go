@@ -23,6 +23,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -62,6 +63,14 @@ type TiFlashManagementContext struct {
 	HandlePdCounter           uint64
 	UpdateTiFlashStoreCounter uint64
 	UpdateMap                 map[int64]bool
+	// SyncStarts records the first observed sync progress of every unavailable replica, it is used to estimate the ETA.
+	SyncStarts map[int64]TiFlashSyncSample
+}
+
+// TiFlashSyncSample is the sync progress of a TiFlash replica observed at some time.
+type TiFlashSyncSample struct {
+	Time     time.Time
+	Progress float64
 }
 
 // NewTiFlashManagementContext creates an instance for TiFlashManagementContext.
@@ -71,7 +80,19 @@ func NewTiFlashManagementContext() *TiFlashManagementContext {
 		UpdateTiFlashStoreCounter: 0,
 		TiFlashStores:             make(map[int64]helper.StoreStat),
 		UpdateMap:                 make(map[int64]bool),
+		SyncStarts:                make(map[int64]TiFlashSyncSample),
+	}
+}
+
+// EstimateTiFlashSyncETA estimates when the replica becomes available by the progress speed since start.
+// It returns zero time if the speed is unknown.
+func EstimateTiFlashSyncETA(start TiFlashSyncSample, now time.Time, progress float64) time.Time {
+	if progress <= start.Progress || !now.After(start.Time) {
+		return time.Time{}
 	}
+	elapsed := float64(now.Sub(start.Time))
+	remaining := elapsed * (1 - progress) / (progress - start.Progress)
+	return now.Add(time.Duration(remaining))
 }
 
 var (
@@ -132,7 +153,7 @@ func GetTiFlashReplicaInfo(tblInfo *model.TableInfo, tableList *[]TiFlashReplica
 		for _, p := range pi.Definitions {
 			logutil.BgLogger().Debug(fmt.Sprintf("Table %v has partition %v\n", tblInfo.ID, p.ID))
 			*tableList = append(*tableList, TiFlashReplicaStatus{p.ID,
-				tblInfo.TiFlashReplica.Count, tblInfo.TiFlashReplica.LocationLabels, tblInfo.TiFlashReplica.IsPartitionAvailable(p.ID), false})
+				tblInfo.TiFlashReplica.Count, tblInfo.TiFlashReplica.LocationLabels, tblInfo.TiFlashReplica.IsPartitionAvailable(p.ID), tblInfo.TiFlashReplica.HighPriority})
 		}
 		// partitions that in adding mid-state
 		for _, p := range pi.AddingDefinitions {
@@ -141,7 +162,7 @@ func GetTiFlashReplicaInfo(tblInfo *model.TableInfo, tableList *[]TiFlashReplica
 		}
 	} else {
 		logutil.BgLogger().Debug(fmt.Sprintf("Table %v has no partition\n", tblInfo.ID))
-		*tableList = append(*tableList, TiFlashReplicaStatus{tblInfo.ID, tblInfo.TiFlashReplica.Count, tblInfo.TiFlashReplica.LocationLabels, tblInfo.TiFlashReplica.Available, false})
+		*tableList = append(*tableList, TiFlashReplicaStatus{tblInfo.ID, tblInfo.TiFlashReplica.Count, tblInfo.TiFlashReplica.LocationLabels, tblInfo.TiFlashReplica.Available, tblInfo.TiFlashReplica.HighPriority})
 	}
 }
 
@@ -246,6 +267,11 @@ func (d *ddl) pollTiFlashReplicaStatus(ctx sessionctx.Context, pollTiFlashContex
 		}
 	}
 
+	// Handle the high priority replicas first.
+	sort.SliceStable(tableList, func(i, j int) bool {
+		return tableList[i].HighPriority && !tableList[j].HighPriority
+	})
+
 	for _, tb := range tableList {
 		// For every region in each table, if it has one replica, we reckon it ready.
 		// These request can be batched as an optimization.
@@ -260,6 +286,12 @@ func (d *ddl) pollTiFlashReplicaStatus(ctx sessionctx.Context, pollTiFlashContex
 			// We don't need to set accelerate schedule for this table, since it is already done in DDL, when
 			// 1. Add partition
 			// 2. Set TiFlash replica
+			// But the accelerate schedule of PD expires, so we keep renewing it for high priority replicas.
+			if tb.HighPriority && updateTiFlash {
+				if err := infosync.PostTiFlashAccelerateSchedule(context.Background(), tb.ID); err != nil {
+					logutil.BgLogger().Warn("renew accelerate schedule for high priority TiFlash replica failed", zap.Int64("id", tb.ID), zap.Error(err))
+				}
+			}
 
 			// Compute sync data process by request TiFlash.
 			regionReplica := make(map[int64]int)
@@ -285,12 +317,29 @@ func (d *ddl) pollTiFlashReplicaStatus(ctx sessionctx.Context, pollTiFlashContex
 
 			if !avail {
 				logutil.BgLogger().Info("Tiflash replica is not available", zap.Int64("id", tb.ID), zap.Uint64("region need", uint64(regionCount)), zap.Uint64("region have", uint64(flashRegionCount)))
-				err := infosync.UpdateTiFlashTableSyncProgress(context.Background(), tb.ID, float64(flashRegionCount)/float64(regionCount))
+				progress := float64(flashRegionCount) / float64(regionCount)
+				err := infosync.UpdateTiFlashTableSyncProgress(context.Background(), tb.ID, progress)
 				if err != nil {
 					return false, err
 				}
+				now := time.Now()
+				start, ok := pollTiFlashContext.SyncStarts[tb.ID]
+				if !ok {
+					start = TiFlashSyncSample{Time: now, Progress: progress}
+					pollTiFlashContext.SyncStarts[tb.ID] = start
+				}
+				detail := &infosync.TiFlashTableSyncDetail{
+					ReplicatedRegions: flashRegionCount,
+					TotalRegions:      regionCount,
+					TotalBytes:        stats.StorageSize * 1024 * 1024,
+					ETA:               EstimateTiFlashSyncETA(start, now, progress),
+				}
+				if err := infosync.UpdateTiFlashTableSyncDetail(context.Background(), tb.ID, detail); err != nil {
+					return false, err
+				}
 			} else {
 				logutil.BgLogger().Info("Tiflash replica is available", zap.Int64("id", tb.ID), zap.Uint64("region need", uint64(regionCount)))
+				delete(pollTiFlashContext.SyncStarts, tb.ID)
 				err := infosync.DeleteTiFlashTableSyncProgress(tb.ID)
 				if err != nil {
 					return false, err
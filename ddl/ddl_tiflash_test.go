@@ -367,6 +367,37 @@ func (s *tiflashDDLTestSuite) TestTiFlashReplicaAvailable(c *C) {
 	c.Assert(replica, IsNil)
 }
 
+// High priority TiFlash replica shall be accelerated, and changing the priority shall keep the replica available.
+func (s *tiflashDDLTestSuite) TestTiFlashReplicaHighPriority(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists ddltiflash")
+	tk.MustExec("create table ddltiflash(z int)")
+	tk.MustExec("alter table ddltiflash set tiflash replica 1 high_priority")
+	tb, err := s.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("ddltiflash"))
+	c.Assert(err, IsNil)
+	c.Assert(tb.Meta().TiFlashReplica.HighPriority, IsTrue)
+	table, ok := s.tiflash.GetTableSyncStatus(int(tb.Meta().ID))
+	c.Assert(ok, IsTrue)
+	c.Assert(table.Accel, IsTrue)
+	time.Sleep(ddl.PollTiFlashInterval * RoundToBeAvailable * 3)
+	CheckTableAvailable(s.dom, c, 1, []string{})
+
+	tk.MustExec("alter table ddltiflash set tiflash replica 1")
+	tb, err = s.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("ddltiflash"))
+	c.Assert(err, IsNil)
+	c.Assert(tb.Meta().TiFlashReplica.HighPriority, IsFalse)
+	CheckTableAvailable(s.dom, c, 1, []string{})
+}
+
+func (s *tiflashDDLTestSuite) TestEstimateTiFlashSyncETA(c *C) {
+	now := time.Now()
+	start := ddl.TiFlashSyncSample{Time: now.Add(-time.Minute), Progress: 0.2}
+	c.Assert(ddl.EstimateTiFlashSyncETA(start, now, 0.2).IsZero(), IsTrue)
+	c.Assert(ddl.EstimateTiFlashSyncETA(start, now, 0.1).IsZero(), IsTrue)
+	c.Assert(ddl.EstimateTiFlashSyncETA(start, now, 0.6), Equals, now.Add(time.Minute))
+}
+
 // Truncate partition shall not block.
 func (s *tiflashDDLTestSuite) TestTiFlashTruncatePartition(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
@@ -92,10 +92,23 @@ type worker struct {
 	reorgCtx        *reorgCtx    // reorgCtx is used for reorganization.
 	delRangeManager delRangeManager
 	logCtx          context.Context
+	// blockedQueue is the state of the DDL job queue when no job in it can run because of the paused jobs.
+	// The queue isn't scanned again until its state changes.
+	blockedQueue *blockedQueueState
+	// jobIdx is the index of the running job in the DDL queue.
+	jobIdx int64
 
 	ddlJobCache
 }
 
+// blockedQueueState identifies the state of the DDL job queue. It changes when a job is added to or removed
+// from the queue, or the queued jobs are paused, resumed or cancelled.
+type blockedQueueState struct {
+	headJobID int64
+	queueLen  int64
+	updateVer int64
+}
+
 // ddlJobCache is a cache for each DDL job.
 type ddlJobCache struct {
 	// below fields are cache for top sql
@@ -343,6 +356,118 @@ func (w *worker) getFirstDDLJob(t *meta.Meta) (*model.Job, error) {
 	return job, errors.Trace(err)
 }
 
+func isPausedJob(job *model.Job) bool {
+	return job.Paused && !job.IsCancelling() && !job.IsFinished()
+}
+
+// hasJobStarted returns whether the DDL job has started to run.
+func hasJobStarted(job *model.Job) bool {
+	return job.State != model.JobStateNone
+}
+
+// isTableLocalJob returns whether the DDL job only changes its own table and
+// doesn't change the names or the IDs of the tables and databases.
+func isTableLocalJob(job *model.Job) bool {
+	switch job.Type {
+	case model.ActionAddColumn, model.ActionDropColumn, model.ActionAddColumns, model.ActionDropColumns,
+		model.ActionModifyColumn, model.ActionSetDefaultValue, model.ActionAddIndex, model.ActionDropIndex,
+		model.ActionAddPrimaryKey, model.ActionDropPrimaryKey, model.ActionDropIndexes, model.ActionRenameIndex,
+		model.ActionAlterIndexVisibility, model.ActionRebaseAutoID, model.ActionRebaseAutoRandomBase,
+		model.ActionShardRowID, model.ActionModifyTableAutoIdCache, model.ActionModifyTableComment,
+		model.ActionModifyTableCharsetAndCollate, model.ActionAddTablePartition, model.ActionDropTablePartition,
+		model.ActionTruncateTablePartition, model.ActionSetTiFlashReplica, model.ActionUpdateTiFlashReplicaStatus,
+		model.ActionAlterTableAttributes, model.ActionAlterTablePartitionAttributes, model.ActionAlterTablePlacement,
+		model.ActionAlterTablePartitionPlacement, model.ActionAlterCacheTable, model.ActionAlterNoCacheTable,
+		model.ActionAlterTableStatsOptions:
+		return true
+	}
+	return false
+}
+
+// isDependentJob returns whether the DDL job must run after the job queued before it.
+// Only the table local jobs on different tables can run out of the queue order.
+func isDependentJob(job, prev *model.Job) bool {
+	return !isTableLocalJob(job) || !isTableLocalJob(prev) || job.TableID == prev.TableID
+}
+
+// jobPriorityRank returns the rank of the priority of the DDL job, the job with the higher rank runs earlier.
+func jobPriorityRank(job *model.Job) int {
+	switch job.Priority {
+	case kv.PriorityHigh:
+		return 2
+	case kv.PriorityNormal:
+		return 1
+	}
+	return 0
+}
+
+// getRunnableDDLJob gets the DDL job to run and its index in the DDL queue. A job which has started runs until
+// it's finished, and nothing runs while it's paused, because the worker only handles the schema change of one
+// job at a time. Otherwise, the job with the highest priority runs among the jobs which aren't paused and don't
+// depend on the jobs queued before them, and the earlier one runs if their priorities are the same.
+// The queue isn't reordered, and it's scanned only if it has changed since the last scan found no job to run.
+func (w *worker) getRunnableDDLJob(t *meta.Meta, head *model.Job) (*model.Job, int64, error) {
+	if hasJobStarted(head) && !isPausedJob(head) {
+		return head, 0, nil
+	}
+	queueLen, err := t.DDLJobQueueLen()
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	if queueLen == 1 && !isPausedJob(head) {
+		return head, 0, nil
+	}
+	updateVer, err := t.GetDDLJobUpdateVersion()
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	state := blockedQueueState{headJobID: head.ID, queueLen: queueLen, updateVer: updateVer}
+	if w.blockedQueue != nil && *w.blockedQueue == state {
+		return nil, 0, nil
+	}
+
+	// The jobs are got in the reverse order of the queue.
+	jobs, err := t.GetAllDDLJobsInQueue()
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	for i, j := 0, len(jobs)-1; i < j; i, j = i+1, j-1 {
+		jobs[i], jobs[j] = jobs[j], jobs[i]
+	}
+	var runnable *model.Job
+	idx := 0
+	for i, job := range jobs {
+		if hasJobStarted(job) {
+			runnable, idx = job, i
+			if isPausedJob(job) {
+				runnable = nil
+			}
+			break
+		}
+		if isPausedJob(job) || (runnable != nil && jobPriorityRank(job) <= jobPriorityRank(runnable)) {
+			continue
+		}
+		isDependent := false
+		for _, prev := range jobs[:i] {
+			if isDependent = isDependentJob(job, prev); isDependent {
+				break
+			}
+		}
+		if !isDependent {
+			runnable, idx = job, i
+		}
+	}
+	if runnable == nil {
+		w.blockedQueue = &state
+		return nil, 0, nil
+	}
+	w.blockedQueue = nil
+	if idx != 0 && !hasJobStarted(runnable) {
+		logutil.Logger(w.logCtx).Info("[ddl] run the DDL job before the jobs queued earlier", zap.String("job", runnable.String()))
+	}
+	return runnable, int64(idx), nil
+}
+
 // handleUpdateJobError handles the too large DDL job.
 func (w *worker) handleUpdateJobError(t *meta.Meta, job *model.Job, err error) error {
 	if err == nil {
@@ -377,7 +502,7 @@ func (w *worker) updateDDLJob(t *meta.Meta, job *model.Job, meetErr bool) error
 			zap.String("job", job.String()))
 		updateRawArgs = false
 	}
-	return errors.Trace(t.UpdateDDLJob(0, job, updateRawArgs))
+	return errors.Trace(t.UpdateDDLJob(w.jobIdx, job, updateRawArgs))
 }
 
 func (w *worker) deleteRange(ctx context.Context, job *model.Job) error {
@@ -400,15 +525,17 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 
 	if !job.IsCancelled() {
 		switch job.Type {
-		case model.ActionAddIndex, model.ActionAddPrimaryKey:
+		case model.ActionAddIndex, model.ActionAddPrimaryKey, model.ActionCreateTableAsSelect:
 			if job.State != model.JobStateRollbackDone {
 				break
 			}
 
-			// After rolling back an AddIndex operation, we need to use delete-range to delete the half-done index data.
+			// After rolling back an AddIndex or a CreateTableAsSelect operation, we need to use delete-range to delete
+			// the half-done index data or the inserted rows.
 			err = w.deleteRange(w.ddlJobCtx, job)
 		case model.ActionDropSchema, model.ActionDropTable, model.ActionTruncateTable, model.ActionDropIndex, model.ActionDropPrimaryKey,
-			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn, model.ActionDropIndexes:
+			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn, model.ActionDropIndexes,
+			model.ActionAlterTableClusteredIndex:
 			err = w.deleteRange(w.ddlJobCtx, job)
 		}
 	}
@@ -427,7 +554,7 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 		return errors.Trace(err)
 	}
 
-	_, err = t.DeQueueDDLJob()
+	err = t.RemoveDDLJobByIdx(w.jobIdx)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -527,6 +654,19 @@ func (w *worker) handleDDLJobQueue(d *ddlCtx) error {
 				return errors.Trace(err)
 			}
 
+			// The paused jobs are skipped until they are resumed or cancelled, and the jobs
+			// queued after them run by priority if they don't depend on the earlier jobs.
+			job, w.jobIdx, err = w.getRunnableDDLJob(t, job)
+			if job == nil || err != nil {
+				return errors.Trace(err)
+			}
+			// All the jobs are held while the whole DDL is paused, e.g. by a volume snapshot backup.
+			paused, err := t.IsDDLPaused(time.Now())
+			if err != nil || paused {
+				job = nil
+				return errors.Trace(err)
+			}
+
 			// only general ddls allowed to be executed when TiKV is disk full.
 			if w.tp == addIdxWorker && job.IsRunning() {
 				txn.SetDiskFullOpt(kvrpcpb.DiskFullOpt_NotAllowedOnFull)
@@ -603,6 +743,12 @@ func (w *worker) handleDDLJobQueue(d *ddlCtx) error {
 		// If the job is done or still running or rolling back, we will wait 2 * lease time to guarantee other servers to update
 		// the newest schema.
 		ctx, cancel := context.WithTimeout(w.ctx, waitTime)
+		if variable.EnableMDL.Load() {
+			// Other servers report the new schema version only after the transactions holding the metadata lock
+			// at the old version finish, so the owner can't stop waiting after 2 * lease time.
+			cancel()
+			ctx, cancel = context.WithCancel(w.ctx)
+		}
 		w.waitSchemaChanged(ctx, d, waitTime, schemaVer, job)
 		cancel()
 
@@ -769,6 +915,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onCreateTable(d, t, job)
 	case model.ActionCreateTables:
 		ver, err = onCreateTables(d, t, job)
+	case model.ActionCreateTableAsSelect:
+		ver, err = w.onCreateTableAsSelect(d, t, job)
 	case model.ActionRepairTable:
 		ver, err = onRepairTable(d, t, job)
 	case model.ActionCreateView:
@@ -778,7 +926,7 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 	case model.ActionDropTablePartition:
 		ver, err = w.onDropTablePartition(d, t, job)
 	case model.ActionTruncateTablePartition:
-		ver, err = onTruncateTablePartition(d, t, job)
+		ver, err = w.onTruncateTablePartition(d, t, job)
 	case model.ActionExchangeTablePartition:
 		ver, err = w.onExchangeTablePartition(d, t, job)
 	case model.ActionAddColumn:
@@ -791,6 +939,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onDropColumns(t, job)
 	case model.ActionModifyColumn:
 		ver, err = w.onModifyColumn(d, t, job)
+	case model.ActionAlterTableClusteredIndex:
+		ver, err = w.onAlterTableClusteredIndex(d, t, job)
 	case model.ActionSetDefaultValue:
 		ver, err = onSetDefaultValue(t, job)
 	case model.ActionAddIndex:
@@ -902,6 +1052,12 @@ func (w *worker) waitSchemaChanged(ctx context.Context, d *ddlCtx, waitTime time
 	if !job.IsRunning() && !job.IsRollingback() && !job.IsDone() && !job.IsRollbackDone() {
 		return
 	}
+	if variable.EnableMDL.Load() && d.mdlWaiter != nil && job.TableID != 0 && latestSchemaVersion != 0 {
+		// Wait for the transactions on this server which hold the metadata lock of the table at an old schema version.
+		if err := d.mdlWaiter(ctx, job, latestSchemaVersion); err != nil {
+			logutil.Logger(w.logCtx).Info("[ddl] wait metadata lock released failed", zap.Int64("ver", latestSchemaVersion), zap.Error(err))
+		}
+	}
 	if waitTime == 0 {
 		return
 	}
@@ -1022,6 +1178,26 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 			newIDs := job.CtxVars[1].([]int64)
 			diff.AffectedOpts = buildPlacementAffects(oldIDs, newIDs)
 		}
+	case model.ActionAlterTableClusteredIndex:
+		diff.TableID = job.TableID
+		if job.SchemaState == model.StatePublic {
+			// The table is replaced by its changing table, which has a new table ID.
+			var clustered bool
+			err = job.DecodeArgs(&clustered, &diff.TableID)
+			if err != nil {
+				return 0, errors.Trace(err)
+			}
+			diff.OldTableID = job.TableID
+		}
+	case model.ActionCreateTableAsSelect:
+		// The table exists before the version unless it's created by the version, and it's dropped
+		// by the version if the job is rolled back.
+		if job.SchemaState != model.StateNone {
+			diff.OldTableID = job.TableID
+		}
+		if !job.IsRollingback() {
+			diff.TableID = job.TableID
+		}
 	case model.ActionCreateView:
 		tbInfo := &model.TableInfo{}
 		var orReplace bool
@@ -1096,6 +1272,14 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 				diff.AffectedOpts = buildPlacementAffects(oldIDs, oldIDs)
 			}
 		}
+	case model.ActionModifyColumn:
+		diff.TableID = job.TableID
+		// affects are used to reload the views which refer to the renamed column.
+		if len(job.CtxVars) > 0 {
+			if affects, ok := job.CtxVars[0].([]*model.AffectedOption); ok {
+				diff.AffectedOpts = affects
+			}
+		}
 	default:
 		diff.TableID = job.TableID
 	}
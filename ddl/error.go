@@ -33,9 +33,12 @@ var (
 	errFileNotFound          = dbterror.ClassDDL.NewStd(mysql.ErrFileNotFound)
 	errRunMultiSchemaChanges = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "multi schema change"), nil))
 	errWaitReorgTimeout      = dbterror.ClassDDL.NewStdErr(mysql.ErrLockWaitTimeout, mysql.MySQLErrName[mysql.ErrWaitReorgTimeout])
+	errDDLTimeout            = dbterror.ClassDDL.NewStdErr(mysql.ErrLockWaitTimeout, parser_mysql.Message("DDL job %d is cancelled because it does not finish within tidb_ddl_timeout", nil))
 	errInvalidStoreVer       = dbterror.ClassDDL.NewStd(mysql.ErrInvalidStoreVersion)
 	// ErrRepairTableFail is used to repair tableInfo in repair mode.
 	ErrRepairTableFail = dbterror.ClassDDL.NewStd(mysql.ErrRepairTable)
+	// errCreateTableAsSelectInterrupted is returned when the DDL owner changes while inserting the rows of CREATE TABLE ... SELECT.
+	errCreateTableAsSelectInterrupted = dbterror.ClassDDL.NewStdErr(mysql.ErrUnknown, parser_mysql.Message("the rows of CREATE TABLE `%s` ... SELECT were partially inserted before the DDL owner changed", nil))
 
 	// We don't support dropping column with index covered now.
 	errCantDropColWithIndex                   = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "drop column with index"), nil))
@@ -255,6 +258,9 @@ var (
 	// ErrCheckNoSuchTable is returned when exchanged normal table is view or sequence.
 	ErrCheckNoSuchTable         = dbterror.ClassDDL.NewStd(mysql.ErrCheckNoSuchTable)
 	errUnsupportedPartitionType = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "partition type of table %s when exchanging partition"), nil))
+	// errUnsupportedExchangeGlobalIndex is returned when exchanging a partition of the table with global indexes,
+	// the global index entries of the exchanged table can't be converted in place.
+	errUnsupportedExchangeGlobalIndex = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "exchanging partition of table %s with global index"), nil))
 	// ErrPartitionExchangeDifferentOption is returned when attribute does not match between partition table and normal table.
 	ErrPartitionExchangeDifferentOption = dbterror.ClassDDL.NewStd(mysql.ErrPartitionExchangeDifferentOption)
 	// ErrTableOptionUnionUnsupported is returned when create/alter table with union option.
@@ -298,6 +304,8 @@ var (
 	ErrOptOnCacheTable                  = dbterror.ClassDDL.NewStd(mysql.ErrOptOnCacheTable)
 	errUnsupportedOnCommitPreserve      = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("TiDB doesn't support ON COMMIT PRESERVE ROWS for now", nil))
 	errUnsupportedClusteredSecondaryKey = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("CLUSTERED/NONCLUSTERED keyword is only supported for primary key", nil))
+	// errUnsupportedAlterClusteredIndex returns when the clustered index of a table can't be changed.
+	errUnsupportedAlterClusteredIndex = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "changing clustered index of %s"), nil))
 
 	// ErrUnsupportedLocalTempTableDDL returns when ddl operation unsupported for local temporary table
 	ErrUnsupportedLocalTempTableDDL = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("TiDB doesn't support %s for local temporary table", nil))
@@ -309,6 +317,8 @@ var (
 	errDependentByFunctionalIndex = dbterror.ClassDDL.NewStd(mysql.ErrDependentByFunctionalIndex)
 	// errFunctionalIndexOnBlob when the expression of expression index returns blob or text.
 	errFunctionalIndexOnBlob = dbterror.ClassDDL.NewStd(mysql.ErrFunctionalIndexOnBlob)
+	// ErrTableWithoutPrimaryKey is returned when creating or altering a table without primary key and sql_require_primary_key is on.
+	ErrTableWithoutPrimaryKey = dbterror.ClassDDL.NewStd(mysql.ErrTableWithoutPrimaryKey)
 	// ErrIncompatibleTiFlashAndPlacement when placement and tiflash replica options are set at the same time
 	ErrIncompatibleTiFlashAndPlacement = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("Placement and tiflash replica options cannot be set at the same time", nil))
 )
@@ -16,6 +16,8 @@ package ddl
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -24,6 +26,8 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/ddl/ingest"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
@@ -33,7 +37,9 @@ import (
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
@@ -605,6 +611,7 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 		}
 		// Finish this job.
 		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		asyncNotifyEvent(d, &ddlutil.Event{Tp: job.Type, TableInfo: tblInfo, IndexInfo: indexInfo})
 	default:
 		err = ErrInvalidDDLState.GenWithStackByArgs("index", tblInfo.State)
 	}
@@ -1031,6 +1038,12 @@ type addIndexWorker struct {
 	idxKeyBufs         [][]byte
 	batchCheckKeys     []kv.Key
 	distinctCheckFlags []bool
+
+	// ingestEngine is not nil if the index data is backfilled by ingest,
+	// the rows are read from the snapshot of snapshotVer.
+	ingestEngine *ingest.Engine
+	snapshotVer  uint64
+	ingestPairs  []ingest.Pair
 }
 
 func newAddIndexWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, indexInfo *model.IndexInfo, decodeColMap map[int64]decoder.Column, sqlMode mysql.SQLMode) *addIndexWorker {
@@ -1144,7 +1157,7 @@ func (w *baseIndexWorker) updateRowDecoder(handle kv.Handle, recordKey []byte, r
 // 2. Next handle of entry that we need to process.
 // 3. Boolean indicates whether the task is done.
 // 4. error occurs in fetchRowColVals. nil if no error occurs.
-func (w *baseIndexWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBackfillTask) ([]*indexRecord, kv.Key, bool, error) {
+func (w *baseIndexWorker) fetchRowColVals(version uint64, taskRange reorgBackfillTask) ([]*indexRecord, kv.Key, bool, error) {
 	// TODO: use tableScan to prune columns.
 	w.idxRecords = w.idxRecords[:0]
 	startTime := time.Now()
@@ -1152,7 +1165,7 @@ func (w *baseIndexWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBac
 	// taskDone means that the reorged handle is out of taskRange.endHandle.
 	taskDone := false
 	oprStartTime := startTime
-	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), taskRange.startKey, taskRange.endKey,
+	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, version, taskRange.startKey, taskRange.endKey,
 		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
 			oprEndTime := time.Now()
 			logSlowOperations(oprEndTime.Sub(oprStartTime), "iterateSnapshotRows in baseIndexWorker fetchRowColVals", 0)
@@ -1192,7 +1205,7 @@ func (w *baseIndexWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBac
 		taskDone = true
 	}
 
-	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", txn.StartTS()),
+	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", version),
 		zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
 	return w.idxRecords, w.getNextKey(taskRange, taskDone), taskDone, errors.Trace(err)
 }
@@ -1218,12 +1231,18 @@ func (w *addIndexWorker) checkHandleExists(key kv.Key, value []byte, handle kv.H
 	if hasBeenBackFilled {
 		return nil
 	}
+	return genKeyExistsErr(key, value, tblInfo, idxInfo)
+}
+
+// genKeyExistsErr generates the duplicate entry error with the index values decoded from the index key-value.
+func genKeyExistsErr(key kv.Key, value []byte, tblInfo *model.TableInfo, idxInfo *model.IndexInfo) error {
+	idxColLen := len(idxInfo.Columns)
 	colInfos := tables.BuildRowcodecColInfoForIndexColumns(idxInfo, tblInfo)
 	values, err := tablecodec.DecodeIndexKV(key, value, idxColLen, tablecodec.HandleNotNeeded, colInfos)
 	if err != nil {
 		return err
 	}
-	indexName := w.index.Meta().Name.String()
+	indexName := idxInfo.Name.String()
 	valueStr := make([]string, 0, idxColLen)
 	for i, val := range values[:idxColLen] {
 		d, err := tablecodec.DecodeColumnValue(val, colInfos[i].Ft, time.Local)
@@ -1302,6 +1321,9 @@ func (w *addIndexWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskC
 			panic("panic test")
 		}
 	})
+	if w.ingestEngine != nil {
+		return w.backfillDataByIngest(handleRange)
+	}
 
 	oprStartTime := time.Now()
 	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
@@ -1309,7 +1331,7 @@ func (w *addIndexWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskC
 		taskCtx.scanCount = 0
 		txn.SetOption(kv.Priority, w.priority)
 
-		idxRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		idxRecords, nextKey, taskDone, err := w.fetchRowColVals(txn.StartTS(), handleRange)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -1356,11 +1378,237 @@ func (w *addIndexWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskC
 	return
 }
 
+// backfillDataByIngest writes the index data of the rows in the reorg snapshot to the ingest engine.
+// Unlike BackfillDataInTxn, the rows are neither locked nor checked for the uniqueness here. The index
+// data is imported with the snapshot version as the commit ts, so the later changes of the DMLs are
+// kept, and the uniqueness is checked before importing.
+func (w *addIndexWorker) backfillDataByIngest(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, err error) {
+	oprStartTime := time.Now()
+	idxRecords, nextKey, taskDone, err := w.fetchRowColVals(w.snapshotVer, handleRange)
+	if err != nil {
+		return taskCtx, errors.Trace(err)
+	}
+	taskCtx.nextKey = nextKey
+	taskCtx.done = taskDone
+
+	stmtCtx := w.sessCtx.GetSessionVars().StmtCtx
+	tblInfo, idxInfo := w.table.Meta(), w.index.Meta()
+	needRsData := tables.NeedRestoredData(idxInfo.Columns, tblInfo.Columns)
+	physicalID := w.table.(table.PhysicalTable).GetPhysicalID()
+	pairs := w.ingestPairs[:0]
+	for _, idxRecord := range idxRecords {
+		key, distinct, err := w.index.GenIndexKey(stmtCtx, idxRecord.vals, idxRecord.handle, nil)
+		if err != nil {
+			return taskCtx, errors.Trace(err)
+		}
+		val, err := tablecodec.GenIndexValuePortal(stmtCtx, tblInfo, idxInfo, needRsData, distinct, false, idxRecord.vals, idxRecord.handle, physicalID, idxRecord.rsData)
+		if err != nil {
+			return taskCtx, errors.Trace(err)
+		}
+		pairs = append(pairs, ingest.Pair{Key: key, Value: val, RowID: idxRecord.handle.Encoded()})
+	}
+	w.ingestPairs = pairs
+	if err = w.ingestEngine.Write(pairs); err != nil {
+		return taskCtx, errors.Trace(err)
+	}
+	taskCtx.scanCount = len(idxRecords)
+	taskCtx.addedCount = len(idxRecords)
+	logSlowOperations(time.Since(oprStartTime), "AddIndexBackfillDataByIngest", 3000)
+	return taskCtx, nil
+}
+
 func (w *worker) addPhysicalTableIndex(t table.PhysicalTable, indexInfo *model.IndexInfo, reorgInfo *reorgInfo) error {
 	logutil.BgLogger().Info("[ddl] start to add table index", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
+	if canAddIndexByIngest(indexInfo) {
+		return w.addPhysicalTableIndexByIngest(t, indexInfo, reorgInfo)
+	}
 	return w.writePhysicalTableRecord(t, typeAddIndexWorker, indexInfo, nil, nil, reorgInfo)
 }
 
+// canAddIndexByIngest checks whether the index can be backfilled by ingest.
+func canAddIndexByIngest(indexInfo *model.IndexInfo) bool {
+	// The global index data of all the partitions is in the same key range, it's not supported yet.
+	return variable.DDLEnableFastReorg.Load() && !indexInfo.Global
+}
+
+// ingestContext is the context of adding an index of a physical table by ingest.
+type ingestContext struct {
+	engine    *ingest.Engine
+	tbl       table.PhysicalTable
+	indexInfo *model.IndexInfo
+}
+
+// ingestCheckpointPairs is the number of the pairs in the ingest engine to import at a checkpoint.
+const ingestCheckpointPairs = 1 << 20
+
+// addPhysicalTableIndexByIngest writes the index data of the table to a local engine and imports
+// the engine into the storage at the checkpoints and after all the rows are scanned. The reorg
+// handle is only updated after the engine is imported, since the engine is lost if the DDL owner
+// changes, see checkpointIngest.
+// The GC safe point is kept at the snapshot version until the engine is imported, see ingest.KeepGCSafePoint.
+func (w *worker) addPhysicalTableIndexByIngest(t table.PhysicalTable, indexInfo *model.IndexInfo, reorgInfo *reorgInfo) error {
+	releaseSafePoint, err := ingest.KeepGCSafePoint(w.ctx, reorgInfo.d.store, ingest.ServiceSafePointID(reorgInfo.Job.ID), reorgInfo.SnapshotVer)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer releaseSafePoint()
+
+	dir := filepath.Join(config.GetGlobalConfig().TempStoragePath, "ddl-ingest", fmt.Sprintf("%d-%d", reorgInfo.Job.ID, t.GetPhysicalID()))
+	engine, err := ingest.NewEngine(dir, variable.DDLDiskQuota.Load())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err := engine.Close(); err != nil {
+			logutil.BgLogger().Warn("[ddl] close ingest engine failed", zap.String("dir", dir), zap.Error(err))
+		}
+	}()
+
+	reorgInfo.ingestCtx = &ingestContext{engine: engine, tbl: t, indexInfo: indexInfo}
+	defer func() {
+		reorgInfo.ingestCtx = nil
+	}()
+	err = w.writePhysicalTableRecord(t, typeAddIndexWorker, indexInfo, nil, nil, reorgInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	failpoint.Inject("beforeIngestImport", nil)
+	return w.importIngestEngine(reorgInfo)
+}
+
+// checkpointIngest imports the index data in the ingest engine and updates the reorg handle to
+// nextKey if there are enough pairs in the engine, so the backfill is resumed from nextKey instead
+// of the start of the table if the DDL owner changes.
+func (w *worker) checkpointIngest(reorgInfo *reorgInfo, nextKey kv.Key) error {
+	checkpointPairs := int64(ingestCheckpointPairs)
+	failpoint.Inject("mockIngestCheckpointPairs", func(val failpoint.Value) {
+		checkpointPairs = int64(val.(int))
+	})
+	if reorgInfo.ingestCtx.engine.Count() < checkpointPairs {
+		return nil
+	}
+	if err := w.importIngestEngine(reorgInfo); err != nil {
+		return errors.Trace(err)
+	}
+	if err := reorgInfo.ingestCtx.engine.Reset(); err != nil {
+		return errors.Trace(err)
+	}
+	w.reorgCtx.setNextKey(nextKey)
+	err := reorgInfo.UpdateReorgMeta(nextKey)
+	logutil.BgLogger().Info("[ddl] checkpoint the ingest of the index data",
+		zap.Int64("jobID", reorgInfo.Job.ID),
+		zap.Int64("physicalTableID", reorgInfo.PhysicalTableID),
+		zap.String("nextHandle", tryDecodeToHandleString(nextKey)), zap.Error(err))
+	return errors.Trace(err)
+}
+
+// importIngestEngine imports the index data in the ingest engine into the storage.
+func (w *worker) importIngestEngine(reorgInfo *reorgInfo) error {
+	ic := reorgInfo.ingestCtx
+	if err := ic.engine.Flush(); err != nil {
+		return errors.Trace(err)
+	}
+	startTime := time.Now()
+	importer, err := ingest.NewImporter(reorgInfo.d.store)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer importer.Close()
+	if ic.indexInfo.Unique {
+		err = importUniqueIndexByIngest(w.ctx, reorgInfo.d.store, importer, ic.engine, reorgInfo.SnapshotVer, ic.tbl.Meta(), ic.indexInfo)
+	} else {
+		err = importer.Import(w.ctx, ic.engine, nil, nil, reorgInfo.SnapshotVer, nil)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logutil.BgLogger().Info("[ddl] import index data by ingest",
+		zap.Int64("physicalTableID", ic.tbl.GetPhysicalID()),
+		zap.Int64("count", ic.engine.Count()),
+		zap.String("takeTime", time.Since(startTime).String()))
+	return nil
+}
+
+// uniqueIngestBatchSize is the number of the unique index keys checked and imported in a transaction.
+const uniqueIngestBatchSize = 4096
+
+// importUniqueIndexByIngest imports the unique index data in the ingest engine in batches. The keys of
+// a batch are locked before they are checked for the duplicates, and unlocked after they are imported,
+// so the DMLs can't write the conflicting index data between the check and the import.
+func importUniqueIndexByIngest(ctx context.Context, store kv.Storage, importer ingest.Importer, engine *ingest.Engine,
+	commitTS uint64, tblInfo *model.TableInfo, idxInfo *model.IndexInfo) error {
+	keys := make([]kv.Key, 0, uniqueIngestBatchSize)
+	values := make(map[string][]byte, uniqueIngestBatchSize)
+	importBatch := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		err := ingest.RunInLockedTxn(ctx, store, keys, commitTS, func(ctx context.Context, txn *ingest.LockedTxn) error {
+			ver, err := getValidCurrentVersion(store)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			// No one can write the locked keys after ver.
+			if err = checkIngestDuplicates(ctx, store.GetSnapshot(ver), keys, values, tblInfo, idxInfo); err != nil {
+				return errors.Trace(err)
+			}
+			return importer.Import(ctx, engine, keys[0], keys[len(keys)-1].Next(), commitTS, txn)
+		})
+		keys = keys[:0]
+		for key := range values {
+			delete(values, key)
+		}
+		return errors.Trace(err)
+	}
+
+	it := engine.NewIter(nil, nil)
+	defer terror.Call(it.Close)
+	var prevKey kv.Key
+	for it.First(); it.Valid(); it.Next() {
+		// The pairs with the same key are generated by different rows.
+		if prevKey.Cmp(it.Key()) == 0 {
+			return genKeyExistsErr(prevKey, it.Value(), tblInfo, idxInfo)
+		}
+		prevKey = append(prevKey[:0], it.Key()...)
+		keys = append(keys, kv.Key(string(prevKey)))
+		values[string(prevKey)] = append([]byte{}, it.Value()...)
+		if len(keys) >= uniqueIngestBatchSize {
+			if err := importBatch(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return errors.Trace(err)
+	}
+	return importBatch()
+}
+
+// checkIngestDuplicates checks whether the unique index data in the ingest engine conflicts with
+// the index data written by the DMLs during the reorganization.
+func checkIngestDuplicates(ctx context.Context, snap kv.Snapshot, keys []kv.Key, values map[string][]byte,
+	tblInfo *model.TableInfo, idxInfo *model.IndexInfo) error {
+	existedVals, err := snap.BatchGet(ctx, keys)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	idxColLen := len(idxInfo.Columns)
+	for key, existedVal := range existedVals {
+		existedHandle, err := tablecodec.DecodeIndexHandle(kv.Key(key), existedVal, idxColLen)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		handle, err := tablecodec.DecodeIndexHandle(kv.Key(key), values[key], idxColLen)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !existedHandle.Equal(handle) {
+			return genKeyExistsErr(kv.Key(key), existedVal, tblInfo, idxInfo)
+		}
+	}
+	return nil
+}
+
 // addTableIndex handles the add index reorganization state for a table.
 func (w *worker) addTableIndex(t table.Table, idx *model.IndexInfo, reorgInfo *reorgInfo) error {
 	var err error
@@ -1520,7 +1768,7 @@ func (w *cleanUpIndexWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (t
 		taskCtx.scanCount = 0
 		txn.SetOption(kv.Priority, w.priority)
 
-		idxRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		idxRecords, nextKey, taskDone, err := w.fetchRowColVals(txn.StartTS(), handleRange)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -1591,8 +1839,9 @@ func (w *worker) updateReorgInfoForPartitions(t table.PartitionedTable, reorg *r
 			if i == len(partitionIDs)-1 {
 				return true, nil
 			}
+			pid = partitionIDs[i+1]
+			break
 		}
-		pid = partitionIDs[i+1]
 	}
 
 	currentVer, err := getValidCurrentVersion(reorg.d.store)
@@ -0,0 +1,213 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"math"
+	"os"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/codec"
+	"go.uber.org/atomic"
+)
+
+// Pair is a key-value pair written to the engine.
+type Pair struct {
+	Key   []byte
+	Value []byte
+	// RowID identifies the row which generates the pair. The pairs with the same
+	// key are kept in the engine if their row IDs are different, so the conflicts
+	// of the unique index can be found before importing.
+	RowID []byte
+}
+
+// Engine sorts the key-value pairs in the local disk before they are imported
+// into the storage. It's safe to write to an engine concurrently.
+//
+// The pairs are stored with the key encoded in memory comparable format followed
+// by the row ID, so the pairs with the same key are adjacent in the engine.
+type Engine struct {
+	dir       string
+	db        *pebble.DB
+	diskQuota uint64
+	count     atomic.Int64
+}
+
+// NewEngine creates an engine in the directory dir, the files left in the
+// directory are removed. The engine fails to write if the disk usage exceeds
+// the diskQuota.
+func NewEngine(dir string, diskQuota uint64) (*Engine, error) {
+	db, err := openDB(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Engine{dir: dir, db: db, diskQuota: diskQuota}, nil
+}
+
+func openDB(dir string) (*pebble.DB, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, errors.Trace(err)
+	}
+	opts := &pebble.Options{
+		MemTableSize: 64 << 20,
+		// The engine is dropped if TiDB restarts, so the WAL is useless.
+		DisableWAL: true,
+		// The engine is read only once when importing, it's not worth compacting the data.
+		L0CompactionThreshold: math.MaxInt32,
+		L0StopWritesThreshold: math.MaxInt32,
+		MaxOpenFiles:          1024,
+	}
+	db, err := pebble.Open(dir, opts)
+	return db, errors.Trace(err)
+}
+
+// Write writes the pairs to the engine.
+func (e *Engine) Write(pairs []Pair) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+	if usage := e.db.Metrics().DiskSpaceUsage(); usage > e.diskQuota {
+		return errors.Annotatef(ErrDiskQuotaExceeded, "usage %d, quota %d", usage, e.diskQuota)
+	}
+	b := e.db.NewBatch()
+	defer terror.Call(b.Close)
+	var buf []byte
+	for _, p := range pairs {
+		buf = encodeKey(buf[:0], p.Key, p.RowID)
+		if err := b.Set(buf, p.Value, nil); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := b.Commit(pebble.NoSync); err != nil {
+		return errors.Trace(err)
+	}
+	e.count.Add(int64(len(pairs)))
+	return nil
+}
+
+// Count returns the number of the pairs written to the engine.
+func (e *Engine) Count() int64 {
+	return e.count.Load()
+}
+
+// Flush flushes the pairs in memory to the disk.
+func (e *Engine) Flush() error {
+	return errors.Trace(e.db.Flush())
+}
+
+// NewIter creates an iterator on the pairs whose keys are in [lower, upper).
+// An empty lower or upper means the range is unbounded.
+func (e *Engine) NewIter(lower, upper []byte) *Iter {
+	opts := &pebble.IterOptions{}
+	if len(lower) > 0 {
+		opts.LowerBound = codec.EncodeBytes(nil, lower)
+	}
+	if len(upper) > 0 {
+		opts.UpperBound = codec.EncodeBytes(nil, upper)
+	}
+	return &Iter{iter: e.db.NewIter(opts)}
+}
+
+// Reset removes all the pairs in the engine, e.g. after they are imported. It
+// must not be called concurrently with the other methods.
+func (e *Engine) Reset() error {
+	if err := e.db.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	db, err := openDB(e.dir)
+	if err != nil {
+		return err
+	}
+	e.db = db
+	e.count.Store(0)
+	return nil
+}
+
+// Close closes the engine and removes its files.
+func (e *Engine) Close() error {
+	err := e.db.Close()
+	if err1 := os.RemoveAll(e.dir); err == nil {
+		err = err1
+	}
+	return errors.Trace(err)
+}
+
+// Iter iterates the pairs of an engine in the order of the keys.
+type Iter struct {
+	iter *pebble.Iterator
+	key  []byte
+	err  error
+}
+
+// First moves the iterator to the first pair.
+func (it *Iter) First() bool {
+	it.iter.First()
+	return it.decode()
+}
+
+// Last moves the iterator to the last pair.
+func (it *Iter) Last() bool {
+	it.iter.Last()
+	return it.decode()
+}
+
+// Next moves the iterator to the next pair.
+func (it *Iter) Next() bool {
+	it.iter.Next()
+	return it.decode()
+}
+
+func (it *Iter) decode() bool {
+	if !it.iter.Valid() {
+		return false
+	}
+	_, it.key, it.err = codec.DecodeBytes(it.iter.Key(), it.key[:0])
+	return it.err == nil
+}
+
+// Valid returns whether the iterator is positioned at a pair.
+func (it *Iter) Valid() bool {
+	return it.err == nil && it.iter.Valid()
+}
+
+// Key returns the key of the current pair, it's only valid until the next move.
+func (it *Iter) Key() []byte {
+	return it.key
+}
+
+// Value returns the value of the current pair, it's only valid until the next move.
+func (it *Iter) Value() []byte {
+	return it.iter.Value()
+}
+
+// Error returns the error occurred during the iteration.
+func (it *Iter) Error() error {
+	if it.err != nil {
+		return errors.Trace(it.err)
+	}
+	return errors.Trace(it.iter.Error())
+}
+
+// Close closes the iterator.
+func (it *Iter) Close() error {
+	return errors.Trace(it.iter.Close())
+}
+
+func encodeKey(buf, key, rowID []byte) []byte {
+	buf = codec.EncodeBytes(buf, key)
+	return append(buf, rowID...)
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "engine")
+	e, err := NewEngine(dir, 1<<30)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, e.Close())
+		require.NoDirExists(t, dir)
+	}()
+
+	require.NoError(t, e.Write([]Pair{
+		{Key: []byte("b"), Value: []byte("2"), RowID: []byte{1}},
+		{Key: []byte("a\x00"), Value: []byte("1"), RowID: []byte{2}},
+		{Key: []byte("c"), Value: []byte("3"), RowID: []byte{3}},
+	}))
+	require.NoError(t, e.Write([]Pair{
+		{Key: []byte("a"), Value: []byte("0"), RowID: []byte{4}},
+		{Key: []byte("b"), Value: []byte("4"), RowID: []byte{5}},
+	}))
+	require.NoError(t, e.Flush())
+	require.Equal(t, int64(5), e.Count())
+
+	collect := func(lower, upper []byte) []string {
+		it := e.NewIter(lower, upper)
+		defer func() {
+			require.NoError(t, it.Close())
+		}()
+		var res []string
+		for it.First(); it.Valid(); it.Next() {
+			res = append(res, string(it.Key())+"="+string(it.Value()))
+		}
+		require.NoError(t, it.Error())
+		return res
+	}
+	// The pairs with the same key are adjacent, and ordered by the row IDs.
+	require.Equal(t, []string{"a=0", "a\x00=1", "b=2", "b=4", "c=3"}, collect(nil, nil))
+	require.Equal(t, []string{"a\x00=1", "b=2", "b=4"}, collect([]byte("a\x00"), []byte("c")))
+	require.Empty(t, collect([]byte("d"), nil))
+
+	require.NoError(t, e.Reset())
+	require.Equal(t, int64(0), e.Count())
+	require.Empty(t, collect(nil, nil))
+	require.NoError(t, e.Write([]Pair{{Key: []byte("d"), Value: []byte("5")}}))
+	require.Equal(t, []string{"d=5"}, collect(nil, nil))
+}
+
+func TestEngineDiskQuota(t *testing.T) {
+	e, err := NewEngine(filepath.Join(t.TempDir(), "engine"), 1<<30)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, e.Close())
+	}()
+	// The files created by the engine itself don't exceed the quota.
+	e.diskQuota = e.db.Metrics().DiskSpaceUsage()
+
+	require.NoError(t, e.Write([]Pair{{Key: []byte("a"), Value: []byte("1")}}))
+	require.NoError(t, e.Flush())
+	err = e.Write([]Pair{{Key: []byte("b"), Value: []byte("2")}})
+	require.True(t, errors.ErrorEqual(err, ErrDiskQuotaExceeded), "%v", err)
+}
+
+func TestTxnImporter(t *testing.T) {
+	store, err := mockstore.NewMockStore()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, store.Close())
+	}()
+
+	e, err := NewEngine(filepath.Join(t.TempDir(), "engine"), 1<<30)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, e.Close())
+	}()
+	pairs := make([]Pair, 0, txnBatchSize+1)
+	for i := 0; i < txnBatchSize+1; i++ {
+		key := []byte{'k', byte(i >> 8), byte(i)}
+		pairs = append(pairs, Pair{Key: key, Value: key})
+	}
+	require.NoError(t, e.Write(pairs))
+
+	// The keys written after the commit ts are kept.
+	ver, err := store.CurrentVersion(kv.GlobalTxnScope)
+	require.NoError(t, err)
+	err = kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
+		if err := txn.Set(pairs[1].Key, []byte("v")); err != nil {
+			return err
+		}
+		return txn.Delete(pairs[txnBatchSize].Key)
+	})
+	require.NoError(t, err)
+	pairs[1].Value = []byte("v")
+	pairs = pairs[:txnBatchSize]
+
+	importer, err := NewImporter(store)
+	require.NoError(t, err)
+	defer importer.Close()
+	require.IsType(t, &txnImporter{}, importer)
+	require.NoError(t, importer.Import(context.Background(), e, nil, nil, ver.Ver, nil))
+
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	it, err := txn.Iter(kv.Key("k"), kv.Key("l"))
+	require.NoError(t, err)
+	defer it.Close()
+	cnt := 0
+	for ; it.Valid(); cnt++ {
+		require.Equal(t, pairs[cnt].Key, []byte(it.Key()))
+		require.Equal(t, pairs[cnt].Value, it.Value())
+		require.NoError(t, it.Next())
+	}
+	require.Equal(t, len(pairs), cnt)
+	require.NoError(t, txn.Rollback())
+}
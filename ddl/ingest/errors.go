@@ -0,0 +1,26 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import "errors"
+
+var (
+	// ErrDiskQuotaExceeded is from engine.go
+	ErrDiskQuotaExceeded = errors.New("the disk usage of the ingest engine exceeds tidb_ddl_disk_quota")
+	// ErrGCSafePointExceeded is from gc.go
+	ErrGCSafePointExceeded = errors.New("the GC safe point exceeds the snapshot of the ingest")
+	// errRetryable is from tikv.go
+	errRetryable = errors.New("the region is changed, retry later")
+)
@@ -0,0 +1,100 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/tikv/client-go/v2/oracle"
+	"github.com/tikv/client-go/v2/tikv"
+	pd "github.com/tikv/pd/client"
+	"go.uber.org/zap"
+)
+
+// gcSafePointTTL is the TTL of the service safe point in seconds, it's updated
+// every gcSafePointTTL/3 seconds until it's released.
+const gcSafePointTTL = 5 * 60
+
+// ServiceSafePointID returns the ID of the service safe point kept by the
+// ingest of the DDL job.
+func ServiceSafePointID(jobID int64) string {
+	return fmt.Sprintf("tidb-ddl-ingest-%d", jobID)
+}
+
+// KeepGCSafePoint sets a service safe point in PD, so GC doesn't remove the
+// MVCC versions which the snapshot at ts needs until the returned release
+// function is called.
+//
+// The ingested data is written with an old commit ts, if GC collected the
+// versions written by the DMLs after ts, e.g. a deleted index key, the ingested
+// data would become visible and overwrite them. So the safe point must be kept
+// from the snapshot is scanned until the data is ingested.
+func KeepGCSafePoint(ctx context.Context, store kv.Storage, serviceID string, ts uint64) (release func(), err error) {
+	s, ok := store.(tikv.Storage)
+	if !ok {
+		return func() {}, nil
+	}
+	pdCli := s.GetRegionCache().PDClient()
+	if err = updateServiceSafePoint(ctx, pdCli, serviceID, ts); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(gcSafePointTTL * time.Second / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := updateServiceSafePoint(ctx, pdCli, serviceID, ts); err != nil {
+					logutil.BgLogger().Warn("[ddl-ingest] update service safe point failed",
+						zap.String("service", serviceID), zap.Error(err))
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		wg.Wait()
+		if _, err := pdCli.UpdateServiceGCSafePoint(context.Background(), serviceID, 0, 0); err != nil {
+			logutil.BgLogger().Warn("[ddl-ingest] remove service safe point failed",
+				zap.String("service", serviceID), zap.Error(err))
+		}
+	}, nil
+}
+
+func updateServiceSafePoint(ctx context.Context, pdCli pd.Client, serviceID string, ts uint64) error {
+	minSafePoint, err := pdCli.UpdateServiceGCSafePoint(ctx, serviceID, gcSafePointTTL, ts)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// PD doesn't set the service safe point if it's less than the GC safe point.
+	if minSafePoint > ts {
+		return errors.Annotatef(ErrGCSafePointExceeded, "safe point %s, snapshot %s",
+			oracle.GetTimeFromTS(minSafePoint), oracle.GetTimeFromTS(ts))
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	tikvstore "github.com/tikv/client-go/v2/kv"
+	"github.com/tikv/client-go/v2/tikv"
+)
+
+// Importer imports the pairs of an engine into the storage.
+type Importer interface {
+	// Import imports the pairs of the engine whose keys are in [start, end), an
+	// empty start or end means the range is unbounded. The pairs are visible to
+	// the snapshots whose version is not less than commitTS.
+	// If txn is not nil, it holds the locks of the keys in the range. The
+	// importers writing the pairs in transactions write them in txn, and the
+	// caller commits it.
+	Import(ctx context.Context, e *Engine, start, end []byte, commitTS uint64, txn *LockedTxn) error
+	// Close releases the resources of the importer.
+	Close()
+}
+
+// LockedTxn is a pessimistic transaction which holds the locks of the keys to
+// import, see RunInLockedTxn.
+type LockedTxn struct {
+	kv.Transaction
+	// written are the keys written after the commit ts of the imported pairs.
+	written map[string]struct{}
+}
+
+// IsWrittenAfter returns whether the key is written after the commit ts of the
+// imported pairs. The keys only locked after the commit ts may be reported as
+// written too, if the storage records the locks as versions.
+func (txn *LockedTxn) IsWrittenAfter(key []byte) bool {
+	_, ok := txn.written[string(key)]
+	return ok
+}
+
+// RunInLockedTxn locks the keys in a pessimistic transaction and runs f in it.
+// The transaction is committed if f succeeds.
+//
+// The keys are locked at the for update ts commitTS, so a key written after
+// commitTS makes the lock fail with a write conflict, then the transaction is
+// retried and the key is locked at the current ts instead. So the keys written
+// after commitTS are known without reading their MVCC versions one by one.
+func RunInLockedTxn(ctx context.Context, store kv.Storage, keys []kv.Key, commitTS uint64, f func(ctx context.Context, txn *LockedTxn) error) error {
+	written := make(map[string]struct{})
+	for {
+		var conflictKey kv.Key
+		err := kv.RunInNewTxn(ctx, store, false, func(ctx context.Context, txn kv.Transaction) error {
+			txn.SetOption(kv.Pessimistic, true)
+			unwrittenKeys := make([]kv.Key, 0, len(keys))
+			writtenKeys := make([]kv.Key, 0, len(written))
+			for _, key := range keys {
+				if _, ok := written[string(key)]; ok {
+					writtenKeys = append(writtenKeys, key)
+				} else {
+					unwrittenKeys = append(unwrittenKeys, key)
+				}
+			}
+			lockCtx := tikvstore.NewLockCtx(commitTS, variable.DefInnodbLockWaitTimeout*1000, time.Now())
+			if err := txn.LockKeys(ctx, lockCtx, unwrittenKeys...); err != nil {
+				conflictKey = writeConflictKey(err)
+				return errors.Trace(err)
+			}
+			if len(writtenKeys) > 0 {
+				ver, err := store.CurrentVersion(kv.GlobalTxnScope)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				lockCtx = tikvstore.NewLockCtx(ver.Ver, variable.DefInnodbLockWaitTimeout*1000, time.Now())
+				if err = txn.LockKeys(ctx, lockCtx, writtenKeys...); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			return f(ctx, &LockedTxn{Transaction: txn, written: written})
+		})
+		if _, ok := written[string(conflictKey)]; conflictKey == nil || ok {
+			return err
+		}
+		written[string(conflictKey)] = struct{}{}
+	}
+}
+
+// writeConflictKey returns the conflicting key if err is a write conflict.
+func writeConflictKey(err error) kv.Key {
+	if !kv.ErrWriteConflict.Equal(err) {
+		return nil
+	}
+	terr, ok := errors.Cause(err).(*terror.Error)
+	if !ok {
+		return nil
+	}
+	args := terr.Args()
+	if len(args) != 4 {
+		return nil
+	}
+	if keys, ok := args[3].(*kv.WriteConflictKeys); ok {
+		return keys.Key
+	}
+	return nil
+}
+
+// NewImporter creates an importer for the storage. The pairs are ingested as
+// SST files if the storage is TiKV, otherwise they are written in transactions.
+func NewImporter(store kv.Storage) (Importer, error) {
+	if s, ok := store.(tikv.Storage); ok && config.GetGlobalConfig().Store == "tikv" {
+		return newTiKVImporter(s)
+	}
+	return &txnImporter{store: store}, nil
+}
+
+// txnBatchSize is the number of pairs written in a transaction by txnImporter.
+const txnBatchSize = 1024
+
+// txnImporter writes the pairs in transactions, which are committed after
+// commitTS. To keep the changes written after commitTS like the ingested data
+// does, the pairs whose keys are written after commitTS are skipped. It's used
+// by the storages which can't ingest SST files, such as the mock store.
+type txnImporter struct {
+	store kv.Storage
+}
+
+// Import implements the Importer interface.
+func (i *txnImporter) Import(ctx context.Context, e *Engine, start, end []byte, commitTS uint64, txn *LockedTxn) error {
+	it := e.NewIter(start, end)
+	defer terror.Call(it.Close)
+	if txn != nil {
+		for it.First(); it.Valid(); it.Next() {
+			if txn.IsWrittenAfter(it.Key()) {
+				continue
+			}
+			if err := txn.Set(append([]byte{}, it.Key()...), append([]byte{}, it.Value()...)); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return it.Error()
+	}
+	keys := make([]kv.Key, 0, txnBatchSize)
+	values := make([][]byte, 0, txnBatchSize)
+	flush := func() error {
+		err := RunInLockedTxn(ctx, i.store, keys, commitTS, func(ctx context.Context, txn *LockedTxn) error {
+			for j := range keys {
+				if txn.IsWrittenAfter(keys[j]) {
+					continue
+				}
+				if err := txn.Set(keys[j], values[j]); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			return nil
+		})
+		keys, values = keys[:0], values[:0]
+		return errors.Trace(err)
+	}
+	for it.First(); it.Valid(); it.Next() {
+		keys = append(keys, append([]byte{}, it.Key()...))
+		values = append(values, append([]byte{}, it.Value()...))
+		if len(keys) >= txnBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return flush()
+	}
+	return nil
+}
+
+// Close implements the Importer interface.
+func (i *txnImporter) Close() {}
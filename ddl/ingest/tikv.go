@@ -0,0 +1,295 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/import_sstpb"
+	"github.com/pingcap/kvproto/pkg/kvrpcpb"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/tikv/client-go/v2/tikv"
+	pd "github.com/tikv/pd/client"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// writeBatchSize is the number of pairs sent to TiKV in a write request.
+	writeBatchSize = 4096
+	// scanRegionLimit is the number of regions scanned from PD at a time.
+	scanRegionLimit = 128
+	maxRetryTimes   = 5
+	retryInterval   = time.Second
+)
+
+// tikvImporter writes the pairs to the peers of each region as SST files, and
+// then ingests the SST files into the region by its leader.
+// See https://github.com/tikv/tikv/blob/master/components/sst_importer
+type tikvImporter struct {
+	pdCli    pd.Client
+	grpcOpts []grpc.DialOption
+
+	mu    sync.Mutex
+	conns map[uint64]*grpc.ClientConn
+}
+
+func newTiKVImporter(store tikv.Storage) (*tikvImporter, error) {
+	opt := grpc.WithInsecure()
+	security := config.GetGlobalConfig().Security.ClusterSecurity()
+	tlsConf, err := security.ToTLSConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if tlsConf != nil {
+		opt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConf))
+	}
+	return &tikvImporter{
+		pdCli:    store.GetRegionCache().PDClient(),
+		grpcOpts: []grpc.DialOption{opt},
+		conns:    make(map[uint64]*grpc.ClientConn),
+	}, nil
+}
+
+// Import implements the Importer interface.
+func (i *tikvImporter) Import(ctx context.Context, e *Engine, start, end []byte, commitTS uint64, _ *LockedTxn) error {
+	it := e.NewIter(start, end)
+	if !it.First() {
+		terror.Log(it.Close())
+		return it.Error()
+	}
+	start = append([]byte{}, it.Key()...)
+	it.Last()
+	end = kv.Key(it.Key()).Next()
+	if err := it.Close(); err != nil {
+		return err
+	}
+
+	for retry := 0; len(start) > 0; {
+		regions, err := i.pdCli.ScanRegions(ctx, codec.EncodeBytes(nil, start), codec.EncodeBytes(nil, end), scanRegionLimit)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(regions) == 0 {
+			return errors.Errorf("no region found in [%x, %x)", start, end)
+		}
+		for _, region := range regions {
+			var regionEnd []byte
+			regionEnd, err = i.writeAndIngest(ctx, e, region, start, end, commitTS)
+			if err != nil {
+				break
+			}
+			start = regionEnd
+			if len(start) == 0 || bytes.Compare(start, end) >= 0 {
+				return nil
+			}
+		}
+		if err == nil {
+			continue
+		}
+		if !errors.ErrorEqual(err, errRetryable) || retry >= maxRetryTimes {
+			return err
+		}
+		retry++
+		logutil.BgLogger().Info("[ddl-ingest] retry to import", zap.Int("retry", retry), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryInterval):
+		}
+	}
+	return nil
+}
+
+// writeAndIngest imports the pairs in [start, end) of the region, and returns the
+// end key of the region.
+func (i *tikvImporter) writeAndIngest(ctx context.Context, e *Engine, region *pd.Region, start, end []byte, commitTS uint64) ([]byte, error) {
+	regionStart, regionEnd, err := decodeRegionRange(region.Meta)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Compare(regionStart, start) > 0 {
+		start = regionStart
+	}
+	if len(regionEnd) > 0 && bytes.Compare(regionEnd, end) < 0 {
+		end = regionEnd
+	}
+	if region.Leader == nil || region.Leader.GetId() == 0 {
+		return nil, errors.Annotatef(errRetryable, "region %d has no leader", region.Meta.GetId())
+	}
+
+	it := e.NewIter(start, end)
+	defer terror.Call(it.Close)
+	if !it.First() {
+		return regionEnd, it.Error()
+	}
+	first := append([]byte{}, it.Key()...)
+	it.Last()
+	last := append([]byte{}, it.Key()...)
+
+	id := uuid.New()
+	meta := &import_sstpb.SSTMeta{
+		Uuid:        id[:],
+		RegionId:    region.Meta.GetId(),
+		RegionEpoch: region.Meta.GetRegionEpoch(),
+		Range: &import_sstpb.Range{
+			Start: codec.EncodeBytes(nil, first),
+			End:   codec.EncodeBytes(nil, last),
+		},
+	}
+	streams := make([]import_sstpb.ImportSST_WriteClient, 0, len(region.Meta.GetPeers()))
+	for _, peer := range region.Meta.GetPeers() {
+		cli, err := i.getClient(ctx, peer.GetStoreId())
+		if err != nil {
+			return nil, err
+		}
+		stream, err := cli.Write(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err = stream.Send(&import_sstpb.WriteRequest{Chunk: &import_sstpb.WriteRequest_Meta{Meta: meta}}); err != nil {
+			return nil, errors.Trace(err)
+		}
+		streams = append(streams, stream)
+	}
+
+	send := func(pairs []*import_sstpb.Pair) error {
+		req := &import_sstpb.WriteRequest{Chunk: &import_sstpb.WriteRequest_Batch{
+			Batch: &import_sstpb.WriteBatch{CommitTs: commitTS, Pairs: pairs},
+		}}
+		for _, stream := range streams {
+			if err := stream.Send(req); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return nil
+	}
+	pairs := make([]*import_sstpb.Pair, 0, writeBatchSize)
+	for it.First(); it.Valid(); it.Next() {
+		pairs = append(pairs, &import_sstpb.Pair{
+			Key:   append([]byte{}, it.Key()...),
+			Value: append([]byte{}, it.Value()...),
+		})
+		if len(pairs) >= writeBatchSize {
+			if err = send(pairs); err != nil {
+				return nil, err
+			}
+			pairs = pairs[:0]
+		}
+	}
+	if err = it.Error(); err != nil {
+		return nil, err
+	}
+	if len(pairs) > 0 {
+		if err = send(pairs); err != nil {
+			return nil, err
+		}
+	}
+
+	var leaderMetas []*import_sstpb.SSTMeta
+	for j, stream := range streams {
+		resp, err := stream.CloseAndRecv()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if resp.GetError() != nil {
+			return nil, errors.New(resp.GetError().GetMessage())
+		}
+		if region.Meta.GetPeers()[j].GetId() == region.Leader.GetId() {
+			leaderMetas = resp.GetMetas()
+		}
+	}
+	if len(leaderMetas) == 0 {
+		return nil, errors.Annotatef(errRetryable, "no SST is written to the leader of region %d", region.Meta.GetId())
+	}
+
+	cli, err := i.getClient(ctx, region.Leader.GetStoreId())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cli.MultiIngest(ctx, &import_sstpb.MultiIngestRequest{
+		Context: &kvrpcpb.Context{
+			RegionId:    region.Meta.GetId(),
+			RegionEpoch: region.Meta.GetRegionEpoch(),
+			Peer:        region.Leader,
+		},
+		Ssts: leaderMetas,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if regionErr := resp.GetError(); regionErr != nil {
+		if regionErr.GetNotLeader() != nil || regionErr.GetEpochNotMatch() != nil ||
+			regionErr.GetServerIsBusy() != nil || regionErr.GetRegionNotFound() != nil {
+			return nil, errors.Annotatef(errRetryable, "ingest region %d: %s", region.Meta.GetId(), regionErr.String())
+		}
+		return nil, errors.Errorf("ingest region %d: %s", region.Meta.GetId(), regionErr.String())
+	}
+	return regionEnd, nil
+}
+
+func (i *tikvImporter) getClient(ctx context.Context, storeID uint64) (import_sstpb.ImportSSTClient, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	conn, ok := i.conns[storeID]
+	if !ok {
+		store, err := i.pdCli.GetStore(ctx, storeID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		conn, err = grpc.DialContext(ctx, store.GetAddress(), i.grpcOpts...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		i.conns[storeID] = conn
+	}
+	return import_sstpb.NewImportSSTClient(conn), nil
+}
+
+// Close implements the Importer interface.
+func (i *tikvImporter) Close() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for id, conn := range i.conns {
+		terror.Log(conn.Close())
+		delete(i.conns, id)
+	}
+}
+
+// decodeRegionRange decodes the raw key range of the region.
+func decodeRegionRange(region *metapb.Region) (start, end []byte, err error) {
+	if len(region.GetStartKey()) > 0 {
+		if _, start, err = codec.DecodeBytes(region.GetStartKey(), nil); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	if len(region.GetEndKey()) > 0 {
+		if _, end, err = codec.DecodeBytes(region.GetEndKey(), nil); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	return start, end, nil
+}
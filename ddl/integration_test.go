@@ -15,13 +15,26 @@
 package ddl_test
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/util/admin"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+	"github.com/tikv/client-go/v2/tikv"
 )
 
 func TestDefaultValueIsBinaryString(t *testing.T) {
@@ -131,3 +144,508 @@ func TestTestSerialStatSuite(t *testing.T) {
 
 	ddl.ExportTestSerialStatSuite(t)
 }
+
+func TestAddIndexByIngest(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = on")
+	defer tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = default")
+
+	tk.MustExec("create table t (a int, b varchar(10), c int, primary key (a) clustered)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, '%d', %d)", i, i%10, i))
+	}
+	tk.MustExec("alter table t add index idx_b (b)")
+	tk.MustExec("alter table t add unique index idx_c (c)")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t use index (idx_b) where b = '3'").Check(testkit.Rows("10"))
+	tk.MustQuery("select a from t use index (idx_c) where c = 42").Check(testkit.Rows("42"))
+
+	// The duplicate keys in the ingest engine.
+	tk.MustGetErrMsg("alter table t add unique index idx_b2 (b)", "[kv:1062]Duplicate entry '0' for key 'idx_b2'")
+	tk.MustExec("admin check table t")
+
+	tk.MustExec("create table tp (a int, b int) partition by hash (a) partitions 4")
+	tk.MustExec("insert into tp values (1, 1), (2, 2), (3, 3), (4, 4), (5, NULL), (6, NULL)")
+	tk.MustExec("alter table tp add unique index idx_ba (b, a)")
+	tk.MustExec("admin check table tp")
+	tk.MustQuery("select a from tp use index (idx_ba) where b is null order by a").Check(testkit.Rows("5", "6"))
+}
+
+func TestAddUniqueIndexByIngestConflictWithDML(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = on")
+	defer tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = default")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+
+	// The row inserted during the reorganization conflicts with the backfilled index data.
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	inserted := false
+	callback := &ddl.TestDDLCallback{}
+	callback.OnJobUpdatedExported = func(job *model.Job) {
+		if !inserted && job.SchemaState == model.StateWriteReorganization {
+			inserted = true
+			tk1.MustExec("insert into t values (3, 1)")
+		}
+	}
+	d := dom.DDL()
+	originalCallback := d.GetHook()
+	defer d.(ddl.DDLForTest).SetHook(originalCallback)
+	d.(ddl.DDLForTest).SetHook(callback)
+
+	tk.MustGetErrMsg("alter table t add unique index idx_b (b)", "[kv:1062]Duplicate entry '1' for key 'idx_b'")
+	require.True(t, inserted)
+	tk.MustExec("admin check table t")
+}
+
+func TestAddIndexByIngestWithConcurrentDML(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = on")
+	defer tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = default")
+	tk.MustExec("create table t (a int primary key, b int, c int)")
+	for i := 0; i < 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, %d)", i, i, i))
+	}
+
+	pdCli := store.(tikv.Storage).GetRegionCache().PDClient()
+	minServiceSafePoint := func() uint64 {
+		// Removing a service safe point which doesn't exist only returns the min one.
+		minSafePoint, err := pdCli.UpdateServiceGCSafePoint(context.Background(), "test", 0, 0)
+		require.NoError(t, err)
+		return minSafePoint
+	}
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	for i, sql := range []string{"alter table t add index idx_b (b)", "alter table t add unique index idx_c (c)"} {
+		ver, err := store.(tikv.Storage).CurrentTimestamp(oracle.GlobalTxnScope)
+		require.NoError(t, err)
+		require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/ddl/beforeIngestImport", "pause"))
+		done := make(chan struct{})
+		go func() {
+			tk1.MustExec(sql)
+			close(done)
+		}()
+
+		// The service safe point is set at the snapshot of the scan, the DMLs change the rows after the
+		// snapshot, and the import is paused until they're done.
+		var safePoint uint64
+		require.Eventually(t, func() bool {
+			safePoint = minServiceSafePoint()
+			return safePoint < math.MaxUint64
+		}, 10*time.Second, 10*time.Millisecond)
+		require.Greater(t, safePoint, ver)
+		tk.MustExec(fmt.Sprintf("update t set b = b + 100, c = c + 100 where a = %d", i))
+		tk.MustExec(fmt.Sprintf("delete from t where a = %d", 10+i))
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, %d)", 20+i, 20+i, 20+i))
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/ddl/beforeIngestImport"))
+		<-done
+
+		require.Equal(t, uint64(math.MaxUint64), minServiceSafePoint())
+		tk.MustExec("admin check table t")
+	}
+	tk.MustQuery("select a from t use index (idx_b) where b >= 100 order by a").Check(testkit.Rows("0", "1"))
+	tk.MustQuery("select a from t use index (idx_c) where c in (10, 11, 21)").Check(testkit.Rows("21"))
+	tk.MustQuery("select count(*) from t use index (idx_b)").Check(testkit.Rows("20"))
+}
+
+func TestAddIndexByIngestCheckpoint(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = on")
+	tk.MustExec("set @@global.tidb_ddl_reorg_worker_cnt = 1")
+	defer func() {
+		tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = default")
+		tk.MustExec("set @@global.tidb_ddl_reorg_worker_cnt = default")
+	}()
+	tk.MustExec("create table t (a int primary key, b int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i))
+	}
+	// Each region is backfilled in a batch of tasks by the only worker.
+	tk.MustQuery("split table t by (25), (50), (75)").Check(testkit.Rows("3 1"))
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	countIndexKeys := func() int {
+		txn, err := store.Begin()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, txn.Rollback())
+		}()
+		prefix := tablecodec.EncodeTableIndexPrefix(tbl.Meta().ID, 1)
+		it, err := txn.Iter(prefix, prefix.PrefixNext())
+		require.NoError(t, err)
+		defer it.Close()
+		cnt := 0
+		for ; it.Valid(); cnt++ {
+			require.NoError(t, it.Next())
+		}
+		return cnt
+	}
+
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/ddl/mockIngestCheckpointPairs", "return(60)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/ddl/mockIngestCheckpointPairs"))
+	}()
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/ddl/beforeIngestImport", "pause"))
+	done := make(chan struct{})
+	go func() {
+		tk1 := testkit.NewTestKit(t, store)
+		tk1.MustExec("alter table test.t add index idx_b (b)")
+		close(done)
+	}()
+	// The index data of the handles [0, 75] backfilled by the first 3 tasks is imported at the checkpoint, and the
+	// rest is imported at last.
+	cnt := countIndexKeys()
+	for i := 0; i < 1000 && cnt != 76; i++ {
+		time.Sleep(10 * time.Millisecond)
+		cnt = countIndexKeys()
+	}
+	require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/ddl/beforeIngestImport"))
+	require.Equal(t, 76, cnt)
+	<-done
+	require.Equal(t, 100, countIndexKeys())
+	tk.MustExec("admin check table t")
+}
+
+func TestDDLPaused(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	setDDLPaused := func(expire time.Time) {
+		err := kv.RunInNewTxn(context.Background(), store, true, func(ctx context.Context, txn kv.Transaction) error {
+			return meta.NewMeta(txn).SetDDLPaused(expire)
+		})
+		require.NoError(t, err)
+	}
+	setDDLPaused(time.Now().Add(time.Hour))
+	done := make(chan struct{})
+	go func() {
+		tk1 := testkit.NewTestKit(t, store)
+		tk1.MustExec("create table test.t (a int)")
+		close(done)
+	}()
+	select {
+	case <-done:
+		require.FailNow(t, "the DDL job should not run while DDL is paused")
+	case <-time.After(500 * time.Millisecond):
+	}
+	tk.MustQuery("show tables like 't'").Check(testkit.Rows())
+
+	setDDLPaused(time.Time{})
+	<-done
+	tk.MustQuery("show tables like 't'").Check(testkit.Rows("t"))
+
+	// DDL is resumed by itself once the pause expires, e.g. the one pausing DDL crashes.
+	setDDLPaused(time.Now().Add(time.Second))
+	tk.MustExec("create table test.t1 (a int)")
+	tk.MustQuery("show tables like 't1'").Check(testkit.Rows("t1"))
+}
+
+func TestPausedDDLJobNotBlockingIndependentJobs(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+
+	runInTxn := func(f func(txn kv.Transaction) error) {
+		err := kv.RunInNewTxn(context.Background(), store, true, func(ctx context.Context, txn kv.Transaction) error {
+			return f(txn)
+		})
+		require.NoError(t, err)
+	}
+	var jobs []*model.Job
+	runDDL := func(sql string) chan struct{} {
+		done := make(chan struct{})
+		go func() {
+			tk1 := testkit.NewTestKit(t, store)
+			tk1.MustExec(sql)
+			close(done)
+		}()
+		n := len(jobs) + 1
+		require.Eventually(t, func() bool {
+			runInTxn(func(txn kv.Transaction) (err error) {
+				jobs, err = admin.GetDDLJobs(txn)
+				return err
+			})
+			return len(jobs) == n
+		}, 5*time.Second, 10*time.Millisecond)
+		return done
+	}
+	isDone := func(done chan struct{}, wait time.Duration) bool {
+		select {
+		case <-done:
+			return true
+		case <-time.After(wait):
+			return false
+		}
+	}
+
+	// Queue the jobs while DDL is paused, then pause the first job.
+	runInTxn(func(txn kv.Transaction) error {
+		return meta.NewMeta(txn).SetDDLPaused(time.Now().Add(time.Hour))
+	})
+	done1 := runDDL("alter table test.t1 add column b int")
+	done2 := runDDL("alter table test.t1 add column c int")
+	done3 := runDDL("alter table test.t2 add column b int")
+	runInTxn(func(txn kv.Transaction) error {
+		errs, err := admin.PauseJobs(txn, []int64{jobs[0].ID})
+		require.NoError(t, errs[0])
+		return err
+	})
+	runInTxn(func(txn kv.Transaction) error {
+		return meta.NewMeta(txn).SetDDLPaused(time.Time{})
+	})
+
+	// The job on the other table runs, the job on the same table waits for the paused job.
+	require.True(t, isDone(done3, 10*time.Second))
+	require.False(t, isDone(done1, 500*time.Millisecond))
+	require.False(t, isDone(done2, 0))
+	tk.MustQuery("select count(*) from information_schema.columns where table_schema = 'test' and table_name = 't2'").Check(testkit.Rows("2"))
+	tk.MustQuery("select count(*) from information_schema.columns where table_schema = 'test' and table_name = 't1'").Check(testkit.Rows("1"))
+
+	// The job queued after the queue is blocked by the paused job runs too.
+	done4 := make(chan struct{})
+	go func() {
+		tk1 := testkit.NewTestKit(t, store)
+		tk1.MustExec("alter table test.t2 add column c int")
+		close(done4)
+	}()
+	require.True(t, isDone(done4, 10*time.Second))
+	require.False(t, isDone(done1, 0))
+
+	runInTxn(func(txn kv.Transaction) error {
+		errs, err := admin.ResumeJobs(txn, []int64{jobs[0].ID})
+		require.NoError(t, errs[0])
+		return err
+	})
+	<-done1
+	<-done2
+	tk.MustQuery("select count(*) from information_schema.columns where table_schema = 'test' and table_name = 't1'").Check(testkit.Rows("3"))
+}
+
+func TestDDLJobPriority(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("create table t3 (a int)")
+
+	runInTxn := func(f func(txn kv.Transaction) error) {
+		err := kv.RunInNewTxn(context.Background(), store, true, func(ctx context.Context, txn kv.Transaction) error {
+			return f(txn)
+		})
+		require.NoError(t, err)
+	}
+	runInTxn(func(txn kv.Transaction) error {
+		return meta.NewMeta(txn).SetDDLPaused(time.Now().Add(time.Hour))
+	})
+	sqls := []string{
+		"alter table test.t1 add column b int",
+		"alter table test.t2 add column b int",
+		"alter table test.t1 add column c int",
+		"create table test.t4 (a int)",
+		"alter table test.t3 add column b int",
+	}
+	var wg sync.WaitGroup
+	var jobs []*model.Job
+	for i, sql := range sqls {
+		wg.Add(1)
+		go func(sql string) {
+			defer wg.Done()
+			tk1 := testkit.NewTestKit(t, store)
+			tk1.MustExec(sql)
+		}(sql)
+		require.Eventually(t, func() bool {
+			runInTxn(func(txn kv.Transaction) (err error) {
+				jobs, err = admin.GetDDLJobs(txn)
+				return err
+			})
+			return len(jobs) == i+1
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	// The job on t1 can't run before the earlier job on the same table, and the job on t3 can't run before
+	// the CREATE TABLE job.
+	runInTxn(func(txn kv.Transaction) error {
+		errs, err := admin.UpdateJobsPriority(txn, []int64{jobs[1].ID, jobs[2].ID, jobs[4].ID}, kv.PriorityHigh)
+		for _, e := range errs {
+			require.NoError(t, e)
+		}
+		return err
+	})
+	runInTxn(func(txn kv.Transaction) error {
+		return meta.NewMeta(txn).SetDDLPaused(time.Time{})
+	})
+	wg.Wait()
+
+	finishedTS := make([]uint64, len(jobs))
+	runInTxn(func(txn kv.Transaction) error {
+		for i, job := range jobs {
+			historyJob, err := meta.NewMeta(txn).GetHistoryDDLJob(job.ID)
+			if err != nil {
+				return err
+			}
+			finishedTS[i] = historyJob.BinlogInfo.FinishedTS
+		}
+		return nil
+	})
+	for _, order := range [][2]int{{1, 0}, {0, 2}, {2, 3}, {3, 4}} {
+		require.Less(t, finishedTS[order[0]], finishedTS[order[1]], sqls[order[0]])
+	}
+}
+
+func TestRequirePrimaryKey(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t_no_pk (a int)")
+	tk.MustExec("create table t_pk (a int primary key nonclustered, b int)")
+	tk.MustQuery("select @@session.sql_require_primary_key").Check(testkit.Rows("0"))
+	tk.MustExec("set @@session.sql_require_primary_key = 1")
+
+	requireErr := func(sql string) {
+		err := tk.ExecToErr(sql)
+		require.True(t, ddl.ErrTableWithoutPrimaryKey.Equal(err), "%s: %v", sql, err)
+	}
+	requireErr("create table t (a int)")
+	requireErr("create table t (a int, unique key (a))")
+	requireErr("create table t like t_no_pk")
+	requireErr("create temporary table t (a int)")
+	requireErr("create global temporary table t (a int) on commit delete rows")
+	tk.MustExec("create table t1 (a int primary key)")
+	tk.MustExec("create table t2 (a int, b int, primary key (a, b) clustered)")
+	tk.MustExec("create table t3 (a int, primary key (a) nonclustered)")
+	tk.MustExec("create table t4 like t_pk")
+	tk.MustExec("create view v as select * from t_no_pk")
+
+	requireErr("alter table t_no_pk add column b int")
+	requireErr("alter table t_no_pk add index idx(a)")
+	requireErr("create index idx on t_no_pk (a)")
+	requireErr("alter table t_pk drop primary key")
+	requireErr("drop index `primary` on t_pk")
+	tk.MustExec("alter table t_pk add column c int")
+	tk.MustExec("alter table t_pk add index idx(b)")
+	tk.MustExec("alter table t_no_pk add primary key (a)")
+	tk.MustExec("alter table t_no_pk add column b int")
+
+	tk.MustExec("set @@session.sql_require_primary_key = 0")
+	tk.MustExec("alter table t_pk drop primary key")
+	tk.MustExec("create table t (a int)")
+}
+
+func TestDDLTimeout(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+
+	originalHook := dom.DDL().GetHook()
+	defer dom.DDL().SetHook(originalHook)
+	blocked := false
+	dom.DDL().SetHook(&ddl.TestDDLCallback{
+		Do: dom,
+		OnJobRunBeforeExported: func(job *model.Job) {
+			// Block the job once so it can't finish within the timeout.
+			if job.Type == model.ActionAddColumn && job.SchemaState == model.StateWriteOnly && !blocked {
+				blocked = true
+				time.Sleep(500 * time.Millisecond)
+			}
+		},
+	})
+
+	colsSQL := "select column_name from information_schema.columns where table_schema = 'test' and table_name = 't'"
+	tk.MustExec("set @@tidb_ddl_timeout = 100")
+	err := tk.ExecToErr("alter table t add column b int")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not finish within tidb_ddl_timeout")
+	require.True(t, blocked)
+	tk.MustQuery(colsSQL).Check(testkit.Rows("a"))
+
+	// The job finishes if the timeout is not exceeded.
+	tk.MustExec("set @@tidb_ddl_timeout = 0")
+	blocked = false
+	tk.MustExec("alter table t add column b int")
+	require.True(t, blocked)
+	tk.MustQuery(colsSQL).Check(testkit.Rows("a", "b"))
+}
+
+func TestMetadataLock(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("set global tidb_enable_metadata_lock = 1")
+	defer tk.MustExec("set global tidb_enable_metadata_lock = 0")
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	tk1.Session().GetSessionVars().ConnectionID = 100
+	tk1.MustExec("begin")
+	tk1.MustQuery("select * from t").Check(testkit.Rows())
+
+	done := make(chan error, 1)
+	go func() {
+		tk2 := testkit.NewTestKit(t, store)
+		tk2.MustExec("use test")
+		done <- tk2.ExecToErr("alter table t add column b int")
+	}()
+
+	// The DDL waits for the transaction which uses the table.
+	mdlSQL := "select table_name, query, session_id from information_schema.mdl_view"
+	require.Eventually(t, func() bool {
+		return len(tk.MustQuery(mdlSQL).Rows()) > 0
+	}, 5*time.Second, 50*time.Millisecond)
+	tk.MustQuery(mdlSQL).Check(testkit.Rows("t alter table t add column b int 100"))
+	select {
+	case err := <-done:
+		require.FailNow(t, "the DDL isn't blocked", "err: %v", err)
+	default:
+	}
+
+	// The transaction commits with the schema it started with.
+	tk1.MustExec("insert into t values (1)")
+	tk1.MustExec("commit")
+	require.NoError(t, <-done)
+	tk.MustQuery(mdlSQL).Check(testkit.Rows())
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 <nil>"))
+
+	// The locks blocking the DDL longer than tidb_metadata_lock_wait_timeout are revoked.
+	tk.MustExec("set global tidb_metadata_lock_wait_timeout = 1")
+	defer tk.MustExec(fmt.Sprintf("set global tidb_metadata_lock_wait_timeout = %d", variable.DefTiDBMDLWaitTimeout))
+	tk1.MustExec("begin")
+	tk1.MustExec("insert into t values (2, 2)")
+	start := time.Now()
+	tk.MustExec("alter table t add column c int")
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+	tk.MustQuery(mdlSQL).Check(testkit.Rows())
+	tk1.MustGetErrCode("commit", errno.ErrInfoSchemaChanged)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 <nil> <nil>"))
+
+	// The transactions out of the metadata lock don't block the DDL.
+	tk.MustExec("set global tidb_enable_metadata_lock = 0")
+	tk1.MustExec("begin")
+	tk1.MustQuery("select a from t").Check(testkit.Rows("1"))
+	tk.MustExec("alter table t add column d int")
+	tk1.MustExec("rollback")
+}
@@ -15,10 +15,13 @@
 package ddl
 
 import (
+	"context"
 	"time"
 
+	"github.com/ngaut/pools"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
 	"go.etcd.io/etcd/clientv3"
 )
 
@@ -32,8 +35,15 @@ type Options struct {
 	InfoCache *infoschema.InfoCache
 	Hook      Callback
 	Lease     time.Duration
+	MDLWaiter MDLWaiter
+	// SessionFactory creates the sessions of the workers inserting the rows of CREATE TABLE ... SELECT.
+	SessionFactory pools.Factory
 }
 
+// MDLWaiter waits until the transactions holding the metadata lock of the table changed by the job
+// at a schema version older than schemaVer finish.
+type MDLWaiter func(ctx context.Context, job *model.Job, schemaVer int64) error
+
 // WithEtcdClient specifies the `clientv3.Client` of DDL used to request the etcd service
 func WithEtcdClient(client *clientv3.Client) Option {
 	return func(options *Options) {
@@ -68,3 +78,17 @@ func WithLease(lease time.Duration) Option {
 		options.Lease = lease
 	}
 }
+
+// WithMDLWaiter specifies the `MDLWaiter` of DDL used to wait for the metadata locks when metadata lock is enabled
+func WithMDLWaiter(waiter MDLWaiter) Option {
+	return func(options *Options) {
+		options.MDLWaiter = waiter
+	}
+}
+
+// WithSessionFactory specifies the factory of the sessions used by the workers inserting the rows of CREATE TABLE ... SELECT
+func WithSessionFactory(factory pools.Factory) Option {
+	return func(options *Options) {
+		options.SessionFactory = factory
+	}
+}
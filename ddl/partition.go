@@ -1108,47 +1108,11 @@ func (w *worker) onDropTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (
 		job.SchemaState = model.StateDeleteReorganization
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != job.SchemaState)
 	case model.StateDeleteReorganization:
-		oldTblInfo := getTableInfoWithDroppingPartitions(tblInfo)
 		physicalTableIDs = getPartitionIDsFromDefinitions(tblInfo.Partition.DroppingDefinitions)
-		tbl, err := getTable(d.store, job.SchemaID, oldTblInfo)
-		if err != nil {
+		done, err := w.cleanupDroppingPartitionsGlobalIndexes(d, t, job, tblInfo, physicalTableIDs)
+		if err != nil || !done {
 			return ver, errors.Trace(err)
 		}
-		// If table has global indexes, we need reorg to clean up them.
-		if pt, ok := tbl.(table.PartitionedTable); ok && hasGlobalIndex(tblInfo) {
-			// Build elements for compatible with modify column type. elements will not be used when reorganizing.
-			elements := make([]*meta.Element, 0, len(tblInfo.Indices))
-			for _, idxInfo := range tblInfo.Indices {
-				if idxInfo.Global {
-					elements = append(elements, &meta.Element{ID: idxInfo.ID, TypeKey: meta.IndexElementKey})
-				}
-			}
-			reorgInfo, err := getReorgInfoFromPartitions(d, t, job, tbl, physicalTableIDs, elements)
-
-			if err != nil || reorgInfo.first {
-				// If we run reorg firstly, we should update the job snapshot version
-				// and then run the reorg next time.
-				return ver, errors.Trace(err)
-			}
-			err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (dropIndexErr error) {
-				defer tidbutil.Recover(metrics.LabelDDL, "onDropTablePartition",
-					func() {
-						dropIndexErr = errCancelledDDLJob.GenWithStack("drop partition panic")
-					}, false)
-				return w.cleanupGlobalIndexes(pt, physicalTableIDs, reorgInfo)
-			})
-			if err != nil {
-				if errWaitReorgTimeout.Equal(err) {
-					// if timeout, we should return, check for the owner and re-wait job done.
-					return ver, nil
-				}
-				// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-				w.reorgCtx.cleanNotifyReorgCancel()
-				return ver, errors.Trace(err)
-			}
-			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-			w.reorgCtx.cleanNotifyReorgCancel()
-		}
 		tblInfo.Partition.DroppingDefinitions = nil
 		// used by ApplyDiff in updateSchemaVersion
 		job.CtxVars = []interface{}{physicalTableIDs}
@@ -1166,8 +1130,57 @@ func (w *worker) onDropTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (
 	return ver, errors.Trace(err)
 }
 
+// cleanupDroppingPartitionsGlobalIndexes cleans up the global index entries of the partitions in DroppingDefinitions
+// by reorganization. It returns false if the reorganization isn't finished and the job should be run again.
+func (w *worker) cleanupDroppingPartitionsGlobalIndexes(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, physicalTableIDs []int64) (bool, error) {
+	oldTblInfo := getTableInfoWithDroppingPartitions(tblInfo)
+	tbl, err := getTable(d.store, job.SchemaID, oldTblInfo)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	// If table has global indexes, we need reorg to clean up them.
+	pt, ok := tbl.(table.PartitionedTable)
+	if !ok || !hasGlobalIndex(tblInfo) {
+		return true, nil
+	}
+	// Build elements for compatible with modify column type. elements will not be used when reorganizing.
+	elements := make([]*meta.Element, 0, len(tblInfo.Indices))
+	for _, idxInfo := range tblInfo.Indices {
+		if idxInfo.Global {
+			elements = append(elements, &meta.Element{ID: idxInfo.ID, TypeKey: meta.IndexElementKey})
+		}
+	}
+	reorgInfo, err := getReorgInfoFromPartitions(d, t, job, tbl, physicalTableIDs, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, errors.Trace(err)
+	}
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (dropIndexErr error) {
+		defer tidbutil.Recover(metrics.LabelDDL, "cleanupDroppingPartitionsGlobalIndexes",
+			func() {
+				dropIndexErr = errCancelledDDLJob.GenWithStack("%s panic", job.Type)
+			}, false)
+		return w.cleanupGlobalIndexes(pt, physicalTableIDs, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return false, nil
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, nil
+}
+
 // onTruncateTablePartition truncates old partition meta.
-func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, error) {
+// If the table has global indexes, the old partitions are kept in DroppingDefinitions after their IDs are replaced,
+// and the global index entries of them are cleaned up in the delete reorganization state like dropping partitions.
+func (w *worker) onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, error) {
 	var ver int64
 	var oldIDs []int64
 	if err := job.DecodeArgs(&oldIDs); err != nil {
@@ -1183,6 +1196,76 @@ func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, e
 		return ver, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
 	}
 
+	if !hasGlobalIndex(tblInfo) {
+		newPartitions, err := truncatePartitionDefinitions(t, job, tblInfo, oldIDs)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		return finishTruncateTablePartition(d, t, job, tblInfo, oldIDs, newPartitions)
+	}
+
+	if job.State == model.JobStateRunning && job.SchemaState == model.StateNone {
+		// Manually set first state.
+		job.SchemaState = model.StatePublic
+	}
+	switch job.SchemaState {
+	case model.StatePublic:
+		droppingDefs := make([]model.PartitionDefinition, 0, len(oldIDs))
+		for _, oldID := range oldIDs {
+			for _, def := range pi.Definitions {
+				if def.ID == oldID {
+					droppingDefs = append(droppingDefs, def)
+					break
+				}
+			}
+		}
+		var newPartitions []model.PartitionDefinition
+		newPartitions, err = truncatePartitionDefinitions(t, job, tblInfo, oldIDs)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		pi.DroppingDefinitions = droppingDefs
+		// used by ApplyDiff in updateSchemaVersion
+		job.CtxVars = []interface{}{getPartitionIDsFromDefinitions(droppingDefs), getPartitionIDsFromDefinitions(newPartitions)}
+		job.SchemaState = model.StateDeleteOnly
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	case model.StateDeleteOnly:
+		// Insert this state to confirm all servers can not see the old partitions when reorg is running,
+		// so that no new data will be inserted into old partitions when reorganizing.
+		job.SchemaState = model.StateDeleteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	case model.StateDeleteReorganization:
+		done, err := w.cleanupDroppingPartitionsGlobalIndexes(d, t, job, tblInfo, getPartitionIDsFromDefinitions(pi.DroppingDefinitions))
+		if err != nil || !done {
+			return ver, errors.Trace(err)
+		}
+		newPartitions := make([]model.PartitionDefinition, 0, len(oldIDs))
+		for _, oldID := range oldIDs {
+			for _, droppingDef := range pi.DroppingDefinitions {
+				if droppingDef.ID != oldID {
+					continue
+				}
+				for _, def := range pi.Definitions {
+					if def.Name.L == droppingDef.Name.L {
+						newPartitions = append(newPartitions, def)
+						break
+					}
+				}
+				break
+			}
+		}
+		pi.DroppingDefinitions = nil
+		return finishTruncateTablePartition(d, t, job, tblInfo, oldIDs, newPartitions)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
+
+// truncatePartitionDefinitions replaces the IDs of the truncated partitions with new IDs and updates the
+// TiFlash replica, placement rules and label rules of them, it returns the truncated partitions with new IDs.
+func truncatePartitionDefinitions(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, oldIDs []int64) ([]model.PartitionDefinition, error) {
+	pi := tblInfo.GetPartitionInfo()
 	newPartitions := make([]model.PartitionDefinition, 0, len(oldIDs))
 	for _, oldID := range oldIDs {
 		for i := 0; i < len(pi.Definitions); i++ {
@@ -1190,7 +1273,7 @@ func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, e
 			if def.ID == oldID {
 				pid, err1 := t.GenGlobalID()
 				if err1 != nil {
-					return ver, errors.Trace(err1)
+					return nil, errors.Trace(err1)
 				}
 				def.ID = pid
 				// Shallow copy only use the def.ID in event handle.
@@ -1201,7 +1284,7 @@ func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, e
 	}
 	if len(newPartitions) == 0 {
 		job.State = model.JobStateCancelled
-		return ver, table.ErrUnknownPartition.GenWithStackByArgs(fmt.Sprintf("pid:%v", oldIDs), tblInfo.Name.O)
+		return nil, table.ErrUnknownPartition.GenWithStackByArgs(fmt.Sprintf("pid:%v", oldIDs), tblInfo.Name.O)
 	}
 
 	// Clear the tiflash replica available status.
@@ -1213,7 +1296,7 @@ func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, e
 		if e != nil {
 			logutil.BgLogger().Error("ConfigureTiFlashPDForPartitions fails", zap.Error(e))
 			job.State = model.JobStateCancelled
-			return ver, e
+			return nil, e
 		}
 		tblInfo.TiFlashReplica.Available = false
 		// Set partition replica become unavailable.
@@ -1232,13 +1315,13 @@ func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, e
 	bundles, err := placement.NewPartitionListBundles(t, newPartitions)
 	if err != nil {
 		job.State = model.JobStateCancelled
-		return ver, errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	err = infosync.PutRuleBundlesWithDefaultRetry(context.TODO(), bundles)
 	if err != nil {
 		job.State = model.JobStateCancelled
-		return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+		return nil, errors.Wrapf(err, "failed to notify PD the placement rules")
 	}
 
 	tableID := fmt.Sprintf(label.TableIDFormat, label.IDPrefix, job.SchemaName, tblInfo.Name.L)
@@ -1251,7 +1334,7 @@ func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, e
 	rules, err := infosync.GetLabelRules(context.TODO(), append(oldPartRules, tableID))
 	if err != nil {
 		job.State = model.JobStateCancelled
-		return ver, errors.Wrapf(err, "failed to get label rules from PD")
+		return nil, errors.Wrapf(err, "failed to get label rules from PD")
 	}
 
 	newPartIDs := getPartitionIDs(tblInfo)
@@ -1270,15 +1353,19 @@ func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, e
 	err = infosync.UpdateLabelRules(context.TODO(), patch)
 	if err != nil {
 		job.State = model.JobStateCancelled
-		return ver, errors.Wrapf(err, "failed to notify PD the label rules")
+		return nil, errors.Wrapf(err, "failed to notify PD the label rules")
 	}
+	return newPartitions, nil
+}
 
+// finishTruncateTablePartition updates the table info and finishes the truncate partition job.
+func finishTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, oldIDs []int64, newPartitions []model.PartitionDefinition) (int64, error) {
 	newIDs := make([]int64, len(oldIDs))
 	for i := range oldIDs {
 		newIDs[i] = newPartitions[i].ID
 	}
 	job.CtxVars = []interface{}{oldIDs, newIDs}
-	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	ver, err := updateVersionAndTableInfo(t, job, tblInfo, true)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -1557,7 +1644,9 @@ func checkExchangePartitionRecordValidation(w *worker, pt *model.TableInfo, inde
 		if len(pi.Columns) == 0 {
 			sql, paramList = buildCheckSQLForRangeExprPartition(pi, index, schemaName, tableName)
 		} else if len(pi.Columns) == 1 {
-			sql, paramList = buildCheckSQLForRangeColumnsPartition(pi, index, schemaName, tableName)
+			colInfo := findColumnByName(pi.Columns[0].L, pt)
+			isTimestamp := colInfo != nil && colInfo.Tp == mysql.TypeTimestamp
+			sql, paramList = buildCheckSQLForRangeColumnsPartition(pi, index, schemaName, tableName, isTimestamp)
 		}
 	case model.PartitionTypeList:
 		if len(pi.Columns) == 0 {
@@ -1619,18 +1708,23 @@ func trimQuotation(str string) string {
 	return strings.Trim(str, "\"")
 }
 
-func buildCheckSQLForRangeColumnsPartition(pi *model.PartitionInfo, index int, schemaName, tableName model.CIStr) (string, []interface{}) {
+func buildCheckSQLForRangeColumnsPartition(pi *model.PartitionInfo, index int, schemaName, tableName model.CIStr, isTimestamp bool) (string, []interface{}) {
 	paramList := make([]interface{}, 0, 6)
 	colName := pi.Columns[0].L
+	// The range boundaries of a TIMESTAMP column are in UTC, so compare the column in UTC too.
+	col := "%n"
+	if isTimestamp {
+		col = "convert_tz(%n, @@time_zone, '+00:00')"
+	}
 	if index == 0 {
 		paramList = append(paramList, schemaName.L, tableName.L, colName, trimQuotation(pi.Definitions[index].LessThan[0]))
-		return "select 1 from %n.%n where %n >= %? limit 1", paramList
+		return "select 1 from %n.%n where " + col + " >= %? limit 1", paramList
 	} else if index == len(pi.Definitions)-1 && strings.EqualFold(pi.Definitions[index].LessThan[0], partitionMaxValue) {
 		paramList = append(paramList, schemaName.L, tableName.L, colName, trimQuotation(pi.Definitions[index-1].LessThan[0]))
-		return "select 1 from %n.%n where %n < %? limit 1", paramList
+		return "select 1 from %n.%n where " + col + " < %? limit 1", paramList
 	} else {
 		paramList = append(paramList, schemaName.L, tableName.L, colName, trimQuotation(pi.Definitions[index-1].LessThan[0]), colName, trimQuotation(pi.Definitions[index].LessThan[0]))
-		return "select 1 from %n.%n where %n < %? or %n >= %? limit 1", paramList
+		return "select 1 from %n.%n where " + col + " < %? or " + col + " >= %? limit 1", paramList
 	}
 }
 
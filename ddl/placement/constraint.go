@@ -154,3 +154,24 @@ func (c *Constraint) CompatibleWith(o *Constraint) ConstraintCompatibility {
 
 	return ConstraintCompatible
 }
+
+// MatchLabels checks whether the store with the labels satisfies the constraint.
+func (c *Constraint) MatchLabels(labels map[string]string) bool {
+	val, ok := labels[c.Key]
+	switch c.Op {
+	case In, NotIn:
+		found := false
+		for _, v := range c.Values {
+			if ok && v == val {
+				found = true
+				break
+			}
+		}
+		return found == (c.Op == In)
+	case Exists:
+		return ok
+	case NotExists:
+		return !ok
+	}
+	return false
+}
@@ -265,3 +265,31 @@ func (t *testConstraintSuite) TestCompatibleWith(c *C) {
 		c.Assert(t.i1.CompatibleWith(&t.i2), Equals, t.output, comment)
 	}
 }
+
+func (t *testConstraintSuite) TestMatchLabels(c *C) {
+	labels := map[string]string{"zone": "sh", "disk": "ssd"}
+	tests := []struct {
+		cnst  Constraint
+		match bool
+	}{
+		{NewConstraintDirect("zone", In, "sh"), true},
+		{NewConstraintDirect("zone", In, "bj", "sh"), true},
+		{NewConstraintDirect("zone", In, "bj"), false},
+		{NewConstraintDirect("host", In, "h1"), false},
+		{NewConstraintDirect("zone", NotIn, "sh"), false},
+		{NewConstraintDirect("zone", NotIn, "bj"), true},
+		{NewConstraintDirect("host", NotIn, "h1"), true},
+		{NewConstraintDirect("disk", Exists), true},
+		{NewConstraintDirect("host", Exists), false},
+		{NewConstraintDirect("disk", NotExists), false},
+		{NewConstraintDirect("host", NotExists), true},
+	}
+	for i, test := range tests {
+		c.Assert(test.cnst.MatchLabels(labels), Equals, test.match, Commentf("case %d", i))
+	}
+
+	cnsts := NewConstraintsDirect(NewConstraintDirect("zone", In, "sh"), NewConstraintDirect("engine", NotIn, "tiflash"))
+	c.Assert(cnsts.MatchLabels(labels), IsTrue)
+	c.Assert(cnsts.MatchLabels(map[string]string{"zone": "sh", "engine": "tiflash"}), IsFalse)
+	c.Assert(Constraints(nil).MatchLabels(labels), IsTrue)
+}
@@ -110,3 +110,13 @@ func (constraints *Constraints) Add(label Constraint) error {
 	}
 	return nil
 }
+
+// MatchLabels checks whether the store with the labels satisfies all the constraints.
+func (constraints Constraints) MatchLabels(labels map[string]string) bool {
+	for i := range constraints {
+		if !constraints[i].MatchLabels(labels) {
+			return false
+		}
+	}
+	return true
+}
@@ -383,6 +383,9 @@ type reorgInfo struct {
 	PhysicalTableID int64
 	elements        []*meta.Element
 	currElement     *meta.Element
+	// ingestCtx is used to store the index data of the current physical table
+	// when the index is added by ingest, it isn't persisted.
+	ingestCtx *ingestContext
 }
 
 func (r *reorgInfo) String() string {
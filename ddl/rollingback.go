@@ -150,6 +150,43 @@ func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 	return ver, errCancelledDDLJob
 }
 
+func rollingbackAlterTableClusteredIndex(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	// If the value of SnapshotVer isn't zero, it means the reorg workers have been started.
+	if job.SchemaState == model.StateWriteReorganization && job.SnapshotVer != 0 {
+		// Backfill workers are started. we have to ask them to exit.
+		logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+		w.reorgCtx.notifyReorgCancel()
+		// Give the this kind of ddl one more round to run, the errCancelledDDLJob should be fetched from the bottom up.
+		return w.onAlterTableClusteredIndex(d, t, job)
+	}
+	if job.SchemaState == model.StateNone {
+		// The job hasn't been handled and we cancel it directly.
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	}
+	// The job has been in its middle state (but the reorg worker hasn't started) and we roll it back here.
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
+
+func rollingbackCreateTableAsSelect(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	// If the value of SnapshotVer isn't zero, it means the rows are being inserted.
+	if job.SchemaState == model.StateWriteReorganization && job.SnapshotVer != 0 {
+		logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+		w.reorgCtx.notifyReorgCancel()
+		// Give the this kind of ddl one more round to run, the errCancelledDDLJob should be fetched from the bottom up.
+		return w.onCreateTableAsSelect(d, t, job)
+	}
+	if job.SchemaState == model.StateNone {
+		// The job hasn't been handled and we cancel it directly.
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	}
+	// The table has been created (but the rows aren't being inserted) and we drop it here.
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
+
 func rollingbackAddColumn(t *meta.Meta, job *model.Job) (ver int64, err error) {
 	tblInfo, columnInfo, col, _, _, err := checkAddColumn(t, job)
 	if err != nil {
@@ -471,6 +508,10 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		ver, err = rollingbackTruncateTable(t, job)
 	case model.ActionModifyColumn:
 		ver, err = rollingbackModifyColumn(w, d, t, job)
+	case model.ActionAlterTableClusteredIndex:
+		ver, err = rollingbackAlterTableClusteredIndex(w, d, t, job)
+	case model.ActionCreateTableAsSelect:
+		ver, err = rollingbackCreateTableAsSelect(w, d, t, job)
 	case model.ActionRebaseAutoID, model.ActionShardRowID, model.ActionAddForeignKey,
 		model.ActionDropForeignKey, model.ActionRenameTable, model.ActionRenameTables,
 		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
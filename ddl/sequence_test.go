@@ -1108,3 +1108,37 @@ func TestDdl_AlterSequenceIssue31265(t *testing.T) {
 	tk.MustExec("alter sequence nocache_to_cache_seq cache 10;")
 	tk.MustQuery("show create sequence nocache_to_cache_seq;").Check(testkit.Rows("nocache_to_cache_seq CREATE SEQUENCE `nocache_to_cache_seq` start with 1 minvalue 1 maxvalue 9223372036854775806 increment by 1 cache 10 nocycle ENGINE=InnoDB"))
 }
+
+func TestSequenceCacheLimit(t *testing.T) {
+	store, clean := testkit2.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit2.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create sequence seq maxvalue 5 cache 10 cycle")
+	checkUsage := func(allocated, round string) {
+		tk.MustQuery("select allocated_value, cycle_round from information_schema.sequences where sequence_schema = 'test' and sequence_name = 'seq'").
+			Check(testkit.Rows(allocated + " " + round))
+	}
+	checkUsage("0", "0")
+
+	tk.MustExec("set @@global.tidb_sequence_cache_limit = 2")
+	defer tk.MustExec("set @@global.tidb_sequence_cache_limit = default")
+	tk.MustQuery("select @@global.tidb_sequence_cache_limit").Check(testkit.Rows("2"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("1"))
+	checkUsage("2", "0")
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("2"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("3"))
+	checkUsage("4", "0")
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("4"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("5"))
+	checkUsage("5", "0")
+	// The sequence cycles rather than runs out.
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("1"))
+	checkUsage("2", "1")
+
+	tk.MustExec("set @@global.tidb_sequence_cache_limit = 0")
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("2"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("3"))
+	checkUsage("5", "1")
+}
@@ -1088,7 +1088,7 @@ func (w *worker) onSetTableFlashReplica(t *meta.Meta, job *model.Job) (ver int64
 	// We should check this first, in order to avoid creating redundant DDL jobs.
 	if pi := tblInfo.GetPartitionInfo(); pi != nil {
 		logutil.BgLogger().Info("Set TiFlash replica pd rule for partitioned table", zap.Int64("tableID", tblInfo.ID))
-		if e := infosync.ConfigureTiFlashPDForPartitions(false, &pi.Definitions, replicaInfo.Count, &replicaInfo.Labels); e != nil {
+		if e := infosync.ConfigureTiFlashPDForPartitions(replicaInfo.HighPriority, &pi.Definitions, replicaInfo.Count, &replicaInfo.Labels); e != nil {
 			job.State = model.JobStateCancelled
 			return ver, errors.Trace(err)
 		}
@@ -1103,12 +1103,22 @@ func (w *worker) onSetTableFlashReplica(t *meta.Meta, job *model.Job) (ver int64
 			job.State = model.JobStateCancelled
 			return ver, errors.Trace(err)
 		}
+		if replicaInfo.Count > 0 && replicaInfo.HighPriority {
+			if e := infosync.PostTiFlashAccelerateSchedule(context.Background(), tblInfo.ID); e != nil {
+				job.State = model.JobStateCancelled
+				return ver, errors.Trace(e)
+			}
+		}
 	}
 
-	if replicaInfo.Count > 0 {
+	if replicaInfo.Count > 0 && isSameTiFlashReplica(tblInfo.TiFlashReplica, &replicaInfo) {
+		// Only the priority is changed, keep the replica available status.
+		tblInfo.TiFlashReplica.HighPriority = replicaInfo.HighPriority
+	} else if replicaInfo.Count > 0 {
 		tblInfo.TiFlashReplica = &model.TiFlashReplicaInfo{
 			Count:          replicaInfo.Count,
 			LocationLabels: replicaInfo.Labels,
+			HighPriority:   replicaInfo.HighPriority,
 		}
 	} else {
 		tblInfo.TiFlashReplica = nil
@@ -1122,6 +1132,19 @@ func (w *worker) onSetTableFlashReplica(t *meta.Meta, job *model.Job) (ver int64
 	return ver, nil
 }
 
+// isSameTiFlashReplica checks whether the replica spec has the same count and location labels as the replica info.
+func isSameTiFlashReplica(replica *model.TiFlashReplicaInfo, spec *ast.TiFlashReplicaSpec) bool {
+	if replica == nil || replica.Count != spec.Count || len(replica.LocationLabels) != len(spec.Labels) {
+		return false
+	}
+	for i, label := range replica.LocationLabels {
+		if spec.Labels[i] != label {
+			return false
+		}
+	}
+	return true
+}
+
 func (w *worker) checkTiFlashReplicaCount(replicaCount uint64) error {
 	ctx, err := w.sessPool.get()
 	if err != nil {
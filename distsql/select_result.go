@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/store/copr"
 	"github.com/pingcap/tidb/telemetry"
@@ -247,6 +248,15 @@ func (r *selectResult) fetchResp(ctx context.Context) error {
 				r.updateCopRuntimeStats(ctx, copStats, resultSubset.RespTime())
 				copStats.CopTime = duration
 				sc.MergeExecDetails(&copStats.ExecDetails, nil)
+				if threshold := sessVars.SlowRPCThreshold; threshold > 0 && resultSubset.RespTime() >= threshold {
+					sc.RecordSlowRPC(stmtctx.SlowRPCDetail{
+						RegionID:    copStats.RegionID,
+						StoreAddr:   copStats.CalleeAddress,
+						RespTime:    resultSubset.RespTime(),
+						ProcessTime: copStats.TimeDetail.ProcessTime,
+						WaitTime:    copStats.TimeDetail.WaitTime,
+					})
+				}
 			}
 		}
 		if len(r.selectResp.Chunks) != 0 {
@@ -56,6 +56,7 @@ import (
 	"github.com/pingcap/tidb/util/expensivequery"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/tikv/client-go/v2/txnkv/transaction"
 	"go.etcd.io/etcd/clientv3"
 	"go.etcd.io/etcd/clientv3/concurrency"
@@ -98,6 +99,7 @@ type Domain struct {
 	renewLeaseCh         chan func()      // It is used to call the renewLease function of the cache table.
 	onClose              func()
 	sysExecutorFactory   func(*Domain) (pools.Resource, error)
+	mdl                  *mdlManager
 }
 
 // loadInfoSchema loads infoschema at startTS.
@@ -419,12 +421,11 @@ func (do *Domain) Reload() error {
 		// loaded newer schema
 		if oldSchemaVersion < is.SchemaMetaVersion() {
 			// Update self schema version to etcd.
-			err = do.ddl.SchemaSyncer().UpdateSelfVersion(context.Background(), is.SchemaMetaVersion())
-			if err != nil {
-				logutil.BgLogger().Info("update self version failed",
-					zap.Int64("oldSchemaVersion", oldSchemaVersion),
-					zap.Int64("neededSchemaVersion", is.SchemaMetaVersion()), zap.Error(err))
+			var changedTableIDs []int64
+			if changes != nil {
+				changedTableIDs = changes.PhyTblIDS
 			}
+			do.reportSelfVersion(is.SchemaMetaVersion(), changedTableIDs)
 		}
 
 		// it is full load
@@ -469,7 +470,14 @@ func (do *Domain) ShowSlowQuery(showSlow *ast.ShowSlow) []*SlowQueryInfo {
 	msg.Add(1)
 	do.slowQuery.msgCh <- msg
 	msg.Wait()
-	return msg.result
+
+	threshold := time.Duration(atomic.LoadUint64(&config.GetGlobalConfig().Log.SlowThreshold)) * time.Millisecond
+	slowStmts, err := stmtsummary.StmtSummaryByDigestMap.PersistedSlowStmts(threshold)
+	if err != nil {
+		logutil.BgLogger().Warn("read the persisted slow statements failed", zap.Error(err))
+		return msg.result
+	}
+	return mergePersistedSlowQueries(showSlow, msg.result, slowStmts, do.slowQuery.period, time.Now())
 }
 
 func (do *Domain) topNSlowQueryLoop() {
@@ -733,6 +741,7 @@ func NewDomain(store kv.Storage, ddlLease time.Duration, statsLease time.Duratio
 		onClose:             onClose,
 		renewLeaseCh:        make(chan func(), 10),
 		expiredTimeStamp4PC: types.NewTime(types.ZeroCoreTime, mysql.TypeTimestamp, types.DefaultFsp),
+		mdl:                 newMDLManager(),
 	}
 
 	do.SchemaValidator = NewSchemaValidator(ddlLease, do)
@@ -805,6 +814,8 @@ func (do *Domain) Init(ddlLease time.Duration, sysExecutorFactory func(*Domain)
 		ddl.WithInfoCache(do.infoCache),
 		ddl.WithHook(callback),
 		ddl.WithLease(ddlLease),
+		ddl.WithMDLWaiter(do.WaitMDLReleased),
+		ddl.WithSessionFactory(sysFac),
 	)
 	failpoint.Inject("MockReplaceDDL", func(val failpoint.Value) {
 		if val.(bool) {
@@ -858,8 +869,9 @@ func (do *Domain) Init(ddlLease time.Duration, sysExecutorFactory func(*Domain)
 		// Local store needs to get the change information for every DDL state in each session.
 		go do.loadSchemaInLoop(ctx, ddlLease)
 	}
-	do.wg.Add(4)
+	do.wg.Add(5)
 	go do.topNSlowQueryLoop()
+	go do.mdlReportLoop()
 	go do.infoSyncerKeeper()
 	go do.renewLease()
 	go do.globalConfigSyncerKeeper()
@@ -1060,6 +1072,57 @@ func (do *Domain) LoadSysVarCacheLoop(ctx sessionctx.Context) error {
 	return nil
 }
 
+// LoadSQLBlocklistLoop creates a goroutine that reloads the rules of mysql.sql_blocklist with
+// the load function in a loop, it should be called only once in BootstrapSession.
+func (do *Domain) LoadSQLBlocklistLoop(ctx sessionctx.Context, load func(sessionctx.Context) error) error {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	err := load(ctx)
+	if err != nil {
+		return err
+	}
+
+	var watchCh clientv3.WatchChan
+	duration := 5 * time.Minute
+	if do.etcdClient != nil {
+		watchCh = do.etcdClient.Watch(context.Background(), sqlBlocklistKey)
+		duration = 10 * time.Minute
+	}
+
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("loadSQLBlocklistInLoop exited.")
+			util.Recover(metrics.LabelDomain, "loadSQLBlocklistInLoop", nil, false)
+		}()
+		var count int
+		for {
+			ok := true
+			select {
+			case <-do.exit:
+				return
+			case _, ok = <-watchCh:
+			case <-time.After(duration):
+			}
+			if !ok {
+				logutil.BgLogger().Error("load SQL blocklist loop watch channel closed")
+				watchCh = do.etcdClient.Watch(context.Background(), sqlBlocklistKey)
+				count++
+				if count > 10 {
+					time.Sleep(time.Duration(count) * time.Second)
+				}
+				continue
+			}
+
+			count = 0
+			if err := load(ctx); err != nil {
+				logutil.BgLogger().Error("load SQL blocklist failed", zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
 // PrivilegeHandle returns the MySQLPrivilege.
 func (do *Domain) PrivilegeHandle() *privileges.Handle {
 	return do.privHandle
@@ -1434,6 +1497,10 @@ func (do *Domain) updateStatsWorker(ctx sessionctx.Context, owner owner.Manager)
 				logutil.BgLogger().Debug("dump stats delta failed", zap.Error(err))
 			}
 			statsHandle.UpdateErrorRate(do.InfoSchema())
+			err = statsHandle.DumpIndexValueDeltaToKV(do.InfoSchema())
+			if err != nil {
+				logutil.BgLogger().Debug("dump index value delta failed", zap.Error(err))
+			}
 		case <-loadFeedbackTicker.C:
 			statsHandle.UpdateStatsByLocalFeedback(do.InfoSchema())
 			if !owner.IsOwner() {
@@ -1477,6 +1544,11 @@ func (do *Domain) autoAnalyzeWorker(owner owner.Manager) {
 	for {
 		select {
 		case <-analyzeTicker.C:
+			// The DDL events are only handled by the server which runs the DDL jobs,
+			// so the analyze jobs triggered by them don't require the ownership.
+			if statsHandle.HandleDDLAutoAnalyze(do.InfoSchema()) {
+				continue
+			}
 			if owner.IsOwner() {
 				statsHandle.HandleAutoAnalyze(do.InfoSchema())
 			}
@@ -1492,8 +1564,9 @@ func (do *Domain) ExpensiveQueryHandle() *expensivequery.Handle {
 }
 
 const (
-	privilegeKey   = "/tidb/privilege"
-	sysVarCacheKey = "/tidb/sysvars"
+	privilegeKey    = "/tidb/privilege"
+	sysVarCacheKey  = "/tidb/sysvars"
+	sqlBlocklistKey = "/tidb/sql_blocklist"
 )
 
 // NotifyUpdatePrivilege updates privilege key in etcd, TiDB client that watches
@@ -1544,6 +1617,18 @@ func (do *Domain) NotifyUpdateSysVarCache() {
 	}
 }
 
+// NotifyUpdateSQLBlocklist updates the SQL blocklist key in etcd, TiDB instances that
+// watch the key will reload the rules of mysql.sql_blocklist.
+func (do *Domain) NotifyUpdateSQLBlocklist() {
+	if do.etcdClient != nil {
+		row := do.etcdClient.KV
+		_, err := row.Put(context.Background(), sqlBlocklistKey, "")
+		if err != nil {
+			logutil.BgLogger().Warn("notify update SQL blocklist failed", zap.Error(err))
+		}
+	}
+}
+
 // ServerID gets serverID.
 func (do *Domain) ServerID() uint64 {
 	return atomic.LoadUint64(&do.serverID)
@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/session/txninfo"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/util"
@@ -438,3 +439,50 @@ func (msm *mockSessionManager) UpdateTLSConfig(*tls.Config) {}
 func (msm *mockSessionManager) ServerID() uint64 {
 	return 1
 }
+
+func TestSQLBlocklistLoop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("integration.NewClusterV3 will create file contains a colon which is not allowed on Windows")
+	}
+
+	if !unixSocketAvailable() {
+		t.Skip("ETCD use ip:port as unix socket address, skip when it is unavailable.")
+	}
+
+	store, err := mockstore.NewMockStore()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, store.Close())
+	}()
+
+	cluster := integration.NewClusterV3(t, &integration.ClusterConfig{Size: 1})
+	defer cluster.Terminate(t)
+
+	// Two domains watching the same etcd act as two TiDB instances.
+	loads := make([]chan struct{}, 2)
+	for i := range loads {
+		dom := NewDomain(store, 0, 0, 0, 0, mockFactory, nil)
+		// The domain closes its etcd client, so it can't share the clients of the cluster.
+		dom.etcdClient, err = integration.NewClientV3(cluster.Members[0])
+		require.NoError(t, err)
+		ch := make(chan struct{}, 10)
+		loads[i] = ch
+		err = dom.LoadSQLBlocklistLoop(mock.NewContext(), func(sessionctx.Context) error {
+			ch <- struct{}{}
+			return nil
+		})
+		require.NoError(t, err)
+		defer dom.Close()
+		// The rules are loaded once on start.
+		<-ch
+	}
+
+	(&Domain{etcdClient: cluster.RandClient()}).NotifyUpdateSQLBlocklist()
+	for _, ch := range loads {
+		select {
+		case <-ch:
+		case <-time.After(10 * time.Second):
+			require.FailNow(t, "the SQL blocklist is not reloaded")
+		}
+	}
+}
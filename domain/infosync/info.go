@@ -63,6 +63,8 @@ const (
 	ServerMinStartTSPath = "/tidb/server/minstartts"
 	// TiFlashTableSyncProgressPath store the tiflash table replica sync progress.
 	TiFlashTableSyncProgressPath = "/tiflash/table/sync"
+	// TiFlashTableSyncDetailPath store the tiflash table replica sync details, such as region count and ETA.
+	TiFlashTableSyncDetailPath = "/tiflash/table/sync_detail"
 	// keyOpDefaultRetryCnt is the default retry count for etcd store.
 	keyOpDefaultRetryCnt = 5
 	// keyOpDefaultTimeout is the default time out for etcd store.
@@ -325,6 +327,32 @@ func UpdateTiFlashTableSyncProgress(ctx context.Context, tid int64, progress flo
 	return util.PutKVToEtcd(ctx, is.etcdCli, keyOpDefaultRetryCnt, key, progressString)
 }
 
+// TiFlashTableSyncDetail is the detail of the tiflash table replica sync progress.
+type TiFlashTableSyncDetail struct {
+	ReplicatedRegions int   `json:"replicated_regions"`
+	TotalRegions      int   `json:"total_regions"`
+	TotalBytes        int64 `json:"total_bytes"`
+	// ETA is the estimated time when the replica becomes available, it is zero if unknown.
+	ETA time.Time `json:"eta"`
+}
+
+// UpdateTiFlashTableSyncDetail is used to update the tiflash table replica sync detail.
+func UpdateTiFlashTableSyncDetail(ctx context.Context, tid int64, detail *TiFlashTableSyncDetail) error {
+	is, err := getGlobalInfoSyncer()
+	if err != nil {
+		return err
+	}
+	if is.etcdCli == nil {
+		return nil
+	}
+	value, err := json.Marshal(detail)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	key := fmt.Sprintf("%s/%v", TiFlashTableSyncDetailPath, tid)
+	return util.PutKVToEtcd(ctx, is.etcdCli, keyOpDefaultRetryCnt, key, string(value))
+}
+
 // DeleteTiFlashTableSyncProgress is used to delete the tiflash table replica sync progress.
 func DeleteTiFlashTableSyncProgress(tid int64) error {
 	is, err := getGlobalInfoSyncer()
@@ -335,9 +363,48 @@ func DeleteTiFlashTableSyncProgress(tid int64) error {
 		return nil
 	}
 	key := fmt.Sprintf("%s/%v", TiFlashTableSyncProgressPath, tid)
+	if err := util.DeleteKeyFromEtcd(key, is.etcdCli, keyOpDefaultRetryCnt, keyOpDefaultTimeout); err != nil {
+		return err
+	}
+	key = fmt.Sprintf("%s/%v", TiFlashTableSyncDetailPath, tid)
 	return util.DeleteKeyFromEtcd(key, is.etcdCli, keyOpDefaultRetryCnt, keyOpDefaultTimeout)
 }
 
+// GetTiFlashTableSyncDetails gets the tiflash table replica sync details.
+func GetTiFlashTableSyncDetails(ctx context.Context) (map[int64]*TiFlashTableSyncDetail, error) {
+	is, err := getGlobalInfoSyncer()
+	if err != nil {
+		return nil, err
+	}
+	details := make(map[int64]*TiFlashTableSyncDetail)
+	if is.etcdCli == nil {
+		return details, nil
+	}
+	for i := 0; i < keyOpDefaultRetryCnt; i++ {
+		resp, err := is.etcdCli.Get(ctx, TiFlashTableSyncDetailPath+"/", clientv3.WithPrefix())
+		if err != nil {
+			logutil.BgLogger().Info("get tiflash table replica sync detail failed, continue checking.", zap.Error(err))
+			continue
+		}
+		for _, kv := range resp.Kvs {
+			tid, err := strconv.ParseInt(string(kv.Key[len(TiFlashTableSyncDetailPath)+1:]), 10, 64)
+			if err != nil {
+				logutil.BgLogger().Info("invalid tiflash table replica sync detail key.", zap.String("key", string(kv.Key)))
+				continue
+			}
+			detail := &TiFlashTableSyncDetail{}
+			if err := json.Unmarshal(kv.Value, detail); err != nil {
+				logutil.BgLogger().Info("invalid tiflash table replica sync detail value.",
+					zap.String("key", string(kv.Key)), zap.String("value", string(kv.Value)))
+				continue
+			}
+			details[tid] = detail
+		}
+		break
+	}
+	return details, nil
+}
+
 // GetTiFlashTableSyncProgress uses to get all the tiflash table replica sync progress.
 func GetTiFlashTableSyncProgress(ctx context.Context) (map[int64]float64, error) {
 	is, err := getGlobalInfoSyncer()
@@ -0,0 +1,272 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// mdlCheckInterval is the interval to check whether the metadata locks blocking a schema change are released.
+var mdlCheckInterval = 50 * time.Millisecond
+
+// MDLBlocker is a transaction which holds the metadata lock of a table at an old schema version.
+type MDLBlocker struct {
+	ConnID     uint64
+	TxnStartTS uint64
+	TableID    int64
+	SchemaVer  int64
+}
+
+// MDLWait is a schema change waiting for the metadata locks held by the transactions on this TiDB server.
+type MDLWait struct {
+	// Job is the DDL job which waits, it's nil if the wait is for reporting the loaded schema version.
+	Job       *model.Job
+	SchemaVer int64
+	Blockers  []MDLBlocker
+}
+
+// mdlVersionReport is a loaded schema version which isn't reported to the DDL owner yet.
+type mdlVersionReport struct {
+	schemaVer int64
+	tableIDs  map[int64]struct{}
+	since     time.Time
+}
+
+// mdlManager tracks the metadata locks held by the transactions on this TiDB server.
+// The DDL owner waits for the transactions holding the locks at an old schema version before
+// moving a table to the next schema state, and the other TiDB servers delay reporting the loaded
+// schema version until such transactions finish, so that a transaction always sees compatible
+// schemas from its start to its commit. The locks blocking a schema change for longer than
+// tidb_metadata_lock_wait_timeout are revoked, so a long transaction can't block DDL forever.
+type mdlManager struct {
+	holders sync.Map // *variable.SessionVars -> struct{}
+
+	mu struct {
+		sync.Mutex
+		waits   map[*MDLWait]struct{}
+		reports []mdlVersionReport
+	}
+	reportCh chan struct{}
+
+	// reportMu serializes reporting the schema versions, so that a reported version never goes back.
+	reportMu    sync.Mutex
+	reportedVer int64
+}
+
+func newMDLManager() *mdlManager {
+	m := &mdlManager{reportCh: make(chan struct{}, 1)}
+	m.mu.waits = make(map[*MDLWait]struct{})
+	return m
+}
+
+// RegisterMDLHolder registers a session whose transaction holds metadata locks.
+func (do *Domain) RegisterMDLHolder(vars *variable.SessionVars) {
+	do.mdl.holders.Store(vars, struct{}{})
+}
+
+// UnregisterMDLHolder unregisters a session whose transaction has released its metadata locks.
+func (do *Domain) UnregisterMDLHolder(vars *variable.SessionVars) {
+	do.mdl.holders.Delete(vars)
+}
+
+// MDLBlockers returns the transactions holding the metadata locks of the tables at a schema version older than schemaVer.
+func (do *Domain) MDLBlockers(tableIDs map[int64]struct{}, schemaVer int64) []MDLBlocker {
+	var blockers []MDLBlocker
+	do.mdl.holders.Range(func(key, _ interface{}) bool {
+		vars := key.(*variable.SessionVars)
+		tables, startTS := vars.GetMDLTables()
+		for id, ver := range tables {
+			if _, ok := tableIDs[id]; ok && ver < schemaVer {
+				blockers = append(blockers, MDLBlocker{
+					ConnID:     vars.ConnectionID,
+					TxnStartTS: startTS,
+					TableID:    id,
+					SchemaVer:  ver,
+				})
+			}
+		}
+		return true
+	})
+	sort.Slice(blockers, func(i, j int) bool {
+		if blockers[i].ConnID != blockers[j].ConnID {
+			return blockers[i].ConnID < blockers[j].ConnID
+		}
+		return blockers[i].TableID < blockers[j].TableID
+	})
+	return blockers
+}
+
+// MDLWaits returns the schema changes which are waiting for metadata locks on this TiDB server.
+func (do *Domain) MDLWaits() []MDLWait {
+	do.mdl.mu.Lock()
+	defer do.mdl.mu.Unlock()
+	waits := make([]MDLWait, 0, len(do.mdl.mu.waits)+len(do.mdl.mu.reports))
+	for w := range do.mdl.mu.waits {
+		waits = append(waits, *w)
+	}
+	for _, r := range do.mdl.mu.reports {
+		if blockers := do.MDLBlockers(r.tableIDs, r.schemaVer); len(blockers) > 0 {
+			waits = append(waits, MDLWait{SchemaVer: r.schemaVer, Blockers: blockers})
+		}
+	}
+	sort.Slice(waits, func(i, j int) bool { return waits[i].SchemaVer < waits[j].SchemaVer })
+	return waits
+}
+
+// WaitMDLReleased waits until no transaction on this TiDB server holds the metadata lock of the table
+// changed by the job at a schema version older than schemaVer.
+func (do *Domain) WaitMDLReleased(ctx context.Context, job *model.Job, schemaVer int64) error {
+	tableIDs := map[int64]struct{}{job.TableID: {}}
+	blockers := do.MDLBlockers(tableIDs, schemaVer)
+	if len(blockers) == 0 {
+		return nil
+	}
+	wait := &MDLWait{Job: job, SchemaVer: schemaVer, Blockers: blockers}
+	do.mdl.mu.Lock()
+	do.mdl.mu.waits[wait] = struct{}{}
+	do.mdl.mu.Unlock()
+	defer func() {
+		do.mdl.mu.Lock()
+		delete(do.mdl.mu.waits, wait)
+		do.mdl.mu.Unlock()
+	}()
+	logutil.BgLogger().Info("[ddl] wait for the metadata locks to be released", zap.Int64("jobID", job.ID),
+		zap.Int64("tableID", job.TableID), zap.Int64("schemaVersion", schemaVer), zap.Int("blockers", len(blockers)))
+
+	start := time.Now()
+	ticker := time.NewTicker(mdlCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if !variable.EnableMDL.Load() {
+			return nil
+		}
+		blockers = do.MDLBlockers(tableIDs, schemaVer)
+		if len(blockers) == 0 {
+			return nil
+		}
+		if time.Since(start) >= mdlWaitTimeout() {
+			do.revokeMDL(tableIDs, schemaVer)
+			return nil
+		}
+		do.mdl.mu.Lock()
+		wait.Blockers = blockers
+		do.mdl.mu.Unlock()
+	}
+}
+
+// mdlWaitTimeout returns the max time a schema change waits for the metadata locks, see tidb_metadata_lock_wait_timeout.
+func mdlWaitTimeout() time.Duration {
+	return time.Duration(variable.MDLWaitTimeout.Load()) * time.Second
+}
+
+// revokeMDL revokes the metadata locks of the tables held at a schema version older than schemaVer,
+// the transactions holding them are checked for the schema change when committing.
+func (do *Domain) revokeMDL(tableIDs map[int64]struct{}, schemaVer int64) {
+	do.mdl.holders.Range(func(key, _ interface{}) bool {
+		vars := key.(*variable.SessionVars)
+		if revoked := vars.RevokeMDL(tableIDs, schemaVer); len(revoked) > 0 {
+			logutil.BgLogger().Warn("[ddl] revoke the metadata locks blocking the schema change longer than tidb_metadata_lock_wait_timeout",
+				zap.Uint64("conn", vars.ConnectionID), zap.Int64s("tableIDs", revoked), zap.Int64("schemaVersion", schemaVer))
+		}
+		return true
+	})
+}
+
+// reportSelfVersion reports the loaded schema version to the DDL owner. If metadata lock is enabled,
+// the version is reported after the transactions holding the locks of the changed tables finish.
+func (do *Domain) reportSelfVersion(schemaVer int64, changedTableIDs []int64) {
+	do.mdl.mu.Lock()
+	if variable.EnableMDL.Load() && changedTableIDs != nil {
+		tableIDs := make(map[int64]struct{}, len(changedTableIDs))
+		for _, id := range changedTableIDs {
+			tableIDs[id] = struct{}{}
+		}
+		if len(do.mdl.mu.reports) > 0 || len(do.MDLBlockers(tableIDs, schemaVer)) > 0 {
+			do.mdl.mu.reports = append(do.mdl.mu.reports, mdlVersionReport{schemaVer: schemaVer, tableIDs: tableIDs, since: time.Now()})
+			do.mdl.mu.Unlock()
+			select {
+			case do.mdl.reportCh <- struct{}{}:
+			default:
+			}
+			return
+		}
+	}
+	// Drop the pending reports, the newer version covers them.
+	do.mdl.mu.reports = nil
+	do.mdl.mu.Unlock()
+	do.updateSelfVersion(schemaVer)
+}
+
+func (do *Domain) updateSelfVersion(schemaVer int64) {
+	do.mdl.reportMu.Lock()
+	defer do.mdl.reportMu.Unlock()
+	if schemaVer < do.mdl.reportedVer {
+		return
+	}
+	err := do.ddl.SchemaSyncer().UpdateSelfVersion(context.Background(), schemaVer)
+	if err != nil {
+		logutil.BgLogger().Info("update self version failed",
+			zap.Int64("neededSchemaVersion", schemaVer), zap.Error(err))
+		return
+	}
+	do.mdl.reportedVer = schemaVer
+}
+
+// mdlReportLoop reports the delayed schema versions in order once the metadata locks blocking them are released.
+func (do *Domain) mdlReportLoop() {
+	defer util.Recover("domain", "mdlReportLoop", nil, false)
+	defer do.wg.Done()
+	ticker := time.NewTicker(mdlCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-do.exit:
+			return
+		case <-do.mdl.reportCh:
+		case <-ticker.C:
+		}
+		do.mdl.mu.Lock()
+		reported := int64(0)
+		for len(do.mdl.mu.reports) > 0 {
+			r := do.mdl.mu.reports[0]
+			if variable.EnableMDL.Load() && len(do.MDLBlockers(r.tableIDs, r.schemaVer)) > 0 {
+				if time.Since(r.since) < mdlWaitTimeout() {
+					break
+				}
+				do.revokeMDL(r.tableIDs, r.schemaVer)
+			}
+			reported = r.schemaVer
+			do.mdl.mu.reports = do.mdl.mu.reports[1:]
+		}
+		do.mdl.mu.Unlock()
+		if reported != 0 {
+			do.updateSelfVersion(reported)
+		}
+	}
+}
@@ -241,6 +241,22 @@ func (do *Domain) checkEnableServerGlobalVar(name, sVal string) {
 		variable.StatsLoadSyncWait.Store(val)
 	case variable.TiDBStatsLoadPseudoTimeout:
 		variable.StatsLoadPseudoTimeout.Store(variable.TiDBOptOn(sVal))
+	case variable.TiDBStatsCacheMemQuota:
+		var val int64
+		val, err = strconv.ParseInt(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		variable.StatsCacheMemQuota.Store(val)
+	case variable.TiDBDDLEnableFastReorg:
+		variable.DDLEnableFastReorg.Store(variable.TiDBOptOn(sVal))
+	case variable.TiDBDDLDiskQuota:
+		var val uint64
+		val, err = strconv.ParseUint(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		variable.DDLDiskQuota.Store(val)
 	}
 	if err != nil {
 		logutil.BgLogger().Error(fmt.Sprintf("load global variable %s error", name), zap.Error(err))
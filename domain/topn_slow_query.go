@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/stmtsummary"
 )
 
 type slowQueryHeap struct {
@@ -221,3 +222,52 @@ type SlowQueryInfo struct {
 	Internal   bool
 	Succ       bool
 }
+
+// mergePersistedSlowQueries merges the slow statements in the persisted statements summary into the result of
+// ADMIN SHOW SLOW. The persisted statements are collected before the server started, so they're older than the
+// slow queries in memory.
+func mergePersistedSlowQueries(req *ast.ShowSlow, result []*SlowQueryInfo, slowStmts []*stmtsummary.SlowStmt, period time.Duration, now time.Time) []*SlowQueryInfo {
+	persisted := make([]*SlowQueryInfo, 0, len(slowStmts))
+	for _, stmt := range slowStmts {
+		if req.Tp == ast.ShowSlowTop {
+			if stmt.LastSeen.Add(period).Before(now) {
+				continue
+			}
+			if (req.Kind == ast.ShowSlowKindDefault && stmt.Internal) || (req.Kind == ast.ShowSlowKindInternal && !stmt.Internal) {
+				continue
+			}
+		}
+		persisted = append(persisted, &SlowQueryInfo{
+			SQL:        stmt.SQL,
+			Start:      stmt.LastSeen,
+			Duration:   stmt.MaxLatency,
+			User:       stmt.User,
+			DB:         stmt.DB,
+			IndexNames: stmt.IndexNames,
+			Digest:     stmt.Digest,
+			Internal:   stmt.Internal,
+			Succ:       stmt.Succ,
+		})
+	}
+	if len(persisted) == 0 {
+		return result
+	}
+
+	var merged []*SlowQueryInfo
+	switch req.Tp {
+	case ast.ShowSlowTop:
+		merged = append(append(merged, result...), persisted...)
+		sort.SliceStable(merged, func(i, j int) bool { return merged[i].Duration > merged[j].Duration })
+	case ast.ShowSlowRecent:
+		merged = append(append(merged, result...), persisted...)
+		sort.SliceStable(merged, func(i, j int) bool { return merged[i].Start.After(merged[j].Start) })
+	default:
+		// All the slow queries are shown from the oldest.
+		sort.SliceStable(persisted, func(i, j int) bool { return persisted[i].Start.Before(persisted[j].Start) })
+		return append(persisted, result...)
+	}
+	if len(merged) > int(req.Count) {
+		merged = merged[:req.Count]
+	}
+	return merged
+}
@@ -18,6 +18,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/stretchr/testify/require"
 )
 
@@ -130,6 +132,37 @@ func TestQueue(t *testing.T) {
 	require.Equal(t, "ccc", query[4].SQL)
 }
 
+func TestMergePersistedSlowQueries(t *testing.T) {
+	now := time.Now()
+	result := []*SlowQueryInfo{
+		{SQL: "aaa", Start: now.Add(-time.Minute), Duration: 2 * time.Second},
+		{SQL: "bbb", Start: now.Add(-2 * time.Minute), Duration: 4 * time.Second},
+	}
+	slowStmts := []*stmtsummary.SlowStmt{
+		{SQL: "ccc", LastSeen: now.Add(-time.Hour), MaxLatency: 3 * time.Second},
+		{SQL: "ddd", LastSeen: now.Add(-2 * time.Hour), MaxLatency: 5 * time.Second, Internal: true},
+		{SQL: "eee", LastSeen: now.Add(-30 * 24 * time.Hour), MaxLatency: 6 * time.Second},
+	}
+	sqls := func(queries []*SlowQueryInfo) []string {
+		res := make([]string, 0, len(queries))
+		for _, query := range queries {
+			res = append(res, query.SQL)
+		}
+		return res
+	}
+
+	req := &ast.ShowSlow{Tp: ast.ShowSlowTop, Count: 10, Kind: ast.ShowSlowKindDefault}
+	require.Equal(t, []string{"bbb", "ccc", "aaa"}, sqls(mergePersistedSlowQueries(req, result, slowStmts, 7*24*time.Hour, now)))
+	req = &ast.ShowSlow{Tp: ast.ShowSlowTop, Count: 2, Kind: ast.ShowSlowKindAll}
+	require.Equal(t, []string{"ddd", "bbb"}, sqls(mergePersistedSlowQueries(req, result, slowStmts, 7*24*time.Hour, now)))
+	req = &ast.ShowSlow{Tp: ast.ShowSlowTop, Count: 10, Kind: ast.ShowSlowKindInternal}
+	require.Equal(t, []string{"ddd"}, sqls(mergePersistedSlowQueries(req, nil, slowStmts, 7*24*time.Hour, now)))
+	req = &ast.ShowSlow{Tp: ast.ShowSlowRecent, Count: 3}
+	require.Equal(t, []string{"aaa", "bbb", "ccc"}, sqls(mergePersistedSlowQueries(req, result, slowStmts, 7*24*time.Hour, now)))
+	req = &ast.ShowSlow{Tp: ast.ShowSlowRecent, Count: 3}
+	require.Equal(t, []string{"aaa", "bbb"}, sqls(mergePersistedSlowQueries(req, result, nil, 7*24*time.Hour, now)))
+}
+
 func checkHeap(q *slowQueryHeap, t *testing.T) {
 	for i := 0; i < len(q.data); i++ {
 		left := 2*i + 1
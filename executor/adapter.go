@@ -17,8 +17,10 @@ package executor
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
+	"math/rand"
 	"runtime/trace"
 	"strings"
 	"sync/atomic"
@@ -47,6 +49,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/sessiontxn"
+	storeerr "github.com/pingcap/tidb/store/driver/error"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/execdetails"
@@ -88,6 +91,8 @@ type recordSet struct {
 	stmt       *ExecStmt
 	lastErr    error
 	txnStartTS uint64
+	// truncated indicates the result has been truncated by tidb_max_result_rows, so no more rows are returned.
+	truncated bool
 }
 
 func (a *recordSet) Fields() []*ast.ResultField {
@@ -146,7 +151,14 @@ func (a *recordSet) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		logutil.Logger(ctx).Error("execute sql panic", zap.String("sql", a.stmt.GetTextToLog()), zap.Stack("stack"))
 	}()
 
+	if a.truncated {
+		req.Reset()
+		return nil
+	}
 	err = Next(ctx, a.executor, req)
+	if err == nil && a.stmt != nil {
+		err = a.limitResultRows(req)
+	}
 	if err != nil {
 		a.lastErr = err
 		return err
@@ -164,6 +176,28 @@ func (a *recordSet) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	return nil
 }
 
+// limitResultRows checks the rows returned by the SELECT statement against tidb_max_result_rows. When the limit
+// is exceeded, it returns an error, or truncates the result with a warning if tidb_max_result_rows_mode is 'warn'.
+func (a *recordSet) limitResultRows(req *chunk.Chunk) error {
+	sessVars := a.stmt.Ctx.GetSessionVars()
+	limit := sessVars.MaxResultRows
+	if limit <= 0 || sessVars.InRestrictedSQL || !sessVars.StmtCtx.InSelectStmt {
+		return nil
+	}
+	returned := int64(sessVars.StmtCtx.FoundRows())
+	if returned+int64(req.NumRows()) <= limit {
+		return nil
+	}
+	err := ErrResultRowsExceeded.GenWithStackByArgs(limit)
+	if !sessVars.TruncateOnMaxResultRows {
+		return err
+	}
+	sessVars.StmtCtx.AppendWarning(err)
+	req.TruncateTo(int(limit - returned))
+	a.truncated = true
+	return nil
+}
+
 // NewChunk create a chunk base on top-level executor's newFirstChunk().
 func (a *recordSet) NewChunk(alloc chunk.Allocator) *chunk.Chunk {
 	if alloc == nil {
@@ -189,6 +223,7 @@ func (a *recordSet) OnFetchReturned() {
 type TelemetryInfo struct {
 	UseNonRecursive bool
 	UseRecursive    bool
+	UseMPP          bool
 }
 
 // ExecStmt implements the sqlexec.Statement interface, it builds a planner.Plan to an sqlexec.Statement.
@@ -219,6 +254,8 @@ type ExecStmt struct {
 	isSelectForUpdate bool
 	retryCount        uint
 	retryStartTime    time.Time
+	// lockWaitTimeoutRetryCount is the number of retries of the statement caused by the lock wait timeout.
+	lockWaitTimeoutRetryCount int
 
 	// OutputNames will be set if using cached plan
 	OutputNames []*types.FieldName
@@ -422,6 +459,9 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 			}
 		}
 		maxExecutionTime := getMaxExecutionTime(sctx)
+		if !a.isMaxExecutionTimeApplicable() {
+			maxExecutionTime = 0
+		}
 		// Update processinfo, ShowProcess() will use it.
 		pi.SetProcessInfo(sql, time.Now(), cmd, maxExecutionTime)
 		if a.Ctx.GetSessionVars().StmtCtx.StmtType == "" {
@@ -521,6 +561,24 @@ func isNoResultPlan(p plannercore.Plan) bool {
 	return false
 }
 
+// isMaxExecutionTimeApplicable checks whether the max execution timeout applies to the statement. It applies to
+// the read-only statements, and also applies to INSERT, REPLACE, UPDATE and DELETE statements if
+// tidb_enable_dml_max_execution_time is on. The writes of a timed out DML statement are rolled back with the statement.
+func (a *ExecStmt) isMaxExecutionTimeApplicable() bool {
+	vars := a.Ctx.GetSessionVars()
+	if a.IsReadOnly(vars) {
+		return true
+	}
+	if !vars.EnableDMLMaxExecutionTime {
+		return false
+	}
+	switch a.Plan.(type) {
+	case *plannercore.Insert, *plannercore.Update, *plannercore.Delete:
+		return true
+	}
+	return false
+}
+
 // getMaxExecutionTime get the max execution timeout value.
 func getMaxExecutionTime(sctx sessionctx.Context) uint64 {
 	if sctx.GetSessionVars().StmtCtx.HasMaxExecutionTime {
@@ -584,7 +642,7 @@ func (a *ExecStmt) runPessimisticSelectForUpdate(ctx context.Context, e Executor
 	}()
 	var rows []chunk.Row
 	var err error
-	req := newFirstChunk(e)
+	req := tryNewCacheChunk(e)
 	for {
 		err = Next(ctx, e, req)
 		if err != nil {
@@ -760,6 +818,15 @@ func (a *ExecStmt) handlePessimisticLockError(ctx context.Context, err error) (E
 		// is used, the commitTS of this transaction may exceed the max timestamp
 		// that PD allocates. Then, the change may be invisible to a new transaction,
 		// which means linearizability is broken.
+	} else if a.isLockWaitTimeoutRetryable(err) {
+		a.lockWaitTimeoutRetryCount++
+		logutil.Logger(ctx).Info("pessimistic lock wait timeout, retry auto-committed statement",
+			zap.Uint64("txn", txnCtx.StartTS),
+			zap.Uint64("forUpdateTS", txnCtx.GetForUpdateTS()),
+			zap.Int("retryCount", a.lockWaitTimeoutRetryCount))
+		if err = lockWaitTimeoutRetryBackoff(ctx, sessVars.LockWaitTimeoutRetryJitter); err != nil {
+			return nil, err
+		}
 	} else {
 		// this branch if err not nil, always update forUpdateTS to avoid problem described below
 		// for nowait, when ErrLock happened, ErrLockAcquireFailAndNoWaitSet will be returned, and in the same txn
@@ -804,6 +871,36 @@ func (a *ExecStmt) handlePessimisticLockError(ctx context.Context, err error) (E
 	return e, nil
 }
 
+// isLockWaitTimeoutRetryable checks whether the statement can be retried in the server after its lock wait
+// times out. Only the auto-committed DML statements are retried, the statements in an explicit transaction
+// and SELECT FOR UPDATE return the error to the application, which may hold other locks and decide by itself.
+func (a *ExecStmt) isLockWaitTimeoutRetryable(err error) bool {
+	if !storeerr.ErrLockWaitTimeout.Equal(err) {
+		return false
+	}
+	sessVars := a.Ctx.GetSessionVars()
+	if a.isSelectForUpdate || !sessVars.IsAutocommit() || sessVars.InTxn() {
+		return false
+	}
+	return a.lockWaitTimeoutRetryCount < sessVars.LockWaitTimeoutRetryLimit
+}
+
+// lockWaitTimeoutRetryBackoff sleeps a random duration in [0, jitter) before retrying the statement, so
+// the statements timed out on the same lock do not retry at the same time.
+func lockWaitTimeoutRetryBackoff(ctx context.Context, jitter time.Duration) error {
+	if jitter <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(jitter))))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 type pessimisticTxn interface {
 	kv.Transaction
 	// KeysNeedToLock returns the keys need to be locked.
@@ -846,6 +943,7 @@ func (a *ExecStmt) buildExecutor() (Executor, error) {
 		ctx.GetSessionVars().StmtCtx.Priority = kv.PriorityLow
 	}
 
+	setStmtMaxChunkSize(ctx.GetSessionVars(), a.Plan)
 	b := newExecutorBuilder(ctx, a.InfoSchema, a.Ti, a.SnapshotTS, a.IsStaleness, a.ReplicaReadScope)
 	e := b.build(a.Plan)
 	if b.err != nil {
@@ -873,9 +971,108 @@ func (a *ExecStmt) buildExecutor() (Executor, error) {
 		e = executorExec.stmtExec
 	}
 	a.isSelectForUpdate = b.hasLock && (!stmtCtx.InDeleteStmt && !stmtCtx.InUpdateStmt && !stmtCtx.InInsertStmt)
+	// The replica to read from is decided when the executors are opened, so it's decided by the final plan here.
+	if vars := ctx.GetSessionVars(); vars.IsReplicaReadClosestAdaptive() {
+		stmtCtx.UseClosestReplica = estimateResponseSize(a.Plan) >= float64(vars.ReplicaClosestReadThreshold)
+	}
 	return e, nil
 }
 
+// setStmtMaxChunkSize sets the max row count of the Chunks of the statement by the widest rows of its plan,
+// so all the executors of the statement use the same Chunk size.
+func setStmtMaxChunkSize(vars *variable.SessionVars, p plannercore.Plan) {
+	if execute, ok := p.(*plannercore.Execute); ok && execute.Plan != nil {
+		p = execute.Plan
+	}
+	vars.StmtCtx.MaxChunkSize = vars.GetMaxChunkSizeByRowWidth(estimatePlanRowWidth(p))
+}
+
+// estimatePlanRowWidth estimates the width of the widest rows of the executors built from the plan.
+func estimatePlanRowWidth(p plannercore.Plan) int {
+	width := 0
+	if schema := p.Schema(); schema != nil {
+		fields := make([]*types.FieldType, 0, schema.Len())
+		for _, col := range schema.Columns {
+			fields = append(fields, col.RetType)
+		}
+		width = chunk.EstimateRowWidth(fields)
+	}
+	var children []plannercore.PhysicalPlan
+	switch x := p.(type) {
+	case plannercore.PhysicalPlan:
+		children = x.Children()
+	case *plannercore.Insert:
+		children = []plannercore.PhysicalPlan{x.SelectPlan}
+	case *plannercore.Update:
+		children = []plannercore.PhysicalPlan{x.SelectPlan}
+	case *plannercore.Delete:
+		children = []plannercore.PhysicalPlan{x.SelectPlan}
+	}
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		if childWidth := estimatePlanRowWidth(child); childWidth > width {
+			width = childWidth
+		}
+	}
+	return width
+}
+
+// estimateResponseSize estimates the size in bytes of the data returned from the storage for the plan.
+func estimateResponseSize(p plannercore.Plan) float64 {
+	switch x := p.(type) {
+	case *plannercore.PhysicalTableReader:
+		return estimatePlanOutputSize(x.TablePlans[len(x.TablePlans)-1])
+	case *plannercore.PhysicalIndexReader:
+		return estimatePlanOutputSize(x.IndexPlans[len(x.IndexPlans)-1])
+	case *plannercore.PhysicalIndexLookUpReader:
+		return estimatePlanOutputSize(x.IndexPlans[len(x.IndexPlans)-1]) + estimatePlanOutputSize(x.TablePlans[len(x.TablePlans)-1])
+	case *plannercore.PhysicalIndexMergeReader:
+		size := estimatePlanOutputSize(x.TablePlans[len(x.TablePlans)-1])
+		for _, partialPlans := range x.PartialPlans {
+			size += estimatePlanOutputSize(partialPlans[len(partialPlans)-1])
+		}
+		return size
+	case *plannercore.PointGetPlan:
+		return estimatePlanOutputSize(x)
+	case *plannercore.BatchPointGetPlan:
+		return estimatePlanOutputSize(x)
+	case plannercore.PhysicalPlan:
+		var size float64
+		for _, child := range x.Children() {
+			size += estimateResponseSize(child)
+		}
+		return size
+	case *plannercore.Insert:
+		if x.SelectPlan != nil {
+			return estimateResponseSize(x.SelectPlan)
+		}
+	case *plannercore.Update:
+		if x.SelectPlan != nil {
+			return estimateResponseSize(x.SelectPlan)
+		}
+	case *plannercore.Delete:
+		if x.SelectPlan != nil {
+			return estimateResponseSize(x.SelectPlan)
+		}
+	case *plannercore.Explain:
+		return estimateResponseSize(x.TargetPlan)
+	case *plannercore.SelectInto:
+		return estimateResponseSize(x.TargetPlan)
+	}
+	return 0
+}
+
+func estimatePlanOutputSize(p plannercore.PhysicalPlan) float64 {
+	cols := p.Schema().Columns
+	fieldTypes := make([]*types.FieldType, 0, len(cols))
+	for _, col := range cols {
+		fieldTypes = append(fieldTypes, col.RetType)
+	}
+	return p.StatsCount() * float64(chunk.EstimateRowWidth(fieldTypes))
+}
+
 // QueryReplacer replaces new line and tab for grep result including query string.
 var QueryReplacer = strings.NewReplacer("\r", " ", "\n", " ", "\t", " ")
 
@@ -1066,6 +1263,8 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 		ExecRetryCount:    a.retryCount,
 		IsExplicitTxn:     sessVars.TxnCtx.IsExplicit,
 		IsWriteCacheTable: sessVars.StmtCtx.WaitLockLeaseTime > 0,
+		QueryAttributes:   getQueryAttributes(sessVars),
+		SlowRPCs:          sessVars.StmtCtx.SlowRPCs(),
 	}
 	if a.retryCount > 0 {
 		slowItems.ExecRetryTime = costTime - sessVars.DurationParse - sessVars.DurationCompile - time.Since(a.retryStartTime)
@@ -1141,6 +1340,19 @@ func getPlanTree(sctx sessionctx.Context, p plannercore.Plan) string {
 	return variable.SlowLogPlanPrefix + planTree + variable.SlowLogPlanSuffix
 }
 
+// getQueryAttributes returns the JSON encoded query attributes of the current statement.
+// The attributes are not recorded if the log is redacted.
+func getQueryAttributes(sessVars *variable.SessionVars) string {
+	if len(sessVars.QueryAttributes) == 0 || sessVars.EnableRedactLog {
+		return ""
+	}
+	attrs, err := json.Marshal(sessVars.QueryAttributes)
+	if err != nil {
+		return ""
+	}
+	return string(attrs)
+}
+
 // getPlanDigest will try to get the select plan tree if the plan is select or the select plan of delete/update/insert statement.
 func getPlanDigest(sctx sessionctx.Context, p plannercore.Plan) (string, *parser.Digest) {
 	sc := sctx.GetSessionVars().StmtCtx
@@ -1284,6 +1496,7 @@ func (a *ExecStmt) SummaryStmt(succ bool) {
 		ResultRows:      GetResultRowsCount(a.Ctx, a.Plan),
 		TiKVExecDetails: tikvExecDetail,
 		Prepared:        a.isPreparedStmt,
+		QueryAttributes: getQueryAttributes(sessVars),
 	}
 	if a.retryCount > 0 {
 		stmtExecInfo.ExecRetryTime = costTime - sessVars.DurationParse - sessVars.DurationCompile - time.Since(a.retryStartTime)
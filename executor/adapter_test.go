@@ -18,6 +18,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/stretchr/testify/require"
 )
@@ -40,3 +42,126 @@ func TestQueryTime(t *testing.T) {
 	costTime = time.Since(tk.Session().GetSessionVars().StartTime)
 	require.Less(t, costTime, time.Second)
 }
+
+func TestAdaptiveClosestReplicaRead(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b varchar(100))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec("insert into t values (?, 'a')", i)
+	}
+	tk.MustExec("analyze table t")
+
+	vars := tk.Session().GetSessionVars()
+	tk.MustExec("set @@tidb_replica_read = 'closest-adaptive'")
+	tk.MustQuery("select @@tidb_adaptive_closest_read_threshold").Check(testkit.Rows("4096"))
+	// The small reads are sent to the leader.
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 a"))
+	require.False(t, vars.StmtCtx.UseClosestReplica)
+	require.Equal(t, kv.ReplicaReadLeader, vars.GetReplicaRead())
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("100"))
+	require.False(t, vars.StmtCtx.UseClosestReplica)
+	// The large reads are sent to the closest replicas.
+	require.Len(t, tk.MustQuery("select * from t").Rows(), 100)
+	require.True(t, vars.StmtCtx.UseClosestReplica)
+	require.Equal(t, kv.ReplicaReadClosestAdaptive, vars.GetReplicaRead())
+	tk.MustExec("update t set b = 'a' where b = 'a'")
+	require.True(t, vars.StmtCtx.UseClosestReplica)
+
+	tk.MustExec("set @@tidb_adaptive_closest_read_threshold = 0")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 a"))
+	require.True(t, vars.StmtCtx.UseClosestReplica)
+	tk.MustExec("prepare stmt from 'select * from t where a = ?'")
+	tk.MustExec("set @@tidb_adaptive_closest_read_threshold = 4096")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1 a"))
+	require.False(t, vars.StmtCtx.UseClosestReplica)
+
+	tk.MustExec("set @@tidb_replica_read = 'leader'")
+	require.Len(t, tk.MustQuery("select * from t").Rows(), 100)
+	require.False(t, vars.StmtCtx.UseClosestReplica)
+	require.Equal(t, kv.ReplicaReadLeader, vars.GetReplicaRead())
+}
+
+func TestDMLMaxExecutionTime(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int)")
+	tk.MustExec("set @@max_execution_time = 1000")
+
+	checkMaxExecutionTime := func(sql string, expected uint64) {
+		if rs, err := tk.Exec(sql); rs != nil {
+			require.NoError(t, err, sql)
+			require.NoError(t, rs.Close())
+		} else {
+			require.NoError(t, err, sql)
+		}
+		require.Equal(t, expected, tk.Session().ShowProcess().MaxExecutionTime, sql)
+	}
+	dmls := []string{
+		"insert ignore into t values (1, 1)",
+		"replace into t values (2, 2)",
+		"update t set b = b + 1 where a = 1",
+		"update t set b = b + 1",
+		"delete from t where a = 2",
+	}
+
+	// The max execution time only applies to the read-only statements by default.
+	checkMaxExecutionTime("select * from t", 1000)
+	checkMaxExecutionTime("select /*+ max_execution_time(500) */ * from t", 500)
+	checkMaxExecutionTime("select * from t for update", 0)
+	for _, sql := range dmls {
+		checkMaxExecutionTime(sql, 0)
+	}
+
+	tk.MustExec("set @@tidb_enable_dml_max_execution_time = on")
+	checkMaxExecutionTime("select * from t", 1000)
+	for _, sql := range dmls {
+		checkMaxExecutionTime(sql, 1000)
+	}
+	checkMaxExecutionTime("alter table t add column c int", 0)
+}
+
+func TestMaxResultRows(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec("insert into t values (?)", i)
+	}
+	// Return the rows in several chunks.
+	tk.MustExec("set @@tidb_init_chunk_size = 1, @@tidb_max_chunk_size = 32")
+	tk.MustQuery("select @@tidb_max_result_rows, @@tidb_max_result_rows_mode").Check(testkit.Rows("0 error"))
+
+	tk.MustExec("set @@tidb_max_result_rows = 50")
+	err := tk.QueryToErr("select * from t")
+	require.EqualError(t, err, "[executor:8247]The result of the statement exceeds @@tidb_max_result_rows (50 rows)")
+	require.Len(t, tk.MustQuery("select * from t where a < 50").Rows(), 50)
+	// The statements which don't return the rows to the client are not limited.
+	tk.MustExec("create table t2(a int)")
+	tk.MustExec("insert into t2 select * from t")
+	tk.MustQuery("select count(*) from t2").Check(testkit.Rows("100"))
+
+	tk.MustExec("set @@tidb_max_result_rows_mode = 'warn'")
+	rows := tk.MustQuery("select * from t order by a").Rows()
+	require.Len(t, rows, 50)
+	require.Equal(t, "49", rows[49][0])
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 8247 The result of the statement exceeds @@tidb_max_result_rows (50 rows)"))
+	require.Len(t, tk.MustQuery("select * from t union all select * from t").Rows(), 50)
+	require.Len(t, tk.MustQuery("select * from t limit 50").Rows(), 50)
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+
+	tk.MustExec("set @@tidb_max_result_rows = 0")
+	require.Len(t, tk.MustQuery("select * from t").Rows(), 100)
+	tk.MustGetErrCode("set @@tidb_max_result_rows_mode = 'truncate'", errno.ErrWrongValueForVar)
+}
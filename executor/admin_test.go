@@ -116,6 +116,63 @@ func TestAdminCheckIndexInTemporaryMode(t *testing.T) {
 	tk.MustExec("drop table if exists temporary_admin_checksum_table_with_index_test,temporary_admin_checksum_table_without_index_test;")
 }
 
+func TestAdminChecksumTableWithIndexesAndCompare(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, checksums, checksums_without_index, bad_checksums")
+	tk.MustExec("create table t1 (id int, c int, primary key (id), key idx_c (c))")
+	tk.MustExec("create table t2 (id int, primary key (id))")
+	// The mock store returns 1 for every table/index scan, then the checksums of a table are xor-ed.
+	tk.MustQuery("admin checksum table t1, t2").Check(testkit.Rows("test t1 0 2 2", "test t2 1 1 1"))
+	tk.MustQuery("admin checksum table t1, t2 with indexes").Check(testkit.Rows(
+		"test t1 <nil> 0 2 2",
+		"test t1 idx_c 1 1 1",
+		"test t2 <nil> 1 1 1",
+	))
+	tk.MustQuery("admin checksum table t1 as of timestamp now() - interval 1 second with indexes").Check(testkit.Rows(
+		"test t1 <nil> 0 2 2",
+		"test t1 idx_c 1 1 1",
+	))
+	_, err := tk.Exec("admin checksum table t1 as of timestamp now() + interval 1 day")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot set read timestamp to a future time")
+
+	tk.MustExec("create table checksums (db_name varchar(64), table_name varchar(64), index_name varchar(64), " +
+		"checksum_crc64_xor bigint unsigned, total_kvs bigint unsigned, total_bytes bigint unsigned)")
+	tk.MustExec("insert into checksums values ('test', 't1', null, 0, 2, 2), ('TEST', 'T1', 'IDX_C', 1, 1, 2)")
+	tk.MustQuery("admin compare checksum table t1, t2 using checksums").Check(testkit.Rows(
+		"test t1 <nil> 0 2 2 0 2 2 MATCH",
+		"test t1 idx_c 1 1 1 1 1 2 MISMATCH",
+		"test t2 <nil> 1 1 1 <nil> <nil> <nil> MISSING",
+	))
+	tk.MustQuery("admin compare checksum table t1 as of timestamp now() - interval 1 second using test.checksums").Check(testkit.Rows(
+		"test t1 <nil> 0 2 2 0 2 2 MATCH",
+		"test t1 idx_c 1 1 1 1 1 2 MISMATCH",
+	))
+
+	// Only the checksums of the whole tables are compared if the indexes are not stored.
+	tk.MustExec("create table checksums_without_index (db_name varchar(64), table_name varchar(64), " +
+		"checksum_crc64_xor bigint unsigned, total_kvs bigint unsigned, total_bytes bigint unsigned)")
+	tk.MustExec("insert into checksums_without_index values ('test', 't1', 0, 2, 2), ('test', 't2', 0, 1, 1)")
+	tk.MustQuery("admin compare checksum table t1, t2 using checksums_without_index").Check(testkit.Rows(
+		"test t1 <nil> 0 2 2 0 2 2 MATCH",
+		"test t2 <nil> 1 1 1 0 1 1 MISMATCH",
+	))
+
+	tk.MustExec("create table bad_checksums (db_name varchar(64), table_name varchar(64), checksum_crc64_xor bigint unsigned)")
+	_, err = tk.Exec("admin compare checksum table t1 using bad_checksums")
+	require.EqualError(t, err, core.ErrUnknownColumn.GenWithStackByArgs("total_kvs", "bad_checksums").Error())
+	_, err = tk.Exec("admin compare checksum table t1 using not_exists")
+	require.Error(t, err)
+
+	tk.MustExec("create temporary table t3 (id int primary key)")
+	_, err = tk.Exec("admin compare checksum table t3 using checksums")
+	require.EqualError(t, err, core.ErrOptOnTemporaryTable.GenWithStackByArgs("admin compare checksum table").Error())
+}
+
 func TestAdminCheckIndexInLocalTemporaryMode(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -22,7 +22,6 @@ import (
 	"unsafe"
 
 	"github.com/pingcap/tidb/expression"
-	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -277,6 +276,9 @@ func (e *groupConcatDistinct) GetTruncated() *int32 {
 type sortRow struct {
 	buffer  *bytes.Buffer
 	byItems []*types.Datum
+	// key is the encoded distinct key of the row, it's only set by groupConcatDistinctOrder.
+	// Its memory is tracked by the valSet.
+	key string
 }
 
 type topNRows struct {
@@ -288,12 +290,7 @@ type topNRows struct {
 	currSize  uint64
 	limitSize uint64
 	sepSize   uint64
-	// If sep is truncated, we need to append part of sep to result.
-	// In the following example, session.group_concat_max_len is 10 and sep is '---'.
-	// ('---', 'ccc') should be poped from heap, so '-' should be appended to result.
-	// eg: 'aaa---bbb---ccc' -> 'aaa---bbb-'
-	isSepTruncated bool
-	collators      []collate.Collator
+	collators []collate.Collator
 }
 
 func (h topNRows) Len() int {
@@ -342,32 +339,35 @@ func (h *topNRows) tryToAdd(row sortRow) (truncated bool, memDelta int64) {
 		h.currSize += h.sepSize
 	}
 	heap.Push(h, row)
-	memDelta += int64(row.buffer.Cap())
-	for _, dt := range row.byItems {
-		memDelta += GetDatumMemSize(dt)
-	}
+	memDelta += sortRowMemSize(row)
 	if h.currSize <= h.limitSize {
 		return false, memDelta
 	}
 
-	for h.currSize > h.limitSize {
-		debt := h.currSize - h.limitSize
-		if uint64(h.rows[0].buffer.Len()) > debt {
-			h.currSize -= debt
-			h.rows[0].buffer.Truncate(h.rows[0].buffer.Len() - int(debt))
-		} else {
-			h.currSize -= uint64(h.rows[0].buffer.Len()) + h.sepSize
-			memDelta -= int64(h.rows[0].buffer.Cap())
-			for _, dt := range h.rows[0].byItems {
-				memDelta -= GetDatumMemSize(dt)
-			}
-			heap.Pop(h)
-			h.isSepTruncated = true
+	// Only pop the rows which are not needed by the result. The last row of the result is kept
+	// untruncated and the result is truncated in concat, so the rows can still be merged correctly.
+	// In the following example, session.group_concat_max_len is 10 and sep is '---'.
+	// 'ccc' is kept and the result is 'aaa---bbb---ccc' -> 'aaa---bbb-'.
+	for len(h.rows) > 1 {
+		restSize := h.currSize - uint64(h.rows[0].buffer.Len()) - h.sepSize
+		if restSize < h.limitSize {
+			break
 		}
+		h.currSize = restSize
+		memDelta -= sortRowMemSize(h.rows[0])
+		heap.Pop(h)
 	}
 	return true, memDelta
 }
 
+func sortRowMemSize(row sortRow) (memSize int64) {
+	memSize += int64(row.buffer.Cap())
+	for _, dt := range row.byItems {
+		memSize += GetDatumMemSize(dt)
+	}
+	return memSize
+}
+
 func (h *topNRows) reset() {
 	h.rows = h.rows[:0]
 	h.err = nil
@@ -383,11 +383,8 @@ func (h *topNRows) concat(sep string, truncated bool) string {
 		}
 		buffer.Write(row.buffer.Bytes())
 	}
-	if h.isSepTruncated {
-		buffer.WriteString(sep)
-		if uint64(buffer.Len()) > h.limitSize {
-			buffer.Truncate(int(h.limitSize))
-		}
+	if uint64(buffer.Len()) > h.limitSize {
+		buffer.Truncate(int(h.limitSize))
 	}
 	return buffer.String()
 }
@@ -419,12 +416,11 @@ func (e *groupConcatOrder) AllocPartialResult() (pr PartialResult, memDelta int6
 	}
 	p := &partialResult4GroupConcatOrder{
 		topN: &topNRows{
-			desc:           desc,
-			currSize:       0,
-			limitSize:      e.maxLen,
-			sepSize:        uint64(len(e.sep)),
-			isSepTruncated: false,
-			collators:      ctors,
+			desc:      desc,
+			currSize:  0,
+			limitSize: e.maxLen,
+			sepSize:   uint64(len(e.sep)),
+			collators: ctors,
 		},
 	}
 	return PartialResult(p), DefPartialResult4GroupConcatOrderSize + DefTopNRowsSize
@@ -480,9 +476,21 @@ func (e *groupConcatOrder) UpdatePartialResult(sctx sessionctx.Context, rowsInGr
 }
 
 func (e *groupConcatOrder) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	// If order by exists, the parallel hash aggregation is forbidden in executorBuilder.buildHashAgg.
-	// So MergePartialResult will not be called.
-	return 0, plannercore.ErrInternal.GenWithStack("groupConcatOrder.MergePartialResult should not be called")
+	p1, p2 := (*partialResult4GroupConcatOrder)(src), (*partialResult4GroupConcatOrder)(dst)
+	p2.topN.sctx = sctx
+	for _, row := range p1.topN.rows {
+		truncated, sortRowMemSize := p2.topN.tryToAdd(row)
+		memDelta += sortRowMemSize
+		if p2.topN.err != nil {
+			return memDelta, p2.topN.err
+		}
+		if truncated {
+			if err := e.handleTruncateError(sctx); err != nil {
+				return memDelta, err
+			}
+		}
+	}
+	return memDelta, nil
 }
 
 // SetTruncated will be called in `executorBuilder#buildHashAgg` with duck-type.
@@ -525,12 +533,11 @@ func (e *groupConcatDistinctOrder) AllocPartialResult() (pr PartialResult, memDe
 	valSet, setSize := set.NewStringSetWithMemoryUsage()
 	p := &partialResult4GroupConcatOrderDistinct{
 		topN: &topNRows{
-			desc:           desc,
-			currSize:       0,
-			limitSize:      e.maxLen,
-			sepSize:        uint64(len(e.sep)),
-			isSepTruncated: false,
-			collators:      ctors,
+			desc:      desc,
+			currSize:  0,
+			limitSize: e.maxLen,
+			sepSize:   uint64(len(e.sep)),
+			collators: ctors,
 		},
 		valSet: valSet,
 	}
@@ -580,6 +587,7 @@ func (e *groupConcatDistinctOrder) UpdatePartialResult(sctx sessionctx.Context,
 		sortRow := sortRow{
 			buffer:  buffer,
 			byItems: make([]*types.Datum, 0, len(e.byItems)),
+			key:     joinedVal,
 		}
 		for _, byItem := range e.byItems {
 			d, err := byItem.Expr.Eval(row)
@@ -603,9 +611,27 @@ func (e *groupConcatDistinctOrder) UpdatePartialResult(sctx sessionctx.Context,
 }
 
 func (e *groupConcatDistinctOrder) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	// If order by exists, the parallel hash aggregation is forbidden in executorBuilder.buildHashAgg.
-	// So MergePartialResult will not be called.
-	return 0, plannercore.ErrInternal.GenWithStack("groupConcatDistinctOrder.MergePartialResult should not be called")
+	p1, p2 := (*partialResult4GroupConcatOrderDistinct)(src), (*partialResult4GroupConcatOrderDistinct)(dst)
+	p2.topN.sctx = sctx
+	// The rows popped from the src heap are not needed: the rows ahead of them in the src heap
+	// are merged into dst, so they can't be in the result of dst either.
+	for _, row := range p1.topN.rows {
+		if p2.valSet.Exist(row.key) {
+			continue
+		}
+		memDelta += p2.valSet.Insert(row.key)
+		truncated, sortRowMemSize := p2.topN.tryToAdd(row)
+		memDelta += sortRowMemSize
+		if p2.topN.err != nil {
+			return memDelta, p2.topN.err
+		}
+		if truncated {
+			if err := e.handleTruncateError(sctx); err != nil {
+				return memDelta, err
+			}
+		}
+	}
+	return memDelta, nil
 }
 
 // GetDatumMemSize calculates the memory size of each types.Datum in sortRow.byItems.
@@ -20,6 +20,8 @@ import (
 	"testing"
 
 	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/util"
@@ -38,6 +40,49 @@ func TestMergePartialResult4GroupConcat(t *testing.T) {
 	testMergePartialResult(t, test)
 }
 
+func TestMergePartialResult4GroupConcatOrder(t *testing.T) {
+	ctx := mock.NewContext()
+	ctx.GetSessionVars().StmtCtx.TruncateAsWarning = true
+	err := variable.SetSessionSystemVar(ctx.GetSessionVars(), variable.GroupConcatMaxLen, "8")
+	require.NoError(t, err)
+
+	ft := types.NewFieldType(mysql.TypeString)
+	col := &expression.Column{RetType: ft, Index: 0}
+	args := []expression.Expression{col, &expression.Constant{Value: types.NewStringDatum(separator), RetType: ft}}
+	for _, distinct := range []bool{false, true} {
+		desc, err := aggregation.NewAggFuncDesc(ctx, ast.AggFuncGroupConcat, args, distinct)
+		require.NoError(t, err)
+		desc.OrderByItems = []*util.ByItems{{Expr: col}}
+		partialDesc, finalDesc := desc.Split([]int{0})
+		partialFunc := aggfuncs.Build(ctx, partialDesc, 0)
+		finalFunc := aggfuncs.Build(ctx, finalDesc, 0)
+		finalPr, _ := finalFunc.AllocPartialResult()
+		for _, vals := range [][]string{{"bbb", "aaa"}, {"ccc", "aaa"}} {
+			srcChk := chunk.NewChunkWithCapacity([]*types.FieldType{ft}, len(vals))
+			rows := make([]chunk.Row, 0, len(vals))
+			for i, val := range vals {
+				srcChk.AppendString(0, val)
+				rows = append(rows, srcChk.GetRow(i))
+			}
+			partialPr, _ := partialFunc.AllocPartialResult()
+			_, err = partialFunc.UpdatePartialResult(ctx, rows, partialPr)
+			require.NoError(t, err)
+			_, err = finalFunc.MergePartialResult(ctx, partialPr, finalPr)
+			require.NoError(t, err)
+		}
+		resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{ft}, 1)
+		err = finalFunc.AppendFinalResult2Chunk(ctx, finalPr, resultChk)
+		require.NoError(t, err)
+		// The rows are truncated by group_concat_max_len after being merged.
+		expected := "aaa aaa "
+		if distinct {
+			expected = "aaa bbb "
+		}
+		require.Equal(t, expected, resultChk.GetRow(0).GetString(0))
+	}
+	require.Len(t, ctx.GetSessionVars().StmtCtx.GetWarnings(), 2)
+}
+
 func TestGroupConcat(t *testing.T) {
 	ctx := mock.NewContext()
 
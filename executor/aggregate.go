@@ -97,8 +97,28 @@ type HashAggPartialWorker struct {
 	// chk stores the input data from child,
 	// and is reused by childExec and partial worker.
 	chk *chunk.Chunk
+	// inputRows is the number of rows aggregated before deciding whether to bypass the partial aggregation.
+	inputRows int
+	// bypassed indicates the group keys are nearly unique, so the partial results are sent to the final
+	// workers for each input chunk instead of being accumulated in partialResultsMap.
+	bypassed bool
+	// partialResultsMemUsage is the memory consumed by partialResultsMap beyond its initial bucket, it's
+	// released when partialResultsMap is handed over to the final workers in the bypass mode.
+	partialResultsMemUsage int64
+	// parallelSpill is not nil if the data may spill. The input chunks are left to the unparallel execution
+	// once the data spills.
+	parallelSpill *hashAggParallelSpill
 }
 
+const (
+	// partialAggBypassCheckRows is the number of input rows a partial worker aggregates before checking
+	// whether the partial aggregation reduces the data.
+	partialAggBypassCheckRows = 8192
+	// partialAggBypassRatio is the ratio of the number of groups to the number of input rows above which
+	// the partial aggregation is considered to be pure overhead.
+	partialAggBypassRatio = 0.9
+)
+
 // HashAggFinalWorker indicates the final workers of parallel hash agg execution,
 // the number of the worker can be set by `tidb_hashagg_final_concurrency`.
 type HashAggFinalWorker struct {
@@ -112,6 +132,9 @@ type HashAggFinalWorker struct {
 	outputCh            chan *AfFinalResult
 	finalResultHolderCh chan *chunk.Chunk
 	groupKeys           [][]byte
+	// parallelSpill is not nil if the data may spill. The final results are not returned if the parallel
+	// execution is stopped, since the partial results are taken over by the unparallel execution.
+	parallelSpill *hashAggParallelSpill
 }
 
 // AfFinalResult indicates aggregation functions final result.
@@ -218,6 +241,17 @@ type HashAggExec struct {
 	spillAction *AggSpillDiskAction
 	// isChildDrained indicates whether the all data from child has been taken out.
 	isChildDrained bool
+
+	// spillByPartition indicates whether the unprocessed data is spilled by the hash partitions of group keys.
+	// If so, the spilled partitions are aggregated in parallel after all the data in memory has been returned,
+	// and a partition is recursively repartitioned if it still exceeds the memory quota. The parallel execution
+	// is stopped by parallelSpill when the data spills, and falls back to the unparallel execution.
+	spillByPartition bool
+	spillPartitions  *hashAggSpillPartitions
+	spillMerger      *hashAggSpillMerger
+	parallelSpill    *hashAggParallelSpill
+	// pendingChks are the chunks of the child which are fetched but not aggregated by the parallel execution.
+	pendingChks []*chunk.Chunk
 }
 
 // HashAggInput indicates the input of hash agg exec.
@@ -261,6 +295,9 @@ func (e *HashAggExec) Close() error {
 		if e.listInDisk != nil {
 			firstErr = e.listInDisk.Close()
 		}
+		if err := e.closeSpillPartitions(); firstErr == nil {
+			firstErr = err
+		}
 		e.spillAction, e.tmpChkForSpill = nil, nil
 		if err := e.baseExecutor.Close(); firstErr == nil {
 			firstErr = err
@@ -295,7 +332,17 @@ func (e *HashAggExec) Close() error {
 			e.memTracker.ReplaceBytesUsed(0)
 		}
 	}
-	return e.baseExecutor.Close()
+	var firstErr error
+	if e.spillByPartition {
+		e.childResult, e.partialResultMap, e.pendingChks = nil, nil, nil
+		e.parallelSpill = nil
+		firstErr = e.closeSpillPartitions()
+		e.spillAction, e.tmpChkForSpill = nil, nil
+	}
+	if err := e.baseExecutor.Close(); firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
 }
 
 // Open implements the Executor Open interface.
@@ -334,19 +381,22 @@ func (e *HashAggExec) initForUnparallelExec() {
 	failpoint.Inject("ConsumeRandomPanic", nil)
 	e.memTracker.Consume(defBucketMemoryUsage*(1<<e.bInMap) + setSize)
 	e.groupKeyBuffer = make([][]byte, 0, 8)
-	e.childResult = newFirstChunk(e.children[0])
+	e.childResult = tryNewCacheChunk(e.children[0])
 	e.memTracker.Consume(e.childResult.MemoryUsage())
 
 	e.offsetOfSpilledChks, e.numOfSpilledChks = 0, 0
 	e.executed, e.isChildDrained = false, false
 	e.listInDisk = chunk.NewListInDisk(retTypes(e.children[0]))
-	e.tmpChkForSpill = newFirstChunk(e.children[0])
+	e.tmpChkForSpill = tryNewCacheChunk(e.children[0])
 	if e.ctx.GetSessionVars().TrackAggregateMemoryUsage && config.GetGlobalConfig().OOMUseTmpStorage {
 		e.diskTracker = disk.NewTracker(e.id, -1)
 		e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
 		e.listInDisk.GetDiskTracker().AttachTo(e.diskTracker)
 		e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewActionForSoftLimit(e.ActionSpill())
 	}
+	if e.spillByPartition {
+		e.spillPartitions = newHashAggSpillPartitions(retTypes(e.children[0]), 0, e.maxChunkSize, e.diskTracker)
+	}
 }
 
 func closeBaseExecutor(b *baseExecutor) {
@@ -380,6 +430,15 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	e.finalWorkers = make([]HashAggFinalWorker, finalConcurrency)
 	e.initRuntimeStats()
 
+	e.parallelSpill = nil
+	if e.spillByPartition {
+		e.parallelSpill = &hashAggParallelSpill{inSpillMode: &e.inSpillMode}
+		e.diskTracker = disk.NewTracker(e.id, -1)
+		e.diskTracker.AttachTo(sessionVars.StmtCtx.DiskTracker)
+		e.spillPartitions = newHashAggSpillPartitions(retTypes(e.children[0]), 0, e.maxChunkSize, e.diskTracker)
+		sessionVars.StmtCtx.MemTracker.FallbackOldAndSetNewActionForSoftLimit(e.ActionSpill())
+	}
+
 	// Init partial workers.
 	for i := 0; i < partialConcurrency; i++ {
 		w := HashAggPartialWorker{
@@ -392,6 +451,7 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 			groupByItems:      e.GroupByItems,
 			chk:               newFirstChunk(e.children[0]),
 			groupKey:          make([][]byte, 0, 8),
+			parallelSpill:     e.parallelSpill,
 		}
 		// There is a bucket in the empty partialResultsMap.
 		failpoint.Inject("ConsumeRandomPanic", nil)
@@ -423,6 +483,7 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 			rowBuffer:           make([]types.Datum, 0, e.Schema().Len()),
 			mutableRow:          chunk.MutRowFromTypes(retTypes(e)),
 			groupKeys:           make([][]byte, 0, 8),
+			parallelSpill:       e.parallelSpill,
 		}
 		// There is a bucket in the empty partialResultsMap.
 		e.memTracker.Consume(defBucketMemoryUsage*(1<<w.BInMap) + setSize)
@@ -485,6 +546,10 @@ func (w *HashAggPartialWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitG
 		if !ok {
 			return
 		}
+		if w.parallelSpill.needStop() {
+			w.parallelSpill.stop(w.chk.CopyConstruct())
+			continue
+		}
 		execStart := time.Now()
 		if err := w.updatePartialResult(ctx, sc, w.chk, len(w.partialResultsMap)); err != nil {
 			w.globalOutputCh <- &AfFinalResult{err: err}
@@ -494,6 +559,25 @@ func (w *HashAggPartialWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitG
 			w.stats.ExecTime += int64(time.Since(execStart))
 			w.stats.TaskNum += 1
 		}
+		if !w.bypassed {
+			w.inputRows += w.chk.NumRows()
+			w.bypassed = w.inputRows >= partialAggBypassCheckRows &&
+				float64(len(w.partialResultsMap)) >= partialAggBypassRatio*float64(w.inputRows)
+			if w.bypassed && w.stats != nil {
+				w.stats.Bypassed = true
+			}
+		}
+		if w.bypassed {
+			// Hand over the partial results to the final workers immediately, since keeping them
+			// in the map only costs memory without merging rows.
+			w.shuffleIntermData(sc, finalConcurrency)
+			w.partialResultsMap = make(aggPartialResultMapper)
+			w.BInMap = 0
+			w.memTracker.Consume(-w.partialResultsMemUsage)
+			w.partialResultsMemUsage = 0
+			needShuffle = false
+			continue
+		}
 		// The intermData can be promised to be not empty if reaching here,
 		// so we set needShuffle to be true.
 		needShuffle = true
@@ -518,7 +602,7 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 		return err
 	}
 
-	partialResults := w.getPartialResult(sc, w.groupKey, w.partialResultsMap)
+	partialResults, mapMemDelta := w.getPartialResult(sc, w.groupKey, w.partialResultsMap)
 	numRows := chk.NumRows()
 	rows := make([]chunk.Row, 1)
 	allMemDelta := int64(0)
@@ -533,6 +617,7 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 		}
 	}
 	w.memTracker.Consume(allMemDelta)
+	w.partialResultsMemUsage += mapMemDelta + allMemDelta
 	return nil
 }
 
@@ -597,10 +682,12 @@ func getGroupKey(ctx sessionctx.Context, input *chunk.Chunk, groupKey [][]byte,
 	return groupKey, nil
 }
 
-func (w *baseHashAggWorker) getPartialResult(sc *stmtctx.StatementContext, groupKey [][]byte, mapper aggPartialResultMapper) [][]aggfuncs.PartialResult {
+// getPartialResult gets or allocates the partial results of the group keys in mapper, it returns the memory
+// consumed for mapper along with the partial results.
+func (w *baseHashAggWorker) getPartialResult(sc *stmtctx.StatementContext, groupKey [][]byte, mapper aggPartialResultMapper) ([][]aggfuncs.PartialResult, int64) {
 	n := len(groupKey)
 	partialResults := make([][]aggfuncs.PartialResult, n)
-	allMemDelta := int64(0)
+	allMemDelta, bucketMemDelta := int64(0), int64(0)
 	for i := 0; i < n; i++ {
 		var ok bool
 		if partialResults[i], ok = mapper[string(groupKey[i])]; ok {
@@ -616,12 +703,13 @@ func (w *baseHashAggWorker) getPartialResult(sc *stmtctx.StatementContext, group
 		// Map will expand when count > bucketNum * loadFactor. The memory usage will doubled.
 		if len(mapper) > (1<<w.BInMap)*hack.LoadFactorNum/hack.LoadFactorDen {
 			w.memTracker.Consume(defBucketMemoryUsage * (1 << w.BInMap))
+			bucketMemDelta += defBucketMemoryUsage * (1 << w.BInMap)
 			w.BInMap++
 		}
 	}
 	failpoint.Inject("ConsumeRandomPanic", nil)
 	w.memTracker.Consume(allMemDelta)
-	return partialResults
+	return partialResults, allMemDelta + bucketMemDelta
 }
 
 func (w *HashAggFinalWorker) getPartialInput() (input *HashAggIntermData, ok bool) {
@@ -668,15 +756,33 @@ func (w *HashAggFinalWorker) consumeIntermData(sctx sessionctx.Context) (err err
 			}
 			failpoint.Inject("ConsumeRandomPanic", nil)
 			w.memTracker.Consume(getGroupKeyMemUsage(w.groupKeys) - memSize)
-			finalPartialResults := w.getPartialResult(sc, w.groupKeys, w.partialResultMap)
+			spilled := w.parallelSpill.needStop() || w.parallelSpill.isStopped()
+			var finalPartialResults [][]aggfuncs.PartialResult
+			if !spilled {
+				finalPartialResults, _ = w.getPartialResult(sc, w.groupKeys, w.partialResultMap)
+			}
 			allMemDelta := int64(0)
 			for i, groupKey := range groupKeys {
+				prs := intermDataBuffer[i]
+				var dst []aggfuncs.PartialResult
+				if spilled {
+					// The partial results of the new groups are taken over instead of being merged into the
+					// newly allocated ones. Their memory has been tracked by the partial workers, so the
+					// memory doesn't grow after the data spills.
+					var ok bool
+					if dst, ok = w.partialResultMap[groupKey]; !ok {
+						w.groupSet.Insert(groupKey)
+						w.partialResultMap[groupKey] = prs
+						continue
+					}
+				} else {
+					dst = finalPartialResults[i]
+				}
 				if !w.groupSet.Exist(groupKey) {
 					allMemDelta += w.groupSet.Insert(groupKey)
 				}
-				prs := intermDataBuffer[i]
 				for j, af := range w.aggFuncs {
-					memDelta, err := af.MergePartialResult(sctx, prs[j], finalPartialResults[i][j])
+					memDelta, err := af.MergePartialResult(sctx, prs[j], dst[j])
 					if err != nil {
 						return err
 					}
@@ -709,7 +815,7 @@ func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
 	}
 	failpoint.Inject("ConsumeRandomPanic", nil)
 	w.memTracker.Consume(getGroupKeyMemUsage(w.groupKeys) - memSize)
-	partialResults := w.getPartialResult(sctx.GetSessionVars().StmtCtx, w.groupKeys, w.partialResultMap)
+	partialResults, _ := w.getPartialResult(sctx.GetSessionVars().StmtCtx, w.groupKeys, w.partialResultMap)
 	for i := 0; i < len(w.groupSet.StringSet); i++ {
 		for j, af := range w.aggFuncs {
 			if err := af.AppendFinalResult2Chunk(sctx, partialResults[i][j], result); err != nil {
@@ -756,13 +862,16 @@ func (w *HashAggFinalWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitGro
 	if err := w.consumeIntermData(ctx); err != nil {
 		w.outputCh <- &AfFinalResult{err: err}
 	}
+	if w.parallelSpill.isStopped() {
+		return
+	}
 	w.getFinalResult(ctx)
 }
 
 // Next implements the Executor Next interface.
 func (e *HashAggExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
-	if e.isUnparallelExec {
+	if e.isUnparallelExec || e.parallelSpill.isStopped() {
 		return e.unparallelExec(ctx, req)
 	}
 	return e.parallelExec(ctx, req)
@@ -785,6 +894,12 @@ func (e *HashAggExec) fetchChildData(ctx context.Context, waitGroup *sync.WaitGr
 		waitGroup.Done()
 	}()
 	for {
+		// Stop fetching once the data spills, the rest rows of the child are aggregated by the
+		// unparallel execution.
+		if e.parallelSpill.needStop() {
+			e.parallelSpill.stop(nil)
+			return
+		}
 		select {
 		case <-e.finishCh:
 			return
@@ -887,6 +1002,10 @@ func (e *HashAggExec) parallelExec(ctx context.Context, chk *chunk.Chunk) error
 	for {
 		result, ok := <-e.finalOutputCh
 		if !ok {
+			if e.parallelSpill.isStopped() {
+				e.fallbackToUnparallelExec()
+				return e.unparallelExec(ctx, chk)
+			}
 			e.executed = true
 			if e.isChildReturnEmpty && e.defaultVal != nil {
 				chk.Append(e.defaultVal, 0, 1)
@@ -906,9 +1025,47 @@ func (e *HashAggExec) parallelExec(ctx context.Context, chk *chunk.Chunk) error
 	}
 }
 
+// fallbackToUnparallelExec is called after all the workers of the parallel execution exit if it's stopped by spilling.
+// The groups of the final workers are taken over by the unparallel execution, which aggregates the rest rows
+// of the child and spills the rows of the new groups by partitions. The partial results of PartialAggFuncs
+// and FinalAggFuncs are compatible, so they are updated by the former and returned by the latter.
+func (e *HashAggExec) fallbackToUnparallelExec() {
+	// The memory of the groups has been tracked by the final workers.
+	e.groupSet, _ = set.NewStringSetWithMemoryUsage()
+	e.partialResultMap = make(aggPartialResultMapper)
+	e.groupKeys, e.cursor4GroupKey = e.groupKeys[:0], 0
+	for i := range e.finalWorkers {
+		w := &e.finalWorkers[i]
+		for groupKey := range w.groupSet.StringSet {
+			e.groupSet.Insert(groupKey)
+			e.groupKeys = append(e.groupKeys, groupKey)
+			e.partialResultMap[groupKey] = w.partialResultMap[groupKey]
+		}
+		w.groupSet, w.partialResultMap = set.StringSetWithMemoryUsage{}, nil
+	}
+	for e.bInMap = 0; len(e.partialResultMap) > (1<<e.bInMap)*hack.LoadFactorNum/hack.LoadFactorDen; e.bInMap++ {
+	}
+	e.pendingChks = e.parallelSpill.pendingChks
+	e.groupKeyBuffer = make([][]byte, 0, 8)
+	e.childResult = tryNewCacheChunk(e.children[0])
+	e.tmpChkForSpill = tryNewCacheChunk(e.children[0])
+	e.prepared, e.executed, e.isChildDrained = false, false, false
+}
+
+// outputAggFuncs returns the aggregate functions which return the final results in the unparallel execution.
+func (e *HashAggExec) outputAggFuncs() []aggfuncs.AggFunc {
+	if e.parallelSpill.isStopped() {
+		return e.FinalAggFuncs
+	}
+	return e.PartialAggFuncs
+}
+
 // unparallelExec executes hash aggregation algorithm in single thread.
 func (e *HashAggExec) unparallelExec(ctx context.Context, chk *chunk.Chunk) error {
 	chk.Reset()
+	if e.spillMerger != nil {
+		return e.spillMerger.next(chk)
+	}
 	for {
 		if e.prepared {
 			// Since we return e.maxChunkSize rows every time, so we should not traverse
@@ -918,7 +1075,7 @@ func (e *HashAggExec) unparallelExec(ctx context.Context, chk *chunk.Chunk) erro
 				if len(e.PartialAggFuncs) == 0 {
 					chk.SetNumVirtualRows(chk.NumRows() + 1)
 				}
-				for i, af := range e.PartialAggFuncs {
+				for i, af := range e.outputAggFuncs() {
 					if err := af.AppendFinalResult2Chunk(e.ctx, partialResults[i], chk); err != nil {
 						return err
 					}
@@ -928,6 +1085,9 @@ func (e *HashAggExec) unparallelExec(ctx context.Context, chk *chunk.Chunk) erro
 					return nil
 				}
 			}
+			if e.spillByPartition {
+				return e.mergeSpillPartitions(ctx, chk)
+			}
 			e.resetSpillMode()
 		}
 		if e.executed {
@@ -968,6 +1128,9 @@ func (e *HashAggExec) execute(ctx context.Context) (err error) {
 			err = e.listInDisk.Add(e.tmpChkForSpill)
 			e.tmpChkForSpill.Reset()
 		}
+		if e.spillPartitions != nil && err == nil {
+			err = e.spillPartitions.flush()
+		}
 	}()
 	for {
 		mSize := e.childResult.MemoryUsage()
@@ -1018,7 +1181,13 @@ func (e *HashAggExec) execute(ctx context.Context) (err error) {
 		}
 
 		// spill unprocessed data when exceeded.
-		if len(sel) > 0 {
+		if len(sel) > 0 && e.spillByPartition {
+			for _, j := range sel {
+				if err = e.spillPartitions.spillRow(e.groupKeyBuffer[j], e.childResult.GetRow(j)); err != nil {
+					return err
+				}
+			}
+		} else if len(sel) > 0 {
 			e.childResult.SetSel(sel)
 			err = e.spillUnprocessedData(len(sel) == cap(sel))
 			if err != nil {
@@ -1050,6 +1219,11 @@ func (e *HashAggExec) spillUnprocessedData(isFullChk bool) (err error) {
 
 func (e *HashAggExec) getNextChunk(ctx context.Context) (err error) {
 	e.childResult.Reset()
+	if len(e.pendingChks) > 0 {
+		e.childResult.SwapColumns(e.pendingChks[0])
+		e.pendingChks = e.pendingChks[1:]
+		return nil
+	}
 	if !e.isChildDrained {
 		if err := Next(ctx, e.children[0], e.childResult); err != nil {
 			return err
@@ -1128,6 +1302,8 @@ type AggWorkerStat struct {
 	WaitTime   int64
 	ExecTime   int64
 	WorkerTime int64
+	// Bypassed indicates the partial worker bypassed the partial aggregation.
+	Bypassed bool
 }
 
 // Clone implements the RuntimeStats interface.
@@ -1137,16 +1313,20 @@ func (w *AggWorkerStat) Clone() *AggWorkerStat {
 		WaitTime:   w.WaitTime,
 		ExecTime:   w.ExecTime,
 		WorkerTime: w.WorkerTime,
+		Bypassed:   w.Bypassed,
 	}
 }
 
 func (e *HashAggRuntimeStats) workerString(buf *bytes.Buffer, prefix string, concurrency int, wallTime int64, workerStats []*AggWorkerStat) {
-	var totalTime, totalWait, totalExec, totalTaskNum int64
+	var totalTime, totalWait, totalExec, totalTaskNum, bypassedNum int64
 	for _, w := range workerStats {
 		totalTime += w.WorkerTime
 		totalWait += w.WaitTime
 		totalExec += w.ExecTime
 		totalTaskNum += w.TaskNum
+		if w.Bypassed {
+			bypassedNum++
+		}
 	}
 	buf.WriteString(prefix)
 	buf.WriteString(fmt.Sprintf("_worker:{wall_time:%s, concurrency:%d, task_num:%d, tot_wait:%s, tot_exec:%s, tot_time:%s",
@@ -1157,6 +1337,9 @@ func (e *HashAggRuntimeStats) workerString(buf *bytes.Buffer, prefix string, con
 		buf.WriteString(fmt.Sprintf(", max:%v, p95:%v",
 			time.Duration(workerStats[n-1].WorkerTime), time.Duration(workerStats[n*19/20].WorkerTime)))
 	}
+	if bypassedNum > 0 {
+		buf.WriteString(fmt.Sprintf(", bypassed:%d", bypassedNum))
+	}
 	buf.WriteString("}")
 }
 
@@ -1244,7 +1427,7 @@ func (e *StreamAggExec) Open(ctx context.Context) error {
 	// If panic in Open, the children executor should be closed because they are open.
 	defer closeBaseExecutor(&e.baseExecutor)
 
-	e.childResult = newFirstChunk(e.children[0])
+	e.childResult = tryNewCacheChunk(e.children[0])
 	e.executed = false
 	e.isChildReturnEmpty = true
 	e.inputIter = chunk.NewIterator4Chunk(e.childResult)
@@ -1893,7 +2076,8 @@ func (e *HashAggExec) ActionSpill() *AggSpillDiskAction {
 // maxSpillTimes indicates how many times the data can spill at most.
 const maxSpillTimes = 10
 
-// AggSpillDiskAction implements memory.ActionOnExceed for unparalleled HashAgg.
+// AggSpillDiskAction implements memory.ActionOnExceed for unparalleled HashAgg, and parallel HashAgg
+// which spills by partitions.
 // If the memory quota of a query is exceeded, AggSpillDiskAction.Action is
 // triggered.
 type AggSpillDiskAction struct {
@@ -0,0 +1,439 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/set"
+	"github.com/twmb/murmur3"
+)
+
+const (
+	// aggSpillPartitionNum is the number of partitions the spilled rows are divided into at every level.
+	aggSpillPartitionNum = 8
+	// maxAggSpillPartitionLevel is the max level of the recursive repartitioning. The partitions at
+	// the max level are aggregated in memory no matter how much memory they take.
+	maxAggSpillPartitionLevel = 4
+)
+
+// hashAggSpillPartitions holds the spilled rows of the hash aggregation. The rows are divided by the
+// hash of their group keys, so every partition contains different groups and can be aggregated alone.
+type hashAggSpillPartitions struct {
+	// level is used as the seed of the hash function, so the rows of a partition are divided into
+	// different sub-partitions when it is repartitioned.
+	level        int
+	fieldTypes   []*types.FieldType
+	maxChunkSize int
+	diskTracker  *disk.Tracker
+
+	lists   []*chunk.ListInDisk
+	tmpChks []*chunk.Chunk
+}
+
+func newHashAggSpillPartitions(fieldTypes []*types.FieldType, level int, maxChunkSize int, diskTracker *disk.Tracker) *hashAggSpillPartitions {
+	return &hashAggSpillPartitions{
+		level:        level,
+		fieldTypes:   fieldTypes,
+		maxChunkSize: maxChunkSize,
+		diskTracker:  diskTracker,
+		lists:        make([]*chunk.ListInDisk, aggSpillPartitionNum),
+		tmpChks:      make([]*chunk.Chunk, aggSpillPartitionNum),
+	}
+}
+
+// spillRow appends the row to the partition of its group key.
+func (p *hashAggSpillPartitions) spillRow(groupKey []byte, row chunk.Row) error {
+	idx := int(murmur3.SeedSum64(uint64(p.level), groupKey) % aggSpillPartitionNum)
+	if p.tmpChks[idx] == nil {
+		p.tmpChks[idx] = chunk.New(p.fieldTypes, p.maxChunkSize, p.maxChunkSize)
+	}
+	p.tmpChks[idx].AppendRow(row)
+	if p.tmpChks[idx].IsFull() {
+		return p.flushPartition(idx)
+	}
+	return nil
+}
+
+func (p *hashAggSpillPartitions) flushPartition(idx int) error {
+	if p.lists[idx] == nil {
+		p.lists[idx] = chunk.NewListInDisk(p.fieldTypes)
+		if p.diskTracker != nil {
+			p.lists[idx].GetDiskTracker().AttachTo(p.diskTracker)
+		}
+	}
+	err := p.lists[idx].Add(p.tmpChks[idx])
+	p.tmpChks[idx].Reset()
+	return err
+}
+
+// flush writes the rows remaining in memory to the disk.
+func (p *hashAggSpillPartitions) flush() error {
+	for idx, chk := range p.tmpChks {
+		if chk == nil || chk.NumRows() == 0 {
+			continue
+		}
+		if err := p.flushPartition(idx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spilledLists returns the partitions which have spilled rows.
+func (p *hashAggSpillPartitions) spilledLists() []*chunk.ListInDisk {
+	lists := make([]*chunk.ListInDisk, 0, len(p.lists))
+	for _, list := range p.lists {
+		if list != nil && list.NumChunks() > 0 {
+			lists = append(lists, list)
+		}
+	}
+	return lists
+}
+
+func (p *hashAggSpillPartitions) close() (firstErr error) {
+	for i, list := range p.lists {
+		if list == nil {
+			continue
+		}
+		if err := list.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		p.lists[i] = nil
+	}
+	p.tmpChks = nil
+	return firstErr
+}
+
+// hashAggParallelSpill is shared by the workers of the parallel execution, which stop aggregating the chunks
+// of the child once the data spills. The chunks fetched by then are left to the unparallel execution, see
+// HashAggExec.fallbackToUnparallelExec.
+type hashAggParallelSpill struct {
+	inSpillMode *uint32
+	// stopped indicates the parallel execution is stopped, it's set before the workers exit.
+	stopped     uint32
+	mu          sync.Mutex
+	pendingChks []*chunk.Chunk
+}
+
+func (s *hashAggParallelSpill) needStop() bool {
+	return s != nil && atomic.LoadUint32(s.inSpillMode) == 1
+}
+
+// stop stops the parallel execution and leaves the chunk to the unparallel execution.
+func (s *hashAggParallelSpill) stop(chk *chunk.Chunk) {
+	if chk != nil {
+		s.mu.Lock()
+		s.pendingChks = append(s.pendingChks, chk)
+		s.mu.Unlock()
+	}
+	atomic.StoreUint32(&s.stopped, 1)
+}
+
+func (s *hashAggParallelSpill) isStopped() bool {
+	return s != nil && atomic.LoadUint32(&s.stopped) == 1
+}
+
+// hashAggSpillMerger aggregates the spilled partitions by several workers in parallel. Every worker
+// takes a partition at a time, and repartitions the rows of new groups to the next level if the
+// memory it uses exceeds its quota, so the groups which can't be held in memory at once are
+// aggregated by several rounds.
+type hashAggSpillMerger struct {
+	e        *HashAggExec
+	finishCh chan struct{}
+	outputCh chan *AfFinalResult
+}
+
+// mergeSpillPartitions is called after all the groups in memory have been returned. It releases the
+// memory of these groups and starts to aggregate the spilled partitions.
+func (e *HashAggExec) mergeSpillPartitions(ctx context.Context, chk *chunk.Chunk) error {
+	e.cursor4GroupKey, e.groupKeys = 0, nil
+	var setSize int64
+	e.groupSet, setSize = set.NewStringSetWithMemoryUsage()
+	e.partialResultMap = make(aggPartialResultMapper)
+	e.bInMap = 0
+	e.memTracker.ReplaceBytesUsed(setSize)
+	e.executed = true
+	atomic.StoreUint32(&e.inSpillMode, 0)
+
+	m := &hashAggSpillMerger{e: e}
+	e.spillMerger = m
+	lists := e.spillPartitions.spilledLists()
+	if len(lists) > 0 {
+		concurrency := e.ctx.GetSessionVars().HashAggFinalConcurrency()
+		if concurrency > len(lists) {
+			concurrency = len(lists)
+		}
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		memQuota := int64(math.MaxInt64)
+		if stmtTracker := e.ctx.GetSessionVars().StmtCtx.MemTracker; stmtTracker.GetBytesLimit() > 0 {
+			// Share the quota left by the other executors, and leave a share of it for them.
+			memQuota = (stmtTracker.GetBytesLimit() - stmtTracker.BytesConsumed()) / int64(concurrency+1)
+		}
+		m.start(ctx, lists, concurrency, memQuota)
+	}
+	if chk.NumRows() > 0 {
+		return nil
+	}
+	return m.next(chk)
+}
+
+func (m *hashAggSpillMerger) start(ctx context.Context, lists []*chunk.ListInDisk, concurrency int, memQuota int64) {
+	m.finishCh = make(chan struct{})
+	m.outputCh = make(chan *AfFinalResult, concurrency)
+	taskCh := make(chan *chunk.ListInDisk, len(lists))
+	for _, list := range lists {
+		taskCh <- list
+	}
+	close(taskCh)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		w := &hashAggSpillMergeWorker{
+			e:          m.e,
+			finishCh:   m.finishCh,
+			outputCh:   m.outputCh,
+			giveBackCh: make(chan *chunk.Chunk, 1),
+			memTracker: memory.NewTracker(m.e.id, -1),
+			memQuota:   memQuota,
+		}
+		w.memTracker.AttachTo(m.e.memTracker)
+		go w.run(ctx, taskCh, wg)
+	}
+	outputCh := m.outputCh
+	go func() {
+		wg.Wait()
+		close(outputCh)
+	}()
+}
+
+// next fetches the aggregated results of the spilled partitions.
+func (m *hashAggSpillMerger) next(chk *chunk.Chunk) error {
+	if m.outputCh == nil {
+		return nil
+	}
+	for {
+		result, ok := <-m.outputCh
+		if !ok {
+			return nil
+		}
+		if result.err != nil {
+			return result.err
+		}
+		chk.SwapColumns(result.chk)
+		result.chk.Reset()
+		result.giveBackCh <- result.chk
+		if chk.NumRows() > 0 {
+			return nil
+		}
+	}
+}
+
+func (m *hashAggSpillMerger) close() {
+	if m.finishCh == nil {
+		return
+	}
+	close(m.finishCh)
+	// Wait for all the workers to exit.
+	for range m.outputCh {
+	}
+	m.finishCh, m.outputCh = nil, nil
+}
+
+func (e *HashAggExec) closeSpillPartitions() error {
+	if e.spillMerger != nil {
+		e.spillMerger.close()
+		e.spillMerger = nil
+	}
+	if e.spillPartitions == nil {
+		return nil
+	}
+	err := e.spillPartitions.close()
+	e.spillPartitions = nil
+	return err
+}
+
+type hashAggSpillMergeWorker struct {
+	e          *HashAggExec
+	finishCh   <-chan struct{}
+	outputCh   chan *AfFinalResult
+	giveBackCh chan *chunk.Chunk
+	memTracker *memory.Tracker
+	// memQuota is the memory the worker can use before it repartitions the rows of new groups.
+	memQuota       int64
+	groupKeyBuffer [][]byte
+}
+
+func (w *hashAggSpillMergeWorker) run(ctx context.Context, taskCh <-chan *chunk.ListInDisk, waitGroup *sync.WaitGroup) {
+	defer func() {
+		if r := recover(); r != nil {
+			recoveryHashAgg(w.outputCh, r)
+		}
+		w.memTracker.Detach()
+		waitGroup.Done()
+	}()
+	w.giveBackCh <- newFirstChunk(w.e)
+	for list := range taskCh {
+		ok, err := w.mergePartition(ctx, list, 0)
+		if err != nil {
+			w.outputCh <- &AfFinalResult{err: err}
+			return
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// needSpill checks whether the rows of new groups should be repartitioned to the next level.
+func (w *hashAggSpillMergeWorker) needSpill(level int, memDelta int64) bool {
+	if level >= maxAggSpillPartitionLevel {
+		return false
+	}
+	return atomic.LoadUint32(&w.e.inSpillMode) == 1 || w.memTracker.BytesConsumed()+memDelta > w.memQuota
+}
+
+// mergePartition aggregates the rows of a spilled partition and sends the results. It returns false
+// if the execution is finished early.
+func (w *hashAggSpillMergeWorker) mergePartition(ctx context.Context, list *chunk.ListInDisk, level int) (ok bool, err error) {
+	e := w.e
+	subPartitions := newHashAggSpillPartitions(retTypes(e.children[0]), level+1, e.maxChunkSize, e.diskTracker)
+	defer func() {
+		if closeErr := subPartitions.close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	groupSet, setSize := set.NewStringSetWithMemoryUsage()
+	partialResultMap := make(aggPartialResultMapper)
+	groupKeys := make([]string, 0, 8)
+	w.memTracker.Consume(setSize)
+	// Once a group is repartitioned, all the new groups are repartitioned. Otherwise the later rows of
+	// the group may be aggregated in memory after the spill mode is reset, and the group is returned twice.
+	spilling := false
+	for i := 0; i < list.NumChunks(); i++ {
+		chk, err := list.GetChunk(i)
+		if err != nil {
+			return false, err
+		}
+		w.groupKeyBuffer, err = getGroupKey(e.ctx, chk, w.groupKeyBuffer, e.GroupByItems)
+		if err != nil {
+			return false, err
+		}
+		memDelta := int64(0)
+		for j := 0; j < chk.NumRows(); j++ {
+			groupKey := string(w.groupKeyBuffer[j])
+			partialResults, ok := partialResultMap[groupKey]
+			if !ok {
+				if spilling || groupSet.Count() > 0 && w.needSpill(level, memDelta) {
+					spilling = true
+					if err = subPartitions.spillRow(w.groupKeyBuffer[j], chk.GetRow(j)); err != nil {
+						return false, err
+					}
+					continue
+				}
+				memDelta += groupSet.Insert(groupKey)
+				groupKeys = append(groupKeys, groupKey)
+				partialResults = make([]aggfuncs.PartialResult, 0, len(e.PartialAggFuncs))
+				for _, af := range e.PartialAggFuncs {
+					partialResult, allocDelta := af.AllocPartialResult()
+					partialResults = append(partialResults, partialResult)
+					memDelta += allocDelta
+				}
+				partialResultMap[groupKey] = partialResults
+				memDelta += int64(len(groupKey))
+			}
+			for k, af := range e.PartialAggFuncs {
+				updateDelta, err := af.UpdatePartialResult(e.ctx, []chunk.Row{chk.GetRow(j)}, partialResults[k])
+				if err != nil {
+					return false, err
+				}
+				memDelta += updateDelta
+			}
+		}
+		w.memTracker.Consume(memDelta)
+	}
+	if err = subPartitions.flush(); err != nil {
+		return false, err
+	}
+	if ok, err = w.sendResults(groupKeys, partialResultMap); !ok || err != nil {
+		return ok, err
+	}
+	// Release the memory of the partition before merging the sub-partitions.
+	w.memTracker.Consume(-w.memTracker.BytesConsumed())
+	atomic.StoreUint32(&e.inSpillMode, 0)
+
+	for _, subList := range subPartitions.spilledLists() {
+		if ok, err = w.mergePartition(ctx, subList, level+1); !ok || err != nil {
+			return ok, err
+		}
+	}
+	return true, nil
+}
+
+func (w *hashAggSpillMergeWorker) sendResults(groupKeys []string, partialResultMap aggPartialResultMapper) (bool, error) {
+	e := w.e
+	var result *chunk.Chunk
+	select {
+	case <-w.finishCh:
+		return false, nil
+	case result = <-w.giveBackCh:
+	}
+	for _, groupKey := range groupKeys {
+		partialResults := partialResultMap[groupKey]
+		if len(e.PartialAggFuncs) == 0 {
+			result.SetNumVirtualRows(result.NumRows() + 1)
+		}
+		for i, af := range e.outputAggFuncs() {
+			if err := af.AppendFinalResult2Chunk(e.ctx, partialResults[i], result); err != nil {
+				return false, err
+			}
+		}
+		if !result.IsFull() {
+			continue
+		}
+		select {
+		case <-w.finishCh:
+			return false, nil
+		case w.outputCh <- &AfFinalResult{chk: result, giveBackCh: w.giveBackCh}:
+		}
+		select {
+		case <-w.finishCh:
+			return false, nil
+		case result = <-w.giveBackCh:
+		}
+	}
+	if result.NumRows() == 0 {
+		w.giveBackCh <- result
+		return true, nil
+	}
+	select {
+	case <-w.finishCh:
+		return false, nil
+	case w.outputCh <- &AfFinalResult{chk: result, giveBackCh: w.giveBackCh}:
+	}
+	return true, nil
+}
@@ -696,6 +696,38 @@ func TestAggPushDown(t *testing.T) {
 	tk.MustQuery("select a, count(b) from (select * from t union all select * from tt) k group by a order by a").Check(testkit.Rows("1 2", "2 1"))
 }
 
+func TestJSONAggNotPushedDown(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int, c varchar(10), d json)")
+	tk.MustExec(`insert into t values (1, 1, 'a', '{"x": 1}'), (2, 1, 'b', '[1, 2]'), (3, 2, 'c', null), (4, 2, 'd', '"s"'), (5, 2, null, '1'), (6, 3, 'f', '{}')`)
+	// Make the partial results of the same group come from different regions.
+	tk.MustQuery("split table t by (3), (5)").Check(testkit.Rows("2 1"))
+
+	// Neither TiKV nor TiFlash implements json_arrayagg and json_objectagg, so they're
+	// evaluated by TiDB.
+	rows := tk.MustQuery("explain format = 'brief' select b, json_arrayagg(c), json_objectagg(c, d) from t group by b").Rows()
+	for _, row := range rows {
+		if strings.Contains(row[4].(string), "json_arrayagg(test.t.c)") {
+			require.Equal(t, "root", row[2])
+		}
+	}
+
+	tk.MustQuery("select b, json_length(json_arrayagg(c)), json_objectagg(a, d) from t group by b order by b").Check(testkit.Rows(
+		`1 2 {"1": {"x": 1}, "2": [1, 2]}`,
+		`2 3 {"3": null, "4": "s", "5": 1}`,
+		`3 1 {"6": {}}`))
+	tk.MustQuery(`select json_contains(json_arrayagg(c), '["a", "b", "c", "d", null, "f"]'), json_length(json_arrayagg(d)) from t`).Check(testkit.Rows("1 6"))
+	tk.MustQuery("select /*+ STREAM_AGG() */ json_objectagg(c, b) from t where c is not null").Check(testkit.Rows(
+		`{"a": 1, "b": 1, "c": 2, "d": 2, "f": 3}`))
+	tk.MustQuery("select json_arrayagg(c), json_objectagg(c, d) from t where a > 10").Check(testkit.Rows("<nil> <nil>"))
+	err := tk.QueryToErr("select json_objectagg(c, d) from t")
+	require.EqualError(t, err, "[json:3158]JSON documents may not contain NULL member names.")
+}
+
 func TestOnlyFullGroupBy(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -1288,6 +1320,10 @@ func TestHashAggRuntimeStat(t *testing.T) {
 	stats.Merge(stats.Clone())
 	expect = "partial_worker:{wall_time:40s, concurrency:5, task_num:50, tot_wait:20s, tot_exec:10s, tot_time:20s, max:4s, p95:4s}, final_worker:{wall_time:20s, concurrency:8, task_num:80, tot_wait:32ms, tot_exec:16ms, tot_time:56ms, max:7ms, p95:7ms}"
 	require.Equal(t, expect, stats.String())
+	stats.PartialStats[0].Bypassed = true
+	stats.PartialStats[1].Bypassed = true
+	expect = "partial_worker:{wall_time:40s, concurrency:5, task_num:50, tot_wait:20s, tot_exec:10s, tot_time:20s, max:4s, p95:4s, bypassed:2}, final_worker:{wall_time:20s, concurrency:8, task_num:80, tot_wait:32ms, tot_exec:16ms, tot_time:56ms, max:7ms, p95:7ms}"
+	require.Equal(t, expect, stats.String())
 }
 
 func reconstructParallelGroupConcatResult(rows [][]interface{}) []string {
@@ -1541,6 +1577,151 @@ func TestAggInDisk(t *testing.T) {
 	tk.MustQuery("select /*+ HASH_AGG() */ count(c) from t group by c1;").Check(testkit.Rows())
 }
 
+func TestParallelHashAggSpill(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set tidb_hashagg_final_concurrency = 4;")
+	tk.MustExec("set tidb_hashagg_partial_concurrency = 4;")
+	tk.MustExec("set tidb_enable_parallel_hashagg_spill = 1")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int)")
+	sql := "insert into t values (0)"
+	for i := 1; i <= 200; i++ {
+		sql += fmt.Sprintf(",(%v)", i)
+	}
+	tk.MustExec(sql)
+	tk.MustExec("insert into t values(0)")
+	for _, quota := range []int{4194304, 2097152} {
+		tk.MustExec(fmt.Sprintf("set tidb_mem_quota_query = %d", quota))
+		rows := tk.MustQuery("explain analyze select /*+ HASH_AGG() */ avg(t1.a) from t t1 join t t2 group by t1.a, t2.a;").Rows()
+		for _, row := range rows {
+			if strings.Contains(fmt.Sprintf("%v", row), "HashAgg") {
+				require.NotEqual(t, "0 Bytes", fmt.Sprintf("%v", row[len(row)-1]))
+			}
+		}
+		tk.MustQuery("select sum(tt.b), count(*) from ( select /*+ HASH_AGG() */ avg(t1.a) as b from t t1 join t t2 group by t1.a, t2.a) as tt").Check(
+			testkit.Rows("4040100.0000 40401"))
+	}
+
+	// No data is spilled.
+	tk.MustExec("set tidb_mem_quota_query = default")
+	tk.MustQuery("select /*+ HASH_AGG() */ a, count(*) from t where a < 2 group by a order by a").Check(testkit.Rows("0 2", "1 1"))
+	tk.MustQuery("select /*+ HASH_AGG() */ count(a) from t where a < 0").Check(testkit.Rows("0"))
+}
+
+func TestParallelHashAggSpillFallback(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set tidb_hashagg_final_concurrency = 4;")
+	tk.MustExec("set tidb_hashagg_partial_concurrency = 4;")
+	tk.MustExec("set tidb_max_chunk_size = 32")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b varchar(10))")
+	sql := "insert into t values (0, '0')"
+	for i := 1; i <= 200; i++ {
+		sql += fmt.Sprintf(",(%v, '%v')", i, i%7)
+	}
+	tk.MustExec(sql)
+	sql = "select /*+ HASH_AGG() */ t1.a, t2.a % 50, count(*), sum(t2.a), avg(t1.a + t2.a), max(t2.b), min(t2.b), " +
+		"bit_xor(t2.a), bit_or(t2.a) from t t1 join t t2 group by t1.a, t2.a % 50"
+	expected := tk.MustQuery(sql).Sort().Rows()
+	require.Len(t, expected, 10050)
+
+	// The aggregation is executed in parallel if no data spills.
+	tk.MustExec("set tidb_enable_parallel_hashagg_spill = 1")
+	rows := tk.MustQuery("explain analyze " + sql).Rows()
+	for _, row := range rows {
+		if strings.Contains(fmt.Sprintf("%v", row[0]), "HashAgg") {
+			require.Contains(t, fmt.Sprintf("%v", row[5]), "partial_worker")
+		}
+	}
+	tk.MustQuery(sql).Sort().Check(expected)
+
+	// The parallel execution falls back to the unparallel execution when the data spills, and no group
+	// is returned twice even if the spilled partitions are repartitioned by several workers.
+	tk.MustExec("set tidb_mem_quota_query = 2097152")
+	for _, concurrency := range []int{4, 8} {
+		tk.MustExec(fmt.Sprintf("set tidb_hashagg_final_concurrency = %d", concurrency))
+		rows = tk.MustQuery("explain analyze " + sql).Rows()
+		for _, row := range rows {
+			if strings.Contains(fmt.Sprintf("%v", row), "HashAgg") {
+				require.NotEqual(t, "0 Bytes", fmt.Sprintf("%v", row[len(row)-1]))
+			}
+		}
+		for i := 0; i < 3; i++ {
+			tk.MustQuery(sql).Sort().Check(expected)
+		}
+	}
+}
+
+func TestGroupConcatDistinctOrderInDisk(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int)")
+	sql := "insert into t values (0)"
+	for i := 1; i <= 200; i++ {
+		sql += fmt.Sprintf(",(%v)", i)
+	}
+	tk.MustExec(sql)
+	sql = "select /*+ HASH_AGG() */ t1.a, group_concat(distinct t2.a % 7, t1.a % 3 order by t2.a % 7 desc separator '') from t t1 join t t2 group by t1.a, t2.a % 5"
+	expected := tk.MustQuery(sql).Sort().Rows()
+	require.Len(t, expected, 1005)
+	require.Equal(t, "60504030201000", expected[0][1])
+
+	tk.MustExec("set tidb_mem_quota_query = 1048576")
+	for _, parallelSpill := range []string{"0", "1"} {
+		tk.MustExec("set tidb_enable_parallel_hashagg_spill = " + parallelSpill)
+		rows := tk.MustQuery("explain analyze " + sql).Rows()
+		for _, row := range rows {
+			if strings.Contains(fmt.Sprintf("%v", row), "HashAgg") {
+				require.NotEqual(t, "0 Bytes", fmt.Sprintf("%v", row[len(row)-1]))
+			}
+		}
+		tk.MustQuery(sql).Sort().Check(expected)
+	}
+}
+
+func TestParallelHashAggBypassPartial(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set tidb_hashagg_final_concurrency = 4;")
+	tk.MustExec("set tidb_hashagg_partial_concurrency = 4;")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int)")
+	sql := "insert into t values (0)"
+	for i := 1; i <= 200; i++ {
+		sql += fmt.Sprintf(",(%v)", i)
+	}
+	tk.MustExec(sql)
+	// The group keys are unique, so the partial workers bypass the partial aggregation.
+	tk.MustQuery("select count(*), sum(tt.b), sum(tt.c) from (select /*+ HASH_AGG() */ avg(t1.a) as b, count(*) as c from t t1 join t t2 group by t1.a, t2.a) as tt").Check(
+		testkit.Rows("40401 4040100.0000 40401"))
+	require.Contains(t, hashAggExecInfo(tk, "select /*+ HASH_AGG() */ avg(t1.a), count(*) from t t1 join t t2 group by t1.a, t2.a"), "bypassed:")
+	// The partial aggregation reduces the data.
+	tk.MustQuery("select count(*), sum(tt.b), sum(tt.c) from (select /*+ HASH_AGG() */ avg(t1.a) as b, count(*) as c from t t1 join t t2 group by t1.a + t2.a) as tt").Check(
+		testkit.Rows("401 40100.0000 40401"))
+	require.NotContains(t, hashAggExecInfo(tk, "select /*+ HASH_AGG() */ avg(t1.a), count(*) from t t1 join t t2 group by t1.a + t2.a"), "bypassed:")
+}
+
+// hashAggExecInfo returns the execution info of the HashAgg executor in the EXPLAIN ANALYZE result of sql.
+func hashAggExecInfo(tk *testkit.TestKit, sql string) string {
+	for _, row := range tk.MustQuery("explain analyze " + sql).Rows() {
+		if strings.Contains(row[0].(string), "HashAgg") {
+			return row[5].(string)
+		}
+	}
+	return ""
+}
+
 func TestRandomPanicAggConsume(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -48,6 +48,7 @@ import (
 	"github.com/pingcap/tidb/statistics"
 	derr "github.com/pingcap/tidb/store/driver/error"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -807,6 +808,8 @@ type AnalyzeColumnsExec struct {
 	schemaForVirtualColEval *expression.Schema
 	baseCount               int64
 	baseModifyCnt           int64
+	// storeType is the store to read the samples from, it's TiFlash if the samples are read from the TiFlash replica.
+	storeType kv.StoreType
 }
 
 func (e *AnalyzeColumnsExec) open(ranges []*ranger.Range) error {
@@ -841,6 +844,7 @@ func (e *AnalyzeColumnsExec) buildResp(ranges []*ranger.Range) (distsql.SelectRe
 		SetStartTS(e.snapshot).
 		SetKeepOrder(true).
 		SetConcurrency(e.concurrency).
+		SetStoreType(e.storeType).
 		Build()
 	if err != nil {
 		return nil, err
@@ -905,6 +909,166 @@ func readDataAndSendTask(handler *tableResultHandler, mergeTaskCh chan []byte) e
 	return nil
 }
 
+// readTiFlashDataAndSendTask scans the rows from the TiFlash replica and samples them in TiDB, because
+// TiFlash only serves the DAG requests. The collected samples are sent to the merge workers in the same
+// format as the results of the analyze requests served by TiKV.
+func (e *AnalyzeColumnsExec) readTiFlashDataAndSendTask(ranges []*ranger.Range, mergeTaskCh chan []byte) (err error) {
+	defer close(mergeTaskCh)
+	result, err := e.buildTiFlashResp(ranges)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err1 := result.Close(); err1 != nil && err == nil {
+			err = err1
+		}
+	}()
+	colReq := e.analyzePB.ColReq
+	fieldTypes := make([]*types.FieldType, 0, len(e.colsInfo))
+	collators := make([]collate.Collator, 0, len(e.colsInfo))
+	for _, col := range e.colsInfo {
+		fieldTypes = append(fieldTypes, &col.FieldType)
+		if col.FieldType.EvalType() == types.ETString {
+			collators = append(collators, collate.GetCollator(col.Collate))
+		} else {
+			collators = append(collators, nil)
+		}
+	}
+	colGroups := make([][]int64, 0, len(colReq.ColumnGroups))
+	for _, group := range colReq.ColumnGroups {
+		colGroups = append(colGroups, group.ColumnOffsets)
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	builder := &statistics.RowSampleBuilder{
+		Sc:              sc,
+		RecordSet:       newAnalyzeTiFlashRecordSet(sc, result, fieldTypes, e.ctx.GetSessionVars().MaxChunkSize),
+		ColsFieldType:   fieldTypes,
+		Collators:       collators,
+		ColGroups:       colGroups,
+		MaxSampleSize:   int(colReq.SampleSize),
+		MaxFMSketchSize: int(colReq.SketchSize),
+		SampleRate:      colReq.GetSampleRate(),
+		Rng:             rand.New(rand.NewSource(time.Now().UnixNano())), // #nosec G404
+	}
+	collector, err := builder.Collect()
+	if err != nil {
+		return err
+	}
+	colResp := &tipb.AnalyzeColumnsResp{RowCollector: collector.Base().ToProto()}
+	data, err := colResp.Marshal()
+	if err != nil {
+		return err
+	}
+	mergeTaskCh <- data
+	return nil
+}
+
+// buildTiFlashResp sends a DAG request scanning the analyzed columns to the TiFlash replica.
+func (e *AnalyzeColumnsExec) buildTiFlashResp(ranges []*ranger.Range) (distsql.SelectResult, error) {
+	sessVars := e.ctx.GetSessionVars()
+	dagReq := &tipb.DAGRequest{}
+	dagReq.TimeZoneName, dagReq.TimeZoneOffset = timeutil.Zone(sessVars.Location())
+	dagReq.Flags = sessVars.StmtCtx.PushDownFlags()
+	for i := range e.colsInfo {
+		dagReq.OutputOffsets = append(dagReq.OutputOffsets, uint32(i))
+	}
+	tblScan := tables.BuildTableScanFromInfos(e.tableInfo, e.colsInfo)
+	tblScan.TableId = e.TableID.GetStatisticsID()
+	if err := core.SetPBColumnsDefaultValue(e.ctx, tblScan.Columns, e.colsInfo); err != nil {
+		return nil, err
+	}
+	dagReq.Executors = append(dagReq.Executors, &tipb.Executor{Tp: tipb.ExecType_TypeTableScan, TblScan: tblScan})
+	distsql.SetEncodeType(e.ctx, dagReq)
+
+	// The samples are sorted by the handles later, so the rows don't need to be kept in order.
+	ranges, _ = distsql.SplitRangesAcrossInt64Boundary(ranges, false, false, !hasPkHist(e.handleCols))
+	var builder distsql.RequestBuilder
+	reqBuilder := builder.SetHandleRangesForTables(sessVars.StmtCtx, []int64{e.TableID.GetStatisticsID()}, e.handleCols != nil && !e.handleCols.IsInt(), ranges, nil)
+	builder.SetResourceGroupTagger(sessVars.StmtCtx)
+	kvReq, err := reqBuilder.
+		SetDAGRequest(dagReq).
+		SetStartTS(e.snapshot).
+		SetKeepOrder(false).
+		SetConcurrency(e.concurrency).
+		SetStoreType(kv.TiFlash).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	fieldTypes := make([]*types.FieldType, 0, len(e.colsInfo))
+	for _, col := range e.colsInfo {
+		fieldTypes = append(fieldTypes, &col.FieldType)
+	}
+	return distsql.Select(context.TODO(), e.ctx, kvReq, fieldTypes, statistics.NewQueryFeedback(0, nil, 0, false))
+}
+
+// analyzeTiFlashRecordSet converts the rows scanned from TiFlash to the encoded column values, which are
+// the values sampled by TiKV, so the samples can be merged and decoded in the same way.
+type analyzeTiFlashRecordSet struct {
+	sc         *stmtctx.StatementContext
+	result     distsql.SelectResult
+	fieldTypes []*types.FieldType
+	fields     []*ast.ResultField
+	chk        *chunk.Chunk
+}
+
+func newAnalyzeTiFlashRecordSet(sc *stmtctx.StatementContext, result distsql.SelectResult, fieldTypes []*types.FieldType, maxChunkSize int) *analyzeTiFlashRecordSet {
+	fields := make([]*ast.ResultField, 0, len(fieldTypes))
+	for range fieldTypes {
+		fields = append(fields, &ast.ResultField{Column: &model.ColumnInfo{FieldType: *types.NewFieldType(mysql.TypeBlob)}})
+	}
+	return &analyzeTiFlashRecordSet{
+		sc:         sc,
+		result:     result,
+		fieldTypes: fieldTypes,
+		fields:     fields,
+		chk:        chunk.NewChunkWithCapacity(fieldTypes, maxChunkSize),
+	}
+}
+
+// Fields implements the sqlexec.RecordSet Fields interface.
+func (r *analyzeTiFlashRecordSet) Fields() []*ast.ResultField {
+	return r.fields
+}
+
+// Next implements the sqlexec.RecordSet Next interface.
+func (r *analyzeTiFlashRecordSet) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if err := r.result.Next(ctx, r.chk); err != nil {
+		return err
+	}
+	it := chunk.NewIterator4Chunk(r.chk)
+	for row := it.Begin(); row != it.End(); row = it.Next() {
+		for i, tp := range r.fieldTypes {
+			d := row.GetDatum(i, tp)
+			if d.IsNull() {
+				req.AppendNull(i)
+				continue
+			}
+			value, err := tablecodec.EncodeValue(r.sc, nil, d)
+			if err != nil {
+				return err
+			}
+			req.AppendBytes(i, value)
+		}
+	}
+	return nil
+}
+
+// NewChunk implements the sqlexec.RecordSet NewChunk interface.
+func (r *analyzeTiFlashRecordSet) NewChunk(_ chunk.Allocator) *chunk.Chunk {
+	fieldTypes := make([]*types.FieldType, 0, len(r.fields))
+	for _, field := range r.fields {
+		fieldTypes = append(fieldTypes, &field.Column.FieldType)
+	}
+	return chunk.NewChunkWithCapacity(fieldTypes, r.chk.Capacity())
+}
+
+// Close implements the sqlexec.RecordSet Close interface.
+func (r *analyzeTiFlashRecordSet) Close() error {
+	return nil
+}
+
 func (e *AnalyzeColumnsExec) buildSamplingStats(
 	ranges []*ranger.Range,
 	needExtStats bool,
@@ -918,14 +1082,16 @@ func (e *AnalyzeColumnsExec) buildSamplingStats(
 	extStats *statistics.ExtendedStatsColl,
 	err error,
 ) {
-	if err = e.open(ranges); err != nil {
-		return 0, nil, nil, nil, nil, err
-	}
-	defer func() {
-		if err1 := e.resultHandler.Close(); err1 != nil {
-			err = err1
+	if e.storeType != kv.TiFlash {
+		if err = e.open(ranges); err != nil {
+			return 0, nil, nil, nil, nil, err
 		}
-	}()
+		defer func() {
+			if err1 := e.resultHandler.Close(); err1 != nil {
+				err = err1
+			}
+		}()
+	}
 
 	l := len(e.analyzePB.ColReq.ColumnsInfo) + len(e.analyzePB.ColReq.ColumnGroups)
 	rootRowCollector := statistics.NewRowSampleCollector(int(e.analyzePB.ColReq.SampleSize), e.analyzePB.ColReq.GetSampleRate(), l)
@@ -944,7 +1110,12 @@ func (e *AnalyzeColumnsExec) buildSamplingStats(
 	for i := 0; i < statsConcurrency; i++ {
 		go e.subMergeWorker(mergeResultCh, mergeTaskCh, l, i == 0)
 	}
-	if err = readDataAndSendTask(e.resultHandler, mergeTaskCh); err != nil {
+	if e.storeType == kv.TiFlash {
+		err = e.readTiFlashDataAndSendTask(ranges, mergeTaskCh)
+	} else {
+		err = readDataAndSendTask(e.resultHandler, mergeTaskCh)
+	}
+	if err != nil {
 		return 0, nil, nil, nil, nil, err
 	}
 
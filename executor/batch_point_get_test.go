@@ -380,3 +380,27 @@ func TestPointGetForTemporaryTable(t *testing.T) {
 	tk.MustQuery("select * from t1 where id = 1").Check(testkit.Rows("1 1"))
 	tk.MustQuery("select * from t1 where id = 2").Check(testkit.Rows())
 }
+
+func TestBatchPointGetHashPartition(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, b int, c int) partition by hash(id) partitions 4")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4), (5, 5, 5)")
+
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	require.True(t, tk.HasPlan("select * from t where id in (1, 2, 5) and c > 1", "Batch_Point_Get"))
+	tk.MustQuery("select * from t where id in (1, 2, 5) and c > 1").Sort().Check(testkit.Rows("2 2 2", "5 5 5"))
+	require.True(t, tk.HasPlan("select * from t where id = 1 or id = 4", "Batch_Point_Get"))
+	tk.MustQuery("select * from t where id = 1 or id = 4").Sort().Check(testkit.Rows("1 1 1", "4 4 4"))
+	tk.MustQuery("select * from t where id in (1, 2, 5) for update").Sort().Check(testkit.Rows("1 1 1", "2 2 2", "5 5 5"))
+	// The PARTITION clause restricts the keys, so batch point get is not used.
+	require.False(t, tk.HasPlan("select * from t partition (p1) where id = 1 or id = 4", "Batch_Point_Get"))
+	tk.MustQuery("select * from t partition (p1) where id = 1 or id = 4").Check(testkit.Rows("1 1 1"))
+
+	tk.MustExec("set @@tidb_partition_prune_mode = 'static'")
+	tk.MustQuery("select * from t where id in (1, 2, 5) and c > 1").Sort().Check(testkit.Rows("2 2 2", "5 5 5"))
+}
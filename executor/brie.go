@@ -16,6 +16,7 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"net/url"
 	"strings"
 	"sync"
@@ -35,19 +36,23 @@ import (
 	"github.com/pingcap/tidb/br/pkg/glue"
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/br/pkg/task"
+	"github.com/pingcap/tidb/br/pkg/utils"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/printer"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
 )
 
 const clearInterval = 10 * time.Minute
@@ -292,9 +297,13 @@ func (b *executorBuilder) buildBRIE(s *ast.BRIEStmt, schema *expression.Schema)
 	switch s.Kind {
 	case ast.BRIEKindBackup:
 		e.backupCfg = &task.BackupConfig{Config: cfg}
+		e.volumeSnapshot = s.VolumeSnapshot
 
 		for _, opt := range s.Options {
 			switch opt.Tp {
+			case ast.BRIEOptionOnline:
+				b.err = errors.Errorf("option %s is only supported by RESTORE", opt.Tp)
+				return nil
 			case ast.BRIEOptionLastBackupTS:
 				tso, err := b.parseTSString(opt.StrValue)
 				if err != nil {
@@ -342,6 +351,8 @@ type BRIEExec struct {
 	backupCfg  *task.BackupConfig
 	restoreCfg *task.RestoreConfig
 	info       *brieTaskInfo
+	// volumeSnapshot indicates the backup is taken by external volume snapshots.
+	volumeSnapshot bool
 }
 
 // Next implements the Executor Next interface.
@@ -387,6 +398,10 @@ func (e *BRIEExec) Next(ctx context.Context, req *chunk.Chunk) error {
 
 	switch e.info.kind {
 	case ast.BRIEKindBackup:
+		if e.volumeSnapshot {
+			err = handleBRIEError(runVolumeSnapshotBackup(taskCtx, glue, e.backupCfg), ErrBRIEBackupFailed)
+			break
+		}
 		err = handleBRIEError(task.RunBackup(taskCtx, glue, "Backup", e.backupCfg), ErrBRIEBackupFailed)
 	case ast.BRIEKindRestore:
 		err = handleBRIEError(task.RunRestore(taskCtx, glue, "Restore", e.restoreCfg), ErrBRIERestoreFailed)
@@ -416,6 +431,136 @@ func handleBRIEError(err error, terror *terror.Error) error {
 	return terror.GenWithStackByArgs(err)
 }
 
+const (
+	// volumeSnapshotMetaFile is written to the storage by BACKUP CLUSTER USING SNAPSHOT once the cluster
+	// is held at the consistency point, the external tools can take the volume snapshots after it appears.
+	volumeSnapshotMetaFile = "volume_snapshot.meta"
+	// volumeSnapshotDoneFile is written to the storage by the external tools after all the volume snapshots
+	// are taken, and then the cluster is resumed.
+	volumeSnapshotDoneFile = "volume_snapshot.done"
+)
+
+var (
+	volumeSnapshotCheckInterval = time.Second
+	volumeSnapshotTimeout       = time.Hour
+	// volumeSnapshotDDLPauseLease is the lease of pausing DDL, it's renewed while waiting for the volume snapshots,
+	// so DDL is resumed after the lease if the TiDB server running the backup crashes.
+	volumeSnapshotDDLPauseLease = time.Minute
+)
+
+// volumeSnapshotMeta describes the consistency point of a volume snapshot backup.
+type volumeSnapshotMeta struct {
+	ClusterID uint64 `json:"cluster_id"`
+	BackupTS  uint64 `json:"backup_ts"`
+}
+
+// runVolumeSnapshotBackup holds the cluster at a consistency point while the external tools take the
+// snapshots of the storage volumes. GC is paused at the backup TS and the DDL jobs are paused so that
+// the data at the backup TS can be restored from the volume snapshots, both are resumed on return.
+func runVolumeSnapshotBackup(ctx context.Context, g *tidbGlueSession, cfg *task.BackupConfig) error {
+	store := g.se.GetStore()
+	pdStore, ok := store.(interface{ GetPDClient() pd.Client })
+	if !ok {
+		return errors.New("volume snapshot backup requires tikv store")
+	}
+	pdClient := pdStore.GetPDClient()
+	_, extStorage, err := task.GetStorage(ctx, &cfg.Config)
+	if err != nil {
+		return err
+	}
+	// Remove the stale done file left by the previous backup to the same storage.
+	exists, err := extStorage.FileExists(ctx, volumeSnapshotDoneFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if exists {
+		if err = extStorage.DeleteFile(ctx, volumeSnapshotDoneFile); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	ver, err := store.CurrentVersion(kv.GlobalTxnScope)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	backupTS := ver.Ver
+
+	progress := g.StartProgress(ctx, "Pause GC", 1, false)
+	sp := utils.BRServiceSafePoint{
+		ID:       utils.MakeSafePointID(),
+		TTL:      utils.DefaultBRGCSafePointTTL,
+		BackupTS: backupTS,
+	}
+	keeperCtx, cancelKeeper := context.WithCancel(ctx)
+	defer func() {
+		cancelKeeper()
+		if _, err := pdClient.UpdateServiceGCSafePoint(context.Background(), sp.ID, 0, 0); err != nil {
+			logutil.BgLogger().Warn("failed to remove the service safe point of volume snapshot backup",
+				zap.String("service", sp.ID), zap.Error(err))
+		}
+	}()
+	if err = utils.StartServiceSafePointKeeper(keeperCtx, pdClient, sp); err != nil {
+		return errors.Trace(err)
+	}
+	progress.Close()
+
+	progress = g.StartProgress(ctx, "Pause DDL", 1, false)
+	if err = setDDLPaused(store, time.Now().Add(volumeSnapshotDDLPauseLease)); err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err := setDDLPaused(store, time.Time{}); err != nil {
+			logutil.BgLogger().Warn("failed to resume DDL after volume snapshot backup, it's resumed after the lease",
+				zap.Duration("lease", volumeSnapshotDDLPauseLease), zap.Error(err))
+		}
+	}()
+	progress.Close()
+
+	data, err := json.Marshal(&volumeSnapshotMeta{ClusterID: pdClient.GetClusterID(ctx), BackupTS: backupTS})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = extStorage.WriteFile(ctx, volumeSnapshotMetaFile, data); err != nil {
+		return errors.Trace(err)
+	}
+	g.Record("BackupTS", backupTS)
+
+	progress = g.StartProgress(ctx, "Wait Volume Snapshot", 1, false)
+	defer progress.Close()
+	ticker := time.NewTicker(volumeSnapshotCheckInterval)
+	defer ticker.Stop()
+	renewTicker := time.NewTicker(volumeSnapshotDDLPauseLease / 3)
+	defer renewTicker.Stop()
+	timeout := time.After(volumeSnapshotTimeout)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return errors.Errorf("the volume snapshots are not taken in %s", volumeSnapshotTimeout)
+		case <-renewTicker.C:
+			if err = setDDLPaused(store, time.Now().Add(volumeSnapshotDDLPauseLease)); err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		case <-ticker.C:
+		}
+		exists, err = extStorage.FileExists(ctx, volumeSnapshotDoneFile)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if exists {
+			return nil
+		}
+	}
+}
+
+func setDDLPaused(store kv.Storage, expire time.Time) error {
+	return kv.RunInNewTxn(context.Background(), store, true, func(ctx context.Context, txn kv.Transaction) error {
+		return meta.NewMeta(txn).SetDDLPaused(expire)
+	})
+}
+
 func (e *ShowExec) fetchShowBRIE(kind ast.BRIEKind) error {
 	globalBRIEQueue.tasks.Range(func(key, value interface{}) bool {
 		item := value.(*brieQueueItem)
@@ -16,22 +16,32 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pingcap/tidb/br/pkg/task"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/stretchr/testify/require"
+	pd "github.com/tikv/pd/client"
 )
 
 func TestGlueGetVersion(t *testing.T) {
@@ -152,3 +162,107 @@ func TestFetchShowBRIE(t *testing.T) {
 	globalBRIEQueue.clearTask(e.ctx.GetSessionVars().StmtCtx)
 	require.Equal(t, info2Res, fetchShowBRIEResult(t, e, brieColTypes))
 }
+
+func isDDLPaused(t *testing.T, store kv.Storage) bool {
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, txn.Rollback())
+	}()
+	paused, err := meta.NewMeta(txn).IsDDLPaused(time.Now())
+	require.NoError(t, err)
+	return paused
+}
+
+func TestVolumeSnapshotBackup(t *testing.T) {
+	store, err := mockstore.NewMockStore()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, store.Close())
+	}()
+	pdClient := store.(interface{ GetPDClient() pd.Client }).GetPDClient()
+
+	originInterval, originLease := volumeSnapshotCheckInterval, volumeSnapshotDDLPauseLease
+	volumeSnapshotCheckInterval, volumeSnapshotDDLPauseLease = 10*time.Millisecond, 300*time.Millisecond
+	defer func() {
+		volumeSnapshotCheckInterval, volumeSnapshotDDLPauseLease = originInterval, originLease
+	}()
+
+	sctx := mock.NewContext()
+	sctx.Store = store
+	dir := t.TempDir()
+	cfg := &task.BackupConfig{Config: task.Config{Storage: "local://" + dir}}
+	g := &tidbGlueSession{se: sctx, progress: &brieTaskProgress{}, info: &brieTaskInfo{}}
+
+	// The stale done file is removed before the backup, so the backup waits for the new one.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, volumeSnapshotDoneFile), nil, 0644))
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- runVolumeSnapshotBackup(context.Background(), g, cfg)
+	}()
+	var data []byte
+	require.Eventually(t, func() bool {
+		data, err = os.ReadFile(filepath.Join(dir, volumeSnapshotMetaFile))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	var snapshotMeta volumeSnapshotMeta
+	require.NoError(t, json.Unmarshal(data, &snapshotMeta))
+	require.Greater(t, snapshotMeta.BackupTS, uint64(0))
+	require.Equal(t, snapshotMeta.BackupTS, g.info.backupTS)
+
+	// GC and DDL are paused while the volume snapshots are taken, the pause of DDL is renewed before it expires.
+	require.True(t, isDDLPaused(t, store))
+	time.Sleep(2 * volumeSnapshotDDLPauseLease)
+	require.True(t, isDDLPaused(t, store))
+	minSafePoint, err := pdClient.UpdateServiceGCSafePoint(context.Background(), "test", 1, math.MaxUint64)
+	require.NoError(t, err)
+	require.Equal(t, snapshotMeta.BackupTS-1, minSafePoint)
+	_, err = pdClient.UpdateServiceGCSafePoint(context.Background(), "test", 0, 0)
+	require.NoError(t, err)
+	select {
+	case err = <-errCh:
+		require.FailNow(t, "the backup should wait for the volume snapshots", "err: %v", err)
+	default:
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, volumeSnapshotDoneFile), nil, 0644))
+	require.NoError(t, <-errCh)
+	require.False(t, isDDLPaused(t, store))
+	minSafePoint, err = pdClient.UpdateServiceGCSafePoint(context.Background(), "test", 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(math.MaxUint64), minSafePoint)
+
+	// GC and DDL are resumed when the backup is canceled.
+	require.NoError(t, os.Remove(filepath.Join(dir, volumeSnapshotDoneFile)))
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		errCh <- runVolumeSnapshotBackup(ctx, g, cfg)
+	}()
+	require.Eventually(t, func() bool {
+		return isDDLPaused(t, store)
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+	require.False(t, isDDLPaused(t, store))
+}
+
+func TestBuildRestoreOptions(t *testing.T) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Store = "tikv"
+	})
+	b := newExecutorBuilder(mock.NewContext(), nil, nil, 0, false, "")
+	p := parser.New()
+
+	stmt, err := p.ParseOneStmt("restore database * from 'noop://' rate_limit = 10 MB/second online = 1", "", "")
+	require.NoError(t, err)
+	e := b.buildBRIE(stmt.(*ast.BRIEStmt), nil).(*BRIEExec)
+	require.NoError(t, b.err)
+	require.True(t, e.restoreCfg.Online)
+	require.Equal(t, uint64(10*1024*1024), e.restoreCfg.RateLimit)
+
+	stmt, err = p.ParseOneStmt("backup database * to 'noop://' online = 1", "", "")
+	require.NoError(t, err)
+	require.Nil(t, b.buildBRIE(stmt.(*ast.BRIEStmt), nil))
+	require.EqualError(t, b.err, "option ONLINE is only supported by RESTORE")
+}
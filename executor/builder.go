@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/diagnosticspb"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor/aggfuncs"
@@ -75,6 +76,7 @@ var (
 	executorStreamAggExec                   = metrics.ExecutorCounter.WithLabelValues("StreamAggExec")
 	executorCounterSortExec                 = metrics.ExecutorCounter.WithLabelValues("SortExec")
 	executorCounterTopNExec                 = metrics.ExecutorCounter.WithLabelValues("TopNExec")
+	executorCounterReservoirSampleExec      = metrics.ExecutorCounter.WithLabelValues("ReservoirSampleExec")
 	executorCounterNestedLoopApplyExec      = metrics.ExecutorCounter.WithLabelValues("NestedLoopApplyExec")
 	executorCounterIndexLookUpJoin          = metrics.ExecutorCounter.WithLabelValues("IndexLookUpJoin")
 	executorCounterIndexLookUpExecutor      = metrics.ExecutorCounter.WithLabelValues("IndexLookUpExecutor")
@@ -171,6 +173,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildReloadExprPushdownBlacklist(v)
 	case *plannercore.ReloadOptRuleBlacklist:
 		return b.buildReloadOptRuleBlacklist(v)
+	case *plannercore.ReloadSQLBlocklist:
+		return b.buildReloadSQLBlocklist(v)
 	case *plannercore.AdminPlugins:
 		return b.buildAdminPlugins(v)
 	case *plannercore.DDL:
@@ -217,6 +221,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildShowDDLJobQueries(v)
 	case *plannercore.ShowSlow:
 		return b.buildShowSlow(v)
+	case *plannercore.KillTopMemory:
+		return b.buildKillTopMemory(v)
 	case *plannercore.PhysicalShow:
 		return b.buildShow(v)
 	case *plannercore.Simple:
@@ -231,6 +237,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildSort(v)
 	case *plannercore.PhysicalTopN:
 		return b.buildTopN(v)
+	case *plannercore.PhysicalReservoirSample:
+		return b.buildReservoirSample(v)
 	case *plannercore.PhysicalUnionAll:
 		return b.buildUnionAll(v)
 	case *plannercore.Update:
@@ -266,13 +274,13 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 	case *plannercore.Analyze:
 		return b.buildAnalyze(v)
 	case *plannercore.PhysicalTableReader:
-		return b.buildTableReader(v)
+		return b.buildWithTableSnapshot(v, func(b *executorBuilder) Executor { return b.buildTableReader(v) })
 	case *plannercore.PhysicalTableSample:
 		return b.buildTableSample(v)
 	case *plannercore.PhysicalIndexReader:
-		return b.buildIndexReader(v)
+		return b.buildWithTableSnapshot(v, func(b *executorBuilder) Executor { return b.buildIndexReader(v) })
 	case *plannercore.PhysicalIndexLookUpReader:
-		return b.buildIndexLookUpReader(v)
+		return b.buildWithTableSnapshot(v, func(b *executorBuilder) Executor { return b.buildIndexLookUpReader(v) })
 	case *plannercore.PhysicalWindow:
 		return b.buildWindow(v)
 	case *plannercore.PhysicalShuffle:
@@ -284,7 +292,7 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 	case *plannercore.SplitRegion:
 		return b.buildSplitRegion(v)
 	case *plannercore.PhysicalIndexMergeReader:
-		return b.buildIndexMergeReader(v)
+		return b.buildWithTableSnapshot(v, func(b *executorBuilder) Executor { return b.buildIndexMergeReader(v) })
 	case *plannercore.SelectInto:
 		return b.buildSelectInto(v)
 	case *plannercore.AdminShowTelemetry:
@@ -386,6 +394,14 @@ func (b *executorBuilder) buildShowDDLJobQueries(v *plannercore.ShowDDLJobQuerie
 	return e
 }
 
+func (b *executorBuilder) buildKillTopMemory(v *plannercore.KillTopMemory) Executor {
+	e := &KillTopMemoryExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		topN:         v.TopN,
+	}
+	return e
+}
+
 func (b *executorBuilder) buildShowSlow(v *plannercore.ShowSlow) Executor {
 	e := &ShowSlowExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
@@ -603,15 +619,25 @@ func (b *executorBuilder) buildChecksumTable(v *plannercore.ChecksumTable) Execu
 	e := &ChecksumTableExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 		tables:       make(map[int64]*checksumContext),
+		withIndexes:  v.WithIndexes,
+		resultTable:  v.ResultTable,
 		done:         false,
 	}
-	startTs, err := b.getSnapshotTS()
-	if err != nil {
-		b.err = err
-		return nil
+	startTs := v.StartTS
+	if startTs == 0 {
+		var err error
+		startTs, err = b.getSnapshotTS()
+		if err != nil {
+			b.err = err
+			return nil
+		}
 	}
 	for _, t := range v.Tables {
+		if _, ok := e.tables[t.TableInfo.ID]; ok {
+			continue
+		}
 		e.tables[t.TableInfo.ID] = newChecksumContext(t.DBInfo, t.TableInfo, startTs)
+		e.tableIDs = append(e.tableIDs, t.TableInfo.ID)
 	}
 	return e
 }
@@ -624,6 +650,10 @@ func (b *executorBuilder) buildReloadOptRuleBlacklist(v *plannercore.ReloadOptRu
 	return &ReloadOptRuleBlacklistExec{baseExecutor{ctx: b.ctx}}
 }
 
+func (b *executorBuilder) buildReloadSQLBlocklist(v *plannercore.ReloadSQLBlocklist) Executor {
+	return &ReloadSQLBlocklistExec{baseExecutor{ctx: b.ctx}}
+}
+
 func (b *executorBuilder) buildAdminPlugins(v *plannercore.AdminPlugins) Executor {
 	return &AdminPluginsExec{baseExecutor: baseExecutor{ctx: b.ctx}, Action: v.Action, Plugins: v.Plugins}
 }
@@ -1001,6 +1031,7 @@ func (b *executorBuilder) buildDDL(v *plannercore.DDL) Executor {
 		is:           b.is,
 		tempTableDDL: temptable.GetTemporaryTableDDL(b.ctx),
 	}
+	e.selectCols = v.SelectCols
 	return e
 }
 
@@ -1375,6 +1406,10 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 	if finalCon, partialCon := sessionVars.HashAggFinalConcurrency(), sessionVars.HashAggPartialConcurrency(); finalCon <= 0 || partialCon <= 0 || finalCon == 1 && partialCon == 1 {
 		e.isUnparallelExec = true
 	}
+	// The parallel execution falls back to the unparallel execution only when the data actually spills.
+	if sessionVars.EnableParallelHashAggSpill && sessionVars.TrackAggregateMemoryUsage && config.GetGlobalConfig().OOMUseTmpStorage {
+		e.spillByPartition = true
+	}
 	partialOrdinal := 0
 	for i, aggDesc := range v.AggFuncs {
 		if e.isUnparallelExec {
@@ -1441,6 +1476,10 @@ func (b *executorBuilder) buildSelection(v *plannercore.PhysicalSelection) Execu
 	if b.err != nil {
 		return nil
 	}
+	if reader, ok := childExec.(*TableReaderExecutor); ok && reader.fusedProj == nil && b.canFuseIntoTableReader(v.Conditions) {
+		reader.fusedFilters = append(reader.fusedFilters, v.Conditions...)
+		return reader
+	}
 	e := &SelectionExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), childExec),
 		filters:      v.Conditions,
@@ -1448,6 +1487,45 @@ func (b *executorBuilder) buildSelection(v *plannercore.PhysicalSelection) Execu
 	return e
 }
 
+// canFuseIntoTableReader checks whether the Selection or the Projection with the expressions can be fused into
+// the TableReaderExecutor below it. Only the cheap expressions which can be evaluated in the vectorized way
+// are fused. The operators are not fused for EXPLAIN to keep the execution info of each operator.
+func (b *executorBuilder) canFuseIntoTableReader(exprs []expression.Expression) bool {
+	sessVars := b.ctx.GetSessionVars()
+	if !sessVars.EnableScanOperatorFusion || sessVars.StmtCtx.InExplainStmt {
+		return false
+	}
+	if !expression.Vectorizable(exprs) {
+		return false
+	}
+	for _, expr := range exprs {
+		if !isCheapExpr(expr) {
+			return false
+		}
+	}
+	return true
+}
+
+// isCheapExpr checks whether the expression only consists of the columns, the constants and the builtin
+// functions which are vectorized and have no side effects.
+func isCheapExpr(expr expression.Expression) bool {
+	switch x := expr.(type) {
+	case *expression.Column, *expression.CorrelatedColumn, *expression.Constant:
+		return true
+	case *expression.ScalarFunction:
+		if !x.Vectorized() || expression.IsMutableEffectsExpr(x) {
+			return false
+		}
+		for _, arg := range x.GetArgs() {
+			if !isCheapExpr(arg) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
 func (b *executorBuilder) buildProjection(v *plannercore.PhysicalProjection) Executor {
 	childExec := b.build(v.Children()[0])
 	if b.err != nil {
@@ -1472,6 +1550,21 @@ func (b *executorBuilder) buildProjection(v *plannercore.PhysicalProjection) Exe
 	if b.inUpdateStmt || b.inDeleteStmt || b.inInsertStmt || b.hasLock {
 		e.numWorkers = 0
 	}
+
+	// The un-parallel projection on top of the TableReader can be fused into it to
+	// avoid materializing the chunks of the reader.
+	if reader, ok := childExec.(*TableReaderExecutor); ok && e.numWorkers == 0 && !e.calculateNoDelay &&
+		reader.fusedProj == nil && b.canFuseIntoTableReader(v.Exprs) {
+		reader.fusedProj = &fusedProjection{
+			evaluatorSuit: e.evaluatorSuit,
+			scanSchema:    reader.schema,
+			scanRetTypes:  reader.retFieldTypes,
+			scanChk:       newFirstChunk(reader),
+		}
+		reader.schema = e.schema
+		reader.retFieldTypes = e.retFieldTypes
+		return reader
+	}
 	return e
 }
 
@@ -1544,6 +1637,70 @@ func (b *executorBuilder) getReadTS() (uint64, error) {
 	return snapshotTS, nil
 }
 
+// buildWithTableSnapshot builds the reader with the builder of the snapshot the table is read at,
+// if the table is read at a different AS OF TIMESTAMP from the statement.
+func (b *executorBuilder) buildWithTableSnapshot(p plannercore.PhysicalPlan, build func(*executorBuilder) Executor) Executor {
+	snapshotTS := tableSnapshotTS(p)
+	if snapshotTS == 0 {
+		return build(b)
+	}
+	snapshotBuilder := *b
+	if err := snapshotBuilder.useTableSnapshot(snapshotTS); err != nil {
+		b.err = err
+		return nil
+	}
+	e := build(&snapshotBuilder)
+	if snapshotBuilder.err != nil {
+		b.err = snapshotBuilder.err
+		return nil
+	}
+	return e
+}
+
+// useTableSnapshot makes the builder read at the snapshotTS with the information schema of the snapshot.
+func (b *executorBuilder) useTableSnapshot(snapshotTS uint64) error {
+	is, err := domain.GetDomain(b.ctx).GetSnapshotInfoSchema(snapshotTS)
+	if err != nil {
+		return err
+	}
+	b.is = temptable.AttachLocalTemporaryTableInfoSchema(b.ctx, is)
+	b.snapshotTS = snapshotTS
+	b.snapshotTSCached = true
+	b.dataReaderTS = snapshotTS
+	b.isStaleness = true
+	return nil
+}
+
+// tableSnapshotTS returns the snapshot of the table read by the plan if it differs from the statement's,
+// otherwise it returns 0.
+func tableSnapshotTS(p plannercore.PhysicalPlan) uint64 {
+	var plans []plannercore.PhysicalPlan
+	switch v := p.(type) {
+	case *plannercore.PhysicalTableScan:
+		return v.SnapshotTS
+	case *plannercore.PhysicalIndexScan:
+		return v.SnapshotTS
+	case *plannercore.PhysicalTableReader:
+		plans = v.TablePlans
+	case *plannercore.PhysicalIndexReader:
+		plans = v.IndexPlans
+	case *plannercore.PhysicalIndexLookUpReader:
+		plans = v.IndexPlans
+	case *plannercore.PhysicalIndexMergeReader:
+		for _, partialPlan := range v.PartialPlans {
+			plans = append(plans, partialPlan...)
+		}
+	default:
+		plans = p.Children()
+	}
+	for _, child := range plans {
+		if snapshotTS := tableSnapshotTS(child); snapshotTS != 0 {
+			return snapshotTS
+		}
+	}
+	return 0
+}
+
 func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executor {
 	switch v.DBName.L {
 	case util.MetricSchemaName.L:
@@ -1696,7 +1853,16 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TableClientErrorsSummaryByUser),
 			strings.ToLower(infoschema.TableClientErrorsSummaryByHost),
 			strings.ToLower(infoschema.TableAttributes),
-			strings.ToLower(infoschema.TablePlacementPolicies):
+			strings.ToLower(infoschema.TablePlacementPolicies),
+			strings.ToLower(infoschema.TableCoprocessorCache),
+			strings.ToLower(infoschema.TableMDLView),
+			strings.ToLower(infoschema.TableTrxSummary),
+			strings.ToLower(infoschema.ClusterTableTrxSummary),
+			strings.ToLower(infoschema.TableRegionCacheStats),
+			strings.ToLower(infoschema.ClusterTableRegionCacheStats),
+			strings.ToLower(infoschema.TablePlacementStatus),
+			strings.ToLower(infoschema.TableSessionWriteConflicts),
+			strings.ToLower(infoschema.TableUserAttributes):
 			return &MemTableReaderExec{
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 				table:        v.Table,
@@ -1835,6 +2001,19 @@ func (b *executorBuilder) buildTopN(v *plannercore.PhysicalTopN) Executor {
 	}
 }
 
+func (b *executorBuilder) buildReservoirSample(v *plannercore.PhysicalReservoirSample) Executor {
+	childExec := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	executorCounterReservoirSampleExec.Inc()
+	return &ReservoirSampleExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), childExec),
+		offset:       v.Offset,
+		count:        v.Count,
+	}
+}
+
 func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
 	var (
 		innerPlan plannercore.PhysicalPlan
@@ -1867,6 +2046,8 @@ func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
 		outerExec, innerExec = rightChild, leftChild
 		outerFilter, innerFilter = v.RightConditions, v.LeftConditions
 	}
+	// The inner side is opened for each outer row.
+	disableChunkAlloc(innerExec)
 	tupleJoiner := newJoiner(b.ctx, v.JoinType, v.InnerChildIdx == 0,
 		defaultValues, otherConditions, retTypes(leftChild), retTypes(rightChild), nil)
 	serialExec := &NestedLoopApplyExec{
@@ -1901,6 +2082,7 @@ func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
 				b.err = nil
 				return serialExec
 			}
+			disableChunkAlloc(clonedInnerExec)
 			innerExecs = append(innerExecs, clonedInnerExec)
 			corCols = append(corCols, corCol)
 			innerFilters = append(innerFilters, innerFilter.Clone())
@@ -1946,6 +2128,9 @@ func (b *executorBuilder) buildUnionAll(v *plannercore.PhysicalUnionAll) Executo
 			return nil
 		}
 	}
+	if v.KeepOrder() {
+		return b.buildMergeSortUnion(v, childExecs)
+	}
 	e := &UnionExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), childExecs...),
 		concurrency:  b.ctx.GetSessionVars().UnionConcurrency(),
@@ -1953,6 +2138,27 @@ func (b *executorBuilder) buildUnionAll(v *plannercore.PhysicalUnionAll) Executo
 	return e
 }
 
+func (b *executorBuilder) buildMergeSortUnion(v *plannercore.PhysicalUnionAll, childExecs []Executor) Executor {
+	e := &MergeSortUnionExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), childExecs...),
+		keyColumns:   make([]int, 0, len(v.ByItems)),
+		keyCmpFuncs:  make([]chunk.CompareFunc, 0, len(v.ByItems)),
+		keyDescs:     make([]bool, 0, len(v.ByItems)),
+	}
+	for _, item := range v.ByItems {
+		col := item.Expr.(*expression.Column)
+		colIdx := v.Schema().ColumnIndex(col)
+		if colIdx < 0 {
+			b.err = errors.Errorf("Internal error: sort column %s is not in the schema of union", col)
+			return nil
+		}
+		e.keyColumns = append(e.keyColumns, colIdx)
+		e.keyCmpFuncs = append(e.keyCmpFuncs, chunk.GetCompareFunc(col.GetType()))
+		e.keyDescs = append(e.keyDescs, item.Desc)
+	}
+	return e
+}
+
 func buildHandleColsForSplit(sc *stmtctx.StatementContext, tbInfo *model.TableInfo) plannercore.HandleCols {
 	if tbInfo.IsCommonHandle {
 		primaryIdx := tables.FindPrimaryIndex(tbInfo)
@@ -2319,6 +2525,7 @@ func (b *executorBuilder) buildAnalyzeSamplingPushdown(task plannercore.AnalyzeC
 		schemaForVirtualColEval: schemaForVirtualColEval,
 		baseCount:               count,
 		baseModifyCnt:           modifyCount,
+		storeType:               b.getAnalyzeStoreType(task.TblInfo, task.TableID, task.ColsInfo),
 	}
 	sampleRate := new(float64)
 	if opts[ast.AnalyzeOptNumSamples] == 0 {
@@ -2361,6 +2568,33 @@ func (b *executorBuilder) buildAnalyzeSamplingPushdown(task plannercore.AnalyzeC
 	return &analyzeTask{taskType: colTask, colExec: e, job: job}
 }
 
+// getAnalyzeStoreType returns the store to read the samples of the table or partition from. When
+// tidb_analyze_read_from_tiflash is on, the rows are scanned from the TiFlash replica if it's available
+// and TiFlash is allowed by tidb_isolation_read_engines, otherwise they are sampled by TiKV. The virtual
+// generated columns aren't stored in TiFlash, so the tables analyzing them are sampled by TiKV.
+func (b *executorBuilder) getAnalyzeStoreType(tblInfo *model.TableInfo, tableID statistics.AnalyzeTableID, colsInfo []*model.ColumnInfo) kv.StoreType {
+	sessVars := b.ctx.GetSessionVars()
+	if !sessVars.AnalyzeReadFromTiFlash || tblInfo == nil || tblInfo.TiFlashReplica == nil {
+		return kv.TiKV
+	}
+	if _, ok := sessVars.GetIsolationReadEngines()[kv.TiFlash]; !ok {
+		return kv.TiKV
+	}
+	for _, col := range colsInfo {
+		if col.IsGenerated() && !col.GeneratedStored {
+			return kv.TiKV
+		}
+	}
+	if tableID.PartitionID != -1 {
+		if !tblInfo.TiFlashReplica.IsPartitionAvailable(tableID.PartitionID) {
+			return kv.TiKV
+		}
+	} else if !tblInfo.TiFlashReplica.Available {
+		return kv.TiKV
+	}
+	return kv.TiFlash
+}
+
 // getAdjustedSampleRate calculate the sample rate by the table size. If we cannot get the table size. We use the 0.001 as the default sample rate.
 func (b *executorBuilder) getAdjustedSampleRate(sctx sessionctx.Context, task plannercore.AnalyzeColumnsTask) float64 {
 	statsHandle := domain.GetDomain(sctx).StatsHandle()
@@ -2808,6 +3042,13 @@ func (b *executorBuilder) newDataReaderBuilder(p plannercore.PhysicalPlan) (*dat
 	builderForDataReader := *b
 	builderForDataReader.forDataReaderBuilder = true
 	builderForDataReader.dataReaderTS = ts
+	if p != nil {
+		if snapshotTS := tableSnapshotTS(p); snapshotTS != 0 {
+			if err = builderForDataReader.useTableSnapshot(snapshotTS); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	return &dataReaderBuilder{
 		Plan:            p,
@@ -2946,7 +3187,7 @@ func (b *executorBuilder) buildIndexLookUpJoin(v *plannercore.PhysicalIndexJoin)
 	e.innerCtx.hashCols = innerHashCols
 	e.innerCtx.hashCollators = hashCollators
 
-	e.joinResult = newFirstChunk(e)
+	e.joinResult = tryNewCacheChunk(e)
 	executorCounterIndexLookUpJoin.Inc()
 	return e
 }
@@ -3155,6 +3396,9 @@ func extraPIDColumnIndex(schema *expression.Schema) offsetOptional {
 }
 
 func (b *executorBuilder) buildMPPGather(v *plannercore.PhysicalTableReader) Executor {
+	if b.Ti != nil {
+		b.Ti.UseMPP = true
+	}
 	startTs, err := b.getSnapshotTS()
 	if err != nil {
 		b.err = err
@@ -3782,7 +4026,13 @@ type mockPhysicalIndexReader struct {
 
 func (builder *dataReaderBuilder) buildExecutorForIndexJoin(ctx context.Context, lookUpContents []*indexJoinLookUpContent,
 	IndexRanges []*ranger.Range, keyOff2IdxOff []int, cwc *plannercore.ColWithCmpFuncManager, canReorderHandles bool, memTracker *memory.Tracker, interruptSignal *atomic.Value) (Executor, error) {
-	return builder.buildExecutorForIndexJoinInternal(ctx, builder.Plan, lookUpContents, IndexRanges, keyOff2IdxOff, cwc, canReorderHandles, memTracker, interruptSignal)
+	e, err := builder.buildExecutorForIndexJoinInternal(ctx, builder.Plan, lookUpContents, IndexRanges, keyOff2IdxOff, cwc, canReorderHandles, memTracker, interruptSignal)
+	if err != nil {
+		return nil, err
+	}
+	// The inner executors are built for each batch of the outer rows.
+	disableChunkAlloc(e)
+	return e, nil
 }
 
 func (builder *dataReaderBuilder) buildExecutorForIndexJoinInternal(ctx context.Context, plan plannercore.Plan, lookUpContents []*indexJoinLookUpContent,
@@ -4334,7 +4584,8 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 		resultColIdx++
 	}
 
-	if b.ctx.GetSessionVars().EnablePipelinedWindowExec {
+	// The pipelined window executor slides the frames, which can't exclude rows from them.
+	if b.ctx.GetSessionVars().EnablePipelinedWindowExec && (v.Frame == nil || v.Frame.Exclusion == ast.ExcludeNone) {
 		exec := &PipelinedWindowExec{
 			baseExecutor:   base,
 			groupChecker:   newVecGroupChecker(b.ctx, groupByItems),
@@ -4394,6 +4645,9 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 			expectedCmpResult: cmpResult,
 		}
 	}
+	if v.Frame != nil && v.Frame.Exclusion != ast.ExcludeNone {
+		processor = newExcludeFrameWindowProcessor(windowFuncs, partialResults, processor.(frameWindowProcessor), v.Frame.Exclusion, orderByCols)
+	}
 	return &WindowExec{baseExecutor: base,
 		processor:      processor,
 		groupChecker:   newVecGroupChecker(b.ctx, groupByItems),
@@ -4770,8 +5024,11 @@ func (b *executorBuilder) buildCTE(v *plannercore.PhysicalCTE) Executor {
 		return nil
 	}
 
+	// The recursive UNION ALL is deduplicated like UNION DISTINCT if tidb_cte_dedup_union_all is on.
+	dedupUnionAll := v.RecurPlan != nil && !v.CTE.IsDistinct && b.ctx.GetSessionVars().CTEDedupUnionAll
+	isDistinct := v.CTE.IsDistinct || dedupUnionAll
 	var sel []int
-	if v.CTE.IsDistinct {
+	if isDistinct {
 		sel = make([]int, chkSize)
 		for i := 0; i < chkSize; i++ {
 			sel[i] = i
@@ -4785,7 +5042,8 @@ func (b *executorBuilder) buildCTE(v *plannercore.PhysicalCTE) Executor {
 		resTbl:        resTbl,
 		iterInTbl:     iterInTbl,
 		chkIdx:        0,
-		isDistinct:    v.CTE.IsDistinct,
+		isDistinct:    isDistinct,
+		dedupUnionAll: dedupUnionAll,
 		sel:           sel,
 		hasLimit:      v.CTE.HasLimit,
 		limitBeg:      v.CTE.LimitBeg,
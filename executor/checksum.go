@@ -17,15 +17,18 @@ package executor
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
+	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tipb/go-tipb"
 	"go.uber.org/zap"
 )
@@ -37,7 +40,13 @@ type ChecksumTableExec struct {
 	baseExecutor
 
 	tables map[int64]*checksumContext
-	done   bool
+	// tableIDs keeps the order of the tables in the statement.
+	tableIDs    []int64
+	withIndexes bool
+	// resultTable is the table storing the checksums to compare with, it's nil if nothing is compared.
+	resultTable *ast.TableName
+	expected    map[string]*tipb.ChecksumResponse
+	done        bool
 }
 
 // Open implements the Executor Open interface.
@@ -80,6 +89,9 @@ func (e *ChecksumTableExec) Open(ctx context.Context) error {
 		return err
 	}
 
+	if e.resultTable != nil {
+		return e.loadExpectedChecksums(ctx)
+	}
 	return nil
 }
 
@@ -89,39 +101,120 @@ func (e *ChecksumTableExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	if e.done {
 		return nil
 	}
-	for _, t := range e.tables {
-		req.AppendString(0, t.DBInfo.Name.O)
-		req.AppendString(1, t.TableInfo.Name.O)
-		req.AppendUint64(2, t.Response.Checksum)
-		req.AppendUint64(3, t.Response.TotalKvs)
-		req.AppendUint64(4, t.Response.TotalBytes)
+	for _, id := range e.tableIDs {
+		t := e.tables[id]
+		e.appendResultRow(req, t, "", t.Response)
+		if !e.withIndexes {
+			continue
+		}
+		for _, idx := range t.TableInfo.Indices {
+			if idx.State != model.StatePublic {
+				continue
+			}
+			resp := t.IndexResponses[idx.ID]
+			if resp == nil {
+				resp = &tipb.ChecksumResponse{}
+			}
+			e.appendResultRow(req, t, idx.Name.O, resp)
+		}
 	}
 	e.done = true
 	return nil
 }
 
+// appendResultRow appends the checksum of the table or one of its indexes, the index name is empty for the table.
+func (e *ChecksumTableExec) appendResultRow(req *chunk.Chunk, t *checksumContext, indexName string, resp *tipb.ChecksumResponse) {
+	req.AppendString(0, t.DBInfo.Name.O)
+	req.AppendString(1, t.TableInfo.Name.O)
+	col := 2
+	if e.withIndexes || e.resultTable != nil {
+		if indexName == "" {
+			req.AppendNull(col)
+		} else {
+			req.AppendString(col, indexName)
+		}
+		col++
+	}
+	req.AppendUint64(col, resp.Checksum)
+	req.AppendUint64(col+1, resp.TotalKvs)
+	req.AppendUint64(col+2, resp.TotalBytes)
+	if e.resultTable == nil {
+		return
+	}
+	expected, ok := e.expected[checksumResultKey(t.DBInfo.Name.L, t.TableInfo.Name.L, strings.ToLower(indexName))]
+	if !ok {
+		req.AppendNull(col + 3)
+		req.AppendNull(col + 4)
+		req.AppendNull(col + 5)
+		req.AppendString(col+6, "MISSING")
+		return
+	}
+	req.AppendUint64(col+3, expected.Checksum)
+	req.AppendUint64(col+4, expected.TotalKvs)
+	req.AppendUint64(col+5, expected.TotalBytes)
+	if expected.Checksum == resp.Checksum && expected.TotalKvs == resp.TotalKvs && expected.TotalBytes == resp.TotalBytes {
+		req.AppendString(col+6, "MATCH")
+	} else {
+		req.AppendString(col+6, "MISMATCH")
+	}
+}
+
+// loadExpectedChecksums reads the stored checksums from the result table of `admin compare checksum table`.
+func (e *ChecksumTableExec) loadExpectedChecksums(ctx context.Context) error {
+	indexNameCol := "NULL"
+	if e.withIndexes {
+		indexNameCol = "index_name"
+	}
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	rows, _, err := exec.ExecRestrictedSQL(ctx, nil, "select db_name, table_name, "+indexNameCol+
+		", cast(checksum_crc64_xor as unsigned), cast(total_kvs as unsigned), cast(total_bytes as unsigned) from %n.%n",
+		e.resultTable.Schema.O, e.resultTable.Name.O)
+	if err != nil {
+		return err
+	}
+	e.expected = make(map[string]*tipb.ChecksumResponse, len(rows))
+	for _, row := range rows {
+		if row.IsNull(0) || row.IsNull(1) || row.IsNull(3) || row.IsNull(4) || row.IsNull(5) {
+			continue
+		}
+		indexName := ""
+		if !row.IsNull(2) {
+			indexName = strings.ToLower(row.GetString(2))
+		}
+		key := checksumResultKey(strings.ToLower(row.GetString(0)), strings.ToLower(row.GetString(1)), indexName)
+		e.expected[key] = &tipb.ChecksumResponse{
+			Checksum:   row.GetUint64(3),
+			TotalKvs:   row.GetUint64(4),
+			TotalBytes: row.GetUint64(5),
+		}
+	}
+	return nil
+}
+
+func checksumResultKey(db, table, index string) string {
+	return db + "." + table + "." + index
+}
+
 func (e *ChecksumTableExec) buildTasks() ([]*checksumTask, error) {
 	var tasks []*checksumTask
-	for id, t := range e.tables {
+	for _, t := range e.tables {
 		reqs, err := t.BuildRequests(e.ctx)
 		if err != nil {
 			return nil, err
 		}
-		for _, req := range reqs {
-			tasks = append(tasks, &checksumTask{id, req})
-		}
+		tasks = append(tasks, reqs...)
 	}
 	return tasks, nil
 }
 
 func (e *ChecksumTableExec) handleResult(result *checksumResult) {
 	table := e.tables[result.TableID]
-	table.HandleResponse(result.Response)
+	table.HandleResponse(result.IndexID, result.Response)
 }
 
 func (e *ChecksumTableExec) checksumWorker(taskCh <-chan *checksumTask, resultCh chan<- *checksumResult) {
 	for task := range taskCh {
-		result := &checksumResult{TableID: task.TableID}
+		result := &checksumResult{TableID: task.TableID, IndexID: task.IndexID}
 		result.Response, result.Error = e.handleChecksumRequest(task.Request)
 		resultCh <- result
 	}
@@ -159,14 +252,18 @@ func (e *ChecksumTableExec) handleChecksumRequest(req *kv.Request) (resp *tipb.C
 	return resp, nil
 }
 
+// checksumTask calculates the checksum of the rows of the table if the IndexID is 0,
+// or else the checksum of the index.
 type checksumTask struct {
 	TableID int64
+	IndexID int64
 	Request *kv.Request
 }
 
 type checksumResult struct {
 	Error    error
 	TableID  int64
+	IndexID  int64
 	Response *tipb.ChecksumResponse
 }
 
@@ -175,24 +272,27 @@ type checksumContext struct {
 	TableInfo *model.TableInfo
 	StartTs   uint64
 	Response  *tipb.ChecksumResponse
+	// IndexResponses are the checksums of the indexes, which are also included in the Response.
+	IndexResponses map[int64]*tipb.ChecksumResponse
 }
 
 func newChecksumContext(db *model.DBInfo, table *model.TableInfo, startTs uint64) *checksumContext {
 	return &checksumContext{
-		DBInfo:    db,
-		TableInfo: table,
-		StartTs:   startTs,
-		Response:  &tipb.ChecksumResponse{},
+		DBInfo:         db,
+		TableInfo:      table,
+		StartTs:        startTs,
+		Response:       &tipb.ChecksumResponse{},
+		IndexResponses: make(map[int64]*tipb.ChecksumResponse),
 	}
 }
 
-func (c *checksumContext) BuildRequests(ctx sessionctx.Context) ([]*kv.Request, error) {
+func (c *checksumContext) BuildRequests(ctx sessionctx.Context) ([]*checksumTask, error) {
 	var partDefs []model.PartitionDefinition
 	if part := c.TableInfo.Partition; part != nil {
 		partDefs = part.Definitions
 	}
 
-	reqs := make([]*kv.Request, 0, (len(c.TableInfo.Indices)+1)*(len(partDefs)+1))
+	reqs := make([]*checksumTask, 0, (len(c.TableInfo.Indices)+1)*(len(partDefs)+1))
 	if err := c.appendRequest(ctx, c.TableInfo.ID, &reqs); err != nil {
 		return nil, err
 	}
@@ -206,13 +306,13 @@ func (c *checksumContext) BuildRequests(ctx sessionctx.Context) ([]*kv.Request,
 	return reqs, nil
 }
 
-func (c *checksumContext) appendRequest(ctx sessionctx.Context, tableID int64, reqs *[]*kv.Request) error {
+func (c *checksumContext) appendRequest(ctx sessionctx.Context, tableID int64, reqs *[]*checksumTask) error {
 	req, err := c.buildTableRequest(ctx, tableID)
 	if err != nil {
 		return err
 	}
 
-	*reqs = append(*reqs, req)
+	*reqs = append(*reqs, &checksumTask{TableID: c.TableInfo.ID, Request: req})
 	for _, indexInfo := range c.TableInfo.Indices {
 		if indexInfo.State != model.StatePublic {
 			continue
@@ -221,7 +321,7 @@ func (c *checksumContext) appendRequest(ctx sessionctx.Context, tableID int64, r
 		if err != nil {
 			return err
 		}
-		*reqs = append(*reqs, req)
+		*reqs = append(*reqs, &checksumTask{TableID: c.TableInfo.ID, IndexID: indexInfo.ID, Request: req})
 	}
 
 	return nil
@@ -266,8 +366,17 @@ func (c *checksumContext) buildIndexRequest(ctx sessionctx.Context, tableID int6
 		Build()
 }
 
-func (c *checksumContext) HandleResponse(update *tipb.ChecksumResponse) {
+func (c *checksumContext) HandleResponse(indexID int64, update *tipb.ChecksumResponse) {
 	updateChecksumResponse(c.Response, update)
+	if indexID == 0 {
+		return
+	}
+	resp, ok := c.IndexResponses[indexID]
+	if !ok {
+		resp = &tipb.ChecksumResponse{}
+		c.IndexResponses[indexID] = resp
+	}
+	updateChecksumResponse(resp, update)
 }
 
 func getChecksumTableConcurrency(ctx sessionctx.Context) (int, error) {
@@ -76,9 +76,13 @@ type CTEExec struct {
 
 	// UNION ALL or UNION DISTINCT.
 	isDistinct bool
-	curIter    int
-	hCtx       *hashContext
-	sel        []int
+	// dedupUnionAll indicates the UNION ALL is deduplicated because of tidb_cte_dedup_union_all,
+	// the number of the rows skipped by the recursive part is reported as a warning.
+	dedupUnionAll bool
+	dedupRows     int
+	curIter       int
+	hCtx          *hashContext
+	sel           []int
 
 	// Limit related info.
 	hasLimit       bool
@@ -180,6 +184,9 @@ func (e *CTEExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 			}
 			return err
 		}
+		if e.dedupRows > 0 {
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("Recursive query skipped %d duplicate rows because of @@tidb_cte_dedup_union_all", e.dedupRows))
+		}
 		e.resTbl.SetDone()
 	}
 
@@ -267,8 +274,8 @@ func (e *CTEExec) computeRecursivePart(ctx context.Context) (err error) {
 		return nil
 	}
 
-	if e.curIter > e.ctx.GetSessionVars().CTEMaxRecursionDepth {
-		return ErrCTEMaxRecursionDepth.GenWithStackByArgs(e.curIter)
+	if stop, err := e.reachRecursionLimit(); stop || err != nil {
+		return err
 	}
 
 	if e.limitDone(e.resTbl) {
@@ -293,8 +300,8 @@ func (e *CTEExec) computeRecursivePart(ctx context.Context) (err error) {
 			// Next iteration begins. Need use iterOutTbl as input of next iteration.
 			e.curIter++
 			e.iterInTbl.SetIter(e.curIter)
-			if e.curIter > e.ctx.GetSessionVars().CTEMaxRecursionDepth {
-				return ErrCTEMaxRecursionDepth.GenWithStackByArgs(e.curIter)
+			if stop, err := e.reachRecursionLimit(); stop || err != nil {
+				return err
 			}
 			// Make sure iterInTbl is setup before Close/Open,
 			// because some executors will read iterInTbl in Open() (like IndexLookupJoin).
@@ -313,6 +320,26 @@ func (e *CTEExec) computeRecursivePart(ctx context.Context) (err error) {
 	return nil
 }
 
+// reachRecursionLimit checks whether the recursion reaches cte_max_recursion_depth or tidb_cte_mem_quota
+// before the next iteration. It returns an error when the limit is reached, or stops the recursion with
+// a warning and keeps the rows produced so far if tidb_cte_truncate_on_limit is on.
+func (e *CTEExec) reachRecursionLimit() (stop bool, err error) {
+	sessVars := e.ctx.GetSessionVars()
+	if e.curIter > sessVars.CTEMaxRecursionDepth {
+		err = ErrCTEMaxRecursionDepth.GenWithStackByArgs(e.curIter)
+	} else if quota := sessVars.CTEMemQuota; quota > 0 && e.memTracker.BytesConsumed()+e.diskTracker.BytesConsumed() > quota {
+		err = ErrCTEMemQuotaExceeded.GenWithStackByArgs(e.curIter, quota)
+	}
+	if err == nil {
+		return false, nil
+	}
+	if sessVars.CTETruncateOnLimit {
+		sessVars.StmtCtx.AppendWarning(err)
+		return true, nil
+	}
+	return true, err
+}
+
 // Get next chunk from resTbl for limit.
 func (e *CTEExec) nextChunkLimit(req *chunk.Chunk) error {
 	if !e.meetFirstBatch {
@@ -373,10 +400,14 @@ func (e *CTEExec) setupTblsForNewIteration() (err error) {
 		if e.isDistinct {
 			chk = chk.CopyConstruct()
 		}
+		numRows := chk.NumRows()
 		chk, err = e.tryDedupAndAdd(chk, e.resTbl, e.hashTbl)
 		if err != nil {
 			return err
 		}
+		if e.dedupUnionAll {
+			e.dedupRows += numRows - chk.NumRows()
+		}
 		chks = append(chks, chk)
 	}
 
@@ -404,6 +435,7 @@ func (e *CTEExec) setupTblsForNewIteration() (err error) {
 
 func (e *CTEExec) reset() {
 	e.curIter = 0
+	e.dedupRows = 0
 	e.chkIdx = 0
 	e.hashTbl = nil
 	e.cursor = 0
@@ -143,6 +143,47 @@ func TestCTEMaxRecursionDepth(t *testing.T) {
 	rows.Check(testkit.Rows("1", "2"))
 }
 
+func TestCTEDedupUnionAllAndTruncation(t *testing.T) {
+	store, close := testkit.CreateMockStore(t)
+	defer close()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test;")
+	tk.MustExec("drop table if exists edges;")
+	tk.MustExec("create table edges(src int, dst int);")
+	tk.MustExec("insert into edges values (1, 2), (2, 3), (3, 1), (3, 4);")
+	traverse := "with recursive cte(n) as (select 1 union all select dst from edges join cte on src = n) select * from cte order by n;"
+
+	// The traversal of the cyclic graph never ends unless the UNION ALL is deduplicated.
+	tk.MustExec("set @@cte_max_recursion_depth = 100;")
+	err := tk.QueryToErr(traverse)
+	require.EqualError(t, err, "[executor:3636]Recursive query aborted after 101 iterations. Try increasing @@cte_max_recursion_depth to a larger value")
+
+	tk.MustExec("set @@tidb_cte_dedup_union_all = 1;")
+	tk.MustQuery(traverse).Check(testkit.Rows("1", "2", "3", "4"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 Recursive query skipped 1 duplicate rows because of @@tidb_cte_dedup_union_all"))
+	// UNION DISTINCT isn't affected.
+	tk.MustQuery("with recursive cte(n) as (select 1 union select dst from edges join cte on src = n) select * from cte order by n;").Check(testkit.Rows("1", "2", "3", "4"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustExec("set @@tidb_cte_dedup_union_all = 0;")
+
+	// The rows produced before reaching the limits are returned with a warning.
+	tk.MustExec("set @@tidb_cte_truncate_on_limit = 1;")
+	tk.MustExec("set @@cte_max_recursion_depth = 3;")
+	tk.MustQuery(traverse).Check(testkit.Rows("1", "1", "2", "3", "4"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 3636 Recursive query aborted after 4 iterations. Try increasing @@cte_max_recursion_depth to a larger value"))
+
+	tk.MustExec("set @@cte_max_recursion_depth = 1000;")
+	tk.MustExec("set @@tidb_cte_mem_quota = 1;")
+	tk.MustQuery("with recursive cte(n) as (select 1 union all select n + 1 from cte where n < 10) select * from cte;").Check(testkit.Rows("1"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 8245 Recursive query aborted after 1 iterations because the intermediate results exceed @@tidb_cte_mem_quota (1 bytes)"))
+	tk.MustExec("set @@tidb_cte_truncate_on_limit = 0;")
+	err = tk.QueryToErr("with recursive cte(n) as (select 1 union all select n + 1 from cte where n < 10) select * from cte;")
+	require.EqualError(t, err, "[executor:8245]Recursive query aborted after 1 iterations because the intermediate results exceed @@tidb_cte_mem_quota (1 bytes)")
+	tk.MustExec("set @@tidb_cte_mem_quota = 0;")
+	tk.MustQuery("with recursive cte(n) as (select 1 union all select n + 1 from cte where n < 10) select count(*) from cte;").Check(testkit.Rows("10"))
+}
+
 func TestCTEWithLimit(t *testing.T) {
 	store, close := testkit.CreateMockStore(t)
 	defer close()
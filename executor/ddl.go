@@ -51,6 +51,9 @@ type DDLExec struct {
 	is           infoschema.InfoSchema
 	tempTableDDL temptable.TemporaryTableDDL
 	done         bool
+
+	// selectCols are the columns which the select result of CREATE TABLE ... SELECT is inserted into.
+	selectCols []model.CIStr
 }
 
 // toErr converts the error to the ErrInfoSchemaChanged when the schema is outdated.
@@ -146,7 +149,11 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	case *ast.CreateDatabaseStmt:
 		err = e.executeCreateDatabase(x)
 	case *ast.CreateTableStmt:
-		err = e.executeCreateTable(x)
+		if x.Select != nil {
+			err = e.executeCreateTableAsSelect(x)
+		} else {
+			err = e.executeCreateTable(x)
+		}
 	case *ast.CreateViewStmt:
 		err = e.executeCreateView(x)
 	case *ast.DropIndexStmt:
@@ -336,6 +343,10 @@ func (e *DDLExec) executeCreateTable(s *ast.CreateTableStmt) error {
 	return err
 }
 
+func (e *DDLExec) executeCreateTableAsSelect(s *ast.CreateTableStmt) error {
+	return domain.GetDomain(e.ctx).DDL().CreateTableAsSelect(e.ctx, s, e.selectCols)
+}
+
 func (e *DDLExec) createSessionTemporaryTable(s *ast.CreateTableStmt) error {
 	is := e.ctx.GetInfoSchema().(infoschema.InfoSchema)
 	dbInfo, ok := is.SchemaByName(s.Table.Schema)
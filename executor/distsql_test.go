@@ -422,3 +422,48 @@ func TestPartitionTableIndexJoinIndexLookUp(t *testing.T) {
 		tk.MustQuery("select /*+ TIDB_INLJ(t1, t2) */ t1.* from t t1, t t2 use index(a) where t1.a=t2.b and " + cond).Sort().Check(result)
 	}
 }
+
+func TestScanOperatorFusion(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, tp")
+	tk.MustExec("create table t(a int, b varchar(20), c int as (a + 1), d double)")
+	tk.MustExec("create table tp(a int, b int) partition by hash(a) partitions 4")
+	values := make([]string, 0, 2048)
+	for i := 0; i < 2048; i++ {
+		values = append(values, fmt.Sprintf("(%v, '%v', %v)", i, i%7, float64(i)/3))
+	}
+	tk.MustExec(fmt.Sprintf("insert into t(a, b, d) values %v", strings.Join(values, ", ")))
+	tk.MustExec("insert into tp select a, a % 10 from t")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	queries := []string{
+		"select * from t where a % 3 = 0 and d > 10",
+		"select a + 1, b from t where c > 1000",
+		"select a, c, b from t where b = '3' limit 5",
+		"select d * 2 from t where a < 0",
+		"select * from t where a in (1, 2, 3) or c > 2040",
+		"select count(*) from t where a % 5 = 1",
+		"select b, sum(a) from t where a > 100 group by b",
+		"select a, b + 1 from tp where b > 5 and a < 1000",
+		"select a, (select count(*) from tp where tp.b = t.a % 10 and tp.a < t.a) from t where a < 50",
+	}
+	for _, query := range queries {
+		tk.MustExec("set @@tidb_enable_scan_operator_fusion = 0")
+		result := tk.MustQuery(query).Sort().Rows()
+		tk.MustExec("set @@tidb_enable_scan_operator_fusion = 1")
+		tk.MustQuery(query).Sort().Check(result)
+	}
+
+	// Test the operators fused into the reader under union scan and locking read.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t(a, b, d) values (5000, 'x', 1)")
+	tk.MustQuery("select a, b from t where a > 2040 and b = 'x'").Check(testkit.Rows("5000 x"))
+	tk.MustQuery("select a + 1 from t where a = 2047 for update").Check(testkit.Rows("2048"))
+	tk.MustExec("update t set d = d + 1 where a % 1000 = 0")
+	tk.MustExec("commit")
+	tk.MustQuery("select a from t where d - a / 3 > 0.5").Sort().Check(testkit.Rows("0", "1000", "2000"))
+}
@@ -38,6 +38,7 @@ var (
 	ErrCannotUser                    = dbterror.ClassExecutor.NewStd(mysql.ErrCannotUser)
 	ErrGrantRole                     = dbterror.ClassExecutor.NewStd(mysql.ErrGrantRole)
 	ErrPasswordFormat                = dbterror.ClassExecutor.NewStd(mysql.ErrPasswordFormat)
+	ErrInvalidUserAttributeJSON      = dbterror.ClassExecutor.NewStd(mysql.ErrInvalidUserAttributeJSON)
 	ErrCantChangeTxCharacteristics   = dbterror.ClassExecutor.NewStd(mysql.ErrCantChangeTxCharacteristics)
 	ErrPsManyParam                   = dbterror.ClassExecutor.NewStd(mysql.ErrPsManyParam)
 	ErrAdminCheckTable               = dbterror.ClassExecutor.NewStd(mysql.ErrAdminCheckTable)
@@ -64,6 +65,9 @@ var (
 	ErrNotSupportedWithSem           = dbterror.ClassOptimizer.NewStd(mysql.ErrNotSupportedWithSem)
 	ErrPluginIsNotLoaded             = dbterror.ClassExecutor.NewStd(mysql.ErrPluginIsNotLoaded)
 	ErrSetPasswordAuthPlugin         = dbterror.ClassExecutor.NewStd(mysql.ErrSetPasswordAuthPlugin)
+	ErrSQLBlocked                    = dbterror.ClassExecutor.NewStd(mysql.ErrSQLBlocked)
+	ErrCTEMemQuotaExceeded           = dbterror.ClassExecutor.NewStd(mysql.ErrCTEMemQuotaExceeded)
+	ErrResultRowsExceeded            = dbterror.ClassExecutor.NewStd(mysql.ErrResultRowsExceeded)
 	ErrFuncNotEnabled                = dbterror.ClassExecutor.NewStdErr(mysql.ErrNotSupportedYet, parser_mysql.Message("%-.32s is not supported. To enable this experimental feature, set '%-.32s' in the configuration file.", nil))
 
 	errUnsupportedFlashbackTmpTable = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("Recover/flashback table is not supported on temporary tables", nil))
@@ -21,6 +21,7 @@ import (
 	"runtime"
 	"runtime/pprof"
 	"runtime/trace"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -111,6 +112,9 @@ type baseExecutor struct {
 	children      []Executor
 	retFieldTypes []*types.FieldType
 	runtimeStats  *execdetails.BasicRuntimeStats
+	// chunkAlloc is the session level chunk allocator when the executor is built,
+	// it's nil if reusing chunks is disabled.
+	chunkAlloc chunk.Allocator
 }
 
 const (
@@ -199,6 +203,30 @@ func newFirstChunk(e Executor) *chunk.Chunk {
 	return chunk.New(base.retFieldTypes, base.initCap, base.maxChunkSize)
 }
 
+// tryNewCacheChunk creates a chunk from the session level chunk allocator if
+// reusing chunks is enabled. The chunks of the allocator are only recycled after
+// the statement, so it must only be used for the fixed working chunks of an
+// executor, not for the chunks kept in a container, e.g. the chunks to be sorted
+// or spilled, which are expected to be released by GC.
+func tryNewCacheChunk(e Executor) *chunk.Chunk {
+	base := e.base()
+	if base.chunkAlloc != nil {
+		return base.chunkAlloc.Alloc(base.retFieldTypes, base.initCap, base.maxChunkSize)
+	}
+	return chunk.New(base.retFieldTypes, base.initCap, base.maxChunkSize)
+}
+
+// disableChunkAlloc makes the executor tree not allocate chunks from the session
+// level chunk allocator. It's used for the executors which are built or opened
+// repeatedly in a statement, e.g. the inner side of apply and index join, whose
+// chunks would otherwise pile up in the allocator until the statement ends.
+func disableChunkAlloc(e Executor) {
+	e.base().chunkAlloc = nil
+	for _, child := range e.base().children {
+		disableChunkAlloc(child)
+	}
+}
+
 // newList creates a new List to buffer current executor's result.
 func newList(e Executor) *chunk.List {
 	base := e.base()
@@ -229,6 +257,10 @@ func newBaseExecutor(ctx sessionctx.Context, schema *expression.Schema, id int,
 		schema:       schema,
 		initCap:      ctx.GetSessionVars().InitChunkSize,
 		maxChunkSize: ctx.GetSessionVars().MaxChunkSize,
+		chunkAlloc:   ctx.GetSessionVars().ChunkAllocator(),
+	}
+	if maxChunkSize := ctx.GetSessionVars().StmtCtx.MaxChunkSize; maxChunkSize > 0 {
+		e.maxChunkSize = maxChunkSize
 	}
 	if ctx.GetSessionVars().StmtCtx.RuntimeStatsColl != nil {
 		if e.id > 0 {
@@ -888,6 +920,84 @@ func (e *ShowSlowExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	return nil
 }
 
+// KillTopMemoryExec represents the executor of killing the connections consuming the most memory.
+// It is built from the "admin kill top memory N" statement. The connections are ordered by
+// the memory consumed, including the network buffers, and then by the disk consumed.
+// The current connection is never killed.
+type KillTopMemoryExec struct {
+	baseExecutor
+
+	topN   int64
+	result []*util.ProcessInfo
+	cursor int
+}
+
+// Open implements the Executor Open interface.
+func (e *KillTopMemoryExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	sm := e.ctx.GetSessionManager()
+	if sm == nil {
+		return nil
+	}
+	e.result = e.result[:0]
+	for _, pi := range sm.ShowProcessList() {
+		if pi.ID == e.ctx.GetSessionVars().ConnectionID {
+			continue
+		}
+		e.result = append(e.result, pi)
+	}
+	sortProcessInfoByResource(e.result)
+	if int64(len(e.result)) > e.topN {
+		e.result = e.result[:e.topN]
+	}
+	for _, pi := range e.result {
+		logutil.Logger(ctx).Warn("kill the connection consuming too much memory",
+			zap.Uint64("conn", pi.ID), zap.Int64("mem", pi.MemConsumed()), zap.Int64("disk", pi.DiskConsumed()))
+		sm.Kill(pi.ID, false)
+	}
+	return nil
+}
+
+// sortProcessInfoByResource sorts the processes by the memory and then the disk consumed in descending order.
+func sortProcessInfoByResource(pl []*util.ProcessInfo) {
+	mem := make(map[uint64]int64, len(pl))
+	disk := make(map[uint64]int64, len(pl))
+	for _, pi := range pl {
+		mem[pi.ID], disk[pi.ID] = pi.MemConsumed(), pi.DiskConsumed()
+	}
+	sort.Slice(pl, func(i, j int) bool {
+		if mem[pl[i].ID] != mem[pl[j].ID] {
+			return mem[pl[i].ID] > mem[pl[j].ID]
+		}
+		if disk[pl[i].ID] != disk[pl[j].ID] {
+			return disk[pl[i].ID] > disk[pl[j].ID]
+		}
+		return pl[i].ID < pl[j].ID
+	})
+}
+
+// Next implements the Executor Next interface.
+func (e *KillTopMemoryExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for e.cursor < len(e.result) && req.NumRows() < e.maxChunkSize {
+		row := e.result[e.cursor].ToRowForShow(true)
+		req.AppendUint64(0, row[0].(uint64))
+		req.AppendString(1, row[1].(string))
+		req.AppendString(2, row[2].(string))
+		req.AppendInt64(3, e.result[e.cursor].MemConsumed())
+		req.AppendInt64(4, e.result[e.cursor].DiskConsumed())
+		if info, ok := row[7].(string); ok {
+			req.AppendString(5, info)
+		} else {
+			req.AppendNull(5)
+		}
+		e.cursor++
+	}
+	return nil
+}
+
 // SelectLockExec represents a select lock executor.
 // It is built from the "SELECT .. FOR UPDATE" or the "SELECT .. LOCK IN SHARE MODE" statement.
 // For "SELECT .. FOR UPDATE" statement, it locks every row key from source Executor.
@@ -1034,7 +1144,9 @@ func doLockKeys(ctx context.Context, se sessionctx.Context, lockCtx *tikvstore.L
 	keys = filterLockTableKeys(sessVars.StmtCtx, keys)
 	var lockKeyStats *tikvutil.LockKeysDetails
 	ctx = context.WithValue(ctx, tikvutil.LockKeysDetailCtxKey, &lockKeyStats)
+	sctx.SetWaitState(stmtctx.WaitStateLock)
 	err = txn.LockKeys(tikvutil.SetSessionID(ctx, se.GetSessionVars().ConnectionID), lockCtx, keys...)
+	sctx.SetWaitState("")
 	if lockKeyStats != nil {
 		sctx.MergeLockKeysExecDetails(lockKeyStats)
 	}
@@ -1161,7 +1273,7 @@ func (e *LimitExec) Open(ctx context.Context) error {
 	if err := e.baseExecutor.Open(ctx); err != nil {
 		return err
 	}
-	e.childResult = newFirstChunk(e.children[0])
+	e.childResult = tryNewCacheChunk(e.children[0])
 	e.cursor = 0
 	e.meetFirstBatch = e.begin == 0
 	return nil
@@ -1294,7 +1406,7 @@ func (e *SelectionExec) Open(ctx context.Context) error {
 func (e *SelectionExec) open(ctx context.Context) error {
 	e.memTracker = memory.NewTracker(e.id, -1)
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
-	e.childResult = newFirstChunk(e.children[0])
+	e.childResult = tryNewCacheChunk(e.children[0])
 	e.memTracker.Consume(e.childResult.MemoryUsage())
 	e.batched = expression.Vectorizable(e.filters)
 	if e.batched {
@@ -1474,7 +1586,7 @@ func (e *MaxOneRowExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		return ErrSubqueryMoreThan1Row
 	}
 
-	childChunk := newFirstChunk(e.children[0])
+	childChunk := tryNewCacheChunk(e.children[0])
 	err = Next(ctx, e.children[0], childChunk)
 	if err != nil {
 		return err
@@ -1707,6 +1819,7 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	sc.EnableOptimizeTrace = false
 	sc.OptimizeTracer = nil
 	sc.OptimizerCETrace = nil
+	sc.OptimizerCETraceDetails = nil
 
 	sc.InitMemTracker(memory.LabelForSQLText, vars.MemQuotaQuery)
 	sc.InitDiskTracker(memory.LabelForSQLText, -1)
@@ -31,14 +31,18 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	plannerutil "github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/session/txninfo"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	storeerr "github.com/pingcap/tidb/store/driver/error"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/ranger"
@@ -56,6 +60,7 @@ var (
 type mockSessionManager struct {
 	PS       []*util.ProcessInfo
 	serverID uint64
+	killed   []uint64
 }
 
 func (msm *mockSessionManager) ShowTxnList() []*txninfo.TxnInfo {
@@ -82,6 +87,7 @@ func (msm *mockSessionManager) GetProcessInfo(id uint64) (*util.ProcessInfo, boo
 
 // Kill implements the SessionManager.Kill interface.
 func (msm *mockSessionManager) Kill(cid uint64, query bool) {
+	msm.killed = append(msm.killed, cid)
 }
 
 func (msm *mockSessionManager) KillAllConnections() {
@@ -151,6 +157,53 @@ func TestShowProcessList(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestKillTopMemory(t *testing.T) {
+	newProcessInfo := func(id uint64, netBuffer, memBytes, diskBytes int64) *util.ProcessInfo {
+		sc := &stmtctx.StatementContext{
+			MemTracker:  memory.NewTracker(-1, -1),
+			DiskTracker: disk.NewTracker(-1, -1),
+		}
+		sc.MemTracker.Consume(memBytes)
+		sc.DiskTracker.Consume(diskBytes)
+		return &util.ProcessInfo{ID: id, User: "test", Host: "127.0.0.1", NetBufferBytes: netBuffer, StmtCtx: sc}
+	}
+	sm := &mockSessionManager{
+		PS: []*util.ProcessInfo{
+			newProcessInfo(1, 100, 0, 0),
+			newProcessInfo(2, 100, 200, 0),
+			newProcessInfo(3, 300, 0, 10),
+			newProcessInfo(4, 300, 0, 0),
+			newProcessInfo(5, 100, 1000, 0),
+		},
+	}
+	sctx := mock.NewContext()
+	sctx.SetSessionManager(sm)
+	// The current connection consumes the most memory but is never killed.
+	sctx.GetSessionVars().ConnectionID = 5
+
+	names := []string{"ID", "USER", "HOST", "MEM", "DISK", "INFO"}
+	ftypes := []byte{mysql.TypeLonglong, mysql.TypeVarchar, mysql.TypeVarchar,
+		mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeVarchar}
+	e := &KillTopMemoryExec{
+		baseExecutor: newBaseExecutor(sctx, buildSchema(names, ftypes), 0),
+		topN:         3,
+	}
+	ctx := context.Background()
+	require.NoError(t, e.Open(ctx))
+	require.Equal(t, []uint64{3, 2, 4}, sm.killed)
+
+	chk := newFirstChunk(e)
+	require.NoError(t, e.Next(ctx, chk))
+	require.Equal(t, 3, chk.NumRows())
+	require.Equal(t, uint64(3), chk.GetRow(0).GetUint64(0))
+	require.Equal(t, int64(300), chk.GetRow(0).GetInt64(3))
+	require.Equal(t, int64(10), chk.GetRow(0).GetInt64(4))
+	require.True(t, chk.GetRow(0).IsNull(5))
+	require.NoError(t, e.Next(ctx, chk))
+	require.Equal(t, 0, chk.NumRows())
+	require.NoError(t, e.Close())
+}
+
 func buildSchema(names []string, ftypes []byte) *expression.Schema {
 	schema := expression.NewSchema(make([]*expression.Column, 0, len(names))...)
 	for i := range names {
@@ -394,6 +447,79 @@ func TestFilterTemporaryTableKeys(t *testing.T) {
 	require.Len(t, res, 1)
 }
 
+func TestLockWaitTimeoutRetryable(t *testing.T) {
+	ctx := mock.NewContext()
+	vars := ctx.GetSessionVars()
+	a := &ExecStmt{Ctx: ctx}
+
+	// The retry is disabled by default.
+	require.False(t, a.isLockWaitTimeoutRetryable(storeerr.ErrLockWaitTimeout))
+
+	vars.LockWaitTimeoutRetryLimit = 2
+	require.True(t, a.isLockWaitTimeoutRetryable(storeerr.ErrLockWaitTimeout))
+	require.False(t, a.isLockWaitTimeoutRetryable(kv.ErrWriteConflict))
+
+	// The statements in an explicit transaction are not retried.
+	vars.SetInTxn(true)
+	require.False(t, a.isLockWaitTimeoutRetryable(storeerr.ErrLockWaitTimeout))
+	vars.SetInTxn(false)
+	vars.SetStatusFlag(mysql.ServerStatusAutocommit, false)
+	require.False(t, a.isLockWaitTimeoutRetryable(storeerr.ErrLockWaitTimeout))
+	vars.SetStatusFlag(mysql.ServerStatusAutocommit, true)
+
+	// SELECT FOR UPDATE is not retried.
+	a.isSelectForUpdate = true
+	require.False(t, a.isLockWaitTimeoutRetryable(storeerr.ErrLockWaitTimeout))
+	a.isSelectForUpdate = false
+
+	a.lockWaitTimeoutRetryCount = 2
+	require.False(t, a.isLockWaitTimeoutRetryable(storeerr.ErrLockWaitTimeout))
+
+	require.NoError(t, lockWaitTimeoutRetryBackoff(context.Background(), 0))
+	require.NoError(t, lockWaitTimeoutRetryBackoff(context.Background(), time.Millisecond))
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, lockWaitTimeoutRetryBackoff(cancelCtx, time.Hour))
+}
+
+func TestAdaptiveMaxChunkSize(t *testing.T) {
+	ctx := mock.NewContext()
+	vars := ctx.GetSessionVars()
+	vars.MaxChunkSize = variable.DefMaxChunkSize
+	newSchema := func(tp *types.FieldType, n int) *expression.Schema {
+		schema := expression.NewSchema()
+		for i := 0; i < n; i++ {
+			schema.Append(&expression.Column{UniqueID: int64(i), RetType: tp})
+		}
+		return schema
+	}
+	// Each blob column is estimated to be 516 bytes wide.
+	blobTp := types.NewFieldType(mysql.TypeBlob)
+	blobTp.Flen = mysql.MaxBlobWidth
+	narrowSchema, wideSchema := newSchema(types.NewFieldType(mysql.TypeLonglong), 4), newSchema(blobTp, 8)
+
+	narrowPlan := plannercore.PhysicalLimit{Count: 1}.Init(ctx, nil, 0)
+	narrowPlan.SetSchema(narrowSchema)
+	require.Equal(t, 4*8, estimatePlanRowWidth(narrowPlan))
+	setStmtMaxChunkSize(vars, narrowPlan)
+	require.Equal(t, 1024, newBaseExecutor(ctx, narrowSchema, 0).maxChunkSize)
+
+	// All the executors of the statement use the chunk size of the widest rows.
+	widePlan := plannercore.PhysicalLimit{Count: 1}.Init(ctx, nil, 0)
+	widePlan.SetSchema(wideSchema)
+	narrowPlan.SetChildren(widePlan)
+	require.Equal(t, 8*516, estimatePlanRowWidth(narrowPlan))
+	setStmtMaxChunkSize(vars, narrowPlan)
+	narrow := newBaseExecutor(ctx, narrowSchema, 0)
+	require.Equal(t, (1<<20)/(8*516), narrow.maxChunkSize)
+	require.Equal(t, narrow.maxChunkSize, newFirstChunk(&narrow).RequiredRows())
+	require.Equal(t, narrow.maxChunkSize, newBaseExecutor(ctx, wideSchema, 0).maxChunkSize)
+
+	vars.MaxChunkBytes = 0
+	setStmtMaxChunkSize(vars, narrowPlan)
+	require.Equal(t, 1024, newBaseExecutor(ctx, wideSchema, 0).maxChunkSize)
+}
+
 func TestLoadDataWithDifferentEscapeChar(t *testing.T) {
 	tests := []struct {
 		input      string
@@ -368,6 +368,7 @@ func (s *testSuiteP1) TestShow(c *C) {
 		"RESTRICTED_USER_ADMIN Server Admin ",
 		"RESTRICTED_CONNECTION_ADMIN Server Admin ",
 		"RESTRICTED_REPLICA_WRITER_ADMIN Server Admin ",
+		"STATUS_API_CLIENT Server Admin ",
 	))
 	c.Assert(len(tk.MustQuery("show table status").Rows()), Equals, 1)
 }
@@ -579,6 +580,38 @@ func (s *testSuiteP2) TestAdminShowDDLJobs(c *C) {
 	c.Assert(row[9], Equals, "<nil>")
 }
 
+func (s *testSuiteP2) TestAdminShowDDLJobsLimit(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("drop database if exists test_admin_show_ddl_jobs_limit")
+	tk.MustExec("create database test_admin_show_ddl_jobs_limit")
+	defer tk.MustExec("drop database if exists test_admin_show_ddl_jobs_limit")
+	tk.MustExec("use test_admin_show_ddl_jobs_limit")
+	for i := 1; i <= admin.DefNumHistoryJobs+2; i++ {
+		tk.MustExec(fmt.Sprintf("create table t%d (a int)", i))
+	}
+
+	// Only the last DefNumHistoryJobs jobs are filtered without the limit.
+	tk.MustQuery("admin show ddl jobs where db_name = 'test_admin_show_ddl_jobs_limit' and table_name = 't1'").Check(testkit.Rows())
+	rows := tk.MustQuery("admin show ddl jobs where db_name = 'test_admin_show_ddl_jobs_limit' and table_name = 't1' limit 1").Rows()
+	c.Assert(rows, HasLen, 1)
+	c.Assert(rows[0][2], Equals, "t1")
+	c.Assert(rows[0][3], Equals, "create table")
+
+	rows = tk.MustQuery("admin show ddl jobs where db_name = 'test_admin_show_ddl_jobs_limit' and job_type = 'create table' limit 2, 3").Rows()
+	c.Assert(rows, HasLen, 3)
+	c.Assert(rows[0][2], Equals, "t10")
+	c.Assert(rows[1][2], Equals, "t9")
+	c.Assert(rows[2][2], Equals, "t8")
+	rows = tk.MustQuery("admin show ddl jobs where state = 'synced' limit 1 offset 11").Rows()
+	c.Assert(rows, HasLen, 1)
+	c.Assert(rows[0][2], Equals, "t1")
+
+	// The job number limits the jobs to be filtered.
+	tk.MustQuery("admin show ddl jobs 5 where table_name = 't1' limit 1").Check(testkit.Rows())
+	c.Assert(tk.MustQuery("admin show ddl jobs 5 limit 3").Rows(), HasLen, 3)
+	c.Assert(tk.MustQuery("admin show ddl jobs 2 limit 3").Rows(), HasLen, 2)
+}
+
 func (s *testSuiteP2) TestAdminShowDDLJobsInfo(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("drop database if exists test_admin_show_ddl_jobs")
@@ -5352,7 +5385,7 @@ func (s *testSplitTable) TestShowTableRegion(c *C) {
 	// 4 regions to store record data.
 	// 1 region to store index data.
 	c.Assert(len(rows), Equals, 5)
-	c.Assert(len(rows[0]), Equals, 11)
+	c.Assert(len(rows[0]), Equals, 12)
 	tbl := testGetTableByName(c, tk.Se, "test", "t_regions")
 	// Check the region start key.
 	c.Assert(rows[0][1], Equals, fmt.Sprintf("t_%d_r", tbl.Meta().ID))
@@ -5390,6 +5423,29 @@ func (s *testSplitTable) TestShowTableRegion(c *C) {
 	c.Assert(rows[6][1], Matches, fmt.Sprintf("t_%d_i_1_.*", tbl.Meta().ID))
 	c.Assert(rows[7][2], Equals, fmt.Sprintf("t_%d_i_2_", tbl.Meta().ID))
 	c.Assert(rows[8][2], Equals, fmt.Sprintf("t_%d_r", tbl.Meta().ID))
+	c.Assert(rows[0][11], Not(Equals), "")
+
+	// Test show table regions with the filters and the limit.
+	re = tk.MustQuery(fmt.Sprintf("show table t_regions regions where region_id = %s", rows[1][0]))
+	c.Assert(re.Rows(), HasLen, 1)
+	c.Assert(re.Rows()[0][1], Equals, rows[1][1])
+	re = tk.MustQuery(fmt.Sprintf("show table t_regions regions where region_id in (%s, %s) and leader_store_id = %s", rows[2][0], rows[5][0], rows[2][4]))
+	c.Assert(re.Rows(), HasLen, 2)
+	c.Assert(re.Rows()[0][1], Equals, rows[2][1])
+	c.Assert(re.Rows()[1][1], Equals, rows[5][1])
+	re = tk.MustQuery(fmt.Sprintf("show table t_regions regions where region_id = %s and start_key like 't%%'", rows[3][0]))
+	c.Assert(re.Rows(), HasLen, 1)
+	c.Assert(re.Rows()[0][1], Equals, rows[3][1])
+	tk.MustQuery(fmt.Sprintf("show table t_regions regions where region_id = %s and region_id = %s", rows[3][0], rows[4][0])).Check(testkit.Rows())
+	re = tk.MustQuery("show table t_regions regions limit 2")
+	c.Assert(re.Rows(), HasLen, 2)
+	c.Assert(re.Rows()[1][1], Equals, rows[1][1])
+	re = tk.MustQuery("show table t_regions regions limit 7, 10")
+	c.Assert(re.Rows(), HasLen, 2)
+	c.Assert(re.Rows()[0][1], Equals, rows[7][1])
+	re = tk.MustQuery("show table t_regions regions where start_key like 't%' limit 3")
+	c.Assert(re.Rows(), HasLen, 3)
+	tk.MustQuery("show table t_regions regions where leader_store_id = -1").Check(testkit.Rows())
 
 	// Test unsigned primary key and wait scatter finish.
 	tk.MustExec("drop table if exists t_regions")
@@ -7716,6 +7772,32 @@ func (s *testCoprCache) TestIntegrationCopCache(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(hitRatio > 0, Equals, true)
 
+	// Test for the IGNORE_COPR_CACHE hint.
+	rows = tk.MustQuery("explain analyze select /*+ ignore_copr_cache() */ * from t").Rows()
+	c.Assert(rows[0][2], Equals, "12")
+	c.Assert(strings.Contains(rows[0][5].(string), "copr_cache_hit_ratio: 0.00"), Equals, true)
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
+
+	// Test for the statistics of the cache.
+	rows = tk.MustQuery("select hits, invalidated, hit_ratio from information_schema.tidb_coprocessor_cache").Rows()
+	hits, err := strconv.ParseUint(rows[0][0].(string), 10, 64)
+	c.Assert(err, IsNil)
+	c.Assert(hits > 0, Equals, true)
+	invalidated, err := strconv.ParseUint(rows[0][1].(string), 10, 64)
+	c.Assert(err, IsNil)
+	hitRatio, err = strconv.ParseFloat(rows[0][2].(string), 64)
+	c.Assert(err, IsNil)
+	c.Assert(hitRatio > 0, Equals, true)
+
+	// The cached results are invalidated when the region data version changes.
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/store/mockstore/unistore/cophandler/mockCopCacheInUnistore", `return(124)`), IsNil)
+	rows = tk.MustQuery("explain analyze select * from t").Rows()
+	c.Assert(strings.Contains(rows[0][5].(string), "copr_cache_hit_ratio: 0.00"), Equals, true)
+	rows = tk.MustQuery("select invalidated from information_schema.tidb_coprocessor_cache").Rows()
+	newInvalidated, err := strconv.ParseUint(rows[0][0].(string), 10, 64)
+	c.Assert(err, IsNil)
+	c.Assert(newInvalidated > invalidated, Equals, true)
+
 	// Test for cop cache disabled.
 	cfg := config.NewConfig()
 	cfg.TiKVClient.CoprCache.CapacityMB = 0
@@ -353,6 +353,10 @@ func (e *GrantExec) grantGlobalPriv(ctx sessionctx.Context, user *ast.UserSpec)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	priv, err = keepGlobalPrivResourceGroup(context.Background(), ctx, user.User.Username, user.User.Hostname, priv)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	_, err = ctx.(sqlexec.SQLExecutor).ExecuteInternal(context.Background(), `UPDATE %n.%n SET PRIV=%? WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.GlobalPrivTable, priv, user.User.Username, user.User.Hostname)
 	return err
 }
@@ -436,6 +440,36 @@ func tlsOption2GlobalPriv(tlsOptions []*ast.TLSOption) (priv []byte, err error)
 	return
 }
 
+// setGlobalPrivResourceGroup sets the resource group in the global privilege data of mysql.global_priv.
+func setGlobalPrivResourceGroup(privData []byte, resourceGroup string) ([]byte, error) {
+	var gp privileges.GlobalPrivValue
+	if len(privData) > 0 {
+		if err := json.Unmarshal(privData, &gp); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	gp.ResourceGroup = resourceGroup
+	return json.Marshal(&gp)
+}
+
+// keepGlobalPrivResourceGroup keeps the resource group of the user in the new global privilege data of mysql.global_priv.
+func keepGlobalPrivResourceGroup(ctx context.Context, sctx sessionctx.Context, user, host string, privData []byte) ([]byte, error) {
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	rows, _, err := exec.ExecRestrictedSQL(ctx, nil, `SELECT Priv FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.GlobalPrivTable, user, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return privData, nil
+	}
+	var oldPriv privileges.GlobalPrivValue
+	// The broken privilege data is overwritten.
+	if err = json.Unmarshal([]byte(rows[0].GetString(0)), &oldPriv); err != nil || oldPriv.ResourceGroup == "" {
+		return privData, nil
+	}
+	return setGlobalPrivResourceGroup(privData, oldPriv.ResourceGroup)
+}
+
 // grantLevelPriv grants priv to user in s.Level scope.
 func (e *GrantExec) grantLevelPriv(priv *ast.PrivElem, user *ast.UserSpec, internalSession sessionctx.Context) error {
 	if priv.Priv == mysql.ExtendedPriv {
@@ -500,3 +500,65 @@ func TestIndexMergeSplitTable(t *testing.T) {
 	tk.MustExec("SPLIT TABLE tab2 BY (5);")
 	tk.MustQuery("SELECT /*+ use_index_merge(tab2) */ pk FROM tab2 WHERE (col4 > 565.89 OR col0 > 68 ) and col0 > 10 order by 1;").Check(testkit.Rows("0", "1", "2", "3", "4", "5", "6", "7"))
 }
+
+func TestIndexMergeInDML(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_index_merge = 1")
+	tk.MustExec("create table t(a int, b int, c int, key(a), key(b))")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 5, 4)")
+
+	// DELETE and UPDATE read the rows by IndexMerge instead of a full table scan.
+	tk.MustQuery("explain delete from t where a = 1 or b = 5").Check(testkit.Rows(
+		"Delete_4 N/A root  N/A",
+		"└─IndexMerge_12 19.99 root  ",
+		"  ├─IndexRangeScan_9(Build) 10.00 cop[tikv] table:t, index:a(a) range:[1,1], keep order:false, stats:pseudo",
+		"  ├─IndexRangeScan_10(Build) 10.00 cop[tikv] table:t, index:b(b) range:[5,5], keep order:false, stats:pseudo",
+		"  └─TableRowIDScan_11(Probe) 19.99 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("explain update t set c = 10 where a = 1 or b = 5").Check(testkit.Rows(
+		"Update_4 N/A root  N/A",
+		"└─IndexMerge_12 19.99 root  ",
+		"  ├─IndexRangeScan_9(Build) 10.00 cop[tikv] table:t, index:a(a) range:[1,1], keep order:false, stats:pseudo",
+		"  ├─IndexRangeScan_10(Build) 10.00 cop[tikv] table:t, index:b(b) range:[5,5], keep order:false, stats:pseudo",
+		"  └─TableRowIDScan_11(Probe) 19.99 cop[tikv] table:t keep order:false, stats:pseudo"))
+
+	tk.MustExec("update t set a = 7 where a = 1 or b = 5")
+	tk.MustQuery("select * from t").Sort().Check(testkit.Rows("2 2 2", "3 3 3", "7 1 1", "7 5 4"))
+	tk.MustExec("delete from t where a = 7 or b = 2 limit 2")
+	tk.MustQuery("select * from t").Sort().Check(testkit.Rows("2 2 2", "3 3 3"))
+	tk.MustExec("admin check table t")
+
+	// The rows written in the transaction are read through UnionScan.
+	for _, mode := range []string{"optimistic", "pessimistic"} {
+		tk.MustExec("begin " + mode)
+		tk.MustExec("insert into t values (5, 5, 5)")
+		requireIndexMerge(t, tk, "explain update /*+ use_index_merge(t) */ t set c = 10 where a = 2 or b = 5")
+		requireIndexMerge(t, tk, "explain delete /*+ use_index_merge(t) */ from t where a = 3 or b = 5")
+		tk.MustExec("update /*+ use_index_merge(t) */ t set c = 10 where a = 2 or b = 5")
+		tk.MustExec("delete /*+ use_index_merge(t) */ from t where a = 3 or b = 5")
+		tk.MustQuery("select * from t").Check(testkit.Rows("2 2 10"))
+		tk.MustExec("rollback")
+	}
+	tk.MustQuery("select * from t").Sort().Check(testkit.Rows("2 2 2", "3 3 3"))
+
+	tk.MustExec("create table tp(a int, b int, c int, key(a), key(b)) partition by hash(a) partitions 2")
+	tk.MustExec("insert into tp values (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 5, 4)")
+	requireIndexMerge(t, tk, "explain delete /*+ use_index_merge(tp) */ from tp where a = 1 or b = 5")
+	requireIndexMerge(t, tk, "explain update /*+ use_index_merge(tp) */ tp set c = 9 where a = 2 or b = 3")
+	tk.MustExec("delete /*+ use_index_merge(tp) */ from tp where a = 1 or b = 5")
+	tk.MustExec("update /*+ use_index_merge(tp) */ tp set c = 9 where a = 2 or b = 3")
+	tk.MustQuery("select * from tp").Sort().Check(testkit.Rows("2 2 9", "3 3 9"))
+	tk.MustExec("admin check table tp")
+}
+
+// requireIndexMerge checks that the plan of the statement reads the table by IndexMerge.
+func requireIndexMerge(t *testing.T, tk *testkit.TestKit, sql string) {
+	for _, row := range tk.MustQuery(sql).Rows() {
+		if strings.Contains(row[0].(string), "IndexMerge") {
+			return
+		}
+	}
+	require.FailNow(t, "the plan doesn't use IndexMerge", sql)
+}
@@ -34,13 +34,17 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/deadlock"
 	"github.com/pingcap/tidb/ddl/label"
+	"github.com/pingcap/tidb/ddl/placement"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -53,12 +57,15 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/sessiontxn"
 	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/store/copr"
 	"github.com/pingcap/tidb/store/helper"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	binaryJson "github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/deadlockhistory"
 	"github.com/pingcap/tidb/util/keydecoder"
@@ -70,6 +77,8 @@ import (
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/pingcap/tidb/util/stringutil"
+	"github.com/pingcap/tidb/util/writeconflicthistory"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
 
@@ -108,7 +117,7 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 		case infoschema.TableReferConst:
 			err = e.setDataFromReferConst(ctx, sctx, dbs)
 		case infoschema.TableSequences:
-			e.setDataFromSequences(sctx, dbs)
+			err = e.setDataFromSequences(sctx, dbs)
 		case infoschema.TablePartitions:
 			err = e.setDataFromPartitions(ctx, sctx, dbs)
 		case infoschema.TableClusterInfo:
@@ -164,6 +173,22 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataForAttributes(sctx)
 		case infoschema.TablePlacementPolicies:
 			err = e.setDataFromPlacementPolicies(sctx)
+		case infoschema.TableCoprocessorCache:
+			e.setDataForCoprocessorCache()
+		case infoschema.TableMDLView:
+			e.setDataForMDLView(sctx)
+		case infoschema.TableTrxSummary,
+			infoschema.ClusterTableTrxSummary:
+			err = e.setDataForTrxSummary(sctx)
+		case infoschema.TableRegionCacheStats,
+			infoschema.ClusterTableRegionCacheStats:
+			err = e.setDataForRegionCacheStats(ctx, sctx)
+		case infoschema.TablePlacementStatus:
+			err = e.setDataForPlacementStatus(ctx, sctx)
+		case infoschema.TableSessionWriteConflicts:
+			e.setDataForSessionWriteConflicts(sctx)
+		case infoschema.TableUserAttributes:
+			err = e.setDataFromUserAttributes(ctx, sctx)
 		}
 		if err != nil {
 			return nil, err
@@ -186,18 +211,23 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 	return adjustColumns(ret, e.columns, e.table), nil
 }
 
-func getRowCountAllTable(ctx context.Context, sctx sessionctx.Context) (map[int64]uint64, error) {
+// tableStatsMeta is the row count and the version of the stats of a physical table.
+type tableStatsMeta struct {
+	count   uint64
+	version uint64
+}
+
+func getRowCountAllTable(ctx context.Context, sctx sessionctx.Context) (map[int64]tableStatsMeta, error) {
 	exec := sctx.(sqlexec.RestrictedSQLExecutor)
-	rows, _, err := exec.ExecRestrictedSQL(ctx, nil, "select table_id, count from mysql.stats_meta")
+	rows, _, err := exec.ExecRestrictedSQL(ctx, nil, "select table_id, count, version from mysql.stats_meta")
 	if err != nil {
 		return nil, err
 	}
 
-	rowCountMap := make(map[int64]uint64, len(rows))
+	rowCountMap := make(map[int64]tableStatsMeta, len(rows))
 	for _, row := range rows {
 		tableID := row.GetInt64(0)
-		rowCnt := row.GetUint64(1)
-		rowCountMap[tableID] = rowCnt
+		rowCountMap[tableID] = tableStatsMeta{count: row.GetUint64(1), version: row.GetUint64(2)}
 	}
 	return rowCountMap, nil
 }
@@ -263,7 +293,7 @@ func getDataAndIndexLength(info *model.TableInfo, physicalID int64, rowCount uin
 type statsCache struct {
 	mu         sync.RWMutex
 	modifyTime time.Time
-	tableRows  map[int64]uint64
+	tableRows  map[int64]tableStatsMeta
 	colLength  map[tableHistID]uint64
 }
 
@@ -272,7 +302,7 @@ var tableStatsCache = &statsCache{}
 // TableStatsCacheExpiry is the expiry time for table stats cache.
 var TableStatsCacheExpiry = 3 * time.Second
 
-func (c *statsCache) get(ctx context.Context, sctx sessionctx.Context) (map[int64]uint64, map[tableHistID]uint64, error) {
+func (c *statsCache) get(ctx context.Context, sctx sessionctx.Context) (map[int64]tableStatsMeta, map[tableHistID]uint64, error) {
 	c.mu.RLock()
 	if time.Since(c.modifyTime) < TableStatsCacheExpiry {
 		tableRows, colLength := c.tableRows, c.colLength
@@ -301,6 +331,105 @@ func (c *statsCache) get(ctx context.Context, sctx sessionctx.Context) (map[int6
 	return tableRows, colLength, nil
 }
 
+// pdRegions is the regions fetched from PD, sorted by the start key so the
+// regions of a key range can be located by binary search.
+type pdRegions []helper.RegionInfo
+
+func newPDRegions(regions []helper.RegionInfo) pdRegions {
+	sort.Slice(regions, func(i, j int) bool { return regions[i].StartKey < regions[j].StartKey })
+	return regions
+}
+
+// overlapping returns the regions overlapping the key range [startKey, endKey).
+func (r pdRegions) overlapping(startKey, endKey kv.Key) pdRegions {
+	start := helper.BytesKeyToHex(codec.EncodeBytes(nil, startKey))
+	end := helper.BytesKeyToHex(codec.EncodeBytes(nil, endKey))
+	i := sort.Search(len(r), func(i int) bool { return r[i].EndKey == "" || r[i].EndKey > start })
+	j := i
+	for j < len(r) && r[j].StartKey < end {
+		j++
+	}
+	return r[i:j]
+}
+
+// pdTableSizes computes the approximate sizes of the physical tables without
+// stats from the regions, which are fetched from PD at most once per query.
+type pdTableSizes struct {
+	helper    *helper.Helper
+	fetched   bool
+	regions   pdRegions
+	fetchTime time.Time
+}
+
+// newPDTableSizes returns nil if PD is unavailable, e.g. the storage is not TiKV.
+func newPDTableSizes(sctx sessionctx.Context) *pdTableSizes {
+	tikvStore, ok := sctx.GetStore().(helper.Storage)
+	if !ok {
+		return nil
+	}
+	pdHelper := helper.NewHelper(tikvStore)
+	if _, err := pdHelper.GetPDAddr(); err != nil {
+		return nil
+	}
+	return &pdTableSizes{helper: pdHelper}
+}
+
+// get returns the data length and the index length of the physical table and
+// the time when the regions are fetched.
+func (s *pdTableSizes) get(physicalID int64) (dataLength, indexLength uint64, updateTime time.Time, ok bool) {
+	if s == nil {
+		return 0, 0, updateTime, false
+	}
+	if !s.fetched {
+		s.fetched = true
+		regionsInfo, err := s.helper.GetRegionsInfo()
+		if err != nil {
+			logutil.BgLogger().Warn("get regions from PD failed", zap.Error(err))
+			return 0, 0, updateTime, false
+		}
+		s.regions = newPDRegions(regionsInfo.Regions)
+		s.fetchTime = time.Now()
+	}
+	if s.regions == nil {
+		return 0, 0, updateTime, false
+	}
+	indexPrefix := tablecodec.GenTableIndexPrefix(physicalID)
+	recordPrefix := tablecodec.GenTableRecordPrefix(physicalID)
+	// The approximate size reported by PD is in MiB.
+	for _, region := range s.regions.overlapping(recordPrefix, tablecodec.GenTablePrefix(physicalID+1)) {
+		dataLength += uint64(region.ApproximateSize) << 20
+	}
+	for _, region := range s.regions.overlapping(indexPrefix, recordPrefix) {
+		indexLength += uint64(region.ApproximateSize) << 20
+	}
+	return dataLength, indexLength, s.fetchTime, true
+}
+
+// getPhysicalTableStats returns the row count, the data length, the index length
+// of the physical table and the time when they are collected. They come from the
+// stats if the table has stats, otherwise the lengths come from the regions in
+// PD. The update time is zero if neither is available.
+func getPhysicalTableStats(pdSizes *pdTableSizes, info *model.TableInfo, physicalID int64,
+	tableRowsMap map[int64]tableStatsMeta, colLengthMap map[tableHistID]uint64) (rowCount, dataLength, indexLength uint64, updateTime time.Time) {
+	if meta, ok := tableRowsMap[physicalID]; ok {
+		dataLength, indexLength = getDataAndIndexLength(info, physicalID, meta.count, colLengthMap)
+		return meta.count, dataLength, indexLength, oracle.GetTimeFromTS(meta.version)
+	}
+	if dataLength, indexLength, updateTime, ok := pdSizes.get(physicalID); ok {
+		return 0, dataLength, indexLength, updateTime
+	}
+	return 0, 0, 0, time.Time{}
+}
+
+// statsUpdateTimeDatum converts the update time of the stats to the value of
+// TIDB_STATS_UPDATE_TIME.
+func statsUpdateTimeDatum(updateTime time.Time) interface{} {
+	if updateTime.IsZero() {
+		return nil
+	}
+	return types.NewTime(types.FromGoTime(updateTime), mysql.TypeDatetime, types.DefaultFsp)
+}
+
 func getAutoIncrementID(ctx sessionctx.Context, schema *model.DBInfo, tblInfo *model.TableInfo) (int64, error) {
 	is := ctx.GetInfoSchema().(infoschema.InfoSchema)
 	tbl, err := is.TableByName(schema.Name, tblInfo.Name)
@@ -507,6 +636,7 @@ func (e *memtableRetriever) setDataFromTables(ctx context.Context, sctx sessionc
 	if err != nil {
 		return err
 	}
+	pdSizes := newPDTableSizes(sctx)
 
 	checker := privilege.GetPrivilegeManager(sctx)
 
@@ -514,6 +644,10 @@ func (e *memtableRetriever) setDataFromTables(ctx context.Context, sctx sessionc
 	createTimeTp := mysql.TypeDatetime
 	for _, schema := range schemas {
 		for _, table := range schema.Tables {
+			// The table being created by CREATE TABLE ... SELECT isn't listed until it's public.
+			if table.State == model.StateWriteReorganization {
+				continue
+			}
 			collation := table.Collate
 			if collation == "" {
 				collation = mysql.DefaultCollationName
@@ -542,15 +676,19 @@ func (e *memtableRetriever) setDataFromTables(ctx context.Context, sctx sessionc
 				}
 
 				var rowCount, dataLength, indexLength uint64
+				var updateTime time.Time
 				if table.GetPartitionInfo() == nil {
-					rowCount = tableRowsMap[table.ID]
-					dataLength, indexLength = getDataAndIndexLength(table, table.ID, rowCount, colLengthMap)
+					rowCount, dataLength, indexLength, updateTime = getPhysicalTableStats(pdSizes, table, table.ID, tableRowsMap, colLengthMap)
 				} else {
-					for _, pi := range table.GetPartitionInfo().Definitions {
-						rowCount += tableRowsMap[pi.ID]
-						parDataLen, parIndexLen := getDataAndIndexLength(table, pi.ID, tableRowsMap[pi.ID], colLengthMap)
+					for i, pi := range table.GetPartitionInfo().Definitions {
+						parRowCount, parDataLen, parIndexLen, parUpdateTime := getPhysicalTableStats(pdSizes, table, pi.ID, tableRowsMap, colLengthMap)
+						rowCount += parRowCount
 						dataLength += parDataLen
 						indexLength += parIndexLen
+						// The table is as fresh as its stalest partition.
+						if i == 0 || parUpdateTime.IsZero() || (!updateTime.IsZero() && parUpdateTime.Before(updateTime)) {
+							updateTime = parUpdateTime
+						}
 					}
 				}
 				avgRowLength := uint64(0)
@@ -570,6 +708,7 @@ func (e *memtableRetriever) setDataFromTables(ctx context.Context, sctx sessionc
 					pkType = "CLUSTERED"
 				}
 				shardingInfo := infoschema.GetShardingInfo(schema, table)
+				statsTime := statsUpdateTimeDatum(updateTime)
 				var policyName interface{}
 				if table.PlacementPolicyRef != nil {
 					policyName = table.PlacementPolicyRef.Name.O
@@ -600,6 +739,7 @@ func (e *memtableRetriever) setDataFromTables(ctx context.Context, sctx sessionc
 					shardingInfo,          // TIDB_ROW_ID_SHARDING_INFO
 					pkType,                // TIDB_PK_TYPE
 					policyName,            // TIDB_PLACEMENT_POLICY_NAME
+					statsTime,             // TIDB_STATS_UPDATE_TIME
 				)
 				rows = append(rows, record)
 			} else {
@@ -629,6 +769,7 @@ func (e *memtableRetriever) setDataFromTables(ctx context.Context, sctx sessionc
 					nil,                   // TIDB_ROW_ID_SHARDING_INFO
 					pkType,                // TIDB_PK_TYPE
 					nil,                   // TIDB_PLACEMENT_POLICY_NAME
+					nil,                   // TIDB_STATS_UPDATE_TIME
 				)
 				rows = append(rows, record)
 			}
@@ -647,6 +788,9 @@ func (e *hugeMemTableRetriever) setDataForColumns(ctx context.Context, sctx sess
 		for e.tblIdx < len(schema.Tables) {
 			table := schema.Tables[e.tblIdx]
 			e.tblIdx++
+			if table.State == model.StateWriteReorganization {
+				continue
+			}
 			hasPrivs := false
 			var priv mysql.PrivilegeType
 			if checker != nil {
@@ -827,6 +971,7 @@ func (e *memtableRetriever) setDataFromPartitions(ctx context.Context, sctx sess
 	if err != nil {
 		return err
 	}
+	pdSizes := newPDTableSizes(sctx)
 	checker := privilege.GetPrivilegeManager(sctx)
 	var rows [][]types.Datum
 	createTimeTp := mysql.TypeDatetime
@@ -837,10 +982,9 @@ func (e *memtableRetriever) setDataFromPartitions(ctx context.Context, sctx sess
 			}
 			createTime := types.NewTime(types.FromGoTime(table.GetUpdateTime()), createTimeTp, types.DefaultFsp)
 
-			var rowCount, dataLength, indexLength uint64
 			if table.GetPartitionInfo() == nil {
-				rowCount = tableRowsMap[table.ID]
-				dataLength, indexLength = getDataAndIndexLength(table, table.ID, rowCount, colLengthMap)
+				rowCount, dataLength, indexLength, updateTime := getPhysicalTableStats(pdSizes, table, table.ID, tableRowsMap, colLengthMap)
+				statsTime := statsUpdateTimeDatum(updateTime)
 				avgRowLength := uint64(0)
 				if rowCount != 0 {
 					avgRowLength = dataLength / rowCount
@@ -873,12 +1017,13 @@ func (e *memtableRetriever) setDataFromPartitions(ctx context.Context, sctx sess
 					nil,                   // TABLESPACE_NAME
 					nil,                   // TIDB_PARTITION_ID
 					nil,                   // TIDB_PLACEMENT_POLICY_NAME
+					statsTime,             // TIDB_STATS_UPDATE_TIME
 				)
 				rows = append(rows, record)
 			} else {
 				for i, pi := range table.GetPartitionInfo().Definitions {
-					rowCount = tableRowsMap[pi.ID]
-					dataLength, indexLength = getDataAndIndexLength(table, pi.ID, tableRowsMap[pi.ID], colLengthMap)
+					rowCount, dataLength, indexLength, updateTime := getPhysicalTableStats(pdSizes, table, pi.ID, tableRowsMap, colLengthMap)
+					statsTime := statsUpdateTimeDatum(updateTime)
 
 					avgRowLength := uint64(0)
 					if rowCount != 0 {
@@ -961,6 +1106,7 @@ func (e *memtableRetriever) setDataFromPartitions(ctx context.Context, sctx sess
 						nil,                   // TABLESPACE_NAME
 						pi.ID,                 // TIDB_PARTITION_ID
 						policyName,            // TIDB_PLACEMENT_POLICY_NAME
+						statsTime,             // TIDB_STATS_UPDATE_TIME
 					)
 					rows = append(rows, record)
 				}
@@ -1361,6 +1507,34 @@ func (e *memtableRetriever) setDataFromUserPrivileges(ctx sessionctx.Context) {
 	e.rows = pm.UserPrivilegesTable(ctx.GetSessionVars().ActiveRoles, ctx.GetSessionVars().User.Username, ctx.GetSessionVars().User.Hostname)
 }
 
+func (e *memtableRetriever) setDataFromUserAttributes(ctx context.Context, sctx sessionctx.Context) error {
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	chunkRows, _, err := exec.ExecRestrictedSQL(ctx, nil, `SELECT user, host, JSON_EXTRACT(user_attributes, '$.metadata') FROM mysql.user`)
+	if err != nil {
+		return err
+	}
+	// Users without the privilege to read mysql.user can only see their own attributes.
+	var curUser *auth.UserIdentity
+	if checker := privilege.GetPrivilegeManager(sctx); checker != nil &&
+		!checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, mysql.SystemDB, mysql.UserTable, "", mysql.SelectPriv) {
+		curUser = sctx.GetSessionVars().User
+	}
+	rows := make([][]types.Datum, 0, len(chunkRows))
+	for _, chunkRow := range chunkRows {
+		user, host := chunkRow.GetString(0), chunkRow.GetString(1)
+		if curUser != nil && (curUser.AuthUsername != user || curUser.AuthHostname != host) {
+			continue
+		}
+		var attribute interface{}
+		if !chunkRow.IsNull(2) {
+			attribute = chunkRow.GetJSON(2).String()
+		}
+		rows = append(rows, types.MakeDatums(user, host, attribute))
+	}
+	e.rows = rows
+	return nil
+}
+
 func (e *memtableRetriever) setDataForMetricTables(ctx sessionctx.Context) {
 	tables := make([]string, 0, len(infoschema.MetricTableMap))
 	for name := range infoschema.MetricTableMap {
@@ -1891,8 +2065,13 @@ func (e *memtableRetriever) setDataForServersInfo(ctx sessionctx.Context) error
 	return nil
 }
 
-func (e *memtableRetriever) setDataFromSequences(ctx sessionctx.Context, schemas []*model.DBInfo) {
+func (e *memtableRetriever) setDataFromSequences(ctx sessionctx.Context, schemas []*model.DBInfo) error {
 	checker := privilege.GetPrivilegeManager(ctx)
+	txn, err := ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	m := meta.NewMeta(txn)
 	var rows [][]types.Datum
 	for _, schema := range schemas {
 		for _, table := range schema.Tables {
@@ -1902,6 +2081,16 @@ func (e *memtableRetriever) setDataFromSequences(ctx sessionctx.Context, schemas
 			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.AllPrivMask) {
 				continue
 			}
+			// The values before the allocated value are cached or used by the TiDB servers.
+			acc := m.GetAutoIDAccessors(schema.ID, table.ID)
+			allocated, err := acc.SequenceValue().Get()
+			if err != nil {
+				return err
+			}
+			round, err := acc.SequenceCycle().Get()
+			if err != nil {
+				return err
+			}
 			record := types.MakeDatums(
 				infoschema.CatalogVal,     // TABLE_CATALOG
 				schema.Name.O,             // TABLE_SCHEMA
@@ -1914,11 +2103,14 @@ func (e *memtableRetriever) setDataFromSequences(ctx sessionctx.Context, schemas
 				table.Sequence.MinValue,   // MINVALUE
 				table.Sequence.Start,      // START
 				table.Sequence.Comment,    // COMMENT
+				allocated,                 // ALLOCATED_VALUE
+				round,                     // CYCLE_ROUND
 			)
 			rows = append(rows, record)
 		}
 	}
 	e.rows = rows
+	return nil
 }
 
 // dataForTableTiFlashReplica constructs data for table tiflash replica info.
@@ -1928,12 +2120,17 @@ func (e *memtableRetriever) dataForTableTiFlashReplica(ctx sessionctx.Context, s
 	if err != nil {
 		ctx.GetSessionVars().StmtCtx.AppendWarning(err)
 	}
+	detailMap, err := infosync.GetTiFlashTableSyncDetails(context.Background())
+	if err != nil {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+	}
 	for _, schema := range schemas {
 		for _, tbl := range schema.Tables {
 			if tbl.TiFlashReplica == nil {
 				continue
 			}
 			progress := 1.0
+			var details []*infosync.TiFlashTableSyncDetail
 			if !tbl.TiFlashReplica.Available {
 				if pi := tbl.GetPartitionInfo(); pi != nil && len(pi.Definitions) > 0 {
 					progress = 0
@@ -1942,11 +2139,17 @@ func (e *memtableRetriever) dataForTableTiFlashReplica(ctx sessionctx.Context, s
 							progress += 1
 						} else {
 							progress += progressMap[p.ID]
+							if detail, ok := detailMap[p.ID]; ok {
+								details = append(details, detail)
+							}
 						}
 					}
 					progress = progress / float64(len(pi.Definitions))
 				} else {
 					progress = progressMap[tbl.ID]
+					if detail, ok := detailMap[tbl.ID]; ok {
+						details = append(details, detail)
+					}
 				}
 			}
 			record := types.MakeDatums(
@@ -1958,12 +2161,43 @@ func (e *memtableRetriever) dataForTableTiFlashReplica(ctx sessionctx.Context, s
 				tbl.TiFlashReplica.Available,                         // AVAILABLE
 				progress,                                             // PROGRESS
 			)
+			record = append(record, tiflashReplicaSyncDetailDatums(details, ctx.GetSessionVars().Location())...)
+			record = append(record, types.NewDatum(tbl.TiFlashReplica.HighPriority)) // HIGH_PRIORITY
 			rows = append(rows, record)
 		}
 	}
 	e.rows = rows
 }
 
+// tiflashReplicaSyncDetailDatums merges the sync details of a table or its partitions into
+// REPLICATED_REGIONS, TOTAL_REGIONS, TOTAL_BYTES and ETA, they are NULL if no replica is being synchronized.
+// The ETA is NULL if any of the replicas can not be estimated.
+func tiflashReplicaSyncDetailDatums(details []*infosync.TiFlashTableSyncDetail, loc *time.Location) []types.Datum {
+	if len(details) == 0 {
+		return []types.Datum{{}, {}, {}, {}}
+	}
+	var replicated, total, bytes int64
+	var eta time.Time
+	etaKnown := true
+	for _, detail := range details {
+		replicated += int64(detail.ReplicatedRegions)
+		total += int64(detail.TotalRegions)
+		bytes += detail.TotalBytes
+		if detail.ETA.IsZero() {
+			etaKnown = false
+		} else if detail.ETA.After(eta) {
+			eta = detail.ETA
+		}
+	}
+	datums := types.MakeDatums(replicated, total, bytes)
+	if etaKnown {
+		datums = append(datums, types.NewTimeDatum(types.NewTime(types.FromGoTime(eta.In(loc)), mysql.TypeDatetime, types.DefaultFsp)))
+	} else {
+		datums = append(datums, types.Datum{})
+	}
+	return datums
+}
+
 func (e *memtableRetriever) setDataForStatementsSummaryEvicted(ctx sessionctx.Context) error {
 	if !hasPriv(ctx, mysql.ProcessPriv) {
 		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
@@ -2851,6 +3085,338 @@ func (e *memtableRetriever) setDataFromPlacementPolicies(sctx sessionctx.Context
 	return nil
 }
 
+// placementStatusObject is a table or partition which has placement.
+type placementStatusObject struct {
+	db        *model.DBInfo
+	table     *model.TableInfo
+	partition *model.PartitionDefinition
+	policy    *model.PolicyInfo
+}
+
+func (o *placementStatusObject) physicalID() int64 {
+	if o.partition != nil {
+		return o.partition.ID
+	}
+	return o.table.ID
+}
+
+func (e *memtableRetriever) setDataForPlacementStatus(ctx context.Context, sctx sessionctx.Context) error {
+	is := sessiontxn.GetTxnManager(sctx).GetTxnInfoSchema()
+	checker := privilege.GetPrivilegeManager(sctx)
+	getPolicy := func(ref *model.PolicyRefInfo) (*model.PolicyInfo, error) {
+		if ref == nil {
+			return nil, nil
+		}
+		policy, ok := is.PolicyByName(ref.Name)
+		if !ok {
+			return nil, errors.Errorf("Policy with name '%s' not found", ref.Name)
+		}
+		return policy, nil
+	}
+
+	var objects []*placementStatusObject
+	dbs := is.AllSchemas()
+	sort.Slice(dbs, func(i, j int) bool { return dbs[i].Name.L < dbs[j].Name.L })
+	for _, db := range dbs {
+		tables := make([]*model.TableInfo, 0, len(db.Tables))
+		for _, tbl := range is.SchemaTables(db.Name) {
+			tables = append(tables, tbl.Meta())
+		}
+		sort.Slice(tables, func(i, j int) bool { return tables[i].Name.L < tables[j].Name.L })
+		for _, tbl := range tables {
+			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, db.Name.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			tblPolicy, err := getPolicy(tbl.PlacementPolicyRef)
+			if err != nil {
+				return err
+			}
+			if tbl.Partition == nil {
+				if tblPolicy != nil {
+					objects = append(objects, &placementStatusObject{db: db, table: tbl, policy: tblPolicy})
+				}
+				continue
+			}
+			// The data of a partitioned table is placed by its partitions, which inherit the placement of the table.
+			for i := range tbl.Partition.Definitions {
+				def := &tbl.Partition.Definitions[i]
+				policy, err := getPolicy(def.PlacementPolicyRef)
+				if err != nil {
+					return err
+				}
+				if policy == nil {
+					policy = tblPolicy
+				}
+				if policy != nil {
+					objects = append(objects, &placementStatusObject{db: db, table: tbl, partition: def, policy: policy})
+				}
+			}
+		}
+	}
+	if len(objects) == 0 {
+		return nil
+	}
+
+	regions, storeLabels, err := getRegionsAndStoreLabelsFromPD(sctx)
+	if err != nil {
+		return err
+	}
+	rows := make([][]types.Datum, 0, len(objects))
+	for _, obj := range objects {
+		// The state is derived from the regions fetched once for all the objects, rather than
+		// asking PD for every object. It stays pending if the regions are unavailable.
+		state := infosync.PlacementScheduleStatePending
+		row := types.MakeDatums(
+			obj.db.Name.O,
+			obj.table.Name.O,
+			obj.table.ID,
+			nil,
+			nil,
+			obj.policy.Name.O,
+			obj.policy.PlacementSettings.String(),
+			state.String(),
+			nil,
+			nil,
+			nil,
+		)
+		if obj.partition != nil {
+			row[3].SetString(obj.partition.Name.O, mysql.DefaultCollationName)
+			row[4].SetInt64(obj.partition.ID)
+		}
+		if regions != nil {
+			bundle, err := placement.NewBundleFromOptions(obj.policy.PlacementSettings)
+			if err != nil {
+				return err
+			}
+			total, scheduled := countScheduledRegions(regions, storeLabels, bundle, obj.physicalID())
+			if total > 0 && scheduled == total {
+				state = infosync.PlacementScheduleStateScheduled
+			} else if scheduled > 0 {
+				state = infosync.PlacementScheduleStateInProgress
+			}
+			row[7].SetString(state.String(), mysql.DefaultCollationName)
+			row[8].SetInt64(total)
+			row[9].SetInt64(scheduled)
+			row[10].SetInt64(total - scheduled)
+		}
+		rows = append(rows, row)
+	}
+	e.rows = rows
+	return nil
+}
+
+// getRegionsAndStoreLabelsFromPD returns nil regions if the storage is not TiKV or PD is unavailable.
+func getRegionsAndStoreLabelsFromPD(sctx sessionctx.Context) (pdRegions, map[int64]map[string]string, error) {
+	tikvStore, ok := sctx.GetStore().(helper.Storage)
+	if !ok {
+		return nil, nil, nil
+	}
+	tikvHelper := &helper.Helper{
+		Store:       tikvStore,
+		RegionCache: tikvStore.GetRegionCache(),
+	}
+	if _, err := tikvHelper.GetPDAddr(); err != nil {
+		return nil, nil, nil
+	}
+	regionsInfo, err := tikvHelper.GetRegionsInfo()
+	if err != nil {
+		return nil, nil, err
+	}
+	storesStat, err := tikvHelper.GetStoresStat()
+	if err != nil {
+		return nil, nil, err
+	}
+	storeLabels := make(map[int64]map[string]string, len(storesStat.Stores))
+	for _, store := range storesStat.Stores {
+		labels := make(map[string]string, len(store.Store.Labels))
+		for _, label := range store.Store.Labels {
+			labels[label.Key] = label.Value
+		}
+		storeLabels[store.Store.ID] = labels
+	}
+	return newPDRegions(regionsInfo.Regions), storeLabels, nil
+}
+
+// countScheduledRegions counts the regions of the physical table, and the regions whose peers satisfy the rules of the bundle.
+func countScheduledRegions(regions pdRegions, storeLabels map[int64]map[string]string, bundle *placement.Bundle, physicalID int64) (total, scheduled int64) {
+	// Match the rules of the more specific roles first, so a voter rule doesn't take the peers of a leader or follower rule.
+	rolePriority := map[placement.PeerRoleType]int{placement.Leader: 0, placement.Follower: 1, placement.Learner: 2, placement.Voter: 3}
+	rules := make([]*placement.Rule, 0, len(bundle.Rules))
+	rules = append(rules, bundle.Rules...)
+	sort.SliceStable(rules, func(i, j int) bool { return rolePriority[rules[i].Role] < rolePriority[rules[j].Role] })
+	regions = regions.overlapping(tablecodec.GenTablePrefix(physicalID), tablecodec.GenTablePrefix(physicalID+1))
+	for i := range regions {
+		region := &regions[i]
+		total++
+		if isRegionScheduled(region, storeLabels, rules) {
+			scheduled++
+		}
+	}
+	return total, scheduled
+}
+
+func isRegionScheduled(region *helper.RegionInfo, storeLabels map[int64]map[string]string, rules []*placement.Rule) bool {
+	matched := make([]bool, len(region.Peers))
+	for _, rule := range rules {
+		count := 0
+		for i, peer := range region.Peers {
+			if count >= rule.Count {
+				break
+			}
+			if matched[i] || !rule.Constraints.MatchLabels(storeLabels[peer.StoreID]) {
+				continue
+			}
+			isLeader := peer.ID == region.Leader.ID
+			var roleMatched bool
+			switch rule.Role {
+			case placement.Leader:
+				roleMatched = isLeader
+			case placement.Follower:
+				roleMatched = !isLeader && !peer.IsLearner
+			case placement.Voter:
+				roleMatched = !peer.IsLearner
+			case placement.Learner:
+				roleMatched = peer.IsLearner
+			}
+			if roleMatched {
+				matched[i] = true
+				count++
+			}
+		}
+		if count < rule.Count {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *memtableRetriever) setDataForCoprocessorCache() {
+	stats := copr.GetCoprCacheStats()
+	e.rows = [][]types.Datum{types.MakeDatums(
+		stats.Requests,
+		stats.Hits,
+		stats.Invalidated,
+		stats.Admitted,
+		stats.HitRatio(),
+	)}
+}
+
+func (e *memtableRetriever) setDataForMDLView(sctx sessionctx.Context) {
+	do := domain.GetDomain(sctx)
+	is := do.InfoSchema()
+	for _, wait := range do.MDLWaits() {
+		jobID, query := types.NewDatum(nil), types.NewDatum(nil)
+		if wait.Job != nil {
+			jobID.SetInt64(wait.Job.ID)
+			query.SetString(wait.Job.Query, mysql.DefaultCollationName)
+		}
+		for _, blocker := range wait.Blockers {
+			dbName, tableName := types.NewDatum(nil), types.NewDatum(nil)
+			if tbl, ok := is.TableByID(blocker.TableID); ok {
+				tableName.SetString(tbl.Meta().Name.O, mysql.DefaultCollationName)
+				if db, ok := is.SchemaByTable(tbl.Meta()); ok {
+					dbName.SetString(db.Name.O, mysql.DefaultCollationName)
+				}
+			}
+			txnStart := types.NewDatum(nil)
+			if blocker.TxnStartTS != 0 {
+				txnStart.SetMysqlTime(types.NewTime(types.FromGoTime(oracle.GetTimeFromTS(blocker.TxnStartTS)), mysql.TypeTimestamp, types.MaxFsp))
+			}
+			row := []types.Datum{
+				jobID,
+				dbName,
+				tableName,
+				query,
+				types.NewIntDatum(wait.SchemaVer),
+				types.NewUintDatum(blocker.ConnID),
+				txnStart,
+				types.NewIntDatum(blocker.SchemaVer),
+			}
+			e.rows = append(e.rows, row)
+		}
+	}
+}
+
+func (e *memtableRetriever) setDataForSessionWriteConflicts(sctx sessionctx.Context) {
+	history := sctx.GetSessionVars().WriteConflictHistory
+	if history == nil {
+		return
+	}
+	is := sctx.GetInfoSchema().(infoschema.InfoSchema)
+	for _, record := range history.GetAll() {
+		row := make([]types.Datum, 0, len(e.table.Columns))
+		for _, c := range e.table.Columns {
+			if c.Name.O != writeconflicthistory.ColKeyInfoStr {
+				row = append(row, record.ToDatum(c.Name.O))
+				continue
+			}
+			value := types.NewDatum(nil)
+			if len(record.Key) > 0 {
+				decodedKey, err := keydecoder.DecodeKey(record.Key, is)
+				if err == nil {
+					decodedKeyJSON, err := json.Marshal(decodedKey)
+					if err != nil {
+						logutil.BgLogger().Warn("marshal decoded key info to JSON failed", zap.Error(err))
+					} else {
+						value = types.NewDatum(string(decodedKeyJSON))
+					}
+				} else {
+					logutil.BgLogger().Warn("decode key failed", zap.Error(err))
+				}
+			}
+			row = append(row, value)
+		}
+		e.rows = append(e.rows, row)
+	}
+}
+
+func (e *memtableRetriever) setDataForTrxSummary(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	e.rows = txninfo.Recorder.DumpTrxSummary()
+	switch e.table.Name.O {
+	case infoschema.ClusterTableTrxSummary:
+		rows, err := infoschema.AppendHostInfoToRows(ctx, e.rows)
+		if err != nil {
+			return err
+		}
+		e.rows = rows
+	}
+	return nil
+}
+
+func (e *memtableRetriever) setDataForRegionCacheStats(ctx context.Context, sctx sessionctx.Context) error {
+	if !hasPriv(sctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	tikvStore, ok := sctx.GetStore().(helper.Storage)
+	if !ok {
+		return errors.New("Information about the region cache can be gotten only when the storage is TiKV")
+	}
+	tikvHelper := &helper.Helper{
+		Store:       tikvStore,
+		RegionCache: tikvStore.GetRegionCache(),
+	}
+	stats, err := tikvHelper.GetRegionCacheStats(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([][]types.Datum, 0, len(stats))
+	for _, stat := range stats {
+		rows = append(rows, types.MakeDatums(stat.Type, stat.Name, stat.Value, stat.Detail))
+	}
+	e.rows = rows
+	if e.table.Name.O == infoschema.ClusterTableRegionCacheStats {
+		rows, err := infoschema.AppendHostInfoToRows(sctx, e.rows)
+		if err != nil {
+			return err
+		}
+		e.rows = rows
+	}
+	return nil
+}
+
 func checkRule(rule *label.Rule) (dbName, tableName string, err error) {
 	s := strings.Split(rule.ID, "/")
 	if len(s) < 3 {
@@ -21,6 +21,7 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -43,7 +44,9 @@ import (
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/store/helper"
 	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/pdapi"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/testkit"
@@ -401,6 +404,26 @@ func (s *testInfoschemaTableSuite) TestUserPrivilegesTable(c *C) {
 	tk.MustQuery(`SELECT * FROM information_schema.user_privileges WHERE grantee="'usageuser'@'%'" ORDER BY privilege_type`).Check(testkit.Rows("'usageuser'@'%' def BACKUP_ADMIN NO", "'usageuser'@'%' def SELECT YES"))
 }
 
+func (s *testInfoschemaTableSuite) TestUserAttributes(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create user usr1 comment 'comment1'")
+	tk.MustExec(`create user usr2 attribute '{"name": "Tom", "age": 19}'`)
+	tk.MustExec("create user usr3")
+	tk.MustQuery("select * from information_schema.user_attributes where user like 'usr%' order by user").Check(testkit.Rows(
+		`usr1 % {"comment": "comment1"}`, `usr2 % {"age": 19, "name": "Tom"}`, "usr3 % <nil>"))
+	tk.MustExec(`alter user usr1 attribute '{"name": "Jack"}'`)
+	tk.MustExec(`alter user usr2 attribute '{"age": null}'`)
+	tk.MustExec("alter user usr3 comment 'comment3'")
+	tk.MustQuery("select * from information_schema.user_attributes where user like 'usr%' order by user").Check(testkit.Rows(
+		`usr1 % {"comment": "comment1", "name": "Jack"}`, `usr2 % {"name": "Tom"}`, `usr3 % {"comment": "comment3"}`))
+
+	// Users without the SELECT privilege of mysql.user can only see themselves.
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use information_schema")
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "usr1", Hostname: "127.0.0.1"}, nil, nil), IsTrue)
+	tk1.MustQuery("select * from information_schema.user_attributes").Check(testkit.Rows(`usr1 % {"comment": "comment1", "name": "Jack"}`))
+}
+
 func (s *testInfoschemaTableSerialSuite) TestDataForTableStatsField(c *C) {
 	s.dom.SetStatsUpdating(true)
 	oldExpiryTime := executor.TableStatsCacheExpiry
@@ -415,10 +438,14 @@ func (s *testInfoschemaTableSerialSuite) TestDataForTableStatsField(c *C) {
 	tk.MustExec("use test")
 	tk.MustExec("drop table if exists t")
 	tk.MustExec("create table t (c int, d int, e char(5), index idx(e))")
+	// The table has no stats before the DDL event is handled.
+	tk.MustQuery("select table_rows, data_length, tidb_stats_update_time from information_schema.tables where table_name='t'").Check(
+		testkit.Rows("0 0 <nil>"))
 	err := h.HandleDDLEvent(<-h.DDLEventCh())
 	c.Assert(err, IsNil)
 	tk.MustQuery("select table_rows, avg_row_length, data_length, index_length from information_schema.tables where table_name='t'").Check(
 		testkit.Rows("0 0 0 0"))
+	tk.MustQuery("select tidb_stats_update_time is not null from information_schema.tables where table_name='t'").Check(testkit.Rows("1"))
 	tk.MustExec(`insert into t(c, d, e) values(1, 2, "c"), (2, 3, "d"), (3, 4, "e")`)
 	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
 	c.Assert(h.Update(is), IsNil)
@@ -450,6 +477,8 @@ func (s *testInfoschemaTableSerialSuite) TestDataForTableStatsField(c *C) {
 	c.Assert(h.Update(is), IsNil)
 	tk.MustQuery("select table_rows, avg_row_length, data_length, index_length from information_schema.tables where table_name='t'").Check(
 		testkit.Rows("3 18 54 6"))
+	tk.MustQuery("select count(*) from information_schema.partitions where table_name='t' and tidb_stats_update_time is not null").Check(testkit.Rows("3"))
+	tk.MustQuery("select t.tidb_stats_update_time = (select min(p.tidb_stats_update_time) from information_schema.partitions p where p.table_name='t') from information_schema.tables t where t.table_name='t'").Check(testkit.Rows("1"))
 }
 
 func (s *testInfoschemaTableSerialSuite) TestPartitionsTable(c *C) {
@@ -622,6 +651,12 @@ func (s *testInfoschemaTableSerialSuite) TestForTableTiFlashReplica(c *C) {
 	c.Assert(err, IsNil)
 	tbl.Meta().TiFlashReplica.Available = true
 	tk.MustQuery("select TABLE_SCHEMA,TABLE_NAME,REPLICA_COUNT,LOCATION_LABELS,AVAILABLE, PROGRESS from information_schema.tiflash_replica").Check(testkit.Rows("test t 2 a,b 1 1"))
+	tk.MustQuery("select REPLICATED_REGIONS, TOTAL_REGIONS, TOTAL_BYTES, ETA, HIGH_PRIORITY from information_schema.tiflash_replica").Check(testkit.Rows("<nil> <nil> <nil> <nil> 0"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, index idx(a))")
+	tk.MustExec("alter table t set tiflash replica 1 high_priority")
+	tk.MustQuery("select REPLICA_COUNT, AVAILABLE, HIGH_PRIORITY from information_schema.tiflash_replica").Check(testkit.Rows("1 0 1"))
 }
 
 var _ = SerialSuites(&testInfoschemaClusterTableSuite{testInfoschemaTableSuiteBase: &testInfoschemaTableSuiteBase{}})
@@ -902,7 +937,7 @@ func (s *testInfoschemaClusterTableSuite) TestTableStorageStats(c *C) {
 	tk.MustQuery("select TABLE_SCHEMA, sum(TABLE_SIZE) from information_schema.TABLE_STORAGE_STATS where TABLE_SCHEMA = 'test' group by TABLE_SCHEMA;").Check(testkit.Rows(
 		"test 2",
 	))
-	c.Assert(len(tk.MustQuery("select TABLE_NAME from information_schema.TABLE_STORAGE_STATS where TABLE_SCHEMA = 'mysql';").Rows()), Equals, 29)
+	c.Assert(len(tk.MustQuery("select TABLE_NAME from information_schema.TABLE_STORAGE_STATS where TABLE_SCHEMA = 'mysql';").Rows()), Equals, 35)
 
 	// More tests about the privileges.
 	tk.MustExec("create user 'testuser'@'localhost'")
@@ -928,25 +963,143 @@ func (s *testInfoschemaClusterTableSuite) TestTableStorageStats(c *C) {
 		Hostname: "localhost",
 	}, nil, nil), Equals, true)
 
-	tk.MustQuery("select count(1) from information_schema.TABLE_STORAGE_STATS where TABLE_SCHEMA = 'mysql'").Check(testkit.Rows("29"))
+	tk.MustQuery("select count(1) from information_schema.TABLE_STORAGE_STATS where TABLE_SCHEMA = 'mysql'").Check(testkit.Rows("35"))
 
 	c.Assert(tk.Se.Auth(&auth.UserIdentity{
 		Username: "testuser3",
 		Hostname: "localhost",
 	}, nil, nil), Equals, true)
 
-	tk.MustQuery("select count(1) from information_schema.TABLE_STORAGE_STATS where TABLE_SCHEMA = 'mysql'").Check(testkit.Rows("29"))
+	tk.MustQuery("select count(1) from information_schema.TABLE_STORAGE_STATS where TABLE_SCHEMA = 'mysql'").Check(testkit.Rows("35"))
+}
+
+func (s *testInfoschemaClusterTableSuite) TestPlacementStatus(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3")
+	tk.MustExec("drop placement policy if exists p1")
+	tk.MustExec("create placement policy p1 primary_region='r1' regions='r1,r2' followers=1")
+	defer tk.MustExec("drop placement policy if exists p1")
+	tk.MustExec("create table t1 (a int) placement policy p1")
+	tk.MustExec("create table t2 (a int) partition by range (a) (partition p0 values less than (10) placement policy p1, partition p1 values less than (20))")
+	tk.MustExec("create table t3 (a int)")
+	defer tk.MustExec("drop table if exists t1, t2, t3")
+
+	// The regions can't be counted without PD.
+	tk.MustQuery("select TABLE_NAME, PARTITION_NAME, POLICY_NAME, SCHEDULING_STATE, TOTAL_REGIONS from information_schema.placement_status where TABLE_SCHEMA = 'test'").Check(testkit.Rows(
+		"t1 <nil> p1 PENDING <nil>",
+		"t2 p0 p1 PENDING <nil>",
+	))
+
+	is := s.dom.InfoSchema()
+	t1, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	c.Assert(err, IsNil)
+	t1ID := t1.Meta().ID
+	tableKey := func(id int64) string {
+		return helper.BytesKeyToHex(codec.EncodeBytes(nil, tablecodec.GenTablePrefix(id)))
+	}
+	midKey := helper.BytesKeyToHex(codec.EncodeBytes(nil, tablecodec.EncodeRowKeyWithHandle(t1ID, kv.IntHandle(100))))
+
+	router := mux.NewRouter()
+	httpServer := httptest.NewServer(router)
+	defer httpServer.Close()
+	router.Handle(pdapi.Stores, fn.Wrap(func() (*helper.StoresStat, error) {
+		stores := &helper.StoresStat{Count: 3}
+		for i := 1; i <= 3; i++ {
+			stores.Stores = append(stores.Stores, helper.StoreStat{Store: helper.StoreBaseStat{
+				ID:     int64(i),
+				Labels: []helper.StoreLabel{{Key: "region", Value: fmt.Sprintf("r%d", i)}},
+			}})
+		}
+		return stores, nil
+	}))
+	router.Handle(pdapi.Regions, fn.Wrap(func() (*helper.RegionsInfo, error) {
+		peers := func(stores ...int64) []helper.RegionPeer {
+			ps := make([]helper.RegionPeer, 0, len(stores))
+			for _, store := range stores {
+				ps = append(ps, helper.RegionPeer{ID: store * 10, StoreID: store})
+			}
+			return ps
+		}
+		return &helper.RegionsInfo{Count: 3, Regions: []helper.RegionInfo{
+			// The replicas are placed as the policy.
+			{ID: 1, StartKey: tableKey(t1ID), EndKey: midKey, Peers: peers(1, 2), Leader: helper.RegionPeer{ID: 10, StoreID: 1}},
+			// The follower is not in the region r2.
+			{ID: 2, StartKey: midKey, EndKey: tableKey(t1ID + 1), Peers: peers(1, 3), Leader: helper.RegionPeer{ID: 10, StoreID: 1}},
+			{ID: 3, StartKey: tableKey(t1ID + 1), EndKey: "", Peers: peers(3), Leader: helper.RegionPeer{ID: 30, StoreID: 3}},
+		}}, nil
+	}))
+	tk = testkit.NewTestKit(c, &mockStore{
+		s.store.(helper.Storage),
+		strings.TrimPrefix(httpServer.URL, "http://"),
+	})
+	tk.MustQuery("select TABLE_NAME, PARTITION_NAME, POLICY_NAME, SCHEDULING_STATE, TOTAL_REGIONS, SCHEDULED_REGIONS, UNSCHEDULED_REGIONS from information_schema.placement_status where TABLE_SCHEMA = 'test'").Check(testkit.Rows(
+		"t1 <nil> p1 INPROGRESS 2 1 1",
+		"t2 p0 p1 PENDING 1 0 1",
+	))
+	tk.MustQuery("select s.TABLE_NAME, p.PRIMARY_REGION, p.REGIONS from information_schema.placement_status s join information_schema.placement_policies p on s.POLICY_NAME = p.POLICY_NAME where s.TABLE_SCHEMA = 'test' and s.UNSCHEDULED_REGIONS > 0").Sort().Check(testkit.Rows(
+		"t1 r1 r1,r2",
+		"t2 r1 r1,r2",
+	))
+}
+
+func (s *testInfoschemaTableSerialSuite) TestTablesSizeFromPD(c *C) {
+	oldExpiryTime := executor.TableStatsCacheExpiry
+	executor.TableStatsCacheExpiry = 0
+	defer func() { executor.TableStatsCacheExpiry = oldExpiryTime }()
+	s.dom.StatsHandle().Clear()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_pd1, t_pd2")
+	tk.MustExec("create table t_pd1 (a int, b int, index idx(b))")
+	tk.MustExec("create table t_pd2 (a int)")
+	defer tk.MustExec("drop table if exists t_pd1, t_pd2")
+
+	is := s.dom.InfoSchema()
+	t1, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t_pd1"))
+	c.Assert(err, IsNil)
+	t1ID := t1.Meta().ID
+	t2, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t_pd2"))
+	c.Assert(err, IsNil)
+	t2ID := t2.Meta().ID
+	hexKey := func(key kv.Key) string {
+		return helper.BytesKeyToHex(codec.EncodeBytes(nil, key))
+	}
+
+	var requests int32
+	router := mux.NewRouter()
+	httpServer := httptest.NewServer(router)
+	defer httpServer.Close()
+	router.Handle(pdapi.Regions, fn.Wrap(func() (*helper.RegionsInfo, error) {
+		atomic.AddInt32(&requests, 1)
+		return &helper.RegionsInfo{Count: 4, Regions: []helper.RegionInfo{
+			// The regions are not sorted by PD.
+			{ID: 3, StartKey: hexKey(tablecodec.GenTablePrefix(t2ID)), EndKey: "", ApproximateSize: 4},
+			{ID: 1, StartKey: "", EndKey: hexKey(tablecodec.GenTableRecordPrefix(t1ID)), ApproximateSize: 1},
+			{ID: 2, StartKey: hexKey(tablecodec.GenTableRecordPrefix(t1ID)), EndKey: hexKey(tablecodec.GenTablePrefix(t2ID)), ApproximateSize: 2},
+		}}, nil
+	}))
+	tk = testkit.NewTestKit(c, &mockStore{
+		s.store.(helper.Storage),
+		strings.TrimPrefix(httpServer.URL, "http://"),
+	})
+	// The regions are fetched from PD once for all the tables without stats.
+	tk.MustQuery("select table_name, data_length, index_length, tidb_stats_update_time is not null from information_schema.tables where table_schema = 'test' and table_name like 't_pd%'").Sort().Check(testkit.Rows(
+		fmt.Sprintf("t_pd1 %d %d 1", 2<<20, 1<<20),
+		fmt.Sprintf("t_pd2 %d %d 1", 4<<20, 4<<20),
+	))
+	c.Assert(atomic.LoadInt32(&requests), Equals, int32(1))
 }
 
 func (s *testInfoschemaTableSuite) TestSequences(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("CREATE SEQUENCE test.seq maxvalue 10000000")
-	tk.MustQuery("SELECT * FROM information_schema.sequences WHERE sequence_schema='test' AND sequence_name='seq'").Check(testkit.Rows("def test seq 1 1000 0 1 10000000 1 1 "))
+	tk.MustQuery("SELECT * FROM information_schema.sequences WHERE sequence_schema='test' AND sequence_name='seq'").Check(testkit.Rows("def test seq 1 1000 0 1 10000000 1 1  0 0"))
 	tk.MustExec("DROP SEQUENCE test.seq")
 	tk.MustExec("CREATE SEQUENCE test.seq start = -1 minvalue -1 maxvalue 10 increment 1 cache 10")
-	tk.MustQuery("SELECT * FROM information_schema.sequences WHERE sequence_schema='test' AND sequence_name='seq'").Check(testkit.Rows("def test seq 1 10 0 1 10 -1 -1 "))
+	tk.MustQuery("SELECT * FROM information_schema.sequences WHERE sequence_schema='test' AND sequence_name='seq'").Check(testkit.Rows("def test seq 1 10 0 1 10 -1 -1  -2 0"))
 	tk.MustExec("CREATE SEQUENCE test.seq2 start = -9 minvalue -10 maxvalue 10 increment -1 cache 15")
-	tk.MustQuery("SELECT * FROM information_schema.sequences WHERE sequence_schema='test' AND sequence_name='seq2'").Check(testkit.Rows("def test seq2 1 15 0 -1 10 -10 -9 "))
+	tk.MustQuery("SELECT * FROM information_schema.sequences WHERE sequence_schema='test' AND sequence_name='seq2'").Check(testkit.Rows("def test seq2 1 15 0 -1 10 -10 -9  -8 0"))
 	tk.MustQuery("SELECT TABLE_CATALOG, TABLE_SCHEMA, TABLE_NAME , TABLE_TYPE, ENGINE, TABLE_ROWS FROM information_schema.tables WHERE TABLE_TYPE='SEQUENCE' AND TABLE_NAME='seq2'").Check(testkit.Rows("def test seq2 SEQUENCE InnoDB 1"))
 }
 
@@ -970,3 +1123,33 @@ func (s *testInfoschemaTableSuite) TestTablesPKType(c *C) {
 	tk.MustQuery("SELECT TIDB_PK_TYPE FROM information_schema.tables where table_schema = 'test' and table_name = 't_common'").Check(testkit.Rows("CLUSTERED"))
 	tk.MustQuery("SELECT TIDB_PK_TYPE FROM information_schema.tables where table_schema = 'INFORMATION_SCHEMA' and table_name = 'TABLES'").Check(testkit.Rows("NONCLUSTERED"))
 }
+
+func (s *testInfoschemaTableSuite) TestRegionCacheStats(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("create table t_region_cache (a int primary key)")
+	tk.MustExec("insert into t_region_cache values (1), (2)")
+	tk.MustQuery("select * from t_region_cache").Check(testkit.Rows("1", "2"))
+
+	rows := tk.MustQuery("select name, value from information_schema.region_cache_stats where type = 'summary'").Rows()
+	c.Assert(rows, HasLen, 3)
+	c.Assert(rows[0][0], Equals, "requests")
+	c.Assert(rows[1][0], Equals, "region_loads")
+	c.Assert(rows[2][0], Equals, "hit_ratio")
+	// The requests sent by the mock store aren't counted, but the regions are loaded from the mock PD.
+	loads, err := strconv.ParseFloat(rows[1][1].(string), 64)
+	c.Assert(err, IsNil)
+	c.Assert(loads > 0, IsTrue)
+	hitRatio, err := strconv.ParseFloat(rows[2][1].(string), 64)
+	c.Assert(err, IsNil)
+	c.Assert(hitRatio >= 0 && hitRatio <= 1, IsTrue)
+	tk.MustQuery("select count(*) > 0 from information_schema.region_cache_stats where type = 'operation' and name = 'get_region'").Check(testkit.Rows("1"))
+	tk.MustQuery("select name, value, detail from information_schema.region_cache_stats where type = 'store'").Check(testkit.Rows("1 1 address: store1, state: Up, cached: true"))
+
+	// The PROCESS privilege is required.
+	tk.MustExec("create user 'region_cache_tester'")
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use information_schema")
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "region_cache_tester", Hostname: "127.0.0.1"}, nil, nil), IsTrue)
+	err = tk1.QueryToErr("select * from information_schema.region_cache_stats")
+	c.Assert(err, ErrorMatches, ".*PROCESS privilege.*")
+}
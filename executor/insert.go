@@ -45,9 +45,25 @@ type InsertExec struct {
 	curInsertVals  chunk.MutRow
 	row4Update     []types.Datum
 
+	// dupUpdateCoalescable indicates whether the updates on the same duplicate row can be coalesced.
+	dupUpdateCoalescable bool
+	// pendingDupRows holds the coalesced updates of the duplicate rows in the current batch,
+	// it's nil if the updates are written into the table row by row.
+	pendingDupRows    *kv.HandleMap
+	pendingDupRowList []*pendingDupRow
+
 	Priority mysql.PriorityEnum
 }
 
+// pendingDupRow is a duplicate row whose updates are coalesced in memory.
+type pendingDupRow struct {
+	handle   kv.Handle
+	oldRow   []types.Datum // The row in the transaction.
+	newRow   []types.Datum // The row with all the updates applied.
+	modified []bool
+	changed  bool
+}
+
 func (e *InsertExec) exec(ctx context.Context, rows [][]types.Datum) error {
 	defer trace.StartRegion(ctx, "InsertExec").End()
 	logutil.Eventf(ctx, "insert %d rows into table `%s`", len(rows), stringutil.MemoizeStr(func() string {
@@ -190,9 +206,22 @@ func (e *InsertValues) prefetchDataCache(ctx context.Context, txn kv.Transaction
 
 // updateDupRow updates a duplicate row to a new row.
 func (e *InsertExec) updateDupRow(ctx context.Context, idxInBatch int, txn kv.Transaction, row toBeCheckedRow, handle kv.Handle, onDuplicate []*expression.Assignment) error {
-	oldRow, err := getOldRow(ctx, e.ctx, txn, row.t, handle, e.GenExprs)
-	if err != nil {
-		return err
+	var pending *pendingDupRow
+	if e.pendingDupRows != nil {
+		if v, ok := e.pendingDupRows.Get(handle); ok {
+			pending = v.(*pendingDupRow)
+		}
+	}
+	var oldRow []types.Datum
+	var err error
+	if pending != nil {
+		// The row has been updated in this batch, update it based on the pending one.
+		oldRow = append(make([]types.Datum, 0, len(pending.newRow)), pending.newRow...)
+	} else {
+		oldRow, err = getOldRow(ctx, e.ctx, txn, row.t, handle, e.GenExprs)
+		if err != nil {
+			return err
+		}
 	}
 	// get the extra columns from the SELECT clause and get the final `oldRow`.
 	if len(e.ctx.GetSessionVars().CurrInsertBatchExtraCols) > 0 {
@@ -200,7 +229,11 @@ func (e *InsertExec) updateDupRow(ctx context.Context, idxInBatch int, txn kv.Tr
 		oldRow = append(oldRow, extraCols...)
 	}
 
-	err = e.doDupRowUpdate(ctx, handle, oldRow, row.row, e.OnDuplicate)
+	if e.pendingDupRows != nil {
+		err = e.coalesceDupRowUpdate(ctx, handle, pending, oldRow, row.row, e.OnDuplicate)
+	} else {
+		err = e.doDupRowUpdate(ctx, handle, oldRow, row.row, e.OnDuplicate)
+	}
 	if e.ctx.GetSessionVars().StmtCtx.DupKeyAsWarning && kv.ErrKeyExists.Equal(err) {
 		e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
 		return nil
@@ -238,6 +271,15 @@ func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.D
 		e.stats.Prefetch += time.Since(prefetchStart)
 	}
 
+	// Coalesce the updates on the hot duplicate rows of the batch in memory, so every
+	// duplicate row is written only once no matter how many rows of the batch hit it.
+	if e.dupUpdateCoalescable && hasDupKeysInBatch(toBeCheckedRows) {
+		e.pendingDupRows = kv.NewHandleMap()
+		defer func() {
+			e.pendingDupRows, e.pendingDupRowList = nil, nil
+		}()
+	}
+
 	for i, r := range toBeCheckedRows {
 		if r.handleKey != nil {
 			handle, err := tablecodec.DecodeRowKey(r.handleKey.newKey)
@@ -295,12 +337,141 @@ func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.D
 			}
 		}
 	}
+	if err = e.flushPendingDupRows(ctx); err != nil {
+		return err
+	}
 	if e.stats != nil {
 		e.stats.CheckInsertTime += time.Since(start)
 	}
 	return nil
 }
 
+// hasDupKeysInBatch checks whether there are rows in the batch hitting the same handle or unique key.
+func hasDupKeysInBatch(rows []toBeCheckedRow) bool {
+	keys := make(map[string]struct{}, len(rows))
+	for _, r := range rows {
+		if r.handleKey != nil {
+			if _, ok := keys[string(r.handleKey.newKey)]; ok {
+				return true
+			}
+			keys[string(r.handleKey.newKey)] = struct{}{}
+		}
+		for _, uk := range r.uniqueKeys {
+			if _, ok := keys[string(uk.newKey)]; ok {
+				return true
+			}
+			keys[string(uk.newKey)] = struct{}{}
+		}
+	}
+	return false
+}
+
+// isDupUpdateCoalescable checks whether the updates on the same duplicate row can be coalesced.
+// It's safe only if the assignments never change the handle or the unique keys of the row, so
+// the following rows in the batch can still find the duplicate row by the keys in the transaction
+// before the coalesced updates are written.
+func (e *InsertExec) isDupUpdateCoalescable() bool {
+	if _, ok := e.Table.(table.PartitionedTable); ok {
+		return false
+	}
+	tblInfo := e.Table.Meta()
+	keyCols := make(map[int]struct{})
+	if pkCol := tblInfo.GetPkColInfo(); tblInfo.PKIsHandle && pkCol != nil {
+		keyCols[pkCol.Offset] = struct{}{}
+	}
+	for _, idx := range tblInfo.Indices {
+		if !idx.Unique && !idx.Primary {
+			continue
+		}
+		for _, idxCol := range idx.Columns {
+			keyCols[idxCol.Offset] = struct{}{}
+		}
+	}
+	for _, assign := range e.OnDuplicate {
+		if _, ok := keyCols[assign.Col.Index]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// coalesceDupRowUpdate applies the update on the duplicate row in memory, the row is
+// written into the table by flushPendingDupRows.
+func (e *InsertExec) coalesceDupRowUpdate(ctx context.Context, handle kv.Handle, pending *pendingDupRow, oldRow []types.Datum, newRow []types.Datum,
+	cols []*expression.Assignment) error {
+	numCols := len(e.Table.WritableCols())
+	newData, assignFlag, err := e.evalDupRowUpdate(oldRow, newRow, cols)
+	if err != nil {
+		return err
+	}
+	changed, _, err := checkRecordChanged(ctx, e.ctx, oldRow, newData, assignFlag, e.Table)
+	if err != nil {
+		return err
+	}
+	if pending == nil {
+		pending = &pendingDupRow{
+			handle:   handle,
+			oldRow:   append(make([]types.Datum, 0, numCols), oldRow[:numCols]...),
+			newRow:   append(make([]types.Datum, 0, numCols), oldRow[:numCols]...),
+			modified: make([]bool, numCols),
+		}
+		e.pendingDupRows.Set(handle, pending)
+		e.pendingDupRowList = append(e.pendingDupRowList, pending)
+	}
+
+	sc := e.ctx.GetSessionVars().StmtCtx
+	sc.AddTouchedRows(1)
+	if !changed {
+		// See https://dev.mysql.com/doc/refman/5.7/en/mysql-real-connect.html  CLIENT_FOUND_ROWS
+		if e.ctx.GetSessionVars().ClientCapability&mysql.ClientFoundRows > 0 {
+			sc.AddAffectedRows(1)
+		}
+		return nil
+	}
+	copy(pending.newRow, newData[:numCols])
+	for i := 0; i < numCols; i++ {
+		pending.modified[i] = pending.modified[i] || assignFlag[i]
+	}
+	pending.changed = true
+	sc.AddAffectedRows(2)
+	sc.AddUpdatedRows(1)
+	sc.AddCopiedRows(1)
+	return nil
+}
+
+// flushPendingDupRows writes the coalesced updates of the duplicate rows into the table.
+func (e *InsertExec) flushPendingDupRows(ctx context.Context) error {
+	if len(e.pendingDupRowList) == 0 {
+		return nil
+	}
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	memUsageOfTxnState := txn.Size()
+	defer func() {
+		e.memTracker.Consume(int64(txn.Size() - memUsageOfTxnState))
+	}()
+	txnCtx := e.ctx.GetSessionVars().TxnCtx
+	for _, pending := range e.pendingDupRowList {
+		if !pending.changed {
+			if txnCtx.IsPessimistic {
+				txnCtx.AddUnchangedRowKey(tablecodec.EncodeRowKeyWithHandle(e.Table.Meta().ID, pending.handle))
+			}
+			continue
+		}
+		err = e.Table.UpdateRecord(ctx, e.ctx, pending.handle, pending.oldRow, pending.newRow, pending.modified)
+		if e.ctx.GetSessionVars().StmtCtx.DupKeyAsWarning && kv.ErrKeyExists.Equal(err) {
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Next implements the Executor Next interface.
 func (e *InsertExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
@@ -332,6 +503,7 @@ func (e *InsertExec) Open(ctx context.Context) error {
 
 	if e.OnDuplicate != nil {
 		e.initEvalBuffer4Dup()
+		e.dupUpdateCoalescable = e.isDupUpdateCoalescable()
 	}
 	if e.SelectExec != nil {
 		return e.SelectExec.Open(ctx)
@@ -376,6 +548,20 @@ func (e *InsertExec) initEvalBuffer4Dup() {
 // doDupRowUpdate updates the duplicate row.
 func (e *InsertExec) doDupRowUpdate(ctx context.Context, handle kv.Handle, oldRow []types.Datum, newRow []types.Datum,
 	cols []*expression.Assignment) error {
+	newData, assignFlag, err := e.evalDupRowUpdate(oldRow, newRow, cols)
+	if err != nil {
+		return err
+	}
+	_, err = updateRecord(ctx, e.ctx, handle, oldRow, newData, assignFlag, e.Table, true, e.memTracker)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// evalDupRowUpdate evaluates the assignments on the duplicate row, the updated row and
+// the assigned columns are returned.
+func (e *InsertExec) evalDupRowUpdate(oldRow []types.Datum, newRow []types.Datum, cols []*expression.Assignment) ([]types.Datum, []bool, error) {
 	assignFlag := make([]bool, len(e.Table.WritableCols()))
 	// See http://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_values
 	e.curInsertVals.SetDatums(newRow...)
@@ -391,26 +577,21 @@ func (e *InsertExec) doDupRowUpdate(ctx context.Context, handle kv.Handle, oldRo
 	e.evalBuffer4Dup.SetDatums(e.row4Update...)
 	for _, col := range cols {
 		if col.LazyErr != nil {
-			return col.LazyErr
+			return nil, nil, col.LazyErr
 		}
 		val, err1 := col.Expr.Eval(e.evalBuffer4Dup.ToRow())
 		if err1 != nil {
-			return err1
+			return nil, nil, err1
 		}
 		e.row4Update[col.Col.Index], err1 = table.CastValue(e.ctx, val, col.Col.ToInfo(), false, false)
 		if err1 != nil {
-			return err1
+			return nil, nil, err1
 		}
 		e.evalBuffer4Dup.SetDatum(col.Col.Index, e.row4Update[col.Col.Index])
 		assignFlag[col.Col.Index] = true
 	}
 
-	newData := e.row4Update[:len(oldRow)]
-	_, err := updateRecord(ctx, e.ctx, handle, oldRow, newData, assignFlag, e.Table, true, e.memTracker)
-	if err != nil {
-		return err
-	}
-	return nil
+	return e.row4Update[:len(oldRow)], assignFlag, nil
 }
 
 // setMessage sets info message(ERR_INSERT_INFO) generated by INSERT statement
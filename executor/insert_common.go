@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
@@ -422,7 +423,7 @@ func (e *InsertValues) setValueForRefColumn(row []types.Datum, hasValue []bool)
 	return nil
 }
 
-func insertRowsFromSelect(ctx context.Context, base insertCommon) error {
+func insertRowsFromSelect(ctx context.Context, base insertCommon) (err error) {
 	// process `insert|replace into ... select ... from ...`
 	e := base.insertCommon()
 	selectExec := e.children[0]
@@ -433,7 +434,20 @@ func insertRowsFromSelect(ctx context.Context, base insertCommon) error {
 
 	sessVars := e.ctx.GetSessionVars()
 	batchSize := sessVars.DMLBatchSize
-	batchInsert := sessVars.BatchInsert && !sessVars.InTxn() && config.GetGlobalConfig().EnableBatchDML && batchSize > 0
+	batchDML := !sessVars.InTxn() && config.GetGlobalConfig().EnableBatchDML && batchSize > 0
+	pipelined := sessVars.PipelinedInsertSelect && batchDML
+	batchInsert := pipelined || sessVars.BatchInsert && batchDML
+	// committedRows is the number of rows committed by the pipelined commit so far. It's only reported
+	// in the error, a failed statement can't be resumed from it.
+	var committedRows uint64
+	if pipelined {
+		defer func() {
+			if err != nil && committedRows > 0 {
+				logutil.Logger(ctx).Warn("pipelined insert into select failed", zap.Uint64("committedRows", committedRows), zap.Error(err))
+				err = ErrBatchInsertFail.GenWithStack("BatchInsert failed with error: %v, the first %d rows have been committed", err, committedRows)
+			}
+		}()
+	}
 	memUsageOfRows := int64(0)
 	memUsageOfExtraCols := int64(0)
 	memTracker := e.memTracker
@@ -476,6 +490,7 @@ func insertRowsFromSelect(ctx context.Context, base insertCommon) error {
 				if err = e.doBatchInsert(ctx); err != nil {
 					return err
 				}
+				committedRows = e.rowCount
 			}
 		}
 
@@ -1069,11 +1084,27 @@ func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.D
 			defer snapshot.SetOption(kv.CollectRuntimeStats, nil)
 		}
 	}
+	// The rows conflicting with the keys known to exist are skipped without looking up the keys again.
+	sessVars := e.ctx.GetSessionVars()
+	usePresenceFilter := !replace && sessVars.EnableInsertIgnorePresenceFilter && !sessVars.TxnCtx.IsPessimistic &&
+		e.Table.Meta().TempTableType == model.TempTableNone
+	var presentKeys []*keyValueWithDupInfo
+	rowsToPrefetch := toBeCheckedRows
+	if usePresenceFilter {
+		presentKeys = make([]*keyValueWithDupInfo, len(toBeCheckedRows))
+		rowsToPrefetch = make([]toBeCheckedRow, 0, len(toBeCheckedRows))
+		for i, r := range toBeCheckedRows {
+			if presentKeys[i] = findPresentUniqueKey(sessVars.TxnCtx, r); presentKeys[i] == nil {
+				rowsToPrefetch = append(rowsToPrefetch, r)
+			}
+		}
+	}
+
 	prefetchStart := time.Now()
 	// Fill cache using BatchGet, the following Get requests don't need to visit TiKV.
 	// Temporary table need not to do prefetch because its all data are stored in the memory.
-	if e.Table.Meta().TempTableType == model.TempTableNone {
-		if _, err = prefetchUniqueIndices(ctx, txn, toBeCheckedRows); err != nil {
+	if e.Table.Meta().TempTableType == model.TempTableNone && len(rowsToPrefetch) > 0 {
+		if _, err = prefetchUniqueIndices(ctx, txn, rowsToPrefetch); err != nil {
 			return err
 		}
 	}
@@ -1087,10 +1118,17 @@ func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.D
 		if r.ignored {
 			continue
 		}
+		if usePresenceFilter && presentKeys[i] != nil {
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(presentKeys[i].dupErr)
+			continue
+		}
 		skip := false
 		if r.handleKey != nil {
 			_, err := txn.Get(ctx, r.handleKey.newKey)
 			if err == nil {
+				if usePresenceFilter {
+					sessVars.TxnCtx.AddPresentUniqueKey(r.handleKey.newKey)
+				}
 				if replace {
 					err2 := e.removeRow(ctx, txn, r)
 					if err2 != nil {
@@ -1107,6 +1145,9 @@ func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.D
 		for _, uk := range r.uniqueKeys {
 			_, err := txn.Get(ctx, uk.newKey)
 			if err == nil {
+				if usePresenceFilter {
+					sessVars.TxnCtx.AddPresentUniqueKey(uk.newKey)
+				}
 				// If duplicate keys were found in BatchGet, mark row = nil.
 				e.ctx.GetSessionVars().StmtCtx.AppendWarning(uk.dupErr)
 				skip = true
@@ -1126,6 +1167,9 @@ func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.D
 			if err != nil {
 				return err
 			}
+			if usePresenceFilter {
+				addPresentUniqueKeys(sessVars.TxnCtx, r)
+			}
 		}
 	}
 	if e.stats != nil {
@@ -1134,6 +1178,32 @@ func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.D
 	return nil
 }
 
+// findPresentUniqueKey returns the key of the row which is known to exist in the transaction, or nil if there is none.
+func findPresentUniqueKey(txnCtx *variable.TransactionContext, r toBeCheckedRow) *keyValueWithDupInfo {
+	if r.ignored {
+		return nil
+	}
+	if r.handleKey != nil && txnCtx.IsUniqueKeyPresent(r.handleKey.newKey) {
+		return r.handleKey
+	}
+	for _, uk := range r.uniqueKeys {
+		if txnCtx.IsUniqueKeyPresent(uk.newKey) {
+			return uk
+		}
+	}
+	return nil
+}
+
+// addPresentUniqueKeys records the keys of the inserted row in the presence filter of the transaction.
+func addPresentUniqueKeys(txnCtx *variable.TransactionContext, r toBeCheckedRow) {
+	if r.handleKey != nil {
+		txnCtx.AddPresentUniqueKey(r.handleKey.newKey)
+	}
+	for _, uk := range r.uniqueKeys {
+		txnCtx.AddPresentUniqueKey(uk.newKey)
+	}
+}
+
 func (e *InsertValues) removeRow(ctx context.Context, txn kv.Transaction, r toBeCheckedRow) error {
 	handle, err := tablecodec.DecodeRowKey(r.handleKey.newKey)
 	if err != nil {
@@ -265,6 +265,42 @@ func (s *testSuite8) TestClusterIndexInsertOnDuplicateKey(c *C) {
 	c.Assert(err, ErrorMatches, ".*Duplicate entry 'a-b-c' for.*")
 }
 
+func (s *testSuite8) TestInsertOnDuplicateKeyHotRows(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(k int primary key, cnt int, ts timestamp default '2021-01-01 00:00:00' on update current_timestamp, key idx_cnt(cnt))")
+	tk.MustExec("insert into t(k, cnt) values (1, 10)")
+
+	// The updates on the same row in the batch are coalesced.
+	tk.MustExec("insert into t(k, cnt) values (1, 1), (1, 1), (2, 1), (1, 1), (2, 1), (3, 1) on duplicate key update cnt = cnt + values(cnt)")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(10))
+	tk.CheckLastMessage("Records: 6  Duplicates: 4  Warnings: 0")
+	tk.MustQuery("select k, cnt, ts > '2021-01-01 00:00:00' from t order by k").Check(testkit.Rows("1 13 1", "2 2 1", "3 1 0"))
+	tk.MustExec("admin check table t")
+
+	// The updates which don't change the row.
+	tk.MustExec("insert into t(k, cnt) values (1, 1), (1, 1), (4, 4) on duplicate key update cnt = cnt")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(1))
+	tk.MustExec("insert into t(k, cnt) values (1, 1), (1, 1) on duplicate key update cnt = cnt + values(cnt) - 1")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(0))
+	tk.MustQuery("select k, cnt from t order by k").Check(testkit.Rows("1 13", "2 2", "3 1", "4 4"))
+
+	// The rows are found by the unique key.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key auto_increment, name varchar(10), cnt int, unique key uk_name(name))")
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("insert into t(name, cnt) values ('a', 1), ('b', 1), ('a', 1), ('a', 1) on duplicate key update cnt = cnt + 1")
+	tk.MustExec("commit")
+	tk.MustQuery("select name, cnt from t order by name").Check(testkit.Rows("a 3", "b 1"))
+	tk.MustExec("admin check table t")
+
+	// The updates on the unique key can't be coalesced.
+	tk.MustExec("insert into t(name, cnt) values ('a', 1), ('a', 1), ('b', 1) on duplicate key update name = concat(name, 'x')")
+	tk.MustQuery("select name, cnt from t order by name").Check(testkit.Rows("a 1", "ax 3", "bx 1"))
+	tk.MustExec("admin check table t")
+}
+
 func (s *testSuite10) TestPaddingCommonHandle(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
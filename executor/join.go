@@ -867,8 +867,8 @@ func (e *NestedLoopApplyExec) Open(ctx context.Context) error {
 	}
 	e.cursor = 0
 	e.innerRows = e.innerRows[:0]
-	e.outerChunk = newFirstChunk(e.outerExec)
-	e.innerChunk = newFirstChunk(e.innerExec)
+	e.outerChunk = tryNewCacheChunk(e.outerExec)
+	e.innerChunk = tryNewCacheChunk(e.innerExec)
 	e.innerList = chunk.NewList(retTypes(e.innerExec), e.initCap, e.maxChunkSize)
 
 	e.memTracker = memory.NewTracker(e.id, -1)
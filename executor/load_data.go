@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sem"
 	"go.uber.org/zap"
 )
 
@@ -54,8 +55,8 @@ type LoadDataExec struct {
 // Next implements the Executor Next interface.
 func (e *LoadDataExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.GrowAndReset(e.maxChunkSize)
-	// TODO: support load data without local field.
-	if !e.IsLocal {
+	// TODO: support load data from the local file system of the server.
+	if !e.IsLocal && !isExternalDataSource(e.loadDataInfo.Path) {
 		return errors.New("Load Data: don't support load data without local field")
 	}
 	e.loadDataInfo.OnDuplicate = e.OnDuplicate
@@ -73,6 +74,14 @@ func (e *LoadDataExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	if e.loadDataInfo.Path == "" {
 		return errors.New("Load Data: infile path is empty")
 	}
+	if !e.IsLocal {
+		if sem.IsEnabled() {
+			// The server is not permitted to read the data from the external storage when SEM is enabled.
+			return ErrNotSupportedWithSem.GenWithStackByArgs("LOAD DATA from external storage")
+		}
+		// The data is read by the server itself, so there is nothing left for the client connection to do.
+		return e.loadDataInfo.loadFromExternalSource(ctx)
+	}
 	sctx.SetValue(LoadDataVarKey, e.loadDataInfo)
 
 	return nil
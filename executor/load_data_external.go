@@ -0,0 +1,316 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pingcap/errors"
+	backuppb "github.com/pingcap/kvproto/pkg/brpb"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// loadDataReadBlockSize is the size of the data read from the external source at a time.
+var loadDataReadBlockSize = 1 << 20
+
+// loadDataHTTPTimeout is the timeout of connecting to the HTTP source and waiting for its response.
+// The whole transfer isn't bounded since the infile may be huge, the statement can be killed instead.
+const loadDataHTTPTimeout = 30 * time.Second
+
+// loadDataInternalNetworks are the loopback, private and link-local networks. The infile can't be
+// fetched from them over HTTP or from the cloud storage endpoints in them, so LOAD DATA can't be used
+// to reach the internal services, e.g. the status ports of the cluster or the metadata service of the cloud.
+var loadDataInternalNetworks = func() []*net.IPNet {
+	cidrs := []string{"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16",
+		"172.16.0.0/12", "192.168.0.0/16", "::/128", "::1/128", "fc00::/7", "fe80::/10"}
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// loadDataAllowInternalAddress allows fetching the infile from the internal networks, it's only set by the tests.
+var loadDataAllowInternalAddress = false
+
+// loadDataHTTPClient fetches the infile over HTTP. The address is checked after it's resolved, so the host
+// can't reach the internal networks by the DNS records or the redirections either.
+var loadDataHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: loadDataHTTPTimeout,
+			Control: checkLoadDataDialAddress,
+		}).DialContext,
+		TLSHandshakeTimeout:   loadDataHTTPTimeout,
+		ResponseHeaderTimeout: loadDataHTTPTimeout,
+	},
+}
+
+func checkLoadDataDialAddress(network, address string, _ syscall.RawConn) error {
+	if loadDataAllowInternalAddress {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return errors.Errorf("Load Data: invalid address %s", address)
+	}
+	return checkLoadDataIP(ip)
+}
+
+// checkLoadDataEndpoint checks the endpoint of the cloud storage, which is set by the `endpoint`
+// option of the URI. Like the HTTP source, none of the addresses of the endpoint can be internal.
+func checkLoadDataEndpoint(ctx context.Context, endpoint string) error {
+	if loadDataAllowInternalAddress || endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if u.Hostname() == "" {
+		return errors.Errorf("Load Data: invalid endpoint %s", endpoint)
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, addr := range addrs {
+		if err = checkLoadDataIP(addr.IP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkLoadDataIP(ip net.IP) error {
+	internal := ip.IsMulticast()
+	for _, n := range loadDataInternalNetworks {
+		internal = internal || n.Contains(ip)
+	}
+	if internal {
+		return errors.Errorf("Load Data: can't fetch the infile from the internal address %s", ip)
+	}
+	return nil
+}
+
+// isExternalDataSource checks whether the infile path of LOAD DATA is an URI of
+// the external storage, e.g. `s3://bucket/path/to/file.csv?access-key=...` or
+// `https://example.com/file.csv`.
+func isExternalDataSource(rawURL string) bool {
+	u, err := storage.ParseRawURL(rawURL)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "s3", "gs", "gcs", "azure", "azblob", "http", "https":
+		return true
+	}
+	return false
+}
+
+// checkLoadDataCredentials checks the credentials of the cloud storage are given
+// by the URI. Otherwise the storage would fall back to the credentials of the
+// server, e.g. the instance role or the environment variables, and any user with
+// the FILE privilege could read the buckets which the server can access.
+func checkLoadDataCredentials(backend *backuppb.StorageBackend) error {
+	switch b := backend.Backend.(type) {
+	case *backuppb.StorageBackend_S3:
+		if b.S3.AccessKey == "" || b.S3.SecretAccessKey == "" {
+			return errors.New("Load Data: access-key and secret-access-key must be given in the URI of S3")
+		}
+	case *backuppb.StorageBackend_AzureBlobStorage:
+		if b.AzureBlobStorage.AccountName == "" || b.AzureBlobStorage.SharedKey == "" {
+			return errors.New("Load Data: account-name and account-key must be given in the URI of Azure Blob Storage")
+		}
+	}
+	// GCS can't take the credentials from the URI, it's accessed anonymously so
+	// only the public objects can be read.
+	return nil
+}
+
+// openExternalDataSource opens the file pointed by the URI for reading. The cloud
+// storages are accessed through the BR storage layer, the credentials must be
+// given by the query parameters of the URI in the same way as BACKUP and RESTORE,
+// the ones of the server are never used.
+func openExternalDataSource(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	u, err := storage.ParseRawURL(rawURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		resp, err := loadDataHTTPClient.Do(req)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, errors.Errorf("Load Data: failed to fetch the infile, status: %s", resp.Status)
+		}
+		return resp.Body, nil
+	}
+
+	dir, name := path.Split(u.Path)
+	if name == "" {
+		return nil, errors.New("Load Data: infile path is a directory")
+	}
+	u.Path = dir
+	// The credentials file of GCS is read from the disk of the server.
+	if u.Query().Get("credentials-file") != "" {
+		return nil, errors.New("Load Data: credentials-file isn't allowed for the infile")
+	}
+	backend, err := storage.ParseBackend(u.String(), nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = checkLoadDataCredentials(backend); err != nil {
+		return nil, err
+	}
+	for _, endpoint := range []string{backend.GetS3().GetEndpoint(), backend.GetGcs().GetEndpoint(), backend.GetAzureBlobStorage().GetEndpoint()} {
+		if err = checkLoadDataEndpoint(ctx, endpoint); err != nil {
+			return nil, err
+		}
+	}
+	s, err := storage.New(ctx, backend, &storage.ExternalStorageOptions{NoCredentials: true})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.Open(ctx, name)
+}
+
+// loadFromExternalSource reads the data from the external source and inserts it
+// into the table. It works the same way as the server does for LOAD DATA LOCAL,
+// except that the data comes from the external storage instead of the client.
+func (e *LoadDataInfo) loadFromExternalSource(ctx context.Context) error {
+	if !e.Table.Meta().IsBaseTable() {
+		return errors.New("can only load data into base tables")
+	}
+	r, err := openExternalDataSource(ctx, e.Path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	e.InitQueues()
+	e.SetMaxRowsInBatch(uint64(e.Ctx.GetSessionVars().DMLBatchSize))
+	e.StartStopWatcher()
+	// let stop watcher goroutine quit
+	defer e.ForceQuit()
+
+	var processErr error
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		processErr = e.processExternalSource(ctx, r, wg)
+	}()
+	err = e.CommitWork(ctx)
+	wg.Wait()
+	if processErr != nil {
+		// The commit work is forced to quit by the processing routine, so the
+		// error of the processing routine is the root cause.
+		err = processErr
+	}
+	e.SetMessage()
+	return err
+}
+
+// processExternalSource reads the data block by block and enqueues the commit tasks.
+func (e *LoadDataInfo) processExternalSource(ctx context.Context, r io.Reader, wg *sync.WaitGroup) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logutil.Logger(ctx).Error("process routine panicked",
+				zap.Reflect("r", rec),
+				zap.Stack("stack"))
+			err = errors.Errorf("%v", rec)
+		}
+		if err != nil {
+			e.ForceQuit()
+		} else {
+			e.CloseTaskQueue()
+		}
+		wg.Done()
+	}()
+	var prevData []byte
+	for {
+		select {
+		case <-e.QuitCh:
+			return nil
+		default:
+		}
+		// The unfinished line is kept in prevData and refers to the block, so
+		// every block must be read into a new buffer.
+		curData := make([]byte, loadDataReadBlockSize)
+		n, err := io.ReadFull(r, curData)
+		isEOF := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !isEOF {
+			return errors.Trace(err)
+		}
+		curData = curData[:n]
+		if n > 0 || len(prevData) > 0 {
+			if prevData, err = e.insertDataWithCommit(ctx, prevData, curData); err != nil {
+				return err
+			}
+		}
+		if isEOF && len(curData) == 0 {
+			break
+		}
+	}
+	return e.EnqOneTask(ctx)
+}
+
+// insertDataWithCommit inserts the data and enqueues a commit task once a batch is full.
+func (e *LoadDataInfo) insertDataWithCommit(ctx context.Context, prevData, curData []byte) ([]byte, error) {
+	for {
+		var reachLimit bool
+		var err error
+		prevData, reachLimit, err = e.InsertData(ctx, prevData, curData)
+		if err != nil {
+			return nil, err
+		}
+		if !reachLimit {
+			break
+		}
+		// push into commit task queue
+		if err = e.EnqOneTask(ctx); err != nil {
+			return prevData, err
+		}
+		curData = prevData
+		prevData = nil
+	}
+	return prevData, nil
+}
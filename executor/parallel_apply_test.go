@@ -511,6 +511,12 @@ func (s *testSuite) TestApplyConcurrency(c *C) {
 	tk.MustExec("set tidb_executor_concurrency = 5")
 	checkApplyPlan(c, tk, sql, 5)
 
+	// tidb_apply_concurrency
+	tk.MustExec("set tidb_apply_concurrency = 2")
+	checkApplyPlan(c, tk, sql, 2)
+	tk.MustExec("set tidb_apply_concurrency = default")
+	checkApplyPlan(c, tk, sql, 5)
+
 	// concurrency
 	tk.MustExec("drop table if exists t")
 	tk.MustExec("create table t (a int)")
@@ -530,6 +536,37 @@ func (s *testSuite) TestApplyConcurrency(c *C) {
 	}
 }
 
+func (s *testSuite) TestParallelApplyWithSideEffects(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("set tidb_enable_parallel_apply=true")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2), (3, 3)")
+
+	isParallel := func(sql string) bool {
+		for _, row := range tk.MustQuery("explain analyze " + sql).Rows() {
+			line := fmt.Sprintf("%v", row)
+			if strings.Contains(line, "Apply") {
+				return strings.Contains(line, "Concurrency:")
+			}
+		}
+		c.Fatalf("no apply in the plan of %s", sql)
+		return false
+	}
+
+	c.Assert(isParallel("select * from t1 where t1.b > (select sum(t2.b) from t2 where t2.a > t1.a)"), IsTrue)
+	c.Assert(isParallel("select * from t1 where t1.b > (select sum(t2.b) from t2 where t2.a > t1.a and sleep(0) = 0)"), IsFalse)
+	c.Assert(isParallel("select * from t1 where t1.b > (select sum(t2.b + (@x := t2.a)) from t2 where t2.a > t1.a)"), IsFalse)
+	c.Assert(isParallel("select * from t1 where t1.b > (select max(t2.b) from t2 where t2.a > t1.a and last_insert_id(t2.a) > 0)"), IsFalse)
+	tk.MustExec("begin pessimistic")
+	c.Assert(isParallel("select * from t1 where t1.b in (select t2.b from t2 where t2.a > t1.a for update)"), IsFalse)
+	tk.MustExec("rollback")
+
+	tk.MustQuery("select t1.a, (select sum(t2.b) from t2 where t2.a > t1.a and sleep(0) = 0) from t1").Sort().Check(testkit.Rows("1 5", "2 3", "3 <nil>"))
+}
+
 func (s *testSuite) TestApplyCacheRatio(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
 	tk.MustExec("drop table if exists t1, t2")
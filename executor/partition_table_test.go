@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/israce"
 	"github.com/pingcap/tidb/util/testkit"
@@ -3124,3 +3125,65 @@ func (s *partitionTableSuite) TestIssue26251(c *C) {
 	<-ch
 	tk2.MustExec("rollback")
 }
+
+func (s *partitionTableSuite) TestRangeColumnsPartitionOnTimestamp(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec(`create table t (ts timestamp) partition by range columns (ts) (
+		partition p0 values less than ('2020-01-01 00:00:00'),
+		partition p1 values less than ('2020-01-02 00:00:00'),
+		partition p2 values less than (maxvalue))`)
+
+	// The boundaries are in UTC, no matter which time zone the session uses.
+	tk.MustExec("set @@time_zone = '+08:00'")
+	tk.MustExec("insert into t values ('2020-01-01 07:00:00'), ('2020-01-01 09:00:00'), ('2020-01-02 09:00:00')")
+	tk.MustQuery("select * from t partition (p0)").Check(testkit.Rows("2020-01-01 07:00:00"))
+	tk.MustQuery("select * from t partition (p1)").Check(testkit.Rows("2020-01-01 09:00:00"))
+	tk.MustQuery("select * from t partition (p2)").Check(testkit.Rows("2020-01-02 09:00:00"))
+	tk.MustExec("set @@time_zone = '+00:00'")
+	tk.MustQuery("select * from t partition (p0)").Check(testkit.Rows("2019-12-31 23:00:00"))
+
+	// Pruning converts the constants from the session time zone to UTC.
+	tk.MustExec("set @@time_zone = '+08:00'")
+	tk.UsedPartitions("select * from t where ts < '2020-01-01 08:00:00'").Check(testkit.Rows("p0"))
+	tk.MustQuery("select * from t where ts < '2020-01-01 08:00:00'").Check(testkit.Rows("2020-01-01 07:00:00"))
+	tk.UsedPartitions("select * from t where ts = '2020-01-01 09:00:00'").Check(testkit.Rows("p1"))
+	tk.UsedPartitions("select * from t where ts >= '2020-01-02 08:00:00'").Check(testkit.Rows("p2"))
+	tk.UsedPartitions("select * from t where ts in ('2020-01-01 07:00:00', '2020-01-02 09:00:00')").Check(testkit.Rows("p0 p2"))
+	tk.MustExec("set @@time_zone = '-08:00'")
+	tk.UsedPartitions("select * from t where ts < '2019-12-31 16:00:00'").Check(testkit.Rows("p0"))
+	tk.MustQuery("select * from t where ts < '2019-12-31 16:00:00'").Check(testkit.Rows("2019-12-31 15:00:00"))
+
+	// Both rows are displayed as 2021-11-07 01:30:00 in America/New_York,
+	// when the clock is turned back from 02:00 EDT to 01:00 EST.
+	tk.MustExec("drop table t")
+	tk.MustExec(`create table t (ts timestamp) partition by range columns (ts) (
+		partition p0 values less than ('2021-11-07 06:00:00'),
+		partition p1 values less than (maxvalue))`)
+	tk.MustExec("create table tnormal (ts timestamp)")
+	tk.MustExec("set @@time_zone = '+00:00'")
+	tk.MustExec("insert into t values ('2021-11-07 05:30:00'), ('2021-11-07 06:30:00')")
+	tk.MustExec("insert into tnormal select * from t")
+	tk.MustExec("set @@time_zone = 'America/New_York'")
+	tk.UsedPartitions("select * from t where ts = '2021-11-07 01:30:00'").Check(testkit.Rows("all"))
+	for _, cond := range []string{"ts = '2021-11-07 01:30:00'", "ts <= '2021-11-07 01:30:00'", "ts > '2021-11-07 01:10:00'", "ts < '2021-11-07 01:50:00'"} {
+		tk.MustQuery("select * from t where " + cond).Sort().Check(tk.MustQuery("select * from tnormal where " + cond).Sort().Rows())
+	}
+	tk.MustExec("drop table tnormal")
+
+	// Exchanging a partition checks the rows against the boundaries in UTC.
+	tk.MustExec("set @@tidb_enable_exchange_partition = 1")
+	tk.MustExec("create table tnormal (ts timestamp)")
+	tk.MustExec("set @@time_zone = '+08:00'")
+	tk.MustExec("insert into tnormal values ('2021-11-07 13:00:00')")
+	tk.MustGetErrCode("alter table t exchange partition p1 with table tnormal", mysql.ErrRowDoesNotMatchPartition)
+	tk.MustExec("alter table t exchange partition p0 with table tnormal")
+	tk.MustQuery("select * from t partition (p0)").Check(testkit.Rows("2021-11-07 13:00:00"))
+	tk.MustExec("drop table tnormal")
+
+	// TIMESTAMP columns are still not allowed in LIST COLUMNS partitioning.
+	tk.MustExec("set @@tidb_enable_list_partition = 1")
+	tk.MustGetErrCode("create table t1 (ts timestamp) partition by list columns (ts) (partition p0 values in ('2020-01-01 00:00:00'))", mysql.ErrFieldTypeNotAllowedAsPartitionField)
+}
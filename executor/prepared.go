@@ -148,6 +148,12 @@ func (e *PrepareExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	}
 	stmt := stmts[0]
 
+	// The prepared statements can not be rewritten since the parameters are bound later,
+	// so they are rejected by the rewrite rules as well.
+	if rule := MatchSQLBlocklist(e.ctx, stmt); rule != nil {
+		return ErrSQLBlocked.GenWithStackByArgs(rule.Name, rule.Reason)
+	}
+
 	if e.needReset {
 		err = ResetContextOfStmt(e.ctx, stmt)
 		if err != nil {
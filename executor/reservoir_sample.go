@@ -0,0 +1,142 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/cznic/mathutil"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+var _ Executor = &ReservoirSampleExec{}
+
+// ReservoirSampleExec implements `ORDER BY RAND() LIMIT N`. Instead of generating a random
+// number for each row and sorting all the rows, it keeps a uniform random sample of the rows
+// with reservoir sampling, see https://en.wikipedia.org/wiki/Reservoir_sampling.
+type ReservoirSampleExec struct {
+	baseExecutor
+
+	offset uint64
+	count  uint64
+
+	// rowChunks is the chunks to store row values.
+	rowChunks *chunk.List
+	// rowPtrs stores the chunk index and row index for each sampled row.
+	rowPtrs []chunk.RowPtr
+	// seen is the number of child rows consumed.
+	seen int64
+	rng  *rand.Rand
+
+	idx        int
+	fetched    bool
+	memTracker *memory.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *ReservoirSampleExec) Open(ctx context.Context) error {
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.rng = rand.New(rand.NewSource(time.Now().UnixNano())) // #nosec G404
+	e.fetched = false
+	e.seen = 0
+	e.idx = 0
+	return e.children[0].Open(ctx)
+}
+
+// Close implements the Executor Close interface.
+func (e *ReservoirSampleExec) Close() error {
+	if e.memTracker != nil {
+		e.memTracker.Detach()
+		e.memTracker = nil
+	}
+	e.rowChunks = nil
+	e.rowPtrs = nil
+	return e.children[0].Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *ReservoirSampleExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if !e.fetched {
+		if err := e.fetchAndSample(ctx); err != nil {
+			return err
+		}
+		// The rows which are never replaced are kept in the original order, shuffle them.
+		e.rng.Shuffle(len(e.rowPtrs), func(i, j int) {
+			e.rowPtrs[i], e.rowPtrs[j] = e.rowPtrs[j], e.rowPtrs[i]
+		})
+		e.idx = int(mathutil.MinUint64(e.offset, uint64(len(e.rowPtrs))))
+		e.fetched = true
+	}
+	if e.idx >= len(e.rowPtrs) {
+		return nil
+	}
+	numToAppend := mathutil.Min(len(e.rowPtrs)-e.idx, req.RequiredRows()-req.NumRows())
+	for i := 0; i < numToAppend; i++ {
+		req.AppendRow(e.rowChunks.GetRow(e.rowPtrs[e.idx]))
+		e.idx++
+	}
+	return nil
+}
+
+func (e *ReservoirSampleExec) fetchAndSample(ctx context.Context) error {
+	e.rowChunks = chunk.NewList(retTypes(e), e.initCap, e.maxChunkSize)
+	e.rowChunks.GetMemTracker().AttachTo(e.memTracker)
+	e.rowChunks.GetMemTracker().SetLabel(memory.LabelForRowChunks)
+	sampleSize := e.offset + e.count
+	if sampleSize == 0 {
+		return nil
+	}
+	childRowChk := tryNewCacheChunk(e.children[0])
+	for {
+		err := Next(ctx, e.children[0], childRowChk)
+		if err != nil {
+			return err
+		}
+		if childRowChk.NumRows() == 0 {
+			return nil
+		}
+		oldCap := cap(e.rowPtrs)
+		for i := 0; i < childRowChk.NumRows(); i++ {
+			row := childRowChk.GetRow(i)
+			if uint64(len(e.rowPtrs)) < sampleSize {
+				e.rowPtrs = append(e.rowPtrs, e.rowChunks.AppendRow(row))
+			} else if j := uint64(e.rng.Int63n(e.seen + 1)); j < sampleSize {
+				// Replace a sampled row with probability sampleSize/(seen+1).
+				e.rowPtrs[j] = e.rowChunks.AppendRow(row)
+			}
+			e.seen++
+		}
+		e.memTracker.Consume(int64(8 * (cap(e.rowPtrs) - oldCap)))
+		if e.rowChunks.Len() > len(e.rowPtrs)*topNCompactionFactor {
+			e.doCompaction()
+		}
+	}
+}
+
+// doCompaction rebuilds the chunks to release the memory of the replaced rows.
+func (e *ReservoirSampleExec) doCompaction() {
+	newRowChunks := chunk.NewList(retTypes(e), e.initCap, e.maxChunkSize)
+	for i, rowPtr := range e.rowPtrs {
+		e.rowPtrs[i] = newRowChunks.AppendRow(e.rowChunks.GetRow(rowPtr))
+	}
+	newRowChunks.GetMemTracker().SetLabel(memory.LabelForRowChunks)
+	e.memTracker.ReplaceChild(e.rowChunks.GetMemTracker(), newRowChunks.GetMemTracker())
+	e.rowChunks = newRowChunks
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservoirSample(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int)")
+	values := make([]string, 0, 100)
+	for i := 1; i <= 100; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	// Make the sampled rows be replaced and compacted across several chunks.
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	rows := tk.MustQuery("explain format = 'brief' select * from t order by rand() limit 2, 3").Rows()
+	require.Equal(t, "ReservoirSample", rows[0][0])
+	require.Equal(t, "offset:2, count:3", rows[0][4])
+	// RAND() with a seed is deterministic, so the rows are still sorted.
+	rows = tk.MustQuery("explain format = 'brief' select * from t order by rand(1) limit 3").Rows()
+	require.Contains(t, rows[1][0], "TopN")
+
+	checkSample := func(sql string, expectedCnt int) {
+		rows := tk.MustQuery(sql).Rows()
+		require.Len(t, rows, expectedCnt)
+		seen := make(map[string]struct{}, len(rows))
+		for _, row := range rows {
+			a := row[0].(string)
+			require.Equal(t, a, row[1])
+			_, ok := seen[a]
+			require.False(t, ok)
+			seen[a] = struct{}{}
+		}
+	}
+	checkSample("select * from t order by rand() limit 10", 10)
+	checkSample("select * from t order by rand() limit 5, 10", 10)
+	checkSample("select * from t order by rand() limit 95, 10", 5)
+	checkSample("select * from t where a > 90 order by rand() limit 3", 3)
+	checkSample("select * from t order by rand() limit 100, 10", 0)
+	tk.MustQuery("select count(*), sum(a) from (select a from t order by rand() limit 200) s").Check(testkit.Rows("100 5050"))
+
+	picked := make(map[string]struct{})
+	for i := 0; i < 20; i++ {
+		picked[tk.MustQuery("select a from t order by rand() limit 1").Rows()[0][0].(string)] = struct{}{}
+	}
+	require.Greater(t, len(picked), 1)
+
+	tk.MustQuery("select * from t order by rand(1) limit 3").Check(tk.MustQuery("select * from t order by rand(1) limit 3").Rows())
+}
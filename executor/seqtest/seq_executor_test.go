@@ -1067,6 +1067,70 @@ func TestBatchInsertDelete(t *testing.T) {
 	r.Check(testkit.Rows("0"))
 }
 
+func TestPipelinedInsertSelect(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	originLimit := atomic.LoadUint64(&kv.TxnTotalSizeLimit)
+	defer func() {
+		atomic.StoreUint64(&kv.TxnTotalSizeLimit, originLimit)
+	}()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists src, dst")
+	tk.MustExec("create table src (id int primary key, c int)")
+	tk.MustExec("create table dst (id int primary key, c int)")
+	values := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into src values " + strings.Join(values, ","))
+	tk.MustExec("insert into src select id + 100, c from src")
+	tk.MustExec("insert into src select id + 200, c from src")
+	tk.MustQuery("select count(*) from src").Check(testkit.Rows("400"))
+	// Set the limitation to a small value, make it easier to reach the limitation.
+	atomic.StoreUint64(&kv.TxnTotalSizeLimit, 5500)
+
+	// This will meet txn too large error.
+	err := tk.ExecToErr("insert into dst select * from src")
+	require.Error(t, err)
+	require.True(t, kv.ErrTxnTooLarge.Equal(err))
+	tk.MustQuery("select count(*) from dst").Check(testkit.Rows("0"))
+
+	// The pipelined commit only takes effect when the batch DML is enabled.
+	tk.MustExec("set @@session.tidb_pipelined_insert_select=on")
+	tk.MustExec("set @@session.tidb_dml_batch_size=50")
+	err = tk.ExecToErr("insert into dst select * from src")
+	require.Error(t, err)
+	require.True(t, kv.ErrTxnTooLarge.Equal(err))
+	tk.MustQuery("select count(*) from dst").Check(testkit.Rows("0"))
+
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.EnableBatchDML = true
+	})
+	tk.MustExec("insert into dst select * from src")
+	require.Equal(t, uint64(400), tk.Session().AffectedRows())
+	tk.MustQuery("select count(*), sum(c) from dst").Check(testkit.Rows("400 19800"))
+
+	// The rows committed before the failure are kept and reported in the error.
+	tk.MustExec("delete from dst where id < 50")
+	err = tk.ExecToErr("insert into dst select * from src")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "the first 50 rows have been committed")
+	tk.MustQuery("select count(*) from dst").Check(testkit.Rows("400"))
+
+	// The pipelined commit is disabled inside an explicit transaction.
+	tk.MustExec("truncate table dst")
+	tk.MustExec("begin")
+	err = tk.ExecToErr("insert into dst select * from src")
+	require.Error(t, err)
+	require.True(t, kv.ErrTxnTooLarge.Equal(err))
+	tk.MustExec("rollback")
+	tk.MustQuery("select count(*) from dst").Check(testkit.Rows("0"))
+}
+
 type checkPrioClient struct {
 	tikv.Client
 	priority kvrpcpb.CommandPri
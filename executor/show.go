@@ -397,7 +397,13 @@ func (e *ShowExec) fetchShowProcessList() error {
 	}
 
 	pl := sm.ShowProcessList()
-	for _, pi := range pl {
+	ids := make([]uint64, 0, len(pl))
+	for id := range pl {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		pi := pl[id]
 		// If you have the PROCESS privilege, you can see all threads.
 		// Otherwise, you can see only your own threads.
 		if !hasProcessPriv && pi.User != loginUser.Username {
@@ -446,7 +452,10 @@ func (e *ShowExec) fetchShowTables() error {
 	for _, v := range schemaTables {
 		// Test with mysql.AllPrivMask means any privilege would be OK.
 		// TODO: Should consider column privileges, which also make a table visible.
-		if checker != nil && !checker.RequestVerification(activeRoles, e.DBName.O, v.Meta().Name.O, "", mysql.AllPrivMask) {
+		if v.Meta().State == model.StateWriteReorganization {
+			// The table being created by CREATE TABLE ... SELECT isn't listed until it's public.
+			continue
+		} else if checker != nil && !checker.RequestVerification(activeRoles, e.DBName.O, v.Meta().Name.O, "", mysql.AllPrivMask) {
 			continue
 		} else if FieldFilterEnable && v.Meta().Name.L != fieldFilter {
 			continue
@@ -1410,6 +1419,9 @@ func (e *ShowExec) fetchShowCollation() error {
 }
 
 // fetchShowCreateUser composes show create create user result.
+// userAttributesMetadataPath is the path of the COMMENT and ATTRIBUTE in mysql.user.User_attributes.
+var userAttributesMetadataPath, _ = json.ParseJSONPathExpr("$.metadata")
+
 func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 	checker := privilege.GetPrivilegeManager(e.ctx)
 	if checker == nil {
@@ -1432,7 +1444,7 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
 
-	rows, _, err := exec.ExecRestrictedSQL(ctx, nil, `SELECT plugin FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, strings.ToLower(hostName))
+	rows, _, err := exec.ExecRestrictedSQL(ctx, nil, `SELECT plugin, max_user_connections, account_locked, user_attributes FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, strings.ToLower(hostName))
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -1447,6 +1459,31 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 	if len(rows) == 1 && rows[0].GetString(0) != "" {
 		authplugin = rows[0].GetString(0)
 	}
+	maxUserConnections := rows[0].GetUint64(1)
+	accountLocked := "UNLOCK"
+	if rows[0].GetEnum(2).String() == "Y" {
+		accountLocked = "LOCK"
+	}
+	// The FAILED_LOGIN_ATTEMPTS, PASSWORD_LOCK_TIME and ATTRIBUTE are only shown when they are set.
+	userAttributesStr := ""
+	if !rows[0].IsNull(3) {
+		userAttributes := rows[0].GetJSON(3)
+		passwordLocking, err := privileges.ParsePasswordLocking(userAttributes.String())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if passwordLocking.FailedLoginAttempts > 0 {
+			userAttributesStr += fmt.Sprintf(" FAILED_LOGIN_ATTEMPTS %d", passwordLocking.FailedLoginAttempts)
+		}
+		if passwordLocking.PasswordLockTimeDays == privileges.PasswordLockTimeUnbounded {
+			userAttributesStr += " PASSWORD_LOCK_TIME UNBOUNDED"
+		} else if passwordLocking.PasswordLockTimeDays > 0 {
+			userAttributesStr += fmt.Sprintf(" PASSWORD_LOCK_TIME %d", passwordLocking.PasswordLockTimeDays)
+		}
+		if metadata, ok := userAttributes.Extract([]json.PathExpression{userAttributesMetadataPath}); ok {
+			userAttributesStr += fmt.Sprintf(" ATTRIBUTE '%s'", metadata.String())
+		}
+	}
 
 	rows, _, err = exec.ExecRestrictedSQL(ctx, nil, `SELECT Priv FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.GlobalPrivTable, userName, hostName)
 	if err != nil {
@@ -1454,6 +1491,7 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 	}
 
 	require := "NONE"
+	resourceGroup := ""
 	if len(rows) == 1 {
 		privData := rows[0].GetString(0)
 		var privValue privileges.GlobalPrivValue
@@ -1462,6 +1500,18 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 			return errors.Trace(err)
 		}
 		require = privValue.RequireStr()
+		resourceGroup = privValue.ResourceGroup
+	}
+
+	resourceStr := ""
+	if maxUserConnections > 0 {
+		resourceStr += fmt.Sprintf(" MAX_USER_CONNECTIONS %d", maxUserConnections)
+	}
+	if resourceGroup != "" {
+		resourceStr += fmt.Sprintf(" RESOURCE GROUP `%s`", resourceGroup)
+	}
+	if resourceStr != "" {
+		resourceStr = " WITH" + resourceStr
 	}
 
 	authData := checker.GetEncodedPassword(e.User.Username, e.User.Hostname)
@@ -1471,8 +1521,8 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 	}
 
 	// FIXME: the returned string is not escaped safely
-	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s'%s REQUIRE %s PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
-		e.User.Username, e.User.Hostname, authplugin, authStr, require)
+	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s'%s REQUIRE %s%s PASSWORD EXPIRE DEFAULT ACCOUNT %s%s",
+		e.User.Username, e.User.Hostname, authplugin, authStr, require, resourceStr, accountLocked, userAttributesStr)
 	e.appendRow([]interface{}{showStr})
 	return nil
 }
@@ -1755,23 +1805,51 @@ func (e *ShowExec) fetchShowTableRegions() error {
 		if indexInfo == nil {
 			return plannercore.ErrKeyDoesNotExist.GenWithStackByArgs(e.IndexName, tb.Meta().Name)
 		}
-		regions, err = getTableIndexRegions(indexInfo, physicalIDs, tikvStore, splitStore)
+		regions, err = getTableIndexRegions(indexInfo, physicalIDs, tikvStore)
 	} else {
-		regions, err = getTableRegions(tb, physicalIDs, tikvStore, splitStore)
+		regions, err = getTableRegions(tb, physicalIDs, tikvStore)
 	}
-
 	if err != nil {
 		return err
 	}
+
+	// Filter the regions before requesting their details, which is expensive for the tables with many regions.
+	if e.Extractor != nil {
+		regions = filterRegions(regions, e.Extractor.(*plannercore.ShowRegionsExtractor))
+	}
+	if err = fillRegionsDetail(tikvStore, splitStore, regions); err != nil {
+		return err
+	}
 	e.fillRegionsToChunk(regions)
 	return nil
 }
 
-func getTableRegions(tb table.Table, physicalIDs []int64, tikvStore helper.Storage, splitStore kv.SplittableStore) ([]regionMeta, error) {
+func filterRegions(regions []regionMeta, extractor *plannercore.ShowRegionsExtractor) []regionMeta {
+	filtered := regions[:0]
+	for _, region := range regions {
+		if extractor.Limit > 0 && uint64(len(filtered)) >= extractor.Limit {
+			break
+		}
+		if extractor.RegionIDs != nil {
+			if _, ok := extractor.RegionIDs[region.region.Id]; !ok {
+				continue
+			}
+		}
+		if extractor.LeaderStoreIDs != nil {
+			if _, ok := extractor.LeaderStoreIDs[region.storeID]; !ok {
+				continue
+			}
+		}
+		filtered = append(filtered, region)
+	}
+	return filtered
+}
+
+func getTableRegions(tb table.Table, physicalIDs []int64, tikvStore helper.Storage) ([]regionMeta, error) {
 	regions := make([]regionMeta, 0, len(physicalIDs))
 	uniqueRegionMap := make(map[uint64]struct{})
 	for _, id := range physicalIDs {
-		rs, err := getPhysicalTableRegions(id, tb.Meta(), tikvStore, splitStore, uniqueRegionMap)
+		rs, err := getPhysicalTableRegions(id, tb.Meta(), tikvStore, uniqueRegionMap)
 		if err != nil {
 			return nil, err
 		}
@@ -1780,11 +1858,11 @@ func getTableRegions(tb table.Table, physicalIDs []int64, tikvStore helper.Stora
 	return regions, nil
 }
 
-func getTableIndexRegions(indexInfo *model.IndexInfo, physicalIDs []int64, tikvStore helper.Storage, splitStore kv.SplittableStore) ([]regionMeta, error) {
+func getTableIndexRegions(indexInfo *model.IndexInfo, physicalIDs []int64, tikvStore helper.Storage) ([]regionMeta, error) {
 	regions := make([]regionMeta, 0, len(physicalIDs))
 	uniqueRegionMap := make(map[uint64]struct{})
 	for _, id := range physicalIDs {
-		rs, err := getPhysicalIndexRegions(id, indexInfo, tikvStore, splitStore, uniqueRegionMap)
+		rs, err := getPhysicalIndexRegions(id, indexInfo, tikvStore, uniqueRegionMap)
 		if err != nil {
 			return nil, err
 		}
@@ -1819,6 +1897,7 @@ func (e *ShowExec) fillRegionsToChunk(regions []regionMeta) {
 		e.result.AppendUint64(8, regions[i].readBytes)
 		e.result.AppendInt64(9, regions[i].approximateSize)
 		e.result.AppendInt64(10, regions[i].approximateKeys)
+		e.result.AppendString(11, regions[i].storeAddress)
 	}
 }
 
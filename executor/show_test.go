@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/auth"
@@ -484,6 +485,51 @@ func TestShowCreateUser(t *testing.T) {
 	require.Equal(t, "CREATE USER 'sock2'@'%' IDENTIFIED WITH 'auth_socket' AS 'sock3' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK", rows.Rows()[0][0].(string))
 }
 
+func TestShowCreateUserWithResourceOptions(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER 'res_user' WITH MAX_USER_CONNECTIONS 3 RESOURCE GROUP rg1")
+	tk.MustQuery("SHOW CREATE USER 'res_user'").Check(testkit.Rows(
+		"CREATE USER 'res_user'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE WITH MAX_USER_CONNECTIONS 3 RESOURCE GROUP `rg1` PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+	tk.MustQuery("SELECT max_user_connections FROM mysql.user WHERE User = 'res_user'").Check(testkit.Rows("3"))
+
+	// The resource group is kept when the TLS options are changed.
+	tk.MustExec("ALTER USER 'res_user' REQUIRE SSL")
+	tk.MustQuery("SHOW CREATE USER 'res_user'").Check(testkit.Rows(
+		"CREATE USER 'res_user'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE SSL WITH MAX_USER_CONNECTIONS 3 RESOURCE GROUP `rg1` PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+	tk.MustExec("GRANT SELECT ON *.* TO 'res_user' REQUIRE NONE")
+	tk.MustQuery("SHOW CREATE USER 'res_user'").Check(testkit.Rows(
+		"CREATE USER 'res_user'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE WITH MAX_USER_CONNECTIONS 3 RESOURCE GROUP `rg1` PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+	tk.MustExec("ALTER USER 'res_user' WITH MAX_USER_CONNECTIONS 0 RESOURCE GROUP rg2")
+	tk.MustQuery("SHOW CREATE USER 'res_user'").Check(testkit.Rows(
+		"CREATE USER 'res_user'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE WITH RESOURCE GROUP `rg2` PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+	tk.MustExec("ALTER USER 'res_user' WITH RESOURCE GROUP ``")
+	tk.MustQuery("SHOW CREATE USER 'res_user'").Check(testkit.Rows(
+		"CREATE USER 'res_user'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+
+	// The other resource options are ignored.
+	tk.MustExec("CREATE USER 'res_user2' WITH MAX_QUERIES_PER_HOUR 2")
+	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows("Warning 1105 TiDB does not support MAX_QUERIES_PER_HOUR 2 now, it would be parsed but ignored."))
+}
+
+func TestShowCreateUserWithPasswordOrLockOptions(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER 'lock_user' ACCOUNT LOCK FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME 2 COMMENT 'some comment'")
+	tk.MustQuery("SHOW CREATE USER 'lock_user'").Check(testkit.Rows(
+		`CREATE USER 'lock_user'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT LOCK FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME 2 ATTRIBUTE '{"comment": "some comment"}'`))
+	tk.MustExec("ALTER USER 'lock_user' ACCOUNT UNLOCK PASSWORD_LOCK_TIME UNBOUNDED ATTRIBUTE '{\"comment\": null, \"name\": \"Tom\"}'")
+	tk.MustQuery("SHOW CREATE USER 'lock_user'").Check(testkit.Rows(
+		`CREATE USER 'lock_user'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME UNBOUNDED ATTRIBUTE '{"name": "Tom"}'`))
+
+	tk.MustGetErrCode("ALTER USER 'lock_user' ATTRIBUTE '[1, 2]'", errno.ErrInvalidUserAttributeJSON)
+	tk.MustGetErrCode("ALTER USER 'lock_user' FAILED_LOGIN_ATTEMPTS 32768", errno.ErrTruncatedWrongValue)
+	tk.MustExec("ALTER USER 'lock_user' PASSWORD EXPIRE NEVER")
+	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows("Warning 1105 TiDB does not support PASSWORD EXPIRE NEVER now, it would be parsed but ignored."))
+}
+
 func TestUnprivilegedShow(t *testing.T) {
 	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
 	defer clean()
@@ -1404,7 +1450,7 @@ func TestShowBuiltin(t *testing.T) {
 	res := tk.MustQuery("show builtins;")
 	require.NotNil(t, res)
 	rows := res.Rows()
-	const builtinFuncNum = 274
+	const builtinFuncNum = 277
 	require.Equal(t, len(rows), builtinFuncNum)
 	require.Equal(t, rows[0][0].(string), "abs")
 	require.Equal(t, rows[builtinFuncNum-1][0].(string), "yearweek")
@@ -16,8 +16,10 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -34,12 +36,14 @@ import (
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/format"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/core"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
@@ -751,6 +755,101 @@ func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
 	return nil
 }
 
+// userResourceOptions returns the MAX_USER_CONNECTIONS and RESOURCE GROUP options, nil means the option is not
+// specified. The other resource options are ignored.
+func (e *SimpleExec) userResourceOptions(options []*ast.ResourceOption) (maxUserConnections *int64, resourceGroup *string) {
+	for _, option := range options {
+		switch option.Type {
+		case ast.MaxUserConnections:
+			maxUserConnections = &option.Count
+		case ast.ResourceGroup:
+			resourceGroup = &option.ResourceGroupName
+		default:
+			var sb strings.Builder
+			if err := option.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+				continue
+			}
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("TiDB does not support %s now, it would be parsed but ignored.", sb.String()))
+		}
+	}
+	return maxUserConnections, resourceGroup
+}
+
+// maxPasswordLockingValue is the maximum value of FAILED_LOGIN_ATTEMPTS and PASSWORD_LOCK_TIME.
+const maxPasswordLockingValue = 32767
+
+// userPasswordOrLockOptions returns the ACCOUNT LOCK/UNLOCK option and the Password_locking patch of
+// mysql.user.User_attributes built from the FAILED_LOGIN_ATTEMPTS and PASSWORD_LOCK_TIME options.
+// accountLocked is "Y" for ACCOUNT LOCK, "N" for ACCOUNT UNLOCK and "" if it is not specified, nil
+// passwordLocking means nothing to change. The other password options are ignored.
+func (e *SimpleExec) userPasswordOrLockOptions(options []*ast.PasswordOrLockOption) (accountLocked string, passwordLocking map[string]interface{}, err error) {
+	passwordLocking = make(map[string]interface{})
+	for _, option := range options {
+		switch option.Type {
+		case ast.Lock:
+			accountLocked = "Y"
+		case ast.Unlock:
+			accountLocked = "N"
+		case ast.FailedLoginAttempts:
+			if option.Count < 0 || option.Count > maxPasswordLockingValue {
+				return "", nil, types.ErrWrongValue.GenWithStackByArgs("failed_login_attempts", strconv.FormatInt(option.Count, 10))
+			}
+			passwordLocking["failed_login_attempts"] = option.Count
+		case ast.PasswordLockTime:
+			if option.Count < 0 || option.Count > maxPasswordLockingValue {
+				return "", nil, types.ErrWrongValue.GenWithStackByArgs("password_lock_time", strconv.FormatInt(option.Count, 10))
+			}
+			passwordLocking["password_lock_time_days"] = option.Count
+		case ast.PasswordLockTimeUnbounded:
+			passwordLocking["password_lock_time_days"] = privileges.PasswordLockTimeUnbounded
+		default:
+			var sb strings.Builder
+			if err := option.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+				continue
+			}
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("TiDB does not support %s now, it would be parsed but ignored.", sb.String()))
+		}
+	}
+	// Changing FAILED_LOGIN_ATTEMPTS or PASSWORD_LOCK_TIME and ACCOUNT UNLOCK reset the failed login count
+	// and unlock the account locked by consecutive failed logins.
+	if len(passwordLocking) == 0 && accountLocked != "N" {
+		return accountLocked, nil, nil
+	}
+	passwordLocking["failed_login_count"] = 0
+	passwordLocking["auto_account_locked"] = false
+	passwordLocking["auto_locked_last_changed"] = ""
+	return accountLocked, passwordLocking, nil
+}
+
+// userAttributesPatch returns the JSON merge patch of mysql.user.User_attributes built from the Password_locking
+// patch and the COMMENT or ATTRIBUTE option, empty string means nothing to change.
+func userAttributesPatch(passwordLocking map[string]interface{}, option *ast.CommentOrAttributeOption) (string, error) {
+	patch := make(map[string]interface{})
+	if passwordLocking != nil {
+		patch["Password_locking"] = passwordLocking
+	}
+	if option != nil {
+		switch option.Type {
+		case ast.UserCommentType:
+			patch["metadata"] = map[string]interface{}{"comment": option.Value}
+		case ast.UserAttributeType:
+			var metadata map[string]interface{}
+			if err := json.Unmarshal(hack.Slice(option.Value), &metadata); err != nil || metadata == nil {
+				return "", ErrInvalidUserAttributeJSON
+			}
+			patch["metadata"] = metadata
+		}
+	}
+	if len(patch) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data), nil
+}
+
 func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStmt) error {
 	// Check `CREATE USER` privilege.
 	if !config.GetGlobalConfig().Security.SkipGrantTable {
@@ -776,12 +875,38 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 	if err != nil {
 		return err
 	}
+	maxUserConnections, resourceGroup := e.userResourceOptions(s.ResourceOptions)
+	if resourceGroup != nil {
+		privData, err = setGlobalPrivResourceGroup(privData, *resourceGroup)
+		if err != nil {
+			return err
+		}
+	}
+	var maxUserConns int64
+	if maxUserConnections != nil {
+		maxUserConns = *maxUserConnections
+	}
+	accountLocked, passwordLocking, err := e.userPasswordOrLockOptions(s.PasswordOrLockOptions)
+	if err != nil {
+		return err
+	}
+	if accountLocked == "" {
+		accountLocked = "N"
+	}
+	var userAttributes interface{}
+	patch, err := userAttributesPatch(passwordLocking, s.CommentOrAttributeOption)
+	if err != nil {
+		return err
+	}
+	if patch != "" {
+		userAttributes = patch
+	}
 
 	sql := new(strings.Builder)
 	if s.IsCreateRole {
 		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, Account_locked) VALUES `, mysql.SystemDB, mysql.UserTable)
 	} else {
-		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin) VALUES `, mysql.SystemDB, mysql.UserTable)
+		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, max_user_connections, Account_locked, User_attributes) VALUES `, mysql.SystemDB, mysql.UserTable)
 	}
 
 	users := make([]*auth.UserIdentity, 0, len(s.Specs))
@@ -825,7 +950,7 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 		if s.IsCreateRole {
 			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?)`, hostName, spec.User.Username, pwd, authPlugin, "Y")
 		} else {
-			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?)`, hostName, spec.User.Username, pwd, authPlugin)
+			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?, %?, %?)`, hostName, spec.User.Username, pwd, authPlugin, maxUserConns, accountLocked, userAttributes)
 		}
 		users = append(users, spec.User)
 	}
@@ -893,6 +1018,15 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 	if err != nil {
 		return err
 	}
+	maxUserConnections, resourceGroup := e.userResourceOptions(s.ResourceOptions)
+	accountLocked, passwordLocking, err := e.userPasswordOrLockOptions(s.PasswordOrLockOptions)
+	if err != nil {
+		return err
+	}
+	userAttributes, err := userAttributesPatch(passwordLocking, s.CommentOrAttributeOption)
+	if err != nil {
+		return err
+	}
 
 	failedUsers := make([]string, 0, len(s.Specs))
 	checker := privilege.GetPrivilegeManager(e.ctx)
@@ -968,7 +1102,7 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 				return errors.Trace(ErrPasswordFormat)
 			}
 			_, _, err := exec.ExecRestrictedSQL(ctx, nil,
-				`UPDATE %n.%n SET authentication_string=%?, plugin=%? WHERE Host=%? and User=%?;`,
+				`UPDATE %n.%n SET authentication_string=%?, plugin=%?, password_last_changed=CURRENT_TIMESTAMP() WHERE Host=%? and User=%?;`,
 				mysql.SystemDB, mysql.UserTable, pwd, spec.AuthOpt.AuthPlugin, strings.ToLower(spec.User.Hostname), spec.User.Username,
 			)
 			if err != nil {
@@ -976,8 +1110,47 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 			}
 		}
 
+		if maxUserConnections != nil {
+			_, _, err := exec.ExecRestrictedSQL(ctx, nil,
+				`UPDATE %n.%n SET max_user_connections=%? WHERE Host=%? and User=%?;`,
+				mysql.SystemDB, mysql.UserTable, *maxUserConnections, strings.ToLower(spec.User.Hostname), spec.User.Username,
+			)
+			if err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
+		}
+
+		if accountLocked != "" {
+			_, _, err := exec.ExecRestrictedSQL(ctx, nil,
+				`UPDATE %n.%n SET account_locked=%? WHERE Host=%? and User=%?;`,
+				mysql.SystemDB, mysql.UserTable, accountLocked, strings.ToLower(spec.User.Hostname), spec.User.Username,
+			)
+			if err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
+		}
+
+		if userAttributes != "" {
+			_, _, err := exec.ExecRestrictedSQL(ctx, nil,
+				`UPDATE %n.%n SET user_attributes=JSON_MERGE_PATCH(COALESCE(user_attributes, '{}'), %?) WHERE Host=%? and User=%?;`,
+				mysql.SystemDB, mysql.UserTable, userAttributes, strings.ToLower(spec.User.Hostname), spec.User.Username,
+			)
+			if err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
+		}
+
 		if len(privData) > 0 {
-			_, _, err := exec.ExecRestrictedSQL(ctx, nil, "INSERT INTO %n.%n (Host, User, Priv) VALUES (%?,%?,%?) ON DUPLICATE KEY UPDATE Priv = values(Priv)", mysql.SystemDB, mysql.GlobalPrivTable, spec.User.Hostname, spec.User.Username, string(hack.String(privData)))
+			var userPrivData []byte
+			if resourceGroup != nil {
+				userPrivData, err = setGlobalPrivResourceGroup(privData, *resourceGroup)
+			} else {
+				userPrivData, err = keepGlobalPrivResourceGroup(ctx, e.ctx, spec.User.Username, spec.User.Hostname, privData)
+			}
+			if err != nil {
+				return err
+			}
+			_, _, err := exec.ExecRestrictedSQL(ctx, nil, "INSERT INTO %n.%n (Host, User, Priv) VALUES (%?,%?,%?) ON DUPLICATE KEY UPDATE Priv = values(Priv)", mysql.SystemDB, mysql.GlobalPrivTable, spec.User.Hostname, spec.User.Username, string(hack.String(userPrivData)))
 			if err != nil {
 				failedUsers = append(failedUsers, spec.User.String())
 			}
@@ -1430,7 +1603,7 @@ func (e *SimpleExec) executeSetPwd(ctx context.Context, s *ast.SetPwdStmt) error
 
 	// update mysql.user
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
-	_, _, err = exec.ExecRestrictedSQL(ctx, nil, `UPDATE %n.%n SET authentication_string=%? WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, strings.ToLower(h))
+	_, _, err = exec.ExecRestrictedSQL(ctx, nil, `UPDATE %n.%n SET authentication_string=%?, password_last_changed=CURRENT_TIMESTAMP() WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, strings.ToLower(h))
 	if err != nil {
 		return err
 	}
@@ -1540,6 +1713,8 @@ func (e *SimpleExec) executeFlush(s *ast.FlushStmt) error {
 		if s.ReadLock {
 			return errors.New("FLUSH TABLES WITH READ LOCK is not supported.  Please use @@tidb_snapshot")
 		}
+		// Reload the time zone tables, so the changes of them take effect.
+		return LoadTimeZoneTables(e.ctx)
 	case ast.FlushPrivileges:
 		dom := domain.GetDomain(e.ctx)
 		return dom.NotifyUpdatePrivilege()
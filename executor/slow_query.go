@@ -564,6 +564,10 @@ func (e *slowQueryRetriever) parseLog(ctx context.Context, sctx sessionctx.Conte
 				valid := true
 				if strings.HasPrefix(line, variable.SlowLogPrevStmtPrefix) {
 					valid = e.setColumnValue(sctx, row, tz, variable.SlowLogPrevStmt, line[len(variable.SlowLogPrevStmtPrefix):], e.checker, fileLine)
+				} else if strings.HasPrefix(line, variable.SlowLogQueryAttributesPrefix) {
+					valid = e.setColumnValue(sctx, row, tz, variable.SlowLogQueryAttributes, line[len(variable.SlowLogQueryAttributesPrefix):], e.checker, fileLine)
+				} else if strings.HasPrefix(line, variable.SlowLogSessionAliasPrefix) {
+					valid = e.setColumnValue(sctx, row, tz, variable.SlowLogSessionAlias, line[len(variable.SlowLogSessionAliasPrefix):], e.checker, fileLine)
 				} else if strings.HasPrefix(line, variable.SlowLogUserAndHostStr+variable.SlowLogSpaceMarkStr) {
 					value := line[len(variable.SlowLogUserAndHostStr+variable.SlowLogSpaceMarkStr):]
 					fields := strings.SplitN(value, "@", 2)
@@ -723,7 +727,7 @@ func getColumnValueFactoryByName(sctx sessionctx.Context, colName string, column
 		}, nil
 	case variable.SlowLogUserStr, variable.SlowLogHostStr, execdetails.BackoffTypesStr, variable.SlowLogDBStr, variable.SlowLogIndexNamesStr, variable.SlowLogDigestStr,
 		variable.SlowLogStatsInfoStr, variable.SlowLogCopProcAddr, variable.SlowLogCopWaitAddr, variable.SlowLogPlanDigest,
-		variable.SlowLogPrevStmt, variable.SlowLogQuerySQLStr:
+		variable.SlowLogQueryAttributes, variable.SlowLogSessionAlias, variable.SlowLogPrevStmt, variable.SlowLogQuerySQLStr:
 		return func(row []types.Datum, value string, tz *time.Location, checker *slowLogChecker) (valid bool, err error) {
 			row[columnIdx] = types.NewStringDatum(value)
 			return true, nil
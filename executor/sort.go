@@ -450,7 +450,7 @@ func (e *TopNExec) executeTopN(ctx context.Context) error {
 		// The number of rows we loaded may exceeds total limit, remove greatest rows by Pop.
 		heap.Pop(e.chkHeap)
 	}
-	childRowChk := newFirstChunk(e.children[0])
+	childRowChk := tryNewCacheChunk(e.children[0])
 	for {
 		err := Next(ctx, e.children[0], childRowChk)
 		if err != nil {
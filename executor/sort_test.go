@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/stretchr/testify/require"
 )
 
@@ -93,6 +94,50 @@ func testSortInDisk(t *testing.T, removeDir bool) {
 	require.Greater(t, tk.Session().GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), int64(0))
 }
 
+func TestSortSpillWithReuseChunk(t *testing.T) {
+	restore := config.RestoreFunc()
+	defer restore()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+		conf.OOMAction = config.OOMActionLog
+	})
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/testSortedRowContainerSpill", "return(true)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/testSortedRowContainerSpill"))
+	}()
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	alloc := chunk.NewSyncAllocator(0)
+	tk.Session().GetSessionVars().SetChunkAllocator(alloc)
+	tk.MustExec("set @@tidb_enable_reuse_chunk = on")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	tk.MustExec("create table t(c1 int, c2 int, c3 int)")
+	var buf bytes.Buffer
+	buf.WriteString("insert into t values ")
+	for i := 0; i < 1024; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(fmt.Sprintf("(%v, %v, %v)", 1023-i, i, i))
+	}
+	tk.MustExec(buf.String())
+	alloc.Reset()
+	alloc.TakeStats()
+
+	tk.MustExec("set @@tidb_mem_quota_query = 1")
+	result := tk.MustQuery("select * from t order by c1")
+	require.Len(t, result.Rows(), 1024)
+	require.Equal(t, "0", result.Rows()[0][0])
+	require.Greater(t, tk.Session().GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), int64(0))
+	require.Equal(t, int64(0), tk.Session().GetSessionVars().StmtCtx.MemTracker.BytesConsumed())
+	// The 32 chunks read by the sort are spilled and released by GC, they must
+	// not be held by the allocator until the statement ends.
+	stats := alloc.TakeStats()
+	require.Less(t, stats.NewColumns+stats.ReusedColumns, int64(32))
+}
+
 func TestIssue16696(t *testing.T) {
 	defer config.RestoreFunc()()
 	config.UpdateGlobal(func(conf *config.Config) {
@@ -620,9 +620,12 @@ type regionMeta struct {
 	readBytes       uint64
 	approximateSize int64
 	approximateKeys int64
+	storeAddress    string // storeAddress is the address of the leader store.
 }
 
-func getPhysicalTableRegions(physicalTableID int64, tableInfo *model.TableInfo, tikvStore helper.Storage, s kv.SplittableStore, uniqueRegionMap map[uint64]struct{}) ([]regionMeta, error) {
+// getPhysicalTableRegions returns the regions of the table from the region cache, the details
+// from PD and the scattering status aren't filled, see fillRegionsDetail.
+func getPhysicalTableRegions(physicalTableID int64, tableInfo *model.TableInfo, tikvStore helper.Storage, uniqueRegionMap map[uint64]struct{}) ([]regionMeta, error) {
 	if uniqueRegionMap == nil {
 		uniqueRegionMap = make(map[uint64]struct{})
 	}
@@ -640,12 +643,7 @@ func getPhysicalTableRegions(physicalTableID int64, tableInfo *model.TableInfo,
 	}
 	recordPrefix := tablecodec.GenTableRecordPrefix(physicalTableID)
 	tablePrefix := tablecodec.GenTablePrefix(physicalTableID)
-	recordRegions, err := getRegionMeta(tikvStore, recordRegionMetas, uniqueRegionMap, tablePrefix, recordPrefix, nil, physicalTableID, 0, hasUnsignedIntHandle)
-	if err != nil {
-		return nil, err
-	}
-
-	regions := recordRegions
+	regions := getRegionMeta(recordRegionMetas, uniqueRegionMap, tablePrefix, recordPrefix, nil, physicalTableID, 0, hasUnsignedIntHandle)
 	// for indices
 	for _, index := range tableInfo.Indices {
 		if index.State != model.StatePublic {
@@ -657,20 +655,15 @@ func getPhysicalTableRegions(physicalTableID int64, tableInfo *model.TableInfo,
 			return nil, err
 		}
 		indexPrefix := tablecodec.EncodeTableIndexPrefix(physicalTableID, index.ID)
-		indexRegions, err := getRegionMeta(tikvStore, regionMetas, uniqueRegionMap, tablePrefix, recordPrefix, indexPrefix, physicalTableID, index.ID, hasUnsignedIntHandle)
-		if err != nil {
-			return nil, err
-		}
+		indexRegions := getRegionMeta(regionMetas, uniqueRegionMap, tablePrefix, recordPrefix, indexPrefix, physicalTableID, index.ID, hasUnsignedIntHandle)
 		regions = append(regions, indexRegions...)
 	}
-	err = checkRegionsStatus(s, regions)
-	if err != nil {
-		return nil, err
-	}
 	return regions, nil
 }
 
-func getPhysicalIndexRegions(physicalTableID int64, indexInfo *model.IndexInfo, tikvStore helper.Storage, s kv.SplittableStore, uniqueRegionMap map[uint64]struct{}) ([]regionMeta, error) {
+// getPhysicalIndexRegions returns the regions of the index from the region cache, the details
+// from PD and the scattering status aren't filled, see fillRegionsDetail.
+func getPhysicalIndexRegions(physicalTableID int64, indexInfo *model.IndexInfo, tikvStore helper.Storage, uniqueRegionMap map[uint64]struct{}) ([]regionMeta, error) {
 	if uniqueRegionMap == nil {
 		uniqueRegionMap = make(map[uint64]struct{})
 	}
@@ -684,15 +677,19 @@ func getPhysicalIndexRegions(physicalTableID int64, indexInfo *model.IndexInfo,
 	recordPrefix := tablecodec.GenTableRecordPrefix(physicalTableID)
 	tablePrefix := tablecodec.GenTablePrefix(physicalTableID)
 	indexPrefix := tablecodec.EncodeTableIndexPrefix(physicalTableID, indexInfo.ID)
-	indexRegions, err := getRegionMeta(tikvStore, regions, uniqueRegionMap, tablePrefix, recordPrefix, indexPrefix, physicalTableID, indexInfo.ID, false)
-	if err != nil {
-		return nil, err
+	return getRegionMeta(regions, uniqueRegionMap, tablePrefix, recordPrefix, indexPrefix, physicalTableID, indexInfo.ID, false), nil
+}
+
+// fillRegionsDetail fills the details from PD and the scattering status of the regions,
+// they are requested region by region, so the regions should be filtered before.
+func fillRegionsDetail(tikvStore helper.Storage, s kv.SplittableStore, regions []regionMeta) error {
+	if err := getRegionInfo(tikvStore, regions); err != nil {
+		return err
 	}
-	err = checkRegionsStatus(s, indexRegions)
-	if err != nil {
-		return nil, err
+	if err := getLeaderStoreAddress(tikvStore, regions); err != nil {
+		return err
 	}
-	return indexRegions, nil
+	return checkRegionsStatus(s, regions)
 }
 
 func checkRegionsStatus(store kv.SplittableStore, regions []regionMeta) error {
@@ -775,9 +772,9 @@ func (d *regionKeyDecoder) decodeRegionKey(key []byte) string {
 	return fmt.Sprintf("%x", key)
 }
 
-func getRegionMeta(tikvStore helper.Storage, regionMetas []*tikv.Region, uniqueRegionMap map[uint64]struct{},
+func getRegionMeta(regionMetas []*tikv.Region, uniqueRegionMap map[uint64]struct{},
 	tablePrefix, recordPrefix, indexPrefix []byte, physicalTableID, indexID int64,
-	hasUnsignedIntHandle bool) ([]regionMeta, error) {
+	hasUnsignedIntHandle bool) []regionMeta {
 	regions := make([]regionMeta, 0, len(regionMetas))
 	for _, r := range regionMetas {
 		if _, ok := uniqueRegionMap[r.GetID()]; ok {
@@ -790,26 +787,22 @@ func getRegionMeta(tikvStore helper.Storage, regionMetas []*tikv.Region, uniqueR
 			storeID:  r.GetLeaderStoreID(),
 		})
 	}
-	regions, err := getRegionInfo(tikvStore, regions)
-	if err != nil {
-		return regions, err
-	}
 	decodeRegionsKey(regions, tablePrefix, recordPrefix, indexPrefix, physicalTableID, indexID, hasUnsignedIntHandle)
-	return regions, nil
+	return regions
 }
 
-func getRegionInfo(store helper.Storage, regions []regionMeta) ([]regionMeta, error) {
+func getRegionInfo(store helper.Storage, regions []regionMeta) error {
 	// check pd server exists.
 	etcd, ok := store.(kv.EtcdBackend)
 	if !ok {
-		return regions, nil
+		return nil
 	}
 	pdHosts, err := etcd.EtcdAddrs()
 	if err != nil {
-		return regions, err
+		return err
 	}
 	if len(pdHosts) == 0 {
-		return regions, nil
+		return nil
 	}
 	tikvHelper := &helper.Helper{
 		Store:       store,
@@ -818,12 +811,35 @@ func getRegionInfo(store helper.Storage, regions []regionMeta) ([]regionMeta, er
 	for i := range regions {
 		regionInfo, err := tikvHelper.GetRegionInfoByID(regions[i].region.Id)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		regions[i].writtenBytes = regionInfo.WrittenBytes
 		regions[i].readBytes = regionInfo.ReadBytes
 		regions[i].approximateSize = regionInfo.ApproximateSize
 		regions[i].approximateKeys = regionInfo.ApproximateKeys
 	}
-	return regions, nil
+	return nil
+}
+
+// getLeaderStoreAddress fills the leader store addresses of the regions from PD.
+func getLeaderStoreAddress(store helper.Storage, regions []regionMeta) error {
+	pdCli := store.GetRegionCache().PDClient()
+	addrs := make(map[uint64]string)
+	for i := range regions {
+		// The leader of the region may be unknown.
+		if regions[i].storeID == 0 {
+			continue
+		}
+		addr, ok := addrs[regions[i].storeID]
+		if !ok {
+			s, err := pdCli.GetStore(context.Background(), regions[i].storeID)
+			if err != nil {
+				return err
+			}
+			addr = s.GetAddress()
+			addrs[regions[i].storeID] = addr
+		}
+		regions[i].storeAddress = addr
+	}
+	return nil
 }
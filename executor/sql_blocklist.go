@@ -0,0 +1,80 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlblocklist"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// ReloadSQLBlocklistExec indicates ReloadSQLBlocklist executor.
+type ReloadSQLBlocklistExec struct {
+	baseExecutor
+}
+
+// Next implements the Executor Next interface.
+func (e *ReloadSQLBlocklistExec) Next(ctx context.Context, _ *chunk.Chunk) error {
+	if err := LoadSQLBlocklist(e.ctx); err != nil {
+		return err
+	}
+	// Let the other TiDB instances reload the rules too.
+	domain.GetDomain(e.ctx).NotifyUpdateSQLBlocklist()
+	return nil
+}
+
+// LoadSQLBlocklist loads the latest data from table mysql.sql_blocklist.
+func LoadSQLBlocklist(ctx sessionctx.Context) (err error) {
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	rows, _, err := exec.ExecRestrictedSQL(context.TODO(), nil, "select HIGH_PRIORITY name, digest, "+
+		"IFNULL(pattern, ''), user, table_name, action, IFNULL(replacement, ''), IFNULL(reason, '') "+
+		"from mysql.sql_blocklist order by name")
+	if err != nil {
+		return err
+	}
+	rules := make([]*sqlblocklist.Rule, 0, len(rows))
+	for _, row := range rows {
+		rules = append(rules, sqlblocklist.NewRule(row.GetString(0), row.GetString(1), row.GetString(2),
+			row.GetString(3), row.GetString(4), sqlblocklist.Action(row.GetEnum(5).String()),
+			row.GetString(6), row.GetString(7)))
+	}
+	sqlblocklist.SetRules(rules)
+	return nil
+}
+
+// MatchSQLBlocklist returns the first rule of mysql.sql_blocklist that the statement
+// matches, or nil if there is none. The internal SQLs are never matched.
+func MatchSQLBlocklist(sctx sessionctx.Context, stmtNode ast.StmtNode) *sqlblocklist.Rule {
+	vars := sctx.GetSessionVars()
+	if vars.InRestrictedSQL || len(sqlblocklist.GetRules()) == 0 {
+		return nil
+	}
+	normalizedSQL, digest := parser.NormalizeDigest(stmtNode.Text())
+	info := &sqlblocklist.StmtInfo{
+		Digest:        digest.String(),
+		NormalizedSQL: normalizedSQL,
+		Tables:        sqlblocklist.ExtractTables(stmtNode, vars.CurrentDB),
+	}
+	if vars.User != nil {
+		info.User = vars.User.Username
+	}
+	return sqlblocklist.Match(info)
+}
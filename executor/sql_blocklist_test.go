@@ -0,0 +1,101 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/util/sqlblocklist"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLBlocklist(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	defer sqlblocklist.SetRules(nil)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("insert into t values (1), (2)")
+	tk.MustExec("create user 'u1'@'%'")
+	tk.MustExec("grant all on test.* to 'u1'@'%'")
+
+	requireBlocked := func(tk *testkit.TestKit, sql string) {
+		err := tk.ExecToErr(sql)
+		require.Error(t, err, sql)
+		require.True(t, executor.ErrSQLBlocked.Equal(err), err.Error())
+	}
+
+	// The rules take effect after reloading.
+	tk.MustExec("insert into mysql.sql_blocklist (name, table_name, reason) values ('no_t1', 'test.t1', 'forbidden table')")
+	tk.MustQuery("select * from t1").Check(testkit.Rows())
+	tk.MustExec("admin reload sql_blocklist")
+	err := tk.ExecToErr("select * from t1")
+	require.EqualError(t, err, "[executor:8244]Statement is blocked by the SQL blocklist rule 'no_t1': forbidden table")
+	requireBlocked(tk, "insert into t1 select * from t")
+	requireBlocked(tk, "select * from t where a in (select a from test.t1)")
+	requireBlocked(tk, "prepare stmt from 'select * from t1 where a = ?'")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1", "2"))
+
+	// Rules by pattern and by digest.
+	_, digest := parser.NormalizeDigest("delete from t where a = 1")
+	tk.MustExec("delete from mysql.sql_blocklist")
+	tk.MustExec("insert into mysql.sql_blocklist (name, pattern) values ('no_truncate', 'truncate table %')")
+	tk.MustExec("insert into mysql.sql_blocklist (name, digest) values ('no_delete', ?)", digest.String())
+	tk.MustExec("admin reload sql_blocklist")
+	tk.MustQuery("select * from t1").Check(testkit.Rows())
+	requireBlocked(tk, "TRUNCATE TABLE t")
+	requireBlocked(tk, "delete from t where a = 2")
+	requireBlocked(tk, "prepare stmt from 'delete from t where a = ?'")
+	tk.MustExec("delete from t where a > 2")
+
+	// Rules by user only apply to the sessions of the user.
+	tk.MustExec("delete from mysql.sql_blocklist")
+	tk.MustExec("insert into mysql.sql_blocklist (name, user, pattern) values ('u1_no_select', 'u1', 'select %')")
+	tk.MustExec("admin reload sql_blocklist")
+	tk1 := testkit.NewTestKit(t, store)
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk1.MustExec("use test")
+	requireBlocked(tk1, "select * from t")
+	tk1.MustExec("insert into t values (3)")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1", "2", "3"))
+
+	// The rewrite rules replace the matching statements.
+	tk.MustExec("delete from mysql.sql_blocklist")
+	tk.MustExec("insert into mysql.sql_blocklist (name, table_name, action, replacement) " +
+		"values ('limit_t', 'test.t', 'rewrite', 'select * from test.t order by a limit 1')")
+	tk.MustExec("admin reload sql_blocklist")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1"))
+	requireBlocked(tk, "prepare stmt from 'select * from t where a = ?'")
+	tk.MustExec("update mysql.sql_blocklist set replacement = 'select 1; select 2'")
+	tk.MustExec("admin reload sql_blocklist")
+	requireBlocked(tk, "select * from t")
+
+	// Removing the rules.
+	tk.MustExec("delete from mysql.sql_blocklist")
+	tk.MustExec("admin reload sql_blocklist")
+	require.Empty(t, sqlblocklist.GetRules())
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1", "2", "3"))
+
+	// Only the administrators can reload the rules.
+	err = tk1.ExecToErr("admin reload sql_blocklist")
+	require.True(t, core.ErrPrivilegeCheckFail.Equal(err))
+}
@@ -111,6 +111,23 @@ type TableReaderExecutor struct {
 
 	// extraPIDColumnIndex is used for partition reader to add an extra partition ID column.
 	extraPIDColumnIndex offsetOptional
+
+	// fusedFilters are the conditions of the Selection fused into the reader, they are evaluated right after
+	// the rows are decoded and the unselected rows are removed from the chunk in place.
+	fusedFilters []expression.Expression
+	selected     []bool
+	sel          []int
+	// fusedProj is the Projection fused into the reader, it's evaluated after the fusedFilters.
+	fusedProj *fusedProjection
+}
+
+// fusedProjection is a cheap Projection fused into the TableReaderExecutor. Since the schema of the reader is
+// replaced by the schema of the Projection, it records the schema of the decoded rows.
+type fusedProjection struct {
+	evaluatorSuit *expression.EvaluatorSuite
+	scanSchema    *expression.Schema
+	scanRetTypes  []*types.FieldType
+	scanChk       *chunk.Chunk
 }
 
 // offsetOptional may be a positive integer, or invalid.
@@ -231,24 +248,87 @@ func (e *TableReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 		}
 		return tableName
 	}), e.ranges)
-	if err := e.resultHandler.nextChunk(ctx, req); err != nil {
-		e.feedback.Invalidate()
-		return err
+	scanChk := req
+	if e.fusedProj != nil {
+		// The columns of req may refer to the columns of scanChk, so req must be
+		// reset before scanChk is filled.
+		req.GrowAndReset(e.maxChunkSize)
+		scanChk = e.fusedProj.scanChk
+		scanChk.SetRequiredRows(req.RequiredRows(), e.maxChunkSize)
 	}
+	for {
+		if err := e.resultHandler.nextChunk(ctx, scanChk); err != nil {
+			e.feedback.Invalidate()
+			return err
+		}
 
-	err := FillVirtualColumnValue(e.virtualColumnRetFieldTypes, e.virtualColumnIndex, e.schema, e.columns, e.ctx, req)
-	if err != nil {
-		return err
+		err := FillVirtualColumnValue(e.virtualColumnRetFieldTypes, e.virtualColumnIndex, e.scanSchema(), e.columns, e.ctx, scanChk)
+		if err != nil {
+			return err
+		}
+
+		// When 'select ... for update' work on a partitioned table, the table reader should
+		// add the partition ID as an extra column. The SelectLockExec need this information
+		// to construct the lock key.
+		physicalID := getPhysicalTableID(e.table)
+		if e.extraPIDColumnIndex.valid() {
+			fillExtraPIDColumn(scanChk, e.extraPIDColumnIndex.value(), physicalID)
+		}
+
+		// An empty chunk means there is no more data, so we need to read the next
+		// chunk if all the rows are filtered out.
+		if scanChk.NumRows() == 0 || len(e.fusedFilters) == 0 {
+			break
+		}
+		if err = e.filterFusedChunk(scanChk); err != nil {
+			return err
+		}
+		if scanChk.NumRows() > 0 {
+			break
+		}
 	}
 
-	// When 'select ... for update' work on a partitioned table, the table reader should
-	// add the partition ID as an extra column. The SelectLockExec need this information
-	// to construct the lock key.
-	physicalID := getPhysicalTableID(e.table)
-	if e.extraPIDColumnIndex.valid() {
-		fillExtraPIDColumn(req, e.extraPIDColumnIndex.value(), physicalID)
+	if e.fusedProj == nil || scanChk.NumRows() == 0 {
+		return nil
 	}
+	return e.fusedProj.evaluatorSuit.Run(e.ctx, scanChk, req)
+}
 
+// scanSchema returns the schema of the rows decoded by the reader.
+func (e *TableReaderExecutor) scanSchema() *expression.Schema {
+	if e.fusedProj != nil {
+		return e.fusedProj.scanSchema
+	}
+	return e.schema
+}
+
+// scanRetTypes returns the field types of the rows decoded by the reader.
+func (e *TableReaderExecutor) scanRetTypes() []*types.FieldType {
+	if e.fusedProj != nil {
+		return e.fusedProj.scanRetTypes
+	}
+	return retTypes(e)
+}
+
+// filterFusedChunk evaluates the fusedFilters on the chunk and removes the unselected rows in place.
+func (e *TableReaderExecutor) filterFusedChunk(chk *chunk.Chunk) (err error) {
+	e.selected, err = expression.VectorizedFilter(e.ctx, e.fusedFilters, chunk.NewIterator4Chunk(chk), e.selected)
+	if err != nil {
+		return err
+	}
+	e.sel = e.sel[:0]
+	for i, selected := range e.selected {
+		if selected {
+			e.sel = append(e.sel, i)
+		}
+	}
+	switch {
+	case len(e.sel) == 0:
+		chk.Reset()
+	case len(e.sel) < chk.NumRows():
+		chk.SetSel(e.sel)
+		chk.Reconstruct()
+	}
 	return nil
 }
 
@@ -287,7 +367,7 @@ func (e *TableReaderExecutor) buildResp(ctx context.Context, ranges []*ranger.Ra
 		}
 		var results []distsql.SelectResult
 		for _, kvReq := range kvReqs {
-			result, err := e.SelectResult(ctx, e.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
+			result, err := e.SelectResult(ctx, e.ctx, kvReq, e.scanRetTypes(), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
 			if err != nil {
 				return nil, err
 			}
@@ -302,7 +382,7 @@ func (e *TableReaderExecutor) buildResp(ctx context.Context, ranges []*ranger.Ra
 	}
 	e.kvRanges = append(e.kvRanges, kvReq.KeyRanges...)
 
-	result, err := e.SelectResult(ctx, e.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
+	result, err := e.SelectResult(ctx, e.ctx, kvReq, e.scanRetTypes(), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
 	if err != nil {
 		return nil, err
 	}
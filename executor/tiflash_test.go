@@ -42,6 +42,7 @@ import (
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
 	"github.com/tikv/client-go/v2/testutils"
+	"github.com/tikv/client-go/v2/tikvrpc"
 )
 
 type tiflashTestSuite struct {
@@ -166,6 +167,60 @@ func (s *tiflashTestSuite) TestReadUnsigedPK(c *C) {
 	tk.MustQuery("select count(*) from t1 , t where t1.a = t.a and ((t1.a < 9223372036854775800 and t1.a > 2) or (t1.a <= 1 and t1.a > -1))").Check(testkit.Rows("3"))
 }
 
+func (s *tiflashTestSuite) TestAnalyzeReadFromTiFlash(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c varchar(10), index idx(b), index idx2(c))")
+	tk.MustExec("alter table t set tiflash replica 1")
+	tb := testGetTableByName(c, tk.Se, "test", "t")
+	err := domain.GetDomain(tk.Se).DDL().UpdateTableReplicaInfo(tk.Se, tb.Meta().ID, true)
+	c.Assert(err, IsNil)
+	tk.MustExec("insert into t values(1,1,'a'),(2,2,'b'),(3,3,'c'),(4,4,null),(5,5,'a')")
+
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/store/mockstore/unistore/unistoreRPCClientSendHook", `return(true)`), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/store/mockstore/unistore/unistoreRPCClientSendHook"), IsNil)
+	}()
+	var tiflashDAGReqs, tiflashAnalyzeReqs, tikvAnalyzeReqs int32
+	unistore.UnistoreRPCClientSendHook = func(req *tikvrpc.Request) {
+		if req.Type != tikvrpc.CmdCop {
+			return
+		}
+		switch {
+		case req.StoreTp == tikvrpc.TiFlash && req.Cop().Tp == kv.ReqTypeDAG:
+			atomic.AddInt32(&tiflashDAGReqs, 1)
+		case req.StoreTp == tikvrpc.TiFlash && req.Cop().Tp == kv.ReqTypeAnalyze:
+			atomic.AddInt32(&tiflashAnalyzeReqs, 1)
+		case req.Cop().Tp == kv.ReqTypeAnalyze:
+			atomic.AddInt32(&tikvAnalyzeReqs, 1)
+		}
+	}
+	defer func() {
+		unistore.UnistoreRPCClientSendHook = nil
+	}()
+
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustQuery("select @@tidb_analyze_read_from_tiflash").Check(testkit.Rows("0"))
+	tk.MustExec("analyze table t")
+	c.Assert(atomic.LoadInt32(&tiflashDAGReqs), Equals, int32(0))
+	c.Assert(atomic.LoadInt32(&tikvAnalyzeReqs), Greater, int32(0))
+
+	atomic.StoreInt32(&tikvAnalyzeReqs, 0)
+	tk.MustExec("set @@session.tidb_analyze_read_from_tiflash = 1")
+	tk.MustExec("analyze table t")
+	// The rows are scanned from TiFlash with the DAG request and nothing is sampled by TiKV.
+	c.Assert(atomic.LoadInt32(&tiflashDAGReqs), Greater, int32(0))
+	c.Assert(atomic.LoadInt32(&tiflashAnalyzeReqs), Equals, int32(0))
+	c.Assert(atomic.LoadInt32(&tikvAnalyzeReqs), Equals, int32(0))
+	tk.MustQuery("select count from mysql.stats_meta where table_id = ?", tb.Meta().ID).Check(testkit.Rows("5"))
+	tk.MustQuery("select is_index, hist_id, distinct_count, null_count from mysql.stats_histograms where table_id = ? order by is_index, hist_id", tb.Meta().ID).Check(testkit.Rows(
+		"0 1 5 0", "0 2 5 0", "0 3 3 1", "1 1 5 0", "1 2 3 1"))
+	tk.MustQuery("select count(*) from t use index(idx2) where c = 'a'").Check(testkit.Rows("2"))
+	tk.MustQuery("explain format = 'brief' select * from t use index(idx2) where c = 'a'").CheckAt([]int{0, 1}, testkit.Rows(
+		"IndexLookUp 2.00", "├─IndexRangeScan(Build) 2.00", "└─TableRowIDScan(Probe) 2.00"))
+}
+
 // to fix https://github.com/pingcap/tidb/issues/27952
 func (s *tiflashTestSuite) TestJoinRace(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
@@ -860,6 +915,68 @@ func (s *tiflashTestSuite) TestMppApply(c *C) {
 	tk.MustQuery("select /*+ agg_to_cop(), hash_agg()*/ count(*) from x1 where b > any (select x2.a from x2 where x1.a = x2.a);").Check(testkit.Rows("2"))
 }
 
+func (s *tiflashTestSuite) TestMppSemiJoin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists y1, y2")
+	tk.MustExec("create table y1(a int not null, b int not null)")
+	tk.MustExec("alter table y1 set tiflash replica 1")
+	tb := testGetTableByName(c, tk.Se, "test", "y1")
+	err := domain.GetDomain(tk.Se).DDL().UpdateTableReplicaInfo(tk.Se, tb.Meta().ID, true)
+	c.Assert(err, IsNil)
+	tk.MustExec("insert into y1 values(1, 1), (2, 2), (3, 3), (4, 4)")
+
+	tk.MustExec("create table y2(a int not null)")
+	tk.MustExec("alter table y2 set tiflash replica 1")
+	tb = testGetTableByName(c, tk.Se, "test", "y2")
+	err = domain.GetDomain(tk.Se).DDL().UpdateTableReplicaInfo(tk.Se, tb.Meta().ID, true)
+	c.Assert(err, IsNil)
+	tk.MustExec("insert into y2 values(1), (3), (3), (5)")
+
+	tk.MustExec("drop table if exists y3")
+	tk.MustExec("create table y3(a int)")
+	tk.MustExec("alter table y3 set tiflash replica 1")
+	tb = testGetTableByName(c, tk.Se, "test", "y3")
+	err = domain.GetDomain(tk.Se).DDL().UpdateTableReplicaInfo(tk.Se, tb.Meta().ID, true)
+	c.Assert(err, IsNil)
+	tk.MustExec("insert into y3 values(1), (2), (null)")
+
+	tk.MustExec("set @@session.tidb_isolation_read_engines=\"tiflash\"")
+	tk.MustExec("set @@session.tidb_allow_mpp=ON")
+	tk.MustExec("set @@session.tidb_enforce_mpp=ON")
+	tk.MustExec("set @@session.tidb_opt_insubq_to_join_and_agg=0")
+	for _, shuffle := range []bool{false, true} {
+		if shuffle {
+			tk.MustExec("set @@session.tidb_broadcast_join_threshold_size=0")
+			tk.MustExec("set @@session.tidb_broadcast_join_threshold_count=0")
+		}
+		// semi join and anti semi join
+		sql := "select a from y1 where a in (select a from y2) order by a"
+		c.Assert(tk.HasPlan(sql, "ExchangeSender"), IsTrue)
+		tk.MustQuery(sql).Check(testkit.Rows("1", "3"))
+		tk.MustQuery("select a from y1 where a not in (select a from y2) order by a").Check(testkit.Rows("2", "4"))
+		// left outer semi join and anti left outer semi join
+		sql = "select a from y1 where a > 3 or a in (select a from y2) order by a"
+		c.Assert(tk.HasPlan(sql, "ExchangeSender"), IsTrue)
+		tk.MustQuery(sql).Check(testkit.Rows("1", "3", "4"))
+		tk.MustQuery("select a from y1 where a > 3 or a not in (select a from y2) order by a").Check(testkit.Rows("2", "4"))
+		tk.MustQuery("select a, a in (select a from y2), a not in (select a from y2) from y1 order by a").Check(
+			testkit.Rows("1 1 0", "2 0 1", "3 1 0", "4 0 1"))
+		// The nullable columns make the joins cartesian, and the results of IN may be NULL.
+		sql = "select a, a in (select a from y3), a not in (select a from y3) from y1 order by a"
+		if !shuffle {
+			c.Assert(tk.HasPlan(sql, "ExchangeSender"), IsTrue)
+		}
+		tk.MustQuery(sql).Check(testkit.Rows("1 1 0", "2 1 0", "3 <nil> <nil>", "4 <nil> <nil>"))
+		tk.MustQuery("select a, a in (select a from y2), a not in (select a from y2) from y3 order by a").Check(
+			testkit.Rows("<nil> <nil> <nil>", "1 1 0", "2 0 1"))
+		tk.MustQuery("select a from y1 where a > 3 or a in (select a from y3) order by a").Check(testkit.Rows("1", "2", "4"))
+		tk.MustQuery("select a from y1 where a > 3 or a not in (select a from y3) order by a").Check(testkit.Rows("4"))
+		tk.MustQuery("select a from y1 where a not in (select a from y3) order by a").Check(testkit.Rows())
+		tk.MustQuery("select a from y3 where a not in (select a from y2) order by a").Check(testkit.Rows("2"))
+	}
+}
+
 func (s *tiflashTestSuite) TestTiFlashVirtualColumn(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -0,0 +1,101 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/timeutil"
+	"go.uber.org/zap"
+)
+
+type sysTableTimeZone struct {
+	types       []timeutil.TransitionType
+	typeIdx     map[uint64]int
+	transitions []timeutil.Transition
+}
+
+// LoadTimeZoneTables loads the time zones from the tables mysql.time_zone*. The time zones
+// which are not complete are skipped.
+func LoadTimeZoneTables(ctx sessionctx.Context) error {
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	zones := make(map[uint64]*sysTableTimeZone)
+	rows, _, err := exec.ExecRestrictedSQL(context.TODO(), nil, "select HIGH_PRIORITY Time_zone_id, Transition_type_id, "+
+		"Offset, Is_DST, Abbreviation from mysql.time_zone_transition_type")
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		id := row.GetUint64(0)
+		zone, ok := zones[id]
+		if !ok {
+			zone = &sysTableTimeZone{typeIdx: make(map[uint64]int)}
+			zones[id] = zone
+		}
+		zone.typeIdx[row.GetUint64(1)] = len(zone.types)
+		zone.types = append(zone.types, timeutil.TransitionType{
+			Offset:       int32(row.GetInt64(2)),
+			IsDST:        row.GetUint64(3) != 0,
+			Abbreviation: row.GetString(4),
+		})
+	}
+
+	rows, _, err = exec.ExecRestrictedSQL(context.TODO(), nil, "select HIGH_PRIORITY Time_zone_id, Transition_time, "+
+		"Transition_type_id from mysql.time_zone_transition")
+	if err != nil {
+		return err
+	}
+	invalid := make(map[uint64]struct{})
+	for _, row := range rows {
+		id := row.GetUint64(0)
+		zone, ok := zones[id]
+		if !ok {
+			invalid[id] = struct{}{}
+			continue
+		}
+		idx, ok := zone.typeIdx[row.GetUint64(2)]
+		if !ok {
+			invalid[id] = struct{}{}
+			continue
+		}
+		zone.transitions = append(zone.transitions, timeutil.Transition{Time: row.GetInt64(1), TypeIdx: idx})
+	}
+
+	rows, _, err = exec.ExecRestrictedSQL(context.TODO(), nil, "select HIGH_PRIORITY Name, Time_zone_id from mysql.time_zone_name")
+	if err != nil {
+		return err
+	}
+	locs := make(map[string]*time.Location, len(rows))
+	for _, row := range rows {
+		name, id := row.GetString(0), row.GetUint64(1)
+		zone, ok := zones[id]
+		if _, isInvalid := invalid[id]; !ok || isInvalid {
+			logutil.BgLogger().Warn("skip the incomplete time zone in mysql.time_zone*", zap.String("name", name), zap.Uint64("id", id))
+			continue
+		}
+		loc, err := timeutil.NewLocationFromTransitions(name, zone.types, zone.transitions)
+		if err != nil {
+			logutil.BgLogger().Warn("skip the invalid time zone in mysql.time_zone*", zap.String("name", name), zap.Error(err))
+			continue
+		}
+		locs[name] = loc
+	}
+	timeutil.SetSysTableLocations(locs)
+	return nil
+}
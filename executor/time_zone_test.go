@@ -0,0 +1,58 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/util/timeutil"
+)
+
+func TestTimeZoneTables(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	defer timeutil.SetSysTableLocations(nil)
+	tk := testkit.NewTestKit(t, store)
+
+	convertSQL := "select convert_tz('2000-01-01 00:00:00', '+00:00', 'Test/Zone'), convert_tz('2030-06-01 00:00:00', '+00:00', 'test/zone')"
+	tk.MustQuery(convertSQL).Check(testkit.Rows("<nil> <nil>"))
+
+	// 2030-04-01 00:00:00 UTC and 2030-10-01 00:00:00 UTC.
+	tk.MustExec("insert into mysql.time_zone (Time_zone_id) values (1)")
+	tk.MustExec("insert into mysql.time_zone_name values ('Test/Zone', 1)")
+	tk.MustExec("insert into mysql.time_zone_transition_type values (1, 0, 3600, 0, 'TST'), (1, 1, 7200, 1, 'TDT')")
+	tk.MustExec("insert into mysql.time_zone_transition values (1, 1901232000, 1), (1, 1917043200, 0)")
+	tk.MustQuery(convertSQL).Check(testkit.Rows("<nil> <nil>"))
+	tk.MustExec("flush tables")
+	tk.MustQuery(convertSQL).Check(testkit.Rows("2000-01-01 01:00:00 2030-06-01 02:00:00"))
+	tk.MustQuery("select convert_tz('2030-12-01 00:00:00', 'Test/Zone', 'UTC')").Check(testkit.Rows("2030-11-30 23:00:00"))
+
+	// The time zones can be used by the sessions.
+	tk.MustExec("set @@time_zone = 'Test/Zone'")
+	tk.MustQuery("select from_unixtime(1906416000)").Check(testkit.Rows("2030-05-31 02:00:00"))
+	tk.MustExec("set @@time_zone = 'UTC'")
+
+	// The incomplete time zones are skipped.
+	tk.MustExec("insert into mysql.time_zone_name values ('Test/Incomplete', 2)")
+	tk.MustExec("insert into mysql.time_zone_transition values (2, 0, 0)")
+	tk.MustExec("flush tables")
+	tk.MustQuery("select convert_tz('2000-01-01 00:00:00', '+00:00', 'Test/Incomplete')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery(convertSQL).Check(testkit.Rows("2000-01-01 01:00:00 2030-06-01 02:00:00"))
+
+	tk.MustExec("delete from mysql.time_zone_name")
+	tk.MustExec("flush tables")
+	tk.MustQuery(convertSQL).Check(testkit.Rows("<nil> <nil>"))
+}
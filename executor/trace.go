@@ -147,10 +147,12 @@ func (e *TraceExec) nextOptimizerPlanTrace(ctx context.Context, se sessionctx.Co
 		return errors.AddStack(err)
 	}
 	stmtCtx := se.GetSessionVars().StmtCtx
-	origin := stmtCtx.EnableOptimizeTrace
+	origin, originTarget := stmtCtx.EnableOptimizeTrace, stmtCtx.OptimizeTraceTarget
 	stmtCtx.EnableOptimizeTrace = true
+	stmtCtx.OptimizeTraceTarget = e.optimizerTraceTarget
 	defer func() {
 		stmtCtx.EnableOptimizeTrace = origin
+		stmtCtx.OptimizeTraceTarget = originTarget
 	}()
 	_, _, err = core.OptimizeAstNode(ctx, se, e.stmtNode, se.GetInfoSchema().(infoschema.InfoSchema))
 	if err != nil {
@@ -82,3 +82,17 @@ func TestTracePlanStmt(t *testing.T) {
 	require.Len(t, rows[0], 1)
 	require.Regexp(t, ".*zip", rows[0][0])
 }
+
+func TestTracePlanStmtWithTarget(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table tp123(id int);")
+	for _, target := range []string{"logical", "physical"} {
+		rows := tk.MustQuery("trace plan target = '" + target + "' select * from tp123").Rows()
+		require.Len(t, rows, 1)
+		require.Regexp(t, ".*zip", rows[0][0])
+	}
+	tk.MustGetErrMsg("trace plan target = 'unknown' select * from tp123", "trace plan target should only be 'estimation', 'logical' or 'physical'")
+}
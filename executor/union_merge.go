@@ -0,0 +1,225 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"container/heap"
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// MergeSortUnionExec merges the rows of its children, which are already sorted
+// by the same keys, so the output keeps the order. Every child is pulled by its
+// own worker, and the rows are only merged on demand, so a parent Limit can stop
+// the execution early without reading all the children.
+type MergeSortUnionExec struct {
+	baseExecutor
+
+	// keyColumns is the column offsets of the sort keys.
+	keyColumns []int
+	// keyCmpFuncs is used to compare each sort key.
+	keyCmpFuncs []chunk.CompareFunc
+	keyDescs    []bool
+
+	finished      chan struct{}
+	wg            sync.WaitGroup
+	resourcePools []chan *chunk.Chunk
+	resultPools   []chan *unionWorkerResult
+
+	initialized bool
+	cursors     mergeSortUnionHeap
+}
+
+// mergeSortUnionCursor points to the next row of a child to be merged.
+type mergeSortUnionCursor struct {
+	childID int
+	chk     *chunk.Chunk
+	idx     int
+}
+
+type mergeSortUnionHeap struct {
+	*MergeSortUnionExec
+	cursors []*mergeSortUnionCursor
+}
+
+func (h *mergeSortUnionHeap) Less(i, j int) bool {
+	rowI := h.cursors[i].chk.GetRow(h.cursors[i].idx)
+	rowJ := h.cursors[j].chk.GetRow(h.cursors[j].idx)
+	for k, colIdx := range h.keyColumns {
+		cmp := h.keyCmpFuncs[k](rowI, colIdx, rowJ, colIdx)
+		if h.keyDescs[k] {
+			cmp = -cmp
+		}
+		if cmp < 0 {
+			return true
+		} else if cmp > 0 {
+			return false
+		}
+	}
+	// Keep the rows of the former children first for the same keys.
+	return h.cursors[i].childID < h.cursors[j].childID
+}
+
+func (h *mergeSortUnionHeap) Len() int {
+	return len(h.cursors)
+}
+
+func (h *mergeSortUnionHeap) Push(x interface{}) {
+	h.cursors = append(h.cursors, x.(*mergeSortUnionCursor))
+}
+
+func (h *mergeSortUnionHeap) Pop() interface{} {
+	last := h.cursors[len(h.cursors)-1]
+	h.cursors = h.cursors[:len(h.cursors)-1]
+	return last
+}
+
+func (h *mergeSortUnionHeap) Swap(i, j int) {
+	h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i]
+}
+
+// Open implements the Executor Open interface.
+func (e *MergeSortUnionExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.initialized = false
+	e.finished = make(chan struct{})
+	e.cursors = mergeSortUnionHeap{MergeSortUnionExec: e}
+	return nil
+}
+
+func (e *MergeSortUnionExec) initialize(ctx context.Context) error {
+	e.resourcePools = make([]chan *chunk.Chunk, len(e.children))
+	e.resultPools = make([]chan *unionWorkerResult, len(e.children))
+	for i := range e.children {
+		// Two chunks for every child, so the worker can fetch the next chunk
+		// while the current one is being merged.
+		e.resourcePools[i] = make(chan *chunk.Chunk, 2)
+		e.resourcePools[i] <- newFirstChunk(e.children[i])
+		e.resourcePools[i] <- newFirstChunk(e.children[i])
+		e.resultPools[i] = make(chan *unionWorkerResult, 2)
+		e.wg.Add(1)
+		go e.resultPuller(ctx, i)
+	}
+	for i := range e.children {
+		cursor := &mergeSortUnionCursor{childID: i}
+		ok, err := e.fetchNextChunk(cursor)
+		if err != nil {
+			return err
+		}
+		if ok {
+			e.cursors.cursors = append(e.cursors.cursors, cursor)
+		}
+	}
+	heap.Init(&e.cursors)
+	return nil
+}
+
+func (e *MergeSortUnionExec) resultPuller(ctx context.Context, childID int) {
+	result := &unionWorkerResult{}
+	defer func() {
+		if r := recover(); r != nil {
+			buf := make([]byte, 4096)
+			stackSize := runtime.Stack(buf, false)
+			buf = buf[:stackSize]
+			logutil.Logger(ctx).Error("resultPuller panicked", zap.String("stack", string(buf)))
+			result.err = errors.Errorf("%v", r)
+			e.resultPools[childID] <- result
+		}
+		e.wg.Done()
+	}()
+	for {
+		var chk *chunk.Chunk
+		select {
+		case <-e.finished:
+			return
+		case chk = <-e.resourcePools[childID]:
+		}
+		err := Next(ctx, e.children[childID], chk)
+		e.resultPools[childID] <- &unionWorkerResult{chk: chk, err: err}
+		if err != nil || chk.NumRows() == 0 {
+			return
+		}
+	}
+}
+
+// fetchNextChunk returns the current chunk of the cursor to its worker and
+// waits for the next one. It returns false if the child is drained.
+func (e *MergeSortUnionExec) fetchNextChunk(cursor *mergeSortUnionCursor) (bool, error) {
+	if cursor.chk != nil {
+		e.resourcePools[cursor.childID] <- cursor.chk
+		cursor.chk = nil
+	}
+	result := <-e.resultPools[cursor.childID]
+	if result.err != nil {
+		return false, errors.Trace(result.err)
+	}
+	if result.chk.NumRows() == 0 {
+		return false, nil
+	}
+	cursor.chk, cursor.idx = result.chk, 0
+	return true, nil
+}
+
+// Next implements the Executor Next interface.
+func (e *MergeSortUnionExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.GrowAndReset(e.maxChunkSize)
+	if !e.initialized {
+		e.initialized = true
+		if err := e.initialize(ctx); err != nil {
+			return err
+		}
+	}
+	for !req.IsFull() && e.cursors.Len() > 0 {
+		cursor := e.cursors.cursors[0]
+		req.AppendRow(cursor.chk.GetRow(cursor.idx))
+		cursor.idx++
+		if cursor.idx < cursor.chk.NumRows() {
+			heap.Fix(&e.cursors, 0)
+			continue
+		}
+		ok, err := e.fetchNextChunk(cursor)
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(&e.cursors, 0)
+		} else {
+			heap.Pop(&e.cursors)
+		}
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *MergeSortUnionExec) Close() error {
+	if e.finished != nil {
+		close(e.finished)
+	}
+	// The results never block the workers since every child only owns two
+	// chunks, so the workers exit soon after e.finished is closed.
+	e.wg.Wait()
+	e.resourcePools = nil
+	e.resultPools = nil
+	e.cursors.cursors = nil
+	return e.baseExecutor.Close()
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSortUnion(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3")
+	tk.MustExec("create table t1(a int, b int, key(a, b))")
+	tk.MustExec("create table t2(a int, b int, key(a, b))")
+	tk.MustExec("create table t3(a int, b int, key(a, b))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t%d values (%d, %d), (%d, %d)", i%3+1, i, i, i/2, i))
+	}
+	// Use small chunks so the children are merged across several chunks.
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	tk.MustExec("set @@tidb_init_chunk_size = 2")
+
+	checkMergeSortUnion := func(sql string, expected []string) {
+		rows := tk.MustQuery("explain format = 'brief' " + sql).Rows()
+		require.True(t, strings.Contains(fmt.Sprintf("%v", rows), "merge sort by:"), fmt.Sprintf("%v", rows))
+		tk.MustQuery(sql).Check(testkit.Rows(expected...))
+	}
+
+	type row struct{ a, b int }
+	all := make([]row, 0, 200)
+	for i := 0; i < 100; i++ {
+		all = append(all, row{i, i}, row{i / 2, i})
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].a != all[j].a {
+			return all[i].a < all[j].a
+		}
+		return all[i].b < all[j].b
+	})
+	expectedRows := func(offset, count int, desc bool) []string {
+		rows := make([]string, 0, count)
+		for i := offset; i < offset+count; i++ {
+			r := all[i]
+			if desc {
+				r = all[len(all)-1-i]
+			}
+			rows = append(rows, fmt.Sprintf("%d %d", r.a, r.b))
+		}
+		return rows
+	}
+	union := "select a, b from t1 union all select a, b from t2 union all select a, b from t3"
+	checkMergeSortUnion("select * from ("+union+") t order by a, b limit 50", expectedRows(0, 50, false))
+	checkMergeSortUnion("select * from ("+union+") t order by a desc, b desc limit 10", expectedRows(0, 10, true))
+	checkMergeSortUnion("select * from ("+union+") t order by a, b limit 30, 20", expectedRows(30, 20, false))
+	checkMergeSortUnion("select * from ("+union+") t order by a, b limit 190, 20", expectedRows(190, 10, false))
+
+	// The ordered branches can be merged directly.
+	tk.MustQuery("(select a from t1 order by a limit 5) union all (select a from t2 order by a limit 5) order by a limit 6").Check(testkit.Rows("0", "0", "0", "1", "1", "2"))
+	tk.MustQuery("select a from t1 where a < 0 union all select a from t2 where a < 0 order by a limit 1").Check(testkit.Rows())
+}
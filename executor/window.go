@@ -287,6 +287,13 @@ func (p *rowFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
 	return 0
 }
 
+func (p *rowFrameWindowProcessor) nextFrame(_ sessionctx.Context, rows []chunk.Row) (start, end uint64, err error) {
+	numRows := uint64(len(rows))
+	start, end = p.getStartOffset(numRows), p.getEndOffset(numRows)
+	p.curRowIdx++
+	return start, end, nil
+}
+
 func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
 	return rows, nil
 }
@@ -522,6 +529,19 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 	return rows, nil
 }
 
+func (p *rangeFrameWindowProcessor) nextFrame(ctx sessionctx.Context, rows []chunk.Row) (start, end uint64, err error) {
+	start, err = p.getStartOffset(ctx, rows)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err = p.getEndOffset(ctx, rows)
+	if err != nil {
+		return 0, 0, err
+	}
+	p.curRowIdx++
+	return start, end, nil
+}
+
 func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
 	return rows, nil
 }
@@ -531,3 +551,138 @@ func (p *rangeFrameWindowProcessor) resetPartialResult() {
 	p.lastStartOffset = 0
 	p.lastEndOffset = 0
 }
+
+// frameWindowProcessor is a windowProcessor which can tell the frame of each row.
+type frameWindowProcessor interface {
+	windowProcessor
+	// nextFrame returns the frame [start, end) of the current row, and moves to the next row.
+	nextFrame(ctx sessionctx.Context, rows []chunk.Row) (start, end uint64, err error)
+}
+
+// excludeFrameWindowProcessor processes the frames with the EXCLUDE clause. The excluded rows may be in the middle of
+// the frame, so the window functions are calculated from scratch for each row instead of sliding the frame.
+type excludeFrameWindowProcessor struct {
+	windowFuncs    []aggfuncs.AggFunc
+	partialResults []aggfuncs.PartialResult
+	frame          frameWindowProcessor
+	exclusion      ast.FrameExclusion
+	// The rows with the same values of the order by columns are peers. All the rows in the partition are peers if
+	// there is no order by column.
+	orderByCols []*expression.Column
+	cmpFuncs    []chunk.CompareFunc
+	curRowIdx   uint64
+	// [peerStart, peerEnd) are the peers of the current row.
+	peerStart uint64
+	peerEnd   uint64
+}
+
+func newExcludeFrameWindowProcessor(windowFuncs []aggfuncs.AggFunc, partialResults []aggfuncs.PartialResult,
+	frame frameWindowProcessor, exclusion ast.FrameExclusion, orderByCols []*expression.Column) *excludeFrameWindowProcessor {
+	p := &excludeFrameWindowProcessor{
+		windowFuncs:    windowFuncs,
+		partialResults: partialResults,
+		frame:          frame,
+		exclusion:      exclusion,
+		orderByCols:    make([]*expression.Column, 0, len(orderByCols)),
+		cmpFuncs:       make([]chunk.CompareFunc, 0, len(orderByCols)),
+	}
+	for _, col := range orderByCols {
+		if cmpFunc := chunk.GetCompareFunc(col.RetType); cmpFunc != nil {
+			p.orderByCols = append(p.orderByCols, col)
+			p.cmpFuncs = append(p.cmpFuncs, cmpFunc)
+		}
+	}
+	return p
+}
+
+func (p *excludeFrameWindowProcessor) isPeer(a, b chunk.Row) bool {
+	for i, col := range p.orderByCols {
+		if p.cmpFuncs[i](a, col.Index, b, col.Index) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *excludeFrameWindowProcessor) updatePeers(rows []chunk.Row) {
+	if p.curRowIdx < p.peerEnd {
+		return
+	}
+	numRows := uint64(len(rows))
+	p.peerStart = p.curRowIdx
+	p.peerEnd = p.curRowIdx + 1
+	for p.peerEnd < numRows && p.isPeer(rows[p.curRowIdx], rows[p.peerEnd]) {
+		p.peerEnd++
+	}
+}
+
+// excludedRows returns the excluded rows [start, end) of the current row, and whether the current row is kept.
+func (p *excludeFrameWindowProcessor) excludedRows() (start, end uint64, keepCurRow bool) {
+	switch p.exclusion {
+	case ast.ExcludeCurrentRow:
+		return p.curRowIdx, p.curRowIdx + 1, false
+	case ast.ExcludeGroup:
+		return p.peerStart, p.peerEnd, false
+	case ast.ExcludeTies:
+		return p.peerStart, p.peerEnd, true
+	}
+	return p.curRowIdx, p.curRowIdx, false
+}
+
+func (p *excludeFrameWindowProcessor) updateFrame(ctx sessionctx.Context, rows []chunk.Row, start, end uint64) error {
+	if start >= end {
+		return nil
+	}
+	for i, windowFunc := range p.windowFuncs {
+		// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of the rows.
+		if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
+			minMaxSlidingWindowAggFunc.SetWindowStart(start)
+		}
+		if _, err := windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *excludeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
+	return rows, nil
+}
+
+func (p *excludeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+	for ; remained > 0; remained-- {
+		start, end, err := p.frame.nextFrame(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		p.updatePeers(rows)
+		excludeStart, excludeEnd, keepCurRow := p.excludedRows()
+		// The frame is split into three parts by the excluded rows: [start, excludeStart), the current row if
+		// it's kept, and [excludeEnd, end).
+		err = p.updateFrame(ctx, rows, start, mathutil.MinUint64(end, excludeStart))
+		if err == nil && keepCurRow && start <= p.curRowIdx && p.curRowIdx < end {
+			err = p.updateFrame(ctx, rows, p.curRowIdx, p.curRowIdx+1)
+		}
+		if err == nil {
+			err = p.updateFrame(ctx, rows, mathutil.MaxUint64(start, excludeEnd), end)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i, windowFunc := range p.windowFuncs {
+			if err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk); err != nil {
+				return nil, err
+			}
+			windowFunc.ResetPartialResult(p.partialResults[i])
+		}
+		p.curRowIdx++
+	}
+	return rows, nil
+}
+
+func (p *excludeFrameWindowProcessor) resetPartialResult() {
+	p.frame.resetPartialResult()
+	p.curRowIdx = 0
+	p.peerStart = 0
+	p.peerEnd = 0
+}
@@ -507,3 +507,33 @@ func TestIssue29947(t *testing.T) {
 	result.Check(testkit.Rows("2", "3"))
 	tk.MustExec("commit")
 }
+
+func TestWindowFrameExclusion(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (p int, o int, v int)")
+	tk.MustExec("insert into t values (1, 1, 1), (1, 2, 2), (1, 2, 4), (1, 3, 8), (2, 1, 16)")
+
+	for _, pipelined := range []int{0, 1} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_enable_pipelined_window_function = %d", pipelined))
+		tk.MustQuery("select p, o, v, sum(v) over (partition by p order by o rows between unbounded preceding and unbounded following exclude current row) from t order by p, o, v").
+			Check(testkit.Rows("1 1 1 14", "1 2 2 13", "1 2 4 11", "1 3 8 7", "2 1 16 <nil>"))
+		tk.MustQuery("select p, o, v, sum(v) over (partition by p order by o range between unbounded preceding and unbounded following exclude group) from t order by p, o, v").
+			Check(testkit.Rows("1 1 1 14", "1 2 2 9", "1 2 4 9", "1 3 8 7", "2 1 16 <nil>"))
+		tk.MustQuery("select p, o, v, sum(v) over (partition by p order by o range between unbounded preceding and unbounded following exclude ties) from t order by p, o, v").
+			Check(testkit.Rows("1 1 1 15", "1 2 2 11", "1 2 4 13", "1 3 8 15", "2 1 16 16"))
+		tk.MustQuery("select p, o, v, sum(v) over (partition by p order by o range between current row and unbounded following exclude ties) from t order by p, o, v").
+			Check(testkit.Rows("1 1 1 15", "1 2 2 10", "1 2 4 12", "1 3 8 8", "2 1 16 16"))
+		tk.MustQuery("select p, o, v, sum(v) over (partition by p order by o range between unbounded preceding and unbounded following exclude no others) from t order by p, o, v").
+			Check(testkit.Rows("1 1 1 15", "1 2 2 15", "1 2 4 15", "1 3 8 15", "2 1 16 16"))
+		tk.MustQuery("select p, o, v, max(v) over w, min(v) over w, count(*) over w from t window w as (partition by p order by o range between unbounded preceding and unbounded following exclude group) order by p, o, v").
+			Check(testkit.Rows("1 1 1 8 2 3", "1 2 2 8 1 2", "1 2 4 8 1 2", "1 3 8 4 1 3", "2 1 16 <nil> <nil> 0"))
+		// All the rows in the partition are peers without ORDER BY.
+		tk.MustQuery("select p, v, sum(v) over (partition by p rows between unbounded preceding and unbounded following exclude group) from t order by p, v").
+			Check(testkit.Rows("1 1 <nil>", "1 2 <nil>", "1 4 <nil>", "1 8 <nil>", "2 16 <nil>"))
+	}
+	tk.MustExec("set @@tidb_enable_pipelined_window_function = default")
+}
@@ -63,72 +63,9 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 	memUsageOfTxnState := txn.Size()
 	defer memTracker.Consume(int64(txn.Size() - memUsageOfTxnState))
 	sc := sctx.GetSessionVars().StmtCtx
-	changed, handleChanged := false, false
-	// onUpdateSpecified is for "UPDATE SET ts_field = old_value", the
-	// timestamp field is explicitly set, but not changed in fact.
-	onUpdateSpecified := make(map[int]bool)
-
-	// We can iterate on public columns not writable columns,
-	// because all of them are sorted by their `Offset`, which
-	// causes all writable columns are after public columns.
-
-	// 1. Cast modified values.
-	for i, col := range t.Cols() {
-		if modified[i] {
-			// Cast changed fields with respective columns.
-			v, err := table.CastValue(sctx, newData[i], col.ToInfo(), false, false)
-			if err != nil {
-				return false, err
-			}
-			newData[i] = v
-		}
-	}
-
-	// 2. Handle the bad null error.
-	for i, col := range t.Cols() {
-		var err error
-		if err = col.HandleBadNull(&newData[i], sc); err != nil {
-			return false, err
-		}
-	}
-
-	// 3. Compare datum, then handle some flags.
-	for i, col := range t.Cols() {
-		// We should use binary collation to compare datum, otherwise the result will be incorrect.
-		cmp, err := newData[i].Compare(sc, &oldData[i], collate.GetBinaryCollator())
-		if err != nil {
-			return false, err
-		}
-		if cmp != 0 {
-			changed = true
-			modified[i] = true
-			// Rebase auto increment id if the field is changed.
-			if mysql.HasAutoIncrementFlag(col.Flag) {
-				recordID, err := getAutoRecordID(newData[i], &col.FieldType, false)
-				if err != nil {
-					return false, err
-				}
-				if err = t.Allocators(sctx).Get(autoid.RowIDAllocType).Rebase(ctx, recordID, true); err != nil {
-					return false, err
-				}
-			}
-			if col.IsPKHandleColumn(t.Meta()) {
-				handleChanged = true
-				// Rebase auto random id if the field is changed.
-				if err := rebaseAutoRandomValue(ctx, sctx, t, &newData[i], col); err != nil {
-					return false, err
-				}
-			}
-			if col.IsCommonHandleColumn(t.Meta()) {
-				handleChanged = true
-			}
-		} else {
-			if mysql.HasOnUpdateNowFlag(col.Flag) && modified[i] {
-				// It's for "UPDATE t SET ts = ts" and ts is a timestamp.
-				onUpdateSpecified[i] = true
-			}
-			modified[i] = false
-		}
+	changed, handleChanged, err := checkRecordChanged(ctx, sctx, oldData, newData, modified, t)
+	if err != nil {
+		return false, err
 	}
 
 	sc.AddTouchedRows(1)
@@ -156,19 +93,7 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 		return false, nil
 	}
 
-	// 4. Fill values into on-update-now fields, only if they are really changed.
-	for i, col := range t.Cols() {
-		if mysql.HasOnUpdateNowFlag(col.Flag) && !modified[i] && !onUpdateSpecified[i] {
-			if v, err := expression.GetTimeValue(sctx, strings.ToUpper(ast.CurrentTimestamp), col.Tp, col.Decimal); err == nil {
-				newData[i] = v
-				modified[i] = true
-			} else {
-				return false, err
-			}
-		}
-	}
-
-	// 5. If handle changed, remove the old then add the new record, otherwise update the record.
+	// If handle changed, remove the old then add the new record, otherwise update the record.
 	if handleChanged {
 		// For `UPDATE IGNORE`/`INSERT IGNORE ON DUPLICATE KEY UPDATE`
 		// we use the staging buffer so that we don't need to precheck the existence of handle or unique keys by sending
@@ -219,6 +144,96 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 	return true, nil
 }
 
+// checkRecordChanged casts the modified values of `newData` and compares it with `oldData`.
+// If the row is really changed, the on-update-now fields of `newData` are filled as well.
+// `modified` is set to the columns which are really modified.
+func checkRecordChanged(ctx context.Context, sctx sessionctx.Context, oldData, newData []types.Datum, modified []bool, t table.Table) (changed, handleChanged bool, err error) {
+	sc := sctx.GetSessionVars().StmtCtx
+	// onUpdateSpecified is for "UPDATE SET ts_field = old_value", the
+	// timestamp field is explicitly set, but not changed in fact.
+	onUpdateSpecified := make(map[int]bool)
+
+	// We can iterate on public columns not writable columns,
+	// because all of them are sorted by their `Offset`, which
+	// causes all writable columns are after public columns.
+
+	// 1. Cast modified values.
+	for i, col := range t.Cols() {
+		if modified[i] {
+			// Cast changed fields with respective columns.
+			v, err := table.CastValue(sctx, newData[i], col.ToInfo(), false, false)
+			if err != nil {
+				return false, false, err
+			}
+			newData[i] = v
+		}
+	}
+
+	// 2. Handle the bad null error.
+	for i, col := range t.Cols() {
+		var err error
+		if err = col.HandleBadNull(&newData[i], sc); err != nil {
+			return false, false, err
+		}
+	}
+
+	// 3. Compare datum, then handle some flags.
+	for i, col := range t.Cols() {
+		// We should use binary collation to compare datum, otherwise the result will be incorrect.
+		cmp, err := newData[i].Compare(sc, &oldData[i], collate.GetBinaryCollator())
+		if err != nil {
+			return false, false, err
+		}
+		if cmp != 0 {
+			changed = true
+			modified[i] = true
+			// Rebase auto increment id if the field is changed.
+			if mysql.HasAutoIncrementFlag(col.Flag) {
+				recordID, err := getAutoRecordID(newData[i], &col.FieldType, false)
+				if err != nil {
+					return false, false, err
+				}
+				if err = t.Allocators(sctx).Get(autoid.RowIDAllocType).Rebase(ctx, recordID, true); err != nil {
+					return false, false, err
+				}
+			}
+			if col.IsPKHandleColumn(t.Meta()) {
+				handleChanged = true
+				// Rebase auto random id if the field is changed.
+				if err := rebaseAutoRandomValue(ctx, sctx, t, &newData[i], col); err != nil {
+					return false, false, err
+				}
+			}
+			if col.IsCommonHandleColumn(t.Meta()) {
+				handleChanged = true
+			}
+		} else {
+			if mysql.HasOnUpdateNowFlag(col.Flag) && modified[i] {
+				// It's for "UPDATE t SET ts = ts" and ts is a timestamp.
+				onUpdateSpecified[i] = true
+			}
+			modified[i] = false
+		}
+	}
+
+	if !changed {
+		return false, false, nil
+	}
+
+	// 4. Fill values into on-update-now fields, only if they are really changed.
+	for i, col := range t.Cols() {
+		if mysql.HasOnUpdateNowFlag(col.Flag) && !modified[i] && !onUpdateSpecified[i] {
+			if v, err := expression.GetTimeValue(sctx, strings.ToUpper(ast.CurrentTimestamp), col.Tp, col.Decimal); err == nil {
+				newData[i] = v
+				modified[i] = true
+			} else {
+				return false, false, err
+			}
+		}
+	}
+	return true, handleChanged, nil
+}
+
 func rebaseAutoRandomValue(ctx context.Context, sctx sessionctx.Context, t table.Table, newData *types.Datum, col *table.Column) error {
 	tableInfo := t.Meta()
 	if !tableInfo.ContainsAutoRandomBits() {
@@ -18,10 +18,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/model"
@@ -38,6 +41,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/mock"
+	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -761,6 +765,45 @@ commit;`
 	tk.MustQuery(`SELECT * FROM t1 order by f1;`).Check(testkit.Rows("1 0", "2 2"))
 }
 
+func TestInsertIgnorePresenceFilter(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	var cfg kv.InjectionConfig
+	tk := testkit.NewTestKit(t, kv.NewInjectedStore(store, &cfg))
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, c1 int unique key)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tk.MustExec("set @@tidb_insert_ignore_presence_filter = 1")
+
+	tk.MustExec("begin optimistic")
+	tk.MustExec("insert ignore into t values (1, 10), (3, 3)")
+	require.Equal(t, "Records: 2  Duplicates: 1  Warnings: 1", tk.Session().LastMessage())
+	// The keys known to exist aren't looked up again.
+	cfg.SetGetError(errors.New("injected get error"))
+	tk.MustExec("insert ignore into t values (1, 11), (3, 12), (13, 3)")
+	require.Equal(t, "Records: 3  Duplicates: 3  Warnings: 3", tk.Session().LastMessage())
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1062 Duplicate entry '1' for key 'PRIMARY'",
+		"Warning 1062 Duplicate entry '3' for key 'PRIMARY'",
+		"Warning 1062 Duplicate entry '3' for key 'c1'"))
+	cfg.SetGetError(nil)
+	// The deleted keys are looked up again.
+	tk.MustExec("delete from t where id = 3")
+	tk.MustExec("insert ignore into t values (3, 30), (4, 2)")
+	require.Equal(t, "Records: 2  Duplicates: 1  Warnings: 1", tk.Session().LastMessage())
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "2 2", "3 30"))
+
+	// The filter isn't used in the pessimistic transactions.
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("insert ignore into t values (5, 5)")
+	cfg.SetGetError(errors.New("injected get error"))
+	require.Error(t, tk.ExecToErr("insert ignore into t values (5, 5)"))
+	cfg.SetGetError(nil)
+	tk.MustExec("rollback")
+}
+
 func TestInsertIgnoreOnDup(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -2285,6 +2328,77 @@ func TestLoadDataIntoPartitionedTable(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLoadDataFromExternalStorage(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table load_data_ext (id int primary key, value varchar(20))")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data.csv" {
+			http.NotFound(w, r)
+			return
+		}
+		// The last line has no terminator.
+		_, _ = w.Write([]byte("1,a\n2,b\n3,c\n4,d\n5,e"))
+	}))
+	defer srv.Close()
+
+	// The test server listens on the loopback address, which is internal.
+	_, err := tk.Exec(fmt.Sprintf("load data infile '%s/data.csv' into table load_data_ext fields terminated by ','", srv.URL))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Load Data: can't fetch the infile from the internal address 127.0.0.1")
+	// So are the endpoints of the cloud storages.
+	for _, uri := range []string{
+		"s3://bucket/data.csv?access-key=ak&secret-access-key=sk&endpoint=http://127.0.0.1:9000",
+		"s3://bucket/data.csv?access-key=ak&secret-access-key=sk&endpoint=http://169.254.169.254",
+		"gcs://bucket/data.csv?endpoint=http://localhost:4443",
+		"azure://bucket/data.csv?account-name=test&account-key=a2V5&endpoint=http://[::1]:10000",
+	} {
+		_, err = tk.Exec(fmt.Sprintf("load data infile '%s' into table load_data_ext", uri))
+		require.Error(t, err, uri)
+		require.Contains(t, err.Error(), "Load Data: can't fetch the infile from the internal address", uri)
+	}
+	// The credentials of the server can't be used to read the cloud storages.
+	for uri, msg := range map[string]string{
+		"s3://bucket/data.csv":                                 "Load Data: access-key and secret-access-key must be given in the URI of S3",
+		"azure://bucket/data.csv?account-name=test":            "Load Data: account-name and account-key must be given in the URI of Azure Blob Storage",
+		"gcs://bucket/data.csv?credentials-file=/etc/gcs.json": "Load Data: credentials-file isn't allowed for the infile",
+	} {
+		_, err = tk.Exec(fmt.Sprintf("load data infile '%s' into table load_data_ext", uri))
+		require.EqualError(t, err, msg, uri)
+	}
+	*executor.LoadDataAllowInternalAddressForTesting = true
+	defer func() {
+		*executor.LoadDataAllowInternalAddressForTesting = false
+	}()
+
+	tk.MustExec("set @@tidb_dml_batch_size = 2")
+	tk.MustExec(fmt.Sprintf("load data infile '%s/data.csv' into table load_data_ext fields terminated by ','", srv.URL))
+	require.Equal(t, "Records: 5  Deleted: 0  Skipped: 0  Warnings: 0", tk.Session().LastMessage())
+	tk.MustQuery("select * from load_data_ext").Check(testkit.Rows("1 a", "2 b", "3 c", "4 d", "5 e"))
+	ctx := tk.Session().(sessionctx.Context)
+	require.Nil(t, ctx.Value(executor.LoadDataVarKey))
+
+	tk.MustExec("update load_data_ext set value = 'x' where id <= 2")
+	tk.MustExec(fmt.Sprintf("load data infile '%s/data.csv' replace into table load_data_ext fields terminated by ','", srv.URL))
+	require.Equal(t, "Records: 5  Deleted: 2  Skipped: 0  Warnings: 0", tk.Session().LastMessage())
+	tk.MustQuery("select * from load_data_ext").Check(testkit.Rows("1 a", "2 b", "3 c", "4 d", "5 e"))
+
+	_, err = tk.Exec(fmt.Sprintf("load data infile '%s/nonexistence.csv' into table load_data_ext", srv.URL))
+	require.EqualError(t, err, "Load Data: failed to fetch the infile, status: 404 Not Found")
+	_, err = tk.Exec("load data infile 's3://bucket/' into table load_data_ext")
+	require.EqualError(t, err, "Load Data: infile path is a directory")
+	_, err = tk.Exec("load data infile '/tmp/nonexistence.csv' into table load_data_ext")
+	require.EqualError(t, err, "Load Data: don't support load data without local field")
+
+	// The external storage can't be read by the server when SEM is enabled.
+	sem.Enable()
+	defer sem.Disable()
+	tk.MustGetErrCode(fmt.Sprintf("load data infile '%s/data.csv' into table load_data_ext", srv.URL), errno.ErrNotSupportedWithSem)
+}
+
 func TestNullDefault(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -197,6 +197,7 @@ func CheckAggPushDown(aggFunc *AggFuncDesc, storeType kv.StoreType) bool {
 	if len(aggFunc.OrderByItems) > 0 && aggFunc.Name != ast.AggFuncGroupConcat {
 		return false
 	}
+	// There's no expression type of approx_percentile in tipb, so no store can evaluate it.
 	if aggFunc.Name == ast.AggFuncApproxPercentile {
 		return false
 	}
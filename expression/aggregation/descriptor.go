@@ -161,6 +161,11 @@ func (a *AggFuncDesc) Split(ordinal []int) (partialAggDesc, finalAggDesc *AggFun
 		if finalAggDesc.Name == ast.AggFuncGroupConcat || finalAggDesc.Name == ast.AggFuncApproxPercentile {
 			finalAggDesc.Args = append(finalAggDesc.Args, a.Args[len(a.Args)-1]) // separator
 		}
+		if finalAggDesc.Name == ast.AggFuncGroupConcat {
+			// The final phase merges the sorted partial results, the order by items are kept
+			// to build the same kind of group_concat, they are not evaluated in the final phase.
+			finalAggDesc.OrderByItems = a.OrderByItems
+		}
 	}
 	return
 }
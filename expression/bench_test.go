@@ -1106,6 +1106,9 @@ func genVecExprBenchCase(ctx sessionctx.Context, funcName string, testCase vecEx
 		}
 	}
 
+	if _, ok := pgCompatFuncs[funcName]; ok {
+		ctx.GetSessionVars().EnablePGCompatFunctions = true
+	}
 	expr, err := NewFunction(ctx, funcName, eType2FieldType(testCase.retEvalType), cols...)
 	if err != nil {
 		panic(err)
@@ -722,6 +722,7 @@ var funcs = map[string]functionClass{
 	ast.Substring:       &substringFunctionClass{baseFunctionClass{ast.Substring, 2, 3}},
 	ast.Substr:          &substringFunctionClass{baseFunctionClass{ast.Substr, 2, 3}},
 	ast.SubstringIndex:  &substringIndexFunctionClass{baseFunctionClass{ast.SubstringIndex, 3, 3}},
+	ast.SplitPart:       &splitPartFunctionClass{baseFunctionClass{ast.SplitPart, 3, 3}},
 	ast.ToBase64:        &toBase64FunctionClass{baseFunctionClass{ast.ToBase64, 1, 1}},
 	ast.Trim:            &trimFunctionClass{baseFunctionClass{ast.Trim, 1, 3}},
 	ast.Translate:       &translateFunctionClass{baseFunctionClass{ast.Translate, 3, 3}},
@@ -754,6 +755,9 @@ var funcs = map[string]functionClass{
 	ast.FormatBytes:    &formatBytesFunctionClass{baseFunctionClass{ast.FormatBytes, 1, 1}},
 	ast.FormatNanoTime: &formatNanoTimeFunctionClass{baseFunctionClass{ast.FormatNanoTime, 1, 1}},
 
+	// See https://dev.mysql.com/doc/refman/8.0/en/query-attributes.html
+	ast.QueryAttrString: &queryAttrStringFunctionClass{baseFunctionClass{ast.QueryAttrString, 1, 1}},
+
 	// control functions
 	ast.If:     &ifFunctionClass{baseFunctionClass{ast.If, 3, 3}},
 	ast.Ifnull: &ifNullFunctionClass{baseFunctionClass{ast.Ifnull, 2, 2}},
@@ -778,6 +782,7 @@ var funcs = map[string]functionClass{
 	ast.ReleaseAllLocks: &releaseAllLocksFunctionClass{baseFunctionClass{ast.ReleaseAllLocks, 0, 0}},
 	ast.UUID:            &uuidFunctionClass{baseFunctionClass{ast.UUID, 0, 0}},
 	ast.UUIDShort:       &uuidShortFunctionClass{baseFunctionClass{ast.UUIDShort, 0, 0}},
+	ast.UUIDv7:          &uuidV7FunctionClass{baseFunctionClass{ast.UUIDv7, 0, 0}},
 	ast.VitessHash:      &vitessHashFunctionClass{baseFunctionClass{ast.VitessHash, 1, 1}},
 	ast.UUIDToBin:       &uuidToBinFunctionClass{baseFunctionClass{ast.UUIDToBin, 1, 2}},
 	ast.BinToUUID:       &binToUUIDFunctionClass{baseFunctionClass{ast.BinToUUID, 1, 2}},
@@ -265,6 +265,23 @@ func (c *caseWhenFunctionClass) getFunction(ctx sessionctx.Context, args []Expre
 	return sig, nil
 }
 
+// caseWhenSel returns the selection vector of the input chunk to evaluate the CASE WHEN arguments on the rows,
+// which are the logical row indexes of the chunk with the selection vector origSel and n rows.
+// It returns nil if all the rows are selected, so the arguments are evaluated without the selection overhead.
+func caseWhenSel(origSel []int, rows []int, n int) []int {
+	if origSel == nil {
+		if len(rows) == n {
+			return nil
+		}
+		return rows
+	}
+	sel := make([]int, len(rows))
+	for i, row := range rows {
+		sel[i] = origSel[row]
+	}
+	return sel
+}
+
 type builtinCaseWhenIntSig struct {
 	baseBuiltinFunc
 }
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/testkit/trequire"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -58,6 +59,52 @@ func TestCaseWhen(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestCaseWhenVecEvalOnSelectedRows(t *testing.T) {
+	ctx := createContext(t)
+	sc := ctx.GetSessionVars().StmtCtx
+	tp := types.NewFieldType(mysql.TypeLonglong)
+	col := &Column{Index: 0, RetType: tp}
+	hundred := &Constant{Value: types.NewIntDatum(100), RetType: tp}
+	five := &Constant{Value: types.NewIntDatum(5), RetType: tp}
+	eq, err := newFunctionForTest(ctx, ast.EQ, col, NewZero())
+	require.NoError(t, err)
+	gt, err := newFunctionForTest(ctx, ast.GT, col, five)
+	require.NoError(t, err)
+	div, err := newFunctionForTest(ctx, ast.IntDiv, hundred, col)
+	require.NoError(t, err)
+	// The division by zero is never evaluated, because the zero rows take the first branch.
+	withElse, err := newFunctionForTest(ctx, ast.Case, eq, NewZero(), gt, col, div)
+	require.NoError(t, err)
+	withoutElse, err := newFunctionForTest(ctx, ast.Case, eq, NewZero(), gt, div)
+	require.NoError(t, err)
+
+	input := chunk.NewChunkWithCapacity([]*types.FieldType{tp}, 1024)
+	for i := 0; i < 1024; i++ {
+		if i%10 == 9 {
+			input.AppendNull(0)
+		} else {
+			input.AppendInt64(0, int64(i%8))
+		}
+	}
+	for _, sel := range [][]int{nil, generateRandomSel()} {
+		input.SetSel(sel)
+		for _, expr := range []Expression{withElse, withoutElse} {
+			result := chunk.NewColumn(tp, input.NumRows())
+			require.NoError(t, expr.VecEvalInt(ctx, input, result))
+			require.Equal(t, sel, input.Sel())
+			require.Equal(t, uint16(0), sc.WarningCount())
+			for i := 0; i < input.NumRows(); i++ {
+				val, isNull, err := expr.EvalInt(ctx, input.GetRow(i))
+				require.NoError(t, err)
+				require.Equal(t, isNull, result.IsNull(i))
+				if !isNull {
+					require.Equal(t, val, result.GetInt64(i))
+				}
+			}
+		}
+	}
+}
+
 func TestIf(t *testing.T) {
 	ctx := createContext(t)
 	stmtCtx := ctx.GetSessionVars().StmtCtx
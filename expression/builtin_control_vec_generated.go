@@ -25,111 +25,103 @@ import (
 
 // NOTE: Control expressions optionally evaluate some branches depending on conditions, but vectorization executes all
 // branches, during which the unnecessary branches may return errors or warnings. To avoid this case, when branches
-// meet errors or warnings, the vectorization falls back the scalar execution.
-
-func (b *builtinCaseWhenIntSig) fallbackEvalInt(input *chunk.Chunk, result *chunk.Column) error {
-	n := input.NumRows()
-	result.ResizeInt64(n, false)
-	x := result.Int64s()
-	for i := 0; i < n; i++ {
-		res, isNull, err := b.evalInt(input.GetRow(i))
-		if err != nil {
-			return err
-		}
-		result.SetNull(i, isNull)
-		if isNull {
-			continue
-		}
-
-		x[i] = res
-
-	}
-	return nil
-}
+// meet errors or warnings, the vectorization falls back the scalar execution. CASE WHEN is an exception, it evaluates
+// every condition and branch only on the rows that need it by the selection vector of the input chunk.
 
 func (b *builtinCaseWhenIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	args, l := b.getArgs(), len(b.getArgs())
-	whens := make([]*chunk.Column, l/2)
-	whensSlice := make([][]int64, l/2)
-	thens := make([]*chunk.Column, l/2)
-	var eLse *chunk.Column
-	thensSlice := make([][]int64, l/2)
-	var eLseSlice []int64
-	sc := b.ctx.GetSessionVars().StmtCtx
-	beforeWarns := sc.WarningCount()
-
-	for j := 0; j < l-1; j += 2 {
+	// branches[i] is the index of the branch taken by the i-th row, l/2 means the else clause and -1 means NULL.
+	// offsets[i] is the offset of the i-th row in the result of its branch.
+	branches := make([]int, n)
+	offsets := make([]int, n)
+	bufs := make([]*chunk.Column, l/2+1)
+	bufsSlice := make([][]int64, l/2+1)
+	origSel := input.Sel()
+	defer input.SetSel(origSel)
+
+	remains := make([]int, n)
+	for i := range remains {
+		remains[i] = i
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	// Like the scalar execution, a condition is only evaluated on the rows unmatched by the former conditions,
+	// and a branch is only evaluated on the rows matching its condition.
+	for j := 0; j < l-1 && len(remains) > 0; j += 2 {
 		bufWhen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufWhen)
-		err = args[j].VecEvalInt(b.ctx, input, bufWhen)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		input.SetSel(caseWhenSel(origSel, remains, n))
+		if err = args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		whenSlice := bufWhen.Int64s()
+		matched := make([]int, 0, len(remains))
+		unmatched := remains[:0]
+		for k, row := range remains {
+			if bufWhen.IsNull(k) || whenSlice[k] == 0 {
+				unmatched = append(unmatched, row)
+			} else {
+				matched = append(matched, row)
 			}
-			return b.fallbackEvalInt(input, result)
 		}
-		whens[j/2] = bufWhen
-		whensSlice[j/2] = bufWhen.Int64s()
+		remains = unmatched
+		if len(matched) == 0 {
+			continue
+		}
 
 		bufThen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufThen)
-		err = args[j+1].VecEvalInt(b.ctx, input, bufThen)
-		afterWarns = sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
-			}
-			return b.fallbackEvalInt(input, result)
-		}
-		thens[j/2] = bufThen
-		thensSlice[j/2] = bufThen.Int64s()
-	}
-	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
-	// else clause -> args[l-1]
-	// If case clause has else clause, l%2 == 1.
-	if l%2 == 1 {
-		bufElse, err := b.bufAllocator.get()
-		if err != nil {
+		input.SetSel(caseWhenSel(origSel, matched, n))
+		if err = args[j+1].VecEvalInt(b.ctx, input, bufThen); err != nil {
 			return err
 		}
-		defer b.bufAllocator.put(bufElse)
-		err = args[l-1].VecEvalInt(b.ctx, input, bufElse)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		bufs[j/2] = bufThen
+		bufsSlice[j/2] = bufThen.Int64s()
+		for k, row := range matched {
+			branches[row] = j / 2
+			offsets[row] = k
+		}
+	}
+	if len(remains) > 0 {
+		if l%2 == 1 {
+			bufElse, err := b.bufAllocator.get()
+			if err != nil {
+				return err
+			}
+			defer b.bufAllocator.put(bufElse)
+			input.SetSel(caseWhenSel(origSel, remains, n))
+			if err = args[l-1].VecEvalInt(b.ctx, input, bufElse); err != nil {
+				return err
+			}
+			bufs[l/2] = bufElse
+			bufsSlice[l/2] = bufElse.Int64s()
+			for k, row := range remains {
+				branches[row] = l / 2
+				offsets[row] = k
+			}
+		} else {
+			for _, row := range remains {
+				branches[row] = -1
 			}
-			return b.fallbackEvalInt(input, result)
 		}
-		eLse = bufElse
-		eLseSlice = bufElse.Int64s()
 	}
 	result.ResizeInt64(n, false)
 	resultSlice := result.Int64s()
-ROW:
 	for i := 0; i < n; i++ {
-		for j := 0; j < l/2; j++ {
-			if whens[j].IsNull(i) || whensSlice[j][i] == 0 {
-				continue
-			}
-			resultSlice[i] = thensSlice[j][i]
-			result.SetNull(i, thens[j].IsNull(i))
-			continue ROW
-		}
-		if eLse != nil {
-			resultSlice[i] = eLseSlice[i]
-			result.SetNull(i, eLse.IsNull(i))
-		} else {
+		branch, offset := branches[i], offsets[i]
+		if branch < 0 || bufs[branch].IsNull(offset) {
 			result.SetNull(i, true)
+			continue
 		}
+		resultSlice[i] = bufsSlice[branch][offset]
 	}
 	return nil
 }
@@ -138,109 +130,100 @@ func (b *builtinCaseWhenIntSig) vectorized() bool {
 	return true
 }
 
-func (b *builtinCaseWhenRealSig) fallbackEvalReal(input *chunk.Chunk, result *chunk.Column) error {
-	n := input.NumRows()
-	result.ResizeFloat64(n, false)
-	x := result.Float64s()
-	for i := 0; i < n; i++ {
-		res, isNull, err := b.evalReal(input.GetRow(i))
-		if err != nil {
-			return err
-		}
-		result.SetNull(i, isNull)
-		if isNull {
-			continue
-		}
-
-		x[i] = res
-
-	}
-	return nil
-}
-
 func (b *builtinCaseWhenRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	args, l := b.getArgs(), len(b.getArgs())
-	whens := make([]*chunk.Column, l/2)
-	whensSlice := make([][]int64, l/2)
-	thens := make([]*chunk.Column, l/2)
-	var eLse *chunk.Column
-	thensSlice := make([][]float64, l/2)
-	var eLseSlice []float64
-	sc := b.ctx.GetSessionVars().StmtCtx
-	beforeWarns := sc.WarningCount()
-
-	for j := 0; j < l-1; j += 2 {
+	// branches[i] is the index of the branch taken by the i-th row, l/2 means the else clause and -1 means NULL.
+	// offsets[i] is the offset of the i-th row in the result of its branch.
+	branches := make([]int, n)
+	offsets := make([]int, n)
+	bufs := make([]*chunk.Column, l/2+1)
+	bufsSlice := make([][]float64, l/2+1)
+	origSel := input.Sel()
+	defer input.SetSel(origSel)
+
+	remains := make([]int, n)
+	for i := range remains {
+		remains[i] = i
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	// Like the scalar execution, a condition is only evaluated on the rows unmatched by the former conditions,
+	// and a branch is only evaluated on the rows matching its condition.
+	for j := 0; j < l-1 && len(remains) > 0; j += 2 {
 		bufWhen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufWhen)
-		err = args[j].VecEvalInt(b.ctx, input, bufWhen)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		input.SetSel(caseWhenSel(origSel, remains, n))
+		if err = args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		whenSlice := bufWhen.Int64s()
+		matched := make([]int, 0, len(remains))
+		unmatched := remains[:0]
+		for k, row := range remains {
+			if bufWhen.IsNull(k) || whenSlice[k] == 0 {
+				unmatched = append(unmatched, row)
+			} else {
+				matched = append(matched, row)
 			}
-			return b.fallbackEvalReal(input, result)
 		}
-		whens[j/2] = bufWhen
-		whensSlice[j/2] = bufWhen.Int64s()
+		remains = unmatched
+		if len(matched) == 0 {
+			continue
+		}
 
 		bufThen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufThen)
-		err = args[j+1].VecEvalReal(b.ctx, input, bufThen)
-		afterWarns = sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
-			}
-			return b.fallbackEvalReal(input, result)
-		}
-		thens[j/2] = bufThen
-		thensSlice[j/2] = bufThen.Float64s()
-	}
-	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
-	// else clause -> args[l-1]
-	// If case clause has else clause, l%2 == 1.
-	if l%2 == 1 {
-		bufElse, err := b.bufAllocator.get()
-		if err != nil {
+		input.SetSel(caseWhenSel(origSel, matched, n))
+		if err = args[j+1].VecEvalReal(b.ctx, input, bufThen); err != nil {
 			return err
 		}
-		defer b.bufAllocator.put(bufElse)
-		err = args[l-1].VecEvalReal(b.ctx, input, bufElse)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		bufs[j/2] = bufThen
+		bufsSlice[j/2] = bufThen.Float64s()
+		for k, row := range matched {
+			branches[row] = j / 2
+			offsets[row] = k
+		}
+	}
+	if len(remains) > 0 {
+		if l%2 == 1 {
+			bufElse, err := b.bufAllocator.get()
+			if err != nil {
+				return err
+			}
+			defer b.bufAllocator.put(bufElse)
+			input.SetSel(caseWhenSel(origSel, remains, n))
+			if err = args[l-1].VecEvalReal(b.ctx, input, bufElse); err != nil {
+				return err
+			}
+			bufs[l/2] = bufElse
+			bufsSlice[l/2] = bufElse.Float64s()
+			for k, row := range remains {
+				branches[row] = l / 2
+				offsets[row] = k
+			}
+		} else {
+			for _, row := range remains {
+				branches[row] = -1
 			}
-			return b.fallbackEvalReal(input, result)
 		}
-		eLse = bufElse
-		eLseSlice = bufElse.Float64s()
 	}
 	result.ResizeFloat64(n, false)
 	resultSlice := result.Float64s()
-ROW:
 	for i := 0; i < n; i++ {
-		for j := 0; j < l/2; j++ {
-			if whens[j].IsNull(i) || whensSlice[j][i] == 0 {
-				continue
-			}
-			resultSlice[i] = thensSlice[j][i]
-			result.SetNull(i, thens[j].IsNull(i))
-			continue ROW
-		}
-		if eLse != nil {
-			resultSlice[i] = eLseSlice[i]
-			result.SetNull(i, eLse.IsNull(i))
-		} else {
+		branch, offset := branches[i], offsets[i]
+		if branch < 0 || bufs[branch].IsNull(offset) {
 			result.SetNull(i, true)
+			continue
 		}
+		resultSlice[i] = bufsSlice[branch][offset]
 	}
 	return nil
 }
@@ -249,109 +232,100 @@ func (b *builtinCaseWhenRealSig) vectorized() bool {
 	return true
 }
 
-func (b *builtinCaseWhenDecimalSig) fallbackEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
-	n := input.NumRows()
-	result.ResizeDecimal(n, false)
-	x := result.Decimals()
-	for i := 0; i < n; i++ {
-		res, isNull, err := b.evalDecimal(input.GetRow(i))
-		if err != nil {
-			return err
-		}
-		result.SetNull(i, isNull)
-		if isNull {
-			continue
-		}
-
-		x[i] = *res
-
-	}
-	return nil
-}
-
 func (b *builtinCaseWhenDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	args, l := b.getArgs(), len(b.getArgs())
-	whens := make([]*chunk.Column, l/2)
-	whensSlice := make([][]int64, l/2)
-	thens := make([]*chunk.Column, l/2)
-	var eLse *chunk.Column
-	thensSlice := make([][]types.MyDecimal, l/2)
-	var eLseSlice []types.MyDecimal
-	sc := b.ctx.GetSessionVars().StmtCtx
-	beforeWarns := sc.WarningCount()
-
-	for j := 0; j < l-1; j += 2 {
+	// branches[i] is the index of the branch taken by the i-th row, l/2 means the else clause and -1 means NULL.
+	// offsets[i] is the offset of the i-th row in the result of its branch.
+	branches := make([]int, n)
+	offsets := make([]int, n)
+	bufs := make([]*chunk.Column, l/2+1)
+	bufsSlice := make([][]types.MyDecimal, l/2+1)
+	origSel := input.Sel()
+	defer input.SetSel(origSel)
+
+	remains := make([]int, n)
+	for i := range remains {
+		remains[i] = i
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	// Like the scalar execution, a condition is only evaluated on the rows unmatched by the former conditions,
+	// and a branch is only evaluated on the rows matching its condition.
+	for j := 0; j < l-1 && len(remains) > 0; j += 2 {
 		bufWhen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufWhen)
-		err = args[j].VecEvalInt(b.ctx, input, bufWhen)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		input.SetSel(caseWhenSel(origSel, remains, n))
+		if err = args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		whenSlice := bufWhen.Int64s()
+		matched := make([]int, 0, len(remains))
+		unmatched := remains[:0]
+		for k, row := range remains {
+			if bufWhen.IsNull(k) || whenSlice[k] == 0 {
+				unmatched = append(unmatched, row)
+			} else {
+				matched = append(matched, row)
 			}
-			return b.fallbackEvalDecimal(input, result)
 		}
-		whens[j/2] = bufWhen
-		whensSlice[j/2] = bufWhen.Int64s()
+		remains = unmatched
+		if len(matched) == 0 {
+			continue
+		}
 
 		bufThen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufThen)
-		err = args[j+1].VecEvalDecimal(b.ctx, input, bufThen)
-		afterWarns = sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
-			}
-			return b.fallbackEvalDecimal(input, result)
-		}
-		thens[j/2] = bufThen
-		thensSlice[j/2] = bufThen.Decimals()
-	}
-	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
-	// else clause -> args[l-1]
-	// If case clause has else clause, l%2 == 1.
-	if l%2 == 1 {
-		bufElse, err := b.bufAllocator.get()
-		if err != nil {
+		input.SetSel(caseWhenSel(origSel, matched, n))
+		if err = args[j+1].VecEvalDecimal(b.ctx, input, bufThen); err != nil {
 			return err
 		}
-		defer b.bufAllocator.put(bufElse)
-		err = args[l-1].VecEvalDecimal(b.ctx, input, bufElse)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		bufs[j/2] = bufThen
+		bufsSlice[j/2] = bufThen.Decimals()
+		for k, row := range matched {
+			branches[row] = j / 2
+			offsets[row] = k
+		}
+	}
+	if len(remains) > 0 {
+		if l%2 == 1 {
+			bufElse, err := b.bufAllocator.get()
+			if err != nil {
+				return err
+			}
+			defer b.bufAllocator.put(bufElse)
+			input.SetSel(caseWhenSel(origSel, remains, n))
+			if err = args[l-1].VecEvalDecimal(b.ctx, input, bufElse); err != nil {
+				return err
+			}
+			bufs[l/2] = bufElse
+			bufsSlice[l/2] = bufElse.Decimals()
+			for k, row := range remains {
+				branches[row] = l / 2
+				offsets[row] = k
+			}
+		} else {
+			for _, row := range remains {
+				branches[row] = -1
 			}
-			return b.fallbackEvalDecimal(input, result)
 		}
-		eLse = bufElse
-		eLseSlice = bufElse.Decimals()
 	}
 	result.ResizeDecimal(n, false)
 	resultSlice := result.Decimals()
-ROW:
 	for i := 0; i < n; i++ {
-		for j := 0; j < l/2; j++ {
-			if whens[j].IsNull(i) || whensSlice[j][i] == 0 {
-				continue
-			}
-			resultSlice[i] = thensSlice[j][i]
-			result.SetNull(i, thens[j].IsNull(i))
-			continue ROW
-		}
-		if eLse != nil {
-			resultSlice[i] = eLseSlice[i]
-			result.SetNull(i, eLse.IsNull(i))
-		} else {
+		branch, offset := branches[i], offsets[i]
+		if branch < 0 || bufs[branch].IsNull(offset) {
 			result.SetNull(i, true)
+			continue
 		}
+		resultSlice[i] = bufsSlice[branch][offset]
 	}
 	return nil
 }
@@ -360,107 +334,96 @@ func (b *builtinCaseWhenDecimalSig) vectorized() bool {
 	return true
 }
 
-func (b *builtinCaseWhenStringSig) fallbackEvalString(input *chunk.Chunk, result *chunk.Column) error {
-	n := input.NumRows()
-	result.ReserveString(n)
-	for i := 0; i < n; i++ {
-		res, isNull, err := b.evalString(input.GetRow(i))
-		if err != nil {
-			return err
-		}
-		if isNull {
-			result.AppendNull()
-			continue
-		}
-		result.AppendString(res)
-	}
-	return nil
-}
-
 func (b *builtinCaseWhenStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	args, l := b.getArgs(), len(b.getArgs())
-	whens := make([]*chunk.Column, l/2)
-	whensSlice := make([][]int64, l/2)
-	thens := make([]*chunk.Column, l/2)
-	var eLse *chunk.Column
-	sc := b.ctx.GetSessionVars().StmtCtx
-	beforeWarns := sc.WarningCount()
-
-	for j := 0; j < l-1; j += 2 {
+	// branches[i] is the index of the branch taken by the i-th row, l/2 means the else clause and -1 means NULL.
+	// offsets[i] is the offset of the i-th row in the result of its branch.
+	branches := make([]int, n)
+	offsets := make([]int, n)
+	bufs := make([]*chunk.Column, l/2+1)
+	origSel := input.Sel()
+	defer input.SetSel(origSel)
+
+	remains := make([]int, n)
+	for i := range remains {
+		remains[i] = i
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	// Like the scalar execution, a condition is only evaluated on the rows unmatched by the former conditions,
+	// and a branch is only evaluated on the rows matching its condition.
+	for j := 0; j < l-1 && len(remains) > 0; j += 2 {
 		bufWhen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufWhen)
-		err = args[j].VecEvalInt(b.ctx, input, bufWhen)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		input.SetSel(caseWhenSel(origSel, remains, n))
+		if err = args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		whenSlice := bufWhen.Int64s()
+		matched := make([]int, 0, len(remains))
+		unmatched := remains[:0]
+		for k, row := range remains {
+			if bufWhen.IsNull(k) || whenSlice[k] == 0 {
+				unmatched = append(unmatched, row)
+			} else {
+				matched = append(matched, row)
 			}
-			return b.fallbackEvalString(input, result)
 		}
-		whens[j/2] = bufWhen
-		whensSlice[j/2] = bufWhen.Int64s()
+		remains = unmatched
+		if len(matched) == 0 {
+			continue
+		}
 
 		bufThen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufThen)
-		err = args[j+1].VecEvalString(b.ctx, input, bufThen)
-		afterWarns = sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
-			}
-			return b.fallbackEvalString(input, result)
-		}
-		thens[j/2] = bufThen
-	}
-	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
-	// else clause -> args[l-1]
-	// If case clause has else clause, l%2 == 1.
-	if l%2 == 1 {
-		bufElse, err := b.bufAllocator.get()
-		if err != nil {
+		input.SetSel(caseWhenSel(origSel, matched, n))
+		if err = args[j+1].VecEvalString(b.ctx, input, bufThen); err != nil {
 			return err
 		}
-		defer b.bufAllocator.put(bufElse)
-		err = args[l-1].VecEvalString(b.ctx, input, bufElse)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
-			}
-			return b.fallbackEvalString(input, result)
+		bufs[j/2] = bufThen
+		for k, row := range matched {
+			branches[row] = j / 2
+			offsets[row] = k
 		}
-		eLse = bufElse
 	}
-	result.ReserveString(n)
-ROW:
-	for i := 0; i < n; i++ {
-		for j := 0; j < l/2; j++ {
-			if whens[j].IsNull(i) || whensSlice[j][i] == 0 {
-				continue
+	if len(remains) > 0 {
+		if l%2 == 1 {
+			bufElse, err := b.bufAllocator.get()
+			if err != nil {
+				return err
 			}
-			if thens[j].IsNull(i) {
-				result.AppendNull()
-			} else {
-				result.AppendString(thens[j].GetString(i))
+			defer b.bufAllocator.put(bufElse)
+			input.SetSel(caseWhenSel(origSel, remains, n))
+			if err = args[l-1].VecEvalString(b.ctx, input, bufElse); err != nil {
+				return err
 			}
-			continue ROW
-		}
-		if eLse != nil {
-			if eLse.IsNull(i) {
-				result.AppendNull()
-			} else {
-				result.AppendString(eLse.GetString(i))
+			bufs[l/2] = bufElse
+			for k, row := range remains {
+				branches[row] = l / 2
+				offsets[row] = k
 			}
 		} else {
+			for _, row := range remains {
+				branches[row] = -1
+			}
+		}
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		branch, offset := branches[i], offsets[i]
+		if branch < 0 || bufs[branch].IsNull(offset) {
 			result.AppendNull()
+			continue
 		}
+		result.AppendString(bufs[branch].GetString(offset))
 	}
 	return nil
 }
@@ -469,109 +432,100 @@ func (b *builtinCaseWhenStringSig) vectorized() bool {
 	return true
 }
 
-func (b *builtinCaseWhenTimeSig) fallbackEvalTime(input *chunk.Chunk, result *chunk.Column) error {
-	n := input.NumRows()
-	result.ResizeTime(n, false)
-	x := result.Times()
-	for i := 0; i < n; i++ {
-		res, isNull, err := b.evalTime(input.GetRow(i))
-		if err != nil {
-			return err
-		}
-		result.SetNull(i, isNull)
-		if isNull {
-			continue
-		}
-
-		x[i] = res
-
-	}
-	return nil
-}
-
 func (b *builtinCaseWhenTimeSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	args, l := b.getArgs(), len(b.getArgs())
-	whens := make([]*chunk.Column, l/2)
-	whensSlice := make([][]int64, l/2)
-	thens := make([]*chunk.Column, l/2)
-	var eLse *chunk.Column
-	thensSlice := make([][]types.Time, l/2)
-	var eLseSlice []types.Time
-	sc := b.ctx.GetSessionVars().StmtCtx
-	beforeWarns := sc.WarningCount()
-
-	for j := 0; j < l-1; j += 2 {
+	// branches[i] is the index of the branch taken by the i-th row, l/2 means the else clause and -1 means NULL.
+	// offsets[i] is the offset of the i-th row in the result of its branch.
+	branches := make([]int, n)
+	offsets := make([]int, n)
+	bufs := make([]*chunk.Column, l/2+1)
+	bufsSlice := make([][]types.Time, l/2+1)
+	origSel := input.Sel()
+	defer input.SetSel(origSel)
+
+	remains := make([]int, n)
+	for i := range remains {
+		remains[i] = i
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	// Like the scalar execution, a condition is only evaluated on the rows unmatched by the former conditions,
+	// and a branch is only evaluated on the rows matching its condition.
+	for j := 0; j < l-1 && len(remains) > 0; j += 2 {
 		bufWhen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufWhen)
-		err = args[j].VecEvalInt(b.ctx, input, bufWhen)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		input.SetSel(caseWhenSel(origSel, remains, n))
+		if err = args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		whenSlice := bufWhen.Int64s()
+		matched := make([]int, 0, len(remains))
+		unmatched := remains[:0]
+		for k, row := range remains {
+			if bufWhen.IsNull(k) || whenSlice[k] == 0 {
+				unmatched = append(unmatched, row)
+			} else {
+				matched = append(matched, row)
 			}
-			return b.fallbackEvalTime(input, result)
 		}
-		whens[j/2] = bufWhen
-		whensSlice[j/2] = bufWhen.Int64s()
+		remains = unmatched
+		if len(matched) == 0 {
+			continue
+		}
 
 		bufThen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufThen)
-		err = args[j+1].VecEvalTime(b.ctx, input, bufThen)
-		afterWarns = sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
-			}
-			return b.fallbackEvalTime(input, result)
-		}
-		thens[j/2] = bufThen
-		thensSlice[j/2] = bufThen.Times()
-	}
-	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
-	// else clause -> args[l-1]
-	// If case clause has else clause, l%2 == 1.
-	if l%2 == 1 {
-		bufElse, err := b.bufAllocator.get()
-		if err != nil {
+		input.SetSel(caseWhenSel(origSel, matched, n))
+		if err = args[j+1].VecEvalTime(b.ctx, input, bufThen); err != nil {
 			return err
 		}
-		defer b.bufAllocator.put(bufElse)
-		err = args[l-1].VecEvalTime(b.ctx, input, bufElse)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		bufs[j/2] = bufThen
+		bufsSlice[j/2] = bufThen.Times()
+		for k, row := range matched {
+			branches[row] = j / 2
+			offsets[row] = k
+		}
+	}
+	if len(remains) > 0 {
+		if l%2 == 1 {
+			bufElse, err := b.bufAllocator.get()
+			if err != nil {
+				return err
+			}
+			defer b.bufAllocator.put(bufElse)
+			input.SetSel(caseWhenSel(origSel, remains, n))
+			if err = args[l-1].VecEvalTime(b.ctx, input, bufElse); err != nil {
+				return err
+			}
+			bufs[l/2] = bufElse
+			bufsSlice[l/2] = bufElse.Times()
+			for k, row := range remains {
+				branches[row] = l / 2
+				offsets[row] = k
+			}
+		} else {
+			for _, row := range remains {
+				branches[row] = -1
 			}
-			return b.fallbackEvalTime(input, result)
 		}
-		eLse = bufElse
-		eLseSlice = bufElse.Times()
 	}
 	result.ResizeTime(n, false)
 	resultSlice := result.Times()
-ROW:
 	for i := 0; i < n; i++ {
-		for j := 0; j < l/2; j++ {
-			if whens[j].IsNull(i) || whensSlice[j][i] == 0 {
-				continue
-			}
-			resultSlice[i] = thensSlice[j][i]
-			result.SetNull(i, thens[j].IsNull(i))
-			continue ROW
-		}
-		if eLse != nil {
-			resultSlice[i] = eLseSlice[i]
-			result.SetNull(i, eLse.IsNull(i))
-		} else {
+		branch, offset := branches[i], offsets[i]
+		if branch < 0 || bufs[branch].IsNull(offset) {
 			result.SetNull(i, true)
+			continue
 		}
+		resultSlice[i] = bufsSlice[branch][offset]
 	}
 	return nil
 }
@@ -580,109 +534,100 @@ func (b *builtinCaseWhenTimeSig) vectorized() bool {
 	return true
 }
 
-func (b *builtinCaseWhenDurationSig) fallbackEvalDuration(input *chunk.Chunk, result *chunk.Column) error {
-	n := input.NumRows()
-	result.ResizeGoDuration(n, false)
-	x := result.GoDurations()
-	for i := 0; i < n; i++ {
-		res, isNull, err := b.evalDuration(input.GetRow(i))
-		if err != nil {
-			return err
-		}
-		result.SetNull(i, isNull)
-		if isNull {
-			continue
-		}
-
-		x[i] = res.Duration
-
-	}
-	return nil
-}
-
 func (b *builtinCaseWhenDurationSig) vecEvalDuration(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	args, l := b.getArgs(), len(b.getArgs())
-	whens := make([]*chunk.Column, l/2)
-	whensSlice := make([][]int64, l/2)
-	thens := make([]*chunk.Column, l/2)
-	var eLse *chunk.Column
-	thensSlice := make([][]time.Duration, l/2)
-	var eLseSlice []time.Duration
-	sc := b.ctx.GetSessionVars().StmtCtx
-	beforeWarns := sc.WarningCount()
-
-	for j := 0; j < l-1; j += 2 {
+	// branches[i] is the index of the branch taken by the i-th row, l/2 means the else clause and -1 means NULL.
+	// offsets[i] is the offset of the i-th row in the result of its branch.
+	branches := make([]int, n)
+	offsets := make([]int, n)
+	bufs := make([]*chunk.Column, l/2+1)
+	bufsSlice := make([][]time.Duration, l/2+1)
+	origSel := input.Sel()
+	defer input.SetSel(origSel)
+
+	remains := make([]int, n)
+	for i := range remains {
+		remains[i] = i
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	// Like the scalar execution, a condition is only evaluated on the rows unmatched by the former conditions,
+	// and a branch is only evaluated on the rows matching its condition.
+	for j := 0; j < l-1 && len(remains) > 0; j += 2 {
 		bufWhen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufWhen)
-		err = args[j].VecEvalInt(b.ctx, input, bufWhen)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		input.SetSel(caseWhenSel(origSel, remains, n))
+		if err = args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		whenSlice := bufWhen.Int64s()
+		matched := make([]int, 0, len(remains))
+		unmatched := remains[:0]
+		for k, row := range remains {
+			if bufWhen.IsNull(k) || whenSlice[k] == 0 {
+				unmatched = append(unmatched, row)
+			} else {
+				matched = append(matched, row)
 			}
-			return b.fallbackEvalDuration(input, result)
 		}
-		whens[j/2] = bufWhen
-		whensSlice[j/2] = bufWhen.Int64s()
+		remains = unmatched
+		if len(matched) == 0 {
+			continue
+		}
 
 		bufThen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufThen)
-		err = args[j+1].VecEvalDuration(b.ctx, input, bufThen)
-		afterWarns = sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
-			}
-			return b.fallbackEvalDuration(input, result)
-		}
-		thens[j/2] = bufThen
-		thensSlice[j/2] = bufThen.GoDurations()
-	}
-	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
-	// else clause -> args[l-1]
-	// If case clause has else clause, l%2 == 1.
-	if l%2 == 1 {
-		bufElse, err := b.bufAllocator.get()
-		if err != nil {
+		input.SetSel(caseWhenSel(origSel, matched, n))
+		if err = args[j+1].VecEvalDuration(b.ctx, input, bufThen); err != nil {
 			return err
 		}
-		defer b.bufAllocator.put(bufElse)
-		err = args[l-1].VecEvalDuration(b.ctx, input, bufElse)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		bufs[j/2] = bufThen
+		bufsSlice[j/2] = bufThen.GoDurations()
+		for k, row := range matched {
+			branches[row] = j / 2
+			offsets[row] = k
+		}
+	}
+	if len(remains) > 0 {
+		if l%2 == 1 {
+			bufElse, err := b.bufAllocator.get()
+			if err != nil {
+				return err
+			}
+			defer b.bufAllocator.put(bufElse)
+			input.SetSel(caseWhenSel(origSel, remains, n))
+			if err = args[l-1].VecEvalDuration(b.ctx, input, bufElse); err != nil {
+				return err
+			}
+			bufs[l/2] = bufElse
+			bufsSlice[l/2] = bufElse.GoDurations()
+			for k, row := range remains {
+				branches[row] = l / 2
+				offsets[row] = k
+			}
+		} else {
+			for _, row := range remains {
+				branches[row] = -1
 			}
-			return b.fallbackEvalDuration(input, result)
 		}
-		eLse = bufElse
-		eLseSlice = bufElse.GoDurations()
 	}
 	result.ResizeGoDuration(n, false)
 	resultSlice := result.GoDurations()
-ROW:
 	for i := 0; i < n; i++ {
-		for j := 0; j < l/2; j++ {
-			if whens[j].IsNull(i) || whensSlice[j][i] == 0 {
-				continue
-			}
-			resultSlice[i] = thensSlice[j][i]
-			result.SetNull(i, thens[j].IsNull(i))
-			continue ROW
-		}
-		if eLse != nil {
-			resultSlice[i] = eLseSlice[i]
-			result.SetNull(i, eLse.IsNull(i))
-		} else {
+		branch, offset := branches[i], offsets[i]
+		if branch < 0 || bufs[branch].IsNull(offset) {
 			result.SetNull(i, true)
+			continue
 		}
+		resultSlice[i] = bufsSlice[branch][offset]
 	}
 	return nil
 }
@@ -691,107 +636,96 @@ func (b *builtinCaseWhenDurationSig) vectorized() bool {
 	return true
 }
 
-func (b *builtinCaseWhenJSONSig) fallbackEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
-	n := input.NumRows()
-	result.ReserveJSON(n)
-	for i := 0; i < n; i++ {
-		res, isNull, err := b.evalJSON(input.GetRow(i))
-		if err != nil {
-			return err
-		}
-		if isNull {
-			result.AppendNull()
-			continue
-		}
-		result.AppendJSON(res)
-	}
-	return nil
-}
-
 func (b *builtinCaseWhenJSONSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	args, l := b.getArgs(), len(b.getArgs())
-	whens := make([]*chunk.Column, l/2)
-	whensSlice := make([][]int64, l/2)
-	thens := make([]*chunk.Column, l/2)
-	var eLse *chunk.Column
-	sc := b.ctx.GetSessionVars().StmtCtx
-	beforeWarns := sc.WarningCount()
-
-	for j := 0; j < l-1; j += 2 {
+	// branches[i] is the index of the branch taken by the i-th row, l/2 means the else clause and -1 means NULL.
+	// offsets[i] is the offset of the i-th row in the result of its branch.
+	branches := make([]int, n)
+	offsets := make([]int, n)
+	bufs := make([]*chunk.Column, l/2+1)
+	origSel := input.Sel()
+	defer input.SetSel(origSel)
+
+	remains := make([]int, n)
+	for i := range remains {
+		remains[i] = i
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	// Like the scalar execution, a condition is only evaluated on the rows unmatched by the former conditions,
+	// and a branch is only evaluated on the rows matching its condition.
+	for j := 0; j < l-1 && len(remains) > 0; j += 2 {
 		bufWhen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufWhen)
-		err = args[j].VecEvalInt(b.ctx, input, bufWhen)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		input.SetSel(caseWhenSel(origSel, remains, n))
+		if err = args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		whenSlice := bufWhen.Int64s()
+		matched := make([]int, 0, len(remains))
+		unmatched := remains[:0]
+		for k, row := range remains {
+			if bufWhen.IsNull(k) || whenSlice[k] == 0 {
+				unmatched = append(unmatched, row)
+			} else {
+				matched = append(matched, row)
 			}
-			return b.fallbackEvalJSON(input, result)
 		}
-		whens[j/2] = bufWhen
-		whensSlice[j/2] = bufWhen.Int64s()
+		remains = unmatched
+		if len(matched) == 0 {
+			continue
+		}
 
 		bufThen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufThen)
-		err = args[j+1].VecEvalJSON(b.ctx, input, bufThen)
-		afterWarns = sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
-			}
-			return b.fallbackEvalJSON(input, result)
-		}
-		thens[j/2] = bufThen
-	}
-	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
-	// else clause -> args[l-1]
-	// If case clause has else clause, l%2 == 1.
-	if l%2 == 1 {
-		bufElse, err := b.bufAllocator.get()
-		if err != nil {
+		input.SetSel(caseWhenSel(origSel, matched, n))
+		if err = args[j+1].VecEvalJSON(b.ctx, input, bufThen); err != nil {
 			return err
 		}
-		defer b.bufAllocator.put(bufElse)
-		err = args[l-1].VecEvalJSON(b.ctx, input, bufElse)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
-			}
-			return b.fallbackEvalJSON(input, result)
+		bufs[j/2] = bufThen
+		for k, row := range matched {
+			branches[row] = j / 2
+			offsets[row] = k
 		}
-		eLse = bufElse
 	}
-	result.ReserveJSON(n)
-ROW:
-	for i := 0; i < n; i++ {
-		for j := 0; j < l/2; j++ {
-			if whens[j].IsNull(i) || whensSlice[j][i] == 0 {
-				continue
+	if len(remains) > 0 {
+		if l%2 == 1 {
+			bufElse, err := b.bufAllocator.get()
+			if err != nil {
+				return err
 			}
-			if thens[j].IsNull(i) {
-				result.AppendNull()
-			} else {
-				result.AppendJSON(thens[j].GetJSON(i))
+			defer b.bufAllocator.put(bufElse)
+			input.SetSel(caseWhenSel(origSel, remains, n))
+			if err = args[l-1].VecEvalJSON(b.ctx, input, bufElse); err != nil {
+				return err
 			}
-			continue ROW
-		}
-		if eLse != nil {
-			if eLse.IsNull(i) {
-				result.AppendNull()
-			} else {
-				result.AppendJSON(eLse.GetJSON(i))
+			bufs[l/2] = bufElse
+			for k, row := range remains {
+				branches[row] = l / 2
+				offsets[row] = k
 			}
 		} else {
+			for _, row := range remains {
+				branches[row] = -1
+			}
+		}
+	}
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		branch, offset := branches[i], offsets[i]
+		if branch < 0 || bufs[branch].IsNull(offset) {
 			result.AppendNull()
+			continue
 		}
+		result.AppendJSON(bufs[branch].GetJSON(offset))
 	}
 	return nil
 }
@@ -62,6 +62,7 @@ var (
 	_ functionClass = &setValFunctionClass{}
 	_ functionClass = &formatBytesFunctionClass{}
 	_ functionClass = &formatNanoTimeFunctionClass{}
+	_ functionClass = &queryAttrStringFunctionClass{}
 )
 
 var (
@@ -82,6 +83,7 @@ var (
 	_ builtinFunc = &builtinSetValSig{}
 	_ builtinFunc = &builtinFormatBytesSig{}
 	_ builtinFunc = &builtinFormatNanoTimeSig{}
+	_ builtinFunc = &builtinQueryAttrStringSig{}
 )
 
 type databaseFunctionClass struct {
@@ -1174,3 +1176,42 @@ func (b *builtinFormatNanoTimeSig) evalString(row chunk.Row) (string, bool, erro
 	}
 	return GetFormatNanoTime(val), false, nil
 }
+
+type queryAttrStringFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *queryAttrStringFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Charset, bf.tp.Collate = ctx.GetSessionVars().GetCharsetInfo()
+	bf.tp.Flen = mysql.MaxFieldVarCharLength
+	sig := &builtinQueryAttrStringSig{bf}
+	return sig, nil
+}
+
+type builtinQueryAttrStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinQueryAttrStringSig) Clone() builtinFunc {
+	newSig := &builtinQueryAttrStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinQueryAttrStringSig, it returns NULL if the attribute is not sent by the client.
+// See https://dev.mysql.com/doc/refman/8.0/en/query-attributes.html
+func (b *builtinQueryAttrStringSig) evalString(row chunk.Row) (string, bool, error) {
+	name, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	value, ok := b.ctx.GetSessionVars().QueryAttributes[name]
+	return value, !ok, nil
+}
@@ -355,3 +355,34 @@ func TestFormatNanoTime(t *testing.T) {
 		trequire.DatumEqual(t, tt["Ret"][0], v)
 	}
 }
+
+func TestQueryAttrString(t *testing.T) {
+	ctx := createContext(t)
+	ctx.GetSessionVars().QueryAttributes = map[string]string{"trace_id": "abc"}
+	tbl := []struct {
+		Arg interface{}
+		Ret interface{}
+	}{
+		{nil, nil},
+		{"trace_id", "abc"},
+		{"TRACE_ID", nil},
+		{"span_id", nil},
+	}
+	Dtbl := tblToDtbl(tbl)
+
+	for _, tt := range Dtbl {
+		fc := funcs[ast.QueryAttrString]
+		f, err := fc.getFunction(ctx, datumsToConstants(tt["Arg"]))
+		require.NoError(t, err)
+		v, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		trequire.DatumEqual(t, tt["Ret"][0], v)
+	}
+
+	ctx.GetSessionVars().QueryAttributes = nil
+	f, err := funcs[ast.QueryAttrString].getFunction(ctx, datumsToConstants(types.MakeDatums("trace_id")))
+	require.NoError(t, err)
+	v, err := evalBuiltinFunc(f, chunk.Row{})
+	require.NoError(t, err)
+	require.True(t, v.IsNull())
+}
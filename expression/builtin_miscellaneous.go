@@ -16,11 +16,13 @@ package expression
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/binary"
 	"fmt"
 	"math"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -54,6 +56,7 @@ var (
 	_ functionClass = &releaseAllLocksFunctionClass{}
 	_ functionClass = &uuidFunctionClass{}
 	_ functionClass = &uuidShortFunctionClass{}
+	_ functionClass = &uuidV7FunctionClass{}
 	_ functionClass = &vitessHashFunctionClass{}
 	_ functionClass = &uuidToBinFunctionClass{}
 	_ functionClass = &binToUUIDFunctionClass{}
@@ -81,6 +84,7 @@ var (
 	_ builtinFunc = &builtinIsIPv6Sig{}
 	_ builtinFunc = &builtinIsUUIDSig{}
 	_ builtinFunc = &builtinUUIDSig{}
+	_ builtinFunc = &builtinUUIDv7Sig{}
 	_ builtinFunc = &builtinVitessHashSig{}
 	_ builtinFunc = &builtinUUIDToBinSig{}
 	_ builtinFunc = &builtinBinToUUIDSig{}
@@ -1107,6 +1111,89 @@ func (c *uuidShortFunctionClass) getFunction(ctx sessionctx.Context, args []Expr
 	return nil, errFunctionNotExists.GenWithStackByArgs("FUNCTION", "UUID_SHORT")
 }
 
+type uuidV7FunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *uuidV7FunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Charset, bf.tp.Collate = ctx.GetSessionVars().GetCharsetInfo()
+	bf.tp.Flen = 36
+	sig := &builtinUUIDv7Sig{bf}
+	return sig, nil
+}
+
+type builtinUUIDv7Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUUIDv7Sig) Clone() builtinFunc {
+	newSig := &builtinUUIDv7Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinUUIDv7Sig.
+// The UUIDs generated by the same TiDB instance are strictly increasing, so they
+// can be used as the primary key without scattering the writes like UUID().
+// See https://www.rfc-editor.org/rfc/rfc9562#name-uuid-version-7
+func (b *builtinUUIDv7Sig) evalString(_ chunk.Row) (d string, isNull bool, err error) {
+	var id uuid.UUID
+	id, err = globalUUIDv7Generator.next()
+	if err != nil {
+		return
+	}
+	d = id.String()
+	return
+}
+
+// uuidV7Generator generates UUID version 7 which starts with a 48 bits unix
+// timestamp in milliseconds. The 12 bits following the version are used as a
+// counter to keep the UUIDs generated in the same millisecond increasing.
+type uuidV7Generator struct {
+	mu      sync.Mutex
+	lastMs  int64
+	counter uint16
+}
+
+var globalUUIDv7Generator = &uuidV7Generator{}
+
+func (g *uuidV7Generator) next() (uuid.UUID, error) {
+	var id uuid.UUID
+	if _, err := rand.Read(id[6:]); err != nil {
+		return id, err
+	}
+	ms := time.Now().UnixNano() / int64(time.Millisecond)
+	g.mu.Lock()
+	if ms > g.lastMs {
+		g.lastMs = ms
+		// Start from a random value in the lower half to leave room for the increments.
+		g.counter = binary.BigEndian.Uint16(id[6:8]) & 0x7ff
+	} else {
+		g.counter++
+		// Borrow the next millisecond if the counter overflows, the clock will catch up soon.
+		if g.counter > 0xfff {
+			g.lastMs++
+			g.counter = 0
+		}
+	}
+	ms, counter := g.lastMs, g.counter
+	g.mu.Unlock()
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(ms))
+	copy(id[0:6], ts[2:])
+	binary.BigEndian.PutUint16(id[6:8], 0x7000|counter)
+	id[8] = id[8]&0x3f | 0x80
+	return id, nil
+}
+
 type vitessHashFunctionClass struct {
 	baseFunctionClass
 }
@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/testkit/trequire"
@@ -156,6 +157,31 @@ func TestUUID(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestUUIDv7(t *testing.T) {
+	ctx := createContext(t)
+	f, err := newFunctionForTest(ctx, ast.UUIDv7)
+	require.NoError(t, err)
+	var last string
+	for i := 0; i < 10000; i++ {
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		id, err := uuid.Parse(d.GetString())
+		require.NoError(t, err)
+		require.Equal(t, uuid.Version(7), id.Version())
+		require.Equal(t, uuid.RFC4122, id.Variant())
+		require.Less(t, last, d.GetString())
+		last = d.GetString()
+	}
+
+	// The counter overflow borrows the next millisecond.
+	g := &uuidV7Generator{lastMs: 1<<47 - 2, counter: 0xfff}
+	id, err := g.next()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0x70, 0x00}, id[:8])
+	_, err = funcs[ast.UUIDv7].getFunction(ctx, datumsToConstants(nil))
+	require.NoError(t, err)
+}
+
 func TestAnyValue(t *testing.T) {
 	ctx := createContext(t)
 	tbl := []struct {
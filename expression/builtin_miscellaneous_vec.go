@@ -219,6 +219,25 @@ func (b *builtinUUIDSig) vecEvalString(input *chunk.Chunk, result *chunk.Column)
 	return nil
 }
 
+func (b *builtinUUIDv7Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUUIDv7Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ReserveString(n)
+	var id uuid.UUID
+	var err error
+	for i := 0; i < n; i++ {
+		id, err = globalUUIDv7Generator.next()
+		if err != nil {
+			return err
+		}
+		result.AppendString(id.String())
+	}
+	return nil
+}
+
 func (b *builtinNameConstDurationSig) vectorized() bool {
 	return true
 }
@@ -38,7 +38,8 @@ var vecBuiltinMiscellaneousCases = map[string][]vecExprBenchCase{
 			newSelectRealGener([]float64{0, 0.000001}),
 		}},
 	},
-	ast.UUID: {},
+	ast.UUID:   {},
+	ast.UUIDv7: {},
 	ast.Inet6Ntoa: {
 		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{
 			newSelectStringGener(
@@ -59,6 +59,7 @@ var (
 	_ functionClass = &convertFunctionClass{}
 	_ functionClass = &substringFunctionClass{}
 	_ functionClass = &substringIndexFunctionClass{}
+	_ functionClass = &splitPartFunctionClass{}
 	_ functionClass = &locateFunctionClass{}
 	_ functionClass = &hexFunctionClass{}
 	_ functionClass = &unhexFunctionClass{}
@@ -113,6 +114,7 @@ var (
 	_ builtinFunc = &builtinSubstring2ArgsUTF8Sig{}
 	_ builtinFunc = &builtinSubstring3ArgsUTF8Sig{}
 	_ builtinFunc = &builtinSubstringIndexSig{}
+	_ builtinFunc = &builtinSplitPartSig{}
 	_ builtinFunc = &builtinLocate2ArgsUTF8Sig{}
 	_ builtinFunc = &builtinLocate3ArgsUTF8Sig{}
 	_ builtinFunc = &builtinLocate2ArgsSig{}
@@ -1429,6 +1431,81 @@ func (b *builtinSubstringIndexSig) evalString(row chunk.Row) (d string, isNull b
 	return strings.Join(substrs, delim), false, nil
 }
 
+type splitPartFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *splitPartFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString, types.ETString, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := &builtinSplitPartSig{bf}
+	return sig, nil
+}
+
+type builtinSplitPartSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSplitPartSig) Clone() builtinFunc {
+	newSig := &builtinSplitPartSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinSplitPartSig.
+// See https://www.postgresql.org/docs/current/functions-string.html
+func (b *builtinSplitPartSig) evalString(row chunk.Row) (d string, isNull bool, err error) {
+	var (
+		str, delim string
+		n          int64
+	)
+	str, isNull, err = b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return d, isNull, err
+	}
+	delim, isNull, err = b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return d, isNull, err
+	}
+	n, isNull, err = b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return d, isNull, err
+	}
+	return splitPart(str, delim, n, mysql.HasUnsignedFlag(b.args[2].GetType().Flag))
+}
+
+// splitPart returns the n-th field of str split by delim. The fields are counted from the end if n is negative,
+// an empty string is returned if there are not enough fields.
+func splitPart(str, delim string, n int64, unsigned bool) (string, bool, error) {
+	if n == 0 {
+		return "", true, errIncorrectArgs.GenWithStackByArgs(ast.SplitPart)
+	}
+	// when n > MaxInt64, there are not enough fields.
+	if n < 0 && unsigned {
+		return "", false, nil
+	}
+	fields := []string{str}
+	if len(delim) > 0 {
+		fields = strings.Split(str, delim)
+	}
+	idx := n - 1
+	if n < 0 {
+		idx = int64(len(fields)) + n
+	}
+	if idx < 0 || idx >= int64(len(fields)) {
+		return "", false, nil
+	}
+	return fields[idx], false, nil
+}
+
 type locateFunctionClass struct {
 	baseFunctionClass
 }
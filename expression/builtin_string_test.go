@@ -970,6 +970,47 @@ func TestSubstringIndex(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSplitPart(t *testing.T) {
+	ctx := createContext(t)
+	cases := []struct {
+		args   []interface{}
+		isNil  bool
+		getErr bool
+		res    string
+	}{
+		{[]interface{}{"www.pingcap.com", ".", 1}, false, false, "www"},
+		{[]interface{}{"www.pingcap.com", ".", 2}, false, false, "pingcap"},
+		{[]interface{}{"www.pingcap.com", ".", 4}, false, false, ""},
+		{[]interface{}{"www.pingcap.com", ".", -1}, false, false, "com"},
+		{[]interface{}{"www.pingcap.com", ".", -3}, false, false, "www"},
+		{[]interface{}{"www.pingcap.com", ".", -4}, false, false, ""},
+		{[]interface{}{"www..com", ".", 2}, false, false, ""},
+		{[]interface{}{"www.pingcap.com", "pingcap", 2}, false, false, ".com"},
+		{[]interface{}{"www.pingcap.com", "", 1}, false, false, "www.pingcap.com"},
+		{[]interface{}{"www.pingcap.com", "", 2}, false, false, ""},
+		{[]interface{}{"", ".", 1}, false, false, ""},
+		{[]interface{}{"www.pingcap.com", ".", 0}, false, true, ""},
+		{[]interface{}{nil, ".", 1}, true, false, ""},
+		{[]interface{}{"www.pingcap.com", nil, 1}, true, false, ""},
+		{[]interface{}{"www.pingcap.com", ".", nil}, true, false, ""},
+	}
+	for _, c := range cases {
+		f, err := newFunctionForTest(ctx, ast.SplitPart, primitiveValsToConstants(ctx, c.args)...)
+		require.NoError(t, err)
+		d, err := f.Eval(chunk.Row{})
+		if c.getErr {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+			if c.isNil {
+				require.Equal(t, types.KindNull, d.Kind())
+			} else {
+				require.Equal(t, c.res, d.GetString())
+			}
+		}
+	}
+}
+
 func TestSpace(t *testing.T) {
 	ctx := createContext(t)
 	stmtCtx := ctx.GetSessionVars().StmtCtx
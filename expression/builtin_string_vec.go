@@ -830,6 +830,58 @@ func (b *builtinSubstringIndexSig) vecEvalString(input *chunk.Chunk, result *chu
 	return nil
 }
 
+func (b *builtinSplitPartSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a builtinSplitPartSig.
+// See https://www.postgresql.org/docs/current/functions-string.html
+func (b *builtinSplitPartSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	buf1, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	if err := b.args[1].VecEvalString(b.ctx, input, buf1); err != nil {
+		return err
+	}
+
+	buf2, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf2)
+	if err := b.args[2].VecEvalInt(b.ctx, input, buf2); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	fieldNums := buf2.Int64s()
+	unsigned := mysql.HasUnsignedFlag(b.args[2].GetType().Flag)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || buf1.IsNull(i) || buf2.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		field, _, err := splitPart(buf.GetString(i), buf1.GetString(i), fieldNums[i], unsigned)
+		if err != nil {
+			return err
+		}
+		result.AppendString(field)
+	}
+	return nil
+}
+
 func (b *builtinUnHexSig) vectorized() bool {
 	return true
 }
@@ -21,34 +21,37 @@ import (
 
 // UnCacheableFunctions stores functions which can not be cached to plan cache.
 var UnCacheableFunctions = map[string]struct{}{
-	ast.Database:     {},
-	ast.CurrentUser:  {},
-	ast.CurrentRole:  {},
-	ast.User:         {},
-	ast.ConnectionID: {},
-	ast.LastInsertId: {},
-	ast.RowCount:     {},
-	ast.Version:      {},
-	ast.Like:         {},
+	ast.Database:        {},
+	ast.CurrentUser:     {},
+	ast.CurrentRole:     {},
+	ast.User:            {},
+	ast.ConnectionID:    {},
+	ast.LastInsertId:    {},
+	ast.RowCount:        {},
+	ast.Version:         {},
+	ast.Like:            {},
+	ast.QueryAttrString: {},
 }
 
 // unFoldableFunctions stores functions which can not be folded duration constant folding stage.
 var unFoldableFunctions = map[string]struct{}{
-	ast.Sysdate:   {},
-	ast.FoundRows: {},
-	ast.Rand:      {},
-	ast.UUID:      {},
-	ast.Sleep:     {},
-	ast.RowFunc:   {},
-	ast.Values:    {},
-	ast.SetVar:    {},
-	ast.GetVar:    {},
-	ast.GetParam:  {},
-	ast.Benchmark: {},
-	ast.DayName:   {},
-	ast.NextVal:   {},
-	ast.LastVal:   {},
-	ast.SetVal:    {},
+	ast.Sysdate:         {},
+	ast.FoundRows:       {},
+	ast.Rand:            {},
+	ast.UUID:            {},
+	ast.UUIDv7:          {},
+	ast.Sleep:           {},
+	ast.RowFunc:         {},
+	ast.Values:          {},
+	ast.SetVar:          {},
+	ast.GetVar:          {},
+	ast.GetParam:        {},
+	ast.Benchmark:       {},
+	ast.DayName:         {},
+	ast.NextVal:         {},
+	ast.LastVal:         {},
+	ast.SetVal:          {},
+	ast.QueryAttrString: {},
 }
 
 // DisableFoldFunctions stores functions which prevent child scope functions from being constant folded.
@@ -80,6 +83,7 @@ var IllegalFunctions4GeneratedColumns = map[string]struct{}{
 	ast.Rand:             {},
 	ast.UUID:             {},
 	ast.UUIDShort:        {},
+	ast.UUIDv7:           {},
 	ast.Curdate:          {},
 	ast.CurrentDate:      {},
 	ast.Curtime:          {},
@@ -219,12 +223,26 @@ var mutableEffectsFunctions = map[string]struct{}{
 	ast.RandomBytes: {},
 	ast.UUID:        {},
 	ast.UUIDShort:   {},
+	ast.UUIDv7:      {},
 	ast.Sleep:       {},
 	ast.SetVar:      {},
 	ast.GetVar:      {},
 	ast.AnyValue:    {},
 }
 
+// sideEffectFunctions stores functions which change the state of the session or the cluster,
+// expressions containing them should not be evaluated concurrently or out of order.
+var sideEffectFunctions = map[string]struct{}{
+	ast.SetVar:          {},
+	ast.Sleep:           {},
+	ast.GetLock:         {},
+	ast.ReleaseLock:     {},
+	ast.ReleaseAllLocks: {},
+	ast.NextVal:         {},
+	ast.SetVal:          {},
+	ast.LastInsertId:    {},
+}
+
 // some functions like "get_lock" and "release_lock" currently do NOT have
 // right implementations, but may have noop ones(like with any inputs, always return 1)
 // if apps really need these "funcs" to run, we offer sys var(tidb_enable_noop_functions) to enable noop usage
@@ -233,6 +251,12 @@ var noopFuncs = map[string]struct{}{
 	ast.ReleaseLock: {},
 }
 
+// pgCompatFuncs are the functions compatible with PostgreSQL,
+// they are available only when tidb_enable_pg_compat_functions is ON.
+var pgCompatFuncs = map[string]struct{}{
+	ast.SplitPart: {},
+}
+
 // booleanFunctions stores boolean functions
 var booleanFunctions = map[string]struct{}{
 	ast.UnaryNot:           {},
@@ -55,166 +55,127 @@ import (
 
 // NOTE: Control expressions optionally evaluate some branches depending on conditions, but vectorization executes all
 // branches, during which the unnecessary branches may return errors or warnings. To avoid this case, when branches
-// meet errors or warnings, the vectorization falls back the scalar execution.
+// meet errors or warnings, the vectorization falls back the scalar execution. CASE WHEN is an exception, it evaluates
+// every condition and branch only on the rows that need it by the selection vector of the input chunk.
 
 `
 
 var builtinCaseWhenVec = template.Must(template.New("builtinCaseWhenVec").Parse(`
 {{ range .Sigs }}{{ with .Arg0 }}
-func (b *builtinCaseWhen{{ .TypeName }}Sig) fallbackEval{{ .TypeName }}(input *chunk.Chunk, result *chunk.Column) error {
-	n := input.NumRows()
-	{{- if .Fixed }}
-	result.Resize{{ .TypeNameInColumn }}(n, false)
-	x := result.{{ .TypeNameInColumn }}s()
-	for i := 0; i < n; i++ {
-		res, isNull, err := b.eval{{ .TypeName }}(input.GetRow(i))
-		if err != nil {
-			return err
-		}
-		result.SetNull(i, isNull)
-		if isNull {
-			continue
-		}
-		{{ if eq .TypeName "Decimal" }}
-			x[i] = *res
-		{{ else if eq .TypeName "Duration" }}
-			x[i] = res.Duration
-		{{ else }}
-			x[i] = res
-		{{ end }}
-	}
-	{{ else }}
-	result.Reserve{{ .TypeNameInColumn }}(n)
-	for i := 0; i < n; i++ {
-		res, isNull, err := b.eval{{ .TypeName }}(input.GetRow(i))
-		if err != nil {
-			return err
-		}
-		if isNull {
-			result.AppendNull()
-			continue
-		}
-		result.Append{{ .TypeNameInColumn }}(res)
-	}
-	{{ end -}}
-	return nil
-}
-
 func (b *builtinCaseWhen{{ .TypeName }}Sig) vecEval{{ .TypeName }}(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	args, l := b.getArgs(), len(b.getArgs())
-	whens := make([]*chunk.Column, l/2)
-	whensSlice := make([][]int64, l/2)
-	thens := make([]*chunk.Column, l/2)
-	var eLse *chunk.Column
+	// branches[i] is the index of the branch taken by the i-th row, l/2 means the else clause and -1 means NULL.
+	// offsets[i] is the offset of the i-th row in the result of its branch.
+	branches := make([]int, n)
+	offsets := make([]int, n)
+	bufs := make([]*chunk.Column, l/2+1)
 	{{- if .Fixed }}
-	thensSlice := make([][]{{.TypeNameGo}}, l/2)
-	var eLseSlice []{{.TypeNameGo}}
+	bufsSlice := make([][]{{.TypeNameGo}}, l/2+1)
 	{{- end }}
-	sc := b.ctx.GetSessionVars().StmtCtx
-	beforeWarns := sc.WarningCount()
+	origSel := input.Sel()
+	defer input.SetSel(origSel)
 
-	for j := 0; j < l-1; j+=2 {
+	remains := make([]int, n)
+	for i := range remains {
+		remains[i] = i
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	// Like the scalar execution, a condition is only evaluated on the rows unmatched by the former conditions,
+	// and a branch is only evaluated on the rows matching its condition.
+	for j := 0; j < l-1 && len(remains) > 0; j += 2 {
 		bufWhen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufWhen)
-		err = args[j].VecEvalInt(b.ctx, input, bufWhen)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+		input.SetSel(caseWhenSel(origSel, remains, n))
+		if err = args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		whenSlice := bufWhen.Int64s()
+		matched := make([]int, 0, len(remains))
+		unmatched := remains[:0]
+		for k, row := range remains {
+			if bufWhen.IsNull(k) || whenSlice[k] == 0 {
+				unmatched = append(unmatched, row)
+			} else {
+				matched = append(matched, row)
 			}
-			return b.fallbackEval{{ .TypeName }}(input, result)
 		}
-		whens[j/2] = bufWhen
-		whensSlice[j/2] = bufWhen.Int64s()
+		remains = unmatched
+		if len(matched) == 0 {
+			continue
+		}
 
 		bufThen, err := b.bufAllocator.get()
 		if err != nil {
 			return err
 		}
 		defer b.bufAllocator.put(bufThen)
-		err = args[j+1].VecEval{{ .TypeName }}(b.ctx, input, bufThen)
-		afterWarns = sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
-			}
-			return b.fallbackEval{{ .TypeName }}(input, result)
+		input.SetSel(caseWhenSel(origSel, matched, n))
+		if err = args[j+1].VecEval{{ .TypeName }}(b.ctx, input, bufThen); err != nil {
+			return err
 		}
-		thens[j/2] = bufThen
+		bufs[j/2] = bufThen
 		{{- if .Fixed }}
-		thensSlice[j/2] = bufThen.{{ .TypeNameInColumn }}s()
+		bufsSlice[j/2] = bufThen.{{ .TypeNameInColumn }}s()
 		{{- end }}
-	}
-	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
-	// else clause -> args[l-1]
-	// If case clause has else clause, l%2 == 1.
-	if l%2==1 {
-		bufElse, err := b.bufAllocator.get()
-		if err != nil {
-			return err
+		for k, row := range matched {
+			branches[row] = j / 2
+			offsets[row] = k
 		}
-		defer b.bufAllocator.put(bufElse)
-		err = args[l-1].VecEval{{ .TypeName }}(b.ctx, input, bufElse)
-		afterWarns := sc.WarningCount()
-		if err != nil || afterWarns > beforeWarns {
-			if afterWarns > beforeWarns {
-				sc.TruncateWarnings(int(beforeWarns))
+	}
+	if len(remains) > 0 {
+		if l%2 == 1 {
+			bufElse, err := b.bufAllocator.get()
+			if err != nil {
+				return err
+			}
+			defer b.bufAllocator.put(bufElse)
+			input.SetSel(caseWhenSel(origSel, remains, n))
+			if err = args[l-1].VecEval{{ .TypeName }}(b.ctx, input, bufElse); err != nil {
+				return err
+			}
+			bufs[l/2] = bufElse
+			{{- if .Fixed }}
+			bufsSlice[l/2] = bufElse.{{ .TypeNameInColumn }}s()
+			{{- end }}
+			for k, row := range remains {
+				branches[row] = l / 2
+				offsets[row] = k
+			}
+		} else {
+			for _, row := range remains {
+				branches[row] = -1
 			}
-			return b.fallbackEval{{ .TypeName }}(input, result)
 		}
-		eLse = bufElse
-		{{- if .Fixed }}
-		eLseSlice = bufElse.{{ .TypeNameInColumn }}s()
-		{{- end }}
 	}
 
 	{{- if .Fixed }}
 	result.Resize{{ .TypeNameInColumn }}(n, false)
 	resultSlice := result.{{ .TypeNameInColumn }}s()
+	for i := 0; i < n; i++ {
+		branch, offset := branches[i], offsets[i]
+		if branch < 0 || bufs[branch].IsNull(offset) {
+			result.SetNull(i, true)
+			continue
+		}
+		resultSlice[i] = bufsSlice[branch][offset]
+	}
 	{{- else }}
 	result.Reserve{{ .TypeNameInColumn }}(n)
-	{{- end }}
-ROW:
 	for i := 0; i < n; i++ {
-		for j := 0; j < l/2; j++ {
-			if whens[j].IsNull(i) || whensSlice[j][i] == 0 {
-				continue
-			}
-			{{- if .Fixed }}
-			resultSlice[i] = thensSlice[j][i]
-			result.SetNull(i, thens[j].IsNull(i))
-			{{- else }}
-			if thens[j].IsNull(i) {
-				result.AppendNull()
-			} else {
-				result.Append{{ .TypeNameInColumn }}(thens[j].Get{{ .TypeNameInColumn }}(i))
-			}
-			{{- end }}
-			continue ROW
-		}
-		if eLse != nil {
-			{{- if .Fixed }}
-			resultSlice[i] = eLseSlice[i]
-			result.SetNull(i, eLse.IsNull(i))
-			{{- else }}
-			if eLse.IsNull(i) {
-				result.AppendNull()
-			} else {
-				result.Append{{ .TypeNameInColumn }}(eLse.Get{{ .TypeNameInColumn }}(i))
-			}
-			{{- end }}
-		} else {
-			{{- if .Fixed }}
-			result.SetNull(i, true)
-			{{- else }}
+		branch, offset := branches[i], offsets[i]
+		if branch < 0 || bufs[branch].IsNull(offset) {
 			result.AppendNull()
-			{{- end }}
+			continue
 		}
+		result.Append{{ .TypeNameInColumn }}(bufs[branch].Get{{ .TypeNameInColumn }}(offset))
 	}
+	{{- end }}
 	return nil
 }
 
@@ -6554,6 +6554,40 @@ func TestTranslate(t *testing.T) {
 	tk.MustQuery("select translate(i, '0123456', 'abcdefg') from t").Check(testkit.Rows("a", "b", "c", "d", "e", "f", "g"))
 }
 
+func TestPGCompatFunctions(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int, name varchar(20), tags varchar(50))")
+	tk.MustExec("insert into t values (1, 'Alice', 'a,b,c'), (2, 'bob', 'x,,z'), (1, 'ALBERT', NULL)")
+
+	// The functions and operators are disabled by default.
+	tk.MustGetErrMsg("select name from t where name ilike 'al%'", "[planner:1235]This version of TiDB doesn't yet support 'ILIKE when tidb_enable_pg_compat_functions is OFF'")
+	tk.MustGetErrMsg("select split_part(tags, ',', 2) from t", "[expression:1305]FUNCTION test.split_part does not exist")
+	tk.MustGetErrMsg("select array_agg(name) from t", "[expression:1305]FUNCTION test.array_agg does not exist")
+
+	tk.MustExec("set @@tidb_enable_pg_compat_functions = 1")
+	tk.MustQuery("select name from t where name ilike 'al%' order by name").Check(testkit.Rows("ALBERT", "Alice"))
+	tk.MustQuery("select name from t where name not ilike 'AL%'").Check(testkit.Rows("bob"))
+	tk.MustQuery("select name from t where name ilike 'BO_'").Check(testkit.Rows("bob"))
+	tk.MustQuery("select 'A_B' ilike 'aX_b' escape 'X', 'AxB' ilike 'aX_b' escape 'X'").Check(testkit.Rows("1 0"))
+	tk.MustQuery("select split_part(tags, ',', 2), split_part(tags, ',', -1), split_part(tags, ',', 4) from t order by name").
+		Check(testkit.Rows("<nil> <nil> <nil>", "b c ", " z "))
+	err := tk.QueryToErr("select split_part(tags, ',', 0) from t")
+	require.EqualError(t, err, "[expression:1210]Incorrect arguments to split_part")
+	tk.MustQuery("select id, array_agg(name) from t group by id order by id").Check(testkit.Rows("1 [\"Alice\", \"ALBERT\"]", "2 [\"bob\"]"))
+	tk.MustQuery("select array_agg(tags) from t where id = 2").Check(testkit.Rows("[\"x,,z\"]"))
+	tk.MustGetErrMsg("select array_agg(id, name) from t", "[expression:1582]Incorrect parameter count in the call to native function 'array_agg'")
+
+	// ARRAY_AGG is stored as JSON_ARRAYAGG in the views.
+	tk.MustExec("create view v as select id, array_agg(name) as names from t where name ilike 'a%' group by id")
+	tk.MustQuery("show create view v").Check(testkit.Rows("v CREATE ALGORITHM=UNDEFINED DEFINER=``@`` SQL SECURITY DEFINER VIEW `v` (`id`, `names`) AS " +
+		"SELECT `id` AS `id`,JSON_ARRAYAGG(`name`) AS `names` FROM `test`.`t` WHERE `name` ILIKE _UTF8MB4'a%' GROUP BY `id` utf8mb4 utf8mb4_bin"))
+}
+
 func TestIssue26958(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -189,6 +189,9 @@ func newFunctionImpl(ctx sessionctx.Context, fold int, funcName string, retType
 		return BuildToBinaryFunction(ctx, args[0]), nil
 	}
 	fc, ok := funcs[funcName]
+	if _, isPGCompat := pgCompatFuncs[funcName]; isPGCompat && !ctx.GetSessionVars().EnablePGCompatFunctions {
+		ok = false
+	}
 	if !ok {
 		db := ctx.GetSessionVars().CurrentDB
 		if db == "" {
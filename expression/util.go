@@ -882,6 +882,26 @@ func IsMutableEffectsExpr(expr Expression) bool {
 	return false
 }
 
+// ContainSideEffectFunctions checks if any of the exprs contains function which has side effects.
+func ContainSideEffectFunctions(exprs ...Expression) bool {
+	for _, expr := range exprs {
+		switch x := expr.(type) {
+		case *ScalarFunction:
+			if _, ok := sideEffectFunctions[x.FuncName.L]; ok {
+				return true
+			}
+			if ContainSideEffectFunctions(x.GetArgs()...) {
+				return true
+			}
+		case *Constant:
+			if x.DeferredExpr != nil && ContainSideEffectFunctions(x.DeferredExpr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // RemoveDupExprs removes identical exprs. Not that if expr contains functions which
 // are mutable or have side effects, we cannot remove it even if it has duplicates;
 // if the plan is going to be cached, we cannot remove expressions containing `?` neither.
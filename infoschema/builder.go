@@ -196,9 +196,17 @@ func (b *Builder) applyTableUpdate(m *meta.Meta, diff *model.SchemaDiff) ([]int6
 		newTableID = diff.TableID
 	case model.ActionDropTable, model.ActionDropView, model.ActionDropSequence:
 		oldTableID = diff.TableID
-	case model.ActionTruncateTable, model.ActionCreateView, model.ActionExchangeTablePartition:
+	case model.ActionTruncateTable, model.ActionCreateView, model.ActionExchangeTablePartition,
+		model.ActionCreateTableAsSelect:
 		oldTableID = diff.OldTableID
 		newTableID = diff.TableID
+	case model.ActionAlterTableClusteredIndex:
+		// The table ID is changed when the changing table replaces the table.
+		oldTableID = diff.TableID
+		if diff.OldTableID != 0 {
+			oldTableID = diff.OldTableID
+		}
+		newTableID = diff.TableID
 	default:
 		oldTableID = diff.TableID
 		newTableID = diff.TableID
@@ -224,6 +232,21 @@ func (b *Builder) applyTableUpdate(m *meta.Meta, diff *model.SchemaDiff) ([]int6
 		if err := b.applyPlacementUpdate(placement.GroupID(newTableID)); err != nil {
 			return nil, errors.Trace(err)
 		}
+	case model.ActionCreateTableAsSelect:
+		if !tableIDIsValid(oldTableID) {
+			if err := b.applyPlacementUpdate(placement.GroupID(newTableID)); err != nil {
+				return nil, errors.Trace(err)
+			}
+		} else if !tableIDIsValid(newTableID) {
+			b.applyPlacementDelete(placement.GroupID(oldTableID))
+		}
+	case model.ActionAlterTableClusteredIndex:
+		if oldTableID != newTableID {
+			b.applyPlacementDelete(placement.GroupID(oldTableID))
+			if err := b.applyPlacementUpdate(placement.GroupID(newTableID)); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
 	}
 	b.copySortedTables(oldTableID, newTableID)
 
@@ -508,6 +531,12 @@ func (b *Builder) applyCreateTable(m *meta.Meta, dbInfo *model.DBInfo, tableID i
 				newAlloc := autoid.NewAllocator(b.store, dbInfo.ID, tblInfo.ID, tblInfo.IsAutoRandomBitColUnsigned(), autoid.AutoRandomType, tblVer)
 				allocs = append(allocs, newAlloc)
 			}
+		case model.ActionAlterTableClusteredIndex:
+			// The row IDs of the non-clustered changing table are allocated from the table.
+			if tblInfo.ChangingTable != nil && !tblInfo.ChangingTable.HasClusteredIndex() && allocs.Get(autoid.RowIDAllocType) == nil {
+				newAlloc := autoid.NewAllocator(b.store, dbInfo.ID, tblInfo.ID, tblInfo.IsAutoIncColUnsigned(), autoid.RowIDAllocType, tblVer)
+				allocs = append(allocs, newAlloc)
+			}
 		}
 	}
 	tbl, err := b.tableFromMeta(allocs, tblInfo)
@@ -44,6 +44,10 @@ const (
 	ClusterTableTiDBTrx = "CLUSTER_TIDB_TRX"
 	// ClusterTableDeadlocks is the string constant of cluster dead lock table.
 	ClusterTableDeadlocks = "CLUSTER_DEADLOCKS"
+	// ClusterTableTrxSummary is the string constant of cluster transaction summary table.
+	ClusterTableTrxSummary = "CLUSTER_TRX_SUMMARY"
+	// ClusterTableRegionCacheStats is the string constant of cluster region cache statistics table.
+	ClusterTableRegionCacheStats = "CLUSTER_REGION_CACHE_STATS"
 )
 
 // memTableToClusterTables means add memory table to cluster table.
@@ -55,6 +59,8 @@ var memTableToClusterTables = map[string]string{
 	TableStatementsSummaryEvicted: ClusterTableStatementsSummaryEvicted,
 	TableTiDBTrx:                  ClusterTableTiDBTrx,
 	TableDeadlocks:                ClusterTableDeadlocks,
+	TableTrxSummary:               ClusterTableTrxSummary,
+	TableRegionCacheStats:         ClusterTableRegionCacheStats,
 }
 
 func init() {
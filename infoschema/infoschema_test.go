@@ -293,6 +293,12 @@ func TestInfoTables(t *testing.T) {
 		"TIDB_TRX",
 		"DEADLOCKS",
 		"PLACEMENT_POLICIES",
+		"TIDB_COPROCESSOR_CACHE",
+		"MDL_VIEW",
+		"TRX_SUMMARY",
+		"REGION_CACHE_STATS",
+		"PLACEMENT_STATUS",
+		"SESSION_WRITE_CONFLICTS",
 	}
 	for _, tbl := range infoTables {
 		tb, err1 := is.TableByName(util.InformationSchemaName, model.NewCIStr(tbl))
@@ -47,6 +47,7 @@ import (
 	"github.com/pingcap/tidb/util/deadlockhistory"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/pdapi"
+	"github.com/pingcap/tidb/util/writeconflicthistory"
 	"github.com/tikv/client-go/v2/tikv"
 )
 
@@ -178,6 +179,22 @@ const (
 	TableAttributes = "ATTRIBUTES"
 	// TablePlacementPolicies is the string constant of placement policies table.
 	TablePlacementPolicies = "PLACEMENT_POLICIES"
+	// TableCoprocessorCache is the string constant of the coprocessor cache statistics table.
+	TableCoprocessorCache = "TIDB_COPROCESSOR_CACHE"
+	// TableMDLView is the string constant of the table which shows the schema changes blocked by metadata locks.
+	TableMDLView = "MDL_VIEW"
+	// TableTrxSummary is the string constant of the recently finished transactions summary table.
+	TableTrxSummary = "TRX_SUMMARY"
+	// TableRegionCacheStats is the string constant of the region cache statistics table.
+	TableRegionCacheStats = "REGION_CACHE_STATS"
+	// TablePlacementStatus is the string constant of the table which shows whether the regions of the tables
+	// and partitions satisfy their placement.
+	TablePlacementStatus = "PLACEMENT_STATUS"
+	// TableSessionWriteConflicts is the string constant of the table which shows the recent write conflicts met by
+	// the optimistic transactions of the current session.
+	TableSessionWriteConflicts = "SESSION_WRITE_CONFLICTS"
+	// TableUserAttributes is the string constant of the table which shows the COMMENT and ATTRIBUTE of user accounts.
+	TableUserAttributes = "USER_ATTRIBUTES"
 )
 
 const (
@@ -276,6 +293,15 @@ var tableIDMap = map[string]int64{
 	TableAttributes:                      autoid.InformationSchemaDBID + 77,
 	TableTiDBHotRegionsHistory:           autoid.InformationSchemaDBID + 78,
 	TablePlacementPolicies:               autoid.InformationSchemaDBID + 79,
+	TableCoprocessorCache:                autoid.InformationSchemaDBID + 80,
+	TableMDLView:                         autoid.InformationSchemaDBID + 81,
+	TableTrxSummary:                      autoid.InformationSchemaDBID + 82,
+	ClusterTableTrxSummary:               autoid.InformationSchemaDBID + 83,
+	TableRegionCacheStats:                autoid.InformationSchemaDBID + 84,
+	ClusterTableRegionCacheStats:         autoid.InformationSchemaDBID + 85,
+	TablePlacementStatus:                 autoid.InformationSchemaDBID + 86,
+	TableSessionWriteConflicts:           autoid.InformationSchemaDBID + 87,
+	TableUserAttributes:                  autoid.InformationSchemaDBID + 88,
 }
 
 type columnInfo struct {
@@ -388,6 +414,7 @@ var tablesCols = []columnInfo{
 	{name: "TIDB_ROW_ID_SHARDING_INFO", tp: mysql.TypeVarchar, size: 255},
 	{name: "TIDB_PK_TYPE", tp: mysql.TypeVarchar, size: 64},
 	{name: "TIDB_PLACEMENT_POLICY_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "TIDB_STATS_UPDATE_TIME", tp: mysql.TypeDatetime, size: 19},
 }
 
 // See: http://dev.mysql.com/doc/refman/5.7/en/columns-table.html
@@ -560,6 +587,7 @@ var partitionsCols = []columnInfo{
 	{name: "TABLESPACE_NAME", tp: mysql.TypeVarchar, size: 64},
 	{name: "TIDB_PARTITION_ID", tp: mysql.TypeLonglong, size: 21},
 	{name: "TIDB_PLACEMENT_POLICY_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "TIDB_STATS_UPDATE_TIME", tp: mysql.TypeDatetime},
 }
 
 var tableConstraintsCols = []columnInfo{
@@ -784,6 +812,10 @@ var tableProcesslistCols = []columnInfo{
 	{name: "MEM", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
 	{name: "DISK", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
 	{name: "TxnStart", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, deflt: ""},
+	{name: "RU", tp: mysql.TypeDouble, size: 22, comment: "Estimated request units consumed by the current statement"},
+	{name: "WAIT_STATE", tp: mysql.TypeVarchar, size: 64, comment: "What the current statement is waiting for, e.g. tso, lock or commit"},
+	{name: "BACKOFF_TYPES", tp: mysql.TypeVarchar, size: 1024, comment: "Backoff types met by the current statement with the times and the sleep time"},
+	{name: "SESSION_ALIAS", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, deflt: "", comment: "The alias of the session set by tidb_session_alias"},
 }
 
 var tableTiDBIndexesCols = []columnInfo{
@@ -871,6 +903,8 @@ var slowQueryCols = []columnInfo{
 	{name: variable.SlowLogPlanFromBinding, tp: mysql.TypeTiny, size: 1},
 	{name: variable.SlowLogPlan, tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
 	{name: variable.SlowLogPlanDigest, tp: mysql.TypeVarchar, size: 128},
+	{name: variable.SlowLogQueryAttributes, tp: mysql.TypeVarchar, size: 4096},
+	{name: variable.SlowLogSessionAlias, tp: mysql.TypeVarchar, size: 64},
 	{name: variable.SlowLogPrevStmt, tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
 	{name: variable.SlowLogQuerySQLStr, tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
 }
@@ -1089,6 +1123,11 @@ var tableTableTiFlashReplicaCols = []columnInfo{
 	{name: "LOCATION_LABELS", tp: mysql.TypeVarchar, size: 64},
 	{name: "AVAILABLE", tp: mysql.TypeTiny, size: 1},
 	{name: "PROGRESS", tp: mysql.TypeDouble, size: 22},
+	{name: "REPLICATED_REGIONS", tp: mysql.TypeLonglong, size: 21},
+	{name: "TOTAL_REGIONS", tp: mysql.TypeLonglong, size: 21},
+	{name: "TOTAL_BYTES", tp: mysql.TypeLonglong, size: 21},
+	{name: "ETA", tp: mysql.TypeDatetime, size: 19},
+	{name: "HIGH_PRIORITY", tp: mysql.TypeTiny, size: 1},
 }
 
 var tableInspectionResultCols = []columnInfo{
@@ -1178,6 +1217,8 @@ var tableSequencesCols = []columnInfo{
 	{name: "MIN_VALUE", tp: mysql.TypeLonglong, size: 21},
 	{name: "START", tp: mysql.TypeLonglong, size: 21},
 	{name: "COMMENT", tp: mysql.TypeVarchar, size: 64},
+	{name: "ALLOCATED_VALUE", tp: mysql.TypeLonglong, size: 21, comment: "The values before it are cached or used by the TiDB servers"},
+	{name: "CYCLE_ROUND", tp: mysql.TypeLonglong, size: 21, comment: "The times the sequence has cycled"},
 }
 
 var tableStatementsSummaryCols = []columnInfo{
@@ -1272,6 +1313,7 @@ var tableStatementsSummaryCols = []columnInfo{
 	{name: stmtsummary.PrevSampleTextStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The previous statement before commit"},
 	{name: stmtsummary.PlanDigestStr, tp: mysql.TypeVarchar, size: 64, comment: "Digest of its execution plan"},
 	{name: stmtsummary.PlanStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Sampled execution plan"},
+	{name: stmtsummary.QueryAttributesStr, tp: mysql.TypeVarchar, size: 4096, comment: "Query attributes of the last statement"},
 }
 
 var tableStorageStatsCols = []columnInfo{
@@ -1426,6 +1468,24 @@ var tableDeadlocksCols = []columnInfo{
 	{name: deadlockhistory.ColTrxHoldingLockStr, tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The transaction ID (start ts) of the transaction that's currently holding the lock"},
 }
 
+var tableSessionWriteConflictsCols = []columnInfo{
+	{name: writeconflicthistory.ColIDStr, tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The ID to distinguish different write conflicts of the session"},
+	{name: writeconflicthistory.ColOccurTimeStr, tp: mysql.TypeTimestamp, decimal: 6, size: 26, comment: "The physical time when the write conflict occurs"},
+	{name: writeconflicthistory.ColStartTSStr, tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The start ts of the transaction that meets the write conflict"},
+	{name: writeconflicthistory.ColConflictStartTSStr, tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The start ts of the transaction that has written the key"},
+	{name: writeconflicthistory.ColConflictCommitTSStr, tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The commit ts of the transaction that has written the key"},
+	{name: writeconflicthistory.ColKeyStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The conflicting key"},
+	{name: writeconflicthistory.ColKeyInfoStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Information of the conflicting key"},
+	{name: writeconflicthistory.ColPrimaryKeyStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The primary key of the transaction that meets the write conflict"},
+	{name: writeconflicthistory.ColAutoRetryStr, tp: mysql.TypeTiny, size: 1, flag: mysql.NotNullFlag, comment: "Whether the write conflict is met when TiDB retries the transaction automatically"},
+}
+
+var tableUserAttributesCols = []columnInfo{
+	{name: "USER", tp: mysql.TypeVarchar, size: 32, flag: mysql.NotNullFlag},
+	{name: "HOST", tp: mysql.TypeVarchar, size: 255, flag: mysql.NotNullFlag},
+	{name: "ATTRIBUTE", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+}
+
 var tableDataLockWaitsCols = []columnInfo{
 	{name: DataLockWaitsColumnKey, tp: mysql.TypeBlob, size: types.UnspecifiedLength, flag: mysql.NotNullFlag, comment: "The key that's being waiting on"},
 	{name: DataLockWaitsColumnKeyInfo, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Information of the key"},
@@ -1463,6 +1523,51 @@ var tablePlacementPoliciesCols = []columnInfo{
 	{name: "LEARNERS", tp: mysql.TypeLonglong, size: 64},
 }
 
+var tablePlacementStatusCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TABLE_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag},
+	{name: "PARTITION_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "PARTITION_ID", tp: mysql.TypeLonglong, size: 21},
+	{name: "POLICY_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, comment: "The placement policy of the table or partition, which can be joined with PLACEMENT_POLICIES"},
+	{name: "PLACEMENT", tp: mysql.TypeVarchar, size: 1024},
+	{name: "SCHEDULING_STATE", tp: mysql.TypeVarchar, size: 16, comment: "The scheduling state derived from the regions in PD"},
+	{name: "TOTAL_REGIONS", tp: mysql.TypeLonglong, size: 21, comment: "The number of the regions of the table or partition, NULL if PD is unavailable"},
+	{name: "SCHEDULED_REGIONS", tp: mysql.TypeLonglong, size: 21, comment: "The number of the regions whose peers satisfy the placement"},
+	{name: "UNSCHEDULED_REGIONS", tp: mysql.TypeLonglong, size: 21, comment: "The number of the regions whose peers don't satisfy the placement yet"},
+}
+
+var tableCoprocessorCacheCols = []columnInfo{
+	{name: "REQUESTS", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The number of the coprocessor requests which look up the cache"},
+	{name: "HITS", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The number of the requests whose cached results are valid"},
+	{name: "INVALIDATED", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The number of the requests whose cached results are outdated by the region data version"},
+	{name: "ADMITTED", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The number of the results inserted into the cache"},
+	{name: "HIT_RATIO", tp: mysql.TypeDouble, size: 22, comment: "The ratio of the hits to the requests"},
+}
+
+var tableMDLViewCols = []columnInfo{
+	{name: "JOB_ID", tp: mysql.TypeLonglong, size: 21, comment: "The DDL job which waits, NULL if it's the server waiting to report the loaded schema version"},
+	{name: "DB_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "QUERY", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+	{name: "SCHEMA_VERSION", tp: mysql.TypeLonglong, size: 21, comment: "The schema version which waits for the metadata lock"},
+	{name: "SESSION_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The session whose transaction holds the metadata lock"},
+	{name: "TXN_START", tp: mysql.TypeTimestamp, size: 26, decimal: 6},
+	{name: "HELD_SCHEMA_VERSION", tp: mysql.TypeLonglong, size: 21, comment: "The schema version of the table which the transaction uses"},
+}
+
+var tableTrxSummaryCols = []columnInfo{
+	{name: "DIGEST", tp: mysql.TypeVarchar, size: 16, flag: mysql.NotNullFlag, comment: "The digest of the transaction, calculated from the digests of its SQL statements"},
+	{name: txninfo.AllSQLDigestsStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, flag: mysql.NotNullFlag, comment: "A list of the digests of SQL statements that the transaction has executed"},
+}
+
+var tableRegionCacheStatsCols = []columnInfo{
+	{name: "TYPE", tp: mysql.TypeVarchar, size: 16, flag: mysql.NotNullFlag, comment: "One of summary, operation, invalidation and store"},
+	{name: "NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, comment: "The statistic, the region error which invalidates the cached region, or the store ID"},
+	{name: "VALUE", tp: mysql.TypeDouble, size: 22, comment: "The count or the ratio, 1 if the store is up and 0 otherwise for the stores"},
+	{name: "DETAIL", tp: mysql.TypeVarchar, size: 256},
+}
+
 // GetShardingInfo returns a nil or description string for the sharding information of given TableInfo.
 // The returned description string may be:
 //  - "NOT_SHARDED": for tables that SHARD_ROW_ID_BITS is not specified.
@@ -1833,6 +1938,13 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableDataLockWaits:                      tableDataLockWaitsCols,
 	TableAttributes:                         tableAttributesCols,
 	TablePlacementPolicies:                  tablePlacementPoliciesCols,
+	TableCoprocessorCache:                   tableCoprocessorCacheCols,
+	TableMDLView:                            tableMDLViewCols,
+	TableTrxSummary:                         tableTrxSummaryCols,
+	TableRegionCacheStats:                   tableRegionCacheStatsCols,
+	TablePlacementStatus:                    tablePlacementStatusCols,
+	TableSessionWriteConflicts:              tableSessionWriteConflictsCols,
+	TableUserAttributes:                     tableUserAttributesCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
@@ -56,6 +56,21 @@ var (
 	ErrLockExpire = dbterror.ClassTiKV.NewStd(mysql.ErrLockExpire)
 )
 
+// WriteConflictKeys is the argument of ErrWriteConflict that describes the conflicting key and the primary key of
+// the transaction. The raw keys are kept so that the SQL layer can decode them with the schema information.
+type WriteConflictKeys struct {
+	Key     Key
+	Primary Key
+	// KeyDesc and PrimaryDesc are the readable descriptions of Key and Primary.
+	KeyDesc     string
+	PrimaryDesc string
+}
+
+// String implements fmt.Stringer interface.
+func (k *WriteConflictKeys) String() string {
+	return k.KeyDesc + " primary=" + k.PrimaryDesc
+}
+
 // IsTxnRetryableError checks if the error could safely retry the transaction.
 func IsTxnRetryableError(err error) bool {
 	if err == nil {
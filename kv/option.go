@@ -90,6 +90,9 @@ const (
 	ReplicaReadMixed
 	// ReplicaReadClosest stands for 'read from leader and follower which locates with the same zone'
 	ReplicaReadClosest
+	// ReplicaReadClosestAdaptive stands for 'read from leader and follower which locates with the same zone'
+	// if the estimated response size of the statement exceeds the threshold, otherwise 'read from leader'.
+	ReplicaReadClosestAdaptive
 )
 
 // IsFollowerRead checks if follower is going to be used to read data.
@@ -99,5 +102,5 @@ func (r ReplicaReadType) IsFollowerRead() bool {
 
 // IsClosestRead checks whether is going to request closet store to read
 func (r ReplicaReadType) IsClosestRead() bool {
-	return r == ReplicaReadClosest
+	return r == ReplicaReadClosest || r == ReplicaReadClosestAdaptive
 }
@@ -36,6 +36,7 @@ import (
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/tikv/client-go/v2/txnkv/txnsnapshot"
 	tikvutil "github.com/tikv/client-go/v2/util"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
 
@@ -74,6 +75,11 @@ const MaxAutoRandomBits = 15
 // Test needs to change it, so it's a variable.
 var step = int64(30000)
 
+// SequenceCacheLimit is the max number of values a TiDB server caches for a sequence in one allocation,
+// 0 means the CACHE value of the sequence is used. The values of a sequence are allocated from the storage,
+// so a small limit keeps the values used by the TiDB servers close to each other.
+var SequenceCacheLimit = atomic.NewInt64(0)
+
 // AllocatorType is the type of allocator for generating auto-id. Different type of allocators use different key-value pairs.
 type AllocatorType uint8
 
@@ -530,6 +536,10 @@ func NewAllocatorsFromTblInfo(store kv.Storage, schemaID int64, tblInfo *model.T
 	tblVer := AllocOptionTableInfoVersion(tblInfo.Version)
 
 	hasRowID := !tblInfo.PKIsHandle && !tblInfo.IsCommonHandle
+	// The changing table allocates its row IDs from the original table.
+	if tblInfo.ChangingTable != nil && !tblInfo.ChangingTable.HasClusteredIndex() {
+		hasRowID = true
+	}
 	hasAutoIncID := tblInfo.GetAutoIncrementColInfo() != nil
 	if hasRowID || hasAutoIncID {
 		alloc := NewAllocator(store, dbID, tblInfo.ID, tblInfo.IsAutoIncColUnsigned(), RowIDAllocType, idCacheOpt, tblVer)
@@ -928,6 +938,9 @@ func (alloc *allocator) alloc4Sequence() (min int64, max int64, round int64, err
 	if !alloc.sequence.Cache {
 		cacheSize = 1
 	}
+	if limit := SequenceCacheLimit.Load(); limit > 0 && cacheSize > limit {
+		cacheSize = limit
+	}
 
 	var newBase, newEnd int64
 	startTime := time.Now()
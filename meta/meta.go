@@ -668,6 +668,7 @@ func (m *Meta) GetTable(dbID int64, tableID int64) (*model.TableInfo, error) {
 //	DDLJobList: list jobs
 //	DDLJobHistory: hash
 //	DDLJobReorg: hash
+//	DDLPaused: int64
 //
 // for multi DDL workers, only one can become the owner
 // to operate DDL jobs, and dispatch them to MR Jobs.
@@ -677,6 +678,8 @@ var (
 	mDDLJobAddIdxList = []byte("DDLJobAddIdxList")
 	mDDLJobHistoryKey = []byte("DDLJobHistory")
 	mDDLJobReorgKey   = []byte("DDLJobReorg")
+	mDDLPausedKey     = []byte("DDLPaused")
+	mDDLJobUpdateVer  = []byte("DDLJobUpdateVersion")
 )
 
 // JobListKeyType is a key type of the DDL job queue.
@@ -785,6 +788,30 @@ func (m *Meta) UpdateDDLJob(index int64, job *model.Job, updateRawArgs bool, job
 	return errors.Trace(err)
 }
 
+// RemoveDDLJobByIdx removes the DDL job with the index from the list,
+// and the jobs before it are moved back by one.
+// The length of jobListKeys can only be 1 or 0.
+// If its length is 1, we need to replace m.jobListKey with jobListKeys[0].
+// Otherwise, we use m.jobListKey directly.
+func (m *Meta) RemoveDDLJobByIdx(index int64, jobListKeys ...JobListKeyType) error {
+	listKey := m.jobListKey
+	if len(jobListKeys) != 0 {
+		listKey = jobListKeys[0]
+	}
+
+	for i := index; i > 0; i-- {
+		prev, err := m.txn.LIndex(listKey, i-1)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err = m.txn.LSet(listKey, i, prev); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	_, err := m.txn.LPop(listKey)
+	return errors.Trace(err)
+}
+
 // DDLJobQueueLen returns the DDL job queue length.
 // The length of jobListKeys can only be 1 or 0.
 // If its length is 1, we need to replace m.jobListKey with jobListKeys[0].
@@ -797,6 +824,37 @@ func (m *Meta) DDLJobQueueLen(jobListKeys ...JobListKeyType) (int64, error) {
 	return m.txn.LLen(listKey)
 }
 
+// SetDDLPaused stops the DDL owner running DDL jobs until the time expire, the one pausing DDL renews it
+// before it expires. So DDL is resumed by itself if the one pausing DDL crashes. A zero expire resumes DDL.
+func (m *Meta) SetDDLPaused(expire time.Time) error {
+	if expire.IsZero() {
+		return errors.Trace(m.txn.Clear(mDDLPausedKey))
+	}
+	return errors.Trace(m.txn.Set(mDDLPausedKey, []byte(strconv.FormatInt(expire.UnixNano(), 10))))
+}
+
+// IsDDLPaused returns whether the DDL owner stops running DDL jobs at the time now.
+func (m *Meta) IsDDLPaused(now time.Time) (bool, error) {
+	expire, err := m.txn.GetInt64(mDDLPausedKey)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return expire > now.UnixNano(), nil
+}
+
+// IncDDLJobUpdateVersion increases the version of the queued DDL jobs, it's called when the queued jobs are
+// paused, resumed or cancelled by the users, so the DDL owner knows the paused jobs need to be checked again.
+func (m *Meta) IncDDLJobUpdateVersion() error {
+	_, err := m.txn.Inc(mDDLJobUpdateVer, 1)
+	return errors.Trace(err)
+}
+
+// GetDDLJobUpdateVersion gets the version of the queued DDL jobs.
+func (m *Meta) GetDDLJobUpdateVersion() (int64, error) {
+	ver, err := m.txn.GetInt64(mDDLJobUpdateVer)
+	return ver, errors.Trace(err)
+}
+
 // GetAllDDLJobsInQueue gets all DDL Jobs in the current queue.
 // The length of jobListKeys can only be 1 or 0.
 // If its length is 1, we need to replace m.jobListKey with jobListKeys[0].
@@ -1016,6 +1074,8 @@ var (
 	ColumnElementKey ElementKeyType = []byte("_col_")
 	// IndexElementKey is the key for index element.
 	IndexElementKey ElementKeyType = []byte("_idx_")
+	// TableElementKey is the key for table element.
+	TableElementKey ElementKeyType = []byte("_tbl_")
 )
 
 const elementKeyLen = 5
@@ -1056,6 +1116,8 @@ func DecodeElement(b []byte) (*Element, error) {
 		tp = IndexElementKey
 	case string(ColumnElementKey):
 		tp = ColumnElementKey
+	case string(TableElementKey):
+		tp = TableElementKey
 	default:
 		return nil, errors.Errorf("invalid encoded element key prefix %q", prefix)
 	}
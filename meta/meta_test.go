@@ -431,6 +431,7 @@ func TestElement(t *testing.T) {
 	checkElement(key, errors.Errorf(`invalid encoded element key prefix "_col\x00"`))
 	checkElement(meta.IndexElementKey, nil)
 	checkElement(meta.ColumnElementKey, nil)
+	checkElement(meta.TableElementKey, nil)
 	key = []byte("inexistent")
 	checkElement(key, errors.Errorf("invalid encoded element key prefix %q", key[:5]))
 
@@ -440,6 +441,37 @@ func TestElement(t *testing.T) {
 	require.EqualError(t, err, `invalid encoded element "_col_" length 5`)
 }
 
+func TestDDLPaused(t *testing.T) {
+	store, err := mockstore.NewMockStore()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, store.Close())
+	}()
+
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	m := meta.NewMeta(txn)
+	now := time.Now()
+	paused, err := m.IsDDLPaused(now)
+	require.NoError(t, err)
+	require.False(t, paused)
+
+	require.NoError(t, m.SetDDLPaused(now.Add(time.Minute)))
+	paused, err = m.IsDDLPaused(now)
+	require.NoError(t, err)
+	require.True(t, paused)
+	// DDL is resumed once the pause expires.
+	paused, err = m.IsDDLPaused(now.Add(time.Minute))
+	require.NoError(t, err)
+	require.False(t, paused)
+
+	require.NoError(t, m.SetDDLPaused(time.Time{}))
+	paused, err = m.IsDDLPaused(now)
+	require.NoError(t, err)
+	require.False(t, paused)
+	require.NoError(t, txn.Rollback())
+}
+
 func TestDDL(t *testing.T) {
 	testCases := []struct {
 		desc        string
@@ -636,6 +668,32 @@ func TestAddIndexJob(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRemoveDDLJobByIdx(t *testing.T) {
+	store, err := mockstore.NewMockStore()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, store.Close())
+	}()
+
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	m := meta.NewMeta(txn)
+	for i := int64(1); i <= 4; i++ {
+		require.NoError(t, m.EnQueueDDLJob(&model.Job{ID: i}))
+	}
+	require.NoError(t, m.RemoveDDLJobByIdx(2))
+	require.NoError(t, m.RemoveDDLJobByIdx(0))
+	for i, id := range []int64{2, 4} {
+		job, err := m.GetDDLJobByIdx(int64(i))
+		require.NoError(t, err)
+		require.Equal(t, id, job.ID)
+	}
+	cnt, err := m.DDLJobQueueLen()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), cnt)
+	require.NoError(t, txn.Rollback())
+}
+
 func BenchmarkGenGlobalIDs(b *testing.B) {
 	store, err := mockstore.NewMockStore()
 	require.NoError(b, err)
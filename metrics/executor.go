@@ -46,4 +46,13 @@ var (
 			Name:      "statement_db_total",
 			Help:      "Counter of StmtNode by Database.",
 		}, []string{LblDb, LblType})
+
+	// ChunkColumnAllocCounter records the number of chunk columns allocated by the connection chunk allocators.
+	ChunkColumnAllocCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "executor",
+			Name:      "chunk_column_alloc_total",
+			Help:      "Counter of chunk columns allocated by the connection chunk allocators.",
+		}, []string{LblType})
 )
@@ -52,6 +52,25 @@ var (
 			Help:      "Counter of queries.",
 		}, []string{LblType, LblResult})
 
+	// TenantQueryDurationHistogram records the duration of queries by the current database and the resource group.
+	TenantQueryDurationHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "tenant_handle_query_duration_seconds",
+			Help:      "Bucketed histogram of processing time (s) of handled queries by database and resource group.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 29), // 0.5ms ~ 1.5days
+		}, []string{LblSQLType, LblDb, LblResGroup})
+
+	// TenantQueryTotalCounter records the number of queries by the current database and the resource group.
+	TenantQueryTotalCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "tenant_query_total",
+			Help:      "Counter of queries by database and resource group.",
+		}, []string{LblSQLType, LblResult, LblDb, LblResGroup})
+
 	ConnGauge = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: "tidb",
@@ -28,6 +28,13 @@ var (
 			Name:      "non_recursive_cte_usage",
 			Help:      "Counter of usage of CTE",
 		}, []string{LblCTEType})
+	TelemetryMPPQueryCnt = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "telemetry",
+			Name:      "mpp_query_usage",
+			Help:      "Counter of queries executed in MPP mode",
+		})
 )
 
 // readCounter reads the value of a prometheus.Counter.
@@ -67,3 +74,8 @@ func GetCTECounter() CTEUsageCounter {
 		NonCTEUsed:          readCounter(TelemetrySQLCTECnt.With(prometheus.Labels{LblCTEType: "notCTE"})),
 	}
 }
+
+// GetMPPQueryCounter gets the count of queries executed in MPP mode.
+func GetMPPQueryCounter() int64 {
+	return readCounter(TelemetryMPPQueryCnt)
+}
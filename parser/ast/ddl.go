@@ -2545,6 +2545,7 @@ const (
 	AlterTableCache
 	AlterTableNoCache
 	AlterTableStatsOptions
+	AlterTableClusteredIndex
 )
 
 // LockType is the type for AlterTableSpec.
@@ -2645,11 +2646,14 @@ type AlterTableSpec struct {
 	Statistics       *StatisticsSpec
 	AttributesSpec   *AttributesSpec
 	StatsOptionsSpec *StatsOptionsSpec
+	PrimaryKeyTp     model.PrimaryKeyType
 }
 
 type TiFlashReplicaSpec struct {
 	Count  uint64
 	Labels []string
+	// HighPriority indicates the replica construction of the table should be scheduled first.
+	HighPriority bool
 }
 
 // AlterOrderItem represents an item in order by at alter table stmt.
@@ -2676,15 +2680,17 @@ func (n *AlterTableSpec) Restore(ctx *format.RestoreCtx) error {
 	case AlterTableSetTiFlashReplica:
 		ctx.WriteKeyWord("SET TIFLASH REPLICA ")
 		ctx.WritePlainf("%d", n.TiFlashReplica.Count)
-		if len(n.TiFlashReplica.Labels) == 0 {
-			break
-		}
-		ctx.WriteKeyWord(" LOCATION LABELS ")
-		for i, v := range n.TiFlashReplica.Labels {
-			if i > 0 {
-				ctx.WritePlain(", ")
+		if len(n.TiFlashReplica.Labels) > 0 {
+			ctx.WriteKeyWord(" LOCATION LABELS ")
+			for i, v := range n.TiFlashReplica.Labels {
+				if i > 0 {
+					ctx.WritePlain(", ")
+				}
+				ctx.WriteString(v)
 			}
-			ctx.WriteString(v)
+		}
+		if n.TiFlashReplica.HighPriority {
+			ctx.WriteKeyWord(" HIGH_PRIORITY")
 		}
 	case AlterTableAddStatistics:
 		ctx.WriteKeyWord("ADD STATS_EXTENDED ")
@@ -3179,6 +3185,14 @@ func (n *AlterTableSpec) Restore(ctx *format.RestoreCtx) error {
 		if err := spec.Restore(ctx); err != nil {
 			return errors.Annotatef(err, "An error occurred while restore AlterTableSpec.StatsOptionsSpec")
 		}
+	case AlterTableClusteredIndex:
+		ctx.WriteKeyWord("ALTER PRIMARY KEY ")
+		switch n.PrimaryKeyTp {
+		case model.PrimaryKeyTypeClustered:
+			ctx.WriteKeyWord("CLUSTERED")
+		case model.PrimaryKeyTypeNonClustered:
+			ctx.WriteKeyWord("NONCLUSTERED")
+		}
 
 	default:
 		// TODO: not support
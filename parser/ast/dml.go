@@ -518,6 +518,10 @@ type TableSource struct {
 
 	// AsName is the alias name of the table source.
 	AsName model.CIStr
+
+	// Lateral indicates the derived table is a LATERAL derived table,
+	// which can refer to the columns of the preceding tables in the FROM clause.
+	Lateral bool
 }
 
 func (*TableSource) resultSet() {}
@@ -564,6 +568,9 @@ func (n *TableSource) Restore(ctx *format.RestoreCtx) error {
 			ctx.WritePlain(")")
 		}
 	} else {
+		if n.Lateral {
+			ctx.WriteKeyWord("LATERAL ")
+		}
 		if needParen {
 			ctx.WritePlain("(")
 		}
@@ -1844,6 +1851,16 @@ func (n *LoadDataStmt) Restore(ctx *format.RestoreCtx) error {
 	return nil
 }
 
+// SecureText implements SensitiveStmtNode
+func (n *LoadDataStmt) SecureText() string {
+	redactedStmt := *n
+	redactedStmt.Path = redactStorageURL(n.Path)
+
+	var sb strings.Builder
+	_ = redactedStmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb))
+	return sb.String()
+}
+
 // Accept implements Node Accept interface.
 func (n *LoadDataStmt) Accept(v Visitor) (Node, bool) {
 	newNode, skipChildren := v.Enter(n)
@@ -2646,6 +2663,8 @@ type ShowStmt struct {
 	ShowProfileTypes []int  // Used for `SHOW PROFILE` syntax
 	ShowProfileArgs  *int64 // Used for `SHOW PROFILE` syntax
 	ShowProfileLimit *Limit // Used for `SHOW PROFILE` syntax
+
+	Limit *Limit // Used for `SHOW TABLE REGIONS` syntax
 }
 
 // Restore implements Node interface.
@@ -2934,6 +2953,12 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 			if err := restoreShowLikeOrWhereOpt(); err != nil {
 				return err
 			}
+			if n.Limit != nil {
+				ctx.WritePlain(" ")
+				if err := n.Limit.Restore(ctx); err != nil {
+					return errors.Annotate(err, "An error occurred while restore ShowStmt.Limit")
+				}
+			}
 			return nil
 		case ShowTableNextRowId:
 			ctx.WriteKeyWord("TABLE ")
@@ -2996,6 +3021,13 @@ func (n *ShowStmt) Accept(v Visitor) (Node, bool) {
 		}
 		n.Where = node.(ExprNode)
 	}
+	if n.Limit != nil {
+		node, ok := n.Limit.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Limit = node.(*Limit)
+	}
 	return v.Leave(n)
 }
 
@@ -3185,12 +3217,40 @@ const (
 	Groups
 )
 
+// FrameExclusion is the type of the EXCLUDE clause of window function frame.
+type FrameExclusion int
+
+// Window function frame exclusion types.
+const (
+	ExcludeNone FrameExclusion = iota
+	ExcludeCurrentRow
+	ExcludeGroup
+	ExcludeTies
+	ExcludeNoOthers
+)
+
+// String implements fmt.Stringer interface.
+func (e FrameExclusion) String() string {
+	switch e {
+	case ExcludeCurrentRow:
+		return "CURRENT ROW"
+	case ExcludeGroup:
+		return "GROUP"
+	case ExcludeTies:
+		return "TIES"
+	case ExcludeNoOthers:
+		return "NO OTHERS"
+	}
+	return ""
+}
+
 // FrameClause represents frame clause.
 type FrameClause struct {
 	node
 
-	Type   FrameType
-	Extent FrameExtent
+	Type      FrameType
+	Extent    FrameExtent
+	Exclusion FrameExclusion
 }
 
 // Restore implements Node interface.
@@ -3211,6 +3271,10 @@ func (n *FrameClause) Restore(ctx *format.RestoreCtx) error {
 	if err := n.Extent.End.Restore(ctx); err != nil {
 		return errors.Annotate(err, "An error occurred while restore FrameClause.Extent.End")
 	}
+	if n.Exclusion != ExcludeNone {
+		ctx.WriteKeyWord(" EXCLUDE ")
+		ctx.WriteKeyWord(n.Exclusion.String())
+	}
 
 	return nil
 }
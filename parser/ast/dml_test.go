@@ -16,6 +16,7 @@ package ast_test
 import (
 	"testing"
 
+	"github.com/pingcap/tidb/parser"
 	. "github.com/pingcap/tidb/parser/ast"
 	"github.com/stretchr/testify/require"
 )
@@ -414,3 +415,28 @@ func TestFulltextSearchModifier(t *testing.T) {
 	require.True(t, FulltextSearchModifier(FulltextSearchModifierNaturalLanguageMode).IsNaturalLanguageMode())
 	require.False(t, FulltextSearchModifier(FulltextSearchModifierNaturalLanguageMode).WithQueryExpansion())
 }
+
+func TestLoadDataSecureText(t *testing.T) {
+	testCases := []struct {
+		input   string
+		secured string
+	}{
+		{
+			input:   "load data local infile '/tmp/t.csv' into table t",
+			secured: `^\QLOAD DATA LOCAL INFILE '/tmp/t.csv' IGNORE INTO TABLE ` + "`t`" + `\E`,
+		},
+		{
+			input:   "load data infile 's3://bucket/t.csv?access-key=abcdefghi&secret-access-key=123' into table t",
+			secured: `^\QLOAD DATA INFILE 's3://bucket/t.csv?access-key=xxxxxx&secret-access-key=xxxxxx' INTO TABLE ` + "`t`" + `\E`,
+		},
+	}
+
+	p := parser.New()
+	for _, tc := range testCases {
+		node, err := p.ParseOneStmt(tc.input, "", "")
+		require.NoError(t, err, tc.input)
+		n, ok := node.(SensitiveStmtNode)
+		require.True(t, ok, tc.input)
+		require.Regexp(t, tc.secured, n.SecureText(), tc.input)
+	}
+}
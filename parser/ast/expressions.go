@@ -882,6 +882,8 @@ type PatternLikeExpr struct {
 	Pattern ExprNode
 	// Not is true, the expression is "not like".
 	Not bool
+	// IsILike is true, the expression is "ilike", which matches the pattern case-insensitively.
+	IsILike bool
 
 	Escape byte
 
@@ -896,7 +898,10 @@ func (n *PatternLikeExpr) Restore(ctx *format.RestoreCtx) error {
 	}
 
 	if n.Not {
-		ctx.WriteKeyWord(" NOT LIKE ")
+		ctx.WriteKeyWord(" NOT")
+	}
+	if n.IsILike {
+		ctx.WriteKeyWord(" ILIKE ")
 	} else {
 		ctx.WriteKeyWord(" LIKE ")
 	}
@@ -918,7 +923,10 @@ func (n *PatternLikeExpr) Restore(ctx *format.RestoreCtx) error {
 func (n *PatternLikeExpr) Format(w io.Writer) {
 	n.Expr.Format(w)
 	if n.Not {
-		fmt.Fprint(w, " NOT LIKE ")
+		fmt.Fprint(w, " NOT")
+	}
+	if n.IsILike {
+		fmt.Fprint(w, " ILIKE ")
 	} else {
 		fmt.Fprint(w, " LIKE ")
 	}
@@ -287,6 +287,8 @@ func TestPatternLikeExprRestore(t *testing.T) {
 		{"a not like 't1%'", "`a` NOT LIKE _UTF8MB4't1%'"},
 		{"a not like '%D%v%'", "`a` NOT LIKE _UTF8MB4'%D%v%'"},
 		{"a not like '%t1_|'", "`a` NOT LIKE _UTF8MB4'%t1_|'"},
+		{"a ilike 't1%'", "`a` ILIKE _UTF8MB4't1%'"},
+		{"a not ilike '%T1|_%' escape '|'", "`a` NOT ILIKE _UTF8MB4'%T1|_%' ESCAPE '|'"},
 	}
 	extractNodeFunc := func(node Node) Node {
 		return node.(*SelectStmt).Fields.Fields[0].Expr
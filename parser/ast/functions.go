@@ -221,6 +221,7 @@ const (
 	Substring       = "substring"
 	Substr          = "substr"
 	SubstringIndex  = "substring_index"
+	SplitPart       = "split_part"
 	ToBase64        = "to_base64"
 	Trim            = "trim"
 	Translate       = "translate"
@@ -260,6 +261,7 @@ const (
 	TiDBDecodeSQLDigests = "tidb_decode_sql_digests"
 	FormatBytes          = "format_bytes"
 	FormatNanoTime       = "format_nano_time"
+	QueryAttrString      = "mysql_query_attribute_string"
 
 	// control functions
 	If     = "if"
@@ -286,6 +288,7 @@ const (
 	Sleep           = "sleep"
 	UUID            = "uuid"
 	UUIDShort       = "uuid_short"
+	UUIDv7          = "uuid_v7"
 	UUIDToBin       = "uuid_to_bin"
 	BinToUUID       = "bin_to_uuid"
 	VitessHash      = "vitess_hash"
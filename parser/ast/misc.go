@@ -228,6 +228,12 @@ func (n *ExplainStmt) Restore(ctx *format.RestoreCtx) error {
 	ctx.WriteKeyWord("EXPLAIN ")
 	if n.Analyze {
 		ctx.WriteKeyWord("ANALYZE ")
+		if !strings.EqualFold(n.Format, "row") {
+			ctx.WriteKeyWord("FORMAT ")
+			ctx.WritePlain("= ")
+			ctx.WriteString(n.Format)
+			ctx.WritePlain(" ")
+		}
 	} else {
 		ctx.WriteKeyWord("FORMAT ")
 		ctx.WritePlain("= ")
@@ -1278,11 +1284,14 @@ const (
 	MaxUpdatesPerHour
 	MaxConnectionsPerHour
 	MaxUserConnections
+	ResourceGroup
 )
 
 type ResourceOption struct {
 	Type  int
 	Count int64
+	// ResourceGroupName is the resource group of the user, which is only set for the ResourceGroup type.
+	ResourceGroupName string
 }
 
 func (r *ResourceOption) Restore(ctx *format.RestoreCtx) error {
@@ -1295,6 +1304,10 @@ func (r *ResourceOption) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("MAX_CONNECTIONS_PER_HOUR ")
 	case MaxUserConnections:
 		ctx.WriteKeyWord("MAX_USER_CONNECTIONS ")
+	case ResourceGroup:
+		ctx.WriteKeyWord("RESOURCE GROUP ")
+		ctx.WriteName(r.ResourceGroupName)
+		return nil
 	default:
 		return errors.Errorf("Unsupported ResourceOption.Type %d", r.Type)
 	}
@@ -1309,6 +1322,9 @@ const (
 	PasswordExpireInterval
 	Lock
 	Unlock
+	FailedLoginAttempts
+	PasswordLockTime
+	PasswordLockTimeUnbounded
 )
 
 type PasswordOrLockOption struct {
@@ -1332,23 +1348,56 @@ func (p *PasswordOrLockOption) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("ACCOUNT LOCK")
 	case Unlock:
 		ctx.WriteKeyWord("ACCOUNT UNLOCK")
+	case FailedLoginAttempts:
+		ctx.WriteKeyWord("FAILED_LOGIN_ATTEMPTS")
+		ctx.WritePlainf(" %d", p.Count)
+	case PasswordLockTime:
+		ctx.WriteKeyWord("PASSWORD_LOCK_TIME")
+		ctx.WritePlainf(" %d", p.Count)
+	case PasswordLockTimeUnbounded:
+		ctx.WriteKeyWord("PASSWORD_LOCK_TIME UNBOUNDED")
 	default:
 		return errors.Errorf("Unsupported PasswordOrLockOption.Type %d", p.Type)
 	}
 	return nil
 }
 
+const (
+	UserCommentType = iota + 1
+	UserAttributeType
+)
+
+// CommentOrAttributeOption is the COMMENT or ATTRIBUTE option of CREATE USER and ALTER USER.
+type CommentOrAttributeOption struct {
+	Type  int
+	Value string
+}
+
+func (c *CommentOrAttributeOption) Restore(ctx *format.RestoreCtx) error {
+	switch c.Type {
+	case UserCommentType:
+		ctx.WriteKeyWord("COMMENT ")
+	case UserAttributeType:
+		ctx.WriteKeyWord("ATTRIBUTE ")
+	default:
+		return errors.Errorf("Unsupported CommentOrAttributeOption.Type %d", c.Type)
+	}
+	ctx.WriteString(c.Value)
+	return nil
+}
+
 // CreateUserStmt creates user account.
 // See https://dev.mysql.com/doc/refman/5.7/en/create-user.html
 type CreateUserStmt struct {
 	stmtNode
 
-	IsCreateRole          bool
-	IfNotExists           bool
-	Specs                 []*UserSpec
-	TLSOptions            []*TLSOption
-	ResourceOptions       []*ResourceOption
-	PasswordOrLockOptions []*PasswordOrLockOption
+	IsCreateRole             bool
+	IfNotExists              bool
+	Specs                    []*UserSpec
+	TLSOptions               []*TLSOption
+	ResourceOptions          []*ResourceOption
+	PasswordOrLockOptions    []*PasswordOrLockOption
+	CommentOrAttributeOption *CommentOrAttributeOption
 }
 
 // Restore implements Node interface.
@@ -1400,6 +1449,13 @@ func (n *CreateUserStmt) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotatef(err, "An error occurred while restore CreateUserStmt.PasswordOrLockOptions[%d]", i)
 		}
 	}
+
+	if n.CommentOrAttributeOption != nil {
+		ctx.WritePlain(" ")
+		if err := n.CommentOrAttributeOption.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore CreateUserStmt.CommentOrAttributeOption")
+		}
+	}
 	return nil
 }
 
@@ -1429,12 +1485,13 @@ func (n *CreateUserStmt) SecureText() string {
 type AlterUserStmt struct {
 	stmtNode
 
-	IfExists              bool
-	CurrentAuth           *AuthOption
-	Specs                 []*UserSpec
-	TLSOptions            []*TLSOption
-	ResourceOptions       []*ResourceOption
-	PasswordOrLockOptions []*PasswordOrLockOption
+	IfExists                 bool
+	CurrentAuth              *AuthOption
+	Specs                    []*UserSpec
+	TLSOptions               []*TLSOption
+	ResourceOptions          []*ResourceOption
+	PasswordOrLockOptions    []*PasswordOrLockOption
+	CommentOrAttributeOption *CommentOrAttributeOption
 }
 
 // Restore implements Node interface.
@@ -1489,6 +1546,13 @@ func (n *AlterUserStmt) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotatef(err, "An error occurred while restore AlterUserStmt.PasswordOrLockOptions[%d]", i)
 		}
 	}
+
+	if n.CommentOrAttributeOption != nil {
+		ctx.WritePlain(" ")
+		if err := n.CommentOrAttributeOption.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore AlterUserStmt.CommentOrAttributeOption")
+		}
+	}
 	return nil
 }
 
@@ -1697,9 +1761,10 @@ type StatisticsSpec struct {
 
 // CreateStatisticsStmt is a statement to create extended statistics.
 // Examples:
-//   CREATE STATISTICS stats1 (cardinality) ON t(a, b, c);
-//   CREATE STATISTICS stats2 (dependency) ON t(a, b);
-//   CREATE STATISTICS stats3 (correlation) ON t(a, b);
+//
+//	CREATE STATISTICS stats1 (cardinality) ON t(a, b, c);
+//	CREATE STATISTICS stats2 (dependency) ON t(a, b);
+//	CREATE STATISTICS stats3 (correlation) ON t(a, b);
 type CreateStatisticsStmt struct {
 	stmtNode
 
@@ -1767,7 +1832,8 @@ func (n *CreateStatisticsStmt) Accept(v Visitor) (Node, bool) {
 
 // DropStatisticsStmt is a statement to drop extended statistics.
 // Examples:
-//   DROP STATISTICS stats1;
+//
+//	DROP STATISTICS stats1;
 type DropStatisticsStmt struct {
 	stmtNode
 
@@ -1858,6 +1924,9 @@ const (
 	AdminResetTelemetryID
 	AdminReloadStatistics
 	AdminFlushPlanCache
+	AdminReloadSQLBlocklist
+	AdminKillTopMemory
+	AdminCompareChecksumTable
 )
 
 // HandleRange represents a range where handle value >= Begin and < End.
@@ -1898,6 +1967,7 @@ const (
 )
 
 // ShowSlow is used for the following command:
+//
 //	admin show slow top [ internal | all] N
 //	admin show slow recent N
 type ShowSlow struct {
@@ -1939,11 +2009,20 @@ type AdminStmt struct {
 	Tables    []*TableName
 	JobIDs    []int64
 	JobNumber int64
+	// TopN is the number of connections to kill for `ADMIN KILL TOP MEMORY N`.
+	TopN int64
+	// AsOf is the snapshot to calculate the checksums at for `ADMIN [COMPARE] CHECKSUM TABLE`.
+	AsOf *AsOfClause
+	// ChecksumIndexes reports the checksum of every index besides the whole table.
+	ChecksumIndexes bool
+	// ChecksumResultTable stores the checksums to compare with for `ADMIN COMPARE CHECKSUM TABLE`.
+	ChecksumResultTable *TableName
 
 	HandleRanges   []HandleRange
 	ShowSlow       *ShowSlow
 	Plugins        []string
 	Where          ExprNode
+	Limit          *Limit
 	StatementScope StatementScope
 }
 
@@ -1960,6 +2039,16 @@ func (n *AdminStmt) Restore(ctx *format.RestoreCtx) error {
 		}
 		return nil
 	}
+	restoreAsOf := func() error {
+		if n.AsOf == nil {
+			return nil
+		}
+		ctx.WritePlain(" ")
+		if err := n.AsOf.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore AdminStmt.AsOf")
+		}
+		return nil
+	}
 	restoreJobIDs := func() {
 		for i, v := range n.JobIDs {
 			if i != 0 {
@@ -1984,6 +2073,12 @@ func (n *AdminStmt) Restore(ctx *format.RestoreCtx) error {
 				return errors.Annotate(err, "An error occurred while restore ShowStmt.Where")
 			}
 		}
+		if n.Limit != nil {
+			ctx.WritePlain(" ")
+			if err := n.Limit.Restore(ctx); err != nil {
+				return errors.Annotate(err, "An error occurred while restore AdminStmt.Limit")
+			}
+		}
 	case AdminShowNextRowID:
 		ctx.WriteKeyWord("SHOW ")
 		if err := restoreTables(); err != nil {
@@ -2033,6 +2128,24 @@ func (n *AdminStmt) Restore(ctx *format.RestoreCtx) error {
 		if err := restoreTables(); err != nil {
 			return err
 		}
+		if err := restoreAsOf(); err != nil {
+			return err
+		}
+		if n.ChecksumIndexes {
+			ctx.WriteKeyWord(" WITH INDEXES")
+		}
+	case AdminCompareChecksumTable:
+		ctx.WriteKeyWord("COMPARE CHECKSUM TABLE ")
+		if err := restoreTables(); err != nil {
+			return err
+		}
+		if err := restoreAsOf(); err != nil {
+			return err
+		}
+		ctx.WriteKeyWord(" USING ")
+		if err := n.ChecksumResultTable.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore AdminStmt.ChecksumResultTable")
+		}
 	case AdminCancelDDLJobs:
 		ctx.WriteKeyWord("CANCEL DDL JOBS ")
 		restoreJobIDs()
@@ -2048,6 +2161,11 @@ func (n *AdminStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("RELOAD EXPR_PUSHDOWN_BLACKLIST")
 	case AdminReloadOptRuleBlacklist:
 		ctx.WriteKeyWord("RELOAD OPT_RULE_BLACKLIST")
+	case AdminReloadSQLBlocklist:
+		ctx.WriteKeyWord("RELOAD SQL_BLOCKLIST")
+	case AdminKillTopMemory:
+		ctx.WriteKeyWord("KILL TOP MEMORY ")
+		ctx.WritePlainf("%d", n.TopN)
 	case AdminPluginEnable:
 		ctx.WriteKeyWord("PLUGINS ENABLE")
 		for i, v := range n.Plugins {
@@ -2120,6 +2238,30 @@ func (n *AdminStmt) Accept(v Visitor) (Node, bool) {
 		n.Where = node.(ExprNode)
 	}
 
+	if n.Limit != nil {
+		node, ok := n.Limit.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Limit = node.(*Limit)
+	}
+
+	if n.AsOf != nil {
+		node, ok := n.AsOf.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.AsOf = node.(*AsOfClause)
+	}
+
+	if n.ChecksumResultTable != nil {
+		node, ok := n.ChecksumResultTable.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.ChecksumResultTable = node.(*TableName)
+	}
+
 	return v.Leave(n)
 }
 
@@ -2873,6 +3015,9 @@ type BRIEStmt struct {
 	Tables  []*TableName
 	Storage string
 	Options []*BRIEOption
+	// VolumeSnapshot is set for BACKUP CLUSTER USING SNAPSHOT, the data is backed up by
+	// the snapshots of the storage volumes taken by external tools.
+	VolumeSnapshot bool
 }
 
 func (n *BRIEStmt) Accept(v Visitor) (Node, bool) {
@@ -2895,6 +3040,8 @@ func (n *BRIEStmt) Restore(ctx *format.RestoreCtx) error {
 	ctx.WriteKeyWord(n.Kind.String())
 
 	switch {
+	case n.VolumeSnapshot:
+		ctx.WriteKeyWord(" CLUSTER USING SNAPSHOT")
 	case len(n.Tables) != 0:
 		ctx.WriteKeyWord(" TABLE ")
 		for index, table := range n.Tables {
@@ -2936,11 +3083,10 @@ func (n *BRIEStmt) Restore(ctx *format.RestoreCtx) error {
 	return nil
 }
 
-// SecureText implements SensitiveStmtNode
-func (n *BRIEStmt) SecureText() string {
+// redactStorageURL hides the credentials in the storage URL.
+func redactStorageURL(storage string) string {
 	// FIXME: this solution is not scalable, and duplicates some logic from BR.
-	redactedStorage := n.Storage
-	u, err := url.Parse(n.Storage)
+	u, err := url.Parse(storage)
 	if err == nil {
 		if u.Scheme == "s3" {
 			query := u.Query()
@@ -2951,16 +3097,21 @@ func (n *BRIEStmt) SecureText() string {
 				}
 			}
 			u.RawQuery = query.Encode()
-			redactedStorage = u.String()
+			return u.String()
 		}
 	}
+	return storage
+}
 
+// SecureText implements SensitiveStmtNode
+func (n *BRIEStmt) SecureText() string {
 	redactedStmt := &BRIEStmt{
-		Kind:    n.Kind,
-		Schemas: n.Schemas,
-		Tables:  n.Tables,
-		Storage: redactedStorage,
-		Options: n.Options,
+		Kind:           n.Kind,
+		Schemas:        n.Schemas,
+		Tables:         n.Tables,
+		Storage:        redactStorageURL(n.Storage),
+		Options:        n.Options,
+		VolumeSnapshot: n.VolumeSnapshot,
 	}
 
 	var sb strings.Builder
@@ -3353,9 +3504,24 @@ func (n *TableOptimizerHint) Restore(ctx *format.RestoreCtx) error {
 		}
 		ctx.WriteName(n.QBName.String())
 	}
+	if n.HintName.L == "qb_name" && len(n.Tables) > 0 {
+		// The view path of the query block, e.g. `qb_name(qb, v1@sel_1 .v2@sel_2 .@sel_2)`.
+		ctx.WritePlain(", ")
+		for i, table := range n.Tables {
+			if i != 0 {
+				ctx.WritePlain(" .")
+			}
+			if table.TableName.L == "" {
+				ctx.WriteKeyWord("@")
+				ctx.WriteName(table.QBName.String())
+				continue
+			}
+			table.Restore(ctx)
+		}
+	}
 	// Hints without args except query block.
 	switch n.HintName.L {
-	case "hash_agg", "stream_agg", "agg_to_cop", "read_consistent_replica", "no_index_merge", "qb_name", "ignore_plan_cache", "limit_to_cop":
+	case "hash_agg", "stream_agg", "agg_to_cop", "read_consistent_replica", "no_index_merge", "qb_name", "ignore_plan_cache", "ignore_copr_cache", "limit_to_cop":
 		ctx.WritePlain(")")
 		return nil
 	}
@@ -3368,14 +3534,14 @@ func (n *TableOptimizerHint) Restore(ctx *format.RestoreCtx) error {
 		ctx.WritePlainf("%d", n.HintData.(uint64))
 	case "nth_plan":
 		ctx.WritePlainf("%d", n.HintData.(int64))
-	case "tidb_hj", "tidb_smj", "tidb_inlj", "hash_join", "merge_join", "inl_join", "broadcast_join", "broadcast_join_local", "inl_hash_join", "inl_merge_join":
+	case "tidb_hj", "tidb_smj", "tidb_inlj", "hash_join", "merge_join", "inl_join", "broadcast_join", "broadcast_join_local", "shuffle_join", "inl_hash_join", "inl_merge_join":
 		for i, table := range n.Tables {
 			if i != 0 {
 				ctx.WritePlain(", ")
 			}
 			table.Restore(ctx)
 		}
-	case "use_index", "ignore_index", "use_index_merge", "force_index":
+	case "use_index", "ignore_index", "use_index_merge", "force_index", "access_path":
 		n.Tables[0].Restore(ctx)
 		ctx.WritePlain(" ")
 		for i, index := range n.Indexes {
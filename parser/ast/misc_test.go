@@ -219,6 +219,8 @@ func TestTableOptimizerHintRestore(t *testing.T) {
 		{"FORCE_INDEX(t1@sel_1 c1)", "FORCE_INDEX(`t1`@`sel_1` `c1`)"},
 		{"FORCE_INDEX(test.t1@sel_1 c1)", "FORCE_INDEX(`test`.`t1`@`sel_1` `c1`)"},
 		{"FORCE_INDEX(test.t1@sel_1 partition(p0) c1)", "FORCE_INDEX(`test`.`t1`@`sel_1` PARTITION(`p0`) `c1`)"},
+		{"ACCESS_PATH(t1 c2, c1)", "ACCESS_PATH(`t1` `c2`, `c1`)"},
+		{"ACCESS_PATH(@sel_1 t1 c2, PRIMARY)", "ACCESS_PATH(@`sel_1` `t1` `c2`, `PRIMARY`)"},
 		{"IGNORE_INDEX(t1 c1)", "IGNORE_INDEX(`t1` `c1`)"},
 		{"IGNORE_INDEX(@sel_1 t1 c1)", "IGNORE_INDEX(@`sel_1` `t1` `c1`)"},
 		{"IGNORE_INDEX(t1@sel_1 c1)", "IGNORE_INDEX(`t1`@`sel_1` `c1`)"},
@@ -236,6 +238,7 @@ func TestTableOptimizerHintRestore(t *testing.T) {
 		{"TIDB_HJ(t1@sel1,t2@sel2)", "TIDB_HJ(`t1`@`sel1`, `t2`@`sel2`)"},
 		{"MERGE_JOIN(t1,t2)", "MERGE_JOIN(`t1`, `t2`)"},
 		{"BROADCAST_JOIN(t1,t2)", "BROADCAST_JOIN(`t1`, `t2`)"},
+		{"SHUFFLE_JOIN(t1,t2)", "SHUFFLE_JOIN(`t1`, `t2`)"},
 		{"INL_HASH_JOIN(t1,t2)", "INL_HASH_JOIN(`t1`, `t2`)"},
 		{"INL_MERGE_JOIN(t1,t2)", "INL_MERGE_JOIN(`t1`, `t2`)"},
 		{"INL_JOIN(t1,t2)", "INL_JOIN(`t1`, `t2`)"},
@@ -270,6 +273,8 @@ func TestTableOptimizerHintRestore(t *testing.T) {
 		{"READ_CONSISTENT_REPLICA()", "READ_CONSISTENT_REPLICA()"},
 		{"READ_CONSISTENT_REPLICA(@sel1)", "READ_CONSISTENT_REPLICA(@`sel1`)"},
 		{"QB_NAME(sel1)", "QB_NAME(`sel1`)"},
+		{"QB_NAME(qb_v, v@sel_1)", "QB_NAME(`qb_v`, `v`@`sel_1`)"},
+		{"QB_NAME(qb_v, v1@sel_1 .v2@sel_2 .@sel_2)", "QB_NAME(`qb_v`, `v1`@`sel_1` .`v2`@`sel_2` .@`sel_2`)"},
 		{"READ_FROM_STORAGE(@sel TIFLASH[t1, t2])", "READ_FROM_STORAGE(@`sel` TIFLASH[`t1`, `t2`])"},
 		{"READ_FROM_STORAGE(@sel TIFLASH[t1 partition(p0)])", "READ_FROM_STORAGE(@`sel` TIFLASH[`t1` PARTITION(`p0`)])"},
 		{"TIME_RANGE('2020-02-02 10:10:10','2020-02-02 11:10:10')", "TIME_RANGE('2020-02-02 10:10:10', '2020-02-02 11:10:10')"},
@@ -33,6 +33,7 @@ type yyhintSymType struct {
 	hint        *ast.TableOptimizerHint
 	hints       []*ast.TableOptimizerHint
 	table       ast.HintTable
+	tables      []ast.HintTable
 	modelIdents []model.CIStr
 }
 
@@ -41,41 +42,43 @@ type yyhintXError struct {
 }
 
 const (
-	yyhintDefault             = 57416
+	yyhintDefault             = 57419
 	yyhintEOFCode             = 57344
 	yyhintErrCode             = 57345
+	hintAccessPath            = 57405
 	hintAggToCop              = 57377
-	hintBCJoin                = 57390
-	hintBCJoinPreferLocal     = 57391
+	hintBCJoin                = 57391
+	hintBCJoinPreferLocal     = 57392
 	hintBKA                   = 57355
 	hintBNL                   = 57357
-	hintDupsWeedOut           = 57412
-	hintFalse                 = 57408
-	hintFirstMatch            = 57413
-	hintForceIndex            = 57402
-	hintGB                    = 57411
-	hintHashAgg               = 57379
+	hintDupsWeedOut           = 57415
+	hintFalse                 = 57411
+	hintFirstMatch            = 57416
+	hintForceIndex            = 57404
+	hintGB                    = 57414
+	hintHashAgg               = 57380
 	hintHashJoin              = 57359
 	hintIdentifier            = 57347
-	hintIgnoreIndex           = 57380
+	hintIgnoreCoprCache       = 57379
+	hintIgnoreIndex           = 57381
 	hintIgnorePlanCache       = 57378
 	hintIndexMerge            = 57363
-	hintInlHashJoin           = 57381
-	hintInlJoin               = 57382
-	hintInlMergeJoin          = 57383
+	hintInlHashJoin           = 57382
+	hintInlJoin               = 57383
+	hintInlMergeJoin          = 57384
 	hintIntLit                = 57346
 	hintInvalid               = 57348
 	hintJoinFixedOrder        = 57351
 	hintJoinOrder             = 57352
 	hintJoinPrefix            = 57353
 	hintJoinSuffix            = 57354
-	hintLimitToCop            = 57401
-	hintLooseScan             = 57414
-	hintMB                    = 57410
+	hintLimitToCop            = 57403
+	hintLooseScan             = 57417
+	hintMB                    = 57413
 	hintMRR                   = 57365
-	hintMaterialization       = 57415
+	hintMaterialization       = 57418
 	hintMaxExecutionTime      = 57373
-	hintMemoryQuota           = 57384
+	hintMemoryQuota           = 57385
 	hintMerge                 = 57361
 	hintNoBKA                 = 57356
 	hintNoBNL                 = 57358
@@ -87,157 +90,164 @@ const (
 	hintNoRangeOptimization   = 57368
 	hintNoSemijoin            = 57372
 	hintNoSkipScan            = 57370
-	hintNoSwapJoinInputs      = 57385
-	hintNthPlan               = 57400
-	hintOLAP                  = 57403
-	hintOLTP                  = 57404
-	hintPartition             = 57405
+	hintNoSwapJoinInputs      = 57386
+	hintNthPlan               = 57402
+	hintOLAP                  = 57406
+	hintOLTP                  = 57407
+	hintPartition             = 57408
 	hintQBName                = 57376
-	hintQueryType             = 57386
-	hintReadConsistentReplica = 57387
-	hintReadFromStorage       = 57388
+	hintQueryType             = 57387
+	hintReadConsistentReplica = 57388
+	hintReadFromStorage       = 57389
 	hintResourceGroup         = 57375
-	hintSMJoin                = 57389
+	hintSMJoin                = 57390
 	hintSemijoin              = 57371
 	hintSetVar                = 57374
+	hintShuffleJoin           = 57393
 	hintSingleAtIdentifier    = 57349
 	hintSkipScan              = 57369
-	hintStreamAgg             = 57392
+	hintStreamAgg             = 57394
 	hintStringLit             = 57350
-	hintSwapJoinInputs        = 57393
-	hintTiFlash               = 57407
-	hintTiKV                  = 57406
-	hintTimeRange             = 57398
-	hintTrue                  = 57409
-	hintUseCascades           = 57399
-	hintUseIndex              = 57395
-	hintUseIndexMerge         = 57394
-	hintUsePlanCache          = 57396
-	hintUseToja               = 57397
+	hintSwapJoinInputs        = 57395
+	hintTiFlash               = 57410
+	hintTiKV                  = 57409
+	hintTimeRange             = 57400
+	hintTrue                  = 57412
+	hintUseCascades           = 57401
+	hintUseIndex              = 57397
+	hintUseIndexMerge         = 57396
+	hintUsePlanCache          = 57398
+	hintUseToja               = 57399
 
 	yyhintMaxDepth = 200
-	yyhintTabOfs   = -172
+	yyhintTabOfs   = -183
 )
 
 var (
 	yyhintXLAT = map[int]int{
-		41:    0,   // ')' (130x)
-		57377: 1,   // hintAggToCop (122x)
-		57390: 2,   // hintBCJoin (122x)
-		57391: 3,   // hintBCJoinPreferLocal (122x)
-		57355: 4,   // hintBKA (122x)
-		57357: 5,   // hintBNL (122x)
-		57402: 6,   // hintForceIndex (122x)
-		57379: 7,   // hintHashAgg (122x)
-		57359: 8,   // hintHashJoin (122x)
-		57380: 9,   // hintIgnoreIndex (122x)
-		57378: 10,  // hintIgnorePlanCache (122x)
-		57363: 11,  // hintIndexMerge (122x)
-		57381: 12,  // hintInlHashJoin (122x)
-		57382: 13,  // hintInlJoin (122x)
-		57383: 14,  // hintInlMergeJoin (122x)
-		57351: 15,  // hintJoinFixedOrder (122x)
-		57352: 16,  // hintJoinOrder (122x)
-		57353: 17,  // hintJoinPrefix (122x)
-		57354: 18,  // hintJoinSuffix (122x)
-		57401: 19,  // hintLimitToCop (122x)
-		57373: 20,  // hintMaxExecutionTime (122x)
-		57384: 21,  // hintMemoryQuota (122x)
-		57361: 22,  // hintMerge (122x)
-		57365: 23,  // hintMRR (122x)
-		57356: 24,  // hintNoBKA (122x)
-		57358: 25,  // hintNoBNL (122x)
-		57360: 26,  // hintNoHashJoin (122x)
-		57367: 27,  // hintNoICP (122x)
-		57364: 28,  // hintNoIndexMerge (122x)
-		57362: 29,  // hintNoMerge (122x)
-		57366: 30,  // hintNoMRR (122x)
-		57368: 31,  // hintNoRangeOptimization (122x)
-		57372: 32,  // hintNoSemijoin (122x)
-		57370: 33,  // hintNoSkipScan (122x)
-		57385: 34,  // hintNoSwapJoinInputs (122x)
-		57400: 35,  // hintNthPlan (122x)
-		57376: 36,  // hintQBName (122x)
-		57386: 37,  // hintQueryType (122x)
-		57387: 38,  // hintReadConsistentReplica (122x)
-		57388: 39,  // hintReadFromStorage (122x)
-		57375: 40,  // hintResourceGroup (122x)
-		57371: 41,  // hintSemijoin (122x)
-		57374: 42,  // hintSetVar (122x)
-		57369: 43,  // hintSkipScan (122x)
-		57389: 44,  // hintSMJoin (122x)
-		57392: 45,  // hintStreamAgg (122x)
-		57393: 46,  // hintSwapJoinInputs (122x)
-		57398: 47,  // hintTimeRange (122x)
-		57399: 48,  // hintUseCascades (122x)
-		57395: 49,  // hintUseIndex (122x)
-		57394: 50,  // hintUseIndexMerge (122x)
-		57396: 51,  // hintUsePlanCache (122x)
-		57397: 52,  // hintUseToja (122x)
-		44:    53,  // ',' (120x)
-		57412: 54,  // hintDupsWeedOut (100x)
-		57413: 55,  // hintFirstMatch (100x)
-		57414: 56,  // hintLooseScan (100x)
-		57415: 57,  // hintMaterialization (100x)
-		57407: 58,  // hintTiFlash (100x)
-		57406: 59,  // hintTiKV (100x)
-		57408: 60,  // hintFalse (99x)
-		57403: 61,  // hintOLAP (99x)
-		57404: 62,  // hintOLTP (99x)
-		57409: 63,  // hintTrue (99x)
-		57411: 64,  // hintGB (98x)
-		57410: 65,  // hintMB (98x)
-		57347: 66,  // hintIdentifier (97x)
-		57349: 67,  // hintSingleAtIdentifier (82x)
-		93:    68,  // ']' (76x)
-		57405: 69,  // hintPartition (70x)
-		46:    70,  // '.' (66x)
-		61:    71,  // '=' (66x)
-		40:    72,  // '(' (61x)
-		57344: 73,  // $end (24x)
-		57436: 74,  // QueryBlockOpt (17x)
-		57428: 75,  // Identifier (13x)
-		57346: 76,  // hintIntLit (8x)
-		57350: 77,  // hintStringLit (5x)
-		57418: 78,  // CommaOpt (4x)
-		57424: 79,  // HintTable (4x)
-		57425: 80,  // HintTableList (4x)
-		91:    81,  // '[' (3x)
-		57417: 82,  // BooleanHintName (2x)
-		57419: 83,  // HintIndexList (2x)
-		57421: 84,  // HintStorageType (2x)
-		57422: 85,  // HintStorageTypeAndTable (2x)
-		57426: 86,  // HintTableListOpt (2x)
-		57431: 87,  // JoinOrderOptimizerHintName (2x)
-		57432: 88,  // NullaryHintName (2x)
-		57435: 89,  // PartitionListOpt (2x)
-		57438: 90,  // StorageOptimizerHintOpt (2x)
-		57439: 91,  // SubqueryOptimizerHintName (2x)
-		57442: 92,  // SubqueryStrategy (2x)
-		57443: 93,  // SupportedIndexLevelOptimizerHintName (2x)
-		57444: 94,  // SupportedTableLevelOptimizerHintName (2x)
-		57445: 95,  // TableOptimizerHintOpt (2x)
-		57447: 96,  // UnsupportedIndexLevelOptimizerHintName (2x)
-		57448: 97,  // UnsupportedTableLevelOptimizerHintName (2x)
-		57420: 98,  // HintQueryType (1x)
-		57423: 99,  // HintStorageTypeAndTableList (1x)
-		57427: 100, // HintTrueOrFalse (1x)
-		57429: 101, // IndexNameList (1x)
-		57430: 102, // IndexNameListOpt (1x)
-		57433: 103, // OptimizerHintList (1x)
-		57434: 104, // PartitionList (1x)
-		57437: 105, // Start (1x)
-		57440: 106, // SubqueryStrategies (1x)
-		57441: 107, // SubqueryStrategiesOpt (1x)
-		57446: 108, // UnitOfBytes (1x)
-		57449: 109, // Value (1x)
-		57416: 110, // $default (0x)
-		57345: 111, // error (0x)
-		57348: 112, // hintInvalid (0x)
+		41:    0,   // ')' (139x)
+		57405: 1,   // hintAccessPath (128x)
+		57377: 2,   // hintAggToCop (128x)
+		57391: 3,   // hintBCJoin (128x)
+		57392: 4,   // hintBCJoinPreferLocal (128x)
+		57355: 5,   // hintBKA (128x)
+		57357: 6,   // hintBNL (128x)
+		57404: 7,   // hintForceIndex (128x)
+		57380: 8,   // hintHashAgg (128x)
+		57359: 9,   // hintHashJoin (128x)
+		57379: 10,  // hintIgnoreCoprCache (128x)
+		57381: 11,  // hintIgnoreIndex (128x)
+		57378: 12,  // hintIgnorePlanCache (128x)
+		57363: 13,  // hintIndexMerge (128x)
+		57382: 14,  // hintInlHashJoin (128x)
+		57383: 15,  // hintInlJoin (128x)
+		57384: 16,  // hintInlMergeJoin (128x)
+		57351: 17,  // hintJoinFixedOrder (128x)
+		57352: 18,  // hintJoinOrder (128x)
+		57353: 19,  // hintJoinPrefix (128x)
+		57354: 20,  // hintJoinSuffix (128x)
+		57403: 21,  // hintLimitToCop (128x)
+		57373: 22,  // hintMaxExecutionTime (128x)
+		57385: 23,  // hintMemoryQuota (128x)
+		57361: 24,  // hintMerge (128x)
+		57365: 25,  // hintMRR (128x)
+		57356: 26,  // hintNoBKA (128x)
+		57358: 27,  // hintNoBNL (128x)
+		57360: 28,  // hintNoHashJoin (128x)
+		57367: 29,  // hintNoICP (128x)
+		57364: 30,  // hintNoIndexMerge (128x)
+		57362: 31,  // hintNoMerge (128x)
+		57366: 32,  // hintNoMRR (128x)
+		57368: 33,  // hintNoRangeOptimization (128x)
+		57372: 34,  // hintNoSemijoin (128x)
+		57370: 35,  // hintNoSkipScan (128x)
+		57386: 36,  // hintNoSwapJoinInputs (128x)
+		57402: 37,  // hintNthPlan (128x)
+		57376: 38,  // hintQBName (128x)
+		57387: 39,  // hintQueryType (128x)
+		57388: 40,  // hintReadConsistentReplica (128x)
+		57389: 41,  // hintReadFromStorage (128x)
+		57375: 42,  // hintResourceGroup (128x)
+		57371: 43,  // hintSemijoin (128x)
+		57374: 44,  // hintSetVar (128x)
+		57393: 45,  // hintShuffleJoin (128x)
+		57369: 46,  // hintSkipScan (128x)
+		57390: 47,  // hintSMJoin (128x)
+		57394: 48,  // hintStreamAgg (128x)
+		57395: 49,  // hintSwapJoinInputs (128x)
+		57400: 50,  // hintTimeRange (128x)
+		57401: 51,  // hintUseCascades (128x)
+		57397: 52,  // hintUseIndex (128x)
+		57396: 53,  // hintUseIndexMerge (128x)
+		57398: 54,  // hintUsePlanCache (128x)
+		57399: 55,  // hintUseToja (128x)
+		44:    56,  // ',' (125x)
+		57415: 57,  // hintDupsWeedOut (105x)
+		57416: 58,  // hintFirstMatch (105x)
+		57417: 59,  // hintLooseScan (105x)
+		57418: 60,  // hintMaterialization (105x)
+		57410: 61,  // hintTiFlash (105x)
+		57409: 62,  // hintTiKV (105x)
+		57411: 63,  // hintFalse (104x)
+		57406: 64,  // hintOLAP (104x)
+		57407: 65,  // hintOLTP (104x)
+		57412: 66,  // hintTrue (104x)
+		57414: 67,  // hintGB (103x)
+		57413: 68,  // hintMB (103x)
+		57347: 69,  // hintIdentifier (102x)
+		57349: 70,  // hintSingleAtIdentifier (88x)
+		93:    71,  // ']' (79x)
+		46:    72,  // '.' (76x)
+		57408: 73,  // hintPartition (73x)
+		61:    74,  // '=' (69x)
+		40:    75,  // '(' (64x)
+		57344: 76,  // $end (25x)
+		57439: 77,  // QueryBlockOpt (18x)
+		57431: 78,  // Identifier (15x)
+		57346: 79,  // hintIntLit (8x)
+		57350: 80,  // hintStringLit (5x)
+		57421: 81,  // CommaOpt (4x)
+		57427: 82,  // HintTable (4x)
+		57428: 83,  // HintTableList (4x)
+		91:    84,  // '[' (3x)
+		57420: 85,  // BooleanHintName (2x)
+		57422: 86,  // HintIndexList (2x)
+		57424: 87,  // HintStorageType (2x)
+		57425: 88,  // HintStorageTypeAndTable (2x)
+		57429: 89,  // HintTableListOpt (2x)
+		57434: 90,  // JoinOrderOptimizerHintName (2x)
+		57435: 91,  // NullaryHintName (2x)
+		57438: 92,  // PartitionListOpt (2x)
+		57441: 93,  // StorageOptimizerHintOpt (2x)
+		57442: 94,  // SubqueryOptimizerHintName (2x)
+		57445: 95,  // SubqueryStrategy (2x)
+		57446: 96,  // SupportedIndexLevelOptimizerHintName (2x)
+		57447: 97,  // SupportedTableLevelOptimizerHintName (2x)
+		57448: 98,  // TableOptimizerHintOpt (2x)
+		57450: 99,  // UnsupportedIndexLevelOptimizerHintName (2x)
+		57451: 100, // UnsupportedTableLevelOptimizerHintName (2x)
+		57453: 101, // ViewName (2x)
+		57423: 102, // HintQueryType (1x)
+		57426: 103, // HintStorageTypeAndTableList (1x)
+		57430: 104, // HintTrueOrFalse (1x)
+		57432: 105, // IndexNameList (1x)
+		57433: 106, // IndexNameListOpt (1x)
+		57436: 107, // OptimizerHintList (1x)
+		57437: 108, // PartitionList (1x)
+		57440: 109, // Start (1x)
+		57443: 110, // SubqueryStrategies (1x)
+		57444: 111, // SubqueryStrategiesOpt (1x)
+		57449: 112, // UnitOfBytes (1x)
+		57452: 113, // Value (1x)
+		57454: 114, // ViewNameList (1x)
+		57419: 115, // $default (0x)
+		57345: 116, // error (0x)
+		57348: 117, // hintInvalid (0x)
 	}
 
 	yyhintSymNames = []string{
 		"')'",
+		"hintAccessPath",
 		"hintAggToCop",
 		"hintBCJoin",
 		"hintBCJoinPreferLocal",
@@ -246,6 +256,7 @@ var (
 		"hintForceIndex",
 		"hintHashAgg",
 		"hintHashJoin",
+		"hintIgnoreCoprCache",
 		"hintIgnoreIndex",
 		"hintIgnorePlanCache",
 		"hintIndexMerge",
@@ -280,6 +291,7 @@ var (
 		"hintResourceGroup",
 		"hintSemijoin",
 		"hintSetVar",
+		"hintShuffleJoin",
 		"hintSkipScan",
 		"hintSMJoin",
 		"hintStreamAgg",
@@ -306,8 +318,8 @@ var (
 		"hintIdentifier",
 		"hintSingleAtIdentifier",
 		"']'",
-		"hintPartition",
 		"'.'",
+		"hintPartition",
 		"'='",
 		"'('",
 		"$end",
@@ -335,6 +347,7 @@ var (
 		"TableOptimizerHintOpt",
 		"UnsupportedIndexLevelOptimizerHintName",
 		"UnsupportedTableLevelOptimizerHintName",
+		"ViewName",
 		"HintQueryType",
 		"HintStorageTypeAndTableList",
 		"HintTrueOrFalse",
@@ -347,6 +360,7 @@ var (
 		"SubqueryStrategiesOpt",
 		"UnitOfBytes",
 		"Value",
+		"ViewNameList",
 		"$default",
 		"error",
 		"hintInvalid",
@@ -354,65 +368,77 @@ var (
 
 	yyhintReductions = []struct{ xsym, components int }{
 		{0, 1},
-		{105, 1},
-		{103, 1},
-		{103, 3},
+		{109, 1},
+		{107, 1},
+		{107, 3},
+		{107, 1},
+		{107, 3},
+		{98, 4},
+		{98, 4},
+		{98, 4},
+		{98, 4},
+		{98, 4},
+		{98, 4},
+		{98, 5},
+		{98, 5},
+		{98, 5},
+		{98, 6},
+		{98, 4},
+		{98, 4},
+		{98, 6},
+		{98, 6},
+		{98, 6},
+		{98, 5},
+		{98, 4},
+		{98, 5},
+		{93, 5},
 		{103, 1},
 		{103, 3},
-		{95, 4},
-		{95, 4},
-		{95, 4},
-		{95, 4},
-		{95, 4},
-		{95, 4},
-		{95, 5},
-		{95, 5},
-		{95, 5},
-		{95, 6},
-		{95, 4},
-		{95, 4},
-		{95, 6},
-		{95, 6},
-		{95, 5},
-		{95, 4},
-		{95, 5},
-		{90, 5},
-		{99, 1},
-		{99, 3},
-		{85, 4},
-		{74, 0},
-		{74, 1},
-		{78, 0},
-		{78, 1},
-		{89, 0},
-		{89, 4},
-		{104, 1},
-		{104, 3},
-		{86, 1},
-		{86, 1},
-		{80, 2},
-		{80, 3},
-		{79, 3},
-		{79, 5},
-		{83, 4},
-		{102, 0},
-		{102, 1},
+		{88, 4},
+		{77, 0},
+		{77, 1},
+		{81, 0},
+		{81, 1},
+		{92, 0},
+		{92, 4},
+		{108, 1},
+		{108, 3},
+		{89, 1},
+		{89, 1},
+		{83, 2},
+		{83, 3},
+		{82, 3},
+		{82, 5},
+		{114, 1},
+		{114, 3},
+		{101, 2},
 		{101, 1},
-		{101, 3},
-		{107, 0},
-		{107, 1},
+		{86, 4},
+		{106, 0},
 		{106, 1},
-		{106, 3},
-		{109, 1},
-		{109, 1},
-		{109, 1},
-		{108, 1},
-		{108, 1},
+		{105, 1},
+		{105, 3},
+		{111, 0},
+		{111, 1},
+		{110, 1},
+		{110, 3},
+		{113, 1},
+		{113, 1},
+		{113, 1},
+		{112, 1},
+		{112, 1},
+		{104, 1},
+		{104, 1},
+		{90, 1},
+		{90, 1},
+		{90, 1},
+		{100, 1},
+		{100, 1},
+		{100, 1},
+		{100, 1},
+		{100, 1},
 		{100, 1},
 		{100, 1},
-		{87, 1},
-		{87, 1},
-		{87, 1},
 		{97, 1},
 		{97, 1},
 		{97, 1},
@@ -420,422 +446,439 @@ var (
 		{97, 1},
 		{97, 1},
 		{97, 1},
-		{94, 1},
-		{94, 1},
-		{94, 1},
-		{94, 1},
-		{94, 1},
-		{94, 1},
-		{94, 1},
-		{94, 1},
-		{94, 1},
-		{96, 1},
-		{96, 1},
+		{97, 1},
+		{97, 1},
+		{97, 1},
+		{99, 1},
+		{99, 1},
+		{99, 1},
+		{99, 1},
+		{99, 1},
+		{99, 1},
+		{99, 1},
 		{96, 1},
 		{96, 1},
 		{96, 1},
 		{96, 1},
 		{96, 1},
-		{93, 1},
-		{93, 1},
-		{93, 1},
-		{93, 1},
+		{94, 1},
+		{94, 1},
+		{95, 1},
+		{95, 1},
+		{95, 1},
+		{95, 1},
+		{85, 1},
+		{85, 1},
+		{91, 1},
+		{91, 1},
+		{91, 1},
 		{91, 1},
 		{91, 1},
-		{92, 1},
-		{92, 1},
-		{92, 1},
-		{92, 1},
-		{82, 1},
-		{82, 1},
-		{88, 1},
-		{88, 1},
-		{88, 1},
-		{88, 1},
-		{88, 1},
-		{88, 1},
-		{88, 1},
-		{88, 1},
-		{98, 1},
-		{98, 1},
-		{84, 1},
-		{84, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
-		{75, 1},
+		{91, 1},
+		{91, 1},
+		{91, 1},
+		{91, 1},
+		{102, 1},
+		{102, 1},
+		{87, 1},
+		{87, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
+		{78, 1},
 	}
 
 	yyhintXErrors = map[yyhintXError]string{}
 
-	yyhintParseTab = [255][]uint16{
+	yyhintParseTab = [270][]uint16{
 		// 0
-		{1: 232, 206, 207, 198, 200, 224, 230, 213, 222, 236, 214, 209, 208, 212, 177, 195, 196, 197, 233, 184, 189, 203, 215, 199, 201, 202, 217, 234, 204, 216, 218, 226, 220, 211, 185, 188, 193, 235, 194, 187, 225, 186, 219, 205, 231, 210, 190, 228, 221, 223, 229, 227, 82: 191, 87: 178, 192, 90: 176, 183, 93: 182, 180, 175, 181, 179, 103: 174, 105: 173},
-		{73: 172},
-		{1: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 324, 73: 171, 78: 424},
-		{1: 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 73: 170},
-		{1: 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 73: 168},
+		{1: 237, 245, 217, 218, 209, 211, 236, 243, 225, 250, 234, 249, 226, 221, 220, 224, 188, 206, 207, 208, 246, 195, 200, 214, 227, 210, 212, 213, 229, 247, 215, 228, 230, 239, 232, 223, 196, 199, 204, 248, 205, 198, 238, 197, 219, 231, 216, 244, 222, 201, 241, 233, 235, 242, 240, 85: 202, 90: 189, 203, 93: 187, 194, 96: 193, 191, 186, 192, 190, 107: 185, 109: 184},
+		{76: 183},
+		{1: 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 341, 76: 182, 81: 450},
+		{1: 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 181, 76: 181},
+		{1: 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 179, 76: 179},
 		// 5
-		{72: 421},
-		{72: 418},
-		{72: 415},
-		{72: 410},
-		{72: 407},
+		{75: 447},
+		{75: 444},
+		{75: 441},
+		{75: 436},
+		{75: 433},
 		// 10
-		{72: 396},
-		{72: 384},
-		{72: 380},
-		{72: 376},
-		{72: 368},
+		{75: 422},
+		{75: 410},
+		{75: 406},
+		{75: 402},
+		{75: 394},
 		// 15
-		{72: 365},
-		{72: 362},
-		{72: 355},
-		{72: 350},
-		{72: 344},
+		{75: 391},
+		{75: 379},
+		{75: 372},
+		{75: 367},
+		{75: 361},
 		// 20
-		{72: 341},
-		{72: 335},
-		{72: 237},
-		{72: 115},
-		{72: 114},
+		{75: 358},
+		{75: 352},
+		{75: 251},
+		{75: 121},
+		{75: 120},
 		// 25
-		{72: 113},
-		{72: 112},
-		{72: 111},
-		{72: 110},
-		{72: 109},
+		{75: 119},
+		{75: 118},
+		{75: 117},
+		{75: 116},
+		{75: 115},
 		// 30
-		{72: 108},
-		{72: 107},
-		{72: 106},
-		{72: 105},
-		{72: 104},
+		{75: 114},
+		{75: 113},
+		{75: 112},
+		{75: 111},
+		{75: 110},
 		// 35
-		{72: 103},
-		{72: 102},
-		{72: 101},
-		{72: 100},
-		{72: 99},
+		{75: 109},
+		{75: 108},
+		{75: 107},
+		{75: 106},
+		{75: 105},
 		// 40
-		{72: 98},
-		{72: 97},
-		{72: 96},
-		{72: 95},
-		{72: 94},
+		{75: 104},
+		{75: 103},
+		{75: 102},
+		{75: 101},
+		{75: 100},
 		// 45
-		{72: 93},
-		{72: 92},
-		{72: 91},
-		{72: 90},
-		{72: 89},
+		{75: 99},
+		{75: 98},
+		{75: 97},
+		{75: 96},
+		{75: 95},
 		// 50
-		{72: 88},
-		{72: 87},
-		{72: 86},
-		{72: 85},
-		{72: 84},
+		{75: 94},
+		{75: 93},
+		{75: 92},
+		{75: 91},
+		{75: 90},
 		// 55
-		{72: 79},
-		{72: 78},
-		{72: 77},
-		{72: 76},
-		{72: 75},
+		{75: 89},
+		{75: 88},
+		{75: 83},
+		{75: 82},
+		{75: 81},
 		// 60
-		{72: 74},
-		{72: 73},
-		{72: 72},
-		{72: 71},
-		{72: 70},
+		{75: 80},
+		{75: 79},
+		{75: 78},
+		{75: 77},
+		{75: 76},
 		// 65
-		{58: 145, 145, 67: 239, 74: 238},
-		{58: 244, 243, 84: 242, 241, 99: 240},
-		{144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 68: 144, 144, 76: 144},
-		{332, 53: 333},
-		{148, 53: 148},
+		{75: 75},
+		{75: 74},
+		{75: 73},
+		{61: 155, 155, 70: 253, 77: 252},
+		{61: 258, 257, 87: 256, 255, 103: 254},
 		// 70
-		{81: 245},
-		{81: 67},
-		{81: 66},
-		{1: 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 54: 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 239, 74: 247, 80: 246},
-		{53: 330, 68: 329},
+		{154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 71: 154, 154, 154, 79: 154},
+		{349, 56: 350},
+		{158, 56: 158},
+		{84: 259},
+		{84: 70},
 		// 75
-		{1: 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 249, 79: 248},
-		{135, 53: 135, 68: 135},
-		{145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 239, 145, 145, 316, 74: 315},
-		{65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65},
-		{64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64},
+		{84: 69},
+		{1: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 57: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 253, 77: 261, 83: 260},
+		{56: 347, 71: 346},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 263, 82: 262},
+		{145, 56: 145, 71: 145},
 		// 80
-		{63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63},
-		{62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62},
-		{61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61},
-		{60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60},
-		{59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59},
+		{155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 253, 155, 333, 155, 77: 332},
+		{68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68, 68},
+		{67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67, 67},
+		{66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66, 66},
+		{65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65, 65},
 		// 85
-		{58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58},
-		{57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57},
-		{56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56},
-		{55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55},
-		{54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54},
+		{64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64},
+		{63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63},
+		{62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62},
+		{61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61},
+		{60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60},
 		// 90
-		{53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53},
-		{52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52},
-		{51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51},
-		{50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50},
-		{49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49},
+		{59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59},
+		{58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58},
+		{57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57},
+		{56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56},
+		{55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55},
 		// 95
-		{48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48},
-		{47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47},
-		{46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46},
-		{45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45},
-		{44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44},
+		{54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54},
+		{53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53},
+		{52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52},
+		{51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51},
+		{50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50},
 		// 100
-		{43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43},
-		{42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42},
-		{41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41},
-		{40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40},
-		{39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39},
+		{49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49},
+		{48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48},
+		{47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47},
+		{46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46},
+		{45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45},
 		// 105
-		{38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38},
-		{37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37},
-		{36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36},
-		{35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35},
-		{34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34},
+		{44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44},
+		{43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43},
+		{42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42},
+		{41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41},
+		{40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40},
 		// 110
-		{33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33},
-		{32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32},
-		{31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31},
-		{30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
-		{29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29},
+		{39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39},
+		{38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38},
+		{37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37},
+		{36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36},
+		{35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35},
 		// 115
-		{28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
-		{27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27},
-		{26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26},
-		{25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25},
-		{24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24},
+		{34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34},
+		{33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33},
+		{32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32},
+		{31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31},
+		{30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
 		// 120
-		{23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23},
-		{22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22},
-		{21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21},
-		{20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20},
-		{19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19},
+		{29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29},
+		{28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		{27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27},
+		{26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26},
+		{25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25},
 		// 125
-		{18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18},
-		{17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17},
-		{16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16},
-		{15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15},
-		{14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14},
+		{24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24},
+		{23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23},
+		{22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22},
+		{21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21},
+		{20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20},
 		// 130
-		{13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13},
-		{12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12},
-		{11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11},
-		{10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10},
-		{9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9},
+		{19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19},
+		{18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18},
+		{17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17},
+		{16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16},
+		{15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15},
 		// 135
-		{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8},
-		{7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7},
-		{6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6},
-		{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5},
-		{4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4},
+		{14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14},
+		{13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13},
+		{12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12},
+		{11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11},
+		{10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10},
 		// 140
-		{3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3},
-		{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 68: 141, 319, 89: 328},
-		{1: 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 317},
+		{9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9},
+		{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8},
+		{7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7},
+		{6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6},
+		{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5},
 		// 145
-		{145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 239, 145, 145, 74: 318},
-		{141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 68: 141, 319, 89: 320},
-		{72: 321},
-		{132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 68: 132},
-		{1: 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 323, 104: 322},
+		{4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4},
+		{3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3},
+		{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		{151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 71: 151, 73: 336, 92: 345},
 		// 150
-		{325, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 324, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 78: 326},
-		{139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139},
-		{142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 54: 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 77: 142},
-		{140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 68: 140},
-		{1: 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 327},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 334},
+		{155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 253, 155, 73: 155, 77: 335},
+		{151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 71: 151, 73: 336, 92: 337},
+		{75: 338},
+		{142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 71: 142},
 		// 155
-		{138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138},
-		{133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 133, 68: 133},
-		{146, 53: 146},
-		{1: 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 249, 79: 331},
-		{134, 53: 134, 68: 134},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 340, 108: 339},
+		{342, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 341, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 81: 343},
+		{149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149},
+		{152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 57: 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 80: 152},
+		{150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 71: 150},
 		// 160
-		{1: 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 73: 149},
-		{58: 244, 243, 84: 242, 334},
-		{147, 53: 147},
-		{61: 145, 145, 67: 239, 74: 336},
-		{61: 338, 339, 98: 337},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 344},
+		{148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148},
+		{143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 71: 143},
+		{156, 56: 156},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 263, 82: 348},
 		// 165
-		{340},
-		{69},
-		{68},
-		{1: 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 73: 150},
-		{145, 67: 239, 74: 342},
+		{144, 56: 144, 71: 144},
+		{1: 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 76: 159},
+		{61: 258, 257, 87: 256, 351},
+		{157, 56: 157},
+		{64: 155, 155, 70: 253, 77: 353},
 		// 170
-		{343},
-		{1: 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 73: 151},
-		{60: 145, 63: 145, 67: 239, 74: 345},
-		{60: 348, 63: 347, 100: 346},
-		{349},
+		{64: 355, 356, 102: 354},
+		{357},
+		{72},
+		{71},
+		{1: 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 76: 160},
 		// 175
-		{117},
-		{116},
-		{1: 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 73: 152},
-		{77: 351},
-		{53: 324, 77: 143, 352},
+		{155, 70: 253, 77: 359},
+		{360},
+		{1: 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 76: 161},
+		{63: 155, 66: 155, 70: 253, 77: 362},
+		{63: 365, 66: 364, 104: 363},
 		// 180
-		{77: 353},
-		{354},
-		{1: 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 73: 153},
-		{67: 239, 74: 356, 76: 145},
-		{76: 357},
+		{366},
+		{123},
+		{122},
+		{1: 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 76: 162},
+		{80: 368},
 		// 185
-		{64: 360, 359, 108: 358},
-		{361},
-		{119},
-		{118},
-		{1: 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 73: 154},
+		{56: 341, 80: 153, 369},
+		{80: 370},
+		{371},
+		{1: 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 76: 163},
+		{70: 253, 77: 373, 79: 155},
 		// 190
-		{1: 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 363},
-		{364},
-		{1: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 73: 155},
-		{1: 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 366},
-		{367},
+		{79: 374},
+		{67: 377, 376, 112: 375},
+		{378},
+		{125},
+		{124},
 		// 195
-		{1: 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 73: 156},
-		{1: 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 369},
-		{71: 370},
-		{1: 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 373, 374, 372, 109: 371},
-		{375},
+		{1: 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 76: 164},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 380},
+		{381, 56: 382},
+		{1: 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 76: 166},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 386, 78: 385, 101: 384, 114: 383},
 		// 200
-		{122},
-		{121},
-		{120},
-		{1: 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 73: 157},
-		{67: 239, 74: 377, 76: 145},
+		{388, 72: 389},
+		{141, 72: 141},
+		{155, 70: 253, 72: 155, 77: 387},
+		{138, 72: 138},
+		{139, 72: 139},
 		// 205
-		{76: 378},
-		{379},
-		{1: 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 73: 158},
-		{67: 239, 74: 381, 76: 145},
-		{76: 382},
+		{1: 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 76: 165},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 386, 78: 385, 101: 390},
+		{140, 72: 140},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 392},
+		{393},
 		// 210
-		{383},
-		{1: 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 73: 159},
-		{145, 54: 145, 145, 145, 145, 67: 239, 74: 385},
-		{126, 54: 389, 390, 391, 392, 92: 388, 106: 387, 386},
-		{395},
+		{1: 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 76: 167},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 395},
+		{74: 396},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 399, 400, 398, 113: 397},
+		{401},
 		// 215
-		{125, 53: 393},
-		{124, 53: 124},
-		{83, 53: 83},
-		{82, 53: 82},
-		{81, 53: 81},
+		{128},
+		{127},
+		{126},
+		{1: 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 76: 168},
+		{70: 253, 77: 403, 79: 155},
 		// 220
-		{80, 53: 80},
-		{54: 389, 390, 391, 392, 92: 394},
-		{123, 53: 123},
-		{1: 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 73: 160},
-		{1: 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 54: 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 239, 74: 398, 83: 397},
+		{79: 404},
+		{405},
+		{1: 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 76: 169},
+		{70: 253, 77: 407, 79: 155},
+		{79: 408},
 		// 225
-		{406},
-		{1: 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 249, 79: 399},
-		{143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 324, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 78: 400},
-		{130, 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 403, 101: 402, 401},
-		{131},
+		{409},
+		{1: 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 170, 76: 170},
+		{155, 57: 155, 155, 155, 155, 70: 253, 77: 411},
+		{132, 57: 415, 416, 417, 418, 95: 414, 110: 413, 412},
+		{421},
 		// 230
-		{129, 53: 404},
-		{128, 53: 128},
-		{1: 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 405},
-		{127, 53: 127},
-		{1: 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 73: 161},
+		{131, 56: 419},
+		{130, 56: 130},
+		{87, 56: 87},
+		{86, 56: 86},
+		{85, 56: 85},
 		// 235
-		{1: 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 54: 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 239, 74: 398, 83: 408},
-		{409},
-		{1: 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 73: 162},
-		{145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 54: 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 239, 74: 413, 80: 412, 86: 411},
-		{414},
+		{84, 56: 84},
+		{57: 415, 416, 417, 418, 95: 420},
+		{129, 56: 129},
+		{1: 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 171, 76: 171},
+		{1: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 57: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 253, 77: 424, 86: 423},
 		// 240
-		{137, 53: 330},
-		{136, 277, 291, 292, 255, 257, 302, 280, 259, 281, 279, 263, 282, 283, 284, 251, 252, 253, 254, 278, 273, 285, 261, 265, 256, 258, 260, 267, 264, 262, 266, 268, 272, 270, 286, 301, 276, 287, 288, 289, 275, 271, 274, 269, 290, 293, 294, 299, 300, 296, 295, 297, 298, 54: 311, 312, 313, 314, 306, 305, 307, 303, 304, 308, 310, 309, 250, 75: 249, 79: 248},
-		{1: 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 73: 163},
-		{145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 54: 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 239, 74: 413, 80: 412, 86: 416},
-		{417},
+		{432},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 263, 82: 425},
+		{153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 341, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 81: 426},
+		{136, 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 429, 105: 428, 427},
+		{137},
 		// 245
-		{1: 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 73: 164},
-		{1: 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 54: 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 239, 74: 247, 80: 419},
-		{420, 53: 330},
-		{1: 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 73: 165},
-		{145, 67: 239, 74: 422},
+		{135, 56: 430},
+		{134, 56: 134},
+		{1: 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 431},
+		{133, 56: 133},
+		{1: 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 172, 76: 172},
 		// 250
-		{423},
-		{1: 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 73: 166},
-		{1: 232, 206, 207, 198, 200, 224, 230, 213, 222, 236, 214, 209, 208, 212, 177, 195, 196, 197, 233, 184, 189, 203, 215, 199, 201, 202, 217, 234, 204, 216, 218, 226, 220, 211, 185, 188, 193, 235, 194, 187, 225, 186, 219, 205, 231, 210, 190, 228, 221, 223, 229, 227, 82: 191, 87: 178, 192, 90: 426, 183, 93: 182, 180, 425, 181, 179},
-		{1: 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 169, 73: 169},
-		{1: 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 73: 167},
+		{1: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 57: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 253, 77: 424, 86: 434},
+		{435},
+		{1: 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 173, 76: 173},
+		{155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 57: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 253, 77: 439, 83: 438, 89: 437},
+		{440},
+		// 255
+		{147, 56: 347},
+		{146, 319, 291, 306, 307, 269, 271, 318, 295, 273, 294, 296, 293, 277, 297, 298, 299, 265, 266, 267, 268, 292, 287, 300, 275, 279, 270, 272, 274, 281, 278, 276, 280, 282, 286, 284, 301, 317, 290, 302, 303, 304, 289, 285, 288, 308, 283, 305, 309, 310, 315, 316, 312, 311, 313, 314, 57: 328, 329, 330, 331, 323, 322, 324, 320, 321, 325, 327, 326, 264, 78: 263, 82: 262},
+		{1: 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 174, 76: 174},
+		{155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 57: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 253, 77: 439, 83: 438, 89: 442},
+		{443},
+		// 260
+		{1: 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 175, 76: 175},
+		{1: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 57: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 253, 77: 261, 83: 445},
+		{446, 56: 347},
+		{1: 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 176, 76: 176},
+		{155, 70: 253, 77: 448},
+		// 265
+		{449},
+		{1: 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 76: 177},
+		{1: 237, 245, 217, 218, 209, 211, 236, 243, 225, 250, 234, 249, 226, 221, 220, 224, 188, 206, 207, 208, 246, 195, 200, 214, 227, 210, 212, 213, 229, 247, 215, 228, 230, 239, 232, 223, 196, 199, 204, 248, 205, 198, 238, 197, 219, 231, 216, 244, 222, 201, 241, 233, 235, 242, 240, 85: 202, 90: 189, 203, 93: 452, 194, 96: 193, 191, 451, 192, 190},
+		{1: 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 76: 180},
+		{1: 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 76: 178},
 	}
 )
 
@@ -875,7 +918,7 @@ func yyhintlex1(yylex yyhintLexer, lval *yyhintSymType) (n int) {
 }
 
 func yyhintParse(yylex yyhintLexer, parser *hintParser) int {
-	const yyError = 111
+	const yyError = 116
 
 	yyEx, _ := yylex.(yyhintLexerEx)
 	var yyn int
@@ -1152,6 +1195,14 @@ yynewstate:
 			}
 		}
 	case 18:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-5].ident),
+				QBName:   model.NewCIStr(yyS[yypt-3].ident),
+				Tables:   yyS[yypt-1].tables,
+			}
+		}
+	case 19:
 		{
 			maxValue := uint64(math.MaxInt64) / yyS[yypt-1].number
 			if yyS[yypt-2].number <= maxValue {
@@ -1166,7 +1217,7 @@ yynewstate:
 				parser.yyVAL.hint = nil
 			}
 		}
-	case 19:
+	case 20:
 		{
 			parser.yyVAL.hint = &ast.TableOptimizerHint{
 				HintName: model.NewCIStr(yyS[yypt-5].ident),
@@ -1176,21 +1227,21 @@ yynewstate:
 				},
 			}
 		}
-	case 20:
+	case 21:
 		{
 			h := yyS[yypt-1].hint
 			h.HintName = model.NewCIStr(yyS[yypt-4].ident)
 			h.QBName = model.NewCIStr(yyS[yypt-2].ident)
 			parser.yyVAL.hint = h
 		}
-	case 21:
+	case 22:
 		{
 			parser.yyVAL.hint = &ast.TableOptimizerHint{
 				HintName: model.NewCIStr(yyS[yypt-3].ident),
 				QBName:   model.NewCIStr(yyS[yypt-1].ident),
 			}
 		}
-	case 22:
+	case 23:
 		{
 			parser.yyVAL.hint = &ast.TableOptimizerHint{
 				HintName: model.NewCIStr(yyS[yypt-4].ident),
@@ -1198,7 +1249,7 @@ yynewstate:
 				HintData: model.NewCIStr(yyS[yypt-1].ident),
 			}
 		}
-	case 23:
+	case 24:
 		{
 			hs := yyS[yypt-1].hints
 			name := model.NewCIStr(yyS[yypt-4].ident)
@@ -1209,60 +1260,60 @@ yynewstate:
 			}
 			parser.yyVAL.hints = hs
 		}
-	case 24:
+	case 25:
 		{
 			parser.yyVAL.hints = []*ast.TableOptimizerHint{yyS[yypt-0].hint}
 		}
-	case 25:
+	case 26:
 		{
 			parser.yyVAL.hints = append(yyS[yypt-2].hints, yyS[yypt-0].hint)
 		}
-	case 26:
+	case 27:
 		{
 			h := yyS[yypt-1].hint
 			h.HintData = model.NewCIStr(yyS[yypt-3].ident)
 			parser.yyVAL.hint = h
 		}
-	case 27:
+	case 28:
 		{
 			parser.yyVAL.ident = ""
 		}
-	case 31:
+	case 32:
 		{
 			parser.yyVAL.modelIdents = nil
 		}
-	case 32:
+	case 33:
 		{
 			parser.yyVAL.modelIdents = yyS[yypt-1].modelIdents
 		}
-	case 33:
+	case 34:
 		{
 			parser.yyVAL.modelIdents = []model.CIStr{model.NewCIStr(yyS[yypt-0].ident)}
 		}
-	case 34:
+	case 35:
 		{
 			parser.yyVAL.modelIdents = append(yyS[yypt-2].modelIdents, model.NewCIStr(yyS[yypt-0].ident))
 		}
-	case 36:
+	case 37:
 		{
 			parser.yyVAL.hint = &ast.TableOptimizerHint{
 				QBName: model.NewCIStr(yyS[yypt-0].ident),
 			}
 		}
-	case 37:
+	case 38:
 		{
 			parser.yyVAL.hint = &ast.TableOptimizerHint{
 				Tables: []ast.HintTable{yyS[yypt-0].table},
 				QBName: model.NewCIStr(yyS[yypt-1].ident),
 			}
 		}
-	case 38:
+	case 39:
 		{
 			h := yyS[yypt-2].hint
 			h.Tables = append(h.Tables, yyS[yypt-0].table)
 			parser.yyVAL.hint = h
 		}
-	case 39:
+	case 40:
 		{
 			parser.yyVAL.table = ast.HintTable{
 				TableName:     model.NewCIStr(yyS[yypt-2].ident),
@@ -1270,7 +1321,7 @@ yynewstate:
 				PartitionList: yyS[yypt-0].modelIdents,
 			}
 		}
-	case 40:
+	case 41:
 		{
 			parser.yyVAL.table = ast.HintTable{
 				DBName:        model.NewCIStr(yyS[yypt-4].ident),
@@ -1279,46 +1330,67 @@ yynewstate:
 				PartitionList: yyS[yypt-0].modelIdents,
 			}
 		}
-	case 41:
+	case 42:
+		{
+			parser.yyVAL.tables = []ast.HintTable{yyS[yypt-0].table}
+		}
+	case 43:
+		{
+			parser.yyVAL.tables = append(yyS[yypt-2].tables, yyS[yypt-0].table)
+		}
+	case 44:
+		{
+			parser.yyVAL.table = ast.HintTable{
+				TableName: model.NewCIStr(yyS[yypt-1].ident),
+				QBName:    model.NewCIStr(yyS[yypt-0].ident),
+			}
+		}
+	case 45:
+		{
+			parser.yyVAL.table = ast.HintTable{
+				QBName: model.NewCIStr(yyS[yypt-0].ident),
+			}
+		}
+	case 46:
 		{
 			h := yyS[yypt-0].hint
 			h.Tables = []ast.HintTable{yyS[yypt-2].table}
 			h.QBName = model.NewCIStr(yyS[yypt-3].ident)
 			parser.yyVAL.hint = h
 		}
-	case 42:
+	case 47:
 		{
 			parser.yyVAL.hint = &ast.TableOptimizerHint{}
 		}
-	case 44:
+	case 49:
 		{
 			parser.yyVAL.hint = &ast.TableOptimizerHint{
 				Indexes: []model.CIStr{model.NewCIStr(yyS[yypt-0].ident)},
 			}
 		}
-	case 45:
+	case 50:
 		{
 			h := yyS[yypt-2].hint
 			h.Indexes = append(h.Indexes, model.NewCIStr(yyS[yypt-0].ident))
 			parser.yyVAL.hint = h
 		}
-	case 52:
+	case 57:
 		{
 			parser.yyVAL.ident = strconv.FormatUint(yyS[yypt-0].number, 10)
 		}
-	case 53:
+	case 58:
 		{
 			parser.yyVAL.number = 1024 * 1024
 		}
-	case 54:
+	case 59:
 		{
 			parser.yyVAL.number = 1024 * 1024 * 1024
 		}
-	case 55:
+	case 60:
 		{
 			parser.yyVAL.hint = &ast.TableOptimizerHint{HintData: true}
 		}
-	case 56:
+	case 61:
 		{
 			parser.yyVAL.hint = &ast.TableOptimizerHint{HintData: false}
 		}
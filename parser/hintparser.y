@@ -31,6 +31,7 @@ import (
 	hint    *ast.TableOptimizerHint
 	hints []*ast.TableOptimizerHint
 	table 	ast.HintTable
+	tables 	[]ast.HintTable
 	modelIdents []model.CIStr
 }
 
@@ -82,6 +83,7 @@ import (
 	/* TiDB hint names */
 	hintAggToCop              "AGG_TO_COP"
 	hintIgnorePlanCache       "IGNORE_PLAN_CACHE"
+	hintIgnoreCoprCache       "IGNORE_COPR_CACHE"
 	hintHashAgg               "HASH_AGG"
 	hintIgnoreIndex           "IGNORE_INDEX"
 	hintInlHashJoin           "INL_HASH_JOIN"
@@ -95,6 +97,7 @@ import (
 	hintSMJoin                "MERGE_JOIN"
 	hintBCJoin                "BROADCAST_JOIN"
 	hintBCJoinPreferLocal     "BROADCAST_JOIN_LOCAL"
+	hintShuffleJoin           "SHUFFLE_JOIN"
 	hintStreamAgg             "STREAM_AGG"
 	hintSwapJoinInputs        "SWAP_JOIN_INPUTS"
 	hintUseIndexMerge         "USE_INDEX_MERGE"
@@ -106,6 +109,7 @@ import (
 	hintNthPlan               "NTH_PLAN"
 	hintLimitToCop            "LIMIT_TO_COP"
 	hintForceIndex            "FORCE_INDEX"
+	hintAccessPath            "ACCESS_PATH"
 
 	/* Other keywords */
 	hintOLAP            "OLAP"
@@ -161,6 +165,10 @@ import (
 
 %type	<table>
 	HintTable "Table in optimizer hint"
+	ViewName  "View name in optimizer hint"
+
+%type	<tables>
+	ViewNameList "view name list in optimizer hint"
 
 %type	<modelIdents>
 	PartitionList    "partition name list in optimizer hint"
@@ -277,6 +285,14 @@ TableOptimizerHintOpt:
 			QBName:   model.NewCIStr($3),
 		}
 	}
+|	"QB_NAME" '(' Identifier ',' ViewNameList ')'
+	{
+		$$ = &ast.TableOptimizerHint{
+			HintName: model.NewCIStr($1),
+			QBName:   model.NewCIStr($3),
+			Tables:   $5,
+		}
+	}
 |	"MEMORY_QUOTA" '(' QueryBlockOpt hintIntLit UnitOfBytes ')'
 	{
 		maxValue := uint64(math.MaxInt64) / $5
@@ -439,6 +455,39 @@ HintTable:
 		}
 	}
 
+/**
+ * ViewNameList:
+ *
+ *	view_name@query_block_name [.view_name@query_block_name ...] [.@query_block_name]
+ *
+ * The path is resolved from the outermost view to the innermost one, the optional
+ * trailing query block names a query block in the innermost view.
+ */
+ViewNameList:
+	ViewName
+	{
+		$$ = []ast.HintTable{$1}
+	}
+|	ViewNameList '.' ViewName
+	{
+		$$ = append($1, $3)
+	}
+
+ViewName:
+	Identifier QueryBlockOpt
+	{
+		$$ = ast.HintTable{
+			TableName: model.NewCIStr($1),
+			QBName:    model.NewCIStr($2),
+		}
+	}
+|	hintSingleAtIdentifier
+	{
+		$$ = ast.HintTable{
+			QBName: model.NewCIStr($1),
+		}
+	}
+
 /**
  * HintIndexList:
  *
@@ -535,6 +584,7 @@ SupportedTableLevelOptimizerHintName:
 	"MERGE_JOIN"
 |	"BROADCAST_JOIN"
 |	"BROADCAST_JOIN_LOCAL"
+|	"SHUFFLE_JOIN"
 |	"INL_JOIN"
 |	"INL_HASH_JOIN"
 |	"SWAP_JOIN_INPUTS"
@@ -557,6 +607,7 @@ SupportedIndexLevelOptimizerHintName:
 |	"IGNORE_INDEX"
 |	"USE_INDEX_MERGE"
 |	"FORCE_INDEX"
+|	"ACCESS_PATH"
 
 SubqueryOptimizerHintName:
 	"SEMIJOIN"
@@ -581,6 +632,7 @@ NullaryHintName:
 |	"NO_INDEX_MERGE"
 |	"READ_CONSISTENT_REPLICA"
 |	"IGNORE_PLAN_CACHE"
+|	"IGNORE_COPR_CACHE"
 
 HintQueryType:
 	"OLAP"
@@ -623,6 +675,7 @@ Identifier:
 |	"AGG_TO_COP"
 |	"LIMIT_TO_COP"
 |	"IGNORE_PLAN_CACHE"
+|	"IGNORE_COPR_CACHE"
 |	"HASH_AGG"
 |	"IGNORE_INDEX"
 |	"INL_HASH_JOIN"
@@ -636,6 +689,7 @@ Identifier:
 |	"MERGE_JOIN"
 |	"BROADCAST_JOIN"
 |	"BROADCAST_JOIN_LOCAL"
+|	"SHUFFLE_JOIN"
 |	"STREAM_AGG"
 |	"SWAP_JOIN_INPUTS"
 |	"USE_INDEX_MERGE"
@@ -646,6 +700,7 @@ Identifier:
 |	"USE_CASCADES"
 |	"NTH_PLAN"
 |	"FORCE_INDEX"
+|	"ACCESS_PATH"
 /* other keywords */
 |	"OLAP"
 |	"OLTP"
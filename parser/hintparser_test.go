@@ -98,6 +98,24 @@ func TestParseHint(t *testing.T) {
 			input: "QB_NAME(@qb1)",
 			errs:  []string{`Optimizer hint syntax error at line 1 `},
 		},
+		{
+			input: "QB_NAME(qb_v, v1@sel_1 .v2 .@sel_2)",
+			output: []*ast.TableOptimizerHint{
+				{
+					HintName: model.NewCIStr("QB_NAME"),
+					QBName:   model.NewCIStr("qb_v"),
+					Tables: []ast.HintTable{
+						{TableName: model.NewCIStr("v1"), QBName: model.NewCIStr("sel_1")},
+						{TableName: model.NewCIStr("v2")},
+						{QBName: model.NewCIStr("sel_2")},
+					},
+				},
+			},
+		},
+		{
+			input: "QB_NAME(qb_v, )",
+			errs:  []string{`Optimizer hint syntax error at line 1 `},
+		},
 		{
 			input: "QB_NAME(b'10')",
 			errs: []string{
@@ -169,6 +187,21 @@ func TestParseHint(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: "ACCESS_PATH(t1 idx_b, idx_a, PRIMARY) access_path(@qb1 t2@qb1)",
+			output: []*ast.TableOptimizerHint{
+				{
+					HintName: model.NewCIStr("ACCESS_PATH"),
+					Tables:   []ast.HintTable{{TableName: model.NewCIStr("t1")}},
+					Indexes:  []model.CIStr{model.NewCIStr("idx_b"), model.NewCIStr("idx_a"), model.NewCIStr("PRIMARY")},
+				},
+				{
+					HintName: model.NewCIStr("access_path"),
+					Tables:   []ast.HintTable{{TableName: model.NewCIStr("t2"), QBName: model.NewCIStr("qb1")}},
+					QBName:   model.NewCIStr("qb1"),
+				},
+			},
+		},
 		{
 			input: "USE_INDEX(@qb1 tbl1 partition(p0) x) USE_INDEX_MERGE(@qb2 tbl2@qb2 partition(p0, p1) x, y, z)",
 			output: []*ast.TableOptimizerHint{
@@ -156,6 +156,7 @@ var tokenMap = map[string]int{
 	"AS":                       as,
 	"ASC":                      asc,
 	"ASCII":                    ascii,
+	"ATTRIBUTE":                attribute,
 	"ATTRIBUTES":               attributes,
 	"STATS_OPTIONS":            statsOptions,
 	"STATS_SAMPLE_RATE":        statsSampleRate,
@@ -216,6 +217,7 @@ var tokenMap = map[string]int{
 	"CLEANUP":                  cleanup,
 	"CLIENT":                   client,
 	"CLIENT_ERRORS_SUMMARY":    clientErrorsSummary,
+	"CLUSTER":                  cluster,
 	"CLUSTERED":                clustered,
 	"CMSKETCH":                 cmSketch,
 	"COALESCE":                 coalesce,
@@ -229,6 +231,7 @@ var tokenMap = map[string]int{
 	"COMMIT":                   commit,
 	"COMMITTED":                committed,
 	"COMPACT":                  compact,
+	"COMPARE":                  compare,
 	"COMPRESSED":               compressed,
 	"COMPRESSION":              compression,
 	"CONCURRENCY":              concurrency,
@@ -320,6 +323,7 @@ var tokenMap = map[string]int{
 	"EXACT":                    exact,
 	"EXCEPT":                   except,
 	"EXCHANGE":                 exchange,
+	"EXCLUDE":                  exclude,
 	"EXCLUSIVE":                exclusive,
 	"EXECUTE":                  execute,
 	"EXISTS":                   exists,
@@ -330,6 +334,7 @@ var tokenMap = map[string]int{
 	"EXTENDED":                 extended,
 	"EXTRACT":                  extract,
 	"FALSE":                    falseKwd,
+	"FAILED_LOGIN_ATTEMPTS":    failedLoginAttempts,
 	"FAULTS":                   faultsSym,
 	"FETCH":                    fetch,
 	"FIELDS":                   fields,
@@ -373,6 +378,7 @@ var tokenMap = map[string]int{
 	"IDENTIFIED":               identified,
 	"IF":                       ifKwd,
 	"IGNORE":                   ignore,
+	"ILIKE":                    ilike,
 	"IMPORT":                   importKwd,
 	"IMPORTS":                  imports,
 	"IN":                       in,
@@ -420,6 +426,7 @@ var tokenMap = map[string]int{
 	"LAST_BACKUP":              lastBackup,
 	"LAST":                     last,
 	"LASTVAL":                  lastval,
+	"LATERAL":                  lateral,
 	"LEADER":                   leader,
 	"LEADER_CONSTRAINTS":       leaderConstraints,
 	"LEADING":                  leading,
@@ -514,6 +521,7 @@ var tokenMap = map[string]int{
 	"OPTION":                   option,
 	"OPTIONAL":                 optional,
 	"OPTIONALLY":               optionally,
+	"OTHERS":                   others,
 	"OR":                       or,
 	"ORDER":                    order,
 	"OUTER":                    outer,
@@ -526,6 +534,7 @@ var tokenMap = map[string]int{
 	"PARTITIONING":             partitioning,
 	"PARTITIONS":               partitions,
 	"PASSWORD":                 password,
+	"PASSWORD_LOCK_TIME":       passwordLockTime,
 	"PERCENT":                  percent,
 	"PER_DB":                   per_db,
 	"PER_TABLE":                per_table,
@@ -586,6 +595,7 @@ var tokenMap = map[string]int{
 	"REQUIRE":                  require,
 	"REQUIRED":                 required,
 	"RESET":                    reset,
+	"RESOURCE":                 resource,
 	"RESPECT":                  respect,
 	"RESTART":                  restart,
 	"RESTORE":                  restore,
@@ -619,6 +629,7 @@ var tokenMap = map[string]int{
 	"SECONDARY_UNLOAD":         secondaryUnload,
 	"SECURITY":                 security,
 	"SELECT":                   selectKwd,
+	"SELECTIVITY":              selectivityType,
 	"SEND_CREDENTIALS_TO_TIKV": sendCredentialsToTiKV,
 	"SEPARATOR":                separator,
 	"SEQUENCE":                 sequence,
@@ -645,6 +656,7 @@ var tokenMap = map[string]int{
 	"SPATIAL":                  spatial,
 	"SPLIT":                    split,
 	"SQL_BIG_RESULT":           sqlBigResult,
+	"SQL_BLOCKLIST":            sqlBlocklist,
 	"SQL_BUFFER_RESULT":        sqlBufferResult,
 	"SQL_CACHE":                sqlCache,
 	"SQL_CALC_FOUND_ROWS":      sqlCalcFoundRows,
@@ -712,6 +724,7 @@ var tokenMap = map[string]int{
 	"TERMINATED":               terminated,
 	"TEXT":                     textType,
 	"THAN":                     than,
+	"TIES":                     ties,
 	"THEN":                     then,
 	"TIDB":                     tidb,
 	"TIFLASH":                  tiFlash,
@@ -906,6 +919,7 @@ var hintTokenMap = map[string]int{
 	"AGG_TO_COP":              hintAggToCop,
 	"LIMIT_TO_COP":            hintLimitToCop,
 	"IGNORE_PLAN_CACHE":       hintIgnorePlanCache,
+	"IGNORE_COPR_CACHE":       hintIgnoreCoprCache,
 	"HASH_AGG":                hintHashAgg,
 	"IGNORE_INDEX":            hintIgnoreIndex,
 	"INL_HASH_JOIN":           hintInlHashJoin,
@@ -918,6 +932,7 @@ var hintTokenMap = map[string]int{
 	"READ_FROM_STORAGE":       hintReadFromStorage,
 	"BROADCAST_JOIN":          hintBCJoin,
 	"BROADCAST_JOIN_LOCAL":    hintBCJoinPreferLocal,
+	"SHUFFLE_JOIN":            hintShuffleJoin,
 	"MERGE_JOIN":              hintSMJoin,
 	"STREAM_AGG":              hintStreamAgg,
 	"SWAP_JOIN_INPUTS":        hintSwapJoinInputs,
@@ -929,6 +944,7 @@ var hintTokenMap = map[string]int{
 	"USE_CASCADES":            hintUseCascades,
 	"NTH_PLAN":                hintNthPlan,
 	"FORCE_INDEX":             hintForceIndex,
+	"ACCESS_PATH":             hintAccessPath,
 
 	// TiDB hint aliases
 	"TIDB_HJ":   hintHashJoin,
@@ -95,6 +95,11 @@ const (
 	ActionAlterTableStatsOptions        ActionType = 58
 	ActionAlterNoCacheTable             ActionType = 59
 	ActionCreateTables                  ActionType = 60
+	ActionAlterTableClusteredIndex      ActionType = 61
+
+	// The action types from 101 on are only known by this project, they are kept away from the
+	// ones defined by the upstream, so the tools decoding the DDL jobs never mistake them.
+	ActionCreateTableAsSelect ActionType = 101
 )
 
 var actionMap = map[ActionType]string{
@@ -157,6 +162,8 @@ var actionMap = map[ActionType]string{
 	ActionAlterCacheTable:               "alter table cache",
 	ActionAlterNoCacheTable:             "alter table nocache",
 	ActionAlterTableStatsOptions:        "alter table statistics options",
+	ActionAlterTableClusteredIndex:      "alter table clustered index",
+	ActionCreateTableAsSelect:           "create table as select",
 
 	// `ActionAlterTableAlterPartition` is removed and will never be used.
 	// Just left a tombstone here for compatibility.
@@ -288,8 +295,12 @@ type Job struct {
 	// MultiSchemaInfo keeps some warning now for multi schema change.
 	MultiSchemaInfo *MultiSchemaInfo `json:"multi_schema_info"`
 
-	// Priority is only used to set the operation priority of adding indices.
+	// Priority is the operation priority of adding indices, and the queued job with
+	// the higher priority runs earlier if it doesn't depend on the jobs queued before it.
 	Priority int `json:"priority"`
+
+	// Paused means the job is paused by the user and won't be run until it is resumed.
+	Paused bool `json:"paused"`
 }
 
 // FinishTableJob is called when a job is finished.
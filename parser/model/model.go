@@ -353,6 +353,11 @@ type TableInfo struct {
 
 	// StatsOptions is used when do analyze/auto-analyze for each table
 	StatsOptions *StatsOptions `json:"stats_options"`
+
+	// ChangingTable is the table info with the target primary key layout when
+	// the clustered index of the table is being changed. Its State tells which
+	// writes it receives, and its ID is the ID the table uses after the change.
+	ChangingTable *TableInfo `json:"changing_table,omitempty"`
 }
 type TableCacheStatusType int
 
@@ -490,6 +495,7 @@ type TiFlashReplicaInfo struct {
 	LocationLabels        []string
 	Available             bool
 	AvailablePartitionIDs []int64
+	HighPriority          bool
 }
 
 // IsPartitionAvailable checks whether the partition table replica was available.
@@ -543,6 +549,10 @@ func (t *TableInfo) Clone() *TableInfo {
 		nt.ForeignKeys[i] = t.ForeignKeys[i].Clone()
 	}
 
+	if t.ChangingTable != nil {
+		nt.ChangingTable = t.ChangingTable.Clone()
+	}
+
 	return &nt
 }
 
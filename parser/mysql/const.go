@@ -155,6 +155,18 @@ const (
 	ClientPluginAuth
 	ClientConnectAtts
 	ClientPluginAuthLenencClientData
+	ClientCanHandleExpiredPasswords
+	ClientSessionTrack
+	ClientDeprecateEOF
+	ClientOptionalResultsetMetadata
+	ClientZstdCompressionAlgorithm
+	ClientQueryAttributes
+)
+
+// The metadata_follows flag of result sets, it is sent when the client supports ClientOptionalResultsetMetadata.
+const (
+	ResultsetMetadataNone byte = 0
+	ResultsetMetadataFull byte = 1
 )
 
 // Cache type information.
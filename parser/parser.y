@@ -171,6 +171,7 @@ import (
 	kill              "KILL"
 	lag               "LAG"
 	lastValue         "LAST_VALUE"
+	lateral           "LATERAL"
 	lead              "LEAD"
 	leading           "LEADING"
 	left              "LEFT"
@@ -297,6 +298,7 @@ import (
 	always                "ALWAYS"
 	any                   "ANY"
 	ascii                 "ASCII"
+	attribute             "ATTRIBUTE"
 	attributes            "ATTRIBUTES"
 	statsOptions          "STATS_OPTIONS"
 	statsSampleRate       "STATS_SAMPLE_RATE"
@@ -334,6 +336,7 @@ import (
 	cleanup               "CLEANUP"
 	client                "CLIENT"
 	clientErrorsSummary   "CLIENT_ERRORS_SUMMARY"
+	cluster               "CLUSTER"
 	coalesce              "COALESCE"
 	collation             "COLLATION"
 	columnFormat          "COLUMN_FORMAT"
@@ -343,6 +346,7 @@ import (
 	commit                "COMMIT"
 	committed             "COMMITTED"
 	compact               "COMPACT"
+	compare               "COMPARE"
 	compressed            "COMPRESSED"
 	compression           "COMPRESSION"
 	concurrency           "CONCURRENCY"
@@ -388,11 +392,13 @@ import (
 	events                "EVENTS"
 	evolve                "EVOLVE"
 	exchange              "EXCHANGE"
+	exclude               "EXCLUDE"
 	exclusive             "EXCLUSIVE"
 	execute               "EXECUTE"
 	expansion             "EXPANSION"
 	expire                "EXPIRE"
 	extended              "EXTENDED"
+	failedLoginAttempts   "FAILED_LOGIN_ATTEMPTS"
 	faultsSym             "FAULTS"
 	fields                "FIELDS"
 	file                  "FILE"
@@ -483,6 +489,7 @@ import (
 	only                  "ONLY"
 	open                  "OPEN"
 	optional              "OPTIONAL"
+	others                "OTHERS"
 	packKeys              "PACK_KEYS"
 	pageSym               "PAGE"
 	parser                "PARSER"
@@ -490,6 +497,7 @@ import (
 	partitioning          "PARTITIONING"
 	partitions            "PARTITIONS"
 	password              "PASSWORD"
+	passwordLockTime      "PASSWORD_LOCK_TIME"
 	percent               "PERCENT"
 	per_db                "PER_DB"
 	per_table             "PER_TABLE"
@@ -524,6 +532,7 @@ import (
 	replicas              "REPLICAS"
 	replication           "REPLICATION"
 	required              "REQUIRED"
+	resource              "RESOURCE"
 	respect               "RESPECT"
 	restart               "RESTART"
 	restore               "RESTORE"
@@ -595,6 +604,7 @@ import (
 	temptable             "TEMPTABLE"
 	textType              "TEXT"
 	than                  "THAN"
+	ties                  "TIES"
 	tikvImporter          "TIKV_IMPORTER"
 	timestampType         "TIMESTAMP"
 	timeType              "TIME"
@@ -650,6 +660,7 @@ import (
 	getFormat             "GET_FORMAT"
 	groupConcat           "GROUP_CONCAT"
 	next_row_id           "NEXT_ROW_ID"
+	ilike                 "ILIKE"
 	inplace               "INPLACE"
 	instant               "INSTANT"
 	internal              "INTERNAL"
@@ -675,6 +686,8 @@ import (
 	running               "RUNNING"
 	s3                    "S3"
 	schedule              "SCHEDULE"
+	selectivityType       "SELECTIVITY"
+	sqlBlocklist          "SQL_BLOCKLIST"
 	staleness             "STALENESS"
 	std                   "STD"
 	stddev                "STDDEV"
@@ -935,6 +948,7 @@ import (
 	HelpStmt                   "HELP statement"
 
 %type	<item>
+	AdminChecksumIndexesOpt                "Optional WITH INDEXES of ADMIN CHECKSUM TABLE"
 	AdminShowSlow                          "Admin Show Slow statement"
 	AllOrPartitionNameList                 "All or partition name list"
 	AlgorithmClause                        "Alter table algorithm"
@@ -1102,6 +1116,7 @@ import (
 	PasswordExpire                         "Single password option for create user statement"
 	PasswordOrLockOption                   "Single password or lock option for create user statement"
 	PasswordOrLockOptionList               "Password or lock options for create user statement"
+	CommentOrAttributeOption               "Optional comment or attribute option for CREATE/ALTER USER"
 	PasswordOrLockOptions                  "Optional password or lock options for create user statement"
 	ColumnPosition                         "Column position [First|After ColumnName]"
 	PrepareSQL                             "Prepare statement sql string"
@@ -1197,6 +1212,7 @@ import (
 	TextStringList                         "text string list"
 	TimeUnit                               "Time unit for 'DATE_ADD', 'DATE_SUB', 'ADDDATE', 'SUBDATE', 'EXTRACT'"
 	TimestampUnit                          "Time unit for 'TIMESTAMPADD' and 'TIMESTAMPDIFF'"
+	TiFlashReplicaPriorityOpt              "optional TiFlash replica priority"
 	LockType                               "Table locks type"
 	TransactionChar                        "Transaction characteristic"
 	TransactionChars                       "Transaction characteristic list"
@@ -1245,6 +1261,7 @@ import (
 	OptWild                                "Optional Wildcard"
 	OptWindowOrderByClause                 "Optional ORDER BY clause in WINDOW"
 	OptWindowFrameClause                   "Optional FRAME clause in WINDOW"
+	OptWindowFrameExclusion                "Optional EXCLUDE clause in window FRAME"
 	OptWindowingClause                     "Optional OVER clause"
 	WindowingClause                        "OVER clause"
 	WindowClauseOptional                   "Optional WINDOW clause"
@@ -1263,6 +1280,7 @@ import (
 	IsOrNotOp                              "Is predicate"
 	InOrNotOp                              "In predicate"
 	LikeOrNotOp                            "Like predicate"
+	IlikeOrNotOp                           "Ilike predicate"
 	RegexpOrNotOp                          "Regexp predicate"
 	NumericType                            "Numeric types"
 	IntegerType                            "Integer Types types"
@@ -1441,7 +1459,7 @@ import (
 %left andand and
 %left between
 %precedence lowerThanEq
-%left eq ge le neq neqSynonym '>' '<' is like in
+%left eq ge le neq neqSynonym '>' '<' is like ilike in
 %left '|'
 %left '&'
 %left rsh lsh
@@ -1651,6 +1669,15 @@ LocationLabelList:
 		$$ = $3
 	}
 
+TiFlashReplicaPriorityOpt:
+	{
+		$$ = false
+	}
+|	"HIGH_PRIORITY"
+	{
+		$$ = true
+	}
+
 AlterTableSpec:
 	TableOptionList %prec higherThanComma
 	{
@@ -1659,11 +1686,12 @@ AlterTableSpec:
 			Options: $1.([]*ast.TableOption),
 		}
 	}
-|	"SET" "TIFLASH" "REPLICA" LengthNum LocationLabelList
+|	"SET" "TIFLASH" "REPLICA" LengthNum LocationLabelList TiFlashReplicaPriorityOpt
 	{
 		tiflashReplicaSpec := &ast.TiFlashReplicaSpec{
-			Count:  $4.(uint64),
-			Labels: $5.([]string),
+			Count:        $4.(uint64),
+			Labels:       $5.([]string),
+			HighPriority: $6.(bool),
 		}
 		$$ = &ast.AlterTableSpec{
 			Tp:             ast.AlterTableSetTiFlashReplica,
@@ -2174,6 +2202,13 @@ AlterTableSpec:
 			Visibility: $4.(ast.IndexVisibility),
 		}
 	}
+|	"ALTER" "PRIMARY" "KEY" WithClustered
+	{
+		$$ = &ast.AlterTableSpec{
+			Tp:           ast.AlterTableClusteredIndex,
+			PrimaryKeyTp: $4.(model.PrimaryKeyType),
+		}
+	}
 // 	Support caching or non-caching a table in memory for tidb, It can be found in the official Oracle document, see: https://docs.oracle.com/database/121/SQLRF/statements_3001.htm
 |	"CACHE"
 	{
@@ -4591,6 +4626,13 @@ ExplainStmt:
 			Format: "row",
 		}
 	}
+|	ExplainSym "SELECTIVITY" ExplainableStmt
+	{
+		$$ = &ast.ExplainStmt{
+			Stmt:   $3,
+			Format: "selectivity",
+		}
+	}
 |	ExplainSym "FOR" "CONNECTION" NUM
 	{
 		$$ = &ast.ExplainForStmt{
@@ -4634,6 +4676,22 @@ ExplainStmt:
 			Analyze: true,
 		}
 	}
+|	ExplainSym "ANALYZE" "FORMAT" "=" ExplainFormatType ExplainableStmt
+	{
+		$$ = &ast.ExplainStmt{
+			Stmt:    $6,
+			Format:  $5,
+			Analyze: true,
+		}
+	}
+|	ExplainSym "ANALYZE" "FORMAT" "=" stringLit ExplainableStmt
+	{
+		$$ = &ast.ExplainStmt{
+			Stmt:    $6,
+			Format:  $5,
+			Analyze: true,
+		}
+	}
 
 ExplainFormatType:
 	"TRADITIONAL"
@@ -4642,6 +4700,7 @@ ExplainFormatType:
 |	"DOT"
 |	"BRIEF"
 |	"VERBOSE"
+|	"SELECTIVITY"
 
 /*******************************************************************
  * Backup / restore / import statements
@@ -4650,6 +4709,7 @@ ExplainFormatType:
  *	BACKUP TABLE [ db1.tbl1, db2.tbl2 ] TO 'scheme://location' [ options... ]
  *	RESTORE DATABASE [ * | db1, db2, db3 ] FROM 'scheme://location' [ options... ]
  *	RESTORE TABLE [ db1.tbl1, db2.tbl2 ] FROM 'scheme://location' [ options... ]
+ *	BACKUP CLUSTER USING SNAPSHOT TO 'scheme://location' [ options... ]
  */
 BRIEStmt:
 	"BACKUP" BRIETables "TO" stringLit BRIEOptions
@@ -4660,6 +4720,15 @@ BRIEStmt:
 		stmt.Options = $5.([]*ast.BRIEOption)
 		$$ = stmt
 	}
+|	"BACKUP" "CLUSTER" "USING" "SNAPSHOT" "TO" stringLit BRIEOptions
+	{
+		$$ = &ast.BRIEStmt{
+			Kind:           ast.BRIEKindBackup,
+			VolumeSnapshot: true,
+			Storage:        $6,
+			Options:        $7.([]*ast.BRIEOption),
+		}
+	}
 |	"RESTORE" BRIETables "FROM" stringLit BRIEOptions
 	{
 		stmt := $2.(*ast.BRIEStmt)
@@ -5355,6 +5424,16 @@ LikeOrNotOp:
 		$$ = false
 	}
 
+IlikeOrNotOp:
+	"ILIKE"
+	{
+		$$ = true
+	}
+|	NotSym "ILIKE"
+	{
+		$$ = false
+	}
+
 RegexpOrNotOp:
 	RegexpSym
 	{
@@ -5415,11 +5494,28 @@ PredicateExpr:
 			Escape:  escape[0],
 		}
 	}
+|	BitExpr IlikeOrNotOp SimpleExpr LikeEscapeOpt
+	{
+		escape := $4
+		if len(escape) > 1 {
+			yylex.AppendError(ErrWrongArguments.GenWithStackByArgs("ESCAPE"))
+			return 1
+		} else if len(escape) == 0 {
+			escape = "\\"
+		}
+		$$ = &ast.PatternLikeExpr{
+			Expr:    $1,
+			Pattern: $3,
+			Not:     !$2.(bool),
+			Escape:  escape[0],
+			IsILike: true,
+		}
+	}
 |	BitExpr RegexpOrNotOp SimpleExpr
 	{
 		$$ = &ast.PatternRegexpExpr{Expr: $1, Pattern: $3, Not: !$2.(bool)}
 	}
-|	BitExpr
+|	BitExpr %prec lowerThanEq
 
 RegexpSym:
 	"REGEXP"
@@ -5721,6 +5817,7 @@ UnReservedKeyword:
 	"ACTION"
 |	"ADVISE"
 |	"ASCII"
+|	"ATTRIBUTE"
 |	"ATTRIBUTES"
 |	"STATS_OPTIONS"
 |	"STATS_SAMPLE_RATE"
@@ -5747,6 +5844,7 @@ UnReservedKeyword:
 |	"SAN"
 |	"COMMIT"
 |	"COMPACT"
+|	"COMPARE"
 |	"COMPRESSED"
 |	"CONSISTENCY"
 |	"CONSISTENT"
@@ -5939,6 +6037,8 @@ UnReservedKeyword:
 |	"SWITCHES"
 |	"PAGE"
 |	"FAULTS"
+|	"FAILED_LOGIN_ATTEMPTS"
+|	"PASSWORD_LOCK_TIME"
 |	"IPC"
 |	"SWAPS"
 |	"SOURCE"
@@ -5988,6 +6088,7 @@ UnReservedKeyword:
 |	"NOWAIT"
 |	"INSTANCE"
 |	"REPLICA"
+|	"RESOURCE"
 |	"LOCATION"
 |	"LABELS"
 |	"LOGS"
@@ -6049,9 +6150,13 @@ UnReservedKeyword:
 |	"PURGE"
 |	"SKIP"
 |	"LOCKED"
+|	"CLUSTER"
 |	"CLUSTERED"
 |	"NONCLUSTERED"
 |	"PRESERVE"
+|	"EXCLUDE"
+|	"OTHERS"
+|	"TIES"
 
 TiDBKeyword:
 	"ADMIN"
@@ -6112,6 +6217,7 @@ NotKeywordToken:
 |	"EXTRACT"
 |	"GET_FORMAT"
 |	"GROUP_CONCAT"
+|	"ILIKE"
 |	"INPLACE"
 |	"INSTANT"
 |	"INTERNAL"
@@ -6155,6 +6261,7 @@ NotKeywordToken:
 |	"NEXT_ROW_ID"
 |	"EXPR_PUSHDOWN_BLACKLIST"
 |	"OPT_RULE_BLACKLIST"
+|	"SQL_BLOCKLIST"
 |	"BOUND"
 |	"EXACT" %prec lowerThanStringLitToken
 |	"STALENESS"
@@ -6169,6 +6276,7 @@ NotKeywordToken:
 |	"LEARNER"
 |	"LEARNERS"
 |	"VERBOSE"
+|	"SELECTIVITY"
 |	"VOTER"
 |	"VOTERS"
 |	"CONSTRAINTS"
@@ -8448,14 +8556,36 @@ OptWindowFrameClause:
 	{
 		$$ = nil
 	}
-|	WindowFrameUnits WindowFrameExtent
+|	WindowFrameUnits WindowFrameExtent OptWindowFrameExclusion
 	{
 		$$ = &ast.FrameClause{
-			Type:   $1.(ast.FrameType),
-			Extent: $2.(ast.FrameExtent),
+			Type:      $1.(ast.FrameType),
+			Extent:    $2.(ast.FrameExtent),
+			Exclusion: $3.(ast.FrameExclusion),
 		}
 	}
 
+OptWindowFrameExclusion:
+	{
+		$$ = ast.ExcludeNone
+	}
+|	"EXCLUDE" "CURRENT" "ROW"
+	{
+		$$ = ast.ExcludeCurrentRow
+	}
+|	"EXCLUDE" "GROUP"
+	{
+		$$ = ast.ExcludeGroup
+	}
+|	"EXCLUDE" "TIES"
+	{
+		$$ = ast.ExcludeTies
+	}
+|	"EXCLUDE" "NO" "OTHERS"
+	{
+		$$ = ast.ExcludeNoOthers
+	}
+
 WindowFrameUnits:
 	"ROWS"
 	{
@@ -8716,6 +8846,11 @@ TableFactor:
 		resultNode := $1.(*ast.SubqueryExpr).Query
 		$$ = &ast.TableSource{Source: resultNode, AsName: $2.(model.CIStr)}
 	}
+|	"LATERAL" SubSelect TableAsNameOpt
+	{
+		resultNode := $2.(*ast.SubqueryExpr).Query
+		$$ = &ast.TableSource{Source: resultNode, AsName: $3.(model.CIStr), Lateral: true}
+	}
 |	'(' TableRefs ')'
 	{
 		j := $2.(*ast.Join)
@@ -9966,15 +10101,18 @@ AdminStmt:
 	{
 		$$ = &ast.AdminStmt{Tp: ast.AdminShowDDL}
 	}
-|	"ADMIN" "SHOW" "DDL" "JOBS" WhereClauseOptional
+|	"ADMIN" "SHOW" "DDL" "JOBS" WhereClauseOptional SelectStmtLimitOpt
 	{
 		stmt := &ast.AdminStmt{Tp: ast.AdminShowDDLJobs}
 		if $5 != nil {
 			stmt.Where = $5.(ast.ExprNode)
 		}
+		if $6 != nil {
+			stmt.Limit = $6.(*ast.Limit)
+		}
 		$$ = stmt
 	}
-|	"ADMIN" "SHOW" "DDL" "JOBS" Int64Num WhereClauseOptional
+|	"ADMIN" "SHOW" "DDL" "JOBS" Int64Num WhereClauseOptional SelectStmtLimitOpt
 	{
 		stmt := &ast.AdminStmt{
 			Tp:        ast.AdminShowDDLJobs,
@@ -9983,6 +10121,9 @@ AdminStmt:
 		if $6 != nil {
 			stmt.Where = $6.(ast.ExprNode)
 		}
+		if $7 != nil {
+			stmt.Limit = $7.(*ast.Limit)
+		}
 		$$ = stmt
 	}
 |	"ADMIN" "SHOW" TableName "NEXT_ROW_ID"
@@ -10032,12 +10173,29 @@ AdminStmt:
 			HandleRanges: $6.([]ast.HandleRange),
 		}
 	}
-|	"ADMIN" "CHECKSUM" "TABLE" TableNameList
+|	"ADMIN" "CHECKSUM" "TABLE" TableNameList AsOfClauseOpt AdminChecksumIndexesOpt
 	{
-		$$ = &ast.AdminStmt{
-			Tp:     ast.AdminChecksumTable,
-			Tables: $4.([]*ast.TableName),
+		stmt := &ast.AdminStmt{
+			Tp:              ast.AdminChecksumTable,
+			Tables:          $4.([]*ast.TableName),
+			ChecksumIndexes: $6.(bool),
+		}
+		if $5 != nil {
+			stmt.AsOf = $5.(*ast.AsOfClause)
+		}
+		$$ = stmt
+	}
+|	"ADMIN" "COMPARE" "CHECKSUM" "TABLE" TableNameList AsOfClauseOpt "USING" TableName
+	{
+		stmt := &ast.AdminStmt{
+			Tp:                  ast.AdminCompareChecksumTable,
+			Tables:              $5.([]*ast.TableName),
+			ChecksumResultTable: $8.(*ast.TableName),
 		}
+		if $6 != nil {
+			stmt.AsOf = $6.(*ast.AsOfClause)
+		}
+		$$ = stmt
 	}
 |	"ADMIN" "CANCEL" "DDL" "JOBS" NumList
 	{
@@ -10072,6 +10230,19 @@ AdminStmt:
 			Tp: ast.AdminReloadOptRuleBlacklist,
 		}
 	}
+|	"ADMIN" "RELOAD" "SQL_BLOCKLIST"
+	{
+		$$ = &ast.AdminStmt{
+			Tp: ast.AdminReloadSQLBlocklist,
+		}
+	}
+|	"ADMIN" "KILL" "TOP" "MEMORY" Int64Num
+	{
+		$$ = &ast.AdminStmt{
+			Tp:   ast.AdminKillTopMemory,
+			TopN: $5.(int64),
+		}
+	}
 |	"ADMIN" "PLUGINS" "ENABLE" PluginNameList
 	{
 		$$ = &ast.AdminStmt{
@@ -10155,6 +10326,15 @@ AdminStmt:
 		}
 	}
 
+AdminChecksumIndexesOpt:
+	{
+		$$ = false
+	}
+|	"WITH" "INDEXES"
+	{
+		$$ = true
+	}
+
 AdminShowSlow:
 	"RECENT" NUM
 	{
@@ -10279,7 +10459,7 @@ ShowStmt:
 			DBName: $4, // we reuse DBName of ShowStmt
 		}
 	}
-|	"SHOW" "TABLE" TableName PartitionNameListOpt "REGIONS" WhereClauseOptional
+|	"SHOW" "TABLE" TableName PartitionNameListOpt "REGIONS" WhereClauseOptional SelectStmtLimitOpt
 	{
 		stmt := &ast.ShowStmt{
 			Tp:    ast.ShowRegions,
@@ -10289,6 +10469,9 @@ ShowStmt:
 		if $6 != nil {
 			stmt.Where = $6.(ast.ExprNode)
 		}
+		if $7 != nil {
+			stmt.Limit = $7.(*ast.Limit)
+		}
 		$$ = stmt
 	}
 |	"SHOW" "TABLE" TableName "NEXT_ROW_ID"
@@ -10298,7 +10481,7 @@ ShowStmt:
 			Table: $3.(*ast.TableName),
 		}
 	}
-|	"SHOW" "TABLE" TableName PartitionNameListOpt "INDEX" Identifier "REGIONS" WhereClauseOptional
+|	"SHOW" "TABLE" TableName PartitionNameListOpt "INDEX" Identifier "REGIONS" WhereClauseOptional SelectStmtLimitOpt
 	{
 		stmt := &ast.ShowStmt{
 			Tp:        ast.ShowRegions,
@@ -10309,6 +10492,9 @@ ShowStmt:
 		if $8 != nil {
 			stmt.Where = $8.(ast.ExprNode)
 		}
+		if $9 != nil {
+			stmt.Limit = $9.(*ast.Limit)
+		}
 		$$ = stmt
 	}
 |	"SHOW" "GRANTS"
@@ -12175,10 +12361,10 @@ CommaOpt:
  *  https://dev.mysql.com/doc/refman/5.7/en/account-management-sql.html
  ************************************************************************************/
 CreateUserStmt:
-	"CREATE" "USER" IfNotExists UserSpecList RequireClauseOpt ConnectionOptions PasswordOrLockOptions
+	"CREATE" "USER" IfNotExists UserSpecList RequireClauseOpt ConnectionOptions PasswordOrLockOptions CommentOrAttributeOption
 	{
-		// See https://dev.mysql.com/doc/refman/5.7/en/create-user.html
-		$$ = &ast.CreateUserStmt{
+		// See https://dev.mysql.com/doc/refman/8.0/en/create-user.html
+		ret := &ast.CreateUserStmt{
 			IsCreateRole:          false,
 			IfNotExists:           $3.(bool),
 			Specs:                 $4.([]*ast.UserSpec),
@@ -12186,6 +12372,10 @@ CreateUserStmt:
 			ResourceOptions:       $6.([]*ast.ResourceOption),
 			PasswordOrLockOptions: $7.([]*ast.PasswordOrLockOption),
 		}
+		if $8 != nil {
+			ret.CommentOrAttributeOption = $8.(*ast.CommentOrAttributeOption)
+		}
+		$$ = ret
 	}
 
 CreateRoleStmt:
@@ -12201,15 +12391,19 @@ CreateRoleStmt:
 
 /* See http://dev.mysql.com/doc/refman/5.7/en/alter-user.html */
 AlterUserStmt:
-	"ALTER" "USER" IfExists UserSpecList RequireClauseOpt ConnectionOptions PasswordOrLockOptions
+	"ALTER" "USER" IfExists UserSpecList RequireClauseOpt ConnectionOptions PasswordOrLockOptions CommentOrAttributeOption
 	{
-		$$ = &ast.AlterUserStmt{
+		ret := &ast.AlterUserStmt{
 			IfExists:              $3.(bool),
 			Specs:                 $4.([]*ast.UserSpec),
 			TLSOptions:            $5.([]*ast.TLSOption),
 			ResourceOptions:       $6.([]*ast.ResourceOption),
 			PasswordOrLockOptions: $7.([]*ast.PasswordOrLockOption),
 		}
+		if $8 != nil {
+			ret.CommentOrAttributeOption = $8.(*ast.CommentOrAttributeOption)
+		}
+		$$ = ret
 	}
 |	"ALTER" "USER" IfExists "USER" '(' ')' "IDENTIFIED" "BY" AuthString
 	{
@@ -12275,8 +12469,6 @@ ConnectionOptions:
 |	"WITH" ConnectionOptionList
 	{
 		$$ = $2
-		yylex.AppendError(yylex.Errorf("TiDB does not support WITH ConnectionOptions now, they would be parsed but ignored."))
-		parser.lastErrorAsWarn()
 	}
 
 ConnectionOptionList:
@@ -12320,6 +12512,13 @@ ConnectionOption:
 			Count: $2.(int64),
 		}
 	}
+|	"RESOURCE" "GROUP" Identifier
+	{
+		$$ = &ast.ResourceOption{
+			Type:              ast.ResourceGroup,
+			ResourceGroupName: $3,
+		}
+	}
 
 RequireClauseOpt:
 	{
@@ -12410,8 +12609,19 @@ PasswordOrLockOptions:
 |	PasswordOrLockOptionList
 	{
 		$$ = $1
-		yylex.AppendError(yylex.Errorf("TiDB does not support PASSWORD EXPIRE and ACCOUNT LOCK now, they would be parsed but ignored."))
-		parser.lastErrorAsWarn()
+	}
+
+CommentOrAttributeOption:
+	{
+		$$ = nil
+	}
+|	"COMMENT" stringLit
+	{
+		$$ = &ast.CommentOrAttributeOption{Type: ast.UserCommentType, Value: $2}
+	}
+|	"ATTRIBUTE" stringLit
+	{
+		$$ = &ast.CommentOrAttributeOption{Type: ast.UserAttributeType, Value: $2}
 	}
 
 PasswordOrLockOptionList:
@@ -12464,6 +12674,26 @@ PasswordOrLockOption:
 			Type: ast.PasswordExpireDefault,
 		}
 	}
+|	"FAILED_LOGIN_ATTEMPTS" Int64Num
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type:  ast.FailedLoginAttempts,
+			Count: $2.(int64),
+		}
+	}
+|	"PASSWORD_LOCK_TIME" Int64Num
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type:  ast.PasswordLockTime,
+			Count: $2.(int64),
+		}
+	}
+|	"PASSWORD_LOCK_TIME" "UNBOUNDED"
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type: ast.PasswordLockTimeUnbounded,
+		}
+	}
 
 PasswordExpire:
 	"PASSWORD" "EXPIRE" ClearPasswordExpireOptions
@@ -60,7 +60,7 @@ func TestSimple(t *testing.T) {
 		"delayed", "high_priority", "low_priority",
 		"cumeDist", "denseRank", "firstValue", "lag", "lastValue", "lead", "nthValue", "ntile",
 		"over", "percentRank", "rank", "row", "rows", "rowNumber", "window", "linear",
-		"match", "until", "placement", "tablesample", "attributes",
+		"match", "until", "placement", "tablesample", "attributes", "lateral",
 		// TODO: support the following keywords
 		// "with",
 	}
@@ -95,10 +95,10 @@ func TestSimple(t *testing.T) {
 		"binlog", "hex", "unhex", "function", "indexes", "from_unixtime", "processlist", "events", "less", "than", "timediff",
 		"ln", "log", "log2", "log10", "timestampdiff", "pi", "proxy", "quote", "none", "super", "shared", "exclusive",
 		"always", "stats", "stats_meta", "stats_histogram", "stats_buckets", "stats_healthy", "tidb_version", "replication", "slave", "client",
-		"max_connections_per_hour", "max_queries_per_hour", "max_updates_per_hour", "max_user_connections", "event", "reload", "routine", "temporary",
+		"max_connections_per_hour", "max_queries_per_hour", "max_updates_per_hour", "max_user_connections", "resource", "event", "reload", "routine", "temporary",
 		"following", "preceding", "unbounded", "respect", "nulls", "current", "last", "against", "expansion",
 		"chain", "error", "general", "nvarchar", "pack_keys", "p", "shard_row_id_bits", "pre_split_regions",
-		"constraints", "role", "replicas", "policy", "s3", "strict", "running", "stop", "preserve", "placement",
+		"constraints", "role", "replicas", "policy", "s3", "strict", "running", "stop", "preserve", "placement", "ilike",
 	}
 	for _, kw := range unreservedKws {
 		src := fmt.Sprintf("SELECT %s FROM tbl;", kw)
@@ -783,6 +783,10 @@ func TestDMLStmt(t *testing.T) {
 		{"admin show ddl jobs where id > 0;", true, "ADMIN SHOW DDL JOBS WHERE `id`>0"},
 		{"admin show ddl jobs 20 where id=0;", true, "ADMIN SHOW DDL JOBS 20 WHERE `id`=0"},
 		{"admin show ddl jobs -1;", false, ""},
+		{"admin show ddl jobs limit 10;", true, "ADMIN SHOW DDL JOBS LIMIT 10"},
+		{"admin show ddl jobs where state = 'cancelled' limit 5, 10;", true, "ADMIN SHOW DDL JOBS WHERE `state`=_UTF8MB4'cancelled' LIMIT 5,10"},
+		{"admin show ddl jobs 20 where table_name = 't' limit 10 offset 5;", true, "ADMIN SHOW DDL JOBS 20 WHERE `table_name`=_UTF8MB4't' LIMIT 5,10"},
+		{"admin show ddl jobs limit -1;", false, ""},
 		{"admin show ddl job queries 1", true, "ADMIN SHOW DDL JOB QUERIES 1"},
 		{"admin show ddl job queries 1, 2, 3, 4", true, "ADMIN SHOW DDL JOB QUERIES 1, 2, 3, 4"},
 		{"admin show t1 next_row_id", true, "ADMIN SHOW `t1` NEXT_ROW_ID"},
@@ -790,6 +794,13 @@ func TestDMLStmt(t *testing.T) {
 		{"admin check index tableName idxName;", true, "ADMIN CHECK INDEX `tableName` idxName"},
 		{"admin check index tableName idxName (1, 2), (4, 5);", true, "ADMIN CHECK INDEX `tableName` idxName (1,2), (4,5)"},
 		{"admin checksum table t1, t2;", true, "ADMIN CHECKSUM TABLE `t1`, `t2`"},
+		{"admin checksum table t1 with indexes;", true, "ADMIN CHECKSUM TABLE `t1` WITH INDEXES"},
+		{"admin checksum table t1, t2 as of timestamp '2021-10-01 00:00:00';", true, "ADMIN CHECKSUM TABLE `t1`, `t2` AS OF TIMESTAMP _UTF8MB4'2021-10-01 00:00:00'"},
+		{"admin checksum table t1 as of timestamp now() - interval 1 hour with indexes;", true, "ADMIN CHECKSUM TABLE `t1` AS OF TIMESTAMP DATE_SUB(NOW(), INTERVAL 1 HOUR) WITH INDEXES"},
+		{"admin checksum table t1 with indexes as of timestamp now();", false, ""},
+		{"admin compare checksum table t1, t2 using test.checksums;", true, "ADMIN COMPARE CHECKSUM TABLE `t1`, `t2` USING `test`.`checksums`"},
+		{"admin compare checksum table t1 as of timestamp '2021-10-01 00:00:00' using checksums;", true, "ADMIN COMPARE CHECKSUM TABLE `t1` AS OF TIMESTAMP _UTF8MB4'2021-10-01 00:00:00' USING `checksums`"},
+		{"admin compare checksum table t1;", false, ""},
 		{"admin cancel ddl jobs 1", true, "ADMIN CANCEL DDL JOBS 1"},
 		{"admin cancel ddl jobs 1, 2", true, "ADMIN CANCEL DDL JOBS 1, 2"},
 		{"admin recover index t1 idx_a", true, "ADMIN RECOVER INDEX `t1` idx_a"},
@@ -799,6 +810,9 @@ func TestDMLStmt(t *testing.T) {
 		{"admin show slow top all 9", true, "ADMIN SHOW SLOW TOP ALL 9"},
 		{"admin show slow recent 11", true, "ADMIN SHOW SLOW RECENT 11"},
 		{"admin reload expr_pushdown_blacklist", true, "ADMIN RELOAD EXPR_PUSHDOWN_BLACKLIST"},
+		{"admin reload sql_blocklist", true, "ADMIN RELOAD SQL_BLOCKLIST"},
+		{"admin kill top memory 3", true, "ADMIN KILL TOP MEMORY 3"},
+		{"admin kill top memory", false, ""},
 		{"admin plugins disable audit, whitelist", true, "ADMIN PLUGINS DISABLE audit, whitelist"},
 		{"admin plugins enable audit, whitelist", true, "ADMIN PLUGINS ENABLE audit, whitelist"},
 		{"admin flush bindings", true, "ADMIN FLUSH BINDINGS"},
@@ -965,6 +979,9 @@ AAAAAAAAAAAA5gm5Mg==
 		{"show table t1 index idx1 regions", true, "SHOW TABLE `t1` INDEX `idx1` REGIONS"},
 		{"show table t1 index idx1 regions where a=2", true, "SHOW TABLE `t1` INDEX `idx1` REGIONS WHERE `a`=2"},
 		{"show table t1 index idx1", false, ""},
+		{"show table t1 regions limit 10", true, "SHOW TABLE `t1` REGIONS LIMIT 10"},
+		{"show table t1 regions where leader_store_id=1 limit 10 offset 20", true, "SHOW TABLE `t1` REGIONS WHERE `leader_store_id`=1 LIMIT 20,10"},
+		{"show table t1 index idx1 regions limit 5, 10", true, "SHOW TABLE `t1` INDEX `idx1` REGIONS LIMIT 5,10"},
 
 		// for show table partition regions.
 		{"show table t1 partition (p0,p1) regions", true, "SHOW TABLE `t1` PARTITION(`p0`, `p1`) REGIONS"},
@@ -2973,6 +2990,12 @@ func TestDDL(t *testing.T) {
 		{"ALTER TABLE t ALTER INDEX a INVISIBLE", true, "ALTER TABLE `t` ALTER INDEX `a` INVISIBLE"},
 		{"ALTER TABLE t ALTER INDEX a VISIBLE", true, "ALTER TABLE `t` ALTER INDEX `a` VISIBLE"},
 
+		// For alter table alter primary key statement
+		{"ALTER TABLE t ALTER PRIMARY KEY CLUSTERED", true, "ALTER TABLE `t` ALTER PRIMARY KEY CLUSTERED"},
+		{"ALTER TABLE t ALTER PRIMARY KEY NONCLUSTERED", true, "ALTER TABLE `t` ALTER PRIMARY KEY NONCLUSTERED"},
+		{"ALTER TABLE t ALTER PRIMARY KEY", false, ""},
+		{"ALTER TABLE t ALTER PRIMARY KEY VISIBLE", false, ""},
+
 		{"ALTER TABLE t DROP FOREIGN KEY a", true, "ALTER TABLE `t` DROP FOREIGN KEY `a`"},
 		{"ALTER TABLE t DROP FOREIGN KEY IF EXISTS a", true, "ALTER TABLE `t` DROP FOREIGN KEY IF EXISTS `a`"},
 		{"ALTER TABLE t DROP COLUMN a CASCADE", true, "ALTER TABLE `t` DROP COLUMN `a`"},
@@ -3340,6 +3363,9 @@ func TestDDL(t *testing.T) {
 
 		{"ALTER TABLE t SET TIFLASH REPLICA 2 LOCATION LABELS 'a','b'", true, "ALTER TABLE `t` SET TIFLASH REPLICA 2 LOCATION LABELS 'a', 'b'"},
 		{"ALTER TABLE t SET TIFLASH REPLICA 0", true, "ALTER TABLE `t` SET TIFLASH REPLICA 0"},
+		{"ALTER TABLE t SET TIFLASH REPLICA 1 HIGH_PRIORITY", true, "ALTER TABLE `t` SET TIFLASH REPLICA 1 HIGH_PRIORITY"},
+		{"ALTER TABLE t SET TIFLASH REPLICA 2 LOCATION LABELS 'a' HIGH_PRIORITY", true, "ALTER TABLE `t` SET TIFLASH REPLICA 2 LOCATION LABELS 'a' HIGH_PRIORITY"},
+		{"ALTER TABLE t SET TIFLASH REPLICA 1 LOW_PRIORITY", false, ""},
 
 		// for issue 537
 		{"CREATE TABLE IF NOT EXISTS table_ident (a SQL_TSI_YEAR(4), b SQL_TSI_YEAR);", true, "CREATE TABLE IF NOT EXISTS `table_ident` (`a` YEAR(4),`b` YEAR)"},
@@ -3824,6 +3850,18 @@ func TestOptimizerHints(t *testing.T) {
 	require.Len(t, hints[2].Tables, 1)
 	require.Equal(t, "t2", hints[2].Tables[0].TableName.L)
 
+	// TEST SHUFFLE_JOIN
+	stmt, _, err = p.Parse("select /*+ SHUFFLE_JOIN(t1, T2) */ c1, c2 from t1, t2 where t1.c1 = t2.c1", "", "")
+	require.NoError(t, err)
+	selectStmt = stmt[0].(*ast.SelectStmt)
+
+	hints = selectStmt.TableHints
+	require.Len(t, hints, 1)
+	require.Equal(t, "shuffle_join", hints[0].HintName.L)
+	require.Len(t, hints[0].Tables, 2)
+	require.Equal(t, "t1", hints[0].Tables[0].TableName.L)
+	require.Equal(t, "t2", hints[0].Tables[1].TableName.L)
+
 	// Test TIDB_INLJ
 	stmt, _, err = p.Parse("select /*+ TIDB_INLJ(t1, T2), tidb_inlj(t3, t4) */ c1, c2 from t1, t2 where t1.c1 = t2.c1", "", "")
 	require.NoError(t, err)
@@ -4068,6 +4106,16 @@ func TestOptimizerHints(t *testing.T) {
 	require.Equal(t, "ignore_plan_cache", hints[0].HintName.L)
 	require.Equal(t, "ignore_plan_cache", hints[1].HintName.L)
 
+	// Test IGNORE_COPR_CACHE
+	stmt, _, err = p.Parse("select /*+ IGNORE_COPR_CACHE(), ignore_copr_cache() */ c1, c2 from t1, t2 where t1.c1 = t2.c1", "", "")
+	require.NoError(t, err)
+	selectStmt = stmt[0].(*ast.SelectStmt)
+
+	hints = selectStmt.TableHints
+	require.Len(t, hints, 2)
+	require.Equal(t, "ignore_copr_cache", hints[0].HintName.L)
+	require.Equal(t, "ignore_copr_cache", hints[1].HintName.L)
+
 	// Test USE_CASCADES
 	stmt, _, err = p.Parse("select /*+ USE_CASCADES(true), use_cascades(false) */ c1, c2 from t1, t2 where t1.c1 = t2.c1", "", "")
 	require.NoError(t, err)
@@ -4232,6 +4280,7 @@ func TestPrivilege(t *testing.T) {
 		{`CREATE USER 'ttt' REQUIRE ISSUER '/C=SE/ST=Stockholm/L=Stockholm/O=MySQL/CN=CA/emailAddress=ca@example.com' CIPHER 'EDH-RSA-DES-CBC3-SHA' SUBJECT '/C=SE/ST=Stockholm/L=Stockholm/O=MySQL/CN=CA/emailAddress=ca@example.com';`, true, "CREATE USER `ttt`@`%` REQUIRE ISSUER '/C=SE/ST=Stockholm/L=Stockholm/O=MySQL/CN=CA/emailAddress=ca@example.com' AND CIPHER 'EDH-RSA-DES-CBC3-SHA' AND SUBJECT '/C=SE/ST=Stockholm/L=Stockholm/O=MySQL/CN=CA/emailAddress=ca@example.com'"},
 		{`CREATE USER 'ttt' REQUIRE SAN 'DNS:mysql-user, URI:spiffe://example.org/myservice'`, true, "CREATE USER `ttt`@`%` REQUIRE SAN 'DNS:mysql-user, URI:spiffe://example.org/myservice'"},
 		{`CREATE USER 'ttt' WITH MAX_QUERIES_PER_HOUR 2;`, true, "CREATE USER `ttt`@`%` WITH MAX_QUERIES_PER_HOUR 2"},
+		{`CREATE USER 'ttt' WITH MAX_USER_CONNECTIONS 2 RESOURCE GROUP rg1;`, true, "CREATE USER `ttt`@`%` WITH MAX_USER_CONNECTIONS 2 RESOURCE GROUP `rg1`"},
 		{`CREATE USER 'ttt'@'localhost' REQUIRE NONE WITH MAX_QUERIES_PER_HOUR 1 MAX_UPDATES_PER_HOUR 10 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK;`, true, "CREATE USER `ttt`@`localhost` REQUIRE NONE WITH MAX_QUERIES_PER_HOUR 1 MAX_UPDATES_PER_HOUR 10 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"},
 		{`CREATE USER 'u1'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK ;`, true, "CREATE USER `u1`@`%` IDENTIFIED WITH 'mysql_native_password' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"},
 		{`CREATE USER 'test'`, true, "CREATE USER `test`@`%`"},
@@ -4257,6 +4306,10 @@ func TestPrivilege(t *testing.T) {
 		{"create user 'test@localhost' password expire never;", true, "CREATE USER `test@localhost`@`%` PASSWORD EXPIRE NEVER"},
 		{"create user 'test@localhost' password expire default;", true, "CREATE USER `test@localhost`@`%` PASSWORD EXPIRE DEFAULT"},
 		{"create user 'test@localhost' password expire interval 3 day;", true, "CREATE USER `test@localhost`@`%` PASSWORD EXPIRE INTERVAL 3 DAY"},
+		{"create user 'u1' failed_login_attempts 3 password_lock_time 2;", true, "CREATE USER `u1`@`%` FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME 2"},
+		{"create user 'u1' failed_login_attempts 3 password_lock_time unbounded comment 'some comment';", true, "CREATE USER `u1`@`%` FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME UNBOUNDED COMMENT 'some comment'"},
+		{`create user 'u1' attribute '{"name": "Tom"}';`, true, "CREATE USER `u1`@`%` ATTRIBUTE '{\"name\": \"Tom\"}'"},
+		{"create user 'u1' comment 'c1' attribute '{}';", false, ""},
 		{"CREATE USER 'sha_test'@'localhost' IDENTIFIED WITH 'caching_sha2_password' BY 'sha_test'", true, "CREATE USER `sha_test`@`localhost` IDENTIFIED WITH 'caching_sha2_password' BY 'sha_test'"},
 		{"CREATE USER 'sha_test3'@'localhost' IDENTIFIED WITH 'caching_sha2_password' AS 0x24412430303524255B03496C662C1055127B3B654A2F04207D01485276703644704B76303247474564416A516662346C5868646D32764C6B514F43585A473779565947514F34", true, "CREATE USER `sha_test3`@`localhost` IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$%[\x03Ilf,\x10U\x12{;eJ/\x04 }\x01HRvp6DpKv02GGEdAjQfb4lXhdm2vLkQOCXZG7yVYGQO4'"},
 		{"CREATE USER 'sha_test4'@'localhost' IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$%[\x03Ilf,\x10U\x12{;eJ/\x04 }\x01HRvp6DpKv02GGEdAjQfb4lXhdm2vLkQOCXZG7yVYGQO4'", true, "CREATE USER `sha_test4`@`localhost` IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$%[\x03Ilf,\x10U\x12{;eJ/\x04 }\x01HRvp6DpKv02GGEdAjQfb4lXhdm2vLkQOCXZG7yVYGQO4'"},
@@ -4283,6 +4336,8 @@ func TestPrivilege(t *testing.T) {
 		{"alter user 'test@localhost' password expire never;", true, "ALTER USER `test@localhost`@`%` PASSWORD EXPIRE NEVER"},
 		{"alter user 'test@localhost' password expire default;", true, "ALTER USER `test@localhost`@`%` PASSWORD EXPIRE DEFAULT"},
 		{"alter user 'test@localhost' password expire interval 3 day;", true, "ALTER USER `test@localhost`@`%` PASSWORD EXPIRE INTERVAL 3 DAY"},
+		{"alter user 'u1' account lock failed_login_attempts 0 password_lock_time 10 attribute '{}';", true, "ALTER USER `u1`@`%` ACCOUNT LOCK FAILED_LOGIN_ATTEMPTS 0 PASSWORD_LOCK_TIME 10 ATTRIBUTE '{}'"},
+		{"alter user 'u1' comment 'c1';", true, "ALTER USER `u1`@`%` COMMENT 'c1'"},
 		{"ALTER USER 'ttt' REQUIRE X509;", true, "ALTER USER `ttt`@`%` REQUIRE X509"},
 		{"ALTER USER 'ttt' REQUIRE SSL;", true, "ALTER USER `ttt`@`%` REQUIRE SSL"},
 		{"ALTER USER 'ttt' REQUIRE NONE;", true, "ALTER USER `ttt`@`%` REQUIRE NONE"},
@@ -4291,6 +4346,8 @@ func TestPrivilege(t *testing.T) {
 		{"ALTER USER 'ttt' WITH MAX_UPDATES_PER_HOUR 2;", true, "ALTER USER `ttt`@`%` WITH MAX_UPDATES_PER_HOUR 2"},
 		{"ALTER USER 'ttt' WITH MAX_CONNECTIONS_PER_HOUR 2;", true, "ALTER USER `ttt`@`%` WITH MAX_CONNECTIONS_PER_HOUR 2"},
 		{"ALTER USER 'ttt' WITH MAX_USER_CONNECTIONS 2;", true, "ALTER USER `ttt`@`%` WITH MAX_USER_CONNECTIONS 2"},
+		{"ALTER USER 'ttt' WITH RESOURCE GROUP rg1;", true, "ALTER USER `ttt`@`%` WITH RESOURCE GROUP `rg1`"},
+		{"ALTER USER 'ttt' WITH MAX_USER_CONNECTIONS 2 RESOURCE GROUP `rg1`;", true, "ALTER USER `ttt`@`%` WITH MAX_USER_CONNECTIONS 2 RESOURCE GROUP `rg1`"},
 		{"ALTER USER 'ttt'@'localhost' REQUIRE NONE WITH MAX_QUERIES_PER_HOUR 1 MAX_UPDATES_PER_HOUR 10 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK;", true, "ALTER USER `ttt`@`localhost` REQUIRE NONE WITH MAX_QUERIES_PER_HOUR 1 MAX_UPDATES_PER_HOUR 10 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"},
 		{`DROP USER 'root'@'localhost', 'root1'@'localhost'`, true, "DROP USER `root`@`localhost`, `root1`@`localhost`"},
 		{`DROP USER IF EXISTS 'root'@'localhost'`, true, "DROP USER IF EXISTS `root`@`localhost`"},
@@ -4638,6 +4695,12 @@ func TestLikeEscape(t *testing.T) {
 		{`select "abc_" like "abc\\_" escape '||'`, false, ""},
 		{`select "abc" like "escape" escape '+'`, true, "SELECT _UTF8MB4'abc' LIKE _UTF8MB4'escape' ESCAPE '+'"},
 		{"select '''_' like '''_' escape ''''", true, "SELECT _UTF8MB4'''_' LIKE _UTF8MB4'''_' ESCAPE ''''"},
+		{"select a ilike 'a%' from t", true, "SELECT `a` ILIKE _UTF8MB4'a%' FROM `t`"},
+		{"select a not ilike 'a%' escape '+' from t", true, "SELECT `a` NOT ILIKE _UTF8MB4'a%' ESCAPE '+' FROM `t`"},
+		{"select ilike from ilike", true, "SELECT `ilike` FROM `ilike`"},
+		{"select a as ilike from t", true, "SELECT `a` AS `ilike` FROM `t`"},
+		{"select ilike ilike 'a%' from t", true, "SELECT `ilike` ILIKE _UTF8MB4'a%' FROM `t`"},
+		{"create table ilike (ilike int)", true, "CREATE TABLE `ilike` (`ilike` INT)"},
 	}
 
 	RunTest(t, table, false)
@@ -4804,6 +4867,17 @@ func TestExplain(t *testing.T) {
 		{"EXPLAIN FORMAT = JSON FOR CONNECTION 1", true, "EXPLAIN FORMAT = 'JSON' FOR CONNECTION 1"},
 		{"EXPLAIN FORMAT = JSON SELECT 1", true, "EXPLAIN FORMAT = 'JSON' SELECT 1"},
 		{"EXPLAIN FORMAT = 'hint' SELECT 1", true, "EXPLAIN FORMAT = 'hint' SELECT 1"},
+		{"EXPLAIN FORMAT = 'cost_trace' SELECT 1", true, "EXPLAIN FORMAT = 'cost_trace' SELECT 1"},
+		{"EXPLAIN FORMAT = 'cost_trace' FOR CONNECTION 1", true, "EXPLAIN FORMAT = 'cost_trace' FOR CONNECTION 1"},
+		{"EXPLAIN ANALYZE FORMAT = VERBOSE SELECT 1", true, "EXPLAIN ANALYZE FORMAT = 'VERBOSE' SELECT 1"},
+		{"EXPLAIN ANALYZE FORMAT = 'cost_trace' SELECT 1", true, "EXPLAIN ANALYZE FORMAT = 'cost_trace' SELECT 1"},
+		{"EXPLAIN ANALYZE FORMAT = 'row' SELECT 1", true, "EXPLAIN ANALYZE SELECT 1"},
+		{"create table tree (tree int)", true, "CREATE TABLE `tree` (`tree` INT)"},
+		{"EXPLAIN FORMAT = SELECTIVITY SELECT * FROM t WHERE a > 1", true, "EXPLAIN FORMAT = 'SELECTIVITY' SELECT * FROM `t` WHERE `a`>1"},
+		{"EXPLAIN FORMAT = 'selectivity' SELECT * FROM t WHERE a > 1", true, "EXPLAIN FORMAT = 'selectivity' SELECT * FROM `t` WHERE `a`>1"},
+		{"EXPLAIN SELECTIVITY SELECT * FROM t WHERE a > 1", true, "EXPLAIN FORMAT = 'selectivity' SELECT * FROM `t` WHERE `a`>1"},
+		{"EXPLAIN selectivity", true, "DESC `selectivity`"},
+		{"create table selectivity (selectivity int)", true, "CREATE TABLE `selectivity` (`selectivity` INT)"},
 		{"EXPLAIN ALTER TABLE t1 ADD INDEX (a)", true, "EXPLAIN FORMAT = 'row' ALTER TABLE `t1` ADD INDEX(`a`)"},
 		{"EXPLAIN ALTER TABLE t1 ADD a varchar(255)", true, "EXPLAIN FORMAT = 'row' ALTER TABLE `t1` ADD COLUMN `a` VARCHAR(255)"},
 	}
@@ -5332,6 +5406,19 @@ func TestTableSample(t *testing.T) {
 	}
 }
 
+func TestLateralDerivedTable(t *testing.T) {
+	table := []testCase{
+		{"select * from t1, lateral (select t1.a) as dt;", true, "SELECT * FROM (`t1`) JOIN LATERAL (SELECT `t1`.`a`) AS `dt`"},
+		{"select * from t1 join lateral (select * from t2 where t2.a = t1.a) dt;", true, "SELECT * FROM `t1` JOIN LATERAL (SELECT * FROM `t2` WHERE `t2`.`a`=`t1`.`a`) AS `dt`"},
+		{"select * from t1 left join lateral (select max(b) m from t2 where t2.a = t1.a) as dt on true;", true, "SELECT * FROM `t1` LEFT JOIN LATERAL (SELECT MAX(`b`) AS `m` FROM `t2` WHERE `t2`.`a`=`t1`.`a`) AS `dt` ON TRUE"},
+		{"select * from t1, lateral (select 1 union select t1.a) as dt;", true, "SELECT * FROM (`t1`) JOIN LATERAL (SELECT 1 UNION SELECT `t1`.`a`) AS `dt`"},
+		{"select * from t1, lateral t2;", false, ""},
+		{"select * from lateral;", false, ""},
+		{"select * from `lateral`;", true, "SELECT * FROM `lateral`"},
+	}
+	RunTest(t, table, false)
+}
+
 func TestGeneratedColumn(t *testing.T) {
 	tests := []struct {
 		input string
@@ -5706,6 +5793,13 @@ func TestWindowFunctions(t *testing.T) {
 		{`SELECT AVG(val) OVER (RANGE BETWEEN INTERVAL 5 DAY PRECEDING AND INTERVAL '2:30' MINUTE_SECOND FOLLOWING) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN INTERVAL 5 DAY PRECEDING AND INTERVAL _UTF8MB4'2:30' MINUTE_SECOND FOLLOWING) FROM `t`"},
 		{`SELECT AVG(val) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM `t`"},
 		{`SELECT AVG(val) OVER (RANGE CURRENT ROW) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM `t`"},
+		{`SELECT SUM(val) OVER (ORDER BY time ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE CURRENT ROW) FROM t;`, true, "SELECT SUM(`val`) OVER (ORDER BY `time` ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE CURRENT ROW) FROM `t`"},
+		{`SELECT SUM(val) OVER (ORDER BY time RANGE BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING EXCLUDE GROUP) FROM t;`, true, "SELECT SUM(`val`) OVER (ORDER BY `time` RANGE BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING EXCLUDE GROUP) FROM `t`"},
+		{`SELECT SUM(val) OVER (ORDER BY time ROWS UNBOUNDED PRECEDING EXCLUDE TIES) FROM t;`, true, "SELECT SUM(`val`) OVER (ORDER BY `time` ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW EXCLUDE TIES) FROM `t`"},
+		{`SELECT SUM(val) OVER (ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE NO OTHERS) FROM t;`, true, "SELECT SUM(`val`) OVER (ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE NO OTHERS) FROM `t`"},
+		{`SELECT SUM(val) OVER (ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE) FROM t;`, false, ""},
+		{`SELECT SUM(val) OVER (EXCLUDE TIES) FROM t;`, false, ""},
+		{`SELECT exclude, others, ties FROM t;`, true, "SELECT `exclude`,`others`,`ties` FROM `t`"},
 
 		// For named windows.
 		// See https://dev.mysql.com/doc/refman/8.0/en/window-functions-named-windows.html
@@ -6185,6 +6279,11 @@ func TestBRIE(t *testing.T) {
 		},
 		{"restore table g from 'noop://' checksum off", true, "RESTORE TABLE `g` FROM 'noop://' CHECKSUM = OFF"},
 		{"restore table g from 'noop://' checksum optional", true, "RESTORE TABLE `g` FROM 'noop://' CHECKSUM = OPTIONAL"},
+		{"backup cluster using snapshot to 's3://bucket/snapshot/'", true, "BACKUP CLUSTER USING SNAPSHOT TO 's3://bucket/snapshot/'"},
+		{"backup cluster using snapshot to 'local:///tmp/snapshot' send_credentials_to_tikv = 0", true, "BACKUP CLUSTER USING SNAPSHOT TO 'local:///tmp/snapshot' SEND_CREDENTIALS_TO_TIKV = 0"},
+		{"backup cluster to 's3://bucket/snapshot/'", false, ""},
+		{"restore cluster using snapshot from 's3://bucket/snapshot/'", false, ""},
+		{"create table cluster (cluster int)", true, "CREATE TABLE `cluster` (`cluster` INT)"},
 	}
 
 	RunTest(t, table, false)
@@ -79,6 +79,7 @@ type planCacheKey struct {
 	timezoneOffset       int
 	isolationReadEngines map[kv.StoreType]struct{}
 	selectLimit          uint64
+	tableMapping         string
 
 	hash []byte
 }
@@ -109,6 +110,7 @@ func (key *planCacheKey) Hash() []byte {
 			key.hash = append(key.hash, kv.TiFlash.Name()...)
 		}
 		key.hash = codec.EncodeInt(key.hash, int64(key.selectLimit))
+		key.hash = append(key.hash, hack.Slice(key.tableMapping)...)
 	}
 	return key.hash
 }
@@ -145,6 +147,8 @@ func NewPlanCacheKey(sessionVars *variable.SessionVars, pstmtID uint32, schemaVe
 		isolationReadEngines: make(map[kv.StoreType]struct{}),
 		selectLimit:          sessionVars.SelectLimit,
 	}
+	// The plan accesses the tables routed by tidb_table_mapping.
+	key.tableMapping, _ = sessionVars.GetSystemVar(variable.TiDBTableMapping)
 	for k, v := range sessionVars.IsolationReadEngines {
 		key.isolationReadEngines[k] = v
 	}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -47,6 +48,7 @@ import (
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/texttree"
+	"github.com/pingcap/tidb/util/tracing"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
@@ -65,6 +67,13 @@ type ShowSlow struct {
 	*ast.ShowSlow
 }
 
+// KillTopMemory is for killing the connections consuming the most memory.
+type KillTopMemory struct {
+	baseSchemaProducer
+
+	TopN int64
+}
+
 // ShowDDLJobQueries is for showing DDL job queries sql.
 type ShowDDLJobQueries struct {
 	baseSchemaProducer
@@ -115,11 +124,18 @@ type CheckIndexRange struct {
 	HandleRanges []ast.HandleRange
 }
 
-// ChecksumTable is used for calculating table checksum, built from the `admin checksum table` statement.
+// ChecksumTable is used for calculating table checksum, built from the `admin checksum table` and
+// `admin compare checksum table` statements.
 type ChecksumTable struct {
 	baseSchemaProducer
 
 	Tables []*ast.TableName
+	// StartTS is the snapshot to calculate the checksums at, 0 means the snapshot of the statement.
+	StartTS uint64
+	// WithIndexes reports the checksum of every index besides the whole table.
+	WithIndexes bool
+	// ResultTable stores the checksums to be compared with, it's nil if nothing is compared.
+	ResultTable *ast.TableName
 }
 
 // CancelDDLJobs represents a cancel DDL jobs plan.
@@ -139,6 +155,11 @@ type ReloadOptRuleBlacklist struct {
 	baseSchemaProducer
 }
 
+// ReloadSQLBlocklist reloads the data from sql_blocklist table.
+type ReloadSQLBlocklist struct {
+	baseSchemaProducer
+}
+
 // AdminPluginsAction indicate action will be taken on plugins.
 type AdminPluginsAction int
 
@@ -295,6 +316,9 @@ func (e *Execute) OptimizePreparedPlan(ctx context.Context, sctx sessionctx.Cont
 		}
 		prepared.SchemaVersion = is.SchemaMetaVersion()
 	}
+	if !isStaleness {
+		acquireMDLForStmt(sctx, is, prepared.Stmt)
+	}
 	// If the lastUpdateTime less than expiredTimeStamp4PC,
 	// it means other sessions have executed 'admin flush instance plan_cache'.
 	// So we need to clear the current session's plan cache.
@@ -1158,6 +1182,8 @@ type DDL struct {
 	baseSchemaProducer
 
 	Statement ast.DDLNode
+	// SelectCols are the columns which the select result of CREATE TABLE ... SELECT is inserted into.
+	SelectCols []model.CIStr
 }
 
 // SelectInto represents a select-into plan.
@@ -1181,6 +1207,8 @@ type Explain struct {
 	Rows           [][]string
 	ExplainRows    [][]string
 	explainedPlans map[int]bool
+	// CETrace is the cardinality estimation trace of the target plan, it's rendered in the selectivity format.
+	CETrace []*tracing.CETraceRecord
 
 	ctes []*PhysicalCTE
 }
@@ -1192,6 +1220,15 @@ func GetExplainRowsForPlan(plan Plan) (rows [][]string) {
 		Format:     types.ExplainFormatROW,
 		Analyze:    false,
 	}
+	// The context of the plan is used to show the partitions it accesses, which are pruned with the parameters
+	// of the current execution if the plan is cached.
+	ctxPlan := plan
+	if execute, ok := plan.(*Execute); ok && execute.Plan != nil {
+		ctxPlan = execute.Plan
+	}
+	if ctxPlan != nil {
+		explain.ctx = ctxPlan.SCtx()
+	}
 	if err := explain.RenderResult(); err != nil {
 		return rows
 	}
@@ -1204,8 +1241,8 @@ func (e *Explain) prepareSchema() error {
 	format := strings.ToLower(e.Format)
 	if format == types.ExplainFormatTraditional {
 		format = types.ExplainFormatROW
-		e.Format = types.ExplainFormatROW
 	}
+	e.Format = format
 	switch {
 	case (format == types.ExplainFormatROW && (!e.Analyze && e.RuntimeStatsColl == nil)) || (format == types.ExplainFormatBrief):
 		fieldNames = []string{"id", "estRows", "task", "access object", "operator info"}
@@ -1215,12 +1252,20 @@ func (e *Explain) prepareSchema() error {
 		} else {
 			fieldNames = []string{"id", "estRows", "estCost", "task", "access object", "operator info"}
 		}
+	case format == types.ExplainFormatCostTrace:
+		if e.Analyze || e.RuntimeStatsColl != nil {
+			fieldNames = []string{"id", "estRows", "estCost", "costFormula", "actRows", "task", "access object", "execution info", "operator info", "memory", "disk"}
+		} else {
+			fieldNames = []string{"id", "estRows", "estCost", "costFormula", "task", "access object", "operator info"}
+		}
 	case format == types.ExplainFormatROW && (e.Analyze || e.RuntimeStatsColl != nil):
 		fieldNames = []string{"id", "estRows", "actRows", "task", "access object", "execution info", "operator info", "memory", "disk"}
 	case format == types.ExplainFormatDOT:
 		fieldNames = []string{"dot contents"}
 	case format == types.ExplainFormatHint:
 		fieldNames = []string{"hint"}
+	case format == types.ExplainFormatSelectivity && !e.Analyze && e.RuntimeStatsColl == nil:
+		fieldNames = []string{"table", "type", "expr", "estRows", "selectivity", "detail"}
 	default:
 		return errors.Errorf("explain format '%s' is not supported now", e.Format)
 	}
@@ -1244,7 +1289,7 @@ func (e *Explain) RenderResult() error {
 		return nil
 	}
 	switch strings.ToLower(e.Format) {
-	case types.ExplainFormatROW, types.ExplainFormatBrief, types.ExplainFormatVerbose:
+	case types.ExplainFormatROW, types.ExplainFormatBrief, types.ExplainFormatVerbose, types.ExplainFormatCostTrace:
 		if e.Rows == nil || e.Analyze {
 			e.explainedPlans = map[int]bool{}
 			err := e.explainPlanInRowFormat(e.TargetPlan, "root", "", "", true)
@@ -1264,6 +1309,17 @@ func (e *Explain) RenderResult() error {
 		hints := GenHintsFromPhysicalPlan(e.TargetPlan)
 		hints = append(hints, hint.ExtractTableHintsFromStmtNode(e.ExecStmt, nil)...)
 		e.Rows = append(e.Rows, []string{hint.RestoreOptimizerHints(hints)})
+	case types.ExplainFormatSelectivity:
+		for _, rec := range e.CETrace {
+			e.Rows = append(e.Rows, []string{
+				rec.TableName,
+				rec.Type,
+				rec.Expr,
+				strconv.FormatUint(rec.RowCount, 10),
+				strconv.FormatFloat(rec.Selectivity, 'f', 4, 64),
+				rec.Detail,
+			})
+		}
 	default:
 		return errors.Errorf("explain format '%s' is not supported now", e.Format)
 	}
@@ -1456,24 +1512,76 @@ func (e *Explain) prepareOperatorInfo(p Plan, taskType, driverSide, indent strin
 	id := texttree.PrettyIdentifier(p.ExplainID().String()+driverSide, indent, isLastChild)
 	estRows, estCost, accessObject, operatorInfo := e.getOperatorInfo(p, id)
 
-	var row []string
-	if e.Analyze || e.RuntimeStatsColl != nil {
-		row = []string{id, estRows}
-		if e.Format == types.ExplainFormatVerbose {
-			row = append(row, estCost)
+	row := []string{id, estRows}
+	switch e.Format {
+	case types.ExplainFormatVerbose:
+		row = append(row, estCost)
+	case types.ExplainFormatCostTrace:
+		costFormula := "N/A"
+		if estCost != "N/A" {
+			costFormula = getCostFormula(p)
 		}
+		row = append(row, estCost, costFormula)
+	}
+	if e.Analyze || e.RuntimeStatsColl != nil {
 		actRows, analyzeInfo, memoryInfo, diskInfo := getRuntimeInfo(e.ctx, p, e.RuntimeStatsColl)
+		if e.ctx != nil && e.ctx.GetSessionVars().ExplainDeterministic {
+			analyzeInfo, memoryInfo, diskInfo = maskRuntimeInfo(analyzeInfo), maskRuntimeInfo(memoryInfo), maskRuntimeInfo(diskInfo)
+		}
 		row = append(row, actRows, taskType, accessObject, analyzeInfo, operatorInfo, memoryInfo, diskInfo)
 	} else {
-		row = []string{id, estRows}
-		if e.Format == types.ExplainFormatVerbose {
-			row = append(row, estCost)
-		}
 		row = append(row, taskType, accessObject, operatorInfo)
 	}
 	e.Rows = append(e.Rows, row)
 }
 
+// getCostFormula returns how the estimated cost of the operator is accumulated, which is the cost of the
+// operator itself plus the costs of its children, e.g. "10.00 + TableReader_7(200.00)".
+// It returns "N/A" if the cost of the operator isn't the plain sum of its own cost and the costs of its children,
+// so the cost of the operator itself can't be separated from the costs of its children, e.g. the readers amortize
+// the costs of the coprocessor tasks to the concurrency, the index joins and apply multiply the cost of the inner
+// child by the outer rows, and union takes the max cost of the children.
+func getCostFormula(p Plan) string {
+	pp, ok := p.(PhysicalPlan)
+	if !ok {
+		return "N/A"
+	}
+	switch pp.(type) {
+	case *PhysicalTableReader, *PhysicalIndexReader, *PhysicalIndexLookUpReader, *PhysicalIndexMergeReader,
+		*PhysicalIndexJoin, *PhysicalIndexHashJoin, *PhysicalIndexMergeJoin, *PhysicalApply, *PhysicalUnionAll:
+		return "N/A"
+	}
+	selfCost := pp.Cost()
+	var children strings.Builder
+	for _, child := range pp.Children() {
+		selfCost -= child.Cost()
+		fmt.Fprintf(&children, " + %s(%s)", child.ExplainID().String(), strconv.FormatFloat(child.Cost(), 'f', 2, 64))
+	}
+	if selfCost < 0 {
+		return "N/A"
+	}
+	return strconv.FormatFloat(selfCost, 'f', 2, 64) + children.String()
+}
+
+var (
+	// explainDurationPattern matches the durations like "1.5ms" and "1m2s" in the runtime information.
+	explainDurationPattern = regexp.MustCompile(`\b(\d+(\.\d+)?(ns|µs|us|ms|h|m|s))+\b`)
+	// explainBytesPattern matches the sizes like "1.2 KB" in the runtime information.
+	explainBytesPattern = regexp.MustCompile(`\b\d+(\.\d+)? (Bytes|KB|MB|GB|TB)\b`)
+)
+
+// maskedExplainValue replaces the machine-dependent values when tidb_explain_deterministic is on.
+const maskedExplainValue = "<masked>"
+
+// maskRuntimeInfo masks the time and memory usage in the runtime information, which change from run to run.
+func maskRuntimeInfo(info string) string {
+	if info == "N/A" {
+		return info
+	}
+	info = explainDurationPattern.ReplaceAllString(info, maskedExplainValue)
+	return explainBytesPattern.ReplaceAllString(info, maskedExplainValue)
+}
+
 func (e *Explain) getOperatorInfo(p Plan, id string) (string, string, string, string) {
 	// For `explain for connection` statement, `e.ExplainRows` will be set.
 	for _, row := range e.ExplainRows {
@@ -940,6 +940,8 @@ func (p *LogicalJoin) constructInnerTableScanTask(
 		Table:           ds.tableInfo,
 		Columns:         ds.Columns,
 		TableAsName:     ds.TableAsName,
+		viewPath:        ds.viewPath,
+		SnapshotTS:      ds.snapshotTS,
 		DBName:          ds.DBName,
 		filterCondition: ds.pushedDownConds,
 		Ranges:          ranges,
@@ -1031,6 +1033,8 @@ func (p *LogicalJoin) constructInnerIndexScanTask(
 	is := PhysicalIndexScan{
 		Table:            ds.tableInfo,
 		TableAsName:      ds.TableAsName,
+		viewPath:         ds.viewPath,
+		SnapshotTS:       ds.snapshotTS,
 		DBName:           ds.DBName,
 		Columns:          ds.Columns,
 		Index:            path.Index,
@@ -1062,6 +1066,8 @@ func (p *LogicalJoin) constructInnerIndexScanTask(
 			Columns:         ds.Columns,
 			Table:           is.Table,
 			TableAsName:     ds.TableAsName,
+			viewPath:        ds.viewPath,
+			SnapshotTS:      ds.snapshotTS,
 			isPartition:     ds.isPartition,
 			physicalTableID: ds.physicalTableID,
 		}.Init(ds.ctx, ds.blockOffset)
@@ -1759,7 +1765,8 @@ func (p *LogicalJoin) shouldUseMPPBCJ() bool {
 	if len(p.EqualConditions) == 0 && p.ctx.GetSessionVars().AllowCartesianBCJ == 2 {
 		return true
 	}
-	if p.JoinType == LeftOuterJoin || p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin {
+	if p.JoinType == LeftOuterJoin || p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin ||
+		p.JoinType == LeftOuterSemiJoin || p.JoinType == AntiLeftOuterSemiJoin {
 		return checkChildFitBC(p.children[1])
 	} else if p.JoinType == RightOuterJoin {
 		return checkChildFitBC(p.children[0])
@@ -1779,7 +1786,7 @@ func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]P
 		}
 	})
 
-	if (p.preferJoinType&preferBCJoin) == 0 && p.preferJoinType > 0 {
+	if (p.preferJoinType&(preferBCJoin|preferShuffleJoin)) == 0 && p.preferJoinType > 0 {
 		p.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced("MPP mode may be blocked because you have used hint to specify a join algorithm which is not supported by mpp now.")
 		if prop.IsFlashProp() {
 			return nil, false, nil
@@ -1791,13 +1798,21 @@ func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]P
 	joins := make([]PhysicalPlan, 0, 8)
 	canPushToTiFlash := p.canPushToCop(kv.TiFlash)
 	if p.ctx.GetSessionVars().IsMPPAllowed() && canPushToTiFlash {
-		if p.shouldUseMPPBCJ() {
+		// The BROADCAST_JOIN and SHUFFLE_JOIN hints take precedence over the
+		// broadcast thresholds, so users can correct a poor choice of the cost model.
+		switch {
+		case (p.preferJoinType & preferShuffleJoin) > 0:
+			mppJoins := p.tryToGetMppHashJoin(prop, false)
+			if len(mppJoins) > 0 {
+				return mppJoins, true, nil
+			}
+		case (p.preferJoinType&preferBCJoin) > 0 || p.shouldUseMPPBCJ():
 			mppJoins := p.tryToGetMppHashJoin(prop, true)
 			if (p.preferJoinType & preferBCJoin) > 0 {
 				return mppJoins, true, nil
 			}
 			joins = append(joins, mppJoins...)
-		} else {
+		default:
 			mppJoins := p.tryToGetMppHashJoin(prop, false)
 			joins = append(joins, mppJoins...)
 		}
@@ -1870,7 +1885,8 @@ func (p *LogicalJoin) tryToGetMppHashJoin(prop *property.PhysicalProperty, useBC
 		return nil
 	}
 
-	if p.JoinType != InnerJoin && p.JoinType != LeftOuterJoin && p.JoinType != RightOuterJoin && p.JoinType != SemiJoin && p.JoinType != AntiSemiJoin {
+	if p.JoinType != InnerJoin && p.JoinType != LeftOuterJoin && p.JoinType != RightOuterJoin && p.JoinType != SemiJoin && p.JoinType != AntiSemiJoin &&
+		p.JoinType != LeftOuterSemiJoin && p.JoinType != AntiLeftOuterSemiJoin {
 		p.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced("MPP mode may be blocked because join type `" + p.JoinType.String() + "` is not supported now.")
 		return nil
 	}
@@ -1918,7 +1934,9 @@ func (p *LogicalJoin) tryToGetMppHashJoin(prop *property.PhysicalProperty, useBC
 		if p.children[0].statsInfo().Count() > p.children[1].statsInfo().Count() {
 			preferredBuildIndex = 1
 		}
-	} else if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin {
+	} else if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin ||
+		p.JoinType == LeftOuterSemiJoin || p.JoinType == AntiLeftOuterSemiJoin {
+		// The semi joins produced from the IN/EXISTS subqueries always build the hash table on the inner side.
 		preferredBuildIndex = 1
 	}
 	if p.JoinType == LeftOuterJoin || p.JoinType == RightOuterJoin {
@@ -1996,6 +2014,9 @@ func (p *LogicalJoin) tryToGetMppHashJoin(prop *property.PhysicalProperty, useBC
 		mppShuffleJoin:   !useBCJ,
 		// Mpp Join has quite heavy cost. Even limit might not suspend it in time, so we dont scale the count.
 	}.Init(p.ctx, p.stats, p.blockOffset, childrenProps...)
+	// The schema of the (anti) left outer semi join has an auxiliary column which is needed when rebuilding the
+	// join schema from the children.
+	join.SetSchema(p.schema)
 	return []PhysicalPlan{join}
 }
 
@@ -2232,7 +2253,23 @@ func MatchItems(p *property.PhysicalProperty, items []*util.ByItems) bool {
 	return true
 }
 
+func (lt *LogicalTopN) getPhysReservoirSample(prop *property.PhysicalProperty) []PhysicalPlan {
+	resultProp := &property.PhysicalProperty{TaskTp: property.RootTaskType, ExpectedCnt: math.MaxFloat64}
+	sample := PhysicalReservoirSample{
+		Count:  lt.Count,
+		Offset: lt.Offset,
+	}.Init(lt.ctx, lt.stats, lt.blockOffset, resultProp)
+	return []PhysicalPlan{sample}
+}
+
 func (lt *LogicalTopN) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]PhysicalPlan, bool, error) {
+	if lt.isRandomOrder() {
+		// `ORDER BY RAND() LIMIT N` picks N random rows, which doesn't need to sort all the rows.
+		if prop.IsEmpty() {
+			return lt.getPhysReservoirSample(prop), true, nil
+		}
+		return nil, true, nil
+	}
 	if MatchItems(prop, lt.ByItems) {
 		return append(lt.getPhysTopN(prop), lt.getPhysLimits(prop)...), true, nil
 	}
@@ -2424,6 +2461,7 @@ func (la *LogicalAggregation) getEnforcedStreamAggs(prop *property.PhysicalPrope
 		agg := basePhysicalAgg{
 			GroupByItems: la.GroupByItems,
 			AggFuncs:     la.AggFuncs,
+			viewPath:     la.viewPath,
 		}.initForStream(la.ctx, la.stats.ScaleByExpectCnt(prop.ExpectedCnt), la.blockOffset, copiedChildProperty)
 		agg.SetSchema(la.schema.Clone())
 		enforcedAggs = append(enforcedAggs, agg)
@@ -2501,6 +2539,7 @@ func (la *LogicalAggregation) getStreamAggs(prop *property.PhysicalProperty) []P
 			agg := basePhysicalAgg{
 				GroupByItems: la.GroupByItems,
 				AggFuncs:     la.AggFuncs,
+				viewPath:     la.viewPath,
 			}.initForStream(la.ctx, la.stats.ScaleByExpectCnt(prop.ExpectedCnt), la.blockOffset, copiedChildProperty)
 			agg.SetSchema(la.schema.Clone())
 			streamAggs = append(streamAggs, agg)
@@ -2749,8 +2788,11 @@ func (p *LogicalLock) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]P
 }
 
 func (p *LogicalUnionAll) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]PhysicalPlan, bool, error) {
-	// TODO: UnionAll can not pass any order, but we can change it to sort merge to keep order.
-	if !prop.IsEmpty() || (prop.IsFlashProp() && prop.TaskTp != property.MppTaskType) {
+	if !prop.IsEmpty() {
+		// UnionAll can keep the order by merging its sorted children.
+		return p.getMergeSortUnionAll(prop), true, nil
+	}
+	if prop.IsFlashProp() && prop.TaskTp != property.MppTaskType {
 		return nil, true, nil
 	}
 	// TODO: UnionAll can pass partition info, but for briefness, we prevent it from pushing down.
@@ -2790,6 +2832,41 @@ func (p *LogicalUnionAll) exhaustPhysicalPlans(prop *property.PhysicalProperty)
 	return []PhysicalPlan{ua}, true, nil
 }
 
+// getMergeSortUnionAll requires every child to be sorted by the columns at the
+// same offsets as the sort items of prop, and merges the children to keep the order.
+// It's only used when the parent needs part of the rows, e.g. ORDER BY ... LIMIT,
+// since the merge can stop early then.
+func (p *LogicalUnionAll) getMergeSortUnionAll(prop *property.PhysicalProperty) []PhysicalPlan {
+	if prop.TaskTp != property.RootTaskType || prop.IsFlashProp() || prop.ExpectedCnt >= p.stats.RowCount {
+		return nil
+	}
+	offsets := make([]int, 0, len(prop.SortItems))
+	byItems := make([]*util.ByItems, 0, len(prop.SortItems))
+	for _, item := range prop.SortItems {
+		offset := p.Schema().ColumnIndex(item.Col)
+		if offset < 0 {
+			return nil
+		}
+		offsets = append(offsets, offset)
+		byItems = append(byItems, &util.ByItems{Expr: p.Schema().Columns[offset], Desc: item.Desc})
+	}
+	chReqProps := make([]*property.PhysicalProperty, 0, len(p.children))
+	for _, child := range p.children {
+		sortItems := make([]property.SortItem, 0, len(offsets))
+		for i, offset := range offsets {
+			sortItems = append(sortItems, property.SortItem{Col: child.Schema().Columns[offset], Desc: prop.SortItems[i].Desc})
+		}
+		chReqProps = append(chReqProps, &property.PhysicalProperty{
+			ExpectedCnt:    prop.ExpectedCnt,
+			SortItems:      sortItems,
+			CanAddEnforcer: true,
+		})
+	}
+	ua := PhysicalUnionAll{ByItems: byItems}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), p.blockOffset, chReqProps...)
+	ua.SetSchema(p.Schema())
+	return []PhysicalPlan{ua}
+}
+
 func (p *LogicalPartitionUnionAll) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]PhysicalPlan, bool, error) {
 	uas, flagHint, err := p.LogicalUnionAll.exhaustPhysicalPlans(prop)
 	if err != nil {
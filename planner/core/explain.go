@@ -555,6 +555,15 @@ func (p *PhysicalSort) ExplainInfo() string {
 	return explainByItems(buffer, p.ByItems).String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalUnionAll) ExplainInfo() string {
+	if !p.KeepOrder() {
+		return ""
+	}
+	buffer := bytes.NewBufferString("merge sort by:")
+	return explainByItems(buffer, p.ByItems).String()
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalLimit) ExplainInfo() string {
 	var str strings.Builder
@@ -796,6 +805,11 @@ func (p *PhysicalTopN) ExplainNormalizedInfo() string {
 	return buffer.String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalReservoirSample) ExplainInfo() string {
+	return fmt.Sprintf("offset:%v, count:%v", p.Offset, p.Count)
+}
+
 func (p *PhysicalWindow) formatFrameBound(buffer *bytes.Buffer, bound *FrameBound) {
 	if bound.Type == ast.CurrentRow {
 		buffer.WriteString("current row")
@@ -870,6 +884,10 @@ func (p *PhysicalWindow) ExplainInfo() string {
 		p.formatFrameBound(buffer, p.Frame.Start)
 		buffer.WriteString(" and ")
 		p.formatFrameBound(buffer, p.Frame.End)
+		if p.Frame.Exclusion != ast.ExcludeNone {
+			buffer.WriteString(" exclude ")
+			buffer.WriteString(strings.ToLower(p.Frame.Exclusion.String()))
+		}
 	}
 	buffer.WriteString(")")
 	return buffer.String()
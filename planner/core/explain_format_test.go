@@ -0,0 +1,96 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainFormatCostTrace(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, index idx(a))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+
+	// The cost of every operator is its own cost plus the costs of its children.
+	tk.MustQuery("explain format = 'cost_trace' select * from t where b > 1 order by b").Check(testkit.Rows(
+		"Sort_5 3333.33 162661.28 119048.83 + TableReader_10(43612.44) root  test.t.b",
+		"└─TableReader_10 3333.33 43612.44 N/A root  data:Selection_9",
+		"  └─Selection_9 3333.33 600020.00 30000.00 + TableFullScan_8(570020.00) cop[tikv]  gt(test.t.b, 1)",
+		"    └─TableFullScan_8 10000.00 570020.00 570020.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	// The cost of the operator itself isn't shown if it can't be separated from the costs of its children.
+	tk.MustQuery("explain format = 'cost_trace' select a from t union all select b from t").Check(testkit.Rows(
+		"Union_8 20000.00 49445.00 N/A root  ",
+		"├─IndexReader_13 10000.00 34418.00 N/A root  index:IndexFullScan_12",
+		"│ └─IndexFullScan_12 10000.00 435020.00 435020.00 cop[tikv] table:t, index:idx(a) keep order:false, stats:pseudo",
+		"└─TableReader_16 10000.00 43418.00 N/A root  data:TableFullScan_15",
+		"  └─TableFullScan_15 10000.00 570020.00 570020.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+
+	// The runtime columns actRows, execution info, memory and disk are added for EXPLAIN ANALYZE.
+	rows := tk.MustQuery("explain analyze format = 'cost_trace' select * from t where b > 1").Rows()
+	require.Len(t, rows, 3)
+	require.Len(t, rows[0], 11)
+	require.Equal(t, "2", rows[0][4])
+
+	// The execution time and the memory usage are masked in the deterministic mode.
+	tk.MustExec("set @@tidb_explain_deterministic = 1")
+	rows = tk.MustQuery("explain analyze format = 'verbose' select * from t where b > 1").Rows()
+	require.Len(t, rows, 3)
+	require.Contains(t, rows[0][6], "time:<masked>")
+	require.Equal(t, "<masked>", rows[0][8])
+	require.Equal(t, "N/A", rows[0][9])
+	first := tk.MustQuery("explain analyze select * from t where b > 1").Rows()
+	second := tk.MustQuery("explain analyze select * from t where b > 1").Rows()
+	require.Equal(t, first, second)
+}
+
+func TestExplainFormatSelectivity(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, index idx(a))")
+
+	// The estimation falls back to the pseudo stats before the table is analyzed.
+	tk.MustQuery("explain format = 'selectivity' select * from t where b > 1").Check(testkit.Rows(
+		"t Column Stats-Pseudo-Range ((b > 1 and true)) 3333 0.3333 pseudo estimation: stats of the table are pseudo",
+		"t Table Stats-Expression-CNF `gt`(test.t.b, 1) 3333 0.3333 ",
+	))
+
+	tk.MustExec("insert into t values (1, 1), (1, 2), (1, 3), (2, 4), (3, 5), (4, 6), (5, 7), (6, 8)")
+	tk.MustExec("analyze table t with 1 topn, 2 buckets")
+	tk.MustQuery("explain select * from t where a = 1 and b > 3")
+	require.NoError(t, dom.StatsHandle().LoadNeededHistograms())
+
+	// The value 1 is the only TopN entry of the index and the column a, the others are estimated by the histogram.
+	tk.MustQuery("explain selectivity select * from t where a in (1, 9)").Sort().Check(testkit.Rows(
+		"t Column Stats-Point ((a = 1)) or ((a = 9)) 4 0.5000 1: TopN hit, 3 rows; 9: not in TopN or bucket bounds, uniform estimation 5/5",
+		"t Index Stats-Point ((a = 1)) or ((a = 9)) 4 0.5000 TopN hit, 3 rows; not in TopN or bucket bounds, uniform estimation 5/5",
+		"t Table Stats-Expression-CNF `in`(test.t.a, 1, 9) 4 0.5000 ",
+	))
+	tk.MustQuery("explain selectivity select * from t where b between 2 and 5").Check(testkit.Rows(
+		"t Column Stats-Range ((b >= 2 and b <= 5)) 4 0.5000 [2, 5): histogram bucket #0 of 2, TopN 0 rows, total 3.00 rows; 5: histogram bucket hit, 1.00 rows",
+		"t Table Stats-Expression-CNF `and`(`ge`(test.t.b, 2), `le`(test.t.b, 5)) 4 0.5000 ",
+	))
+
+	tk.MustGetErrMsg("explain analyze format = 'selectivity' select * from t", "explain format 'selectivity' is not supported by EXPLAIN ANALYZE")
+}
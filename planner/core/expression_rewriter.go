@@ -327,8 +327,12 @@ func (er *expressionRewriter) buildSubquery(ctx context.Context, subq *ast.Subqu
 		}()
 	}
 	outerWindowSpecs := er.b.windowSpecs
+	// The subqueries are not the table sources, they don't inherit the "STRAIGHT_JOIN" option.
+	outerStraightJoin := er.b.inStraightJoinTableSource
+	er.b.inStraightJoinTableSource = false
 	defer func() {
 		er.b.windowSpecs = outerWindowSpecs
+		er.b.inStraightJoinTableSource = outerStraightJoin
 	}()
 
 	np, err := er.b.buildResultSetNode(ctx, subq.Query)
@@ -944,8 +948,9 @@ func (er *expressionRewriter) handleInSubquery(ctx context.Context, v *ast.Patte
 			er.err = err
 			return v, true
 		}
-		// Build inner join above the aggregation.
-		join := LogicalJoin{JoinType: InnerJoin}.Init(er.sctx, er.b.getSelectOffset())
+		// Build inner join above the aggregation. The tables in the "FROM" clause are kept
+		// ahead of the subquery if the "SELECT" statement has "STRAIGHT_JOIN" option.
+		join := LogicalJoin{JoinType: InnerJoin, StraightJoin: er.b.inStraightJoin}.Init(er.sctx, er.b.getSelectOffset())
 		join.SetChildren(er.p, agg)
 		join.SetSchema(expression.MergeSchema(er.p.Schema(), agg.schema))
 		join.names = make([]*types.FieldName, er.p.Schema().Len()+agg.Schema().Len())
@@ -1627,6 +1632,24 @@ func (er *expressionRewriter) patternLikeToExpression(v *ast.PatternLikeExpr) {
 		return
 	}
 
+	escape := v.Escape
+	if v.IsILike {
+		if !er.sctx.GetSessionVars().EnablePGCompatFunctions {
+			er.err = ErrNotSupportedYet.GenWithStackByArgs("ILIKE when tidb_enable_pg_compat_functions is OFF")
+			return
+		}
+		// ILIKE is translated to LIKE on the lower cases of the expression and the pattern.
+		for i := l - 2; i < l; i++ {
+			er.ctxStack[i], er.err = er.newFunction(ast.Lower, types.NewFieldType(mysql.TypeVarString), er.ctxStack[i])
+			if er.err != nil {
+				return
+			}
+		}
+		if escape >= 'A' && escape <= 'Z' {
+			escape += 'a' - 'A'
+		}
+	}
+
 	char, col := er.sctx.GetSessionVars().GetCharsetInfo()
 	var function expression.Expression
 	fieldType := &types.FieldType{}
@@ -1639,7 +1662,7 @@ func (er *expressionRewriter) patternLikeToExpression(v *ast.PatternLikeExpr) {
 			return
 		}
 		if !isNull {
-			patValue, patTypes := stringutil.CompilePattern(patString, v.Escape)
+			patValue, patTypes := stringutil.CompilePattern(patString, escape)
 			if stringutil.IsExactMatch(patTypes) && er.ctxStack[l-2].GetType().EvalType() == types.ETString {
 				op := ast.EQ
 				if v.Not {
@@ -1654,9 +1677,9 @@ func (er *expressionRewriter) patternLikeToExpression(v *ast.PatternLikeExpr) {
 		}
 	}
 	if !isPatternExactMatch {
-		types.DefaultTypeForValue(int(v.Escape), fieldType, char, col)
+		types.DefaultTypeForValue(int(escape), fieldType, char, col)
 		function = er.notToExpression(v.Not, ast.Like, &v.Type,
-			er.ctxStack[l-2], er.ctxStack[l-1], &expression.Constant{Value: types.NewIntDatum(int64(v.Escape)), RetType: fieldType})
+			er.ctxStack[l-2], er.ctxStack[l-1], &expression.Constant{Value: types.NewIntDatum(int64(escape)), RetType: fieldType})
 	}
 
 	er.ctxStackPop(2)
@@ -1899,6 +1922,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 			er.err = ErrUnknownColumn.GenWithStackByArgs(v.Name, clauseMsg[er.b.curClause])
 			return
 		}
+		er.b.visitColumn(column)
 		er.ctxStackAppend(column, er.names[idx])
 		return
 	}
@@ -1907,6 +1931,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		idx, err = expression.FindFieldName(outerName, v)
 		if idx >= 0 {
 			column := outerSchema.Columns[idx]
+			er.b.visitColumn(column)
 			er.ctxStackAppend(&expression.CorrelatedColumn{Column: *column, Data: new(types.Datum)}, outerName[idx])
 			return
 		}
@@ -1924,6 +1949,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		er.err = err
 		return
 	} else if col != nil {
+		er.b.visitColumn(col)
 		er.ctxStackAppend(col, name)
 		return
 	}
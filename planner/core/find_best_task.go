@@ -278,6 +278,7 @@ func (p *baseLogicalPlan) enumeratePhysicalPlans4Task(physicalPlans []PhysicalPl
 			break
 		}
 		opt.appendCandidate(p, curTask.plan(), prop)
+		opt.appendCostComparison(p, curTask, bestTask, prop)
 		// Get the most efficient one.
 		if curTask.cost() < bestTask.cost() || (bestTask.invalid() && !curTask.invalid()) {
 			bestTask = curTask
@@ -326,6 +327,22 @@ func (op *physicalOptimizeOp) appendCandidate(lp LogicalPlan, pp PhysicalPlan, p
 	op.tracer.State[name] = pps
 }
 
+// appendCostComparison records the cost comparison between the candidate task and the best task
+// found so far for the logical plan.
+func (op *physicalOptimizeOp) appendCostComparison(lp LogicalPlan, candidate, best task, prop *property.PhysicalProperty) {
+	if op == nil || op.tracer == nil || candidate == nil || candidate.invalid() {
+		return
+	}
+	var bestTrace *tracing.PlanTrace
+	bestCost := math.MaxFloat64
+	if best != nil && !best.invalid() {
+		bestTrace = best.plan().buildPlanTrace()
+		bestCost = best.cost()
+	}
+	op.tracer.AppendCostComparison(tracing.CodecPlanName(lp.TP(), lp.ID()), prop.String(),
+		candidate.plan().buildPlanTrace(), candidate.cost(), bestTrace, bestCost, candidate.cost() < bestCost || bestTrace == nil)
+}
+
 // findBestTask implements LogicalPlan interface.
 func (p *baseLogicalPlan) findBestTask(prop *property.PhysicalProperty, planCounter *PlanCounterTp, opt *physicalOptimizeOp) (bestTask task, cntPlan int64, err error) {
 	// If p is an inner plan in an IndexJoin, the IndexJoin will generate an inner plan by itself,
@@ -416,6 +433,7 @@ func (p *baseLogicalPlan) findBestTask(prop *property.PhysicalProperty, planCoun
 		goto END
 	}
 	opt.appendCandidate(p, curTask.plan(), prop)
+	opt.appendCostComparison(p, curTask, bestTask, prop)
 	if curTask.cost() < bestTask.cost() || (bestTask.invalid() && !curTask.invalid()) {
 		bestTask = curTask
 	}
@@ -680,6 +698,61 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 	return candidates
 }
 
+// prunedReason explains why the candidate is pruned by the winner in the skyline pruning.
+func prunedReason(winner, candidate *candidatePath) string {
+	reasons := make([]string, 0, 3)
+	if accessResult, _ := util.CompareCol2Len(winner.accessCondsColMap, candidate.accessCondsColMap); accessResult > 0 {
+		reasons = append(reasons, "more access columns")
+	}
+	if scanResult, _ := compareIndexBack(winner, candidate); scanResult > 0 {
+		if winner.path.IsSingleScan && !candidate.path.IsSingleScan {
+			reasons = append(reasons, "single scan")
+		} else {
+			reasons = append(reasons, "fewer rows to look up")
+		}
+	}
+	if compareBool(winner.isMatchProp, candidate.isMatchProp) > 0 {
+		reasons = append(reasons, "matching the property")
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// getPrunedPathsInfo explains why each path in ds.possibleAccessPaths is not in the candidates.
+func (ds *DataSource) getPrunedPathsInfo(candidates []*candidatePath, prop *property.PhysicalProperty, getPathName func(*util.AccessPath) string) []string {
+	remained := make(map[*util.AccessPath]struct{}, len(candidates))
+	for _, cand := range candidates {
+		remained[cand.path] = struct{}{}
+	}
+	infos := make([]string, 0, len(ds.possibleAccessPaths)-len(candidates))
+	for _, path := range ds.possibleAccessPaths {
+		if _, ok := remained[path]; ok || path.PartialIndexPaths != nil {
+			continue
+		}
+		if path.StoreType == kv.TiFlash || path.IsTiFlashGlobalRead || prop.IsFlashProp() {
+			// The paths of the other store types are not comparable with the candidates.
+			continue
+		}
+		var current *candidatePath
+		if path.IsTablePath() {
+			current = ds.getTableCandidate(path, prop)
+		} else if len(path.AccessConds) > 0 || !prop.IsEmpty() || path.Forced || path.IsSingleScan {
+			current = ds.getIndexCandidate(path, prop)
+		} else {
+			infos = append(infos, fmt.Sprintf("%s is pruned since it has no access conditions and needs double scan", getPathName(path)))
+			continue
+		}
+		reason := fmt.Sprintf("%s is pruned since range scans are preferred to its full range scan", getPathName(path))
+		for _, cand := range candidates {
+			if cand.path.StoreType != kv.TiFlash && compareCandidates(cand, current) == 1 {
+				reason = fmt.Sprintf("%s is pruned by %s with %s", getPathName(path), getPathName(cand.path), prunedReason(cand, current))
+				break
+			}
+		}
+		infos = append(infos, reason)
+	}
+	return infos
+}
+
 func (ds *DataSource) getPruningInfo(candidates []*candidatePath, prop *property.PhysicalProperty) string {
 	if !ds.ctx.GetSessionVars().StmtCtx.InVerboseExplain || len(candidates) == len(ds.possibleAccessPaths) {
 		return ""
@@ -719,8 +792,14 @@ func (ds *DataSource) getPruningInfo(candidates []*candidatePath, prop *property
 	for _, item := range prop.SortItems {
 		items = append(items, item.String())
 	}
-	return fmt.Sprintf("[%s] remain after pruning paths for %s given Prop{SortItems: [%s], TaskTp: %s}",
+	info := fmt.Sprintf("[%s] remain after pruning paths for %s given Prop{SortItems: [%s], TaskTp: %s}",
 		strings.Join(names, ","), tableName, strings.Join(items, " "), prop.TaskTp)
+	if ds.ctx.GetSessionVars().ExplainPrunedPaths {
+		if prunedInfos := ds.getPrunedPathsInfo(candidates, prop, getSimplePathName); len(prunedInfos) > 0 {
+			info += ": " + strings.Join(prunedInfos, "; ")
+		}
+	}
+	return info
 }
 
 func (ds *DataSource) isPointGetConvertableSchema() bool {
@@ -822,6 +901,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 				planCounter.Dec(1)
 			}
 			appendCandidate(ds, idxMergeTask, prop, opt)
+			opt.appendCostComparison(ds, idxMergeTask, t, prop)
 			if idxMergeTask.cost() < t.cost() || planCounter.Empty() {
 				t = idxMergeTask
 			}
@@ -845,6 +925,9 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 			}, cntPlan, nil
 		}
 		canConvertPointGet := len(path.Ranges) > 0 && path.StoreType == kv.TiKV && ds.isPointGetConvertableSchema()
+		// The point get reads from the snapshot of the statement, so it is not used for the tables
+		// read at their own snapshots.
+		canConvertPointGet = canConvertPointGet && ds.snapshotTS == 0
 
 		if canConvertPointGet && expression.MaybeOverOptimized4PlanCache(ds.ctx, path.AccessConds) {
 			canConvertPointGet = ds.canConvertToPointGetForPlanCache(path)
@@ -864,8 +947,9 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 		}
 		var hashPartColName *ast.ColumnName
 		if tblInfo := ds.table.Meta(); canConvertPointGet && tblInfo.GetPartitionInfo() != nil {
-			// We do not build [batch] point get for dynamic table partitions now. This can be optimized.
-			if ds.ctx.GetSessionVars().UseDynamicPartitionPrune() {
+			// We only build batch point get for dynamic table partitions now, its executor locates the partition
+			// of each key. This is not applicable if the partitions are restricted by the PARTITION clause.
+			if ds.ctx.GetSessionVars().UseDynamicPartitionPrune() && (len(path.Ranges) <= 1 || len(ds.partitionNames) > 0) {
 				canConvertPointGet = false
 			}
 			if canConvertPointGet && len(path.Ranges) > 1 {
@@ -906,6 +990,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 					pointGetTask = ds.convertToBatchPointGet(prop, candidate, hashPartColName, opt)
 				}
 				appendCandidate(ds, pointGetTask, prop, opt)
+				opt.appendCostComparison(ds, pointGetTask, t, prop)
 				if !pointGetTask.invalid() {
 					cntPlan += 1
 					planCounter.Dec(1)
@@ -940,6 +1025,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 				planCounter.Dec(1)
 			}
 			appendCandidate(ds, tblTask, prop, opt)
+			opt.appendCostComparison(ds, tblTask, t, prop)
 			if tblTask.cost() < t.cost() || planCounter.Empty() {
 				t = tblTask
 			}
@@ -961,6 +1047,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 			planCounter.Dec(1)
 		}
 		appendCandidate(ds, idxTask, prop, opt)
+		opt.appendCostComparison(ds, idxTask, t, prop)
 		if idxTask.cost() < t.cost() || planCounter.Empty() {
 			t = idxTask
 		}
@@ -1157,6 +1244,8 @@ func (ds *DataSource) buildIndexMergeTableScan(prop *property.PhysicalProperty,
 		Table:           ds.tableInfo,
 		Columns:         ds.Columns,
 		TableAsName:     ds.TableAsName,
+		viewPath:        ds.viewPath,
+		SnapshotTS:      ds.snapshotTS,
 		DBName:          ds.DBName,
 		isPartition:     ds.isPartition,
 		physicalTableID: ds.physicalTableID,
@@ -1315,6 +1404,8 @@ func (ds *DataSource) convertToIndexScan(prop *property.PhysicalProperty,
 			Columns:         ds.Columns,
 			Table:           is.Table,
 			TableAsName:     ds.TableAsName,
+			viewPath:        ds.viewPath,
+			SnapshotTS:      ds.snapshotTS,
 			isPartition:     ds.isPartition,
 			physicalTableID: ds.physicalTableID,
 		}.Init(ds.ctx, is.blockOffset)
@@ -1723,6 +1814,9 @@ func getMostCorrCol4Index(path *util.AccessPath, histColl *statistics.Table, thr
 		colSet.Insert(col.UniqueID)
 		curCorr := float64(0)
 		for _, item := range histColl.ExtendedStats.Stats {
+			if item.Tp != ast.StatsTypeCorrelation {
+				continue
+			}
 			if (col.ID == item.ColIDs[0] && path.FullIdxCols[0].ID == item.ColIDs[1]) ||
 				(col.ID == item.ColIDs[1] && path.FullIdxCols[0].ID == item.ColIDs[0]) {
 				curCorr = item.ScalarVals
@@ -1747,6 +1841,8 @@ func (s *LogicalTableScan) GetPhysicalScan(schema *expression.Schema, stats *pro
 		Table:           ds.tableInfo,
 		Columns:         ds.Columns,
 		TableAsName:     ds.TableAsName,
+		viewPath:        ds.viewPath,
+		SnapshotTS:      ds.snapshotTS,
 		DBName:          ds.DBName,
 		isPartition:     ds.isPartition,
 		physicalTableID: ds.physicalTableID,
@@ -1771,6 +1867,8 @@ func (s *LogicalIndexScan) GetPhysicalIndexScan(schema *expression.Schema, stats
 	is := PhysicalIndexScan{
 		Table:            ds.tableInfo,
 		TableAsName:      ds.TableAsName,
+		viewPath:         ds.viewPath,
+		SnapshotTS:       ds.snapshotTS,
 		DBName:           ds.DBName,
 		Columns:          s.Columns,
 		Index:            s.Index,
@@ -2100,6 +2198,8 @@ func (ds *DataSource) getOriginalPhysicalTableScan(prop *property.PhysicalProper
 		Table:           ds.tableInfo,
 		Columns:         ds.Columns,
 		TableAsName:     ds.TableAsName,
+		viewPath:        ds.viewPath,
+		SnapshotTS:      ds.snapshotTS,
 		DBName:          ds.DBName,
 		isPartition:     ds.isPartition,
 		physicalTableID: ds.physicalTableID,
@@ -2180,6 +2280,8 @@ func (ds *DataSource) getOriginalPhysicalIndexScan(prop *property.PhysicalProper
 	is := PhysicalIndexScan{
 		Table:            ds.tableInfo,
 		TableAsName:      ds.TableAsName,
+		viewPath:         ds.viewPath,
+		SnapshotTS:       ds.snapshotTS,
 		DBName:           ds.DBName,
 		Columns:          ds.Columns,
 		Index:            idx,
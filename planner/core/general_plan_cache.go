@@ -0,0 +1,163 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+)
+
+// ParameterizeForGeneralPlanCache replaces the constants in the WHERE clause of a SELECT, UPDATE or DELETE
+// statement with parameter markers, so the text protocol statements which only differ in these constants
+// can share a prepared statement and its cached plans. It returns the parameterized SQL and the constants
+// in the order of the parameter markers. The statement is left unchanged.
+func ParameterizeForGeneralPlanCache(sctx sessionctx.Context, stmt ast.StmtNode) (string, []types.Datum, bool) {
+	var where *ast.ExprNode
+	switch x := stmt.(type) {
+	case *ast.SelectStmt:
+		where = &x.Where
+	case *ast.UpdateStmt:
+		where = &x.Where
+	case *ast.DeleteStmt:
+		where = &x.Where
+	default:
+		return "", nil, false
+	}
+	if *where == nil {
+		return "", nil, false
+	}
+	var checker paramMarkerChecker
+	stmt.Accept(&checker)
+	if checker.hasMarker {
+		// It's already a prepared statement.
+		return "", nil, false
+	}
+
+	charset, collation := sctx.GetSessionVars().GetCharsetInfo()
+	replacer := &constantReplacer{charset: charset, collation: collation}
+	node, _ := (*where).Accept(replacer)
+	*where = node.(ast.ExprNode)
+	if len(replacer.replaced) == 0 {
+		return "", nil, false
+	}
+
+	var sb strings.Builder
+	restoreCtx := format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)
+	err := stmt.Restore(restoreCtx)
+
+	// Put the constants back, the visitor returns the original constants in place of the markers.
+	node, _ = (*where).Accept(&constantRestorer{})
+	*where = node.(ast.ExprNode)
+	if err != nil || len(replacer.restored) != len(replacer.replaced) {
+		return "", nil, false
+	}
+	params := make([]types.Datum, 0, len(replacer.restored))
+	for _, marker := range replacer.restored {
+		params = append(params, marker.Datum)
+	}
+	return sb.String(), params, true
+}
+
+// generalParamMarker wraps a constant replaced by a parameter marker. It's restored as "?" and records
+// the restoring order, which is the order of the parameter markers in the parameterized statement.
+type generalParamMarker struct {
+	*driver.ValueExpr
+	replacer *constantReplacer
+}
+
+// Restore implements Node interface.
+func (n *generalParamMarker) Restore(ctx *format.RestoreCtx) error {
+	n.replacer.restored = append(n.replacer.restored, n)
+	ctx.WritePlain("?")
+	return nil
+}
+
+// constantReplacer replaces the constants which can be parameters without changing the semantics.
+type constantReplacer struct {
+	charset   string
+	collation string
+	replaced  []*generalParamMarker
+	restored  []*generalParamMarker
+}
+
+func (r *constantReplacer) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.FieldList, *ast.OrderByClause, *ast.GroupByClause, *ast.HavingClause, *ast.Limit,
+		*ast.TableName, *ast.AsOfClause, *ast.SetCollationExpr, *ast.WindowFuncExpr, *ast.AggregateFuncExpr, *ast.MatchAgainst:
+		// The constants here are part of the plan, or the collation of them matters.
+		return in, true
+	case *ast.FuncCallExpr:
+		switch x.FnName.L {
+		case ast.CharFunc, ast.Convert, ast.WeightString, ast.GetFormat:
+			// Some arguments of these functions are keywords.
+			return in, true
+		}
+	}
+	return in, false
+}
+
+func (r *constantReplacer) Leave(in ast.Node) (ast.Node, bool) {
+	v, ok := in.(*driver.ValueExpr)
+	if !ok {
+		return in, true
+	}
+	switch v.Kind() {
+	case types.KindInt64, types.KindUint64, types.KindFloat32, types.KindFloat64, types.KindMysqlDecimal:
+	case types.KindString:
+		// The string with a charset introducer or collation is not the same as a parameter.
+		if v.Type.Charset != r.charset || v.Type.Collate != r.collation {
+			return in, true
+		}
+	default:
+		return in, true
+	}
+	marker := &generalParamMarker{ValueExpr: v, replacer: r}
+	r.replaced = append(r.replaced, marker)
+	return marker, true
+}
+
+// constantRestorer puts the constants replaced by constantReplacer back. The markers delegate Accept to the
+// constants they wrap, so returning the visited node is enough.
+type constantRestorer struct{}
+
+func (r *constantRestorer) Enter(in ast.Node) (ast.Node, bool) {
+	return in, false
+}
+
+func (r *constantRestorer) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// paramMarkerChecker checks whether a statement has parameter markers.
+type paramMarkerChecker struct {
+	hasMarker bool
+}
+
+func (e *paramMarkerChecker) Enter(in ast.Node) (ast.Node, bool) {
+	if _, ok := in.(*driver.ParamMarkerExpr); ok {
+		e.hasMarker = true
+		return in, true
+	}
+	return in, e.hasMarker
+}
+
+func (e *paramMarkerChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
@@ -15,6 +15,9 @@
 package core
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
@@ -41,6 +44,114 @@ func GenHintsFromPhysicalPlan(p Plan) []*ast.TableOptimizerHint {
 	return hints
 }
 
+// viewQueryBlock is a view referenced in a query block. The operators built from a view record the views they are
+// in from the outermost one, the hints generated for them reach them through the view path of a QB_NAME hint.
+type viewQueryBlock struct {
+	dbName   model.CIStr
+	viewName model.CIStr
+	// asName is the name of the view in the query block, which is used by the join hints.
+	asName model.CIStr
+	// blockOffset is the offset of the query block which references the view.
+	blockOffset int
+}
+
+func (v *viewQueryBlock) equal(other *viewQueryBlock) bool {
+	return v.dbName.L == other.dbName.L && v.viewName.L == other.viewName.L && v.asName.L == other.asName.L && v.blockOffset == other.blockOffset
+}
+
+// physicalViewPath returns the views which the plan is in, from the outermost one.
+func physicalViewPath(p PhysicalPlan) []*viewQueryBlock {
+	switch x := p.(type) {
+	case *PhysicalTableReader:
+		if ts, ok := x.TablePlans[0].(*PhysicalTableScan); ok {
+			return ts.viewPath
+		}
+		return nil
+	case *PhysicalIndexReader:
+		return x.IndexPlans[0].(*PhysicalIndexScan).viewPath
+	case *PhysicalIndexLookUpReader:
+		return x.IndexPlans[0].(*PhysicalIndexScan).viewPath
+	case *PhysicalIndexMergeReader:
+		switch scan := x.PartialPlans[0][0].(type) {
+		case *PhysicalIndexScan:
+			return scan.viewPath
+		case *PhysicalTableScan:
+			return scan.viewPath
+		}
+		return nil
+	case *PhysicalHashAgg:
+		return x.viewPath
+	case *PhysicalStreamAgg:
+		return x.viewPath
+	}
+	// The other operators are in the views shared by all their children.
+	var path []*viewQueryBlock
+	for i, child := range p.Children() {
+		childPath := physicalViewPath(child)
+		if i == 0 {
+			path = childPath
+			continue
+		}
+		n := 0
+		for n < len(path) && n < len(childPath) && path[n].equal(childPath[n]) {
+			n++
+		}
+		path = path[:n]
+	}
+	return path
+}
+
+// hintGenerator generates the hints from a physical plan.
+type hintGenerator struct {
+	nodeType utilhint.NodeType
+	// viewQBNames are the query block names of the query blocks in views, which are defined by the viewQBHints.
+	viewQBNames map[string]model.CIStr
+	viewQBHints []*ast.TableOptimizerHint
+}
+
+// qbName returns the name of the query block at blockOffset. If the query block is in a view, a QB_NAME hint with
+// the view path like `qb_name(qb_v1, v1@sel_1 .v2@sel_2 .@sel_1)` is generated to name it.
+func (g *hintGenerator) qbName(viewPath []*viewQueryBlock, blockOffset int) (model.CIStr, error) {
+	if len(viewPath) == 0 {
+		return utilhint.GenerateQBName(g.nodeType, blockOffset)
+	}
+	tables := make([]ast.HintTable, 0, len(viewPath)+1)
+	var key strings.Builder
+	for i, view := range viewPath {
+		// Only the first view is referenced by the query block of the statement, the others are in views.
+		nodeType := utilhint.TypeSelect
+		if i == 0 {
+			nodeType = g.nodeType
+		}
+		qbName, err := utilhint.GenerateQBName(nodeType, view.blockOffset)
+		if err != nil {
+			return model.CIStr{}, err
+		}
+		tables = append(tables, ast.HintTable{TableName: view.viewName, QBName: qbName})
+		fmt.Fprintf(&key, "%s@%s.", view.viewName.L, qbName.L)
+	}
+	qbName, err := utilhint.GenerateQBName(utilhint.TypeSelect, blockOffset)
+	if err != nil {
+		return model.CIStr{}, err
+	}
+	tables = append(tables, ast.HintTable{QBName: qbName})
+	key.WriteString(qbName.L)
+	if name, ok := g.viewQBNames[key.String()]; ok {
+		return name, nil
+	}
+	if g.viewQBNames == nil {
+		g.viewQBNames = make(map[string]model.CIStr)
+	}
+	name := model.NewCIStr(fmt.Sprintf("qb_v%d", len(g.viewQBNames)+1))
+	g.viewQBNames[key.String()] = name
+	g.viewQBHints = append(g.viewQBHints, &ast.TableOptimizerHint{
+		QBName:   name,
+		HintName: model.NewCIStr(utilhint.HintQBName),
+		Tables:   tables,
+	})
+	return name, nil
+}
+
 func getTableName(tblName model.CIStr, asName *model.CIStr) model.CIStr {
 	if asName != nil && asName.L != "" {
 		return *asName
@@ -80,17 +191,31 @@ func extractTableAsName(p PhysicalPlan) (*model.CIStr, *model.CIStr) {
 	return nil, nil
 }
 
-func getJoinHints(sctx sessionctx.Context, joinType string, parentOffset int, nodeType utilhint.NodeType, children ...PhysicalPlan) (res []*ast.TableOptimizerHint) {
+func (g *hintGenerator) getJoinHints(sctx sessionctx.Context, joinType string, parent PhysicalPlan, children ...PhysicalPlan) (res []*ast.TableOptimizerHint) {
+	parentOffset := parent.SelectBlockOffset()
 	if parentOffset == -1 {
 		return res
 	}
+	parentPath := physicalViewPath(parent)
 	for _, child := range children {
 		blockOffset := child.SelectBlockOffset()
 		if blockOffset == -1 {
 			continue
 		}
 		var dbName, tableName *model.CIStr
-		if blockOffset != parentOffset {
+		if childPath := physicalViewPath(child); len(childPath) > len(parentPath) {
+			// The child is read from a view, which is referenced by the query block of the join or its sub-queries.
+			view := childPath[len(parentPath)]
+			if view.blockOffset == parentOffset {
+				dbName, tableName = &view.dbName, &view.asName
+			}
+			blockOffset = view.blockOffset
+		}
+		if tableName == nil && blockOffset != parentOffset {
+			// The aliases of the sub-queries in views are not kept.
+			if len(parentPath) > 0 {
+				continue
+			}
 			blockAsNames := sctx.GetSessionVars().PlannerSelectBlockAsName
 			if blockOffset >= len(blockAsNames) {
 				continue
@@ -98,13 +223,13 @@ func getJoinHints(sctx sessionctx.Context, joinType string, parentOffset int, no
 			hintTable := blockAsNames[blockOffset]
 			// For sub-queries like `(select * from t) t1`, t1 should belong to its surrounding select block.
 			dbName, tableName, blockOffset = &hintTable.DBName, &hintTable.TableName, parentOffset
-		} else {
+		} else if tableName == nil {
 			dbName, tableName = extractTableAsName(child)
 		}
 		if tableName == nil || tableName.L == "" {
 			continue
 		}
-		qbName, err := utilhint.GenerateQBName(nodeType, blockOffset)
+		qbName, err := g.qbName(parentPath, parentOffset)
 		if err != nil {
 			continue
 		}
@@ -118,19 +243,32 @@ func getJoinHints(sctx sessionctx.Context, joinType string, parentOffset int, no
 	return res
 }
 
-func genHintsFromPhysicalPlan(p PhysicalPlan, nodeType utilhint.NodeType) (res []*ast.TableOptimizerHint) {
+func genHintsFromPhysicalPlan(p PhysicalPlan, nodeType utilhint.NodeType) []*ast.TableOptimizerHint {
+	g := &hintGenerator{nodeType: nodeType}
+	res := g.genHints(p)
+	// The query blocks in views are named before the hints use them.
+	return append(g.viewQBHints, res...)
+}
+
+func (g *hintGenerator) genHints(p PhysicalPlan) (res []*ast.TableOptimizerHint) {
 	if p == nil {
 		return res
 	}
 	for _, child := range p.Children() {
-		res = append(res, genHintsFromPhysicalPlan(child, nodeType)...)
+		res = append(res, g.genHints(child)...)
 	}
 	if phCte, ok := p.(*PhysicalCTE); ok {
-		res = append(res, genHintsFromPhysicalPlan(phCte.CTE.seedPartPhysicalPlan, nodeType)...)
-		res = append(res, genHintsFromPhysicalPlan(phCte.CTE.recursivePartPhysicalPlan, nodeType)...)
+		res = append(res, g.genHints(phCte.CTE.seedPartPhysicalPlan)...)
+		res = append(res, g.genHints(phCte.CTE.recursivePartPhysicalPlan)...)
 	}
 
-	qbName, err := utilhint.GenerateQBName(nodeType, p.SelectBlockOffset())
+	switch p.(type) {
+	case *PhysicalTableReader, *PhysicalIndexLookUpReader, *PhysicalIndexReader, *PhysicalIndexMergeReader,
+		*PhysicalHashAgg, *PhysicalStreamAgg:
+	default:
+		return g.genJoinHints(p, res)
+	}
+	qbName, err := g.qbName(physicalViewPath(p), p.SelectBlockOffset())
 	if err != nil {
 		return res
 	}
@@ -197,16 +335,22 @@ func genHintsFromPhysicalPlan(p PhysicalPlan, nodeType utilhint.NodeType) (res [
 			QBName:   qbName,
 			HintName: model.NewCIStr(HintStreamAgg),
 		})
+	}
+	return res
+}
+
+func (g *hintGenerator) genJoinHints(p PhysicalPlan, res []*ast.TableOptimizerHint) []*ast.TableOptimizerHint {
+	switch pp := p.(type) {
 	case *PhysicalMergeJoin:
-		res = append(res, getJoinHints(p.SCtx(), HintSMJ, p.SelectBlockOffset(), nodeType, pp.children...)...)
+		res = append(res, g.getJoinHints(p.SCtx(), HintSMJ, p, pp.children...)...)
 	case *PhysicalHashJoin:
-		res = append(res, getJoinHints(p.SCtx(), HintHJ, p.SelectBlockOffset(), nodeType, pp.children...)...)
+		res = append(res, g.getJoinHints(p.SCtx(), HintHJ, p, pp.children...)...)
 	case *PhysicalIndexJoin:
-		res = append(res, getJoinHints(p.SCtx(), HintINLJ, p.SelectBlockOffset(), nodeType, pp.children[pp.InnerChildIdx])...)
+		res = append(res, g.getJoinHints(p.SCtx(), HintINLJ, p, pp.children[pp.InnerChildIdx])...)
 	case *PhysicalIndexMergeJoin:
-		res = append(res, getJoinHints(p.SCtx(), HintINLMJ, p.SelectBlockOffset(), nodeType, pp.children[pp.InnerChildIdx])...)
+		res = append(res, g.getJoinHints(p.SCtx(), HintINLMJ, p, pp.children[pp.InnerChildIdx])...)
 	case *PhysicalIndexHashJoin:
-		res = append(res, getJoinHints(p.SCtx(), HintINLHJ, p.SelectBlockOffset(), nodeType, pp.children[pp.InnerChildIdx])...)
+		res = append(res, g.getJoinHints(p.SCtx(), HintINLHJ, p, pp.children[pp.InnerChildIdx])...)
 	}
 	return res
 }
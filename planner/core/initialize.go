@@ -155,6 +155,14 @@ func (p PhysicalTopN) Init(ctx sessionctx.Context, stats *property.StatsInfo, of
 	return &p
 }
 
+// Init initializes PhysicalReservoirSample.
+func (p PhysicalReservoirSample) Init(ctx sessionctx.Context, stats *property.StatsInfo, offset int, props ...*property.PhysicalProperty) *PhysicalReservoirSample {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeReservoirSample, &p, offset)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
 // Init initializes LogicalLimit.
 func (p LogicalLimit) Init(ctx sessionctx.Context, offset int) *LogicalLimit {
 	p.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeLimit, &p, offset)
@@ -467,6 +475,8 @@ func (p PhysicalIndexMergeJoin) Init(ctx sessionctx.Context) *PhysicalIndexMerge
 	p.tp = plancodec.TypeIndexMergeJoin
 	p.id = ctx.GetSessionVars().PlanID
 	p.ctx = ctx
+	// Point self to the join itself rather than the embedded index join, which has no children.
+	p.self = &p
 	return &p
 }
 
@@ -476,6 +486,8 @@ func (p PhysicalIndexHashJoin) Init(ctx sessionctx.Context) *PhysicalIndexHashJo
 	p.tp = plancodec.TypeIndexHashJoin
 	p.id = ctx.GetSessionVars().PlanID
 	p.ctx = ctx
+	// Point self to the join itself rather than the embedded index join, which has no children.
+	p.self = &p
 	return &p
 }
 
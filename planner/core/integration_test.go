@@ -17,6 +17,7 @@ package core_test
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"strings"
 
 	. "github.com/pingcap/check"
@@ -36,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testutil"
 )
@@ -974,6 +976,48 @@ func (s *testIntegrationSerialSuite) TestMPPWithBroadcastExchangeUnderNewCollati
 	}
 }
 
+func (s *testIntegrationSerialSuite) TestMPPJoinHints(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+
+	// Create virtual tiflash replica info.
+	dom := domain.GetDomain(tk.Se)
+	is := dom.InfoSchema()
+	db, exists := is.SchemaByName(model.NewCIStr("test"))
+	c.Assert(exists, IsTrue)
+	for _, tblInfo := range db.Tables {
+		if tblInfo.Name.L == "t1" || tblInfo.Name.L == "t2" {
+			tblInfo.TiFlashReplica = &model.TiFlashReplicaInfo{
+				Count:     1,
+				Available: true,
+			}
+		}
+	}
+
+	tk.MustExec("set @@session.tidb_isolation_read_engines = 'tiflash'")
+	tk.MustExec("set @@session.tidb_allow_mpp = 1")
+	var input []string
+	var output []struct {
+		SQL  string
+		Plan []string
+		Warn []string
+	}
+	s.testData.GetTestCases(c, &input, &output)
+	for i, tt := range input {
+		s.testData.OnRecord(func() {
+			output[i].SQL = tt
+			output[i].Plan = s.testData.ConvertRowsToStrings(tk.MustQuery(tt).Rows())
+			output[i].Warn = s.testData.ConvertSQLWarnToStrings(tk.Se.GetSessionVars().StmtCtx.GetWarnings())
+		})
+		res := tk.MustQuery(tt)
+		res.Check(testkit.Rows(output[i].Plan...))
+		c.Assert(s.testData.ConvertSQLWarnToStrings(tk.Se.GetSessionVars().StmtCtx.GetWarnings()), DeepEquals, output[i].Warn)
+	}
+}
+
 func (s *testIntegrationSerialSuite) TestPartitionTableDynamicModeUnderNewCollation(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
 	tk.MustExec("create database test_new_collation")
@@ -4603,6 +4647,53 @@ func (s *testIntegrationSuite) TestOutputSkylinePruningInfo(c *C) {
 	}
 }
 
+func (s *testIntegrationSuite) TestOutputSkylinePrunedPaths(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, d int, e int, f int, g int, primary key (a), unique key c_d_e (c, d, e), unique key f (f), unique key f_g (f, g), key g (g))")
+	tk.MustExec("set @@session.tidb_opt_explain_pruned_paths = 1")
+
+	var input []string
+	var output []struct {
+		SQL      string
+		Warnings []string
+	}
+	s.testData.GetTestCases(c, &input, &output)
+	for i, tt := range input {
+		tk.MustQuery("explain format = 'verbose' " + tt)
+		s.testData.OnRecord(func() {
+			output[i].SQL = tt
+			output[i].Warnings = s.testData.ConvertRowsToStrings(tk.MustQuery("show warnings").Rows())
+		})
+		tk.MustQuery("show warnings").Check(testkit.Rows(output[i].Warnings...))
+	}
+}
+
+func (s *testIntegrationSuite) TestAccessPathHint(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, d int, e int, f int, g int, primary key (a), unique key c_d_e (c, d, e), unique key f (f), unique key f_g (f, g), key g (g))")
+
+	var input []string
+	var output []struct {
+		SQL      string
+		Plan     []string
+		Warnings []string
+	}
+	s.testData.GetTestCases(c, &input, &output)
+	for i, tt := range input {
+		s.testData.OnRecord(func() {
+			output[i].SQL = tt
+			output[i].Plan = s.testData.ConvertRowsToStrings(tk.MustQuery("explain format = 'verbose' " + tt).Rows())
+			output[i].Warnings = s.testData.ConvertRowsToStrings(tk.MustQuery("show warnings").Rows())
+		})
+		tk.MustQuery("explain format = 'verbose' " + tt).Check(testkit.Rows(output[i].Plan...))
+		tk.MustQuery("show warnings").Check(testkit.Rows(output[i].Warnings...))
+	}
+}
+
 func (s *testIntegrationSuite) TestPreferRangeScanForUnsignedIntHandle(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -5349,3 +5440,285 @@ func (s *testIntegrationSuite) TestIssue31202(c *C) {
 		"└─TableFullScan 10000.00 cop[tikv] table:t31202 keep order:false, stats:pseudo"))
 	tk.MustExec("drop table if exists t31202")
 }
+
+func (s *testIntegrationSuite) TestTableMapping(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_old, t_new")
+	tk.MustExec("create table t_old(a int, b int)")
+	tk.MustExec("create table t_new(a int, b int)")
+	tk.MustExec("insert into t_old values (1, 1)")
+
+	tk.MustGetErrCode("set @@tidb_table_mapping = 't_old=test.t_new'", mysql.ErrWrongValueForVar)
+	tk.MustExec("set @@tidb_table_mapping = 'test.t_old=test.t_new'")
+	tk.MustExec("insert into t_old values (2, 2)")
+	tk.MustExec("update t_old set t_old.b = 3 where a = 2")
+	tk.MustQuery("select t_old.a, b from t_old").Check(testkit.Rows("2 3"))
+	tk.MustQuery("select t.a from test.t_old t").Check(testkit.Rows("2"))
+	tk.MustExec("delete from t_old")
+	tk.MustQuery("select * from t_new").Check(testkit.Rows())
+	tk.MustExec("insert into t_old values (2, 2)")
+
+	// The prepared statements follow the mapping when they are executed.
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer core.SetPreparedPlanCache(orgEnable)
+	for _, enablePlanCache := range []bool{false, true} {
+		core.SetPreparedPlanCache(enablePlanCache)
+		tk1 := testkit.NewTestKit(c, s.store)
+		var err error
+		tk1.Se, err = session.CreateSession4TestWithOpt(s.store, &session.Opt{
+			PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+		})
+		c.Assert(err, IsNil)
+		tk1.MustExec("use test")
+		tk1.MustExec("set @@tidb_table_mapping = 'test.t_old=test.t_new'")
+		tk1.MustExec("prepare s from 'select a from t_old where a = ?'")
+		tk1.MustExec("prepare i from 'insert into t_old values (?, ?)'")
+		tk1.MustExec("set @a = 1")
+		tk1.MustQuery("execute s using @a").Check(testkit.Rows())
+		tk1.MustExec("set @a = 2")
+		tk1.MustQuery("execute s using @a").Check(testkit.Rows("2"))
+		tk1.MustExec("set @@tidb_table_mapping = ''")
+		tk1.MustExec("set @a = 1")
+		tk1.MustQuery("execute s using @a").Check(testkit.Rows("1"))
+		tk1.MustExec("set @a = 2")
+		tk1.MustQuery("execute s using @a").Check(testkit.Rows())
+		tk1.MustExec("set @a = 3")
+		tk1.MustExec("execute i using @a, @a")
+		tk1.MustExec("set @@tidb_table_mapping = 'test.t_old=test.t_new'")
+		tk1.MustExec("set @a = 4")
+		tk1.MustExec("execute i using @a, @a")
+		tk1.MustQuery("select a from t_old").Sort().Check(testkit.Rows("2", "4"))
+		tk1.MustExec("set @@tidb_table_mapping = ''")
+		tk1.MustQuery("select a from t_old").Sort().Check(testkit.Rows("1", "3"))
+		tk1.MustExec("delete from t_old where a = 3")
+		tk1.MustExec("delete from t_new where a = 4")
+	}
+	tk.MustExec("delete from t_new")
+
+	// DDL statements are not mapped.
+	tk.MustExec("alter table t_old add column c int")
+	tk.MustExec("set @@tidb_table_mapping = ''")
+	tk.MustQuery("select * from t_old").Check(testkit.Rows("1 1 <nil>"))
+	tk.MustExec("drop table t_old, t_new")
+}
+
+func (s *testIntegrationSuite) TestViewHint(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop view if exists v, v1, v2")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t(a int, b int, key(a))")
+	tk.MustExec("create table t1(a int, b int, key(a))")
+	tk.MustExec("create definer='root'@'localhost' view v as select t.a, t1.b from t join t1 on t.a = t1.a")
+	tk.MustExec("create definer='root'@'localhost' view v1 as select a, count(*) from t where a in (select a from v) group by a")
+	tk.MustExec("create definer='root'@'localhost' view v2 as select * from t where a in (select t1.a from t1 join t t2 on t1.a = t2.a)")
+
+	// The hints take effect in the top query block of the view.
+	tk.MustQuery("explain format = 'brief' select * from v").Check(testkit.Rows(
+		"HashJoin 12487.50 root  inner join, equal:[eq(test.t.a, test.t1.a)]",
+		"├─TableReader(Build) 9990.00 root  data:Selection",
+		"│ └─Selection 9990.00 cop[tikv]  not(isnull(test.t1.a))",
+		"│   └─TableFullScan 10000.00 cop[tikv] table:t1 keep order:false, stats:pseudo",
+		"└─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"  └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:false, stats:pseudo"))
+	tk.MustQuery("explain format = 'brief' select /*+ qb_name(qb_v, v@sel_1), merge_join(t@qb_v) */ * from v").Check(testkit.Rows(
+		"MergeJoin 12487.50 root  inner join, left key:test.t.a, right key:test.t1.a",
+		"├─Projection(Build) 9990.00 root  test.t1.a, test.t1.b",
+		"│ └─IndexLookUp 9990.00 root  ",
+		"│   ├─IndexFullScan(Build) 9990.00 cop[tikv] table:t1, index:a(a) keep order:true, stats:pseudo",
+		"│   └─TableRowIDScan(Probe) 9990.00 cop[tikv] table:t1 keep order:false, stats:pseudo",
+		"└─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"  └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:true, stats:pseudo"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustQuery("explain format = 'brief' select /*+ qb_name(qb_v, v), inl_join(@qb_v t1) */ * from v").Check(testkit.Rows(
+		"IndexJoin 12487.50 root  inner join, inner:IndexLookUp, outer key:test.t.a, inner key:test.t1.a, equal cond:eq(test.t.a, test.t1.a)",
+		"├─IndexReader(Build) 9990.00 root  index:IndexFullScan",
+		"│ └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:false, stats:pseudo",
+		"└─IndexLookUp(Probe) 1.25 root  ",
+		"  ├─Selection(Build) 1.25 cop[tikv]  not(isnull(test.t1.a))",
+		"  │ └─IndexRangeScan 1.25 cop[tikv] table:t1, index:a(a) range: decided by [eq(test.t1.a, test.t.a)], keep order:false, stats:pseudo",
+		"  └─TableRowIDScan(Probe) 1.25 cop[tikv] table:t1 keep order:false, stats:pseudo"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustQuery("explain format = 'brief' select /*+ qb_name(qb_v1, v1), stream_agg(@qb_v1) */ * from v1").Check(testkit.Rows(
+		"Projection 7992.00 root  test.t.a, Column#10",
+		"└─StreamAgg 7992.00 root  group by:test.t.a, funcs:count(1)->Column#10, funcs:firstrow(test.t.a)->test.t.a",
+		"  └─MergeJoin 9990.00 root  inner join, left key:test.t.a, right key:test.t.a",
+		"    ├─StreamAgg(Build) 7992.00 root  group by:test.t.a, funcs:firstrow(test.t.a)->test.t.a",
+		"    │ └─MergeJoin 12487.50 root  inner join, left key:test.t.a, right key:test.t1.a",
+		"    │   ├─IndexReader(Build) 9990.00 root  index:IndexFullScan",
+		"    │   │ └─IndexFullScan 9990.00 cop[tikv] table:t1, index:a(a) keep order:true, stats:pseudo",
+		"    │   └─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"    │     └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:true, stats:pseudo",
+		"    └─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"      └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:true, stats:pseudo"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustQuery("explain format = 'brief' update /*+ qb_name(qb_v, v), merge_join(t@qb_v) */ t, v set t.b = 1 where t.a = v.a").Check(testkit.Rows(
+		"Update N/A root  N/A",
+		"└─HashJoin 15609.38 root  inner join, equal:[eq(test.t.a, Column#4)]",
+		"  ├─TableReader(Build) 9990.00 root  data:Selection",
+		"  │ └─Selection 9990.00 cop[tikv]  not(isnull(test.t.a))",
+		"  │   └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo",
+		"  └─MergeJoin(Probe) 12487.50 root  inner join, left key:test.t.a, right key:test.t1.a",
+		"    ├─Projection(Build) 9990.00 root  test.t1.a, test.t1.b",
+		"    │ └─IndexLookUp 9990.00 root  ",
+		"    │   ├─IndexFullScan(Build) 9990.00 cop[tikv] table:t1, index:a(a) keep order:true, stats:pseudo",
+		"    │   └─TableRowIDScan(Probe) 9990.00 cop[tikv] table:t1 keep order:false, stats:pseudo",
+		"    └─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"      └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:true, stats:pseudo"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	// The hints take effect in the nested view.
+	tk.MustQuery("explain format = 'brief' select * from v1").Check(testkit.Rows(
+		"Projection 7992.00 root  test.t.a, Column#10",
+		"└─HashAgg 7992.00 root  group by:test.t.a, funcs:count(1)->Column#10, funcs:firstrow(test.t.a)->test.t.a",
+		"  └─HashJoin 9990.00 root  inner join, equal:[eq(test.t.a, test.t.a)]",
+		"    ├─HashAgg(Build) 7992.00 root  group by:test.t.a, funcs:firstrow(test.t.a)->test.t.a",
+		"    │ └─MergeJoin 12487.50 root  inner join, left key:test.t.a, right key:test.t1.a",
+		"    │   ├─IndexReader(Build) 9990.00 root  index:IndexFullScan",
+		"    │   │ └─IndexFullScan 9990.00 cop[tikv] table:t1, index:a(a) keep order:true, stats:pseudo",
+		"    │   └─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"    │     └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:true, stats:pseudo",
+		"    └─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"      └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:false, stats:pseudo"))
+	tk.MustQuery("explain format = 'brief' select /*+ qb_name(qb_v, v1 .v@sel_2), hash_join(t@qb_v) */ * from v1").Check(testkit.Rows(
+		"Projection 7992.00 root  test.t.a, Column#10",
+		"└─HashAgg 7992.00 root  group by:test.t.a, funcs:count(1)->Column#10, funcs:firstrow(test.t.a)->test.t.a",
+		"  └─HashJoin 9990.00 root  inner join, equal:[eq(test.t.a, test.t.a)]",
+		"    ├─HashAgg(Build) 7992.00 root  group by:test.t.a, funcs:firstrow(test.t.a)->test.t.a",
+		"    │ └─HashJoin 12487.50 root  inner join, equal:[eq(test.t.a, test.t1.a)]",
+		"    │   ├─IndexReader(Build) 9990.00 root  index:IndexFullScan",
+		"    │   │ └─IndexFullScan 9990.00 cop[tikv] table:t1, index:a(a) keep order:false, stats:pseudo",
+		"    │   └─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"    │     └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:false, stats:pseudo",
+		"    └─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"      └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:false, stats:pseudo"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	// The hints take effect in the specified query block of the view.
+	tk.MustQuery("explain format = 'brief' select * from v2").Check(testkit.Rows(
+		"HashJoin 9990.00 root  inner join, equal:[eq(test.t.a, test.t1.a)]",
+		"├─HashAgg(Build) 7992.00 root  group by:test.t1.a, funcs:firstrow(test.t1.a)->test.t1.a",
+		"│ └─MergeJoin 12487.50 root  inner join, left key:test.t1.a, right key:test.t.a",
+		"│   ├─IndexReader(Build) 9990.00 root  index:IndexFullScan",
+		"│   │ └─IndexFullScan 9990.00 cop[tikv] table:t2, index:a(a) keep order:true, stats:pseudo",
+		"│   └─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"│     └─IndexFullScan 9990.00 cop[tikv] table:t1, index:a(a) keep order:true, stats:pseudo",
+		"└─TableReader(Probe) 9990.00 root  data:Selection",
+		"  └─Selection 9990.00 cop[tikv]  not(isnull(test.t.a))",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("explain format = 'brief' select /*+ qb_name(qb_v2, v2 .@sel_2), hash_join(t1@qb_v2) */ * from v2").Check(testkit.Rows(
+		"HashJoin 9990.00 root  inner join, equal:[eq(test.t.a, test.t1.a)]",
+		"├─HashAgg(Build) 7992.00 root  group by:test.t1.a, funcs:firstrow(test.t1.a)->test.t1.a",
+		"│ └─HashJoin 12487.50 root  inner join, equal:[eq(test.t1.a, test.t.a)]",
+		"│   ├─IndexReader(Build) 9990.00 root  index:IndexFullScan",
+		"│   │ └─IndexFullScan 9990.00 cop[tikv] table:t2, index:a(a) keep order:false, stats:pseudo",
+		"│   └─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"│     └─IndexFullScan 9990.00 cop[tikv] table:t1, index:a(a) keep order:false, stats:pseudo",
+		"└─TableReader(Probe) 9990.00 root  data:Selection",
+		"  └─Selection 9990.00 cop[tikv]  not(isnull(test.t.a))",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	// The hints are ignored if the view path doesn't match.
+	tk.MustQuery("explain format = 'brief' select /*+ qb_name(qb_v, v@sel_2), merge_join(t@qb_v) */ * from v").Check(testkit.Rows(
+		"HashJoin 12487.50 root  inner join, equal:[eq(test.t.a, test.t1.a)]",
+		"├─TableReader(Build) 9990.00 root  data:Selection",
+		"│ └─Selection 9990.00 cop[tikv]  not(isnull(test.t1.a))",
+		"│   └─TableFullScan 10000.00 cop[tikv] table:t1 keep order:false, stats:pseudo",
+		"└─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"  └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:false, stats:pseudo"))
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1105 The view path of query block name qb_v doesn't match any view, the hints are ignored"))
+	tk.MustQuery("explain format = 'brief' select /*+ qb_name(qb_v2, v2 .@sel_3), hash_join(t1@qb_v2) */ * from v2").Check(testkit.Rows(
+		"HashJoin 9990.00 root  inner join, equal:[eq(test.t.a, test.t1.a)]",
+		"├─HashAgg(Build) 7992.00 root  group by:test.t1.a, funcs:firstrow(test.t1.a)->test.t1.a",
+		"│ └─MergeJoin 12487.50 root  inner join, left key:test.t1.a, right key:test.t.a",
+		"│   ├─IndexReader(Build) 9990.00 root  index:IndexFullScan",
+		"│   │ └─IndexFullScan 9990.00 cop[tikv] table:t2, index:a(a) keep order:true, stats:pseudo",
+		"│   └─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"│     └─IndexFullScan 9990.00 cop[tikv] table:t1, index:a(a) keep order:true, stats:pseudo",
+		"└─TableReader(Probe) 9990.00 root  data:Selection",
+		"  └─Selection 9990.00 cop[tikv]  not(isnull(test.t.a))",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1105 Unknown query block name sel_3 in view v2"))
+	tk.MustQuery("select /*+ qb_name(qb_v, v1 .@sel_2 .v), merge_join(t@qb_v) */ * from v1").Check(testkit.Rows())
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1105 The view path of query block name qb_v is invalid, the hint is ignored",
+		"Warning 1105 Hint merge_join(`t`@`qb_v`) is ignored due to unknown query block name"))
+
+	// The view hints can be used in bindings.
+	tk.MustExec("create session binding for select * from v using select /*+ qb_name(qb_v, v), merge_join(t@qb_v) */ * from v")
+	tk.MustQuery("explain format = 'brief' select * from v").Check(testkit.Rows(
+		"MergeJoin 12487.50 root  inner join, left key:test.t.a, right key:test.t1.a",
+		"├─Projection(Build) 9990.00 root  test.t1.a, test.t1.b",
+		"│ └─IndexLookUp 9990.00 root  ",
+		"│   ├─IndexFullScan(Build) 9990.00 cop[tikv] table:t1, index:a(a) keep order:true, stats:pseudo",
+		"│   └─TableRowIDScan(Probe) 9990.00 cop[tikv] table:t1 keep order:false, stats:pseudo",
+		"└─IndexReader(Probe) 9990.00 root  index:IndexFullScan",
+		"  └─IndexFullScan 9990.00 cop[tikv] table:t, index:a(a) keep order:true, stats:pseudo"))
+	tk.MustExec("drop session binding for select * from v")
+}
+
+func (s *testIntegrationSuite) TestViewHintGeneration(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop view if exists v, v1, v2")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t(a int, b int, key ia(a), key ib(b))")
+	tk.MustExec("create table t1(a int, b int, key(a))")
+	tk.MustExec("create definer='root'@'localhost' view v as select /*+ use_index(t, ib) */ * from t where a = 1 and b = 1")
+	tk.MustExec("create definer='root'@'localhost' view v1 as select /*+ stream_agg() */ a, count(*) from (select t.a from t join t1 on t.a = t1.a) tt group by a")
+	tk.MustExec("create definer='root'@'localhost' view v2 as select * from t where b = 1")
+
+	// The hints of the operators in views reach them through the view paths of QB_NAME hints.
+	tk.MustQuery("explain format = 'hint' select * from v").Check(testkit.Rows(
+		"qb_name(`qb_v1`, `v`@`sel_1` .@`sel_1`), use_index(@`qb_v1` `test`.`t` `ib`)"))
+	tk.MustQuery("explain format = 'hint' select * from v1").Check(testkit.Rows(
+		"qb_name(`qb_v1`, `v1`@`sel_1` .@`sel_2`), qb_name(`qb_v2`, `v1`@`sel_1` .@`sel_1`), use_index(@`qb_v1` `test`.`t` `ia`), use_index(@`qb_v1` `test`.`t1` `a`), merge_join(@`qb_v1` `test`.`t`), stream_agg(@`qb_v2`)"))
+	tk.MustQuery("explain format = 'hint' select * from t1 where a in (select a from v)").Check(testkit.Rows(
+		"qb_name(`qb_v1`, `v`@`sel_2` .@`sel_1`), use_index(@`qb_v1` `test`.`t` `ib`), hash_agg(@`sel_2`), use_index(@`sel_1` `test`.`t1` `a`)"))
+	tk.MustQuery("explain format = 'hint' select * from v2 where a in (select a from v1)").Check(testkit.Rows(
+		"qb_name(`qb_v1`, `v2`@`sel_1` .@`sel_1`), qb_name(`qb_v2`, `v1`@`sel_2` .@`sel_2`), qb_name(`qb_v3`, `v1`@`sel_2` .@`sel_1`), use_index(@`qb_v1` `test`.`t` `ib`), use_index(@`qb_v2` `test`.`t` `ia`), use_index(@`qb_v2` `test`.`t1` `a`), merge_join(@`qb_v2` `test`.`t`), stream_agg(@`qb_v3`), hash_join(@`sel_1` `test`.`v2`)"))
+	// The join hints name the views by their aliases.
+	tk.MustQuery("explain format = 'hint' select /*+ inl_join(vv) */ * from t1, v2 vv where t1.a = vv.a").Check(testkit.Rows(
+		"qb_name(`qb_v1`, `v2`@`sel_1` .@`sel_1`), use_index(@`sel_1` `test`.`t1` ), use_index(@`qb_v1` `test`.`t` `ia`), inl_join(@`sel_1` `test`.`vv`), inl_join(`vv`)"))
+
+	// The generated hints are able to reproduce the plan in a binding.
+	tk.MustExec("create binding for select * from t1, v2 where t1.a = v2.a using " +
+		"select /*+ qb_name(qb_v1, v2@sel_1 .@sel_1), use_index(@sel_1 test.t1), use_index(@qb_v1 test.t ia), inl_join(@sel_1 test.v2) */ * from t1, v2 where t1.a = v2.a")
+	tk.MustQuery("explain format = 'hint' select * from t1, v2 where t1.a = v2.a").Check(testkit.Rows(
+		"qb_name(`qb_v1`, `v2`@`sel_1` .@`sel_1`), use_index(@`sel_1` `test`.`t1` ), use_index(@`qb_v1` `test`.`t` `ia`), inl_join(@`sel_1` `test`.`v2`)"))
+	tk.MustQuery("select @@last_plan_from_binding").Check(testkit.Rows("1"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustExec("drop binding for select * from t1, v2 where t1.a = v2.a")
+}
+
+func (s *testIntegrationSuite) TestLateralDerivedTable(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("create table t2 (a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1, 10), (1, 11), (2, 20)")
+
+	tk.MustQuery("select * from t1, lateral (select t1.a + 1 as c) as dt order by t1.a").Check(testkit.Rows("1 1 2", "2 2 3", "3 3 4"))
+	tk.MustQuery("select * from t1 join lateral (select * from t2 where t2.a = t1.a) dt on dt.b > t1.b * 10 order by t1.a").Check(testkit.Rows("1 1 1 11"))
+	tk.MustQuery("select * from t1, lateral (select sum(b) s from t2 where t2.a = t1.a) as dt order by t1.a").Check(testkit.Rows("1 1 21", "2 2 20", "3 3 <nil>"))
+	tk.MustQuery("select * from t1 left join lateral (select * from t2 where t2.a = t1.a order by t2.b desc limit 1) as dt on true order by t1.a").Check(testkit.Rows("1 1 1 11", "2 2 2 20", "3 3 <nil> <nil>"))
+	tk.MustQuery("select * from t1, lateral (select 1 as c union select t1.a) as dt order by t1.a, dt.c").Check(testkit.Rows("1 1 1", "2 2 1", "2 2 2", "3 3 1", "3 3 3"))
+	tk.MustQuery("select t1.a, dt.a, dt2.s from t1, lateral (select * from t1 as x where x.a < t1.a) dt, lateral (select dt.a + t1.a s) dt2 order by 1, 2").Check(testkit.Rows("2 1 3", "3 1 4", "3 2 5"))
+	tk.MustQuery("select * from t1 left join lateral (select count(*) c from t2 where t2.a = t1.a) dt on true order by t1.a").Check(testkit.Rows("1 1 2", "2 2 1", "3 3 0"))
+	// The unmatched rows of a LEFT JOIN are filled with NULL, even if the derived table has an aggregation.
+	tk.MustQuery("select * from t1 left join lateral (select count(*) c from t2 where t2.a = t1.a) dt on dt.c > 1 order by t1.a").Check(testkit.Rows("1 1 2", "2 2 <nil>", "3 3 <nil>"))
+
+	// The correlated derived table is decorrelated if possible.
+	rows := tk.MustQuery("explain format = 'brief' select * from t1 join lateral (select * from t2 where t2.a = t1.a) dt").Rows()
+	c.Assert(rows[0][0], Matches, "HashJoin.*")
+	rows = tk.MustQuery("explain format = 'brief' select * from t1, lateral (select sum(b) s from t2 where t2.a = t1.a) as dt").Rows()
+	c.Assert(rows[0][0], Matches, "Apply.*")
+
+	// The columns of the left side are invisible to the LATERAL derived table on the right of a RIGHT JOIN.
+	tk.MustGetErrCode("select * from t1 right join lateral (select t1.a) as dt on true", mysql.ErrBadField)
+	tk.MustGetErrCode("select * from t1, (select t1.a) as dt", mysql.ErrBadField)
+	tk.MustGetErrCode("select * from t1, lateral (select 1)", mysql.ErrDerivedMustHaveAlias)
+}
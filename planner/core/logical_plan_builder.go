@@ -72,6 +72,8 @@ const (
 	HintBCJ = "broadcast_join"
 	// HintBCJPreferLocal specifies the preferred local read table
 	HintBCJPreferLocal = "broadcast_join_local"
+	// HintShuffleJoin indicates applying shuffle (hash partition) MPP join by force.
+	HintShuffleJoin = "shuffle_join"
 
 	// TiDBIndexNestedLoopJoin is hint enforce index nested loop join.
 	TiDBIndexNestedLoopJoin = "tidb_inlj"
@@ -95,6 +97,8 @@ const (
 	HintIgnoreIndex = "ignore_index"
 	// HintForceIndex make optimizer to use this index even if it thinks a table scan is more efficient.
 	HintForceIndex = "force_index"
+	// HintAccessPath is hint enforce using the first applicable index in an ordered list of indexes.
+	HintAccessPath = "access_path"
 	// HintAggToCop is hint enforce pushing aggregation to coprocessor.
 	HintAggToCop = "agg_to_cop"
 	// HintReadFromStorage is hint enforce some tables read from specific type of storage.
@@ -180,6 +184,9 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 	b.optFlag |= flagPredicatePushDown
 	b.optFlag |= flagEliminateAgg
 	b.optFlag |= flagEliminateProjection
+	if b.ctx.GetSessionVars().EnableApproxCount {
+		b.optFlag |= flagApproxCount
+	}
 
 	plan4Agg := LogicalAggregation{AggFuncs: make([]*aggregation.AggFuncDesc, 0, len(aggFuncList))}.Init(b.ctx, b.getSelectOffset())
 	if hint := b.TableHints(); hint != nil {
@@ -327,6 +334,9 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		return b.buildJoin(ctx, x)
 	case *ast.TableSource:
 		var isTableName bool
+		originStraightJoin := b.inStraightJoinTableSource
+		b.inStraightJoinTableSource = b.inStraightJoin
+		defer func() { b.inStraightJoinTableSource = originStraightJoin }()
 		switch v := x.Source.(type) {
 		case *ast.SelectStmt:
 			ci := b.prepareCTECheckForSubQuery()
@@ -566,6 +576,9 @@ func (p *LogicalJoin) setPreferredJoinType(hintInfo *tableHintInfo) {
 	if hintInfo.ifPreferBroadcastJoin(lhsAlias, rhsAlias) {
 		p.preferJoinType |= preferBCJoin
 	}
+	if hintInfo.ifPreferShuffleJoin(lhsAlias, rhsAlias) {
+		p.preferJoinType |= preferShuffleJoin
+	}
 	if hintInfo.ifPreferHashJoin(lhsAlias, rhsAlias) {
 		p.preferJoinType |= preferHashJoin
 	}
@@ -683,7 +696,12 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		return nil, err
 	}
 
-	rightPlan, err := b.buildResultSetNode(ctx, joinNode.Right)
+	var rightPlan LogicalPlan
+	if ts, ok := joinNode.Right.(*ast.TableSource); ok && ts.Lateral && joinNode.Tp != ast.RightJoin {
+		rightPlan, err = b.buildLateralTableSource(ctx, leftPlan, ts)
+	} else {
+		rightPlan, err = b.buildResultSetNode(ctx, joinNode.Right)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -697,7 +715,23 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 	handleMap2 := b.handleHelper.popMap()
 	b.handleHelper.mergeAndPush(handleMap1, handleMap2)
 
-	joinPlan := LogicalJoin{StraightJoin: joinNode.StraightJoin || b.inStraightJoin}.Init(b.ctx, b.getSelectOffset())
+	// A LATERAL derived table which refers to the columns of the left side is evaluated
+	// for every row of the left side, so the join becomes an Apply. It may be turned back
+	// into a join by the decorrelation rule.
+	var (
+		joinPlan   *LogicalJoin
+		resultPlan LogicalPlan
+	)
+	straightJoin := joinNode.StraightJoin || b.inStraightJoin
+	if len(extractCorColumnsBySchema4LogicalPlan(rightPlan, leftPlan.Schema())) > 0 {
+		b.optFlag = b.optFlag | flagBuildKeyInfo | flagDecorrelate
+		setIsInApplyForCTE(rightPlan)
+		ap := LogicalApply{LogicalJoin: LogicalJoin{StraightJoin: straightJoin}, isLateral: true}.Init(b.ctx, b.getSelectOffset())
+		joinPlan, resultPlan = &ap.LogicalJoin, ap
+	} else {
+		joinPlan = LogicalJoin{StraightJoin: straightJoin}.Init(b.ctx, b.getSelectOffset())
+		resultPlan = joinPlan
+	}
 	joinPlan.SetChildren(leftPlan, rightPlan)
 	joinPlan.SetSchema(expression.MergeSchema(leftPlan.Schema(), rightPlan.Schema()))
 	joinPlan.names = make([]*types.FieldName, leftPlan.Schema().Len()+rightPlan.Schema().Len())
@@ -799,7 +833,7 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		// possible decorrelate optimizations. The ON clause is actually treated as a WHERE clause now.
 		if joinPlan.JoinType == InnerJoin {
 			sel := LogicalSelection{Conditions: onCondition}.Init(b.ctx, b.getSelectOffset())
-			sel.SetChildren(joinPlan)
+			sel.SetChildren(resultPlan)
 			return sel, nil
 		}
 		joinPlan.AttachOnConds(onCondition)
@@ -809,7 +843,19 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		joinPlan.cartesianJoin = true
 	}
 
-	return joinPlan, nil
+	return resultPlan, nil
+}
+
+// buildLateralTableSource builds a LATERAL derived table, in which the columns of the
+// preceding tables, i.e. the left side of the join, are visible as outer columns.
+func (b *PlanBuilder) buildLateralTableSource(ctx context.Context, leftPlan LogicalPlan, ts *ast.TableSource) (LogicalPlan, error) {
+	b.outerSchemas = append(b.outerSchemas, leftPlan.Schema().Clone())
+	b.outerNames = append(b.outerNames, leftPlan.OutputNames())
+	defer func() {
+		b.outerSchemas = b.outerSchemas[0 : len(b.outerSchemas)-1]
+		b.outerNames = b.outerNames[0 : len(b.outerNames)-1]
+	}()
+	return b.buildResultSetNode(ctx, ts)
 }
 
 // buildUsingClause eliminate the redundant columns and ordering columns based
@@ -957,6 +1003,9 @@ func (b *PlanBuilder) coalesceCommonColumns(p *LogicalJoin, leftPlan, rightPlan
 	conds := make([]expression.Expression, 0, commonLen)
 	for i := 0; i < commonLen; i++ {
 		lc, rc := lsc.Columns[i], rsc.Columns[i]
+		// The common columns are read by the join condition, which isn't rewritten from the AST.
+		b.visitColumn(lc)
+		b.visitColumn(rc)
 		cond, err := expression.NewFunction(b.ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), lc, rc)
 		if err != nil {
 			return err
@@ -3286,6 +3335,7 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 	hints = b.hintProcessor.GetCurrentStmtHints(hints, currentLevel)
 	var (
 		sortMergeTables, INLJTables, INLHJTables, INLMJTables, hashJoinTables, BCTables, BCJPreferLocalTables []hintTableInfo
+		shuffleJoinTables                                                                                     []hintTableInfo
 		indexHintList, indexMergeHintList                                                                     []indexHintInfo
 		tiflashTables, tikvTables                                                                             []hintTableInfo
 		aggHints                                                                                              aggHintInfo
@@ -3296,7 +3346,7 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 		// Set warning for the hint that requires the table name.
 		switch hint.HintName.L {
 		case TiDBMergeJoin, HintSMJ, TiDBIndexNestedLoopJoin, HintINLJ, HintINLHJ, HintINLMJ,
-			TiDBHashJoin, HintHJ, HintUseIndex, HintIgnoreIndex, HintForceIndex, HintIndexMerge:
+			TiDBHashJoin, HintHJ, HintUseIndex, HintIgnoreIndex, HintForceIndex, HintAccessPath, HintIndexMerge:
 			if len(hint.Tables) == 0 {
 				b.pushHintWithoutTableWarning(hint)
 				continue
@@ -3308,6 +3358,8 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 			sortMergeTables = append(sortMergeTables, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
 		case TiDBBroadCastJoin, HintBCJ:
 			BCTables = append(BCTables, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
+		case HintShuffleJoin:
+			shuffleJoinTables = append(shuffleJoinTables, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
 		case HintBCJPreferLocal:
 			BCJPreferLocalTables = append(BCJPreferLocalTables, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
 		case TiDBIndexNestedLoopJoin, HintINLJ:
@@ -3369,6 +3421,22 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 					HintScope:  ast.HintForScan,
 				},
 			})
+		case HintAccessPath:
+			dbName := hint.Tables[0].DBName
+			if dbName.L == "" {
+				dbName = model.NewCIStr(b.ctx.GetSessionVars().CurrentDB)
+			}
+			indexHintList = append(indexHintList, indexHintInfo{
+				dbName:     dbName,
+				tblName:    hint.Tables[0].TableName,
+				partitions: hint.Tables[0].PartitionList,
+				indexHint: &ast.IndexHint{
+					IndexNames: hint.Indexes,
+					HintType:   ast.HintUse,
+					HintScope:  ast.HintForScan,
+				},
+				prioritized: true,
+			})
 		case HintReadFromStorage:
 			switch hint.HintData.(model.CIStr).L {
 			case HintTiFlash:
@@ -3403,6 +3471,7 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 		sortMergeJoinTables:         sortMergeTables,
 		broadcastJoinTables:         BCTables,
 		broadcastJoinPreferredLocal: BCJPreferLocalTables,
+		shuffleJoinTables:           shuffleJoinTables,
 		indexNestedLoopJoinTables:   indexNestedLoopJoinTables{INLJTables, INLHJTables, INLMJTables},
 		hashJoinTables:              hashJoinTables,
 		indexHintList:               indexHintList,
@@ -3432,6 +3501,7 @@ func (b *PlanBuilder) popTableHints() {
 	b.appendUnmatchedJoinHintWarning(HintSMJ, TiDBMergeJoin, hintInfo.sortMergeJoinTables)
 	b.appendUnmatchedJoinHintWarning(HintBCJ, TiDBBroadCastJoin, hintInfo.broadcastJoinTables)
 	b.appendUnmatchedJoinHintWarning(HintBCJPreferLocal, "", hintInfo.broadcastJoinPreferredLocal)
+	b.appendUnmatchedJoinHintWarning(HintShuffleJoin, "", hintInfo.shuffleJoinTables)
 	b.appendUnmatchedJoinHintWarning(HintHJ, TiDBHashJoin, hintInfo.hashJoinTables)
 	b.appendUnmatchedStorageHintWarning(hintInfo.tiflashTables, hintInfo.tikvTables)
 	b.tableHintInfo = b.tableHintInfo[:len(b.tableHintInfo)-1]
@@ -3518,7 +3588,7 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 			b.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
 		}
 		origin := b.inStraightJoin
-		b.inStraightJoin = sel.SelectStmtOpts.StraightJoin
+		b.inStraightJoin = sel.SelectStmtOpts.StraightJoin || b.inStraightJoinTableSource
 		defer func() { b.inStraightJoin = origin }()
 	}
 
@@ -3923,6 +3993,21 @@ func (b *PlanBuilder) tryBuildCTE(ctx context.Context, tn *ast.TableName, asName
 	return nil, nil
 }
 
+// tableMappingTarget returns the table which the table reference of a DML statement is routed to by
+// tidb_table_mapping. The mapping is resolved when the plan is built rather than in the AST, so a prepared
+// statement follows the mapping of the session when it's executed.
+func (b *PlanBuilder) tableMappingTarget(dbName, tblName model.CIStr) (variable.TableMappingTarget, bool) {
+	if len(b.buildingViewStack) > 0 || b.isCreateView {
+		return variable.TableMappingTarget{}, false
+	}
+	sessionVars := b.ctx.GetSessionVars()
+	sc := sessionVars.StmtCtx
+	if !sc.InSelectStmt && !sc.InInsertStmt && !sc.InUpdateStmt && !sc.InDeleteStmt {
+		return variable.TableMappingTarget{}, false
+	}
+	return sessionVars.MappedTable(dbName, tblName)
+}
+
 func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, asName *model.CIStr) (LogicalPlan, error) {
 	dbName := tn.Schema
 	sessionVars := b.ctx.GetSessionVars()
@@ -3943,13 +4028,36 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		// At this time, executing 'select * from v1' should still return all records from normal table `t1` instead of temporary table `t1`.
 		is = temptable.DetachLocalTemporaryTableInfoSchema(is)
 	}
+	// The table read with a different AS OF TIMESTAMP from the statement is resolved in the information
+	// schema of its own snapshot.
+	snapshotTS := sessionVars.StmtCtx.TableSnapshotTS[tn]
+	if snapshotTS != 0 {
+		snapshotIS, err := domain.GetDomain(b.ctx).GetSnapshotInfoSchema(snapshotTS)
+		if err != nil {
+			return nil, err
+		}
+		is = temptable.AttachLocalTemporaryTableInfoSchema(b.ctx, snapshotIS)
+	}
 
-	tbl, err := is.TableByName(dbName, tn.Name)
+	lookupName := tn.Name
+	target, mapped := b.tableMappingTarget(dbName, tn.Name)
+	if mapped {
+		// Keep the original name as the alias of a mapped table, so the column references are still valid.
+		if asName == nil || asName.L == "" {
+			asName = &tn.Name
+		}
+		dbName, lookupName = target.Schema, target.Name
+	}
+	tbl, err := is.TableByName(dbName, lookupName)
 	if err != nil {
 		return nil, err
 	}
 
 	tableInfo := tbl.Meta()
+	schemaTblName := tableInfo.Name
+	if mapped {
+		schemaTblName = tn.Name
+	}
 	if b.isCreateView && tableInfo.TempTableType == model.TempTableLocal {
 		return nil, ErrViewSelectTemporaryTable.GenWithStackByArgs(tn.Name)
 	}
@@ -3959,6 +4067,9 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		authErr = ErrTableaccessDenied.FastGenByArgs("SELECT", sessionVars.User.AuthUsername, sessionVars.User.AuthHostname, tableInfo.Name.L)
 	}
 	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName.L, tableInfo.Name.L, "", authErr)
+	if !tbl.Type().IsVirtualTable() && !tableInfo.IsView() {
+		b.visitInfo[len(b.visitInfo)-1].colPrivTable = tableInfo
+	}
 
 	if tbl.Type().IsVirtualTable() {
 		if tn.TableSample != nil {
@@ -3971,7 +4082,17 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		if tn.TableSample != nil {
 			return nil, expression.ErrInvalidTableSample.GenWithStackByArgs("Unsupported TABLESAMPLE in views")
 		}
-		return b.BuildDataSourceFromView(ctx, dbName, tableInfo)
+		offset := b.getSelectOffset()
+		p, err := b.BuildDataSourceFromView(ctx, dbName, tableInfo)
+		if err != nil {
+			return nil, err
+		}
+		view := &viewQueryBlock{dbName: dbName, viewName: tableInfo.Name, asName: tableInfo.Name, blockOffset: offset}
+		if asName != nil && asName.L != "" {
+			view.asName = *asName
+		}
+		setViewPath(p, view)
+		return p, nil
 	}
 
 	if tableInfo.IsSequence() {
@@ -3986,6 +4107,9 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		// Use the new partition implementation, clean up the code here when it's full implemented.
 		if !b.ctx.GetSessionVars().UseDynamicPartitionPrune() {
 			b.optFlag = b.optFlag | flagPartitionProcessor
+			if b.ctx.GetSessionVars().EnablePartitionWiseJoin {
+				b.optFlag = b.optFlag | flagPartitionWiseJoin
+			}
 		}
 
 		pt := tbl.(table.PartitionedTable)
@@ -4014,6 +4138,16 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	if err != nil {
 		return nil, err
 	}
+	if snapshotTS != 0 {
+		// Only the TiKV readers support reading the table at its own snapshot.
+		tikvPaths := possiblePaths[:0]
+		for _, path := range possiblePaths {
+			if path.StoreType == kv.TiKV {
+				tikvPaths = append(tikvPaths, path)
+			}
+		}
+		possiblePaths = tikvPaths
+	}
 	// Skip storage engine check for CreateView.
 	if b.capFlag&canExpandAST == 0 {
 		possiblePaths, err = filterPathByIsolationRead(b.ctx, possiblePaths, tblName, dbName)
@@ -4107,6 +4241,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		preferPartitions:    make(map[int][]model.CIStr),
 		is:                  b.is,
 		isForUpdateRead:     b.isForUpdateRead,
+		snapshotTS:          snapshotTS,
 	}.Init(b.ctx, b.getSelectOffset())
 	var handleCols HandleCols
 	schema := expression.NewSchema(make([]*expression.Column, 0, len(columns))...)
@@ -4115,7 +4250,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		ds.Columns = append(ds.Columns, col.ToInfo())
 		names = append(names, &types.FieldName{
 			DBName:      dbName,
-			TblName:     tableInfo.Name,
+			TblName:     schemaTblName,
 			ColName:     col.Name,
 			OrigTblName: tableInfo.Name,
 			OrigColName: col.Name,
@@ -4134,6 +4269,12 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		}
 		schema.Append(newCol)
 		ds.TblCols = append(ds.TblCols, newCol)
+		if col.State == model.StatePublic && !col.Hidden {
+			if b.colPrivNames == nil {
+				b.colPrivNames = make(map[int64]*types.FieldName)
+			}
+			b.colPrivNames[newCol.UniqueID] = names[i]
+		}
 	}
 	// We append an extra handle column to the schema when the handle
 	// column is not the primary key of "ds".
@@ -4148,7 +4289,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 			schema.Append(extraCol)
 			names = append(names, &types.FieldName{
 				DBName:      dbName,
-				TblName:     tableInfo.Name,
+				TblName:     schemaTblName,
 				ColName:     model.ExtraHandleName,
 				OrigColName: model.ExtraHandleName,
 			})
@@ -4367,6 +4508,20 @@ func (b *PlanBuilder) checkRecursiveView(dbName model.CIStr, tableName model.CIS
 	return func() { delete(b.buildingViewStack, viewFullName) }, nil
 }
 
+// setViewPath records that the operators of the plan are built from the view, so the hints generated for them
+// are able to reach them through the view.
+func setViewPath(p LogicalPlan, view *viewQueryBlock) {
+	switch x := p.(type) {
+	case *DataSource:
+		x.viewPath = append([]*viewQueryBlock{view}, x.viewPath...)
+	case *LogicalAggregation:
+		x.viewPath = append([]*viewQueryBlock{view}, x.viewPath...)
+	}
+	for _, child := range p.Children() {
+		setViewPath(child, view)
+	}
+}
+
 // BuildDataSourceFromView is used to build LogicalPlan from view
 func (b *PlanBuilder) BuildDataSourceFromView(ctx context.Context, dbName model.CIStr, tableInfo *model.TableInfo) (LogicalPlan, error) {
 	deferFunc, err := b.checkRecursiveView(dbName, tableInfo.Name)
@@ -4382,9 +4537,18 @@ func (b *PlanBuilder) BuildDataSourceFromView(ctx context.Context, dbName model.
 	if err != nil {
 		return nil, err
 	}
+	// The view is built with its own hint processor, the hints of the outer query which are
+	// addressed to the view through the view paths of QB_NAME hints are inherited by it.
+	originHintProcessor := b.hintProcessor
+	originBlockAsNames := b.ctx.GetSessionVars().PlannerSelectBlockAsName
+	b.hintProcessor = originHintProcessor.HintProcessor4View(tableInfo.Name, b.getSelectOffset(), selectNode)
+	b.ctx.GetSessionVars().PlannerSelectBlockAsName = make([]ast.HintTable, b.hintProcessor.MaxSelectStmtOffset()+1)
 	originalVisitInfo := b.visitInfo
 	b.visitInfo = make([]visitInfo, 0)
 	selectLogicalPlan, err := b.Build(ctx, selectNode)
+	b.hintProcessor.HandleUnusedViewHints()
+	b.hintProcessor = originHintProcessor
+	b.ctx.GetSessionVars().PlannerSelectBlockAsName = originBlockAsNames
 	if err != nil {
 		if terror.ErrorNotEqual(err, ErrViewRecursive) &&
 			terror.ErrorNotEqual(err, ErrNoSuchTable) &&
@@ -4397,7 +4561,10 @@ func (b *PlanBuilder) BuildDataSourceFromView(ctx context.Context, dbName model.
 	if tableInfo.View.Security == model.SecurityDefiner {
 		if pm := privilege.GetPrivilegeManager(b.ctx); pm != nil {
 			for _, v := range b.visitInfo {
-				if !pm.RequestVerificationWithUser(v.db, v.table, v.column, v.privilege, tableInfo.View.Definer) {
+				if !pm.RequestVerificationWithUser(v.db, v.table, v.column, v.privilege, tableInfo.View.Definer) &&
+					!v.requestAnyColumn(func(column string) bool {
+						return pm.RequestVerificationWithUser(v.db, v.table, column, v.privilege, tableInfo.View.Definer)
+					}) {
 					return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
 				}
 			}
@@ -5524,6 +5691,9 @@ func (b *PlanBuilder) buildWindowFunctionFrame(ctx context.Context, spec *ast.Wi
 		return nil, nil
 	}
 	frame := &WindowFrame{Type: frameClause.Type}
+	if frameClause.Exclusion != ast.ExcludeNoOthers {
+		frame.Exclusion = frameClause.Exclusion
+	}
 	var err error
 	frame.Start, err = b.buildWindowFunctionFrameBound(ctx, spec, orderByItems, &frameClause.Extent.Start)
 	if err != nil {
@@ -5796,9 +5966,11 @@ func (b *PlanBuilder) handleDefaultFrame(spec *ast.WindowSpec, windowFuncName st
 		}
 		return &newSpec, true
 	}
-	// "RANGE/ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING" is equivalent to empty frame.
+	// "RANGE/ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING" is equivalent to empty frame,
+	// unless some rows are excluded from the frame.
 	if needFrame && spec.Frame != nil &&
-		spec.Frame.Extent.Start.UnBounded && spec.Frame.Extent.End.UnBounded {
+		spec.Frame.Extent.Start.UnBounded && spec.Frame.Extent.End.UnBounded &&
+		(spec.Frame.Exclusion == ast.ExcludeNone || spec.Frame.Exclusion == ast.ExcludeNoOthers) {
 		newSpec := *spec
 		newSpec.Frame = nil
 		return &newSpec, true
@@ -6175,6 +6347,21 @@ func appendVisitInfo(vi []visitInfo, priv mysql.PrivilegeType, db, tbl, col stri
 	})
 }
 
+// visitColumn appends the column level SELECT privilege check of the referenced column if it comes from a
+// base table, every column is only checked once.
+func (b *PlanBuilder) visitColumn(col *expression.Column) {
+	name, ok := b.colPrivNames[col.UniqueID]
+	if !ok {
+		return
+	}
+	delete(b.colPrivNames, col.UniqueID)
+	var authErr error
+	if user := b.ctx.GetSessionVars().User; user != nil {
+		authErr = ErrColumnaccessDenied.FastGenByArgs("SELECT", user.AuthUsername, user.AuthHostname, name.OrigColName.L, name.OrigTblName.L)
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, name.DBName.L, name.OrigTblName.L, name.OrigColName.L, authErr)
+}
+
 func getInnerFromParenthesesAndUnaryPlus(expr ast.ExprNode) ast.ExprNode {
 	if pexpr, ok := expr.(*ast.ParenthesesExpr); ok {
 		return getInnerFromParenthesesAndUnaryPlus(pexpr.Expr)
@@ -1024,266 +1024,285 @@ func (s *testPlanSuite) TestVisitInfo(c *C) {
 		{
 			sql: "insert into t (a) values (1)",
 			ans: []visitInfo{
-				{mysql.InsertPriv, "test", "t", "", nil, false, "", false},
+				{mysql.InsertPriv, "test", "t", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "delete from t where a = 1",
 			ans: []visitInfo{
-				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.DeletePriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "delete from t order by a",
 			ans: []visitInfo{
-				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.DeletePriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "delete from t",
 			ans: []visitInfo{
-				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
+				{mysql.DeletePriv, "test", "t", "", nil, false, "", false, nil},
 			},
 		},
 		/* Not currently supported. See https://github.com/pingcap/tidb/issues/23644
 		{
 			sql: "delete from t where 1=1",
 			ans: []visitInfo{
-				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
+				{mysql.DeletePriv, "test", "t", "", nil, false, "", false, nil},
 			},
 		},
 		*/
 		{
 			sql: "delete from a1 using t as a1 inner join t as a2 where a1.a = a2.a",
 			ans: []visitInfo{
-				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.DeletePriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "update t set a = 7 where a = 1",
 			ans: []visitInfo{
-				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "update t, (select * from t) a1 set t.a = a1.a;",
 			ans: []visitInfo{
-				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "b", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "c", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "c_str", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "d", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "d_str", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "e", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "e_str", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "f", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "g", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "h", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "i_date", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "update t a1 set a1.a = a1.a + 1",
 			ans: []visitInfo{
-				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "select a, sum(e) from t group by a",
 			ans: []visitInfo{
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "e", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "truncate table t",
 			ans: []visitInfo{
-				{mysql.DropPriv, "test", "t", "", nil, false, "", false},
+				{mysql.DropPriv, "test", "t", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "drop table t",
 			ans: []visitInfo{
-				{mysql.DropPriv, "test", "t", "", nil, false, "", false},
+				{mysql.DropPriv, "test", "t", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "create table t (a int)",
 			ans: []visitInfo{
-				{mysql.CreatePriv, "test", "t", "", nil, false, "", false},
+				{mysql.CreatePriv, "test", "t", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "create table t1 like t",
 			ans: []visitInfo{
-				{mysql.CreatePriv, "test", "t1", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.CreatePriv, "test", "t1", "", nil, false, "", false, nil},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "create database test",
 			ans: []visitInfo{
-				{mysql.CreatePriv, "test", "", "", nil, false, "", false},
+				{mysql.CreatePriv, "test", "", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "drop database test",
 			ans: []visitInfo{
-				{mysql.DropPriv, "test", "", "", nil, false, "", false},
+				{mysql.DropPriv, "test", "", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "create index t_1 on t (a)",
 			ans: []visitInfo{
-				{mysql.IndexPriv, "test", "t", "", nil, false, "", false},
+				{mysql.IndexPriv, "test", "t", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "drop index e on t",
 			ans: []visitInfo{
-				{mysql.IndexPriv, "test", "t", "", nil, false, "", false},
+				{mysql.IndexPriv, "test", "t", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: `grant all privileges on test.* to 'test'@'%'`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "test", "", "", nil, false, "", false},
-				{mysql.InsertPriv, "test", "", "", nil, false, "", false},
-				{mysql.UpdatePriv, "test", "", "", nil, false, "", false},
-				{mysql.DeletePriv, "test", "", "", nil, false, "", false},
-				{mysql.CreatePriv, "test", "", "", nil, false, "", false},
-				{mysql.DropPriv, "test", "", "", nil, false, "", false},
-				{mysql.GrantPriv, "test", "", "", nil, false, "", false},
-				{mysql.ReferencesPriv, "test", "", "", nil, false, "", false},
-				{mysql.LockTablesPriv, "test", "", "", nil, false, "", false},
-				{mysql.CreateTMPTablePriv, "test", "", "", nil, false, "", false},
-				{mysql.EventPriv, "test", "", "", nil, false, "", false},
-				{mysql.CreateRoutinePriv, "test", "", "", nil, false, "", false},
-				{mysql.AlterRoutinePriv, "test", "", "", nil, false, "", false},
-				{mysql.AlterPriv, "test", "", "", nil, false, "", false},
-				{mysql.ExecutePriv, "test", "", "", nil, false, "", false},
-				{mysql.IndexPriv, "test", "", "", nil, false, "", false},
-				{mysql.CreateViewPriv, "test", "", "", nil, false, "", false},
-				{mysql.ShowViewPriv, "test", "", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.InsertPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.UpdatePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.DeletePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.CreatePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.DropPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.GrantPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.ReferencesPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.LockTablesPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.CreateTMPTablePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.EventPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.CreateRoutinePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.AlterRoutinePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.AlterPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.ExecutePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.IndexPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.CreateViewPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.ShowViewPriv, "test", "", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: `grant all privileges on *.* to 'test'@'%'`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "", "", "", nil, false, "", false},
-				{mysql.InsertPriv, "", "", "", nil, false, "", false},
-				{mysql.UpdatePriv, "", "", "", nil, false, "", false},
-				{mysql.DeletePriv, "", "", "", nil, false, "", false},
-				{mysql.CreatePriv, "", "", "", nil, false, "", false},
-				{mysql.DropPriv, "", "", "", nil, false, "", false},
-				{mysql.ProcessPriv, "", "", "", nil, false, "", false},
-				{mysql.ReferencesPriv, "", "", "", nil, false, "", false},
-				{mysql.AlterPriv, "", "", "", nil, false, "", false},
-				{mysql.ShowDBPriv, "", "", "", nil, false, "", false},
-				{mysql.SuperPriv, "", "", "", nil, false, "", false},
-				{mysql.ExecutePriv, "", "", "", nil, false, "", false},
-				{mysql.IndexPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateUserPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateTablespacePriv, "", "", "", nil, false, "", false},
-				{mysql.TriggerPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateViewPriv, "", "", "", nil, false, "", false},
-				{mysql.ShowViewPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateRolePriv, "", "", "", nil, false, "", false},
-				{mysql.DropRolePriv, "", "", "", nil, false, "", false},
-				{mysql.CreateTMPTablePriv, "", "", "", nil, false, "", false},
-				{mysql.LockTablesPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateRoutinePriv, "", "", "", nil, false, "", false},
-				{mysql.AlterRoutinePriv, "", "", "", nil, false, "", false},
-				{mysql.EventPriv, "", "", "", nil, false, "", false},
-				{mysql.ShutdownPriv, "", "", "", nil, false, "", false},
-				{mysql.ReloadPriv, "", "", "", nil, false, "", false},
-				{mysql.FilePriv, "", "", "", nil, false, "", false},
-				{mysql.ConfigPriv, "", "", "", nil, false, "", false},
-				{mysql.ReplicationClientPriv, "", "", "", nil, false, "", false},
-				{mysql.ReplicationSlavePriv, "", "", "", nil, false, "", false},
-				{mysql.GrantPriv, "", "", "", nil, false, "", false},
+				{mysql.SelectPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.InsertPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.UpdatePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.DeletePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreatePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.DropPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ProcessPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ReferencesPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.AlterPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ShowDBPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.SuperPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ExecutePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.IndexPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateUserPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateTablespacePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.TriggerPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateViewPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ShowViewPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateRolePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.DropRolePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateTMPTablePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.LockTablesPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateRoutinePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.AlterRoutinePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.EventPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ShutdownPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ReloadPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.FilePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ConfigPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ReplicationClientPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ReplicationSlavePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.GrantPriv, "", "", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: `grant select on test.ttt to 'test'@'%'`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "test", "ttt", "", nil, false, "", false},
-				{mysql.GrantPriv, "test", "ttt", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "ttt", "", nil, false, "", false, nil},
+				{mysql.GrantPriv, "test", "ttt", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: `grant select on ttt to 'test'@'%'`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "test", "ttt", "", nil, false, "", false},
-				{mysql.GrantPriv, "test", "ttt", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "ttt", "", nil, false, "", false, nil},
+				{mysql.GrantPriv, "test", "ttt", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: `revoke all privileges on test.* from 'test'@'%'`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "test", "", "", nil, false, "", false},
-				{mysql.InsertPriv, "test", "", "", nil, false, "", false},
-				{mysql.UpdatePriv, "test", "", "", nil, false, "", false},
-				{mysql.DeletePriv, "test", "", "", nil, false, "", false},
-				{mysql.CreatePriv, "test", "", "", nil, false, "", false},
-				{mysql.DropPriv, "test", "", "", nil, false, "", false},
-				{mysql.GrantPriv, "test", "", "", nil, false, "", false},
-				{mysql.ReferencesPriv, "test", "", "", nil, false, "", false},
-				{mysql.LockTablesPriv, "test", "", "", nil, false, "", false},
-				{mysql.CreateTMPTablePriv, "test", "", "", nil, false, "", false},
-				{mysql.EventPriv, "test", "", "", nil, false, "", false},
-				{mysql.CreateRoutinePriv, "test", "", "", nil, false, "", false},
-				{mysql.AlterRoutinePriv, "test", "", "", nil, false, "", false},
-				{mysql.AlterPriv, "test", "", "", nil, false, "", false},
-				{mysql.ExecutePriv, "test", "", "", nil, false, "", false},
-				{mysql.IndexPriv, "test", "", "", nil, false, "", false},
-				{mysql.CreateViewPriv, "test", "", "", nil, false, "", false},
-				{mysql.ShowViewPriv, "test", "", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.InsertPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.UpdatePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.DeletePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.CreatePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.DropPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.GrantPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.ReferencesPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.LockTablesPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.CreateTMPTablePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.EventPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.CreateRoutinePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.AlterRoutinePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.AlterPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.ExecutePriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.IndexPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.CreateViewPriv, "test", "", "", nil, false, "", false, nil},
+				{mysql.ShowViewPriv, "test", "", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: `revoke connection_admin on *.* from u1`,
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", nil, false, "CONNECTION_ADMIN", true},
+				{mysql.ExtendedPriv, "", "", "", nil, false, "CONNECTION_ADMIN", true, nil},
 			},
 		},
 		{
 			sql: `revoke connection_admin, select on *.* from u1`,
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", nil, false, "CONNECTION_ADMIN", true},
-				{mysql.SelectPriv, "", "", "", nil, false, "", false},
-				{mysql.GrantPriv, "", "", "", nil, false, "", false},
+				{mysql.ExtendedPriv, "", "", "", nil, false, "CONNECTION_ADMIN", true, nil},
+				{mysql.SelectPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.GrantPriv, "", "", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: `revoke all privileges on *.* FROM u1`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "", "", "", nil, false, "", false},
-				{mysql.InsertPriv, "", "", "", nil, false, "", false},
-				{mysql.UpdatePriv, "", "", "", nil, false, "", false},
-				{mysql.DeletePriv, "", "", "", nil, false, "", false},
-				{mysql.CreatePriv, "", "", "", nil, false, "", false},
-				{mysql.DropPriv, "", "", "", nil, false, "", false},
-				{mysql.ProcessPriv, "", "", "", nil, false, "", false},
-				{mysql.ReferencesPriv, "", "", "", nil, false, "", false},
-				{mysql.AlterPriv, "", "", "", nil, false, "", false},
-				{mysql.ShowDBPriv, "", "", "", nil, false, "", false},
-				{mysql.SuperPriv, "", "", "", nil, false, "", false},
-				{mysql.ExecutePriv, "", "", "", nil, false, "", false},
-				{mysql.IndexPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateUserPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateTablespacePriv, "", "", "", nil, false, "", false},
-				{mysql.TriggerPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateViewPriv, "", "", "", nil, false, "", false},
-				{mysql.ShowViewPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateRolePriv, "", "", "", nil, false, "", false},
-				{mysql.DropRolePriv, "", "", "", nil, false, "", false},
-				{mysql.CreateTMPTablePriv, "", "", "", nil, false, "", false},
-				{mysql.LockTablesPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateRoutinePriv, "", "", "", nil, false, "", false},
-				{mysql.AlterRoutinePriv, "", "", "", nil, false, "", false},
-				{mysql.EventPriv, "", "", "", nil, false, "", false},
-				{mysql.ShutdownPriv, "", "", "", nil, false, "", false},
-				{mysql.ReloadPriv, "", "", "", nil, false, "", false},
-				{mysql.FilePriv, "", "", "", nil, false, "", false},
-				{mysql.ConfigPriv, "", "", "", nil, false, "", false},
-				{mysql.ReplicationClientPriv, "", "", "", nil, false, "", false},
-				{mysql.ReplicationSlavePriv, "", "", "", nil, false, "", false},
-				{mysql.GrantPriv, "", "", "", nil, false, "", false},
+				{mysql.SelectPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.InsertPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.UpdatePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.DeletePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreatePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.DropPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ProcessPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ReferencesPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.AlterPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ShowDBPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.SuperPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ExecutePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.IndexPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateUserPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateTablespacePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.TriggerPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateViewPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ShowViewPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateRolePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.DropRolePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateTMPTablePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.LockTablesPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.CreateRoutinePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.AlterRoutinePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.EventPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ShutdownPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ReloadPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.FilePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ConfigPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ReplicationClientPriv, "", "", "", nil, false, "", false, nil},
+				{mysql.ReplicationSlavePriv, "", "", "", nil, false, "", false, nil},
+				{mysql.GrantPriv, "", "", "", nil, false, "", false, nil},
 			},
 		},
 		{
@@ -1293,122 +1312,122 @@ func (s *testPlanSuite) TestVisitInfo(c *C) {
 		{
 			sql: `show create table test.ttt`,
 			ans: []visitInfo{
-				{mysql.AllPrivMask, "test", "ttt", "", nil, false, "", false},
+				{mysql.AllPrivMask, "test", "ttt", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "alter table t add column a int(4)",
 			ans: []visitInfo{
-				{mysql.AlterPriv, "test", "t", "", nil, false, "", false},
+				{mysql.AlterPriv, "test", "t", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "rename table t_old to t_new",
 			ans: []visitInfo{
-				{mysql.AlterPriv, "test", "t_old", "", nil, false, "", false},
-				{mysql.DropPriv, "test", "t_old", "", nil, false, "", false},
-				{mysql.CreatePriv, "test", "t_new", "", nil, false, "", false},
-				{mysql.InsertPriv, "test", "t_new", "", nil, false, "", false},
+				{mysql.AlterPriv, "test", "t_old", "", nil, false, "", false, nil},
+				{mysql.DropPriv, "test", "t_old", "", nil, false, "", false, nil},
+				{mysql.CreatePriv, "test", "t_new", "", nil, false, "", false, nil},
+				{mysql.InsertPriv, "test", "t_new", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "alter table t_old rename to t_new",
 			ans: []visitInfo{
-				{mysql.AlterPriv, "test", "t_old", "", nil, false, "", false},
-				{mysql.DropPriv, "test", "t_old", "", nil, false, "", false},
-				{mysql.CreatePriv, "test", "t_new", "", nil, false, "", false},
-				{mysql.InsertPriv, "test", "t_new", "", nil, false, "", false},
+				{mysql.AlterPriv, "test", "t_old", "", nil, false, "", false, nil},
+				{mysql.DropPriv, "test", "t_old", "", nil, false, "", false, nil},
+				{mysql.CreatePriv, "test", "t_new", "", nil, false, "", false, nil},
+				{mysql.InsertPriv, "test", "t_new", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "alter table t drop partition p0;",
 			ans: []visitInfo{
-				{mysql.AlterPriv, "test", "t", "", nil, false, "", false},
-				{mysql.DropPriv, "test", "t", "", nil, false, "", false},
+				{mysql.AlterPriv, "test", "t", "", nil, false, "", false, nil},
+				{mysql.DropPriv, "test", "t", "", nil, false, "", false, nil},
 			},
 		},
 		{
 			sql: "flush privileges",
 			ans: []visitInfo{
-				{mysql.ReloadPriv, "", "", "", ErrSpecificAccessDenied, false, "", false},
+				{mysql.ReloadPriv, "", "", "", ErrSpecificAccessDenied, false, "", false, nil},
 			},
 		},
 		{
 			sql: "SET GLOBAL wait_timeout=12345",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "SYSTEM_VARIABLES_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "SYSTEM_VARIABLES_ADMIN", false, nil},
 			},
 		},
 		{
 			sql: "create placement policy x LEARNERS=1",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "PLACEMENT_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "PLACEMENT_ADMIN", false, nil},
 			},
 		},
 		{
 			sql: "drop placement policy if exists x",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "PLACEMENT_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "PLACEMENT_ADMIN", false, nil},
 			},
 		},
 		{
 			sql: "BACKUP DATABASE test TO 'local:///tmp/a'",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false, nil},
 			},
 		},
 		{
 			sql: "RESTORE DATABASE test FROM 'local:///tmp/a'",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "RESTORE_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "RESTORE_ADMIN", false, nil},
 			},
 		},
 		{
 			sql: "SHOW BACKUPS",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false, nil},
 			},
 		},
 		{
 			sql: "SHOW RESTORES",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "RESTORE_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "RESTORE_ADMIN", false, nil},
 			},
 		},
 		{
 			sql: "GRANT rolename TO user1",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "ROLE_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "ROLE_ADMIN", false, nil},
 			},
 		},
 		{
 			sql: "REVOKE rolename FROM user1",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "ROLE_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "ROLE_ADMIN", false, nil},
 			},
 		},
 		{
 			sql: "GRANT BACKUP_ADMIN ON *.* TO user1",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", true},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", true, nil},
 			},
 		},
 		{
 			sql: "GRANT BACKUP_ADMIN ON *.* TO user1 WITH GRANT OPTION",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", true},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", true, nil},
 			},
 		},
 		{
 			sql: "RENAME USER user1 to user1_tmp",
 			ans: []visitInfo{
-				{mysql.CreateUserPriv, "", "", "", ErrSpecificAccessDenied, false, "", false},
+				{mysql.CreateUserPriv, "", "", "", ErrSpecificAccessDenied, false, "", false, nil},
 			},
 		},
 		{
 			sql: "SHOW CONFIG",
 			ans: []visitInfo{
-				{mysql.ConfigPriv, "", "", "", ErrSpecificAccessDenied, false, "", false},
+				{mysql.ConfigPriv, "", "", "", ErrSpecificAccessDenied, false, "", false, nil},
 			},
 		},
 	}
@@ -1438,20 +1457,16 @@ func (v visitInfoArray) Len() int {
 }
 
 func (v visitInfoArray) Less(i, j int) bool {
-	if v[i].privilege < v[j].privilege {
-		return true
+	if v[i].privilege != v[j].privilege {
+		return v[i].privilege < v[j].privilege
 	}
-	if v[i].db < v[j].db {
-		return true
+	if v[i].db != v[j].db {
+		return v[i].db < v[j].db
 	}
-	if v[i].table < v[j].table {
-		return true
+	if v[i].table != v[j].table {
+		return v[i].table < v[j].table
 	}
-	if v[i].column < v[j].column {
-		return true
-	}
-
-	return false
+	return v[i].column < v[j].column
 }
 
 func (v visitInfoArray) Swap(i, j int) {
@@ -1471,6 +1486,11 @@ func unique(v []visitInfo) []visitInfo {
 }
 
 func checkVisitInfo(c *C, v1, v2 []visitInfo, comment CommentInterface) {
+	// the table info of the column level privilege is not compared, the referenced columns are
+	// compared by their own visitInfo
+	for i := range v1 {
+		v1[i].colPrivTable = nil
+	}
 	sort.Sort(visitInfoArray(v1))
 	sort.Sort(visitInfoArray(v2))
 	v1 = unique(v1)
@@ -420,6 +420,8 @@ func (s *testPlanSuite) TestSingleRuleTraceStep(c *C) {
 		for _, step := range otrace.Steps {
 			if step.RuleName == tc.assertRuleName {
 				assert = true
+				c.Assert(step.Before, Not(HasLen), 0)
+				c.Assert(step.After, Not(HasLen), 0)
 				for i, ruleStep := range step.Steps {
 					c.Assert(ruleStep.Action, Equals, tc.assertRuleSteps[i].assertAction)
 					c.Assert(ruleStep.Reason, Equals, tc.assertRuleSteps[i].assertReason)
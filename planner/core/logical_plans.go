@@ -111,6 +111,7 @@ const (
 	preferHashJoin
 	preferMergeJoin
 	preferBCJoin
+	preferShuffleJoin
 	preferHashAgg
 	preferStreamAgg
 )
@@ -341,6 +342,9 @@ type LogicalAggregation struct {
 	// noCopPushDown indicates if planner must not push this agg down to coprocessor.
 	// It is true when the agg is in the outer child tree of apply.
 	noCopPushDown bool
+
+	// viewPath is the views which the aggregation is in, from the outermost one.
+	viewPath []*viewQueryBlock
 }
 
 // HasDistinct shows whether LogicalAggregation has functions with distinct.
@@ -471,6 +475,8 @@ type LogicalApply struct {
 	LogicalJoin
 
 	CorCols []*expression.CorrelatedColumn
+	// isLateral indicates the apply is built from the join with a LATERAL derived table.
+	isLateral bool
 }
 
 // ExtractCorrelatedCols implements LogicalPlan interface.
@@ -544,6 +550,8 @@ type DataSource struct {
 	DBName        model.CIStr
 
 	TableAsName *model.CIStr
+	// viewPath is the views which the table is read in, from the outermost one.
+	viewPath []*viewQueryBlock
 	// indexMergeHints are the hint for indexmerge.
 	indexMergeHints []indexHintInfo
 	// pushedDownConds are the conditions that will be pushed down to coprocessor.
@@ -586,6 +594,8 @@ type DataSource struct {
 	// 1. use `inside insert`, `update`, `delete` or `select for update` statement
 	// 2. isolation level is RC
 	isForUpdateRead bool
+	// snapshotTS is the snapshot of the table if it is read with a different AS OF TIMESTAMP from the statement.
+	snapshotTS uint64
 }
 
 // ExtractCorrelatedCols implements LogicalPlan interface.
@@ -1036,6 +1046,16 @@ func (lt *LogicalTopN) isLimit() bool {
 	return len(lt.ByItems) == 0
 }
 
+// isRandomOrder checks if TopN orders the rows by `RAND()` without a seed. The order of
+// such rows is not reproducible, so TopN can be implemented by sampling the rows randomly.
+func (lt *LogicalTopN) isRandomOrder() bool {
+	if len(lt.ByItems) != 1 {
+		return false
+	}
+	f, ok := lt.ByItems[0].Expr.(*expression.ScalarFunction)
+	return ok && f.FuncName.L == ast.Rand && len(f.GetArgs()) == 0
+}
+
 // LogicalLimit represents offset and limit plan.
 type LogicalLimit struct {
 	logicalSchemaProducer
@@ -1072,6 +1092,8 @@ type WindowFrame struct {
 	Type  ast.FrameType
 	Start *FrameBound
 	End   *FrameBound
+	// Exclusion is the rows excluded from the frame, `EXCLUDE NO OTHERS` is regarded as ast.ExcludeNone.
+	Exclusion ast.FrameExclusion
 }
 
 // FrameBound is the boundary of a frame.
@@ -1143,7 +1165,8 @@ func (p *LogicalWindow) EqualFrame(ctx sessionctx.Context, newWindow *LogicalWin
 		p.Frame.Start.Num != newWindow.Frame.Start.Num ||
 		p.Frame.End.Type != newWindow.Frame.End.Type ||
 		p.Frame.End.UnBounded != newWindow.Frame.End.UnBounded ||
-		p.Frame.End.Num != newWindow.Frame.End.Num {
+		p.Frame.End.Num != newWindow.Frame.End.Num ||
+		p.Frame.Exclusion != newWindow.Frame.Exclusion {
 		return false
 	}
 	for i, expr := range p.Frame.Start.CalcFuncs {
@@ -29,8 +29,10 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/property"
+	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	utilhint "github.com/pingcap/tidb/util/hint"
@@ -60,8 +62,10 @@ const (
 	flagEliminateProjection
 	flagMaxMinEliminate
 	flagPredicatePushDown
+	flagApproxCount
 	flagEliminateOuterJoin
 	flagPartitionProcessor
+	flagPartitionWiseJoin
 	flagCollectPredicateColumnsPoint
 	flagPushDownAgg
 	flagPushDownTopN
@@ -80,8 +84,10 @@ var optRuleList = []logicalOptRule{
 	&projectionEliminator{},
 	&maxMinEliminator{},
 	&ppdSolver{},
+	&approxCountSolver{},
 	&outerJoinEliminator{},
 	&partitionProcessor{},
+	&partitionWiseJoinSolver{},
 	&collectPredicateColumnsPoint{},
 	&aggregationPushDownSolver{},
 	&pushDownTopNOptimizer{},
@@ -111,6 +117,13 @@ func (op *logicalOptimizeOp) appendBeforeRuleOptimize(index int, name string, be
 	op.tracer.AppendRuleTracerBeforeRuleOptimize(index, name, before.buildPlanTrace())
 }
 
+func (op *logicalOptimizeOp) recordAfterRuleOptimize(after LogicalPlan) {
+	if op == nil || op.tracer == nil {
+		return
+	}
+	op.tracer.RecordRuleTracerAfterRuleOptimize(after.buildPlanTrace())
+}
+
 func (op *logicalOptimizeOp) appendStepToCurrent(id int, tp string, reason, action func() string) {
 	if op == nil || op.tracer == nil {
 		return
@@ -153,7 +166,10 @@ func CheckPrivilege(activeRoles []*auth.RoleIdentity, pm privilege.Manager, vs [
 				}
 				return v.err
 			}
-		} else if !pm.RequestVerification(activeRoles, v.db, v.table, v.column, v.privilege) {
+		} else if !pm.RequestVerification(activeRoles, v.db, v.table, v.column, v.privilege) &&
+			!v.requestAnyColumn(func(column string) bool {
+				return pm.RequestVerification(activeRoles, v.db, v.table, column, v.privilege)
+			}) {
 			if v.err == nil {
 				return ErrPrivilegeCheckFail.GenWithStackByArgs(v.privilege.String())
 			}
@@ -306,8 +322,16 @@ func refineCETrace(sctx sessionctx.Context) {
 	for _, rec := range traceRecords {
 		tbl, ok := is.TableByID(rec.TableID)
 		if !ok {
-			logutil.BgLogger().Warn("[OptimizerTrace] Failed to find table in infoschema",
-				zap.Int64("table id", rec.TableID))
+			// The estimation of a partitioned table is traced by the partition ID.
+			var def *model.PartitionDefinition
+			tbl, _, def = is.FindTableByPartitionID(rec.TableID)
+			if tbl == nil {
+				logutil.BgLogger().Warn("[OptimizerTrace] Failed to find table in infoschema",
+					zap.Int64("table id", rec.TableID))
+				continue
+			}
+			rec.TableName = tbl.Meta().Name.O + "." + def.Name.O
+			continue
 		}
 		rec.TableName = tbl.Meta().Name.O
 	}
@@ -381,19 +405,22 @@ func enableParallelApply(sctx sessionctx.Context, plan PhysicalPlan) PhysicalPla
 	if !sctx.GetSessionVars().EnableParallelApply {
 		return plan
 	}
-	// the parallel apply has three limitation:
+	// the parallel apply has four limitation:
 	// 1. the parallel implementation now cannot keep order;
 	// 2. the inner child has to support clone;
 	// 3. if one Apply is in the inner side of another Apply, it cannot be parallel, for example:
 	//		The topology of 3 Apply operators are A1(A2, A3), which means A2 is the outer child of A1
 	//		while A3 is the inner child. Then A1 and A2 can be parallel and A3 cannot.
+	// 4. the inner child has to be read-only and free of side effects, since it is evaluated
+	//		for different outer rows concurrently.
 	if apply, ok := plan.(*PhysicalApply); ok {
 		outerIdx := 1 - apply.InnerChildIdx
 		noOrder := len(apply.GetChildReqProps(outerIdx).SortItems) == 0 // limitation 1
 		_, err := SafeClone(apply.Children()[apply.InnerChildIdx])
-		supportClone := err == nil // limitation 2
-		if noOrder && supportClone {
-			apply.Concurrency = sctx.GetSessionVars().ExecutorConcurrency
+		supportClone := err == nil                                              // limitation 2
+		noSideEffects := !hasSideEffects(apply.Children()[apply.InnerChildIdx]) // limitation 4
+		if noOrder && supportClone && noSideEffects {
+			apply.Concurrency = sctx.GetSessionVars().ApplyConcurrency()
 		}
 
 		// because of the limitation 3, we cannot parallelize Apply operators in this Apply's inner size,
@@ -407,11 +434,126 @@ func enableParallelApply(sctx sessionctx.Context, plan PhysicalPlan) PhysicalPla
 	return plan
 }
 
+// hasSideEffects checks whether the plan tree locks rows or contains functions with side effects.
+func hasSideEffects(p PhysicalPlan) bool {
+	switch x := p.(type) {
+	case *PhysicalLock:
+		return true
+	case *PhysicalSelection:
+		if expression.ContainSideEffectFunctions(x.Conditions...) {
+			return true
+		}
+	case *PhysicalProjection:
+		if expression.ContainSideEffectFunctions(x.Exprs...) {
+			return true
+		}
+	case *PhysicalHashAgg:
+		if x.aggHasSideEffects() {
+			return true
+		}
+	case *PhysicalStreamAgg:
+		if x.aggHasSideEffects() {
+			return true
+		}
+	case *PhysicalSort:
+		if byItemsHaveSideEffects(x.ByItems) {
+			return true
+		}
+	case *NominalSort:
+		if byItemsHaveSideEffects(x.ByItems) {
+			return true
+		}
+	case *PhysicalTopN:
+		if byItemsHaveSideEffects(x.ByItems) {
+			return true
+		}
+	case *PhysicalWindow:
+		for _, desc := range x.WindowFuncDescs {
+			if expression.ContainSideEffectFunctions(desc.Args...) {
+				return true
+			}
+		}
+	case *PhysicalApply:
+		if x.joinHasSideEffects() {
+			return true
+		}
+	case *PhysicalHashJoin:
+		if x.joinHasSideEffects() {
+			return true
+		}
+	case *PhysicalMergeJoin:
+		if x.joinHasSideEffects() {
+			return true
+		}
+	case *PhysicalIndexJoin:
+		if x.joinHasSideEffects() {
+			return true
+		}
+	case *PhysicalIndexHashJoin:
+		if x.joinHasSideEffects() {
+			return true
+		}
+	case *PhysicalIndexMergeJoin:
+		if x.joinHasSideEffects() {
+			return true
+		}
+	case *PhysicalTableReader:
+		return plansHaveSideEffects(x.TablePlans)
+	case *PhysicalIndexReader:
+		return plansHaveSideEffects(x.IndexPlans)
+	case *PhysicalIndexLookUpReader:
+		return plansHaveSideEffects(x.IndexPlans) || plansHaveSideEffects(x.TablePlans)
+	case *PhysicalIndexMergeReader:
+		for _, partialPlans := range x.PartialPlans {
+			if plansHaveSideEffects(partialPlans) {
+				return true
+			}
+		}
+		return plansHaveSideEffects(x.TablePlans)
+	}
+	return plansHaveSideEffects(p.Children())
+}
+
+func plansHaveSideEffects(plans []PhysicalPlan) bool {
+	for _, p := range plans {
+		if hasSideEffects(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func byItemsHaveSideEffects(byItems []*util.ByItems) bool {
+	for _, item := range byItems {
+		if expression.ContainSideEffectFunctions(item.Expr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *basePhysicalAgg) aggHasSideEffects() bool {
+	for _, aggFunc := range p.AggFuncs {
+		if expression.ContainSideEffectFunctions(aggFunc.Args...) {
+			return true
+		}
+	}
+	return expression.ContainSideEffectFunctions(p.GroupByItems...)
+}
+
+func (p *basePhysicalJoin) joinHasSideEffects() bool {
+	return expression.ContainSideEffectFunctions(p.LeftConditions...) ||
+		expression.ContainSideEffectFunctions(p.RightConditions...) ||
+		expression.ContainSideEffectFunctions(p.OtherConditions...)
+}
+
 func logicalOptimize(ctx context.Context, flag uint64, logic LogicalPlan) (LogicalPlan, error) {
 	opt := defaultLogicalOptimizeOption()
 	vars := logic.SCtx().GetSessionVars()
 	if vars.StmtCtx.EnableOptimizeTrace {
 		vars.StmtCtx.OptimizeTracer = &tracing.OptimizeTracer{}
+	}
+	if isOptimizeTraceTarget(vars.StmtCtx, TracePlanTargetLogical) {
 		tracer := &tracing.LogicalOptimizeTracer{
 			Steps: make([]*tracing.LogicalRuleOptimizeTracer, 0),
 		}
@@ -433,11 +575,21 @@ func logicalOptimize(ctx context.Context, flag uint64, logic LogicalPlan) (Logic
 		if err != nil {
 			return nil, err
 		}
+		opt.recordAfterRuleOptimize(logic)
 	}
 	opt.recordFinalLogicalPlan(logic)
 	return logic, err
 }
 
+// isOptimizeTraceTarget checks whether the optimizer trace is enabled for the target,
+// an empty trace target means all the targets are traced.
+func isOptimizeTraceTarget(stmtCtx *stmtctx.StatementContext, target string) bool {
+	if !stmtCtx.EnableOptimizeTrace {
+		return false
+	}
+	return stmtCtx.OptimizeTraceTarget == "" || stmtCtx.OptimizeTraceTarget == target
+}
+
 func isLogicalRuleDisabled(r logicalOptRule) bool {
 	disabled := DefaultDisabledLogicalRulesList.Load().(set.StringSet).Exist(r.name())
 	return disabled
@@ -457,7 +609,7 @@ func physicalOptimize(logic LogicalPlan, planCounter *PlanCounterTp) (plan Physi
 
 	opt := defaultPhysicalOptimizeOption()
 	stmtCtx := logic.SCtx().GetSessionVars().StmtCtx
-	if stmtCtx.EnableOptimizeTrace {
+	if isOptimizeTraceTarget(stmtCtx, TracePlanTargetPhysical) {
 		tracer := &tracing.PhysicalOptimizeTracer{State: make(map[string]map[string]*tracing.PlanTrace)}
 		opt = opt.withEnableOptimizeTracer(tracer)
 		defer func() {
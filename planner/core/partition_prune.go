@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 )
 
 // PartitionPruning finds all used partitions according to query conditions, it will
@@ -29,6 +30,12 @@ func PartitionPruning(ctx sessionctx.Context, tbl table.PartitionedTable, conds
 	columns []*expression.Column, names types.NameSlice) ([]int, error) {
 	s := partitionProcessor{}
 	pi := tbl.Meta().Partition
+	// The conditions may belong to a cached plan, so the parameters in them are replaced by the values of
+	// the current execution and the pruning works on a copy of them.
+	conds, err := materializeMutableConsts(ctx, conds)
+	if err != nil {
+		return nil, err
+	}
 	// PushDownNot here can convert condition 'not (a != 1)' to 'a = 1'. When we build range from conds, the condition like
 	// 'not (a != 1)' would not be handled so we need to convert it to 'a = 1', which can be handled when building range.
 	// TODO: there may be a better way to push down Not once for all.
@@ -50,3 +57,48 @@ func PartitionPruning(ctx sessionctx.Context, tbl table.PartitionedTable, conds
 	}
 	return []int{FullRange}, nil
 }
+
+// materializeMutableConsts returns a copy of conds in which every constant that refers to a parameter
+// marker or a deferred expression is replaced by its current value. Such constants are treated as
+// unknown by the pruning and range building under the plan cache, which makes the pruning fall back to
+// all partitions, while they are already known when the partitions are pruned at execution time.
+func materializeMutableConsts(ctx sessionctx.Context, conds []expression.Expression) ([]expression.Expression, error) {
+	ret := make([]expression.Expression, 0, len(conds))
+	for _, cond := range conds {
+		newCond, err := materializeMutableConst(ctx, cond)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, newCond)
+	}
+	return ret, nil
+}
+
+func materializeMutableConst(ctx sessionctx.Context, expr expression.Expression) (expression.Expression, error) {
+	switch x := expr.(type) {
+	case *expression.Constant:
+		if x.ParamMarker == nil && x.DeferredExpr == nil {
+			return x, nil
+		}
+		tp := x.GetType()
+		val, err := x.Eval(chunk.Row{})
+		if err != nil {
+			return nil, err
+		}
+		return &expression.Constant{Value: val, RetType: tp}, nil
+	case *expression.ScalarFunction:
+		if !expression.MaybeOverOptimized4PlanCache(ctx, []expression.Expression{x}) {
+			return x, nil
+		}
+		args := make([]expression.Expression, 0, len(x.GetArgs()))
+		for _, arg := range x.GetArgs() {
+			newArg, err := materializeMutableConst(ctx, arg)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, newArg)
+		}
+		return expression.NewFunction(ctx, x.FuncName.L, x.RetType, args...)
+	}
+	return expr, nil
+}
@@ -92,6 +92,47 @@ func TestPhysicalOptimizeWithTraceEnabled(t *testing.T) {
 		logicalList, physicalList := getList(otrace)
 		require.True(t, checkList(logicalList, testcase.logicalList))
 		require.True(t, checkList(physicalList, testcase.physicalList))
+		require.NotEmpty(t, otrace.CostComparisons)
+		for i, comparison := range otrace.CostComparisons {
+			require.Equal(t, i, comparison.Index)
+			require.NotEmpty(t, comparison.Candidate)
+			require.Contains(t, testcase.logicalList, comparison.MappingLogicalPlan)
+		}
+	}
+}
+
+func TestOptimizeTraceTarget(t *testing.T) {
+	p := parser.New()
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	ctx := tk.Session().(sessionctx.Context)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, index ib(b))")
+	for _, target := range []string{"", core.TracePlanTargetLogical, core.TracePlanTargetPhysical} {
+		stmt, err := p.ParseOneStmt("select a from t where b > 1", "", "")
+		require.NoError(t, err)
+		err = core.Preprocess(ctx, stmt, core.WithPreprocessorReturn(&core.PreprocessorReturn{InfoSchema: dom.InfoSchema()}))
+		require.NoError(t, err)
+		sctx := core.MockContext()
+		sctx.GetSessionVars().StmtCtx.EnableOptimizeTrace = true
+		sctx.GetSessionVars().StmtCtx.OptimizeTraceTarget = target
+		builder, _ := core.NewPlanBuilder().Init(sctx, dom.InfoSchema(), &hint.BlockHintProcessor{})
+		domain.GetDomain(sctx).MockInfoCacheAndLoadInfoSchema(dom.InfoSchema())
+		plan, err := builder.Build(context.TODO(), stmt)
+		require.NoError(t, err)
+		_, _, err = core.DoOptimize(context.TODO(), sctx, builder.GetOptFlag(), plan.(core.LogicalPlan))
+		require.NoError(t, err)
+		otrace := sctx.GetSessionVars().StmtCtx.OptimizeTracer
+		require.NotNil(t, otrace)
+		require.Equal(t, target != core.TracePlanTargetPhysical, otrace.Logical != nil)
+		require.Equal(t, target != core.TracePlanTargetLogical, otrace.Physical != nil)
+		require.NotEmpty(t, otrace.FinalPlan)
+		if otrace.Physical != nil {
+			// The table scan and the index scans are compared with each other.
+			require.GreaterOrEqual(t, len(otrace.Physical.CostComparisons), 2)
+		}
 	}
 }
 
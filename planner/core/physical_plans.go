@@ -41,6 +41,7 @@ var (
 	_ PhysicalPlan = &PhysicalSelection{}
 	_ PhysicalPlan = &PhysicalProjection{}
 	_ PhysicalPlan = &PhysicalTopN{}
+	_ PhysicalPlan = &PhysicalReservoirSample{}
 	_ PhysicalPlan = &PhysicalMaxOneRow{}
 	_ PhysicalPlan = &PhysicalTableDual{}
 	_ PhysicalPlan = &PhysicalUnionAll{}
@@ -371,6 +372,10 @@ type PhysicalIndexScan struct {
 	DBName     model.CIStr
 
 	TableAsName *model.CIStr
+	// viewPath is the views which the table is read in, from the outermost one.
+	viewPath []*viewQueryBlock
+	// SnapshotTS is the snapshot to read the table if it differs from the statement's, otherwise it is 0.
+	SnapshotTS uint64
 
 	// dataSourceSchema is the original schema of DataSource. The schema of index scan in KV and index reader in TiDB
 	// will be different. The schema of index scan will decode all columns of index but the TiDB only need some of them.
@@ -461,6 +466,10 @@ type PhysicalTableScan struct {
 	Ranges  []*ranger.Range
 
 	TableAsName *model.CIStr
+	// viewPath is the views which the table is read in, from the outermost one.
+	viewPath []*viewQueryBlock
+	// SnapshotTS is the snapshot to read the table if it differs from the statement's, otherwise it is 0.
+	SnapshotTS uint64
 
 	// Hist is the histogram when the query was issued.
 	// It is used for query feedback.
@@ -667,6 +676,28 @@ func (lt *PhysicalTopN) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
 	return corCols
 }
 
+// PhysicalReservoirSample is the physical operator of `ORDER BY RAND() LIMIT N`. It picks
+// Offset+Count rows uniformly at random from its child by reservoir sampling, so the
+// child rows don't need to be sorted, then skips the first Offset rows of the sample.
+type PhysicalReservoirSample struct {
+	basePhysicalPlan
+
+	Offset uint64
+	Count  uint64
+}
+
+// Clone implements PhysicalPlan interface.
+func (p *PhysicalReservoirSample) Clone() (PhysicalPlan, error) {
+	cloned := new(PhysicalReservoirSample)
+	*cloned = *p
+	base, err := p.basePhysicalPlan.cloneWithSelf(cloned)
+	if err != nil {
+		return nil, err
+	}
+	cloned.basePhysicalPlan = *base
+	return cloned, nil
+}
+
 // PhysicalApply represents apply plan, only used for subquery.
 type PhysicalApply struct {
 	PhysicalHashJoin
@@ -989,6 +1020,14 @@ type PhysicalUnionAll struct {
 	physicalSchemaProducer
 
 	mpp bool
+	// ByItems is not empty when every child is sorted by the same keys, the
+	// children are then merged so the output keeps the order.
+	ByItems []*util.ByItems
+}
+
+// KeepOrder returns whether the UnionAll merges its sorted children.
+func (p *PhysicalUnionAll) KeepOrder() bool {
+	return len(p.ByItems) > 0
 }
 
 // Clone implements PhysicalPlan interface.
@@ -999,6 +1038,10 @@ func (p *PhysicalUnionAll) Clone() (PhysicalPlan, error) {
 		return nil, err
 	}
 	cloned.physicalSchemaProducer = *base
+	cloned.mpp = p.mpp
+	for _, it := range p.ByItems {
+		cloned.ByItems = append(cloned.ByItems, it.Clone())
+	}
 	return cloned, nil
 }
 
@@ -1025,6 +1068,9 @@ type basePhysicalAgg struct {
 	GroupByItems     []expression.Expression
 	MppRunMode       AggMppRunMode
 	MppPartitionCols []*property.MPPPartitionColumn
+
+	// viewPath is the views which the aggregation is in, from the outermost one.
+	viewPath []*viewQueryBlock
 }
 
 func (p *basePhysicalAgg) isFinalAgg() bool {
@@ -1047,6 +1093,7 @@ func (p *basePhysicalAgg) cloneWithSelf(newSelf PhysicalPlan) (*basePhysicalAgg,
 		cloned.AggFuncs = append(cloned.AggFuncs, aggDesc.Clone())
 	}
 	cloned.GroupByItems = cloneExprs(p.GroupByItems)
+	cloned.viewPath = p.viewPath
 	return cloned, nil
 }
 
@@ -1117,6 +1164,7 @@ func NewPhysicalHashAgg(la *LogicalAggregation, newStats *property.StatsInfo, pr
 	agg := basePhysicalAgg{
 		GroupByItems: la.GroupByItems,
 		AggFuncs:     la.AggFuncs,
+		viewPath:     la.viewPath,
 	}.initForHash(la.ctx, newStats, la.blockOffset, prop)
 	return agg
 }
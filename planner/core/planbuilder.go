@@ -77,6 +77,22 @@ type visitInfo struct {
 	alterWritable    bool
 	dynamicPriv      string
 	dynamicWithGrant bool
+	// colPrivTable is set for the SELECT privilege of a base table, the check also passes if the user has the
+	// privilege on any column of the table. The referenced columns are checked by their own visitInfo.
+	colPrivTable *model.TableInfo
+}
+
+// requestAnyColumn checks whether the privilege of the visitInfo is granted on any column of colPrivTable.
+func (v *visitInfo) requestAnyColumn(verify func(column string) bool) bool {
+	if v.colPrivTable == nil {
+		return false
+	}
+	for _, col := range v.colPrivTable.Columns {
+		if col.State == model.StatePublic && !col.Hidden && verify(col.Name.L) {
+			return true
+		}
+	}
+	return false
 }
 
 type indexNestedLoopJoinTables struct {
@@ -90,6 +106,7 @@ type tableHintInfo struct {
 	sortMergeJoinTables         []hintTableInfo
 	broadcastJoinTables         []hintTableInfo
 	broadcastJoinPreferredLocal []hintTableInfo
+	shuffleJoinTables           []hintTableInfo
 	hashJoinTables              []hintTableInfo
 	indexHintList               []indexHintInfo
 	tiflashTables               []hintTableInfo
@@ -122,9 +139,15 @@ type indexHintInfo struct {
 	// If an indexHintInfo is not matched after building
 	// a Select statement, we will generate a warning for it.
 	matched bool
+	// prioritized indicates the hint is an ACCESS_PATH hint, whose
+	// indexes are tried in order and the first applicable one is used.
+	prioritized bool
 }
 
 func (hint *indexHintInfo) hintTypeString() string {
+	if hint.prioritized {
+		return HintAccessPath
+	}
 	switch hint.indexHint.HintType {
 	case ast.HintUse:
 		return "use_index"
@@ -223,6 +246,10 @@ func (info *tableHintInfo) ifPreferBroadcastJoin(tableNames ...*hintTableInfo) b
 	return info.matchTableName(tableNames, info.broadcastJoinTables)
 }
 
+func (info *tableHintInfo) ifPreferShuffleJoin(tableNames ...*hintTableInfo) bool {
+	return info.matchTableName(tableNames, info.shuffleJoinTables)
+}
+
 func (info *tableHintInfo) ifPreferHashJoin(tableNames ...*hintTableInfo) bool {
 	return info.matchTableName(tableNames, info.hashJoinTables)
 }
@@ -456,7 +483,10 @@ type PlanBuilder struct {
 	// colMapper stores the column that must be pre-resolved.
 	colMapper map[*ast.ColumnNameExpr]int
 	// visitInfo is used for privilege check.
-	visitInfo     []visitInfo
+	visitInfo []visitInfo
+	// colPrivNames maps the unique ID of the base table columns to their names, the SELECT privilege of the
+	// columns is checked once they are referenced.
+	colPrivNames  map[int64]*types.FieldName
 	tableHintInfo []tableHintInfo
 	// optFlag indicates the flags of the optimizer rules.
 	optFlag uint64
@@ -476,6 +506,10 @@ type PlanBuilder struct {
 	// inStraightJoin represents whether the current "SELECT" statement has
 	// "STRAIGHT_JOIN" option.
 	inStraightJoin bool
+	// inStraightJoinTableSource represents whether the table source being built
+	// is in a "SELECT" statement with "STRAIGHT_JOIN" option. The derived tables
+	// and views inherit the option, so the order of their tables is kept as well.
+	inStraightJoinTableSource bool
 
 	// handleHelper records the handle column position for tables. Delete/Update/SelectLock/UnionScan may need this information.
 	// It collects the information by the following procedure:
@@ -1084,11 +1118,15 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 
 	// Extract comment-style index hint like /*+ INDEX(t, idx1, idx2) */.
 	indexHintsLen := len(indexHints)
+	var prioritizedHints []*ast.IndexHint
 	if tableHints != nil {
 		for i, hint := range tableHints.indexHintList {
 			if hint.dbName.L == dbName.L && hint.tblName.L == tblName.L {
 				indexHints = append(indexHints, hint.indexHint)
 				tableHints.indexHintList[i].matched = true
+				if hint.prioritized {
+					prioritizedHints = append(prioritizedHints, hint.indexHint)
+				}
 			}
 		}
 	}
@@ -1150,6 +1188,17 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 		available = publicPaths
 	}
 
+	// The indexes of ACCESS_PATH hints are prioritized by their positions in the hints.
+	priority := 0
+	for _, hint := range prioritizedHints {
+		for _, idxName := range hint.IndexNames {
+			if path := getPathByIndexName(publicPaths, idxName, tblInfo); path != nil && path.AccessPriority == 0 {
+				priority++
+				path.AccessPriority = priority
+			}
+		}
+	}
+
 	available = removeIgnoredPaths(available, ignored, tblInfo)
 	if ctx.GetSessionVars().StmtCtx.IsStaleness {
 		// skip tiflash if the statement is for stale read until tiflash support stale read
@@ -1310,10 +1359,11 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 		p := &CleanupIndex{Table: as.Tables[0], IndexName: as.Index}
 		p.setSchemaAndNames(buildCleanupIndexFields())
 		ret = p
-	case ast.AdminChecksumTable:
-		p := &ChecksumTable{Tables: as.Tables}
-		p.setSchemaAndNames(buildChecksumTableSchema())
-		ret = p
+	case ast.AdminChecksumTable, ast.AdminCompareChecksumTable:
+		ret, err = b.buildChecksumTable(ctx, as)
+		if err != nil {
+			return nil, err
+		}
 	case ast.AdminShowNextRowID:
 		p := &ShowNextRowID{TableName: as.Tables[0]}
 		p.setSchemaAndNames(buildShowNextRowID())
@@ -1328,13 +1378,25 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 		for _, col := range p.schema.Columns {
 			col.UniqueID = b.ctx.GetSessionVars().AllocPlanColumnID()
 		}
-		ret = p
+		var lp LogicalPlan = p
 		if as.Where != nil {
-			ret, err = b.buildSelection(ctx, p, as.Where, nil)
+			lp, err = b.buildSelection(ctx, lp, as.Where, nil)
 			if err != nil {
 				return nil, err
 			}
 		}
+		if as.Limit != nil {
+			// The history jobs are read lazily, so all of them can be filtered if
+			// the job number isn't specified, until the limit is reached.
+			if as.JobNumber == 0 {
+				p.JobNumber = math.MaxInt64
+			}
+			lp, err = b.buildLimit(lp, as.Limit)
+			if err != nil {
+				return nil, err
+			}
+		}
+		ret = lp
 	case ast.AdminCancelDDLJobs:
 		p := &CancelDDLJobs{JobIDs: as.JobIDs}
 		p.setSchemaAndNames(buildCancelDDLJobsFields())
@@ -1356,10 +1418,16 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 		p := &ShowSlow{ShowSlow: as.ShowSlow}
 		p.setSchemaAndNames(buildShowSlowSchema())
 		ret = p
+	case ast.AdminKillTopMemory:
+		p := &KillTopMemory{TopN: as.TopN}
+		p.setSchemaAndNames(buildKillTopMemorySchema())
+		ret = p
 	case ast.AdminReloadExprPushdownBlacklist:
 		return &ReloadExprPushdownBlacklist{}, nil
 	case ast.AdminReloadOptRuleBlacklist:
 		return &ReloadOptRuleBlacklist{}, nil
+	case ast.AdminReloadSQLBlocklist:
+		ret = &ReloadSQLBlocklist{}
 	case ast.AdminPluginEnable:
 		return &AdminPlugins{Action: Enable, Plugins: as.Plugins}, nil
 	case ast.AdminPluginDisable:
@@ -1396,6 +1464,42 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 	return ret, nil
 }
 
+// checksumResultRequiredColumns are the columns which must be in the result table of `admin compare checksum table`.
+var checksumResultRequiredColumns = []string{"db_name", "table_name", "checksum_crc64_xor", "total_kvs", "total_bytes"}
+
+func (b *PlanBuilder) buildChecksumTable(ctx context.Context, as *ast.AdminStmt) (*ChecksumTable, error) {
+	p := &ChecksumTable{Tables: as.Tables, WithIndexes: as.ChecksumIndexes}
+	if as.AsOf != nil {
+		startTS, err := calculateTsExpr(b.ctx, as.AsOf)
+		if err != nil {
+			return nil, err
+		}
+		if err = sessionctx.ValidateStaleReadTS(ctx, b.ctx, startTS); err != nil {
+			return nil, err
+		}
+		p.StartTS = startTS
+	}
+	if as.Tp == ast.AdminChecksumTable {
+		p.setSchemaAndNames(buildChecksumTableSchema(p.WithIndexes))
+		return p, nil
+	}
+
+	tbl, err := b.is.TableByName(as.ChecksumResultTable.Schema, as.ChecksumResultTable.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range checksumResultRequiredColumns {
+		if table.FindCol(tbl.Cols(), name) == nil {
+			return nil, ErrUnknownColumn.GenWithStackByArgs(name, as.ChecksumResultTable.Name.O)
+		}
+	}
+	// The checksums of the indexes are compared only if they are stored.
+	p.WithIndexes = table.FindCol(tbl.Cols(), "index_name") != nil
+	p.ResultTable = as.ChecksumResultTable
+	p.setSchemaAndNames(buildCompareChecksumTableSchema())
+	return p, nil
+}
+
 func (b *PlanBuilder) buildPhysicalIndexLookUpReader(ctx context.Context, dbName model.CIStr, tbl table.Table, idx *model.IndexInfo) (Plan, error) {
 	tblInfo := tbl.Meta()
 	physicalID, isPartition := getPhysicalID(tbl)
@@ -2705,7 +2809,7 @@ func buildShowDDLJobsFields() (*expression.Schema, types.NameSlice) {
 }
 
 func buildTableRegionsSchema() (*expression.Schema, types.NameSlice) {
-	schema := newColumnsWithNames(11)
+	schema := newColumnsWithNames(12)
 	schema.Append(buildColumnWithName("", "REGION_ID", mysql.TypeLonglong, 4))
 	schema.Append(buildColumnWithName("", "START_KEY", mysql.TypeVarchar, 64))
 	schema.Append(buildColumnWithName("", "END_KEY", mysql.TypeVarchar, 64))
@@ -2717,6 +2821,7 @@ func buildTableRegionsSchema() (*expression.Schema, types.NameSlice) {
 	schema.Append(buildColumnWithName("", "READ_BYTES", mysql.TypeLonglong, 4))
 	schema.Append(buildColumnWithName("", "APPROXIMATE_SIZE(MB)", mysql.TypeLonglong, 4))
 	schema.Append(buildColumnWithName("", "APPROXIMATE_KEYS", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "LEADER_STORE_ADDRESS", mysql.TypeVarchar, 64))
 	return schema.col2Schema(), schema.names
 }
 
@@ -2756,6 +2861,19 @@ func buildShowSlowSchema() (*expression.Schema, types.NameSlice) {
 	return schema.col2Schema(), schema.names
 }
 
+func buildKillTopMemorySchema() (*expression.Schema, types.NameSlice) {
+	longlongSize, _ := mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeLonglong)
+
+	schema := newColumnsWithNames(6)
+	schema.Append(buildColumnWithName("", "ID", mysql.TypeLonglong, longlongSize))
+	schema.Append(buildColumnWithName("", "USER", mysql.TypeVarchar, 32))
+	schema.Append(buildColumnWithName("", "HOST", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "MEM", mysql.TypeLonglong, longlongSize))
+	schema.Append(buildColumnWithName("", "DISK", mysql.TypeLonglong, longlongSize))
+	schema.Append(buildColumnWithName("", "INFO", mysql.TypeVarchar, 512))
+	return schema.col2Schema(), schema.names
+}
+
 func buildCancelDDLJobsFields() (*expression.Schema, types.NameSlice) {
 	schema := newColumnsWithNames(2)
 	schema.Append(buildColumnWithName("", "JOB_ID", mysql.TypeVarchar, 64))
@@ -2867,6 +2985,7 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 	}.Init(b.ctx)
 	isView := false
 	isSequence := false
+	where := show.Where
 
 	switch show.Tp {
 	case ast.ShowColumns:
@@ -2948,6 +3067,24 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 		if tableInfo.Meta().TempTableType != model.TempTableNone {
 			return nil, ErrOptOnTemporaryTable.GenWithStackByArgs("show table regions")
 		}
+		var extractor ShowRegionsExtractor
+		extracted := extractor.Extract(show)
+		if !extractor.predicatesRemained {
+			// Avoid building Selection, the regions are filtered when retrieving them.
+			where = nil
+			if show.Limit != nil {
+				count, offset, err := extractLimitCountOffset(b.ctx, show.Limit)
+				if err != nil {
+					return nil, err
+				}
+				if count > 0 && count <= math.MaxUint64-offset {
+					extractor.Limit = count + offset
+				}
+			}
+		}
+		if extracted || extractor.Limit > 0 {
+			p.Extractor = &extractor
+		}
 	}
 	schema, names := buildShowSchema(show, isView, isSequence)
 	p.SetSchema(schema)
@@ -2967,8 +3104,8 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 			return nil, err
 		}
 	}
-	if show.Where != nil {
-		np, err = b.buildSelection(ctx, np, show.Where, nil)
+	if where != nil {
+		np, err = b.buildSelection(ctx, np, where, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -2998,6 +3135,12 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 		sort.SetChildren(np)
 		np = sort
 	}
+	if show.Limit != nil {
+		np, err = b.buildLimit(np, show.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return np, nil
 }
 
@@ -3310,7 +3453,15 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	if !ok {
 		return nil, infoschema.ErrTableNotExists.GenWithStackByArgs()
 	}
-	tableInfo := tn.TableInfo
+	tableInfo, dbName := tn.TableInfo, tn.DBInfo.Name
+	target, mapped := b.tableMappingTarget(tn.Schema, tn.Name)
+	if mapped {
+		tbl, err := b.is.TableByName(target.Schema, target.Name)
+		if err != nil {
+			return nil, err
+		}
+		tableInfo, dbName = tbl.Meta(), target.Schema
+	}
 	if tableInfo.IsView() {
 		err := errors.Errorf("insert into view %s is not supported now.", tableInfo.Name.O)
 		if insert.IsReplace {
@@ -3330,6 +3481,12 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	if err != nil {
 		return nil, err
 	}
+	if mapped {
+		// The columns of a mapped table are still referenced by the original name.
+		for _, name := range names {
+			name.TblName = tn.Name
+		}
+	}
 	tableInPlan, ok := b.is.TableByID(tableInfo.ID)
 	if !ok {
 		return nil, errors.Errorf("Can't get table %s.", tableInfo.Name.O)
@@ -3365,7 +3522,7 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		authErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
 	}
 
-	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tn.DBInfo.Name.L,
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, dbName.L,
 		tableInfo.Name.L, "", authErr)
 
 	// `REPLACE INTO` requires both INSERT + DELETE privilege
@@ -3381,7 +3538,7 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 			cmd := strings.ToUpper(mysql.Priv2Str[extraPriv])
 			authErr = ErrTableaccessDenied.GenWithStackByArgs(cmd, user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
 		}
-		b.visitInfo = appendVisitInfo(b.visitInfo, extraPriv, tn.DBInfo.Name.L, tableInfo.Name.L, "", authErr)
+		b.visitInfo = appendVisitInfo(b.visitInfo, extraPriv, dbName.L, tableInfo.Name.L, "", authErr)
 	}
 
 	mockTablePlan := LogicalTableDual{}.Init(b.ctx, b.getSelectOffset())
@@ -3832,6 +3989,10 @@ func (b *PlanBuilder) buildLoadData(ctx context.Context, ld *ast.LoadDataStmt) (
 	if p.OnDuplicate == ast.OnDuplicateKeyHandlingReplace {
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DeletePriv, p.Table.Schema.O, p.Table.Name.O, "", deleteErr)
 	}
+	if !p.IsLocal {
+		// The file is read by the server, which requires the FILE privilege like MySQL.
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.FilePriv, "", "", "", ErrSpecificAccessDenied.GenWithStackByArgs("FILE"))
+	}
 	tableInfo := p.Table.TableInfo
 	tableInPlan, ok := b.is.TableByID(tableInfo.ID)
 	if !ok {
@@ -4231,6 +4392,9 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, v.ReferTable.Schema.L,
 				v.ReferTable.Name.L, "", authErr)
 		}
+		if v.Select != nil {
+			return b.buildCreateTableAsSelect(ctx, v)
+		}
 	case *ast.CreateViewStmt:
 		b.isCreateView = true
 		b.capFlag |= canExpandAST | renameView
@@ -4378,6 +4542,10 @@ const (
 
 	// TracePlanTargetEstimation indicates CE trace target for optimizer trace.
 	TracePlanTargetEstimation = "estimation"
+	// TracePlanTargetLogical indicates the optimizer trace only traces the logical optimization.
+	TracePlanTargetLogical = "logical"
+	// TracePlanTargetPhysical indicates the optimizer trace only traces the physical optimization.
+	TracePlanTargetPhysical = "physical"
 )
 
 // buildTrace builds a trace plan. Inside this method, it first optimize the
@@ -4392,8 +4560,10 @@ func (b *PlanBuilder) buildTrace(trace *ast.TraceStmt) (Plan, error) {
 	}
 	// TODO: forbid trace plan if the statement isn't select read-only statement
 	if trace.TracePlan {
-		if trace.TracePlanTarget != "" && trace.TracePlanTarget != TracePlanTargetEstimation {
-			return nil, errors.New("trace plan target should only be 'estimation'")
+		switch trace.TracePlanTarget {
+		case "", TracePlanTargetEstimation, TracePlanTargetLogical, TracePlanTargetPhysical:
+		default:
+			return nil, errors.New("trace plan target should only be 'estimation', 'logical' or 'physical'")
 		}
 		if trace.TracePlanTarget == TracePlanTargetEstimation {
 			schema := newColumnsWithNames(1)
@@ -4448,6 +4618,95 @@ func (b *PlanBuilder) buildExplainPlan(targetPlan Plan, format string, explainRo
 	return p, p.prepareSchema()
 }
 
+// buildCreateTableAsSelect builds the plan of CREATE TABLE ... SELECT. The select is only built to check the privileges
+// and to infer the types of the columns, it's executed by the DDL owner. The columns of the select result which are not
+// defined in the statement are appended to the column definitions of the statement in the plan.
+func (b *PlanBuilder) buildCreateTableAsSelect(ctx context.Context, stmt *ast.CreateTableStmt) (Plan, error) {
+	var authErr error
+	if b.ctx.GetSessionVars().User != nil {
+		authErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", b.ctx.GetSessionVars().User.AuthUsername,
+			b.ctx.GetSessionVars().User.AuthHostname, stmt.Table.Name.L)
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, stmt.Table.Schema.L,
+		stmt.Table.Name.L, "", authErr)
+
+	// The local temporary tables can't be read by the DDL owner.
+	for _, tn := range extractTableList(stmt.Select, nil, false) {
+		tbl, err := b.is.TableByName(tn.Schema, tn.Name)
+		if err == nil && tbl.Meta().TempTableType == model.TempTableLocal {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("CREATE TABLE ... SELECT from local temporary table")
+		}
+	}
+	selectPlan, err := b.Build(ctx, stmt.Select)
+	if err != nil {
+		return nil, err
+	}
+	names := selectPlan.OutputNames()
+	definedCols := make(map[string]struct{}, len(stmt.Cols))
+	for _, col := range stmt.Cols {
+		definedCols[col.Name.Name.L] = struct{}{}
+	}
+	// The statement is copied, so the AST isn't changed if it's executed again.
+	ctas := *stmt
+	ctas.Cols = append(make([]*ast.ColumnDef, 0, len(stmt.Cols)+len(names)), stmt.Cols...)
+	selectCols := make([]model.CIStr, 0, len(names))
+	for i, name := range names {
+		selectCols = append(selectCols, name.ColName)
+		if _, ok := definedCols[name.ColName.L]; ok {
+			continue
+		}
+		tp, notNull := createTableAsSelectColumnType(selectPlan.Schema().Columns[i].RetType)
+		colDef := &ast.ColumnDef{
+			Name: &ast.ColumnName{Name: name.ColName},
+			Tp:   tp,
+		}
+		if notNull {
+			colDef.Options = append(colDef.Options, &ast.ColumnOption{Tp: ast.ColumnOptionNotNull})
+		}
+		ctas.Cols = append(ctas.Cols, colDef)
+	}
+	return &DDL{Statement: &ctas, SelectCols: selectCols}, nil
+}
+
+// createTableAsSelectColumnType returns the type of the column created for the select result of the type ft,
+// and whether the column is NOT NULL.
+func createTableAsSelectColumnType(ft *types.FieldType) (*types.FieldType, bool) {
+	tp := ft.Clone()
+	notNull := mysql.HasNotNullFlag(tp.Flag)
+	tp.Flag &= mysql.UnsignedFlag | mysql.ZerofillFlag
+	switch tp.Tp {
+	case mysql.TypeNull:
+		tp = types.NewFieldType(mysql.TypeString)
+		tp.Flen = 0
+		tp.Charset, tp.Collate = charset.CharsetBin, charset.CollationBin
+	case mysql.TypeVarString:
+		tp.Tp = mysql.TypeVarchar
+	case mysql.TypeString:
+		if tp.Flen > mysql.MaxFieldCharLength {
+			tp.Tp = mysql.TypeVarchar
+		}
+	case mysql.TypeNewDecimal:
+		if tp.Flen == types.UnspecifiedLength || tp.Flen > mysql.MaxDecimalWidth {
+			tp.Flen = mysql.MaxDecimalWidth
+		}
+		if tp.Decimal == types.UnspecifiedLength || tp.Decimal > mysql.MaxDecimalScale {
+			tp.Decimal = mysql.MaxDecimalScale
+		}
+	case mysql.TypeFloat, mysql.TypeDouble:
+		if tp.Decimal == types.UnspecifiedLength || tp.Decimal >= mysql.NotFixedDec || tp.Flen < tp.Decimal {
+			tp.Flen, tp.Decimal = types.UnspecifiedLength, types.UnspecifiedLength
+		}
+	}
+	if tp.Tp == mysql.TypeVarchar {
+		// The column is a TEXT or BLOB if the length exceeds the limit of VARCHAR.
+		desc, err := charset.GetCharsetInfo(tp.Charset)
+		if tp.Flen == types.UnspecifiedLength || err != nil || tp.Flen*desc.Maxlen > mysql.MaxFieldVarCharLength {
+			tp.Tp, tp.Flen = mysql.TypeLongBlob, types.UnspecifiedLength
+		}
+	}
+	return tp, notNull
+}
+
 // buildExplainFor gets *last* (maybe running or finished) query plan from connection #connection id.
 // See https://dev.mysql.com/doc/refman/8.0/en/explain-for-connection.html.
 func (b *PlanBuilder) buildExplainFor(explainFor *ast.ExplainForStmt) (Plan, error) {
@@ -4467,6 +4726,9 @@ func (b *PlanBuilder) buildExplainFor(explainFor *ast.ExplainForStmt) (Plan, err
 	if !ok || targetPlan == nil {
 		return &Explain{Format: explainFor.Format}, nil
 	}
+	if strings.ToLower(explainFor.Format) == types.ExplainFormatSelectivity {
+		return nil, errors.Errorf("explain format '%s' is not supported by EXPLAIN FOR CONNECTION", explainFor.Format)
+	}
 	var explainRows [][]string
 	if explainFor.Format == types.ExplainFormatROW {
 		explainRows = processInfo.PlanExplainRows
@@ -4478,6 +4740,9 @@ func (b *PlanBuilder) buildExplain(ctx context.Context, explain *ast.ExplainStmt
 	if show, ok := explain.Stmt.(*ast.ShowStmt); ok {
 		return b.buildShow(ctx, show)
 	}
+	if strings.ToLower(explain.Format) == types.ExplainFormatSelectivity {
+		return b.buildExplainSelectivity(ctx, explain)
+	}
 	targetPlan, _, err := OptimizeAstNode(ctx, b.ctx, explain.Stmt, b.is)
 	if err != nil {
 		return nil, err
@@ -4486,6 +4751,32 @@ func (b *PlanBuilder) buildExplain(ctx context.Context, explain *ast.ExplainStmt
 	return b.buildExplainPlan(targetPlan, explain.Format, nil, explain.Analyze, explain.Stmt, nil)
 }
 
+// buildExplainSelectivity optimizes the statement with the cardinality estimation trace enabled, so that the
+// explain result can show how the row count of every predicate is estimated.
+func (b *PlanBuilder) buildExplainSelectivity(ctx context.Context, explain *ast.ExplainStmt) (Plan, error) {
+	if explain.Analyze {
+		return nil, errors.Errorf("explain format '%s' is not supported by EXPLAIN ANALYZE", explain.Format)
+	}
+	stmtCtx := b.ctx.GetSessionVars().StmtCtx
+	origin := stmtCtx.EnableOptimizerCETrace
+	stmtCtx.EnableOptimizerCETrace = true
+	stmtCtx.OptimizerCETrace = nil
+	stmtCtx.OptimizerCETraceDetails = nil
+	defer func() {
+		stmtCtx.EnableOptimizerCETrace = origin
+	}()
+	targetPlan, _, err := OptimizeAstNode(ctx, b.ctx, explain.Stmt, b.is)
+	if err != nil {
+		return nil, err
+	}
+	p, err := b.buildExplainPlan(targetPlan, explain.Format, nil, false, explain.Stmt, nil)
+	if err != nil {
+		return nil, err
+	}
+	p.(*Explain).CETrace = stmtCtx.OptimizerCETrace
+	return p, nil
+}
+
 func (b *PlanBuilder) buildSelectInto(ctx context.Context, sel *ast.SelectStmt) (Plan, error) {
 	if sem.IsEnabled() {
 		return nil, ErrNotSupportedWithSem.GenWithStackByArgs("SELECT INTO")
@@ -4748,13 +5039,31 @@ func (b *PlanBuilder) buildPlanReplayer(pc *ast.PlanReplayerStmt) Plan {
 	return p
 }
 
-func buildChecksumTableSchema() (*expression.Schema, []*types.FieldName) {
-	schema := newColumnsWithNames(5)
+func buildChecksumTableSchema(withIndexes bool) (*expression.Schema, []*types.FieldName) {
+	schema := newColumnsWithNames(6)
+	schema.Append(buildColumnWithName("", "Db_name", mysql.TypeVarchar, 128))
+	schema.Append(buildColumnWithName("", "Table_name", mysql.TypeVarchar, 128))
+	if withIndexes {
+		schema.Append(buildColumnWithName("", "Index_name", mysql.TypeVarchar, 128))
+	}
+	schema.Append(buildColumnWithName("", "Checksum_crc64_xor", mysql.TypeLonglong, 22))
+	schema.Append(buildColumnWithName("", "Total_kvs", mysql.TypeLonglong, 22))
+	schema.Append(buildColumnWithName("", "Total_bytes", mysql.TypeLonglong, 22))
+	return schema.col2Schema(), schema.names
+}
+
+func buildCompareChecksumTableSchema() (*expression.Schema, []*types.FieldName) {
+	schema := newColumnsWithNames(10)
 	schema.Append(buildColumnWithName("", "Db_name", mysql.TypeVarchar, 128))
 	schema.Append(buildColumnWithName("", "Table_name", mysql.TypeVarchar, 128))
+	schema.Append(buildColumnWithName("", "Index_name", mysql.TypeVarchar, 128))
 	schema.Append(buildColumnWithName("", "Checksum_crc64_xor", mysql.TypeLonglong, 22))
 	schema.Append(buildColumnWithName("", "Total_kvs", mysql.TypeLonglong, 22))
 	schema.Append(buildColumnWithName("", "Total_bytes", mysql.TypeLonglong, 22))
+	schema.Append(buildColumnWithName("", "Expected_checksum_crc64_xor", mysql.TypeLonglong, 22))
+	schema.Append(buildColumnWithName("", "Expected_total_kvs", mysql.TypeLonglong, 22))
+	schema.Append(buildColumnWithName("", "Expected_total_bytes", mysql.TypeLonglong, 22))
+	schema.Append(buildColumnWithName("", "Result", mysql.TypeVarchar, 16))
 	return schema.col2Schema(), schema.names
 }
 
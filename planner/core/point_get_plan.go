@@ -470,6 +470,10 @@ func TryFastPlan(ctx sessionctx.Context, node ast.Node) (p Plan) {
 		// the rule of stabilizing results has not taken effect yet, so cannot generate a plan here in this mode
 		return nil
 	}
+	if len(ctx.GetSessionVars().TableMapping) > 0 {
+		// The fast plan takes the tables resolved in the AST, which are not routed by tidb_table_mapping.
+		return nil
+	}
 
 	ctx.GetSessionVars().PlanID = 0
 	ctx.GetSessionVars().PlanColumnID = 0
@@ -16,6 +16,7 @@ package core_test
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"math"
 	"math/rand"
@@ -32,7 +33,9 @@ import (
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/israce"
 	"github.com/pingcap/tidb/util/kvcache"
@@ -2536,6 +2539,118 @@ func (s *testPlanSerialSuite) TestPartitionTable(c *C) {
 	}
 }
 
+// mockSessionManager is a util.SessionManager which only shows the processes of the sessions in it.
+type mockSessionManager struct {
+	PS []*util.ProcessInfo
+}
+
+func (msm *mockSessionManager) ShowTxnList() []*txninfo.TxnInfo {
+	return nil
+}
+
+func (msm *mockSessionManager) ShowProcessList() map[uint64]*util.ProcessInfo {
+	ret := make(map[uint64]*util.ProcessInfo)
+	for _, item := range msm.PS {
+		ret[item.ID] = item
+	}
+	return ret
+}
+
+func (msm *mockSessionManager) GetProcessInfo(id uint64) (*util.ProcessInfo, bool) {
+	for _, item := range msm.PS {
+		if item.ID == id {
+			return item, true
+		}
+	}
+	return &util.ProcessInfo{}, false
+}
+
+func (msm *mockSessionManager) Kill(cid uint64, query bool) {}
+
+func (msm *mockSessionManager) KillAllConnections() {}
+
+func (msm *mockSessionManager) UpdateTLSConfig(cfg *tls.Config) {}
+
+func (msm *mockSessionManager) ServerID() uint64 {
+	return 1
+}
+
+func (s *testPlanSerialSuite) TestPartitionPruningWithParams(c *C) {
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	tk.Se, err = session.CreateSession4TestWithOpt(store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	c.Assert(err, IsNil)
+
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("set @@tidb_enable_list_partition = 1")
+	tk.MustExec("drop table if exists tl, tlc, tr")
+	tk.MustExec("create table tl (a int, b int) partition by list (a) (partition p0 values in (1, 2), partition p1 values in (3, 4), partition p2 values in (5, 6))")
+	tk.MustExec("create table tlc (a int, b int) partition by list columns (a) (partition p0 values in (1, 2), partition p1 values in (3, 4), partition p2 values in (5, 6))")
+	tk.MustExec("create table tr (a int, b int) partition by range (a) (partition p0 values less than (3), partition p1 values less than (5), partition p2 values less than (7))")
+	// checkPartitions checks the partitions accessed by the last execution of tk by explaining it in another session.
+	tk1 := testkit.NewTestKitWithInit(c, store)
+	tk1.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	checkPartitions := func(tbl, partitions string) {
+		tk1.Se.SetSessionManager(&mockSessionManager{PS: []*util.ProcessInfo{tk.Se.ShowProcess()}})
+		rows := tk1.MustQuery(fmt.Sprintf("explain for connection %d", tk.Se.ShowProcess().ID)).Rows()
+		var accessObject string
+		for _, row := range rows {
+			for _, col := range row {
+				if obj := col.(string); strings.HasPrefix(obj, "partition:") {
+					accessObject = obj
+				}
+			}
+		}
+		c.Assert(accessObject, Equals, partitions)
+	}
+	for _, tbl := range []string{"tl", "tlc", "tr"} {
+		tk.MustExec(fmt.Sprintf("insert into %s values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6)", tbl))
+
+		tk.MustExec(fmt.Sprintf("prepare stmt from 'select a from %s where a in (?, ?)'", tbl))
+		tk.MustExec("set @a = 1, @b = 3")
+		tk.MustQuery("execute stmt using @a, @b").Sort().Check(testkit.Rows("1", "3"))
+		checkPartitions(tbl, "partition:p0,p1")
+		tk.MustExec("set @a = 5, @b = 6")
+		tk.MustQuery("execute stmt using @a, @b").Sort().Check(testkit.Rows("5", "6"))
+		checkPartitions(tbl, "partition:p2")
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+		tk.MustExec("set @a = 2, @b = 7")
+		tk.MustQuery("execute stmt using @a, @b").Sort().Check(testkit.Rows("2"))
+		checkPartitions(tbl, "partition:p0")
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+		tk.MustExec(fmt.Sprintf("prepare stmt from 'select a from %s where a = ?'", tbl))
+		tk.MustExec("set @a = 4")
+		tk.MustQuery("execute stmt using @a").Check(testkit.Rows("4"))
+		checkPartitions(tbl, "partition:p1")
+		tk.MustExec("set @a = 6")
+		tk.MustQuery("execute stmt using @a").Check(testkit.Rows("6"))
+		checkPartitions(tbl, "partition:p2")
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+		tk.MustExec(fmt.Sprintf("prepare stmt from 'select a from %s where a in (?, ?) and a in (?, ?)'", tbl))
+		tk.MustExec("set @a = 1, @b = 5, @c = 5, @d = 6")
+		tk.MustQuery("execute stmt using @a, @b, @c, @d").Check(testkit.Rows("5"))
+		checkPartitions(tbl, "partition:p2")
+		tk.MustExec("set @a = 1, @b = 2, @c = 3, @d = 4")
+		tk.MustQuery("execute stmt using @a, @b, @c, @d").Check(testkit.Rows())
+		checkPartitions(tbl, "partition:dual")
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	}
+}
+
 func (s *testPlanSerialSuite) TestPartitionWithVariedDatasources(c *C) {
 	if israce.RaceEnabled {
 		c.Skip("exhaustive types test, skip race test")
@@ -2695,3 +2810,94 @@ func (s *testPlanSerialSuite) TestPartitionWithVariedDatasources(c *C) {
 		}
 	}
 }
+
+func (s *testPrepareSerialSuite) TestGeneralPlanCache(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	tk.Se, err = session.CreateSession4TestWithOpt(store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	c.Assert(err, IsNil)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b varchar(10), key(a))")
+	tk.MustExec("insert into t values (1, 'a'), (2, 'b'), (3, 'c')")
+
+	// The text protocol statements don't use the plan cache by default.
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 a"))
+	tk.MustQuery("select * from t where a = 2").Check(testkit.Rows("2 b"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@tidb_enable_general_plan_cache = 1")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 a"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	start := time.Now()
+	tk.MustQuery("select * from t where a = 2").Check(testkit.Rows("2 b"))
+	c.Assert(tk.Se.GetSessionVars().StartTime.Before(start), IsFalse)
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The internal statements and the prepared statements don't use the general plan cache.
+	stmtCount := len(tk.Se.GetSessionVars().PreparedStmts)
+	rs, err := tk.Se.ExecuteInternal(context.Background(), "select * from t where a = 3 and b = 'c'")
+	c.Assert(err, IsNil)
+	c.Assert(rs.Close(), IsNil)
+	tk.MustExec("prepare stmt from 'select * from t where a = ? and b = \\'c\\''")
+	tk.MustExec("set @a = 3")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("3 c"))
+	tk.MustExec("deallocate prepare stmt")
+	c.Assert(tk.Se.GetSessionVars().PreparedStmts, HasLen, stmtCount)
+	tk.MustQuery("select * from t where a > 1 and b = 'c'").Check(testkit.Rows("3 c"))
+	tk.MustQuery("select * from t where a > 0 and b = 'a'").Check(testkit.Rows("1 a"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The constants in LIMIT are part of the plan.
+	tk.MustQuery("select * from t where a > 1 order by a limit 1").Check(testkit.Rows("2 b"))
+	tk.MustQuery("select * from t where a > 2 order by a limit 1").Check(testkit.Rows("3 c"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select * from t where a > 0 order by a limit 2").Check(testkit.Rows("1 a", "2 b"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustExec("update t set b = 'x' where a = 1")
+	tk.MustExec("update t set b = 'x' where a = 2")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("delete from t where a = 3")
+	tk.MustQuery("select * from t where a >= 1").Check(testkit.Rows("1 x", "2 x"))
+
+	// The statements without constants in WHERE aren't parameterized.
+	tk.MustQuery("select * from t where a is null").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// The internal prepared statements are dropped when they're evicted.
+	tk.MustExec("set @@tidb_general_plan_cache_size = 1")
+	tk.MustQuery("select a from t where b = 'x'").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select * from t where a < 2").Check(testkit.Rows("1 x"))
+	tk.MustQuery("select a from t where b = 'y'").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	c.Assert(len(tk.Se.GetSessionVars().PreparedStmts) <= 1, IsTrue)
+
+	// The statements are also evicted when the used memory exceeds the guard.
+	tk.MustExec("set @@tidb_general_plan_cache_size = 100")
+	orgMaxMemory := core.PreparedPlanCacheMaxMemory.Load()
+	core.PreparedPlanCacheMaxMemory.Store(1)
+	tk.MustQuery("select * from t where a < 3").Check(testkit.Rows("1 x", "2 x"))
+	tk.MustQuery("select a from t where b = 'z'").Check(testkit.Rows())
+	tk.MustQuery("select b from t where a = 2").Check(testkit.Rows("x"))
+	core.PreparedPlanCacheMaxMemory.Store(orgMaxMemory)
+	c.Assert(tk.Se.GetSessionVars().PreparedStmts, HasLen, 1)
+
+	tk.MustExec("set @@tidb_enable_general_plan_cache = 0")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 x"))
+	tk.MustQuery("select * from t where a = 2").Check(testkit.Rows("2 x"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+}
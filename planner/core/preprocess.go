@@ -121,6 +121,10 @@ func Preprocess(ctx sessionctx.Context, node ast.Node, preprocessOpt ...Preproce
 		v.PreprocessorReturn = &PreprocessorReturn{}
 	}
 	node.Accept(&v)
+	if v.exprTranslated {
+		// The flags of the expressions containing the translated ones are stale.
+		ast.SetFlag(node)
+	}
 	// InfoSchema must be non-nil after preprocessing
 	v.ensureInfoSchema()
 
@@ -182,6 +186,10 @@ type preprocessor struct {
 	// len(tableAliasInJoin) may bigger than 1 because the left/right child of join may be subquery that contains `JOIN`
 	tableAliasInJoin []map[string]interface{}
 	withName         map[string]interface{}
+	// snapshotByAsOf indicates the snapshot of the statement is set by the AS OF TIMESTAMP clause of a table.
+	snapshotByAsOf bool
+	// exprTranslated indicates some expressions are translated, such as ARRAY_AGG to JSON_ARRAYAGG.
+	exprTranslated bool
 
 	// values that may be returned
 	*PreprocessorReturn
@@ -430,6 +438,10 @@ func (p *preprocessor) tableByName(tn *ast.TableName) (table.Table, error) {
 		}
 		return nil, err
 	}
+	// The table being created by CREATE TABLE ... SELECT is only accessible by the internal SQLs until it's public.
+	if tbl.Meta().State == model.StateWriteReorganization && !p.ctx.GetSessionVars().InRestrictedSQL {
+		return nil, infoschema.ErrTableNotExists.GenWithStackByArgs(sName, tn.Name)
+	}
 	return tbl, err
 }
 
@@ -518,6 +530,9 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 			p.tableAliasInJoin = p.tableAliasInJoin[:len(p.tableAliasInJoin)-1]
 		}
 	case *ast.FuncCallExpr:
+		if x.FnName.L == pgArrayAgg && p.ctx.GetSessionVars().EnablePGCompatFunctions {
+			return p.translateArrayAgg(x), p.err == nil
+		}
 		// The arguments for builtin NAME_CONST should be constants
 		// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_name-const for details
 		if x.FnName.L == ast.NameConst {
@@ -561,6 +576,19 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 	return in, p.err == nil
 }
 
+// pgArrayAgg is the ARRAY_AGG aggregate function compatible with PostgreSQL.
+const pgArrayAgg = "array_agg"
+
+// translateArrayAgg translates ARRAY_AGG to JSON_ARRAYAGG, which aggregates the values into a JSON array.
+func (p *preprocessor) translateArrayAgg(x *ast.FuncCallExpr) ast.Node {
+	if len(x.Args) != 1 {
+		p.err = expression.ErrIncorrectParameterCount.GenWithStackByArgs(x.FnName.O)
+		return x
+	}
+	p.exprTranslated = true
+	return &ast.AggregateFuncExpr{F: ast.AggFuncJsonArrayagg, Args: x.Args}
+}
+
 func checkAutoIncrementOp(colDef *ast.ColumnDef, index int) (bool, error) {
 	var hasAutoIncrement bool
 
@@ -728,9 +756,11 @@ func (p *preprocessor) checkAdminCheckTableGrammar(stmt *ast.AdminStmt) {
 			return
 		}
 		tempTableType := tableInfo.Meta().TempTableType
-		if (stmt.Tp == ast.AdminCheckTable || stmt.Tp == ast.AdminChecksumTable || stmt.Tp == ast.AdminCheckIndex) && tempTableType != model.TempTableNone {
+		if (stmt.Tp == ast.AdminCheckTable || stmt.Tp == ast.AdminChecksumTable || stmt.Tp == ast.AdminCompareChecksumTable || stmt.Tp == ast.AdminCheckIndex) && tempTableType != model.TempTableNone {
 			if stmt.Tp == ast.AdminChecksumTable {
 				p.err = ErrOptOnTemporaryTable.GenWithStackByArgs("admin checksum table")
+			} else if stmt.Tp == ast.AdminCompareChecksumTable {
+				p.err = ErrOptOnTemporaryTable.GenWithStackByArgs("admin compare checksum table")
 			} else if stmt.Tp == ast.AdminCheckTable {
 				p.err = ErrOptOnTemporaryTable.GenWithStackByArgs("admin check table")
 			} else {
@@ -741,6 +771,61 @@ func (p *preprocessor) checkAdminCheckTableGrammar(stmt *ast.AdminStmt) {
 	}
 }
 
+// ctasSessionFunctions are the functions whose results depend on the session or the time they're evaluated,
+// they can't be used in the select of CREATE TABLE ... SELECT which is executed by the DDL owner.
+var ctasSessionFunctions = map[string]struct{}{
+	ast.ConnectionID:     {},
+	ast.CurrentUser:      {},
+	ast.CurrentRole:      {},
+	ast.User:             {},
+	ast.SessionUser:      {},
+	ast.SystemUser:       {},
+	ast.LastInsertId:     {},
+	ast.FoundRows:        {},
+	ast.RowCount:         {},
+	ast.LastVal:          {},
+	ast.GetLock:          {},
+	ast.ReleaseLock:      {},
+	ast.ReleaseAllLocks:  {},
+	ast.IsFreeLock:       {},
+	ast.IsUsedLock:       {},
+	ast.Now:              {},
+	ast.CurrentTimestamp: {},
+	ast.LocalTime:        {},
+	ast.LocalTimestamp:   {},
+	ast.Sysdate:          {},
+	ast.Curdate:          {},
+	ast.CurrentDate:      {},
+	ast.Curtime:          {},
+	ast.CurrentTime:      {},
+	ast.UTCDate:          {},
+	ast.UTCTime:          {},
+	ast.UTCTimestamp:     {},
+	ast.UnixTimestamp:    {},
+}
+
+// ctasSessionChecker checks whether the select of CREATE TABLE ... SELECT depends on the session, which means
+// it refers to variables, parameters or the functions in ctasSessionFunctions.
+type ctasSessionChecker struct {
+	unsupported string
+}
+
+func (c *ctasSessionChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.VariableExpr, *driver.ParamMarkerExpr:
+		c.unsupported = "variables or parameters"
+	case *ast.FuncCallExpr:
+		if _, ok := ctasSessionFunctions[x.FnName.L]; ok {
+			c.unsupported = fmt.Sprintf("function %s()", x.FnName.L)
+		}
+	}
+	return in, c.unsupported != ""
+}
+
+func (c *ctasSessionChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
 func (p *preprocessor) checkCreateTableGrammar(stmt *ast.CreateTableStmt) {
 	if stmt.ReferTable != nil {
 		schema := model.NewCIStr(p.ctx.GetSessionVars().CurrentDB)
@@ -830,9 +915,27 @@ func (p *preprocessor) checkCreateTableGrammar(stmt *ast.CreateTableStmt) {
 		return
 	}
 	if stmt.Select != nil {
-		// FIXME: a temp error noticing 'not implemented' (issue 4754)
-		p.err = errors.New("'CREATE TABLE ... SELECT' is not implemented yet")
-		return
+		if stmt.TemporaryKeyword != ast.TemporaryNone {
+			p.err = errors.New("'CREATE TEMPORARY TABLE ... SELECT' is not implemented yet")
+			return
+		}
+		for _, tn := range extractTableList(stmt.Select, nil, false) {
+			if tn.AsOf != nil {
+				p.err = ErrAsOf.FastGenWithCause("as of timestamp can't be set in CREATE TABLE ... SELECT.")
+				return
+			}
+		}
+		// The select is executed by the DDL owner, so it can't depend on the session creating the table.
+		if p.ctx.GetSessionVars().SnapshotTS != 0 {
+			p.err = ErrNotSupportedYet.GenWithStackByArgs("CREATE TABLE ... SELECT with tidb_snapshot")
+			return
+		}
+		checker := &ctasSessionChecker{}
+		stmt.Select.Accept(checker)
+		if checker.unsupported != "" {
+			p.err = ErrNotSupportedYet.GenWithStackByArgs(checker.unsupported + " in CREATE TABLE ... SELECT")
+			return
+		}
 	} else if len(stmt.Cols) == 0 && stmt.ReferTable == nil {
 		p.err = ddl.ErrTableMustHaveColumns
 		return
@@ -1418,10 +1521,28 @@ func (p *preprocessor) handleTableName(tn *ast.TableName) {
 		return
 	}
 
-	p.handleAsOfAndReadTS(tn.AsOf)
+	snapshotTS := p.handleAsOfAndReadTS(tn.AsOf)
 	if p.err != nil {
 		return
 	}
+	if snapshotTS != 0 {
+		// Resolve the table in the information schema of its own snapshot.
+		is, err := p.snapshotInfoSchema(snapshotTS)
+		if err != nil {
+			p.err = err
+			return
+		}
+		stmtCtx := p.ctx.GetSessionVars().StmtCtx
+		if stmtCtx.TableSnapshotTS == nil {
+			stmtCtx.TableSnapshotTS = make(map[*ast.TableName]uint64)
+		}
+		stmtCtx.TableSnapshotTS[tn] = snapshotTS
+		stmtIS := p.InfoSchema
+		p.InfoSchema = is
+		defer func() {
+			p.InfoSchema = stmtIS
+		}()
+	}
 
 	table, err := p.tableByName(tn)
 	if err != nil {
@@ -1440,6 +1561,92 @@ func (p *preprocessor) handleTableName(tn *ast.TableName) {
 	}
 	tn.TableInfo = tableInfo
 	tn.DBInfo = dbInfo
+	if target, ok := p.tableMappingTarget(tn); ok {
+		// The mapped table is resolved when the plan is built, so the AST keeps the original table.
+		// Only check that the target exists here, and lock the target which is accessed instead.
+		table, err = p.ensureInfoSchema().TableByName(target.Schema, target.Name)
+		if err != nil {
+			p.err = err
+			return
+		}
+		tableInfo = table.Meta()
+		dbInfo, _ = p.ensureInfoSchema().SchemaByTable(tableInfo)
+	}
+	if snapshotTS == 0 && p.flag&inPrepare == 0 {
+		switch p.stmtTp {
+		case TypeSelect, TypeInsert, TypeUpdate, TypeDelete:
+			acquireMDL(p.ctx, p.ensureInfoSchema(), dbInfo, tableInfo)
+		}
+	}
+}
+
+// acquireMDL records the metadata lock of the table for the transaction of the session when metadata lock is enabled,
+// so that the DDL on the table waits for the transaction instead of failing it with "Information schema is changed".
+func acquireMDL(sctx sessionctx.Context, is infoschema.InfoSchema, dbInfo *model.DBInfo, tblInfo *model.TableInfo) {
+	sessVars := sctx.GetSessionVars()
+	if !variable.EnableMDL.Load() || sessVars.InRestrictedSQL || sessVars.SnapshotTS != 0 || sessVars.TxnCtx.IsStaleness {
+		return
+	}
+	if dbInfo == nil || util.IsMemDB(dbInfo.Name.L) || tblInfo.TempTableType != model.TempTableNone || sessVars.HoldMDL(tblInfo.ID) {
+		return
+	}
+	do := domain.GetDomain(sctx)
+	if do == nil {
+		return
+	}
+	// If the table has been changed since the transaction took its information schema, the DDL may have passed
+	// more than one schema state, the transaction can't hold the lock and it still checks the table when committing.
+	latest, ok := do.InfoSchema().TableByID(tblInfo.ID)
+	if !ok || latest.Meta().UpdateTS != tblInfo.UpdateTS {
+		return
+	}
+	if sessVars.AcquireMDL(tblInfo.ID, is.SchemaMetaVersion(), sessVars.TxnCtx.StartTS) {
+		do.RegisterMDLHolder(sessVars)
+	}
+}
+
+// acquireMDLForStmt acquires the metadata locks of the tables resolved in a prepared statement.
+func acquireMDLForStmt(sctx sessionctx.Context, is infoschema.InfoSchema, stmt ast.StmtNode) {
+	if !variable.EnableMDL.Load() {
+		return
+	}
+	switch stmt.(type) {
+	case *ast.SelectStmt, *ast.SetOprStmt, *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+	default:
+		return
+	}
+	collector := &mdlTableCollector{}
+	stmt.Accept(collector)
+	for _, tn := range collector.tables {
+		if tblInfo, ok := is.TableByID(tn.TableInfo.ID); ok {
+			acquireMDL(sctx, is, tn.DBInfo, tblInfo.Meta())
+		}
+	}
+}
+
+// mdlTableCollector collects the resolved table names of a statement.
+type mdlTableCollector struct {
+	tables []*ast.TableName
+}
+
+func (c *mdlTableCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if tn, ok := in.(*ast.TableName); ok && tn.TableInfo != nil {
+		c.tables = append(c.tables, tn)
+	}
+	return in, false
+}
+
+func (c *mdlTableCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// tableMappingTarget returns the table which the table reference of a DML statement is routed to by tidb_table_mapping.
+func (p *preprocessor) tableMappingTarget(tn *ast.TableName) (variable.TableMappingTarget, bool) {
+	switch p.stmtTp {
+	case TypeSelect, TypeInsert, TypeUpdate, TypeDelete:
+		return p.ctx.GetSessionVars().MappedTable(tn.Schema, tn.Name)
+	}
+	return variable.TableMappingTarget{}, false
 }
 
 func (p *preprocessor) checkNotInRepair(tn *ast.TableName) {
@@ -1579,8 +1786,9 @@ func (p *preprocessor) checkFuncCastExpr(node *ast.FuncCastExpr) {
 	}
 }
 
-// handleAsOfAndReadTS tries to handle as of closure, or possibly read_ts.
-func (p *preprocessor) handleAsOfAndReadTS(node *ast.AsOfClause) {
+// handleAsOfAndReadTS tries to handle as of closure, or possibly read_ts. It returns the snapshot of the table
+// if the table is read with a different AS OF TIMESTAMP from the statement, otherwise it returns 0.
+func (p *preprocessor) handleAsOfAndReadTS(node *ast.AsOfClause) (tableSnapshotTS uint64) {
 	if p.stmtTp != TypeSelect {
 		return
 	}
@@ -1656,6 +1864,7 @@ func (p *preprocessor) handleAsOfAndReadTS(node *ast.AsOfClause) {
 			}
 			p.LastSnapshotTS = ts
 			p.IsStaleness = true
+			p.snapshotByAsOf = true
 		}
 	case readTS == 0 && node == nil && readStaleness != 0:
 		// If both readTS and node is empty while the readStaleness isn't, it means we meet following situation:
@@ -1684,26 +1893,37 @@ func (p *preprocessor) handleAsOfAndReadTS(node *ast.AsOfClause) {
 		p.ReadReplicaScope = scope
 	}
 
-	// If the select statement is related to multi tables, we should grantee that all tables use the same timestamp
 	if p.LastSnapshotTS != ts {
-		p.err = ErrAsOf.GenWithStack("can not set different time in the as of")
-		return
-	}
-	if p.LastSnapshotTS != 0 {
-		dom := domain.GetDomain(p.ctx)
-		is, err := dom.GetSnapshotInfoSchema(p.LastSnapshotTS)
-		// if infoschema is empty, LastSnapshotTS init failed
-		if err != nil {
-			p.err = err
+		// The tables of a select statement can be read at different snapshots only if all of them are specified by
+		// the AS OF TIMESTAMP clauses. The prepared statements only evaluate the snapshot of the statement when
+		// executing, so all of their tables must use the same timestamp.
+		if node == nil || ts == 0 || !p.snapshotByAsOf || p.flag&inPrepare > 0 {
+			p.err = ErrAsOf.GenWithStack("can not set different time in the as of")
 			return
 		}
-		if is == nil {
-			p.err = fmt.Errorf("can not get any information schema based on snapshotTS: %d", p.LastSnapshotTS)
+		return ts
+	}
+	if p.LastSnapshotTS != 0 {
+		p.InfoSchema, p.err = p.snapshotInfoSchema(p.LastSnapshotTS)
+		if p.err != nil {
 			return
 		}
-		p.InfoSchema = temptable.AttachLocalTemporaryTableInfoSchema(p.ctx, is)
 	}
 	p.initedLastSnapshotTS = true
+	return
+}
+
+// snapshotInfoSchema returns the information schema of the snapshot.
+func (p *preprocessor) snapshotInfoSchema(snapshotTS uint64) (infoschema.InfoSchema, error) {
+	is, err := domain.GetDomain(p.ctx).GetSnapshotInfoSchema(snapshotTS)
+	// if infoschema is empty, snapshotTS init failed
+	if err != nil {
+		return nil, err
+	}
+	if is == nil {
+		return nil, fmt.Errorf("can not get any information schema based on snapshotTS: %d", snapshotTS)
+	}
+	return temptable.AttachLocalTemporaryTableInfoSchema(p.ctx, is), nil
 }
 
 // ensureInfoSchema get the infoschema from the preprocessor.
@@ -233,11 +233,12 @@ func (s *testValidatorSuite) TestValidator(c *C) {
 		{"CREATE TABLE t (a float(54))", false, types.ErrWrongFieldSpec},
 		{"CREATE TABLE t (a double)", true, nil},
 
-		// FIXME: temporary 'not implemented yet' test for 'CREATE TABLE ... SELECT' (issue 4754)
-		{"CREATE TABLE t SELECT * FROM u", false, errors.New("'CREATE TABLE ... SELECT' is not implemented yet")},
-		{"CREATE TABLE t (m int) SELECT * FROM u", false, errors.New("'CREATE TABLE ... SELECT' is not implemented yet")},
-		{"CREATE TABLE t IGNORE SELECT * FROM u UNION SELECT * from v", false, errors.New("'CREATE TABLE ... SELECT' is not implemented yet")},
-		{"CREATE TABLE t (m int) REPLACE AS (SELECT * FROM u) UNION (SELECT * FROM v)", false, errors.New("'CREATE TABLE ... SELECT' is not implemented yet")},
+		{"CREATE TABLE t SELECT * FROM u", true, nil},
+		{"CREATE TABLE t (m int) SELECT * FROM u", true, nil},
+		{"CREATE TABLE t IGNORE SELECT * FROM u UNION SELECT * from v", true, nil},
+		{"CREATE TABLE t (m int) REPLACE AS (SELECT * FROM u) UNION (SELECT * FROM v)", true, nil},
+		{"CREATE TEMPORARY TABLE t SELECT * FROM u", false, errors.New("'CREATE TEMPORARY TABLE ... SELECT' is not implemented yet")},
+		{"CREATE TABLE t SELECT * FROM u AS OF TIMESTAMP NOW()", false, core.ErrAsOf},
 
 		// issue 24309
 		{"SELECT * FROM t INTO OUTFILE 'ttt' UNION SELECT * FROM u", false, core.ErrWrongUsage.GenWithStackByArgs("UNION", "INTO")},
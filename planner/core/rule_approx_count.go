@@ -0,0 +1,123 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/types"
+)
+
+// approxCountSolver answers `select count(*) from t` by the row count in the statistics of t when
+// `tidb_enable_approx_count` is on. The aggregation is replaced by a projection of the row count
+// over a one-row table dual, so the table is not scanned at all.
+// The row count may lag behind the recent writes, see `variable.TiDBEnableApproxCount` for the bound.
+type approxCountSolver struct {
+}
+
+func (a *approxCountSolver) optimize(ctx context.Context, p LogicalPlan, opt *logicalOptimizeOp) (LogicalPlan, error) {
+	newChildren := make([]LogicalPlan, 0, len(p.Children()))
+	for _, child := range p.Children() {
+		newChild, err := a.optimize(ctx, child, opt)
+		if err != nil {
+			return nil, err
+		}
+		newChildren = append(newChildren, newChild)
+	}
+	p.SetChildren(newChildren...)
+	agg, ok := p.(*LogicalAggregation)
+	if !ok {
+		return p, nil
+	}
+	ds, ok := agg.children[0].(*DataSource)
+	if !ok || !isApproxCountAgg(agg) || !canApproxCount(ds) {
+		return p, nil
+	}
+	count := ds.statisticTable.Count
+	if count < 0 {
+		count = 0
+	}
+	dual := LogicalTableDual{RowCount: 1}.Init(agg.ctx, agg.blockOffset)
+	dual.SetSchema(expression.NewSchema())
+	proj := LogicalProjection{
+		Exprs: []expression.Expression{&expression.Constant{Value: types.NewIntDatum(count), RetType: agg.schema.Columns[0].RetType}},
+	}.Init(agg.ctx, agg.blockOffset)
+	proj.SetSchema(agg.schema.Clone())
+	proj.SetChildren(dual)
+	// The row count changes with the statistics, so the plan can't be cached.
+	agg.ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+	appendApproxCountTraceStep(agg, ds, proj, count, opt)
+	return proj, nil
+}
+
+// isApproxCountAgg checks whether the aggregation is a `count(*)` without group by.
+func isApproxCountAgg(agg *LogicalAggregation) bool {
+	if len(agg.GroupByItems) != 0 || len(agg.AggFuncs) != 1 || agg.schema.Len() != 1 {
+		return false
+	}
+	f := agg.AggFuncs[0]
+	if f.Name != ast.AggFuncCount || f.HasDistinct || len(f.Args) != 1 {
+		return false
+	}
+	// count(*) is rewritten to count(1), count of a non-null constant is the row count as well.
+	con, ok := f.Args[0].(*expression.Constant)
+	return ok && con.DeferredExpr == nil && con.ParamMarker == nil && !con.Value.IsNull()
+}
+
+// canApproxCount checks whether the row count of the data source can be read from the statistics.
+func canApproxCount(ds *DataSource) bool {
+	if len(ds.allConds) != 0 || len(ds.pushedDownConds) != 0 || ds.isForUpdateRead {
+		return false
+	}
+	// The statistics of the partitions and the temporary tables are not maintained at the table level.
+	if ds.tableInfo.GetPartitionInfo() != nil || ds.tableInfo.TempTableType != model.TempTableNone {
+		return false
+	}
+	if ds.statisticTable == nil {
+		ds.statisticTable = getStatsTable(ds.ctx, ds.tableInfo, ds.physicalTableID)
+	}
+	// The row count of the pseudo statistics is made up.
+	if ds.statisticTable.Pseudo {
+		return false
+	}
+	sessVars := ds.ctx.GetSessionVars()
+	// The statistics only reflect the latest committed writes, the historical reads need the exact count.
+	if sessVars.SnapshotTS != 0 || sessVars.StmtCtx.IsStaleness {
+		return false
+	}
+	// The uncommitted writes of the current transaction aren't in the statistics.
+	if txn, err := ds.ctx.Txn(false); err == nil && txn.Valid() && !txn.IsReadOnly() {
+		return false
+	}
+	return true
+}
+
+func appendApproxCountTraceStep(agg *LogicalAggregation, ds *DataSource, proj *LogicalProjection, count int64, opt *logicalOptimizeOp) {
+	action := func() string {
+		return fmt.Sprintf("%v_%v is replaced by %v_%v with the row count %v in the statistics", agg.TP(), agg.ID(), proj.TP(), proj.ID(), count)
+	}
+	reason := func() string {
+		return fmt.Sprintf("%v_%v only counts the rows of %v_%v without predicates", agg.TP(), agg.ID(), ds.TP(), ds.ID())
+	}
+	opt.appendStepToCurrent(agg.ID(), agg.TP(), reason, action)
+}
+
+func (*approxCountSolver) name() string {
+	return "approx_count"
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/statistics/handle"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproxCount(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, tp")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("analyze table t")
+
+	// The table is scanned when the approximate count is disabled.
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("3"))
+	require.False(t, tk.HasPlan("select count(*) from t", "TableDual"))
+
+	tk.MustExec("set @@tidb_enable_approx_count = 1")
+	tk.MustQuery("explain format = 'brief' select count(*) from t").Check(testkit.Rows(
+		"Projection 1.00 root  3->Column#4",
+		"└─TableDual 1.00 root  rows:1"))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("3"))
+	tk.MustQuery("select count(1) from t").Check(testkit.Rows("3"))
+
+	// The new rows are counted after the statistics delta is dumped and loaded.
+	tk.MustExec("insert into t values (4, 4), (5, 5)")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("3"))
+	h := dom.StatsHandle()
+	require.NoError(t, h.DumpStatsDeltaToKV(handle.DumpAll))
+	require.NoError(t, h.Update(dom.InfoSchema()))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("5"))
+
+	// The exact count is used for predicates, nullable arguments, distinct and group by.
+	tk.MustExec("insert into t values (6, null)")
+	tk.MustQuery("select count(*) from t where a > 1").Check(testkit.Rows("5"))
+	tk.MustQuery("select count(b) from t").Check(testkit.Rows("5"))
+	tk.MustQuery("select count(distinct 1) from t").Check(testkit.Rows("1"))
+	tk.MustQuery("select count(*) from t group by a > 3 order by 1").Check(testkit.Rows("3", "3"))
+
+	// The uncommitted writes of the transaction are counted exactly.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (7, 7)")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("7"))
+	tk.MustExec("rollback")
+
+	// The partitioned tables aren't approximated.
+	tk.MustExec("create table tp (a int) partition by hash(a) partitions 2")
+	tk.MustExec("insert into tp values (1), (2)")
+	tk.MustExec("analyze table tp")
+	tk.MustQuery("select count(*) from tp").Check(testkit.Rows("2"))
+	require.False(t, tk.HasPlan("select count(*) from tp", "TableDual"))
+}
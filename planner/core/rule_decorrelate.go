@@ -39,6 +39,11 @@ func (la *LogicalApply) canPullUpAgg() bool {
 	return len(la.children[0].Schema().Keys) > 0
 }
 
+// hasLateralJoinConditions checks if the apply has the ON conditions of a LATERAL join.
+func (la *LogicalApply) hasLateralJoinConditions() bool {
+	return la.isLateral && len(la.EqualConditions)+len(la.LeftConditions)+len(la.RightConditions)+len(la.OtherConditions) > 0
+}
+
 // canPullUp checks if an aggregation can be pulled up. An aggregate function like count(*) cannot be pulled up.
 func (la *LogicalAggregation) canPullUp() bool {
 	if len(la.GroupByItems) > 0 {
@@ -234,7 +239,9 @@ func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan, opt *lo
 			}
 			// We can pull up the equal conditions below the aggregation as the join key of the apply, if only
 			// the equal conditions contain the correlated column of this apply.
-			if sel, ok := agg.children[0].(*LogicalSelection); ok && apply.JoinType == LeftOuterJoin {
+			// The default values of the aggregate functions are filled for the unmatched outer rows, while the
+			// rows filtered by the ON clause of a LEFT JOIN LATERAL must be filled with NULL.
+			if sel, ok := agg.children[0].(*LogicalSelection); ok && apply.JoinType == LeftOuterJoin && !apply.hasLateralJoinConditions() {
 				var (
 					eqCondWithCorCol []*expression.ScalarFunction
 					remainedExpr     []expression.Expression
@@ -21,6 +21,7 @@ import (
 	gomath "math"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/ddl"
@@ -1565,10 +1566,71 @@ func (p *rangeColumnsPruner) partitionRangeForExpr(sctx sessionctx.Context, expr
 		return 0, len(p.data), false
 	}
 
+	if col.RetType.Tp == mysql.TypeTimestamp {
+		// The range boundaries of a TIMESTAMP column are in UTC while the constant is in the
+		// session time zone. Around a daylight saving time transition a local time maps to
+		// more than one UTC time, so the constant becomes the range [lower, upper] in UTC.
+		lower, upper, ok := timestampConstRangeInUTC(sctx, con, col.RetType)
+		if !ok {
+			return 0, len(p.data), false
+		}
+		switch opName {
+		case ast.LT, ast.LE:
+			con = upper
+		case ast.GT, ast.GE:
+			con = lower
+		case ast.EQ:
+			start, _ := p.pruneUseBinarySearch(sctx, ast.GE, lower, op)
+			_, end := p.pruneUseBinarySearch(sctx, ast.LE, upper, op)
+			return start, end, true
+		}
+	}
+
 	start, end := p.pruneUseBinarySearch(sctx, opName, con, op)
 	return start, end, true
 }
 
+// timestampConstRangeInUTC converts a constant compared with a TIMESTAMP column from the
+// session time zone to UTC. Every UTC time which is displayed as the constant in the session
+// time zone falls in the returned range [lower, upper].
+func timestampConstRangeInUTC(sctx sessionctx.Context, con *expression.Constant, tp *types.FieldType) (lower, upper *expression.Constant, ok bool) {
+	sc := sctx.GetSessionVars().StmtCtx
+	val, err := con.Eval(chunk.Row{})
+	if err != nil || val.IsNull() {
+		return nil, nil, false
+	}
+	val, err = val.ConvertTo(sc, tp)
+	if err != nil || val.Kind() != types.KindMysqlTime {
+		return nil, nil, false
+	}
+	t := val.GetMysqlTime()
+	if t.IsZero() {
+		return con, con, true
+	}
+	wall, err := t.GoTime(time.UTC)
+	if err != nil {
+		return nil, nil, false
+	}
+	// The offset of the time zone changes at most once in a day around the constant.
+	tz := sctx.GetSessionVars().Location()
+	minOffset, maxOffset := gomath.MaxInt32, gomath.MinInt32
+	for _, d := range []time.Duration{-24 * time.Hour, 0, 24 * time.Hour} {
+		_, offset := wall.Add(d).In(tz).Zone()
+		if offset < minOffset {
+			minOffset = offset
+		}
+		if offset > maxOffset {
+			maxOffset = offset
+		}
+	}
+	toConst := func(offset int) *expression.Constant {
+		utc := wall.Add(-time.Duration(offset) * time.Second)
+		ts := types.NewTime(types.FromGoTime(utc), mysql.TypeTimestamp, t.Fsp())
+		return &expression.Constant{Value: types.NewTimeDatum(ts), RetType: tp}
+	}
+	return toConst(maxOffset), toConst(minOffset), true
+}
+
 func (p *rangeColumnsPruner) pruneUseBinarySearch(sctx sessionctx.Context, op string, data *expression.Constant, f *expression.ScalarFunction) (start int, end int) {
 	var err error
 	var isNull bool
@@ -0,0 +1,287 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+)
+
+// partitionWiseJoinSolver pushes the join of two partitioned tables below the union of their partitions.
+// For SQL like `select * from t1 join t2 on t1.a = t2.a` where both t1 and t2 are partitioned by `a`
+// with the same partition definitions, the rows can only be joined with the rows in the matching partition,
+// so the join is rewritten to `union all(t1.p0 join t2.p0, t1.p1 join t2.p1, ...)`.
+// The smaller joins run in parallel and each of them builds a smaller hash table.
+// It only works in the static partition prune mode, which unions the partitions in the logical plan.
+type partitionWiseJoinSolver struct {
+}
+
+func (s *partitionWiseJoinSolver) optimize(ctx context.Context, p LogicalPlan, opt *logicalOptimizeOp) (LogicalPlan, error) {
+	newChildren := make([]LogicalPlan, 0, len(p.Children()))
+	for _, child := range p.Children() {
+		newChild, err := s.optimize(ctx, child, opt)
+		if err != nil {
+			return nil, err
+		}
+		newChildren = append(newChildren, newChild)
+	}
+	p.SetChildren(newChildren...)
+	join, ok := p.(*LogicalJoin)
+	if !ok {
+		return p, nil
+	}
+	if union := s.tryPushDownJoin(join, opt); union != nil {
+		return union, nil
+	}
+	return p, nil
+}
+
+// tryPushDownJoin returns the union of the partition joins, or nil if the join can't be done partition by partition.
+func (s *partitionWiseJoinSolver) tryPushDownJoin(join *LogicalJoin, opt *logicalOptimizeOp) LogicalPlan {
+	switch join.JoinType {
+	case InnerJoin, LeftOuterJoin, RightOuterJoin, SemiJoin:
+	default:
+		// The anti semi joins and the left outer semi joins need to know whether the inner side has NULL
+		// values, which may be in another partition.
+		return nil
+	}
+	leftUnion, ok := join.children[0].(*LogicalPartitionUnionAll)
+	if !ok {
+		return nil
+	}
+	rightUnion, ok := join.children[1].(*LogicalPartitionUnionAll)
+	if !ok {
+		return nil
+	}
+	leftParts, leftDS := partitionsOfUnion(leftUnion)
+	rightParts, rightDS := partitionsOfUnion(rightUnion)
+	if leftParts == nil || rightParts == nil {
+		return nil
+	}
+	if !isPartitionWiseJoinable(join, leftDS, rightDS) {
+		return nil
+	}
+
+	children := make([]LogicalPlan, 0, len(leftUnion.children))
+	for i := range leftDS.tableInfo.Partition.Definitions {
+		leftChild, rightChild := leftParts[i], rightParts[i]
+		if leftChild == nil && rightChild == nil {
+			continue
+		}
+		// The partition pruned on one side can't be joined with any rows, it only matters for the outer joins.
+		switch {
+		case leftChild == nil && join.JoinType == RightOuterJoin:
+			leftChild = newEmptyDual(leftUnion)
+		case rightChild == nil && join.JoinType == LeftOuterJoin:
+			rightChild = newEmptyDual(rightUnion)
+		case leftChild == nil || rightChild == nil:
+			continue
+		}
+		children = append(children, cloneJoinForPartition(join, leftChild, rightChild))
+	}
+	if len(children) == 0 {
+		tableDual := LogicalTableDual{RowCount: 0}.Init(join.ctx, join.blockOffset)
+		tableDual.schema = join.schema
+		appendPartitionWiseJoinTraceStep(join, tableDual, children, opt)
+		return tableDual
+	}
+	if len(children) == 1 {
+		appendPartitionWiseJoinTraceStep(join, children[0], children, opt)
+		return children[0]
+	}
+	unionAll := LogicalPartitionUnionAll{}.Init(join.ctx, join.blockOffset)
+	unionAll.SetChildren(children...)
+	unionAll.SetSchema(join.schema.Clone())
+	appendPartitionWiseJoinTraceStep(join, unionAll, children, opt)
+	return unionAll
+}
+
+// partitionsOfUnion returns the partitions of the union indexed by their partition definitions. It returns nil
+// if the children of the union aren't the partitions of one table.
+func partitionsOfUnion(union *LogicalPartitionUnionAll) ([]LogicalPlan, *DataSource) {
+	var first *DataSource
+	for _, child := range union.children {
+		ds, ok := child.(*DataSource)
+		if !ok || (first != nil && ds.tableInfo.ID != first.tableInfo.ID) {
+			return nil, nil
+		}
+		first = ds
+	}
+	if first == nil {
+		return nil, nil
+	}
+	pi := first.tableInfo.GetPartitionInfo()
+	if pi == nil {
+		return nil, nil
+	}
+	idx := make(map[int64]int, len(pi.Definitions))
+	for i, def := range pi.Definitions {
+		idx[def.ID] = i
+	}
+	parts := make([]LogicalPlan, len(pi.Definitions))
+	for _, child := range union.children {
+		ds := child.(*DataSource)
+		i, ok := idx[ds.physicalTableID]
+		if !ok || parts[i] != nil {
+			return nil, nil
+		}
+		parts[i] = ds
+	}
+	return parts, first
+}
+
+// isPartitionWiseJoinable checks whether the two tables are partitioned by the same definitions and
+// the join has an equal condition on their partition columns, so the matching rows are always in
+// the partitions of the same position.
+func isPartitionWiseJoinable(join *LogicalJoin, leftDS, rightDS *DataSource) bool {
+	lpi, rpi := leftDS.tableInfo.GetPartitionInfo(), rightDS.tableInfo.GetPartitionInfo()
+	if !isSamePartitionDefinitions(lpi, rpi) {
+		return false
+	}
+	lcol, rcol := partitionColumnOf(leftDS), partitionColumnOf(rightDS)
+	if lcol == nil || rcol == nil {
+		return false
+	}
+	// The values which are equal under a different type or collation may be in different partitions.
+	if lcol.RetType.Tp != rcol.RetType.Tp || mysql.HasUnsignedFlag(lcol.RetType.Flag) != mysql.HasUnsignedFlag(rcol.RetType.Flag) ||
+		lcol.RetType.Collate != rcol.RetType.Collate {
+		return false
+	}
+	leftKeys, rightKeys, _, _ := join.GetJoinKeys()
+	for i := range leftKeys {
+		if leftKeys[i].Equal(nil, lcol) && rightKeys[i].Equal(nil, rcol) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSamePartitionDefinitions checks whether the two partition infos put the same value to the partitions of the same position.
+func isSamePartitionDefinitions(lpi, rpi *model.PartitionInfo) bool {
+	if lpi.Type != rpi.Type || len(lpi.Definitions) != len(rpi.Definitions) || len(lpi.Columns) != len(rpi.Columns) {
+		return false
+	}
+	switch lpi.Type {
+	case model.PartitionTypeHash:
+		return lpi.Num == rpi.Num
+	case model.PartitionTypeRange:
+		for i := range lpi.Definitions {
+			if !stringSliceEqual(lpi.Definitions[i].LessThan, rpi.Definitions[i].LessThan) {
+				return false
+			}
+		}
+		return true
+	case model.PartitionTypeList:
+		for i := range lpi.Definitions {
+			lvals, rvals := lpi.Definitions[i].InValues, rpi.Definitions[i].InValues
+			if len(lvals) != len(rvals) {
+				return false
+			}
+			for j := range lvals {
+				if !stringSliceEqual(lvals[j], rvals[j]) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func stringSliceEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// partitionColumnOf returns the column of the data source the table is partitioned by. It returns nil if
+// the table is partitioned by an expression or multiple columns, or the column is pruned.
+func partitionColumnOf(ds *DataSource) *expression.Column {
+	pi := ds.tableInfo.GetPartitionInfo()
+	var name string
+	switch len(pi.Columns) {
+	case 0:
+		name = strings.ToLower(strings.Trim(strings.TrimSpace(pi.Expr), "`"))
+	case 1:
+		name = pi.Columns[0].L
+	default:
+		return nil
+	}
+	for i, col := range ds.Columns {
+		if col.Name.L == name && i < ds.schema.Len() {
+			return ds.schema.Columns[i]
+		}
+	}
+	return nil
+}
+
+// newEmptyDual returns an empty table dual with the schema of the union.
+func newEmptyDual(union *LogicalPartitionUnionAll) LogicalPlan {
+	tableDual := LogicalTableDual{RowCount: 0}.Init(union.ctx, union.blockOffset)
+	tableDual.schema = union.schema.Clone()
+	return tableDual
+}
+
+// cloneJoinForPartition clones the join for a pair of the partitions.
+func cloneJoinForPartition(join *LogicalJoin, leftChild, rightChild LogicalPlan) *LogicalJoin {
+	newJoin := join.Shallow()
+	newJoin.SetSchema(join.schema.Clone())
+	newJoin.EqualConditions = make([]*expression.ScalarFunction, 0, len(join.EqualConditions))
+	for _, cond := range join.EqualConditions {
+		newJoin.EqualConditions = append(newJoin.EqualConditions, cond.Clone().(*expression.ScalarFunction))
+	}
+	newJoin.LeftConditions = cloneExprs(join.LeftConditions)
+	newJoin.RightConditions = cloneExprs(join.RightConditions)
+	newJoin.OtherConditions = cloneExprs(join.OtherConditions)
+	newJoin.SetChildren(leftChild, rightChild)
+	return newJoin
+}
+
+func appendPartitionWiseJoinTraceStep(join *LogicalJoin, result LogicalPlan, children []LogicalPlan, opt *logicalOptimizeOp) {
+	action := func() string {
+		buffer := bytes.NewBufferString(fmt.Sprintf("%v_%v is replaced by %v_%v", join.TP(), join.ID(), result.TP(), result.ID()))
+		if len(children) > 1 {
+			buffer.WriteString(" with children[")
+			for i, child := range children {
+				if i > 0 {
+					buffer.WriteString(",")
+				}
+				buffer.WriteString(fmt.Sprintf("%v_%v", child.TP(), child.ID()))
+			}
+			buffer.WriteString("]")
+		}
+		return buffer.String()
+	}
+	reason := func() string {
+		return fmt.Sprintf("the children of %v_%v are partitioned by the join keys in the same way", join.TP(), join.ID())
+	}
+	opt.appendStepToCurrent(join.ID(), join.TP(), reason, action)
+}
+
+func (*partitionWiseJoinSolver) name() string {
+	return "partition_wise_join"
+}
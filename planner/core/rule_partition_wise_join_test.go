@@ -0,0 +1,93 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionWiseJoin(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'static'")
+	tk.MustExec("drop table if exists t1, t2, t3, r1, r2")
+	tk.MustExec("create table t1 (a int, b int) partition by hash(a) partitions 4")
+	tk.MustExec("create table t2 (a int, b int) partition by hash(a) partitions 4")
+	tk.MustExec("create table t3 (a int, b int) partition by hash(a) partitions 3")
+	tk.MustExec("create table r1 (a int, b int) partition by range(a) (partition p0 values less than (3), partition p1 values less than (6), partition p2 values less than maxvalue)")
+	tk.MustExec("create table r2 (a int, b int) partition by range(a) (partition p0 values less than (3), partition p1 values less than (6), partition p2 values less than maxvalue)")
+	for _, tbl := range []string{"t1", "r1"} {
+		tk.MustExec("insert into " + tbl + " values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (null, 7)")
+	}
+	for _, tbl := range []string{"t2", "t3", "r2"} {
+		tk.MustExec("insert into " + tbl + " values (2, 20), (4, 40), (4, 41), (7, 70), (null, 80)")
+	}
+
+	queries := []string{
+		"select t1.b, t2.b from t1 join t2 on t1.a = t2.a",
+		"select t1.b, t2.b from t1 left join t2 on t1.a = t2.a",
+		"select t1.b, t2.b from t1 right join t2 on t1.a = t2.a",
+		"select t1.b from t1 where t1.a in (select a from t2)",
+		"select t1.b, t2.b from t1 join t2 on t1.a = t2.a and t1.b < t2.b where t1.a in (2, 4)",
+		"select t1.b, t2.b from t1 left join t2 on t1.a = t2.a where t1.a in (2, 3)",
+		"select r1.b, r2.b from r1 join r2 on r1.a = r2.a",
+		"select r1.b, r2.b from r1 left join r2 on r1.a = r2.a where r1.a < 3",
+		"select t1.b from t1 where t1.a not in (select a from t2)",
+		"select t1.b, t3.b from t1 join t3 on t1.a = t3.a",
+		"select t1.b, t2.b from t1 join t2 on t1.b = t2.a",
+	}
+	expected := make([][][]interface{}, len(queries))
+	for i, q := range queries {
+		expected[i] = tk.MustQuery(q).Sort().Rows()
+	}
+	tk.MustExec("set @@tidb_opt_enable_partition_wise_join = 1")
+	for i, q := range queries {
+		tk.MustQuery(q).Sort().Check(expected[i])
+	}
+
+	tk.MustQuery("explain format = 'brief' select t1.b, t2.b from t1 join t2 on t1.a = t2.a where t1.a in (2, 3) and t2.a in (2, 3, 4)").Check(testkit.Rows(
+		"PartitionUnion 50.00 root  ",
+		"├─HashJoin 25.00 root  inner join, equal:[eq(test.t1.a, test.t2.a)]",
+		"│ ├─TableReader(Build) 20.00 root  data:Selection",
+		"│ │ └─Selection 20.00 cop[tikv]  in(test.t2.a, 2, 3), in(test.t2.a, 2, 3, 4), not(isnull(test.t2.a))",
+		"│ │   └─TableFullScan 10000.00 cop[tikv] table:t2, partition:p2 keep order:false, stats:pseudo",
+		"│ └─TableReader(Probe) 20.00 root  data:Selection",
+		"│   └─Selection 20.00 cop[tikv]  in(test.t1.a, 2, 3), in(test.t1.a, 2, 3, 4), not(isnull(test.t1.a))",
+		"│     └─TableFullScan 10000.00 cop[tikv] table:t1, partition:p2 keep order:false, stats:pseudo",
+		"└─HashJoin 25.00 root  inner join, equal:[eq(test.t1.a, test.t2.a)]",
+		"  ├─TableReader(Build) 20.00 root  data:Selection",
+		"  │ └─Selection 20.00 cop[tikv]  in(test.t2.a, 2, 3), in(test.t2.a, 2, 3, 4), not(isnull(test.t2.a))",
+		"  │   └─TableFullScan 10000.00 cop[tikv] table:t2, partition:p3 keep order:false, stats:pseudo",
+		"  └─TableReader(Probe) 20.00 root  data:Selection",
+		"    └─Selection 20.00 cop[tikv]  in(test.t1.a, 2, 3), in(test.t1.a, 2, 3, 4), not(isnull(test.t1.a))",
+		"      └─TableFullScan 10000.00 cop[tikv] table:t1, partition:p3 keep order:false, stats:pseudo"))
+	// The anti semi join, the different partition definitions and the join on other columns aren't pushed down.
+	require.False(t, hasPartitionUnionAboveJoin(tk, "select t1.b from t1 where t1.a not in (select a from t2)"))
+	require.False(t, hasPartitionUnionAboveJoin(tk, "select t1.b, t3.b from t1 join t3 on t1.a = t3.a"))
+	require.False(t, hasPartitionUnionAboveJoin(tk, "select t1.b, t2.b from t1 join t2 on t1.b = t2.a"))
+	require.True(t, hasPartitionUnionAboveJoin(tk, "select t1.b, t2.b from t1 join t2 on t1.a = t2.a"))
+	require.True(t, hasPartitionUnionAboveJoin(tk, "select r1.b, r2.b from r1 left join r2 on r1.a = r2.a"))
+}
+
+// hasPartitionUnionAboveJoin checks whether the first operator of the plan is the union of the joins.
+func hasPartitionUnionAboveJoin(tk *testkit.TestKit, sql string) bool {
+	rows := tk.MustQuery("explain format = 'brief' " + sql).Rows()
+	return len(rows) > 1 && rows[0][0] == "PartitionUnion" && rows[1][0] == "├─HashJoin"
+}
@@ -17,9 +17,12 @@ package core
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/stringutil"
@@ -28,6 +31,7 @@ import (
 var (
 	_ ShowPredicateExtractor = &ShowColumnsTableExtractor{}
 	_ ShowPredicateExtractor = &ShowTablesTableExtractor{}
+	_ ShowPredicateExtractor = &ShowRegionsExtractor{}
 )
 
 // ShowPredicateExtractor is used to extract some predicates from `PatternLikeExpr` clause
@@ -141,3 +145,131 @@ func (e *ShowTablesTableExtractor) explainInfo() string {
 	}
 	return s
 }
+
+// ShowRegionsExtractor is used to extract some predicates and the limit of `SHOW TABLE REGIONS`,
+// so the regions can be filtered before requesting their details from PD.
+type ShowRegionsExtractor struct {
+	// RegionIDs represents all region ids we should filter in the result, nil means no restriction.
+	RegionIDs map[uint64]struct{}
+	// LeaderStoreIDs represents all leader store ids we should filter in the result, nil means no restriction.
+	LeaderStoreIDs map[uint64]struct{}
+	// Limit is the number of regions needed by the LIMIT clause including the offset, 0 means no limit.
+	// It is only set when all the predicates are extracted.
+	Limit uint64
+
+	// predicatesRemained indicates whether some predicates can't be extracted and must be evaluated by a Selection.
+	predicatesRemained bool
+}
+
+// Extract implements the ShowPredicateExtractor Extract interface
+func (e *ShowRegionsExtractor) Extract(show *ast.ShowStmt) bool {
+	if show.Where == nil {
+		return false
+	}
+	e.predicatesRemained = !e.extractExpr(show.Where)
+	return e.RegionIDs != nil || e.LeaderStoreIDs != nil
+}
+
+// extractExpr extracts the predicates of the expression and returns whether the whole expression is extracted.
+func (e *ShowRegionsExtractor) extractExpr(expr ast.ExprNode) bool {
+	switch x := expr.(type) {
+	case *ast.ParenthesesExpr:
+		return e.extractExpr(x.Expr)
+	case *ast.BinaryOperationExpr:
+		switch x.Op {
+		case opcode.LogicAnd:
+			// Both sides must be extracted, so don't short circuit here.
+			left := e.extractExpr(x.L)
+			right := e.extractExpr(x.R)
+			return left && right
+		case opcode.EQ:
+			if _, ok := x.L.(*ast.ColumnNameExpr); ok {
+				return e.extractColumn(x.L, []ast.ExprNode{x.R})
+			}
+			return e.extractColumn(x.R, []ast.ExprNode{x.L})
+		}
+	case *ast.PatternInExpr:
+		if !x.Not && x.Sel == nil {
+			return e.extractColumn(x.Expr, x.List)
+		}
+	}
+	return false
+}
+
+// extractColumn extracts the predicate `column IN (list)`, the values are intersected with the extracted ones.
+func (e *ShowRegionsExtractor) extractColumn(expr ast.ExprNode, list []ast.ExprNode) bool {
+	col, ok := expr.(*ast.ColumnNameExpr)
+	if !ok {
+		return false
+	}
+	var target *map[uint64]struct{}
+	switch col.Name.Name.L {
+	case "region_id":
+		target = &e.RegionIDs
+	case "leader_store_id":
+		target = &e.LeaderStoreIDs
+	default:
+		return false
+	}
+	values := make(map[uint64]struct{}, len(list))
+	for _, item := range list {
+		v, ok := item.(*driver.ValueExpr)
+		if !ok {
+			return false
+		}
+		switch v.Kind() {
+		case types.KindInt64:
+			// The ids are unsigned, a negative value never matches.
+			if v.GetInt64() >= 0 {
+				values[uint64(v.GetInt64())] = struct{}{}
+			}
+		case types.KindUint64:
+			values[v.GetUint64()] = struct{}{}
+		default:
+			return false
+		}
+	}
+	if *target == nil {
+		*target = values
+		return true
+	}
+	for id := range *target {
+		if _, ok := values[id]; !ok {
+			delete(*target, id)
+		}
+	}
+	return true
+}
+
+func (e *ShowRegionsExtractor) explainInfo() string {
+	r := new(bytes.Buffer)
+	if e.RegionIDs != nil {
+		r.WriteString(fmt.Sprintf("region_id:[%s], ", sortedUint64Set(e.RegionIDs)))
+	}
+	if e.LeaderStoreIDs != nil {
+		r.WriteString(fmt.Sprintf("leader_store_id:[%s], ", sortedUint64Set(e.LeaderStoreIDs)))
+	}
+	if e.Limit > 0 {
+		r.WriteString(fmt.Sprintf("limit:%d, ", e.Limit))
+	}
+
+	// remove the last ", " in the message info
+	s := r.String()
+	if len(s) > 2 {
+		return s[:len(s)-2]
+	}
+	return s
+}
+
+func sortedUint64Set(set map[uint64]struct{}) string {
+	ids := make([]uint64, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	strs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		strs = append(strs, fmt.Sprintf("%d", id))
+	}
+	return strings.Join(strs, ",")
+}
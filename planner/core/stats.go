@@ -217,6 +217,67 @@ func (ds *DataSource) getGroupNDVs(colGroups [][]*expression.Column) []property.
 			}
 		}
 	}
+	if ds.ctx.GetSessionVars().EnableExtendedStats && ds.statisticTable != nil && ds.statisticTable.ExtendedStats != nil {
+		ndvs = ds.appendGroupNDVsFromExtendedStats(ndvs, colGroups)
+	}
+	return ndvs
+}
+
+// appendGroupNDVsFromExtendedStats appends the NDVs of the column groups which exactly match the cardinality
+// extended stats, if they are not covered by any index.
+func (ds *DataSource) appendGroupNDVsFromExtendedStats(ndvs []property.GroupNDV, colGroups [][]*expression.Column) []property.GroupNDV {
+	for _, g := range colGroups {
+		covered := false
+		for _, ndv := range ndvs {
+			if len(ndv.Cols) != len(g) {
+				continue
+			}
+			covered = true
+			for i, col := range g {
+				// Both slices are sorted according to UniqueID.
+				if col.UniqueID != ndv.Cols[i] {
+					covered = false
+					break
+				}
+			}
+			if covered {
+				break
+			}
+		}
+		if covered {
+			continue
+		}
+		for _, item := range ds.statisticTable.ExtendedStats.Stats {
+			if item.Tp != ast.StatsTypeCardinality || len(item.ColIDs) != len(g) || item.ScalarVals <= 0 {
+				continue
+			}
+			match := true
+			for _, col := range g {
+				found := false
+				for _, id := range item.ColIDs {
+					if col.ID == id {
+						found = true
+						break
+					}
+				}
+				if !found {
+					match = false
+					break
+				}
+			}
+			if match {
+				cols := make([]int64, 0, len(g))
+				for _, col := range g {
+					cols = append(cols, col.UniqueID)
+				}
+				ndvs = append(ndvs, property.GroupNDV{
+					Cols: cols,
+					NDV:  item.ScalarVals,
+				})
+				break
+			}
+		}
+	}
 	return ndvs
 }
 
@@ -252,6 +313,7 @@ func (ds *DataSource) deriveStatsByFilter(conds expression.CNFExprs, filledPaths
 		logutil.BgLogger().Debug("something wrong happened, use the default selectivity", zap.Error(err))
 		selectivity = SelectionFactor
 	}
+	selectivity = ds.adjustSelectivityByDependency(conds, selectivity)
 	stats := ds.tableStats.Scale(selectivity)
 	if ds.ctx.GetSessionVars().OptimizerSelectivityLevel >= 1 {
 		stats.HistColl = stats.HistColl.NewHistCollBySelectivity(ds.ctx, nodes)
@@ -259,6 +321,136 @@ func (ds *DataSource) deriveStatsByFilter(conds expression.CNFExprs, filledPaths
 	return stats
 }
 
+// adjustSelectivityByDependency corrects the selectivity of the equal conditions on two columns with functional
+// dependency extended stats. Suppose `a -> b` with degree d, the selectivity of `a = x and b = y` is estimated by
+// `sel(a = x) * (d + (1 - d) * sel(b = y))` instead of the product under the independence assumption.
+func (ds *DataSource) adjustSelectivityByDependency(conds expression.CNFExprs, selectivity float64) float64 {
+	if !ds.ctx.GetSessionVars().EnableExtendedStats || ds.statisticTable == nil || ds.statisticTable.ExtendedStats == nil {
+		return selectivity
+	}
+	colID2Cond := make(map[int64]expression.Expression, len(conds))
+	for _, cond := range conds {
+		sf, ok := cond.(*expression.ScalarFunction)
+		if !ok || sf.FuncName.L != ast.EQ {
+			continue
+		}
+		args := sf.GetArgs()
+		col, isCol := args[0].(*expression.Column)
+		_, isConst := args[1].(*expression.Constant)
+		if !isCol || !isConst {
+			col, isCol = args[1].(*expression.Column)
+			_, isConst = args[0].(*expression.Constant)
+		}
+		if isCol && isConst {
+			colID2Cond[col.ID] = cond
+		}
+	}
+	if len(colID2Cond) < 2 {
+		return selectivity
+	}
+	adjustedCols := make(map[int64]struct{})
+	for _, item := range ds.statisticTable.ExtendedStats.Stats {
+		if item.Tp != ast.StatsTypeDependency || len(item.ColIDs) != 2 {
+			continue
+		}
+		condX, okX := colID2Cond[item.ColIDs[0]]
+		condY, okY := colID2Cond[item.ColIDs[1]]
+		if !okX || !okY {
+			continue
+		}
+		degreeXY, degreeYX, err := item.DependencyDegrees()
+		if err != nil {
+			logutil.BgLogger().Debug("invalid dependency extended stats", zap.Error(err))
+			continue
+		}
+		// Take the determined column of the stronger dependency.
+		dependentID, dependentCond, degree := item.ColIDs[1], condY, degreeXY
+		if degreeYX > degreeXY {
+			dependentID, dependentCond, degree = item.ColIDs[0], condX, degreeYX
+		}
+		if _, ok := adjustedCols[dependentID]; ok {
+			continue
+		}
+		histColl := ds.tableStats.HistColl
+		selX, _, errX := histColl.Selectivity(ds.ctx, []expression.Expression{condX}, nil)
+		selY, _, errY := histColl.Selectivity(ds.ctx, []expression.Expression{condY}, nil)
+		selXY, _, errXY := histColl.Selectivity(ds.ctx, []expression.Expression{condX, condY}, nil)
+		if errX != nil || errY != nil || errXY != nil {
+			continue
+		}
+		selDependent := selY
+		if dependentCond == condX {
+			selDependent = selX
+		}
+		// If the two conditions are not estimated independently, e.g, by an index on both columns, we keep it.
+		if selDependent <= 0 || math.Abs(selXY-selX*selY) > 1e-9 {
+			continue
+		}
+		selectivity *= (degree + (1-degree)*selDependent) / selDependent
+		adjustedCols[dependentID] = struct{}{}
+	}
+	return math.Min(selectivity, 1)
+}
+
+func (ds *DataSource) derivePathStats(path *util.AccessPath) error {
+	if path.IsTablePath() {
+		err := ds.deriveTablePathStats(path, ds.pushedDownConds, false)
+		if err != nil {
+			return err
+		}
+		path.IsSingleScan = true
+	} else {
+		ds.deriveIndexPathStats(path, ds.pushedDownConds, false)
+		path.IsSingleScan = ds.isCoveringIndex(ds.schema.Columns, path.FullIdxCols, path.FullIdxColLens, ds.tableInfo)
+	}
+	return nil
+}
+
+// derivePathStatsAndSelectByPriority selects the path for the `access_path()` hint. The paths in the hint are tried
+// in order, the first one which has access conditions is selected. If none of them has, the first one is selected.
+func (ds *DataSource) derivePathStatsAndSelectByPriority() error {
+	var first, selected *util.AccessPath
+	for _, path := range ds.possibleAccessPaths {
+		if path.AccessPriority == 0 {
+			continue
+		}
+		if err := ds.derivePathStats(path); err != nil {
+			return err
+		}
+		if first == nil || path.AccessPriority < first.AccessPriority {
+			first = path
+		}
+		if len(path.AccessConds) == 0 && len(path.Ranges) > 0 {
+			continue
+		}
+		if selected == nil || path.AccessPriority < selected.AccessPriority {
+			selected = path
+		}
+	}
+	isApplicable := selected != nil
+	if !isApplicable {
+		selected = first
+	}
+	ds.possibleAccessPaths[0] = selected
+	ds.possibleAccessPaths = ds.possibleAccessPaths[:1]
+	if ds.ctx.GetSessionVars().StmtCtx.InVerboseExplain {
+		tableName := ds.tableInfo.Name.O
+		if ds.TableAsName.O != "" {
+			tableName = ds.TableAsName.O
+		}
+		pathName := "handle"
+		if !selected.IsTablePath() {
+			pathName = "index " + selected.Index.Name.O
+		}
+		reason := "it is the first path with access conditions"
+		if !isApplicable {
+			reason = "no path has access conditions and it is the first path"
+		}
+		ds.ctx.GetSessionVars().StmtCtx.AppendNote(errors.New(fmt.Sprintf("%s of %s is selected by the access_path hint since %s", pathName, tableName, reason)))
+	}
+	return nil
+}
+
 // We bind logic of derivePathStats and tryHeuristics together. When some path matches the heuristic rule, we don't need
 // to derive stats of subsequent paths. In this way we can save unnecessary computation of derivePathStats.
 func (ds *DataSource) derivePathStatsAndTryHeuristics() error {
@@ -269,15 +461,8 @@ func (ds *DataSource) derivePathStatsAndTryHeuristics() error {
 		isRefinedPath                     bool
 	)
 	for _, path := range ds.possibleAccessPaths {
-		if path.IsTablePath() {
-			err := ds.deriveTablePathStats(path, ds.pushedDownConds, false)
-			if err != nil {
-				return err
-			}
-			path.IsSingleScan = true
-		} else {
-			ds.deriveIndexPathStats(path, ds.pushedDownConds, false)
-			path.IsSingleScan = ds.isCoveringIndex(ds.schema.Columns, path.FullIdxCols, path.FullIdxColLens, ds.tableInfo)
+		if err := ds.derivePathStats(path); err != nil {
+			return err
 		}
 		// Try some heuristic rules to select access path.
 		if len(path.Ranges) == 0 {
@@ -404,7 +589,12 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 	// TODO: Can we move ds.deriveStatsByFilter after pruning by heuristics? In this way some computation can be avoided
 	// when ds.possibleAccessPaths are pruned.
 	ds.stats = ds.deriveStatsByFilter(ds.pushedDownConds, ds.possibleAccessPaths)
-	err := ds.derivePathStatsAndTryHeuristics()
+	var err error
+	if hasPrioritizedPath(ds.possibleAccessPaths) {
+		err = ds.derivePathStatsAndSelectByPriority()
+	} else {
+		err = ds.derivePathStatsAndTryHeuristics()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1304,3 +1494,12 @@ func (p *LogicalCTETable) DeriveStats(childStats []*property.StatsInfo, selfSche
 	p.stats = p.seedStat
 	return p.stats, nil
 }
+
+func hasPrioritizedPath(paths []*util.AccessPath) bool {
+	for _, path := range paths {
+		if path.AccessPriority > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -86,3 +86,47 @@ func TestPlanStringer(t *testing.T) {
 		require.Equal(t, tt.plan, core.ToString(p))
 	}
 }
+
+func TestShowRegionsPlanStringer(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int, index idx(a))")
+	tests := []struct {
+		sql  string
+		plan string
+	}{
+		{
+			sql:  "show table t regions where region_id in (3, 1) and leader_store_id = 1 limit 10, 5",
+			plan: "Show(region_id:[1,3], leader_store_id:[1], limit:15)->Limit",
+		},
+		{
+			sql:  "show table t regions where region_id = 1 and start_key like 't%' limit 5",
+			plan: "Show(region_id:[1])->Sel([eq(Column#13, 1) like(Column#14, t%, 92)])->Limit->Projection",
+		},
+		{
+			sql:  "show table t index idx regions limit 5",
+			plan: "Show(limit:5)->Limit",
+		},
+		{
+			sql:  "show table t regions where region_id = 1 or leader_store_id = 1",
+			plan: "Show->Sel([or(eq(Column#49, 1), eq(Column#53, 1))])->Projection",
+		},
+	}
+	parser := parser.New()
+	for _, tt := range tests {
+		stmt, err := parser.ParseOneStmt(tt.sql, "", "")
+		require.NoError(t, err, "for %s", tt.sql)
+		ret := &core.PreprocessorReturn{}
+		err = core.Preprocess(tk.Session(), stmt, core.WithPreprocessorReturn(ret))
+		require.NoError(t, err, "for %s", tt.sql)
+		builder, _ := core.NewPlanBuilder().Init(tk.Session(), ret.InfoSchema, &hint.BlockHintProcessor{})
+		p, err := builder.Build(context.TODO(), stmt)
+		require.NoError(t, err, "for %s", tt.sql)
+		p, err = core.LogicalOptimize(context.TODO(), builder.GetOptFlag(), p.(core.LogicalPlan))
+		require.NoError(t, err, "for %s", tt.sql)
+		require.Equal(t, tt.plan, core.ToString(p), "for %s", tt.sql)
+	}
+}
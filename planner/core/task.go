@@ -1396,6 +1396,22 @@ func (p *PhysicalTopN) attach2Task(tasks ...task) task {
 	return attachPlan2Task(p, rootTask)
 }
 
+// GetCost computes the cost of reservoir sample operator itself.
+func (p *PhysicalReservoirSample) GetCost(count float64) float64 {
+	sessVars := p.ctx.GetSessionVars()
+	cpuCost := count * sessVars.CPUFactor
+	memoryCost := float64(p.Offset+p.Count) * sessVars.MemoryFactor
+	return cpuCost + memoryCost
+}
+
+func (p *PhysicalReservoirSample) attach2Task(tasks ...task) task {
+	t := tasks[0].copy()
+	rootTask := t.convertToRootTask(p.ctx)
+	rootTask.addCost(p.GetCost(rootTask.count()))
+	p.cost = rootTask.cost()
+	return attachPlan2Task(p, rootTask)
+}
+
 // GetCost computes the cost of projection operator itself.
 func (p *PhysicalProjection) GetCost(count float64) float64 {
 	sessVars := p.ctx.GetSessionVars()
@@ -1485,6 +1501,10 @@ func (p *PhysicalUnionAll) attach2Task(tasks ...task) task {
 	sessVars := p.ctx.GetSessionVars()
 	// Children of UnionExec are executed in parallel.
 	t.cst = childMaxCost + float64(1+len(tasks))*sessVars.ConcurrencyFactor
+	if p.KeepOrder() {
+		// Merging the sorted children needs a heap of the children.
+		t.cst += p.statsInfo().RowCount * math.Log2(float64(len(tasks))+1) * sessVars.CPUFactor
+	}
 	p.cost = t.cost()
 	return t
 }
@@ -1906,6 +1926,7 @@ func (p *basePhysicalAgg) newPartialAggregate(copTaskType kv.StoreType, isMPPTas
 			AggFuncs:     finalPref.AggFuncs,
 			GroupByItems: finalPref.GroupByItems,
 			MppRunMode:   p.MppRunMode,
+			viewPath:     p.viewPath,
 		}.initForStream(p.ctx, p.stats, p.blockOffset, prop)
 		finalAgg.schema = finalPref.Schema
 		return partialAgg, finalAgg
@@ -1915,6 +1936,7 @@ func (p *basePhysicalAgg) newPartialAggregate(copTaskType kv.StoreType, isMPPTas
 		AggFuncs:     finalPref.AggFuncs,
 		GroupByItems: finalPref.GroupByItems,
 		MppRunMode:   p.MppRunMode,
+		viewPath:     p.viewPath,
 	}.initForHash(p.ctx, p.stats, p.blockOffset, prop)
 	finalAgg.schema = finalPref.Schema
 	return partialAgg, finalAgg
@@ -333,6 +333,10 @@ func optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 	if err != nil {
 		return nil, nil, 0, err
 	}
+	// The hints of the explained statement are handled when the statement itself is optimized.
+	if _, ok := node.(*ast.ExplainStmt); !ok {
+		hintProcessor.HandleUnusedViewHints()
+	}
 	sctx.GetSessionVars().RewritePhaseInfo.DurationRewrite = time.Since(beginRewrite)
 
 	sctx.GetSessionVars().StmtCtx.Tables = builder.GetDBTableInfo()
@@ -547,7 +551,7 @@ func handleStmtHints(hints []*ast.TableOptimizerHint) (stmtHints stmtctx.StmtHin
 	}
 	hintOffs := make(map[string]int, len(hints))
 	var forceNthPlan *ast.TableOptimizerHint
-	var memoryQuotaHintCnt, useToJAHintCnt, useCascadesHintCnt, noIndexMergeHintCnt, readReplicaHintCnt, maxExecutionTimeCnt, forceNthPlanCnt int
+	var memoryQuotaHintCnt, useToJAHintCnt, useCascadesHintCnt, noIndexMergeHintCnt, readReplicaHintCnt, ignoreCoprCacheHintCnt, maxExecutionTimeCnt, forceNthPlanCnt int
 	setVars := make(map[string]string)
 	setVarsOffs := make([]int, 0, len(hints))
 	for i, hint := range hints {
@@ -567,6 +571,9 @@ func handleStmtHints(hints []*ast.TableOptimizerHint) (stmtHints stmtctx.StmtHin
 		case "read_consistent_replica":
 			hintOffs[hint.HintName.L] = i
 			readReplicaHintCnt++
+		case "ignore_copr_cache":
+			hintOffs[hint.HintName.L] = i
+			ignoreCoprCacheHintCnt++
 		case "max_execution_time":
 			hintOffs[hint.HintName.L] = i
 			maxExecutionTimeCnt++
@@ -656,6 +663,14 @@ func handleStmtHints(hints []*ast.TableOptimizerHint) (stmtHints stmtctx.StmtHin
 		stmtHints.HasReplicaReadHint = true
 		stmtHints.ReplicaRead = byte(kv.ReplicaReadFollower)
 	}
+	// Handle IGNORE_COPR_CACHE
+	if ignoreCoprCacheHintCnt != 0 {
+		if ignoreCoprCacheHintCnt > 1 {
+			warn := errors.New("IGNORE_COPR_CACHE() is defined more than once, only the last definition takes effect")
+			warns = append(warns, warn)
+		}
+		stmtHints.IgnoreCoprCacheHint = true
+	}
 	// Handle MAX_EXECUTION_TIME
 	if maxExecutionTimeCnt != 0 {
 		maxExecutionTime := hints[hintOffs["max_execution_time"]]
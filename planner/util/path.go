@@ -64,6 +64,9 @@ type AccessPath struct {
 	Forced bool
 	// IsSingleScan indicates whether the path is a single index/table scan or table access after index scan.
 	IsSingleScan bool
+	// AccessPriority is the position of the path in the `access_path()` hint, starting from 1.
+	// 0 means the path is not in the hint.
+	AccessPriority int
 }
 
 // IsTablePath returns true if it's IntHandlePath or CommonHandlePath.
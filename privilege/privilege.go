@@ -69,6 +69,9 @@ type Manager interface {
 	// MatchIdentity matches an identity
 	MatchIdentity(user, host string, skipNameResolve bool) (string, string, bool)
 
+	// MatchCertUser finds the only user whose REQUIRE SAN matches the verified client certificate.
+	MatchCertUser(host string, tlsState *tls.ConnectionState) (string, bool)
+
 	// DBIsVisible returns true is the database is visible to current user.
 	DBIsVisible(activeRole []*auth.RoleIdentity, db string) bool
 
@@ -93,6 +96,15 @@ type Manager interface {
 
 	// Get the authentication plugin for a user
 	GetAuthPlugin(user, host string) (string, error)
+
+	// GetConnectionLimits gets the MAX_USER_CONNECTIONS and the resource group of a user.
+	// Requires exact match on user name and host name.
+	GetConnectionLimits(user, host string) (maxUserConnections uint64, resourceGroup string)
+
+	// GetFailedLoginTracking gets whether the consecutive failed logins of a user are tracked for
+	// FAILED_LOGIN_ATTEMPTS and PASSWORD_LOCK_TIME, and the number of them so far.
+	// Requires exact match on user name and host name.
+	GetFailedLoginTracking(user, host string) (enabled bool, failedLoginCount int64)
 }
 
 const key keyType = 0
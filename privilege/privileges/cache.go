@@ -65,7 +65,7 @@ const (
 	References_priv,Alter_priv,Execute_priv,Index_priv,Create_view_priv,Show_view_priv,
 	Create_role_priv,Drop_role_priv,Create_tmp_table_priv,Lock_tables_priv,Create_routine_priv,
 	Alter_routine_priv,Event_priv,Shutdown_priv,Reload_priv,File_priv,Config_priv,Repl_client_priv,Repl_slave_priv,
-	account_locked,plugin FROM mysql.user`
+	account_locked,plugin,max_user_connections,user_attributes FROM mysql.user`
 	sqlLoadGlobalGrantsTable = `SELECT HIGH_PRIORITY Host,User,Priv,With_Grant_Option FROM mysql.global_grants`
 )
 
@@ -99,6 +99,87 @@ type UserRecord struct {
 	Privileges           mysql.PrivilegeType
 	AccountLocked        bool // A role record when this field is true
 	AuthPlugin           string
+	// MaxUserConnections is the maximum number of simultaneous connections of the user, 0 means no limit.
+	MaxUserConnections uint64
+	// PasswordLocking is the FAILED_LOGIN_ATTEMPTS and PASSWORD_LOCK_TIME state of the user.
+	PasswordLocking PasswordLocking
+}
+
+// PasswordLockTimeUnbounded is the PasswordLockTimeDays of PASSWORD_LOCK_TIME UNBOUNDED.
+const PasswordLockTimeUnbounded = -1
+
+// passwordLockingTimeFormat is the format of PasswordLocking.AutoLockedLastChanged.
+const passwordLockingTimeFormat = "2006-01-02 15:04:05"
+
+// PasswordLocking is the json format of the Password_locking field in mysql.user.User_attributes.
+// It tracks the consecutive failed logins of a user and whether the user is temporarily locked by them.
+type PasswordLocking struct {
+	FailedLoginAttempts   int64  `json:"failed_login_attempts"`
+	PasswordLockTimeDays  int64  `json:"password_lock_time_days"`
+	AutoAccountLocked     bool   `json:"auto_account_locked"`
+	FailedLoginCount      int64  `json:"failed_login_count"`
+	AutoLockedLastChanged string `json:"auto_locked_last_changed"`
+}
+
+// ParsePasswordLocking parses the Password_locking field from the json string of mysql.user.User_attributes.
+func ParsePasswordLocking(userAttributes string) (PasswordLocking, error) {
+	var attrs struct {
+		PasswordLocking PasswordLocking `json:"Password_locking"`
+	}
+	err := json.Unmarshal(hack.Slice(userAttributes), &attrs)
+	return attrs.PasswordLocking, err
+}
+
+// TrackingEnabled returns whether the failed logins of the user should be tracked.
+func (l *PasswordLocking) TrackingEnabled() bool {
+	return l.FailedLoginAttempts > 0 && l.PasswordLockTimeDays != 0
+}
+
+// OnFailedLogin counts a failed login, and locks the user if it reaches FAILED_LOGIN_ATTEMPTS.
+func (l *PasswordLocking) OnFailedLogin(now time.Time) {
+	if !l.TrackingEnabled() {
+		return
+	}
+	if locked, _ := l.RemainingLockDays(now); locked {
+		return
+	}
+	if l.AutoAccountLocked {
+		// The lock has expired, start a new round of counting.
+		l.Reset()
+	}
+	l.FailedLoginCount++
+	if l.FailedLoginCount >= l.FailedLoginAttempts {
+		l.AutoAccountLocked = true
+		l.AutoLockedLastChanged = now.Format(passwordLockingTimeFormat)
+	}
+}
+
+// Reset clears the failed login count and unlocks the user.
+func (l *PasswordLocking) Reset() {
+	l.AutoAccountLocked = false
+	l.FailedLoginCount = 0
+	l.AutoLockedLastChanged = ""
+}
+
+// RemainingLockDays returns whether the user is locked by too many consecutive failed logins at now,
+// and the remaining days of the lock. The remaining days is -1 if the lock is unbounded.
+func (l *PasswordLocking) RemainingLockDays(now time.Time) (locked bool, days int64) {
+	if !l.AutoAccountLocked || l.PasswordLockTimeDays == 0 {
+		return false, 0
+	}
+	if l.PasswordLockTimeDays == PasswordLockTimeUnbounded {
+		return true, PasswordLockTimeUnbounded
+	}
+	lockedAt, err := time.ParseInLocation(passwordLockingTimeFormat, l.AutoLockedLastChanged, time.Local)
+	if err != nil {
+		// A broken lock time should not lock the user forever.
+		return false, 0
+	}
+	remaining := lockedAt.Add(time.Duration(l.PasswordLockTimeDays) * 24 * time.Hour).Sub(now)
+	if remaining <= 0 {
+		return false, 0
+	}
+	return true, int64((remaining + 24*time.Hour - 1) / (24 * time.Hour))
 }
 
 // NewUserRecord return a UserRecord, only use for unit test.
@@ -150,6 +231,8 @@ type GlobalPrivValue struct {
 	X509Subject string                    `json:"x509_subject,omitempty"`
 	SAN         string                    `json:"san,omitempty"`
 	SANs        map[util.SANType][]string `json:"-"`
+	// ResourceGroup is the resource group which the connections of the user belong to.
+	ResourceGroup string `json:"resource_group,omitempty"`
 }
 
 // RequireStr returns describe string after `REQUIRE` clause.
@@ -653,6 +736,19 @@ func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField
 			} else {
 				value.AuthPlugin = mysql.AuthNativePassword
 			}
+		case f.ColumnAsName.L == "max_user_connections":
+			value.MaxUserConnections = row.GetUint64(i)
+		case f.ColumnAsName.L == "user_attributes":
+			if row.IsNull(i) {
+				continue
+			}
+			passwordLocking, err := ParsePasswordLocking(row.GetJSON(i).String())
+			if err != nil {
+				logutil.BgLogger().Warn("failed to parse the password locking of user, ignore it",
+					zap.String("user", value.User), zap.String("host", value.Host), zap.Error(err))
+				continue
+			}
+			value.PasswordLocking = passwordLocking
 		case f.Column.Tp == mysql.TypeEnum:
 			if row.GetEnum(i).String() != "Y" {
 				continue
@@ -689,6 +785,7 @@ func (p *MySQLPrivilege) decodeGlobalPrivTableRow(row chunk.Row, fs []*ast.Resul
 					value.Priv.X509Issuer = privValue.X509Issuer
 					value.Priv.X509Subject = privValue.X509Subject
 					value.Priv.SAN = privValue.SAN
+					value.Priv.ResourceGroup = privValue.ResourceGroup
 					if len(value.Priv.SAN) > 0 {
 						value.Priv.SANs, err = util.ParseAndCheckSAN(value.Priv.SAN)
 						if err != nil {
@@ -1100,16 +1197,14 @@ func (p *MySQLPrivilege) RequestVerification(activeRoles []*auth.RoleIdentity, u
 		tableRecord := p.matchTables(r.Username, r.Hostname, db, table)
 		if tableRecord != nil {
 			tablePriv |= tableRecord.TablePriv
-			if column != "" {
-				columnPriv |= tableRecord.ColumnPriv
-			}
 		}
 	}
-	if tablePriv&priv > 0 || columnPriv&priv > 0 {
+	if tablePriv&priv > 0 {
 		return true
 	}
 
-	columnPriv = 0
+	// The Column_priv of tables_priv only tells some columns of the table have the privilege,
+	// so the column level privilege must be checked against columns_priv.
 	for _, r := range roleList {
 		columnRecord := p.matchColumns(r.Username, r.Hostname, db, table, column)
 		if columnRecord != nil {
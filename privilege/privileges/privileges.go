@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/infoschema/perfschema"
@@ -54,6 +55,7 @@ var dynamicPrivs = []string{
 	"RESTRICTED_USER_ADMIN",           // User can not have their access revoked by SUPER users.
 	"RESTRICTED_CONNECTION_ADMIN",     // Can not be killed by PROCESS/CONNECTION_ADMIN privilege
 	"RESTRICTED_REPLICA_WRITER_ADMIN", // Can write to the sever even when tidb_restriced_read_only is turned on.
+	"STATUS_API_CLIENT",               // Can access the HTTP status API when status.require-auth is enabled.
 }
 var dynamicPrivLock sync.Mutex
 
@@ -256,6 +258,37 @@ func (p *UserPrivileges) GetAuthPlugin(user, host string) (string, error) {
 	return "", errors.New("Failed to get plugin for user")
 }
 
+// GetConnectionLimits implements the Manager interface.
+func (p *UserPrivileges) GetConnectionLimits(user, host string) (maxUserConnections uint64, resourceGroup string) {
+	if SkipWithGrant {
+		return 0, ""
+	}
+	mysqlPriv := p.Handle.Get()
+	if record := mysqlPriv.matchUser(user, host); record != nil {
+		maxUserConnections = record.MaxUserConnections
+	}
+	for _, record := range mysqlPriv.Global[user] {
+		if record.Host == host {
+			resourceGroup = record.Priv.ResourceGroup
+			break
+		}
+	}
+	return maxUserConnections, resourceGroup
+}
+
+// GetFailedLoginTracking implements the Manager interface.
+func (p *UserPrivileges) GetFailedLoginTracking(user, host string) (enabled bool, failedLoginCount int64) {
+	if SkipWithGrant {
+		return false, 0
+	}
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.matchUser(user, host)
+	if record == nil {
+		return false, 0
+	}
+	return record.PasswordLocking.TrackingEnabled(), record.PasswordLocking.FailedLoginCount
+}
+
 // MatchIdentity implements the Manager interface.
 func (p *UserPrivileges) MatchIdentity(user, host string, skipNameResolve bool) (u string, h string, success bool) {
 	if SkipWithGrant {
@@ -327,6 +360,15 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 		success = false
 		return
 	}
+	if locked, remainingDays := record.PasswordLocking.RemainingLockDays(time.Now()); locked {
+		logutil.BgLogger().Error("try to login an account locked by consecutive failed logins",
+			zap.String("user", user), zap.String("host", host),
+			zap.Int64("failed login attempts", record.PasswordLocking.FailedLoginAttempts),
+			zap.Int64("password lock time days", record.PasswordLocking.PasswordLockTimeDays),
+			zap.Int64("remaining days", remainingDays))
+		success = false
+		return
+	}
 
 	pwd := record.AuthenticationString
 	if !p.isValidHash(record) {
@@ -489,28 +531,30 @@ func (p *UserPrivileges) checkSSL(priv *globalPrivRecord, tlsState *tls.Connecti
 	}
 }
 
+// certSAN returns the SANs of the given type in the certificate, or false if the type is unsupported.
+func certSAN(cert *x509.Certificate, typ util.SANType) (given []string, supported bool) {
+	switch typ {
+	case util.URI:
+		for _, uri := range cert.URIs {
+			given = append(given, uri.String())
+		}
+	case util.DNS:
+		given = cert.DNSNames
+	case util.IP:
+		for _, ip := range cert.IPAddresses {
+			given = append(given, ip.String())
+		}
+	default:
+		return nil, false
+	}
+	return given, true
+}
+
 func checkCertSAN(priv *globalPrivRecord, cert *x509.Certificate, sans map[util.SANType][]string) (r bool) {
 	r = true
 	for typ, requireOr := range sans {
-		var (
-			unsupported bool
-			given       []string
-		)
-		switch typ {
-		case util.URI:
-			for _, uri := range cert.URIs {
-				given = append(given, uri.String())
-			}
-		case util.DNS:
-			given = cert.DNSNames
-		case util.IP:
-			for _, ip := range cert.IPAddresses {
-				given = append(given, ip.String())
-			}
-		default:
-			unsupported = true
-		}
-		if unsupported {
+		given, supported := certSAN(cert, typ)
+		if !supported {
 			logutil.BgLogger().Warn("skip unsupported SAN type", zap.String("type", string(typ)),
 				zap.String("user", priv.User), zap.String("host", priv.Host))
 			continue
@@ -534,6 +578,63 @@ func checkCertSAN(priv *globalPrivRecord, cert *x509.Certificate, sans map[util.
 	return
 }
 
+// matchCertSAN is like checkCertSAN, but it doesn't log the mismatches, which are expected when looking for the
+// user of a certificate.
+func matchCertSAN(cert *x509.Certificate, sans map[util.SANType][]string) bool {
+	for typ, requireOr := range sans {
+		given, supported := certSAN(cert, typ)
+		if !supported {
+			continue
+		}
+		var givenMatchOne bool
+		for _, req := range requireOr {
+			for _, give := range given {
+				if req == give {
+					givenMatchOne = true
+					break
+				}
+			}
+		}
+		if !givenMatchOne {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchCertUser implements the Manager interface.
+func (p *UserPrivileges) MatchCertUser(host string, tlsState *tls.ConnectionState) (user string, success bool) {
+	if SkipWithGrant || tlsState == nil {
+		return
+	}
+
+	mysqlPriv := p.Handle.Get()
+	for name := range mysqlPriv.Global {
+		// Only the record used by checkSSL is considered, so that the matched user can pass it.
+		record := mysqlPriv.matchGlobalPriv(name, host)
+		if record == nil || record.Broken || record.Priv.SSLType != SslTypeSpecified || len(record.Priv.SANs) == 0 {
+			continue
+		}
+		matched := false
+		for _, chain := range tlsState.VerifiedChains {
+			if len(chain) > 0 && matchCertSAN(chain[0], record.Priv.SANs) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		if success {
+			logutil.BgLogger().Warn("the client certificate matches more than one user",
+				zap.String("user", user), zap.String("another user", name), zap.String("host", host))
+			return "", false
+		}
+		user, success = name, true
+	}
+	return
+}
+
 // DBIsVisible implements the Manager interface.
 func (p *UserPrivileges) DBIsVisible(activeRoles []*auth.RoleIdentity, db string) bool {
 	if SkipWithGrant {
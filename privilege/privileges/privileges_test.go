@@ -159,6 +159,60 @@ func TestCheckTablePrivilege(t *testing.T) {
 	require.True(t, pc2.RequestVerification(activeRoles, "test", "test", "", mysql.IndexPriv))
 }
 
+func TestFailedLoginTracking(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER 'lock_tester'@'localhost' FAILED_LOGIN_ATTEMPTS 2 PASSWORD_LOCK_TIME 1")
+	login := func(authentication []byte) bool {
+		return testkit.NewTestKit(t, store).Session().Auth(&auth.UserIdentity{Username: "lock_tester", Hostname: "localhost"}, authentication, nil)
+	}
+	checkState := func(expected string) {
+		tk.MustQuery(`SELECT JSON_EXTRACT(user_attributes, '$.Password_locking.failed_login_count', '$.Password_locking.auto_account_locked')
+			FROM mysql.user WHERE user = 'lock_tester'`).Check(testkit.Rows(expected))
+	}
+
+	// A successful login resets the failed login count.
+	require.False(t, login([]byte("wrong")))
+	checkState("[1, false]")
+	require.True(t, login(nil))
+	checkState("[0, false]")
+
+	// The account is locked after 2 consecutive failed logins, even with the right password.
+	require.False(t, login([]byte("wrong")))
+	require.False(t, login([]byte("wrong")))
+	checkState("[2, true]")
+	require.False(t, login(nil))
+	checkState("[2, true]")
+
+	// The account is unlocked after the PASSWORD_LOCK_TIME.
+	tk.MustExec(`UPDATE mysql.user SET user_attributes = JSON_SET(user_attributes, '$.Password_locking.auto_locked_last_changed', '2000-01-01 00:00:00')
+		WHERE user = 'lock_tester'`)
+	tk.MustExec("FLUSH PRIVILEGES")
+	require.True(t, login(nil))
+	checkState("[0, false]")
+
+	// ACCOUNT UNLOCK unlocks the account at once.
+	tk.MustExec("ALTER USER 'lock_tester'@'localhost' PASSWORD_LOCK_TIME UNBOUNDED")
+	require.False(t, login([]byte("wrong")))
+	require.False(t, login([]byte("wrong")))
+	require.False(t, login(nil))
+	tk.MustExec("ALTER USER 'lock_tester'@'localhost' ACCOUNT UNLOCK")
+	checkState("[0, false]")
+	require.True(t, login(nil))
+
+	// The failed logins are not tracked if FAILED_LOGIN_ATTEMPTS or PASSWORD_LOCK_TIME is 0.
+	tk.MustExec("ALTER USER 'lock_tester'@'localhost' FAILED_LOGIN_ATTEMPTS 0")
+	require.False(t, login([]byte("wrong")))
+	require.False(t, login([]byte("wrong")))
+	checkState("[0, false]")
+	require.True(t, login(nil))
+
+	// ACCOUNT LOCK locks the account.
+	tk.MustExec("ALTER USER 'lock_tester'@'localhost' ACCOUNT LOCK")
+	require.False(t, login(nil))
+}
+
 func TestCheckViewPrivilege(t *testing.T) {
 	store, clean := newStore(t)
 	defer clean()
@@ -438,6 +492,55 @@ func TestShowColumnGrants(t *testing.T) {
 	require.Equal(t, "GRANT USAGE ON *.* TO 'column'@'%' GRANT SELECT(a), INSERT(c), UPDATE(a, b) ON test.column_table TO 'column'@'%'", strings.Join(gs, " "))
 }
 
+func TestColumnPrivilegeCheck(t *testing.T) {
+	store, clean := newStore(t)
+	defer clean()
+	rootSe := newSession(t, store, dbName)
+	mustExec(t, rootSe, `USE test`)
+	mustExec(t, rootSe, `CREATE USER 'column_check'@'%'`)
+	mustExec(t, rootSe, `CREATE TABLE column_check_t (a int, b int, c int)`)
+	mustExec(t, rootSe, `INSERT INTO column_check_t VALUES (1, 2, 3)`)
+	mustExec(t, rootSe, `GRANT SELECT(a, b) ON test.column_check_t TO 'column_check'@'%'`)
+
+	se := newSession(t, store, dbName)
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "column_check", Hostname: "localhost", AuthUsername: "column_check", AuthHostname: "%"}, nil, nil))
+	pc := privilege.GetPrivilegeManager(se)
+	require.True(t, pc.RequestVerification(nil, "test", "column_check_t", "a", mysql.SelectPriv))
+	require.False(t, pc.RequestVerification(nil, "test", "column_check_t", "c", mysql.SelectPriv))
+	require.False(t, pc.RequestVerification(nil, "test", "column_check_t", "", mysql.SelectPriv))
+
+	mustExec(t, se, `SELECT a, b FROM test.column_check_t WHERE a > 0 ORDER BY b`)
+	mustExec(t, se, `SELECT count(*) FROM test.column_check_t`)
+	mustExec(t, se, `SELECT t1.a FROM test.column_check_t t1 JOIN test.column_check_t t2 ON t1.b = t2.b`)
+	mustExec(t, se, `SELECT a FROM test.column_check_t WHERE b IN (SELECT a FROM test.column_check_t)`)
+
+	_, err := se.ExecuteInternal(context.Background(), `SELECT a, c FROM test.column_check_t`)
+	require.True(t, terror.ErrorEqual(err, core.ErrColumnaccessDenied))
+	require.EqualError(t, err, "[planner:1143]SELECT command denied to user 'column_check'@'%' for column 'c' in table 'column_check_t'")
+	_, err = se.ExecuteInternal(context.Background(), `SELECT a FROM test.column_check_t WHERE c = 3`)
+	require.True(t, terror.ErrorEqual(err, core.ErrColumnaccessDenied))
+	_, err = se.ExecuteInternal(context.Background(), `SELECT * FROM test.column_check_t`)
+	require.True(t, terror.ErrorEqual(err, core.ErrColumnaccessDenied))
+	_, err = se.ExecuteInternal(context.Background(), `SELECT a FROM test.column_check_t t WHERE EXISTS (SELECT 1 FROM test.column_check_t WHERE a = t.c)`)
+	require.True(t, terror.ErrorEqual(err, core.ErrColumnaccessDenied))
+	// The common columns of USING and NATURAL joins are read by the join conditions.
+	mustExec(t, se, `SELECT t1.a FROM test.column_check_t t1 JOIN test.column_check_t t2 USING (b)`)
+	_, err = se.ExecuteInternal(context.Background(), `SELECT t1.a FROM test.column_check_t t1 JOIN test.column_check_t t2 USING (c)`)
+	require.True(t, terror.ErrorEqual(err, core.ErrColumnaccessDenied))
+	_, err = se.ExecuteInternal(context.Background(), `SELECT t1.a FROM test.column_check_t t1 NATURAL JOIN test.column_check_t t2`)
+	require.True(t, terror.ErrorEqual(err, core.ErrColumnaccessDenied))
+
+	// The table level privilege covers all the columns.
+	mustExec(t, rootSe, `GRANT SELECT ON test.column_check_t TO 'column_check'@'%'`)
+	mustExec(t, se, `SELECT * FROM test.column_check_t`)
+	mustExec(t, rootSe, `REVOKE SELECT ON test.column_check_t FROM 'column_check'@'%'`)
+
+	// Users without any privilege on the table can not access it even if no column is referenced.
+	mustExec(t, rootSe, `REVOKE SELECT(a, b) ON test.column_check_t FROM 'column_check'@'%'`)
+	_, err = se.ExecuteInternal(context.Background(), `SELECT count(*) FROM test.column_check_t`)
+	require.True(t, terror.ErrorEqual(err, core.ErrTableaccessDenied))
+}
+
 func TestDropTablePrivileges(t *testing.T) {
 	store, clean := newStore(t)
 	defer clean()
@@ -954,6 +1057,66 @@ func TestCheckCertBasedAuth(t *testing.T) {
 
 }
 
+func TestMatchCertUser(t *testing.T) {
+	store, clean := newStore(t)
+	defer clean()
+
+	se := newSession(t, store, dbName)
+	mustExec(t, se, `CREATE USER 'san1'@'localhost' require san 'URI:spiffe://mesh.pingcap.com/ns/timesh/sa/me1'`)
+	mustExec(t, se, `CREATE USER 'san2'@'localhost' require san 'URI:spiffe://mesh.pingcap.com/ns/timesh/sa/me2, DNS:pingcap.com'`)
+	mustExec(t, se, `CREATE USER 'san3'@'localhost' require san 'DNS:pingcap.com'`)
+	mustExec(t, se, `CREATE USER 'san4'@'127.0.0.1' require san 'URI:spiffe://mesh.pingcap.com/ns/timesh/sa/me4'`)
+	mustExec(t, se, `CREATE USER 'san5'@'localhost' identified by 'abc' require san 'URI:spiffe://mesh.pingcap.com/ns/timesh/sa/me5'`)
+	defer func() {
+		require.True(t, se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+		mustExec(t, se, "drop user 'san1'@'localhost', 'san2'@'localhost', 'san3'@'localhost', 'san4'@'127.0.0.1', 'san5'@'localhost'")
+	}()
+
+	withSAN := func(uri string, dnsNames ...string) *tls.ConnectionState {
+		return connectionState(pkix.Name{}, pkix.Name{}, tls.TLS_AES_128_GCM_SHA256, func(cert *x509.Certificate) {
+			var url url.URL
+			err := url.UnmarshalBinary([]byte(uri))
+			require.NoError(t, err)
+			cert.URIs = append(cert.URIs, &url)
+			cert.DNSNames = dnsNames
+		})
+	}
+
+	pc := privilege.GetPrivilegeManager(se)
+	_, ok := pc.MatchCertUser("localhost", nil)
+	require.False(t, ok)
+	user, ok := pc.MatchCertUser("localhost", withSAN("spiffe://mesh.pingcap.com/ns/timesh/sa/me1"))
+	require.True(t, ok)
+	require.Equal(t, "san1", user)
+	user, ok = pc.MatchCertUser("localhost", withSAN("spiffe://mesh.pingcap.com/ns/timesh/sa/me3", "pingcap.com"))
+	require.True(t, ok)
+	require.Equal(t, "san3", user)
+	// The certificate matches both san2 and san3, so it's ambiguous.
+	_, ok = pc.MatchCertUser("localhost", withSAN("spiffe://mesh.pingcap.com/ns/timesh/sa/me2", "pingcap.com"))
+	require.False(t, ok)
+	_, ok = pc.MatchCertUser("localhost", withSAN("spiffe://mesh.pingcap.com/ns/timesh/sa/unknown"))
+	require.False(t, ok)
+	// The host should match too.
+	_, ok = pc.MatchCertUser("localhost", withSAN("spiffe://mesh.pingcap.com/ns/timesh/sa/me4"))
+	require.False(t, ok)
+	user, ok = pc.MatchCertUser("127.0.0.1", withSAN("spiffe://mesh.pingcap.com/ns/timesh/sa/me4"))
+	require.True(t, ok)
+	require.Equal(t, "san4", user)
+
+	// The mapped user logs in without password, unless it has one.
+	se.GetSessionVars().TLSConnectionState = withSAN("spiffe://mesh.pingcap.com/ns/timesh/sa/me1")
+	user, err := se.MatchCertUser("localhost")
+	require.NoError(t, err)
+	require.True(t, se.Auth(&auth.UserIdentity{Username: user, Hostname: "localhost"}, nil, nil))
+	se.GetSessionVars().TLSConnectionState = withSAN("spiffe://mesh.pingcap.com/ns/timesh/sa/me5")
+	user, err = se.MatchCertUser("localhost")
+	require.NoError(t, err)
+	require.False(t, se.Auth(&auth.UserIdentity{Username: user, Hostname: "localhost"}, nil, nil))
+	se.GetSessionVars().TLSConnectionState = withSAN("spiffe://mesh.pingcap.com/ns/timesh/sa/unknown")
+	_, err = se.MatchCertUser("localhost")
+	require.Error(t, err)
+}
+
 func connectionState(issuer, subject pkix.Name, cipher uint16, opt ...func(c *x509.Certificate)) *tls.ConnectionState {
 	cert := &x509.Certificate{Issuer: issuer, Subject: subject}
 	for _, o := range opt {
@@ -1543,6 +1706,11 @@ func TestLoadDataPrivilege(t *testing.T) {
 	_, err = se.ExecuteInternal(context.Background(), "LOAD DATA LOCAL INFILE '/tmp/load_data_priv.csv' REPLACE INTO TABLE t_load")
 	require.Error(t, err)
 	require.True(t, terror.ErrorEqual(err, core.ErrTableaccessDenied))
+
+	// Loading the file on the server side requires the FILE privilege.
+	_, err = se.ExecuteInternal(context.Background(), "LOAD DATA INFILE 's3://bucket/load_data_priv.csv' INTO TABLE t_load")
+	require.Error(t, err)
+	require.True(t, terror.ErrorEqual(err, core.ErrSpecificAccessDenied))
 }
 
 func TestSelectIntoNoPermissions(t *testing.T) {
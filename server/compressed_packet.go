@@ -0,0 +1,238 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+)
+
+// The compression algorithms of the compressed protocol.
+const (
+	compressionNone = iota
+	compressionZlib
+	compressionZstd
+)
+
+const (
+	// compressedHeaderLen is the length of the header of a compressed packet, which contains the length of
+	// the compressed payload, the compressed sequence and the length of the payload before compression.
+	compressedHeaderLen = 7
+	// minCompressLength is the minimal length of the payload to compress, smaller payloads are sent as is.
+	minCompressLength = 50
+	// defaultZstdLevel is the zstd compression level used when the client does not specify one.
+	defaultZstdLevel = 3
+)
+
+// compressedReader reads the compressed packets from the connection and returns the decompressed stream
+// of the normal packets.
+type compressedReader struct {
+	p   *packetIO
+	buf []byte
+}
+
+func (r *compressedReader) Read(b []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if err := r.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *compressedReader) readCompressedPacket() error {
+	var header [compressedHeaderLen]byte
+	if _, err := io.ReadFull(r.p.bufReadConn, header[:]); err != nil {
+		return errors.Trace(err)
+	}
+	compressedLength := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	sequence := header[3]
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+	if sequence != r.p.compressedSequence {
+		return errInvalidSequence.GenWithStack("invalid compressed sequence %d != %d", sequence, r.p.compressedSequence)
+	}
+	r.p.compressedSequence++
+
+	data := make([]byte, compressedLength)
+	if _, err := io.ReadFull(r.p.bufReadConn, data); err != nil {
+		return errors.Trace(err)
+	}
+	// The payload is not compressed if the uncompressed length is 0.
+	if uncompressedLength == 0 {
+		r.buf = data
+		return nil
+	}
+	decompressed, err := r.p.decompress(data, uncompressedLength)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(decompressed) != uncompressedLength {
+		return errors.Trace(mysql.ErrMalformPacket)
+	}
+	r.buf = decompressed
+	return nil
+}
+
+// compressedWriter buffers the normal packets written to it, and writes them to the connection in compressed
+// packets when flushed.
+type compressedWriter struct {
+	p   *packetIO
+	buf bytes.Buffer
+}
+
+func (w *compressedWriter) Write(data []byte) (int, error) {
+	n, err := w.buf.Write(data)
+	if err != nil {
+		return n, err
+	}
+	for w.buf.Len() >= mysql.MaxPayloadLen {
+		if err := w.writeCompressedPacket(w.buf.Next(mysql.MaxPayloadLen)); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (w *compressedWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	err := w.writeCompressedPacket(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressedWriter) writeCompressedPacket(payload []byte) error {
+	uncompressedLength := 0
+	if len(payload) >= minCompressLength {
+		compressed, err := w.p.compress(payload)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// Send the payload as is if it can not be compressed.
+		if len(compressed) < len(payload) {
+			uncompressedLength = len(payload)
+			payload = compressed
+		}
+	}
+	header := [compressedHeaderLen]byte{
+		byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16),
+		w.p.compressedSequence,
+		byte(uncompressedLength), byte(uncompressedLength >> 8), byte(uncompressedLength >> 16),
+	}
+	w.p.compressedSequence++
+	if _, err := w.p.bufReadConn.Write(append(header[:], payload...)); err != nil {
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	return nil
+}
+
+func (p *packetIO) compress(data []byte) ([]byte, error) {
+	switch p.compressionAlgorithm {
+	case compressionZlib:
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case compressionZstd:
+		if p.zstdEncoder == nil {
+			encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(p.zstdLevel)))
+			if err != nil {
+				return nil, err
+			}
+			p.zstdEncoder = encoder
+		}
+		return p.zstdEncoder.EncodeAll(data, nil), nil
+	}
+	return nil, errors.Errorf("unknown compression algorithm %d", p.compressionAlgorithm)
+}
+
+func (p *packetIO) decompress(data []byte, uncompressedLength int) ([]byte, error) {
+	switch p.compressionAlgorithm {
+	case compressionZlib:
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		decompressed := make([]byte, uncompressedLength)
+		if _, err = io.ReadFull(zr, decompressed); err != nil {
+			return nil, err
+		}
+		return decompressed, zr.Close()
+	case compressionZstd:
+		if p.zstdDecoder == nil {
+			// The window of the frames is bounded by the max payload length, so a small frame can't make the
+			// decoder allocate a huge window.
+			decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(mysql.MaxPayloadLen))
+			if err != nil {
+				return nil, err
+			}
+			p.zstdDecoder = decoder
+		}
+		// The payload is decoded as a stream instead of at once, so no more than the uncompressed length is
+		// decoded and allocated whatever the frames are.
+		if err := p.zstdDecoder.Reset(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		decompressed := make([]byte, uncompressedLength)
+		if _, err := io.ReadFull(p.zstdDecoder, decompressed); err != nil {
+			return nil, err
+		}
+		if n, err := p.zstdDecoder.Read(make([]byte, 1)); n > 0 || err != io.EOF {
+			return nil, mysql.ErrMalformPacket
+		}
+		return decompressed, nil
+	}
+	return nil, errors.Errorf("unknown compression algorithm %d", p.compressionAlgorithm)
+}
+
+// setCompression enables the compressed protocol with the algorithm negotiated in the handshake.
+func (p *packetIO) setCompression(algorithm int, zstdLevel int) {
+	p.compressionAlgorithm = algorithm
+	if zstdLevel <= 0 {
+		zstdLevel = defaultZstdLevel
+	}
+	p.zstdLevel = zstdLevel
+	p.compressedSequence = 0
+	p.compressedReader = &compressedReader{p: p}
+	p.compressedWriter = &compressedWriter{p: p}
+	p.bufWriter = bufio.NewWriterSize(p.compressedWriter, defaultWriterSize)
+}
+
+// closeCompression releases the resources held by the compressed protocol.
+func (p *packetIO) closeCompression() {
+	if p.zstdEncoder != nil {
+		terror.Log(p.zstdEncoder.Close())
+		p.zstdEncoder = nil
+	}
+	if p.zstdDecoder != nil {
+		p.zstdDecoder.Close()
+		p.zstdDecoder = nil
+	}
+}
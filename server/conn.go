@@ -86,6 +86,7 @@ import (
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/otlptrace"
 	topsqlstate "github.com/pingcap/tidb/util/topsql/state"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/util"
@@ -151,16 +152,26 @@ var (
 
 	connIdleDurationHistogramNotInTxn = metrics.ConnIdleDurationHistogram.WithLabelValues("0")
 	connIdleDurationHistogramInTxn    = metrics.ConnIdleDurationHistogram.WithLabelValues("1")
+
+	chunkColumnAllocNew   = metrics.ChunkColumnAllocCounter.WithLabelValues("new")
+	chunkColumnAllocReuse = metrics.ChunkColumnAllocCounter.WithLabelValues("reuse")
 )
 
+// chunkAllocQuotaRatio is the ratio of the query memory quota to the max bytes
+// of the recycled chunk columns cached by a connection.
+const chunkAllocQuotaRatio = 16
+
+// allocatorSize is the capacity of the arena allocator of a connection.
+const allocatorSize = 32 * 1024
+
 // newClientConn creates a *clientConn object.
 func newClientConn(s *Server) *clientConn {
 	return &clientConn{
 		server:       s,
 		connectionID: s.globalConnID.NextID(),
 		collation:    mysql.DefaultCollationID,
-		alloc:        arena.NewAllocator(32 * 1024),
-		chunkAlloc:   chunk.NewAllocator(),
+		alloc:        arena.NewAllocator(allocatorSize),
+		chunkAlloc:   chunk.NewSyncAllocator(0),
 		status:       connStatusDispatching,
 		lastActive:   time.Now(),
 		authPlugin:   mysql.AuthNativePassword,
@@ -170,31 +181,35 @@ func newClientConn(s *Server) *clientConn {
 // clientConn represents a connection between server and client, it maintains connection specific state,
 // handles client query.
 type clientConn struct {
-	pkt           *packetIO         // a helper to read and write data in packet format.
-	bufReadConn   *bufferedReadConn // a buffered-read net.Conn or buffered-read tls.Conn.
-	tlsConn       *tls.Conn         // TLS connection, nil if not TLS.
-	server        *Server           // a reference of server instance.
-	capability    uint32            // client capability affects the way server handles client request.
-	connectionID  uint64            // atomically allocated by a global variable, unique in process scope.
-	user          string            // user of the client.
-	dbname        string            // default database name.
-	salt          []byte            // random bytes used for authentication.
-	alloc         arena.Allocator   // an memory allocator for reducing memory allocation.
-	chunkAlloc    chunk.Allocator
-	lastPacket    []byte            // latest sql query string, currently used for logging error.
-	ctx           *TiDBContext      // an interface to execute sql statements.
-	attrs         map[string]string // attributes parsed from client handshake response, not used for now.
-	peerHost      string            // peer host
-	peerPort      string            // peer port
-	status        int32             // dispatching/reading/shutdown/waitshutdown
-	lastCode      uint16            // last error code
-	collation     uint8             // collation used by client, may be different from the collation used by database.
-	lastActive    time.Time         // last active time
-	authPlugin    string            // default authentication plugin
-	isUnixSocket  bool              // connection is Unix Socket file
-	rsEncoder     *resultEncoder    // rsEncoder is used to encode the string result to different charsets.
-	inputDecoder  *inputDecoder     // inputDecoder is used to decode the different charsets of incoming strings to utf-8.
-	socketCredUID uint32            // UID from the other end of the Unix Socket
+	pkt           *packetIO            // a helper to read and write data in packet format.
+	bufReadConn   *bufferedReadConn    // a buffered-read net.Conn or buffered-read tls.Conn.
+	tlsConn       *tls.Conn            // TLS connection, nil if not TLS.
+	server        *Server              // a reference of server instance.
+	capability    uint32               // client capability affects the way server handles client request.
+	connectionID  uint64               // atomically allocated by a global variable, unique in process scope.
+	user          string               // user of the client.
+	dbname        string               // default database name.
+	salt          []byte               // random bytes used for authentication.
+	alloc         arena.Allocator      // an memory allocator for reducing memory allocation.
+	chunkAlloc    *chunk.SyncAllocator // the chunk allocator shared by the executors of the session.
+	lastPacket    []byte               // latest sql query string, currently used for logging error.
+	ctx           *TiDBContext         // an interface to execute sql statements.
+	attrs         map[string]string    // attributes parsed from client handshake response, not used for now.
+	zstdLevel     uint8                // zstd compression level parsed from client handshake response.
+	peerHost      string               // peer host
+	peerPort      string               // peer port
+	status        int32                // dispatching/reading/shutdown/waitshutdown
+	lastCode      uint16               // last error code
+	collation     uint8                // collation used by client, may be different from the collation used by database.
+	lastActive    time.Time            // last active time
+	authPlugin    string               // default authentication plugin
+	isUnixSocket  bool                 // connection is Unix Socket file
+	rsEncoder     *resultEncoder       // rsEncoder is used to encode the string result to different charsets.
+	inputDecoder  *inputDecoder        // inputDecoder is used to decode the different charsets of incoming strings to utf-8.
+	socketCredUID uint32               // UID from the other end of the Unix Socket
+	resourceGroup string               // resource group of the user, which limits the connections of the group, protected by server.rwlock.
+	userConnKey   string               // key of the user in server.userConns if the connection is counted, protected by server.rwlock.
+	lastPacketCap int64                // capacity of lastPacket, accessed atomically.
 	// mu is used for cancelling the execution of current transaction.
 	mu struct {
 		sync.RWMutex
@@ -202,6 +217,11 @@ type clientConn struct {
 	}
 }
 
+// netBufferBytes returns the memory held by the read/write buffers of the connection.
+func (cc *clientConn) netBufferBytes() int64 {
+	return defaultReaderSize + defaultWriterSize + allocatorSize + atomic.LoadInt64(&cc.lastPacketCap)
+}
+
 func (cc *clientConn) String() string {
 	collationStr := mysql.Collations[cc.collation]
 	return fmt.Sprintf("id:%d, addr:%s status:%b, collation:%s, user:%s",
@@ -290,7 +310,7 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 	}
 
 	err := cc.writePacket(data)
-	cc.pkt.sequence = 0
+	cc.pkt.resetSequence()
 	if err != nil {
 		err = errors.SuspendStack(err)
 		logutil.Logger(ctx).Debug("write response to client failed", zap.Error(err))
@@ -303,12 +323,20 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		logutil.Logger(ctx).Debug("flush response to client failed", zap.Error(err))
 		return err
 	}
+
+	// The compressed protocol is used after the handshake.
+	if cc.capability&mysql.ClientZstdCompressionAlgorithm > 0 {
+		cc.pkt.setCompression(compressionZstd, int(cc.zstdLevel))
+	} else if cc.capability&mysql.ClientCompress > 0 {
+		cc.pkt.setCompression(compressionZlib, 0)
+	}
 	return err
 }
 
 func (cc *clientConn) Close() error {
 	cc.server.rwlock.Lock()
 	delete(cc.server.clients, cc.connectionID)
+	cc.server.releaseUserConnectionWithoutLock(cc)
 	connections := len(cc.server.clients)
 	cc.server.rwlock.Unlock()
 	return closeConn(cc, connections)
@@ -320,6 +348,9 @@ func closeConn(cc *clientConn, connections int) error {
 		err := cc.bufReadConn.Close()
 		terror.Log(err)
 	}
+	if cc.pkt != nil {
+		cc.pkt.closeCompression()
+	}
 	if cc.ctx != nil {
 		return cc.ctx.Close()
 	}
@@ -328,6 +359,7 @@ func closeConn(cc *clientConn, connections int) error {
 
 func (cc *clientConn) closeWithoutLock() error {
 	delete(cc.server.clients, cc.connectionID)
+	cc.server.releaseUserConnectionWithoutLock(cc)
 	return closeConn(cc, len(cc.server.clients))
 }
 
@@ -429,6 +461,7 @@ type handshakeResponse41 struct {
 	Auth       []byte
 	AuthPlugin string
 	Attrs      map[string]string
+	ZstdLevel  uint8
 }
 
 // parseOldHandshakeResponseHeader parses the old version handshake header HandshakeResponse320
@@ -581,9 +614,14 @@ func parseHandshakeResponseBody(ctx context.Context, packet *handshakeResponse41
 				return nil
 			}
 			packet.Attrs = attrs
+			offset += int(num)
 		}
 	}
 
+	if packet.Capability&mysql.ClientZstdCompressionAlgorithm > 0 && len(data[offset:]) > 0 {
+		packet.ZstdLevel = data[offset]
+	}
+
 	return nil
 }
 
@@ -688,6 +726,7 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 	cc.dbname = resp.DBName
 	cc.collation = resp.Collation
 	cc.attrs = resp.Attrs
+	cc.zstdLevel = resp.ZstdLevel
 
 	err = cc.handleAuthPlugin(ctx, &resp)
 	if err != nil {
@@ -799,6 +838,7 @@ func (cc *clientConn) openSession() error {
 	if err != nil {
 		return err
 	}
+	cc.attachChunkAlloc()
 
 	err = cc.server.checkConnectionCount()
 	if err != nil {
@@ -807,6 +847,30 @@ func (cc *clientConn) openSession() error {
 	return nil
 }
 
+// attachChunkAlloc makes the executors of the session allocate chunks from the
+// connection chunk allocator.
+func (cc *clientConn) attachChunkAlloc() {
+	if cc.chunkAlloc == nil {
+		return
+	}
+	vars := cc.ctx.GetSessionVars()
+	cc.chunkAlloc.SetQuota(vars.MemQuotaQuery / chunkAllocQuotaRatio)
+	vars.SetChunkAllocator(cc.chunkAlloc)
+}
+
+// resetChunkAlloc recycles the chunks allocated during the last command and
+// records the statistics of the chunk column allocation.
+func (cc *clientConn) resetChunkAlloc() {
+	cc.chunkAlloc.Reset()
+	stats := cc.chunkAlloc.TakeStats()
+	chunkColumnAllocNew.Add(float64(stats.NewColumns))
+	chunkColumnAllocReuse.Add(float64(stats.ReusedColumns))
+	if cc.ctx != nil {
+		// The memory quota may be changed by the last command.
+		cc.chunkAlloc.SetQuota(cc.ctx.GetSessionVars().MemQuotaQuery / chunkAllocQuotaRatio)
+	}
+}
+
 func (cc *clientConn) openSessionAndDoAuth(authData []byte, authPlugin string) error {
 	// Open a context unless this was done before.
 	if cc.ctx == nil {
@@ -833,6 +897,10 @@ func (cc *clientConn) openSessionAndDoAuth(authData []byte, authPlugin string) e
 		return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
 	}
 	cc.ctx.SetPort(port)
+	if err = cc.server.checkUserConnectionCount(cc); err != nil {
+		return err
+	}
+	failpoint.Inject("afterCheckUserConnectionCount", nil)
 	if cc.dbname != "" {
 		err = cc.useDB(context.Background(), cc.dbname)
 		if err != nil {
@@ -862,6 +930,13 @@ func (cc *clientConn) checkAuthPlugin(ctx context.Context, resp *handshakeRespon
 	if err != nil {
 		return nil, err
 	}
+	// Map the client certificate to the user if no user name is given.
+	if len(cc.user) == 0 && cc.tlsConn != nil && config.GetGlobalConfig().Security.MapUserByCertSAN {
+		cc.user, err = cc.ctx.MatchCertUser(host)
+		if err != nil {
+			return nil, errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
+		}
+	}
 	// Find the identity of the user based on username and peer host.
 	identity, err := cc.ctx.MatchIdentity(cc.user, host)
 	if err != nil {
@@ -1089,7 +1164,7 @@ func (cc *clientConn) Run(ctx context.Context) {
 
 		startTime := time.Now()
 		err = cc.dispatch(ctx, data)
-		cc.chunkAlloc.Reset()
+		cc.resetChunkAlloc()
 		if err != nil {
 			cc.audit(plugin.Error) // tell the plugin API there was a dispatch error
 			if terror.ErrorEqual(err, io.EOF) {
@@ -1130,7 +1205,7 @@ func (cc *clientConn) Run(ctx context.Context) {
 			terror.Log(err1)
 		}
 		cc.addMetrics(data[0], startTime, err)
-		cc.pkt.sequence = 0
+		cc.pkt.resetSequence()
 	}
 }
 
@@ -1229,6 +1304,26 @@ func (cc *clientConn) addMetrics(cmd byte, startTime time.Time, err error) {
 	default:
 		metrics.QueryDurationHistogram.WithLabelValues(sqlType).Observe(cost.Seconds())
 	}
+
+	cfg := config.GetGlobalConfig()
+	if cfg.Status.RecordQueryByDB || cfg.Status.RecordQueryByResGroup {
+		// The labels are empty if they're not enabled to limit the cardinality.
+		var db, resGroup string
+		if cfg.Status.RecordQueryByDB {
+			db = sessionVar.CurrentDB
+		}
+		// The resource group may be set before the allowed values change, so check it again to
+		// keep the label values fixed.
+		if cfg.Status.RecordQueryByResGroup && variable.IsMetricsResourceGroup(sessionVar.MetricsResourceGroup) {
+			resGroup = sessionVar.MetricsResourceGroup
+		}
+		result := metrics.LblOK
+		if err != nil {
+			result = metrics.LblError
+		}
+		metrics.TenantQueryTotalCounter.WithLabelValues(sqlType, result, db, resGroup).Inc()
+		metrics.TenantQueryDurationHistogram.WithLabelValues(sqlType, db, resGroup).Observe(cost.Seconds())
+	}
 }
 
 // dispatch handles client request based on command which is the first byte of the data.
@@ -1258,7 +1353,12 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 	cc.mu.cancelFunc = cancelFunc
 	cc.mu.Unlock()
 
+	if alias := cc.ctx.GetSessionVars().SessionAlias; len(alias) > 0 {
+		ctx = logutil.WithKeyValue(ctx, "session_alias", alias)
+	}
+
 	cc.lastPacket = data
+	atomic.StoreInt64(&cc.lastPacketCap, int64(cap(data)))
 	cmd := data[0]
 	data = data[1:]
 	if topsqlstate.TopSQLEnabled() {
@@ -1293,6 +1393,7 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 		// if handleChangeUser failed, cc.ctx may be nil
 		if cc.ctx != nil {
 			cc.ctx.SetProcessInfo("", t, mysql.ComSleep, 0)
+			cc.ctx.GetSessionVars().QueryAttributes = nil
 		}
 
 		cc.server.releaseToken(token)
@@ -1330,6 +1431,16 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 		}
 		return cc.writeOK(ctx)
 	case mysql.ComQuery: // Most frequently used command.
+		if cc.capability&mysql.ClientQueryAttributes > 0 {
+			cc.initInputEncoder(ctx)
+			attrs, query, err := parseQueryAttributes(vars.StmtCtx, data, cc.inputDecoder)
+			if err != nil {
+				return err
+			}
+			vars.QueryAttributes = attrs
+			data = query
+			dataStr = string(hack.String(data))
+		}
 		// For issue 1989
 		// Input payload may end with byte '\0', we didn't find related mysql document about it, but mysql
 		// implementation accept that case. So trim the last '\0' here as if the payload an EOF string.
@@ -1338,6 +1449,8 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 			data = data[:len(data)-1]
 			dataStr = string(hack.String(data))
 		}
+		ctx, finishTrace := cc.startOTLPTrace(ctx, "server.Query")
+		defer finishTrace()
 		return cc.handleQuery(ctx, dataStr)
 	case mysql.ComFieldList:
 		return cc.handleFieldList(ctx, dataStr)
@@ -1380,6 +1493,22 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 	}
 }
 
+// startOTLPTrace starts collecting the spans of the statement to export them to
+// the OpenTelemetry collector, the statement span is linked to the client span
+// by the traceparent query attribute. The returned function must be called
+// after the statement finishes.
+func (cc *clientConn) startOTLPTrace(ctx context.Context, opName string) (context.Context, func()) {
+	stmt := otlptrace.StartStatement(opName, cc.ctx.GetSessionVars().QueryAttributes[otlptrace.TraceParentAttr])
+	if stmt == nil {
+		return ctx, func() {}
+	}
+	span := stmt.Span()
+	span.SetTag("db.system", "tidb")
+	span.SetTag("db.user", cc.user)
+	span.SetTag("tidb.conn_id", cc.connectionID)
+	return opentracing.ContextWithSpan(ctx, span), stmt.Finish
+}
+
 func (cc *clientConn) writeStats(ctx context.Context) error {
 	var err error
 	var uptime int64 = 0
@@ -2125,12 +2254,28 @@ func (cc *clientConn) writeResultset(ctx context.Context, rs ResultSet, binary b
 	return false, cc.flush(ctx)
 }
 
+// resultsetMetadataFollows returns whether the column definitions should be sent to the client,
+// and appends the metadata_follows flag to data if the client supports CLIENT_OPTIONAL_RESULTSET_METADATA.
+func (cc *clientConn) resultsetMetadataFollows(data []byte) ([]byte, bool) {
+	if cc.capability&mysql.ClientOptionalResultsetMetadata == 0 {
+		return data, true
+	}
+	if cc.ctx.GetSessionVars().ResultsetMetadataNone {
+		return append(data, mysql.ResultsetMetadataNone), false
+	}
+	return append(data, mysql.ResultsetMetadataFull), true
+}
+
 func (cc *clientConn) writeColumnInfo(columns []*ColumnInfo, serverStatus uint16) error {
 	data := cc.alloc.AllocWithLen(4, 1024)
 	data = dumpLengthEncodedInt(data, uint64(len(columns)))
+	data, metadataFollows := cc.resultsetMetadataFollows(data)
 	if err := cc.writePacket(data); err != nil {
 		return err
 	}
+	if !metadataFollows {
+		return cc.writeEOF(serverStatus)
+	}
 	for _, v := range columns {
 		data = data[0:4]
 		data = v.Dump(data, cc.rsEncoder)
@@ -2347,6 +2492,7 @@ func (cc *clientConn) handleResetConnection(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	cc.attachChunkAlloc()
 	if !cc.ctx.AuthWithoutVerification(user) {
 		return errors.New("Could not reset connection")
 	}
@@ -79,6 +79,7 @@ func (cc *clientConn) handleStmtPrepare(ctx context.Context, sql string) error {
 	data = append(data, 0)
 	// warning count
 	data = append(data, 0, 0) // TODO support warning count
+	data, metadataFollows := cc.resultsetMetadataFollows(data)
 
 	if err := cc.writePacket(data); err != nil {
 		return err
@@ -87,7 +88,7 @@ func (cc *clientConn) handleStmtPrepare(ctx context.Context, sql string) error {
 	cc.initResultEncoder(ctx)
 	defer cc.rsEncoder.clean()
 	if len(params) > 0 {
-		for i := 0; i < len(params); i++ {
+		for i := 0; metadataFollows && i < len(params); i++ {
 			data = data[0:4]
 			data = params[i].Dump(data, cc.rsEncoder)
 
@@ -102,7 +103,7 @@ func (cc *clientConn) handleStmtPrepare(ctx context.Context, sql string) error {
 	}
 
 	if len(columns) > 0 {
-		for i := 0; i < len(columns); i++ {
+		for i := 0; metadataFollows && i < len(columns); i++ {
 			data = data[0:4]
 			data = columns[i].Dump(data, cc.rsEncoder)
 
@@ -150,6 +151,13 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 	// 0x02 CURSOR_TYPE_FOR_UPDATE
 	// 0x04 CURSOR_TYPE_SCROLLABLE
 	// Now we only support forward-only, read-only cursor.
+	// The clients with CLIENT_QUERY_ATTRIBUTES capability set 0x08 PARAMETER_COUNT_AVAILABLE
+	// to send the parameter count even if the statement has no parameter.
+	withAttrs := cc.capability&mysql.ClientQueryAttributes > 0
+	paramCountAvailable := withAttrs && flag&parameterCountAvailable > 0
+	if withAttrs {
+		flag &^= parameterCountAvailable
+	}
 	var useCursor bool
 	switch flag {
 	case 0:
@@ -167,12 +175,26 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 		nullBitmaps []byte
 		paramTypes  []byte
 		paramValues []byte
+		paramNames  []string
 	)
 	cc.initInputEncoder(ctx)
 	numParams := stmt.NumParams()
-	args := make([]types.Datum, numParams)
-	if numParams > 0 {
-		nullBitmapLen := (numParams + 7) >> 3
+	// The parameters after the statement parameters are the query attributes.
+	paramCount := numParams
+	if withAttrs && (numParams > 0 || paramCountAvailable) {
+		count, n, err := parseLengthEncodedCount(data[pos:])
+		if err != nil {
+			return err
+		}
+		if count < numParams {
+			return mysql.ErrMalformPacket
+		}
+		paramCount = count
+		pos += n
+	}
+	args := make([]types.Datum, paramCount)
+	if paramCount > 0 {
+		nullBitmapLen := (paramCount + 7) >> 3
 		if len(data) < (pos + nullBitmapLen + 1) {
 			return mysql.ErrMalformPacket
 		}
@@ -182,26 +204,49 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 		// new param bound flag
 		if data[pos] == 1 {
 			pos++
-			if len(data) < (pos + (numParams << 1)) {
-				return mysql.ErrMalformPacket
+			if withAttrs {
+				var n int
+				paramTypes, paramNames, n, err = parseParamTypesWithNames(data[pos:], paramCount)
+				if err != nil {
+					return err
+				}
+				pos += n
+			} else {
+				if len(data) < (pos + (numParams << 1)) {
+					return mysql.ErrMalformPacket
+				}
+				paramTypes = data[pos : pos+(numParams<<1)]
+				pos += numParams << 1
 			}
-
-			paramTypes = data[pos : pos+(numParams<<1)]
-			pos += numParams << 1
 			paramValues = data[pos:]
 			// Just the first StmtExecute packet contain parameters type,
 			// we need save it for further use.
-			stmt.SetParamsType(paramTypes)
+			stmt.SetParamsType(paramTypes[:numParams<<1])
 		} else {
+			// The types of the query attributes are always sent.
+			if paramCount > numParams {
+				return mysql.ErrMalformPacket
+			}
+			paramTypes = stmt.GetParamsType()
 			paramValues = data[pos+1:]
 		}
 
-		err = parseExecArgs(cc.ctx.GetSessionVars().StmtCtx, args, stmt.BoundParams(), nullBitmaps, stmt.GetParamsType(), paramValues, cc.inputDecoder)
+		_, err = parseBinaryParams(cc.ctx.GetSessionVars().StmtCtx, args, stmt.BoundParams(), nullBitmaps, paramTypes, paramValues, cc.inputDecoder)
 		stmt.Reset()
 		if err != nil {
 			return errors.Annotate(err, cc.preparedStmt2String(stmtID))
 		}
+		if paramCount > numParams {
+			attrs, err := buildQueryAttributes(paramNames[numParams:], args[numParams:])
+			if err != nil {
+				return err
+			}
+			cc.ctx.GetSessionVars().QueryAttributes = attrs
+			args = args[:numParams]
+		}
 	}
+	ctx, finishTrace := cc.startOTLPTrace(ctx, "server.StmtExecute")
+	defer finishTrace()
 	ctx = context.WithValue(ctx, execdetails.StmtExecDetailKey, &execdetails.StmtExecDetails{})
 	ctx = context.WithValue(ctx, util.ExecDetailsKey, &util.ExecDetails{})
 	retryable, err := cc.executePreparedStmtAndWriteResult(ctx, stmt, args, useCursor)
@@ -224,6 +269,13 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 // The first return value indicates whether the call of executePreparedStmtAndWriteResult has no side effect and can be retried.
 // Currently the first return value is used to fallback to TiKV when TiFlash is down.
 func (cc *clientConn) executePreparedStmtAndWriteResult(ctx context.Context, stmt PreparedStatement, args []types.Datum, useCursor bool) (bool, error) {
+	if useCursor {
+		// The executors of a cursor outlive the command, so they must not allocate
+		// chunks from the connection chunk allocator which is reset after each command.
+		vars := cc.ctx.GetSessionVars()
+		vars.SetChunkAllocator(nil)
+		defer vars.SetChunkAllocator(cc.chunkAlloc)
+	}
 	rs, err := stmt.Execute(ctx, args)
 	if err != nil {
 		return true, errors.Annotate(err, cc.preparedStmt2String(uint32(stmt.ID())))
@@ -262,6 +314,10 @@ const (
 	maxFetchSize = 1024
 )
 
+// parameterCountAvailable is the flag of COM_STMT_EXECUTE which indicates the
+// parameter count is sent.
+const parameterCountAvailable = 0x08
+
 func (cc *clientConn) handleStmtFetch(ctx context.Context, data []byte) (err error) {
 	cc.ctx.GetSessionVars().StartTime = time.Now()
 
@@ -313,8 +369,15 @@ func parseStmtFetchCmd(data []byte) (uint32, uint32, error) {
 }
 
 func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams [][]byte,
-	nullBitmap, paramTypes, paramValues []byte, enc *inputDecoder) (err error) {
-	pos := 0
+	nullBitmap, paramTypes, paramValues []byte, enc *inputDecoder) error {
+	_, err := parseBinaryParams(sc, args, boundParams, nullBitmap, paramTypes, paramValues, enc)
+	return err
+}
+
+// parseBinaryParams parses the parameters in binary protocol into args, and
+// returns the number of bytes consumed from paramValues.
+func parseBinaryParams(sc *stmtctx.StatementContext, args []types.Datum, boundParams [][]byte,
+	nullBitmap, paramTypes, paramValues []byte, enc *inputDecoder) (pos int, err error) {
 	var (
 		tmp    interface{}
 		v      []byte
@@ -329,7 +392,7 @@ func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams
 		// if params had received via ComStmtSendLongData, use them directly.
 		// ref https://dev.mysql.com/doc/internals/en/com-stmt-send-long-data.html
 		// see clientConn#handleStmtSendLongData
-		if boundParams[i] != nil {
+		if i < len(boundParams) && boundParams[i] != nil {
 			args[i] = types.NewBytesDatum(enc.decodeInput(boundParams[i]))
 			continue
 		}
@@ -346,7 +409,7 @@ func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams
 		}
 
 		if (i<<1)+1 >= len(paramTypes) {
-			return mysql.ErrMalformPacket
+			return pos, mysql.ErrMalformPacket
 		}
 
 		tp := paramTypes[i<<1]
@@ -513,7 +576,7 @@ func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams
 				var dec types.MyDecimal
 				err = sc.HandleTruncate(dec.FromString(v))
 				if err != nil {
-					return err
+					return pos, err
 				}
 				args[i] = types.NewDecimalDatum(&dec)
 			}
@@ -564,6 +627,122 @@ func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams
 	return
 }
 
+// parseQueryAttributes parses the query attributes at the head of a COM_QUERY
+// packet sent by a client with the CLIENT_QUERY_ATTRIBUTES capability, and
+// returns the attributes and the rest of the packet which is the query.
+// See https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_com_query.html
+func parseQueryAttributes(sc *stmtctx.StatementContext, data []byte, enc *inputDecoder) (map[string]string, []byte, error) {
+	paramCount, n, err := parseLengthEncodedCount(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	pos := n
+	// parameter_set_count is always 1.
+	_, n, err = parseLengthEncodedCount(data[pos:])
+	if err != nil {
+		return nil, nil, err
+	}
+	pos += n
+	if paramCount == 0 {
+		return nil, data[pos:], nil
+	}
+
+	nullBitmapLen := (paramCount + 7) >> 3
+	if len(data) < pos+nullBitmapLen+1 {
+		return nil, nil, mysql.ErrMalformPacket
+	}
+	nullBitmap := data[pos : pos+nullBitmapLen]
+	pos += nullBitmapLen
+	// new_params_bind_flag is always 1, the types and names must be sent.
+	if data[pos] != 1 {
+		return nil, nil, mysql.ErrMalformPacket
+	}
+	pos++
+	paramTypes, names, n, err := parseParamTypesWithNames(data[pos:], paramCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	pos += n
+	args := make([]types.Datum, paramCount)
+	n, err = parseBinaryParams(sc, args, nil, nullBitmap, paramTypes, data[pos:], enc)
+	if err != nil {
+		return nil, nil, err
+	}
+	pos += n
+	attrs, err := buildQueryAttributes(names, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attrs, data[pos:], nil
+}
+
+// parseParamTypesWithNames parses the parameter types and names sent by a
+// client with the CLIENT_QUERY_ATTRIBUTES capability. The types are returned in
+// the layout expected by parseBinaryParams.
+func parseParamTypesWithNames(data []byte, paramCount int) (paramTypes []byte, names []string, pos int, err error) {
+	paramTypes = make([]byte, 0, paramCount<<1)
+	names = make([]string, 0, paramCount)
+	for i := 0; i < paramCount; i++ {
+		if len(data) < pos+2 {
+			return nil, nil, 0, mysql.ErrMalformPacket
+		}
+		paramTypes = append(paramTypes, data[pos], data[pos+1])
+		pos += 2
+		nameLen, n, err := parseLengthEncodedCount(data[pos:])
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		pos += n
+		if len(data) < pos+nameLen {
+			return nil, nil, 0, mysql.ErrMalformPacket
+		}
+		names = append(names, string(data[pos:pos+nameLen]))
+		pos += nameLen
+	}
+	return paramTypes, names, pos, nil
+}
+
+// buildQueryAttributes builds the query attributes from the names and values,
+// the attributes with NULL values are ignored.
+func buildQueryAttributes(names []string, values []types.Datum) (map[string]string, error) {
+	attrs := make(map[string]string, len(names))
+	for i, name := range names {
+		if values[i].IsNull() {
+			continue
+		}
+		value, err := values[i].ToString()
+		if err != nil {
+			return nil, err
+		}
+		attrs[name] = value
+	}
+	return attrs, nil
+}
+
+// parseLengthEncodedCount parses a length encoded count at the head of data.
+func parseLengthEncodedCount(data []byte) (int, int, error) {
+	if len(data) == 0 {
+		return 0, 0, mysql.ErrMalformPacket
+	}
+	size := 1
+	switch data[0] {
+	case 0xfc:
+		size = 3
+	case 0xfd:
+		size = 4
+	case 0xfe:
+		size = 9
+	}
+	if len(data) < size {
+		return 0, 0, mysql.ErrMalformPacket
+	}
+	num, isNull, n := parseLengthEncodedInt(data)
+	if isNull || num > math.MaxUint16 {
+		return 0, 0, mysql.ErrMalformPacket
+	}
+	return int(num), n, nil
+}
+
 func parseBinaryDate(pos int, paramValues []byte) (int, string) {
 	year := binary.LittleEndian.Uint16(paramValues[pos : pos+2])
 	pos += 2
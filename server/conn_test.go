@@ -19,18 +19,29 @@ import (
 	"bytes"
 	"context"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/store/mockstore/unistore"
@@ -38,6 +49,9 @@ import (
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/util/arena"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/otlptrace"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
 	tikverr "github.com/tikv/client-go/v2/error"
 	"github.com/tikv/client-go/v2/testutils"
@@ -494,7 +508,7 @@ func testDispatch(t *testing.T, inputs []dispatchInput, capability uint32) {
 		collation:  mysql.DefaultCollationID,
 		peerHost:   "localhost",
 		alloc:      arena.NewAllocator(512),
-		chunkAlloc: chunk.NewAllocator(),
+		chunkAlloc: chunk.NewSyncAllocator(0),
 		ctx:        tc,
 		capability: capability,
 	}
@@ -570,7 +584,7 @@ func TestConnExecutionTimeout(t *testing.T) {
 		},
 		ctx:        tc,
 		alloc:      arena.NewAllocator(32 * 1024),
-		chunkAlloc: chunk.NewAllocator(),
+		chunkAlloc: chunk.NewSyncAllocator(0),
 	}
 	srv := &Server{
 		clients: map[uint64]*clientConn{
@@ -673,7 +687,7 @@ func TestPrefetchPointKeys(t *testing.T) {
 
 	cc := &clientConn{
 		alloc:      arena.NewAllocator(1024),
-		chunkAlloc: chunk.NewAllocator(),
+		chunkAlloc: chunk.NewSyncAllocator(0),
 		pkt: &packetIO{
 			bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
 		},
@@ -744,7 +758,7 @@ func TestTiFlashFallback(t *testing.T) {
 
 	cc := &clientConn{
 		alloc:      arena.NewAllocator(1024),
-		chunkAlloc: chunk.NewAllocator(),
+		chunkAlloc: chunk.NewSyncAllocator(0),
 		pkt: &packetIO{
 			bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
 		},
@@ -863,7 +877,7 @@ func TestShowErrors(t *testing.T) {
 	defer clean()
 	cc := &clientConn{
 		alloc:      arena.NewAllocator(1024),
-		chunkAlloc: chunk.NewAllocator(),
+		chunkAlloc: chunk.NewSyncAllocator(0),
 		pkt: &packetIO{
 			bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
 		},
@@ -906,7 +920,7 @@ func TestHandleAuthPlugin(t *testing.T) {
 	cc := &clientConn{
 		connectionID: 1,
 		alloc:        arena.NewAllocator(1024),
-		chunkAlloc:   chunk.NewAllocator(),
+		chunkAlloc:   chunk.NewSyncAllocator(0),
 		collation:    mysql.DefaultCollationID,
 		peerHost:     "localhost",
 		pkt: &packetIO{
@@ -927,7 +941,7 @@ func TestHandleAuthPlugin(t *testing.T) {
 	cc = &clientConn{
 		connectionID: 1,
 		alloc:        arena.NewAllocator(1024),
-		chunkAlloc:   chunk.NewAllocator(),
+		chunkAlloc:   chunk.NewSyncAllocator(0),
 		collation:    mysql.DefaultCollationID,
 		peerHost:     "localhost",
 		pkt: &packetIO{
@@ -949,7 +963,7 @@ func TestHandleAuthPlugin(t *testing.T) {
 	cc = &clientConn{
 		connectionID: 1,
 		alloc:        arena.NewAllocator(1024),
-		chunkAlloc:   chunk.NewAllocator(),
+		chunkAlloc:   chunk.NewSyncAllocator(0),
 		collation:    mysql.DefaultCollationID,
 		peerHost:     "localhost",
 		pkt: &packetIO{
@@ -972,7 +986,7 @@ func TestHandleAuthPlugin(t *testing.T) {
 	cc = &clientConn{
 		connectionID: 1,
 		alloc:        arena.NewAllocator(1024),
-		chunkAlloc:   chunk.NewAllocator(),
+		chunkAlloc:   chunk.NewSyncAllocator(0),
 		collation:    mysql.DefaultCollationID,
 		peerHost:     "localhost",
 		pkt: &packetIO{
@@ -995,7 +1009,7 @@ func TestHandleAuthPlugin(t *testing.T) {
 	cc = &clientConn{
 		connectionID: 1,
 		alloc:        arena.NewAllocator(1024),
-		chunkAlloc:   chunk.NewAllocator(),
+		chunkAlloc:   chunk.NewSyncAllocator(0),
 		collation:    mysql.DefaultCollationID,
 		peerHost:     "localhost",
 		pkt: &packetIO{
@@ -1017,7 +1031,7 @@ func TestHandleAuthPlugin(t *testing.T) {
 	cc = &clientConn{
 		connectionID: 1,
 		alloc:        arena.NewAllocator(1024),
-		chunkAlloc:   chunk.NewAllocator(),
+		chunkAlloc:   chunk.NewSyncAllocator(0),
 		collation:    mysql.DefaultCollationID,
 		peerHost:     "localhost",
 		pkt: &packetIO{
@@ -1041,7 +1055,7 @@ func TestHandleAuthPlugin(t *testing.T) {
 	cc = &clientConn{
 		connectionID: 1,
 		alloc:        arena.NewAllocator(1024),
-		chunkAlloc:   chunk.NewAllocator(),
+		chunkAlloc:   chunk.NewSyncAllocator(0),
 		collation:    mysql.DefaultCollationID,
 		peerHost:     "localhost",
 		pkt: &packetIO{
@@ -1064,7 +1078,7 @@ func TestHandleAuthPlugin(t *testing.T) {
 	cc = &clientConn{
 		connectionID: 1,
 		alloc:        arena.NewAllocator(1024),
-		chunkAlloc:   chunk.NewAllocator(),
+		chunkAlloc:   chunk.NewSyncAllocator(0),
 		collation:    mysql.DefaultCollationID,
 		peerHost:     "localhost",
 		pkt: &packetIO{
@@ -1086,7 +1100,7 @@ func TestHandleAuthPlugin(t *testing.T) {
 	cc = &clientConn{
 		connectionID: 1,
 		alloc:        arena.NewAllocator(1024),
-		chunkAlloc:   chunk.NewAllocator(),
+		chunkAlloc:   chunk.NewSyncAllocator(0),
 		collation:    mysql.DefaultCollationID,
 		peerHost:     "localhost",
 		pkt: &packetIO{
@@ -1118,7 +1132,7 @@ func TestAuthPlugin2(t *testing.T) {
 	cc := &clientConn{
 		connectionID: 1,
 		alloc:        arena.NewAllocator(1024),
-		chunkAlloc:   chunk.NewAllocator(),
+		chunkAlloc:   chunk.NewSyncAllocator(0),
 		pkt: &packetIO{
 			bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
 		},
@@ -1145,3 +1159,299 @@ func TestAuthPlugin2(t *testing.T) {
 	require.NoError(t, err)
 
 }
+
+func TestReuseChunk(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	cc := &clientConn{
+		alloc:      arena.NewAllocator(1024),
+		chunkAlloc: chunk.NewSyncAllocator(0),
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
+		},
+	}
+	tk := testkit.NewTestKit(t, store)
+	cc.ctx = &TiDBContext{Session: tk.Session()}
+	cc.attachChunkAlloc()
+	ctx := context.Background()
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b double, c varchar(10), key(a))")
+	tk.MustExec("insert t values (1, 1, 'a'), (2, 2, 'b'), (3, 3, 'c')")
+
+	query := "select a, b from t where a > 1 order by b"
+	require.NoError(t, cc.handleQuery(ctx, query))
+	cc.resetChunkAlloc()
+	require.NoError(t, cc.handleQuery(ctx, query))
+	stats := cc.chunkAlloc.TakeStats()
+	require.Greater(t, stats.ReusedColumns, int64(0))
+	cc.resetChunkAlloc()
+
+	// Only the chunk of the result set is allocated from the connection allocator if reusing chunk is disabled.
+	tk.MustExec("set @@tidb_enable_reuse_chunk = off")
+	require.NoError(t, cc.handleQuery(ctx, query))
+	stats = cc.chunkAlloc.TakeStats()
+	require.Equal(t, int64(2), stats.NewColumns+stats.ReusedColumns)
+	cc.resetChunkAlloc()
+	tk.MustExec("set @@tidb_enable_reuse_chunk = on")
+	tk.MustQuery("select a, b from t where a > 1 order by b").Check(testkit.Rows("2 2", "3 3"))
+}
+
+func TestQueryAttributes(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	// The query attributes trace_id = 'abc' and span_id = NULL.
+	attrs := []byte{0x02, 0x01, 0x02, 0x01}
+	attrs = append(attrs, mysql.TypeString, 0x00)
+	attrs = dumpLengthEncodedString(attrs, []byte("trace_id"))
+	attrs = append(attrs, mysql.TypeLonglong, 0x00)
+	attrs = dumpLengthEncodedString(attrs, []byte("span_id"))
+	attrs = dumpLengthEncodedString(attrs, []byte("abc"))
+
+	query := "select mysql_query_attribute_string('trace_id'), mysql_query_attribute_string('span_id')"
+	parsed, rest, err := parseQueryAttributes(&stmtctx.StatementContext{}, append(attrs, query...), nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"trace_id": "abc"}, parsed)
+	require.Equal(t, query, string(rest))
+	_, _, err = parseQueryAttributes(&stmtctx.StatementContext{}, attrs[:len(attrs)-4], nil)
+	require.Equal(t, mysql.ErrMalformPacket, err)
+	// No query attributes.
+	parsed, rest, err = parseQueryAttributes(&stmtctx.StatementContext{}, append([]byte{0x00, 0x01}, "select 1"...), nil)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Equal(t, "select 1", string(rest))
+
+	var outBuffer bytes.Buffer
+	cfg := newTestConfig()
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	server, err := NewServer(cfg, NewTiDBDriver(store))
+	require.NoError(t, err)
+	defer server.Close()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("set global tidb_enable_stmt_summary = 1")
+	cc := &clientConn{
+		connectionID: 1,
+		server:       server,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(&outBuffer),
+		},
+		collation:  mysql.DefaultCollationID,
+		alloc:      arena.NewAllocator(1024),
+		chunkAlloc: chunk.NewSyncAllocator(0),
+		ctx:        &TiDBContext{Session: tk.Session(), stmts: make(map[int]*TiDBStatement)},
+		capability: mysql.ClientProtocol41 | mysql.ClientQueryAttributes,
+	}
+	ctx := context.Background()
+	require.NoError(t, cc.dispatch(ctx, append(append([]byte{mysql.ComQuery}, attrs...), query...)))
+	require.NoError(t, cc.flush(ctx))
+	require.Contains(t, outBuffer.String(), "\x03abc\xfb")
+	require.Nil(t, tk.Session().GetSessionVars().QueryAttributes)
+	tk.MustQuery("select query_attributes from information_schema.statements_summary where query_sample_text = ?", query).
+		Check(testkit.Rows(`{"trace_id":"abc"}`))
+	outBuffer.Reset()
+
+	// The query attributes are sent after the parameters of the statement.
+	require.NoError(t, cc.dispatch(ctx, append([]byte{mysql.ComStmtPrepare}, "select ?, mysql_query_attribute_string('trace_id')"...)))
+	require.NoError(t, cc.flush(ctx))
+	stmtID := outBuffer.Bytes()[5:9]
+	outBuffer.Reset()
+	execute := append([]byte{mysql.ComStmtExecute}, stmtID...)
+	execute = append(execute, parameterCountAvailable, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x01)
+	execute = append(execute, mysql.TypeLonglong, 0x00, 0x00, mysql.TypeString, 0x00)
+	execute = dumpLengthEncodedString(execute, []byte("trace_id"))
+	execute = dumpUint64(execute, 10)
+	execute = dumpLengthEncodedString(execute, []byte("xyz"))
+	require.NoError(t, cc.dispatch(ctx, execute))
+	require.NoError(t, cc.flush(ctx))
+	require.Contains(t, outBuffer.String(), string(dumpUint64(nil, 10))+"\x03xyz")
+}
+
+func TestOTLPTraceParent(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	var mu sync.Mutex
+	spans := make(map[string]map[string]interface{})
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []map[string]interface{} `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		defer mu.Unlock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, sp := range ss.Spans {
+					spans[sp["name"].(string)] = sp
+				}
+			}
+		}
+	}))
+	defer collector.Close()
+	otlptrace.Setup(otlptrace.Config{Endpoint: collector.URL})
+	defer otlptrace.Close()
+
+	// The query attribute traceparent with the sampled flag.
+	traceParent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	attrs := []byte{0x01, 0x01, 0x00, 0x01}
+	attrs = append(attrs, mysql.TypeString, 0x00)
+	attrs = dumpLengthEncodedString(attrs, []byte(otlptrace.TraceParentAttr))
+	attrs = dumpLengthEncodedString(attrs, []byte(traceParent))
+
+	var outBuffer bytes.Buffer
+	cfg := newTestConfig()
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	server, err := NewServer(cfg, NewTiDBDriver(store))
+	require.NoError(t, err)
+	defer server.Close()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	cc := &clientConn{
+		connectionID: 1,
+		server:       server,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(&outBuffer),
+		},
+		collation:  mysql.DefaultCollationID,
+		alloc:      arena.NewAllocator(1024),
+		chunkAlloc: chunk.NewSyncAllocator(0),
+		ctx:        &TiDBContext{Session: tk.Session(), stmts: make(map[int]*TiDBStatement)},
+		capability: mysql.ClientProtocol41 | mysql.ClientQueryAttributes,
+	}
+	ctx := context.Background()
+	require.NoError(t, cc.dispatch(ctx, append(append([]byte{mysql.ComQuery}, attrs...), "insert into t values (1)"...)))
+	otlptrace.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	root := spans["server.Query"]
+	require.NotNil(t, root)
+	require.Equal(t, "0af7651916cd43dd8448eb211c80319c", root["traceId"])
+	require.Equal(t, "b7ad6b7169203331", root["parentSpanId"])
+	for _, name := range []string{"session.ParseSQL", "executor.Compile", "session.CommitTxn"} {
+		require.Contains(t, spans, name)
+		require.Equal(t, root["traceId"], spans[name]["traceId"])
+	}
+}
+
+func TestTenantQueryMetrics(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Status.RecordQueryByDB = true
+		conf.Status.RecordQueryByResGroup = true
+		conf.Status.MetricsResourceGroups = []string{"tenant_metrics_rg"}
+	})
+
+	var outBuffer bytes.Buffer
+	cfg := newTestConfig()
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	server, err := NewServer(cfg, NewTiDBDriver(store))
+	require.NoError(t, err)
+	defer server.Close()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	// Only the configured values are allowed, so the label set is fixed.
+	tk.MustGetErrCode("set @@tidb_metrics_resource_group = 'unknown_rg'", errno.ErrWrongValueForVar)
+	tk.MustExec("set @@tidb_metrics_resource_group = 'tenant_metrics_rg'")
+	cc := &clientConn{
+		connectionID: 1,
+		server:       server,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(&outBuffer),
+		},
+		collation:  mysql.DefaultCollationID,
+		alloc:      arena.NewAllocator(1024),
+		chunkAlloc: chunk.NewSyncAllocator(0),
+		ctx:        &TiDBContext{Session: tk.Session(), stmts: make(map[int]*TiDBStatement)},
+		capability: mysql.ClientProtocol41,
+	}
+	ctx := context.Background()
+	require.NoError(t, cc.handleQuery(ctx, "select 1"))
+	cc.addMetrics(mysql.ComQuery, time.Now(), nil)
+	cc.addMetrics(mysql.ComQuery, time.Now(), errors.New("mock error"))
+
+	pb := &dto.Metric{}
+	require.NoError(t, metrics.TenantQueryTotalCounter.WithLabelValues("Select", metrics.LblOK, "test", "tenant_metrics_rg").Write(pb))
+	require.Equal(t, float64(1), pb.GetCounter().GetValue())
+	require.NoError(t, metrics.TenantQueryTotalCounter.WithLabelValues("Select", metrics.LblError, "test", "tenant_metrics_rg").Write(pb))
+	require.Equal(t, float64(1), pb.GetCounter().GetValue())
+	hist := metrics.TenantQueryDurationHistogram.WithLabelValues("Select", "test", "tenant_metrics_rg").(prometheus.Histogram)
+	require.NoError(t, hist.Write(pb))
+	require.Equal(t, uint64(2), pb.GetHistogram().GetSampleCount())
+}
+
+func TestOptionalResultsetMetadata(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	var outBuffer bytes.Buffer
+	cfg := newTestConfig()
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	server, err := NewServer(cfg, NewTiDBDriver(store))
+	require.NoError(t, err)
+	defer server.Close()
+	tk := testkit.NewTestKit(t, store)
+	cc := &clientConn{
+		connectionID: 1,
+		server:       server,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(&outBuffer),
+		},
+		collation:  mysql.DefaultCollationID,
+		alloc:      arena.NewAllocator(1024),
+		chunkAlloc: chunk.NewSyncAllocator(0),
+		ctx:        &TiDBContext{Session: tk.Session(), stmts: make(map[int]*TiDBStatement)},
+		capability: mysql.ClientProtocol41 | mysql.ClientOptionalResultsetMetadata,
+	}
+	ctx := context.Background()
+
+	// The packets of the column count, the column definition, EOF, the row and EOF.
+	require.NoError(t, cc.dispatch(ctx, append([]byte{mysql.ComQuery}, "select 1"...)))
+	out := outBuffer.Bytes()
+	require.Equal(t, []byte{0x02, 0x00, 0x00}, out[:3])
+	require.Equal(t, []byte{0x01, mysql.ResultsetMetadataFull}, out[4:6])
+	fullLen := len(out)
+
+	// The column definition is skipped.
+	tk.MustExec("set @@resultset_metadata = 'NONE'")
+	outBuffer.Reset()
+	require.NoError(t, cc.dispatch(ctx, append([]byte{mysql.ComQuery}, "select 1"...)))
+	out = outBuffer.Bytes()
+	require.Equal(t, []byte{0x02, 0x00, 0x00}, out[:3])
+	require.Equal(t, []byte{0x01, mysql.ResultsetMetadataNone}, out[4:6])
+	require.Equal(t, mysql.EOFHeader, out[10])
+	require.Less(t, len(out), fullLen)
+
+	// The prepare OK packet is followed by the EOF of the params and the EOF of the columns only.
+	outBuffer.Reset()
+	require.NoError(t, cc.dispatch(ctx, append([]byte{mysql.ComStmtPrepare}, "select ?"...)))
+	out = outBuffer.Bytes()
+	require.Equal(t, []byte{0x0d, 0x00, 0x00}, out[:3])
+	require.Equal(t, mysql.ResultsetMetadataNone, out[16])
+	require.Equal(t, 4+13+2*(4+5), len(out))
+
+	// The clients without CLIENT_OPTIONAL_RESULTSET_METADATA always get the column definitions.
+	cc.capability = mysql.ClientProtocol41
+	outBuffer.Reset()
+	require.NoError(t, cc.dispatch(ctx, append([]byte{mysql.ComQuery}, "select 1"...)))
+	out = outBuffer.Bytes()
+	require.Equal(t, []byte{0x01, 0x00, 0x00}, out[:3])
+	require.Equal(t, byte(0x01), out[4])
+	require.Equal(t, fullLen-1, len(out))
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/util/admin"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// DDL job operations.
+const (
+	opDDLJobList        = "list"
+	opDDLJobCancel      = "cancel"
+	opDDLJobPause       = "pause"
+	opDDLJobResume      = "resume"
+	opDDLJobPriority    = "priority"
+	opDDLJobOwnerResign = "resign"
+)
+
+// ddlJobHandler is the handler for managing the DDL job queue. The requests
+// must be sent over TLS and authenticated by HTTP basic auth with a user which
+// has the SUPER privilege.
+type ddlJobHandler struct {
+	*tikvHandlerTool
+	op string
+}
+
+// ddlJobResult is the result of an operation on a DDL job.
+type ddlJobResult struct {
+	JobID int64  `json:"job_id"`
+	Error string `json:"error,omitempty"`
+}
+
+// ServeHTTP handles the request of managing the DDL job queue.
+func (h ddlJobHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// The password in the basic auth header must not be sent in cleartext.
+	if tlsStateFromRequest(req) == nil {
+		err := errors.New("the DDL job API requires TLS")
+		logutil.BgLogger().Warn("failed to authenticate the DDL job request", zap.Error(err))
+		writeForbidden(w, err)
+		return
+	}
+	if err := h.authenticate(req); err != nil {
+		logutil.BgLogger().Warn("failed to authenticate the DDL job request", zap.Error(err))
+		writeUnauthorized(w, err)
+		return
+	}
+	if h.op == opDDLJobList {
+		jobs, err := h.listJobs()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeData(w, jobs)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		writeError(w, errors.Errorf("This api only support POST method."))
+		return
+	}
+	if h.op == opDDLJobOwnerResign {
+		if err := (ddlResignOwnerHandler{h.Store}).resignDDLOwner(); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeData(w, "success!")
+		return
+	}
+
+	jobID, err := strconv.ParseInt(mux.Vars(req)[pJobID], 10, 64)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var update func(txn kv.Transaction, ids []int64) ([]error, error)
+	switch h.op {
+	case opDDLJobCancel:
+		update = admin.CancelJobs
+	case opDDLJobPause:
+		update = admin.PauseJobs
+	case opDDLJobResume:
+		update = admin.ResumeJobs
+	case opDDLJobPriority:
+		priority, err := parseDDLJobPriority(req.FormValue(qPriority))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		update = func(txn kv.Transaction, ids []int64) ([]error, error) {
+			return admin.UpdateJobsPriority(txn, ids, priority)
+		}
+	default:
+		writeError(w, errors.Errorf("unknown DDL job operation %s", h.op))
+		return
+	}
+
+	var errs []error
+	err = kv.RunInNewTxn(context.Background(), h.Store, true, func(ctx context.Context, txn kv.Transaction) error {
+		var err error
+		errs, err = update(txn, []int64{jobID})
+		return err
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	result := ddlJobResult{JobID: jobID}
+	if len(errs) > 0 && errs[0] != nil {
+		result.Error = errs[0].Error()
+	}
+	logutil.BgLogger().Info("DDL job is updated by the HTTP API", zap.String("op", h.op), zap.Int64("jobID", jobID), zap.String("error", result.Error))
+	writeData(w, result)
+}
+
+func (h ddlJobHandler) listJobs() ([]*model.Job, error) {
+	txn, err := h.Store.Begin()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		terror.Log(txn.Rollback())
+	}()
+	return admin.GetDDLJobs(txn)
+}
+
+// authenticate checks the user and password in the basic auth header, the
+// user must have the SUPER privilege.
+func (h ddlJobHandler) authenticate(req *http.Request) error {
+	do, err := session.GetDomain(h.Store)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return authenticateHTTPRequest(do, req, func(pm privilege.Manager, roles []*auth.RoleIdentity, user, host string) error {
+		if !pm.RequestVerification(roles, "", "", "", mysql.SuperPriv) {
+			return errors.Errorf("the SUPER privilege is required for user '%s'@'%s'", user, host)
+		}
+		return nil
+	})
+}
+
+func parseDDLJobPriority(s string) (int, error) {
+	switch strings.ToLower(s) {
+	case "low":
+		return kv.PriorityLow, nil
+	case "normal":
+		return kv.PriorityNormal, nil
+	case "high":
+		return kv.PriorityHigh, nil
+	}
+	return 0, errors.Errorf("invalid priority %q, should be one of low, normal and high", s)
+}
@@ -0,0 +1,191 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/fastrand"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/soheilhy/cmux"
+	"go.uber.org/zap"
+)
+
+// statusAPIClientPriv is the dynamic privilege required to access the HTTP
+// status API when status.require-auth is enabled.
+const statusAPIClientPriv = "STATUS_API_CLIENT"
+
+// statusAuthHandler authenticates the requests of the HTTP status API. The
+// requests must be sent over TLS, and the client either presents a certificate
+// signed by the cluster CA, which is how the other TiDB instances and the
+// cluster components call the API, or uses HTTP basic auth with a user which
+// has the STATUS_API_CLIENT privilege. The health check `/status` is always
+// allowed so that the load balancers keep working.
+type statusAuthHandler struct {
+	handler http.Handler
+	do      *domain.Domain
+}
+
+func (s *Server) newStatusAuthHandler(handler http.Handler) statusAuthHandler {
+	store, ok := s.driver.(*TiDBDriver)
+	if !ok {
+		panic("Illegal driver")
+	}
+
+	do, err := session.GetDomain(store.store)
+	if err != nil {
+		panic("Failed to get domain")
+	}
+	return statusAuthHandler{handler: handler, do: do}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h statusAuthHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/status" {
+		h.handler.ServeHTTP(w, req)
+		return
+	}
+	tlsState := tlsStateFromRequest(req)
+	if tlsState == nil {
+		err := errors.New("the status API requires TLS")
+		logutil.BgLogger().Warn("failed to authenticate the status API request", zap.String("path", req.URL.Path), zap.Error(err))
+		writeForbidden(w, err)
+		return
+	}
+	// The certificate has been verified against the cluster CA, and the
+	// cluster-verify-cn if it is set, during the TLS handshake.
+	if len(tlsState.VerifiedChains) > 0 {
+		h.handler.ServeHTTP(w, req)
+		return
+	}
+	err := authenticateHTTPRequest(h.do, req, func(pm privilege.Manager, roles []*auth.RoleIdentity, user, host string) error {
+		if !pm.RequestDynamicVerification(roles, statusAPIClientPriv, false) {
+			return errors.Errorf("the %s privilege is required for user '%s'@'%s'", statusAPIClientPriv, user, host)
+		}
+		return nil
+	})
+	if err != nil {
+		logutil.BgLogger().Warn("failed to authenticate the status API request", zap.String("path", req.URL.Path), zap.Error(err))
+		writeUnauthorized(w, err)
+		return
+	}
+	h.handler.ServeHTTP(w, req)
+}
+
+type tlsStateKeyType struct{}
+
+var tlsStateKey tlsStateKeyType
+
+// statusConnContext saves the TLS state of the connection in the context of
+// its requests. The status server is served through cmux, so the connection
+// is not a *tls.Conn and http.Request.TLS is never set.
+func statusConnContext(ctx context.Context, c net.Conn) context.Context {
+	if mc, ok := c.(*cmux.MuxConn); ok {
+		c = mc.Conn
+	}
+	if tc, ok := c.(*tls.Conn); ok {
+		state := tc.ConnectionState()
+		return context.WithValue(ctx, tlsStateKey, &state)
+	}
+	return ctx
+}
+
+// tlsStateFromRequest returns the TLS state of the connection of the request,
+// or nil if the request is not sent over TLS.
+func tlsStateFromRequest(req *http.Request) *tls.ConnectionState {
+	if req.TLS != nil {
+		return req.TLS
+	}
+	state, _ := req.Context().Value(tlsStateKey).(*tls.ConnectionState)
+	return state
+}
+
+// authenticateHTTPRequest checks the user and password in the basic auth
+// header against the privilege cache, then checks the privileges of the user
+// with its default roles by checkPriv.
+func authenticateHTTPRequest(do *domain.Domain, req *http.Request, checkPriv func(pm privilege.Manager, roles []*auth.RoleIdentity, user, host string) error) error {
+	user, password, ok := req.BasicAuth()
+	if !ok {
+		return errors.New("the basic auth is required")
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	pm := &privileges.UserPrivileges{Handle: do.PrivilegeHandle()}
+	skipNameResolve := false
+	if varVal, err := do.GetGlobalVar(variable.SkipNameResolve); err == nil && variable.TiDBOptOn(varVal) {
+		skipNameResolve = true
+	}
+	authUser, authHost, ok := pm.MatchIdentity(user, host, skipNameResolve)
+	if !ok {
+		return errors.Errorf("access denied for user '%s'@'%s'", user, host)
+	}
+	salt := fastrand.Buf(20)
+	authentication := []byte(password)
+	if plugin, err := pm.GetAuthPlugin(authUser, authHost); err == nil && plugin == mysql.AuthNativePassword && len(password) > 0 {
+		authentication = scramblePassword(salt, password)
+	}
+	if !pm.ConnectionVerification(authUser, authHost, authentication, salt, tlsStateFromRequest(req)) {
+		return errors.Errorf("access denied for user '%s'@'%s'", user, host)
+	}
+	return checkPriv(pm, pm.GetDefaultRoles(authUser, authHost), user, host)
+}
+
+// writeUnauthorized writes the 401 response which asks the client to use the
+// basic auth.
+func writeUnauthorized(w http.ResponseWriter, err error) {
+	w.Header().Set("WWW-Authenticate", `Basic realm="tidb"`)
+	w.WriteHeader(http.StatusUnauthorized)
+	_, err = w.Write([]byte(err.Error()))
+	terror.Log(errors.Trace(err))
+}
+
+func writeForbidden(w http.ResponseWriter, err error) {
+	w.WriteHeader(http.StatusForbidden)
+	_, err = w.Write([]byte(err.Error()))
+	terror.Log(errors.Trace(err))
+}
+
+// scramblePassword computes the mysql_native_password reply of the password,
+// see auth.CheckScrambledPassword.
+func scramblePassword(salt []byte, password string) []byte {
+	stage1 := sha1.Sum([]byte(password))
+	stage2 := sha1.Sum(stage1[:])
+	crypt := sha1.New()
+	_, err := crypt.Write(salt)
+	terror.Log(errors.Trace(err))
+	_, err = crypt.Write(stage2[:])
+	terror.Log(errors.Trace(err))
+	scramble := crypt.Sum(nil)
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+	return scramble
+}
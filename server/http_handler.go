@@ -87,6 +87,7 @@ const (
 	pRowBin     = "rowBin"
 	pSnapshot   = "snapshot"
 	pFileName   = "filename"
+	pJobID      = "jobID"
 )
 
 // For query string
@@ -95,6 +96,7 @@ const (
 	qLimit     = "limit"
 	qOperation = "op"
 	qSeconds   = "seconds"
+	qPriority  = "priority"
 )
 
 const (
@@ -28,6 +28,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
+	"path/filepath"
 	"sort"
 	"testing"
 	"time"
@@ -438,7 +439,7 @@ func TestBinlogRecover(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func (ts *basicHTTPHandlerTestSuite) startServer(t *testing.T) {
+func (ts *basicHTTPHandlerTestSuite) startServer(t *testing.T, opts ...func(cfg *config.Config)) {
 	var err error
 	ts.store, err = mockstore.NewMockStore()
 	require.NoError(t, err)
@@ -451,6 +452,9 @@ func (ts *basicHTTPHandlerTestSuite) startServer(t *testing.T) {
 	cfg.Port = 0
 	cfg.Status.StatusPort = 0
 	cfg.Status.ReportStatus = true
+	for _, opt := range opts {
+		opt(cfg)
+	}
 
 	server, err := NewServer(cfg, ts.tidbdrv)
 	require.NoError(t, err)
@@ -461,7 +465,12 @@ func (ts *basicHTTPHandlerTestSuite) startServer(t *testing.T) {
 		err := server.Run()
 		require.NoError(t, err)
 	}()
-	ts.waitUntilServerOnline()
+	if ts.statusScheme == "https" {
+		// The status port can't be fetched without the certificate of the cluster.
+		ts.waitUntilServerCanConnect()
+	} else {
+		ts.waitUntilServerOnline()
+	}
 
 	do, err := session.GetDomain(ts.store)
 	require.NoError(t, err)
@@ -1104,3 +1113,84 @@ func TestWriteDBTablesData(t *testing.T) {
 	require.Equal(t, ti[0].Name.String(), tbs[0].Meta().Name.String())
 	require.Equal(t, ti[1].Name.String(), tbs[1].Meta().Name.String())
 }
+
+func TestDDLJobHandler(t *testing.T) {
+	// The request which isn't sent over TLS is rejected before the basic auth.
+	req := httptest.NewRequest(http.MethodGet, "/ddl/jobs", nil)
+	req.SetBasicAuth("root", "")
+	rec := httptest.NewRecorder()
+	ddlJobHandler{op: opDDLJobList}.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.Contains(t, rec.Body.String(), "requires TLS")
+
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca-cert-ddl.pem")
+	serverKeyPath := filepath.Join(dir, "server-key-ddl.pem")
+	serverCertPath := filepath.Join(dir, "server-cert-ddl.pem")
+	caCert, caKey, err := generateCert(0, "TiDB CA DDL", nil, nil, filepath.Join(dir, "ca-key-ddl.pem"), caPath)
+	require.NoError(t, err)
+	_, _, err = generateCert(1, "tidb-server-ddl", caCert, caKey, serverKeyPath, serverCertPath)
+	require.NoError(t, err)
+
+	ts := createBasicHTTPHandlerTestSuite()
+	ts.statusScheme = "https"
+	ts.startServer(t, func(cfg *config.Config) {
+		cfg.Security.ClusterSSLCA = caPath
+		cfg.Security.ClusterSSLCert = serverCertPath
+		cfg.Security.ClusterSSLKey = serverKeyPath
+	})
+	defer ts.stopServer(t)
+	hc := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+
+	tk := testkit.NewTestKit(t, ts.store)
+	tk.MustExec("create user 'ddl_admin'@'%' identified by '123'")
+	tk.MustExec("grant super on *.* to 'ddl_admin'@'%'")
+	tk.MustExec("create user 'ddl_nobody'@'%' identified with 'caching_sha2_password' by '456'")
+
+	doRequest := func(method, path, user, password string) (int, []byte) {
+		req, err := http.NewRequest(method, ts.statusURL(path), nil)
+		require.NoError(t, err)
+		if user != "" {
+			req.SetBasicAuth(user, password)
+		}
+		resp, err := hc.Do(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode, body
+	}
+
+	code, _ := doRequest(http.MethodGet, "/ddl/jobs", "", "")
+	require.Equal(t, http.StatusUnauthorized, code)
+	code, _ = doRequest(http.MethodGet, "/ddl/jobs", "ddl_admin", "wrong")
+	require.Equal(t, http.StatusUnauthorized, code)
+	code, body := doRequest(http.MethodGet, "/ddl/jobs", "ddl_nobody", "456")
+	require.Equal(t, http.StatusUnauthorized, code)
+	require.Contains(t, string(body), "SUPER privilege")
+
+	code, body = doRequest(http.MethodGet, "/ddl/jobs", "ddl_admin", "123")
+	require.Equal(t, http.StatusOK, code, string(body))
+	var jobs []*model.Job
+	require.NoError(t, json.Unmarshal(body, &jobs))
+	require.Len(t, jobs, 0)
+	code, _ = doRequest(http.MethodGet, "/ddl/jobs", "root", "")
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = doRequest(http.MethodGet, "/ddl/jobs/1/pause", "root", "")
+	require.Equal(t, http.StatusBadRequest, code)
+	for _, op := range []string{"cancel", "pause", "resume", "priority?priority=high"} {
+		code, body = doRequest(http.MethodPost, "/ddl/jobs/100000/"+op, "root", "")
+		require.Equal(t, http.StatusOK, code)
+		var result ddlJobResult
+		require.NoError(t, json.Unmarshal(body, &result))
+		require.Equal(t, int64(100000), result.JobID)
+		require.Regexp(t, "DDL Job:100000 not found$", result.Error)
+	}
+	code, body = doRequest(http.MethodPost, "/ddl/jobs/1/priority?priority=urgent", "root", "")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, string(body), "invalid priority")
+
+	code, _ = doRequest(http.MethodPost, "/ddl/jobs/owner/resign", "root", "")
+	require.Equal(t, http.StatusOK, code)
+}
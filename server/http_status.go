@@ -92,6 +92,12 @@ func (s *Server) listenStatusHTTPServer() error {
 		return errors.Trace(err)
 	}
 	tlsConfig = s.setCNChecker(tlsConfig)
+	if tlsConfig != nil && s.cfg.Status.RequireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	} else if tlsConfig != nil && s.cfg.Status.RequireAuth && tlsConfig.ClientAuth == tls.NoClientCert {
+		// Accept the cluster certificates instead of the basic auth, see statusAuthHandler.
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
 
 	if tlsConfig != nil {
 		// we need to manage TLS here for cmux to distinguish between HTTP and gRPC.
@@ -220,6 +226,12 @@ func (s *Server) startHTTPServer() {
 
 	router.Handle("/ddl/history", ddlHistoryJobHandler{tikvHandlerTool}).Name("DDL_History")
 	router.Handle("/ddl/owner/resign", ddlResignOwnerHandler{tikvHandlerTool.Store.(kv.Storage)}).Name("DDL_Owner_Resign")
+	router.Handle("/ddl/jobs", ddlJobHandler{tikvHandlerTool, opDDLJobList}).Name("DDL_Jobs")
+	router.Handle("/ddl/jobs/owner/resign", ddlJobHandler{tikvHandlerTool, opDDLJobOwnerResign})
+	router.Handle("/ddl/jobs/{jobID:[0-9]+}/cancel", ddlJobHandler{tikvHandlerTool, opDDLJobCancel})
+	router.Handle("/ddl/jobs/{jobID:[0-9]+}/pause", ddlJobHandler{tikvHandlerTool, opDDLJobPause})
+	router.Handle("/ddl/jobs/{jobID:[0-9]+}/resume", ddlJobHandler{tikvHandlerTool, opDDLJobResume})
+	router.Handle("/ddl/jobs/{jobID:[0-9]+}/priority", ddlJobHandler{tikvHandlerTool, opDDLJobPriority})
 
 	// HTTP path for get the TiDB config
 	router.Handle("/config", fn.Wrap(func() (*config.Config, error) {
@@ -453,7 +465,11 @@ func (s *Server) startStatusServerAndRPCServer(serverMux *http.ServeMux) {
 	httpL := m.Match(cmux.HTTP1Fast())
 	grpcL := m.Match(cmux.Any())
 
-	statusServer := &http.Server{Addr: s.statusAddr, Handler: CorsHandler{handler: serverMux, cfg: s.cfg}}
+	var handler http.Handler = CorsHandler{handler: serverMux, cfg: s.cfg}
+	if s.cfg.Status.RequireAuth {
+		handler = s.newStatusAuthHandler(handler)
+	}
+	statusServer := &http.Server{Addr: s.statusAddr, Handler: handler, ConnContext: statusConnContext}
 	grpcServer := NewRPCServer(s.cfg, s.dom, s)
 	service.RegisterChannelzServiceToServer(grpcServer)
 
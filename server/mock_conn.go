@@ -99,7 +99,7 @@ func CreateMockConn(t *testing.T, store kv.Storage, server *Server) MockConn {
 		salt:       []byte{},
 		collation:  mysql.DefaultCollationID,
 		alloc:      arena.NewAllocator(1024),
-		chunkAlloc: chunk.NewAllocator(),
+		chunkAlloc: chunk.NewSyncAllocator(0),
 		pkt: &packetIO{
 			bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
 		},
@@ -40,6 +40,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -59,6 +60,15 @@ type packetIO struct {
 	bufWriter   *bufio.Writer
 	sequence    uint8
 	readTimeout time.Duration
+
+	// The fields below are used by the compressed protocol.
+	compressionAlgorithm int
+	zstdLevel            int
+	compressedSequence   uint8
+	compressedReader     *compressedReader
+	compressedWriter     *compressedWriter
+	zstdEncoder          *zstd.Encoder
+	zstdDecoder          *zstd.Decoder
 }
 
 func newPacketIO(bufReadConn *bufferedReadConn) *packetIO {
@@ -72,6 +82,20 @@ func (p *packetIO) setBufferedReadConn(bufReadConn *bufferedReadConn) {
 	p.bufWriter = bufio.NewWriterSize(bufReadConn, defaultWriterSize)
 }
 
+// resetSequence resets the sequences at the beginning of a command.
+func (p *packetIO) resetSequence() {
+	p.sequence = 0
+	p.compressedSequence = 0
+}
+
+// reader returns the reader of the normal packets.
+func (p *packetIO) reader() io.Reader {
+	if p.compressedReader != nil {
+		return p.compressedReader
+	}
+	return p.bufReadConn
+}
+
 func (p *packetIO) setReadTimeout(timeout time.Duration) {
 	p.readTimeout = timeout
 }
@@ -83,7 +107,7 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+	if _, err := io.ReadFull(p.reader(), header[:]); err != nil {
 		return nil, errors.Trace(err)
 	}
 
@@ -102,7 +126,7 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+	if _, err := io.ReadFull(p.reader(), data); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return data, nil
@@ -186,5 +210,8 @@ func (p *packetIO) flush() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	return err
+	if p.compressedWriter != nil {
+		err = p.compressedWriter.flush()
+	}
+	return errors.Trace(err)
 }
@@ -18,9 +18,11 @@ import (
 	"bufio"
 	"bytes"
 	"net"
+	"runtime"
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/stretchr/testify/require"
 )
@@ -86,6 +88,71 @@ func TestPacketIORead(t *testing.T) {
 	require.Equal(t, byte(0x0a), bytes[mysql.MaxPayloadLen])
 }
 
+func TestCompressedPacketIO(t *testing.T) {
+	small := []byte{0x03, 0x01, 0x02}
+	large := bytes.Repeat([]byte("compressed protocol "), mysql.MaxPayloadLen/10)
+	for _, algorithm := range []int{compressionZlib, compressionZstd} {
+		conn := &bytesConn{}
+		pkt := newPacketIO(newBufferedReadConn(conn))
+		pkt.setCompression(algorithm, 0)
+		require.NoError(t, pkt.writePacket(append(make([]byte, 4), small...)))
+		require.NoError(t, pkt.writePacket(append(make([]byte, 4), large...)))
+		require.NoError(t, pkt.flush())
+		pkt.closeCompression()
+		// The data is compressed and split into more than one compressed packet.
+		require.Less(t, conn.b.Len(), len(large))
+
+		pkt = newPacketIO(newBufferedReadConn(&bytesConn{conn.b}))
+		pkt.setCompression(algorithm, 0)
+		data, err := pkt.readPacket()
+		require.NoError(t, err)
+		require.Equal(t, small, data)
+		data, err = pkt.readPacket()
+		require.NoError(t, err)
+		require.Equal(t, large, data)
+		require.Equal(t, uint8(3), pkt.compressedSequence)
+		pkt.closeCompression()
+	}
+}
+
+func TestCompressedPacketIOMalformed(t *testing.T) {
+	readCompressed := func(payload []byte, uncompressedLength int) error {
+		header := []byte{
+			byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16),
+			0,
+			byte(uncompressedLength), byte(uncompressedLength >> 8), byte(uncompressedLength >> 16),
+		}
+		conn := &bytesConn{}
+		conn.b.Write(append(header, payload...))
+		pkt := newPacketIO(newBufferedReadConn(conn))
+		pkt.setCompression(compressionZstd, 0)
+		defer pkt.closeCompression()
+		_, err := pkt.readPacket()
+		return err
+	}
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	defer encoder.Close()
+
+	// A frame which decodes to more than the max payload length, and the
+	// frames which decode to 256MB in total.
+	huge := encoder.EncodeAll(make([]byte, 2*mysql.MaxPayloadLen), nil)
+	frames := bytes.Repeat(encoder.EncodeAll(make([]byte, 1<<20), nil), 256)
+	for _, payload := range [][]byte{huge, frames} {
+		require.Less(t, len(payload), mysql.MaxPayloadLen)
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		require.Error(t, readCompressed(payload, 100))
+		runtime.ReadMemStats(&after)
+		// The decoded data is bounded by the uncompressed length in the header.
+		require.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(4*mysql.MaxPayloadLen))
+	}
+
+	// The frame decodes to less than the uncompressed length in the header.
+	require.Error(t, readCompressed(encoder.EncodeAll(make([]byte, 100), nil), 200))
+	require.NoError(t, readCompressed(encoder.EncodeAll(make([]byte, 100), nil), 100))
+}
+
 type bytesConn struct {
 	b bytes.Buffer
 }
@@ -95,7 +162,7 @@ func (c *bytesConn) Read(b []byte) (n int, err error) {
 }
 
 func (c *bytesConn) Write(b []byte) (n int, err error) {
-	return 0, nil
+	return c.b.Write(b)
 }
 
 func (c *bytesConn) Close() error {
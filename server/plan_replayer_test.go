@@ -32,6 +32,8 @@ import (
 )
 
 func TestDumpPlanReplayerAPI(t *testing.T) {
+	// Loading the plan replayer sets tidb_log_file_max_days, which reinitializes the loggers.
+	useTempSlowQueryFile(t)
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
 
@@ -42,6 +42,7 @@ import (
 	_ "net/http/pprof" // #nosec G108
 	"os"
 	"os/user"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -57,6 +58,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/plugin"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
@@ -99,6 +101,8 @@ var (
 	errAccessDenied            = dbterror.ClassServer.NewStd(errno.ErrAccessDenied)
 	errAccessDeniedNoPassword  = dbterror.ClassServer.NewStd(errno.ErrAccessDeniedNoPassword)
 	errConCount                = dbterror.ClassServer.NewStd(errno.ErrConCount)
+	errUserConCount            = dbterror.ClassServer.NewStd(errno.ErrTooManyUserConnections)
+	errResourceGroupConCount   = dbterror.ClassServer.NewStd(errno.ErrTooManyResourceGroupConnections)
 	errSecureTransportRequired = dbterror.ClassServer.NewStd(errno.ErrSecureTransportRequired)
 	errMultiStatementDisabled  = dbterror.ClassServer.NewStd(errno.ErrMultiStatementDisabled)
 	errNewAbortingConnection   = dbterror.ClassServer.NewStd(errno.ErrNewAbortingConnection)
@@ -111,7 +115,9 @@ const defaultCapability = mysql.ClientLongPassword | mysql.ClientLongFlag |
 	mysql.ClientConnectWithDB | mysql.ClientProtocol41 |
 	mysql.ClientTransactions | mysql.ClientSecureConnection | mysql.ClientFoundRows |
 	mysql.ClientMultiStatements | mysql.ClientMultiResults | mysql.ClientLocalFiles |
-	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive
+	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive |
+	mysql.ClientQueryAttributes | mysql.ClientCompress | mysql.ClientZstdCompressionAlgorithm |
+	mysql.ClientOptionalResultsetMetadata
 
 // Server is the MySQL protocol server
 type Server struct {
@@ -132,6 +138,13 @@ type Server struct {
 	statusServer   *http.Server
 	grpcServer     *grpc.Server
 	inShutdownMode bool
+
+	// rejectedUserConns is the number of the connections rejected by the limits of the users and resource groups.
+	rejectedUserConns uint64
+	// userConns and groupConns are the numbers of the connections of the users and resource groups, they're
+	// protected by rwlock.
+	userConns  map[string]uint64
+	groupConns map[string]uint64
 }
 
 // ConnectionCount gets current connection count.
@@ -194,6 +207,8 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 		concurrentLimiter: NewTokenLimiter(cfg.TokenLimit),
 		clients:           make(map[uint64]*clientConn),
 		globalConnID:      util.GlobalConnID{ServerID: 0, Is64bits: true},
+		userConns:         make(map[string]uint64),
+		groupConns:        make(map[string]uint64),
 	}
 	s.capability = defaultCapability
 	setTxnScope()
@@ -617,6 +632,75 @@ func (s *Server) checkConnectionCount() error {
 	return nil
 }
 
+// checkUserConnectionCount checks the number of the connections of the user and its resource group when the user
+// connects. The users without MAX_USER_CONNECTIONS are limited by the global max_user_connections.
+func (s *Server) checkUserConnectionCount(cc *clientConn) error {
+	sessionVars := cc.ctx.GetSessionVars()
+	user := sessionVars.User
+	checker := privilege.GetPrivilegeManager(cc.ctx.Session)
+	if user == nil || checker == nil {
+		return nil
+	}
+	maxUserConns, resourceGroup := checker.GetConnectionLimits(user.AuthUsername, user.AuthHostname)
+	if maxUserConns == 0 {
+		val, err := sessionVars.GlobalVarsAccessor.GetGlobalSysVar(variable.MaxUserConnections)
+		if err != nil {
+			return err
+		}
+		maxUserConns, err = strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+	var maxGroupConns uint32
+	if resourceGroup != "" {
+		maxGroupConns = s.cfg.MaxResourceGroupConnections[resourceGroup]
+	}
+
+	// The connections are checked and counted atomically, so the concurrent connections can't exceed the limits.
+	userKey := user.AuthUsername + "@" + user.AuthHostname
+	s.rwlock.Lock()
+	// The connection of COM_CHANGE_USER is counted again.
+	s.releaseUserConnectionWithoutLock(cc)
+	userConns, groupConns := s.userConns[userKey], s.groupConns[resourceGroup]
+	if (maxUserConns == 0 || userConns < maxUserConns) && (maxGroupConns == 0 || groupConns < uint64(maxGroupConns)) {
+		s.userConns[userKey]++
+		if resourceGroup != "" {
+			s.groupConns[resourceGroup]++
+		}
+		cc.userConnKey, cc.resourceGroup = userKey, resourceGroup
+		s.rwlock.Unlock()
+		return nil
+	}
+	s.rwlock.Unlock()
+
+	atomic.AddUint64(&s.rejectedUserConns, 1)
+	if maxUserConns > 0 && userConns >= maxUserConns {
+		logutil.BgLogger().Warn("too many connections of user",
+			zap.String("user", user.String()), zap.Uint64("max user connections", maxUserConns))
+		return errUserConCount.FastGenByArgs(user.Username)
+	}
+	logutil.BgLogger().Warn("too many connections of resource group",
+		zap.String("resource group", resourceGroup), zap.Uint32("max resource group connections", maxGroupConns))
+	return errResourceGroupConCount.FastGenByArgs(resourceGroup, maxGroupConns)
+}
+
+// releaseUserConnectionWithoutLock uncounts the connection from its user and resource group. The caller should hold rwlock.
+func (s *Server) releaseUserConnectionWithoutLock(cc *clientConn) {
+	if cc.userConnKey == "" {
+		return
+	}
+	if s.userConns[cc.userConnKey]--; s.userConns[cc.userConnKey] == 0 {
+		delete(s.userConns, cc.userConnKey)
+	}
+	if cc.resourceGroup != "" {
+		if s.groupConns[cc.resourceGroup]--; s.groupConns[cc.resourceGroup] == 0 {
+			delete(s.groupConns, cc.resourceGroup)
+		}
+	}
+	cc.userConnKey, cc.resourceGroup = "", ""
+}
+
 // ShowProcessList implements the SessionManager interface.
 func (s *Server) ShowProcessList() map[uint64]*util.ProcessInfo {
 	s.rwlock.RLock()
@@ -627,6 +711,7 @@ func (s *Server) ShowProcessList() map[uint64]*util.ProcessInfo {
 			continue
 		}
 		if pi := client.ctx.ShowProcess(); pi != nil {
+			atomic.StoreInt64(&pi.NetBufferBytes, client.netBufferBytes())
 			rs[pi.ID] = pi
 		}
 	}
@@ -16,6 +16,7 @@ package server
 
 import (
 	"crypto/x509"
+	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/tidb/domain/infosync"
@@ -28,12 +29,16 @@ var (
 	serverNotAfter  = "Ssl_server_not_after"
 	serverNotBefore = "Ssl_server_not_before"
 	upTime          = "Uptime"
+	// userConnErrors is the number of the connections rejected by MAX_USER_CONNECTIONS, the global
+	// max_user_connections or the max connections of the resource groups.
+	userConnErrors = "Connection_errors_max_user_connections"
 )
 
 var defaultStatus = map[string]*variable.StatusVal{
 	serverNotAfter:  {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
 	serverNotBefore: {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
 	upTime:          {Scope: variable.ScopeGlobal, Value: 0},
+	userConnErrors:  {Scope: variable.ScopeGlobal, Value: 0},
 }
 
 // GetScope gets the status variables scope.
@@ -62,6 +67,8 @@ func (s *Server) Stats(vars *variable.SessionVars) (map[string]interface{}, erro
 		}
 	}
 
+	m[userConnErrors] = atomic.LoadUint64(&s.rejectedUserConns)
+
 	var err error
 	info := serverInfo{}
 	info.ServerInfo, err = infosync.GetServerInfo()
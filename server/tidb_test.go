@@ -27,6 +27,7 @@ import (
 	"database/sql"
 	"encoding/pem"
 	"fmt"
+	"io"
 	"math/big"
 	"net/http"
 	"os"
@@ -352,6 +353,152 @@ func TestStatusAPIWithTLSCNCheck(t *testing.T) {
 	require.Nil(t, resp.Body.Close())
 }
 
+func TestStatusAPIRequireClientCert(t *testing.T) {
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+
+	caPath := filepath.Join(dir, "ca-cert-require.pem")
+	serverKeyPath := filepath.Join(dir, "server-key-require.pem")
+	serverCertPath := filepath.Join(dir, "server-cert-require.pem")
+	clientKeyPath := filepath.Join(dir, "client-key-require.pem")
+	clientCertPath := filepath.Join(dir, "client-cert-require.pem")
+
+	caCert, caKey, err := generateCert(0, "TiDB CA REQUIRE", nil, nil, filepath.Join(dir, "ca-key-require.pem"), caPath)
+	require.NoError(t, err)
+	_, _, err = generateCert(1, "tidb-server-require", caCert, caKey, serverKeyPath, serverCertPath)
+	require.NoError(t, err)
+	_, _, err = generateCert(2, "tidb-client-require", caCert, caKey, clientKeyPath, clientCertPath)
+	require.NoError(t, err)
+
+	cli := newTestServerClient()
+	cli.statusScheme = "https"
+	cfg := newTestConfig()
+	cfg.Port = cli.port
+	cfg.Status.StatusPort = cli.statusPort
+	cfg.Status.RequireClientCert = true
+	cfg.Security.ClusterSSLCA = caPath
+	cfg.Security.ClusterSSLCert = serverCertPath
+	cfg.Security.ClusterSSLKey = serverKeyPath
+	server, err := NewServer(cfg, ts.tidbdrv)
+	require.NoError(t, err)
+
+	cli.port = getPortFromTCPAddr(server.listener.Addr())
+	cli.statusPort = getPortFromTCPAddr(server.statusListener.Addr())
+	go func() {
+		err := server.Run()
+		require.NoError(t, err)
+	}()
+	defer server.Close()
+	time.Sleep(time.Millisecond * 100)
+
+	// The client without certificate is rejected.
+	hc := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	_, err = hc.Get(cli.statusURL("/status")) // nolint: bodyclose
+	require.Error(t, err)
+
+	hc = newTLSHttpClient(t, caPath, clientCertPath, clientKeyPath)
+	resp, err := hc.Get(cli.statusURL("/status"))
+	require.NoError(t, err)
+	require.Nil(t, resp.Body.Close())
+}
+
+func TestStatusAPIRequireAuth(t *testing.T) {
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+
+	caPath := filepath.Join(dir, "ca-cert-auth.pem")
+	serverKeyPath := filepath.Join(dir, "server-key-auth.pem")
+	serverCertPath := filepath.Join(dir, "server-cert-auth.pem")
+	clientKeyPath := filepath.Join(dir, "client-key-auth.pem")
+	clientCertPath := filepath.Join(dir, "client-cert-auth.pem")
+
+	caCert, caKey, err := generateCert(0, "TiDB CA AUTH", nil, nil, filepath.Join(dir, "ca-key-auth.pem"), caPath)
+	require.NoError(t, err)
+	_, _, err = generateCert(1, "tidb-server-auth", caCert, caKey, serverKeyPath, serverCertPath)
+	require.NoError(t, err)
+	_, _, err = generateCert(2, "tidb-client-auth", caCert, caKey, clientKeyPath, clientCertPath)
+	require.NoError(t, err)
+
+	cli := newTestServerClient()
+	cli.statusScheme = "https"
+	cfg := newTestConfig()
+	cfg.Port = cli.port
+	cfg.Status.StatusPort = cli.statusPort
+	cfg.Status.RequireAuth = true
+	cfg.Security.ClusterSSLCA = caPath
+	cfg.Security.ClusterSSLCert = serverCertPath
+	cfg.Security.ClusterSSLKey = serverKeyPath
+	server, err := NewServer(cfg, ts.tidbdrv)
+	require.NoError(t, err)
+
+	cli.port = getPortFromTCPAddr(server.listener.Addr())
+	cli.statusPort = getPortFromTCPAddr(server.statusListener.Addr())
+	go func() {
+		err := server.Run()
+		require.NoError(t, err)
+	}()
+	defer server.Close()
+	cli.waitUntilServerCanConnect()
+
+	tk := testkit.NewTestKit(t, ts.store)
+	tk.MustExec("create user 'status_client'@'%' identified by '123'")
+	tk.MustExec("grant STATUS_API_CLIENT on *.* to 'status_client'@'%'")
+	tk.MustExec("create user 'status_nobody'@'%' identified with 'caching_sha2_password' by '456'")
+
+	// The plain HTTP is rejected.
+	_, err = http.Get(fmt.Sprintf("http://localhost:%d/status", cli.statusPort)) // nolint: bodyclose
+	require.Error(t, err)
+
+	var hc *http.Client
+	doRequest := func(path, user, password string) (int, string) {
+		req, err := http.NewRequest(http.MethodGet, cli.statusURL(path), nil)
+		require.NoError(t, err)
+		if user != "" {
+			req.SetBasicAuth(user, password)
+		}
+		resp, err := hc.Do(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode, string(body)
+	}
+
+	// The clients with the cluster certificate, like the other TiDB instances and
+	// Prometheus, don't need the basic auth.
+	hc = newTLSHttpClient(t, caPath, clientCertPath, clientKeyPath)
+	for _, path := range []string{"/status", "/metrics", "/schema", "/settings"} {
+		code, _ := doRequest(path, "", "")
+		require.Equal(t, http.StatusOK, code, path)
+	}
+
+	hc = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	// The health check doesn't need to be authenticated.
+	code, _ := doRequest("/status", "", "")
+	require.Equal(t, http.StatusOK, code)
+
+	for _, path := range []string{"/schema", "/settings", "/config", "/debug/pprof/"} {
+		code, body := doRequest(path, "", "")
+		require.Equal(t, http.StatusUnauthorized, code, path)
+		require.Contains(t, body, "basic auth is required")
+		code, body = doRequest(path, "status_client", "wrong")
+		require.Equal(t, http.StatusUnauthorized, code, path)
+		require.Contains(t, body, "access denied")
+		code, body = doRequest(path, "status_nobody", "456")
+		require.Equal(t, http.StatusUnauthorized, code, path)
+		require.Contains(t, body, "STATUS_API_CLIENT privilege is required")
+		code, _ = doRequest(path, "status_client", "123")
+		require.Equal(t, http.StatusOK, code, path)
+		// The SUPER privilege implies the STATUS_API_CLIENT privilege.
+		code, _ = doRequest(path, "root", "")
+		require.Equal(t, http.StatusOK, code, path)
+	}
+}
+
 func newTLSHttpClient(t *testing.T, caFile, certFile, keyFile string) *http.Client {
 	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
 	require.NoError(t, err)
@@ -1090,6 +1237,108 @@ func TestInitConnect(t *testing.T) {
 	ts.runTestInitConnect(t)
 }
 
+func TestUserConnectionLimits(t *testing.T) {
+	useTempSlowQueryFile(t)
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+	ts.server.cfg.MaxResourceGroupConnections = map[string]uint32{"rg1": 1}
+
+	ctx := context.Background()
+	rootDB, err := sql.Open("mysql", ts.getDSN())
+	require.NoError(t, err)
+	defer rootDB.Close()
+	root, err := rootDB.Conn(ctx)
+	require.NoError(t, err)
+	defer root.Close()
+	for _, sql := range []string{
+		"CREATE USER conn_limit WITH MAX_USER_CONNECTIONS 1",
+		"CREATE USER conn_nolimit",
+		"CREATE USER conn_rg1, conn_rg2 WITH RESOURCE GROUP rg1",
+	} {
+		_, err = root.ExecContext(ctx, sql)
+		require.NoError(t, err)
+	}
+	rejected := func() int {
+		var name string
+		var value int
+		err := root.QueryRowContext(ctx, "SHOW GLOBAL STATUS LIKE 'Connection_errors_max_user_connections'").Scan(&name, &value)
+		require.NoError(t, err)
+		return value
+	}
+	// connect opens a connection of the user, it returns the function to close the connection.
+	connect := func(user string) (func(), error) {
+		db, err := sql.Open("mysql", ts.getDSN(func(config *mysql.Config) {
+			config.User = user
+			config.DBName = ""
+		}))
+		require.NoError(t, err)
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			require.NoError(t, db.Close())
+			return nil, err
+		}
+		return func() {
+			require.NoError(t, conn.Close())
+			require.NoError(t, db.Close())
+		}, nil
+	}
+
+	// The connections of the user are limited by MAX_USER_CONNECTIONS.
+	closeConn, err := connect("conn_limit")
+	require.NoError(t, err)
+	_, err = connect("conn_limit")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Error 1203")
+	require.Equal(t, 1, rejected())
+	closeConn()
+
+	// The users without MAX_USER_CONNECTIONS are limited by the global max_user_connections.
+	_, err = root.ExecContext(ctx, "SET GLOBAL max_user_connections = 1")
+	require.NoError(t, err)
+	closeConn, err = connect("conn_nolimit")
+	require.NoError(t, err)
+	_, err = connect("conn_nolimit")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Error 1203")
+	require.Equal(t, 2, rejected())
+	closeConn()
+	_, err = root.ExecContext(ctx, "SET GLOBAL max_user_connections = 0")
+	require.NoError(t, err)
+
+	// The connections of the users in a resource group are limited by the resource group.
+	closeConn, err = connect("conn_rg1")
+	require.NoError(t, err)
+	_, err = connect("conn_rg2")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Resource group rg1 already has more than 1 active connections")
+	require.Equal(t, 3, rejected())
+	closeConn()
+
+	// The concurrent connections can't exceed the limit.
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/server/afterCheckUserConnectionCount", "sleep(100)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/server/afterCheckUserConnectionCount"))
+	}()
+	var wg sync.WaitGroup
+	closeConns := make([]func(), 10)
+	for i := range closeConns {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			closeConns[i], _ = connect("conn_limit")
+		}(i)
+	}
+	wg.Wait()
+	connected := 0
+	for _, closeConn := range closeConns {
+		if closeConn != nil {
+			connected++
+			closeConn()
+		}
+	}
+	require.Equal(t, 1, connected)
+}
+
 func TestSumAvg(t *testing.T) {
 	ts, cleanup := createTidbTestSuite(t)
 	defer cleanup()
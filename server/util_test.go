@@ -15,6 +15,7 @@
 package server
 
 import (
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
@@ -590,3 +591,13 @@ func newTestConfig() *config.Config {
 	cfg.Socket = ""
 	return cfg
 }
+
+// useTempSlowQueryFile points the slow query log to a temporary directory, so
+// the tests which reinitialize the loggers don't write it into the source tree.
+func useTempSlowQueryFile(t *testing.T) {
+	restore := config.RestoreFunc()
+	t.Cleanup(restore)
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Log.SlowQueryFile = filepath.Join(t.TempDir(), "tidb-slow.log")
+	})
+}
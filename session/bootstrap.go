@@ -23,10 +23,12 @@ import (
 	"encoding/hex"
 	"flag"
 	"fmt"
+	"os"
 	osuser "os/user"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -36,6 +38,7 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -91,6 +94,9 @@ const (
 		Create_Tablespace_Priv  ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_slave_priv	    	ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_client_priv		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		max_user_connections	INT UNSIGNED NOT NULL DEFAULT 0,
+		User_attributes			JSON,
+		Password_last_changed	TIMESTAMP DEFAULT CURRENT_TIMESTAMP(),
 		PRIMARY KEY (Host, User));`
 	// CreateGlobalPrivTable is the SQL statement creates Global scope privilege table in system db.
 	CreateGlobalPrivTable = "CREATE TABLE IF NOT EXISTS mysql.global_priv (" +
@@ -386,6 +392,53 @@ const (
 		UNIQUE KEY table_version_seq (table_id, version, seq_no),
 		KEY table_create_time (table_id, create_time, seq_no)
 	);`
+	// CreateSQLBlocklist stores the rules to block or rewrite the statements of users.
+	CreateSQLBlocklist = `CREATE TABLE IF NOT EXISTS mysql.sql_blocklist (
+		name 		VARCHAR(64) NOT NULL,
+		digest 		VARCHAR(64) NOT NULL DEFAULT '' comment 'the digest of the normalized statement',
+		pattern 	TEXT comment 'the LIKE pattern against the normalized statement',
+		user 		CHAR(32) NOT NULL DEFAULT '',
+		table_name 	VARCHAR(129) NOT NULL DEFAULT '' comment 'in the form of db.table',
+		action 		ENUM('block','rewrite') NOT NULL DEFAULT 'block',
+		replacement TEXT,
+		reason 		VARCHAR(200),
+		PRIMARY KEY (name)
+	);`
+	// CreateTimeZoneNameTable maps the names of the time zones to their IDs.
+	CreateTimeZoneNameTable = `CREATE TABLE IF NOT EXISTS mysql.time_zone_name (
+		Name 			CHAR(64) NOT NULL,
+		Time_zone_id 	INT UNSIGNED NOT NULL,
+		PRIMARY KEY (Name)
+	);`
+	// CreateTimeZoneTable stores the time zones.
+	CreateTimeZoneTable = `CREATE TABLE IF NOT EXISTS mysql.time_zone (
+		Time_zone_id 		INT UNSIGNED NOT NULL AUTO_INCREMENT,
+		Use_leap_seconds 	ENUM('Y','N') NOT NULL DEFAULT 'N',
+		PRIMARY KEY (Time_zone_id)
+	);`
+	// CreateTimeZoneTransitionTable stores the transitions of the time zones.
+	CreateTimeZoneTransitionTable = `CREATE TABLE IF NOT EXISTS mysql.time_zone_transition (
+		Time_zone_id 		INT UNSIGNED NOT NULL,
+		Transition_time 	BIGINT NOT NULL comment 'the UTC unix timestamp of the transition',
+		Transition_type_id 	INT UNSIGNED NOT NULL,
+		PRIMARY KEY (Time_zone_id, Transition_time)
+	);`
+	// CreateTimeZoneTransitionTypeTable stores the local time types of the time zones.
+	CreateTimeZoneTransitionTypeTable = `CREATE TABLE IF NOT EXISTS mysql.time_zone_transition_type (
+		Time_zone_id 		INT UNSIGNED NOT NULL,
+		Transition_type_id 	INT UNSIGNED NOT NULL,
+		Offset 				INT NOT NULL DEFAULT 0 comment 'the offset from UTC in seconds',
+		Is_DST 				TINYINT UNSIGNED NOT NULL DEFAULT 0,
+		Abbreviation 		CHAR(8) NOT NULL DEFAULT '',
+		PRIMARY KEY (Time_zone_id, Transition_type_id)
+	);`
+	// CreateTimeZoneLeapSecondTable stores the leap seconds, it is created for the compatibility with
+	// mysql_tzinfo_to_sql only, the leap seconds are ignored.
+	CreateTimeZoneLeapSecondTable = `CREATE TABLE IF NOT EXISTS mysql.time_zone_leap_second (
+		Transition_time 	BIGINT NOT NULL,
+		Correction 			INT NOT NULL,
+		PRIMARY KEY (Transition_time)
+	);`
 )
 
 // bootstrap initiates system DB for a store.
@@ -435,6 +488,12 @@ const (
 	tidbSystemTZ = "system_tz"
 	// The variable name in mysql.tidb table and it will indicate if the new collations are enabled in the TiDB cluster.
 	tidbNewCollationEnabled = "new_collation_enabled"
+	// The variable name in mysql.tidb table. It exists if the initialize-sql-file hasn't been executed
+	// successfully after the first bootstrap.
+	tidbInitSQLFilePending = "initialize_sql_file_pending"
+	// The variable name in mysql.tidb table. It exists if the upgrade hooks and the upgrade-sql-file haven't been
+	// executed successfully after the upgrade, and its value is the bootstrap version upgraded from.
+	tidbUpgradeHooksPending = "upgrade_hooks_pending"
 	// The variable name in mysql.tidb table and it records the default value of
 	// mem-quota-query when upgrade from v3.0.x to v4.0.9+.
 	tidbDefMemoryQuotaQuery = "default_memory_quota_query"
@@ -569,11 +628,19 @@ const (
 	version82 = 82
 	// version83 adds the tables mysql.stats_history
 	version83 = 83
+	// version84 adds the table mysql.sql_blocklist
+	version84 = 84
+	// version85 adds the time zone tables mysql.time_zone*
+	version85 = 85
+	// version86 adds the column max_user_connections to mysql.user
+	version86 = 86
+	// version87 adds the columns User_attributes and Password_last_changed to mysql.user
+	version87 = 87
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version83
+var currentBootstrapVersion int64 = version87
 
 var (
 	bootstrapVersion = []func(Session, int64){
@@ -660,6 +727,10 @@ var (
 		upgradeToVer81,
 		upgradeToVer82,
 		upgradeToVer83,
+		upgradeToVer84,
+		upgradeToVer85,
+		upgradeToVer86,
+		upgradeToVer87,
 	}
 )
 
@@ -725,6 +796,13 @@ func upgrade(s Session) {
 		// It is already bootstrapped/upgraded by a higher version TiDB server.
 		return
 	}
+	// Record the upgrade hooks as pending before the upgrade, and keep the earliest version if the
+	// hooks of an earlier upgrade haven't been executed.
+	if hasUpgradeHooks() {
+		mustExecute(s, `INSERT HIGH_PRIORITY IGNORE INTO %n.%n VALUES (%?, %?, "The upgrade hooks haven't been executed.")`,
+			mysql.SystemDB, mysql.TiDBTable, tidbUpgradeHooksPending, ver,
+		)
+	}
 	// Do upgrade works then update bootstrap version.
 	for _, upgrade := range bootstrapVersion {
 		upgrade(s, ver)
@@ -754,6 +832,155 @@ func upgrade(s Session) {
 	}
 }
 
+// UpgradeHook is a migration hook executed after the TiDB cluster is upgraded from the bootstrap version fromVer.
+type UpgradeHook func(s Session, fromVer int64) error
+
+var upgradeHooks struct {
+	sync.Mutex
+	names []string
+	hooks []UpgradeHook
+}
+
+// RegisterUpgradeHook registers a migration hook executed after the TiDB cluster is upgraded to a new bootstrap
+// version. The hooks are executed in the order of registration by one TiDB server. They're executed again from
+// the first one if any of them fails or TiDB crashes before they are finished, so they should be idempotent.
+func RegisterUpgradeHook(name string, hook UpgradeHook) {
+	upgradeHooks.Lock()
+	defer upgradeHooks.Unlock()
+	upgradeHooks.names = append(upgradeHooks.names, name)
+	upgradeHooks.hooks = append(upgradeHooks.hooks, hook)
+}
+
+func hasUpgradeHooks() bool {
+	upgradeHooks.Lock()
+	defer upgradeHooks.Unlock()
+	return len(upgradeHooks.hooks) > 0 || len(config.GetGlobalConfig().UpgradeSQLFile) > 0
+}
+
+// doUpgradeHooks executes the registered migration hooks and the upgrade-sql-file after the upgrade.
+func doUpgradeHooks(s Session, fromVer int64) error {
+	upgradeHooks.Lock()
+	names, hooks := upgradeHooks.names, upgradeHooks.hooks
+	upgradeHooks.Unlock()
+	for i, hook := range hooks {
+		logutil.BgLogger().Info("executing upgrade hook", zap.String("name", names[i]), zap.Int64("from", fromVer))
+		if err := hook(s, fromVer); err != nil {
+			return errors.Annotatef(err, "upgrade hook %s failed", names[i])
+		}
+	}
+	sqlFile := config.GetGlobalConfig().UpgradeSQLFile
+	if len(sqlFile) == 0 {
+		return nil
+	}
+	logutil.BgLogger().Info("executing upgrade-sql-file", zap.String("file", sqlFile), zap.Int64("from", fromVer))
+	return executeSQLFile(s, sqlFile)
+}
+
+// doBootstrapSQLFile executes the initialize-sql-file after the TiDB cluster is bootstrapped on the first time.
+func doBootstrapSQLFile(s Session) error {
+	sqlFile := config.GetGlobalConfig().InitializeSQLFile
+	logutil.BgLogger().Info("executing initialize-sql-file", zap.String("file", sqlFile))
+	return executeSQLFile(s, sqlFile)
+}
+
+// doPendingBootstrapWorks executes the initialize-sql-file and the upgrade hooks recorded as pending in mysql.tidb
+// by the bootstrap and the upgrade, then removes the records. So they're executed again on the next start if they
+// fail or TiDB crashes before they are finished. The record is locked while its work is executed, so only one
+// TiDB server executes it.
+func doPendingBootstrapWorks(store kv.Storage) error {
+	if len(config.GetGlobalConfig().InitializeSQLFile) > 0 {
+		if err := doPendingBootstrapWork(store, tidbInitSQLFilePending, func(s Session, _ string) error {
+			return doBootstrapSQLFile(s)
+		}); err != nil {
+			return err
+		}
+	}
+	return doPendingBootstrapWork(store, tidbUpgradeHooksPending, func(s Session, val string) error {
+		fromVer, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return doUpgradeHooks(s, fromVer)
+	})
+}
+
+func doPendingBootstrapWork(store kv.Storage, name string, work func(s Session, val string) error) (err error) {
+	lockSe, err := createSession(store)
+	if err != nil {
+		return err
+	}
+	defer lockSe.Close()
+	// Wait for the other TiDB server which is executing the work.
+	lockSe.GetSessionVars().LockWaitTimeout = int64(time.Hour / time.Millisecond)
+	ctx := context.Background()
+	if _, err = lockSe.ExecuteInternal(ctx, "BEGIN PESSIMISTIC"); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_, rbErr := lockSe.ExecuteInternal(ctx, "ROLLBACK")
+			terror.Log(rbErr)
+		}
+	}()
+	rs, err := lockSe.ExecuteInternal(ctx, `SELECT HIGH_PRIORITY VARIABLE_VALUE FROM %n.%n WHERE VARIABLE_NAME = %? FOR UPDATE`,
+		mysql.SystemDB, mysql.TiDBTable, name)
+	if err != nil {
+		return err
+	}
+	rows, err := sqlexec.DrainRecordSet(ctx, rs, 1)
+	terror.Call(rs.Close)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		_, err = lockSe.ExecuteInternal(ctx, "COMMIT")
+		return err
+	}
+
+	se, err := CreateSession(store)
+	if err != nil {
+		return err
+	}
+	err = work(se, rows[0].GetString(0))
+	se.Close()
+	if err != nil {
+		return err
+	}
+	if _, err = lockSe.ExecuteInternal(ctx, "DELETE HIGH_PRIORITY FROM %n.%n WHERE VARIABLE_NAME = %?",
+		mysql.SystemDB, mysql.TiDBTable, name); err != nil {
+		return err
+	}
+	_, err = lockSe.ExecuteInternal(ctx, "COMMIT")
+	return err
+}
+
+// executeSQLFile executes the statements in the SQL file one by one, and stops at the first failed statement.
+// The statements aren't logged since they may contain the passwords.
+func executeSQLFile(s Session, sqlFile string) error {
+	b, err := os.ReadFile(sqlFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ctx := context.Background()
+	stmts, err := s.Parse(ctx, string(b))
+	if err != nil {
+		return errors.Annotatef(err, "parse %s failed", sqlFile)
+	}
+	for i, stmt := range stmts {
+		rs, err := s.ExecuteStmt(ctx, stmt)
+		if err == nil && rs != nil {
+			_, err = sqlexec.DrainRecordSet(ctx, rs, s.GetSessionVars().MaxChunkSize)
+			if closeErr := rs.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			return errors.Annotatef(err, "execute statement %d in %s failed", i+1, sqlFile)
+		}
+	}
+	return nil
+}
+
 // upgradeToVer2 updates to version 2.
 func upgradeToVer2(s Session, ver int64) {
 	if ver >= version2 {
@@ -1722,6 +1949,39 @@ func upgradeToVer83(s Session, ver int64) {
 	doReentrantDDL(s, CreateStatsHistory)
 }
 
+func upgradeToVer84(s Session, ver int64) {
+	if ver >= version84 {
+		return
+	}
+	doReentrantDDL(s, CreateSQLBlocklist)
+}
+
+func upgradeToVer85(s Session, ver int64) {
+	if ver >= version85 {
+		return
+	}
+	doReentrantDDL(s, CreateTimeZoneNameTable)
+	doReentrantDDL(s, CreateTimeZoneTable)
+	doReentrantDDL(s, CreateTimeZoneTransitionTable)
+	doReentrantDDL(s, CreateTimeZoneTransitionTypeTable)
+	doReentrantDDL(s, CreateTimeZoneLeapSecondTable)
+}
+
+func upgradeToVer86(s Session, ver int64) {
+	if ver >= version86 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `max_user_connections` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `Repl_client_priv`", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer87(s Session, ver int64) {
+	if ver >= version87 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `User_attributes` JSON AFTER `max_user_connections`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_last_changed` TIMESTAMP DEFAULT CURRENT_TIMESTAMP() AFTER `User_attributes`", infoschema.ErrColumnExists)
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -1810,6 +2070,14 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateAnalyzeOptionsTable)
 	// Create stats_history table.
 	mustExecute(s, CreateStatsHistory)
+	// Create sql_blocklist table.
+	mustExecute(s, CreateSQLBlocklist)
+	// Create time zone tables.
+	mustExecute(s, CreateTimeZoneNameTable)
+	mustExecute(s, CreateTimeZoneTable)
+	mustExecute(s, CreateTimeZoneTransitionTable)
+	mustExecute(s, CreateTimeZoneTransitionTypeTable)
+	mustExecute(s, CreateTimeZoneLeapSecondTable)
 }
 
 // doDMLWorks executes DML statements in bootstrap stage.
@@ -1826,10 +2094,10 @@ func doDMLWorks(s Session) {
 			logutil.BgLogger().Fatal("failed to read current user. unable to secure bootstrap.", zap.Error(err))
 		}
 		mustExecute(s, `INSERT HIGH_PRIORITY INTO mysql.user VALUES
-		("localhost", "root", %?, "auth_socket", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`, u.Username)
+		("localhost", "root", %?, "auth_socket", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", 0, NULL, CURRENT_TIMESTAMP())`, u.Username)
 	} else {
 		mustExecute(s, `INSERT HIGH_PRIORITY INTO mysql.user VALUES
-		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`)
+		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", 0, NULL, CURRENT_TIMESTAMP())`)
 	}
 
 	// Init global system variables table.
@@ -1888,6 +2156,12 @@ func doDMLWorks(s Session) {
 
 	writeStmtSummaryVars(s)
 
+	if len(config.GetGlobalConfig().InitializeSQLFile) > 0 {
+		mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES(%?, %?, "The initialize-sql-file hasn't been executed.")`,
+			mysql.SystemDB, mysql.TiDBTable, tidbInitSQLFilePending, varTrue,
+		)
+	}
+
 	_, err := s.ExecuteInternal(context.Background(), "COMMIT")
 	if err != nil {
 		sleepTime := 1 * time.Second
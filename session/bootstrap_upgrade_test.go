@@ -16,10 +16,16 @@ package session_test
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/stretchr/testify/require"
 )
@@ -55,3 +61,86 @@ func TestUpgradeVersion83(t *testing.T) {
 		require.Equal(t, statsHistoryTblFields[i].tp, strings.ToLower(row.GetString(1)))
 	}
 }
+
+func TestInitializeSQLFile(t *testing.T) {
+	sqlFile := filepath.Join(t.TempDir(), "init.sql")
+	stmts := `CREATE USER IF NOT EXISTS cloudadmin IDENTIFIED BY 'secret';
+GRANT ALL ON *.* TO cloudadmin WITH GRANT OPTION;
+DROP USER IF EXISTS root;
+SELECT no_such_column FROM mysql.user;
+CREATE DATABASE IF NOT EXISTS app;
+CREATE TABLE IF NOT EXISTS app.t (a INT PRIMARY KEY);
+INSERT IGNORE INTO app.t VALUES (1), (2);`
+	require.NoError(t, os.WriteFile(sqlFile, []byte(stmts), 0644))
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.InitializeSQLFile = sqlFile
+	})
+
+	store, err := mockstore.NewMockStore()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, store.Close()) }()
+	// TiDB fails to start at the failed statement, and the file is executed again on the next start.
+	_, err = session.BootstrapSession(store)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "execute statement 4 in")
+	require.NotContains(t, err.Error(), "secret")
+	dom, err := session.GetDomain(store)
+	require.NoError(t, err)
+	dom.Close()
+	session.UnsetStoreBootstrapped(store.UUID())
+
+	require.NoError(t, os.WriteFile(sqlFile, []byte(strings.Replace(stmts, "no_such_column", "user", 1)), 0644))
+	dom, err = session.BootstrapSession(store)
+	require.NoError(t, err)
+	defer dom.Close()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select user, grant_priv from mysql.user").Check(testkit.Rows("cloudadmin Y"))
+	tk.MustQuery("select * from app.t").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select count(*) from mysql.tidb where variable_name = 'initialize_sql_file_pending'").Check(testkit.Rows("0"))
+}
+
+func TestUpgradeHooks(t *testing.T) {
+	sqlFile := filepath.Join(t.TempDir(), "upgrade.sql")
+	require.NoError(t, os.WriteFile(sqlFile, []byte(`CREATE TABLE IF NOT EXISTS test.upgrade_log (ver BIGINT);
+INSERT INTO test.upgrade_log VALUES (1);`), 0644))
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.UpgradeSQLFile = sqlFile
+	})
+
+	var fromVers []int64
+	session.RegisterUpgradeHook("test", func(s session.Session, fromVer int64) error {
+		fromVers = append(fromVers, fromVer)
+		_, err := s.ExecuteInternal(context.Background(), "CREATE TABLE IF NOT EXISTS test.hook_log (ver BIGINT)")
+		return err
+	})
+
+	store, err := mockstore.NewMockStore()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, store.Close()) }()
+	dom, err := session.BootstrapSession(store)
+	require.NoError(t, err)
+	// The hooks aren't executed on the first bootstrap.
+	require.Empty(t, fromVers)
+
+	// Downgrade the store by one version.
+	oldVer := session.CurrentBootstrapVersion - 1
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	require.NoError(t, meta.NewMeta(txn).FinishBootstrap(oldVer))
+	require.NoError(t, txn.Commit(context.Background()))
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec(fmt.Sprintf(`update mysql.tidb set variable_value = "%d" where variable_name = "tidb_server_version"`, oldVer))
+	session.UnsetStoreBootstrapped(store.UUID())
+	dom.Close()
+
+	dom, err = session.BootstrapSession(store)
+	require.NoError(t, err)
+	defer dom.Close()
+	require.Equal(t, []int64{oldVer}, fromVers)
+	tk = testkit.NewTestKit(t, store)
+	tk.MustQuery("select * from test.upgrade_log").Check(testkit.Rows("1"))
+	tk.MustQuery("show tables in test like 'hook_log'").Check(testkit.Rows("hook_log"))
+	tk.MustQuery("select count(*) from mysql.tidb where variable_name = 'upgrade_hooks_pending'").Check(testkit.Rows("0"))
+}
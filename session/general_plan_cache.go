@@ -0,0 +1,140 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/parser/ast"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/hack"
+	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// generalPlanCacheKey is the parameterized statement with the current database.
+type generalPlanCacheKey string
+
+// Hash implements kvcache.Key interface.
+func (k generalPlanCacheKey) Hash() []byte {
+	return hack.Slice(string(k))
+}
+
+// generalPlanCacheValue is the ID of the prepared statement created for the parameterized statement,
+// 0 means the statement can't use the plan cache.
+type generalPlanCacheValue uint32
+
+// getGeneralPlanCache returns the general plan cache of the session, whose capacity follows tidb_general_plan_cache_size.
+func (s *session) getGeneralPlanCache() *kvcache.SimpleLRUCache {
+	capacity := uint(s.sessionVars.GeneralPlanCacheSize)
+	if capacity == 0 {
+		capacity = variable.DefTiDBGeneralPlanCacheSize
+	}
+	if s.generalPlanCache == nil {
+		s.generalPlanCache = kvcache.NewSimpleLRUCache(capacity, 0, 0)
+		s.generalPlanCache.SetOnEvict(func(_ kvcache.Key, value kvcache.Value) {
+			s.dropGeneralPlanCacheStmt(value.(generalPlanCacheValue))
+		})
+		return s.generalPlanCache
+	}
+	for uint(s.generalPlanCache.Size()) > capacity {
+		_, value, _ := s.generalPlanCache.RemoveOldest()
+		s.dropGeneralPlanCacheStmt(value.(generalPlanCacheValue))
+	}
+	if err := s.generalPlanCache.SetCapacity(capacity); err != nil {
+		logutil.BgLogger().Warn("set the capacity of the general plan cache failed", zap.Error(err))
+	}
+	return s.generalPlanCache
+}
+
+func (s *session) dropGeneralPlanCacheStmt(stmtID generalPlanCacheValue) {
+	if stmtID != 0 {
+		// The statement may be referred by the history of the transaction, so drop it like a closed prepared statement.
+		_ = s.DropPreparedStmt(uint32(stmtID))
+	}
+}
+
+// evictGeneralPlanCacheByMemory drops the least recently used statements of the general plan cache while the used
+// memory exceeds the guard of the plan cache, like the prepared plan cache does.
+func (s *session) evictGeneralPlanCacheByMemory(cache *kvcache.SimpleLRUCache) {
+	limit := uint64(float64(plannercore.PreparedPlanCacheMaxMemory.Load()) * (1.0 - plannercore.PreparedPlanCacheMemoryGuardRatio))
+	for cache.Size() > 0 {
+		memUsed, err := memory.MemUsed()
+		if err != nil || memUsed <= limit {
+			return
+		}
+		_, value, _ := cache.RemoveOldest()
+		s.dropGeneralPlanCacheStmt(value.(generalPlanCacheValue))
+	}
+}
+
+// executeWithGeneralPlanCache executes a text protocol statement as a prepared statement of its parameterized form,
+// so the statements which only differ in constants reuse the cached plans. It returns false if the statement isn't
+// executed, then it should be executed as usual.
+func (s *session) executeWithGeneralPlanCache(ctx context.Context, stmtNode ast.StmtNode) (sqlexec.RecordSet, bool, error) {
+	vars := s.sessionVars
+	if !vars.EnableGeneralPlanCache || s.isInternal() || !plannercore.PreparedPlanCacheEnabled() {
+		return nil, false, nil
+	}
+	switch stmtNode.(type) {
+	case *ast.PrepareStmt, *ast.ExecuteStmt, *ast.DeallocateStmt:
+		// The prepared statements, including the internal ones of this cache, are executed as they are.
+		return nil, false, nil
+	}
+	paramSQL, params, ok := plannercore.ParameterizeForGeneralPlanCache(s, stmtNode)
+	if !ok {
+		return nil, false, nil
+	}
+	cache := s.getGeneralPlanCache()
+	key := generalPlanCacheKey(vars.CurrentDB + "." + paramSQL)
+	var stmtID uint32
+	if value, ok := cache.Get(key); ok {
+		stmtID = uint32(value.(generalPlanCacheValue))
+		if stmtID == 0 {
+			return nil, false, nil
+		}
+		if _, ok := vars.PreparedStmts[stmtID]; !ok {
+			stmtID = 0
+			cache.Delete(key)
+		}
+	}
+	if stmtID == 0 {
+		s.evictGeneralPlanCacheByMemory(cache)
+		id, _, _, err := s.PrepareStmt(paramSQL)
+		if err != nil {
+			// Execute the original statement to report the error, if any.
+			return nil, false, nil
+		}
+		prepared, ok := vars.PreparedStmts[id].(*plannercore.CachedPrepareStmt)
+		if !ok || !prepared.PreparedAst.UseCache {
+			vars.RemovePreparedStmt(id)
+			cache.Put(key, generalPlanCacheValue(0))
+			return nil, false, nil
+		}
+		cache.Put(key, generalPlanCacheValue(id))
+		stmtID = id
+	}
+	// Preparing the statement may roll back the txn context, so prepare it again.
+	if err := s.PrepareTxnCtx(ctx); err != nil {
+		return nil, true, err
+	}
+	// The start time of the statement is kept, so the parameterization is counted in its timings.
+	rs, err := s.executePreparedStmt(ctx, stmtID, params)
+	return rs, true, err
+}
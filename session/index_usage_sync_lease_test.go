@@ -22,6 +22,7 @@ import (
 
 var GetBootstrapVersion = getBootstrapVersion
 var CurrentBootstrapVersion = currentBootstrapVersion
+var UnsetStoreBootstrapped = unsetStoreBootstrapped
 
 func TestIndexUsageSyncLease(t *testing.T) {
 	store, dom := createStoreAndBootstrap(t)
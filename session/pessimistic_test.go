@@ -831,6 +831,51 @@ func (s *testPessimisticSuite) TestInnodbLockWaitTimeout(c *C) {
 	wg.Wait()
 }
 
+func (s *testPessimisticSuite) TestAutoCommitLockWaitTimeoutRetry(c *C) {
+	// Increasing the ManagedLockTTL so that the lock may not be resolved testing with TiKV.
+	atomic.StoreUint64(&transaction.ManagedLockTTL, 5000)
+	defer func() {
+		atomic.StoreUint64(&transaction.ManagedLockTTL, 300)
+	}()
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, v int)")
+	tk.MustExec("insert into t values (1, 1)")
+
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	tk2.MustExec("set tidb_txn_mode = 'pessimistic'")
+	tk2.MustExec("set innodb_lock_wait_timeout = 1")
+	tk2.MustExec("set tidb_lock_wait_timeout_retry_jitter = 0")
+	tk2.MustExec("set tidb_lock_wait_timeout_retry_limit = 1")
+
+	// The auto-committed statement waits for the lock and times out after the retry.
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("update t set v = 2 where id = 1")
+	start := time.Now()
+	_, err := tk2.Exec("update t set v = v + 10 where id = 1")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, storeerr.ErrLockWaitTimeout.Error())
+	c.Assert(time.Since(start), GreaterEqual, 2*time.Second)
+	tk.MustExec("rollback")
+
+	// The auto-committed statement times out once and succeeds after the lock is released.
+	tk2.MustExec("set tidb_lock_wait_timeout_retry_limit = 3")
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("update t set v = 2 where id = 1")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		time.Sleep(1500 * time.Millisecond)
+		tk.MustExec("commit")
+	}()
+	start = time.Now()
+	tk2.MustExec("update t set v = v + 10 where id = 1")
+	c.Assert(time.Since(start), GreaterEqual, time.Second)
+	wg.Wait()
+	tk.MustQuery("select v from t where id = 1").Check(testkit.Rows("12"))
+}
+
 func (s *testPessimisticSuite) TestPushConditionCheckForPessimisticTxn(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
 	tk1 := testkit.NewTestKitWithInit(c, s.store)
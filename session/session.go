@@ -87,12 +87,15 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/keydecoder"
 	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sli"
+	"github.com/pingcap/tidb/util/sqlblocklist"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/tableutil"
 	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/pingcap/tidb/util/writeconflicthistory"
 	tikvstore "github.com/tikv/client-go/v2/kv"
 	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
@@ -115,6 +118,7 @@ var (
 	sessionExecuteParseDurationGeneral    = metrics.SessionExecuteParseDuration.WithLabelValues(metrics.LblGeneral)
 
 	telemetryCTEUsage = metrics.TelemetrySQLCTECnt
+	telemetryMPPUsage = metrics.TelemetryMPPQueryCnt
 )
 
 // Session context, it is consistent with the lifecycle of a client connection.
@@ -152,6 +156,7 @@ type Session interface {
 	AuthWithoutVerification(user *auth.UserIdentity) bool
 	AuthPluginForUser(user *auth.UserIdentity) (string, error)
 	MatchIdentity(username, remoteHost string) (*auth.UserIdentity, error)
+	MatchCertUser(remoteHost string) (string, error)
 	ShowProcess() *util.ProcessInfo
 	// Return the information of the txn current running
 	TxnInfo() *txninfo.TxnInfo
@@ -209,6 +214,8 @@ type session struct {
 	store kv.Storage
 
 	preparedPlanCache *kvcache.SimpleLRUCache
+	// generalPlanCache maps the parameterized text protocol statements to their prepared statements.
+	generalPlanCache *kvcache.SimpleLRUCache
 
 	sessionVars    *variable.SessionVars
 	sessionManager util.SessionManager
@@ -552,12 +559,22 @@ func (s *session) doCommit(ctx context.Context) error {
 	relatedPhysicalTables := sessVars.TxnCtx.TableDeltaMap
 	// Get accessed temporary tables in the transaction.
 	temporaryTables := sessVars.TxnCtx.TemporaryTables
+	mdlTables, _ := sessVars.GetMDLTables()
+	revokedMDLTables := sessVars.GetRevokedMDLTables()
 	physicalTableIDs := make([]int64, 0, len(relatedPhysicalTables))
 	for id := range relatedPhysicalTables {
 		// Schema change on global temporary tables doesn't affect transactions.
 		if _, ok := temporaryTables[id]; ok {
 			continue
 		}
+		// The schema change has gone on without waiting for the transaction, which may write the table in an old schema.
+		if len(revokedMDLTables) > 0 && s.holdMDLOfPhysicalTable(revokedMDLTables, id) {
+			return errors.Trace(domain.ErrInfoSchemaChanged)
+		}
+		// The DDL on the tables whose metadata lock is held waits for the transaction, so they needn't be checked.
+		if len(mdlTables) > 0 && s.holdMDLOfPhysicalTable(mdlTables, id) {
+			continue
+		}
 		physicalTableIDs = append(physicalTableIDs, id)
 	}
 	// Set this option for 2 phase commit to validate schema lease.
@@ -599,7 +616,81 @@ func (s *session) doCommit(ctx context.Context) error {
 		s.txn.SetOption(kv.CommitTSUpperBoundCheck, c.commitTSCheck)
 	}
 
-	return s.commitTxnWithTemporaryData(tikvutil.SetSessionID(ctx, sessVars.ConnectionID), &s.txn)
+	sessVars.StmtCtx.SetWaitState(stmtctx.WaitStateCommit)
+	defer sessVars.StmtCtx.SetWaitState("")
+	err = s.commitTxnWithTemporaryData(tikvutil.SetSessionID(ctx, sessVars.ConnectionID), &s.txn)
+	return s.handleWriteConflict(err)
+}
+
+// handleWriteConflict records the write conflict met by the commit into the session's write conflict history, and
+// rewrites the error to describe the conflicting keys with the names of the tables and indexes they belong to.
+func (s *session) handleWriteConflict(err error) error {
+	if !kv.ErrWriteConflict.Equal(err) {
+		return err
+	}
+	terr, ok := errors.Cause(err).(*terror.Error)
+	if !ok {
+		return err
+	}
+	args := terr.Args()
+	if len(args) != 4 {
+		return err
+	}
+	// The keys are replaced by "?" when the log redaction is enabled.
+	keys, ok := args[3].(*kv.WriteConflictKeys)
+	if !ok {
+		return err
+	}
+	startTS, _ := args[0].(uint64)
+	conflictStartTS, _ := args[1].(uint64)
+	conflictCommitTS, _ := args[2].(uint64)
+
+	sessVars := s.GetSessionVars()
+	if sessVars.WriteConflictHistory == nil {
+		sessVars.WriteConflictHistory = writeconflicthistory.NewWriteConflictHistory(writeconflicthistory.DefaultCapacity)
+	}
+	sessVars.WriteConflictHistory.Push(&writeconflicthistory.WriteConflictRecord{
+		OccurTime:        time.Now(),
+		StartTS:          startTS,
+		ConflictStartTS:  conflictStartTS,
+		ConflictCommitTS: conflictCommitTS,
+		Key:              keys.Key,
+		Primary:          keys.Primary,
+		AutoRetry:        sessVars.RetryInfo.Retrying,
+	})
+
+	is := s.GetInfoSchema().(infoschema.InfoSchema)
+	decorated := &kv.WriteConflictKeys{
+		Key:         keys.Key,
+		Primary:     keys.Primary,
+		KeyDesc:     describeWriteConflictKey(keys.Key, keys.KeyDesc, is),
+		PrimaryDesc: describeWriteConflictKey(keys.Primary, keys.PrimaryDesc, is),
+	}
+	return kv.ErrWriteConflict.FastGenByArgs(startTS, conflictStartTS, conflictCommitTS, decorated)
+}
+
+// describeWriteConflictKey describes the key with the decoded table, index and row information. The original
+// description is returned if the key can't be decoded with the infoschema.
+func describeWriteConflictKey(key kv.Key, desc string, is infoschema.InfoSchema) string {
+	decoded, err := keydecoder.DecodeKey(key, is)
+	if err != nil || len(decoded.TableName) == 0 {
+		return desc
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "{tableID=%d, tableName=%s.%s", decoded.TableID, decoded.DbName, decoded.TableName)
+	if len(decoded.PartitionName) > 0 {
+		fmt.Fprintf(&buf, ", partitionName=%s", decoded.PartitionName)
+	}
+	if decoded.IndexID != 0 {
+		if len(decoded.IndexName) == 0 {
+			return desc
+		}
+		fmt.Fprintf(&buf, ", indexID=%d, indexName=%s, indexValues={%s}", decoded.IndexID, decoded.IndexName, strings.Join(decoded.IndexValues, ", "))
+	} else {
+		fmt.Fprintf(&buf, ", handle=%s", decoded.HandleValue)
+	}
+	buf.WriteString("}")
+	return buf.String()
 }
 
 type cachedTableRenewLease struct {
@@ -875,6 +966,11 @@ func (s *session) updateStatsDeltaToCollector() {
 			}
 		}
 	}
+	if s.statsCollector != nil {
+		for id, delta := range s.GetSessionVars().TxnCtx.IndexValueDeltaMap {
+			s.statsCollector.UpdateIndexValueDelta(id, delta)
+		}
+	}
 }
 
 func (s *session) CommitTxn(ctx context.Context) error {
@@ -898,9 +994,36 @@ func (s *session) CommitTxn(ctx context.Context) error {
 	})
 	s.sessionVars.TxnCtx.Cleanup()
 	s.sessionVars.CleanupTxnReadTSIfUsed()
+	s.releaseMDL()
 	return err
 }
 
+// holdMDLOfPhysicalTable returns if the metadata locks of the transaction cover the table or the partition.
+func (s *session) holdMDLOfPhysicalTable(mdlTables map[int64]int64, id int64) bool {
+	if _, ok := mdlTables[id]; ok {
+		return true
+	}
+	is, ok := s.sessionVars.TxnCtx.InfoSchema.(infoschema.InfoSchema)
+	if !ok {
+		return false
+	}
+	tbl, _, _ := is.FindTableByPartitionID(id)
+	if tbl == nil {
+		return false
+	}
+	_, ok = mdlTables[tbl.Meta().ID]
+	return ok
+}
+
+// releaseMDL releases the metadata locks held by the transaction.
+func (s *session) releaseMDL() {
+	if s.sessionVars.ReleaseMDL() {
+		if do := domain.GetDomain(s); do != nil {
+			do.UnregisterMDLHolder(s.sessionVars)
+		}
+	}
+}
+
 func (s *session) RollbackTxn(ctx context.Context) {
 	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
 		span1 := span.Tracer().StartSpan("session.RollbackTxn", opentracing.ChildOf(span.Context()))
@@ -917,6 +1040,7 @@ func (s *session) RollbackTxn(ctx context.Context) {
 	s.sessionVars.TxnCtx.Cleanup()
 	s.sessionVars.CleanupTxnReadTSIfUsed()
 	s.sessionVars.SetInTxn(false)
+	s.releaseMDL()
 }
 
 func (s *session) GetClient() kv.Client {
@@ -1340,6 +1464,7 @@ func (s *session) SetProcessInfo(sql string, t time.Time, command byte, maxExecu
 	pi := util.ProcessInfo{
 		ID:               s.sessionVars.ConnectionID,
 		Port:             s.sessionVars.Port,
+		SessionAlias:     s.sessionVars.SessionAlias,
 		DB:               s.sessionVars.CurrentDB,
 		Command:          command,
 		Plan:             p,
@@ -1697,13 +1822,23 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	if err := s.PrepareTxnCtx(ctx); err != nil {
 		return nil, err
 	}
+	s.preparePessimisticAutoCommitDML(stmtNode)
 
 	if err := s.loadCommonGlobalVariablesIfNeeded(); err != nil {
 		return nil, err
 	}
 
+	stmtNode, err := s.applySQLBlocklist(ctx, stmtNode)
+	if err != nil {
+		return nil, err
+	}
+
 	s.sessionVars.StartTime = time.Now()
 
+	if rs, ok, err := s.executeWithGeneralPlanCache(ctx, stmtNode); ok {
+		return rs, err
+	}
+
 	// Some executions are done in compile stage, so we reset them before compile.
 	if err := executor.ResetContextOfStmt(s, stmtNode); err != nil {
 		return nil, err
@@ -1967,6 +2102,28 @@ func (s *session) rollbackOnError(ctx context.Context) {
 	}
 }
 
+// applySQLBlocklist checks the statement against the rules of mysql.sql_blocklist. The blocked
+// statement returns an error, and the statement matching a rewrite rule is replaced by the
+// replacement of the rule.
+func (s *session) applySQLBlocklist(ctx context.Context, stmtNode ast.StmtNode) (ast.StmtNode, error) {
+	rule := executor.MatchSQLBlocklist(s, stmtNode)
+	if rule == nil {
+		return stmtNode, nil
+	}
+	if rule.Action != sqlblocklist.ActionRewrite {
+		return nil, executor.ErrSQLBlocked.GenWithStackByArgs(rule.Name, rule.Reason)
+	}
+	stmts, _, err := s.ParseSQL(ctx, rule.Replacement, s.sessionVars.GetParseParams()...)
+	if err != nil {
+		return nil, util.SyntaxError(err)
+	}
+	if len(stmts) != 1 {
+		return nil, executor.ErrSQLBlocked.GenWithStackByArgs(rule.Name, "the replacement must be a single statement")
+	}
+	logutil.Logger(ctx).Debug("statement rewritten by SQL blocklist", zap.String("rule", rule.Name))
+	return stmts[0], nil
+}
+
 // PrepareStmt is used for executing prepare statement in binary protocol
 func (s *session) PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error) {
 	if s.sessionVars.TxnCtx.InfoSchema == nil {
@@ -2150,12 +2307,17 @@ func (s *session) IsCachedExecOk(ctx context.Context, preparedStmt *plannercore.
 
 // ExecutePreparedStmt executes a prepared statement.
 func (s *session) ExecutePreparedStmt(ctx context.Context, stmtID uint32, args []types.Datum) (sqlexec.RecordSet, error) {
-	var err error
-	if err = s.PrepareTxnCtx(ctx); err != nil {
+	if err := s.PrepareTxnCtx(ctx); err != nil {
 		return nil, err
 	}
 
 	s.sessionVars.StartTime = time.Now()
+	return s.executePreparedStmt(ctx, stmtID, args)
+}
+
+// executePreparedStmt executes the prepared statement, the caller should prepare the txn context and set the start time.
+func (s *session) executePreparedStmt(ctx context.Context, stmtID uint32, args []types.Datum) (sqlexec.RecordSet, error) {
+	var err error
 	preparedPointer, ok := s.sessionVars.PreparedStmts[stmtID]
 	if !ok {
 		err = plannercore.ErrStmtNotFound
@@ -2193,6 +2355,7 @@ func (s *session) ExecutePreparedStmt(ctx context.Context, stmtID uint32, args [
 		return nil, err
 	}
 
+	s.preparePessimisticAutoCommitDML(preparedStmt.PreparedAst.Stmt)
 	executor.CountStmtNode(preparedStmt.PreparedAst.Stmt, s.sessionVars.InRestrictedSQL)
 	ok, err = s.IsCachedExecOk(ctx, preparedStmt)
 	if err != nil {
@@ -2230,7 +2393,10 @@ func (s *session) Txn(active bool) (kv.Transaction, error) {
 		// Transaction is lazy initialized.
 		// PrepareTxnCtx is called to get a tso future, makes s.txn a pending txn,
 		// If Txn() is called later, wait for the future to get a valid txn.
-		if err := s.txn.changePendingToValid(s.currentCtx); err != nil {
+		s.sessionVars.StmtCtx.SetWaitState(stmtctx.WaitStateTSO)
+		err := s.txn.changePendingToValid(s.currentCtx)
+		s.sessionVars.StmtCtx.SetWaitState("")
+		if err != nil {
 			logutil.BgLogger().Error("active transaction fail",
 				zap.Error(err))
 			s.txn.cleanup()
@@ -2404,6 +2570,7 @@ func (s *session) Close() {
 			logutil.BgLogger().Error("release table lock failed", zap.Uint64("conn", s.sessionVars.ConnectionID))
 		}
 	}
+	s.releaseMDL()
 	if s.statsCollector != nil {
 		s.statsCollector.Delete()
 	}
@@ -2449,7 +2616,16 @@ func (s *session) Auth(user *auth.UserIdentity, authentication []byte, salt []by
 	if err != nil {
 		return false
 	}
-	if pm.ConnectionVerification(authUser.Username, authUser.Hostname, authentication, salt, s.sessionVars.TLSConnectionState) {
+	success := pm.ConnectionVerification(authUser.Username, authUser.Hostname, authentication, salt, s.sessionVars.TLSConnectionState)
+	// Track the consecutive failed logins for FAILED_LOGIN_ATTEMPTS and PASSWORD_LOCK_TIME,
+	// a successful login resets the count.
+	if enabled, failedLoginCount := pm.GetFailedLoginTracking(authUser.Username, authUser.Hostname); enabled && (!success || failedLoginCount > 0) {
+		if err := s.updateFailedLoginCount(context.Background(), authUser.Username, authUser.Hostname, !success); err != nil {
+			logutil.BgLogger().Warn("failed to update the failed login count",
+				zap.String("user", authUser.Username), zap.String("host", authUser.Hostname), zap.Error(err))
+		}
+	}
+	if success {
 		user.AuthUsername = authUser.Username
 		user.AuthHostname = authUser.Hostname
 		s.sessionVars.User = user
@@ -2459,6 +2635,70 @@ func (s *session) Auth(user *auth.UserIdentity, authentication []byte, salt []by
 	return false
 }
 
+// updateFailedLoginCount counts a failed login of the user or resets the count after a successful login.
+// The Password_locking of mysql.user is updated in a pessimistic transaction, so that concurrent logins
+// are counted correctly, and then the privileges are reloaded to make an automatic lock take effect.
+func (s *session) updateFailedLoginCount(ctx context.Context, user, host string, failed bool) (err error) {
+	tmp, err := s.sysSessionPool().Get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer s.sysSessionPool().Put(tmp)
+	se := tmp.(*session)
+
+	if _, err = se.ExecuteInternal(ctx, "BEGIN PESSIMISTIC"); err != nil {
+		return errors.Trace(err)
+	}
+	changed := false
+	defer func() {
+		if err != nil || !changed {
+			if _, rollbackErr := se.ExecuteInternal(ctx, "ROLLBACK"); rollbackErr != nil && err == nil {
+				err = rollbackErr
+			}
+			return
+		}
+		if _, err = se.ExecuteInternal(ctx, "COMMIT"); err == nil {
+			err = domain.GetDomain(s).NotifyUpdatePrivilege()
+		}
+	}()
+
+	rs, err := se.ExecuteInternal(ctx, "SELECT user_attributes FROM %n.%n WHERE User=%? AND Host=%? FOR UPDATE", mysql.SystemDB, mysql.UserTable, user, host)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, err := drainRecordSet(ctx, se, rs, nil)
+	terror.Call(rs.Close)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) != 1 || rows[0].IsNull(0) {
+		return nil
+	}
+	passwordLocking, err := privileges.ParsePasswordLocking(rows[0].GetJSON(0).String())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	origin := passwordLocking
+	if failed {
+		passwordLocking.OnFailedLogin(time.Now())
+	} else {
+		passwordLocking.Reset()
+	}
+	if passwordLocking == origin {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]privileges.PasswordLocking{"Password_locking": passwordLocking})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err = se.ExecuteInternal(ctx, "UPDATE %n.%n SET user_attributes=JSON_MERGE_PATCH(user_attributes, %?) WHERE User=%? AND Host=%?",
+		mysql.SystemDB, mysql.UserTable, string(patch), user, host); err != nil {
+		return errors.Trace(err)
+	}
+	changed = true
+	return nil
+}
+
 // MatchIdentity finds the matching username + password in the MySQL privilege tables
 // for a username + hostname, since MySQL can have wildcards.
 func (s *session) MatchIdentity(username, remoteHost string) (*auth.UserIdentity, error) {
@@ -2478,6 +2718,16 @@ func (s *session) MatchIdentity(username, remoteHost string) (*auth.UserIdentity
 	return nil, fmt.Errorf("could not find matching user in MatchIdentity: %s, %s", username, remoteHost)
 }
 
+// MatchCertUser finds the user whose REQUIRE SAN matches the verified client certificate of the connection.
+func (s *session) MatchCertUser(remoteHost string) (string, error) {
+	pm := privilege.GetPrivilegeManager(s)
+	if username, success := pm.MatchCertUser(remoteHost, s.sessionVars.TLSConnectionState); success {
+		return username, nil
+	}
+	// This error will not be returned to the user, access denied will be instead
+	return "", fmt.Errorf("could not find matching user in MatchCertUser: %s", remoteHost)
+}
+
 // AuthWithoutVerification is required by the ResetConnection RPC
 func (s *session) AuthWithoutVerification(user *auth.UserIdentity) bool {
 	pm := privilege.GetPrivilegeManager(s)
@@ -2735,14 +2985,28 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 		return nil, err
 	}
 
+	se7, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	err = dom.LoadSQLBlocklistLoop(se7, executor.LoadSQLBlocklist)
+	if err != nil {
+		return nil, err
+	}
+
+	err = executor.LoadTimeZoneTables(se6)
+	if err != nil {
+		return nil, err
+	}
+
 	dom.TelemetryReportLoop(se6)
 	dom.TelemetryRotateSubWindowLoop(se6)
 
-	se7, err := createSession(store)
+	se8, err := createSession(store)
 	if err != nil {
 		return nil, err
 	}
-	err = dom.UpdateTableStatsLoop(se7)
+	err = dom.UpdateTableStatsLoop(se8)
 	if err != nil {
 		return nil, err
 	}
@@ -2761,6 +3025,11 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 
 	dom.PlanReplayerLoop()
 
+	// The initialize-sql-file and the upgrade hooks are executed after the privileges and the system variables are loaded.
+	if err = doPendingBootstrapWorks(store); err != nil {
+		return nil, err
+	}
+
 	if raw, ok := store.(kv.EtcdBackend); ok {
 		err = raw.StartGCWorker()
 		if err != nil {
@@ -2979,6 +3248,22 @@ func (s *session) PrepareTxnCtx(ctx context.Context) error {
 	return sessiontxn.GetTxnManager(s).SetContextProvider(txnCtxProvider)
 }
 
+// preparePessimisticAutoCommitDML runs the auto-committed DML statement in a pessimistic transaction when
+// tidb_lock_wait_timeout_retry_limit is set. An optimistic auto-committed statement doesn't wait for the
+// conflicting pessimistic lock, it fails on the lock when committing. In a pessimistic transaction, the
+// statement waits for the lock up to innodb_lock_wait_timeout and the lock wait timeout can be retried.
+func (s *session) preparePessimisticAutoCommitDML(stmtNode ast.StmtNode) {
+	sessVars := s.sessionVars
+	if sessVars.LockWaitTimeoutRetryLimit <= 0 || sessVars.TxnMode != ast.Pessimistic ||
+		!sessVars.IsAutocommit() || sessVars.InTxn() || s.txn.validOrPending() {
+		return
+	}
+	switch stmtNode.(type) {
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+		sessVars.TxnCtx.IsPessimistic = true
+	}
+}
+
 // PrepareTSFuture uses to try to get ts future.
 func (s *session) PrepareTSFuture(ctx context.Context) {
 	if s.sessionVars.SnapshotTS != 0 {
@@ -3109,6 +3394,7 @@ func logGeneralQuery(execStmt *executor.ExecStmt, s *session, isPrepared bool) {
 		}
 		logutil.BgLogger().Info("GENERAL_LOG",
 			zap.Uint64("conn", vars.ConnectionID),
+			zap.String("session_alias", vars.SessionAlias),
 			zap.Stringer("user", vars.User),
 			zap.Int64("schemaVersion", s.GetInfoSchema().SchemaMetaVersion()),
 			zap.Uint64("txnStartTS", vars.TxnCtx.StartTS),
@@ -3295,6 +3581,9 @@ func (s *session) updateTelemetryMetric(es *executor.ExecStmt) {
 	} else {
 		telemetryCTEUsage.WithLabelValues("notCTE").Inc()
 	}
+	if ti.UseMPP {
+		telemetryMPPUsage.Inc()
+	}
 }
 
 func (s *session) GetBuiltinFunctionUsage() map[string]uint32 {
@@ -21,6 +21,7 @@ import (
 	"net"
 	"os"
 	"path"
+	"regexp"
 	"runtime"
 	"sort"
 	"strconv"
@@ -3126,6 +3127,43 @@ func (s *testSchemaSuite) TestDisableTxnAutoRetry(c *C) {
 	tk2.MustQuery("select * from no_retry").Check(testkit.Rows("13"))
 }
 
+func (s *testSchemaSuite) TestWriteConflictKeyInfo(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TxnLocalLatches.Enabled = false
+	})
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	tk1.MustExec("create table write_conflict (id int primary key, v int)")
+	tk1.MustExec("insert into write_conflict values (1, 1)")
+	tk1.MustExec("set @@tidb_disable_txn_auto_retry = 1")
+	tk1.MustQuery("select count(*) from information_schema.session_write_conflicts").Check(testkit.Rows("0"))
+
+	tk1.MustExec("begin optimistic")
+	tk1.MustExec("update write_conflict set v = 2 where id = 1")
+	tk2.MustExec("update write_conflict set v = 3 where id = 1")
+	_, err := tk1.Se.Execute(context.Background(), "commit")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("error: %s", err))
+	tbl, err1 := domain.GetDomain(tk1.Se).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("write_conflict"))
+	c.Assert(err1, IsNil)
+	keyDesc := fmt.Sprintf("{tableID=%d, tableName=test.write_conflict, handle=1}", tbl.Meta().ID)
+	c.Assert(err.Error(), Matches, fmt.Sprintf(".*key=%s primary=.*", regexp.QuoteMeta(keyDesc)))
+
+	// The conflict is kept in the session's write conflict history, but not in other sessions'.
+	tk1.MustQuery("select id, start_ts > 0, conflict_commit_ts > conflict_start_ts, `key` is not null, " +
+		"json_extract(key_info, '$.table_name'), json_extract(key_info, '$.handle_value'), auto_retry " +
+		"from information_schema.session_write_conflicts").Check(testkit.Rows(`1 1 1 1 "write_conflict" "1" 0`))
+	tk2.MustQuery("select count(*) from information_schema.session_write_conflicts").Check(testkit.Rows("0"))
+
+	tk1.MustExec("begin optimistic")
+	tk1.MustExec("update write_conflict set v = 4 where id = 1")
+	tk2.MustExec("update write_conflict set v = 5 where id = 1")
+	_, err = tk1.Se.Execute(context.Background(), "commit")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("error: %s", err))
+	tk1.MustQuery("select id from information_schema.session_write_conflicts").Check(testkit.Rows("1", "2"))
+	tk1.MustQuery("select * from write_conflict").Check(testkit.Rows("1 5"))
+}
+
 // TestSetGroupConcatMaxLen is for issue #7034
 func (s *testSessionSuite2) TestSetGroupConcatMaxLen(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
@@ -3456,6 +3494,15 @@ func (s *testSessionSuite2) TestStmtHints(c *C) {
 	tk.MustExec("select /*+ READ_CONSISTENT_REPLICA(), READ_CONSISTENT_REPLICA() */ 1;")
 	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
 	c.Assert(tk.Se.GetSessionVars().GetReplicaRead(), Equals, kv.ReplicaReadFollower)
+
+	// Test IGNORE_COPR_CACHE hint
+	tk.MustExec("select /*+ IGNORE_COPR_CACHE() */ 1;")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IgnoreCoprCacheHint, IsTrue)
+	tk.MustExec("select /*+ IGNORE_COPR_CACHE(), IGNORE_COPR_CACHE() */ 1;")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IgnoreCoprCacheHint, IsTrue)
+	tk.MustExec("select 1;")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IgnoreCoprCacheHint, IsFalse)
 }
 
 func (s *testSessionSuite3) TestPessimisticLockOnPartition(c *C) {
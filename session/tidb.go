@@ -247,6 +247,10 @@ func finishStmt(ctx context.Context, se *session, meetsErr error, sql sqlexec.St
 		// Reset txn state to invalid to dispose the pending start ts.
 		se.txn.changeToInvalid()
 	}
+	if !sessVars.InTxn() {
+		// The statement runs out of a transaction, release the metadata locks it acquires.
+		se.releaseMDL()
+	}
 	if err != nil {
 		return err
 	}
@@ -297,6 +297,13 @@ func (txn *LazyTxn) onStmtEnd() {
 	txn.mu.Unlock()
 }
 
+// onTxnEnd records the transaction into the transaction summary before it's reset.
+func (txn *LazyTxn) onTxnEnd() {
+	txn.mu.RLock()
+	defer txn.mu.RUnlock()
+	txninfo.Recorder.OnTrxEnd(&txn.mu.TxnInfo)
+}
+
 var hasMockAutoIncIDRetry = int64(0)
 
 func enableMockAutoIncIDRetry() {
@@ -326,6 +333,7 @@ func ResetMockAutoRandIDRetryCount(failTimes int64) {
 // Commit overrides the Transaction interface.
 func (txn *LazyTxn) Commit(ctx context.Context) error {
 	defer txn.reset()
+	defer txn.onTxnEnd()
 	if len(txn.mutations) != 0 || txn.countHint() != 0 {
 		logutil.BgLogger().Error("the code should never run here",
 			zap.String("TxnState", txn.GoString()),
@@ -368,6 +376,7 @@ func (txn *LazyTxn) Commit(ctx context.Context) error {
 // Rollback overrides the Transaction interface.
 func (txn *LazyTxn) Rollback() error {
 	defer txn.reset()
+	defer txn.onTxnEnd()
 	txn.mu.Lock()
 	txn.mu.TxnInfo.State = txninfo.TxnRollingBack
 	txn.mu.Unlock()
@@ -541,6 +550,8 @@ func (s *session) StmtCommit() {
 
 	st := &s.txn
 	st.flushStmtBuf()
+	s.sessionVars.TxnCtx.EndStmtPresentUniqueKeys(true)
+	s.sessionVars.TxnCtx.EndStmtIndexValueDelta(true)
 
 	// Need to flush binlog.
 	for tableID, delta := range st.mutations {
@@ -552,6 +563,8 @@ func (s *session) StmtCommit() {
 // StmtRollback implements the sessionctx.Context interface.
 func (s *session) StmtRollback() {
 	s.txn.cleanup()
+	s.sessionVars.TxnCtx.EndStmtPresentUniqueKeys(false)
+	s.sessionVars.TxnCtx.EndStmtIndexValueDelta(false)
 }
 
 // StmtGetMutation implements the sessionctx.Context interface.
@@ -0,0 +1,159 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txninfo
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+// trxDigest calculates the digest of a transaction from the digests of the SQL statements it executed.
+func trxDigest(digests []string) uint64 {
+	hash := fnv.New64a()
+	for _, digest := range digests {
+		// The length of the SQL digests is fixed, so they're concatenated without separators.
+		_, _ = hash.Write([]byte(digest))
+	}
+	return hash.Sum64()
+}
+
+// trxSummaryEntry is a kind of transactions which executed the same SQL statements.
+type trxSummaryEntry struct {
+	digest        uint64
+	allSQLDigests []string
+}
+
+// trxSummaries keeps the most recently finished kinds of transactions in LRU order.
+type trxSummaries struct {
+	capacity uint
+	elements map[uint64]*list.Element
+	cache    *list.List
+}
+
+func newTrxSummaries(capacity uint) trxSummaries {
+	return trxSummaries{
+		capacity: capacity,
+		elements: make(map[uint64]*list.Element),
+		cache:    list.New(),
+	}
+}
+
+func (s *trxSummaries) onTrxEnd(digests []string) {
+	key := trxDigest(digests)
+	if element, ok := s.elements[key]; ok {
+		s.cache.MoveToFront(element)
+		return
+	}
+	entry := trxSummaryEntry{digest: key, allSQLDigests: append([]string(nil), digests...)}
+	s.elements[key] = s.cache.PushFront(entry)
+	s.shrink()
+}
+
+func (s *trxSummaries) shrink() {
+	for uint(s.cache.Len()) > s.capacity {
+		last := s.cache.Back()
+		delete(s.elements, last.Value.(trxSummaryEntry).digest)
+		s.cache.Remove(last)
+	}
+}
+
+func (s *trxSummaries) dump() [][]types.Datum {
+	result := make([][]types.Datum, 0, s.cache.Len())
+	for element := s.cache.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(trxSummaryEntry)
+		allSQLDigests, err := json.Marshal(entry.allSQLDigests)
+		if err != nil {
+			logutil.BgLogger().Warn("Failed to marshal sql digests list as json", zap.Uint64("trxDigest", entry.digest))
+			continue
+		}
+		result = append(result, []types.Datum{
+			types.NewDatum(fmt.Sprintf("%016x", entry.digest)),
+			types.NewDatum(string(allSQLDigests)),
+		})
+	}
+	return result
+}
+
+// TrxHistoryRecorder records the finished transactions for the `TRX_SUMMARY` table. All its public APIs are
+// thread safe.
+type TrxHistoryRecorder struct {
+	mu          sync.Mutex
+	minDuration time.Duration
+	summaries   trxSummaries
+}
+
+// NewTrxHistoryRecorder creates a TrxHistoryRecorder which keeps at most capacity kinds of transactions.
+func NewTrxHistoryRecorder(capacity uint) *TrxHistoryRecorder {
+	return &TrxHistoryRecorder{summaries: newTrxSummaries(capacity)}
+}
+
+// Recorder is the global instance of TrxHistoryRecorder.
+// The real capacity and min duration should be initialized in `setGlobalVars` in tidb-server/main.go.
+var Recorder = NewTrxHistoryRecorder(0)
+
+// OnTrxEnd records a transaction when it's committed or rolled back. The transactions which executed no SQL
+// statements, or ran shorter than the min duration, are ignored.
+func (r *TrxHistoryRecorder) OnTrxEnd(info *TxnInfo) {
+	if len(info.AllSQLDigests) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.summaries.capacity == 0 {
+		return
+	}
+	if r.minDuration > 0 && time.Since(oracle.GetTimeFromTS(info.StartTS)) < r.minDuration {
+		return
+	}
+	r.summaries.onTrxEnd(info.AllSQLDigests)
+}
+
+// DumpTrxSummary returns the rows of the `TRX_SUMMARY` table, the most recently finished ones come first.
+func (r *TrxHistoryRecorder) DumpTrxSummary() [][]types.Datum {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.summaries.dump()
+}
+
+// Resize updates the max count of the kinds of transactions kept by the recorder.
+func (r *TrxHistoryRecorder) Resize(capacity uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.summaries.capacity = capacity
+	r.summaries.shrink()
+}
+
+// SetMinDuration sets how long a transaction should run to be recorded.
+func (r *TrxHistoryRecorder) SetMinDuration(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.minDuration = d
+}
+
+// Clean removes all the recorded transactions.
+func (r *TrxHistoryRecorder) Clean() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.summaries = newTrxSummaries(r.summaries.capacity)
+}
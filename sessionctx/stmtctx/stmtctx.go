@@ -15,14 +15,17 @@
 package stmtctx
 
 import (
+	"fmt"
 	"math"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/util/disk"
@@ -98,6 +101,9 @@ type StatementContext struct {
 	// or is affected by the tidb_read_staleness session variable, then the statement will be makred as isStaleness
 	// in stmtCtx
 	IsStaleness bool
+	// TableSnapshotTS records the snapshots of the tables which are read with a different AS OF TIMESTAMP
+	// from the statement.
+	TableSnapshotTS map[*ast.TableName]uint64
 	// mu struct holds variables that change during execution.
 	mu struct {
 		sync.Mutex
@@ -129,6 +135,7 @@ type StatementContext struct {
 		errorCount     uint16
 		execDetails    execdetails.ExecDetails
 		allExecDetails []*execdetails.ExecDetails
+		slowRPCs       []SlowRPCDetail
 	}
 	// PrevAffectedRows is the affected-rows value(DDL is 0, DML is the number of affected rows).
 	PrevAffectedRows int64
@@ -171,6 +178,7 @@ type StatementContext struct {
 	Tables                []TableEntry
 	PointExec             bool  // for point update cached execution, Constant expression need to set "paramMarker"
 	lockWaitStartTime     int64 // LockWaitStartTime stores the pessimistic lock wait start time
+	waitState             atomic2.String
 	PessimisticLockWaited int32
 	LockKeysDuration      int64
 	LockKeysCount         int32
@@ -204,12 +212,17 @@ type StatementContext struct {
 
 	// EnableOptimizeTrace indicates whether enable optimizer trace by 'trace plan statement'
 	EnableOptimizeTrace bool
+	// OptimizeTraceTarget indicates which part of the optimization is traced by 'trace plan target = xxx statement',
+	// an empty target means both the logical and physical optimization are traced.
+	OptimizeTraceTarget string
 	// OptimizeTracer indicates the tracer for optimize
 	OptimizeTracer *tracing.OptimizeTracer
 	// EnableOptimizerCETrace indicate if cardinality estimation internal process needs to be traced.
 	// CE Trace is currently a submodule of the optimizer trace and is controlled by a separated option.
 	EnableOptimizerCETrace bool
 	OptimizerCETrace       []*tracing.CETraceRecord
+	// OptimizerCETraceDetails collects the details of the estimation which is not recorded into OptimizerCETrace yet.
+	OptimizerCETraceDetails []string
 
 	// WaitLockLeaseTime is the duration of cached table read lease expiration time.
 	WaitLockLeaseTime time.Duration
@@ -223,6 +236,14 @@ type StatementContext struct {
 	// WeakConsistency is true when read consistency is weak and in a read statement and not in a transaction.
 	WeakConsistency bool
 
+	// UseClosestReplica is true when tidb_replica_read is closest-adaptive and the estimated response size
+	// of the statement exceeds tidb_adaptive_closest_read_threshold.
+	UseClosestReplica bool
+
+	// MaxChunkSize is the max row count of the Chunks of all the executors of the statement, it's computed from
+	// the widest rows of the plan and tidb_max_chunk_bytes. 0 means tidb_max_chunk_size is used.
+	MaxChunkSize int
+
 	StatsLoad struct {
 		// Timeout to wait for sync-load
 		Timeout time.Duration
@@ -246,6 +267,8 @@ type StmtHints struct {
 	ReplicaRead             byte
 	AllowInSubqToJoinAndAgg bool
 	NoIndexMergeHint        bool
+	// IgnoreCoprCacheHint indicates the coprocessor cache is not used by the statement.
+	IgnoreCoprCacheHint bool
 	// EnableCascadesPlanner is use cascades planner for a single query only.
 	EnableCascadesPlanner bool
 	// ForceNthPlan indicates the PlanCounterTp number for finding physical plan.
@@ -629,6 +652,7 @@ func (sc *StatementContext) resetMuForRetry() {
 	sc.mu.warnings = nil
 	sc.mu.execDetails = execdetails.ExecDetails{}
 	sc.mu.allExecDetails = make([]*execdetails.ExecDetails, 0, 4)
+	sc.mu.slowRPCs = nil
 }
 
 // ResetForRetry resets the changed states during execution.
@@ -663,6 +687,41 @@ func (sc *StatementContext) MergeExecDetails(details *execdetails.ExecDetails, c
 	}
 }
 
+// RecordSlowRPC records a coprocessor RPC which exceeds tidb_slow_rpc_threshold, used to print
+// the information in slow query log. Only the slowest maxSlowRPCRecords RPCs are kept.
+func (sc *StatementContext) RecordSlowRPC(detail SlowRPCDetail) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if len(sc.mu.slowRPCs) < maxSlowRPCRecords {
+		sc.mu.slowRPCs = append(sc.mu.slowRPCs, detail)
+		return
+	}
+	fastest := 0
+	for i := range sc.mu.slowRPCs {
+		if sc.mu.slowRPCs[i].RespTime < sc.mu.slowRPCs[fastest].RespTime {
+			fastest = i
+		}
+	}
+	if detail.RespTime > sc.mu.slowRPCs[fastest].RespTime {
+		sc.mu.slowRPCs[fastest] = detail
+	}
+}
+
+// SlowRPCs returns the recorded slow coprocessor RPCs, the slowest one comes first.
+func (sc *StatementContext) SlowRPCs() []SlowRPCDetail {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if len(sc.mu.slowRPCs) == 0 {
+		return nil
+	}
+	rpcs := make([]SlowRPCDetail, len(sc.mu.slowRPCs))
+	copy(rpcs, sc.mu.slowRPCs)
+	sort.SliceStable(rpcs, func(i, j int) bool {
+		return rpcs[i].RespTime > rpcs[j].RespTime
+	})
+	return rpcs
+}
+
 // MergeScanDetail merges scan details into self.
 func (sc *StatementContext) MergeScanDetail(scanDetail *util.ScanDetail) {
 	// Currently TiFlash cop task does not fill scanDetail, so need to skip it if scanDetail is nil
@@ -692,6 +751,61 @@ func (sc *StatementContext) MergeLockKeysExecDetails(lockKeys *util.LockKeysDeta
 	}
 }
 
+// Wait states of the statement, they are shown in the processlist.
+const (
+	WaitStateTSO    = "tso"
+	WaitStateLock   = "lock"
+	WaitStateCommit = "commit"
+)
+
+// SetWaitState sets what the statement is waiting for, an empty state means it is not waiting.
+func (sc *StatementContext) SetWaitState(state string) {
+	sc.waitState.Store(state)
+}
+
+// GetWaitState gets what the statement is waiting for.
+func (sc *StatementContext) GetWaitState() string {
+	return sc.waitState.Load()
+}
+
+// GetRequestUnits estimates the request units consumed by the statement so far.
+func (sc *StatementContext) GetRequestUnits() float64 {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.mu.execDetails.RequestUnits()
+}
+
+// GetBackoffTypes returns the backoff types the statement has met so far, along with the
+// times and the total sleep time of each type, e.g. `regionMiss:2(3ms), tikvRPC:1(2ms)`.
+func (sc *StatementContext) GetBackoffTypes() string {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	times := make(map[string]int)
+	sleep := make(map[string]time.Duration)
+	for _, ed := range sc.mu.allExecDetails {
+		for backoff, n := range ed.BackoffTimes {
+			times[backoff] += n
+			sleep[backoff] += ed.BackoffSleep[backoff]
+		}
+	}
+	if len(times) == 0 {
+		return ""
+	}
+	backoffs := make([]string, 0, len(times))
+	for backoff := range times {
+		backoffs = append(backoffs, backoff)
+	}
+	sort.Strings(backoffs)
+	var buf strings.Builder
+	for i, backoff := range backoffs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "%s:%d(%s)", backoff, times[backoff], sleep[backoff])
+	}
+	return buf.String()
+}
+
 // GetExecDetails gets the execution details for the statement.
 func (sc *StatementContext) GetExecDetails() execdetails.ExecDetails {
 	var details execdetails.ExecDetails
@@ -844,6 +958,18 @@ func (sc *StatementContext) GetLockWaitStartTime() time.Time {
 	return time.Unix(0, startTime)
 }
 
+// maxSlowRPCRecords is the max number of the slow RPCs recorded for a statement.
+const maxSlowRPCRecords = 10
+
+// SlowRPCDetail is the detail of a coprocessor RPC which exceeds tidb_slow_rpc_threshold.
+type SlowRPCDetail struct {
+	RegionID    uint64
+	StoreAddr   string
+	RespTime    time.Duration
+	ProcessTime time.Duration
+	WaitTime    time.Duration
+}
+
 // CopTasksDetails collects some useful information of cop-tasks during execution.
 type CopTasksDetails struct {
 	NumCopTasks int
@@ -69,6 +69,28 @@ func TestCopTasksDetails(t *testing.T) {
 	}
 }
 
+func TestSlowRPCs(t *testing.T) {
+	ctx := new(stmtctx.StatementContext)
+	require.Nil(t, ctx.SlowRPCs())
+	for i := 0; i < 20; i++ {
+		// The RPCs are recorded in the order of 10, 11, ..., 19, 0, 1, ..., 9 milliseconds.
+		respTime := time.Millisecond * time.Duration((i+10)%20)
+		ctx.RecordSlowRPC(stmtctx.SlowRPCDetail{
+			RegionID:  uint64(i),
+			StoreAddr: fmt.Sprintf("store%v", i%3),
+			RespTime:  respTime,
+		})
+	}
+	rpcs := ctx.SlowRPCs()
+	require.Len(t, rpcs, 10)
+	for i, rpc := range rpcs {
+		require.Equal(t, time.Millisecond*time.Duration(19-i), rpc.RespTime)
+		require.Equal(t, uint64(9-i), rpc.RegionID)
+	}
+	ctx.ResetForRetry()
+	require.Nil(t, ctx.SlowRPCs())
+}
+
 func TestStatementContextPushDownFLags(t *testing.T) {
 	testCases := []struct {
 		in  *stmtctx.StatementContext
@@ -51,6 +51,7 @@ import (
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/tableutil"
 	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/pingcap/tidb/util/writeconflicthistory"
 	tikvstore "github.com/tikv/client-go/v2/kv"
 	"github.com/tikv/client-go/v2/oracle"
 	"github.com/twmb/murmur3"
@@ -149,6 +150,13 @@ type TransactionContext struct {
 	// Note: for the partitioned table, it stores all the partition IDs.
 	TableDeltaMap map[int64]TableDelta
 
+	// IndexValueDeltaMap records the index values changed by DML for every physical table, it's only collected
+	// when tidb_enable_stats_sketch_maintenance is on and used to maintain the index statistics incrementally.
+	// stmtIndexValueDeltaMap holds the values changed by the running statement, which are merged into
+	// IndexValueDeltaMap if the statement is committed, or dropped if the statement is rolled back.
+	IndexValueDeltaMap     map[int64]IndexValueDelta
+	stmtIndexValueDeltaMap map[int64]IndexValueDelta
+
 	// unchangedRowKeys is used to store the unchanged rows that needs to lock for pessimistic transaction.
 	unchangedRowKeys map[string]struct{}
 
@@ -157,6 +165,12 @@ type TransactionContext struct {
 	pessimisticLockCache map[string][]byte
 	PessimisticCacheHit  int
 
+	// presentUniqueKeys is the presence filter of the unique keys known to exist in the transaction, the INSERT IGNORE
+	// statements skip the rows conflicting with them without looking up the keys again. stmtPresentUniqueKeys holds
+	// the keys found by the running statement, which are dropped if the statement is rolled back.
+	presentUniqueKeys     map[string]struct{}
+	stmtPresentUniqueKeys map[string]struct{}
+
 	// CreateTime For metrics.
 	CreateTime     time.Time
 	StatementCount int
@@ -246,6 +260,103 @@ func (tc *TransactionContext) UpdateDeltaForTable(physicalTableID int64, delta i
 		item.ColSize[key] += val
 	}
 	tc.TableDeltaMap[physicalTableID] = item
+	// The updated or deleted rows may remove the keys in the presence filter.
+	if count > 0 && delta <= 0 {
+		tc.presentUniqueKeys = nil
+		tc.stmtPresentUniqueKeys = nil
+	}
+}
+
+// MaxIndexValueDeltas is the max number of the distinct values recorded for one index, the index is not
+// maintained if more values are changed, which bounds the memory and the CPU used by the statistics maintenance.
+const MaxIndexValueDeltas = 4096
+
+// UpdateIndexValueDeltaForTable updates the delta count of the encoded index value changed by the running statement.
+func (tc *TransactionContext) UpdateIndexValueDeltaForTable(physicalTableID int64, indexID int64, value []byte, delta int64) {
+	tc.tdmLock.Lock()
+	defer tc.tdmLock.Unlock()
+	if tc.stmtIndexValueDeltaMap == nil {
+		tc.stmtIndexValueDeltaMap = make(map[int64]IndexValueDelta)
+	}
+	item := tc.stmtIndexValueDeltaMap[physicalTableID]
+	if item == nil {
+		item = make(IndexValueDelta)
+		tc.stmtIndexValueDeltaMap[physicalTableID] = item
+	}
+	item.Update(indexID, string(value), delta)
+}
+
+// IsIndexValueDeltaOverflowed checks whether the running statement has changed too many values of the index,
+// then the values of the index needn't be encoded and recorded any more.
+func (tc *TransactionContext) IsIndexValueDeltaOverflowed(physicalTableID int64, indexID int64) bool {
+	tc.tdmLock.Lock()
+	defer tc.tdmLock.Unlock()
+	return tc.stmtIndexValueDeltaMap[physicalTableID].Overflowed(indexID)
+}
+
+// EndStmtIndexValueDelta merges the index values changed by the statement into IndexValueDeltaMap if the
+// statement is committed, or drops them if the statement is rolled back.
+func (tc *TransactionContext) EndStmtIndexValueDelta(commit bool) {
+	tc.tdmLock.Lock()
+	defer tc.tdmLock.Unlock()
+	if commit && len(tc.stmtIndexValueDeltaMap) > 0 {
+		if tc.IndexValueDeltaMap == nil {
+			tc.IndexValueDeltaMap = make(map[int64]IndexValueDelta, len(tc.stmtIndexValueDeltaMap))
+		}
+		for id, delta := range tc.stmtIndexValueDeltaMap {
+			item := tc.IndexValueDeltaMap[id]
+			if item == nil {
+				item = make(IndexValueDelta)
+				tc.IndexValueDeltaMap[id] = item
+			}
+			item.Merge(delta)
+		}
+	}
+	tc.stmtIndexValueDeltaMap = nil
+}
+
+// maxPresentUniqueKeys is the max number of keys in the presence filter of a transaction.
+const maxPresentUniqueKeys = 1 << 20
+
+// AddPresentUniqueKey records the unique key which is known to exist in the transaction.
+func (tc *TransactionContext) AddPresentUniqueKey(key kv.Key) {
+	tc.tdmLock.Lock()
+	defer tc.tdmLock.Unlock()
+	if len(tc.presentUniqueKeys)+len(tc.stmtPresentUniqueKeys) >= maxPresentUniqueKeys {
+		return
+	}
+	if tc.stmtPresentUniqueKeys == nil {
+		tc.stmtPresentUniqueKeys = map[string]struct{}{}
+	}
+	tc.stmtPresentUniqueKeys[string(key)] = struct{}{}
+}
+
+// IsUniqueKeyPresent checks whether the unique key is known to exist in the transaction.
+func (tc *TransactionContext) IsUniqueKeyPresent(key kv.Key) bool {
+	tc.tdmLock.Lock()
+	defer tc.tdmLock.Unlock()
+	if _, ok := tc.stmtPresentUniqueKeys[string(key)]; ok {
+		return true
+	}
+	_, ok := tc.presentUniqueKeys[string(key)]
+	return ok
+}
+
+// EndStmtPresentUniqueKeys merges the unique keys found by the statement into the presence filter if the
+// statement is committed, or drops them if the statement is rolled back.
+func (tc *TransactionContext) EndStmtPresentUniqueKeys(commit bool) {
+	tc.tdmLock.Lock()
+	defer tc.tdmLock.Unlock()
+	if commit && len(tc.stmtPresentUniqueKeys) > 0 {
+		if tc.presentUniqueKeys == nil {
+			tc.presentUniqueKeys = tc.stmtPresentUniqueKeys
+		} else {
+			for key := range tc.stmtPresentUniqueKeys {
+				tc.presentUniqueKeys[key] = struct{}{}
+			}
+		}
+	}
+	tc.stmtPresentUniqueKeys = nil
 }
 
 // GetKeyInPessimisticLockCache gets a key in pessimistic lock cache.
@@ -275,6 +386,10 @@ func (tc *TransactionContext) Cleanup() {
 	tc.History = nil
 	tc.tdmLock.Lock()
 	tc.TableDeltaMap = nil
+	tc.IndexValueDeltaMap = nil
+	tc.stmtIndexValueDeltaMap = nil
+	tc.presentUniqueKeys = nil
+	tc.stmtPresentUniqueKeys = nil
 	tc.tdmLock.Unlock()
 	tc.pessimisticLockCache = nil
 	tc.IsStaleness = false
@@ -284,6 +399,8 @@ func (tc *TransactionContext) Cleanup() {
 func (tc *TransactionContext) ClearDelta() {
 	tc.tdmLock.Lock()
 	tc.TableDeltaMap = nil
+	tc.IndexValueDeltaMap = nil
+	tc.stmtIndexValueDeltaMap = nil
 	tc.tdmLock.Unlock()
 }
 
@@ -456,6 +573,50 @@ func validateReadConsistencyLevel(val string) error {
 	}
 }
 
+// TableMappingTarget is the table a mapped table reference is routed to.
+type TableMappingTarget struct {
+	Schema model.CIStr
+	Name   model.CIStr
+}
+
+// parseTableMapping parses the value of tidb_table_mapping.
+func parseTableMapping(val string) (map[string]TableMappingTarget, error) {
+	mapping := make(map[string]TableMappingTarget)
+	parseTableName := func(name string) (model.CIStr, model.CIStr, bool) {
+		parts := strings.Split(strings.TrimSpace(name), ".")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return model.CIStr{}, model.CIStr{}, false
+		}
+		return model.NewCIStr(parts[0]), model.NewCIStr(parts[1]), true
+	}
+	for _, item := range strings.Split(val, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		pair := strings.Split(item, "=")
+		if len(pair) != 2 {
+			return nil, ErrWrongValueForVar.GenWithStackByArgs(TiDBTableMapping, val)
+		}
+		fromSchema, fromName, ok1 := parseTableName(pair[0])
+		toSchema, toName, ok2 := parseTableName(pair[1])
+		if !ok1 || !ok2 {
+			return nil, ErrWrongValueForVar.GenWithStackByArgs(TiDBTableMapping, val)
+		}
+		mapping[fromSchema.L+"."+fromName.L] = TableMappingTarget{Schema: toSchema, Name: toName}
+	}
+	return mapping, nil
+}
+
+// MappedTable returns the table which the table `schema.name` referenced by a DML statement is routed to
+// by tidb_table_mapping.
+func (s *SessionVars) MappedTable(schema, name model.CIStr) (TableMappingTarget, bool) {
+	if len(s.TableMapping) == 0 {
+		return TableMappingTarget{}, false
+	}
+	target, ok := s.TableMapping[schema.L+"."+name.L]
+	return target, ok
+}
+
 // SessionVars is to handle user-defined or global variables in the current session.
 type SessionVars struct {
 	Concurrency
@@ -517,6 +678,18 @@ type SessionVars struct {
 		taskID int64
 	}
 
+	// mdl records the metadata locks held by the current transaction, it maps the table ID to
+	// the schema version the transaction uses. It is read by the DDL owner, so it's guarded by mu.
+	mdl struct {
+		mu      sync.Mutex
+		tables  map[int64]int64
+		startTS uint64
+		// revoked records the locks revoked because they block a schema change for too long.
+		revoked map[int64]int64
+		// held indicates the session is registered as a holder, it's kept when the locks are revoked.
+		held bool
+	}
+
 	// Status stands for the session status. e.g. in transaction or not, auto commit is on or off, and so on.
 	Status uint16
 
@@ -696,6 +869,10 @@ type SessionVars struct {
 	// BatchInsert indicates if we should split insert data into multiple batches.
 	BatchInsert bool
 
+	// PipelinedInsertSelect indicates if `insert into ... select` commits the data in pipelined batches. The batches
+	// committed before a failure are not rolled back.
+	PipelinedInsertSelect bool
+
 	// BatchDelete indicates if we should split delete data into multiple batches.
 	BatchDelete bool
 
@@ -789,6 +966,74 @@ type SessionVars struct {
 	// See https://dev.mysql.com/doc/refman/5.7/en/server-system-variables.html#sysvar_max_execution_time
 	MaxExecutionTime uint64
 
+	// EnableDMLMaxExecutionTime indicates whether MaxExecutionTime also applies to INSERT, REPLACE, UPDATE and DELETE statements.
+	EnableDMLMaxExecutionTime bool
+
+	// DDLTimeout is the max time a DDL statement waits for its job to finish, in milliseconds.
+	// If the value is 0, timeouts are not enabled.
+	DDLTimeout uint64
+
+	// EnableGeneralPlanCache indicates whether text protocol statements reuse the plans in the plan cache.
+	EnableGeneralPlanCache bool
+
+	// GeneralPlanCacheSize is the max number of the parameterized statements kept for the general plan cache, not a
+	// memory size.
+	GeneralPlanCacheSize uint64
+
+	// EnableApproxCount indicates whether COUNT(*) without predicates is answered by the statistics row count.
+	EnableApproxCount bool
+
+	// EnablePartitionWiseJoin indicates whether the joins of two compatibly partitioned tables are done partition by partition.
+	EnablePartitionWiseJoin bool
+
+	// ExplainDeterministic indicates whether EXPLAIN ANALYZE masks the values which change from run to run.
+	ExplainDeterministic bool
+
+	// CTEDedupUnionAll indicates whether the recursive CTEs with UNION ALL are deduplicated like UNION DISTINCT.
+	CTEDedupUnionAll bool
+
+	// CTETruncateOnLimit indicates whether reaching the limits of the recursive CTEs is a warning instead of an error.
+	CTETruncateOnLimit bool
+
+	// CTEMemQuota is the memory quota of the intermediate results of a recursive CTE, 0 means unlimited.
+	CTEMemQuota int64
+
+	// EnableInsertIgnorePresenceFilter indicates whether INSERT IGNORE skips the rows conflicting with the unique keys
+	// known to exist in the transaction without looking up the keys again.
+	EnableInsertIgnorePresenceFilter bool
+
+	// ExplainPrunedPaths indicates whether the verbose EXPLAIN shows the access paths pruned by the skyline pruning.
+	ExplainPrunedPaths bool
+
+	// EnablePGCompatFunctions indicates whether the functions and operators compatible with PostgreSQL are enabled.
+	EnablePGCompatFunctions bool
+
+	// EnableScanOperatorFusion indicates whether the cheap Selection and Projection are fused into the TableReader.
+	EnableScanOperatorFusion bool
+
+	// MaxResultRows is the max number of rows a SELECT statement returns to the client, 0 means unlimited.
+	MaxResultRows int64
+
+	// TruncateOnMaxResultRows indicates whether exceeding MaxResultRows truncates the result with a warning
+	// instead of returning an error.
+	TruncateOnMaxResultRows bool
+
+	// SlowRPCThreshold is the threshold of a single coprocessor RPC to be recorded in the slow log, 0 means disabled.
+	SlowRPCThreshold time.Duration
+
+	// SessionAlias is the alias of the session set by tidb_session_alias.
+	SessionAlias string
+
+	// LockWaitTimeoutRetryLimit is the max number of in-server retries of an auto-committed DML statement whose
+	// pessimistic lock wait times out.
+	LockWaitTimeoutRetryLimit int
+
+	// LockWaitTimeoutRetryJitter is the max random backoff before each retry of the lock wait timeout statement.
+	LockWaitTimeoutRetryJitter time.Duration
+
+	// AnalyzeReadFromTiFlash indicates whether ANALYZE scans the rows to sample from the available TiFlash replicas.
+	AnalyzeReadFromTiFlash bool
+
 	// Killed is a flag to indicate that this query is killed.
 	Killed uint32
 
@@ -848,6 +1093,10 @@ type SessionVars struct {
 	// replicaRead is used for reading data from replicas, only follower is supported at this time.
 	replicaRead kv.ReplicaReadType
 
+	// ReplicaClosestReadThreshold is the minimum estimated response size in bytes of a statement to read from
+	// the closest replicas when replicaRead is closest-adaptive.
+	ReplicaClosestReadThreshold int64
+
 	// IsolationReadEngines is used to isolation read, tidb only read from the stores whose engine type is in the engines.
 	IsolationReadEngines map[kv.StoreType]struct{}
 
@@ -878,6 +1127,15 @@ type SessionVars struct {
 	// see https://dev.mysql.com/doc/refman/8.0/en/window-function-optimization.html for more details.
 	WindowingUseHighPrecision bool
 
+	// PrimaryKeyRequired indicates whether the tables must have a primary key when they are created or altered.
+	// see https://dev.mysql.com/doc/refman/8.0/en/server-system-variables.html#sysvar_sql_require_primary_key
+	PrimaryKeyRequired bool
+
+	// ResultsetMetadataNone indicates the column definitions of result sets are not sent to the clients
+	// which support CLIENT_OPTIONAL_RESULTSET_METADATA.
+	// see https://dev.mysql.com/doc/refman/8.0/en/server-system-variables.html#sysvar_resultset_metadata
+	ResultsetMetadataNone bool
+
 	// FoundInPlanCache indicates whether this statement was found in plan cache.
 	FoundInPlanCache bool
 	// PrevFoundInPlanCache indicates whether the last statement was found in plan cache.
@@ -903,6 +1161,10 @@ type SessionVars struct {
 	// EnableParallelApply indicates that thether to use parallel apply.
 	EnableParallelApply bool
 
+	// EnableParallelHashAggSpill indicates whether the hash aggregation spills by hash partitions and merges
+	// the spilled partitions in parallel.
+	EnableParallelHashAggSpill bool
+
 	// EnableRedactLog indicates that whether redact log.
 	EnableRedactLog bool
 
@@ -915,6 +1177,10 @@ type SessionVars struct {
 	// LastTxnInfo keeps track the info of last committed transaction.
 	LastTxnInfo string
 
+	// WriteConflictHistory keeps the recent write conflicts met by the optimistic transactions of the session.
+	// It's allocated when the first write conflict is met.
+	WriteConflictHistory *writeconflicthistory.WriteConflictHistory
+
 	// LastQueryInfo keeps track the info of last query.
 	LastQueryInfo QueryInfo
 
@@ -999,8 +1265,39 @@ type SessionVars struct {
 	// ReadConsistency indicates the read consistency requirement.
 	ReadConsistency ReadConsistencyLevel
 
+	// TableMapping maps the lower-cased `db.table` referenced by DML statements to the table to access.
+	TableMapping map[string]TableMappingTarget
+
 	// StatsLoadSyncWait indicates how long to wait for stats load before timeout.
 	StatsLoadSyncWait int64
+
+	// EnableReuseChunk indicates whether the executors allocate chunks from ChunkAllocator.
+	EnableReuseChunk bool
+
+	// MetricsResourceGroup is the resource group label of the queries in the tenant query metrics.
+	MetricsResourceGroup string
+
+	// QueryAttributes are the query attributes sent by the client along with the
+	// current statement, they are only valid during the execution of the statement.
+	QueryAttributes map[string]string
+
+	// chunkAllocator is the session level chunk allocator, the chunks allocated from it
+	// are recycled after each statement. It's nil if the session isn't serving a client connection.
+	chunkAllocator chunk.Allocator
+}
+
+// SetChunkAllocator sets the session level chunk allocator.
+func (s *SessionVars) SetChunkAllocator(alloc chunk.Allocator) {
+	s.chunkAllocator = alloc
+}
+
+// ChunkAllocator returns the session level chunk allocator, it returns nil if
+// reusing chunks is disabled.
+func (s *SessionVars) ChunkAllocator() chunk.Allocator {
+	if !s.EnableReuseChunk {
+		return nil
+	}
+	return s.chunkAllocator
 }
 
 // InitStatementContext initializes a StatementContext, the object is reused to reduce allocation.
@@ -1204,6 +1501,8 @@ func NewSessionVars() *SessionVars {
 		enableIndexMerge:            DefTiDBEnableIndexMerge,
 		NoopFuncsMode:               TiDBOptOnOffWarn(DefTiDBEnableNoopFuncs),
 		replicaRead:                 kv.ReplicaReadLeader,
+		ReplicaClosestReadThreshold: DefAdaptiveClosestReadThreshold,
+		EnableDMLMaxExecutionTime:   DefTiDBEnableDMLMaxExecutionTime,
 		AllowRemoveAutoInc:          DefTiDBAllowRemoveAutoInc,
 		UsePlanBaselines:            DefTiDBUsePlanBaselines,
 		EvolvePlanBaselines:         DefTiDBEvolvePlanBaselines,
@@ -1222,6 +1521,7 @@ func NewSessionVars() *SessionVars {
 		AllowAutoRandExplicitInsert: DefTiDBAllowAutoRandExplicitInsert,
 		EnableClusteredIndex:        DefTiDBEnableClusteredIndex,
 		EnableParallelApply:         DefTiDBEnableParallelApply,
+		EnableParallelHashAggSpill:  DefTiDBEnableParallelHashAggSpill,
 		ShardAllocateStep:           DefTiDBShardAllocateStep,
 		EnableChangeMultiSchema:     DefTiDBChangeMultiSchema,
 		EnablePointGetCache:         DefTiDBPointGetCache,
@@ -1241,6 +1541,9 @@ func NewSessionVars() *SessionVars {
 		MPPStoreFailTTL:             DefTiDBMPPStoreFailTTL,
 		Rng:                         utilMath.NewWithTime(),
 		StatsLoadSyncWait:           StatsLoadSyncWait.Load(),
+		EnableReuseChunk:            DefTiDBEnableReuseChunk,
+		EnableScanOperatorFusion:    DefTiDBEnableScanOperatorFusion,
+		LockWaitTimeoutRetryJitter:  time.Duration(DefTiDBLockWaitTimeoutRetryJitter) * time.Millisecond,
 	}
 	vars.KVVars = tikvstore.NewVariables(&vars.Killed)
 	vars.Concurrency = Concurrency{
@@ -1255,6 +1558,7 @@ func NewSessionVars() *SessionVars {
 		windowConcurrency:          DefTiDBWindowConcurrency,
 		mergeJoinConcurrency:       DefTiDBMergeJoinConcurrency,
 		streamAggConcurrency:       DefTiDBStreamAggConcurrency,
+		applyConcurrency:           DefTiDBApplyConcurrency,
 		ExecutorConcurrency:        DefExecutorConcurrency,
 	}
 	vars.MemQuota = MemQuota{
@@ -1276,6 +1580,7 @@ func NewSessionVars() *SessionVars {
 		IndexLookupSize:    DefIndexLookupSize,
 		InitChunkSize:      DefInitChunkSize,
 		MaxChunkSize:       DefMaxChunkSize,
+		MaxChunkBytes:      DefMaxChunkBytes,
 	}
 	vars.DMLBatchSize = DefDMLBatchSize
 	var enableStreaming string
@@ -1375,14 +1680,40 @@ func (s *SessionVars) GetReplicaRead() kv.ReplicaReadType {
 	if s.StmtCtx.HasReplicaReadHint {
 		return kv.ReplicaReadType(s.StmtCtx.ReplicaRead)
 	}
+	// The closest-adaptive replica read only reads from the closest replicas when the statement reads enough data.
+	if s.replicaRead == kv.ReplicaReadClosestAdaptive && !s.StmtCtx.UseClosestReplica {
+		return kv.ReplicaReadLeader
+	}
 	return s.replicaRead
 }
 
+// IsReplicaReadClosestAdaptive returns whether tidb_replica_read is closest-adaptive.
+func (s *SessionVars) IsReplicaReadClosestAdaptive() bool {
+	return s.replicaRead == kv.ReplicaReadClosestAdaptive && !s.StmtCtx.HasReplicaReadHint
+}
+
 // SetReplicaRead set SessionVars.replicaRead.
 func (s *SessionVars) SetReplicaRead(val kv.ReplicaReadType) {
 	s.replicaRead = val
 }
 
+// GetMaxChunkSizeByRowWidth returns the max row count of a Chunk whose rows are about rowWidth bytes wide.
+// The wide rows get fewer rows in a Chunk to fit tidb_max_chunk_bytes, the result is never more than
+// tidb_max_chunk_size and never less than the lower bound of tidb_max_chunk_size.
+func (s *SessionVars) GetMaxChunkSizeByRowWidth(rowWidth int) int {
+	if s.MaxChunkBytes <= 0 || rowWidth <= 0 {
+		return s.MaxChunkSize
+	}
+	rows := s.MaxChunkBytes / int64(rowWidth)
+	if rows < maxChunkSizeLowerBound {
+		rows = maxChunkSizeLowerBound
+	}
+	if rows >= int64(s.MaxChunkSize) {
+		return s.MaxChunkSize
+	}
+	return int(rows)
+}
+
 // GetWriteStmtBufs get pointer of SessionVars.writeStmtBufs.
 func (s *SessionVars) GetWriteStmtBufs() *WriteStmtBufs {
 	return &s.writeStmtBufs
@@ -1484,6 +1815,95 @@ func (s *SessionVars) InTxn() bool {
 	return s.GetStatusFlag(mysql.ServerStatusInTrans)
 }
 
+// AcquireMDL records that the current transaction holds the metadata lock of the table at schemaVer.
+// It returns false if the lock has been held already.
+func (s *SessionVars) AcquireMDL(tableID, schemaVer int64, startTS uint64) bool {
+	s.mdl.mu.Lock()
+	defer s.mdl.mu.Unlock()
+	if _, ok := s.mdl.tables[tableID]; ok {
+		return false
+	}
+	if s.mdl.tables == nil {
+		s.mdl.tables = make(map[int64]int64)
+	}
+	s.mdl.tables[tableID] = schemaVer
+	s.mdl.startTS = startTS
+	s.mdl.held = true
+	return true
+}
+
+// GetMDLTables returns a copy of the metadata locks held by the current transaction,
+// and the start ts of the transaction.
+func (s *SessionVars) GetMDLTables() (map[int64]int64, uint64) {
+	s.mdl.mu.Lock()
+	defer s.mdl.mu.Unlock()
+	if len(s.mdl.tables) == 0 {
+		return nil, 0
+	}
+	tables := make(map[int64]int64, len(s.mdl.tables))
+	for id, ver := range s.mdl.tables {
+		tables[id] = ver
+	}
+	return tables, s.mdl.startTS
+}
+
+// HoldMDL returns if the current transaction holds the metadata lock of the table.
+func (s *SessionVars) HoldMDL(tableID int64) bool {
+	s.mdl.mu.Lock()
+	defer s.mdl.mu.Unlock()
+	_, ok := s.mdl.tables[tableID]
+	return ok
+}
+
+// ReleaseMDL releases all the metadata locks held by the current transaction.
+// It returns false if the session doesn't hold any lock since the last release.
+func (s *SessionVars) ReleaseMDL() bool {
+	s.mdl.mu.Lock()
+	defer s.mdl.mu.Unlock()
+	if !s.mdl.held {
+		return false
+	}
+	s.mdl.tables = nil
+	s.mdl.revoked = nil
+	s.mdl.startTS = 0
+	s.mdl.held = false
+	return true
+}
+
+// RevokeMDL revokes the metadata locks of the tables held at a schema version older than schemaVer,
+// so the schema change no longer waits for the transaction, and the transaction fails to commit if
+// it writes the tables. It returns the IDs of the revoked tables.
+func (s *SessionVars) RevokeMDL(tableIDs map[int64]struct{}, schemaVer int64) []int64 {
+	s.mdl.mu.Lock()
+	defer s.mdl.mu.Unlock()
+	var revoked []int64
+	for id, ver := range s.mdl.tables {
+		if _, ok := tableIDs[id]; ok && ver < schemaVer {
+			if s.mdl.revoked == nil {
+				s.mdl.revoked = make(map[int64]int64)
+			}
+			s.mdl.revoked[id] = ver
+			delete(s.mdl.tables, id)
+			revoked = append(revoked, id)
+		}
+	}
+	return revoked
+}
+
+// GetRevokedMDLTables returns a copy of the metadata locks revoked from the current transaction.
+func (s *SessionVars) GetRevokedMDLTables() map[int64]int64 {
+	s.mdl.mu.Lock()
+	defer s.mdl.mu.Unlock()
+	if len(s.mdl.revoked) == 0 {
+		return nil
+	}
+	revoked := make(map[int64]int64, len(s.mdl.revoked))
+	for id, ver := range s.mdl.revoked {
+		revoked[id] = ver
+	}
+	return revoked
+}
+
 // IsAutocommit returns if the session is set to autocommit.
 func (s *SessionVars) IsAutocommit() bool {
 	return s.GetStatusFlag(mysql.ServerStatusAutocommit)
@@ -1692,6 +2112,49 @@ type TableDelta struct {
 	TableID  int64
 }
 
+// IndexValueDelta stands for the changed count of the encoded index values for one table or partition,
+// it's indexed by the index ID and then the encoded index value. The values of an index are nil if the
+// index is overflowed, see MaxIndexValueDeltas.
+type IndexValueDelta map[int64]map[string]int64
+
+// Update adds the delta to the count of the value of the index. If there are already MaxIndexValueDeltas
+// values for the index, the values are dropped and the index is marked as overflowed, since the index
+// can't be maintained correctly by a part of the changed values.
+func (d IndexValueDelta) Update(indexID int64, value string, delta int64) {
+	values, ok := d[indexID]
+	if ok && values == nil {
+		return
+	}
+	if values == nil {
+		values = make(map[string]int64)
+		d[indexID] = values
+	}
+	if _, ok := values[value]; !ok && len(values) >= MaxIndexValueDeltas {
+		d[indexID] = nil
+		return
+	}
+	values[value] += delta
+}
+
+// Overflowed checks whether the index is overflowed, whose values are not recorded.
+func (d IndexValueDelta) Overflowed(indexID int64) bool {
+	values, ok := d[indexID]
+	return ok && values == nil
+}
+
+// Merge merges the other delta into d.
+func (d IndexValueDelta) Merge(other IndexValueDelta) {
+	for indexID, values := range other {
+		if values == nil {
+			d[indexID] = nil
+			continue
+		}
+		for value, delta := range values {
+			d.Update(indexID, value, delta)
+		}
+	}
+}
+
 // ConcurrencyUnset means the value the of the concurrency related variable is unset.
 const ConcurrencyUnset = -1
 
@@ -1736,6 +2199,9 @@ type Concurrency struct {
 	// streamAggConcurrency is deprecated, use ExecutorConcurrency instead.
 	streamAggConcurrency int
 
+	// applyConcurrency is the number of concurrent parallel apply worker.
+	applyConcurrency int
+
 	// indexSerialScanConcurrency is the number of concurrent index serial scan worker.
 	indexSerialScanConcurrency int
 
@@ -1796,6 +2262,11 @@ func (c *Concurrency) SetStreamAggConcurrency(n int) {
 	c.streamAggConcurrency = n
 }
 
+// SetApplyConcurrency set the number of concurrent parallel apply worker.
+func (c *Concurrency) SetApplyConcurrency(n int) {
+	c.applyConcurrency = n
+}
+
 // SetIndexSerialScanConcurrency set the number of concurrent index serial scan worker.
 func (c *Concurrency) SetIndexSerialScanConcurrency(n int) {
 	c.indexSerialScanConcurrency = n
@@ -1878,6 +2349,14 @@ func (c *Concurrency) StreamAggConcurrency() int {
 	return c.ExecutorConcurrency
 }
 
+// ApplyConcurrency return the number of concurrent parallel apply worker.
+func (c *Concurrency) ApplyConcurrency() int {
+	if c.applyConcurrency != ConcurrencyUnset {
+		return c.applyConcurrency
+	}
+	return c.ExecutorConcurrency
+}
+
 // IndexSerialScanConcurrency return the number of concurrent index serial scan worker.
 // This option is not sync with ExecutorConcurrency since it's used by Analyze table.
 func (c *Concurrency) IndexSerialScanConcurrency() int {
@@ -1927,6 +2406,9 @@ type BatchSize struct {
 
 	// MaxChunkSize defines max row count of a Chunk during query execution.
 	MaxChunkSize int
+
+	// MaxChunkBytes defines the estimated max memory size of a Chunk during query execution.
+	MaxChunkBytes int64
 }
 
 const (
@@ -1998,6 +2480,12 @@ const (
 	SlowLogCopWaitAddr = "Cop_wait_addr" // #nosec G101
 	// SlowLogCopBackoffPrefix contains backoff information.
 	SlowLogCopBackoffPrefix = "Cop_backoff_"
+	// SlowLogSessionAlias is the alias of the session set by tidb_session_alias.
+	SlowLogSessionAlias = "Session_alias"
+	// SlowLogSessionAliasPrefix is the prefix of the session alias line, the alias may contain spaces and colons.
+	SlowLogSessionAliasPrefix = SlowLogSessionAlias + SlowLogSpaceMarkStr
+	// SlowLogSlowRPCPrefix contains the information of a coprocessor RPC which exceeds tidb_slow_rpc_threshold.
+	SlowLogSlowRPCPrefix = "Slow_rpc_"
 	// SlowLogMemMax is the max number bytes of memory used in this statement.
 	SlowLogMemMax = "Mem_max"
 	// SlowLogDiskMax is the nax number bytes of disk used in this statement.
@@ -2024,6 +2512,10 @@ const (
 	SlowLogPlanSuffix = "')"
 	// SlowLogPrevStmtPrefix is the prefix of Prev_stmt in slow log file.
 	SlowLogPrevStmtPrefix = SlowLogPrevStmt + SlowLogSpaceMarkStr
+	// SlowLogQueryAttributes is used to record the query attributes sent by the client.
+	SlowLogQueryAttributes = "Query_attributes"
+	// SlowLogQueryAttributesPrefix is the prefix of Query_attributes in slow log file.
+	SlowLogQueryAttributesPrefix = SlowLogQueryAttributes + SlowLogSpaceMarkStr
 	// SlowLogKVTotal is the total time waiting for kv.
 	SlowLogKVTotal = "KV_total"
 	// SlowLogPDTotal is the total time waiting for pd.
@@ -2081,6 +2573,8 @@ type SlowQueryLogItems struct {
 	ResultRows        int64
 	IsExplicitTxn     bool
 	IsWriteCacheTable bool
+	QueryAttributes   string
+	SlowRPCs          []stmtctx.SlowRPCDetail
 }
 
 // SlowLogFormat uses for formatting slow log.
@@ -2089,6 +2583,7 @@ type SlowQueryLogItems struct {
 // # Txn_start_ts: 406315658548871171
 // # User@Host: root[root] @ localhost [127.0.0.1]
 // # Conn_ID: 6
+// # Session_alias: order-service-0
 // # Query_time: 4.895492
 // # Process_time: 0.161 Request_count: 1 Total_keys: 100001 Processed_keys: 100000
 // # DB: test
@@ -2099,9 +2594,11 @@ type SlowQueryLogItems struct {
 // # Num_cop_tasks: 10
 // # Cop_process: Avg_time: 1s P90_time: 2s Max_time: 3s Max_addr: 10.6.131.78
 // # Cop_wait: Avg_time: 10ms P90_time: 20ms Max_time: 30ms Max_Addr: 10.6.131.79
+// # Slow_rpc_region_id: 2 Slow_rpc_store_addr: 10.6.131.79 Slow_rpc_time: 1.2 Slow_rpc_process_time: 1 Slow_rpc_wait_time: 0.1
 // # Memory_max: 4096
 // # Disk_max: 65535
 // # Succ: true
+// # Query_attributes: {"trace_id":"abc"}
 // # Prev_stmt: begin;
 // select * from t_slim;
 func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
@@ -2118,6 +2615,9 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 	if s.ConnectionID != 0 {
 		writeSlowLogItem(&buf, SlowLogConnIDStr, strconv.FormatUint(s.ConnectionID, 10))
 	}
+	if len(s.SessionAlias) > 0 {
+		writeSlowLogItem(&buf, SlowLogSessionAlias, s.SessionAlias)
+	}
 	if logItems.ExecRetryCount > 0 {
 		buf.WriteString(SlowLogRowPrefixStr)
 		buf.WriteString(SlowLogExecRetryTime)
@@ -2227,6 +2727,15 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 			}
 		}
 	}
+	for _, rpc := range logItems.SlowRPCs {
+		buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v %v%v%v %v%v%v %v%v%v\n",
+			SlowLogSlowRPCPrefix+"region_id", SlowLogSpaceMarkStr, rpc.RegionID,
+			SlowLogSlowRPCPrefix+"store_addr", SlowLogSpaceMarkStr, rpc.StoreAddr,
+			SlowLogSlowRPCPrefix+"time", SlowLogSpaceMarkStr, rpc.RespTime.Seconds(),
+			SlowLogSlowRPCPrefix+"process_time", SlowLogSpaceMarkStr, rpc.ProcessTime.Seconds(),
+			SlowLogSlowRPCPrefix+"wait_time", SlowLogSpaceMarkStr, rpc.WaitTime.Seconds(),
+		))
+	}
 	if logItems.MemMax > 0 {
 		writeSlowLogItem(&buf, SlowLogMemMax, strconv.FormatInt(logItems.MemMax, 10))
 	}
@@ -2254,6 +2763,9 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 	if len(logItems.PlanDigest) != 0 {
 		writeSlowLogItem(&buf, SlowLogPlanDigest, logItems.PlanDigest)
 	}
+	if len(logItems.QueryAttributes) != 0 {
+		writeSlowLogItem(&buf, SlowLogQueryAttributes, logItems.QueryAttributes)
+	}
 
 	if logItems.PrevStmt != "" {
 		writeSlowLogItem(&buf, SlowLogPrevStmt, logItems.PrevStmt)
@@ -143,6 +143,58 @@ func TestAllocMPPID(t *testing.T) {
 	require.Equal(t, int64(3), seVar.AllocMPPTaskID(2))
 }
 
+func TestGetMaxChunkSizeByRowWidth(t *testing.T) {
+	vars := variable.NewSessionVars()
+	require.Equal(t, variable.DefMaxChunkSize, vars.MaxChunkSize)
+	require.Equal(t, int64(variable.DefMaxChunkBytes), vars.MaxChunkBytes)
+
+	// The narrow rows are capped by tidb_max_chunk_size.
+	require.Equal(t, 1024, vars.GetMaxChunkSizeByRowWidth(8))
+	require.Equal(t, 1024, vars.GetMaxChunkSizeByRowWidth(1024))
+	// The wide rows fit tidb_max_chunk_bytes.
+	require.Equal(t, 512, vars.GetMaxChunkSizeByRowWidth(2048))
+	require.Equal(t, 32, vars.GetMaxChunkSizeByRowWidth(1<<20))
+	vars.MaxChunkSize = 4096
+	require.Equal(t, 4096, vars.GetMaxChunkSizeByRowWidth(8))
+	require.Equal(t, 2048, vars.GetMaxChunkSizeByRowWidth(512))
+	vars.MaxChunkSize = 2
+	require.Equal(t, 2, vars.GetMaxChunkSizeByRowWidth(1<<20))
+
+	// 0 disables the adaptive chunk size.
+	vars.MaxChunkSize = 1024
+	vars.MaxChunkBytes = 0
+	require.Equal(t, 1024, vars.GetMaxChunkSizeByRowWidth(1<<20))
+}
+
+func TestIndexValueDelta(t *testing.T) {
+	tc := &variable.TransactionContext{}
+	tc.UpdateIndexValueDeltaForTable(1, 1, []byte("a"), 1)
+	tc.UpdateIndexValueDeltaForTable(1, 1, []byte("b"), 1)
+	tc.EndStmtIndexValueDelta(true)
+	// The values changed by a rolled back statement are dropped.
+	tc.UpdateIndexValueDeltaForTable(1, 1, []byte("a"), 1)
+	tc.UpdateIndexValueDeltaForTable(1, 2, []byte("a"), 1)
+	tc.EndStmtIndexValueDelta(false)
+	tc.UpdateIndexValueDeltaForTable(1, 1, []byte("a"), -1)
+	tc.EndStmtIndexValueDelta(true)
+	require.Equal(t, map[int64]variable.IndexValueDelta{1: {1: {"a": 0, "b": 1}}}, tc.IndexValueDeltaMap)
+
+	// The index is overflowed if the statement changes too many values of it.
+	for i := 0; i <= variable.MaxIndexValueDeltas; i++ {
+		require.False(t, tc.IsIndexValueDeltaOverflowed(1, 1))
+		tc.UpdateIndexValueDeltaForTable(1, 1, []byte{byte(i >> 8), byte(i)}, 1)
+	}
+	require.True(t, tc.IsIndexValueDeltaOverflowed(1, 1))
+	require.False(t, tc.IsIndexValueDeltaOverflowed(1, 2))
+	tc.EndStmtIndexValueDelta(true)
+	require.False(t, tc.IsIndexValueDeltaOverflowed(1, 1))
+	require.True(t, tc.IndexValueDeltaMap[1].Overflowed(1))
+	// The overflowed index isn't recorded any more.
+	tc.UpdateIndexValueDeltaForTable(1, 1, []byte("a"), 1)
+	tc.EndStmtIndexValueDelta(true)
+	require.True(t, tc.IndexValueDeltaMap[1].Overflowed(1))
+}
+
 func TestSlowLogFormat(t *testing.T) {
 	ctx := mock.NewContext()
 
@@ -152,6 +204,7 @@ func TestSlowLogFormat(t *testing.T) {
 	seVar.User = &auth.UserIdentity{Username: "root", Hostname: "192.168.0.1"}
 	seVar.ConnectionInfo = &variable.ConnectionInfo{ClientIP: "192.168.0.1"}
 	seVar.ConnectionID = 1
+	seVar.SessionAlias = "alias123"
 	seVar.CurrentDB = "test"
 	seVar.InRestrictedSQL = true
 	seVar.StmtCtx.WaitLockLeaseTime = 1
@@ -204,6 +257,7 @@ func TestSlowLogFormat(t *testing.T) {
 	resultFields := `# Txn_start_ts: 406649736972468225
 # User@Host: root[root] @ 192.168.0.1 [192.168.0.1]
 # Conn_ID: 1
+# Session_alias: alias123
 # Exec_retry_time: 5.1 Exec_retry_count: 3
 # Query_time: 1
 # Parse_time: 0.00000001
@@ -223,6 +277,7 @@ func TestSlowLogFormat(t *testing.T) {
 # Cop_backoff_regionMiss_total_times: 200 Cop_backoff_regionMiss_total_time: 0.2 Cop_backoff_regionMiss_max_time: 0.2 Cop_backoff_regionMiss_max_addr: 127.0.0.1 Cop_backoff_regionMiss_avg_time: 0.2 Cop_backoff_regionMiss_p90_time: 0.2
 # Cop_backoff_rpcPD_total_times: 200 Cop_backoff_rpcPD_total_time: 0.2 Cop_backoff_rpcPD_max_time: 0.2 Cop_backoff_rpcPD_max_addr: 127.0.0.1 Cop_backoff_rpcPD_avg_time: 0.2 Cop_backoff_rpcPD_p90_time: 0.2
 # Cop_backoff_rpcTiKV_total_times: 200 Cop_backoff_rpcTiKV_total_time: 0.2 Cop_backoff_rpcTiKV_max_time: 0.2 Cop_backoff_rpcTiKV_max_addr: 127.0.0.1 Cop_backoff_rpcTiKV_avg_time: 0.2 Cop_backoff_rpcTiKV_p90_time: 0.2
+# Slow_rpc_region_id: 2 Slow_rpc_store_addr: 10.6.131.79:20160 Slow_rpc_time: 1.5 Slow_rpc_process_time: 1.2 Slow_rpc_wait_time: 0.2
 # Mem_max: 2333
 # Disk_max: 6666
 # Prepared: true
@@ -236,7 +291,8 @@ func TestSlowLogFormat(t *testing.T) {
 # Result_rows: 12345
 # Succ: true
 # IsExplicitTxn: true
-# IsWriteCacheTable: true`
+# IsWriteCacheTable: true
+# Query_attributes: {"trace_id":"abc"}`
 	sql := "select * from t;"
 	_, digest := parser.NormalizeDigest(sql)
 	logItems := &variable.SlowQueryLogItems{
@@ -273,6 +329,14 @@ func TestSlowLogFormat(t *testing.T) {
 		ExecRetryTime:     5*time.Second + time.Millisecond*100,
 		IsExplicitTxn:     true,
 		IsWriteCacheTable: true,
+		QueryAttributes:   `{"trace_id":"abc"}`,
+		SlowRPCs: []stmtctx.SlowRPCDetail{{
+			RegionID:    2,
+			StoreAddr:   "10.6.131.79:20160",
+			RespTime:    time.Millisecond * 1500,
+			ProcessTime: time.Millisecond * 1200,
+			WaitTime:    time.Millisecond * 200,
+		}},
 	}
 	logString := seVar.SlowLogFormat(logItems)
 	require.Equal(t, resultFields+"\n"+sql, logString)
@@ -23,11 +23,14 @@ import (
 	"strings"
 	"sync/atomic"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -184,6 +187,7 @@ var defaultSysVars = []*SysVar{
 		return nil
 	}},
 	{Scope: ScopeGlobal, Name: MaxPreparedStmtCount, Value: strconv.FormatInt(DefMaxPreparedStmtCount, 10), Type: TypeInt, MinValue: -1, MaxValue: 1048576},
+	{Scope: ScopeGlobal | ScopeSession, Name: MaxUserConnections, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: 4294967295},
 	{Scope: ScopeNone, Name: DataDir, Value: "/usr/local/mysql/data/"},
 	{Scope: ScopeGlobal | ScopeSession, Name: WaitTimeout, Value: strconv.FormatInt(DefWaitTimeout, 10), Type: TypeUnsigned, MinValue: 0, MaxValue: secondsPerYear},
 	{Scope: ScopeGlobal | ScopeSession, Name: InteractiveTimeout, Value: "28800", Type: TypeUnsigned, MinValue: 1, MaxValue: secondsPerYear},
@@ -235,6 +239,14 @@ var defaultSysVars = []*SysVar{
 		s.WindowingUseHighPrecision = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: SQLRequirePrimaryKey, Value: Off, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.PrimaryKeyRequired = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: ResultsetMetadata, Value: "FULL", Type: TypeEnum, PossibleValues: []string{"FULL", "NONE"}, SetSession: func(s *SessionVars, val string) error {
+		s.ResultsetMetadataNone = strings.EqualFold(val, "NONE")
+		return nil
+	}},
 	{Scope: ScopeNone, Name: "license", Value: "Apache License 2.0"},
 	{Scope: ScopeGlobal | ScopeSession, Name: BlockEncryptionMode, Value: "aes-128-ecb"},
 	{Scope: ScopeSession, Name: LastInsertID, Value: "", skipInit: true, GetSession: func(s *SessionVars) (string, error) {
@@ -488,6 +500,10 @@ var defaultSysVars = []*SysVar{
 		s.BatchInsert = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeSession, Name: TiDBPipelinedInsertSelect, Value: BoolToOnOff(DefPipelinedInsertSelect), Type: TypeBool, skipInit: true, SetSession: func(s *SessionVars, val string) error {
+		s.PipelinedInsertSelect = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeSession, Name: TiDBBatchDelete, Value: BoolToOnOff(DefBatchDelete), Type: TypeBool, skipInit: true, SetSession: func(s *SessionVars, val string) error {
 		s.BatchDelete = TiDBOptOn(val)
 		return nil
@@ -530,6 +546,10 @@ var defaultSysVars = []*SysVar{
 		s.InitChunkSize = tidbOptPositiveInt32(val, DefInitChunkSize)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMaxChunkBytes, Value: strconv.Itoa(DefMaxChunkBytes), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.MaxChunkBytes = TidbOptInt64(val, DefMaxChunkBytes)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableCascadesPlanner, Value: Off, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.SetEnableCascadesPlanner(TiDBOptOn(val))
 		return nil
@@ -662,6 +682,14 @@ var defaultSysVars = []*SysVar{
 		s.EnableParallelApply = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableParallelHashAggSpill, Value: BoolToOnOff(DefTiDBEnableParallelHashAggSpill), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableParallelHashAggSpill = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBApplyConcurrency, Value: strconv.Itoa(DefTiDBApplyConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, SetSession: func(s *SessionVars, val string) error {
+		s.applyConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMemQuotaApplyCache, Value: strconv.Itoa(DefTiDBMemQuotaApplyCache), Type: TypeUnsigned, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
 		s.MemQuotaApplyCache = TidbOptInt64(val, DefTiDBMemQuotaApplyCache)
 		return nil
@@ -821,6 +849,22 @@ var defaultSysVars = []*SysVar{
 		SetDDLReorgBatchSize(int32(tidbOptPositiveInt32(val, DefTiDBDDLReorgBatchSize)))
 		return nil
 	}},
+	{Scope: ScopeGlobal, Name: TiDBDDLEnableFastReorg, Value: BoolToOnOff(DefTiDBDDLEnableFastReorg), skipInit: true, Type: TypeBool, GetGlobal: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(DDLEnableFastReorg.Load()), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		DDLEnableFastReorg.Store(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBDDLDiskQuota, Value: strconv.FormatUint(DefTiDBDDLDiskQuota, 10), skipInit: true, Type: TypeUnsigned, MinValue: 1024 * 1024 * 1024, MaxValue: math.MaxInt64, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatUint(DDLDiskQuota.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		DDLDiskQuota.Store(uint64(TidbOptInt64(val, DefTiDBDDLDiskQuota)))
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBDDLTimeout, Value: strconv.Itoa(DefTiDBDDLTimeout), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+		s.DDLTimeout = uint64(TidbOptInt64(val, DefTiDBDDLTimeout))
+		return nil
+	}},
 	{Scope: ScopeGlobal, Name: TiDBDDLErrorCountLimit, Value: strconv.Itoa(DefTiDBDDLErrorCountLimit), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
 		SetDDLErrorCountLimit(TidbOptInt64(val, DefTiDBDDLErrorCountLimit))
 		return nil
@@ -918,7 +962,7 @@ var defaultSysVars = []*SysVar{
 		s.NoopFuncsMode = TiDBOptOnOffWarn(val)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBReplicaRead, Value: "leader", Type: TypeEnum, PossibleValues: []string{"leader", "follower", "leader-and-follower", "closest-replicas"}, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBReplicaRead, Value: "leader", Type: TypeEnum, PossibleValues: []string{"leader", "follower", "leader-and-follower", "closest-replicas", "closest-adaptive"}, SetSession: func(s *SessionVars, val string) error {
 		if strings.EqualFold(val, "follower") {
 			s.SetReplicaRead(kv.ReplicaReadFollower)
 		} else if strings.EqualFold(val, "leader-and-follower") {
@@ -927,9 +971,19 @@ var defaultSysVars = []*SysVar{
 			s.SetReplicaRead(kv.ReplicaReadLeader)
 		} else if strings.EqualFold(val, "closest-replicas") {
 			s.SetReplicaRead(kv.ReplicaReadClosest)
+		} else if strings.EqualFold(val, "closest-adaptive") {
+			s.SetReplicaRead(kv.ReplicaReadClosestAdaptive)
 		}
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAdaptiveClosestReadThreshold, Value: strconv.Itoa(DefAdaptiveClosestReadThreshold), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.ReplicaClosestReadThreshold = TidbOptInt64(val, DefAdaptiveClosestReadThreshold)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableDMLMaxExecutionTime, Value: BoolToOnOff(DefTiDBEnableDMLMaxExecutionTime), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableDMLMaxExecutionTime = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeSession, Name: TiDBAllowRemoveAutoInc, Value: BoolToOnOff(DefTiDBAllowRemoveAutoInc), skipInit: true, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.AllowRemoveAutoInc = TiDBOptOn(val)
 		return nil
@@ -1293,6 +1347,19 @@ var defaultSysVars = []*SysVar{
 		s.EnableStableResultMode = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableReuseChunk, Value: BoolToOnOff(DefTiDBEnableReuseChunk), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableReuseChunk = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMetricsResourceGroup, Value: "", Type: TypeStr, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		if normalizedValue == "" || IsMetricsResourceGroup(normalizedValue) {
+			return normalizedValue, nil
+		}
+		return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBMetricsResourceGroup, originalValue)
+	}, SetSession: func(s *SessionVars, val string) error {
+		s.MetricsResourceGroup = val
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnablePseudoForOutdatedStats, Value: BoolToOnOff(DefTiDBEnablePseudoForOutdatedStats), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnablePseudoForOutdatedStats = TiDBOptOn(val)
 		return nil
@@ -1353,6 +1420,23 @@ var defaultSysVars = []*SysVar{
 			return nil
 		},
 	},
+	{Scope: ScopeSession, Name: TiDBTableMapping, Value: "", Type: TypeStr, skipInit: true,
+		Validation: func(_ *SessionVars, normalized string, _ string, _ ScopeFlag) (string, error) {
+			_, err := parseTableMapping(normalized)
+			return normalized, err
+		},
+		SetSession: func(s *SessionVars, val string) error {
+			mapping, err := parseTableMapping(val)
+			if err != nil {
+				return err
+			}
+			if len(mapping) == 0 {
+				mapping = nil
+			}
+			s.TableMapping = mapping
+			return nil
+		},
+	},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBStatsLoadSyncWait, Value: strconv.Itoa(DefTiDBStatsLoadSyncWait), skipInit: true, Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt32,
 		SetSession: func(s *SessionVars, val string) error {
 			s.StatsLoadSyncWait = TidbOptInt64(val, DefTiDBStatsLoadSyncWait)
@@ -1375,6 +1459,145 @@ var defaultSysVars = []*SysVar{
 			return nil
 		},
 	},
+	{Scope: ScopeGlobal, Name: TiDBStatsCacheMemQuota, Value: strconv.Itoa(DefTiDBStatsCacheMemQuota), skipInit: true, Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64,
+		GetGlobal: func(s *SessionVars) (string, error) {
+			return strconv.FormatInt(StatsCacheMemQuota.Load(), 10), nil
+		},
+		SetGlobal: func(s *SessionVars, val string) error {
+			StatsCacheMemQuota.Store(TidbOptInt64(val, DefTiDBStatsCacheMemQuota))
+			return nil
+		},
+	},
+	{Scope: ScopeGlobal, Name: TiDBEnableAutoAnalyzeAfterDDL, Value: BoolToOnOff(DefTiDBEnableAutoAnalyzeAfterDDL), skipInit: true, Type: TypeBool,
+		GetGlobal: func(s *SessionVars) (string, error) {
+			return BoolToOnOff(EnableAutoAnalyzeAfterDDL.Load()), nil
+		},
+		SetGlobal: func(s *SessionVars, val string) error {
+			EnableAutoAnalyzeAfterDDL.Store(TiDBOptOn(val))
+			return nil
+		},
+	},
+	{Scope: ScopeGlobal, Name: TiDBEnableStatsSketchMaintenance, Value: BoolToOnOff(DefTiDBEnableStatsSketchMaintenance), skipInit: true, Type: TypeBool,
+		GetGlobal: func(s *SessionVars) (string, error) {
+			return BoolToOnOff(EnableStatsSketchMaintenance.Load()), nil
+		},
+		SetGlobal: func(s *SessionVars, val string) error {
+			EnableStatsSketchMaintenance.Store(TiDBOptOn(val))
+			return nil
+		},
+	},
+	{Scope: ScopeGlobal, Name: TiDBSequenceCacheLimit, Value: strconv.Itoa(DefTiDBSequenceCacheLimit), skipInit: true, Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64,
+		GetGlobal: func(s *SessionVars) (string, error) {
+			return strconv.FormatInt(autoid.SequenceCacheLimit.Load(), 10), nil
+		},
+		SetGlobal: func(s *SessionVars, val string) error {
+			autoid.SequenceCacheLimit.Store(TidbOptInt64(val, DefTiDBSequenceCacheLimit))
+			return nil
+		},
+	},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableGeneralPlanCache, Value: BoolToOnOff(DefTiDBEnableGeneralPlanCache), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableGeneralPlanCache = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBGeneralPlanCacheSize, Value: strconv.Itoa(DefTiDBGeneralPlanCacheSize), Type: TypeUnsigned, MinValue: 1, MaxValue: 100000, SetSession: func(s *SessionVars, val string) error {
+		s.GeneralPlanCacheSize = uint64(TidbOptInt64(val, DefTiDBGeneralPlanCacheSize))
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableApproxCount, Value: BoolToOnOff(DefTiDBEnableApproxCount), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableApproxCount = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEnablePartitionWiseJoin, Value: BoolToOnOff(DefTiDBOptEnablePartitionWiseJoin), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnablePartitionWiseJoin = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBExplainDeterministic, Value: BoolToOnOff(DefTiDBExplainDeterministic), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.ExplainDeterministic = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBCTEDedupUnionAll, Value: BoolToOnOff(DefTiDBCTEDedupUnionAll), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.CTEDedupUnionAll = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBCTETruncateOnLimit, Value: BoolToOnOff(DefTiDBCTETruncateOnLimit), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.CTETruncateOnLimit = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBCTEMemQuota, Value: strconv.Itoa(DefTiDBCTEMemQuota), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.CTEMemQuota = TidbOptInt64(val, DefTiDBCTEMemQuota)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBInsertIgnorePresenceFilter, Value: BoolToOnOff(DefTiDBInsertIgnorePresenceFilter), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableInsertIgnorePresenceFilter = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBOptExplainPrunedPaths, Value: BoolToOnOff(DefTiDBOptExplainPrunedPaths), Type: TypeBool, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
+		s.ExplainPrunedPaths = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnablePGCompatFunctions, Value: BoolToOnOff(DefTiDBEnablePGCompatFunctions), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnablePGCompatFunctions = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableScanOperatorFusion, Value: BoolToOnOff(DefTiDBEnableScanOperatorFusion), Type: TypeBool, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
+		s.EnableScanOperatorFusion = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMaxResultRows, Value: strconv.Itoa(DefTiDBMaxResultRows), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.MaxResultRows = TidbOptInt64(val, DefTiDBMaxResultRows)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMaxResultRowsMode, Value: DefTiDBMaxResultRowsMode, Type: TypeEnum, PossibleValues: []string{MaxResultRowsModeError, MaxResultRowsModeWarn}, SetSession: func(s *SessionVars, val string) error {
+		s.TruncateOnMaxResultRows = val == MaxResultRowsModeWarn
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBSlowRPCThreshold, Value: strconv.Itoa(DefTiDBSlowRPCThreshold), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+		s.SlowRPCThreshold = time.Duration(tidbOptPositiveInt32(val, DefTiDBSlowRPCThreshold)) * time.Millisecond
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBSessionAlias, Value: "", Type: TypeStr, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		if utf8.RuneCountInString(normalizedValue) > MaxSessionAliasLength {
+			return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBSessionAlias, originalValue)
+		}
+		// The alias is written into the slow log as is, a line break in it would forge the log items.
+		if strings.IndexFunc(normalizedValue, unicode.IsControl) >= 0 {
+			return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBSessionAlias, originalValue)
+		}
+		return normalizedValue, nil
+	}, SetSession: func(s *SessionVars, val string) error {
+		s.SessionAlias = val
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBLockWaitTimeoutRetryLimit, Value: strconv.Itoa(DefTiDBLockWaitTimeoutRetryLimit), Type: TypeUnsigned, MinValue: 0, MaxValue: 100, SetSession: func(s *SessionVars, val string) error {
+		s.LockWaitTimeoutRetryLimit = TidbOptInt(val, DefTiDBLockWaitTimeoutRetryLimit)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBLockWaitTimeoutRetryJitter, Value: strconv.Itoa(DefTiDBLockWaitTimeoutRetryJitter), Type: TypeUnsigned, MinValue: 0, MaxValue: 60000, SetSession: func(s *SessionVars, val string) error {
+		s.LockWaitTimeoutRetryJitter = time.Duration(TidbOptInt(val, DefTiDBLockWaitTimeoutRetryJitter)) * time.Millisecond
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeReadFromTiFlash, Value: BoolToOnOff(DefTiDBAnalyzeReadFromTiFlash), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.AnalyzeReadFromTiFlash = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBEnableMDL, Value: BoolToOnOff(DefTiDBEnableMDL), skipInit: true, Type: TypeBool,
+		GetGlobal: func(s *SessionVars) (string, error) {
+			return BoolToOnOff(EnableMDL.Load()), nil
+		},
+		SetGlobal: func(s *SessionVars, val string) error {
+			EnableMDL.Store(TiDBOptOn(val))
+			return nil
+		},
+	},
+	{Scope: ScopeGlobal, Name: TiDBMDLWaitTimeout, Value: strconv.Itoa(DefTiDBMDLWaitTimeout), skipInit: true, Type: TypeUnsigned, MinValue: 1, MaxValue: 31536000,
+		GetGlobal: func(s *SessionVars) (string, error) {
+			return strconv.FormatInt(MDLWaitTimeout.Load(), 10), nil
+		},
+		SetGlobal: func(s *SessionVars, val string) error {
+			MDLWaitTimeout.Store(TidbOptInt64(val, DefTiDBMDLWaitTimeout))
+			return nil
+		},
+	},
 }
 
 // FeedbackProbability points to the FeedbackProbability in statistics package.
@@ -1648,6 +1871,10 @@ const (
 	ThreadPoolSize = "thread_pool_size"
 	// WindowingUseHighPrecision is the name of 'windowing_use_high_precision' system variable.
 	WindowingUseHighPrecision = "windowing_use_high_precision"
+	// SQLRequirePrimaryKey is the name of 'sql_require_primary_key' system variable.
+	SQLRequirePrimaryKey = "sql_require_primary_key"
+	// ResultsetMetadata is the name of 'resultset_metadata' system variable.
+	ResultsetMetadata = "resultset_metadata"
 	// OptimizerSwitch is the name of 'optimizer_switch' system variable.
 	OptimizerSwitch = "optimizer_switch"
 	// SystemTimeZone is the name of 'system_time_zone' system variable.
@@ -22,6 +22,7 @@ import (
 	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -882,3 +883,43 @@ func TestNetBufferLength(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "524288", val) // unchanged
 }
+
+func TestSessionAlias(t *testing.T) {
+	sv := GetSysVar(TiDBSessionAlias)
+	vars := NewSessionVars()
+
+	val, err := sv.Validate(vars, strings.Repeat("别名", MaxSessionAliasLength/2), ScopeSession)
+	require.NoError(t, err)
+	require.NoError(t, sv.SetSessionFromHook(vars, val))
+	require.Equal(t, strings.Repeat("别名", MaxSessionAliasLength/2), vars.SessionAlias)
+
+	_, err = sv.Validate(vars, strings.Repeat("a", MaxSessionAliasLength+1), ScopeSession)
+	require.True(t, ErrWrongValueForVar.Equal(err))
+
+	for _, alias := range []string{"a\n# Query_time: 100", "a\rb", "a\tb", "a\x00b"} {
+		_, err = sv.Validate(vars, alias, ScopeSession)
+		require.True(t, ErrWrongValueForVar.Equal(err))
+	}
+
+	require.NoError(t, sv.SetSessionFromHook(vars, ""))
+	require.Equal(t, "", vars.SessionAlias)
+}
+
+func TestLockWaitTimeoutRetry(t *testing.T) {
+	vars := NewSessionVars()
+	require.Equal(t, 0, vars.LockWaitTimeoutRetryLimit)
+	require.Equal(t, 100*time.Millisecond, vars.LockWaitTimeoutRetryJitter)
+
+	limit := GetSysVar(TiDBLockWaitTimeoutRetryLimit)
+	val, err := limit.Validate(vars, "1000", ScopeSession)
+	require.NoError(t, err)
+	require.Equal(t, "100", val) // converts it to max value
+	require.NoError(t, limit.SetSessionFromHook(vars, "3"))
+	require.Equal(t, 3, vars.LockWaitTimeoutRetryLimit)
+
+	jitter := GetSysVar(TiDBLockWaitTimeoutRetryJitter)
+	require.NoError(t, jitter.SetSessionFromHook(vars, "0"))
+	require.Equal(t, time.Duration(0), vars.LockWaitTimeoutRetryJitter)
+	require.NoError(t, jitter.SetSessionFromHook(vars, "20"))
+	require.Equal(t, 20*time.Millisecond, vars.LockWaitTimeoutRetryJitter)
+}
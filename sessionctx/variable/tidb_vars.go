@@ -92,6 +92,13 @@ const (
 	// insert data into multiple batches and use a single txn for each batch. This will be helpful when inserting large data.
 	TiDBBatchInsert = "tidb_batch_insert"
 
+	// tidb_pipelined_insert_select is used to enable/disable the pipelined commit of `insert into ... select`. If set this
+	// option on, the insert executor inserts the select result in batches of tidb_dml_batch_size rows and commits each
+	// batch in its own txn. The statement isn't atomic: when it fails, the batches committed before the failure stay
+	// committed and their row count is reported in the error, no checkpoint is kept to resume the statement. Like
+	// tidb_batch_insert, it only takes effect when enable-batch-dml is on. This will be helpful when moving large data.
+	TiDBPipelinedInsertSelect = "tidb_pipelined_insert_select"
+
 	// tidb_batch_delete is used to enable/disable auto-split delete data. If set this option on, delete executor will automatically
 	// split data into multiple batches and use a single txn for each batch. This will be helpful when deleting large data.
 	TiDBBatchDelete = "tidb_batch_delete"
@@ -101,7 +108,7 @@ const (
 	TiDBBatchCommit = "tidb_batch_commit"
 
 	// tidb_dml_batch_size is used to split the insert/delete data into small batches.
-	// It only takes effort when tidb_batch_insert/tidb_batch_delete is on.
+	// It only takes effort when tidb_batch_insert/tidb_batch_delete/tidb_pipelined_insert_select is on.
 	// Its default value is 20000. When the row size is large, 20k rows could be larger than 100MB.
 	// User could change it to a smaller one to avoid breaking the transaction size limitation.
 	TiDBDMLBatchSize = "tidb_dml_batch_size"
@@ -167,6 +174,14 @@ const (
 	// TiDBReplicaRead is used for reading data from replicas, followers for example.
 	TiDBReplicaRead = "tidb_replica_read"
 
+	// TiDBAdaptiveClosestReadThreshold is the estimated response size in bytes of a statement, above which
+	// the statement reads from the closest replicas when tidb_replica_read is closest-adaptive.
+	TiDBAdaptiveClosestReadThreshold = "tidb_adaptive_closest_read_threshold"
+
+	// TiDBEnableDMLMaxExecutionTime indicates whether max_execution_time also applies to INSERT, REPLACE, UPDATE
+	// and DELETE statements.
+	TiDBEnableDMLMaxExecutionTime = "tidb_enable_dml_max_execution_time"
+
 	// TiDBAllowRemoveAutoInc indicates whether a user can drop the auto_increment column attribute or not.
 	TiDBAllowRemoveAutoInc = "tidb_allow_remove_auto_inc"
 
@@ -220,6 +235,10 @@ const (
 
 	// TiDBReadConsistency indicates whether the autocommit read statement goes through TiKV RC.
 	TiDBReadConsistency = "tidb_read_consistency"
+
+	// TiDBTableMapping routes the table references of DML statements to other tables, the format is
+	// 'db1.t1=db2.t2[,db3.t3=db4.t4...]'.
+	TiDBTableMapping = "tidb_table_mapping"
 )
 
 // TiDB system variable names that both in session and global scope.
@@ -335,6 +354,11 @@ const (
 	// TiDBInitChunkSize is used to control the init chunk size during query execution.
 	TiDBInitChunkSize = "tidb_init_chunk_size"
 
+	// TiDBMaxChunkBytes is used to control the estimated memory size of a chunk during query execution.
+	// The max row count of a chunk is computed from it and the estimated row width, and capped by tidb_max_chunk_size.
+	// 0 means the max row count of a chunk is always tidb_max_chunk_size.
+	TiDBMaxChunkBytes = "tidb_max_chunk_bytes"
+
 	// tidb_enable_cascades_planner is used to control whether to enable the cascades planner.
 	TiDBEnableCascadesPlanner = "tidb_enable_cascades_planner"
 
@@ -381,6 +405,13 @@ const (
 	// tidb_enable_parallel_apply is used for parallel apply.
 	TiDBEnableParallelApply = "tidb_enable_parallel_apply"
 
+	// tidb_apply_concurrency is used for the number of concurrent workers of parallel apply.
+	TiDBApplyConcurrency = "tidb_apply_concurrency"
+
+	// tidb_enable_parallel_hashagg_spill is used to control whether the hash aggregation can spill to disk
+	// by hash partitions, which are recursively repartitioned and merged in parallel.
+	TiDBEnableParallelHashAggSpill = "tidb_enable_parallel_hashagg_spill"
+
 	// tidb_backoff_lock_fast is used for tikv backoff base time in milliseconds.
 	TiDBBackoffLockFast = "tidb_backoff_lock_fast"
 
@@ -403,6 +434,17 @@ const (
 	// It can be: PRIORITY_LOW, PRIORITY_NORMAL, PRIORITY_HIGH
 	TiDBDDLReorgPriority = "tidb_ddl_reorg_priority"
 
+	// tidb_ddl_enable_fast_reorg indicates whether to add indexes by ingesting the sorted index data
+	// into the storage instead of backfilling it in transactions.
+	TiDBDDLEnableFastReorg = "tidb_ddl_enable_fast_reorg"
+
+	// tidb_ddl_disk_quota defines the disk quota of the local files used by the fast reorg.
+	TiDBDDLDiskQuota = "tidb_ddl_disk_quota"
+
+	// tidb_ddl_timeout defines the max time in milliseconds a DDL statement waits for its job to finish,
+	// including waiting for all the TiDB servers to load the new schema. 0 means no timeout.
+	TiDBDDLTimeout = "tidb_ddl_timeout"
+
 	// TiDBEnableChangeMultiSchema is used to control whether to enable the change multi schema.
 	TiDBEnableChangeMultiSchema = "tidb_enable_change_multi_schema"
 
@@ -614,6 +656,12 @@ const (
 
 	// TiDBStatsLoadSyncWait indicates the time sql execution will sync-wait for stats load.
 	TiDBStatsLoadSyncWait = "tidb_stats_load_sync_wait"
+
+	// TiDBEnableReuseChunk indicates whether the executors allocate chunks from the session level chunk allocator.
+	TiDBEnableReuseChunk = "tidb_enable_reuse_chunk"
+
+	// TiDBMetricsResourceGroup is the resource group label of the queries in the tenant query metrics.
+	TiDBMetricsResourceGroup = "tidb_metrics_resource_group"
 )
 
 // TiDB vars that have only global scope
@@ -643,6 +691,114 @@ const (
 	TiDBDisableColumnTrackingTime = "tidb_disable_column_tracking_time"
 	// TiDBStatsLoadPseudoTimeout indicates whether to fallback to pseudo stats after load timeout.
 	TiDBStatsLoadPseudoTimeout = "tidb_stats_load_pseudo_timeout"
+	// TiDBStatsCacheMemQuota is the memory quota of the stats cache in bytes, 0 means unlimited. The column histograms
+	// of the least recently used tables are evicted when the quota is exceeded, and they are loaded again when needed.
+	TiDBStatsCacheMemQuota = "tidb_stats_cache_mem_quota"
+	// TiDBEnableAutoAnalyzeAfterDDL indicates whether to analyze the new indexes, the truncated tables and partitions,
+	// and the new partitions automatically after the DDL finishes.
+	TiDBEnableAutoAnalyzeAfterDDL = "tidb_enable_auto_analyze_after_ddl"
+	// TiDBEnableStatsSketchMaintenance is an experimental feature, which keeps the TopN and NDV of the indexes fresh
+	// between analyzes by applying the index values changed by DML to the index statistics.
+	TiDBEnableStatsSketchMaintenance = "tidb_enable_stats_sketch_maintenance"
+	// TiDBSequenceCacheLimit is the max number of values a TiDB server caches for a sequence in one allocation,
+	// 0 means the CACHE value of the sequence is used.
+	TiDBSequenceCacheLimit = "tidb_sequence_cache_limit"
+
+	// TiDBEnableMDL indicates whether transactions hold metadata locks on the tables they access,
+	// so that DDL jobs wait for them instead of failing them with "Information schema is changed".
+	TiDBEnableMDL = "tidb_enable_metadata_lock"
+
+	// TiDBMDLWaitTimeout is the max time in seconds a schema change waits for the transactions holding the
+	// metadata locks of the changed tables. The locks of the transactions are revoked when it's exceeded,
+	// and the transactions fail with "Information schema is changed" if they write the tables.
+	TiDBMDLWaitTimeout = "tidb_metadata_lock_wait_timeout"
+
+	// TiDBEnableGeneralPlanCache indicates whether text protocol statements which only differ in constants
+	// reuse the plans in the plan cache. It takes effect only when the prepared plan cache is enabled.
+	TiDBEnableGeneralPlanCache = "tidb_enable_general_plan_cache"
+
+	// TiDBGeneralPlanCacheSize is the max number of the parameterized statements a session keeps for the general plan
+	// cache, it's an entry count rather than a memory size. Like the prepared plan cache, the statements are also evicted
+	// when the used memory reaches the guard set by prepared-plan-cache.memory-guard-ratio and
+	// performance.server-memory-quota.
+	TiDBGeneralPlanCacheSize = "tidb_general_plan_cache_size"
+
+	// TiDBEnableApproxCount indicates whether `SELECT COUNT(*) FROM t` without predicates is answered by
+	// the row count in the statistics instead of scanning the table. The statistics row count is refreshed
+	// from the committed writes every 20 stats leases and loaded every stats lease, so the result may lag
+	// behind the writes of the last 21 stats leases, which is about 1 minute with the default stats lease.
+	TiDBEnableApproxCount = "tidb_enable_approx_count"
+
+	// TiDBOptEnablePartitionWiseJoin indicates whether the joins of two partitioned tables are pushed below
+	// the union of their partitions, so each pair of the matching partitions is joined separately.
+	// It only takes effect in the static partition prune mode.
+	TiDBOptEnablePartitionWiseJoin = "tidb_opt_enable_partition_wise_join"
+
+	// TiDBExplainDeterministic indicates whether EXPLAIN ANALYZE masks the execution time and the memory and
+	// disk usage, which change from run to run, so the results can be compared in the regression tests.
+	TiDBExplainDeterministic = "tidb_explain_deterministic"
+
+	// TiDBCTEDedupUnionAll indicates whether the recursive CTEs with UNION ALL are deduplicated like UNION DISTINCT,
+	// so the traversals of the cyclic graphs terminate. Unlike the CYCLE clause of the SQL standard, it doesn't
+	// track the path of every row, a row is skipped if it has been produced before by any path.
+	TiDBCTEDedupUnionAll = "tidb_cte_dedup_union_all"
+
+	// TiDBCTETruncateOnLimit indicates whether the recursive CTEs return the rows produced so far with a
+	// warning instead of an error when the recursion reaches cte_max_recursion_depth or tidb_cte_mem_quota.
+	TiDBCTETruncateOnLimit = "tidb_cte_truncate_on_limit"
+
+	// TiDBCTEMemQuota is the memory quota in bytes of the intermediate results of a recursive CTE,
+	// 0 means unlimited.
+	TiDBCTEMemQuota = "tidb_cte_mem_quota"
+
+	// TiDBInsertIgnorePresenceFilter indicates whether the optimistic transactions remember the unique keys
+	// known to exist, so INSERT IGNORE skips the rows conflicting with them without looking up the keys again.
+	TiDBInsertIgnorePresenceFilter = "tidb_insert_ignore_presence_filter"
+
+	// TiDBOptExplainPrunedPaths indicates whether EXPLAIN FORMAT='verbose' also shows the access paths pruned
+	// by the skyline pruning and the reasons why they are pruned.
+	TiDBOptExplainPrunedPaths = "tidb_opt_explain_pruned_paths"
+
+	// TiDBEnablePGCompatFunctions indicates whether the functions and operators for the compatibility with PostgreSQL,
+	// such as ILIKE, SPLIT_PART and ARRAY_AGG, are enabled to ease the migrations of the applications.
+	TiDBEnablePGCompatFunctions = "tidb_enable_pg_compat_functions"
+
+	// TiDBEnableScanOperatorFusion indicates whether the cheap Selection and Projection on top of the TableReader
+	// are fused into it, so they are evaluated right after the rows are decoded without materializing the
+	// intermediate chunks.
+	TiDBEnableScanOperatorFusion = "tidb_enable_scan_operator_fusion"
+
+	// TiDBMaxResultRows is the max number of rows a SELECT statement returns to the client, 0 means unlimited.
+	// It protects the cluster from the accidental full table reads of the BI tools.
+	TiDBMaxResultRows = "tidb_max_result_rows"
+
+	// TiDBMaxResultRowsMode indicates what happens when a SELECT statement returns more rows than
+	// tidb_max_result_rows: 'error' fails the statement, 'warn' truncates the result with a warning.
+	TiDBMaxResultRowsMode = "tidb_max_result_rows_mode"
+
+	// TiDBSlowRPCThreshold is the threshold in milliseconds of a single coprocessor RPC. The region, store and
+	// TiKV execution details of the RPCs exceeding it are recorded in the slow log, 0 means disabled.
+	TiDBSlowRPCThreshold = "tidb_slow_rpc_threshold"
+
+	// TiDBSessionAlias is the alias of the session, such as the service or pod name of the application. It's attached
+	// to the logs, the slow log and the processlist to correlate them with the application. It can't contain the
+	// control characters.
+	TiDBSessionAlias = "tidb_session_alias"
+
+	// TiDBLockWaitTimeoutRetryLimit is the max number of times an auto-committed DML statement is retried in the
+	// server when its pessimistic lock wait times out, 0 means the lock wait timeout error is returned directly.
+	// When it's set and tidb_txn_mode is pessimistic, the auto-committed DML statements run in pessimistic
+	// transactions, so they wait for the conflicting locks instead of failing on them when committing.
+	TiDBLockWaitTimeoutRetryLimit = "tidb_lock_wait_timeout_retry_limit"
+
+	// TiDBLockWaitTimeoutRetryJitter is the max random backoff in milliseconds before retrying the statement whose
+	// lock wait times out, it spreads the retries of the statements waiting for the same lock.
+	TiDBLockWaitTimeoutRetryJitter = "tidb_lock_wait_timeout_retry_jitter"
+
+	// TiDBAnalyzeReadFromTiFlash indicates whether ANALYZE scans the rows of the tables from their available
+	// TiFlash replicas and samples them in TiDB, which offloads the sampling from the TiKV leaders serving the
+	// OLTP traffic. It only takes effect with tidb_analyze_version = 2.
+	TiDBAnalyzeReadFromTiFlash = "tidb_analyze_read_from_tiflash"
 )
 
 // TiDB intentional limits
@@ -652,6 +808,9 @@ const (
 	// MaxConfigurableConcurrency is the maximum number of "threads" (goroutines) that can be specified
 	// for any type of configuration item that has concurrent workers.
 	MaxConfigurableConcurrency = 256
+
+	// MaxSessionAliasLength is the max length in characters of tidb_session_alias.
+	MaxSessionAliasLength = 64
 )
 
 // Default TiDB system variable values.
@@ -694,11 +853,13 @@ const (
 	DefOptInSubqToJoinAndAgg              = true
 	DefOptPreferRangeScan                 = false
 	DefBatchInsert                        = false
+	DefPipelinedInsertSelect              = false
 	DefBatchDelete                        = false
 	DefBatchCommit                        = false
 	DefCurretTS                           = 0
 	DefInitChunkSize                      = 32
 	DefMaxChunkSize                       = 1024
+	DefMaxChunkBytes                      = 1 << 20
 	DefDMLBatchSize                       = 0
 	DefMaxPreparedStmtCount               = -1
 	DefWaitTimeout                        = 28800
@@ -731,6 +892,8 @@ const (
 	DefTiDBDDLReorgWorkerCount            = 4
 	DefTiDBDDLReorgBatchSize              = 256
 	DefTiDBDDLErrorCountLimit             = 512
+	DefTiDBDDLEnableFastReorg             = false
+	DefTiDBDDLDiskQuota                   = 100 * 1024 * 1024 * 1024 // 100GiB
 	DefTiDBMaxDeltaSchemaCount            = 1024
 	DefTiDBChangeMultiSchema              = false
 	DefTiDBPointGetCache                  = false
@@ -776,6 +939,8 @@ const (
 	DefTiDBShardAllocateStep              = math.MaxInt64
 	DefTiDBEnableTelemetry                = true
 	DefTiDBEnableParallelApply            = false
+	DefTiDBApplyConcurrency               = ConcurrencyUnset
+	DefTiDBEnableParallelHashAggSpill     = false
 	DefTiDBEnableAmendPessimisticTxn      = false
 	DefTiDBPartitionPruneMode             = "static"
 	DefTiDBEnableRateLimitAction          = true
@@ -809,6 +974,42 @@ const (
 	DefTiDBEnableColumnTracking           = false
 	DefTiDBStatsLoadSyncWait              = 0
 	DefTiDBStatsLoadPseudoTimeout         = false
+	DefTiDBStatsCacheMemQuota             = 0
+	DefTiDBEnableAutoAnalyzeAfterDDL      = false
+	DefTiDBEnableStatsSketchMaintenance   = false
+	DefTiDBSequenceCacheLimit             = 0
+	DefAdaptiveClosestReadThreshold       = 4096
+	DefTiDBEnableReuseChunk               = true
+	DefTiDBEnableDMLMaxExecutionTime      = false
+	DefTiDBDDLTimeout                     = 0
+	DefTiDBEnableMDL                      = false
+	DefTiDBMDLWaitTimeout                 = 60
+	DefTiDBEnableGeneralPlanCache         = false
+	DefTiDBGeneralPlanCacheSize           = 100
+	DefTiDBEnableApproxCount              = false
+	DefTiDBOptEnablePartitionWiseJoin     = false
+	DefTiDBExplainDeterministic           = false
+	DefTiDBCTEDedupUnionAll               = false
+	DefTiDBCTETruncateOnLimit             = false
+	DefTiDBCTEMemQuota                    = 0
+	DefTiDBInsertIgnorePresenceFilter     = false
+	DefTiDBOptExplainPrunedPaths          = false
+	DefTiDBEnablePGCompatFunctions        = false
+	DefTiDBEnableScanOperatorFusion       = true
+	DefTiDBMaxResultRows                  = 0
+	DefTiDBMaxResultRowsMode              = MaxResultRowsModeError
+	DefTiDBSlowRPCThreshold               = 0
+	DefTiDBLockWaitTimeoutRetryLimit      = 0
+	DefTiDBLockWaitTimeoutRetryJitter     = 100
+	DefTiDBAnalyzeReadFromTiFlash         = false
+)
+
+// The modes of tidb_max_result_rows_mode.
+const (
+	// MaxResultRowsModeError fails the statement which returns too many rows.
+	MaxResultRowsModeError = "error"
+	// MaxResultRowsModeWarn truncates the result of the statement which returns too many rows with a warning.
+	MaxResultRowsModeWarn = "warn"
 )
 
 // Process global variables.
@@ -842,4 +1043,11 @@ var (
 	EnableColumnTracking                  = atomic.NewBool(DefTiDBEnableColumnTracking)
 	StatsLoadSyncWait                     = atomic.NewInt64(DefTiDBStatsLoadSyncWait)
 	StatsLoadPseudoTimeout                = atomic.NewBool(DefTiDBStatsLoadPseudoTimeout)
+	StatsCacheMemQuota                    = atomic.NewInt64(DefTiDBStatsCacheMemQuota)
+	EnableAutoAnalyzeAfterDDL             = atomic.NewBool(DefTiDBEnableAutoAnalyzeAfterDDL)
+	EnableStatsSketchMaintenance          = atomic.NewBool(DefTiDBEnableStatsSketchMaintenance)
+	DDLEnableFastReorg                    = atomic.NewBool(DefTiDBDDLEnableFastReorg)
+	DDLDiskQuota                          = atomic.NewUint64(DefTiDBDDLDiskQuota)
+	EnableMDL                             = atomic.NewBool(DefTiDBEnableMDL)
+	MDLWaitTimeout                        = atomic.NewInt64(DefTiDBMDLWaitTimeout)
 )
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -366,7 +367,7 @@ func parseTimeZone(s string) (*time.Location, error) {
 		return timeutil.SystemLocation(), nil
 	}
 
-	loc, err := time.LoadLocation(s)
+	loc, err := timeutil.LoadLocation(s)
 	if err == nil {
 		return loc, nil
 	}
@@ -464,6 +465,17 @@ func setReadStaleness(s *SessionVars, sVal string) error {
 	return nil
 }
 
+// IsMetricsResourceGroup checks whether the resource group is one of the values allowed by
+// status.metrics-resource-groups for the resource group label of the tenant query metrics.
+func IsMetricsResourceGroup(name string) bool {
+	for _, group := range config.GetGlobalConfig().Status.MetricsResourceGroups {
+		if group == name {
+			return true
+		}
+	}
+	return false
+}
+
 func collectAllowFuncName4ExpressionIndex() string {
 	str := make([]string, 0, len(GAFunction4ExpressionIndex))
 	for funcName := range GAFunction4ExpressionIndex {
@@ -263,6 +263,7 @@ func BuildHistAndTopN(
 	}
 	curCnt := float64(0)
 	var corrXYSum float64
+	var profile sampleFrequencyProfile
 
 	// Iterate through the samples
 	for i := int64(0); i < sampleNum; i++ {
@@ -280,6 +281,7 @@ func BuildHistAndTopN(
 			continue
 		}
 		// case 2, meet a different value: counting for the "current" is complete
+		profile.add(uint64(curCnt))
 		// case 2-1, now topn is empty: append the "current" count directly
 		if len(topNList) == 0 {
 			topNList = append(topNList, TopNMeta{Encoded: cur, Count: uint64(curCnt)})
@@ -313,6 +315,7 @@ func BuildHistAndTopN(
 	}
 
 	// Handle the counting for the last value. Basically equal to the case 2 above.
+	profile.add(uint64(curCnt))
 	// now topn is empty: append the "current" count directly
 	if len(topNList) == 0 {
 		topNList = append(topNList, TopNMeta{Encoded: cur, Count: uint64(curCnt)})
@@ -332,6 +335,13 @@ func BuildHistAndTopN(
 		}
 	}
 
+	// The FM sketch tends to underestimate the ndv of a skewed column, so the estimation from the frequencies of the
+	// sampled values is used when it is larger.
+	if sampleNDV := int64(profile.estimateNDV(uint64(count))); sampleNDV > ndv {
+		ndv = sampleNDV
+		hg.NDV = ndv
+	}
+
 	topNList = pruneTopNItem(topNList, ndv, nullCount, sampleNum, count)
 
 	// Step2: exclude topn from samples
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"unsafe"
 
 	"github.com/pingcap/tidb/sessionctx"
 
@@ -569,6 +570,18 @@ func (c *TopN) Copy() *TopN {
 	}
 }
 
+// MemoryUsage returns the total memory usage of the TopN.
+func (c *TopN) MemoryUsage() (sum int64) {
+	if c == nil {
+		return
+	}
+	sum = int64(cap(c.TopN)) * int64(unsafe.Sizeof(TopNMeta{}))
+	for _, meta := range c.TopN {
+		sum += int64(cap(meta.Encoded))
+	}
+	return
+}
+
 // TopNMeta stores the unit of the TopN.
 type TopNMeta struct {
 	Encoded []byte
@@ -33,19 +33,82 @@ func calculateEstimateNDV(h *topNHelper, rowCount uint64) (ndv uint64, scaleRati
 		// Nothing to do, no change with scale ratio
 		return sampleNDV, scaleRatio
 	}
+	ndv = estimateNDVByGEE(float64(onlyOnceItems), float64(sampleNDV), float64(sampleSize), float64(rowCount))
+	ndv = mathutil.MaxUint64(ndv, sampleNDV)
+	ndv = mathutil.MinUint64(ndv, rowCount)
+	return ndv, scaleRatio
+}
+
+// estimateNDVByGEE estimates the ndv of a column with N rows from a sample of size n, which contains d distinct
+// values and f1 of them occur only once.
+func estimateNDVByGEE(f1, d, n, N float64) uint64 {
 	// Charikar, Moses, et al. "Towards estimation error guarantees for distinct values."
 	// Proceedings of the nineteenth ACM SIGMOD-SIGACT-SIGART symposium on Principles of database systems. ACM, 2000.
 	// This is GEE in that paper.
 	// estimateNDV = sqrt(N/n) f_1 + sum_2..inf f_i
 	// f_i = number of elements occurred i times in sample
+	return uint64(math.Sqrt(N/n)*f1 + d - f1 + 0.5)
+}
 
-	f1 := float64(onlyOnceItems)
-	n := float64(sampleSize)
-	N := float64(rowCount)
-	d := float64(sampleNDV)
+// sampleFrequencyProfile records how often the distinct values occur in a sample.
+type sampleFrequencyProfile struct {
+	sampleSize    uint64
+	sampleNDV     uint64
+	onlyOnceItems uint64
+	// squareSum is the sum of the squared occurrences of the distinct values.
+	squareSum float64
+}
 
-	ndv = uint64(math.Sqrt(N/n)*f1 + d - f1 + 0.5)
-	ndv = mathutil.MaxUint64(ndv, sampleNDV)
+// add records a distinct value which occurs cnt times in the sample.
+func (p *sampleFrequencyProfile) add(cnt uint64) {
+	if cnt == 0 {
+		return
+	}
+	p.sampleSize += cnt
+	p.sampleNDV++
+	if cnt == 1 {
+		p.onlyOnceItems++
+	}
+	p.squareSum += float64(cnt) * float64(cnt)
+}
+
+// isSkewed uses the chi-square test to check whether the sampled values are far from being uniformly distributed.
+func (p *sampleFrequencyProfile) isSkewed() bool {
+	if p.sampleNDV <= 1 {
+		return false
+	}
+	n, d := float64(p.sampleSize), float64(p.sampleNDV)
+	// sum((n_j - n/d)^2 / (n/d)) = d/n * sum(n_j^2) - n
+	chiSquare := d/n*p.squareSum - n
+	// The 0.975 quantile of the chi-square distribution with d-1 degrees of freedom, which is approximated by the
+	// Wilson-Hilferty transformation.
+	k := d - 1
+	critical := k * math.Pow(1-2/(9*k)+1.96*math.Sqrt(2/(9*k)), 3)
+	return chiSquare > critical
+}
+
+// estimateNDV estimates the ndv of a column with rowCount rows from the sample. It follows the hybrid estimator HYBGEE
+// in the paper of GEE: the sample is tested for skew first, a jackknife estimator is used when the values are
+// distributed almost uniformly and GEE is used otherwise, since the former underestimates the ndv badly on skewed
+// data while the latter is too conservative on uniform data.
+func (p *sampleFrequencyProfile) estimateNDV(rowCount uint64) uint64 {
+	if p.sampleSize == 0 || p.sampleSize >= rowCount {
+		return p.sampleNDV
+	}
+	f1, d := float64(p.onlyOnceItems), float64(p.sampleNDV)
+	n, N := float64(p.sampleSize), float64(rowCount)
+	var ndv uint64
+	if p.isSkewed() {
+		ndv = estimateNDVByGEE(f1, d, n, N)
+	} else {
+		// Haas, Peter J., et al. "Sampling-based estimation of the number of distinct values of an attribute."
+		// VLDB. Vol. 95. 1995.
+		// This is the unsmoothed first-order jackknife estimator D_uj1 in that paper.
+		// estimateNDV = d / (1 - (1 - q) f_1 / n), q = n / N
+		q := n / N
+		ndv = uint64(d/(1-(1-q)*f1/n) + 0.5)
+	}
+	ndv = mathutil.MaxUint64(ndv, p.sampleNDV)
 	ndv = mathutil.MinUint64(ndv, rowCount)
-	return ndv, scaleRatio
+	return ndv
 }
@@ -32,13 +32,18 @@ import (
 // HandleDDLEvent begins to process a ddl task.
 func (h *Handle) HandleDDLEvent(t *util.Event) error {
 	switch t.Tp {
-	case model.ActionCreateTable, model.ActionTruncateTable:
+	case model.ActionCreateTable, model.ActionTruncateTable, model.ActionAlterTableClusteredIndex:
 		ids := h.getInitStateTableIDs(t.TableInfo)
 		for _, id := range ids {
 			if err := h.insertTableStats2KV(t.TableInfo, id); err != nil {
 				return err
 			}
 		}
+		if t.Tp == model.ActionTruncateTable || t.Tp == model.ActionAlterTableClusteredIndex {
+			h.addDDLAnalyzeJob(&ddlAnalyzeJob{tableID: t.TableInfo.ID})
+		}
+	case model.ActionAddIndex, model.ActionAddPrimaryKey:
+		h.addDDLAnalyzeJob(&ddlAnalyzeJob{tableID: t.TableInfo.ID, indexID: t.IndexInfo.ID})
 	case model.ActionAddColumn, model.ActionAddColumns, model.ActionModifyColumn:
 		ids := h.getInitStateTableIDs(t.TableInfo)
 		for _, id := range ids {
@@ -47,11 +52,14 @@ func (h *Handle) HandleDDLEvent(t *util.Event) error {
 			}
 		}
 	case model.ActionAddTablePartition, model.ActionTruncateTablePartition:
+		partitionIDs := make([]int64, 0, len(t.PartInfo.Definitions))
 		for _, def := range t.PartInfo.Definitions {
 			if err := h.insertTableStats2KV(t.TableInfo, def.ID); err != nil {
 				return err
 			}
+			partitionIDs = append(partitionIDs, def.ID)
 		}
+		h.addDDLAnalyzeJob(&ddlAnalyzeJob{tableID: t.TableInfo.ID, partitionIDs: partitionIDs})
 	case model.ActionDropTablePartition:
 		pruneMode := h.CurrentPruneMode()
 		if pruneMode == variable.Dynamic && t.PartInfo != nil {
@@ -288,3 +296,31 @@ func finishTransaction(ctx context.Context, exec sqlexec.SQLExecutor, err error)
 	}
 	return errors.Trace(err)
 }
+
+// ddlAnalyzeJob is an analyze job triggered by the DDL event, it analyzes the whole table if
+// both partitionIDs and indexID are not set.
+type ddlAnalyzeJob struct {
+	tableID      int64
+	partitionIDs []int64
+	indexID      int64
+}
+
+func (h *Handle) addDDLAnalyzeJob(job *ddlAnalyzeJob) {
+	if !variable.EnableAutoAnalyzeAfterDDL.Load() {
+		return
+	}
+	h.ddlAnalyzeJobs.Lock()
+	h.ddlAnalyzeJobs.data = append(h.ddlAnalyzeJobs.data, job)
+	h.ddlAnalyzeJobs.Unlock()
+}
+
+func (h *Handle) popDDLAnalyzeJob() *ddlAnalyzeJob {
+	h.ddlAnalyzeJobs.Lock()
+	defer h.ddlAnalyzeJobs.Unlock()
+	if len(h.ddlAnalyzeJobs.data) == 0 {
+		return nil
+	}
+	job := h.ddlAnalyzeJobs.data[0]
+	h.ddlAnalyzeJobs.data = h.ddlAnalyzeJobs.data[1:]
+	return job
+}
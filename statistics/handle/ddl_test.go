@@ -264,3 +264,56 @@ PARTITION BY RANGE ( a ) (
 		}
 	})
 }
+
+func TestDDLAutoAnalyze(t *testing.T) {
+	store, do, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	testKit := testkit.NewTestKit(t, store)
+	testKit.MustExec("use test")
+	h := do.StatsHandle()
+	testKit.MustQuery("select @@global.tidb_enable_auto_analyze_after_ddl").Check(testkit.Rows("0"))
+	testKit.MustExec("set @@global.tidb_enable_auto_analyze_after_ddl = on")
+	defer testKit.MustExec("set @@global.tidb_enable_auto_analyze_after_ddl = off")
+	testKit.MustExec("create table tddl (a int, b int)")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	testKit.MustExec("insert into tddl values (1, 1), (2, 2), (3, 3)")
+	testKit.MustExec("analyze table tddl")
+	require.False(t, h.HandleDDLAutoAnalyze(do.InfoSchema()))
+
+	// The new index is analyzed.
+	testKit.MustExec("alter table tddl add index idx(a)")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	is := do.InfoSchema()
+	require.True(t, h.HandleDDLAutoAnalyze(is))
+	require.False(t, h.HandleDDLAutoAnalyze(is))
+	require.NoError(t, h.Update(is))
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("tddl"))
+	require.NoError(t, err)
+	statsTbl := h.GetTableStats(tbl.Meta())
+	idx := statsTbl.Indices[tbl.Meta().Indices[0].ID]
+	require.NotNil(t, idx)
+	require.Equal(t, int64(3), idx.Histogram.NDV)
+
+	// The truncated table is analyzed.
+	testKit.MustExec("truncate table tddl")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	is = do.InfoSchema()
+	require.True(t, h.HandleDDLAutoAnalyze(is))
+	testKit.MustQuery("select job_info, processed_rows from information_schema.analyze_status where table_name = 'tddl'").Check(
+		testkit.Rows("analyze table 3", "auto analyze table 3", "auto analyze table 0"))
+
+	// The new partitions are analyzed.
+	testKit.MustExec("create table ptddl (a int) partition by range (a) (partition p0 values less than (10))")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	testKit.MustExec("alter table ptddl add partition (partition p1 values less than (20))")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	is = do.InfoSchema()
+	require.True(t, h.HandleDDLAutoAnalyze(is))
+	testKit.MustQuery("select distinct partition_name from information_schema.analyze_status where table_name = 'ptddl'").Check(testkit.Rows("p1"))
+
+	// The jobs are skipped when the feature is disabled.
+	testKit.MustExec("set @@global.tidb_enable_auto_analyze_after_ddl = off")
+	testKit.MustExec("alter table ptddl add index idx(a)")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	require.False(t, h.HandleDDLAutoAnalyze(do.InfoSchema()))
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"sync"
@@ -41,6 +42,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/sqlexec"
@@ -90,6 +92,9 @@ type Handle struct {
 		sync.Mutex
 		atomic.Value
 		memTracker *memory.Tracker
+		// accessTime maps the physical ID to the last time its stats are retrieved, it's used to
+		// evict the least recently used stats when the memory quota of the cache is exceeded.
+		accessTime sync.Map
 	}
 
 	pool sessionPool
@@ -114,6 +119,16 @@ type Handle struct {
 		sync.Mutex
 		data colStatsUsageMap
 	}
+	// idxValueMap contains the changed index values from collectors, which are used to maintain the index statistics.
+	idxValueMap struct {
+		sync.Mutex
+		data indexValueDeltaMap
+	}
+	// ddlAnalyzeJobs contains the analyze jobs triggered by the DDL events.
+	ddlAnalyzeJobs struct {
+		sync.Mutex
+		data []*ddlAnalyzeJob
+	}
 
 	lease atomic2.Duration
 
@@ -179,6 +194,10 @@ func (h *Handle) Clear() {
 	h.statsCache.Lock()
 	h.statsCache.Store(statsCache{tables: make(map[int64]*statistics.Table)})
 	h.statsCache.memTracker = memory.NewTracker(memory.LabelForStatsCache, -1)
+	h.statsCache.accessTime.Range(func(key, _ interface{}) bool {
+		h.statsCache.accessTime.Delete(key)
+		return true
+	})
 	h.statsCache.Unlock()
 	for len(h.ddlEventCh) > 0 {
 		<-h.ddlEventCh
@@ -197,6 +216,9 @@ func (h *Handle) Clear() {
 	h.colMap.Lock()
 	h.colMap.data = make(colStatsUsageMap)
 	h.colMap.Unlock()
+	h.idxValueMap.Lock()
+	h.idxValueMap.data = make(indexValueDeltaMap)
+	h.idxValueMap.Unlock()
 	h.mu.rateMap = make(errorRateDeltaMap)
 	h.mu.Unlock()
 }
@@ -223,6 +245,7 @@ func NewHandle(ctx sessionctx.Context, lease time.Duration, pool sessionPool) (*
 	handle.globalMap.data = make(tableDeltaMap)
 	handle.feedback.data = statistics.NewQueryFeedbackMap()
 	handle.colMap.data = make(colStatsUsageMap)
+	handle.idxValueMap.data = make(indexValueDeltaMap)
 	handle.StatsLoad.SubCtxs = make([]sessionctx.Context, cfg.Performance.StatsLoadConcurrency)
 	handle.StatsLoad.NeededColumnsCh = make(chan *NeededColumnTask, cfg.Performance.StatsLoadQueueSize)
 	handle.StatsLoad.TimeoutColumnsCh = make(chan *NeededColumnTask, cfg.Performance.StatsLoadQueueSize)
@@ -317,6 +340,9 @@ func (h *Handle) Update(is infoschema.InfoSchema) error {
 		tbl.TblInfoUpdateTS = tableInfo.UpdateTS
 		tables = append(tables, tbl)
 	}
+	for _, id := range deletedTableIDs {
+		h.statsCache.accessTime.Delete(id)
+	}
 	h.updateStatsCache(oldCache.update(tables, deletedTableIDs, lastVersion))
 	return nil
 }
@@ -547,9 +573,63 @@ func (h *Handle) GetPartitionStats(tblInfo *model.TableInfo, pid int64) *statist
 		h.updateStatsCache(statsCache.update([]*statistics.Table{tbl}, nil, statsCache.version))
 		return tbl
 	}
+	if variable.StatsCacheMemQuota.Load() > 0 {
+		h.touchStatsCache(pid)
+	}
 	return tbl
 }
 
+// touchStatsCache records the access time of the stats of the physical table.
+func (h *Handle) touchStatsCache(physicalID int64) {
+	now := time.Now().UnixNano()
+	if v, ok := h.statsCache.accessTime.Load(physicalID); ok {
+		v.(*atomic2.Int64).Store(now)
+		return
+	}
+	h.statsCache.accessTime.Store(physicalID, atomic2.NewInt64(now))
+}
+
+// evictStatsCache drops the column histograms of the least recently used tables until the memory usage of
+// the cache is under the quota. The meta of the tables and the index histograms are kept. It must be called
+// with the lock of statsCache held, and the cache must not be visible to others yet.
+func (h *Handle) evictStatsCache(sc *statsCache, quota int64) {
+	type evictCandidate struct {
+		physicalID int64
+		accessTime int64
+	}
+	candidates := make([]evictCandidate, 0, len(sc.tables))
+	for id, tbl := range sc.tables {
+		if tbl.Pseudo {
+			continue
+		}
+		var accessTime int64
+		if v, ok := h.statsCache.accessTime.Load(id); ok {
+			accessTime = v.(*atomic2.Int64).Load()
+		}
+		candidates = append(candidates, evictCandidate{physicalID: id, accessTime: accessTime})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].accessTime < candidates[j].accessTime
+	})
+	evictedCount := 0
+	for _, c := range candidates {
+		if sc.memUsage <= quota {
+			break
+		}
+		tbl := sc.tables[c.physicalID]
+		newTbl := tbl.DropColumnHistograms()
+		oldMem, newMem := tbl.MemoryUsage(), newTbl.MemoryUsage()
+		if newMem >= oldMem {
+			continue
+		}
+		sc.tables[c.physicalID] = newTbl
+		sc.memUsage += newMem - oldMem
+		evictedCount++
+	}
+	logutil.BgLogger().Debug("[stats] evict column histograms from stats cache",
+		zap.Int("evictedTables", evictedCount), zap.Int64("memUsage", sc.memUsage), zap.Int64("quota", quota))
+}
+
 // updateStatsCache overrides the global statsCache with a new one, it may fail
 // if the global statsCache has been modified by others already.
 // Callers should add retry loop if necessary.
@@ -557,6 +637,9 @@ func (h *Handle) updateStatsCache(newCache statsCache) (updated bool) {
 	h.statsCache.Lock()
 	oldCache := h.statsCache.Load().(statsCache)
 	if oldCache.version < newCache.version || (oldCache.version == newCache.version && oldCache.minorVersion < newCache.minorVersion) {
+		if quota := variable.StatsCacheMemQuota.Load(); quota > 0 && newCache.memUsage > quota {
+			h.evictStatsCache(&newCache, quota)
+		}
 		h.statsCache.memTracker.Consume(newCache.memUsage - oldCache.memUsage)
 		h.statsCache.Store(newCache)
 		updated = true
@@ -579,7 +662,7 @@ func (sc statsCache) copy() statsCache {
 
 //initMemoryUsage calc total memory usage of statsCache and set statsCache.memUsage
 //should be called after the tables and their stats are initilazed
-func (sc statsCache) initMemoryUsage() {
+func (sc *statsCache) initMemoryUsage() {
 	sum := int64(0)
 	for _, tb := range sc.tables {
 		sum += tb.MemoryUsage()
@@ -1191,8 +1274,11 @@ func (h *Handle) SaveStatsToStorage(tableID int64, count int64, isIndex int, hg
 	if err != nil {
 		return errors.Trace(err)
 	}
+	return h.saveStatsToStorageInTxn(ctx, exec, txn.StartTS(), tableID, count, isIndex, hg, cms, topN, fms, statsVersion, isAnalyzed, needDumpFMS, updateAnalyzeTime)
+}
 
-	version := txn.StartTS()
+// saveStatsToStorageInTxn saves the stats to storage in the open transaction of h.mu.ctx with the given version.
+func (h *Handle) saveStatsToStorageInTxn(ctx context.Context, exec sqlexec.SQLExecutor, version uint64, tableID int64, count int64, isIndex int, hg *statistics.Histogram, cms *statistics.CMSketch, topN *statistics.TopN, fms *statistics.FMSketch, statsVersion int, isAnalyzed int64, needDumpFMS bool, updateAnalyzeTime bool) (err error) {
 	// If the count is less than 0, then we do not want to update the modify count and count.
 	if count >= 0 {
 		_, err = exec.ExecuteInternal(ctx, "replace into mysql.stats_meta (version, table_id, count) values (%?, %?, %?)", version, tableID, count)
@@ -1664,14 +1750,172 @@ func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, col
 
 func (h *Handle) fillExtendedStatsItemVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
 	switch item.Tp {
-	case ast.StatsTypeCardinality, ast.StatsTypeDependency:
-		return nil
+	case ast.StatsTypeDependency:
+		return h.fillExtStatsDependencyVals(item, cols, collectors)
+	case ast.StatsTypeCardinality:
+		return h.fillExtStatsCardinalityVals(item, cols, collectors)
 	case ast.StatsTypeCorrelation:
 		return h.fillExtStatsCorrVals(item, cols, collectors)
 	}
 	return nil
 }
 
+// extStatsSketchSize is the max size of the FMSketch used to count the distinct column groups in samples.
+const extStatsSketchSize = 10000
+
+func (h *Handle) fillExtStatsCardinalityVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
+	colOffsets := make([]int, 0, len(item.ColIDs))
+	for _, id := range item.ColIDs {
+		for i, col := range cols {
+			if col.ID == id {
+				colOffsets = append(colOffsets, i)
+				break
+			}
+		}
+	}
+	if len(colOffsets) != len(item.ColIDs) || len(colOffsets) < 2 {
+		return nil
+	}
+	for _, offset := range colOffsets {
+		if collectors[offset].FMSketch == nil {
+			return nil
+		}
+	}
+	h.mu.Lock()
+	sc := h.mu.ctx.GetSessionVars().StmtCtx
+	h.mu.Unlock()
+	rows := extStatsSampleRows(colOffsets, collectors)
+	groupSketch := statistics.NewFMSketch(extStatsSketchSize)
+	colSketches := make([]*statistics.FMSketch, len(colOffsets))
+	for i := range colSketches {
+		colSketches[i] = statistics.NewFMSketch(extStatsSketchSize)
+	}
+	for _, row := range rows {
+		if err := groupSketch.InsertRowValue(sc, row); err != nil {
+			return nil
+		}
+		for i := range row {
+			if err := colSketches[i].InsertValue(sc, row[i]); err != nil {
+				return nil
+			}
+		}
+	}
+	sampleNDV := float64(groupSketch.NDV())
+	if sampleNDV == 0 {
+		item.ScalarVals = 0
+		return item
+	}
+	// The NDV of the column group is scaled from the samples by the largest ratio between the NDV of a single column
+	// in the whole table and in the samples, then bounded by the NDVs of single columns and the row count.
+	lowerBound, upperBound, ratio := float64(1), float64(1), float64(1)
+	rowCount := float64(collectors[colOffsets[0]].Count + collectors[colOffsets[0]].NullCount)
+	for i, offset := range colOffsets {
+		colNDV := float64(collectors[offset].FMSketch.NDV())
+		lowerBound = math.Max(lowerBound, colNDV)
+		upperBound *= math.Max(colNDV, 1)
+		if colSampleNDV := colSketches[i].NDV(); colSampleNDV > 0 {
+			ratio = math.Max(ratio, colNDV/float64(colSampleNDV))
+		}
+	}
+	upperBound = math.Max(math.Min(upperBound, rowCount), lowerBound)
+	item.ScalarVals = math.Min(math.Max(sampleNDV*ratio, lowerBound), upperBound)
+	return item
+}
+
+// extStatsSampleRows matches the samples of the columns by the ordinal of the sampled rows. Rows with NULL values
+// in any of the columns are skipped, since they never match in equal conditions.
+func extStatsSampleRows(colOffsets []int, collectors []*statistics.SampleCollector) [][]types.Datum {
+	rowsByOrdinal := make(map[int][]types.Datum)
+	for i, offset := range colOffsets {
+		for _, sample := range collectors[offset].Samples {
+			row, ok := rowsByOrdinal[sample.Ordinal]
+			if !ok {
+				if i > 0 {
+					continue
+				}
+				row = make([]types.Datum, 0, len(colOffsets))
+			} else if len(row) != i {
+				continue
+			}
+			rowsByOrdinal[sample.Ordinal] = append(row, sample.Value)
+		}
+	}
+	rows := make([][]types.Datum, 0, len(rowsByOrdinal))
+	for _, row := range rowsByOrdinal {
+		if len(row) == len(colOffsets) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func (h *Handle) fillExtStatsDependencyVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
+	colOffsets := make([]int, 0, 2)
+	for _, id := range item.ColIDs {
+		for i, col := range cols {
+			if col.ID == id {
+				colOffsets = append(colOffsets, i)
+				break
+			}
+		}
+	}
+	if len(colOffsets) != 2 {
+		return nil
+	}
+	h.mu.Lock()
+	sc := h.mu.ctx.GetSessionVars().StmtCtx
+	h.mu.Unlock()
+	rows := extStatsSampleRows(colOffsets, collectors)
+	degrees := make([]float64, 2)
+	for i := range degrees {
+		degree, err := sampleDependencyDegree(sc, rows, i, 1-i)
+		if err != nil {
+			return nil
+		}
+		degrees[i] = degree
+	}
+	bytes, err := json.Marshal(degrees)
+	if err != nil {
+		return nil
+	}
+	item.StringVals = string(bytes)
+	return item
+}
+
+// sampleDependencyDegree computes the degree of the functional dependency `rows[from] -> rows[to]`, which is the
+// fraction of rows whose `to` value is the most common one among the rows with the same `from` value.
+func sampleDependencyDegree(sc *stmtctx.StatementContext, rows [][]types.Datum, from, to int) (float64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	groups := make(map[string]map[string]int)
+	for _, row := range rows {
+		fromKey, err := codec.EncodeKey(sc, nil, row[from])
+		if err != nil {
+			return 0, err
+		}
+		toKey, err := codec.EncodeKey(sc, nil, row[to])
+		if err != nil {
+			return 0, err
+		}
+		group, ok := groups[string(fromKey)]
+		if !ok {
+			group = make(map[string]int)
+			groups[string(fromKey)] = group
+		}
+		group[string(toKey)]++
+	}
+	matched := 0
+	for _, group := range groups {
+		maxCnt := 0
+		for _, cnt := range group {
+			maxCnt = mathutil.Max(maxCnt, cnt)
+		}
+		matched += maxCnt
+	}
+	return float64(matched) / float64(len(rows)), nil
+}
+
 func (h *Handle) fillExtStatsCorrVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
 	colOffsets := make([]int, 0, 2)
 	for _, id := range item.ColIDs {
@@ -202,6 +202,62 @@ func isSameExtendedStats(a, b *statistics.ExtendedStatsColl) bool {
 	return true
 }
 
+func TestStatsCacheMemQuota(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	testKit := testkit.NewTestKit(t, store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t1 (a int, b int, index ia(a))")
+	testKit.MustExec("create table t2 (a int, b int, index ia(a))")
+	testKit.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	testKit.MustExec("insert into t2 values (1, 1), (2, 2), (3, 3)")
+	testKit.MustExec("analyze table t1, t2")
+	defer testKit.MustExec("set @@global.tidb_stats_cache_mem_quota = default")
+
+	h := dom.StatsHandle()
+	is := dom.InfoSchema()
+	tbl1, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	require.NoError(t, err)
+	tbl2, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t2"))
+	require.NoError(t, err)
+	tblInfo1, tblInfo2 := tbl1.Meta(), tbl2.Meta()
+	colID := tblInfo1.Columns[1].ID
+	memUsage := h.GetMemConsumed()
+	require.Greater(t, memUsage, int64(0))
+
+	// Access t1 earlier than t2, so the columns of t1 are evicted first.
+	testKit.MustExec(fmt.Sprintf("set @@global.tidb_stats_cache_mem_quota = %d", memUsage*2))
+	h.GetTableStats(tblInfo1)
+	time.Sleep(time.Millisecond)
+	h.GetTableStats(tblInfo2)
+	testKit.MustExec(fmt.Sprintf("set @@global.tidb_stats_cache_mem_quota = %d", memUsage-1))
+	testKit.MustQuery("select @@global.tidb_stats_cache_mem_quota").Check(testkit.Rows(strconv.FormatInt(memUsage-1, 10)))
+	h.SetLastUpdateVersion(h.LastUpdateVersion() + 1)
+	require.LessOrEqual(t, h.GetMemConsumed(), memUsage-1)
+
+	stats1 := h.GetTableStats(tblInfo1)
+	require.False(t, stats1.Pseudo)
+	require.Equal(t, int64(3), stats1.Count)
+	require.True(t, stats1.Columns[colID].IsHistNeeded(stats1.Pseudo))
+	require.False(t, stats1.Indices[tblInfo1.Indices[0].ID].IsInvalid(false))
+	stats2 := h.GetTableStats(tblInfo2)
+	require.False(t, stats2.Columns[colID].IsHistNeeded(stats2.Pseudo))
+
+	// The evicted columns are loaded again when needed, and t2 becomes the least recently used one.
+	memUsage = h.GetMemConsumed()
+	testKit.MustExec(fmt.Sprintf("set @@global.tidb_stats_cache_mem_quota = %d", memUsage))
+	time.Sleep(time.Millisecond)
+	stats1 = h.GetTableStats(tblInfo1)
+	_, err = stats1.ColumnEqualRowCount(testKit.Session(), types.NewIntDatum(1), colID)
+	require.NoError(t, err)
+	require.NoError(t, h.LoadNeededHistograms())
+	stats1 = h.GetTableStats(tblInfo1)
+	require.False(t, stats1.Columns[colID].IsHistNeeded(stats1.Pseudo))
+	stats2 = h.GetTableStats(tblInfo2)
+	require.True(t, stats2.Columns[colID].IsHistNeeded(stats2.Pseudo))
+	require.LessOrEqual(t, h.GetMemConsumed(), memUsage)
+}
+
 func TestStatsStoreAndLoad(t *testing.T) {
 	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
 	defer clean()
@@ -2036,6 +2092,48 @@ func TestCorrelationStatsCompute(t *testing.T) {
 	))
 }
 
+func TestCardinalityStatsCompute(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int)")
+	tk.MustExec("insert into t values(1,1,1),(1,1,2),(2,2,1),(2,2,2),(1,1,1),(2,2,2),(null,1,1)")
+	err := tk.ExecToErr("alter table t add stats_extended s1 cardinality(a)")
+	require.Equal(t, "Only support Cardinality statistics type on at least 2 columns", err.Error())
+	tk.MustExec("alter table t add stats_extended s1 cardinality(a,b)")
+	tk.MustExec("alter table t add stats_extended s2 cardinality(a,c)")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"0 [1,2] <nil> 0",
+		"0 [1,3] <nil> 0",
+	))
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"0 [1,2] 2.000000 1",
+		"0 [1,3] 4.000000 1",
+	))
+}
+
+func TestDependencyStatsCompute(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int)")
+	tk.MustExec("insert into t values(1,1,1),(1,1,2),(2,2,1),(2,2,2),(1,1,1),(2,2,2),(null,1,1)")
+	err := tk.ExecToErr("alter table t add stats_extended s1 dependency(a,b,c)")
+	require.Equal(t, "Only support Correlation and Dependency statistics types on 2 columns", err.Error())
+	tk.MustExec("alter table t add stats_extended s1 dependency(a,b)")
+	tk.MustExec("alter table t add stats_extended s2 dependency(c,a)")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"1 [1,2] [1,1] 1",
+		"1 [1,3] [0.6666666666666666,0.6666666666666666] 1",
+	))
+}
+
 func TestSyncStatsExtendedRemoval(t *testing.T) {
 	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
 	defer clean()
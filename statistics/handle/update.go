@@ -146,9 +146,29 @@ func (m colStatsUsageMap) merge(other colStatsUsageMap) {
 	}
 }
 
-func merge(s *SessionStatsCollector, deltaMap tableDeltaMap, rateMap errorRateDeltaMap, feedback *statistics.QueryFeedbackMap, colMap colStatsUsageMap) {
+// indexValueDeltaMap maps the physical table ID to the changed index values of the table.
+type indexValueDeltaMap map[int64]variable.IndexValueDelta
+
+func (m indexValueDeltaMap) update(id int64, delta variable.IndexValueDelta) {
+	item := m[id]
+	if item == nil {
+		item = make(variable.IndexValueDelta)
+		m[id] = item
+	}
+	item.Merge(delta)
+}
+
+func (m indexValueDeltaMap) merge(deltaMap indexValueDeltaMap) {
+	for id, item := range deltaMap {
+		m.update(id, item)
+	}
+}
+
+func merge(s *SessionStatsCollector, deltaMap tableDeltaMap, rateMap errorRateDeltaMap, feedback *statistics.QueryFeedbackMap, colMap colStatsUsageMap, idxValueMap indexValueDeltaMap) {
 	deltaMap.merge(s.mapper)
 	s.mapper = make(tableDeltaMap)
+	idxValueMap.merge(s.idxValueMap)
+	s.idxValueMap = make(indexValueDeltaMap)
 	rateMap.merge(s.rateMap)
 	s.rateMap = make(errorRateDeltaMap)
 	feedback.Merge(s.feedback)
@@ -165,7 +185,9 @@ type SessionStatsCollector struct {
 	feedback *statistics.QueryFeedbackMap
 	rateMap  errorRateDeltaMap
 	colMap   colStatsUsageMap
-	next     *SessionStatsCollector
+	// idxValueMap is the changed index values used to maintain the index statistics incrementally.
+	idxValueMap indexValueDeltaMap
+	next        *SessionStatsCollector
 	// deleted is set to true when a session is closed. Every time we sweep the list, we will remove the useless collector.
 	deleted bool
 }
@@ -184,6 +206,13 @@ func (s *SessionStatsCollector) Update(id int64, delta int64, count int64, colSi
 	s.mapper.update(id, delta, count, colSize)
 }
 
+// UpdateIndexValueDelta merges the changed index values of one table id.
+func (s *SessionStatsCollector) UpdateIndexValueDelta(id int64, delta variable.IndexValueDelta) {
+	s.Lock()
+	defer s.Unlock()
+	s.idxValueMap.update(id, delta)
+}
+
 var (
 	// MinLogScanCount is the minimum scan count for a feedback to be logged.
 	MinLogScanCount = atomic.NewInt64(1000)
@@ -237,6 +266,8 @@ func (h *Handle) NewSessionStatsCollector() *SessionStatsCollector {
 		next:     h.listHead.next,
 		feedback: statistics.NewQueryFeedbackMap(),
 		colMap:   make(colStatsUsageMap),
+
+		idxValueMap: make(indexValueDeltaMap),
 	}
 	h.listHead.next = newCollector
 	return newCollector
@@ -409,12 +440,13 @@ func (h *Handle) sweepList() {
 	errorRateMap := make(errorRateDeltaMap)
 	feedback := statistics.NewQueryFeedbackMap()
 	colMap := make(colStatsUsageMap)
+	idxValueMap := make(indexValueDeltaMap)
 	prev := h.listHead
 	prev.Lock()
 	for curr := prev.next; curr != nil; curr = curr.next {
 		curr.Lock()
 		// Merge the session stats into deltaMap, errorRateMap and feedback respectively.
-		merge(curr, deltaMap, errorRateMap, feedback, colMap)
+		merge(curr, deltaMap, errorRateMap, feedback, colMap, idxValueMap)
 		if curr.deleted {
 			prev.next = curr.next
 			// Since the session is already closed, we can safely unlock it here.
@@ -439,6 +471,9 @@ func (h *Handle) sweepList() {
 	h.colMap.Lock()
 	h.colMap.data.merge(colMap)
 	h.colMap.Unlock()
+	h.idxValueMap.Lock()
+	h.idxValueMap.data.merge(idxValueMap)
+	h.idxValueMap.Unlock()
 }
 
 // DumpStatsDeltaToKV sweeps the whole list and updates the global map, then we dumps every table that held in map to KV.
@@ -873,6 +908,96 @@ func (h *Handle) dumpStatsUpdateToKV(tableID, isIndex int64, q *statistics.Query
 	return errors.Trace(err)
 }
 
+// DumpIndexValueDeltaToKV sweeps the whole list, applies the changed index values to the index statistics
+// and dumps the updated statistics to KV, so the TopN and NDV of the indexes written frequently are kept
+// fresh between analyzes. It only works when tidb_enable_stats_sketch_maintenance is on.
+func (h *Handle) DumpIndexValueDeltaToKV(is infoschema.InfoSchema) error {
+	if !variable.EnableStatsSketchMaintenance.Load() {
+		return nil
+	}
+	h.sweepList()
+	h.idxValueMap.Lock()
+	idxValueMap := h.idxValueMap.data
+	h.idxValueMap.data = make(indexValueDeltaMap)
+	h.idxValueMap.Unlock()
+	for physicalID, delta := range idxValueMap {
+		h.mu.Lock()
+		table, ok := h.getTableByPhysicalID(is, physicalID)
+		h.mu.Unlock()
+		// The partitioned tables are not supported yet.
+		if !ok || table.Meta().GetPartitionInfo() != nil {
+			continue
+		}
+		err := h.applyIndexValueDelta(table.Meta(), physicalID, delta)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// applyIndexValueDelta applies the changed index values to the latest index statistics in storage in one
+// pessimistic transaction. The stats_meta row of the table is locked first, which is written by all the
+// writers of the statistics, so the concurrent TiDB servers don't lose the updates of each other.
+func (h *Handle) applyIndexValueDelta(tblInfo *model.TableInfo, physicalID int64, delta variable.IndexValueDelta) (err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := context.TODO()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(ctx, "begin pessimistic")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		err = finishTransaction(context.Background(), exec, err)
+	}()
+	rs, err := exec.ExecuteInternal(ctx, "select version from mysql.stats_meta where table_id = %? for update", physicalID)
+	if err != nil {
+		return err
+	}
+	rows, err := sqlexec.DrainRecordSet(ctx, rs, h.mu.ctx.GetSessionVars().MaxChunkSize)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	// Read the statistics at the for update ts, which are the latest ones since the row is locked.
+	version := h.mu.ctx.GetSessionVars().TxnCtx.GetForUpdateTS()
+	reader, err := h.getStatsReader(version, h.mu.ctx.(sqlexec.RestrictedSQLExecutor))
+	if err != nil {
+		return err
+	}
+	rows, _, err = reader.read("select table_id, is_index, hist_id, distinct_count, version, null_count, tot_col_size, stats_ver, flag, correlation, last_analyze_pos from mysql.stats_histograms where table_id = %? and is_index = 1", physicalID)
+	if err != nil {
+		return err
+	}
+	tblStats := &statistics.Table{
+		HistColl: statistics.HistColl{PhysicalID: physicalID, HavePhysicalID: true, Indices: make(map[int64]*statistics.Index, len(delta))},
+	}
+	for _, row := range rows {
+		if values := delta[row.GetInt64(2)]; len(values) == 0 {
+			continue
+		}
+		if err = h.indexStatsFromStorage(reader, row, tblStats, tblInfo); err != nil {
+			return err
+		}
+	}
+	for idxID, values := range delta {
+		// The overflowed indexes are skipped, whose values are nil.
+		if len(values) == 0 {
+			continue
+		}
+		newIdx, ok := statistics.UpdateIndexByValueDelta(tblStats.Indices[idxID], values)
+		if !ok {
+			continue
+		}
+		err = h.saveStatsToStorageInTxn(ctx, exec, version, physicalID, -1, 1, &newIdx.Histogram, newIdx.CMSketch, newIdx.TopN, newIdx.FMSketch, int(newIdx.StatsVer), 0, newIdx.FMSketch != nil, false)
+		metrics.UpdateStatsCounter.WithLabelValues(metrics.RetLabel(err)).Inc()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DumpColStatsUsageToKV sweeps the whole list, updates the column stats usage map and dumps it to KV.
 func (h *Handle) DumpColStatsUsageToKV() error {
 	if !variable.EnableColumnTracking.Load() {
@@ -1080,6 +1205,87 @@ func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
 	return false
 }
 
+// HandleDDLAutoAnalyze runs the analyze jobs triggered by the DDL events, such as adding indexes, truncating
+// tables and adding partitions. Unlike HandleAutoAnalyze, it ignores the auto analyze period and ratio, so the
+// stats of the changed objects are collected as soon as possible. It runs one job at a time.
+func (h *Handle) HandleDDLAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
+	for job := h.popDDLAnalyzeJob(); job != nil; job = h.popDDLAnalyzeJob() {
+		if !variable.EnableAutoAnalyzeAfterDDL.Load() {
+			continue
+		}
+		tblInfo, sql, params, ok := job.buildSQL(is)
+		if !ok {
+			continue
+		}
+		if err := h.UpdateSessionVar(); err != nil {
+			logutil.BgLogger().Error("[stats] update analyze version for auto analyze session failed", zap.Error(err))
+			return false
+		}
+		escaped, err := sqlexec.EscapeSQL(sql, params...)
+		if err != nil {
+			continue
+		}
+		logutil.BgLogger().Info("[stats] auto analyze triggered by DDL", zap.String("sql", escaped))
+		tableStatsVer := h.mu.ctx.GetSessionVars().AnalyzeVersion
+		statistics.CheckAnalyzeVerOnTable(h.GetTableStats(tblInfo), &tableStatsVer)
+		h.execAutoAnalyze(tableStatsVer, sql, params...)
+		return true
+	}
+	return false
+}
+
+// buildSQL builds the analyze statement of the job, ok is false if the objects to analyze no longer exist.
+func (job *ddlAnalyzeJob) buildSQL(is infoschema.InfoSchema) (tblInfo *model.TableInfo, sql string, params []interface{}, ok bool) {
+	tbl, ok := is.TableByID(job.tableID)
+	if !ok {
+		return nil, "", nil, false
+	}
+	tblInfo = tbl.Meta()
+	db, ok := is.SchemaByTable(tblInfo)
+	if !ok || util.IsMemOrSysDB(db.Name.L) {
+		return nil, "", nil, false
+	}
+	var sqlBuilder strings.Builder
+	sqlBuilder.WriteString("analyze table %n.%n")
+	params = []interface{}{db.Name.O, tblInfo.Name.O}
+	if len(job.partitionIDs) > 0 {
+		pi := tblInfo.GetPartitionInfo()
+		if pi == nil {
+			return nil, "", nil, false
+		}
+		for _, pid := range job.partitionIDs {
+			for _, def := range pi.Definitions {
+				if def.ID != pid {
+					continue
+				}
+				if len(params) == 2 {
+					sqlBuilder.WriteString(" partition %n")
+				} else {
+					sqlBuilder.WriteString(", %n")
+				}
+				params = append(params, def.Name.O)
+			}
+		}
+		if len(params) == 2 {
+			return nil, "", nil, false
+		}
+	}
+	if job.indexID != 0 {
+		var idxName string
+		for _, idx := range tblInfo.Indices {
+			if idx.ID == job.indexID && idx.State == model.StatePublic {
+				idxName = idx.Name.O
+			}
+		}
+		if idxName == "" {
+			return nil, "", nil, false
+		}
+		sqlBuilder.WriteString(" index %n")
+		params = append(params, idxName)
+	}
+	return tblInfo, sqlBuilder.String(), params, true
+}
+
 func (h *Handle) autoAnalyzeTable(tblInfo *model.TableInfo, statsTbl *statistics.Table, start, end time.Time, ratio float64, sql string, params ...interface{}) bool {
 	if statsTbl.Pseudo || statsTbl.Count < AutoAnalyzeMinCnt {
 		return false
@@ -1225,6 +1225,71 @@ func TestUpdateStatsByLocalFeedback(t *testing.T) {
 	h.UpdateStatsByLocalFeedback(dom.InfoSchema())
 }
 
+func TestMaintainIndexStatsByValueDelta(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	testKit := testkit.NewTestKit(t, store)
+	testKit.MustExec("use test")
+	testKit.MustExec("set @@session.tidb_analyze_version = 2")
+	testKit.MustExec("create table t (a int, b int, index idx(a))")
+	testKit.MustExec("insert into t values (1, 1), (1, 1), (1, 1), (1, 1), (2, 2), (3, 3), (4, 4), (5, 5)")
+	testKit.MustExec("analyze table t with 1 topn, 2 buckets")
+	testKit.MustExec("set @@global.tidb_enable_stats_sketch_maintenance = 1")
+	defer testKit.MustExec("set @@global.tidb_enable_stats_sketch_maintenance = 0")
+
+	h := dom.StatsHandle()
+	is := dom.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tblInfo := tbl.Meta()
+	idxID := tblInfo.Indices[0].ID
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	encode := func(v int64) []byte {
+		b, err := codec.EncodeKey(sc, nil, types.NewIntDatum(v))
+		require.NoError(t, err)
+		return b
+	}
+	idx := h.GetTableStats(tblInfo).Indices[idxID]
+	require.Equal(t, int64(5), idx.NDV)
+	count, ok := idx.TopN.QueryTopN(encode(1))
+	require.True(t, ok)
+	require.Equal(t, uint64(4), count)
+
+	// The rows which only update the non-indexed columns are not recorded.
+	testKit.MustExec("update t set b = 10 where a = 2")
+	testKit.MustExec("delete from t where a = 1 limit 2")
+	for i := 0; i < 3; i++ {
+		testKit.MustExec("insert into t values (10, 10)")
+	}
+	require.NoError(t, h.DumpStatsDeltaToKV(handle.DumpAll))
+	require.NoError(t, h.DumpIndexValueDeltaToKV(is))
+	require.NoError(t, h.Update(is))
+	idx = h.GetTableStats(tblInfo).Indices[idxID]
+	// The new value 10 is counted in the NDV and replaces 1 in the TopN.
+	require.Equal(t, int64(6), idx.NDV)
+	count, ok = idx.TopN.QueryTopN(encode(10))
+	require.True(t, ok)
+	require.Equal(t, uint64(3), count)
+	_, ok = idx.TopN.QueryTopN(encode(1))
+	require.False(t, ok)
+
+	testKit.MustExec("delete from t where a = 10")
+	require.NoError(t, h.DumpStatsDeltaToKV(handle.DumpAll))
+	require.NoError(t, h.DumpIndexValueDeltaToKV(is))
+	require.NoError(t, h.Update(is))
+	idx = h.GetTableStats(tblInfo).Indices[idxID]
+	require.Equal(t, int64(5), idx.NDV)
+	require.Equal(t, 0, idx.TopN.Num())
+
+	// Nothing is maintained when the feature is off.
+	testKit.MustExec("set @@global.tidb_enable_stats_sketch_maintenance = 0")
+	testKit.MustExec("insert into t values (20, 20)")
+	require.NoError(t, h.DumpStatsDeltaToKV(handle.DumpAll))
+	require.NoError(t, h.DumpIndexValueDeltaToKV(is))
+	require.NoError(t, h.Update(is))
+	require.Equal(t, int64(5), h.GetTableStats(tblInfo).Indices[idxID].NDV)
+}
+
 func TestUpdatePartitionStatsByLocalFeedback(t *testing.T) {
 	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
 	defer clean()
@@ -516,6 +516,31 @@ func (c *Column) BetweenRowCount(sctx sessionctx.Context, l, r types.Datum, lowE
 	return float64(c.TopN.BetweenCount(lowEncoded, highEncoded)) + histBetweenCnt
 }
 
+// traceBetweenRowCount records the histogram buckets and TopN entries used to estimate the interval [l, r).
+func (c *Column) traceBetweenRowCount(sctx sessionctx.Context, l, r types.Datum, lowEncoded, highEncoded []byte, cnt float64) {
+	if !sctx.GetSessionVars().StmtCtx.EnableOptimizerCETrace {
+		return
+	}
+	topNCnt := uint64(0)
+	if c.StatsVer >= Version2 {
+		topNCnt = c.TopN.BetweenCount(lowEncoded, highEncoded)
+	}
+	ceTraceDetail(sctx, "[%s, %s): %s, TopN %d rows, total %.2f rows", ceTraceValue(l), ceTraceValue(r), c.Histogram.bucketsInRange(l, r), topNCnt, cnt)
+}
+
+// bucketsInRange describes the buckets which the interval [l, r) falls into.
+func (hg *Histogram) bucketsInRange(l, r types.Datum) string {
+	if hg.Len() == 0 {
+		return "no histogram buckets"
+	}
+	_, lIdx := hg.LessRowCountWithBktIdx(l)
+	_, rIdx := hg.LessRowCountWithBktIdx(r)
+	if lIdx == rIdx {
+		return fmt.Sprintf("histogram bucket #%d of %d", lIdx, hg.Len())
+	}
+	return fmt.Sprintf("histogram buckets #%d-#%d of %d", lIdx, rIdx, hg.Len())
+}
+
 // TotalRowCount returns the total count of this histogram.
 func (hg *Histogram) TotalRowCount() float64 {
 	return hg.notNullCount() + float64(hg.NullCount)
@@ -1052,13 +1077,14 @@ func (c *Column) GetIncreaseFactor(realtimeRowCount int64) float64 {
 	return float64(realtimeRowCount) / columnCount
 }
 
-// MemoryUsage returns the total memory usage of Histogram and CMSketch in Column.
+// MemoryUsage returns the total memory usage of Histogram, CMSketch, TopN and FMSketch in Column.
 // We ignore the size of other metadata in Column
 func (c *Column) MemoryUsage() (sum int64) {
 	sum = c.Histogram.MemoryUsage()
 	if c.CMSketch != nil {
 		sum += c.CMSketch.MemoryUsage()
 	}
+	sum += c.TopN.MemoryUsage()
 	if c.FMSketch != nil {
 		sum += c.FMSketch.MemoryUsage()
 	}
@@ -1098,46 +1124,57 @@ func (c *Column) IsHistNeeded(collPseudo bool) bool {
 
 func (c *Column) equalRowCount(sctx sessionctx.Context, val types.Datum, encodedVal []byte, realtimeRowCount int64) (float64, error) {
 	if val.IsNull() {
+		ceTraceDetail(sctx, "NULL: null count %d", c.NullCount)
 		return float64(c.NullCount), nil
 	}
 	if c.StatsVer < Version2 {
 		// All the values are null.
 		if c.Histogram.Bounds.NumRows() == 0 {
+			ceTraceDetail(sctx, "%s: all the values are NULL", ceTraceValue(val))
 			return 0.0, nil
 		}
 		if c.Histogram.NDV > 0 && c.outOfRange(val) {
-			return outOfRangeEQSelectivity(c.Histogram.NDV, realtimeRowCount, int64(c.TotalRowCount())) * c.TotalRowCount(), nil
+			count := outOfRangeEQSelectivity(c.Histogram.NDV, realtimeRowCount, int64(c.TotalRowCount())) * c.TotalRowCount()
+			ceTraceDetail(sctx, "%s: out of the histogram range, estimated %.2f rows", ceTraceValue(val), count)
+			return count, nil
 		}
 		if c.CMSketch != nil {
 			count, err := queryValue(sctx.GetSessionVars().StmtCtx, c.CMSketch, c.TopN, val)
+			ceTraceDetail(sctx, "%s: CMSketch returns %d rows", ceTraceValue(val), count)
 			return float64(count), errors.Trace(err)
 		}
 		histRowCount, _ := c.Histogram.equalRowCount(val, false)
+		ceTraceDetail(sctx, "%s: histogram returns %.2f rows", ceTraceValue(val), histRowCount)
 		return histRowCount, nil
 	}
 
 	// Stats version == 2
 	// All the values are null.
 	if c.Histogram.Bounds.NumRows() == 0 && c.TopN.Num() == 0 {
+		ceTraceDetail(sctx, "%s: all the values are NULL", ceTraceValue(val))
 		return 0, nil
 	}
 	// 1. try to find this value in TopN
 	if c.TopN != nil {
 		rowcount, ok := c.QueryTopN(encodedVal)
 		if ok {
+			ceTraceDetail(sctx, "%s: TopN hit, %d rows", ceTraceValue(val), rowcount)
 			return float64(rowcount), nil
 		}
 	}
 	// 2. try to find this value in bucket.Repeat(the last value in every bucket)
 	histCnt, matched := c.Histogram.equalRowCount(val, true)
 	if matched {
+		ceTraceDetail(sctx, "%s: histogram bucket hit, %.2f rows", ceTraceValue(val), histCnt)
 		return histCnt, nil
 	}
 	// 3. use uniform distribution assumption for the rest (even when this value is not covered by the range of stats)
 	histNDV := float64(c.Histogram.NDV - int64(c.TopN.Num()))
 	if histNDV <= 0 {
+		ceTraceDetail(sctx, "%s: not in TopN and no NDV left in the histogram", ceTraceValue(val))
 		return 0, nil
 	}
+	ceTraceDetail(sctx, "%s: not in TopN or bucket bounds, uniform estimation %.0f/%.0f", ceTraceValue(val), c.Histogram.notNullCount(), histNDV)
 	return c.Histogram.notNullCount() / histNDV, nil
 }
 
@@ -1208,6 +1245,7 @@ func (c *Column) GetColumnRowCount(sctx sessionctx.Context, ranges []*ranger.Ran
 
 		// case 3: it's an interval
 		cnt := c.BetweenRowCount(sctx, lowVal, highVal, lowEncoded, highEncoded)
+		c.traceBetweenRowCount(sctx, lowVal, highVal, lowEncoded, highEncoded, cnt)
 		// `betweenRowCount` returns count for [l, h) range, we adjust cnt for boundaries here.
 		// Note that, `cnt` does not include null values, we need specially handle cases
 		// where null is the lower bound.
@@ -1244,11 +1282,18 @@ func (c *Column) GetColumnRowCount(sctx sessionctx.Context, ranges []*ranger.Ran
 			if increaseCount < 0 {
 				increaseCount = 0
 			}
-			cnt += c.Histogram.outOfRangeRowCount(&lowVal, &highVal, increaseCount)
+			outOfRangeCnt := c.Histogram.outOfRangeRowCount(&lowVal, &highVal, increaseCount)
+			if outOfRangeCnt > 0 {
+				ceTraceDetail(sctx, "range is out of the histogram, added %.2f rows", outOfRangeCnt)
+			}
+			cnt += outOfRangeCnt
 		}
 
 		rowCount += cnt
 	}
+	if factor := c.GetIncreaseFactor(realtimeRowCount); factor != 1 && !pkIsHandle {
+		ceTraceDetail(sctx, "scaled by the modify factor %.2f", factor)
+	}
 	if rowCount > float64(realtimeRowCount) {
 		rowCount = float64(realtimeRowCount)
 	} else if rowCount < 0 {
@@ -1287,51 +1332,62 @@ func (idx *Index) IsInvalid(collPseudo bool) bool {
 	return (collPseudo && idx.NotAccurate()) || idx.TotalRowCount() == 0
 }
 
-// MemoryUsage returns the total memory usage of a Histogram and CMSketch in Index.
+// MemoryUsage returns the total memory usage of a Histogram, CMSketch and TopN in Index.
 // We ignore the size of other metadata in Index.
 func (idx *Index) MemoryUsage() (sum int64) {
 	sum = idx.Histogram.MemoryUsage()
 	if idx.CMSketch != nil {
 		sum += idx.CMSketch.MemoryUsage()
 	}
+	sum += idx.TopN.MemoryUsage()
 	return
 }
 
 var nullKeyBytes, _ = codec.EncodeKey(nil, nil, types.NewDatum(nil))
 
-func (idx *Index) equalRowCount(b []byte, realtimeRowCount int64) float64 {
+func (idx *Index) equalRowCount(sctx sessionctx.Context, b []byte, realtimeRowCount int64) float64 {
 	if len(idx.Info.Columns) == 1 {
 		if bytes.Equal(b, nullKeyBytes) {
+			ceTraceDetail(sctx, "NULL: null count %d", idx.NullCount)
 			return float64(idx.NullCount)
 		}
 	}
 	val := types.NewBytesDatum(b)
 	if idx.StatsVer < Version2 {
 		if idx.NDV > 0 && idx.outOfRange(val) {
-			return outOfRangeEQSelectivity(idx.NDV, realtimeRowCount, int64(idx.TotalRowCount())) * idx.TotalRowCount()
+			count := outOfRangeEQSelectivity(idx.NDV, realtimeRowCount, int64(idx.TotalRowCount())) * idx.TotalRowCount()
+			ceTraceDetail(sctx, "point out of the histogram range, estimated %.2f rows", count)
+			return count
 		}
 		if idx.CMSketch != nil {
-			return float64(idx.QueryBytes(b))
+			count := idx.QueryBytes(b)
+			ceTraceDetail(sctx, "CMSketch returns %d rows", count)
+			return float64(count)
 		}
 		histRowCount, _ := idx.Histogram.equalRowCount(val, false)
+		ceTraceDetail(sctx, "histogram returns %.2f rows", histRowCount)
 		return histRowCount
 	}
 	// stats version == 2
 	// 1. try to find this value in TopN
 	count, found := idx.TopN.QueryTopN(b)
 	if found {
+		ceTraceDetail(sctx, "TopN hit, %d rows", count)
 		return float64(count)
 	}
 	// 2. try to find this value in bucket.Repeat(the last value in every bucket)
 	histCnt, matched := idx.Histogram.equalRowCount(val, true)
 	if matched {
+		ceTraceDetail(sctx, "histogram bucket hit, %.2f rows", histCnt)
 		return histCnt
 	}
 	// 3. use uniform distribution assumption for the rest (even when this value is not covered by the range of stats)
 	histNDV := float64(idx.Histogram.NDV - int64(idx.TopN.Num()))
 	if histNDV <= 0 {
+		ceTraceDetail(sctx, "not in TopN and no NDV left in the histogram")
 		return 0
 	}
+	ceTraceDetail(sctx, "not in TopN or bucket bounds, uniform estimation %.0f/%.0f", idx.Histogram.notNullCount(), histNDV)
 	return idx.Histogram.notNullCount() / histNDV
 }
 
@@ -1371,7 +1427,7 @@ func (idx *Index) GetRowCount(sctx sessionctx.Context, coll *HistColl, indexRang
 					totalCount += 1
 					continue
 				}
-				count := idx.equalRowCount(lb, realtimeRowCount)
+				count := idx.equalRowCount(sctx, lb, realtimeRowCount)
 				// If the current table row count has changed, we should scale the row count accordingly.
 				count *= idx.GetIncreaseFactor(realtimeRowCount)
 				totalCount += count
@@ -1404,10 +1460,13 @@ func (idx *Index) GetRowCount(sctx sessionctx.Context, coll *HistColl, indexRang
 			}
 			if expBackoffSuccess {
 				totalCount += expBackoffSel * idx.TotalRowCount()
+				ceTraceDetail(sctx, "exponential backoff on the column stats, selectivity %.4f", expBackoffSel)
 			}
 		}
 		if !expBackoffSuccess {
-			totalCount += idx.BetweenRowCount(l, r)
+			betweenCnt := idx.BetweenRowCount(l, r)
+			ceTraceDetail(sctx, "range uses %s, estimated %.2f rows", idx.Histogram.bucketsInRange(l, r), betweenCnt)
+			totalCount += betweenCnt
 		}
 
 		// If the current table row count has changed, we should scale the row count accordingly.
@@ -1419,9 +1478,16 @@ func (idx *Index) GetRowCount(sctx sessionctx.Context, coll *HistColl, indexRang
 			if increaseCount < 0 {
 				increaseCount = 0
 			}
-			totalCount += idx.Histogram.outOfRangeRowCount(&l, &r, increaseCount)
+			outOfRangeCnt := idx.Histogram.outOfRangeRowCount(&l, &r, increaseCount)
+			if outOfRangeCnt > 0 {
+				ceTraceDetail(sctx, "range is out of the histogram, added %.2f rows", outOfRangeCnt)
+			}
+			totalCount += outOfRangeCnt
 		}
 	}
+	if factor := idx.GetIncreaseFactor(realtimeRowCount); factor != 1 {
+		ceTraceDetail(sctx, "scaled by the modify factor %.2f", factor)
+	}
 	if totalCount > float64(realtimeRowCount) {
 		totalCount = float64(realtimeRowCount)
 	}
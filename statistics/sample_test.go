@@ -193,6 +193,65 @@ func TestBuildStatsOnRowSample(t *testing.T) {
 		"num: 196 lower_bound: 805 upper_bound: 1000 repeats: 1 ndv: 0", hist.ToString(0))
 }
 
+func TestEstimateNDVBySampleProfile(t *testing.T) {
+	// The sample holds all the rows.
+	var profile sampleFrequencyProfile
+	for i := 0; i < 100; i++ {
+		profile.add(3)
+	}
+	require.False(t, profile.isSkewed())
+	require.Equal(t, uint64(100), profile.estimateNDV(300))
+
+	// Uniform data which are all unique in the sample.
+	profile = sampleFrequencyProfile{}
+	for i := 0; i < 1000; i++ {
+		profile.add(1)
+	}
+	require.False(t, profile.isSkewed())
+	require.Equal(t, uint64(100000), profile.estimateNDV(100000))
+
+	// Skewed data, GEE is used.
+	profile = sampleFrequencyProfile{}
+	profile.add(900)
+	for i := 0; i < 100; i++ {
+		profile.add(1)
+	}
+	require.True(t, profile.isSkewed())
+	require.Equal(t, uint64(1001), profile.estimateNDV(100000))
+
+	// The estimation is never less than the sample ndv.
+	profile = sampleFrequencyProfile{}
+	for i := 0; i < 10; i++ {
+		profile.add(100)
+	}
+	require.Equal(t, uint64(10), profile.estimateNDV(100000))
+}
+
+func TestBuildHistAndTopNWithSkewedSamples(t *testing.T) {
+	ctx := mock.NewContext()
+	sketch := NewFMSketch(1000)
+	data := make([]*SampleItem, 0, 1000)
+	for i := 0; i < 900; i++ {
+		data = append(data, &SampleItem{Value: types.NewIntDatum(0)})
+	}
+	for i := 1; i <= 100; i++ {
+		data = append(data, &SampleItem{Value: types.NewIntDatum(int64(i))})
+	}
+	for _, item := range data {
+		require.NoError(t, sketch.InsertValue(ctx.GetSessionVars().StmtCtx, item.Value))
+	}
+	collector := &SampleCollector{
+		Samples:   data,
+		Count:     100000,
+		FMSketch:  sketch,
+		TotalSize: 100000 * 8,
+	}
+	hist, _, err := BuildHistAndTopN(ctx, 5, 1, 1, collector, types.NewFieldType(mysql.TypeLonglong), true)
+	require.NoError(t, err)
+	require.Equal(t, int64(101), sketch.NDV())
+	require.Equal(t, int64(1001), hist.NDV)
+}
+
 type testSampleSuite struct {
 	count int
 	rs    sqlexec.RecordSet
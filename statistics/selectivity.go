@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/format"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -30,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/tracing"
@@ -192,7 +194,12 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 	if len(exprs) > 63 || (len(coll.Columns) == 0 && len(coll.Indices) == 0) {
 		ret = pseudoSelectivity(coll, exprs)
 		if sc.EnableOptimizerCETrace {
-			CETraceExpr(ctx, tableID, "Table Stats-Pseudo-Expression", expression.ComposeCNFCondition(ctx, exprs...), ret*float64(coll.Count))
+			if len(exprs) > 63 {
+				ceTraceDetail(ctx, "pseudo estimation: too many conditions")
+			} else {
+				ceTraceDetail(ctx, "pseudo estimation: no column or index stats")
+			}
+			CETraceExpr(ctx, tableID, "Table Stats-Pseudo-Expression", expression.ComposeCNFCondition(ctx, exprs...), ret*float64(coll.Count), coll.Count)
 		}
 		return ret, nil, nil
 	}
@@ -304,6 +311,7 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 		// conditions.
 		if set.partCover {
 			ret *= selectionFactor
+			ceTraceDetail(ctx, "residual DNF conditions use the default selectivity %v", selectionFactor)
 		}
 		if sc.EnableOptimizerCETrace {
 			// Tracing for the expression estimation results after applying this StatsNode.
@@ -313,7 +321,7 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 				}
 			}
 			expr := expression.ComposeCNFCondition(ctx, curExpr...)
-			CETraceExpr(ctx, tableID, "Table Stats-Expression-CNF", expr, ret*float64(coll.Count))
+			CETraceExpr(ctx, tableID, "Table Stats-Expression-CNF", expr, ret*float64(coll.Count), coll.Count)
 		}
 	}
 
@@ -389,7 +397,7 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 				selectivity = selectivity + curSelectivity - selectivity*curSelectivity
 				if sc.EnableOptimizerCETrace {
 					// Tracing for the expression estimation results of this DNF.
-					CETraceExpr(ctx, tableID, "Table Stats-Expression-DNF", scalarCond, selectivity*float64(coll.Count))
+					CETraceExpr(ctx, tableID, "Table Stats-Expression-DNF", scalarCond, selectivity*float64(coll.Count), coll.Count)
 				}
 			}
 
@@ -401,7 +409,31 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 				// Tracing for the expression estimation results after applying the DNF estimation result.
 				curExpr = append(curExpr, remainedExprs[i])
 				expr := expression.ComposeCNFCondition(ctx, curExpr...)
-				CETraceExpr(ctx, tableID, "Table Stats-Expression-CNF", expr, ret*float64(coll.Count))
+				CETraceExpr(ctx, tableID, "Table Stats-Expression-CNF", expr, ret*float64(coll.Count), coll.Count)
+			}
+		}
+	}
+
+	// Try to estimate the remaining `col like 'prefix%'` conditions by the column histogram. The ranger cannot build
+	// ranges for them when the column uses a non-binary collation, so they are not covered by the nodes above.
+	if mask > 0 {
+		for i, expr := range remainedExprs {
+			if mask&(1<<uint64(i)) == 0 {
+				continue
+			}
+			selectivity, ok, err := coll.likePrefixSelectivity(ctx, expr)
+			if err != nil {
+				return 0, nil, errors.Trace(err)
+			}
+			if !ok {
+				continue
+			}
+			ret *= selectivity
+			mask &^= 1 << uint64(i)
+			if sc.EnableOptimizerCETrace {
+				curExpr = append(curExpr, remainedExprs[i])
+				expr := expression.ComposeCNFCondition(ctx, curExpr...)
+				CETraceExpr(ctx, tableID, "Table Stats-Expression-CNF", expr, ret*float64(coll.Count), coll.Count)
 			}
 		}
 	}
@@ -409,15 +441,118 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 	// If there's still conditions which cannot be calculated, we will multiply a selectionFactor.
 	if mask > 0 {
 		ret *= selectionFactor
+		ceTraceDetail(ctx, "conditions not covered by stats use the default selectivity %v", selectionFactor)
 	}
 	if sc.EnableOptimizerCETrace {
 		// Tracing for the expression estimation results after applying the default selectivity.
 		totalExpr := expression.ComposeCNFCondition(ctx, remainedExprs...)
-		CETraceExpr(ctx, tableID, "Table Stats-Expression-CNF", totalExpr, ret*float64(coll.Count))
+		CETraceExpr(ctx, tableID, "Table Stats-Expression-CNF", totalExpr, ret*float64(coll.Count), coll.Count)
 	}
 	return ret, nodes, nil
 }
 
+// likePrefixSelectivity estimates the selectivity of `col like 'prefix...'` by the histogram of the column.
+// The constant prefix of the pattern is converted to the sort key range [Key(prefix), PrefixNext(Key(prefix))) of the
+// column's collation. Since the sort key of a string always starts with the sort key of its prefix, every matched value
+// falls into this range, and the histogram can interpolate the row count on the bytes of the sort keys.
+// The returned bool indicates whether the expression can be estimated in this way.
+func (coll *HistColl) likePrefixSelectivity(ctx sessionctx.Context, expr expression.Expression) (float64, bool, error) {
+	sf, ok := expr.(*expression.ScalarFunction)
+	if !ok || sf.FuncName.L != ast.Like {
+		return 0, false, nil
+	}
+	args := sf.GetArgs()
+	col, ok := args[0].(*expression.Column)
+	if !ok {
+		return 0, false, nil
+	}
+	patternCon, ok := args[1].(*expression.Constant)
+	if !ok {
+		return 0, false, nil
+	}
+	escapeCon, ok := args[2].(*expression.Constant)
+	if !ok {
+		return 0, false, nil
+	}
+	tp := col.RetType
+	if tp.EvalType() != types.ETString || tp.Tp == mysql.TypeEnum || tp.Tp == mysql.TypeSet {
+		return 0, false, nil
+	}
+	_, collation := sf.CharsetAndCollation()
+	if !collate.CompatibleCollate(tp.Collate, collation) {
+		return 0, false, nil
+	}
+	c, ok := coll.Columns[col.UniqueID]
+	if !ok || c.IsInvalid(ctx, coll.Pseudo) {
+		return 0, false, nil
+	}
+	patternDatum, err := patternCon.Eval(chunk.Row{})
+	if err != nil || patternDatum.IsNull() {
+		return 0, false, nil
+	}
+	pattern, err := patternDatum.ToString()
+	if err != nil {
+		return 0, false, nil
+	}
+	escapeDatum, err := escapeCon.Eval(chunk.Row{})
+	if err != nil {
+		return 0, false, nil
+	}
+	prefix, isExactMatch, hasResidual := extractLikePrefix(pattern, byte(escapeDatum.GetInt64()))
+	if len(prefix) == 0 && !isExactMatch {
+		return 0, false, nil
+	}
+	low := collate.GetCollator(tp.Collate).Key(string(prefix))
+	var rg *ranger.Range
+	if isExactMatch {
+		rg = &ranger.Range{LowVal: []types.Datum{types.NewBytesDatum(low)}, HighVal: []types.Datum{types.NewBytesDatum(low)}}
+	} else {
+		high := types.MaxValueDatum()
+		if next := kv.Key(low).PrefixNext(); len(next) == len(low) {
+			high = types.NewBytesDatum(next)
+		}
+		rg = &ranger.Range{LowVal: []types.Datum{types.NewBytesDatum(low)}, HighVal: []types.Datum{high}, HighExclude: true}
+	}
+	cnt, err := c.GetColumnRowCount(ctx, []*ranger.Range{rg}, coll.Count, false)
+	if err != nil {
+		return 0, false, errors.Trace(err)
+	}
+	if ctx.GetSessionVars().StmtCtx.EnableOptimizerCETrace {
+		CETraceRange(ctx, coll.PhysicalID, []string{c.Info.Name.O}, []*ranger.Range{rg}, "Column Stats", uint64(cnt), coll.Count)
+	}
+	selectivity := cnt / float64(coll.Count)
+	// The part of the pattern after the prefix filters the rows further, e.g. `abc%def`.
+	if hasResidual {
+		selectivity *= selectionFactor
+	}
+	return selectivity, true, nil
+}
+
+// extractLikePrefix extracts the constant prefix of the like pattern. isExactMatch is true when the pattern doesn't
+// contain any wildcard, and hasResidual is true when the pattern is neither an exact match nor in the form of 'prefix%'.
+func extractLikePrefix(pattern string, escape byte) (prefix []byte, isExactMatch bool, hasResidual bool) {
+	prefix = make([]byte, 0, len(pattern))
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == escape {
+			i++
+			if i < len(pattern) {
+				prefix = append(prefix, pattern[i])
+			} else {
+				prefix = append(prefix, escape)
+			}
+			continue
+		}
+		if pattern[i] == '%' {
+			return prefix, false, i != len(pattern)-1
+		}
+		if pattern[i] == '_' {
+			return prefix, false, true
+		}
+		prefix = append(prefix, pattern[i])
+	}
+	return prefix, true, false
+}
+
 func getMaskAndRanges(ctx sessionctx.Context, exprs []expression.Expression, rangeType ranger.RangeType, lengths []int, cachedPath *planutil.AccessPath, cols ...*expression.Column) (mask int64, ranges []*ranger.Range, partCover bool, err error) {
 	isDNF := false
 	var accessConds, remainedConds []expression.Expression
@@ -537,7 +672,9 @@ func FindPrefixOfIndexByCol(cols []*expression.Column, idxColIDs []int64, cached
 }
 
 // CETraceExpr appends an expression and related information into CE trace
-func CETraceExpr(sctx sessionctx.Context, tableID int64, tp string, expr expression.Expression, rowCount float64) {
+func CETraceExpr(sctx sessionctx.Context, tableID int64, tp string, expr expression.Expression, rowCount float64, totalCount int64) {
+	sc := sctx.GetSessionVars().StmtCtx
+	detail := takeCETraceDetails(sc)
 	exprStr, err := ExprToString(expr)
 	if err != nil {
 		logutil.BgLogger().Debug("[OptimizerTrace] Failed to trace CE of an expression",
@@ -545,12 +682,13 @@ func CETraceExpr(sctx sessionctx.Context, tableID int64, tp string, expr express
 		return
 	}
 	rec := tracing.CETraceRecord{
-		TableID:  tableID,
-		Type:     tp,
-		Expr:     exprStr,
-		RowCount: uint64(rowCount),
+		TableID:     tableID,
+		Type:        tp,
+		Expr:        exprStr,
+		RowCount:    uint64(rowCount),
+		Selectivity: ceTraceSelectivity(rowCount, totalCount),
+		Detail:      detail,
 	}
-	sc := sctx.GetSessionVars().StmtCtx
 	sc.OptimizerCETrace = append(sc.OptimizerCETrace, &rec)
 }
 
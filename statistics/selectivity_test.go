@@ -20,6 +20,7 @@ import (
 	"math"
 	"os"
 	"runtime/pprof"
+	"strings"
 	"testing"
 	"time"
 
@@ -906,3 +907,70 @@ func TestSelectivityGreedyAlgo(t *testing.T) {
 	require.Equal(t, 1, len(usedSets))
 	require.Equal(t, int64(1), usedSets[0].ID)
 }
+
+func TestLikePrefixSelectivity(t *testing.T) {
+	domain.RunAutoAnalyze = false
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	testKit := testkit.NewTestKit(t, store)
+	testKit.MustExec("use test")
+	testKit.MustExec("drop table if exists t")
+	testKit.MustExec("create table t(a varchar(64) collate utf8mb4_general_ci, b varchar(64) collate utf8mb4_bin)")
+	values := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		var msg string
+		switch {
+		case i < 50:
+			msg = fmt.Sprintf("ERROR: connection %v lost", i)
+		case i < 100:
+			msg = fmt.Sprintf("error: request %v timeout", i)
+		default:
+			msg = fmt.Sprintf("INFO: request %v done", i)
+		}
+		values = append(values, fmt.Sprintf("('%s', '%s')", msg, msg))
+	}
+	testKit.MustExec("insert into t values " + strings.Join(values, ","))
+	testKit.MustExec("set @@session.tidb_analyze_version=2")
+	testKit.MustExec("analyze table t")
+
+	h := dom.StatsHandle()
+	tb, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	statsTbl := h.GetTableStats(tb.Meta())
+
+	tests := []struct {
+		sql         string
+		selectivity float64
+	}{
+		// Case-insensitive collation: both `ERROR` and `error` are matched.
+		{"select * from t where a like 'error%'", 0.1},
+		{"select * from t where a like 'INFO%'", 0.9},
+		{"select * from t where a like 'WARN%'", 0},
+		{"select * from t where a like 'ERROR: conn%'", 0.05},
+		// The residual part of the pattern is estimated by the default selection factor.
+		{"select * from t where a like 'error%lost'", 0.1 * 0.8},
+		// Binary collation is handled by the ranger.
+		{"select * from t where b like 'ERROR%'", 0.05},
+	}
+	ctx := context.Background()
+	for _, tt := range tests {
+		sctx := testKit.Session().(sessionctx.Context)
+		stmts, err := session.Parse(sctx, tt.sql)
+		require.NoErrorf(t, err, "error %v, for sql %s", err, tt.sql)
+		require.Len(t, stmts, 1)
+
+		ret := &plannercore.PreprocessorReturn{}
+		err = plannercore.Preprocess(sctx, stmts[0], plannercore.WithPreprocessorReturn(ret))
+		require.NoErrorf(t, err, "error %v, for sql %s", err, tt.sql)
+		p, _, err := plannercore.BuildLogicalPlanForTest(ctx, sctx, stmts[0], ret.InfoSchema)
+		require.NoErrorf(t, err, "error %v, for building plan, sql %s", err, tt.sql)
+
+		sel := p.(plannercore.LogicalPlan).Children()[0].(*plannercore.LogicalSelection)
+		ds := sel.Children()[0].(*plannercore.DataSource)
+		histColl := statsTbl.GenerateHistCollFromColumnInfo(ds.Columns, ds.Schema().Columns)
+
+		ratio, _, err := histColl.Selectivity(sctx, sel.Conditions, nil)
+		require.NoErrorf(t, err, "error %v, for sql %s", err, tt.sql)
+		require.InDeltaf(t, tt.selectivity, ratio, 0.02, "for %s", tt.sql)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"sort"
+
+	"github.com/pingcap/tidb/types"
+	"github.com/twmb/murmur3"
+)
+
+// UpdateIndexByValueDelta applies the changed counts of the encoded index values, which are collected from
+// the DML between analyzes, to a copy of the index statistics:
+// 1. The counts of the values in the TopN and the CM Sketch are updated.
+// 2. The value whose count exceeds the least count of the TopN replaces it in the TopN.
+// 3. The NDV is increased by the values which are new to the index, and decreased by the TopN values which are
+//    removed from the index.
+// It returns false if the index has no statistics to maintain.
+func UpdateIndexByValueDelta(idx *Index, deltas map[string]int64) (*Index, bool) {
+	if idx == nil || (idx.Histogram.Len() == 0 && idx.TopN.Num() == 0) {
+		return nil, false
+	}
+	newIdx := *idx
+	newIdx.CMSketch = idx.CMSketch.Copy()
+	newIdx.TopN = idx.TopN.Copy()
+	values := make([]string, 0, len(deltas))
+	for value, delta := range deltas {
+		if delta != 0 {
+			values = append(values, value)
+		}
+	}
+	// Sort the values to make the result stable.
+	sort.Strings(values)
+	for _, value := range values {
+		newIdx.updateValueCount([]byte(value), deltas[value])
+	}
+	newIdx.TopN.Sort()
+	newIdx.Flag = ResetAnalyzeFlag(newIdx.Flag)
+	return &newIdx, true
+}
+
+// estimateValueCount estimates the count of the encoded value before the update.
+func (idx *Index) estimateValueCount(b []byte) float64 {
+	if count, ok := idx.TopN.QueryTopN(b); ok {
+		return float64(count)
+	}
+	if idx.CMSketch != nil {
+		return float64(idx.CMSketch.QueryBytes(b))
+	}
+	val := types.NewBytesDatum(b)
+	if idx.Histogram.Len() == 0 || idx.Histogram.NDV == 0 || idx.Histogram.outOfRange(val) {
+		return 0
+	}
+	count, _ := idx.Histogram.equalRowCount(val, idx.StatsVer >= Version2)
+	return count
+}
+
+func (idx *Index) updateValueCount(b []byte, delta int64) {
+	oldCount := idx.estimateValueCount(b)
+	newCount := oldCount + float64(delta)
+	if newCount < 0 {
+		newCount = 0
+	}
+	if pos := idx.TopN.findTopN(b); pos >= 0 {
+		if newCount < 1 {
+			idx.TopN.TopN = append(idx.TopN.TopN[:pos], idx.TopN.TopN[pos+1:]...)
+			if idx.Histogram.NDV > 0 {
+				idx.Histogram.NDV--
+			}
+			return
+		}
+		idx.TopN.TopN[pos].Count = uint64(newCount)
+		return
+	}
+	if oldCount < 1 && newCount >= 1 {
+		idx.Histogram.NDV++
+	}
+	if idx.CMSketch != nil {
+		if delta > 0 {
+			idx.CMSketch.InsertBytesByCount(b, uint64(delta))
+		} else {
+			idx.CMSketch.subBytesByCount(b, uint64(oldCount-newCount))
+		}
+	}
+	idx.promoteToTopN(b, uint64(newCount))
+}
+
+// promoteToTopN moves the value into the TopN if its count exceeds the least count of the TopN. The
+// evicted value is put back to the CM Sketch if there is one.
+func (idx *Index) promoteToTopN(b []byte, count uint64) {
+	if idx.TopN.Num() == 0 {
+		return
+	}
+	minPos := 0
+	for i, meta := range idx.TopN.TopN {
+		if meta.Count < idx.TopN.TopN[minPos].Count {
+			minPos = i
+		}
+	}
+	evicted := idx.TopN.TopN[minPos]
+	if count <= evicted.Count {
+		return
+	}
+	if idx.CMSketch != nil {
+		idx.CMSketch.subBytesByCount(b, count)
+		idx.CMSketch.InsertBytesByCount(evicted.Encoded, evicted.Count)
+	}
+	encoded := make([]byte, len(b))
+	copy(encoded, b)
+	idx.TopN.TopN[minPos] = TopNMeta{Encoded: encoded, Count: count}
+	// Keep the TopN sorted since it's searched by the following values.
+	idx.TopN.Sort()
+}
+
+// subBytesByCount removes the count of the bytes value from the CM Sketch. Since the count is estimated,
+// it removes at most the least counter of the value to avoid the counters overflowing.
+func (c *CMSketch) subBytesByCount(b []byte, count uint64) {
+	h1, h2 := murmur3.Sum128(b)
+	for i := range c.table {
+		j := (h1 + h2*uint64(i)) % uint64(c.width)
+		if uint64(c.table[i][j]) < count {
+			count = uint64(c.table[i][j])
+		}
+	}
+	c.SubValue(h1, h2, count)
+}
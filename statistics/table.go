@@ -15,6 +15,7 @@
 package statistics
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"sort"
@@ -83,6 +84,19 @@ type ExtendedStatsItem struct {
 	StringVals string
 }
 
+// DependencyDegrees decodes the degrees of the functional dependencies `ColIDs[0] -> ColIDs[1]` and
+// `ColIDs[1] -> ColIDs[0]` from a dependency extended stats item.
+func (item *ExtendedStatsItem) DependencyDegrees() (float64, float64, error) {
+	var degrees []float64
+	if err := json.Unmarshal([]byte(item.StringVals), &degrees); err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	if len(degrees) != 2 || len(item.ColIDs) != 2 {
+		return 0, 0, errors.Errorf("invalid dependency stats %s", item.StringVals)
+	}
+	return degrees[0], degrees[1], nil
+}
+
 // ExtendedStatsColl is a collection of cached items for mysql.stats_extended records.
 type ExtendedStatsColl struct {
 	Stats             map[string]*ExtendedStatsItem
@@ -165,6 +179,32 @@ func (t *Table) Copy() *Table {
 	return nt
 }
 
+// DropColumnHistograms returns a copy of the table whose column histograms, sketches and TopN are dropped to reduce
+// the memory usage. The meta of the columns is kept, so the dropped histograms are loaded again when they are needed.
+// The handle columns are kept since they are not loaded on demand.
+func (t *Table) DropColumnHistograms() *Table {
+	nt := t.Copy()
+	for id, col := range nt.Columns {
+		if col.IsHandle || col.Histogram.NDV == 0 || (col.Len() == 0 && col.TopN == nil && col.CMSketch == nil && col.FMSketch == nil) {
+			continue
+		}
+		newCol := &Column{
+			PhysicalID: col.PhysicalID,
+			Histogram:  *NewHistogram(col.Histogram.ID, col.Histogram.NDV, col.Histogram.NullCount, col.Histogram.LastUpdateVersion, col.Histogram.Tp, 0, col.Histogram.TotColSize),
+			Count:      col.Count,
+			Info:       col.Info,
+			IsHandle:   col.IsHandle,
+			ErrorRate:  col.ErrorRate,
+			Flag:       col.Flag,
+			StatsVer:   col.StatsVer,
+		}
+		newCol.Histogram.Correlation = col.Histogram.Correlation
+		col.LastAnalyzePos.Copy(&newCol.LastAnalyzePos)
+		nt.Columns[id] = newCol
+	}
+	return nt
+}
+
 // String implements Stringer interface.
 func (t *Table) String() string {
 	strs := make([]string, 0, len(t.Columns)+1)
@@ -355,13 +395,14 @@ func (coll *HistColl) GetRowCountByIntColumnRanges(sctx sessionctx.Context, colI
 			result = getPseudoRowCountByUnsignedIntRanges(intRanges, float64(coll.Count))
 		}
 		if sc.EnableOptimizerCETrace && ok {
-			CETraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, intRanges, "Column Stats-Pseudo", uint64(result))
+			ceTraceDetail(sctx, "pseudo estimation: %s", coll.columnPseudoReason(sctx, c))
+			CETraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, intRanges, "Column Stats-Pseudo", uint64(result), coll.Count)
 		}
 		return result, nil
 	}
 	result, err := c.GetColumnRowCount(sctx, intRanges, coll.Count, true)
 	if sc.EnableOptimizerCETrace {
-		CETraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, intRanges, "Column Stats", uint64(result))
+		CETraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, intRanges, "Column Stats", uint64(result), coll.Count)
 	}
 	return result, errors.Trace(err)
 }
@@ -373,13 +414,14 @@ func (coll *HistColl) GetRowCountByColumnRanges(sctx sessionctx.Context, colID i
 	if !ok || c.IsInvalid(sctx, coll.Pseudo) {
 		result, err := GetPseudoRowCountByColumnRanges(sc, float64(coll.Count), colRanges, 0)
 		if err == nil && sc.EnableOptimizerCETrace && ok {
-			CETraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, colRanges, "Column Stats-Pseudo", uint64(result))
+			ceTraceDetail(sctx, "pseudo estimation: %s", coll.columnPseudoReason(sctx, c))
+			CETraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, colRanges, "Column Stats-Pseudo", uint64(result), coll.Count)
 		}
 		return result, err
 	}
 	result, err := c.GetColumnRowCount(sctx, colRanges, coll.Count, false)
 	if sc.EnableOptimizerCETrace {
-		CETraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, colRanges, "Column Stats", uint64(result))
+		CETraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, colRanges, "Column Stats", uint64(result), coll.Count)
 	}
 	return result, errors.Trace(err)
 }
@@ -401,7 +443,8 @@ func (coll *HistColl) GetRowCountByIndexRanges(sctx sessionctx.Context, idxID in
 		}
 		result, err := getPseudoRowCountByIndexRanges(sc, indexRanges, float64(coll.Count), colsLen)
 		if err == nil && sc.EnableOptimizerCETrace && ok {
-			CETraceRange(sctx, coll.PhysicalID, colNames, indexRanges, "Index Stats-Pseudo", uint64(result))
+			ceTraceDetail(sctx, "pseudo estimation: %s", coll.indexPseudoReason(idx))
+			CETraceRange(sctx, coll.PhysicalID, colNames, indexRanges, "Index Stats-Pseudo", uint64(result), coll.Count)
 		}
 		return result, err
 	}
@@ -413,13 +456,13 @@ func (coll *HistColl) GetRowCountByIndexRanges(sctx sessionctx.Context, idxID in
 		result, err = idx.GetRowCount(sctx, coll, indexRanges, coll.Count)
 	}
 	if sc.EnableOptimizerCETrace {
-		CETraceRange(sctx, coll.PhysicalID, colNames, indexRanges, "Index Stats", uint64(result))
+		CETraceRange(sctx, coll.PhysicalID, colNames, indexRanges, "Index Stats", uint64(result), coll.Count)
 	}
 	return result, errors.Trace(err)
 }
 
 // CETraceRange appends a list of ranges and related information into CE trace
-func CETraceRange(sctx sessionctx.Context, tableID int64, colNames []string, ranges []*ranger.Range, tp string, rowCount uint64) {
+func CETraceRange(sctx sessionctx.Context, tableID int64, colNames []string, ranges []*ranger.Range, tp string, rowCount uint64, totalCount int64) {
 	sc := sctx.GetSessionVars().StmtCtx
 	allPoint := true
 	for _, ran := range ranges {
@@ -437,19 +480,88 @@ func CETraceRange(sctx sessionctx.Context, tableID int64, colNames []string, ran
 	if err != nil {
 		logutil.BgLogger().Debug("[OptimizerTrace] Failed to trace CE of ranges", zap.Error(err))
 	}
+	detail := takeCETraceDetails(sc)
 	// We don't need to record meaningless expressions.
 	if expr == "" || expr == "true" || expr == "false" {
 		return
 	}
 	CERecord := tracing.CETraceRecord{
-		TableID:  tableID,
-		Type:     tp,
-		Expr:     expr,
-		RowCount: rowCount,
+		TableID:     tableID,
+		Type:        tp,
+		Expr:        expr,
+		RowCount:    rowCount,
+		Selectivity: ceTraceSelectivity(float64(rowCount), totalCount),
+		Detail:      detail,
 	}
 	sc.OptimizerCETrace = append(sc.OptimizerCETrace, &CERecord)
 }
 
+// ceTraceDetail records how the cardinality of the range or expression being estimated is computed.
+// The details are attached to the next record appended into the CE trace.
+func ceTraceDetail(sctx sessionctx.Context, format string, args ...interface{}) {
+	if sctx == nil {
+		return
+	}
+	sc := sctx.GetSessionVars().StmtCtx
+	if sc == nil || !sc.EnableOptimizerCETrace {
+		return
+	}
+	sc.OptimizerCETraceDetails = append(sc.OptimizerCETraceDetails, fmt.Sprintf(format, args...))
+}
+
+// takeCETraceDetails returns the collected details of the estimation and clears them.
+func takeCETraceDetails(sc *stmtctx.StatementContext) string {
+	detail := strings.Join(sc.OptimizerCETraceDetails, "; ")
+	sc.OptimizerCETraceDetails = sc.OptimizerCETraceDetails[:0]
+	return detail
+}
+
+func ceTraceSelectivity(rowCount float64, totalCount int64) float64 {
+	if totalCount <= 0 {
+		return 0
+	}
+	return math.Min(rowCount/float64(totalCount), 1)
+}
+
+// ceTraceValue formats a datum used in the estimation for the CE trace.
+func ceTraceValue(d types.Datum) string {
+	switch d.Kind() {
+	case types.KindNull:
+		return "NULL"
+	case types.KindMinNotNull:
+		return "-inf"
+	case types.KindMaxValue:
+		return "+inf"
+	}
+	str, err := d.ToString()
+	if err != nil {
+		return d.String()
+	}
+	return str
+}
+
+// columnPseudoReason returns the reason why the stats of the column can't be used for estimation.
+func (coll *HistColl) columnPseudoReason(sctx sessionctx.Context, c *Column) string {
+	switch {
+	case coll.Pseudo && c.NotAccurate():
+		return "stats of the table are pseudo"
+	case sctx.GetSessionVars().StmtCtx.StatsLoad.Fallback:
+		return "loading stats timed out"
+	case c.Histogram.NDV > 0 && c.notNullCount() == 0:
+		return "histogram is not loaded"
+	default:
+		return "column stats are empty"
+	}
+}
+
+// indexPseudoReason returns the reason why the stats of the index can't be used for estimation.
+func (coll *HistColl) indexPseudoReason(idx *Index) string {
+	if coll.Pseudo && idx.NotAccurate() {
+		return "stats of the table are pseudo"
+	}
+	return "index stats are empty"
+}
+
 // PseudoAvgCountPerValue gets a pseudo average count if histogram not exists.
 func (t *Table) PseudoAvgCountPerValue() float64 {
 	return float64(t.Count) / pseudoEqualRate
@@ -27,6 +27,7 @@ import (
 
 	"github.com/cznic/mathutil"
 	"github.com/gogo/protobuf/proto"
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/coprocessor"
@@ -665,6 +666,13 @@ func (worker *copIteratorWorker) handleTask(ctx context.Context, task *copTask,
 			worker.sendToRespCh(resp, respCh, false)
 		}
 	}()
+	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
+		span1 := span.Tracer().StartSpan("copIteratorWorker.handleTask", opentracing.ChildOf(span.Context()))
+		span1.SetTag("region_id", task.region.GetID())
+		span1.SetTag("store_addr", task.storeAddr)
+		defer span1.Finish()
+		ctx = opentracing.ContextWithSpan(ctx, span1)
+	}
 	remainTasks := []*copTask{task}
 	backoffermap := make(map[uint64]*Backoffer)
 	for len(remainTasks) > 0 {
@@ -720,6 +728,7 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 			cacheKey = cKey
 			cValue := worker.store.coprCache.Get(cKey)
 			copReq.IsCacheEnabled = true
+			atomic.AddUint64(&coprCacheStats.Requests, 1)
 			if cValue != nil && cValue.RegionID == task.region.GetID() && cValue.TimeStamp <= worker.req.StartTs {
 				// Append cache version to the request to skip Coprocessor computation if possible
 				// when request result is cached
@@ -996,10 +1005,15 @@ func (worker *copIteratorWorker) handleCopResponse(bo *Backoffer, rpcCtx *tikv.R
 		copy(data, cacheValue.Data)
 		resp.pbResp.Data = data
 		resp.detail.CoprCacheHit = true
+		atomic.AddUint64(&coprCacheStats.Hits, 1)
 	} else {
 		// Cache not hit or cache hit but not valid: update the cache if the response can be cached.
+		admitted := false
 		if cacheKey != nil && resp.pbResp.CanBeCached && resp.pbResp.CacheLastVersion > 0 {
-			if worker.store.coprCache.CheckResponseAdmission(resp.pbResp.Data.Size(), resp.detail.TimeDetail.ProcessTime) {
+			dataSize, processTime := resp.pbResp.Data.Size(), resp.detail.TimeDetail.ProcessTime
+			if worker.store.coprCache.CheckResponseAdmission(dataSize, processTime) ||
+				worker.store.coprCache.CheckRepeatedResponseAdmission(cacheKey, dataSize, processTime) {
+				admitted = true
 				data := make([]byte, len(resp.pbResp.Data))
 				copy(data, resp.pbResp.Data)
 
@@ -1012,6 +1026,14 @@ func (worker *copIteratorWorker) handleCopResponse(bo *Backoffer, rpcCtx *tikv.R
 				worker.store.coprCache.Set(cacheKey, &newCacheValue)
 			}
 		}
+		if cacheValue != nil {
+			// The region data version has changed since the result is cached, remove the outdated result
+			// if it is not replaced by the new one.
+			atomic.AddUint64(&coprCacheStats.Invalidated, 1)
+			if !admitted {
+				worker.store.coprCache.Del(cacheKey)
+			}
+		}
 	}
 	worker.sendToRespCh(resp, ch, true)
 	return nil, nil
@@ -1043,6 +1065,7 @@ func (worker *copIteratorWorker) handleCollectExecutionInfo(bo *Backoffer, rpcCt
 	}
 	if rpcCtx != nil {
 		resp.detail.CalleeAddress = rpcCtx.Addr
+		resp.detail.RegionID = rpcCtx.Region.GetID()
 	}
 	sd := &util.ScanDetail{}
 	td := util.TimeDetail{}
@@ -1075,6 +1098,8 @@ type CopRuntimeStats struct {
 	tikv.RegionRequestRuntimeStats
 
 	CoprCacheHit bool
+	// RegionID is the ID of the region the RPC is sent to.
+	RegionID uint64
 }
 
 func (worker *copIteratorWorker) handleTiDBSendReqErr(err error, task *copTask, ch chan<- *copResponse) error {
@@ -18,7 +18,10 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"hash/fnv"
 	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
@@ -29,10 +32,58 @@ import (
 )
 
 type coprCache struct {
-	cache                   *ristretto.Cache
-	admissionMaxRanges      int
-	admissionMaxSize        int
-	admissionMinProcessTime time.Duration
+	cache                    *ristretto.Cache
+	admissionMaxRanges       int
+	admissionMaxSize         int
+	admissionMaxRepeatedSize int
+	admissionMinProcessTime  time.Duration
+
+	// repeatedKeys records the hashes of the recent requests whose responses are too large to be admitted.
+	// Such a response is admitted when the same request is seen again, so that the region-level results
+	// of the repeated identical scans can be cached.
+	repeatedKeys struct {
+		sync.Mutex
+		m map[uint64]struct{}
+	}
+}
+
+const (
+	// coprCacheRepeatedSizeRatio is the ratio of the cache capacity to the max size of a repeated response.
+	coprCacheRepeatedSizeRatio = 8
+	// coprCacheMaxRepeatedKeys is the max number of the recorded repeated request hashes.
+	coprCacheMaxRepeatedKeys = 4096
+)
+
+// CoprCacheStats is the statistics of the coprocessor cache of the current TiDB instance.
+type CoprCacheStats struct {
+	// Requests is the number of the coprocessor requests which look up the cache.
+	Requests uint64
+	// Hits is the number of the requests whose cached results are still valid.
+	Hits uint64
+	// Invalidated is the number of the requests whose cached results are outdated by the region data version.
+	Invalidated uint64
+	// Admitted is the number of the responses inserted into the cache.
+	Admitted uint64
+}
+
+// HitRatio returns the ratio of the cache hits to the requests.
+func (s CoprCacheStats) HitRatio() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Requests)
+}
+
+var coprCacheStats CoprCacheStats
+
+// GetCoprCacheStats returns the statistics of the coprocessor cache.
+func GetCoprCacheStats() CoprCacheStats {
+	return CoprCacheStats{
+		Requests:    atomic.LoadUint64(&coprCacheStats.Requests),
+		Hits:        atomic.LoadUint64(&coprCacheStats.Hits),
+		Invalidated: atomic.LoadUint64(&coprCacheStats.Invalidated),
+		Admitted:    atomic.LoadUint64(&coprCacheStats.Admitted),
+	}
 }
 
 type coprCacheValue struct {
@@ -82,11 +133,13 @@ func newCoprCache(config *config.CoprocessorCache) (*coprCache, error) {
 		return nil, errors.Trace(err)
 	}
 	c := coprCache{
-		cache:                   cache,
-		admissionMaxRanges:      int(config.AdmissionMaxRanges),
-		admissionMaxSize:        int(maxEntityInBytes),
-		admissionMinProcessTime: time.Duration(config.AdmissionMinProcessMs) * time.Millisecond,
+		cache:                    cache,
+		admissionMaxRanges:       int(config.AdmissionMaxRanges),
+		admissionMaxSize:         int(maxEntityInBytes),
+		admissionMaxRepeatedSize: int(capacityInBytes / coprCacheRepeatedSizeRatio),
+		admissionMinProcessTime:  time.Duration(config.AdmissionMinProcessMs) * time.Millisecond,
 	}
+	c.repeatedKeys.m = make(map[uint64]struct{})
 	return &c, nil
 }
 
@@ -186,6 +239,42 @@ func (c *coprCache) CheckResponseAdmission(dataSize int, processTime time.Durati
 	return true
 }
 
+// CheckRepeatedResponseAdmission checks whether a response item which is too large for `CheckResponseAdmission`
+// is worth caching. Such a response is admitted only when its request has been seen before.
+func (c *coprCache) CheckRepeatedResponseAdmission(key []byte, dataSize int, processTime time.Duration) bool {
+	if c == nil {
+		return false
+	}
+	if dataSize <= c.admissionMaxSize || dataSize > c.admissionMaxRepeatedSize {
+		return false
+	}
+	if processTime < c.admissionMinProcessTime {
+		return false
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(key)
+	hash := h.Sum64()
+	c.repeatedKeys.Lock()
+	defer c.repeatedKeys.Unlock()
+	if _, ok := c.repeatedKeys.m[hash]; ok {
+		delete(c.repeatedKeys.m, hash)
+		return true
+	}
+	if len(c.repeatedKeys.m) >= coprCacheMaxRepeatedKeys {
+		c.repeatedKeys.m = make(map[uint64]struct{})
+	}
+	c.repeatedKeys.m[hash] = struct{}{}
+	return false
+}
+
+// Del removes an item from the cache, it is used when the item is outdated.
+func (c *coprCache) Del(key []byte) {
+	if c == nil {
+		return
+	}
+	c.cache.Del(key)
+}
+
 // Set inserts an item to the cache.
 // It is recommended to call `CheckRequestAdmission` and `CheckResponseAdmission` before inserting
 // the item to the cache.
@@ -195,5 +284,9 @@ func (c *coprCache) Set(key []byte, value *coprCacheValue) bool {
 	}
 	// Always ensure that the `Key` in `value` is the `key` we received.
 	value.Key = key
-	return c.cache.Set(key, value, int64(value.Len()))
+	if !c.cache.Set(key, value, int64(value.Len())) {
+		return false
+	}
+	atomic.AddUint64(&coprCacheStats.Admitted, 1)
+	return true
 }
@@ -149,6 +149,30 @@ func TestAdmission(t *testing.T) {
 	require.False(t, v)
 }
 
+func TestRepeatedAdmission(t *testing.T) {
+	cache, err := newCoprCache(&config.CoprocessorCache{AdmissionMinProcessMs: 5, AdmissionMaxResultMB: 1, CapacityMB: 16})
+	require.NoError(t, err)
+	require.NotNil(t, cache)
+	defer cache.cache.Close()
+
+	// Small responses are admitted by `CheckResponseAdmission`.
+	require.False(t, cache.CheckRepeatedResponseAdmission([]byte("foo"), 1024, 5*time.Millisecond))
+	require.False(t, cache.CheckRepeatedResponseAdmission([]byte("foo"), 1024, 5*time.Millisecond))
+
+	// Large responses are admitted when the request is repeated.
+	require.False(t, cache.CheckRepeatedResponseAdmission([]byte("foo"), 2*1024*1024, 5*time.Millisecond))
+	require.False(t, cache.CheckRepeatedResponseAdmission([]byte("bar"), 2*1024*1024, 5*time.Millisecond))
+	require.True(t, cache.CheckRepeatedResponseAdmission([]byte("foo"), 2*1024*1024, 5*time.Millisecond))
+	require.True(t, cache.CheckRepeatedResponseAdmission([]byte("bar"), 2*1024*1024, 5*time.Millisecond))
+	require.False(t, cache.CheckRepeatedResponseAdmission([]byte("foo"), 2*1024*1024, 5*time.Millisecond))
+
+	// The responses larger than 1/8 of the capacity or processed too fast are never admitted.
+	require.False(t, cache.CheckRepeatedResponseAdmission([]byte("foo"), 2*1024*1024+1, 5*time.Millisecond))
+	require.False(t, cache.CheckRepeatedResponseAdmission([]byte("foo"), 2*1024*1024+1, 5*time.Millisecond))
+	require.False(t, cache.CheckRepeatedResponseAdmission([]byte("bar"), 2*1024*1024, 4*time.Millisecond))
+	require.False(t, cache.CheckRepeatedResponseAdmission([]byte("bar"), 2*1024*1024, 4*time.Millisecond))
+}
+
 func TestCacheValueLen(t *testing.T) {
 	v := coprCacheValue{
 		TimeStamp:         0x123,
@@ -192,6 +216,11 @@ func TestGetSet(t *testing.T) {
 	v = cache.Get([]byte("foo"))
 	require.NotNil(t, v)
 	require.EqualValues(t, []byte("bar"), v.Data)
+
+	cache.Del([]byte("foo"))
+	time.Sleep(time.Millisecond * 50)
+	v = cache.Get([]byte("foo"))
+	require.Nil(t, v)
 }
 
 func TestIssue24118(t *testing.T) {
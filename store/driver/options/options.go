@@ -28,7 +28,7 @@ func GetTiKVReplicaReadType(t kv.ReplicaReadType) storekv.ReplicaReadType {
 		return storekv.ReplicaReadFollower
 	case kv.ReplicaReadMixed:
 		return storekv.ReplicaReadMixed
-	case kv.ReplicaReadClosest:
+	case kv.ReplicaReadClosest, kv.ReplicaReadClosestAdaptive:
 		return storekv.ReplicaReadMixed
 	}
 	return 0
@@ -123,3 +123,36 @@ func TestCoprocessorStreamRecvTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestFaultInjectorRetry(t *testing.T) {
+	store, _, fi, clean := testkit.CreateMockStoreWithFaultInjector(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int, key(v))")
+	start, end := fi.TableRange(tk, "test", "t")
+
+	regionMiss := fi.InjectRegionMiss(start, end, 2)
+	epochNotMatch := fi.InjectEpochNotMatch(start, end, 2)
+	serverBusy := fi.InjectServerBusy(start, end, 2)
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustQuery("select * from t where id = 2").Check(testkit.Rows("2 2"))
+	tk.MustQuery("select sum(v) from t use index(v)").Check(testkit.Rows("6"))
+	require.Equal(t, 2, fi.Hits(regionMiss))
+	require.Equal(t, 2, fi.Hits(epochNotMatch))
+	require.Equal(t, 2, fi.Hits(serverBusy))
+	fi.Clear()
+
+	latency := fi.InjectLatency(start, end, 50*time.Millisecond)
+	begin := time.Now()
+	tk.MustQuery("select * from t where id = 1").Check(testkit.Rows("1 1"))
+	require.GreaterOrEqual(t, time.Since(begin), 50*time.Millisecond)
+	require.Equal(t, 1, fi.Hits(latency))
+	fi.Remove(latency)
+
+	// Faults on other key ranges don't affect the table.
+	other := fi.InjectServerBusy(end, end.PrefixNext(), 0)
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("3"))
+	require.Equal(t, 0, fi.Hits(other))
+}
@@ -158,11 +158,16 @@ func newWriteConflictError(conflict *kvrpcpb.WriteConflict) error {
 	if conflict == nil {
 		return kv.ErrWriteConflict
 	}
-	var buf bytes.Buffer
-	prettyWriteKey(&buf, conflict.Key)
-	buf.WriteString(" primary=")
-	prettyWriteKey(&buf, conflict.Primary)
-	return kv.ErrWriteConflict.FastGenByArgs(conflict.StartTs, conflict.ConflictTs, conflict.ConflictCommitTs, buf.String())
+	var keyBuf, primaryBuf bytes.Buffer
+	prettyWriteKey(&keyBuf, conflict.Key)
+	prettyWriteKey(&primaryBuf, conflict.Primary)
+	keys := &kv.WriteConflictKeys{
+		Key:         conflict.Key,
+		Primary:     conflict.Primary,
+		KeyDesc:     keyBuf.String(),
+		PrimaryDesc: primaryBuf.String(),
+	}
+	return kv.ErrWriteConflict.FastGenByArgs(conflict.StartTs, conflict.ConflictTs, conflict.ConflictCommitTs, keys)
 }
 
 func prettyWriteKey(buf *bytes.Buffer, key []byte) {
@@ -0,0 +1,166 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	tikvmetrics "github.com/tikv/client-go/v2/metrics"
+	"github.com/tikv/client-go/v2/tikvrpc"
+)
+
+// The types of the region cache statistics.
+const (
+	// RegionCacheStatSummary is the overall requests, region loads and hit ratio of the region cache.
+	RegionCacheStatSummary = "summary"
+	// RegionCacheStatOperation is the count of an operation of the region cache, such as loading regions from PD.
+	RegionCacheStatOperation = "operation"
+	// RegionCacheStatInvalidation is the count of a region error returned by TiKV, which invalidates or
+	// refreshes the cached region.
+	RegionCacheStatInvalidation = "invalidation"
+	// RegionCacheStatStore is the state of a store.
+	RegionCacheStatStore = "store"
+)
+
+// RegionCacheStat is a statistic of the region cache of the TiDB instance.
+type RegionCacheStat struct {
+	Type   string
+	Name   string
+	Value  float64
+	Detail string
+}
+
+// regionLoadOperations are the operations of the region cache which load regions from PD,
+// they happen when the regions aren't in the cache or the cached regions are invalidated.
+var regionLoadOperations = map[string]struct{}{
+	"get_region":       {},
+	"get_region_by_id": {},
+	"scan_regions":     {},
+}
+
+// GetRegionCacheStats returns the statistics of the region cache since the TiDB instance started.
+// The hit ratio is estimated by the requests sent to the stores and the regions loaded from PD,
+// because every request locates its region in the cache first.
+func (h *Helper) GetRegionCacheStats(ctx context.Context) ([]RegionCacheStat, error) {
+	var requests, loads float64
+	for _, m := range collectMetrics(tikvmetrics.TiKVSendReqHistogram) {
+		requests += float64(m.GetHistogram().GetSampleCount())
+	}
+	var operations []RegionCacheStat
+	for _, m := range collectMetrics(tikvmetrics.TiKVRegionCacheCounter) {
+		tp, result := metricLabel(m, tikvmetrics.LblType), metricLabel(m, tikvmetrics.LblResult)
+		value := m.GetCounter().GetValue()
+		if _, ok := regionLoadOperations[tp]; ok {
+			loads += value
+		}
+		operations = append(operations, RegionCacheStat{Type: RegionCacheStatOperation, Name: tp, Value: value, Detail: "result: " + result})
+	}
+	var invalidations []RegionCacheStat
+	for _, m := range collectMetrics(tikvmetrics.TiKVRegionErrorCounter) {
+		invalidations = append(invalidations, RegionCacheStat{Type: RegionCacheStatInvalidation, Name: metricLabel(m, tikvmetrics.LblType), Value: m.GetCounter().GetValue()})
+	}
+	sortRegionCacheStats(operations)
+	sortRegionCacheStats(invalidations)
+
+	hitRatio := 0.0
+	if requests > 0 && loads < requests {
+		hitRatio = 1 - loads/requests
+	}
+	stats := []RegionCacheStat{
+		{Type: RegionCacheStatSummary, Name: "requests", Value: requests, Detail: "The requests sent to the stores"},
+		{Type: RegionCacheStatSummary, Name: "region_loads", Value: loads, Detail: "The region loads from PD"},
+		{Type: RegionCacheStatSummary, Name: "hit_ratio", Value: hitRatio, Detail: "The estimated ratio of the requests whose regions are cached"},
+	}
+	stats = append(stats, operations...)
+	stats = append(stats, invalidations...)
+
+	storeStats, err := h.getRegionCacheStoreStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(stats, storeStats...), nil
+}
+
+// getRegionCacheStoreStats returns the states of the stores in PD, the value is 1 if the store is up.
+func (h *Helper) getRegionCacheStoreStats(ctx context.Context) ([]RegionCacheStat, error) {
+	stores, err := h.RegionCache.PDClient().GetAllStores(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cached := make(map[uint64]struct{})
+	for _, tp := range []tikvrpc.EndpointType{tikvrpc.TiKV, tikvrpc.TiFlash} {
+		for _, s := range h.RegionCache.GetStoresByType(tp) {
+			cached[s.StoreID()] = struct{}{}
+		}
+	}
+	sort.Slice(stores, func(i, j int) bool { return stores[i].GetId() < stores[j].GetId() })
+	stats := make([]RegionCacheStat, 0, len(stores))
+	for _, s := range stores {
+		value := 0.0
+		if s.GetState() == metapb.StoreState_Up {
+			value = 1
+		}
+		_, inCache := cached[s.GetId()]
+		stats = append(stats, RegionCacheStat{
+			Type:   RegionCacheStatStore,
+			Name:   strconv.FormatUint(s.GetId(), 10),
+			Value:  value,
+			Detail: fmt.Sprintf("address: %s, state: %s, cached: %v", s.GetAddress(), s.GetState(), inCache),
+		})
+	}
+	return stats, nil
+}
+
+// collectMetrics returns the current values of the metrics of the collector.
+func collectMetrics(c prometheus.Collector) []*dto.Metric {
+	ch := make(chan prometheus.Metric, 16)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+	var metrics []*dto.Metric
+	for m := range ch {
+		pb := &dto.Metric{}
+		if err := m.Write(pb); err == nil {
+			metrics = append(metrics, pb)
+		}
+	}
+	return metrics
+}
+
+func metricLabel(m *dto.Metric, name string) string {
+	for _, label := range m.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+func sortRegionCacheStats(stats []RegionCacheStat) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Name != stats[j].Name {
+			return stats[i].Name < stats[j].Name
+		}
+		return stats[i].Detail < stats[j].Detail
+	})
+}
@@ -48,6 +48,9 @@ type Cluster struct {
 	// delayEvents is used to control the execution sequence of rpc requests for test.
 	delayEvents map[delayKey]time.Duration
 	delayMu     sync.Mutex
+
+	// faults are the injected faults used to test the retry paths.
+	faults faultInjector
 }
 
 func newCluster(rm *us.MockRegionManager) *Cluster {
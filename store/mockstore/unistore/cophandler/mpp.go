@@ -178,8 +178,44 @@ func (b *mppExecBuilder) buildMPPJoin(pb *tipb.Join, children []*tipb.Executor)
 		}
 		e.defaultInner = defaultInner.ToRow()
 	}
-	// because the field type is immutable, so this kind of appending is safe.
-	e.fieldTypes = append(leftCh.getFieldTypes(), rightCh.getFieldTypes()...)
+	switch pb.JoinType {
+	case tipb.JoinType_TypeSemiJoin, tipb.JoinType_TypeAntiSemiJoin:
+		e.fieldTypes = leftCh.getFieldTypes()
+	case tipb.JoinType_TypeLeftOuterSemiJoin, tipb.JoinType_TypeAntiLeftOuterSemiJoin:
+		// the auxiliary column indicates whether the row of the left child is matched.
+		e.fieldTypes = append(leftCh.getFieldTypes(), types.NewFieldType(mysql.TypeTiny))
+	default:
+		// because the field type is immutable, so this kind of appending is safe.
+		e.fieldTypes = append(leftCh.getFieldTypes(), rightCh.getFieldTypes()...)
+	}
+	if e.isSemiJoin() && pb.InnerIdx != 1 {
+		return nil, errors.New("the build side of semi join must be the right child")
+	}
+	if e.isSemiJoin() {
+		// The conditions are evaluated on the rows joined by the probe row and the build row.
+		joinedTypes := make([]*types.FieldType, 0, len(leftCh.getFieldTypes())+len(rightCh.getFieldTypes()))
+		joinedTypes = append(joinedTypes, leftCh.getFieldTypes()...)
+		joinedTypes = append(joinedTypes, rightCh.getFieldTypes()...)
+		for _, conds := range [][]*tipb.Expr{pb.OtherConditions, pb.OtherEqConditionsFromIn} {
+			for _, cond := range conds {
+				expr, err := expression.PBToExpr(cond, joinedTypes, b.sc)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				e.conditions = append(e.conditions, expr)
+			}
+		}
+		e.joinedTypes = joinedTypes
+	}
+	if len(pb.LeftJoinKeys) == 0 {
+		// The cartesian join puts all the build rows into the same bucket.
+		if !e.isSemiJoin() {
+			return nil, errors.New("only the semi joins can be cartesian joins")
+		}
+		e.probeChild = leftCh
+		e.buildChild = rightCh
+		return e, nil
+	}
 	if pb.InnerIdx == 1 {
 		e.probeChild = leftCh
 		e.buildChild = rightCh
@@ -346,6 +346,24 @@ type joinExec struct {
 	inited       bool
 	// align the types of join keys and build keys
 	comKeyTp *types.FieldType
+
+	// conditions are the other conditions of the semi joins, which are evaluated on the joined rows of joinedTypes.
+	conditions  []expression.Expression
+	joinedTypes []*types.FieldType
+}
+
+// getRowKey returns the hash key of the row, the second return value is true if the join key is NULL. The keys of
+// all the rows are the same in cartesian joins.
+func (e *joinExec) getRowKey(row chunk.Row, key *expression.Column, fieldTypes []*types.FieldType) (string, bool, error) {
+	if key == nil {
+		return "", false, nil
+	}
+	keyCol := row.GetDatum(key.Index, fieldTypes[key.Index])
+	if keyCol.IsNull() {
+		return "", true, nil
+	}
+	str, err := e.getHashKey(keyCol)
+	return str, false, err
 }
 
 func (e *joinExec) getHashKey(keyCol types.Datum) (str string, err error) {
@@ -360,6 +378,77 @@ func (e *joinExec) getHashKey(keyCol types.Datum) (str string, err error) {
 	return str, nil
 }
 
+func (e *joinExec) isSemiJoin() bool {
+	switch e.Join.JoinType {
+	case tipb.JoinType_TypeSemiJoin, tipb.JoinType_TypeAntiSemiJoin,
+		tipb.JoinType_TypeLeftOuterSemiJoin, tipb.JoinType_TypeAntiLeftOuterSemiJoin:
+		return true
+	}
+	return false
+}
+
+// matchSemiJoin checks whether the probe row is matched by any of the build rows. The second return value is true
+// if the probe row isn't matched but some conditions are evaluated to NULL, which makes the result of IN unknown.
+func (e *joinExec) matchSemiJoin(row chunk.Row, buildRows []chunk.Row) (matched bool, hasNull bool, err error) {
+	if len(e.conditions) == 0 {
+		return len(buildRows) > 0, false, nil
+	}
+	joinedRow := chunk.MutRowFromTypes(e.joinedTypes)
+	joinedRow.ShallowCopyPartialRow(0, row)
+	for _, buildRow := range buildRows {
+		joinedRow.ShallowCopyPartialRow(row.Len(), buildRow)
+		isFalse, isNull := false, false
+		for _, cond := range e.conditions {
+			d, err := cond.Eval(joinedRow.ToRow())
+			if err != nil {
+				return false, false, errors.Trace(err)
+			}
+			if d.IsNull() {
+				isNull = true
+				continue
+			}
+			isBool, err := d.ToBool(e.sc)
+			if err != nil {
+				return false, false, errors.Trace(err)
+			}
+			if isBool == 0 {
+				isFalse = true
+				break
+			}
+		}
+		if isFalse {
+			continue
+		}
+		if isNull {
+			hasNull = true
+			continue
+		}
+		return true, false, nil
+	}
+	return false, hasNull, nil
+}
+
+// semiJoinResult returns the output row of the probe row for the semi joins, the second return value is false if the
+// probe row is filtered out by the join.
+func (e *joinExec) semiJoinResult(row chunk.Row, matched bool, hasNull bool) (chunk.Row, bool) {
+	newRow := chunk.MutRowFromTypes(e.fieldTypes)
+	newRow.ShallowCopyPartialRow(0, row)
+	switch e.Join.JoinType {
+	case tipb.JoinType_TypeSemiJoin:
+		return newRow.ToRow(), matched
+	case tipb.JoinType_TypeAntiSemiJoin:
+		return newRow.ToRow(), !matched && !hasNull
+	case tipb.JoinType_TypeAntiLeftOuterSemiJoin:
+		matched = !matched
+	}
+	if hasNull {
+		newRow.SetDatum(row.Len(), types.NewDatum(nil))
+	} else {
+		newRow.SetDatum(row.Len(), types.NewDatum(matched))
+	}
+	return newRow.ToRow(), true
+}
+
 func (e *joinExec) buildHashTable() error {
 	for {
 		chk, err := e.buildChild.next()
@@ -372,11 +461,14 @@ func (e *joinExec) buildHashTable() error {
 		rows := chk.NumRows()
 		for i := 0; i < rows; i++ {
 			row := chk.GetRow(i)
-			keyCol := row.GetDatum(e.buildKey.Index, e.buildChild.getFieldTypes()[e.buildKey.Index])
-			key, err := e.getHashKey(keyCol)
+			key, isNull, err := e.getRowKey(row, e.buildKey, e.buildChild.getFieldTypes())
 			if err != nil {
 				return errors.Trace(err)
 			}
+			// NULL never equals to any value, so the row can't be matched by any probe row.
+			if isNull {
+				continue
+			}
 			if rowSet, ok := e.hashMap[key]; ok {
 				rowSet = append(rowSet, row)
 				e.hashMap[key] = rowSet
@@ -400,12 +492,26 @@ func (e *joinExec) fetchRows() (bool, error) {
 	chkSize := chk.NumRows()
 	for i := 0; i < chkSize; i++ {
 		row := chk.GetRow(i)
-		keyCol := row.GetDatum(e.probeKey.Index, e.probeChild.getFieldTypes()[e.probeKey.Index])
-		key, err := e.getHashKey(keyCol)
+		key, isNull, err := e.getRowKey(row, e.probeKey, e.probeChild.getFieldTypes())
 		if err != nil {
 			return false, errors.Trace(err)
 		}
-		if rowSet, ok := e.hashMap[key]; ok {
+		var rowSet []chunk.Row
+		matched := false
+		if !isNull {
+			rowSet, matched = e.hashMap[key]
+		}
+		if e.isSemiJoin() {
+			matched, hasNull, err := e.matchSemiJoin(row, rowSet)
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			if newRow, ok := e.semiJoinResult(row, matched, hasNull); ok {
+				e.reservedRows = append(e.reservedRows, newRow)
+			}
+			continue
+		}
+		if matched {
 			for _, matched := range rowSet {
 				newRow := chunk.MutRowFromTypes(e.fieldTypes)
 				if e.buildSideIdx == 0 {
@@ -0,0 +1,216 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unistore
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/errorpb"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/tikv/client-go/v2/tikvrpc"
+	"golang.org/x/net/context"
+)
+
+// FaultKind is the kind of a fault injected into the mock cluster.
+type FaultKind int
+
+const (
+	// FaultRegionMiss makes the store reply RegionNotFound.
+	FaultRegionMiss FaultKind = iota
+	// FaultEpochNotMatch makes the store reply EpochNotMatch with the current region meta.
+	FaultEpochNotMatch
+	// FaultServerBusy makes the store reply ServerIsBusy.
+	FaultServerBusy
+	// FaultLatency delays the request before it is handled by the store.
+	FaultLatency
+)
+
+// String implements fmt.Stringer interface.
+func (k FaultKind) String() string {
+	switch k {
+	case FaultRegionMiss:
+		return "region-miss"
+	case FaultEpochNotMatch:
+		return "epoch-not-match"
+	case FaultServerBusy:
+		return "server-busy"
+	case FaultLatency:
+		return "latency"
+	}
+	return "unknown"
+}
+
+// Fault describes a fault injected into the requests sent to regions
+// overlapping the key range [StartKey, EndKey).
+type Fault struct {
+	Kind FaultKind
+	// StartKey and EndKey are the raw keys of the affected range. An empty
+	// EndKey means the range is unbounded.
+	StartKey []byte
+	EndKey   []byte
+	// Latency is the delay applied by FaultLatency.
+	Latency time.Duration
+	// Times limits how many requests are affected, 0 means the fault stays
+	// until it is removed.
+	Times int
+}
+
+type faultEntry struct {
+	Fault
+	// encodedStart and encodedEnd are comparable with the region keys.
+	encodedStart []byte
+	encodedEnd   []byte
+	hits         int
+}
+
+func encodeFaultKey(key []byte) []byte {
+	if len(key) == 0 {
+		return nil
+	}
+	return codec.EncodeBytes(nil, key)
+}
+
+type faultInjector struct {
+	sync.Mutex
+	nextID uint64
+	faults map[uint64]*faultEntry
+}
+
+// InjectFault injects a fault into the cluster and returns its id.
+func (c *Cluster) InjectFault(f Fault) uint64 {
+	c.faults.Lock()
+	defer c.faults.Unlock()
+	if c.faults.faults == nil {
+		c.faults.faults = make(map[uint64]*faultEntry)
+	}
+	c.faults.nextID++
+	c.faults.faults[c.faults.nextID] = &faultEntry{
+		Fault:        f,
+		encodedStart: encodeFaultKey(f.StartKey),
+		encodedEnd:   encodeFaultKey(f.EndKey),
+	}
+	return c.faults.nextID
+}
+
+// IsolateRange splits the regions at the raw keys start and end, so that the
+// faults injected on [start, end) don't affect the requests to other keys.
+func (c *Cluster) IsolateRange(start, end []byte) {
+	for _, key := range [][]byte{start, end} {
+		encoded := encodeFaultKey(key)
+		if encoded == nil {
+			continue
+		}
+		region, _ := c.GetRegionByKey(encoded)
+		if region == nil || bytes.Equal(region.StartKey, encoded) {
+			continue
+		}
+		c.Split(region.Id, c.AllocID(), key, c.AllocIDs(len(region.Peers)), region.Peers[0].Id)
+	}
+}
+
+// RemoveFault removes the fault with the given id.
+func (c *Cluster) RemoveFault(id uint64) {
+	c.faults.Lock()
+	delete(c.faults.faults, id)
+	c.faults.Unlock()
+}
+
+// ClearFaults removes all the injected faults.
+func (c *Cluster) ClearFaults() {
+	c.faults.Lock()
+	c.faults.faults = nil
+	c.faults.Unlock()
+}
+
+// FaultHits returns how many requests have been affected by the fault.
+func (c *Cluster) FaultHits(id uint64) int {
+	c.faults.Lock()
+	defer c.faults.Unlock()
+	if e, ok := c.faults.faults[id]; ok {
+		return e.hits
+	}
+	return 0
+}
+
+// matchFaults returns the faults that apply to the region and records the hits.
+func (c *Cluster) matchFaults(region *metapb.Region) []Fault {
+	c.faults.Lock()
+	defer c.faults.Unlock()
+	var matched []Fault
+	for _, e := range c.faults.faults {
+		if e.Times > 0 && e.hits >= e.Times {
+			continue
+		}
+		if !rangeOverlaps(e.encodedStart, e.encodedEnd, region.StartKey, region.EndKey) {
+			continue
+		}
+		e.hits++
+		matched = append(matched, e.Fault)
+	}
+	return matched
+}
+
+// handleFault applies the faults matching the request. It returns a non-nil
+// response if the request should fail with a region error.
+func (c *Cluster) handleFault(ctx context.Context, req *tikvrpc.Request) *tikvrpc.Response {
+	c.faults.Lock()
+	empty := len(c.faults.faults) == 0
+	c.faults.Unlock()
+	if empty || req.Context.RegionId == 0 {
+		return nil
+	}
+	region := c.GetRegion(req.Context.RegionId)
+	if region == nil {
+		return nil
+	}
+	var regionErr *errorpb.Error
+	for _, f := range c.matchFaults(region) {
+		switch f.Kind {
+		case FaultLatency:
+			select {
+			case <-ctx.Done():
+			case <-time.After(f.Latency):
+			}
+		case FaultRegionMiss:
+			regionErr = &errorpb.Error{RegionNotFound: &errorpb.RegionNotFound{RegionId: region.Id}}
+		case FaultEpochNotMatch:
+			regionErr = &errorpb.Error{EpochNotMatch: &errorpb.EpochNotMatch{CurrentRegions: []*metapb.Region{region}}}
+		case FaultServerBusy:
+			regionErr = &errorpb.Error{ServerIsBusy: &errorpb.ServerIsBusy{Reason: "injected fault"}}
+		}
+	}
+	if regionErr == nil {
+		return nil
+	}
+	resp, err := tikvrpc.GenRegionErrorResp(req, regionErr)
+	if err != nil {
+		// The request type doesn't carry region errors, let it pass.
+		return nil
+	}
+	return resp
+}
+
+func rangeOverlaps(start1, end1, start2, end2 []byte) bool {
+	if len(end2) > 0 && bytes.Compare(start1, end2) >= 0 {
+		return false
+	}
+	if len(end1) > 0 && bytes.Compare(start2, end1) >= 0 {
+		return false
+	}
+	return true
+}
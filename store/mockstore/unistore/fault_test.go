@@ -0,0 +1,61 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unistore
+
+import (
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeOverlaps(t *testing.T) {
+	tests := []struct {
+		start1, end1, start2, end2 string
+		overlaps                   bool
+	}{
+		{"a", "c", "b", "d", true},
+		{"a", "b", "b", "c", false},
+		{"b", "c", "a", "b", false},
+		{"a", "", "x", "y", true},
+		{"x", "y", "a", "", true},
+		{"", "", "a", "b", true},
+		{"c", "d", "a", "b", false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.overlaps, rangeOverlaps([]byte(tt.start1), []byte(tt.end1), []byte(tt.start2), []byte(tt.end2)), "%v", tt)
+	}
+}
+
+func TestMatchFaults(t *testing.T) {
+	c := &Cluster{}
+	region := &metapb.Region{StartKey: encodeFaultKey([]byte("a")), EndKey: encodeFaultKey([]byte("c"))}
+	once := c.InjectFault(Fault{Kind: FaultServerBusy, StartKey: []byte("b"), EndKey: []byte("d"), Times: 1})
+	always := c.InjectFault(Fault{Kind: FaultRegionMiss, StartKey: []byte("a")})
+	other := c.InjectFault(Fault{Kind: FaultEpochNotMatch, StartKey: []byte("x"), EndKey: []byte("y")})
+
+	require.Len(t, c.matchFaults(region), 2)
+	matched := c.matchFaults(region)
+	require.Len(t, matched, 1)
+	require.Equal(t, FaultRegionMiss, matched[0].Kind)
+	require.Equal(t, 1, c.FaultHits(once))
+	require.Equal(t, 2, c.FaultHits(always))
+	require.Equal(t, 0, c.FaultHits(other))
+
+	c.RemoveFault(always)
+	require.Len(t, c.matchFaults(region), 0)
+	c.ClearFaults()
+	require.Equal(t, 0, c.FaultHits(once))
+}
@@ -96,6 +96,10 @@ func (c *RPCClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.R
 		return nil, err
 	}
 
+	if faultResp := c.cluster.handleFault(ctx, req); faultResp != nil {
+		return faultResp, nil
+	}
+
 	resp := &tikvrpc.Response{}
 	switch req.Type {
 	case tikvrpc.CmdGet:
@@ -238,7 +238,11 @@ func (item *btreeItem) Less(o btree.Item) bool {
 func (rm *MockRegionManager) GetRegion(id uint64) *metapb.Region {
 	rm.mu.RLock()
 	defer rm.mu.RUnlock()
-	return proto.Clone(rm.regions[id].meta).(*metapb.Region)
+	region, ok := rm.regions[id]
+	if !ok {
+		return nil
+	}
+	return proto.Clone(region.meta).(*metapb.Region)
 }
 
 // GetRegionByKey gets a region by the key.
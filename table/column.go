@@ -336,6 +336,53 @@ func CastValue(ctx sessionctx.Context, val types.Datum, col *model.ColumnInfo, r
 	return casted, err
 }
 
+// CastChangingColumnValue casts the value of the origin column to the changing column of "modify/change column".
+func CastChangingColumnValue(ctx sessionctx.Context, val types.Datum, oldCol, newCol *model.ColumnInfo) (types.Datum, error) {
+	val, err := convertChangingColumnSource(ctx.GetSessionVars().StmtCtx, val, oldCol, newCol)
+	if err = ctx.GetSessionVars().StmtCtx.HandleTruncate(err); err != nil {
+		return val, err
+	}
+	return CastValue(ctx, val, newCol, false, false)
+}
+
+// convertChangingColumnSource converts the value of the origin column into the form MySQL uses when it copies
+// the data to the changing column, for the combinations that can't be cast directly. Enum and set values are
+// converted to date/datetime/timestamp/time by the element name, bit and float values by the number. Temporal and
+// json values are matched against the enum/set elements by the string form, and stored as the bytes of the string
+// form in bit.
+func convertChangingColumnSource(sc *stmtctx.StatementContext, val types.Datum, oldCol, newCol *model.ColumnInfo) (types.Datum, error) {
+	if val.IsNull() {
+		return val, nil
+	}
+	switch newCol.Tp {
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
+		switch val.Kind() {
+		case types.KindMysqlEnum, types.KindMysqlSet:
+			return types.NewStringDatum(val.GetString()), nil
+		case types.KindMysqlBit, types.KindBinaryLiteral:
+			v, err := val.GetBinaryLiteral().ToInt(sc)
+			return types.NewIntDatum(int64(v)), err
+		case types.KindFloat32, types.KindFloat64:
+			if newCol.Tp == mysql.TypeDuration {
+				break
+			}
+			dec := new(types.MyDecimal)
+			err := dec.FromFloat64(val.GetFloat64())
+			return types.NewDecimalDatum(dec), err
+		}
+	case mysql.TypeEnum, mysql.TypeSet, mysql.TypeBit:
+		switch oldCol.Tp {
+		case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration, mysql.TypeJSON:
+			str, err := val.ToString()
+			if newCol.Tp == mysql.TypeBit {
+				return types.NewBytesDatum([]byte(str)), err
+			}
+			return types.NewStringDatum(str), err
+		}
+	}
+	return val, nil
+}
+
 // ColDesc describes column information like MySQL desc and show columns do.
 type ColDesc struct {
 	Field string
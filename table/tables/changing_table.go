@@ -0,0 +1,244 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+)
+
+// When the clustered index of a table is being changed, the rows of the table are
+// copied into a changing table, which has the target primary key layout and a new
+// physical table ID. The DMLs on the table are written into the changing table as
+// well, according to the state of the changing table:
+//   delete only: the rows are removed from the changing table, but never added;
+//   write only / write reorganization: the rows are both added and removed.
+// The changing table shares the columns and the allocators with the table, so the
+// row IDs of a non-clustered changing table are allocated from the table itself.
+
+// initChangingTable initializes the changing table of t if the clustered index of t is being changed.
+func initChangingTable(t *TableCommon, allocs autoid.Allocators) error {
+	info := t.meta.ChangingTable
+	if info == nil {
+		return nil
+	}
+	var ct TableCommon
+	if err := initTableCommonWithIndices(&ct, info, info.ID, t.Columns, allocs); err != nil {
+		return err
+	}
+	t.changingTable = &ct
+	return nil
+}
+
+// buildHandleFromRow builds the handle of the row for the table with clustered index.
+// nil is returned if the rows of the table are organized by the row ID.
+func buildHandleFromRow(sc *stmtctx.StatementContext, tblInfo *model.TableInfo, r []types.Datum) (kv.Handle, error) {
+	if tblInfo.PKIsHandle {
+		return kv.IntHandle(r[tblInfo.GetPkColInfo().Offset].GetInt64()), nil
+	}
+	if !tblInfo.IsCommonHandle {
+		return nil, nil
+	}
+	pkIdx := FindPrimaryIndex(tblInfo)
+	pkDts := make([]types.Datum, 0, len(pkIdx.Columns))
+	for _, idxCol := range pkIdx.Columns {
+		pkDts = append(pkDts, r[idxCol.Offset])
+	}
+	tablecodec.TruncateIndexValues(tblInfo, pkIdx, pkDts)
+	handleBytes, err := codec.EncodeKey(sc, nil, pkDts...)
+	if err != nil {
+		return nil, err
+	}
+	return kv.NewCommonHandle(handleBytes)
+}
+
+// findRecordHandle finds the handle of the row in the changing table.
+// nil is returned if the row isn't written into the changing table.
+func (t *TableCommon) findRecordHandle(sctx sessionctx.Context, txn kv.Transaction, r []types.Datum) (kv.Handle, error) {
+	sc := sctx.GetSessionVars().StmtCtx
+	h, err := buildHandleFromRow(sc, t.meta, r)
+	if err != nil {
+		return nil, err
+	}
+	if h != nil {
+		_, err = txn.Get(context.TODO(), t.RecordKey(h))
+		if kv.IsErrNotFound(err) {
+			return nil, nil
+		}
+		return h, err
+	}
+
+	// The row ID of the row is unknown, look it up by the primary key.
+	var pkIdx table.Index
+	for _, idx := range t.indices {
+		if idx.Meta().Primary {
+			pkIdx = idx
+			break
+		}
+	}
+	if pkIdx == nil {
+		return nil, errors.Errorf("the primary key of the changing table %s is not found", t.meta.Name)
+	}
+	vals, err := pkIdx.FetchValues(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	key, _, err := pkIdx.GenIndexKey(sc, vals, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	val, err := txn.Get(context.TODO(), key)
+	if kv.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tablecodec.DecodeHandleInUniqueIndexValue(val, false)
+}
+
+// writeRecord writes the row and its index entries into the changing table.
+// If h is nil, the handle is built from the row or allocated.
+func (t *TableCommon) writeRecord(sctx sessionctx.Context, txn kv.Transaction, h kv.Handle, r []types.Datum) error {
+	sessVars := sctx.GetSessionVars()
+	var err error
+	if h == nil {
+		h, err = buildHandleFromRow(sessVars.StmtCtx, t.meta, r)
+		if err != nil {
+			return err
+		}
+	}
+	if h == nil {
+		h, err = AllocHandle(context.Background(), sctx, t)
+		if err != nil {
+			return err
+		}
+		// The allocated row ID may be used by a backfilled row, never overwrite it.
+		_, err = txn.Get(context.TODO(), t.RecordKey(h))
+		if err == nil {
+			return kv.ErrKeyExists.FastGenByArgs(h.String(), "PRIMARY")
+		}
+		if !kv.IsErrNotFound(err) {
+			return err
+		}
+	}
+
+	colIDs := make([]int64, 0, len(r))
+	row := make([]types.Datum, 0, len(r))
+	for _, col := range t.Columns {
+		value := r[col.Offset]
+		if !t.canSkip(col, &value) {
+			colIDs = append(colIDs, col.ID)
+			row = append(row, value)
+		}
+	}
+	value, err := tablecodec.EncodeRow(sessVars.StmtCtx, row, colIDs, nil, nil, &sessVars.RowEncoder)
+	if err != nil {
+		return err
+	}
+	if err = txn.Set(t.RecordKey(h), value); err != nil {
+		return err
+	}
+	_, err = t.addIndices(sctx, h, r, txn, nil)
+	return err
+}
+
+// deleteRecord removes the row and its index entries from the changing table.
+func (t *TableCommon) deleteRecord(sctx sessionctx.Context, txn kv.Transaction, h kv.Handle, r []types.Datum) error {
+	if err := txn.Delete(t.RecordKey(h)); err != nil {
+		return err
+	}
+	return t.removeRowIndices(sctx, h, r)
+}
+
+func (t *TableCommon) changingTableWritable() bool {
+	return t.changingTable != nil && t.changingTable.meta.State != model.StateDeleteOnly
+}
+
+// addChangingRecord adds the row into the changing table of t.
+func (t *TableCommon) addChangingRecord(sctx sessionctx.Context, txn kv.Transaction, r []types.Datum) error {
+	if !t.changingTableWritable() {
+		return nil
+	}
+	return t.changingTable.writeRecord(sctx, txn, nil, r[:len(t.Columns)])
+}
+
+// removeChangingRecord removes the row from the changing table of t.
+func (t *TableCommon) removeChangingRecord(sctx sessionctx.Context, txn kv.Transaction, r []types.Datum) error {
+	ct := t.changingTable
+	if ct == nil {
+		return nil
+	}
+	r = r[:len(t.Columns)]
+	h, err := ct.findRecordHandle(sctx, txn, r)
+	if err != nil || h == nil {
+		return err
+	}
+	return ct.deleteRecord(sctx, txn, h, r)
+}
+
+// updateChangingRecord updates the row in the changing table of t. The row ID of
+// the row is kept if the rows of the changing table are organized by the row ID.
+func (t *TableCommon) updateChangingRecord(sctx sessionctx.Context, txn kv.Transaction, oldData, newData []types.Datum) error {
+	ct := t.changingTable
+	if ct == nil {
+		return nil
+	}
+	oldData, newData = oldData[:len(t.Columns)], newData[:len(t.Columns)]
+	h, err := ct.findRecordHandle(sctx, txn, oldData)
+	if err != nil {
+		return err
+	}
+	if h != nil {
+		if err = ct.deleteRecord(sctx, txn, h, oldData); err != nil {
+			return err
+		}
+		if ct.meta.HasClusteredIndex() {
+			h = nil
+		}
+	}
+	if !t.changingTableWritable() {
+		return nil
+	}
+	return ct.writeRecord(sctx, txn, h, newData)
+}
+
+// BackfillChangingRecord writes the row into the changing table of t if it isn't written yet.
+// It's used to backfill the existing rows when the clustered index of the table is being changed.
+func BackfillChangingRecord(sctx sessionctx.Context, txn kv.Transaction, t table.Table, r []types.Datum) (added bool, err error) {
+	tc, ok := t.(*TableCommon)
+	if !ok || tc.changingTable == nil {
+		return false, errors.Errorf("the table %s has no changing table", t.Meta().Name)
+	}
+	r = r[:len(tc.Columns)]
+	h, err := tc.changingTable.findRecordHandle(sctx, txn, r)
+	if err != nil || h != nil {
+		return false, err
+	}
+	if err = tc.changingTable.writeRecord(sctx, txn, nil, r); err != nil {
+		return false, err
+	}
+	return true, nil
+}
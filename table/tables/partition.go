@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/btree"
 	"github.com/pingcap/errors"
@@ -938,6 +939,10 @@ func (t *partitionedTable) locateRangeColumnPartition(ctx sessionctx.Context, pi
 	var err error
 	var isNull bool
 	partitionExprs := t.partitionExpr.UpperBounds
+	r, err = t.rangeColumnsRowInUTC(ctx, r)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
 	evalBuffer := t.evalBufferPool.Get().(*chunk.MutRow)
 	defer t.evalBufferPool.Put(evalBuffer)
 	idx := sort.Search(len(partitionExprs), func(i int) bool {
@@ -979,6 +984,31 @@ func (t *partitionedTable) locateRangeColumnPartition(ctx sessionctx.Context, pi
 	return idx, nil
 }
 
+// rangeColumnsRowInUTC converts the TIMESTAMP partition column value of the row from the
+// session time zone to UTC, in which the range boundaries of such a column are defined.
+// The returned row is a copy if it's changed, the caller's row is never modified.
+func (t *partitionedTable) rangeColumnsRowInUTC(ctx sessionctx.Context, r []types.Datum) ([]types.Datum, error) {
+	if len(t.partitionExpr.ColumnOffset) != 1 {
+		return r, nil
+	}
+	offset := t.partitionExpr.ColumnOffset[0]
+	if offset >= len(r) || t.evalBufferTypes[offset].Tp != mysql.TypeTimestamp || r[offset].Kind() != types.KindMysqlTime {
+		return r, nil
+	}
+	tz := ctx.GetSessionVars().Location()
+	if tz == time.UTC {
+		return r, nil
+	}
+	ts := r[offset].GetMysqlTime()
+	if err := ts.ConvertTimeZone(tz, time.UTC); err != nil {
+		return nil, err
+	}
+	row := make([]types.Datum, len(r))
+	copy(row, r)
+	row[offset].SetMysqlTime(ts)
+	return row, nil
+}
+
 func (t *partitionedTable) locateListPartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int, error) {
 	lp := t.partitionExpr.ForListPruning
 	if len(lp.ColPrunes) == 0 {
@@ -68,6 +68,8 @@ type TableCommon struct {
 	meta                            *model.TableInfo
 	allocs                          autoid.Allocators
 	sequence                        *sequenceCommon
+	// changingTable is the table with the target primary key layout when the clustered index is being changed.
+	changingTable *TableCommon
 
 	// recordPrefix and indexPrefix are generated using physicalTableID.
 	recordPrefix kv.Key
@@ -152,6 +154,9 @@ func TableFromMeta(allocs autoid.Allocators, tblInfo *model.TableInfo) (table.Ta
 		if err := initTableIndices(&t); err != nil {
 			return nil, err
 		}
+		if err := initChangingTable(&t, allocs); err != nil {
+			return nil, err
+		}
 		if tblInfo.TableCacheStatusType != model.TableCacheStatusDisable {
 			return newCachedTable(&t)
 		}
@@ -363,7 +368,7 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context,
 		if col.State == model.StateDeleteOnly || col.State == model.StateDeleteReorganization {
 			if col.ChangeStateInfo != nil {
 				// TODO: Check overflow or ignoreTruncate.
-				value, err = table.CastValue(sctx, oldData[col.DependencyColumnOffset], col.ColumnInfo, false, false)
+				value, err = table.CastChangingColumnValue(sctx, oldData[col.DependencyColumnOffset], t.Columns[col.DependencyColumnOffset].ColumnInfo, col.ColumnInfo)
 				if err != nil {
 					logutil.BgLogger().Info("update record cast value failed", zap.Any("col", col), zap.Uint64("txnStartTS", txn.StartTS()),
 						zap.String("handle", h.String()), zap.Any("val", oldData[col.DependencyColumnOffset]), zap.Error(err))
@@ -381,7 +386,7 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context,
 			value = oldData[col.Offset]
 			if col.ChangeStateInfo != nil {
 				// TODO: Check overflow or ignoreTruncate.
-				value, err = table.CastValue(sctx, newData[col.DependencyColumnOffset], col.ColumnInfo, false, false)
+				value, err = table.CastChangingColumnValue(sctx, newData[col.DependencyColumnOffset], t.Columns[col.DependencyColumnOffset].ColumnInfo, col.ColumnInfo)
 				if err != nil {
 					return err
 				}
@@ -429,6 +434,9 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context,
 	if err = memBuffer.Set(key, value); err != nil {
 		return err
 	}
+	if err = t.updateChangingRecord(sctx, txn, oldData, newData); err != nil {
+		return err
+	}
 	memBuffer.Release(sh)
 	if shouldWriteBinlog(sctx, t.meta) {
 		if !t.meta.PKIsHandle && !t.meta.IsCommonHandle {
@@ -456,9 +464,57 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context,
 		colSize[col.ID] = int64(newLen - oldLen)
 	}
 	sessVars.TxnCtx.UpdateDeltaForTable(t.physicalTableID, 0, 1, colSize)
+	t.updateIndexValueDelta(sctx, oldData, touched, -1)
+	t.updateIndexValueDelta(sctx, newData, touched, 1)
 	return nil
 }
 
+// updateIndexValueDelta records the index values of the row changed by DML, which are used to maintain
+// the index statistics incrementally. If touched is not nil, only the indexes on the touched columns are recorded.
+// The partitioned tables are not supported yet.
+func (t *TableCommon) updateIndexValueDelta(sctx sessionctx.Context, r []types.Datum, touched []bool, delta int64) {
+	if !variable.EnableStatsSketchMaintenance.Load() || t.meta.GetPartitionInfo() != nil {
+		return
+	}
+	sessVars := sctx.GetSessionVars()
+	if sessVars.TxnCtx == nil {
+		return
+	}
+	var vals []types.Datum
+	for _, idx := range t.Indices() {
+		idxInfo := idx.Meta()
+		if idxInfo.State != model.StatePublic || idxInfo.Global {
+			continue
+		}
+		if touched != nil && !isIndexTouched(idxInfo, touched) {
+			continue
+		}
+		if sessVars.TxnCtx.IsIndexValueDeltaOverflowed(t.physicalTableID, idxInfo.ID) {
+			continue
+		}
+		var err error
+		vals, err = idx.FetchValues(r, vals)
+		if err != nil {
+			continue
+		}
+		tablecodec.TruncateIndexValues(t.meta, idxInfo, vals)
+		value, err := codec.EncodeKey(sessVars.StmtCtx, nil, vals...)
+		if err != nil {
+			continue
+		}
+		sessVars.TxnCtx.UpdateIndexValueDeltaForTable(t.physicalTableID, idxInfo.ID, value, delta)
+	}
+}
+
+func isIndexTouched(idxInfo *model.IndexInfo, touched []bool) bool {
+	for _, ic := range idxInfo.Columns {
+		if ic.Offset < len(touched) && touched[ic.Offset] {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *TableCommon) rebuildIndices(ctx sessionctx.Context, txn kv.Transaction, h kv.Handle, touched []bool, oldData []types.Datum, newData []types.Datum, opts ...table.CreateIdxOptFunc) error {
 	for _, idx := range t.deletableIndices() {
 		if t.meta.IsCommonHandle && idx.Meta().Primary {
@@ -683,27 +739,11 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 	} else {
 		tblInfo := t.Meta()
 		txn.CacheTableInfo(t.physicalTableID, tblInfo)
-		if tblInfo.PKIsHandle {
-			recordID = kv.IntHandle(r[tblInfo.GetPkColInfo().Offset].GetInt64())
-			hasRecordID = true
-		} else if tblInfo.IsCommonHandle {
-			pkIdx := FindPrimaryIndex(tblInfo)
-			pkDts := make([]types.Datum, 0, len(pkIdx.Columns))
-			for _, idxCol := range pkIdx.Columns {
-				pkDts = append(pkDts, r[idxCol.Offset])
-			}
-			tablecodec.TruncateIndexValues(tblInfo, pkIdx, pkDts)
-			var handleBytes []byte
-			handleBytes, err = codec.EncodeKey(sctx.GetSessionVars().StmtCtx, nil, pkDts...)
-			if err != nil {
-				return
-			}
-			recordID, err = kv.NewCommonHandle(handleBytes)
-			if err != nil {
-				return
-			}
-			hasRecordID = true
+		recordID, err = buildHandleFromRow(sctx.GetSessionVars().StmtCtx, tblInfo, r)
+		if err != nil {
+			return
 		}
+		hasRecordID = recordID != nil
 	}
 	if !hasRecordID {
 		if opt.ReserveAutoID > 0 {
@@ -745,7 +785,7 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 		// for the new insert statement, we should use the casted value of relative column to insert.
 		if col.ChangeStateInfo != nil && col.State != model.StatePublic {
 			// TODO: Check overflow or ignoreTruncate.
-			value, err = table.CastValue(sctx, r[col.DependencyColumnOffset], col.ColumnInfo, false, false)
+			value, err = table.CastChangingColumnValue(sctx, r[col.DependencyColumnOffset], t.Columns[col.DependencyColumnOffset].ColumnInfo, col.ColumnInfo)
 			if err != nil {
 				return nil, err
 			}
@@ -843,6 +883,9 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 	if err != nil {
 		return h, err
 	}
+	if err = t.addChangingRecord(sctx, txn, r); err != nil {
+		return nil, err
+	}
 
 	memBuffer.Release(sh)
 
@@ -867,6 +910,7 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 		colSize[col.ID] = int64(size) - 1
 	}
 	sessVars.TxnCtx.UpdateDeltaForTable(t.physicalTableID, 1, 1, colSize)
+	t.updateIndexValueDelta(sctx, r, nil, 1)
 	return recordID, nil
 }
 
@@ -1037,7 +1081,7 @@ func GetChangingColVal(ctx sessionctx.Context, cols []*table.Column, col *table.
 	relativeCol := cols[col.ChangeStateInfo.DependencyColumnOffset]
 	idxColumnVal, ok := rowMap[relativeCol.ID]
 	if ok {
-		idxColumnVal, err = table.CastValue(ctx, idxColumnVal, col.ColumnInfo, false, false)
+		idxColumnVal, err = table.CastChangingColumnValue(ctx, idxColumnVal, relativeCol.ColumnInfo, col.ColumnInfo)
 		// TODO: Consider sql_mode and the error msg(encounter this error check whether to rollback).
 		if err != nil {
 			return idxColumnVal, false, errors.Trace(err)
@@ -1078,7 +1122,7 @@ func (t *TableCommon) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r []type
 		// The changing column datum derived from related column should be casted here.
 		// Otherwise, the existed changing indexes will not be deleted.
 		relatedColDatum := r[t.Columns[len(r)].ChangeStateInfo.DependencyColumnOffset]
-		value, err := table.CastValue(ctx, relatedColDatum, t.Columns[len(r)].ColumnInfo, false, false)
+		value, err := table.CastChangingColumnValue(ctx, relatedColDatum, t.Columns[t.Columns[len(r)].DependencyColumnOffset].ColumnInfo, t.Columns[len(r)].ColumnInfo)
 		if err != nil {
 			logutil.BgLogger().Info("remove record cast value failed", zap.Any("col", t.Columns[len(r)]),
 				zap.String("handle", h.String()), zap.Any("val", relatedColDatum), zap.Error(err))
@@ -1090,6 +1134,10 @@ func (t *TableCommon) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r []type
 	if err != nil {
 		return err
 	}
+	err = t.removeChangingRecord(ctx, txn, r)
+	if err != nil {
+		return err
+	}
 
 	if shouldWriteBinlog(ctx, t.meta) {
 		cols := t.Cols()
@@ -1125,6 +1173,7 @@ func (t *TableCommon) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r []type
 		colSize[col.ID] = -int64(size - 1)
 	}
 	ctx.GetSessionVars().TxnCtx.UpdateDeltaForTable(t.physicalTableID, -1, 1, colSize)
+	t.updateIndexValueDelta(ctx, r, nil, -1)
 	return err
 }
 
@@ -57,5 +57,6 @@ func generateTelemetryData(ctx sessionctx.Context, trackingID string) telemetryD
 func postReportTelemetryData() {
 	postReportTxnUsage()
 	postReportCTEUsage()
+	postReportMPPUsage()
 	postReportSlowQueryStats()
 }
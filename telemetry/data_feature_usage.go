@@ -37,6 +37,9 @@ type featureUsage struct {
 	TemporaryTable bool               `json:"temporaryTable"`
 	CTE            *m.CTEUsageCounter `json:"cte"`
 	CachedTable    bool               `json:"cachedTable"`
+	// placement policies usage information
+	PlacementPolicy *PlacementPolicyUsage `json:"placementPolicy"`
+	MPP             *MPPUsage             `json:"mpp"`
 }
 
 func getFeatureUsage(ctx sessionctx.Context) (*featureUsage, error) {
@@ -56,7 +59,11 @@ func getFeatureUsage(ctx sessionctx.Context) (*featureUsage, error) {
 
 	cachedTable := ctx.(TemporaryOrCacheTableFeatureChecker).CachedTableExists()
 
-	return &featureUsage{txnUsage, clusterIdxUsage, temporaryTable, cteUsage, cachedTable}, nil
+	placementPolicyUsage := getPlacementPolicyUsageInfo(ctx)
+
+	mppUsage := getMPPUsageInfo(ctx)
+
+	return &featureUsage{txnUsage, clusterIdxUsage, temporaryTable, cteUsage, cachedTable, placementPolicyUsage, mppUsage}, nil
 }
 
 // ClusterIndexUsage records the usage info of all the tables, no more than 10k tables
@@ -154,6 +161,7 @@ type TxnUsage struct {
 
 var initialTxnCommitCounter metrics.TxnCommitCounter
 var initialCTECounter m.CTEUsageCounter
+var initialMPPQueryCounter int64
 
 // getTxnUsageInfo gets the usage info of transaction related features. It's exported for tests.
 func getTxnUsageInfo(ctx sessionctx.Context) *TxnUsage {
@@ -185,3 +193,61 @@ func getCTEUsageInfo() *m.CTEUsageCounter {
 	diff := curr.Sub(initialCTECounter)
 	return &diff
 }
+
+// PlacementPolicyUsage records the usage info of placement policies, including the number of
+// placement policies and the number of databases, tables and partitions which reference them.
+type PlacementPolicyUsage struct {
+	NumPlacementPolicies     uint64 `json:"numPlacementPolicies"`
+	NumDBWithPolicies        uint64 `json:"numDBWithPolicies"`
+	NumTableWithPolicies     uint64 `json:"numTableWithPolicies"`
+	NumPartitionWithPolicies uint64 `json:"numPartitionWithPolicies"`
+}
+
+// getPlacementPolicyUsageInfo gets the usage info of placement policies from the information schema.
+func getPlacementPolicyUsageInfo(ctx sessionctx.Context) *PlacementPolicyUsage {
+	usage := &PlacementPolicyUsage{}
+	is, ok := ctx.GetInfoSchema().(infoschema.InfoSchema)
+	if !ok {
+		return usage
+	}
+	usage.NumPlacementPolicies = uint64(len(is.AllPlacementPolicies()))
+	for _, db := range is.AllSchemas() {
+		if db.PlacementPolicyRef != nil {
+			usage.NumDBWithPolicies++
+		}
+		for _, tbl := range is.SchemaTables(db.Name) {
+			tblInfo := tbl.Meta()
+			if tblInfo.PlacementPolicyRef != nil {
+				usage.NumTableWithPolicies++
+			}
+			if pi := tblInfo.GetPartitionInfo(); pi != nil {
+				for _, def := range pi.Definitions {
+					if def.PlacementPolicyRef != nil {
+						usage.NumPartitionWithPolicies++
+					}
+				}
+			}
+		}
+	}
+	return usage
+}
+
+// MPPUsage records the usage info of MPP, including whether MPP is allowed globally
+// and the count of queries executed in MPP mode.
+type MPPUsage struct {
+	AllowMPP     bool  `json:"allowMPP"`
+	MPPQueryUsed int64 `json:"mppQueryUsed"`
+}
+
+// getMPPUsageInfo gets the usage info of MPP.
+func getMPPUsageInfo(ctx sessionctx.Context) *MPPUsage {
+	allowMPP := false
+	if val, err := variable.GetGlobalSystemVar(ctx.GetSessionVars(), variable.TiDBAllowMPPExecution); err == nil {
+		allowMPP = val == variable.On
+	}
+	return &MPPUsage{allowMPP, m.GetMPPQueryCounter() - initialMPPQueryCounter}
+}
+
+func postReportMPPUsage() {
+	initialMPPQueryCounter = m.GetMPPQueryCounter()
+}
@@ -105,3 +105,46 @@ func TestCachedTable(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, usage.CachedTable)
 }
+
+func TestPlacementPolicies(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	usage, err := telemetry.GetFeatureUsage(tk.Session())
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), usage.PlacementPolicy.NumPlacementPolicies)
+
+	tk.MustExec("create placement policy p1 followers=4")
+	tk.MustExec("create placement policy p2 primary_region='cn-east-1' regions='cn-east-1,cn-east-2'")
+	tk.MustExec("create database db_with_policy placement policy p1")
+	tk.MustExec("create table t1 (a int) placement policy p1")
+	tk.MustExec("create table t2 (a int) partition by range (a) (partition p0 values less than (10) placement policy p2, partition p1 values less than (20))")
+
+	usage, err = telemetry.GetFeatureUsage(tk.Session())
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), usage.PlacementPolicy.NumPlacementPolicies)
+	require.Equal(t, uint64(1), usage.PlacementPolicy.NumDBWithPolicies)
+	require.Equal(t, uint64(1), usage.PlacementPolicy.NumTableWithPolicies)
+	require.Equal(t, uint64(1), usage.PlacementPolicy.NumPartitionWithPolicies)
+}
+
+func TestMPPUsage(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+
+	tk.MustExec(fmt.Sprintf("set global %s = 0", variable.TiDBAllowMPPExecution))
+	usage, err := telemetry.GetFeatureUsage(tk.Session())
+	require.NoError(t, err)
+	require.False(t, usage.MPP.AllowMPP)
+
+	tk.MustExec(fmt.Sprintf("set global %s = 1", variable.TiDBAllowMPPExecution))
+	usage, err = telemetry.GetFeatureUsage(tk.Session())
+	require.NoError(t, err)
+	require.True(t, usage.MPP.AllowMPP)
+	require.GreaterOrEqual(t, usage.MPP.MPPQueryUsed, int64(0))
+}
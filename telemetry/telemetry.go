@@ -64,13 +64,11 @@ func IsTelemetryEnabled(ctx sessionctx.Context) (bool, error) {
 }
 
 // PreviewUsageData returns a preview of the usage data that is going to be reported.
+// The preview is available even if the telemetry is disabled, so users can audit the data before enabling it.
 func PreviewUsageData(ctx sessionctx.Context, etcdClient *clientv3.Client) (string, error) {
 	if etcdClient == nil {
 		return "", nil
 	}
-	if enabled, err := IsTelemetryEnabled(ctx); err != nil || !enabled {
-		return "", err
-	}
 
 	trackingID, err := GetTrackingID(etcdClient)
 	if err != nil {
@@ -62,10 +62,14 @@ func TestPreview(t *testing.T) {
 	require.NoError(t, err)
 	defer se.Close()
 
+	// The preview is available before enabling the telemetry.
 	config.GetGlobalConfig().EnableTelemetry = false
 	r, err := telemetry.PreviewUsageData(se, etcdCluster.RandClient())
 	require.NoError(t, err)
-	require.Equal(t, "", r)
+	jsonParsed, err := gabs.ParseJSON([]byte(r))
+	require.NoError(t, err)
+	require.Equal(t, "", jsonParsed.Path("trackingId").Data().(string))
+	require.True(t, jsonParsed.ExistsP("featureUsage"))
 
 	trackingID, err := telemetry.ResetTrackingID(etcdCluster.RandClient())
 	require.NoError(t, err)
@@ -74,7 +78,7 @@ func TestPreview(t *testing.T) {
 	r, err = telemetry.PreviewUsageData(se, etcdCluster.RandClient())
 	require.NoError(t, err)
 
-	jsonParsed, err := gabs.ParseJSON([]byte(r))
+	jsonParsed, err = gabs.ParseJSON([]byte(r))
 	require.NoError(t, err)
 	require.Equal(t, trackingID, jsonParsed.Path("trackingId").Data().(string))
 	// Apple M1 doesn't contain cpuFlags
@@ -91,15 +95,13 @@ func TestPreview(t *testing.T) {
 	require.NoError(t, err)
 	r, err = telemetry.PreviewUsageData(se, etcdCluster.RandClient())
 	require.NoError(t, err)
-	require.Equal(t, "", r)
+	jsonParsed, err = gabs.ParseJSON([]byte(r))
+	require.NoError(t, err)
+	require.Equal(t, trackingID, jsonParsed.Path("trackingId").Data().(string))
 
 	_, err = se.Execute(context.Background(), "SET @@global.tidb_enable_telemetry = 1")
 	config.GetGlobalConfig().EnableTelemetry = false
 	require.NoError(t, err)
-
-	r, err = telemetry.PreviewUsageData(se, etcdCluster.RandClient())
-	require.NoError(t, err)
-	require.Equal(t, "", r)
 }
 
 func TestReport(t *testing.T) {
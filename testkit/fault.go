@@ -0,0 +1,117 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !codes
+// +build !codes
+
+package testkit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/store/mockstore/unistore"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/testutils"
+)
+
+// FaultInjector injects region errors and network latency into the requests
+// sent to the embedded unistore cluster, so the retry paths can be tested
+// from plain SQL tests. The key range of each fault is split into its own
+// regions before the fault is injected, so requests to other keys are not
+// affected.
+type FaultInjector struct {
+	t       testing.TB
+	cluster *unistore.Cluster
+}
+
+// CreateMockStoreWithFaultInjector returns a new unistore backed kv.Storage
+// bootstrapped with a single store, its *domain.Domain and a *FaultInjector
+// of its cluster.
+func CreateMockStoreWithFaultInjector(t testing.TB, opts ...mockstore.MockTiKVStoreOption) (kv.Storage, *domain.Domain, *FaultInjector, func()) {
+	fi := &FaultInjector{t: t}
+	opts = append(opts,
+		mockstore.WithStoreType(mockstore.EmbedUnistore),
+		mockstore.WithClusterInspector(func(c testutils.Cluster) {
+			mockstore.BootstrapWithSingleStore(c)
+			fi.cluster = c.(*unistore.Cluster)
+		}),
+	)
+	store, dom, clean := CreateMockStoreAndDomain(t, opts...)
+	return store, dom, fi, clean
+}
+
+// Cluster returns the underlying unistore cluster.
+func (fi *FaultInjector) Cluster() *unistore.Cluster {
+	return fi.cluster
+}
+
+// InjectRegionMiss makes the next `times` requests to the regions overlapping
+// [start, end) fail with RegionNotFound. 0 times means until removed.
+func (fi *FaultInjector) InjectRegionMiss(start, end kv.Key, times int) uint64 {
+	return fi.inject(unistore.Fault{Kind: unistore.FaultRegionMiss, StartKey: start, EndKey: end, Times: times})
+}
+
+// InjectEpochNotMatch makes the next `times` requests to the regions
+// overlapping [start, end) fail with EpochNotMatch. 0 times means until removed.
+func (fi *FaultInjector) InjectEpochNotMatch(start, end kv.Key, times int) uint64 {
+	return fi.inject(unistore.Fault{Kind: unistore.FaultEpochNotMatch, StartKey: start, EndKey: end, Times: times})
+}
+
+// InjectServerBusy makes the next `times` requests to the regions overlapping
+// [start, end) fail with ServerIsBusy. 0 times means until removed.
+func (fi *FaultInjector) InjectServerBusy(start, end kv.Key, times int) uint64 {
+	return fi.inject(unistore.Fault{Kind: unistore.FaultServerBusy, StartKey: start, EndKey: end, Times: times})
+}
+
+// InjectLatency delays the requests to the regions overlapping [start, end)
+// until the fault is removed.
+func (fi *FaultInjector) InjectLatency(start, end kv.Key, latency time.Duration) uint64 {
+	return fi.inject(unistore.Fault{Kind: unistore.FaultLatency, StartKey: start, EndKey: end, Latency: latency})
+}
+
+func (fi *FaultInjector) inject(f unistore.Fault) uint64 {
+	require.NotNil(fi.t, fi.cluster, "the store is not created by CreateMockStoreWithFaultInjector")
+	fi.cluster.IsolateRange(f.StartKey, f.EndKey)
+	return fi.cluster.InjectFault(f)
+}
+
+// Remove removes the fault with the given id.
+func (fi *FaultInjector) Remove(id uint64) {
+	fi.cluster.RemoveFault(id)
+}
+
+// Clear removes all the injected faults.
+func (fi *FaultInjector) Clear() {
+	fi.cluster.ClearFaults()
+}
+
+// Hits returns how many requests have been affected by the fault.
+func (fi *FaultInjector) Hits(id uint64) int {
+	return fi.cluster.FaultHits(id)
+}
+
+// TableRange returns the key range that covers the rows and indices of the table.
+func (fi *FaultInjector) TableRange(tk *TestKit, db, table string) (start, end kv.Key) {
+	is := domain.GetDomain(tk.Session()).InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr(db), model.NewCIStr(table))
+	require.NoError(fi.t, err)
+	start = tablecodec.EncodeTablePrefix(tbl.Meta().ID)
+	return start, start.PrefixNext()
+}
@@ -19,6 +19,7 @@ import (
 	"flag"
 	"fmt"
 	"io/fs"
+	"net"
 	"os"
 	"runtime"
 	"strconv"
@@ -46,6 +47,7 @@ import (
 	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/server"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
@@ -60,9 +62,11 @@ import (
 	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/otlptrace"
 	"github.com/pingcap/tidb/util/printer"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/signal"
+	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/pingcap/tidb/util/sys/linux"
 	storageSys "github.com/pingcap/tidb/util/sys/storage"
 	"github.com/pingcap/tidb/util/systimemon"
@@ -113,6 +117,7 @@ const (
 
 	nmInitializeSecure   = "initialize-secure"
 	nmInitializeInsecure = "initialize-insecure"
+	nmInitializeSQLFile  = "initialize-sql-file"
 )
 
 var (
@@ -159,6 +164,7 @@ var (
 	// Security
 	initializeSecure   = flagBoolean(nmInitializeSecure, false, "bootstrap tidb-server in secure mode")
 	initializeInsecure = flagBoolean(nmInitializeInsecure, true, "bootstrap tidb-server in insecure mode")
+	initializeSQLFile  = flag.String(nmInitializeSQLFile, "", "SQL file to execute on first bootstrap")
 )
 
 func main() {
@@ -182,6 +188,7 @@ func main() {
 		checkTempStorageQuota()
 	}
 	setupLog()
+	setupStmtSummary()
 	err := cpuprofile.StartCPUProfiler()
 	terror.MustNil(err)
 
@@ -528,6 +535,9 @@ func overrideConfig(cfg *config.Config) {
 		err = fmt.Errorf("the option --initialize-secure is not supported on Windows")
 		terror.MustNil(err)
 	}
+	if actualFlags[nmInitializeSQLFile] {
+		cfg.InitializeSQLFile = *initializeSQLFile
+	}
 }
 
 func setGlobalVars() {
@@ -636,6 +646,8 @@ func setGlobalVars() {
 	tikv.SetStoreLivenessTimeout(t)
 	parsertypes.TiDBStrictIntegerDisplayWidth = cfg.DeprecateIntegerDisplayWidth
 	deadlockhistory.GlobalDeadlockHistory.Resize(cfg.PessimisticTxn.DeadlockHistoryCapacity)
+	txninfo.Recorder.Resize(cfg.TrxSummary.TransactionSummaryCapacity)
+	txninfo.Recorder.SetMinDuration(time.Duration(cfg.TrxSummary.TransactionSummaryMinDuration) * time.Millisecond)
 }
 
 func setupLog() {
@@ -647,6 +659,20 @@ func setupLog() {
 	util.InternalHTTPClient()
 }
 
+func setupStmtSummary() {
+	cfg := config.GetGlobalConfig()
+	if !cfg.StmtSummary.EnablePersistent {
+		return
+	}
+	err := stmtsummary.StmtSummaryByDigestMap.SetupPersistent(&stmtsummary.PersistentConfig{
+		Filename:       cfg.StmtSummary.Filename,
+		FileMaxSize:    cfg.StmtSummary.FileMaxSize,
+		FileMaxDays:    cfg.StmtSummary.FileMaxDays,
+		FileMaxBackups: cfg.StmtSummary.FileMaxBackups,
+	})
+	terror.MustNil(err)
+}
+
 func printInfo() {
 	// Make sure the TiDB info is always printed.
 	level := log.GetLevel()
@@ -701,6 +727,16 @@ func setupTracing() {
 		log.Fatal("setup jaeger tracer failed", zap.String("error message", err.Error()))
 	}
 	opentracing.SetGlobalTracer(tracer)
+
+	if otlpCfg := cfg.OpenTracing.OTLP; otlpCfg.Enable {
+		otlptrace.Setup(otlptrace.Config{
+			Endpoint:      otlpCfg.Endpoint,
+			SampleRate:    otlpCfg.SampleRate,
+			SlowThreshold: time.Duration(otlpCfg.SlowThreshold) * time.Millisecond,
+			ServiceName:   "TiDB",
+			InstanceID:    net.JoinHostPort(cfg.AdvertiseAddress, strconv.Itoa(int(cfg.Port))),
+		})
+	}
 }
 
 func closeDomainAndStorage(storage kv.Storage, dom *domain.Domain) {
@@ -721,6 +757,8 @@ func cleanup(svr *server.Server, storage kv.Storage, dom *domain.Domain, gracefu
 	closeDomainAndStorage(storage, dom)
 	disk.CleanUp()
 	topsql.Close()
+	stmtsummary.StmtSummaryByDigestMap.ClosePersistent()
+	otlptrace.Close()
 }
 
 func stringToList(repairString string) []string {
@@ -29,6 +29,12 @@ var (
 	ExplainFormatVerbose = "verbose"
 	// ExplainFormatTraditional is the same as ExplainFormatROW.
 	ExplainFormatTraditional = "traditional"
+	// ExplainFormatCostTrace is the same as ExplainFormatROW, but it also displays the estimated cost of every
+	// operator and how the cost is accumulated from its children.
+	ExplainFormatCostTrace = "cost_trace"
+	// ExplainFormatSelectivity displays how the cardinality of every predicate is estimated by the statistics,
+	// instead of the plan itself.
+	ExplainFormatSelectivity = "selectivity"
 
 	// ExplainFormats stores the valid formats for explain statement, used by validator.
 	ExplainFormats = []string{
@@ -39,5 +45,7 @@ var (
 		ExplainFormatROW,
 		ExplainFormatVerbose,
 		ExplainFormatTraditional,
+		ExplainFormatCostTrace,
+		ExplainFormatSelectivity,
 	}
 )
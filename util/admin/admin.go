@@ -124,6 +124,60 @@ func IsJobRollbackable(job *model.Job) bool {
 
 // CancelJobs cancels the DDL jobs.
 func CancelJobs(txn kv.Transaction, ids []int64) ([]error, error) {
+	return updateJobs(txn, ids, func(job *model.Job) (bool, error) {
+		// These states can't be cancelled.
+		if job.IsDone() || job.IsSynced() {
+			return false, ErrCancelFinishedDDLJob.GenWithStackByArgs(job.ID)
+		}
+		// If the state is rolling back, it means the work is cleaning the data after cancelling the job.
+		if job.IsCancelled() || job.IsRollingback() || job.IsRollbackDone() {
+			return false, nil
+		}
+		if !IsJobRollbackable(job) {
+			return false, ErrCannotCancelDDLJob.GenWithStackByArgs(job.ID)
+		}
+		job.State = model.JobStateCancelling
+		return true, nil
+	})
+}
+
+// PauseJobs pauses the DDL jobs. A paused job is not run until it is resumed,
+// and the jobs queued after it keep running unless they depend on it.
+func PauseJobs(txn kv.Transaction, ids []int64) ([]error, error) {
+	return updateJobs(txn, ids, func(job *model.Job) (bool, error) {
+		if job.IsFinished() || job.IsSynced() || job.Paused {
+			return false, nil
+		}
+		job.Paused = true
+		return true, nil
+	})
+}
+
+// ResumeJobs resumes the paused DDL jobs.
+func ResumeJobs(txn kv.Transaction, ids []int64) ([]error, error) {
+	return updateJobs(txn, ids, func(job *model.Job) (bool, error) {
+		if !job.Paused {
+			return false, nil
+		}
+		job.Paused = false
+		return true, nil
+	})
+}
+
+// UpdateJobsPriority updates the priority of the DDL jobs.
+func UpdateJobsPriority(txn kv.Transaction, ids []int64, priority int) ([]error, error) {
+	return updateJobs(txn, ids, func(job *model.Job) (bool, error) {
+		if job.IsFinished() || job.IsSynced() || job.Priority == priority {
+			return false, nil
+		}
+		job.Priority = priority
+		return true, nil
+	})
+}
+
+// updateJobs applies fn to the queued DDL jobs with the given ids, and saves
+// the jobs changed by fn.
+func updateJobs(txn kv.Transaction, ids []int64, fn func(job *model.Job) (bool, error)) ([]error, error) {
 	if len(ids) == 0 {
 		return nil, nil
 	}
@@ -140,33 +194,25 @@ func CancelJobs(txn kv.Transaction, ids []int64) ([]error, error) {
 	}
 	jobs := append(generalJobs, addIdxJobs...)
 
+	updated := false
 	for i, id := range ids {
 		found := false
 		for j, job := range jobs {
 			if id != job.ID {
-				logutil.BgLogger().Debug("the job that needs to be canceled isn't equal to current job",
-					zap.Int64("need to canceled job ID", id),
+				logutil.BgLogger().Debug("the job that needs to be updated isn't equal to current job",
+					zap.Int64("need to updated job ID", id),
 					zap.Int64("current job ID", job.ID))
 				continue
 			}
 			found = true
-			// These states can't be cancelled.
-			if job.IsDone() || job.IsSynced() {
-				errs[i] = ErrCancelFinishedDDLJob.GenWithStackByArgs(id)
-				continue
-			}
-			// If the state is rolling back, it means the work is cleaning the data after cancelling the job.
-			if job.IsCancelled() || job.IsRollingback() || job.IsRollbackDone() {
-				continue
-			}
-			if !IsJobRollbackable(job) {
-				errs[i] = ErrCannotCancelDDLJob.GenWithStackByArgs(job.ID)
+			changed, err := fn(job)
+			if err != nil || !changed {
+				errs[i] = err
 				continue
 			}
 
-			job.State = model.JobStateCancelling
 			// Make sure RawArgs isn't overwritten.
-			err := json.Unmarshal(job.RawArgs, &job.Args)
+			err = json.Unmarshal(job.RawArgs, &job.Args)
 			if err != nil {
 				errs[i] = errors.Trace(err)
 				continue
@@ -179,12 +225,20 @@ func CancelJobs(txn kv.Transaction, ids []int64) ([]error, error) {
 			}
 			if err != nil {
 				errs[i] = errors.Trace(err)
+				continue
 			}
+			updated = true
 		}
 		if !found {
 			errs[i] = ErrDDLJobNotFound.GenWithStackByArgs(id)
 		}
 	}
+	if updated {
+		// The DDL owner checks the paused jobs again only after the queued jobs are updated.
+		if err = t.IncDDLJobUpdateVersion(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	return errs, nil
 }
 
@@ -401,3 +401,61 @@ func enQueueDDLJobs(t *testing.T, m *meta.Meta, jobType model.ActionType, start,
 		require.NoError(t, err)
 	}
 }
+
+func TestPauseResumeAndPrioritizeJobs(t *testing.T) {
+	store, clean := newMockStore(t)
+	defer clean()
+
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, txn.Rollback())
+	}()
+
+	m := meta.NewMeta(txn)
+	require.NoError(t, m.EnQueueDDLJob(&model.Job{ID: 1, SchemaID: 1, Type: model.ActionCreateTable}))
+	require.NoError(t, m.EnQueueDDLJob(&model.Job{ID: 2, SchemaID: 1, Type: model.ActionCreateTable, State: model.JobStateDone}))
+	require.NoError(t, meta.NewMeta(txn, meta.AddIndexJobListKey).EnQueueDDLJob(&model.Job{ID: 3, SchemaID: 1, Type: model.ActionAddIndex}))
+
+	errs, err := PauseJobs(txn, []int64{1, 2, 3, 4})
+	require.NoError(t, err)
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.NoError(t, errs[2])
+	require.Regexp(t, "DDL Job:4 not found$", errs[3].Error())
+
+	jobs, err := GetDDLJobs(txn)
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+	require.True(t, jobs[0].Paused)
+	require.False(t, jobs[1].Paused)
+	require.True(t, jobs[2].Paused)
+	ver, err := m.GetDDLJobUpdateVersion()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), ver)
+
+	// Pausing the paused jobs changes nothing.
+	errs, err = PauseJobs(txn, []int64{1, 3})
+	require.NoError(t, err)
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	ver, err = m.GetDDLJobUpdateVersion()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), ver)
+
+	errs, err = ResumeJobs(txn, []int64{3})
+	require.NoError(t, err)
+	require.NoError(t, errs[0])
+	errs, err = UpdateJobsPriority(txn, []int64{3}, kv.PriorityHigh)
+	require.NoError(t, err)
+	require.NoError(t, errs[0])
+
+	jobs, err = GetDDLJobs(txn)
+	require.NoError(t, err)
+	require.True(t, jobs[0].Paused)
+	require.False(t, jobs[2].Paused)
+	require.Equal(t, kv.PriorityHigh, jobs[2].Priority)
+	ver, err = m.GetDDLJobUpdateVersion()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), ver)
+}
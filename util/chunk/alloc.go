@@ -15,6 +15,8 @@
 package chunk
 
 import (
+	"sync"
+
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/tidb/types"
 )
@@ -34,6 +36,14 @@ func NewAllocator() *allocator {
 	return ret
 }
 
+// NewAllocatorWithQuota creates an Allocator which caches at most quota bytes of
+// recycled chunk columns, a non-positive quota means no limit.
+func NewAllocatorWithQuota(quota int64) *allocator {
+	ret := NewAllocator()
+	ret.columnAlloc.quota = quota
+	return ret
+}
+
 var _ Allocator = &allocator{}
 
 // allocator try to reuse objects.
@@ -98,6 +108,10 @@ var _ ColumnAllocator = &poolColumnAllocator{}
 
 type poolColumnAllocator struct {
 	pool map[int]freeList
+	// quota is the max bytes of the cached columns, non-positive means no limit.
+	quota     int64
+	freeBytes int64
+	stats     AllocatorStats
 }
 
 // poolColumnAllocator implements the ColumnAllocator interface.
@@ -106,8 +120,12 @@ func (alloc *poolColumnAllocator) NewColumn(ft *types.FieldType, count int) *Col
 	l := alloc.pool[typeSize]
 	if l != nil && !l.empty() {
 		col := l.pop()
+		col.reset()
+		alloc.freeBytes -= col.capacityBytes()
+		alloc.stats.ReusedColumns++
 		return col
 	}
+	alloc.stats.NewColumns++
 	return newColumn(typeSize, count)
 }
 
@@ -121,12 +139,20 @@ func (alloc *poolColumnAllocator) put(col *Column) {
 		return
 	}
 
+	size := col.capacityBytes()
+	if alloc.quota > 0 && alloc.freeBytes+size > alloc.quota {
+		// Don't cache more than the quota.
+		return
+	}
+
 	l := alloc.pool[typeSize]
 	if l == nil {
 		l = make(map[*Column]struct{}, 8)
 		alloc.pool[typeSize] = l
 	}
-	l.push(col)
+	if l.push(col) {
+		alloc.freeBytes += size
+	}
 }
 
 // freeList is defined as a map, rather than a list, because when recycling chunk
@@ -146,10 +172,70 @@ func (l freeList) pop() *Column {
 	return nil
 }
 
-func (l freeList) push(c *Column) {
+// push adds the column into the list, it returns whether a new column is cached.
+func (l freeList) push(c *Column) bool {
 	if len(l) >= maxFreeColumnsPerType {
 		// Don't cache too much to save memory.
-		return
+		return false
+	}
+	if _, ok := l[c]; ok {
+		return false
 	}
 	l[c] = struct{}{}
+	return true
+}
+
+// AllocatorStats is the statistics of the columns allocated by an Allocator.
+type AllocatorStats struct {
+	// NewColumns is the number of the columns newly allocated.
+	NewColumns int64
+	// ReusedColumns is the number of the columns reused from the recycled ones.
+	ReusedColumns int64
+}
+
+// SyncAllocator is an Allocator which is safe for concurrent use, so it can be
+// shared by all the executors of a session as an arena. The chunks allocated
+// from it are recycled together by Reset, so they must not be used after that.
+type SyncAllocator struct {
+	mu    sync.Mutex
+	alloc *allocator
+}
+
+var _ Allocator = &SyncAllocator{}
+
+// NewSyncAllocator creates a SyncAllocator which caches at most quota bytes of
+// recycled chunk columns, a non-positive quota means no limit.
+func NewSyncAllocator(quota int64) *SyncAllocator {
+	return &SyncAllocator{alloc: NewAllocatorWithQuota(quota)}
+}
+
+// Alloc implements the Allocator interface.
+func (a *SyncAllocator) Alloc(fields []*types.FieldType, cap, maxChunkSize int) *Chunk {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.alloc.Alloc(fields, cap, maxChunkSize)
+}
+
+// Reset implements the Allocator interface.
+func (a *SyncAllocator) Reset() {
+	a.mu.Lock()
+	a.alloc.Reset()
+	a.mu.Unlock()
+}
+
+// SetQuota sets the max bytes of the cached columns, the columns which are
+// already cached are not released even if they exceed the new quota.
+func (a *SyncAllocator) SetQuota(quota int64) {
+	a.mu.Lock()
+	a.alloc.columnAlloc.quota = quota
+	a.mu.Unlock()
+}
+
+// TakeStats returns the statistics since the last call and clears them.
+func (a *SyncAllocator) TakeStats() AllocatorStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stats := a.alloc.columnAlloc.stats
+	a.alloc.columnAlloc.stats = AllocatorStats{}
+	return stats
 }
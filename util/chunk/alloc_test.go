@@ -151,3 +151,40 @@ func TestNoDuplicateColumnReuse(t *testing.T) {
 		}
 	}
 }
+
+func TestSyncAllocator(t *testing.T) {
+	fieldTypes := []*types.FieldType{
+		{Tp: mysql.TypeLonglong},
+		{Tp: mysql.TypeDouble},
+		{Tp: mysql.TypeVarchar},
+	}
+	alloc := NewSyncAllocator(0)
+	chk := alloc.Alloc(fieldTypes, 5, 10)
+	chk.AppendInt64(0, 1)
+	chk.AppendFloat64(1, 1)
+	chk.AppendString(2, "a")
+	alloc.Reset()
+	require.Equal(t, AllocatorStats{NewColumns: 3}, alloc.TakeStats())
+	require.Equal(t, AllocatorStats{}, alloc.TakeStats())
+
+	// The fixed length columns are reused and reset.
+	chk = alloc.Alloc(fieldTypes, 5, 10)
+	require.Equal(t, 0, chk.NumRows())
+	require.Equal(t, AllocatorStats{NewColumns: 1, ReusedColumns: 2}, alloc.TakeStats())
+	alloc.Reset()
+
+	// The recycled columns exceeding the quota are not cached.
+	col := NewColumn(fieldTypes[0], 5)
+	alloc.SetQuota(col.capacityBytes())
+	alloc.Reset()
+	alloc.Alloc(fieldTypes, 5, 10)
+	alloc.Alloc(fieldTypes, 5, 10)
+	alloc.Reset()
+	alloc.TakeStats()
+	alloc.Alloc(fieldTypes, 5, 10)
+	alloc.Alloc(fieldTypes, 5, 10)
+	stats := alloc.TakeStats()
+	require.Equal(t, int64(6), stats.NewColumns+stats.ReusedColumns)
+	require.LessOrEqual(t, stats.ReusedColumns, int64(2))
+	require.LessOrEqual(t, alloc.alloc.columnAlloc.freeBytes, col.capacityBytes())
+}
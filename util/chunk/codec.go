@@ -222,6 +222,15 @@ func EstimateTypeWidth(colType *types.FieldType) int {
 	return 32
 }
 
+// EstimateRowWidth estimates the average width of rows of the types.
+func EstimateRowWidth(fields []*types.FieldType) int {
+	width := 0
+	for _, f := range fields {
+		width += EstimateTypeWidth(f)
+	}
+	return width
+}
+
 func init() {
 	for i := 0; i < 128; i++ {
 		allNotNullBitmap[i] = 0xFF
@@ -125,6 +125,11 @@ func (c *Column) typeSize() int {
 	return varElemLen
 }
 
+// capacityBytes returns the bytes of the buffers allocated by the column.
+func (c *Column) capacityBytes() int64 {
+	return int64(cap(c.nullBitmap)) + int64(cap(c.offsets)*8) + int64(cap(c.data)) + int64(cap(c.elemBuf))
+}
+
 func (c *Column) isFixed() bool {
 	return c.elemBuf != nil
 }
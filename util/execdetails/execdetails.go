@@ -108,6 +108,30 @@ const (
 	RocksdbBlockReadByteStr = "Rocksdb_block_read_byte"
 )
 
+// The cost model of the request units, which follows the default one of the resource control in TiKV.
+const (
+	ruPerReadRequest  = 0.125
+	ruPerReadByte     = 1.0 / (64 * 1024)
+	ruPerWriteRequest = 1.0
+	ruPerWriteByte    = 1.0 / 1024
+	ruPerCPUMs        = 1.0 / 3
+)
+
+// RequestUnits estimates the request units consumed by the execution from the read requests,
+// the scanned bytes, the TiKV process time and the written bytes.
+func (d ExecDetails) RequestUnits() float64 {
+	ru := float64(d.RequestCount) * ruPerReadRequest
+	ru += float64(d.TimeDetail.ProcessTime) / float64(time.Millisecond) * ruPerCPUMs
+	if d.ScanDetail != nil {
+		ru += float64(d.ScanDetail.ProcessedKeysSize) * ruPerReadByte
+	}
+	if d.CommitDetail != nil {
+		ru += float64(d.CommitDetail.PrewriteRegionNum) * ruPerWriteRequest
+		ru += float64(d.CommitDetail.WriteSize) * ruPerWriteByte
+	}
+	return ru
+}
+
 // String implements the fmt.Stringer interface.
 func (d ExecDetails) String() string {
 	parts := make([]string, 0, 8)
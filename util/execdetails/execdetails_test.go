@@ -300,3 +300,13 @@ func TestFormatDurationForExplain(t *testing.T) {
 		require.Equal(t, ca.s, result)
 	}
 }
+
+func TestRequestUnits(t *testing.T) {
+	detail := ExecDetails{}
+	require.Equal(t, 0.0, detail.RequestUnits())
+	detail.RequestCount = 8
+	detail.TimeDetail.ProcessTime = 3 * time.Millisecond
+	detail.ScanDetail = &util.ScanDetail{ProcessedKeysSize: 64 * 1024}
+	detail.CommitDetail = &util.CommitDetails{PrewriteRegionNum: 2, WriteSize: 2048}
+	require.Equal(t, 7.0, detail.RequestUnits())
+}
@@ -139,6 +139,9 @@ func genLogFields(costTime time.Duration, info *util.ProcessInfo) []zap.Field {
 	if info.ID != 0 {
 		logFields = append(logFields, zap.Uint64("conn_id", info.ID))
 	}
+	if len(info.SessionAlias) > 0 {
+		logFields = append(logFields, zap.String("session_alias", info.SessionAlias))
+	}
 	if len(info.User) > 0 {
 		logFields = append(logFields, zap.String("user", info.User))
 	}
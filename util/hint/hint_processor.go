@@ -16,6 +16,7 @@ package hint
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -273,7 +274,16 @@ func ParseHintsSet(p *parser.Parser, sql, charset, collation, db string) (*Hints
 			curOffset = curOffset - 1
 		}
 		for _, tblHint := range tblHints {
-			if tblHint.HintName.L == hintQBName {
+			if tblHint.HintName.L == HintQBName {
+				// The query block names of views are kept since the hints inside views are resolved
+				// when the views are built.
+				if len(tblHint.Tables) > 0 {
+					newHints = append(newHints, tblHint)
+				}
+				continue
+			}
+			if processor.viewQBName(tblHint) != "" {
+				newHints = append(newHints, tblHint)
 				continue
 			}
 			offset := processor.GetHintOffset(tblHint.QBName, curOffset)
@@ -315,8 +325,11 @@ func extractHintWarns(warns []error) []error {
 
 // BlockHintProcessor processes hints at different level of sql statement.
 type BlockHintProcessor struct {
-	QbNameMap        map[string]int                    // Map from query block name to select stmt offset.
-	QbHints          map[int][]*ast.TableOptimizerHint // Group all hints at same query block.
+	QbNameMap        map[string]int                       // Map from query block name to select stmt offset.
+	QbHints          map[int][]*ast.TableOptimizerHint    // Group all hints at same query block.
+	QbNameMap4View   map[string][]ast.HintTable           // Map from query block name to the view path it points to.
+	QbHints4View     map[string][]*ast.TableOptimizerHint // Group all hints at same query block of views.
+	QbNameUsed4View  map[string]struct{}                  // Record the query block names of views that are reached.
 	Ctx              sessionctx.Context
 	selectStmtOffset int
 }
@@ -346,13 +359,18 @@ func (p *BlockHintProcessor) Leave(in ast.Node) (ast.Node, bool) {
 	return in, true
 }
 
-const hintQBName = "qb_name"
+// HintQBName is the name of the hint which names a query block.
+const HintQBName = "qb_name"
 
 // checkQueryBlockHints checks the validity of query blocks and records the map of query block name to select offset.
 func (p *BlockHintProcessor) checkQueryBlockHints(hints []*ast.TableOptimizerHint, offset int) {
 	var qbName string
 	for _, hint := range hints {
-		if hint.HintName.L != hintQBName {
+		if hint.HintName.L != HintQBName {
+			continue
+		}
+		if len(hint.Tables) > 0 {
+			p.checkQueryBlockHints4View(hint, offset)
 			continue
 		}
 		if qbName != "" {
@@ -369,7 +387,7 @@ func (p *BlockHintProcessor) checkQueryBlockHints(hints []*ast.TableOptimizerHin
 	if p.QbNameMap == nil {
 		p.QbNameMap = make(map[string]int)
 	}
-	if _, ok := p.QbNameMap[qbName]; ok {
+	if p.isQBNameUsed(qbName) {
 		if p.Ctx != nil {
 			p.Ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New(fmt.Sprintf("Duplicate query block name %s, only the first one is effective", qbName)))
 		}
@@ -378,6 +396,141 @@ func (p *BlockHintProcessor) checkQueryBlockHints(hints []*ast.TableOptimizerHin
 	}
 }
 
+func (p *BlockHintProcessor) isQBNameUsed(qbName string) bool {
+	if _, ok := p.QbNameMap[qbName]; ok {
+		return true
+	}
+	_, ok := p.QbNameMap4View[qbName]
+	return ok
+}
+
+// checkQueryBlockHints4View records the view path of a QB_NAME hint like `qb_name(qb, v1@sel_1 .v2@sel_2 .@sel_2)`,
+// the hints of the query block take effect in the query block that the path points to.
+func (p *BlockHintProcessor) checkQueryBlockHints4View(hint *ast.TableOptimizerHint, offset int) {
+	qbName := hint.QBName.L
+	for i, table := range hint.Tables {
+		// Only the last element of the path can be a bare query block of the innermost view.
+		if table.TableName.L == "" && (i == 0 || i != len(hint.Tables)-1) {
+			if p.Ctx != nil {
+				p.Ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New(fmt.Sprintf("The view path of query block name %s is invalid, the hint is ignored", qbName)))
+			}
+			return
+		}
+	}
+	if p.isQBNameUsed(qbName) {
+		if p.Ctx != nil {
+			p.Ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New(fmt.Sprintf("Duplicate query block name %s, only the first one is effective", qbName)))
+		}
+		return
+	}
+	path := append([]ast.HintTable(nil), hint.Tables...)
+	// The first view without query block is the one in the query block where the hint is written.
+	if path[0].QBName.L == "" {
+		if offset == 0 {
+			path[0].QBName = model.NewCIStr(defaultUpdateBlockName)
+		} else {
+			path[0].QBName = model.NewCIStr(fmt.Sprintf("%s%d", defaultSelectBlockPrefix, offset))
+		}
+	}
+	if p.QbNameMap4View == nil {
+		p.QbNameMap4View = make(map[string][]ast.HintTable)
+	}
+	p.QbNameMap4View[qbName] = path
+}
+
+// viewQBName returns the query block name of the hint if it takes effect in a view. The query block is
+// specified either for the whole hint like `hash_agg(@qb)` or for its tables like `hash_join(t1@qb, t2@qb)`.
+func (p *BlockHintProcessor) viewQBName(hint *ast.TableOptimizerHint) string {
+	qbName := hint.QBName
+	if qbName.L == "" && len(hint.Tables) > 0 {
+		qbName = hint.Tables[0].QBName
+	}
+	if qbName.L == "" {
+		return ""
+	}
+	if _, ok := p.QbNameMap4View[qbName.L]; ok {
+		return qbName.L
+	}
+	return ""
+}
+
+// HintProcessor4View creates the hint processor for the view which is referenced in the query block at `offset`.
+// The hints whose view paths go through the view are inherited by the processor, so they are able to take
+// effect in the nested views or the query blocks of the view.
+func (p *BlockHintProcessor) HintProcessor4View(viewName model.CIStr, offset int, viewStmt ast.Node) *BlockHintProcessor {
+	processor := &BlockHintProcessor{Ctx: p.Ctx}
+	viewStmt.Accept(processor)
+	qbNames := make([]string, 0, len(p.QbNameMap4View))
+	for qbName := range p.QbNameMap4View {
+		qbNames = append(qbNames, qbName)
+	}
+	sort.Strings(qbNames)
+	for _, qbName := range qbNames {
+		path := p.QbNameMap4View[qbName]
+		// The view without query block in a nested view is the one in the top query block of that view.
+		if path[0].TableName.L != viewName.L || p.GetHintOffset(path[0].QBName, 1) != offset {
+			continue
+		}
+		if p.QbNameUsed4View == nil {
+			p.QbNameUsed4View = make(map[string]struct{})
+		}
+		p.QbNameUsed4View[qbName] = struct{}{}
+		if processor.isQBNameUsed(qbName) {
+			if p.Ctx != nil {
+				p.Ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New(fmt.Sprintf("Duplicate query block name %s in view %s, only the one in the view is effective", qbName, viewName.O)))
+			}
+			continue
+		}
+		path = path[1:]
+		if len(path) > 0 && path[0].TableName.L != "" {
+			if processor.QbNameMap4View == nil {
+				processor.QbNameMap4View = make(map[string][]ast.HintTable)
+				processor.QbHints4View = make(map[string][]*ast.TableOptimizerHint)
+			}
+			processor.QbNameMap4View[qbName] = path
+			processor.QbHints4View[qbName] = p.QbHints4View[qbName]
+			continue
+		}
+		// The path ends at this view, the hints take effect in its top query block or the specified one.
+		blockOffset := 1
+		if len(path) > 0 {
+			blockOffset = processor.getBlockOffset(path[0].QBName)
+		}
+		if blockOffset <= 0 {
+			if p.Ctx != nil {
+				p.Ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New(fmt.Sprintf("Unknown query block name %s in view %s", path[0].QBName.O, viewName.O)))
+			}
+			continue
+		}
+		if processor.QbNameMap == nil {
+			processor.QbNameMap = make(map[string]int)
+		}
+		if processor.QbHints == nil {
+			processor.QbHints = make(map[int][]*ast.TableOptimizerHint)
+		}
+		processor.QbNameMap[qbName] = blockOffset
+		processor.QbHints[blockOffset] = append(processor.QbHints[blockOffset], p.QbHints4View[qbName]...)
+	}
+	return processor
+}
+
+// HandleUnusedViewHints warns the hints whose view paths don't reach any view.
+func (p *BlockHintProcessor) HandleUnusedViewHints() {
+	if p.Ctx == nil {
+		return
+	}
+	qbNames := make([]string, 0, len(p.QbNameMap4View))
+	for qbName := range p.QbNameMap4View {
+		if _, ok := p.QbNameUsed4View[qbName]; !ok {
+			qbNames = append(qbNames, qbName)
+		}
+	}
+	sort.Strings(qbNames)
+	for _, qbName := range qbNames {
+		p.Ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New(fmt.Sprintf("The view path of query block name %s doesn't match any view, the hints are ignored", qbName)))
+	}
+}
+
 const (
 	defaultUpdateBlockName   = "upd_1"
 	defaultDeleteBlockName   = "del_1"
@@ -459,7 +612,14 @@ func (p *BlockHintProcessor) GetCurrentStmtHints(hints []*ast.TableOptimizerHint
 		p.QbHints = make(map[int][]*ast.TableOptimizerHint)
 	}
 	for _, hint := range hints {
-		if hint.HintName.L == hintQBName {
+		if hint.HintName.L == HintQBName {
+			continue
+		}
+		if qbName := p.viewQBName(hint); qbName != "" {
+			if p.QbHints4View == nil {
+				p.QbHints4View = make(map[string][]*ast.TableOptimizerHint)
+			}
+			p.QbHints4View[qbName] = append(p.QbHints4View[qbName], hint)
 			continue
 		}
 		offset := p.GetHintOffset(hint.QBName, currentOffset)
@@ -0,0 +1,262 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptrace
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// queueSize is the max number of the statements waiting to be exported,
+	// the statements are dropped if the queue is full.
+	queueSize = 1024
+	// batchSize is the max number of the statements exported in a request.
+	batchSize     = 64
+	flushInterval = time.Second
+	exportTimeout = 10 * time.Second
+)
+
+// The kinds of the spans, see https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/trace/v1/trace.proto
+const (
+	spanKindInternal = 1
+	spanKindServer   = 2
+)
+
+// The types below are the JSON encoding of the OTLP ExportTraceServiceRequest.
+// See https://github.com/open-telemetry/opentelemetry-proto/blob/main/docs/specification.md#json-protobuf-encoding
+
+type exportRequest struct {
+	ResourceSpans []*resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource      `json:"resource"`
+	ScopeSpans []*scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []*attribute `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope   `json:"scope"`
+	Spans []*span `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string       `json:"traceId"`
+	SpanID            string       `json:"spanId"`
+	ParentSpanID      string       `json:"parentSpanId,omitempty"`
+	Name              string       `json:"name"`
+	Kind              int          `json:"kind"`
+	StartTimeUnixNano int64        `json:"startTimeUnixNano,string"`
+	EndTimeUnixNano   int64        `json:"endTimeUnixNano,string"`
+	Attributes        []*attribute `json:"attributes,omitempty"`
+	Events            []*event     `json:"events,omitempty"`
+}
+
+type event struct {
+	TimeUnixNano int64        `json:"timeUnixNano,string"`
+	Name         string       `json:"name"`
+	Attributes   []*attribute `json:"attributes,omitempty"`
+}
+
+type attribute struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *int64   `json:"intValue,omitempty,string"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func newAttribute(key string, value interface{}) *attribute {
+	attr := &attribute{Key: key}
+	switch v := value.(type) {
+	case string:
+		attr.Value.StringValue = &v
+	case bool:
+		attr.Value.BoolValue = &v
+	case int:
+		i := int64(v)
+		attr.Value.IntValue = &i
+	case int32:
+		i := int64(v)
+		attr.Value.IntValue = &i
+	case int64:
+		attr.Value.IntValue = &v
+	case uint32:
+		i := int64(v)
+		attr.Value.IntValue = &i
+	case uint64:
+		i := int64(v)
+		attr.Value.IntValue = &i
+	case float32:
+		f := float64(v)
+		attr.Value.DoubleValue = &f
+	case float64:
+		attr.Value.DoubleValue = &v
+	default:
+		s := fmt.Sprint(v)
+		attr.Value.StringValue = &s
+	}
+	return attr
+}
+
+func convertTags(tags opentracing.Tags) []*attribute {
+	if len(tags) == 0 {
+		return nil
+	}
+	attrs := make([]*attribute, 0, len(tags))
+	for k, v := range tags {
+		attrs = append(attrs, newAttribute(k, v))
+	}
+	return attrs
+}
+
+func encodeSpanID(id uint64) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], id)
+	return hex.EncodeToString(buf[:])
+}
+
+// exporter exports the spans of the statements asynchronously in batches.
+type exporter struct {
+	cfg      Config
+	client   *http.Client
+	resource resource
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan []*span
+	wg     sync.WaitGroup
+}
+
+func newExporter(cfg Config) *exporter {
+	e := &exporter{
+		cfg:    cfg,
+		client: &http.Client{Timeout: exportTimeout},
+		resource: resource{Attributes: []*attribute{
+			newAttribute("service.name", cfg.ServiceName),
+			newAttribute("service.instance.id", cfg.InstanceID),
+		}},
+		queue: make(chan []*span, queueSize),
+	}
+	e.wg.Add(1)
+	go e.run()
+	return e
+}
+
+// enqueue adds the spans of a statement to the queue. The spans are dropped if
+// the queue is full, so the statement is never blocked by exporting.
+func (e *exporter) enqueue(spans []*span) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed {
+		return
+	}
+	select {
+	case e.queue <- spans:
+	default:
+		logutil.BgLogger().Debug("[otlptrace] queue is full, drop the spans")
+	}
+}
+
+func (e *exporter) run() {
+	defer e.wg.Done()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	batch := make([]*span, 0, batchSize)
+	stmts := 0
+	flush := func() {
+		if stmts == 0 {
+			return
+		}
+		if err := e.export(batch); err != nil {
+			logutil.BgLogger().Warn("[otlptrace] export spans failed", zap.String("endpoint", e.cfg.Endpoint), zap.Error(err))
+		}
+		batch = batch[:0]
+		stmts = 0
+	}
+	for {
+		select {
+		case spans, ok := <-e.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, spans...)
+			stmts++
+			if stmts >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (e *exporter) export(spans []*span) error {
+	req := &exportRequest{ResourceSpans: []*resourceSpans{{
+		Resource:   e.resource,
+		ScopeSpans: []*scopeSpans{{Scope: scope{Name: "tidb"}, Spans: spans}},
+	}}}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := e.client.Post(e.cfg.Endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// close stops the exporter after the spans in the queue are exported.
+func (e *exporter) close() {
+	e.mu.Lock()
+	e.closed = true
+	close(e.queue)
+	e.mu.Unlock()
+	e.wg.Wait()
+}
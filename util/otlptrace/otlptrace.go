@@ -0,0 +1,230 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otlptrace exports the spans of the statements to an OpenTelemetry
+// collector with the OTLP/HTTP protocol.
+//
+// The spans are collected by a basictracer for each statement, the existing
+// opentracing spans of parsing, compiling, coprocessor tasks and committing are
+// recorded as children of the statement span. A statement is exported if it's
+// sampled by the sample rate, sampled by the traceparent sent by the client, or
+// slower than the slow threshold.
+package otlptrace
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/opentracing/basictracer-go"
+	"github.com/opentracing/opentracing-go"
+)
+
+// TraceParentAttr is the name of the query attribute which carries the W3C
+// trace context of the client.
+// See https://www.w3.org/TR/trace-context/#traceparent-header
+const TraceParentAttr = "traceparent"
+
+// Config is the config of exporting the statement spans.
+type Config struct {
+	// Endpoint is the URL of the OTLP/HTTP receiver, e.g. http://127.0.0.1:4318/v1/traces.
+	Endpoint string
+	// SampleRate is the ratio of the statements to be exported.
+	SampleRate float64
+	// SlowThreshold is the duration above which the statements are always
+	// exported, 0 means it's disabled.
+	SlowThreshold time.Duration
+	// ServiceName is the service.name of the exported resource.
+	ServiceName string
+	// InstanceID is the service.instance.id of the exported resource.
+	InstanceID string
+}
+
+var (
+	exporterMu     sync.Mutex
+	globalExporter atomic.Value // *exporter
+)
+
+// Setup starts exporting the statement spans with the config. It replaces the
+// exporter set up before.
+func Setup(cfg Config) {
+	exporterMu.Lock()
+	defer exporterMu.Unlock()
+	if old := getExporter(); old != nil {
+		old.close()
+	}
+	globalExporter.Store(newExporter(cfg))
+}
+
+// Close stops exporting the statement spans, the spans in the queue are flushed.
+func Close() {
+	exporterMu.Lock()
+	defer exporterMu.Unlock()
+	if old := getExporter(); old != nil {
+		old.close()
+	}
+	globalExporter.Store((*exporter)(nil))
+}
+
+func getExporter() *exporter {
+	e, _ := globalExporter.Load().(*exporter)
+	return e
+}
+
+// Statement collects the spans of a statement.
+type Statement struct {
+	exporter *exporter
+	span     opentracing.Span
+	// sampled means the statement is exported regardless of its duration.
+	sampled bool
+	// traceID and parentID come from the traceparent of the client, they are
+	// empty if the client doesn't send a valid traceparent.
+	traceID  []byte
+	parentID []byte
+
+	mu    sync.Mutex
+	spans []basictracer.RawSpan
+}
+
+// StartStatement starts collecting the spans of a statement. It returns nil if
+// the exporting is disabled or the statement won't be exported in any case.
+func StartStatement(opName string, traceParent string) *Statement {
+	e := getExporter()
+	if e == nil {
+		return nil
+	}
+	stmt := &Statement{exporter: e}
+	var flags byte
+	stmt.traceID, stmt.parentID, flags = parseTraceParent(traceParent)
+	stmt.sampled = flags&0x01 != 0 || (e.cfg.SampleRate > 0 && rand.Float64() < e.cfg.SampleRate) // #nosec G404
+	if !stmt.sampled && e.cfg.SlowThreshold <= 0 {
+		return nil
+	}
+	tracer := basictracer.New(basictracer.SpanRecorder(stmt))
+	stmt.span = tracer.StartSpan(opName)
+	return stmt
+}
+
+// Span returns the root span of the statement.
+func (s *Statement) Span() opentracing.Span {
+	return s.span
+}
+
+// RecordSpan implements basictracer.SpanRecorder.
+func (s *Statement) RecordSpan(span basictracer.RawSpan) {
+	s.mu.Lock()
+	s.spans = append(s.spans, span)
+	s.mu.Unlock()
+}
+
+// Finish finishes the root span, and exports the spans if the statement is
+// sampled or slower than the slow threshold.
+func (s *Statement) Finish() {
+	s.span.Finish()
+	s.mu.Lock()
+	spans := s.spans
+	s.spans = nil
+	s.mu.Unlock()
+
+	if !s.sampled {
+		rootID := s.span.Context().(basictracer.SpanContext).SpanID
+		for _, sp := range spans {
+			if sp.Context.SpanID == rootID && sp.Duration < s.exporter.cfg.SlowThreshold {
+				return
+			}
+		}
+	}
+	s.exporter.enqueue(s.convert(spans))
+}
+
+// convert converts the spans to OTLP spans. The trace ID is replaced by the
+// one of the client if there is, and the root span becomes a child of the
+// client span.
+func (s *Statement) convert(spans []basictracer.RawSpan) []*span {
+	traceID := s.traceID
+	if traceID == nil {
+		traceID = make([]byte, 16)
+		// #nosec G404
+		rand.Read(traceID)
+	}
+	traceIDHex := hex.EncodeToString(traceID)
+	parentIDHex := hex.EncodeToString(s.parentID)
+	result := make([]*span, 0, len(spans))
+	for _, raw := range spans {
+		sp := &span{
+			TraceID:           traceIDHex,
+			SpanID:            encodeSpanID(raw.Context.SpanID),
+			Name:              raw.Operation,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: raw.Start.UnixNano(),
+			EndTimeUnixNano:   raw.Start.Add(raw.Duration).UnixNano(),
+			Attributes:        convertTags(raw.Tags),
+		}
+		if raw.ParentSpanID != 0 {
+			sp.ParentSpanID = encodeSpanID(raw.ParentSpanID)
+		} else {
+			sp.ParentSpanID = parentIDHex
+			sp.Kind = spanKindServer
+		}
+		for _, log := range raw.Logs {
+			ev := &event{TimeUnixNano: log.Timestamp.UnixNano(), Name: "log"}
+			for _, field := range log.Fields {
+				if field.Key() == "event" {
+					ev.Name = field.String()
+					continue
+				}
+				ev.Attributes = append(ev.Attributes, newAttribute(field.Key(), field.Value()))
+			}
+			sp.Events = append(sp.Events, ev)
+		}
+		result = append(result, sp)
+	}
+	return result
+}
+
+// parseTraceParent parses the W3C traceparent in the format of
+// {version}-{trace-id}-{parent-id}-{trace-flags}. The IDs are nil if it's
+// invalid.
+func parseTraceParent(traceParent string) (traceID, parentID []byte, flags byte) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return nil, nil, 0
+	}
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || isZero(traceID) {
+		return nil, nil, 0
+	}
+	parentID, err = hex.DecodeString(parts[2])
+	if err != nil || isZero(parentID) {
+		return nil, nil, 0
+	}
+	flagBytes, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return nil, nil, 0
+	}
+	return traceID, parentID, flagBytes[0]
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
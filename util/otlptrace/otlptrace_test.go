@@ -0,0 +1,150 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptrace
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	traceID, parentID, flags := parseTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	require.Equal(t, []byte{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c}, traceID)
+	require.Equal(t, []byte{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31}, parentID)
+	require.Equal(t, byte(1), flags)
+
+	for _, tp := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"00-0af7651916cd43dd8448eb211c80319x-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd-b7ad6b7169203331-01",
+	} {
+		traceID, parentID, flags = parseTraceParent(tp)
+		require.Nil(t, traceID, tp)
+		require.Nil(t, parentID, tp)
+		require.Equal(t, byte(0), flags, tp)
+	}
+}
+
+type collector struct {
+	*httptest.Server
+	mu    sync.Mutex
+	spans []*span
+}
+
+func newCollector(t *testing.T) *collector {
+	c := &collector{}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req exportRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		c.mu.Lock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				c.spans = append(c.spans, ss.Spans...)
+			}
+		}
+		c.mu.Unlock()
+	}))
+	return c
+}
+
+func (c *collector) getSpans() []*span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.spans
+}
+
+func runStatement(traceParent string, d time.Duration) {
+	stmt := StartStatement("server.Query", traceParent)
+	if stmt == nil {
+		return
+	}
+	child := stmt.Span().Tracer().StartSpan("session.ParseSQL", opentracing.ChildOf(stmt.Span().Context()))
+	child.SetTag("rows", 3)
+	child.Finish()
+	time.Sleep(d)
+	stmt.Finish()
+}
+
+func TestExportSampledByTraceParent(t *testing.T) {
+	c := newCollector(t)
+	defer c.Close()
+
+	require.Nil(t, StartStatement("server.Query", ""))
+	Setup(Config{Endpoint: c.URL, ServiceName: "TiDB"})
+	defer Close()
+	// Neither sampled nor slow.
+	require.Nil(t, StartStatement("server.Query", ""))
+	require.Nil(t, StartStatement("server.Query", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00"))
+
+	runStatement("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", 0)
+	Close()
+	spans := c.getSpans()
+	require.Len(t, spans, 2)
+	child, root := spans[0], spans[1]
+	require.Equal(t, "session.ParseSQL", child.Name)
+	require.Equal(t, "server.Query", root.Name)
+	require.Equal(t, "0af7651916cd43dd8448eb211c80319c", root.TraceID)
+	require.Equal(t, root.TraceID, child.TraceID)
+	require.Equal(t, "b7ad6b7169203331", root.ParentSpanID)
+	require.Equal(t, spanKindServer, root.Kind)
+	require.Equal(t, root.SpanID, child.ParentSpanID)
+	require.Equal(t, spanKindInternal, child.Kind)
+	require.Len(t, child.Attributes, 1)
+	require.Equal(t, "rows", child.Attributes[0].Key)
+	require.Equal(t, int64(3), *child.Attributes[0].Value.IntValue)
+	require.LessOrEqual(t, root.StartTimeUnixNano, child.StartTimeUnixNano)
+	require.LessOrEqual(t, child.EndTimeUnixNano, root.EndTimeUnixNano)
+}
+
+func TestExportSlowStatement(t *testing.T) {
+	c := newCollector(t)
+	defer c.Close()
+
+	Setup(Config{Endpoint: c.URL, SlowThreshold: 50 * time.Millisecond})
+	defer Close()
+	runStatement("", 0)
+	runStatement("", 100*time.Millisecond)
+	Close()
+	spans := c.getSpans()
+	require.Len(t, spans, 2)
+	require.Equal(t, "server.Query", spans[1].Name)
+	require.Len(t, spans[1].TraceID, 32)
+	require.Empty(t, spans[1].ParentSpanID)
+	require.GreaterOrEqual(t, spans[1].EndTimeUnixNano-spans[1].StartTimeUnixNano, int64(100*time.Millisecond))
+}
+
+func TestExportSampleRate(t *testing.T) {
+	c := newCollector(t)
+	defer c.Close()
+
+	Setup(Config{Endpoint: c.URL, SampleRate: 1})
+	defer Close()
+	for i := 0; i < 3; i++ {
+		runStatement("", 0)
+	}
+	Close()
+	require.Len(t, c.getSpans(), 6)
+}
@@ -127,6 +127,8 @@ const (
 	TypeCTE = "CTEFullScan"
 	// TypeCTEDefinition is the type of CTE definition
 	TypeCTEDefinition = "CTE"
+	// TypeReservoirSample is the type of ReservoirSample.
+	TypeReservoirSample = "ReservoirSample"
 )
 
 // plan id.
@@ -184,6 +186,7 @@ const (
 	typeCTE                   int = 50
 	typeCTEDefinition         int = 51
 	typeCTETable              int = 52
+	typeReservoirSample       int = 53
 )
 
 // TypeStringToPhysicalID converts the plan type string to plan id.
@@ -293,6 +296,8 @@ func TypeStringToPhysicalID(tp string) int {
 		return typeCTEDefinition
 	case TypeCTETable:
 		return typeCTETable
+	case TypeReservoirSample:
+		return typeReservoirSample
 	}
 	// Should never reach here.
 	return 0
@@ -403,6 +408,8 @@ func PhysicalIDToTypeString(id int) string {
 		return TypeCTEDefinition
 	case typeCTETable:
 		return TypeCTETable
+	case typeReservoirSample:
+		return TypeReservoirSample
 	}
 
 	// Should never reach here.
@@ -35,6 +35,7 @@ type ProcessInfo struct {
 	User             string
 	Host             string
 	Port             string
+	SessionAlias     string
 	DB               string
 	Digest           string
 	Plan             interface{}
@@ -48,6 +49,9 @@ type ProcessInfo struct {
 	// MaxExecutionTime is the timeout for select statement, in milliseconds.
 	// If the query takes too long, kill it.
 	MaxExecutionTime uint64
+	// NetBufferBytes is the memory held by the read/write buffers of the connection,
+	// it's refreshed by the SessionManager and should be accessed atomically.
+	NetBufferBytes int64
 
 	State                     uint16
 	Command                   byte
@@ -96,20 +100,38 @@ func (pi *ProcessInfo) txnStartTs(tz *time.Location) (txnStart string) {
 	return
 }
 
+// MemConsumed returns the memory consumed by the connection, including the
+// running statement and the network buffers.
+func (pi *ProcessInfo) MemConsumed() int64 {
+	bytesConsumed := atomic.LoadInt64(&pi.NetBufferBytes)
+	if pi.StmtCtx != nil && pi.StmtCtx.MemTracker != nil {
+		bytesConsumed += pi.StmtCtx.MemTracker.BytesConsumed()
+	}
+	return bytesConsumed
+}
+
+// DiskConsumed returns the disk consumed by the running statement of the connection.
+func (pi *ProcessInfo) DiskConsumed() int64 {
+	if pi.StmtCtx != nil && pi.StmtCtx.DiskTracker != nil {
+		return pi.StmtCtx.DiskTracker.BytesConsumed()
+	}
+	return 0
+}
+
 // ToRow returns []interface{} for the row data of
 // "SELECT * FROM INFORMATION_SCHEMA.PROCESSLIST".
 func (pi *ProcessInfo) ToRow(tz *time.Location) []interface{} {
-	bytesConsumed := int64(0)
-	diskConsumed := int64(0)
+	bytesConsumed := pi.MemConsumed()
+	diskConsumed := pi.DiskConsumed()
+	requestUnits := float64(0)
+	var waitState, backoffTypes string
 	if pi.StmtCtx != nil {
-		if pi.StmtCtx.MemTracker != nil {
-			bytesConsumed = pi.StmtCtx.MemTracker.BytesConsumed()
-		}
-		if pi.StmtCtx.DiskTracker != nil {
-			diskConsumed = pi.StmtCtx.DiskTracker.BytesConsumed()
-		}
+		requestUnits = pi.StmtCtx.GetRequestUnits()
+		waitState = pi.StmtCtx.GetWaitState()
+		backoffTypes = pi.StmtCtx.GetBackoffTypes()
 	}
-	return append(pi.ToRowForShow(true), pi.Digest, bytesConsumed, diskConsumed, pi.txnStartTs(tz))
+	return append(pi.ToRowForShow(true), pi.Digest, bytesConsumed, diskConsumed, pi.txnStartTs(tz),
+		requestUnits, waitState, backoffTypes, pi.SessionAlias)
 }
 
 // ascServerStatus is a slice of all defined server status in ascending order.
@@ -30,6 +30,7 @@ const (
 	gcDeleteRange         = "gc_delete_range"
 	gcDeleteRangeDone     = "gc_delete_range_done"
 	optRuleBlacklist      = "opt_rule_blacklist"
+	sqlBlocklist          = "sql_blocklist"
 	tidb                  = "tidb"
 	globalVariables       = "global_variables"
 	informationSchema     = "information_schema"
@@ -104,7 +105,7 @@ func IsInvisibleTable(dbLowerName, tblLowerName string) bool {
 	switch dbLowerName {
 	case mysql.SystemDB:
 		switch tblLowerName {
-		case exprPushdownBlacklist, gcDeleteRange, gcDeleteRangeDone, optRuleBlacklist, tidb, globalVariables:
+		case exprPushdownBlacklist, gcDeleteRange, gcDeleteRangeDone, optRuleBlacklist, sqlBlocklist, tidb, globalVariables:
 			return true
 		}
 	case informationSchema:
@@ -0,0 +1,176 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlblocklist
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/util/stringutil"
+)
+
+// Action is the action taken on the statements matching a rule.
+type Action string
+
+const (
+	// ActionBlock rejects the matching statements.
+	ActionBlock Action = "block"
+	// ActionRewrite replaces the matching statements with the replacement of the rule.
+	ActionRewrite Action = "rewrite"
+)
+
+// Rule is a rule of the SQL blocklist, which is loaded from mysql.sql_blocklist.
+// The non-empty conditions of a rule are ANDed, a rule without any condition
+// never matches.
+type Rule struct {
+	Name string
+	// Digest is the digest of the normalized statement.
+	Digest string
+	// Pattern is a LIKE pattern which is matched against the normalized statement.
+	Pattern string
+	// User is the name of the authenticated user.
+	User string
+	// Table is in the form of `db.table` or `db.*`.
+	Table       string
+	Action      Action
+	Replacement string
+	Reason      string
+
+	patChars []rune
+	patTypes []byte
+}
+
+// NewRule builds a rule and normalizes its conditions.
+func NewRule(name, digest, pattern, user, table string, action Action, replacement, reason string) *Rule {
+	r := &Rule{
+		Name:        name,
+		Digest:      strings.ToLower(strings.TrimSpace(digest)),
+		Pattern:     strings.ToLower(strings.TrimSpace(pattern)),
+		User:        user,
+		Table:       strings.ToLower(strings.TrimSpace(table)),
+		Action:      action,
+		Replacement: replacement,
+		Reason:      reason,
+	}
+	if r.Pattern != "" {
+		r.patChars, r.patTypes = stringutil.CompilePattern(r.Pattern, '\\')
+	}
+	return r
+}
+
+// StmtInfo is the information of a statement used to match the rules.
+type StmtInfo struct {
+	User          string
+	Digest        string
+	NormalizedSQL string
+	// Tables are the lower-cased `db.table` names accessed by the statement.
+	Tables []string
+}
+
+func (r *Rule) hasCondition() bool {
+	return r.Digest != "" || r.Pattern != "" || r.User != "" || r.Table != ""
+}
+
+// Match checks whether the statement matches the rule.
+func (r *Rule) Match(info *StmtInfo) bool {
+	if !r.hasCondition() {
+		return false
+	}
+	if r.Digest != "" && r.Digest != info.Digest {
+		return false
+	}
+	if r.User != "" && r.User != info.User {
+		return false
+	}
+	if r.Pattern != "" && !stringutil.DoMatch(info.NormalizedSQL, r.patChars, r.patTypes) {
+		return false
+	}
+	if r.Table != "" && !r.matchTables(info.Tables) {
+		return false
+	}
+	return true
+}
+
+func (r *Rule) matchTables(tables []string) bool {
+	db, tbl := r.Table, "*"
+	if idx := strings.IndexByte(r.Table, '.'); idx >= 0 {
+		db, tbl = r.Table[:idx], r.Table[idx+1:]
+	}
+	for _, t := range tables {
+		if tbl == "*" {
+			if strings.HasPrefix(t, db+".") {
+				return true
+			}
+		} else if t == r.Table {
+			return true
+		}
+	}
+	return false
+}
+
+var rules atomic.Value
+
+// SetRules replaces the rules in use.
+func SetRules(rs []*Rule) {
+	rules.Store(rs)
+}
+
+// GetRules returns the rules in use.
+func GetRules() []*Rule {
+	rs, _ := rules.Load().([]*Rule)
+	return rs
+}
+
+// Match returns the first rule the statement matches, or nil if there is none.
+func Match(info *StmtInfo) *Rule {
+	for _, r := range GetRules() {
+		if r.Match(info) {
+			return r
+		}
+	}
+	return nil
+}
+
+// ExtractTables returns the lower-cased `db.table` names referenced by the statement.
+// The table names without schema are qualified by currentDB.
+func ExtractTables(node ast.Node, currentDB string) []string {
+	e := &tableExtractor{currentDB: strings.ToLower(currentDB)}
+	node.Accept(e)
+	return e.tables
+}
+
+type tableExtractor struct {
+	currentDB string
+	tables    []string
+}
+
+// Enter implements the ast.Visitor interface.
+func (e *tableExtractor) Enter(in ast.Node) (ast.Node, bool) {
+	if t, ok := in.(*ast.TableName); ok {
+		db := t.Schema.L
+		if db == "" {
+			db = e.currentDB
+		}
+		e.tables = append(e.tables, db+"."+t.Name.L)
+		return in, true
+	}
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (e *tableExtractor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
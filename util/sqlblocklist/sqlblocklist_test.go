@@ -0,0 +1,72 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlblocklist
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser"
+	_ "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleMatch(t *testing.T) {
+	normalized, digest := parser.NormalizeDigest("select * from t where a = 1")
+	info := &StmtInfo{
+		User:          "u1",
+		Digest:        digest.String(),
+		NormalizedSQL: normalized,
+		Tables:        []string{"test.t"},
+	}
+
+	tests := []struct {
+		rule  *Rule
+		match bool
+	}{
+		{NewRule("r", "", "", "", "", ActionBlock, "", ""), false},
+		{NewRule("r", digest.String(), "", "", "", ActionBlock, "", ""), true},
+		{NewRule("r", "0123", "", "", "", ActionBlock, "", ""), false},
+		{NewRule("r", "", "SELECT * FROM `t` %", "", "", ActionBlock, "", ""), true},
+		{NewRule("r", "", "delete %", "", "", ActionBlock, "", ""), false},
+		{NewRule("r", "", "", "u1", "", ActionBlock, "", ""), true},
+		{NewRule("r", "", "", "u2", "", ActionBlock, "", ""), false},
+		{NewRule("r", "", "", "", "Test.T", ActionBlock, "", ""), true},
+		{NewRule("r", "", "", "", "test.*", ActionBlock, "", ""), true},
+		{NewRule("r", "", "", "", "test", ActionBlock, "", ""), true},
+		{NewRule("r", "", "", "", "test.t1", ActionBlock, "", ""), false},
+		{NewRule("r", "", "", "", "mysql.*", ActionBlock, "", ""), false},
+		{NewRule("r", "", "select %", "u1", "test.t", ActionBlock, "", ""), true},
+		{NewRule("r", "", "select %", "u2", "test.t", ActionBlock, "", ""), false},
+	}
+	for i, tt := range tests {
+		require.Equal(t, tt.match, tt.rule.Match(info), "case %d", i)
+	}
+
+	SetRules([]*Rule{tests[2].rule, tests[5].rule, tests[7].rule})
+	require.Equal(t, tests[5].rule, Match(info))
+	SetRules(nil)
+	require.Nil(t, Match(info))
+}
+
+func TestExtractTables(t *testing.T) {
+	p := parser.New()
+	stmt, err := p.ParseOneStmt("select * from t1 join db2.T2 where a in (select a from t3)", "", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"test.t1", "db2.t2", "test.t3"}, ExtractTables(stmt, "Test"))
+
+	stmt, err = p.ParseOneStmt("insert into t1 select * from mysql.user", "", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"mysql.user", "test.t1"}, ExtractTables(stmt, "test"))
+}
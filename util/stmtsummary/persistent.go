@@ -0,0 +1,369 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"bufio"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxPersistedLineSize is the max size of a persisted summary, the samples in a summary are limited by
+// tidb_stmt_summary_max_sql_length and the encoded plan size, so 16MB is enough.
+const maxPersistedLineSize = 16 * 1024 * 1024
+
+// PersistentConfig is the config of persisting the statements summary.
+type PersistentConfig struct {
+	// Filename is the file which the closed windows of the statements summary are appended to.
+	Filename string
+	// FileMaxSize is the max size in MB of the file before it's rotated.
+	FileMaxSize int
+	// FileMaxDays is the max number of days to keep the rotated files.
+	FileMaxDays int
+	// FileMaxBackups is the max number of the rotated files to keep, 0 means no limit.
+	FileMaxBackups int
+}
+
+// persistedSummary is a statement summary of a closed window, which is encoded as a JSON line in the file.
+type persistedSummary struct {
+	BeginTime int64    `json:"begin_time"`
+	EndTime   int64    `json:"end_time"`
+	Users     []string `json:"users,omitempty"`
+	Internal  bool     `json:"internal,omitempty"`
+	// Columns are the values of the columns of the statements_summary_history table, NULL values are omitted.
+	Columns map[string]string `json:"columns"`
+}
+
+// stmtSummaryPersister appends the closed windows of the statements summary to the rotated files.
+type stmtSummaryPersister struct {
+	filename  string
+	output    zapcore.WriteSyncer
+	startTime int64
+	wg        sync.WaitGroup
+}
+
+// SetupPersistent starts persisting the closed windows of the statements summary, so that
+// statements_summary_history and ADMIN SHOW SLOW can read them after the server restarts.
+func (ssMap *stmtSummaryByDigestMap) SetupPersistent(cfg *PersistentConfig) error {
+	_, props, err := log.InitLogger(&log.Config{
+		Level: "info",
+		File: log.FileLogConfig{
+			Filename:   cfg.Filename,
+			MaxSize:    cfg.FileMaxSize,
+			MaxDays:    cfg.FileMaxDays,
+			MaxBackups: cfg.FileMaxBackups,
+		},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ssMap.Lock()
+	defer ssMap.Unlock()
+	ssMap.persister = &stmtSummaryPersister{
+		filename:  cfg.Filename,
+		output:    props.Syncer,
+		startTime: time.Now().Unix(),
+	}
+	return nil
+}
+
+// ClosePersistent persists the current window and stops persisting the statements summary.
+func (ssMap *stmtSummaryByDigestMap) ClosePersistent() {
+	ssMap.Lock()
+	persister := ssMap.persister
+	ssMap.persister = nil
+	values := ssMap.summaryMap.Values()
+	beginTime := ssMap.beginTimeForCurInterval
+	ssMap.Unlock()
+	if persister == nil {
+		return
+	}
+	if beginTime != 0 {
+		persister.persistWindow(values, ssMap.other, beginTime)
+	}
+	persister.wg.Wait()
+	if err := persister.output.Sync(); err != nil {
+		logutil.BgLogger().Warn("sync the persisted statements summary failed", zap.Error(err))
+	}
+}
+
+// onWindowClosed persists the window beginning at beginTime, which must be called with ssMap locked.
+func (ssMap *stmtSummaryByDigestMap) onWindowClosed(beginTime int64) {
+	persister := ssMap.persister
+	if persister == nil || beginTime == 0 {
+		return
+	}
+	values := ssMap.summaryMap.Values()
+	other := ssMap.other
+	persister.wg.Add(1)
+	go func() {
+		defer persister.wg.Done()
+		persister.persistWindow(values, other, beginTime)
+	}()
+}
+
+func (p *stmtSummaryPersister) persistWindow(values []kvcache.Value, other *stmtSummaryByDigestEvicted, beginTime int64) {
+	var buf []byte
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		if ssElement := ssbd.windowElement(beginTime); ssElement != nil {
+			buf = appendPersistedSummary(buf, ssElement, ssbd)
+		}
+	}
+	other.Lock()
+	for element := other.history.Front(); element != nil; element = element.Next() {
+		if seElement := element.Value.(*stmtSummaryByDigestEvictedElement); seElement.beginTime == beginTime {
+			buf = appendPersistedSummary(buf, seElement.otherSummary, new(stmtSummaryByDigest))
+		}
+	}
+	other.Unlock()
+	if len(buf) == 0 {
+		return
+	}
+	if _, err := p.output.Write(buf); err != nil {
+		logutil.BgLogger().Warn("persist the statements summary failed", zap.Int64("beginTime", beginTime), zap.Error(err))
+	}
+}
+
+// windowElement returns the summary in the window beginning at beginTime.
+func (ssbd *stmtSummaryByDigest) windowElement(beginTime int64) *stmtSummaryByDigestElement {
+	ssbd.Lock()
+	defer ssbd.Unlock()
+	if !ssbd.initialized {
+		return nil
+	}
+	for element := ssbd.history.Back(); element != nil; element = element.Prev() {
+		ssElement := element.Value.(*stmtSummaryByDigestElement)
+		if ssElement.beginTime == beginTime {
+			return ssElement
+		}
+		if ssElement.beginTime < beginTime {
+			break
+		}
+	}
+	return nil
+}
+
+// oldestBeginTime returns the begin time of the oldest window in memory, or math.MaxInt64 if there's none.
+func (ssbd *stmtSummaryByDigest) oldestBeginTime() int64 {
+	ssbd.Lock()
+	defer ssbd.Unlock()
+	if !ssbd.initialized || ssbd.history.Len() == 0 {
+		return math.MaxInt64
+	}
+	return ssbd.history.Front().Value.(*stmtSummaryByDigestElement).beginTime
+}
+
+func appendPersistedSummary(buf []byte, ssElement *stmtSummaryByDigestElement, ssbd *stmtSummaryByDigest) []byte {
+	ssElement.Lock()
+	summary := persistedSummary{
+		BeginTime: ssElement.beginTime,
+		EndTime:   ssElement.endTime,
+		Internal:  ssbd.isInternal,
+		Columns:   make(map[string]string, len(columnValueFactoryMap)),
+	}
+	for user := range ssElement.authUsers {
+		summary.Users = append(summary.Users, user)
+	}
+	for name, factory := range columnValueFactoryMap {
+		d := types.NewDatum(factory(ssElement, ssbd))
+		if d.IsNull() {
+			continue
+		}
+		s, err := d.ToString()
+		if err != nil {
+			continue
+		}
+		summary.Columns[name] = s
+	}
+	ssElement.Unlock()
+	sort.Strings(summary.Users)
+	line, err := json.Marshal(&summary)
+	if err != nil {
+		logutil.BgLogger().Warn("encode the persisted statements summary failed", zap.String("digest", ssbd.digest), zap.Error(err))
+		return buf
+	}
+	buf = append(buf, line...)
+	return append(buf, '\n')
+}
+
+// persistedFiles returns the persisted file and its rotated backups.
+func (p *stmtSummaryPersister) persistedFiles() []string {
+	ext := filepath.Ext(p.filename)
+	prefix := strings.TrimSuffix(p.filename, ext)
+	files, err := filepath.Glob(prefix + "-*" + ext)
+	if err != nil {
+		logutil.BgLogger().Warn("list the persisted statements summary files failed", zap.Error(err))
+	}
+	sort.Strings(files)
+	return append(files, p.filename)
+}
+
+// readPersistedSummaries reads the summaries whose windows begin before beforeTime, or all of them if beforeTime is 0.
+func (p *stmtSummaryPersister) readPersistedSummaries(beforeTime int64) ([]*persistedSummary, error) {
+	var summaries []*persistedSummary
+	for _, file := range p.persistedFiles() {
+		f, err := os.Open(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Trace(err)
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, maxPersistedLineSize)
+		for scanner.Scan() {
+			summary := new(persistedSummary)
+			if err := json.Unmarshal(scanner.Bytes(), summary); err != nil {
+				// The last line may be partially written when the server crashes, skip it.
+				logutil.BgLogger().Warn("decode the persisted statements summary failed", zap.String("file", file), zap.Error(err))
+				continue
+			}
+			if beforeTime == 0 || summary.BeginTime < beforeTime {
+				summaries = append(summaries, summary)
+			}
+		}
+		err = scanner.Err()
+		closeErr := f.Close()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if closeErr != nil {
+			return nil, errors.Trace(closeErr)
+		}
+	}
+	return summaries, nil
+}
+
+// getPersistedRows converts the persisted summaries to the rows of the statements_summary_history table.
+func (ssr *stmtSummaryReader) getPersistedRows(summaries []*persistedSummary) [][]types.Datum {
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	rows := make([][]types.Datum, 0, len(summaries))
+	for _, summary := range summaries {
+		if ssr.checker != nil && !ssr.checker.isDigestValid(summary.Columns[DigestStr]) {
+			continue
+		}
+		if ssr.user != nil && !ssr.hasProcessPriv {
+			idx := sort.SearchStrings(summary.Users, ssr.user.Username)
+			if idx == len(summary.Users) || summary.Users[idx] != ssr.user.Username {
+				continue
+			}
+		}
+		row := make([]types.Datum, len(ssr.columns))
+		for i, col := range ssr.columns {
+			if col.Name.O == util.ClusterTableInstanceColumnName {
+				row[i] = types.NewDatum(ssr.instanceAddr)
+				continue
+			}
+			if s, ok := summary.Columns[col.Name.O]; ok {
+				row[i] = persistedColumnValue(sc, col, s)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func persistedColumnValue(sc *stmtctx.StatementContext, col *model.ColumnInfo, s string) types.Datum {
+	var (
+		d   types.Datum
+		err error
+	)
+	switch col.Tp {
+	case mysql.TypeTimestamp, mysql.TypeDatetime:
+		// The times are persisted in the local time zone as they're shown, so they're not converted.
+		var t types.Time
+		t, err = types.ParseTime(sc, s, col.Tp, col.Decimal)
+		d.SetMysqlTime(t)
+	default:
+		d = types.NewStringDatum(s)
+		d, err = d.ConvertTo(sc, &col.FieldType)
+	}
+	if err != nil {
+		logutil.BgLogger().Warn("decode the persisted statements summary column failed", zap.String("column", col.Name.O), zap.Error(err))
+		return types.Datum{}
+	}
+	return d
+}
+
+// SlowStmt is the slowest execution of a kind of statements in a window of the persisted statements summary.
+type SlowStmt struct {
+	SQL        string
+	LastSeen   time.Time
+	MaxLatency time.Duration
+	User       string
+	DB         string
+	IndexNames string
+	Digest     string
+	Internal   bool
+	Succ       bool
+}
+
+// PersistedSlowStmts returns the statements whose max latency exceeds threshold in the windows persisted before this
+// server started, which aren't covered by the slow queries in memory. It returns nil if persisting is disabled.
+func (ssMap *stmtSummaryByDigestMap) PersistedSlowStmts(threshold time.Duration) ([]*SlowStmt, error) {
+	ssMap.Lock()
+	persister := ssMap.persister
+	ssMap.Unlock()
+	if persister == nil {
+		return nil, nil
+	}
+	summaries, err := persister.readPersistedSummaries(persister.startTime)
+	if err != nil {
+		return nil, err
+	}
+	slowStmts := make([]*SlowStmt, 0, len(summaries))
+	for _, summary := range summaries {
+		maxLatency, err := strconv.ParseInt(summary.Columns[MaxLatencyStr], 10, 64)
+		if err != nil || time.Duration(maxLatency) < threshold {
+			continue
+		}
+		lastSeen, err := time.ParseInLocation(types.TimeFormat, summary.Columns[LastSeenStr], time.Local)
+		if err != nil {
+			lastSeen = time.Unix(summary.EndTime, 0)
+		}
+		slowStmts = append(slowStmts, &SlowStmt{
+			SQL:        summary.Columns[QuerySampleTextStr],
+			LastSeen:   lastSeen,
+			MaxLatency: time.Duration(maxLatency),
+			User:       summary.Columns[SampleUserStr],
+			DB:         summary.Columns[SchemaNameStr],
+			IndexNames: summary.Columns[IndexNamesStr],
+			Digest:     summary.Columns[DigestStr],
+			Internal:   summary.Internal,
+			Succ:       summary.Columns[SumErrorsStr] == "0",
+		})
+	}
+	return slowStmts, nil
+}
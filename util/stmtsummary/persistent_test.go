@@ -0,0 +1,90 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistent(t *testing.T) {
+	ssMap := newStmtSummaryByDigestMap()
+	filename := filepath.Join(t.TempDir(), "tidb-statements.log")
+	err := ssMap.SetupPersistent(&PersistentConfig{Filename: filename, FileMaxSize: 1, FileMaxDays: 1})
+	require.NoError(t, err)
+
+	now := time.Now().Unix()
+	ssMap.beginTimeForCurInterval = now - 100
+	stmtExecInfo := generateAnyExecInfo()
+	ssMap.AddStatement(stmtExecInfo)
+
+	// Close the window and wait for it to be persisted.
+	ssMap.Lock()
+	ssMap.onWindowClosed(now - 100)
+	ssMap.Unlock()
+	ssMap.persister.wg.Wait()
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Contains(t, string(content), stmtExecInfo.Digest)
+
+	// The persisted window is read after it's removed from memory.
+	ssMap.Clear()
+	reader := newStmtSummaryReaderForTest(ssMap)
+	for _, col := range reader.columns {
+		col.FieldType = *types.NewFieldType(mysql.TypeVarchar)
+	}
+	reader.columns[9].FieldType = *types.NewFieldType(mysql.TypeLonglong)
+	datums := reader.GetStmtSummaryHistoryRows()
+	require.Len(t, datums, 1)
+	require.Equal(t, stmtExecInfo.Digest, datums[0][4].GetString())
+	require.Equal(t, int64(1), datums[0][9].GetInt64())
+
+	// The persisted window is skipped if it's still in memory.
+	ssMap.beginTimeForCurInterval = now - 100
+	ssMap.AddStatement(stmtExecInfo)
+	datums = reader.GetStmtSummaryHistoryRows()
+	require.Len(t, datums, 1)
+	ssMap.Clear()
+
+	// Users without the PROCESS privilege can only read their own statements.
+	reader.user = &auth.UserIdentity{Username: "bad_user"}
+	reader.hasProcessPriv = false
+	require.Len(t, reader.GetStmtSummaryHistoryRows(), 0)
+	reader.user = &auth.UserIdentity{Username: stmtExecInfo.User}
+	require.Len(t, reader.GetStmtSummaryHistoryRows(), 1)
+
+	slowStmts, err := ssMap.PersistedSlowStmts(0)
+	require.NoError(t, err)
+	require.Len(t, slowStmts, 1)
+	require.Equal(t, stmtExecInfo.Digest, slowStmts[0].Digest)
+	require.Equal(t, stmtExecInfo.OriginalSQL, slowStmts[0].SQL)
+	require.Equal(t, stmtExecInfo.TotalLatency, slowStmts[0].MaxLatency)
+	require.True(t, slowStmts[0].Succ)
+	slowStmts, err = ssMap.PersistedSlowStmts(time.Hour)
+	require.NoError(t, err)
+	require.Len(t, slowStmts, 0)
+
+	ssMap.ClosePersistent()
+	slowStmts, err = ssMap.PersistedSlowStmts(0)
+	require.NoError(t, err)
+	require.Nil(t, slowStmts)
+}
@@ -16,6 +16,7 @@ package stmtsummary
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -97,18 +98,24 @@ func (ssr *stmtSummaryReader) GetStmtSummaryCurrentRows() [][]types.Datum {
 	return rows
 }
 
-// GetStmtSummaryHistoryRows gets all history statement summaries rows.
+// GetStmtSummaryHistoryRows gets all history statement summaries rows, including the persisted ones.
 func (ssr *stmtSummaryReader) GetStmtSummaryHistoryRows() [][]types.Datum {
 	ssMap := ssr.ssMap
 	ssMap.Lock()
 	values := ssMap.summaryMap.Values()
 	other := ssMap.other
+	persister := ssMap.persister
 	ssMap.Unlock()
 
 	historySize := ssMap.historySize()
 	rows := make([][]types.Datum, 0, len(values)*historySize)
+	// The persisted windows which begin before the ones in memory are appended.
+	oldestBeginTime := int64(math.MaxInt64)
 	for _, value := range values {
 		ssbd := value.(*stmtSummaryByDigest)
+		if beginTime := ssbd.oldestBeginTime(); beginTime < oldestBeginTime {
+			oldestBeginTime = beginTime
+		}
 		if ssr.checker != nil && !ssr.checker.isDigestValid(ssbd.digest) {
 			continue
 		}
@@ -120,6 +127,14 @@ func (ssr *stmtSummaryReader) GetStmtSummaryHistoryRows() [][]types.Datum {
 		otherDatum := ssr.getStmtEvictedOtherHistoryRow(other, historySize)
 		rows = append(rows, otherDatum...)
 	}
+
+	if persister != nil {
+		summaries, err := persister.readPersistedSummaries(oldestBeginTime)
+		if err != nil {
+			logutil.BgLogger().Warn("read the persisted statements summary failed", zap.Error(err))
+		}
+		rows = append(rows, ssr.getPersistedRows(summaries)...)
+	}
 	return rows
 }
 
@@ -313,6 +328,7 @@ const (
 	PrevSampleTextStr               = "PREV_SAMPLE_TEXT"
 	PlanDigestStr                   = "PLAN_DIGEST"
 	PlanStr                         = "PLAN"
+	QueryAttributesStr              = "QUERY_ATTRIBUTES"
 )
 
 type columnValueFactory func(ssElement *stmtSummaryByDigestElement, ssbd *stmtSummaryByDigest) interface{}
@@ -601,4 +617,7 @@ var columnValueFactoryMap = map[string]columnValueFactory{
 		}
 		return plan
 	},
+	QueryAttributesStr: func(ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) interface{} {
+		return ssElement.queryAttributes
+	},
 }
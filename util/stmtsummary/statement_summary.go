@@ -81,6 +81,9 @@ type stmtSummaryByDigestMap struct {
 
 	// other stores summary of evicted data.
 	other *stmtSummaryByDigestEvicted
+
+	// persister persists the closed windows, it's nil if persisting is disabled.
+	persister *stmtSummaryPersister
 }
 
 // StmtSummaryByDigestMap is a global map containing all statement summaries.
@@ -204,6 +207,8 @@ type stmtSummaryByDigestElement struct {
 	planInCache   bool
 	planCacheHits int64
 	planInBinding bool
+	// queryAttributes are the query attributes of the last statement.
+	queryAttributes string
 	// pessimistic execution retry information.
 	execRetryCount uint
 	execRetryTime  time.Duration
@@ -242,6 +247,8 @@ type StmtExecInfo struct {
 	ResultRows      int64
 	TiKVExecDetails util.ExecDetails
 	Prepared        bool
+	// QueryAttributes is the JSON encoded query attributes sent by the client.
+	QueryAttributes string
 }
 
 // newStmtSummaryByDigestMap creates an empty stmtSummaryByDigestMap.
@@ -316,6 +323,7 @@ func (ssMap *stmtSummaryByDigestMap) AddStatement(sei *StmtExecInfo) {
 		}
 
 		if ssMap.beginTimeForCurInterval+intervalSeconds <= now {
+			ssMap.onWindowClosed(ssMap.beginTimeForCurInterval)
 			// `beginTimeForCurInterval` is a multiple of intervalSeconds, so that when the interval is a multiple
 			// of 60 (or 600, 1800, 3600, etc), begin time shows 'XX:XX:00', not 'XX:XX:01'~'XX:XX:59'.
 			ssMap.beginTimeForCurInterval = now / intervalSeconds * intervalSeconds
@@ -801,6 +809,8 @@ func (ssElement *stmtSummaryByDigestElement) add(sei *StmtExecInfo, intervalSeco
 		ssElement.planInBinding = false
 	}
 
+	ssElement.queryAttributes = sei.QueryAttributes
+
 	// other
 	ssElement.sumAffectedRows += sei.StmtCtx.AffectedRows()
 	ssElement.sumMem += sei.MemMax
@@ -52,6 +52,8 @@ type locCache struct {
 	sync.RWMutex
 	// locMap stores locations used in past and can be retrieved by a timezone's name.
 	locMap map[string]*time.Location
+	// sysLocMap stores locations loaded from the system tables, the keys are lower-cased names.
+	sysLocMap map[string]*time.Location
 }
 
 // inferOneStepLinkForPath only read one step link for the path, not like filepath.EvalSymlinks, which gets the
@@ -152,7 +154,7 @@ func GetSystemTZ() (string, error) {
 	return systemTZ, nil
 }
 
-// getLoc first trying to load location from a cache map. If nothing found in such map, then call
+// getLoc first trying to load location from the system tables and a cache map. If nothing found in such maps, then call
 // `time.LoadLocation` to get a timezone location. After trying both way, an error will be returned
 //  if valid Location is not found.
 func (lm *locCache) getLoc(name string) (*time.Location, error) {
@@ -160,7 +162,10 @@ func (lm *locCache) getLoc(name string) (*time.Location, error) {
 		return time.Local, nil
 	}
 	lm.RLock()
-	v, ok := lm.locMap[name]
+	v, ok := lm.sysLocMap[strings.ToLower(name)]
+	if !ok {
+		v, ok = lm.locMap[name]
+	}
 	lm.RUnlock()
 	if ok {
 		return v, nil
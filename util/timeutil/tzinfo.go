@@ -0,0 +1,109 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	// Embed a copy of the IANA time zone database, it is used by `time.LoadLocation`
+	// when the zoneinfo of the OS is not available.
+	_ "time/tzdata"
+)
+
+// TransitionType is a local time type of a time zone, it is loaded from mysql.time_zone_transition_type.
+type TransitionType struct {
+	// Offset is the offset from UTC in seconds.
+	Offset       int32
+	IsDST        bool
+	Abbreviation string
+}
+
+// Transition is a transition of a time zone, it is loaded from mysql.time_zone_transition.
+type Transition struct {
+	// Time is the UTC unix timestamp of the transition.
+	Time int64
+	// TypeIdx is the index of the local time type after the transition.
+	TypeIdx int
+}
+
+// NewLocationFromTransitions builds a time.Location by the local time types and the transitions of a time zone.
+// The time before the first transition uses the first local time type.
+func NewLocationFromTransitions(name string, types []TransitionType, transitions []Transition) (*time.Location, error) {
+	if len(types) == 0 || len(types) > 256 {
+		return nil, fmt.Errorf("invalid number of local time types %d for timezone %s", len(types), name)
+	}
+	transitions = append([]Transition(nil), transitions...)
+	sort.Slice(transitions, func(i, j int) bool { return transitions[i].Time < transitions[j].Time })
+	for _, t := range transitions {
+		if t.TypeIdx < 0 || t.TypeIdx >= len(types) {
+			return nil, fmt.Errorf("invalid local time type %d for timezone %s", t.TypeIdx, name)
+		}
+	}
+
+	// Build the data in TZif format, see RFC 8536. The version 1 data block is left empty,
+	// and the version 2 data block uses 64-bit transition times.
+	var abbrevs bytes.Buffer
+	abbrevIdx := make([]int, len(types))
+	for i, tp := range types {
+		abbrevIdx[i] = abbrevs.Len()
+		abbrevs.WriteString(tp.Abbreviation)
+		abbrevs.WriteByte(0)
+	}
+	var buf bytes.Buffer
+	writeHeader := func(timeCnt, typeCnt, charCnt int) {
+		buf.WriteString("TZif2")
+		buf.Write(make([]byte, 15))
+		for _, n := range []int{0, 0, 0, timeCnt, typeCnt, charCnt} {
+			_ = binary.Write(&buf, binary.BigEndian, uint32(n))
+		}
+	}
+	writeHeader(0, 0, 0)
+	writeHeader(len(transitions), len(types), abbrevs.Len())
+	for _, t := range transitions {
+		_ = binary.Write(&buf, binary.BigEndian, t.Time)
+	}
+	for _, t := range transitions {
+		buf.WriteByte(byte(t.TypeIdx))
+	}
+	for i, tp := range types {
+		_ = binary.Write(&buf, binary.BigEndian, tp.Offset)
+		if tp.IsDST {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+		buf.WriteByte(byte(abbrevIdx[i]))
+	}
+	buf.Write(abbrevs.Bytes())
+	return time.LoadLocationFromTZData(name, buf.Bytes())
+}
+
+// SetSysTableLocations replaces the time zones loaded from the system tables mysql.time_zone*.
+// They take precedence over the time zones of the OS and the embedded time zone database,
+// and their names are case-insensitive.
+func SetSysTableLocations(locs map[string]*time.Location) {
+	sysLocs := make(map[string]*time.Location, len(locs))
+	for name, loc := range locs {
+		sysLocs[strings.ToLower(name)] = loc
+	}
+	locCa.Lock()
+	locCa.sysLocMap = sysLocs
+	locCa.Unlock()
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLocationFromTransitions(t *testing.T) {
+	types := []TransitionType{
+		{Offset: 3600, Abbreviation: "TST"},
+		{Offset: 7200, IsDST: true, Abbreviation: "TDT"},
+	}
+	transitions := []Transition{
+		{Time: time.Date(2030, 10, 1, 0, 0, 0, 0, time.UTC).Unix(), TypeIdx: 0},
+		{Time: time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC).Unix(), TypeIdx: 1},
+	}
+	loc, err := NewLocationFromTransitions("Test/Zone", types, transitions)
+	require.NoError(t, err)
+	require.Equal(t, "Test/Zone", loc.String())
+
+	cases := []struct {
+		t      time.Time
+		name   string
+		offset int
+	}{
+		{time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), "TST", 3600},
+		{time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC), "TDT", 7200},
+		{time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC), "TST", 3600},
+	}
+	for _, c := range cases {
+		name, offset := c.t.In(loc).Zone()
+		require.Equal(t, c.name, name)
+		require.Equal(t, c.offset, offset)
+	}
+
+	_, err = NewLocationFromTransitions("Test/Zone", nil, nil)
+	require.Error(t, err)
+	_, err = NewLocationFromTransitions("Test/Zone", types, []Transition{{Time: 0, TypeIdx: 2}})
+	require.Error(t, err)
+
+	// The time zones from the system tables take precedence, and their names are case-insensitive.
+	SetSysTableLocations(map[string]*time.Location{"Test/Zone": loc})
+	defer SetSysTableLocations(nil)
+	for _, name := range []string{"Test/Zone", "test/zone"} {
+		l, err := LoadLocation(name)
+		require.NoError(t, err)
+		require.Same(t, loc, l)
+	}
+	SetSysTableLocations(nil)
+	_, err = LoadLocation("Test/Zone")
+	require.Error(t, err)
+}
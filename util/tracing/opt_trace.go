@@ -44,6 +44,14 @@ func (tracer *LogicalOptimizeTracer) AppendRuleTracerBeforeRuleOptimize(index in
 	tracer.curRuleTracer = ruleTracer
 }
 
+// RecordRuleTracerAfterRuleOptimize records the plan tracer after the current rule optimize
+func (tracer *LogicalOptimizeTracer) RecordRuleTracerAfterRuleOptimize(after *PlanTrace) {
+	if tracer.curRuleTracer == nil {
+		return
+	}
+	tracer.curRuleTracer.After = toFlattenPlanTrace(after)
+}
+
 // AppendRuleTracerStepToCurrent add rule optimize step to current
 func (tracer *LogicalOptimizeTracer) AppendRuleTracerStepToCurrent(id int, tp, reason, action string) {
 	index := len(tracer.curRuleTracer.Steps)
@@ -78,6 +86,7 @@ func (tracer *LogicalOptimizeTracer) removeUselessStep() {
 type LogicalRuleOptimizeTracer struct {
 	Index    int                            `json:"index"`
 	Before   []*PlanTrace                   `json:"before"`
+	After    []*PlanTrace                   `json:"after"`
 	RuleName string                         `json:"name"`
 	Steps    []LogicalRuleOptimizeTraceStep `json:"steps"`
 }
@@ -141,6 +150,11 @@ type CETraceRecord struct {
 	Type      string `json:"type"`
 	Expr      string `json:"expr"`
 	RowCount  uint64 `json:"row_count"`
+	// Selectivity is the estimated row count divided by the row count of the table.
+	Selectivity float64 `json:"selectivity"`
+	// Detail describes which statistics are used for the estimation, e.g. the hit TopN entries,
+	// the histogram buckets, or the reason of falling back to the pseudo estimation.
+	Detail string `json:"detail,omitempty"`
 }
 
 // DedupCETrace deduplicate a slice of *CETraceRecord and return the deduplicated slice
@@ -162,6 +176,8 @@ type PhysicalOptimizeTracer struct {
 	Final               []*PlanTrace          `json:"final"`
 	SelectedCandidates  []*CandidatePlanTrace `json:"selected_candidates"`
 	DiscardedCandidates []*CandidatePlanTrace `json:"discarded_candidates"`
+	// CostComparisons indicates the cost comparisons between the candidates in order
+	CostComparisons []*CostComparisonTrace `json:"cost_comparisons"`
 	// (logical plan) -> physical plan codename -> physical plan
 	State map[string]map[string]*PlanTrace `json:"-"`
 }
@@ -172,6 +188,39 @@ func (tracer *PhysicalOptimizeTracer) RecordFinalPlanTrace(root *PlanTrace) {
 	tracer.buildCandidatesInfo()
 }
 
+// CostComparisonTrace indicates a cost comparison between a candidate physical plan and
+// the best physical plan found so far for the same logical plan and property
+type CostComparisonTrace struct {
+	Index              int          `json:"index"`
+	MappingLogicalPlan string       `json:"mapping"`
+	ProperType         string       `json:"property"`
+	Candidate          []*PlanTrace `json:"candidate"`
+	CandidateCost      float64      `json:"candidate_cost"`
+	Best               []*PlanTrace `json:"best"`
+	BestCost           float64      `json:"best_cost"`
+	// Replaced indicates whether the candidate replaces the best one
+	Replaced bool `json:"replaced"`
+}
+
+// AppendCostComparison records the cost comparison between the candidate and the best plan,
+// the best plan is nil if no valid plan has been found yet.
+func (tracer *PhysicalOptimizeTracer) AppendCostComparison(logicalPlanKey, prop string, candidate *PlanTrace, candidateCost float64,
+	best *PlanTrace, bestCost float64, replaced bool) {
+	c := &CostComparisonTrace{
+		Index:              len(tracer.CostComparisons),
+		MappingLogicalPlan: logicalPlanKey,
+		ProperType:         prop,
+		Candidate:          toFlattenPlanTrace(candidate),
+		CandidateCost:      candidateCost,
+		BestCost:           bestCost,
+		Replaced:           replaced,
+	}
+	if best != nil {
+		c.Best = toFlattenPlanTrace(best)
+	}
+	tracer.CostComparisons = append(tracer.CostComparisons, c)
+}
+
 // CandidatePlanTrace indicates info for candidate
 type CandidatePlanTrace struct {
 	*PlanTrace
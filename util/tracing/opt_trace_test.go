@@ -103,3 +103,26 @@ func TestFlattenLogicalPlanTrace(t *testing.T) {
 	require.EqualValues(t, toFlattenPlanTrace(root1), expect1)
 	require.EqualValues(t, toFlattenPlanTrace(root2), expect2)
 }
+
+func TestRuleAndCostComparisonTrace(t *testing.T) {
+	before := &PlanTrace{ID: 1, TP: "foo1", Children: []*PlanTrace{{ID: 2, TP: "foo2"}}}
+	after := &PlanTrace{ID: 2, TP: "foo2"}
+	logical := &LogicalOptimizeTracer{Steps: make([]*LogicalRuleOptimizeTracer, 0)}
+	logical.AppendRuleTracerBeforeRuleOptimize(0, "rule", before)
+	logical.AppendRuleTracerStepToCurrent(1, "foo1", "reason", "action")
+	logical.RecordRuleTracerAfterRuleOptimize(after)
+	require.Len(t, logical.Steps, 1)
+	require.Len(t, logical.Steps[0].Before, 2)
+	require.Len(t, logical.Steps[0].After, 1)
+	require.Equal(t, 2, logical.Steps[0].After[0].ID)
+
+	physical := &PhysicalOptimizeTracer{State: make(map[string]map[string]*PlanTrace)}
+	physical.AppendCostComparison("foo_1", "prop", &PlanTrace{ID: 3, TP: "bar"}, 10, nil, 0, true)
+	physical.AppendCostComparison("foo_1", "prop", &PlanTrace{ID: 4, TP: "bar"}, 20, &PlanTrace{ID: 3, TP: "bar"}, 10, false)
+	require.Len(t, physical.CostComparisons, 2)
+	require.Nil(t, physical.CostComparisons[0].Best)
+	require.True(t, physical.CostComparisons[0].Replaced)
+	require.Equal(t, 1, physical.CostComparisons[1].Index)
+	require.Equal(t, 3, physical.CostComparisons[1].Best[0].ID)
+	require.False(t, physical.CostComparisons[1].Replaced)
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writeconflicthistory
+
+import (
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+const (
+	// ColIDStr is the name of the ID column in INFORMATION_SCHEMA.SESSION_WRITE_CONFLICTS table.
+	ColIDStr = "ID"
+	// ColOccurTimeStr is the name of the OCCUR_TIME column in INFORMATION_SCHEMA.SESSION_WRITE_CONFLICTS table.
+	ColOccurTimeStr = "OCCUR_TIME"
+	// ColStartTSStr is the name of the START_TS column in INFORMATION_SCHEMA.SESSION_WRITE_CONFLICTS table.
+	ColStartTSStr = "START_TS"
+	// ColConflictStartTSStr is the name of the CONFLICT_START_TS column in INFORMATION_SCHEMA.SESSION_WRITE_CONFLICTS table.
+	ColConflictStartTSStr = "CONFLICT_START_TS"
+	// ColConflictCommitTSStr is the name of the CONFLICT_COMMIT_TS column in INFORMATION_SCHEMA.SESSION_WRITE_CONFLICTS table.
+	ColConflictCommitTSStr = "CONFLICT_COMMIT_TS"
+	// ColKeyStr is the name of the KEY column in INFORMATION_SCHEMA.SESSION_WRITE_CONFLICTS table.
+	ColKeyStr = "KEY"
+	// ColKeyInfoStr is the name of the KEY_INFO column in INFORMATION_SCHEMA.SESSION_WRITE_CONFLICTS table.
+	ColKeyInfoStr = "KEY_INFO"
+	// ColPrimaryKeyStr is the name of the PRIMARY_KEY column in INFORMATION_SCHEMA.SESSION_WRITE_CONFLICTS table.
+	ColPrimaryKeyStr = "PRIMARY_KEY"
+	// ColAutoRetryStr is the name of the AUTO_RETRY column in INFORMATION_SCHEMA.SESSION_WRITE_CONFLICTS table.
+	ColAutoRetryStr = "AUTO_RETRY"
+)
+
+// DefaultCapacity is the number of write conflicts kept for each session.
+const DefaultCapacity = 32
+
+// WriteConflictRecord represents a write conflict met by an optimistic transaction when committing.
+type WriteConflictRecord struct {
+	// The ID doesn't need to be set manually and it's set when it's added into the WriteConflictHistory by invoking
+	// its Push method.
+	ID               uint64
+	OccurTime        time.Time
+	StartTS          uint64
+	ConflictStartTS  uint64
+	ConflictCommitTS uint64
+	Key              []byte
+	Primary          []byte
+	// AutoRetry indicates whether the conflict is met when TiDB retries the transaction automatically.
+	AutoRetry bool
+}
+
+func hexKey(key []byte) types.Datum {
+	if len(key) == 0 {
+		return types.NewDatum(nil)
+	}
+	return types.NewDatum(strings.ToUpper(hex.EncodeToString(key)))
+}
+
+var columnValueGetterMap = map[string]func(rec *WriteConflictRecord) types.Datum{
+	ColIDStr: func(rec *WriteConflictRecord) types.Datum {
+		return types.NewDatum(rec.ID)
+	},
+	ColOccurTimeStr: func(rec *WriteConflictRecord) types.Datum {
+		return types.NewDatum(types.NewTime(types.FromGoTime(rec.OccurTime), mysql.TypeTimestamp, types.MaxFsp))
+	},
+	ColStartTSStr: func(rec *WriteConflictRecord) types.Datum {
+		return types.NewDatum(rec.StartTS)
+	},
+	ColConflictStartTSStr: func(rec *WriteConflictRecord) types.Datum {
+		return types.NewDatum(rec.ConflictStartTS)
+	},
+	ColConflictCommitTSStr: func(rec *WriteConflictRecord) types.Datum {
+		return types.NewDatum(rec.ConflictCommitTS)
+	},
+	ColKeyStr: func(rec *WriteConflictRecord) types.Datum {
+		return hexKey(rec.Key)
+	},
+	ColPrimaryKeyStr: func(rec *WriteConflictRecord) types.Datum {
+		return hexKey(rec.Primary)
+	},
+	ColAutoRetryStr: func(rec *WriteConflictRecord) types.Datum {
+		return types.NewDatum(rec.AutoRetry)
+	},
+}
+
+// ToDatum creates the datum for the specified column of `INFORMATION_SCHEMA.SESSION_WRITE_CONFLICTS` table.
+func (r *WriteConflictRecord) ToDatum(columnName string) types.Datum {
+	res, ok := columnValueGetterMap[columnName]
+	if !ok {
+		return types.NewDatum(nil)
+	}
+	return res(r)
+}
+
+// WriteConflictHistory keeps the recent write conflicts met by a session. All its public APIs are thread safe.
+type WriteConflictHistory struct {
+	sync.RWMutex
+
+	capacity  int
+	records   []*WriteConflictRecord
+	currentID uint64
+}
+
+// NewWriteConflictHistory creates an instance of WriteConflictHistory.
+func NewWriteConflictHistory(capacity int) *WriteConflictHistory {
+	return &WriteConflictHistory{
+		capacity:  capacity,
+		records:   make([]*WriteConflictRecord, 0, capacity),
+		currentID: 1,
+	}
+}
+
+// Push pushes a record into the history, the oldest record is evicted if the history is full. It will set the `ID`
+// field of the record, so do not modify the record's content after pushing.
+func (h *WriteConflictHistory) Push(record *WriteConflictRecord) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.capacity <= 0 {
+		return
+	}
+	record.ID = h.currentID
+	h.currentID++
+	if len(h.records) == h.capacity {
+		copy(h.records, h.records[1:])
+		h.records = h.records[:len(h.records)-1]
+	}
+	h.records = append(h.records, record)
+}
+
+// GetAll gets all collected write conflicts, from the oldest to the latest.
+func (h *WriteConflictHistory) GetAll() []*WriteConflictRecord {
+	h.RLock()
+	defer h.RUnlock()
+	return append([]*WriteConflictRecord{}, h.records...)
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writeconflicthistory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteConflictHistoryCollection(t *testing.T) {
+	h := NewWriteConflictHistory(2)
+	require.Len(t, h.GetAll(), 0)
+
+	rec1 := &WriteConflictRecord{StartTS: 1}
+	h.Push(rec1)
+	require.Equal(t, []*WriteConflictRecord{rec1}, h.GetAll())
+	require.Equal(t, uint64(1), rec1.ID)
+
+	rec2 := &WriteConflictRecord{StartTS: 2}
+	h.Push(rec2)
+	require.Equal(t, []*WriteConflictRecord{rec1, rec2}, h.GetAll())
+
+	// The oldest record is evicted when the history is full.
+	rec3 := &WriteConflictRecord{StartTS: 3}
+	h.Push(rec3)
+	require.Equal(t, []*WriteConflictRecord{rec2, rec3}, h.GetAll())
+	require.Equal(t, uint64(3), rec3.ID)
+
+	h = NewWriteConflictHistory(0)
+	h.Push(rec1)
+	require.Len(t, h.GetAll(), 0)
+}
+
+func TestWriteConflictRecordToDatum(t *testing.T) {
+	now := time.Now()
+	rec := &WriteConflictRecord{
+		ID:               1,
+		OccurTime:        now,
+		StartTS:          100,
+		ConflictStartTS:  101,
+		ConflictCommitTS: 102,
+		Key:              []byte{0x74, 0x80},
+		AutoRetry:        true,
+	}
+	datum := func(columnName string) *types.Datum {
+		d := rec.ToDatum(columnName)
+		return &d
+	}
+	require.Equal(t, uint64(1), datum(ColIDStr).GetUint64())
+	expectedTime := types.NewTime(types.FromGoTime(now), mysql.TypeTimestamp, types.MaxFsp)
+	require.Equal(t, 0, datum(ColOccurTimeStr).GetMysqlTime().Compare(expectedTime))
+	require.Equal(t, uint64(100), datum(ColStartTSStr).GetUint64())
+	require.Equal(t, uint64(101), datum(ColConflictStartTSStr).GetUint64())
+	require.Equal(t, uint64(102), datum(ColConflictCommitTSStr).GetUint64())
+	require.Equal(t, "7480", datum(ColKeyStr).GetString())
+	require.True(t, datum(ColPrimaryKeyStr).IsNull())
+	require.Equal(t, int64(1), datum(ColAutoRetryStr).GetInt64())
+	require.True(t, datum(ColKeyInfoStr).IsNull())
+}